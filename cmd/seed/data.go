@@ -0,0 +1,108 @@
+// File: cmd/seed/data.go
+package main
+
+// Dictionaries used to generate realistic looking demo data
+
+var firstNames = []string{
+	"Александр", "Мария", "Дмитрий", "Анна", "Сергей", "Екатерина", "Андрей", "Ольга",
+	"Алексей", "Наталья", "Михаил", "Елена", "Иван", "Татьяна", "Никита", "Юлия",
+	"Павел", "Светлана", "Артём", "Ирина", "Егор", "Дарья", "Роман", "Ксения",
+}
+
+var lastNames = []string{
+	"Иванов", "Смирнов", "Кузнецов", "Попов", "Васильев", "Петров", "Соколов", "Михайлов",
+	"Новиков", "Федоров", "Морозов", "Волков", "Алексеев", "Лебедев", "Семенов", "Егоров",
+	"Павлов", "Козлов", "Степанов", "Николаев", "Орлов", "Андреев", "Макаров", "Никитин",
+}
+
+// latinFirstNames and latinLastNames are used to build email addresses, indexes match firstNames and lastNames
+var latinFirstNames = []string{
+	"alexander", "maria", "dmitry", "anna", "sergey", "ekaterina", "andrey", "olga",
+	"alexey", "natalia", "mikhail", "elena", "ivan", "tatiana", "nikita", "yulia",
+	"pavel", "svetlana", "artem", "irina", "egor", "daria", "roman", "ksenia",
+}
+
+var latinLastNames = []string{
+	"ivanov", "smirnov", "kuznetsov", "popov", "vasiliev", "petrov", "sokolov", "mikhailov",
+	"novikov", "fedorov", "morozov", "volkov", "alexeev", "lebedev", "semenov", "egorov",
+	"pavlov", "kozlov", "stepanov", "nikolaev", "orlov", "andreev", "makarov", "nikitin",
+}
+
+var departmentNames = []string{
+	"Разработка", "Тестирование", "Продукт", "Дизайн", "Маркетинг", "Продажи",
+	"Поддержка", "Бухгалтерия", "HR", "Юридический отдел", "Инфраструктура", "Аналитика",
+}
+
+var positions = []string{
+	"Разработчик", "Старший разработчик", "Тимлид", "QA инженер", "Дизайнер",
+	"Менеджер проекта", "Аналитик", "DevOps инженер", "Специалист поддержки", "Бухгалтер",
+}
+
+var groupChatTopics = []string{
+	"Общий", "Флудилка", "Релизы", "Инциденты", "Планирование спринта", "Код-ревью",
+	"Дизайн-ревью", "Новости компании", "Обеды", "Офис", "Найм", "Бэкенд", "Фронтенд", "Мобильная разработка",
+}
+
+var messagePhrases = []string{
+	"Всем привет!",
+	"Доброе утро, коллеги",
+	"Кто посмотрит мой PR?",
+	"Созвон через 5 минут",
+	"Сборка снова упала на тестах",
+	"Выкатили новую версию на стейдж",
+	"Отличная работа, спасибо!",
+	"Можем перенести встречу на завтра?",
+	"Добавил задачу в бэклог",
+	"Кто сегодня дежурный?",
+	"Посмотрите, пожалуйста, документ",
+	"Я на больничном до пятницы",
+	"Исправил баг с авторизацией",
+	"Нужна помощь с миграцией базы",
+	"Отчёт готов, отправил на почту",
+	"Обновил зависимости, всё зелёное",
+	"Согласен",
+	"Давайте обсудим на планировании",
+	"Клиент просит ускорить релиз",
+	"Вечером будут работы на сервере",
+	"**Важно:** завтра в 10:00 общий созвон",
+	"Ссылка на дашборд: https://grafana.example.com/d/overview",
+	"👍",
+	"🔥🔥🔥",
+	"Спасибо!",
+}
+
+var taskVerbs = []string{
+	"Исправить", "Реализовать", "Протестировать", "Задокументировать", "Оптимизировать",
+	"Обновить", "Проверить", "Согласовать", "Подготовить", "Настроить",
+}
+
+var taskObjects = []string{
+	"страницу входа", "отчёт по продажам", "API уведомлений", "экспорт в CSV",
+	"мобильное приложение", "CI пайплайн", "резервное копирование", "поиск по сообщениям",
+	"интеграцию с календарём", "онбординг сотрудников", "метрики производительности", "права доступа",
+}
+
+var eventTitles = []string{
+	"Ежедневный стендап", "Планирование спринта", "Ретроспектива", "Демо для заказчика",
+	"1:1 с руководителем", "Собеседование", "Архитектурный комитет", "Обучение",
+	"Дедлайн релиза", "Квартальное планирование", "Встреча с партнёрами", "Корпоратив",
+}
+
+var eventLocations = []string{
+	"Переговорная «Москва»", "Переговорная «Казань»", "Zoom", "Google Meet", "Офис, 3 этаж", "",
+}
+
+var pollQuestions = []struct {
+	Title   string
+	Options []string
+}{
+	{"Где проведём корпоратив?", []string{"Ресторан", "Загородный клуб", "Боулинг", "Квест"}},
+	{"Удобное время для стендапа", []string{"9:30", "10:00", "10:30", "11:00"}},
+	{"Какой язык выбрать для нового сервиса?", []string{"Go", "Kotlin", "TypeScript", "Rust"}},
+	{"Формат работы летом", []string{"Офис", "Удалённо", "Гибрид"}},
+	{"Что заказать на обед в пятницу?", []string{"Пицца", "Суши", "Бургеры", "Салаты", "Шаурма"}},
+	{"Оцените прошедший спринт", []string{"Отлично", "Хорошо", "Нормально", "Плохо"}},
+	{"Какие темы для внутренних докладов интересны?", []string{"Архитектура", "Тестирование", "DevOps", "Продукт", "Soft skills"}},
+}
+
+var colors = []string{"#3788d8", "#e74c3c", "#2ecc71", "#f39c12", "#9b59b6", "#1abc9c"}
@@ -0,0 +1,105 @@
+// File: cmd/seed/main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"tachyon-messenger/shared/config"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/logger"
+)
+
+func main() {
+	cfg := &seedConfig{}
+
+	flag.IntVar(&cfg.Departments, "departments", 6, "Number of departments")
+	flag.IntVar(&cfg.Users, "users", 50, "Number of users (including the demo admin)")
+	flag.IntVar(&cfg.GroupChats, "group-chats", 10, "Number of group chats")
+	flag.IntVar(&cfg.PrivateChats, "private-chats", 20, "Number of private chats")
+	flag.IntVar(&cfg.MessagesPerChat, "messages-per-chat", 500, "Number of messages in every chat")
+	flag.IntVar(&cfg.Tasks, "tasks", 200, "Number of tasks")
+	flag.IntVar(&cfg.Events, "events", 100, "Number of calendar events")
+	flag.IntVar(&cfg.Polls, "polls", 20, "Number of polls")
+	flag.IntVar(&cfg.Days, "days", 90, "Spread generated history over this many past days")
+	flag.IntVar(&cfg.BatchSize, "batch-size", 500, "Rows per INSERT statement")
+	flag.Int64Var(&cfg.RandomSeed, "random-seed", 0, "Random seed for reproducible data (0 - use current time)")
+	flag.StringVar(&cfg.EmailDomain, "email-domain", "demo.tachyon.local", "Email domain of generated users")
+	flag.StringVar(&cfg.Password, "password", "DemoPassw0rd!", "Password of all generated users")
+	flag.BoolVar(&cfg.Migrate, "migrate", false, "Create missing tables before seeding")
+	help := flag.Bool("help", false, "Show help")
+	flag.Parse()
+
+	if *help {
+		showHelp()
+		return
+	}
+
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid arguments: %v\n", err)
+		os.Exit(2)
+	}
+
+	if cfg.RandomSeed == 0 {
+		cfg.RandomSeed = time.Now().UnixNano()
+	}
+
+	// Initialize logger
+	log := logger.New(&logger.Config{
+		Level:       "info",
+		Format:      "text",
+		Environment: "development",
+	})
+
+	// Load configuration
+	appCfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to database
+	dbConfig := database.DefaultConfig(appCfg.Database.URL)
+	db, err := database.Connect(dbConfig)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Now()
+	seeder := newSeeder(db, cfg, log)
+
+	if cfg.Migrate {
+		log.Info("Creating missing tables...")
+		if err := seeder.migrate(); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+	}
+
+	log.Infof("Seeding demo data (random seed %d)...", cfg.RandomSeed)
+	if err := seeder.Run(); err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+
+	log.Infof("✅ Demo data generated in %s", time.Since(started).Round(time.Millisecond))
+	log.Infof("Demo admin login: admin@%s / %s", cfg.EmailDomain, cfg.Password)
+}
+
+func showHelp() {
+	fmt.Println("Tachyon Demo Data Generator")
+	fmt.Println("")
+	fmt.Println("Generates departments, users, chats with messages, tasks, events and polls")
+	fmt.Println("for demo environments and performance testing. Uses DATABASE_URL.")
+	fmt.Println("")
+	fmt.Println("Usage:")
+	fmt.Println("  go run ./cmd/seed [flags]")
+	fmt.Println("")
+	fmt.Println("Examples:")
+	fmt.Println("  go run ./cmd/seed -migrate                      # Small demo dataset")
+	fmt.Println("  go run ./cmd/seed -users 1000 -group-chats 200 -messages-per-chat 5000")
+	fmt.Println("  go run ./cmd/seed -random-seed 42                # Reproducible dataset")
+	fmt.Println("")
+	fmt.Println("Flags:")
+	flag.PrintDefaults()
+}
@@ -0,0 +1,634 @@
+// File: cmd/seed/seeder.go
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	calendarmodels "tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/services/chat/markdown"
+	chatmodels "tachyon-messenger/services/chat/models"
+	pollmodels "tachyon-messenger/services/poll/models"
+	taskmodels "tachyon-messenger/services/task/models"
+	usermodels "tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedConfig holds volumes of generated data
+type seedConfig struct {
+	Departments     int
+	Users           int
+	GroupChats      int
+	PrivateChats    int
+	MessagesPerChat int
+	Tasks           int
+	Events          int
+	Polls           int
+	Days            int
+	BatchSize       int
+	RandomSeed      int64
+	EmailDomain     string
+	Password        string
+	Migrate         bool
+}
+
+// validate checks seed configuration
+func (c *seedConfig) validate() error {
+	if c.Users < 2 {
+		return fmt.Errorf("at least 2 users are required")
+	}
+	if c.Departments < 0 || c.GroupChats < 0 || c.PrivateChats < 0 || c.MessagesPerChat < 0 ||
+		c.Tasks < 0 || c.Events < 0 || c.Polls < 0 {
+		return fmt.Errorf("volumes cannot be negative")
+	}
+	if c.Departments > len(departmentNames) {
+		return fmt.Errorf("at most %d departments are supported", len(departmentNames))
+	}
+	if c.Days < 1 {
+		return fmt.Errorf("days must be positive")
+	}
+	if c.BatchSize < 1 || c.BatchSize > 5000 {
+		return fmt.Errorf("batch size must be between 1 and 5000")
+	}
+	if strings.TrimSpace(c.EmailDomain) == "" {
+		return fmt.Errorf("email domain is required")
+	}
+	if len(c.Password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+	return nil
+}
+
+// seeder generates demo data directly in the shared database.
+// Inserts skip model hooks: all defaults that hooks would set are filled explicitly,
+// which keeps bulk inserts fast (e.g. Message.AfterCreate updates the chat on every row).
+type seeder struct {
+	db  *gorm.DB
+	cfg *seedConfig
+	log *logger.Logger
+	rnd *rand.Rand
+	now time.Time
+
+	departmentIDs []uint
+	userIDs       []uint
+}
+
+// newSeeder creates a new seeder
+func newSeeder(db *database.DB, cfg *seedConfig, log *logger.Logger) *seeder {
+	return &seeder{
+		db:  db.DB.Session(&gorm.Session{SkipHooks: true, CreateBatchSize: cfg.BatchSize}),
+		cfg: cfg,
+		log: log,
+		rnd: rand.New(rand.NewSource(cfg.RandomSeed)),
+		now: time.Now(),
+	}
+}
+
+// migrate creates tables of all seeded models
+func (s *seeder) migrate() error {
+	return s.db.AutoMigrate(
+		&usermodels.Department{},
+		&usermodels.User{},
+		&chatmodels.Chat{},
+		&chatmodels.ChatMember{},
+		&chatmodels.Message{},
+		&chatmodels.MessageReaction{},
+		&taskmodels.Task{},
+		&taskmodels.TaskComment{},
+		&calendarmodels.Event{},
+		&calendarmodels.EventParticipant{},
+		&pollmodels.Poll{},
+		&pollmodels.PollOption{},
+		&pollmodels.PollVote{},
+	)
+}
+
+// Run generates all demo data
+func (s *seeder) Run() error {
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"departments", s.seedDepartments},
+		{"users", s.seedUsers},
+		{"chats", s.seedChats},
+		{"tasks", s.seedTasks},
+		{"events", s.seedEvents},
+		{"polls", s.seedPolls},
+	}
+
+	for _, step := range steps {
+		started := time.Now()
+		if err := step.fn(); err != nil {
+			return fmt.Errorf("failed to seed %s: %w", step.name, err)
+		}
+		s.log.Infof("Seeded %s in %s", step.name, time.Since(started).Round(time.Millisecond))
+	}
+
+	return nil
+}
+
+// seedDepartments creates departments, existing ones are reused
+func (s *seeder) seedDepartments() error {
+	if s.cfg.Departments == 0 {
+		return nil
+	}
+
+	names := departmentNames[:s.cfg.Departments]
+	departments := make([]usermodels.Department, len(names))
+	for i, name := range names {
+		departments[i] = usermodels.Department{Name: name}
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&departments).Error; err != nil {
+		return err
+	}
+
+	return s.db.Model(&usermodels.Department{}).Where("name IN ?", names).Pluck("id", &s.departmentIDs).Error
+}
+
+// seedUsers creates users with roles. The first user is a super admin with a predictable email.
+// Users with the same email from a previous run are reused.
+func (s *seeder) seedUsers() error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(s.cfg.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	users := make([]usermodels.User, s.cfg.Users)
+	for i := range users {
+		first := s.rnd.Intn(len(firstNames))
+		last := s.rnd.Intn(len(lastNames))
+
+		user := usermodels.User{
+			Email:          fmt.Sprintf("%s.%s.%d@%s", latinFirstNames[first], latinLastNames[last], i, s.cfg.EmailDomain),
+			Name:           firstNames[first] + " " + lastNames[last],
+			HashedPassword: string(hashedPassword),
+			Role:           s.randomRole(),
+			Status:         sharedmodels.StatusOffline,
+			Position:       positions[s.rnd.Intn(len(positions))],
+			IsActive:       true,
+		}
+		if len(s.departmentIDs) > 0 {
+			departmentID := s.departmentIDs[s.rnd.Intn(len(s.departmentIDs))]
+			user.DepartmentID = &departmentID
+		}
+		lastActive := s.randomPastTime()
+		user.LastActiveAt = &lastActive
+		user.CreatedAt = s.now.AddDate(0, 0, -s.cfg.Days)
+		user.UpdatedAt = lastActive
+
+		users[i] = user
+	}
+
+	// Demo admin
+	users[0].Email = "admin@" + s.cfg.EmailDomain
+	users[0].Name = "Демо Администратор"
+	users[0].Role = sharedmodels.RoleSuperAdmin
+
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&users).Error; err != nil {
+		return err
+	}
+
+	err = s.db.Model(&usermodels.User{}).
+		Where("email LIKE ?", "%@"+s.cfg.EmailDomain).
+		Order("id").
+		Pluck("id", &s.userIDs).Error
+	if err != nil {
+		return err
+	}
+
+	s.log.Infof("Users: %d available in domain %s", len(s.userIDs), s.cfg.EmailDomain)
+	return nil
+}
+
+// seedChats creates group and private chats with members, messages and reactions
+func (s *seeder) seedChats() error {
+	totalChats := s.cfg.GroupChats + s.cfg.PrivateChats
+	if totalChats == 0 {
+		return nil
+	}
+
+	chats := make([]chatmodels.Chat, 0, totalChats)
+	chatMembers := make([][]uint, 0, totalChats)
+
+	for i := 0; i < s.cfg.GroupChats; i++ {
+		members := s.randomUsers(3, 15)
+		topic := groupChatTopics[i%len(groupChatTopics)]
+		if i >= len(groupChatTopics) {
+			topic = fmt.Sprintf("%s #%d", topic, i/len(groupChatTopics)+1)
+		}
+
+		chatType := chatmodels.ChatTypeGroup
+		if s.rnd.Intn(5) == 0 {
+			chatType = chatmodels.ChatTypeChannel
+		}
+
+		chats = append(chats, chatmodels.Chat{
+			Name:      topic,
+			Type:      chatType,
+			CreatorID: members[0],
+			IsActive:  true,
+		})
+		chatMembers = append(chatMembers, members)
+	}
+
+	for i := 0; i < s.cfg.PrivateChats; i++ {
+		members := s.randomUsers(2, 2)
+		chats = append(chats, chatmodels.Chat{
+			Type:      chatmodels.ChatTypePrivate,
+			CreatorID: members[0],
+			IsActive:  true,
+		})
+		chatMembers = append(chatMembers, members)
+	}
+
+	for i := range chats {
+		chats[i].CreatedAt = s.now.AddDate(0, 0, -s.cfg.Days)
+	}
+
+	if err := s.db.Create(&chats).Error; err != nil {
+		return err
+	}
+
+	// Members, the creator becomes the owner (normally done by Chat.AfterCreate)
+	members := make([]chatmodels.ChatMember, 0)
+	for i, chat := range chats {
+		for j, userID := range chatMembers[i] {
+			role := chatmodels.ChatMemberRoleMember
+			if j == 0 {
+				role = chatmodels.ChatMemberRoleOwner
+			} else if chat.Type != chatmodels.ChatTypePrivate && j == 1 {
+				role = chatmodels.ChatMemberRoleAdmin
+			}
+			members = append(members, chatmodels.ChatMember{
+				ChatID:   chat.ID,
+				UserID:   userID,
+				Role:     role,
+				JoinedAt: chat.CreatedAt,
+				IsActive: true,
+			})
+		}
+	}
+	if err := s.db.Create(&members).Error; err != nil {
+		return err
+	}
+
+	// Messages are generated chat by chat to keep memory bounded
+	totalMessages := totalChats * s.cfg.MessagesPerChat
+	created := 0
+	for i := range chats {
+		if err := s.seedMessages(&chats[i], chatMembers[i]); err != nil {
+			return err
+		}
+		created += s.cfg.MessagesPerChat
+		s.log.Infof("Messages: %d/%d (chat %d/%d)", created, totalMessages, i+1, totalChats)
+	}
+
+	return nil
+}
+
+// seedMessages creates messages of a single chat ordered by time
+func (s *seeder) seedMessages(chat *chatmodels.Chat, members []uint) error {
+	if s.cfg.MessagesPerChat == 0 {
+		return nil
+	}
+
+	span := s.now.Sub(chat.CreatedAt)
+	step := span / time.Duration(s.cfg.MessagesPerChat+1)
+
+	for start := 0; start < s.cfg.MessagesPerChat; start += s.cfg.BatchSize {
+		end := min(start+s.cfg.BatchSize, s.cfg.MessagesPerChat)
+
+		messages := make([]chatmodels.Message, 0, end-start)
+		for i := start; i < end; i++ {
+			sentAt := chat.CreatedAt.Add(step*time.Duration(i+1) + time.Duration(s.rnd.Int63n(int64(step)+1)))
+			if sentAt.After(s.now) {
+				sentAt = s.now
+			}
+
+			message := chatmodels.Message{
+				ChatID:        chat.ID,
+				SenderID:      members[s.rnd.Intn(len(members))],
+				Content:       messagePhrases[s.rnd.Intn(len(messagePhrases))],
+				ContentFormat: markdown.FormatPlain,
+				Type:          chatmodels.MessageTypeText,
+				Status:        chatmodels.MessageStatusRead,
+			}
+			message.CreatedAt = sentAt
+			message.UpdatedAt = sentAt
+			messages = append(messages, message)
+		}
+
+		if err := s.db.Create(&messages).Error; err != nil {
+			return err
+		}
+
+		// Reactions on roughly every tenth message
+		reactions := make([]chatmodels.MessageReaction, 0)
+		for _, message := range messages {
+			if s.rnd.Intn(10) != 0 {
+				continue
+			}
+			for _, userID := range s.pick(members, 1+s.rnd.Intn(3)) {
+				reactions = append(reactions, chatmodels.MessageReaction{
+					MessageID: message.ID,
+					UserID:    userID,
+					Emoji:     []string{"👍", "❤️", "😂", "🔥", "👀"}[s.rnd.Intn(5)],
+				})
+			}
+		}
+		if len(reactions) > 0 {
+			if err := s.db.Create(&reactions).Error; err != nil {
+				return err
+			}
+		}
+
+		chat.LastMessageAt = &messages[len(messages)-1].CreatedAt
+	}
+
+	return s.db.Model(&chatmodels.Chat{}).Where("id = ?", chat.ID).Update("last_message_at", chat.LastMessageAt).Error
+}
+
+// seedTasks creates tasks with comments
+func (s *seeder) seedTasks() error {
+	if s.cfg.Tasks == 0 {
+		return nil
+	}
+
+	statuses := []taskmodels.TaskStatus{
+		taskmodels.TaskStatusNew, taskmodels.TaskStatusInProgress, taskmodels.TaskStatusInProgress,
+		taskmodels.TaskStatusReview, taskmodels.TaskStatusDone, taskmodels.TaskStatusDone, taskmodels.TaskStatusCancelled,
+	}
+	priorities := []taskmodels.TaskPriority{
+		taskmodels.TaskPriorityLow, taskmodels.TaskPriorityMedium, taskmodels.TaskPriorityMedium,
+		taskmodels.TaskPriorityHigh, taskmodels.TaskPriorityCritical,
+	}
+
+	tasks := make([]taskmodels.Task, s.cfg.Tasks)
+	for i := range tasks {
+		createdAt := s.randomPastTime()
+		dueDate := createdAt.AddDate(0, 0, 1+s.rnd.Intn(30))
+
+		task := taskmodels.Task{
+			Title:       fmt.Sprintf("%s %s", taskVerbs[s.rnd.Intn(len(taskVerbs))], taskObjects[s.rnd.Intn(len(taskObjects))]),
+			Description: "Задача создана генератором демо-данных.",
+			Status:      statuses[s.rnd.Intn(len(statuses))],
+			Priority:    priorities[s.rnd.Intn(len(priorities))],
+			CreatedBy:   s.randomUser(),
+			DueDate:     &dueDate,
+		}
+		if s.rnd.Intn(10) != 0 {
+			assignee := s.randomUser()
+			task.AssignedTo = &assignee
+		}
+		task.CreatedAt = createdAt
+		task.UpdatedAt = createdAt
+
+		tasks[i] = task
+	}
+
+	if err := s.db.Create(&tasks).Error; err != nil {
+		return err
+	}
+
+	comments := make([]taskmodels.TaskComment, 0)
+	for _, task := range tasks {
+		for j := 0; j < s.rnd.Intn(4); j++ {
+			comment := taskmodels.TaskComment{
+				TaskID:  task.ID,
+				UserID:  s.randomUser(),
+				Content: messagePhrases[s.rnd.Intn(len(messagePhrases))],
+			}
+			comment.CreatedAt = task.CreatedAt.Add(time.Duration(j+1) * time.Hour)
+			comment.UpdatedAt = comment.CreatedAt
+			comments = append(comments, comment)
+		}
+	}
+	if len(comments) > 0 {
+		return s.db.Create(&comments).Error
+	}
+
+	return nil
+}
+
+// seedEvents creates past and upcoming calendar events with participants
+func (s *seeder) seedEvents() error {
+	if s.cfg.Events == 0 {
+		return nil
+	}
+
+	eventTypes := []calendarmodels.EventType{
+		calendarmodels.EventTypeMeeting, calendarmodels.EventTypeMeeting,
+		calendarmodels.EventTypePersonal, calendarmodels.EventTypeDeadline,
+	}
+	participantStatuses := []calendarmodels.ParticipantStatus{
+		calendarmodels.ParticipantStatusPending, calendarmodels.ParticipantStatusAccepted,
+		calendarmodels.ParticipantStatusAccepted, calendarmodels.ParticipantStatusDeclined, calendarmodels.ParticipantStatusMaybe,
+	}
+
+	events := make([]calendarmodels.Event, s.cfg.Events)
+	participants := make([][]uint, s.cfg.Events)
+	for i := range events {
+		// Working hours between -days and +30 days
+		day := s.now.AddDate(0, 0, s.rnd.Intn(s.cfg.Days+30)-s.cfg.Days)
+		start := time.Date(day.Year(), day.Month(), day.Day(), 9+s.rnd.Intn(9), 30*s.rnd.Intn(2), 0, 0, day.Location())
+
+		eventType := eventTypes[s.rnd.Intn(len(eventTypes))]
+		event := calendarmodels.Event{
+			Title:     eventTitles[s.rnd.Intn(len(eventTitles))],
+			StartTime: start,
+			EndTime:   start.Add(time.Duration(30*(1+s.rnd.Intn(4))) * time.Minute),
+			Location:  eventLocations[s.rnd.Intn(len(eventLocations))],
+			Type:      eventType,
+			CreatedBy: s.randomUser(),
+			Color:     colors[s.rnd.Intn(len(colors))],
+		}
+		event.CreatedAt = start.AddDate(0, 0, -7)
+		event.UpdatedAt = event.CreatedAt
+
+		events[i] = event
+		participants[i] = []uint{event.CreatedBy}
+		if eventType == calendarmodels.EventTypeMeeting {
+			participants[i] = append(participants[i], s.randomUsers(2, 8)...)
+		}
+	}
+
+	if err := s.db.Create(&events).Error; err != nil {
+		return err
+	}
+
+	rows := make([]calendarmodels.EventParticipant, 0)
+	for i, event := range events {
+		seen := make(map[uint]bool)
+		for j, userID := range participants[i] {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+
+			participant := calendarmodels.EventParticipant{
+				EventID:     event.ID,
+				UserID:      userID,
+				Status:      participantStatuses[s.rnd.Intn(len(participantStatuses))],
+				IsOrganizer: j == 0,
+			}
+			if participant.IsOrganizer {
+				participant.Status = calendarmodels.ParticipantStatusAccepted
+			}
+			if participant.Status != calendarmodels.ParticipantStatusPending {
+				respondedAt := event.CreatedAt.Add(time.Hour)
+				participant.RespondedAt = &respondedAt
+			}
+			rows = append(rows, participant)
+		}
+	}
+
+	return s.db.Create(&rows).Error
+}
+
+// seedPolls creates public choice polls with options and votes
+func (s *seeder) seedPolls() error {
+	if s.cfg.Polls == 0 {
+		return nil
+	}
+
+	polls := make([]pollmodels.Poll, s.cfg.Polls)
+	for i := range polls {
+		question := pollQuestions[i%len(pollQuestions)]
+		startTime := s.randomPastTime()
+		endTime := startTime.AddDate(0, 0, 3+s.rnd.Intn(14))
+
+		status := pollmodels.PollStatusActive
+		if endTime.Before(s.now) {
+			status = pollmodels.PollStatusClosed
+		}
+
+		pollType := pollmodels.PollTypeSingleChoice
+		if s.rnd.Intn(3) == 0 {
+			pollType = pollmodels.PollTypeMultipleChoice
+		}
+
+		poll := pollmodels.Poll{
+			Title:       question.Title,
+			Type:        pollType,
+			Status:      status,
+			Visibility:  pollmodels.PollVisibilityPublic,
+			CreatedBy:   s.randomUser(),
+			StartTime:   &startTime,
+			EndTime:     &endTime,
+			ShowResults: true,
+			Category:    "Демо",
+		}
+		poll.CreatedAt = startTime
+		poll.UpdatedAt = startTime
+
+		polls[i] = poll
+	}
+
+	if err := s.db.Create(&polls).Error; err != nil {
+		return err
+	}
+
+	options := make([]pollmodels.PollOption, 0)
+	for i, poll := range polls {
+		for position, text := range pollQuestions[i%len(pollQuestions)].Options {
+			options = append(options, pollmodels.PollOption{
+				PollID:   poll.ID,
+				Text:     text,
+				Position: position,
+				Color:    colors[position%len(colors)],
+			})
+		}
+	}
+	if err := s.db.Create(&options).Error; err != nil {
+		return err
+	}
+
+	optionsByPoll := make(map[uint][]uint)
+	for _, option := range options {
+		optionsByPoll[option.PollID] = append(optionsByPoll[option.PollID], option.ID)
+	}
+
+	votes := make([]pollmodels.PollVote, 0)
+	for _, poll := range polls {
+		pollOptions := optionsByPoll[poll.ID]
+		for _, voterID := range s.pick(s.userIDs, s.rnd.Intn(len(s.userIDs)+1)) {
+			choices := 1
+			if poll.Type == pollmodels.PollTypeMultipleChoice {
+				choices = 1 + s.rnd.Intn(2)
+			}
+			for _, optionID := range s.pick(pollOptions, choices) {
+				userID := voterID
+				vote := pollmodels.PollVote{
+					PollID:   poll.ID,
+					OptionID: &optionID,
+					UserID:   &userID,
+				}
+				vote.CreatedAt = poll.StartTime.Add(time.Duration(s.rnd.Int63n(int64(poll.EndTime.Sub(*poll.StartTime)))))
+				if vote.CreatedAt.After(s.now) {
+					vote.CreatedAt = s.now
+				}
+				vote.UpdatedAt = vote.CreatedAt
+				votes = append(votes, vote)
+			}
+		}
+	}
+
+	if len(votes) > 0 {
+		if err := s.db.Create(&votes).Error; err != nil {
+			return err
+		}
+	}
+
+	s.log.Infof("Polls: %d polls, %d options, %d votes", len(polls), len(options), len(votes))
+	return nil
+}
+
+// randomRole returns a role with realistic distribution: few admins, some managers, mostly employees
+func (s *seeder) randomRole() sharedmodels.Role {
+	switch n := s.rnd.Intn(100); {
+	case n < 3:
+		return sharedmodels.RoleAdmin
+	case n < 15:
+		return sharedmodels.RoleManager
+	default:
+		return sharedmodels.RoleEmployee
+	}
+}
+
+// randomPastTime returns a random time within the configured history window
+func (s *seeder) randomPastTime() time.Time {
+	return s.now.Add(-time.Duration(s.rnd.Int63n(int64(s.cfg.Days) * int64(24*time.Hour))))
+}
+
+// randomUser returns a random user ID
+func (s *seeder) randomUser() uint {
+	return s.userIDs[s.rnd.Intn(len(s.userIDs))]
+}
+
+// randomUsers returns between minCount and maxCount distinct random user IDs
+func (s *seeder) randomUsers(minCount, maxCount int) []uint {
+	count := minCount + s.rnd.Intn(maxCount-minCount+1)
+	return s.pick(s.userIDs, count)
+}
+
+// pick returns count distinct random elements of ids
+func (s *seeder) pick(ids []uint, count int) []uint {
+	if count > len(ids) {
+		count = len(ids)
+	}
+	picked := make([]uint, 0, count)
+	for _, i := range s.rnd.Perm(len(ids))[:count] {
+		picked = append(picked, ids[i])
+	}
+	return picked
+}
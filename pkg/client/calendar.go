@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	calendarmodels "tachyon-messenger/services/calendar/models"
+)
+
+// CalendarService wraps event endpoints of the calendar service
+type CalendarService struct {
+	client *Client
+}
+
+// ListEvents returns one page of events of the current user
+// GET /api/v1/events
+func (s *CalendarService) ListEvents(ctx context.Context, opts *ListOptions) (*Page[*calendarmodels.EventResponse], error) {
+	return listPage[*calendarmodels.EventResponse](ctx, s.client, ServiceCalendar, "/api/v1/events", opts, "events")
+}
+
+// ListAllEvents iterates over all events of the current user
+func (s *CalendarService) ListAllEvents(opts *ListOptions) *Iterator[*calendarmodels.EventResponse] {
+	return newIterator(opts, func(ctx context.Context, opts *ListOptions) (*Page[*calendarmodels.EventResponse], error) {
+		return s.ListEvents(ctx, opts)
+	})
+}
+
+// SearchEvents returns one page of events matching the search query
+// GET /api/v1/events/search
+func (s *CalendarService) SearchEvents(ctx context.Context, searchQuery string, opts *ListOptions) (*Page[*calendarmodels.EventResponse], error) {
+	opts = opts.clone()
+	opts.Params = withParam(opts.Params, "q", searchQuery)
+	return listPage[*calendarmodels.EventResponse](ctx, s.client, ServiceCalendar, "/api/v1/events/search", opts, "events")
+}
+
+// GetEvent returns an event
+// GET /api/v1/events/:id
+func (s *CalendarService) GetEvent(ctx context.Context, id uint) (*calendarmodels.EventResponse, error) {
+	return s.event(ctx, http.MethodGet, pathf("/api/v1/events/%d", id), nil)
+}
+
+// CreateEvent creates an event
+// POST /api/v1/events
+func (s *CalendarService) CreateEvent(ctx context.Context, req *calendarmodels.CreateEventRequest) (*calendarmodels.EventResponse, error) {
+	return s.event(ctx, http.MethodPost, "/api/v1/events", req)
+}
+
+// UpdateEvent updates an event
+// PUT /api/v1/events/:id
+func (s *CalendarService) UpdateEvent(ctx context.Context, id uint, req *calendarmodels.UpdateEventRequest) (*calendarmodels.EventResponse, error) {
+	return s.event(ctx, http.MethodPut, pathf("/api/v1/events/%d", id), req)
+}
+
+// DeleteEvent moves an event to trash
+// DELETE /api/v1/events/:id
+func (s *CalendarService) DeleteEvent(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServiceCalendar, http.MethodDelete, pathf("/api/v1/events/%d", id), nil, nil, nil)
+}
+
+// RestoreEvent restores a deleted event
+// POST /api/v1/events/:id/restore
+func (s *CalendarService) RestoreEvent(ctx context.Context, id uint) (*calendarmodels.EventResponse, error) {
+	return s.event(ctx, http.MethodPost, pathf("/api/v1/events/%d/restore", id), nil)
+}
+
+// CancelEvent cancels an event and notifies its participants
+// POST /api/v1/events/:id/cancel
+func (s *CalendarService) CancelEvent(ctx context.Context, id uint, req *calendarmodels.CancelEventRequest) (*calendarmodels.EventResponse, error) {
+	return s.event(ctx, http.MethodPost, pathf("/api/v1/events/%d/cancel", id), req)
+}
+
+// RescheduleEvent moves an event to a new time and notifies its participants
+// POST /api/v1/events/:id/reschedule
+func (s *CalendarService) RescheduleEvent(ctx context.Context, id uint, req *calendarmodels.RescheduleEventRequest) (*calendarmodels.EventResponse, error) {
+	return s.event(ctx, http.MethodPost, pathf("/api/v1/events/%d/reschedule", id), req)
+}
+
+// InviteParticipants invites users to an event
+// POST /api/v1/events/:id/participants
+func (s *CalendarService) InviteParticipants(ctx context.Context, id uint, req *calendarmodels.AddParticipantsRequest) error {
+	return s.client.Do(ctx, ServiceCalendar, http.MethodPost, pathf("/api/v1/events/%d/participants", id), nil, req, nil)
+}
+
+// RemoveParticipant removes a user from an event
+// DELETE /api/v1/events/:id/participants/:user_id
+func (s *CalendarService) RemoveParticipant(ctx context.Context, id, userID uint) error {
+	return s.client.Do(ctx, ServiceCalendar, http.MethodDelete, pathf("/api/v1/events/%d/participants/%d", id, userID), nil, nil, nil)
+}
+
+// Respond accepts or declines an event invitation
+// PUT /api/v1/events/:id/status
+func (s *CalendarService) Respond(ctx context.Context, id uint, req *calendarmodels.UpdateParticipantStatusRequest) error {
+	return s.client.Do(ctx, ServiceCalendar, http.MethodPut, pathf("/api/v1/events/%d/status", id), nil, req, nil)
+}
+
+// SetReminder adds a reminder of an event for the current user
+// POST /api/v1/events/:id/reminders
+func (s *CalendarService) SetReminder(ctx context.Context, id uint, req *calendarmodels.CreateReminderRequest) (*calendarmodels.EventReminderResponse, error) {
+	var reminder calendarmodels.EventReminderResponse
+	if err := s.client.doField(ctx, ServiceCalendar, http.MethodPost, pathf("/api/v1/events/%d/reminders", id), nil, req, "reminder", &reminder); err != nil {
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+// Stats returns event statistics of the current user
+// GET /api/v1/events/stats
+func (s *CalendarService) Stats(ctx context.Context) (*calendarmodels.EventStatsResponse, error) {
+	var stats calendarmodels.EventStatsResponse
+	if err := s.client.doField(ctx, ServiceCalendar, http.MethodGet, "/api/v1/events/stats", nil, nil, "stats", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// FindAvailability finds free slots common to the users
+// POST /api/v1/calendar/availability
+func (s *CalendarService) FindAvailability(ctx context.Context, req *calendarmodels.AvailabilityRequest) (*calendarmodels.AvailabilityResponse, error) {
+	var availability calendarmodels.AvailabilityResponse
+	if err := s.client.doField(ctx, ServiceCalendar, http.MethodPost, "/api/v1/calendar/availability", nil, req, "availability", &availability); err != nil {
+		return nil, err
+	}
+	return &availability, nil
+}
+
+// event sends a request returning an event
+func (s *CalendarService) event(ctx context.Context, method, path string, body interface{}) (*calendarmodels.EventResponse, error) {
+	var event calendarmodels.EventResponse
+	if err := s.client.doField(ctx, ServiceCalendar, method, path, nil, body, "event", &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
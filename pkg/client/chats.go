@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	chatmodels "tachyon-messenger/services/chat/models"
+)
+
+// ChatsService wraps chat endpoints of the chat service
+type ChatsService struct {
+	client *Client
+}
+
+// UnreadCounts holds unread message counts of the chats of the current user
+type UnreadCounts struct {
+	Chats map[uint]int64 `json:"chats"`
+	Total int64          `json:"unread_count"`
+}
+
+// List returns one page of chats of the current user
+// GET /api/v1/chats
+func (s *ChatsService) List(ctx context.Context, opts *ListOptions) (*Page[chatmodels.ChatResponse], error) {
+	return listPage[chatmodels.ChatResponse](ctx, s.client, ServiceChat, "/api/v1/chats", opts, "chats")
+}
+
+// ListAll iterates over all chats of the current user
+func (s *ChatsService) ListAll(opts *ListOptions) *Iterator[chatmodels.ChatResponse] {
+	return newIterator(opts, func(ctx context.Context, opts *ListOptions) (*Page[chatmodels.ChatResponse], error) {
+		return s.List(ctx, opts)
+	})
+}
+
+// ListDeleted returns one page of deleted chats that can still be restored
+// GET /api/v1/chats/trash
+func (s *ChatsService) ListDeleted(ctx context.Context, opts *ListOptions) (*Page[chatmodels.ChatResponse], error) {
+	return listPage[chatmodels.ChatResponse](ctx, s.client, ServiceChat, "/api/v1/chats/trash", opts, "chats")
+}
+
+// Get returns a chat
+// GET /api/v1/chats/:id
+func (s *ChatsService) Get(ctx context.Context, id uint) (*chatmodels.ChatResponse, error) {
+	return s.chat(ctx, http.MethodGet, pathf("/api/v1/chats/%d", id), nil)
+}
+
+// Create creates a chat
+// POST /api/v1/chats
+func (s *ChatsService) Create(ctx context.Context, req *chatmodels.CreateChatRequest) (*chatmodels.ChatResponse, error) {
+	return s.chat(ctx, http.MethodPost, "/api/v1/chats", req)
+}
+
+// Update updates a chat
+// PUT /api/v1/chats/:id
+func (s *ChatsService) Update(ctx context.Context, id uint, req *chatmodels.UpdateChatRequest) (*chatmodels.ChatResponse, error) {
+	return s.chat(ctx, http.MethodPut, pathf("/api/v1/chats/%d", id), req)
+}
+
+// Delete moves a chat to trash
+// DELETE /api/v1/chats/:id
+func (s *ChatsService) Delete(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServiceChat, http.MethodDelete, pathf("/api/v1/chats/%d", id), nil, nil, nil)
+}
+
+// Restore restores a deleted chat
+// POST /api/v1/chats/:id/restore
+func (s *ChatsService) Restore(ctx context.Context, id uint) (*chatmodels.ChatResponse, error) {
+	return s.chat(ctx, http.MethodPost, pathf("/api/v1/chats/%d/restore", id), nil)
+}
+
+// Join joins a public chat
+// POST /api/v1/chats/:id/join
+func (s *ChatsService) Join(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServiceChat, http.MethodPost, pathf("/api/v1/chats/%d/join", id), nil, nil, nil)
+}
+
+// Members returns members of a chat
+// GET /api/v1/chats/:id/members
+func (s *ChatsService) Members(ctx context.Context, id uint) ([]chatmodels.ChatMemberResponse, error) {
+	var members []chatmodels.ChatMemberResponse
+	if err := s.client.doField(ctx, ServiceChat, http.MethodGet, pathf("/api/v1/chats/%d/members", id), nil, nil, "members", &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// AddMember adds a user to a chat
+// POST /api/v1/chats/:id/members
+func (s *ChatsService) AddMember(ctx context.Context, id uint, req *chatmodels.AddChatMemberRequest) error {
+	return s.client.Do(ctx, ServiceChat, http.MethodPost, pathf("/api/v1/chats/%d/members", id), nil, req, nil)
+}
+
+// RemoveMember removes a user from a chat
+// DELETE /api/v1/chats/:id/members/:userId
+func (s *ChatsService) RemoveMember(ctx context.Context, id, userID uint) error {
+	return s.client.Do(ctx, ServiceChat, http.MethodDelete, pathf("/api/v1/chats/%d/members/%d", id, userID), nil, nil, nil)
+}
+
+// UnreadCounts returns unread message counts of all chats of the current user
+// GET /api/v1/chats/unread-counts
+func (s *ChatsService) UnreadCounts(ctx context.Context) (*UnreadCounts, error) {
+	var counts UnreadCounts
+	if err := s.client.Do(ctx, ServiceChat, http.MethodGet, "/api/v1/chats/unread-counts", nil, nil, &counts); err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// chat sends a request returning a chat
+func (s *ChatsService) chat(ctx context.Context, method, path string, body interface{}) (*chatmodels.ChatResponse, error) {
+	var chat chatmodels.ChatResponse
+	if err := s.client.doField(ctx, ServiceChat, method, path, nil, body, "chat", &chat); err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// MessagesService wraps message endpoints of the chat service
+type MessagesService struct {
+	client *Client
+}
+
+// ListByChat returns one page of messages of a chat, newest first
+// GET /api/v1/messages/chat/:chatId
+func (s *MessagesService) ListByChat(ctx context.Context, chatID uint, opts *ListOptions) (*Page[chatmodels.MessageResponse], error) {
+	return listPage[chatmodels.MessageResponse](ctx, s.client, ServiceChat, pathf("/api/v1/messages/chat/%d", chatID), opts, "messages")
+}
+
+// ListAllByChat iterates over all messages of a chat, newest first
+func (s *MessagesService) ListAllByChat(chatID uint, opts *ListOptions) *Iterator[chatmodels.MessageResponse] {
+	return newIterator(opts, func(ctx context.Context, opts *ListOptions) (*Page[chatmodels.MessageResponse], error) {
+		return s.ListByChat(ctx, chatID, opts)
+	})
+}
+
+// Send sends a message to a chat
+// POST /api/v1/messages
+func (s *MessagesService) Send(ctx context.Context, req *chatmodels.SendMessageRequest) (*chatmodels.MessageResponse, error) {
+	return s.message(ctx, http.MethodPost, "/api/v1/messages", req)
+}
+
+// Get returns a message
+// GET /api/v1/messages/:id
+func (s *MessagesService) Get(ctx context.Context, id uint) (*chatmodels.MessageResponse, error) {
+	return s.message(ctx, http.MethodGet, pathf("/api/v1/messages/%d", id), nil)
+}
+
+// Update edits a message
+// PUT /api/v1/messages/:id
+func (s *MessagesService) Update(ctx context.Context, id uint, req *chatmodels.UpdateMessageRequest) (*chatmodels.MessageResponse, error) {
+	return s.message(ctx, http.MethodPut, pathf("/api/v1/messages/%d", id), req)
+}
+
+// Delete deletes a message
+// DELETE /api/v1/messages/:id
+func (s *MessagesService) Delete(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServiceChat, http.MethodDelete, pathf("/api/v1/messages/%d", id), nil, nil, nil)
+}
+
+// message sends a request returning a message
+func (s *MessagesService) message(ctx context.Context, method, path string, body interface{}) (*chatmodels.MessageResponse, error) {
+	var message chatmodels.MessageResponse
+	if err := s.client.doField(ctx, ServiceChat, method, path, nil, body, "message", &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
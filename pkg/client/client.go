@@ -0,0 +1,414 @@
+// Package client is a typed Go client of the public Tachyon Messenger API for internal tools
+// and services. Request and response types are the models of the services themselves,
+// so the client breaks at compile time when an API contract changes.
+//
+//	api, err := client.New(client.DefaultConfig("http://gateway:8080"))
+//	login, err := api.Users.Login(ctx, "admin@tachyon.local", password)
+//	api.SetToken(login.Tokens.AccessToken)
+//
+//	tasks := api.Tasks.ListAll(&client.ListOptions{Filter: map[string]string{"status": "todo"}})
+//	for tasks.Next(ctx) {
+//		task := tasks.Item()
+//	}
+//	if err := tasks.Err(); err != nil { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Service identifies a backend service of the API
+type Service string
+
+const (
+	ServiceUser         Service = "user"
+	ServiceChat         Service = "chat"
+	ServiceTask         Service = "task"
+	ServiceCalendar     Service = "calendar"
+	ServicePoll         Service = "poll"
+	ServiceNotification Service = "notification"
+)
+
+// Config holds client configuration
+type Config struct {
+	// BaseURL is the API gateway URL, used for every service without an entry in ServiceURLs
+	BaseURL string
+	// ServiceURLs point services to their own URLs, e.g. for service-to-service calls
+	// inside the cluster. Login and registration are served by the user service only.
+	ServiceURLs map[Service]string
+
+	TokenSource TokenSource
+	Locale      string // Accept-Language of requests, e.g. "en"
+	UserAgent   string
+	HTTPClient  *http.Client
+
+	// Idempotent requests (GET, PUT, DELETE) are retried on network errors, 429, 502, 503 and 504
+	// with exponential backoff starting at RetryWait. Retry-After of the response takes precedence.
+	MaxRetries   int
+	RetryWait    time.Duration
+	MaxRetryWait time.Duration
+}
+
+// DefaultConfig returns default client configuration for the gateway at baseURL
+func DefaultConfig(baseURL string) *Config {
+	return &Config{
+		BaseURL:      baseURL,
+		ServiceURLs:  make(map[Service]string),
+		UserAgent:    "tachyon-go-client",
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		MaxRetries:   3,
+		RetryWait:    500 * time.Millisecond,
+		MaxRetryWait: 30 * time.Second,
+	}
+}
+
+// TokenSource provides the access token of requests
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token
+type StaticToken string
+
+// Token returns the token
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Client is a client of the Tachyon Messenger API. It is safe for concurrent use.
+type Client struct {
+	config *Config
+
+	tokenMu     sync.RWMutex
+	tokenSource TokenSource
+
+	Users         *UsersService
+	Chats         *ChatsService
+	Messages      *MessagesService
+	Tasks         *TasksService
+	Calendar      *CalendarService
+	Polls         *PollsService
+	Notifications *NotificationsService
+}
+
+// New creates a new API client
+func New(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	if config.BaseURL == "" && len(config.ServiceURLs) == 0 {
+		return nil, fmt.Errorf("base URL is required")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+
+	c := &Client{
+		config:      config,
+		tokenSource: config.TokenSource,
+	}
+	c.Users = &UsersService{client: c}
+	c.Chats = &ChatsService{client: c}
+	c.Messages = &MessagesService{client: c}
+	c.Tasks = &TasksService{client: c}
+	c.Calendar = &CalendarService{client: c}
+	c.Polls = &PollsService{client: c}
+	c.Notifications = &NotificationsService{client: c}
+	return c, nil
+}
+
+// SetToken authenticates further requests with the access token
+func (c *Client) SetToken(token string) {
+	c.SetTokenSource(StaticToken(token))
+}
+
+// SetTokenSource authenticates further requests with tokens of the source, nil disables authentication
+func (c *Client) SetTokenSource(source TokenSource) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	c.tokenSource = source
+}
+
+// APIError is returned for responses with an error status
+type APIError struct {
+	StatusCode int
+	Message    string          // "error" of the response body
+	Details    json.RawMessage // "details" of the response body, e.g. validation errors
+	RequestID  string
+	RetryAfter time.Duration // Set for 429 and 503 responses with Retry-After
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("tachyon api: %d %s (request %s)", e.StatusCode, message, e.RequestID)
+	}
+	return fmt.Sprintf("tachyon api: %d %s", e.StatusCode, message)
+}
+
+// IsStatus checks if err is an APIError with the status code
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// Do sends a request to the service and decodes the JSON response into out, if not nil.
+// body is encoded as JSON. It can be used for endpoints the typed services do not wrap.
+func (c *Client) Do(ctx context.Context, service Service, method, path string, query url.Values, body, out interface{}) error {
+	data, err := c.do(ctx, service, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// doField sends a request and decodes one field of the response envelope into out
+func (c *Client) doField(ctx context.Context, service Service, method, path string, query url.Values, body interface{}, field string, out interface{}) error {
+	var envelope map[string]json.RawMessage
+	if err := c.Do(ctx, service, method, path, query, body, &envelope); err != nil {
+		return err
+	}
+
+	raw, ok := envelope[field]
+	if !ok {
+		return fmt.Errorf("response has no %q field", field)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", field, err)
+	}
+	return nil
+}
+
+// do sends a request with retries and returns the response body
+func (c *Client) do(ctx context.Context, service Service, method, path string, query url.Values, body interface{}) ([]byte, error) {
+	endpoint, err := c.endpoint(service, path, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	if body != nil {
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	retries := 0
+	if isIdempotent(method) {
+		retries = c.config.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		data, retryAfter, err := c.send(ctx, method, endpoint, payload)
+		if err == nil || attempt >= retries || !isRetryable(err) {
+			return data, err
+		}
+
+		if err := sleep(ctx, c.backoff(attempt, retryAfter)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send performs one attempt of a request
+func (c *Client) send(ctx context.Context, method, endpoint string, payload []byte) ([]byte, time.Duration, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.UserAgent != "" {
+		req.Header.Set("User-Agent", c.config.UserAgent)
+	}
+	if c.config.Locale != "" {
+		req.Header.Set("Accept-Language", c.config.Locale)
+	}
+
+	c.tokenMu.RLock()
+	source := c.tokenSource
+	c.tokenMu.RUnlock()
+	if source != nil {
+		token, err := source.Token(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get access token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, &networkError{err: err}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, &networkError{err: err}
+	}
+
+	if resp.StatusCode < 300 {
+		return data, 0, nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	var body struct {
+		Error     string          `json:"error"`
+		Details   json.RawMessage `json:"details"`
+		RequestID string          `json:"request_id"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		apiErr.Message = body.Error
+		apiErr.Details = body.Details
+		if body.RequestID != "" {
+			apiErr.RequestID = body.RequestID
+		}
+	}
+	return nil, apiErr.RetryAfter, apiErr
+}
+
+// endpoint builds the URL of a request to the service
+func (c *Client) endpoint(service Service, path string, query url.Values) (string, error) {
+	base := c.config.ServiceURLs[service]
+	if base == "" {
+		base = c.config.BaseURL
+	}
+	if base == "" {
+		return "", fmt.Errorf("no URL configured for %s service", service)
+	}
+
+	endpoint := strings.TrimRight(base, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return endpoint, nil
+}
+
+// backoff returns the wait before the next attempt
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	wait := retryAfter
+	if wait <= 0 {
+		wait = c.config.RetryWait << attempt
+		wait += time.Duration(rand.Int63n(int64(wait)/2 + 1)) // Разносим повторы клиентов во времени
+	}
+	if c.config.MaxRetryWait > 0 && wait > c.config.MaxRetryWait {
+		wait = c.config.MaxRetryWait
+	}
+	return wait
+}
+
+// networkError wraps transport failures, which are safe to retry for idempotent requests
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string {
+	return e.err.Error()
+}
+
+func (e *networkError) Unwrap() error {
+	return e.err
+}
+
+// isRetryable checks if a failed attempt can be repeated
+func isRetryable(err error) bool {
+	switch e := err.(type) {
+	case *networkError:
+		return true
+	case *APIError:
+		switch e.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotent checks if a request can be repeated without side effects
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses Retry-After given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// pathf formats a request path, escaping string arguments
+func pathf(format string, args ...interface{}) string {
+	for i, arg := range args {
+		if s, ok := arg.(string); ok {
+			args[i] = url.PathEscape(s)
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
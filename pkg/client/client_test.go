@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := DefaultConfig(server.URL)
+	config.RetryWait = time.Millisecond
+	config.MaxRetryWait = 10 * time.Millisecond
+
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestClientAuthAndErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"error": "Unauthorized", "request_id": "req-1"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"unread_count": 7, "request_id": "req-2"})
+	})
+	ctx := context.Background()
+
+	_, err := client.Notifications.UnreadCount(ctx)
+	if !IsStatus(err, http.StatusUnauthorized) {
+		t.Fatalf("expected 401 error, got %v", err)
+	}
+	if apiErr := err.(*APIError); apiErr.Message != "Unauthorized" || apiErr.RequestID != "req-1" {
+		t.Errorf("unexpected error fields: %+v", apiErr)
+	}
+
+	client.SetToken("secret")
+	count, err := client.Notifications.UnreadCount(ctx)
+	if err != nil || count != 7 {
+		t.Errorf("expected 7 unread, got %d (%v)", count, err)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{"error": "maintenance"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"stats": map[string]interface{}{}})
+	})
+	ctx := context.Background()
+
+	if _, err := client.Tasks.Stats(ctx); err != nil {
+		t.Fatalf("expected GET to succeed after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if err := client.Chats.Join(ctx, 1); !IsStatus(err, http.StatusServiceUnavailable) {
+		t.Errorf("expected POST to fail without retry, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected POST to be sent once, got %d", calls)
+	}
+}
+
+func TestIteratorCursor(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		if r.URL.Query().Get("filter[status]") != "todo" {
+			t.Errorf("filter is missing in %s", r.URL.RawQuery)
+		}
+
+		page := map[string]interface{}{"tasks": []map[string]interface{}{{"id": 1}, {"id": 2}}, "next_cursor": "c1"}
+		switch cursor {
+		case "c1":
+			page = map[string]interface{}{"tasks": []map[string]interface{}{{"id": 3}}, "next_cursor": ""}
+		case "":
+		default:
+			t.Errorf("unexpected cursor %q", cursor)
+		}
+		writeJSON(w, http.StatusOK, page)
+	})
+
+	tasks, err := client.Tasks.ListAll(&ListOptions{Limit: 2, Filter: map[string]string{"status": "todo"}}).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 3 || tasks[2].ID != 3 {
+		t.Errorf("expected tasks 1..3, got %d tasks", len(tasks))
+	}
+}
+
+func TestIteratorOffset(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var messages []map[string]interface{}
+		for id := offset + 1; id <= offset+2 && id <= 5; id++ {
+			messages = append(messages, map[string]interface{}{"id": id})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"messages": messages,
+			"offset":   offset,
+			"has_more": offset+2 < 5,
+		})
+	})
+
+	messages, err := client.Messages.ListAllByChat(10, &ListOptions{Limit: 2}).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(messages) != 5 || messages[4].ID != 5 {
+		t.Errorf("expected messages 1..5, got %d messages", len(messages))
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	notificationmodels "tachyon-messenger/services/notification/models"
+)
+
+// NotificationsService wraps endpoints of the notification service
+type NotificationsService struct {
+	client *Client
+}
+
+// List returns one page of notifications of the current user
+// GET /api/v1/notifications
+func (s *NotificationsService) List(ctx context.Context, opts *ListOptions) (*Page[*notificationmodels.NotificationResponse], error) {
+	return listPage[*notificationmodels.NotificationResponse](ctx, s.client, ServiceNotification, "/api/v1/notifications", opts, "notifications")
+}
+
+// ListAll iterates over all notifications of the current user
+func (s *NotificationsService) ListAll(opts *ListOptions) *Iterator[*notificationmodels.NotificationResponse] {
+	return newIterator(opts, func(ctx context.Context, opts *ListOptions) (*Page[*notificationmodels.NotificationResponse], error) {
+		return s.List(ctx, opts)
+	})
+}
+
+// Get returns a notification
+// GET /api/v1/notifications/:id
+func (s *NotificationsService) Get(ctx context.Context, id uint) (*notificationmodels.NotificationResponse, error) {
+	var notification notificationmodels.NotificationResponse
+	if err := s.client.doField(ctx, ServiceNotification, http.MethodGet, pathf("/api/v1/notifications/%d", id), nil, nil, "notification", &notification); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// UnreadCount returns the number of unread notifications of the current user
+// GET /api/v1/notifications/unread-count
+func (s *NotificationsService) UnreadCount(ctx context.Context) (int64, error) {
+	var count int64
+	if err := s.client.doField(ctx, ServiceNotification, http.MethodGet, "/api/v1/notifications/unread-count", nil, nil, "unread_count", &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Stats returns notification statistics of the current user
+// GET /api/v1/notifications/stats
+func (s *NotificationsService) Stats(ctx context.Context) (*notificationmodels.NotificationStatsResponse, error) {
+	var stats notificationmodels.NotificationStatsResponse
+	if err := s.client.doField(ctx, ServiceNotification, http.MethodGet, "/api/v1/notifications/stats", nil, nil, "stats", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// MarkAsRead marks a notification as read
+// PUT /api/v1/notifications/:id/read
+func (s *NotificationsService) MarkAsRead(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServiceNotification, http.MethodPut, pathf("/api/v1/notifications/%d/read", id), nil, nil, nil)
+}
+
+// MarkAllAsRead marks all notifications of the current user as read
+// PUT /api/v1/notifications/read-all
+func (s *NotificationsService) MarkAllAsRead(ctx context.Context) error {
+	return s.client.Do(ctx, ServiceNotification, http.MethodPut, "/api/v1/notifications/read-all", nil, nil, nil)
+}
+
+// Preferences returns notification preferences of the current user
+// GET /api/v1/notifications/preferences
+func (s *NotificationsService) Preferences(ctx context.Context) ([]*notificationmodels.UserNotificationPreference, error) {
+	var preferences []*notificationmodels.UserNotificationPreference
+	if err := s.client.doField(ctx, ServiceNotification, http.MethodGet, "/api/v1/notifications/preferences", nil, nil, "preferences", &preferences); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// UpdatePreference updates the preference of req.NotificationType
+// PUT /api/v1/notifications/preferences/:type
+func (s *NotificationsService) UpdatePreference(ctx context.Context, req *notificationmodels.UserPreferenceRequest) error {
+	path := pathf("/api/v1/notifications/preferences/%s", string(req.NotificationType))
+	return s.client.Do(ctx, ServiceNotification, http.MethodPut, path, nil, req, nil)
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions holds list parameters shared by all list endpoints, see shared/query
+type ListOptions struct {
+	Limit  int
+	Offset int               // Ignored when Cursor is set
+	Cursor string            // next_cursor of the previous page
+	Sort   string            // e.g. "-priority,created_at"
+	Filter map[string]string // filter[field]=value, operators as "field][lt"
+	Params url.Values        // Endpoint specific parameters, e.g. "q" of search
+}
+
+// query encodes list options as query string parameters
+func (o *ListOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	for key, values := range o.Params {
+		query[key] = append([]string(nil), values...)
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	} else if o.Offset > 0 {
+		query.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	for field, value := range o.Filter {
+		query.Set("filter["+field+"]", value)
+	}
+	return query
+}
+
+// clone copies options so iterators do not modify options of the caller
+func (o *ListOptions) clone() *ListOptions {
+	if o == nil {
+		return &ListOptions{}
+	}
+	cloned := *o
+	return &cloned
+}
+
+// Page is one page of a list endpoint
+type Page[T any] struct {
+	Items      []T
+	Total      int64
+	Limit      int
+	Offset     int
+	NextCursor string
+	HasMore    bool
+}
+
+// listPage requests one page and takes items from the field of the response envelope
+func listPage[T any](ctx context.Context, c *Client, service Service, path string, opts *ListOptions, field string) (*Page[T], error) {
+	var envelope map[string]json.RawMessage
+	if err := c.Do(ctx, service, "GET", path, opts.query(), nil, &envelope); err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{}
+	raw, ok := envelope[field]
+	if !ok {
+		return nil, fmt.Errorf("response has no %q field", field)
+	}
+	if err := json.Unmarshal(raw, &page.Items); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", field, err)
+	}
+
+	// Метаданные есть не у всех списков, отсутствующие поля оставляем нулевыми
+	for name, target := range map[string]interface{}{
+		"total":       &page.Total,
+		"limit":       &page.Limit,
+		"offset":      &page.Offset,
+		"next_cursor": &page.NextCursor,
+		"has_more":    &page.HasMore,
+	} {
+		if value, ok := envelope[name]; ok {
+			_ = json.Unmarshal(value, target)
+		}
+	}
+	return page, nil
+}
+
+// Iterator walks all items of a list endpoint, requesting pages as needed.
+// Pages are followed by next_cursor when the endpoint returns one, by offset otherwise.
+type Iterator[T any] struct {
+	fetch func(ctx context.Context, opts *ListOptions) (*Page[T], error)
+	opts  *ListOptions
+
+	items   []T
+	index   int
+	current T
+	done    bool
+	err     error
+}
+
+// newIterator creates an iterator over pages returned by fetch
+func newIterator[T any](opts *ListOptions, fetch func(ctx context.Context, opts *ListOptions) (*Page[T], error)) *Iterator[T] {
+	return &Iterator[T]{
+		fetch: fetch,
+		opts:  opts.clone(),
+	}
+}
+
+// Next advances to the next item, requesting the next page when needed.
+// It returns false when all items are read or on error, see Err.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for it.index >= len(it.items) {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		page, err := it.fetch(ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.items, it.index = page.Items, 0
+		it.advance(page)
+	}
+
+	it.current = it.items[it.index]
+	it.index++
+	return true
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// advance prepares options of the page after the given one
+func (it *Iterator[T]) advance(page *Page[T]) {
+	switch {
+	case len(page.Items) == 0:
+		it.done = true
+	case page.NextCursor != "":
+		it.opts.Cursor = page.NextCursor
+	case it.opts.Cursor != "":
+		// Курсорная выдача закончилась
+		it.done = true
+	default:
+		offset := page.Offset + len(page.Items)
+		if page.Offset == 0 && it.opts.Offset > 0 {
+			offset = it.opts.Offset + len(page.Items)
+		}
+		more := page.HasMore || (page.Total > 0 && int64(offset) < page.Total)
+		if !more {
+			it.done = true
+		}
+		it.opts.Offset = offset
+	}
+}
+
+// Collect reads all remaining items of the iterator
+func (it *Iterator[T]) Collect(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// withParam returns a copy of params with the parameter set
+func withParam(params url.Values, key, value string) url.Values {
+	copied := url.Values{}
+	for k, values := range params {
+		copied[k] = append([]string(nil), values...)
+	}
+	copied.Set(key, value)
+	return copied
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	pollmodels "tachyon-messenger/services/poll/models"
+)
+
+// PollsService wraps endpoints of the poll service
+type PollsService struct {
+	client *Client
+}
+
+// List returns one page of polls visible to the current user
+// GET /api/v1/polls
+func (s *PollsService) List(ctx context.Context, opts *ListOptions) (*Page[*pollmodels.PollResponse], error) {
+	return listPage[*pollmodels.PollResponse](ctx, s.client, ServicePoll, "/api/v1/polls", opts, "polls")
+}
+
+// ListAll iterates over all polls visible to the current user
+func (s *PollsService) ListAll(opts *ListOptions) *Iterator[*pollmodels.PollResponse] {
+	return newIterator(opts, func(ctx context.Context, opts *ListOptions) (*Page[*pollmodels.PollResponse], error) {
+		return s.List(ctx, opts)
+	})
+}
+
+// Search returns one page of polls matching the search query
+// GET /api/v1/polls/search
+func (s *PollsService) Search(ctx context.Context, searchQuery string, opts *ListOptions) (*Page[*pollmodels.PollResponse], error) {
+	opts = opts.clone()
+	opts.Params = withParam(opts.Params, "q", searchQuery)
+	return listPage[*pollmodels.PollResponse](ctx, s.client, ServicePoll, "/api/v1/polls/search", opts, "polls")
+}
+
+// Get returns a poll
+// GET /api/v1/polls/:id
+func (s *PollsService) Get(ctx context.Context, id uint) (*pollmodels.PollResponse, error) {
+	return s.poll(ctx, http.MethodGet, pathf("/api/v1/polls/%d", id), nil)
+}
+
+// Create creates a poll
+// POST /api/v1/polls
+func (s *PollsService) Create(ctx context.Context, req *pollmodels.CreatePollRequest) (*pollmodels.PollResponse, error) {
+	return s.poll(ctx, http.MethodPost, "/api/v1/polls", req)
+}
+
+// Update updates a poll
+// PUT /api/v1/polls/:id
+func (s *PollsService) Update(ctx context.Context, id uint, req *pollmodels.UpdatePollRequest) (*pollmodels.PollResponse, error) {
+	return s.poll(ctx, http.MethodPut, pathf("/api/v1/polls/%d", id), req)
+}
+
+// Delete deletes a poll
+// DELETE /api/v1/polls/:id
+func (s *PollsService) Delete(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServicePoll, http.MethodDelete, pathf("/api/v1/polls/%d", id), nil, nil, nil)
+}
+
+// Vote votes in a poll
+// POST /api/v1/polls/:id/vote
+func (s *PollsService) Vote(ctx context.Context, id uint, req *pollmodels.VotePollRequest) ([]*pollmodels.PollVoteResponse, error) {
+	var votes []*pollmodels.PollVoteResponse
+	if err := s.client.doField(ctx, ServicePoll, http.MethodPost, pathf("/api/v1/polls/%d/vote", id), nil, req, "votes", &votes); err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// MyVotes returns votes of the current user in a poll
+// GET /api/v1/polls/:id/my-votes
+func (s *PollsService) MyVotes(ctx context.Context, id uint) ([]*pollmodels.PollVoteResponse, error) {
+	var votes []*pollmodels.PollVoteResponse
+	if err := s.client.doField(ctx, ServicePoll, http.MethodGet, pathf("/api/v1/polls/%d/my-votes", id), nil, nil, "votes", &votes); err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// RetractVote removes votes of the current user from a poll
+// DELETE /api/v1/polls/:id/my-votes
+func (s *PollsService) RetractVote(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServicePoll, http.MethodDelete, pathf("/api/v1/polls/%d/my-votes", id), nil, nil, nil)
+}
+
+// Results returns results of a poll
+// GET /api/v1/polls/:id/results
+func (s *PollsService) Results(ctx context.Context, id uint) (*pollmodels.PollResultsResponse, error) {
+	var results pollmodels.PollResultsResponse
+	if err := s.client.doField(ctx, ServicePoll, http.MethodGet, pathf("/api/v1/polls/%d/results", id), nil, nil, "results", &results); err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
+// Comments returns one page of comments of a poll
+// GET /api/v1/polls/:id/comments
+func (s *PollsService) Comments(ctx context.Context, id uint, opts *ListOptions) (*Page[*pollmodels.PollCommentResponse], error) {
+	return listPage[*pollmodels.PollCommentResponse](ctx, s.client, ServicePoll, pathf("/api/v1/polls/%d/comments", id), opts, "comments")
+}
+
+// AddComment comments on a poll
+// POST /api/v1/polls/:id/comments
+func (s *PollsService) AddComment(ctx context.Context, id uint, req *pollmodels.CreateCommentRequest) (*pollmodels.PollCommentResponse, error) {
+	var comment pollmodels.PollCommentResponse
+	if err := s.client.doField(ctx, ServicePoll, http.MethodPost, pathf("/api/v1/polls/%d/comments", id), nil, req, "comment", &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// poll sends a request returning a poll
+func (s *PollsService) poll(ctx context.Context, method, path string, body interface{}) (*pollmodels.PollResponse, error) {
+	var poll pollmodels.PollResponse
+	if err := s.client.doField(ctx, ServicePoll, method, path, nil, body, "poll", &poll); err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
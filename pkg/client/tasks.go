@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	taskmodels "tachyon-messenger/services/task/models"
+)
+
+// TasksService wraps endpoints of the task service
+type TasksService struct {
+	client *Client
+}
+
+// List returns one page of tasks of the current user
+// GET /api/v1/tasks
+func (s *TasksService) List(ctx context.Context, opts *ListOptions) (*Page[*taskmodels.TaskResponse], error) {
+	return listPage[*taskmodels.TaskResponse](ctx, s.client, ServiceTask, "/api/v1/tasks", opts, "tasks")
+}
+
+// ListAll iterates over all tasks of the current user
+func (s *TasksService) ListAll(opts *ListOptions) *Iterator[*taskmodels.TaskResponse] {
+	return newIterator(opts, func(ctx context.Context, opts *ListOptions) (*Page[*taskmodels.TaskResponse], error) {
+		return s.List(ctx, opts)
+	})
+}
+
+// ListDeleted returns one page of deleted tasks that can still be restored
+// GET /api/v1/tasks/trash
+func (s *TasksService) ListDeleted(ctx context.Context, opts *ListOptions) (*Page[*taskmodels.TaskResponse], error) {
+	return listPage[*taskmodels.TaskResponse](ctx, s.client, ServiceTask, "/api/v1/tasks/trash", opts, "tasks")
+}
+
+// Get returns a task
+// GET /api/v1/tasks/:id
+func (s *TasksService) Get(ctx context.Context, id uint) (*taskmodels.TaskResponse, error) {
+	return s.task(ctx, http.MethodGet, pathf("/api/v1/tasks/%d", id), nil)
+}
+
+// Create creates a task
+// POST /api/v1/tasks
+func (s *TasksService) Create(ctx context.Context, req *taskmodels.CreateTaskRequest) (*taskmodels.TaskResponse, error) {
+	return s.task(ctx, http.MethodPost, "/api/v1/tasks", req)
+}
+
+// Update updates a task
+// PUT /api/v1/tasks/:id
+func (s *TasksService) Update(ctx context.Context, id uint, req *taskmodels.UpdateTaskRequest) (*taskmodels.TaskResponse, error) {
+	return s.task(ctx, http.MethodPut, pathf("/api/v1/tasks/%d", id), req)
+}
+
+// UpdateStatus changes the status of a task
+// PATCH /api/v1/tasks/:id/status
+func (s *TasksService) UpdateStatus(ctx context.Context, id uint, req *taskmodels.UpdateTaskStatusRequest) (*taskmodels.TaskResponse, error) {
+	return s.task(ctx, http.MethodPatch, pathf("/api/v1/tasks/%d/status", id), req)
+}
+
+// Delete moves a task to trash
+// DELETE /api/v1/tasks/:id
+func (s *TasksService) Delete(ctx context.Context, id uint) error {
+	return s.client.Do(ctx, ServiceTask, http.MethodDelete, pathf("/api/v1/tasks/%d", id), nil, nil, nil)
+}
+
+// Restore restores a deleted task
+// POST /api/v1/tasks/:id/restore
+func (s *TasksService) Restore(ctx context.Context, id uint) (*taskmodels.TaskResponse, error) {
+	return s.task(ctx, http.MethodPost, pathf("/api/v1/tasks/%d/restore", id), nil)
+}
+
+// Assign assigns a task to a user
+// POST /api/v1/tasks/:id/assign
+func (s *TasksService) Assign(ctx context.Context, id uint, req *taskmodels.AssignTaskRequest) (*taskmodels.TaskResponse, error) {
+	return s.task(ctx, http.MethodPost, pathf("/api/v1/tasks/%d/assign", id), req)
+}
+
+// Unassign removes the assignee of a task
+// DELETE /api/v1/tasks/:id/assign
+func (s *TasksService) Unassign(ctx context.Context, id uint) (*taskmodels.TaskResponse, error) {
+	return s.task(ctx, http.MethodDelete, pathf("/api/v1/tasks/%d/assign", id), nil)
+}
+
+// Stats returns task statistics of the current user
+// GET /api/v1/tasks/stats
+func (s *TasksService) Stats(ctx context.Context) (*taskmodels.TaskStatsResponse, error) {
+	var stats taskmodels.TaskStatsResponse
+	if err := s.client.doField(ctx, ServiceTask, http.MethodGet, "/api/v1/tasks/stats", nil, nil, "stats", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Comments returns one page of comments of a task
+// GET /api/v1/tasks/:id/comments
+func (s *TasksService) Comments(ctx context.Context, id uint, opts *ListOptions) (*Page[*taskmodels.TaskCommentResponse], error) {
+	return listPage[*taskmodels.TaskCommentResponse](ctx, s.client, ServiceTask, pathf("/api/v1/tasks/%d/comments", id), opts, "comments")
+}
+
+// AddComment comments on a task
+// POST /api/v1/tasks/:id/comments
+func (s *TasksService) AddComment(ctx context.Context, id uint, req *taskmodels.CreateTaskCommentRequest) (*taskmodels.TaskCommentResponse, error) {
+	var comment taskmodels.TaskCommentResponse
+	if err := s.client.doField(ctx, ServiceTask, http.MethodPost, pathf("/api/v1/tasks/%d/comments", id), nil, req, "comment", &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// task sends a request returning a task
+func (s *TasksService) task(ctx context.Context, method, path string, body interface{}) (*taskmodels.TaskResponse, error) {
+	var task taskmodels.TaskResponse
+	if err := s.client.doField(ctx, ServiceTask, method, path, nil, body, "task", &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
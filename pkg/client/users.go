@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	usermodels "tachyon-messenger/services/user/models"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// UsersService wraps authentication, users, profile and department endpoints of the user service
+type UsersService struct {
+	client *Client
+}
+
+// Register creates a new account
+// POST /api/v1/register
+func (s *UsersService) Register(ctx context.Context, req *usermodels.CreateUserRequest) (*usermodels.UserResponse, error) {
+	var user usermodels.UserResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodPost, "/api/v1/register", nil, req, "user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Login authenticates with email and password. Pass the access token to Client.SetToken
+// to authenticate further requests.
+// POST /api/v1/login
+func (s *UsersService) Login(ctx context.Context, email, password string) (*sharedmodels.LoginResponse, error) {
+	req := map[string]string{"email": email, "password": password}
+
+	var login sharedmodels.LoginResponse
+	if err := s.client.Do(ctx, ServiceUser, http.MethodPost, "/api/v1/login", nil, req, &login); err != nil {
+		return nil, err
+	}
+	return &login, nil
+}
+
+// List returns one page of users visible to the current user
+// GET /api/v1/users
+func (s *UsersService) List(ctx context.Context, opts *ListOptions) (*Page[*usermodels.UserListItem], error) {
+	return listPage[*usermodels.UserListItem](ctx, s.client, ServiceUser, "/api/v1/users", opts, "users")
+}
+
+// ListAll iterates over all users visible to the current user
+func (s *UsersService) ListAll(opts *ListOptions) *Iterator[*usermodels.UserListItem] {
+	return newIterator(opts, func(ctx context.Context, opts *ListOptions) (*Page[*usermodels.UserListItem], error) {
+		return s.List(ctx, opts)
+	})
+}
+
+// Get returns a user
+// GET /api/v1/users/:id
+func (s *UsersService) Get(ctx context.Context, id uint) (*usermodels.UserResponse, error) {
+	var user usermodels.UserResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodGet, pathf("/api/v1/users/%d", id), nil, nil, "user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Update updates a user
+// PUT /api/v1/users/:id
+func (s *UsersService) Update(ctx context.Context, id uint, req *usermodels.UpdateUserRequest) (*usermodels.UserResponse, error) {
+	var user usermodels.UserResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodPut, pathf("/api/v1/users/%d", id), nil, req, "user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Me returns the profile of the current user
+// GET /api/v1/profile
+func (s *UsersService) Me(ctx context.Context) (*usermodels.UserResponse, error) {
+	var profile usermodels.UserResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodGet, "/api/v1/profile", nil, nil, "profile", &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetProfile returns the profile of any user
+// GET /api/v1/profile/:id
+func (s *UsersService) GetProfile(ctx context.Context, id uint) (*usermodels.UserResponse, error) {
+	var profile usermodels.UserResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodGet, pathf("/api/v1/profile/%d", id), nil, nil, "profile", &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateProfile updates the profile of the current user
+// PUT /api/v1/profile
+func (s *UsersService) UpdateProfile(ctx context.Context, req *usermodels.UpdateProfileRequest) (*usermodels.UserResponse, error) {
+	var profile usermodels.UserResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodPut, "/api/v1/profile", nil, req, "profile", &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ChangePassword changes the password of the current user
+// PUT /api/v1/profile/password
+func (s *UsersService) ChangePassword(ctx context.Context, req *usermodels.ChangePasswordRequest) error {
+	return s.client.Do(ctx, ServiceUser, http.MethodPut, "/api/v1/profile/password", nil, req, nil)
+}
+
+// UpdateStatus updates the presence status of the current user
+// PUT /api/v1/profile/status
+func (s *UsersService) UpdateStatus(ctx context.Context, status sharedmodels.UserStatus) (*usermodels.UserResponse, error) {
+	req := &usermodels.UpdateStatusRequest{Status: status}
+
+	var profile usermodels.UserResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodPut, "/api/v1/profile/status", nil, req, "profile", &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ListDepartments returns all departments
+// GET /api/v1/departments
+func (s *UsersService) ListDepartments(ctx context.Context) ([]*usermodels.DepartmentResponse, error) {
+	var departments []*usermodels.DepartmentResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodGet, "/api/v1/departments", nil, nil, "departments", &departments); err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+// GetDepartment returns a department with its users
+// GET /api/v1/departments/:id/users
+func (s *UsersService) GetDepartment(ctx context.Context, id uint) (*usermodels.DepartmentWithUsersResponse, error) {
+	var department usermodels.DepartmentWithUsersResponse
+	if err := s.client.doField(ctx, ServiceUser, http.MethodGet, pathf("/api/v1/departments/%d/users", id), nil, nil, "department", &department); err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
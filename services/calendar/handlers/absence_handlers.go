@@ -0,0 +1,528 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAbsencePeriod is the period of own absences listed when no range is given
+const defaultAbsencePeriod = 90 * 24 * time.Hour
+
+// GetHolidays handles listing public holidays
+// GET /api/v1/calendar/holidays
+func (h *CalendarHandler) GetHolidays(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var filter models.HolidayFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for get holidays")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query parameters",
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	holidays, err := h.calendarUsecase.GetHolidays(&filter)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get holidays")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get holidays",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"holidays":   holidays,
+		"total":      len(holidays),
+		"request_id": requestID,
+	})
+}
+
+// CreateHoliday handles adding a public holiday (admin only)
+// POST /api/v1/calendar/holidays
+func (h *CalendarHandler) CreateHoliday(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for create holiday")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	holiday, err := h.calendarUsecase.CreateHoliday(userID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to create holiday")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to create holiday",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"holiday_id": holiday.ID,
+		"region":     holiday.Region,
+	}).Info("Holiday created successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Holiday created successfully",
+		"holiday":    holiday,
+		"request_id": requestID,
+	})
+}
+
+// DeleteHoliday handles removing a public holiday (admin only)
+// DELETE /api/v1/calendar/holidays/:id
+func (h *CalendarHandler) DeleteHoliday(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	holidayID, ok := parseIDParam(c, requestID, "holiday")
+	if !ok {
+		return
+	}
+
+	if err := h.calendarUsecase.DeleteHoliday(holidayID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"holiday_id": holidayID,
+			"error":      err.Error(),
+		}).Error("Failed to delete holiday")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to delete holiday",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"holiday_id": holidayID,
+	}).Info("Holiday deleted successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Holiday deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// GetMyAbsences handles listing absences of the current user
+// GET /api/v1/calendar/absences/my
+func (h *CalendarHandler) GetMyAbsences(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var filter models.AbsenceFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for get absences")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query parameters",
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	from := time.Now().UTC()
+	if filter.From != nil {
+		from = *filter.From
+	}
+	to := from.Add(defaultAbsencePeriod)
+	if filter.To != nil {
+		to = *filter.To
+	}
+
+	absences, err := h.calendarUsecase.GetUserAbsences(userID, from, to)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get absences")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to get absences",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"absences":   absences,
+		"total":      len(absences),
+		"request_id": requestID,
+	})
+}
+
+// CreateAbsence handles recording a vacation, sick leave or other absence
+// POST /api/v1/calendar/absences
+func (h *CalendarHandler) CreateAbsence(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAbsenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for create absence")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	absence, err := h.calendarUsecase.CreateAbsence(userID, canManageAbsences(c), &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to create absence")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to create absence",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":      requestID,
+		"user_id":         userID,
+		"absence_id":      absence.ID,
+		"absence_user_id": absence.UserID,
+		"type":            absence.Type,
+	}).Info("Absence created successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Absence created successfully",
+		"absence":    absence,
+		"request_id": requestID,
+	})
+}
+
+// DeleteAbsence handles removing an absence
+// DELETE /api/v1/calendar/absences/:id
+func (h *CalendarHandler) DeleteAbsence(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	absenceID, ok := parseIDParam(c, requestID, "absence")
+	if !ok {
+		return
+	}
+
+	if err := h.calendarUsecase.DeleteAbsence(userID, canManageAbsences(c), absenceID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"absence_id": absenceID,
+			"error":      err.Error(),
+		}).Error("Failed to delete absence")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to delete absence",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"absence_id": absenceID,
+	}).Info("Absence deleted successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Absence deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// SyncAbsences handles importing absences from an HR system (admin only)
+// POST /api/v1/calendar/absences/sync
+func (h *CalendarHandler) SyncAbsences(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.SyncAbsencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for sync absences")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.calendarUsecase.SyncAbsences(userID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"source":     req.Source,
+			"error":      err.Error(),
+		}).Error("Failed to sync absences")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to sync absences",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"source":     req.Source,
+		"created":    result.Created,
+		"updated":    result.Updated,
+	}).Info("Absences synced successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Absences synced successfully",
+		"result":     result,
+		"request_id": requestID,
+	})
+}
+
+// GetTeamAbsences handles the team absence overview for managers
+// GET /api/v1/calendar/absences/team
+func (h *CalendarHandler) GetTeamAbsences(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.TeamAbsenceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for team absences")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query parameters",
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	overview, err := h.calendarUsecase.GetTeamAbsences(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get team absences")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to get team absences",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"overview":   overview,
+		"request_id": requestID,
+	})
+}
+
+// FindAvailability handles suggesting meeting times for a group of users
+// POST /api/v1/calendar/availability
+func (h *CalendarHandler) FindAvailability(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for find availability")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	availability, err := h.calendarUsecase.FindAvailability(userID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to find availability")
+
+		c.JSON(absenceErrorStatus(err), gin.H{
+			"error":      "Failed to find availability",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"availability": availability,
+		"request_id":   requestID,
+	})
+}
+
+// getUserID gets the user ID from JWT token, responding with 401 if it is missing
+func getUserID(c *gin.Context, requestID string) (uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// parseIDParam parses the ID URL parameter, responding with 400 if it is invalid
+func parseIDParam(c *gin.Context, requestID, entity string) (uint, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":   requestID,
+			entity + "_id": idStr,
+			"error":        err.Error(),
+		}).Warn("Invalid " + entity + " ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid " + entity + " ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// canManageAbsences checks if the current user can manage absences of other users
+func canManageAbsences(c *gin.Context) bool {
+	role, err := middleware.GetUserRoleFromContext(c)
+	if err != nil {
+		return false
+	}
+	switch role {
+	case sharedmodels.RoleManager, sharedmodels.RoleAdmin, sharedmodels.RoleSuperAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// absenceErrorStatus maps holiday, absence and availability errors to HTTP status codes
+func absenceErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case containsAccessDeniedError(err.Error()):
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "cannot "):
+		return http.StatusConflict
+	case containsValidationError(err.Error()):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
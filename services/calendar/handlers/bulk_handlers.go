@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// BulkCreateEvents handles creating several events at once, e.g. importing a training schedule
+// POST /api/v1/events/bulk-create
+func (h *CalendarHandler) BulkCreateEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.BulkCreateEventsRequest
+	if !bindBulkRequest(c, requestID, userID, &req, "bulk create events") {
+		return
+	}
+
+	response, err := h.calendarUsecase.BulkCreateEvents(userID, &req)
+	respondBulkResult(c, requestID, userID, response, err, "create")
+}
+
+// BulkUpdateEvents handles moving a set of events by the same offset
+// PATCH /api/v1/events/bulk-update
+func (h *CalendarHandler) BulkUpdateEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.BulkUpdateEventsRequest
+	if !bindBulkRequest(c, requestID, userID, &req, "bulk update events") {
+		return
+	}
+
+	response, err := h.calendarUsecase.BulkMoveEvents(userID, &req)
+	respondBulkResult(c, requestID, userID, response, err, "move")
+}
+
+// BulkCancelEvents handles cancelling several events with a shared reason
+// POST /api/v1/events/bulk-cancel
+func (h *CalendarHandler) BulkCancelEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.BulkCancelEventsRequest
+	if !bindBulkRequest(c, requestID, userID, &req, "bulk cancel events") {
+		return
+	}
+
+	response, err := h.calendarUsecase.BulkCancelEvents(userID, &req)
+	respondBulkResult(c, requestID, userID, response, err, "cancel")
+}
+
+// bindBulkRequest binds the JSON body of a bulk request, responding with 400 if it is invalid
+func bindBulkRequest(c *gin.Context, requestID string, userID uint, req interface{}, operation string) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for " + operation)
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return false
+	}
+	return true
+}
+
+// respondBulkResult responds with per-event results of a bulk request. Events that failed or
+// conflict are reported in the results, the request itself fails only when it is invalid.
+func respondBulkResult(c *gin.Context, requestID string, userID uint, response *models.BulkEventsResponse, err error, action string) {
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to " + action + " events in bulk")
+
+		c.JSON(lifecycleErrorStatus(err), gin.H{
+			"error":      "Failed to " + action + " events",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"succeeded":  response.Succeeded,
+		"conflicts":  response.Conflicts,
+		"failed":     response.Failed,
+	}).Info("Bulk " + action + " of events completed")
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":    response.Results,
+		"succeeded":  response.Succeeded,
+		"conflicts":  response.Conflicts,
+		"failed":     response.Failed,
+		"total":      len(response.Results),
+		"request_id": requestID,
+	})
+}
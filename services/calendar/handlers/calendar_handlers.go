@@ -7,8 +7,11 @@ import (
 
 	"tachyon-messenger/services/calendar/models"
 	"tachyon-messenger/services/calendar/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -17,12 +20,14 @@ import (
 // CalendarHandler handles HTTP requests for calendar operations
 type CalendarHandler struct {
 	calendarUsecase usecase.CalendarUsecase
+	feedBaseURL     string // Public base URL for calendar feed links, request address if empty
 }
 
 // NewCalendarHandler creates a new calendar handler
-func NewCalendarHandler(calendarUsecase usecase.CalendarUsecase) *CalendarHandler {
+func NewCalendarHandler(calendarUsecase usecase.CalendarUsecase, feedBaseURL string) *CalendarHandler {
 	return &CalendarHandler{
 		calendarUsecase: calendarUsecase,
+		feedBaseURL:     feedBaseURL,
 	}
 }
 
@@ -55,8 +60,8 @@ func (h *CalendarHandler) CreateEvent(c *gin.Context) {
 		}).Warn("Invalid request body for create event")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -161,6 +166,7 @@ func (h *CalendarHandler) GetEvent(c *gin.Context) {
 		return
 	}
 
+	middleware.SetVersionETag(c, event.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"event":      event,
 		"request_id": requestID,
@@ -215,7 +221,18 @@ func (h *CalendarHandler) UpdateEvent(c *gin.Context) {
 		}).Warn("Invalid request body for update event")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Version the changes are based on may also be passed in If-Match header
+	req.Version, err = middleware.GetExpectedVersion(c, req.Version)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid event version",
 			"details":    err.Error(),
 			"request_id": requestID,
 		})
@@ -231,6 +248,11 @@ func (h *CalendarHandler) UpdateEvent(c *gin.Context) {
 			"error":      err.Error(),
 		}).Error("Failed to update event")
 
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, uint(eventID), err)
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "event not found" {
 			statusCode = http.StatusNotFound
@@ -256,6 +278,7 @@ func (h *CalendarHandler) UpdateEvent(c *gin.Context) {
 		"event_id":   eventID,
 	}).Info("Event updated successfully")
 
+	middleware.SetVersionETag(c, event.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Event updated successfully",
 		"event":      event,
@@ -337,9 +360,9 @@ func (h *CalendarHandler) DeleteEvent(c *gin.Context) {
 	})
 }
 
-// GetUserEvents handles getting user's events with filtering
-// GET /api/v1/events
-func (h *CalendarHandler) GetUserEvents(c *gin.Context) {
+// GetDeletedEvents handles getting events deleted by the user that can be restored
+// GET /api/v1/events/trash
+func (h *CalendarHandler) GetDeletedEvents(c *gin.Context) {
 	requestID := requestid.Get(c)
 
 	// Get user ID from JWT token
@@ -357,32 +380,141 @@ func (h *CalendarHandler) GetUserEvents(c *gin.Context) {
 		return
 	}
 
-	// Parse filter parameters
-	var filter models.EventFilterRequest
-	if err := c.ShouldBindQuery(&filter); err != nil {
+	// Parse filter, sorting and pagination parameters
+	filter, ok := bindEventFilter(c, requestID, userID, models.EventTrashListOptions)
+	if !ok {
+		return
+	}
+
+	eventList, err := h.calendarUsecase.GetDeletedEvents(userID, filter)
+	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
 			"user_id":    userID,
 			"error":      err.Error(),
-		}).Warn("Invalid filter parameters")
+		}).Error("Failed to get deleted events")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get deleted events",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":      eventList.Events,
+		"total":       eventList.Total,
+		"limit":       eventList.Limit,
+		"offset":      eventList.Offset,
+		"next_cursor": eventList.NextCursor,
+		"filters":     eventList.Filters,
+		"request_id":  requestID,
+	})
+}
+
+// RestoreEvent handles restoring a deleted event from trash
+// POST /api/v1/events/:id/restore
+func (h *CalendarHandler) RestoreEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse event ID from URL parameter
+	idStr := c.Param("id")
+	eventID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   idStr,
+			"error":      err.Error(),
+		}).Warn("Invalid event ID")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid filter parameters",
+			"error":      "Invalid event ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	event, err := h.calendarUsecase.RestoreEvent(userID, uint(eventID))
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to restore event")
+
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "event not found" {
+			statusCode = http.StatusNotFound
+		} else if containsAccessDeniedError(err.Error()) {
+			statusCode = http.StatusForbidden
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to restore event",
 			"details":    err.Error(),
 			"request_id": requestID,
 		})
 		return
 	}
 
-	// Set default pagination if not provided
-	if filter.Limit <= 0 {
-		filter.Limit = 20
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"event_id":   eventID,
+	}).Info("Event restored successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Event restored successfully",
+		"event":      event,
+		"request_id": requestID,
+	})
+}
+
+// GetUserEvents handles getting user's events with filtering
+// GET /api/v1/events
+func (h *CalendarHandler) GetUserEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
 	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
+
+	// Parse filter, sorting and pagination parameters
+	filter, ok := bindEventFilter(c, requestID, userID, models.EventListOptions)
+	if !ok {
+		return
 	}
 
-	eventList, err := h.calendarUsecase.GetUserEvents(userID, &filter)
+	eventList, err := h.calendarUsecase.GetUserEvents(userID, filter)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -399,12 +531,13 @@ func (h *CalendarHandler) GetUserEvents(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"events":     eventList.Events,
-		"total":      eventList.Total,
-		"limit":      eventList.Limit,
-		"offset":     eventList.Offset,
-		"filters":    eventList.Filters,
-		"request_id": requestID,
+		"events":      eventList.Events,
+		"total":       eventList.Total,
+		"limit":       eventList.Limit,
+		"offset":      eventList.Offset,
+		"next_cursor": eventList.NextCursor,
+		"filters":     eventList.Filters,
+		"request_id":  requestID,
 	})
 }
 
@@ -438,8 +571,8 @@ func (h *CalendarHandler) GetUserCalendar(c *gin.Context) {
 		}).Warn("Invalid calendar parameters")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid calendar parameters",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_calendar_parameters"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -508,24 +641,13 @@ func (h *CalendarHandler) SearchEvents(c *gin.Context) {
 		return
 	}
 
-	// Parse filter parameters
-	var filter models.EventFilterRequest
-	if err := c.ShouldBindQuery(&filter); err != nil {
-		logger.WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"user_id":    userID,
-			"error":      err.Error(),
-		}).Warn("Invalid filter parameters")
-
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid filter parameters",
-			"details":    err.Error(),
-			"request_id": requestID,
-		})
+	// Parse filter, sorting and pagination parameters
+	filter, ok := bindEventFilter(c, requestID, userID, models.EventListOptions)
+	if !ok {
 		return
 	}
 
-	eventList, err := h.calendarUsecase.SearchEvents(userID, searchQuery, &filter)
+	eventList, err := h.calendarUsecase.SearchEvents(userID, searchQuery, filter)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -548,12 +670,13 @@ func (h *CalendarHandler) SearchEvents(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"events":     eventList.Events,
-		"total":      eventList.Total,
-		"limit":      eventList.Limit,
-		"offset":     eventList.Offset,
-		"query":      searchQuery,
-		"request_id": requestID,
+		"events":      eventList.Events,
+		"total":       eventList.Total,
+		"limit":       eventList.Limit,
+		"offset":      eventList.Offset,
+		"next_cursor": eventList.NextCursor,
+		"query":       searchQuery,
+		"request_id":  requestID,
 	})
 }
 
@@ -605,8 +728,8 @@ func (h *CalendarHandler) InviteParticipants(c *gin.Context) {
 		}).Warn("Invalid request body for invite participants")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -795,8 +918,8 @@ func (h *CalendarHandler) UpdateParticipantStatus(c *gin.Context) {
 		}).Warn("Invalid request body for update participant status")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -890,8 +1013,8 @@ func (h *CalendarHandler) SetReminder(c *gin.Context) {
 		}).Warn("Invalid request body for set reminder")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -1109,8 +1232,8 @@ func (h *CalendarHandler) CheckTimeConflict(c *gin.Context) {
 		}).Warn("Invalid request body for check conflict")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -1208,6 +1331,28 @@ func containsConflictError(errMsg string) bool {
 	return false
 }
 
+// containsVersionConflictError checks if the error message reports a stale event version
+func containsVersionConflictError(errMsg string) bool {
+	return containsKeyword(errMsg, "version conflict")
+}
+
+// respondVersionConflict responds with 409 and the current event so the client can reapply its changes
+func (h *CalendarHandler) respondVersionConflict(c *gin.Context, requestID string, userID, eventID uint, err error) {
+	response := gin.H{
+		"error":      "Event was modified by another request",
+		"details":    err.Error(),
+		"request_id": requestID,
+	}
+
+	current, getErr := h.calendarUsecase.GetEventByID(userID, eventID)
+	if getErr == nil {
+		middleware.SetVersionETag(c, current.Version)
+		response["event"] = current
+	}
+
+	c.JSON(http.StatusConflict, response)
+}
+
 // containsKeyword checks if a string contains a keyword (case-insensitive)
 func containsKeyword(text, keyword string) bool {
 	return len(text) >= len(keyword) &&
@@ -1225,3 +1370,29 @@ func containsSubstring(text, substring string) bool {
 	}
 	return false
 }
+
+// bindEventFilter binds event filter parameters together with shared pagination,
+// sorting and filtering parameters, responding with 400 if they are invalid
+func bindEventFilter(c *gin.Context, requestID string, userID uint, opts *query.Options) (*models.EventFilterRequest, bool) {
+	var filter models.EventFilterRequest
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		filter.Page, err = query.Parse(c.Request.URL.Query(), opts)
+	}
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid filter parameters")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_filter_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+
+	return &filter, true
+}
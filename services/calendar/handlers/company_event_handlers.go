@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCompanyEvents handles listing company events of all statuses (managers and above)
+// GET /api/v1/calendar/company-events
+func (h *CalendarHandler) GetCompanyEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var filter models.CompanyEventFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query parameters",
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	companyEvents, total, err := h.calendarUsecase.GetCompanyEvents(&filter)
+	if err != nil {
+		respondCompanyEventError(c, requestID, 0, err, "Failed to get company events")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"company_events": companyEvents,
+		"total":          total,
+		"limit":          filter.Limit,
+		"offset":         filter.Offset,
+		"request_id":     requestID,
+	})
+}
+
+// GetCompanyEvent handles getting a company event (managers and above)
+// GET /api/v1/calendar/company-events/:id
+func (h *CalendarHandler) GetCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	companyEventID, ok := parseCompanyEventID(c, requestID)
+	if !ok {
+		return
+	}
+
+	companyEvent, err := h.calendarUsecase.GetCompanyEvent(companyEventID)
+	if err != nil {
+		respondCompanyEventError(c, requestID, companyEventID, err, "Failed to get company event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"company_event": companyEvent,
+		"request_id":    requestID,
+	})
+}
+
+// CreateCompanyEvent handles drafting a company event (managers and above)
+// POST /api/v1/calendar/company-events
+func (h *CalendarHandler) CreateCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCompanyEventRequest
+	if !bindCompanyEventRequest(c, requestID, &req) {
+		return
+	}
+
+	companyEvent, err := h.calendarUsecase.CreateCompanyEvent(userID, &req)
+	if err != nil {
+		respondCompanyEventError(c, requestID, 0, err, "Failed to create company event")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Company event created successfully",
+		"company_event": companyEvent,
+		"request_id":    requestID,
+	})
+}
+
+// UpdateCompanyEvent handles changing a company event, published ones only by admins
+// PUT /api/v1/calendar/company-events/:id
+func (h *CalendarHandler) UpdateCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, companyEventID, ok := parseCompanyEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCompanyEventRequest
+	if !bindCompanyEventRequest(c, requestID, &req) {
+		return
+	}
+
+	companyEvent, err := h.calendarUsecase.UpdateCompanyEvent(userID, canPublishCompanyEvents(c), companyEventID, &req)
+	if err != nil {
+		respondCompanyEventError(c, requestID, companyEventID, err, "Failed to update company event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Company event updated successfully",
+		"company_event": companyEvent,
+		"request_id":    requestID,
+	})
+}
+
+// DeleteCompanyEvent handles deleting a company event that was not published
+// DELETE /api/v1/calendar/company-events/:id
+func (h *CalendarHandler) DeleteCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, companyEventID, ok := parseCompanyEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarUsecase.DeleteCompanyEvent(userID, canPublishCompanyEvents(c), companyEventID); err != nil {
+		respondCompanyEventError(c, requestID, companyEventID, err, "Failed to delete company event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Company event deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// SubmitCompanyEvent handles sending a draft company event for review
+// POST /api/v1/calendar/company-events/:id/submit
+func (h *CalendarHandler) SubmitCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, companyEventID, ok := parseCompanyEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	companyEvent, err := h.calendarUsecase.SubmitCompanyEvent(userID, canPublishCompanyEvents(c), companyEventID)
+	if err != nil {
+		respondCompanyEventError(c, requestID, companyEventID, err, "Failed to submit company event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Company event submitted for review",
+		"company_event": companyEvent,
+		"request_id":    requestID,
+	})
+}
+
+// RejectCompanyEvent handles returning a company event in review to its author (admin only)
+// POST /api/v1/calendar/company-events/:id/reject
+func (h *CalendarHandler) RejectCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, companyEventID, ok := parseCompanyEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.RejectCompanyEventRequest
+	if !bindCompanyEventRequest(c, requestID, &req) {
+		return
+	}
+
+	companyEvent, err := h.calendarUsecase.RejectCompanyEvent(userID, companyEventID, &req)
+	if err != nil {
+		respondCompanyEventError(c, requestID, companyEventID, err, "Failed to reject company event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Company event returned to draft",
+		"company_event": companyEvent,
+		"request_id":    requestID,
+	})
+}
+
+// PublishCompanyEvent handles publishing a company event to the calendars of its audience (admin only)
+// POST /api/v1/calendar/company-events/:id/publish
+func (h *CalendarHandler) PublishCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, companyEventID, ok := parseCompanyEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	companyEvent, err := h.calendarUsecase.PublishCompanyEvent(userID, companyEventID)
+	if err != nil {
+		respondCompanyEventError(c, requestID, companyEventID, err, "Failed to publish company event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Company event published successfully",
+		"company_event": companyEvent,
+		"request_id":    requestID,
+	})
+}
+
+// CancelCompanyEvent handles cancelling a company event with a reason (admin only)
+// POST /api/v1/calendar/company-events/:id/cancel
+func (h *CalendarHandler) CancelCompanyEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, companyEventID, ok := parseCompanyEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CancelEventRequest
+	if !bindCompanyEventRequest(c, requestID, &req) {
+		return
+	}
+
+	companyEvent, err := h.calendarUsecase.CancelCompanyEvent(userID, companyEventID, &req)
+	if err != nil {
+		respondCompanyEventError(c, requestID, companyEventID, err, "Failed to cancel company event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Company event cancelled successfully",
+		"company_event": companyEvent,
+		"request_id":    requestID,
+	})
+}
+
+// parseCompanyEventRequest extracts the user ID and the company event ID URL parameter
+func parseCompanyEventRequest(c *gin.Context, requestID string) (uint, uint, bool) {
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return 0, 0, false
+	}
+
+	companyEventID, ok := parseCompanyEventID(c, requestID)
+	if !ok {
+		return 0, 0, false
+	}
+	return userID, companyEventID, true
+}
+
+// parseCompanyEventID parses the company event ID URL parameter, responding with 400 if it is invalid
+func parseCompanyEventID(c *gin.Context, requestID string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid company event ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// bindCompanyEventRequest binds a JSON request body, responding with 400 if it is invalid
+func bindCompanyEventRequest(c *gin.Context, requestID string, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return false
+	}
+	return true
+}
+
+// canPublishCompanyEvents checks if the current user reviews and publishes company events
+func canPublishCompanyEvents(c *gin.Context) bool {
+	role, err := middleware.GetUserRoleFromContext(c)
+	if err != nil {
+		return false
+	}
+	return role == sharedmodels.RoleAdmin || role == sharedmodels.RoleSuperAdmin
+}
+
+// respondCompanyEventError maps company event errors to HTTP responses, unexpected errors are logged
+func respondCompanyEventError(c *gin.Context, requestID string, companyEventID uint, err error, message string) {
+	status := companyEventErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		logger.WithFields(map[string]interface{}{
+			"request_id":       requestID,
+			"company_event_id": companyEventID,
+			"error":            err.Error(),
+		}).Error(message)
+	}
+
+	c.JSON(status, gin.H{
+		"error":      message,
+		"details":    err.Error(),
+		"request_id": requestID,
+	})
+}
+
+// companyEventErrorStatus maps company event errors to HTTP status codes
+func companyEventErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case containsAccessDeniedError(err.Error()):
+		return http.StatusForbidden
+	case strings.HasPrefix(err.Error(), "cannot "), strings.Contains(err.Error(), "already cancelled"),
+		strings.Contains(err.Error(), "version conflict"):
+		return http.StatusConflict
+	case containsValidationError(err.Error()):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
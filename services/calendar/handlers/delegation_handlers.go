@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDelegations handles getting delegations the user granted and received
+// GET /api/v1/calendar/delegations
+func (h *CalendarHandler) GetDelegations(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	delegations, err := h.calendarUsecase.GetDelegations(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get calendar delegations")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get calendar delegations",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"granted":    delegations.Granted,
+		"received":   delegations.Received,
+		"request_id": requestID,
+	})
+}
+
+// GrantDelegation handles giving a delegate access to the user's calendar
+// PUT /api/v1/calendar/delegations/:user_id
+func (h *CalendarHandler) GrantDelegation(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, delegateID, ok := parseDelegationRequest(c, requestID, "user_id")
+	if !ok {
+		return
+	}
+
+	var req models.GrantDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"user_id":     userID,
+			"delegate_id": delegateID,
+			"error":       err.Error(),
+		}).Warn("Invalid request body for grant calendar delegation")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	delegation, err := h.calendarUsecase.GrantDelegation(userID, delegateID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"user_id":     userID,
+			"delegate_id": delegateID,
+			"error":       err.Error(),
+		}).Error("Failed to grant calendar delegation")
+
+		c.JSON(delegationErrorStatus(err), gin.H{
+			"error":      "Failed to grant calendar delegation",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":  requestID,
+		"user_id":     userID,
+		"delegate_id": delegateID,
+		"permission":  delegation.Permission,
+	}).Info("Calendar delegation granted successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Calendar delegation granted successfully",
+		"delegation": delegation,
+		"request_id": requestID,
+	})
+}
+
+// RevokeDelegation handles taking access to the user's calendar away from a delegate
+// DELETE /api/v1/calendar/delegations/:user_id
+func (h *CalendarHandler) RevokeDelegation(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, delegateID, ok := parseDelegationRequest(c, requestID, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.calendarUsecase.RevokeDelegation(userID, delegateID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"user_id":     userID,
+			"delegate_id": delegateID,
+			"error":       err.Error(),
+		}).Error("Failed to revoke calendar delegation")
+
+		c.JSON(delegationErrorStatus(err), gin.H{
+			"error":      "Failed to revoke calendar delegation",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":  requestID,
+		"user_id":     userID,
+		"delegate_id": delegateID,
+	}).Info("Calendar delegation revoked successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Calendar delegation revoked successfully",
+		"request_id": requestID,
+	})
+}
+
+// GetDelegationAudit handles getting the audit log of the user's delegations
+// GET /api/v1/calendar/delegations/audit
+func (h *CalendarHandler) GetDelegationAudit(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.DelegationAuditRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	audit, err := h.calendarUsecase.GetDelegationAudit(userID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get delegation audit log")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get delegation audit log",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries":    audit.Entries,
+		"total":      audit.Total,
+		"limit":      audit.Limit,
+		"offset":     audit.Offset,
+		"request_id": requestID,
+	})
+}
+
+// GetDelegatedCalendar handles getting the calendar of a user who delegated it to the current user
+// GET /api/v1/calendar/delegated/:owner_id
+func (h *CalendarHandler) GetDelegatedCalendar(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ownerID, ok := parseDelegationRequest(c, requestID, "owner_id")
+	if !ok {
+		return
+	}
+
+	var calendarReq models.CalendarViewRequest
+	if err := c.ShouldBindQuery(&calendarReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_calendar_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	eventList, err := h.calendarUsecase.GetDelegatedCalendar(userID, ownerID, calendarReq.StartDate, calendarReq.EndDate)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"owner_id":   ownerID,
+			"error":      err.Error(),
+		}).Error("Failed to get delegated calendar")
+
+		c.JSON(delegationErrorStatus(err), gin.H{
+			"error":      "Failed to get calendar",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"owner_id":   ownerID,
+		"events":     eventList.Events,
+		"total":      eventList.Total,
+		"start_date": calendarReq.StartDate,
+		"end_date":   calendarReq.EndDate,
+		"view_type":  calendarReq.ViewType,
+		"request_id": requestID,
+	})
+}
+
+// CreateEventOnBehalf handles creating an event in the calendar of a user who delegated it to the current user
+// POST /api/v1/calendar/delegated/:owner_id/events
+func (h *CalendarHandler) CreateEventOnBehalf(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ownerID, ok := parseDelegationRequest(c, requestID, "owner_id")
+	if !ok {
+		return
+	}
+
+	var req models.CreateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"owner_id":   ownerID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for create event on behalf")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	event, err := h.calendarUsecase.CreateEventOnBehalf(userID, ownerID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"owner_id":   ownerID,
+			"error":      err.Error(),
+		}).Error("Failed to create event on behalf")
+
+		c.JSON(delegationErrorStatus(err), gin.H{
+			"error":      "Failed to create event",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"owner_id":   ownerID,
+		"event_id":   event.ID,
+	}).Info("Event created on behalf successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Event created successfully",
+		"event":      event,
+		"request_id": requestID,
+	})
+}
+
+// RespondOnBehalf handles answering an invitation of a user who delegated their calendar to the current user
+// PUT /api/v1/calendar/delegated/:owner_id/events/:id/status
+func (h *CalendarHandler) RespondOnBehalf(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ownerID, ok := parseDelegationRequest(c, requestID, "owner_id")
+	if !ok {
+		return
+	}
+	_, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateParticipantStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.calendarUsecase.RespondOnBehalf(userID, ownerID, eventID, &req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"owner_id":   ownerID,
+			"event_id":   eventID,
+			"status":     req.Status,
+			"error":      err.Error(),
+		}).Error("Failed to respond on behalf")
+
+		c.JSON(delegationErrorStatus(err), gin.H{
+			"error":      "Failed to update participant status",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"owner_id":   ownerID,
+		"event_id":   eventID,
+		"status":     req.Status,
+	}).Info("Participant status updated on behalf successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Participant status updated successfully",
+		"request_id": requestID,
+	})
+}
+
+// parseDelegationRequest extracts the current user ID and the ID of the other side of a delegation from param
+func parseDelegationRequest(c *gin.Context, requestID, param string) (uint, uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	otherID, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil || otherID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid user ID",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	return userID, uint(otherID), true
+}
+
+// delegationErrorStatus maps errors of calendar delegation to HTTP status codes
+func delegationErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case containsAccessDeniedError(err.Error()), strings.Contains(err.Error(), "not a participant"):
+		return http.StatusForbidden
+	case containsConflictError(err.Error()):
+		return http.StatusConflict
+	case containsValidationError(err.Error()):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
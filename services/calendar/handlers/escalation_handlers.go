@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// SetEventEscalation handles configuring RSVP reminder escalation of an event
+// PUT /api/v1/events/:id/escalation
+func (h *CalendarHandler) SetEventEscalation(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.SetEscalationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for set event escalation")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	escalation, err := h.calendarUsecase.SetEventEscalation(userID, eventID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to set event escalation")
+
+		c.JSON(organizedEventErrorStatus(err), gin.H{
+			"error":      "Failed to set reminder escalation",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":   requestID,
+		"user_id":      userID,
+		"event_id":     eventID,
+		"hours_before": req.HoursBefore,
+		"priority":     req.Priority,
+	}).Info("Event escalation configured successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reminder escalation configured successfully",
+		"escalation": escalation,
+		"request_id": requestID,
+	})
+}
+
+// GetEventEscalation handles getting RSVP reminder escalation of an event
+// GET /api/v1/events/:id/escalation
+func (h *CalendarHandler) GetEventEscalation(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	escalation, err := h.calendarUsecase.GetEventEscalation(userID, eventID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to get event escalation")
+
+		c.JSON(organizedEventErrorStatus(err), gin.H{
+			"error":      "Failed to get reminder escalation",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"escalation": escalation,
+		"request_id": requestID,
+	})
+}
+
+// RemoveEventEscalation handles disabling RSVP reminder escalation of an event
+// DELETE /api/v1/events/:id/escalation
+func (h *CalendarHandler) RemoveEventEscalation(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarUsecase.RemoveEventEscalation(userID, eventID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to remove event escalation")
+
+		c.JSON(organizedEventErrorStatus(err), gin.H{
+			"error":      "Failed to remove reminder escalation",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"event_id":   eventID,
+	}).Info("Event escalation removed successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reminder escalation removed successfully",
+		"request_id": requestID,
+	})
+}
+
+// parseEventRequest gets the user ID from JWT token and the event ID from URL parameter,
+// responding with an error if either is missing
+func parseEventRequest(c *gin.Context, requestID string) (uint, uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	idStr := c.Param("id")
+	eventID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   idStr,
+			"error":      err.Error(),
+		}).Warn("Invalid event ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid event ID",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	return userID, uint(eventID), true
+}
+
+// organizedEventErrorStatus maps errors of event settings managed by the organizer, such as reminder
+// escalation and visibility, to HTTP status codes
+func organizedEventErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case containsAccessDeniedError(err.Error()):
+		return http.StatusForbidden
+	case containsValidationError(err.Error()):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
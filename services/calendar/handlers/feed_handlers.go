@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// feedCacheControl lets polling clients reuse the feed for the advertised refresh interval
+// and revalidate with ETag afterwards
+const feedCacheControl = "private, max-age=900, must-revalidate"
+
+// GetCalendarFeed handles getting the user's calendar feed URL and settings
+// GET /api/v1/calendar/feed
+func (h *CalendarHandler) GetCalendarFeed(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	feed, err := h.calendarUsecase.GetCalendarFeed(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get calendar feed")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get calendar feed",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	h.fillFeedURLs(c, feed)
+
+	c.JSON(http.StatusOK, gin.H{
+		"feed":       feed,
+		"request_id": requestID,
+	})
+}
+
+// UpdateCalendarFeed handles changing calendar feed settings
+// PATCH /api/v1/calendar/feed
+func (h *CalendarHandler) UpdateCalendarFeed(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.UpdateCalendarFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update calendar feed")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	feed, err := h.calendarUsecase.UpdateCalendarFeed(userID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to update calendar feed")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to update calendar feed",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	h.fillFeedURLs(c, feed)
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"busy_only":  feed.BusyOnly,
+	}).Info("Calendar feed updated successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Calendar feed updated successfully",
+		"feed":       feed,
+		"request_id": requestID,
+	})
+}
+
+// RegenerateCalendarFeedToken handles replacing the calendar feed token
+// POST /api/v1/calendar/feed/regenerate
+func (h *CalendarHandler) RegenerateCalendarFeedToken(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	feed, err := h.calendarUsecase.RegenerateCalendarFeedToken(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to regenerate calendar feed token")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to regenerate calendar feed token",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	h.fillFeedURLs(c, feed)
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+	}).Info("Calendar feed token regenerated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Calendar feed token regenerated, previous feed URL no longer works",
+		"feed":       feed,
+		"request_id": requestID,
+	})
+}
+
+// GetCalendarFeedICS handles fetching the iCalendar feed by its secret token.
+// The token authenticates the request, so the route does not require JWT.
+// GET /api/v1/calendar/feed/:token.ics
+func (h *CalendarHandler) GetCalendarFeedICS(c *gin.Context) {
+	requestID := requestid.Get(c)
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	content, err := h.calendarUsecase.RenderCalendarFeed(token)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"error":      err.Error(),
+			}).Error("Failed to render calendar feed")
+		}
+
+		c.String(statusCode, http.StatusText(statusCode))
+		return
+	}
+
+	c.Header("Cache-Control", feedCacheControl)
+	c.Header("ETag", content.ETag)
+	c.Header("Last-Modified", content.LastModified.Format(http.TimeFormat))
+
+	if match := c.GetHeader("If-None-Match"); match != "" && etagMatches(match, content.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", content.Data)
+}
+
+// fillFeedURLs sets feed URLs from the configured public base URL or the request address.
+// URLs are only known together with the token, when it is created or regenerated.
+func (h *CalendarHandler) fillFeedURLs(c *gin.Context, feed *models.CalendarFeedResponse) {
+	if feed.Token == "" {
+		return
+	}
+
+	baseURL := h.feedBaseURL
+	if baseURL == "" {
+		scheme := "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+		if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+			scheme = proto
+		}
+
+		host := c.Request.Host
+		if forwardedHost := c.GetHeader("X-Forwarded-Host"); forwardedHost != "" {
+			host = forwardedHost
+		}
+
+		baseURL = scheme + "://" + host
+	}
+
+	feed.URL = strings.TrimRight(baseURL, "/") + "/api/v1/calendar/feed/" + feed.Token + ".ics"
+	feed.WebcalURL = "webcal://" + feed.URL[strings.Index(feed.URL, "://")+3:]
+}
+
+// etagMatches checks if an If-None-Match header value lists etag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// CancelEvent handles cancelling an event with a reason
+// POST /api/v1/events/:id/cancel
+func (h *CalendarHandler) CancelEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CancelEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for cancel event")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	event, receipt, err := h.calendarUsecase.CancelEvent(userID, eventID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to cancel event")
+
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, eventID, err)
+			return
+		}
+
+		c.JSON(lifecycleErrorStatus(err), gin.H{
+			"error":      "Failed to cancel event",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"event_id":   eventID,
+	}).Info("Event cancelled successfully")
+
+	middleware.SetVersionETag(c, event.Version)
+	// Participants are notified once the cancellation can no longer be undone
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Event cancelled successfully",
+		"event":      event,
+		"undo":       receipt,
+		"request_id": requestID,
+	})
+}
+
+// RescheduleEvent handles moving an event to a new time
+// POST /api/v1/events/:id/reschedule
+func (h *CalendarHandler) RescheduleEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for reschedule event")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	event, err := h.calendarUsecase.RescheduleEvent(userID, eventID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to reschedule event")
+
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, eventID, err)
+			return
+		}
+
+		c.JSON(lifecycleErrorStatus(err), gin.H{
+			"error":      "Failed to reschedule event",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"event_id":   eventID,
+		"start_time": req.StartTime,
+		"end_time":   req.EndTime,
+	}).Info("Event rescheduled successfully")
+
+	middleware.SetVersionETag(c, event.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Event rescheduled successfully",
+		"event":      event,
+		"request_id": requestID,
+	})
+}
+
+// GetEventReschedules handles getting reschedule history of an event
+// GET /api/v1/events/:id/reschedules
+func (h *CalendarHandler) GetEventReschedules(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	reschedules, err := h.calendarUsecase.GetEventReschedules(userID, eventID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to get event reschedules")
+
+		c.JSON(lifecycleErrorStatus(err), gin.H{
+			"error":      "Failed to get reschedule history",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reschedules": reschedules,
+		"total":       len(reschedules),
+		"request_id":  requestID,
+	})
+}
+
+// lifecycleErrorStatus maps cancellation and rescheduling errors to HTTP status codes
+func lifecycleErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case containsAccessDeniedError(err.Error()):
+		return http.StatusForbidden
+	case containsValidationError(err.Error()):
+		return http.StatusBadRequest
+	case containsConflictError(err.Error()), strings.Contains(err.Error(), "already cancelled"):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// MergeUsers handles moving calendar data of a duplicate account to the primary account
+// POST /api/v1/internal/users/merge
+func (h *CalendarHandler) MergeUsers(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req sharedmodels.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for merge users")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.calendarUsecase.MergeUsers(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":        requestID,
+			"primary_user_id":   req.PrimaryUserID,
+			"duplicate_user_id": req.DuplicateUserID,
+			"error":             err.Error(),
+		}).Error("Failed to merge users")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to merge users",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":        requestID,
+		"primary_user_id":   req.PrimaryUserID,
+		"duplicate_user_id": req.DuplicateUserID,
+		"moved":             result.Moved,
+	}).Info("Users merged in calendar service")
+
+	c.JSON(http.StatusOK, gin.H{
+		"result":     result,
+		"request_id": requestID,
+	})
+}
+
+// HandleDepartmentEvent handles a department change published by the user service
+// POST /api/v1/internal/departments/events
+func (h *CalendarHandler) HandleDepartmentEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var event sharedmodels.DepartmentEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for department event")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.calendarUsecase.HandleDepartmentEvent(&event); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":    requestID,
+			"type":          event.Type,
+			"department_id": event.DepartmentID,
+			"error":         err.Error(),
+		}).Error("Failed to handle department event")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Department event handled",
+		"request_id": requestID,
+	})
+}
+
+// GetDigestStats handles counting events of a user for the daily digest of the notification service
+// POST /api/v1/internal/users/digest-stats
+func (h *CalendarHandler) GetDigestStats(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req sharedmodels.DigestStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	stats, err := h.calendarUsecase.GetDigestStats(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    req.UserID,
+			"error":      err.Error(),
+		}).Error("Failed to get digest stats")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to get digest stats",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats":      stats,
+		"request_id": requestID,
+	})
+}
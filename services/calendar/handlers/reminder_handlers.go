@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetEventReminders handles listing reminders the user set on an event
+// GET /api/v1/events/:id/reminders
+func (h *CalendarHandler) GetEventReminders(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	reminders, err := h.calendarUsecase.GetEventReminders(userID, eventID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to get event reminders")
+
+		c.JSON(reminderErrorStatus(err), gin.H{
+			"error":      "Failed to get reminders",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reminders":  reminders,
+		"request_id": requestID,
+	})
+}
+
+// SetEventReminders handles replacing all reminders the user set on an event
+// PUT /api/v1/events/:id/reminders
+func (h *CalendarHandler) SetEventReminders(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.SetRemindersRequest
+	if !bindRemindersRequest(c, requestID, userID, &req) {
+		return
+	}
+
+	reminders, err := h.calendarUsecase.SetEventReminders(userID, eventID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to set event reminders")
+
+		c.JSON(reminderErrorStatus(err), gin.H{
+			"error":      "Failed to set reminders",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":     requestID,
+		"user_id":        userID,
+		"event_id":       eventID,
+		"reminder_count": len(reminders),
+	}).Info("Event reminders set successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reminders set successfully",
+		"reminders":  reminders,
+		"request_id": requestID,
+	})
+}
+
+// GetReminderDefaults handles getting reminders the user gets on events they create or accept
+// GET /api/v1/calendar/settings/reminders
+func (h *CalendarHandler) GetReminderDefaults(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	defaults, err := h.calendarUsecase.GetReminderDefaults(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get default reminders")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get default reminders",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reminders":  defaults,
+		"request_id": requestID,
+	})
+}
+
+// SetReminderDefaults handles replacing default reminders of the user
+// PUT /api/v1/calendar/settings/reminders
+func (h *CalendarHandler) SetReminderDefaults(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.SetRemindersRequest
+	if !bindRemindersRequest(c, requestID, userID, &req) {
+		return
+	}
+
+	defaults, err := h.calendarUsecase.SetReminderDefaults(userID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to set default reminders")
+
+		c.JSON(reminderErrorStatus(err), gin.H{
+			"error":      "Failed to set default reminders",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":     requestID,
+		"user_id":        userID,
+		"reminder_count": len(defaults),
+	}).Info("Default reminders set successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Default reminders set successfully",
+		"reminders":  defaults,
+		"request_id": requestID,
+	})
+}
+
+// bindRemindersRequest binds a reminder list, responding with 400 if it is invalid
+func bindRemindersRequest(c *gin.Context, requestID string, userID uint, req *models.SetRemindersRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for set reminders")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return false
+	}
+	return true
+}
+
+// reminderErrorStatus maps reminder errors to HTTP status codes
+func reminderErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case containsAccessDeniedError(err.Error()):
+		return http.StatusForbidden
+	case containsValidationError(err.Error()):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
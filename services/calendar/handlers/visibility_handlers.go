@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetEventVisibility handles getting visibility of an event and users its details are shared with
+// GET /api/v1/events/:id/visibility
+func (h *CalendarHandler) GetEventVisibility(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	visibility, err := h.calendarUsecase.GetEventVisibility(userID, eventID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to get event visibility")
+
+		c.JSON(organizedEventErrorStatus(err), gin.H{
+			"error":      "Failed to get event visibility",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"visibility": visibility,
+		"request_id": requestID,
+	})
+}
+
+// UpdateEventVisibility handles changing visibility of an event and users its details are shared with
+// PUT /api/v1/events/:id/visibility
+func (h *CalendarHandler) UpdateEventVisibility(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, eventID, ok := parseEventRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateEventVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update event visibility")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	visibility, err := h.calendarUsecase.UpdateEventVisibility(userID, eventID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"event_id":   eventID,
+			"error":      err.Error(),
+		}).Error("Failed to update event visibility")
+
+		c.JSON(organizedEventErrorStatus(err), gin.H{
+			"error":      "Failed to update event visibility",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":  requestID,
+		"user_id":     userID,
+		"event_id":    eventID,
+		"visibility":  visibility.Visibility,
+		"shared_with": len(visibility.SharedWith),
+	}).Info("Event visibility updated successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Event visibility updated successfully",
+		"visibility": visibility,
+		"request_id": requestID,
+	})
+}
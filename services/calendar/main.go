@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -15,8 +16,13 @@ import (
 	"tachyon-messenger/services/calendar/usecase"
 	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/jobs"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/orgsettings"
+	"tachyon-messenger/shared/refs"
+	"tachyon-messenger/shared/undo"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -36,6 +42,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Resolve other services through the registry, endpoints are refreshed in the background
+	registry, err := config.NewRegistryFromEnv(nil)
+	if err != nil {
+		log.Fatalf("Failed to set up service registry: %v", err)
+	}
+	http.DefaultTransport = registry.Transport(http.DefaultTransport)
+	registryCtx, stopRegistry := context.WithCancel(context.Background())
+	defer stopRegistry()
+	go registry.Run(registryCtx)
+
 	log.Info("Starting Calendar service...")
 
 	// Connect to database
@@ -47,7 +63,9 @@ func main() {
 	defer db.Close()
 
 	// Run database migrations
-	if err := db.Migrate(&models.Event{}, &models.EventParticipant{}, &models.EventReminder{}); err != nil {
+	migrationModels := append(models.Models(), jobs.Models()...)
+	migrationModels = append(migrationModels, undo.Models()...)
+	if err := db.Migrate(migrationModels...); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -58,22 +76,54 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Validate request DTOs with shared rules
+	validation.Install()
+
 	// Initialize dependencies
 	eventRepo := repository.NewEventRepository(db)
 	participantRepo := repository.NewParticipantRepository(db)
 	reminderRepo := repository.NewReminderRepository(db)
+	feedRepo := repository.NewFeedRepository(db)
+	escalationRepo := repository.NewEscalationRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	absenceRepo := repository.NewAbsenceRepository(db)
+	companyEventRepo := repository.NewCompanyEventRepository(db)
+	delegationRepo := repository.NewDelegationRepository(db)
+
+	// Organization settings from the user service
+	orgSettings := orgsettings.NewClient(registry.BaseURL(config.UserService), 0)
+
+	// Validation of participant IDs against the user service
+	userRefs := refs.NewUserValidatorFromEnv(registry.BaseURL(config.UserService))
 
 	// Create JWT config
 	jwtConfig := middleware.DefaultJWTConfig(cfg.JWT.Secret)
 
+	// Network restrictions of admin endpoints
+	adminAccess, err := middleware.LoadAdminAccessConfig()
+	if err != nil {
+		log.Fatalf("Failed to load admin access config: %v", err)
+	}
+
+	// Event cancellation is staged for a short window in which it can be undone
+	undoManager := undo.NewManager("calendar", db, undo.WindowFromEnv())
+
 	// Initialize usecases
-	calendarUsecase := usecase.NewCalendarUsecase(eventRepo, participantRepo, reminderRepo)
+	notifier := usecase.NewHTTPEventNotifier(registry.BaseURL(config.NotificationService))
+	audience := usecase.NewHTTPAudienceResolver(registry.BaseURL(config.UserService))
+	userMatcher := usecase.NewHTTPUserMatcher(registry.BaseURL(config.UserService))
+	calendarUsecase := usecase.NewCalendarUsecase(eventRepo, participantRepo, reminderRepo, feedRepo, escalationRepo, holidayRepo, absenceRepo, companyEventRepo, delegationRepo, notifier, audience, userMatcher, orgSettings, userRefs, undoManager)
+
+	// Schedule background jobs
+	scheduler := jobs.NewScheduler("calendar", db, nil)
+	registerJobs(scheduler, calendarUsecase, undoManager, orgSettings, log)
+	scheduler.Start()
 
 	// Initialize handlers
-	calendarHandler := handlers.NewCalendarHandler(calendarUsecase)
+	calendarHandler := handlers.NewCalendarHandler(calendarUsecase, os.Getenv("CALENDAR_FEED_BASE_URL"))
 
 	// Setup routes
-	r := setupRoutes(calendarHandler, jwtConfig)
+	r := setupRoutes(calendarHandler, undoManager, scheduler, jwtConfig, adminAccess)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -110,12 +160,122 @@ func main() {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Wait for running background jobs
+	scheduler.Stop()
+
 	log.Info("Calendar service stopped")
 }
 
+// registerJobs schedules background jobs of the calendar service
+func registerJobs(scheduler *jobs.Scheduler, calendarUsecase usecase.CalendarUsecase, undoManager *undo.Manager, orgSettings *orgsettings.Client, log *logger.Logger) {
+	calendarJobs := []jobs.Job{
+		{
+			// Permanently delete events that stayed in trash longer than retention
+			Name:     "purge_deleted_events",
+			Schedule: trashPurgeSchedule,
+			Run: func(ctx context.Context) error {
+				retention := getTrashRetention(orgSettings)
+				jobs.Report(ctx, "retention_days", int(retention.Hours()/24))
+				purged, err := calendarUsecase.PurgeDeletedEvents(retention)
+				if err != nil {
+					return err
+				}
+				jobs.Report(ctx, "purged_count", purged)
+				if purged > 0 {
+					log.WithField("purged_count", purged).Info("Purged deleted events")
+				}
+				return nil
+			},
+		},
+		{
+			// Send event reminders whose trigger time has come
+			Name:     "send_event_reminders",
+			Schedule: reminderSchedule,
+			Run: func(ctx context.Context) error {
+				sent, err := calendarUsecase.ProcessDueReminders(time.Now())
+				jobs.Report(ctx, "sent_count", sent)
+				if err != nil {
+					return err
+				}
+				if sent > 0 {
+					log.WithField("sent_count", sent).Info("Sent event reminders")
+				}
+				return nil
+			},
+		},
+		{
+			// Remind participants who have not responded to invitations
+			Name:     "process_reminder_escalations",
+			Schedule: escalationSchedule,
+			Run: func(ctx context.Context) error {
+				reminded, err := calendarUsecase.ProcessReminderEscalations(time.Now())
+				if err != nil {
+					return err
+				}
+				if reminded > 0 {
+					log.WithField("reminded_count", reminded).Info("Sent RSVP reminders")
+				}
+				return nil
+			},
+		},
+		{
+			// Give upcoming company events to users who joined their departments and take them from users who left
+			Name:     "sync_company_event_audiences",
+			Schedule: companyEventSyncSchedule,
+			Run: func(ctx context.Context) error {
+				added, err := calendarUsecase.SyncCompanyEventAudiences(time.Now())
+				jobs.Report(ctx, "added_count", added)
+				return err
+			},
+		},
+		{
+			// Commit staged actions whose undo window elapsed
+			Name:     "commit_undo_actions",
+			Schedule: undoCommitSchedule,
+			Run: func(ctx context.Context) error {
+				committed, err := undoManager.CommitDue(time.Now())
+				jobs.Report(ctx, "committed_count", committed)
+				return err
+			},
+		},
+	}
+
+	for _, job := range calendarJobs {
+		if err := scheduler.Register(job); err != nil {
+			log.Fatalf("Failed to register background jobs: %v", err)
+		}
+	}
+}
+
+// reminderSchedule is how often due event reminders are checked
+const reminderSchedule = "* * * * *"
+
+// escalationSchedule is how often due RSVP reminder steps are checked
+const escalationSchedule = "* * * * *"
+
+// companyEventSyncSchedule is how often audiences of upcoming company events are updated
+const companyEventSyncSchedule = "@hourly"
+
+// undoCommitSchedule is how often staged actions are checked for an elapsed undo window
+const undoCommitSchedule = "@every 5s"
+
+// trashPurgeSchedule is how often expired records are purged from trash
+const trashPurgeSchedule = "@hourly"
+
+// getTrashRetention returns how long deleted events stay in trash from environment or organization settings
+func getTrashRetention(orgSettings *orgsettings.Client) time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return orgSettings.Get().Retention.TrashRetention()
+}
+
 func setupRoutes(
 	calendarHandler *handlers.CalendarHandler,
+	undoManager *undo.Manager,
+	scheduler *jobs.Scheduler,
 	jwtConfig *middleware.JWTConfig,
+	adminAccess *middleware.AdminAccessConfig,
 ) *gin.Engine {
 	r := gin.New()
 
@@ -123,12 +283,18 @@ func setupRoutes(
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Use(requestid.New())
+	r.Use(middleware.CorrelationMiddleware())
+	r.Use(middleware.BodyLimitMiddleware(middleware.DefaultBodyLimitConfig()))
+
+	// Concurrency limits of route groups, requests over them are shed with 503
+	limits := middleware.NewConcurrencyLimits("calendar-service")
 
 	// CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, If-Match")
+		c.Header("Access-Control-Expose-Headers", "ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -141,18 +307,31 @@ func setupRoutes(
 	// Health endpoint (no auth required)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"service":   "calendar-service",
-			"timestamp": time.Now().UTC(),
-			"version":   "1.0.0",
+			"status":      "healthy",
+			"service":     "calendar-service",
+			"timestamp":   time.Now().UTC(),
+			"version":     "1.0.0",
+			"concurrency": limits.Stats(),
 		})
 	})
 
+	// Load of route groups for monitoring
+	r.GET("/metrics", limits.MetricsHandler())
+
 	// API routes
 	api := r.Group("/api/v1")
 
+	// Calendar feed for external clients, authenticated by secret token in URL
+	api.GET("/calendar/feed/:token", limits.Group("api"), calendarHandler.GetCalendarFeedICS)
+
+	// Internal endpoints (for service-to-service communication)
+	api.POST("/internal/users/merge", limits.Group("internal"), calendarHandler.MergeUsers)
+	api.POST("/internal/departments/events", limits.Group("internal"), calendarHandler.HandleDepartmentEvent)
+	api.POST("/internal/users/digest-stats", limits.Group("internal"), calendarHandler.GetDigestStats)
+
 	// Protected routes (require JWT)
 	protected := api.Group("")
+	protected.Use(limits.Group("api"))
 	protected.Use(middleware.JWTMiddleware(jwtConfig))
 	{
 		// Event endpoints
@@ -162,9 +341,31 @@ func setupRoutes(
 		protected.PUT("/events/:id", calendarHandler.UpdateEvent)
 		protected.DELETE("/events/:id", calendarHandler.DeleteEvent)
 
+		// Event trash
+		protected.GET("/events/trash", calendarHandler.GetDeletedEvents)
+		protected.POST("/events/:id/restore", calendarHandler.RestoreEvent)
+
+		// Cancellation and rescheduling
+		protected.POST("/events/:id/cancel", calendarHandler.CancelEvent)
+		protected.POST("/events/:id/reschedule", calendarHandler.RescheduleEvent)
+		protected.GET("/events/:id/reschedules", calendarHandler.GetEventReschedules)
+
+		// Bulk operations with per-event results and conflicts
+		protected.POST("/events/bulk-create", calendarHandler.BulkCreateEvents)
+		protected.PATCH("/events/bulk-update", calendarHandler.BulkUpdateEvents)
+		protected.POST("/events/bulk-cancel", calendarHandler.BulkCancelEvents)
+
+		// Undo of staged event cancellations
+		undo.RegisterRoutes(protected, undoManager)
+
 		// Calendar view
 		protected.GET("/calendar", calendarHandler.GetUserCalendar)
 
+		// Calendar feed management
+		protected.GET("/calendar/feed", calendarHandler.GetCalendarFeed)
+		protected.PATCH("/calendar/feed", calendarHandler.UpdateCalendarFeed)
+		protected.POST("/calendar/feed/regenerate", calendarHandler.RegenerateCalendarFeedToken)
+
 		// Event search and stats
 		protected.GET("/events/search", calendarHandler.SearchEvents)
 		protected.GET("/events/stats", calendarHandler.GetEventStats)
@@ -172,15 +373,74 @@ func setupRoutes(
 		// Time conflict checking
 		protected.POST("/events/check-conflict", calendarHandler.CheckTimeConflict)
 
+		// Quick add: free text to an event draft, confirmed with POST /events
+		protected.POST("/events/quick-add", calendarHandler.QuickAddEvent)
+
 		// Participant management
 		protected.POST("/events/:id/participants", calendarHandler.InviteParticipants)
 		protected.DELETE("/events/:id/participants/:user_id", calendarHandler.RemoveParticipant)
 		protected.PUT("/events/:id/status", calendarHandler.UpdateParticipantStatus)
 
 		// Reminder management
+		protected.GET("/events/:id/reminders", calendarHandler.GetEventReminders)
 		protected.POST("/events/:id/reminders", calendarHandler.SetReminder)
+		protected.PUT("/events/:id/reminders", calendarHandler.SetEventReminders)
 		protected.DELETE("/events/:id/reminders/:reminder_id", calendarHandler.RemoveReminder)
+		protected.GET("/calendar/settings/reminders", calendarHandler.GetReminderDefaults)
+		protected.PUT("/calendar/settings/reminders", calendarHandler.SetReminderDefaults)
+
+		// RSVP reminder escalation
+		protected.GET("/events/:id/escalation", calendarHandler.GetEventEscalation)
+		protected.PUT("/events/:id/escalation", calendarHandler.SetEventEscalation)
+		protected.DELETE("/events/:id/escalation", calendarHandler.RemoveEventEscalation)
+
+		// Event visibility and details shared by the organizer
+		protected.GET("/events/:id/visibility", calendarHandler.GetEventVisibility)
+		protected.PUT("/events/:id/visibility", calendarHandler.UpdateEventVisibility)
+
+		// Calendar delegation, e.g. to an assistant, and actions of delegates on behalf of the owner
+		protected.GET("/calendar/delegations", calendarHandler.GetDelegations)
+		protected.GET("/calendar/delegations/audit", calendarHandler.GetDelegationAudit)
+		protected.PUT("/calendar/delegations/:user_id", calendarHandler.GrantDelegation)
+		protected.DELETE("/calendar/delegations/:user_id", calendarHandler.RevokeDelegation)
+		protected.GET("/calendar/delegated/:owner_id", calendarHandler.GetDelegatedCalendar)
+		protected.POST("/calendar/delegated/:owner_id/events", calendarHandler.CreateEventOnBehalf)
+		protected.PUT("/calendar/delegated/:owner_id/events/:id/status", calendarHandler.RespondOnBehalf)
+
+		// Public holidays, managed by admins
+		protected.GET("/calendar/holidays", calendarHandler.GetHolidays)
+		protected.POST("/calendar/holidays", middleware.RequireAdminRole(), calendarHandler.CreateHoliday)
+		protected.DELETE("/calendar/holidays/:id", middleware.RequireAdminRole(), calendarHandler.DeleteHoliday)
+
+		// Employee absences
+		protected.GET("/calendar/absences/my", calendarHandler.GetMyAbsences)
+		protected.POST("/calendar/absences", calendarHandler.CreateAbsence)
+		protected.DELETE("/calendar/absences/:id", calendarHandler.DeleteAbsence)
+		protected.POST("/calendar/absences/sync", middleware.RequireAdminRole(), calendarHandler.SyncAbsences)
+		protected.GET("/calendar/absences/team", middleware.RequireManagerOrAbove(), calendarHandler.GetTeamAbsences)
+
+		// Meeting time suggestions
+		protected.POST("/calendar/availability", calendarHandler.FindAvailability)
+
+		// Company events, drafted by managers and published by admins
+		companyEvents := protected.Group("/calendar/company-events")
+		companyEvents.Use(middleware.RequireManagerOrAbove())
+		companyEvents.GET("", calendarHandler.GetCompanyEvents)
+		companyEvents.POST("", calendarHandler.CreateCompanyEvent)
+		companyEvents.GET("/:id", calendarHandler.GetCompanyEvent)
+		companyEvents.PUT("/:id", calendarHandler.UpdateCompanyEvent)
+		companyEvents.DELETE("/:id", calendarHandler.DeleteCompanyEvent)
+		companyEvents.POST("/:id/submit", calendarHandler.SubmitCompanyEvent)
+		companyEvents.POST("/:id/reject", middleware.RequireAdminRole(), calendarHandler.RejectCompanyEvent)
+		companyEvents.POST("/:id/publish", middleware.RequireAdminRole(), calendarHandler.PublishCompanyEvent)
+		companyEvents.POST("/:id/cancel", middleware.RequireAdminRole(), calendarHandler.CancelCompanyEvent)
 	}
 
+	// Background job management (admin only)
+	admin := protected.Group("/admin")
+	admin.Use(middleware.AdminAccessMiddleware(adminAccess))
+	admin.Use(middleware.RequireAdminRole())
+	jobs.RegisterRoutes(admin, scheduler)
+
 	return r
 }
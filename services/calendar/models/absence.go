@@ -0,0 +1,179 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// AbsenceType represents the reason of an employee absence
+type AbsenceType string
+
+const (
+	AbsenceTypeVacation     AbsenceType = "vacation"
+	AbsenceTypeSickLeave    AbsenceType = "sick_leave"
+	AbsenceTypeBusinessTrip AbsenceType = "business_trip"
+	AbsenceTypeOther        AbsenceType = "other"
+)
+
+// IsValid checks if the absence type is a known type
+func (t AbsenceType) IsValid() bool {
+	switch t {
+	case AbsenceTypeVacation, AbsenceTypeSickLeave, AbsenceTypeBusinessTrip, AbsenceTypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Absence sources
+const (
+	AbsenceSourceManual = "manual"
+)
+
+// Holiday represents a public holiday of a region
+type Holiday struct {
+	models.BaseModel
+	Region    string    `gorm:"not null;size:50;uniqueIndex:idx_holidays_region_date" json:"region"`
+	Date      time.Time `gorm:"not null;type:date;uniqueIndex:idx_holidays_region_date" json:"date"`
+	Name      string    `gorm:"not null;size:255" json:"name"`
+	CreatedBy uint      `gorm:"not null" json:"created_by"`
+}
+
+// TableName returns the table name for Holiday model
+func (Holiday) TableName() string {
+	return "holidays"
+}
+
+// Absence represents an employee vacation, sick leave or other absence.
+// Dates are inclusive days; records synced from HR keep their external ID.
+type Absence struct {
+	models.BaseModel
+	UserID       uint        `gorm:"not null;index" json:"user_id"`
+	DepartmentID *uint       `gorm:"index" json:"department_id,omitempty"`
+	Type         AbsenceType `gorm:"not null;size:20" json:"type"`
+	StartDate    time.Time   `gorm:"not null;type:date;index" json:"start_date"`
+	EndDate      time.Time   `gorm:"not null;type:date;index" json:"end_date"`
+	Note         string      `gorm:"size:500" json:"note,omitempty"`
+	Source       string      `gorm:"not null;size:50;default:'manual';index:idx_absences_source_external" json:"source"`
+	ExternalID   *string     `gorm:"size:100;index:idx_absences_source_external" json:"external_id,omitempty"`
+	CreatedBy    uint        `gorm:"not null" json:"created_by"`
+}
+
+// TableName returns the table name for Absence model
+func (Absence) TableName() string {
+	return "absences"
+}
+
+// Covers checks if the absence includes any part of the period
+func (a *Absence) Covers(start, end time.Time) bool {
+	absenceEnd := a.EndDate.AddDate(0, 0, 1) // End date is inclusive
+	return a.StartDate.Before(end) && absenceEnd.After(start)
+}
+
+// CreateHolidayRequest represents request for adding a public holiday
+type CreateHolidayRequest struct {
+	Region string    `json:"region" binding:"required,min=1,max=50" validate:"required,notblank,max=50"`
+	Date   time.Time `json:"date" binding:"required" validate:"required"`
+	Name   string    `json:"name" binding:"required,min=1,max=255" validate:"required,notblank,max=255"`
+}
+
+// HolidayFilterRequest represents filtering parameters for holidays
+type HolidayFilterRequest struct {
+	Region string `form:"region" binding:"omitempty,max=50"`
+	Year   int    `form:"year" binding:"omitempty,min=1970,max=2100"`
+}
+
+// CreateAbsenceRequest represents request for recording an absence.
+// Managers and admins can record absences of other users.
+type CreateAbsenceRequest struct {
+	UserID       *uint       `json:"user_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+	DepartmentID *uint       `json:"department_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+	Type         AbsenceType `json:"type" binding:"required,oneof=vacation sick_leave business_trip other" validate:"required,enum"`
+	StartDate    time.Time   `json:"start_date" binding:"required" validate:"required"`
+	EndDate      time.Time   `json:"end_date" binding:"required" validate:"required"`
+	Note         string      `json:"note,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// AbsenceFilterRequest represents the period of listed absences
+type AbsenceFilterRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02"`
+	To   *time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// SyncAbsenceItem represents an absence record from an HR system
+type SyncAbsenceItem struct {
+	ExternalID   string      `json:"external_id" binding:"required,max=100" validate:"required,max=100"`
+	UserID       uint        `json:"user_id" binding:"required,min=1" validate:"required,min=1"`
+	DepartmentID *uint       `json:"department_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+	Type         AbsenceType `json:"type" binding:"required,oneof=vacation sick_leave business_trip other" validate:"required,enum"`
+	StartDate    time.Time   `json:"start_date" binding:"required" validate:"required"`
+	EndDate      time.Time   `json:"end_date" binding:"required" validate:"required"`
+	Note         string      `json:"note,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// SyncAbsencesRequest represents a batch of absences synced from an HR system.
+// Records are matched by source and external ID, so repeated syncs update them in place.
+type SyncAbsencesRequest struct {
+	Source   string            `json:"source" binding:"required,min=1,max=50" validate:"required,notblank,max=50"`
+	Absences []SyncAbsenceItem `json:"absences" binding:"required,min=1,max=1000,dive" validate:"required,min=1,max=1000,dive"`
+}
+
+// SyncAbsencesResponse represents the result of an HR absence sync
+type SyncAbsencesResponse struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// TeamAbsenceRequest represents request for a team absence overview
+type TeamAbsenceRequest struct {
+	DepartmentID *uint     `form:"department_id" binding:"omitempty,min=1"`
+	UserIDs      []uint    `form:"user_ids" binding:"omitempty,max=200,dive,min=1"`
+	From         time.Time `form:"from" binding:"required" time_format:"2006-01-02"`
+	To           time.Time `form:"to" binding:"required" time_format:"2006-01-02"`
+}
+
+// TeamAbsenceResponse represents absences of a team within a period
+type TeamAbsenceResponse struct {
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	Absences []*Absence          `json:"absences"`
+	ByUser   map[uint][]*Absence `json:"by_user"`
+	ByType   map[AbsenceType]int `json:"by_type"`
+}
+
+// AvailabilityRequest represents request for meeting time suggestions
+type AvailabilityRequest struct {
+	UserIDs         []uint    `json:"user_ids" binding:"required,min=1,max=50,dive,min=1" validate:"required,min=1,max=50,dive,min=1"`
+	From            time.Time `json:"from" binding:"required" validate:"required"`
+	To              time.Time `json:"to" binding:"required" validate:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=15,max=480" validate:"required,min=15,max=480"`
+	Region          string    `json:"region,omitempty" binding:"omitempty,max=50" validate:"omitempty,max=50"` // Праздники региона исключаются
+	Limit           int       `json:"limit,omitempty" binding:"omitempty,min=1,max=50" validate:"omitempty,min=1,max=50"`
+}
+
+// AvailabilitySlot represents a suggested meeting time
+type AvailabilitySlot struct {
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	AbsentUserIDs []uint    `json:"absent_user_ids,omitempty"` // Отсутствующие участники, исключённые из проверки
+}
+
+// AvailabilityResponse represents meeting time suggestions
+type AvailabilityResponse struct {
+	Slots []*AvailabilitySlot `json:"slots"`
+	Busy  []*BusyBlock        `json:"busy"` // Занятость участников, скрытые события - блоки "Busy"
+}
+
+// BusyPeriod represents a time a user is occupied by an event
+type BusyPeriod struct {
+	UserID    uint      `json:"user_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+
+	// Event taking the time, only shown to viewers who may see its details
+	EventID    uint            `json:"-"`
+	Title      string          `json:"-"`
+	Visibility EventVisibility `json:"-"`
+	IsPrivate  bool            `json:"-"`
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/undo"
+)
+
+// MaxEventOffsetMinutes limits how far bulk update moves events, one year
+const MaxEventOffsetMinutes = 365 * 24 * 60
+
+// MaxReportedConflicts limits conflicting events listed for one item of a bulk request
+const MaxReportedConflicts = 10
+
+// BulkEventStatus represents the outcome of one item of a bulk request
+type BulkEventStatus string
+
+const (
+	BulkEventCreated   BulkEventStatus = "created"
+	BulkEventMoved     BulkEventStatus = "moved"
+	BulkEventCancelled BulkEventStatus = "cancelled"
+	BulkEventConflict  BulkEventStatus = "conflict" // Пересекается с другими событиями пользователя
+	BulkEventFailed    BulkEventStatus = "failed"
+)
+
+// BulkCreateEventsRequest represents request for creating several events at once, e.g. importing
+// a training schedule. Events are validated one by one and created in order, so an event
+// overlapping an earlier one of the same request is reported as a conflict.
+type BulkCreateEventsRequest struct {
+	Events []CreateEventRequest `json:"events" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
+}
+
+// BulkUpdateEventsRequest represents request for moving a set of events by the same offset,
+// e.g. rescheduling a series manually
+type BulkUpdateEventsRequest struct {
+	EventIDs      []uint `json:"event_ids" binding:"required,min=1,max=100,unique,dive,min=1" validate:"required,min=1,max=100,unique,dive,min=1"`
+	OffsetMinutes int    `json:"offset_minutes,omitempty" binding:"min=-525600,max=525600" validate:"min=-525600,max=525600"`
+	Offset        string `json:"offset,omitempty" binding:"omitempty,max=10" validate:"omitempty,max=10"` // Например 1h, -2d, 1w, заменяет offset_minutes
+	Reason        string `json:"reason,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// Duration returns how far the events are moved, from offset if given
+func (req *BulkUpdateEventsRequest) Duration() (time.Duration, error) {
+	minutes := req.OffsetMinutes
+	if strings.TrimSpace(req.Offset) != "" {
+		var err error
+		if minutes, err = ParseEventOffset(req.Offset); err != nil {
+			return 0, err
+		}
+	}
+	if minutes == 0 {
+		return 0, fmt.Errorf("offset is required")
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// ParseEventOffset parses a signed offset such as 30m, -1h, 2d or 1w into minutes
+func ParseEventOffset(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	sign := 1
+	if strings.HasPrefix(value, "-") {
+		sign, value = -1, value[1:]
+	} else {
+		value = strings.TrimPrefix(value, "+")
+	}
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+
+	unit, ok := reminderUnits[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid offset %q: unit must be m, h, d or w", value)
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+
+	minutes := amount * unit
+	if amount > MaxEventOffsetMinutes || minutes > MaxEventOffsetMinutes {
+		return 0, fmt.Errorf("invalid offset %q: must be at most one year", value)
+	}
+	return sign * minutes, nil
+}
+
+// BulkCancelEventsRequest represents request for cancelling several events with a shared reason
+type BulkCancelEventsRequest struct {
+	EventIDs []uint `json:"event_ids" binding:"required,min=1,max=100,unique,dive,min=1" validate:"required,min=1,max=100,unique,dive,min=1"`
+	Reason   string `json:"reason" binding:"required,min=1,max=500" validate:"required,notblank,max=500"`
+}
+
+// EventConflict represents an event of the user overlapping an item of a bulk request
+type EventConflict struct {
+	EventID   uint      `json:"event_id"`
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// NewEventConflicts lists conflicting events
+func NewEventConflicts(events []*Event) []*EventConflict {
+	conflicts := make([]*EventConflict, 0, len(events))
+	for _, event := range events {
+		conflicts = append(conflicts, &EventConflict{
+			EventID:   event.ID,
+			Title:     event.Title,
+			StartTime: event.StartTime,
+			EndTime:   event.EndTime,
+		})
+	}
+	return conflicts
+}
+
+// BulkEventResult represents the outcome of one item of a bulk request
+type BulkEventResult struct {
+	Index     int              `json:"index"` // Позиция элемента в запросе
+	EventID   uint             `json:"event_id,omitempty"`
+	Status    BulkEventStatus  `json:"status"`
+	Error     string           `json:"error,omitempty"`
+	Conflicts []*EventConflict `json:"conflicts,omitempty"`
+	Event     *EventResponse   `json:"event,omitempty"`
+	Undo      *undo.Receipt    `json:"undo,omitempty"` // Отмена события, пока участники не уведомлены
+}
+
+// BulkEventsResponse represents per-item results of a bulk request, in request order
+type BulkEventsResponse struct {
+	Results   []*BulkEventResult `json:"results"`
+	Succeeded int                `json:"succeeded"`
+	Conflicts int                `json:"conflicts"`
+	Failed    int                `json:"failed"`
+}
+
+// Add appends an item result and counts its outcome
+func (r *BulkEventsResponse) Add(result *BulkEventResult) {
+	r.Results = append(r.Results, result)
+	switch result.Status {
+	case BulkEventConflict:
+		r.Conflicts++
+	case BulkEventFailed:
+		r.Failed++
+	default:
+		r.Succeeded++
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// CompanyEventStatus represents the publishing status of a company event
+type CompanyEventStatus string
+
+const (
+	CompanyEventStatusDraft     CompanyEventStatus = "draft"
+	CompanyEventStatusReview    CompanyEventStatus = "review"
+	CompanyEventStatusPublished CompanyEventStatus = "published"
+	CompanyEventStatusCancelled CompanyEventStatus = "cancelled"
+)
+
+// IsValid checks if the company event status is a known status
+func (s CompanyEventStatus) IsValid() bool {
+	switch s {
+	case CompanyEventStatusDraft, CompanyEventStatusReview, CompanyEventStatusPublished, CompanyEventStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// CompanyEventCategory represents the kind of a company event
+type CompanyEventCategory string
+
+const (
+	CompanyEventCategoryTownhall    CompanyEventCategory = "townhall"
+	CompanyEventCategoryTraining    CompanyEventCategory = "training"
+	CompanyEventCategoryCelebration CompanyEventCategory = "celebration"
+	CompanyEventCategoryOther       CompanyEventCategory = "other"
+)
+
+// IsValid checks if the company event category is a known category
+func (c CompanyEventCategory) IsValid() bool {
+	switch c {
+	case CompanyEventCategoryTownhall, CompanyEventCategoryTraining, CompanyEventCategoryCelebration, CompanyEventCategoryOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// CompanyEvent represents an organization-wide event such as a townhall or training.
+// Managers prepare it as a draft and submit it for review, admins publish it to the calendars
+// of all users or of the targeted departments as a read-only event linked by EventID.
+type CompanyEvent struct {
+	models.BaseModel
+	Title         string               `gorm:"not null;size:255" json:"title"`
+	Description   string               `gorm:"type:text" json:"description,omitempty"`
+	Location      string               `gorm:"size:500" json:"location,omitempty"`
+	StartTime     time.Time            `gorm:"not null;index" json:"start_time"`
+	EndTime       time.Time            `gorm:"not null;index" json:"end_time"`
+	AllDay        bool                 `gorm:"not null;default:false" json:"all_day"`
+	Category      CompanyEventCategory `gorm:"not null;default:'other';size:20" json:"category"`
+	DepartmentIDs []uint               `gorm:"type:text;serializer:json" json:"department_ids"` // Пусто - для всей компании
+	Status        CompanyEventStatus   `gorm:"not null;default:'draft';size:20;index" json:"status"`
+	CreatedBy     uint                 `gorm:"not null;index" json:"created_by"`
+
+	// Review
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	ReviewedBy  *uint      `json:"reviewed_by,omitempty"`
+	ReviewNote  string     `gorm:"size:500" json:"review_note,omitempty"`
+
+	// Publishing
+	PublishedBy *uint      `json:"published_by,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	EventID     *uint      `gorm:"index" json:"event_id,omitempty"`
+
+	// Cancellation
+	CancelReason string     `gorm:"size:500" json:"cancel_reason,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CancelledBy  *uint      `json:"cancelled_by,omitempty"`
+}
+
+// TableName returns the table name for CompanyEvent model
+func (CompanyEvent) TableName() string {
+	return "company_events"
+}
+
+// IsEditable checks if the company event can still be changed
+func (e *CompanyEvent) IsEditable() bool {
+	return e.Status != CompanyEventStatusCancelled
+}
+
+// CreateCompanyEventRequest represents request for drafting a company event.
+// No department IDs targets the whole company.
+type CreateCompanyEventRequest struct {
+	Title         string               `json:"title" binding:"required,min=1,max=255" validate:"required,notblank,max=255"`
+	Description   string               `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	Location      string               `json:"location,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	StartTime     time.Time            `json:"start_time" binding:"required" validate:"required"`
+	EndTime       time.Time            `json:"end_time" binding:"required" validate:"required"`
+	AllDay        bool                 `json:"all_day"`
+	Category      CompanyEventCategory `json:"category,omitempty" binding:"omitempty,oneof=townhall training celebration other" validate:"omitempty,enum"`
+	DepartmentIDs []uint               `json:"department_ids,omitempty" binding:"omitempty,max=100,dive,min=1" validate:"omitempty,max=100,dive,min=1"`
+}
+
+// UpdateCompanyEventRequest represents request for changing a company event.
+// Changes of a published event are propagated to the calendars of its audience.
+type UpdateCompanyEventRequest struct {
+	Title         *string               `json:"title,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,notblank,max=255"`
+	Description   *string               `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	Location      *string               `json:"location,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	StartTime     *time.Time            `json:"start_time,omitempty"`
+	EndTime       *time.Time            `json:"end_time,omitempty"`
+	AllDay        *bool                 `json:"all_day,omitempty"`
+	Category      *CompanyEventCategory `json:"category,omitempty" binding:"omitempty,oneof=townhall training celebration other" validate:"omitempty,enum"`
+	DepartmentIDs *[]uint               `json:"department_ids,omitempty" binding:"omitempty,max=100,dive,min=1"`
+}
+
+// RejectCompanyEventRequest represents request for returning a company event in review to its author
+type RejectCompanyEventRequest struct {
+	Note string `json:"note" binding:"required,min=1,max=500" validate:"required,notblank,max=500"`
+}
+
+// CompanyEventFilterRequest represents query parameters for listing company events
+type CompanyEventFilterRequest struct {
+	Status     *CompanyEventStatus `form:"status" binding:"omitempty,oneof=draft review published cancelled"`
+	StartAfter *time.Time          `form:"start_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int                 `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset     int                 `form:"offset" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+// DelegationPermission defines what a delegate may do with the owner's calendar
+type DelegationPermission string
+
+const (
+	DelegationPermissionView   DelegationPermission = "view"   // Просмотр календаря
+	DelegationPermissionManage DelegationPermission = "manage" // Просмотр, создание событий и ответы на приглашения от имени владельца
+)
+
+// IsValid checks if the delegation permission is valid
+func (p DelegationPermission) IsValid() bool {
+	switch p {
+	case DelegationPermissionView, DelegationPermissionManage:
+		return true
+	}
+	return false
+}
+
+// Allows checks if the permission includes required, manage includes view
+func (p DelegationPermission) Allows(required DelegationPermission) bool {
+	return p == required || p == DelegationPermissionManage
+}
+
+// DelegationAction is an entry type of the delegation audit log
+type DelegationAction string
+
+const (
+	DelegationActionGranted        DelegationAction = "granted"
+	DelegationActionUpdated        DelegationAction = "updated"
+	DelegationActionRevoked        DelegationAction = "revoked"
+	DelegationActionEventCreated   DelegationAction = "event_created"
+	DelegationActionEventResponded DelegationAction = "event_responded"
+)
+
+// CalendarDelegation lets a delegate, e.g. an assistant, view and manage the owner's calendar.
+// Revoked delegations are kept for the audit log, an owner has at most one active delegation per delegate.
+type CalendarDelegation struct {
+	ID         uint                 `gorm:"primaryKey" json:"id"`
+	OwnerID    uint                 `gorm:"not null;index" json:"owner_id"`
+	DelegateID uint                 `gorm:"not null;index" json:"delegate_id"`
+	Permission DelegationPermission `gorm:"not null;size:20" json:"permission"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+	RevokedAt  *time.Time           `gorm:"index" json:"revoked_at,omitempty"`
+	RevokedBy  *uint                `json:"revoked_by,omitempty"`
+}
+
+// TableName returns the table name for CalendarDelegation model
+func (CalendarDelegation) TableName() string {
+	return "calendar_delegations"
+}
+
+// IsActive checks if the delegation was not revoked
+func (d *CalendarDelegation) IsActive() bool {
+	return d.RevokedAt == nil
+}
+
+// CalendarDelegationAudit records changes of a delegation and actions the delegate took on behalf of the owner
+type CalendarDelegationAudit struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	DelegationID uint             `gorm:"not null;index" json:"delegation_id"`
+	OwnerID      uint             `gorm:"not null;index" json:"owner_id"`
+	DelegateID   uint             `gorm:"not null;index" json:"delegate_id"`
+	ActorID      uint             `gorm:"not null" json:"actor_id"`
+	Action       DelegationAction `gorm:"not null;size:30" json:"action"`
+	EventID      *uint            `json:"event_id,omitempty"`
+	Details      string           `gorm:"size:255" json:"details,omitempty"`
+	CreatedAt    time.Time        `gorm:"index" json:"created_at"`
+}
+
+// TableName returns the table name for CalendarDelegationAudit model
+func (CalendarDelegationAudit) TableName() string {
+	return "calendar_delegation_audit"
+}
+
+// GrantDelegationRequest represents request for granting or changing access of a delegate to the user's calendar
+type GrantDelegationRequest struct {
+	Permission DelegationPermission `json:"permission" binding:"required,oneof=view manage" validate:"required,enum"`
+}
+
+// DelegationListResponse represents delegations granted by the user and to the user
+type DelegationListResponse struct {
+	Granted  []*CalendarDelegation `json:"granted"`  // Кому пользователь доверил свой календарь
+	Received []*CalendarDelegation `json:"received"` // Чьими календарями пользователь управляет
+}
+
+// DelegationAuditListResponse represents a page of the delegation audit log
+type DelegationAuditListResponse struct {
+	Entries []*CalendarDelegationAudit `json:"entries"`
+	Total   int64                      `json:"total"`
+	Limit   int                        `json:"limit"`
+	Offset  int                        `json:"offset"`
+}
+
+// DelegationAuditRequest represents pagination of the delegation audit log
+type DelegationAuditRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// ReminderPriority represents how far the RSVP reminder chain escalates
+type ReminderPriority string
+
+const (
+	ReminderPriorityLow      ReminderPriority = "low"      // Только уведомление в приложении
+	ReminderPriorityMedium   ReminderPriority = "medium"   // Уведомление, затем email
+	ReminderPriorityHigh     ReminderPriority = "high"     // Уведомление, email, затем SMS
+	ReminderPriorityCritical ReminderPriority = "critical" // Как high, с критическим приоритетом доставки
+)
+
+// IsValid checks if the reminder priority is a known priority
+func (p ReminderPriority) IsValid() bool {
+	switch p {
+	case ReminderPriorityLow, ReminderPriorityMedium, ReminderPriorityHigh, ReminderPriorityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventEscalationRule represents one step of the RSVP reminder chain of an event.
+// Participants who still have not responded when the step is due are reminded through its channel.
+type EventEscalationRule struct {
+	models.BaseModel
+	EventID       uint             `gorm:"not null;index" json:"event_id"`
+	Step          int              `gorm:"not null" json:"step"`
+	Channel       ReminderType     `gorm:"not null;size:20" json:"channel"`
+	MinutesBefore int              `gorm:"not null" json:"minutes_before"`
+	Priority      ReminderPriority `gorm:"not null;size:20" json:"priority"`
+
+	// Associations
+	Event *Event `gorm:"foreignKey:EventID" json:"-"`
+}
+
+// TableName returns the table name for EventEscalationRule model
+func (EventEscalationRule) TableName() string {
+	return "event_escalation_rules"
+}
+
+// EventEscalationDelivery records that a participant was reminded by an escalation step
+type EventEscalationDelivery struct {
+	models.BaseModel
+	RuleID  uint      `gorm:"not null;uniqueIndex:idx_escalation_delivery_rule_user" json:"rule_id"`
+	UserID  uint      `gorm:"not null;uniqueIndex:idx_escalation_delivery_rule_user" json:"user_id"`
+	EventID uint      `gorm:"not null;index" json:"event_id"`
+	SentAt  time.Time `gorm:"not null" json:"sent_at"`
+}
+
+// TableName returns the table name for EventEscalationDelivery model
+func (EventEscalationDelivery) TableName() string {
+	return "event_escalation_deliveries"
+}
+
+// BuildEscalationChain builds reminder steps for participants who have not responded:
+// in-app at hoursBefore, email at half of it and SMS at a quarter, limited by priority
+func BuildEscalationChain(eventID uint, hoursBefore int, priority ReminderPriority) []*EventEscalationRule {
+	channels := []ReminderType{ReminderTypeNotification}
+	switch priority {
+	case ReminderPriorityMedium:
+		channels = append(channels, ReminderTypeEmail)
+	case ReminderPriorityHigh, ReminderPriorityCritical:
+		channels = append(channels, ReminderTypeEmail, ReminderTypeSMS)
+	}
+
+	minutes := hoursBefore * 60
+	rules := make([]*EventEscalationRule, len(channels))
+	for i, channel := range channels {
+		rules[i] = &EventEscalationRule{
+			EventID:       eventID,
+			Step:          i + 1,
+			Channel:       channel,
+			MinutesBefore: minutes,
+			Priority:      priority,
+		}
+		minutes /= 2
+	}
+
+	return rules
+}
+
+// SetEscalationRequest represents request for configuring RSVP reminder escalation of an event
+type SetEscalationRequest struct {
+	HoursBefore int              `json:"hours_before" binding:"required,min=1,max=720" validate:"required,min=1,max=720"`
+	Priority    ReminderPriority `json:"priority" binding:"required,oneof=low medium high critical" validate:"required,enum"`
+}
+
+// EscalationResponse represents RSVP reminder escalation settings of an event
+type EscalationResponse struct {
+	EventID     uint                   `json:"event_id"`
+	Enabled     bool                   `json:"enabled"`
+	HoursBefore int                    `json:"hours_before,omitempty"`
+	Priority    ReminderPriority       `json:"priority,omitempty"`
+	Rules       []*EventEscalationRule `json:"rules"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
 
 	"gorm.io/gorm"
 )
@@ -17,6 +18,34 @@ const (
 	EventTypeDeadline EventType = "deadline"
 )
 
+// IsValid checks if the event type is a known type
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventTypePersonal, EventTypeMeeting, EventTypeDeadline:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventStatus represents the lifecycle status of an event
+type EventStatus string
+
+const (
+	EventStatusActive    EventStatus = "active"
+	EventStatusCancelled EventStatus = "cancelled"
+)
+
+// IsValid checks if the event status is a known status
+func (s EventStatus) IsValid() bool {
+	switch s {
+	case EventStatusActive, EventStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
 // ParticipantStatus represents the participation status
 type ParticipantStatus string
 
@@ -27,18 +56,73 @@ const (
 	ParticipantStatusMaybe    ParticipantStatus = "maybe"
 )
 
+// IsValid checks if the participant status is a known status
+func (s ParticipantStatus) IsValid() bool {
+	switch s {
+	case ParticipantStatusPending, ParticipantStatusAccepted, ParticipantStatusDeclined, ParticipantStatusMaybe:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventVisibility controls what users who don't take part in an event see of it
+type EventVisibility string
+
+const (
+	EventVisibilityPublic  EventVisibility = "public"  // Детали видны всем
+	EventVisibilityBusy    EventVisibility = "busy"    // Остальные видят только блок "Занят"
+	EventVisibilityPrivate EventVisibility = "private" // Остальные не видят событие, время остаётся занятым
+)
+
+// IsValid checks if the event visibility is a known visibility
+func (v EventVisibility) IsValid() bool {
+	switch v {
+	case EventVisibilityPublic, EventVisibilityBusy, EventVisibilityPrivate:
+		return true
+	default:
+		return false
+	}
+}
+
+// BusyTitle replaces the title of events whose details are hidden
+const BusyTitle = "Busy"
+
+// MaxDetailGrants limits users an organizer can share details of a hidden event with
+const MaxDetailGrants = 100
+
 // ReminderType represents the type of reminder
 type ReminderType string
 
 const (
 	ReminderTypeEmail        ReminderType = "email"
-	ReminderTypeNotification ReminderType = "notification"
+	ReminderTypeNotification ReminderType = "notification" // Внутри приложения
 	ReminderTypeSMS          ReminderType = "sms"
+	ReminderTypePush         ReminderType = "push"
 )
 
+// IsValid checks if the reminder type is a known type
+func (t ReminderType) IsValid() bool {
+	switch t {
+	case ReminderTypeEmail, ReminderTypeNotification, ReminderTypeSMS, ReminderTypePush:
+		return true
+	default:
+		return false
+	}
+}
+
+// Channel returns the notification service delivery channel of the reminder type
+func (t ReminderType) Channel() string {
+	if t == ReminderTypeNotification {
+		return "in_app"
+	}
+	return string(t)
+}
+
 // Event represents a calendar event
 type Event struct {
 	models.BaseModel
+	models.VersionMeta
 	Title       string    `gorm:"not null;size:255" json:"title" validate:"required,min=1,max=255"`
 	Description string    `gorm:"type:text" json:"description,omitempty" validate:"omitempty,max=2000"`
 	StartTime   time.Time `gorm:"not null;index" json:"start_time" validate:"required"`
@@ -48,10 +132,17 @@ type Event struct {
 	Type        EventType `gorm:"not null;default:'personal';size:20" json:"type" validate:"required,oneof=personal meeting deadline"`
 	CreatedBy   uint      `gorm:"not null;index" json:"created_by" validate:"required,min=1"`
 
+	// Cancellation
+	Status       EventStatus `gorm:"not null;default:'active';size:20;index" json:"status"`
+	CancelReason string      `gorm:"size:500" json:"cancel_reason,omitempty"`
+	CancelledAt  *time.Time  `json:"cancelled_at,omitempty"`
+	CancelledBy  *uint       `json:"cancelled_by,omitempty"`
+
 	// Calendar organization
-	Color       string `gorm:"size:7;default:'#3788d8'" json:"color" validate:"omitempty,len=7"`
-	IsPrivate   bool   `gorm:"not null;default:false" json:"is_private"`
-	IsRecurring bool   `gorm:"not null;default:false" json:"is_recurring"`
+	Color       string          `gorm:"size:7;default:'#3788d8'" json:"color" validate:"omitempty,len=7"`
+	IsPrivate   bool            `gorm:"not null;default:false" json:"is_private"` // Совпадает с visibility = private, для старых клиентов
+	Visibility  EventVisibility `gorm:"not null;default:'public';size:20" json:"visibility"`
+	IsRecurring bool            `gorm:"not null;default:false" json:"is_recurring"`
 
 	// Recurrence settings (JSON stored as string)
 	RecurrenceRule string `gorm:"type:text" json:"recurrence_rule,omitempty"`
@@ -59,6 +150,12 @@ type Event struct {
 	// Task integration
 	TaskID *uint `gorm:"index" json:"task_id,omitempty" validate:"omitempty,min=1"`
 
+	// Company event this event was published from, read-only for everyone
+	CompanyEventID *uint `gorm:"index" json:"company_event_id,omitempty"`
+
+	// Delegate who created the event on behalf of CreatedBy
+	DelegateID *uint `gorm:"index" json:"delegate_id,omitempty"`
+
 	// Associations
 	Participants []EventParticipant `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"participants,omitempty"`
 	Reminders    []EventReminder    `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"reminders,omitempty"`
@@ -79,9 +176,15 @@ func (e *Event) BeforeCreate(tx *gorm.DB) error {
 	if e.Type == "" {
 		e.Type = EventTypePersonal
 	}
+	if e.Status == "" {
+		e.Status = EventStatusActive
+	}
 	if e.Color == "" {
 		e.Color = "#3788d8"
 	}
+	if e.Visibility == "" {
+		e.SetVisibility(e.EffectiveVisibility())
+	}
 
 	// Validate time logic
 	if e.EndTime.Before(e.StartTime) {
@@ -91,6 +194,49 @@ func (e *Event) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsCompanyEvent checks if the event was published from a company event and can only be changed through it
+func (e *Event) IsCompanyEvent() bool {
+	return e.CompanyEventID != nil
+}
+
+// EffectiveVisibility returns the visibility of the event. Events marked private before
+// visibility existed only have the is_private flag set.
+func (e *Event) EffectiveVisibility() EventVisibility {
+	if e.IsPrivate {
+		return EventVisibilityPrivate
+	}
+	if e.Visibility == "" {
+		return EventVisibilityPublic
+	}
+	return e.Visibility
+}
+
+// SetVisibility changes the visibility of the event keeping the is_private flag in sync
+func (e *Event) SetVisibility(visibility EventVisibility) {
+	e.Visibility = visibility
+	e.IsPrivate = visibility == EventVisibilityPrivate
+}
+
+// ToBusyResponse converts Event model to EventResponse without details, for users who only may
+// know that the time is taken
+func (e *Event) ToBusyResponse() *EventResponse {
+	return &EventResponse{
+		ID:         e.ID,
+		Title:      BusyTitle,
+		StartTime:  e.StartTime,
+		EndTime:    e.EndTime,
+		AllDay:     e.AllDay,
+		Type:       e.Type,
+		CreatedBy:  e.CreatedBy,
+		Status:     e.Status,
+		Color:      e.Color,
+		Visibility: e.EffectiveVisibility(),
+		Busy:       true,
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
+	}
+}
+
 // BeforeUpdate hook is called before updating an event
 func (e *Event) BeforeUpdate(tx *gorm.DB) error {
 	// Validate time logic
@@ -114,6 +260,7 @@ type EventParticipant struct {
 
 	// Response tracking
 	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	RespondedBy *uint      `json:"responded_by,omitempty"` // Делегат, ответивший от имени участника
 
 	// Associations
 	Event *Event `gorm:"foreignKey:EventID" json:"event,omitempty"`
@@ -148,7 +295,7 @@ type EventReminder struct {
 	models.BaseModel
 	EventID     uint         `gorm:"not null;index" json:"event_id" validate:"required"`
 	UserID      uint         `gorm:"not null;index" json:"user_id" validate:"required"`
-	Type        ReminderType `gorm:"not null;size:20" json:"type" validate:"required,oneof=email notification sms"`
+	Type        ReminderType `gorm:"not null;size:20" json:"type" validate:"required,oneof=email notification sms push"`
 	TriggerTime time.Time    `gorm:"not null;index" json:"trigger_time" validate:"required"`
 	IsSent      bool         `gorm:"not null;default:false" json:"is_sent"`
 	SentAt      *time.Time   `json:"sent_at,omitempty"`
@@ -168,6 +315,23 @@ func (EventReminder) TableName() string {
 	return "event_reminders"
 }
 
+// EventReschedule records a move of an event to a new time
+type EventReschedule struct {
+	models.BaseModel
+	EventID      uint      `gorm:"not null;index" json:"event_id"`
+	ChangedBy    uint      `gorm:"not null" json:"changed_by"`
+	OldStartTime time.Time `gorm:"not null" json:"old_start_time"`
+	OldEndTime   time.Time `gorm:"not null" json:"old_end_time"`
+	NewStartTime time.Time `gorm:"not null" json:"new_start_time"`
+	NewEndTime   time.Time `gorm:"not null" json:"new_end_time"`
+	Reason       string    `gorm:"size:500" json:"reason,omitempty"`
+}
+
+// TableName returns the table name for EventReschedule model
+func (EventReschedule) TableName() string {
+	return "event_reschedules"
+}
+
 // BeforeCreate hook is called before creating an event reminder
 func (er *EventReminder) BeforeCreate(tx *gorm.DB) error {
 	// Calculate trigger time based on minutes before if provided
@@ -184,18 +348,19 @@ func (er *EventReminder) BeforeCreate(tx *gorm.DB) error {
 
 // CreateEventRequest represents request for creating an event
 type CreateEventRequest struct {
-	Title          string    `json:"title" binding:"required,min=1,max=255" validate:"required,min=1,max=255"`
-	Description    string    `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
-	StartTime      time.Time `json:"start_time" binding:"required" validate:"required"`
-	EndTime        time.Time `json:"end_time" binding:"required" validate:"required"`
-	AllDay         bool      `json:"all_day"`
-	Location       string    `json:"location,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
-	Type           EventType `json:"type" binding:"omitempty,oneof=personal meeting deadline" validate:"omitempty,oneof=personal meeting deadline"`
-	Color          string    `json:"color,omitempty" binding:"omitempty,len=7" validate:"omitempty,len=7"`
-	IsPrivate      bool      `json:"is_private"`
-	IsRecurring    bool      `json:"is_recurring"`
-	RecurrenceRule string    `json:"recurrence_rule,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
-	TaskID         *uint     `json:"task_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+	Title          string           `json:"title" binding:"required,min=1,max=255" validate:"required,notblank,max=255"`
+	Description    string           `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	StartTime      time.Time        `json:"start_time" binding:"required" validate:"required"`
+	EndTime        time.Time        `json:"end_time" binding:"required" validate:"required"`
+	AllDay         bool             `json:"all_day"`
+	Location       string           `json:"location,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	Type           EventType        `json:"type" binding:"omitempty,oneof=personal meeting deadline" validate:"omitempty,enum"`
+	Color          string           `json:"color,omitempty" binding:"omitempty,len=7" validate:"omitempty,len=7,hexcolor"`
+	IsPrivate      bool             `json:"is_private"` // Устарело, используйте visibility
+	Visibility     *EventVisibility `json:"visibility,omitempty" binding:"omitempty,oneof=public busy private" validate:"omitempty,enum"`
+	IsRecurring    bool             `json:"is_recurring"`
+	RecurrenceRule string           `json:"recurrence_rule,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	TaskID         *uint            `json:"task_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
 
 	// Participants to invite
 	ParticipantIDs []uint `json:"participant_ids,omitempty" validate:"omitempty,dive,min=1"`
@@ -206,29 +371,45 @@ type CreateEventRequest struct {
 
 // UpdateEventRequest represents request for updating an event
 type UpdateEventRequest struct {
-	Title          *string    `json:"title,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,min=1,max=255"`
-	Description    *string    `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
-	StartTime      *time.Time `json:"start_time,omitempty"`
-	EndTime        *time.Time `json:"end_time,omitempty"`
-	AllDay         *bool      `json:"all_day,omitempty"`
-	Location       *string    `json:"location,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
-	Type           *EventType `json:"type,omitempty" binding:"omitempty,oneof=personal meeting deadline" validate:"omitempty,oneof=personal meeting deadline"`
-	Color          *string    `json:"color,omitempty" binding:"omitempty,len=7" validate:"omitempty,len=7"`
-	IsPrivate      *bool      `json:"is_private,omitempty"`
-	IsRecurring    *bool      `json:"is_recurring,omitempty"`
-	RecurrenceRule *string    `json:"recurrence_rule,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	Title          *string          `json:"title,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,notblank,max=255"`
+	Description    *string          `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	StartTime      *time.Time       `json:"start_time,omitempty"`
+	EndTime        *time.Time       `json:"end_time,omitempty"`
+	AllDay         *bool            `json:"all_day,omitempty"`
+	Location       *string          `json:"location,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	Type           *EventType       `json:"type,omitempty" binding:"omitempty,oneof=personal meeting deadline" validate:"omitempty,enum"`
+	Color          *string          `json:"color,omitempty" binding:"omitempty,len=7" validate:"omitempty,len=7,hexcolor"`
+	IsPrivate      *bool            `json:"is_private,omitempty"` // Устарело, используйте visibility
+	Visibility     *EventVisibility `json:"visibility,omitempty" binding:"omitempty,oneof=public busy private" validate:"omitempty,enum"`
+	IsRecurring    *bool            `json:"is_recurring,omitempty"`
+	RecurrenceRule *string          `json:"recurrence_rule,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	Version        *uint            `json:"version,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"` // Версия, на основе которой сделаны изменения
 }
 
 // CreateReminderRequest represents request for creating a reminder
 type CreateReminderRequest struct {
-	Type          ReminderType `json:"type" binding:"required,oneof=email notification sms" validate:"required,oneof=email notification sms"`
-	MinutesBefore int          `json:"minutes_before" binding:"required,min=0,max=43200" validate:"required,min=0,max=43200"`
+	Type          ReminderType `json:"type" binding:"required,oneof=email notification sms push" validate:"required,enum"`
+	MinutesBefore int          `json:"minutes_before" binding:"min=0,max=43200" validate:"min=0,max=43200"`
+	Offset        string       `json:"offset,omitempty" binding:"omitempty,max=10" validate:"omitempty,max=10"` // Например 10m, 1h, 1d, заменяет minutes_before
 	Message       string       `json:"message,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
 }
 
 // UpdateParticipantStatusRequest represents request for updating participant status
 type UpdateParticipantStatusRequest struct {
-	Status ParticipantStatus `json:"status" binding:"required,oneof=pending accepted declined maybe" validate:"required,oneof=pending accepted declined maybe"`
+	Status ParticipantStatus `json:"status" binding:"required,oneof=pending accepted declined maybe" validate:"required,enum"`
+}
+
+// CancelEventRequest represents request for cancelling an event
+type CancelEventRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500" validate:"required,notblank,max=500"`
+}
+
+// RescheduleEventRequest represents request for moving an event to a new time
+type RescheduleEventRequest struct {
+	StartTime time.Time `json:"start_time" binding:"required" validate:"required"`
+	EndTime   time.Time `json:"end_time" binding:"required" validate:"required"`
+	Reason    string    `json:"reason,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	Version   *uint     `json:"version,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"` // Версия, на основе которой сделаны изменения
 }
 
 // AddParticipantsRequest represents request for adding participants to an event
@@ -249,17 +430,27 @@ type EventResponse struct {
 	Location         string                      `json:"location,omitempty"`
 	Type             EventType                   `json:"type"`
 	CreatedBy        uint                        `json:"created_by"`
+	Status           EventStatus                 `json:"status"`
+	CancelReason     string                      `json:"cancel_reason,omitempty"`
+	CancelledAt      *time.Time                  `json:"cancelled_at,omitempty"`
+	CancelledBy      *uint                       `json:"cancelled_by,omitempty"`
 	Color            string                      `json:"color"`
 	IsPrivate        bool                        `json:"is_private"`
+	Visibility       EventVisibility             `json:"visibility"`
+	Busy             bool                        `json:"busy,omitempty"` // Детали скрыты, событие показано блоком "Занят"
 	IsRecurring      bool                        `json:"is_recurring"`
 	RecurrenceRule   string                      `json:"recurrence_rule,omitempty"`
 	TaskID           *uint                       `json:"task_id,omitempty"`
+	CompanyEventID   *uint                       `json:"company_event_id,omitempty"`
+	DelegateID       *uint                       `json:"delegate_id,omitempty"`
 	ParticipantCount int                         `json:"participant_count"`
 	UserStatus       ParticipantStatus           `json:"user_status,omitempty"`
 	Participants     []*EventParticipantResponse `json:"participants,omitempty"`
 	Reminders        []*EventReminderResponse    `json:"reminders,omitempty"`
+	Version          uint                        `json:"version"`
 	CreatedAt        time.Time                   `json:"created_at"`
 	UpdatedAt        time.Time                   `json:"updated_at"`
+	DeletedAt        *time.Time                  `json:"deleted_at,omitempty"` // Только для событий в корзине
 }
 
 // ToResponse converts Event model to EventResponse
@@ -274,17 +465,29 @@ func (e *Event) ToResponse() *EventResponse {
 		Location:         e.Location,
 		Type:             e.Type,
 		CreatedBy:        e.CreatedBy,
+		Status:           e.Status,
+		CancelReason:     e.CancelReason,
+		CancelledAt:      e.CancelledAt,
+		CancelledBy:      e.CancelledBy,
 		Color:            e.Color,
 		IsPrivate:        e.IsPrivate,
+		Visibility:       e.EffectiveVisibility(),
 		IsRecurring:      e.IsRecurring,
 		RecurrenceRule:   e.RecurrenceRule,
 		TaskID:           e.TaskID,
+		CompanyEventID:   e.CompanyEventID,
+		DelegateID:       e.DelegateID,
 		ParticipantCount: e.ParticipantCount,
 		UserStatus:       e.UserStatus,
+		Version:          e.Version,
 		CreatedAt:        e.CreatedAt,
 		UpdatedAt:        e.UpdatedAt,
 	}
 
+	if e.DeletedAt.Valid {
+		response.DeletedAt = &e.DeletedAt.Time
+	}
+
 	// Convert participants if they exist
 	if len(e.Participants) > 0 {
 		response.Participants = make([]*EventParticipantResponse, len(e.Participants))
@@ -313,6 +516,7 @@ type EventParticipantResponse struct {
 	IsOrganizer bool              `json:"is_organizer"`
 	Role        string            `json:"role,omitempty"`
 	RespondedAt *time.Time        `json:"responded_at,omitempty"`
+	RespondedBy *uint             `json:"responded_by,omitempty"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
 }
@@ -327,6 +531,7 @@ func (ep *EventParticipant) ToResponse() *EventParticipantResponse {
 		IsOrganizer: ep.IsOrganizer,
 		Role:        ep.Role,
 		RespondedAt: ep.RespondedAt,
+		RespondedBy: ep.RespondedBy,
 		CreatedAt:   ep.CreatedAt,
 		UpdatedAt:   ep.UpdatedAt,
 	}
@@ -343,12 +548,18 @@ type EventReminderResponse struct {
 	SentAt        *time.Time   `json:"sent_at,omitempty"`
 	Message       string       `json:"message,omitempty"`
 	MinutesBefore *int         `json:"minutes_before,omitempty"`
+	Offset        string       `json:"offset,omitempty"`
 	CreatedAt     time.Time    `json:"created_at"`
 	UpdatedAt     time.Time    `json:"updated_at"`
 }
 
 // ToResponse converts EventReminder model to EventReminderResponse
 func (er *EventReminder) ToResponse() *EventReminderResponse {
+	var offset string
+	if er.MinutesBefore != nil {
+		offset = FormatReminderOffset(*er.MinutesBefore)
+	}
+
 	return &EventReminderResponse{
 		ID:            er.ID,
 		EventID:       er.EventID,
@@ -359,6 +570,7 @@ func (er *EventReminder) ToResponse() *EventReminderResponse {
 		SentAt:        er.SentAt,
 		Message:       er.Message,
 		MinutesBefore: er.MinutesBefore,
+		Offset:        offset,
 		CreatedAt:     er.CreatedAt,
 		UpdatedAt:     er.UpdatedAt,
 	}
@@ -368,30 +580,65 @@ func (er *EventReminder) ToResponse() *EventReminderResponse {
 
 // EventFilterRequest represents filtering parameters for events
 type EventFilterRequest struct {
-	Type        *EventType `form:"type" binding:"omitempty,oneof=personal meeting deadline"`
-	StartAfter  *time.Time `form:"start_after" time_format:"2006-01-02T15:04:05Z07:00"`
-	StartBefore *time.Time `form:"start_before" time_format:"2006-01-02T15:04:05Z07:00"`
-	EndAfter    *time.Time `form:"end_after" time_format:"2006-01-02T15:04:05Z07:00"`
-	EndBefore   *time.Time `form:"end_before" time_format:"2006-01-02T15:04:05Z07:00"`
-	AllDay      *bool      `form:"all_day"`
-	IsPrivate   *bool      `form:"is_private"`
-	IsRecurring *bool      `form:"is_recurring"`
-	CreatedBy   *uint      `form:"created_by" binding:"omitempty,min=1"`
-	TaskID      *uint      `form:"task_id" binding:"omitempty,min=1"`
-	Search      string     `form:"search" binding:"omitempty,max=100"`
-	Limit       int        `form:"limit" binding:"omitempty,min=1,max=100"`
-	Offset      int        `form:"offset" binding:"omitempty,min=0"`
-	SortBy      string     `form:"sort_by" binding:"omitempty,oneof=start_time end_time created_at updated_at title"`
-	SortOrder   string     `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Type        *EventType    `form:"type" binding:"omitempty,oneof=personal meeting deadline"`
+	Status      *EventStatus  `form:"status" binding:"omitempty,oneof=active cancelled"`
+	StartAfter  *time.Time    `form:"start_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	StartBefore *time.Time    `form:"start_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndAfter    *time.Time    `form:"end_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndBefore   *time.Time    `form:"end_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	AllDay      *bool         `form:"all_day"`
+	IsPrivate   *bool         `form:"is_private"`
+	IsRecurring *bool         `form:"is_recurring"`
+	CreatedBy   *uint         `form:"created_by" binding:"omitempty,min=1"`
+	TaskID      *uint         `form:"task_id" binding:"omitempty,min=1"`
+	Search      string        `form:"search" binding:"omitempty,max=100"`
+	Page        *query.Params `form:"-" json:"-"` // Pagination, sorting and generic filters
+}
+
+// EventListOptions defines pagination, sorting and filtering of event lists
+var EventListOptions = &query.Options{
+	DefaultSort:     "start_time",
+	LegacyAscending: true,
+	SortFields: map[string]string{
+		"start_time": "events.start_time",
+		"end_time":   "events.end_time",
+		"created_at": "events.created_at",
+		"updated_at": "events.updated_at",
+		"title":      "events.title",
+	},
+	FilterFields: map[string]string{
+		"type":       "events.type",
+		"status":     "events.status",
+		"start_time": "events.start_time",
+		"end_time":   "events.end_time",
+		"created_by": "events.created_by",
+		"location":   "events.location",
+		"title":      "events.title",
+	},
+	TieBreaker: "events.id",
+}
+
+// EventTrashListOptions defines pagination, sorting and filtering of deleted events
+var EventTrashListOptions = &query.Options{
+	DefaultSort: "-deleted_at",
+	SortFields: map[string]string{
+		"deleted_at": "events.deleted_at",
+		"start_time": "events.start_time",
+		"created_at": "events.created_at",
+		"title":      "events.title",
+	},
+	FilterFields: EventListOptions.FilterFields,
+	TieBreaker:   "events.id",
 }
 
 // EventListResponse represents a paginated list of events
 type EventListResponse struct {
-	Events  []*EventResponse    `json:"events"`
-	Total   int64               `json:"total"`
-	Limit   int                 `json:"limit"`
-	Offset  int                 `json:"offset"`
-	Filters *EventFilterRequest `json:"filters,omitempty"`
+	Events     []*EventResponse    `json:"events"`
+	Total      int64               `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	Filters    *EventFilterRequest `json:"filters,omitempty"`
 }
 
 // CalendarViewRequest represents request for calendar view
@@ -412,3 +659,23 @@ type EventStatsResponse struct {
 	EventsThisWeek  int `json:"events_this_week"`
 	EventsThisMonth int `json:"events_this_month"`
 }
+
+// Models returns all database models of the service for migrations
+func Models() []interface{} {
+	return []interface{}{
+		&Event{},
+		&EventParticipant{},
+		&EventReminder{},
+		&EventReschedule{},
+		&CalendarFeed{},
+		&EventEscalationRule{},
+		&EventEscalationDelivery{},
+		&Holiday{},
+		&Absence{},
+		&ReminderDefault{},
+		&CompanyEvent{},
+		&EventDetailGrant{},
+		&CalendarDelegation{},
+		&CalendarDelegationAudit{},
+	}
+}
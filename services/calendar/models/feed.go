@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// CalendarFeed represents a secret-token iCalendar feed of a user's calendar.
+// Only the SHA-256 hash of the token is stored, the token itself is shown once. The hash keeps
+// the column of plain tokens, so feeds issued before hashing need their token regenerated.
+type CalendarFeed struct {
+	models.BaseModel
+	UserID    uint   `gorm:"not null;uniqueIndex" json:"user_id"`
+	TokenHash string `gorm:"column:token;not null;size:64;uniqueIndex" json:"-"`
+	BusyOnly  bool   `gorm:"not null;default:false" json:"busy_only"` // Публиковать только занятость без деталей событий
+
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
+
+// TableName returns the table name for CalendarFeed model
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
+
+// UpdateCalendarFeedRequest represents request for changing feed settings
+type UpdateCalendarFeedRequest struct {
+	BusyOnly *bool `json:"busy_only" binding:"required"`
+}
+
+// CalendarFeedResponse represents feed settings in API responses.
+// Token and feed URLs are only set when the token is created or regenerated.
+type CalendarFeedResponse struct {
+	Token          string     `json:"token,omitempty"`
+	URL            string     `json:"url,omitempty"`
+	WebcalURL      string     `json:"webcal_url,omitempty"`
+	BusyOnly       bool       `json:"busy_only"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ToResponse converts CalendarFeed model to CalendarFeedResponse.
+// Feed URLs depend on the public address and are filled in by handlers.
+func (f *CalendarFeed) ToResponse() *CalendarFeedResponse {
+	return &CalendarFeedResponse{
+		BusyOnly:       f.BusyOnly,
+		LastAccessedAt: f.LastAccessedAt,
+		CreatedAt:      f.CreatedAt,
+		UpdatedAt:      f.UpdatedAt,
+	}
+}
+
+// CalendarFeedContent represents a rendered iCalendar feed
+type CalendarFeedContent struct {
+	Data         []byte
+	ETag         string
+	LastModified time.Time
+}
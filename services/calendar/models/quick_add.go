@@ -0,0 +1,43 @@
+package models
+
+import "tachyon-messenger/shared/i18n"
+
+// Quick add warnings about parts of the text that were not recognized or resolved
+const (
+	QuickAddWarningDateAssumed          = "date_assumed"          // No date in the text, today or tomorrow is used
+	QuickAddWarningTimeAssumed          = "time_assumed"          // No date or time in the text, the next full hour is used
+	QuickAddWarningAmbiguousParticipant = "ambiguous_participant" // A name matches several users
+	QuickAddWarningUnknownParticipant   = "unknown_participant"   // A name matches no user
+	QuickAddWarningParticipantsSkipped  = "participants_skipped"  // Users can't be looked up, names are not resolved
+)
+
+// QuickAddRequest represents free text describing an event, such as
+// "Встреча с отделом продаж завтра в 15:00 на час" or "Lunch with Anna on friday at 1pm"
+type QuickAddRequest struct {
+	Text     string `json:"text" binding:"required,min=1,max=500" validate:"required,notblank,max=500"`
+	Timezone string `json:"timezone,omitempty" binding:"omitempty,max=64" validate:"omitempty,max=64"` // IANA timezone of the text, organization timezone by default
+}
+
+// QuickAddUser is a user a name in quick add text may refer to
+type QuickAddUser struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Position string `json:"position,omitempty"`
+}
+
+// QuickAddParticipant is a name mentioned in quick add text with the users it may refer to
+type QuickAddParticipant struct {
+	Name       string          `json:"name"`
+	UserID     *uint           `json:"user_id,omitempty"` // Set when exactly one user matches, the user is invited in the draft
+	Candidates []*QuickAddUser `json:"candidates"`
+}
+
+// QuickAddDraft is an event parsed from quick add text. Nothing is created: the user reviews the
+// draft, picks ambiguous participants and confirms it by creating the event with POST /api/v1/events.
+type QuickAddDraft struct {
+	Event        *CreateEventRequest    `json:"event"`
+	Locale       i18n.Locale            `json:"locale"`     // Language the text was recognized in
+	Recognized   []string               `json:"recognized"` // Phrases read as date, time or duration and left out of the title
+	Participants []*QuickAddParticipant `json:"participants"`
+	Warnings     []string               `json:"warnings"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/shared/models"
+)
+
+const (
+	// MaxRemindersPerEvent is the number of reminders a user may set on one event
+	MaxRemindersPerEvent = 10
+	// MaxReminderMinutes is the earliest a reminder may trigger before the event, 30 days
+	MaxReminderMinutes = 43200
+)
+
+// ReminderDefault represents a reminder a user gets on events they create or accept
+type ReminderDefault struct {
+	models.BaseModel
+	UserID        uint         `gorm:"not null;index" json:"user_id"`
+	Type          ReminderType `gorm:"not null;size:20" json:"type"`
+	MinutesBefore int          `gorm:"not null" json:"minutes_before"`
+	Message       string       `gorm:"size:500" json:"message,omitempty"`
+}
+
+// TableName returns the table name for ReminderDefault model
+func (ReminderDefault) TableName() string {
+	return "calendar_reminder_defaults"
+}
+
+// SetRemindersRequest represents request for replacing reminders of an event or default reminders
+type SetRemindersRequest struct {
+	Reminders []CreateReminderRequest `json:"reminders" binding:"max=10,dive" validate:"max=10,dive"`
+}
+
+// ReminderDefaultResponse represents a default reminder in API responses
+type ReminderDefaultResponse struct {
+	Type          ReminderType `json:"type"`
+	MinutesBefore int          `json:"minutes_before"`
+	Offset        string       `json:"offset"`
+	Message       string       `json:"message,omitempty"`
+}
+
+// ToResponse converts ReminderDefault model to ReminderDefaultResponse
+func (d *ReminderDefault) ToResponse() *ReminderDefaultResponse {
+	return &ReminderDefaultResponse{
+		Type:          d.Type,
+		MinutesBefore: d.MinutesBefore,
+		Offset:        FormatReminderOffset(d.MinutesBefore),
+		Message:       d.Message,
+	}
+}
+
+// Minutes returns how many minutes before the event the reminder triggers, from offset if given
+func (req *CreateReminderRequest) Minutes() (int, error) {
+	if strings.TrimSpace(req.Offset) == "" {
+		return req.MinutesBefore, nil
+	}
+	return ParseReminderOffset(req.Offset)
+}
+
+// reminderUnits are the units of relative reminder offsets in minutes
+var reminderUnits = map[byte]int{
+	'm': 1,
+	'h': 60,
+	'd': 24 * 60,
+	'w': 7 * 24 * 60,
+}
+
+// ParseReminderOffset parses a relative reminder offset such as 10m, 1h, 1d or 1w into minutes
+func ParseReminderOffset(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid reminder offset %q", value)
+	}
+
+	unit, ok := reminderUnits[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid reminder offset %q: unit must be m, h, d or w", value)
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid reminder offset %q", value)
+	}
+
+	minutes := amount * unit
+	if amount > MaxReminderMinutes || minutes > MaxReminderMinutes {
+		return 0, fmt.Errorf("invalid reminder offset %q: must be at most 30 days", value)
+	}
+	return minutes, nil
+}
+
+// FormatReminderOffset formats minutes before an event as the largest whole unit, e.g. 90m, 2h or 1d
+func FormatReminderOffset(minutes int) string {
+	for _, unit := range []byte{'w', 'd', 'h'} {
+		size := reminderUnits[unit]
+		if minutes > 0 && minutes%size == 0 {
+			return strconv.Itoa(minutes/size) + string(unit)
+		}
+	}
+	return strconv.Itoa(minutes) + "m"
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// EventDetailGrant lets a user who doesn't take part in a busy-only or private event see its details.
+// Grants are managed by the organizer of the event.
+type EventDetailGrant struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	EventID   uint      `gorm:"not null;uniqueIndex:idx_event_detail_grant" json:"event_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_event_detail_grant;index" json:"user_id"`
+	GrantedBy uint      `gorm:"not null" json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for EventDetailGrant model
+func (EventDetailGrant) TableName() string {
+	return "event_detail_grants"
+}
+
+// UpdateEventVisibilityRequest represents request for changing visibility of an event
+type UpdateEventVisibilityRequest struct {
+	Visibility EventVisibility `json:"visibility" binding:"required,oneof=public busy private" validate:"required,enum"`
+	SharedWith []uint          `json:"shared_with" binding:"omitempty,max=100,dive,min=1" validate:"omitempty,max=100,dive,min=1"` // Кому организатор открывает детали, заменяет прежний список
+}
+
+// EventVisibilityResponse represents visibility of an event and users its details are shared with
+type EventVisibilityResponse struct {
+	EventID    uint            `json:"event_id"`
+	Visibility EventVisibility `json:"visibility"`
+	SharedWith []uint          `json:"shared_with"`
+}
+
+// BusyBlock represents time a user is occupied, with event details only if the viewer may see them
+type BusyBlock struct {
+	UserID    uint      `json:"user_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	EventID   *uint     `json:"event_id,omitempty"`
+	Title     string    `json:"title"`
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// HolidayRepository defines the interface for public holiday data operations
+type HolidayRepository interface {
+	CreateHoliday(holiday *models.Holiday) error
+	DeleteHoliday(id uint) error
+	GetHolidays(region string, from, to time.Time) ([]*models.Holiday, error)
+}
+
+// AbsenceRepository defines the interface for employee absence data operations
+type AbsenceRepository interface {
+	CreateAbsence(absence *models.Absence) error
+	GetAbsenceByID(id uint) (*models.Absence, error)
+	DeleteAbsence(id uint) error
+	GetUserAbsences(userID uint, from, to time.Time) ([]*models.Absence, error)
+	GetAbsencesForUsers(userIDs []uint, from, to time.Time) ([]*models.Absence, error)
+	GetDepartmentAbsences(departmentID uint, from, to time.Time) ([]*models.Absence, error)
+	UpsertExternalAbsences(source string, absences []*models.Absence) (int, int, error)
+}
+
+// holidayRepository implements HolidayRepository interface
+type holidayRepository struct {
+	db *database.DB
+}
+
+// absenceRepository implements AbsenceRepository interface
+type absenceRepository struct {
+	db *database.DB
+}
+
+// NewHolidayRepository creates a new holiday repository
+func NewHolidayRepository(db *database.DB) HolidayRepository {
+	return &holidayRepository{
+		db: db,
+	}
+}
+
+// NewAbsenceRepository creates a new absence repository
+func NewAbsenceRepository(db *database.DB) AbsenceRepository {
+	return &absenceRepository{
+		db: db,
+	}
+}
+
+// CreateHoliday creates a new public holiday
+func (r *holidayRepository) CreateHoliday(holiday *models.Holiday) error {
+	if holiday == nil {
+		return errors.New("holiday cannot be nil")
+	}
+
+	if err := r.db.Create(holiday).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("cannot add holiday: region already has a holiday on this date")
+		}
+		return fmt.Errorf("failed to create holiday: %w", err)
+	}
+	return nil
+}
+
+// DeleteHoliday permanently deletes a public holiday, so the date can be reused
+func (r *holidayRepository) DeleteHoliday(id uint) error {
+	result := r.db.Unscoped().Delete(&models.Holiday{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete holiday: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("holiday not found")
+	}
+	return nil
+}
+
+// GetHolidays retrieves holidays within a date range, optionally for a single region
+func (r *holidayRepository) GetHolidays(region string, from, to time.Time) ([]*models.Holiday, error) {
+	var holidays []*models.Holiday
+
+	query := r.db.Where("date >= ? AND date <= ?", from, to)
+	if region != "" {
+		query = query.Where("region = ?", region)
+	}
+
+	if err := query.Order("date ASC, region ASC").Find(&holidays).Error; err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	return holidays, nil
+}
+
+// CreateAbsence creates a new absence record
+func (r *absenceRepository) CreateAbsence(absence *models.Absence) error {
+	if absence == nil {
+		return errors.New("absence cannot be nil")
+	}
+
+	if err := r.db.Create(absence).Error; err != nil {
+		return fmt.Errorf("failed to create absence: %w", err)
+	}
+	return nil
+}
+
+// GetAbsenceByID retrieves an absence by ID
+func (r *absenceRepository) GetAbsenceByID(id uint) (*models.Absence, error) {
+	var absence models.Absence
+	err := r.db.First(&absence, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("absence not found")
+		}
+		return nil, fmt.Errorf("failed to get absence: %w", err)
+	}
+	return &absence, nil
+}
+
+// DeleteAbsence deletes an absence record
+func (r *absenceRepository) DeleteAbsence(id uint) error {
+	result := r.db.Delete(&models.Absence{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete absence: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("absence not found")
+	}
+	return nil
+}
+
+// GetUserAbsences retrieves absences of a user overlapping a date range
+func (r *absenceRepository) GetUserAbsences(userID uint, from, to time.Time) ([]*models.Absence, error) {
+	return r.findOverlapping(r.db.Where("user_id = ?", userID), from, to)
+}
+
+// GetAbsencesForUsers retrieves absences of the given users overlapping a date range
+func (r *absenceRepository) GetAbsencesForUsers(userIDs []uint, from, to time.Time) ([]*models.Absence, error) {
+	if len(userIDs) == 0 {
+		return []*models.Absence{}, nil
+	}
+	return r.findOverlapping(r.db.Where("user_id IN ?", userIDs), from, to)
+}
+
+// GetDepartmentAbsences retrieves absences of a department overlapping a date range
+func (r *absenceRepository) GetDepartmentAbsences(departmentID uint, from, to time.Time) ([]*models.Absence, error) {
+	return r.findOverlapping(r.db.Where("department_id = ?", departmentID), from, to)
+}
+
+// UpsertExternalAbsences creates or updates absences synced from an external source,
+// matching them by external ID, and returns the numbers of created and updated records
+func (r *absenceRepository) UpsertExternalAbsences(source string, absences []*models.Absence) (int, int, error) {
+	created, updated := 0, 0
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, absence := range absences {
+			absence.Source = source
+
+			var existing models.Absence
+			err := tx.Where("source = ? AND external_id = ?", source, absence.ExternalID).First(&existing).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if err := tx.Create(absence).Error; err != nil {
+					return err
+				}
+				created++
+				continue
+			}
+
+			absence.ID = existing.ID
+			absence.CreatedAt = existing.CreatedAt
+			if err := tx.Save(absence).Error; err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sync absences: %w", err)
+	}
+
+	return created, updated, nil
+}
+
+// findOverlapping applies the date range to an absence query; end dates are inclusive
+func (r *absenceRepository) findOverlapping(query *gorm.DB, from, to time.Time) ([]*models.Absence, error) {
+	var absences []*models.Absence
+
+	err := query.
+		Where("start_date <= ? AND end_date >= ?", to, from).
+		Order("start_date ASC, user_id ASC").
+		Find(&absences).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absences: %w", err)
+	}
+	return absences, nil
+}
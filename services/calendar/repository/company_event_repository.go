@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// audienceBatchSize is how many participants are added to a company event in one insert
+const audienceBatchSize = 500
+
+// CompanyEventRepository defines the interface for company event data operations
+type CompanyEventRepository interface {
+	CreateCompanyEvent(companyEvent *models.CompanyEvent) error
+	GetCompanyEventByID(id uint) (*models.CompanyEvent, error)
+	UpdateCompanyEvent(companyEvent *models.CompanyEvent) error
+	DeleteCompanyEvent(id uint) error
+	GetCompanyEvents(filter *models.CompanyEventFilterRequest) ([]*models.CompanyEvent, int64, error)
+	GetUpcomingPublishedCompanyEvents(now time.Time) ([]*models.CompanyEvent, error)
+	PublishCompanyEvent(companyEvent *models.CompanyEvent, event *models.Event, audience []uint) error
+	UpdatePublishedCompanyEvent(companyEvent *models.CompanyEvent, event *models.Event) error
+	SyncCompanyEventAudience(eventID uint, audience []uint) ([]uint, int64, error)
+}
+
+// companyEventRepository implements CompanyEventRepository interface
+type companyEventRepository struct {
+	db *database.DB
+}
+
+// NewCompanyEventRepository creates a new company event repository
+func NewCompanyEventRepository(db *database.DB) CompanyEventRepository {
+	return &companyEventRepository{
+		db: db,
+	}
+}
+
+// CreateCompanyEvent creates a new company event
+func (r *companyEventRepository) CreateCompanyEvent(companyEvent *models.CompanyEvent) error {
+	if companyEvent == nil {
+		return errors.New("company event cannot be nil")
+	}
+
+	if err := r.db.Create(companyEvent).Error; err != nil {
+		return fmt.Errorf("failed to create company event: %w", err)
+	}
+	return nil
+}
+
+// GetCompanyEventByID retrieves a company event by ID
+func (r *companyEventRepository) GetCompanyEventByID(id uint) (*models.CompanyEvent, error) {
+	var companyEvent models.CompanyEvent
+	if err := r.db.First(&companyEvent, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("company event not found")
+		}
+		return nil, fmt.Errorf("failed to get company event: %w", err)
+	}
+	return &companyEvent, nil
+}
+
+// UpdateCompanyEvent saves changes of a company event
+func (r *companyEventRepository) UpdateCompanyEvent(companyEvent *models.CompanyEvent) error {
+	if err := r.db.Save(companyEvent).Error; err != nil {
+		return fmt.Errorf("failed to update company event: %w", err)
+	}
+	return nil
+}
+
+// DeleteCompanyEvent soft deletes a company event
+func (r *companyEventRepository) DeleteCompanyEvent(id uint) error {
+	result := r.db.Delete(&models.CompanyEvent{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete company event: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("company event not found")
+	}
+	return nil
+}
+
+// GetCompanyEvents retrieves company events by filter, soonest first
+func (r *companyEventRepository) GetCompanyEvents(filter *models.CompanyEventFilterRequest) ([]*models.CompanyEvent, int64, error) {
+	query := r.db.Model(&models.CompanyEvent{})
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.StartAfter != nil {
+		query = query.Where("start_time >= ?", *filter.StartAfter)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count company events: %w", err)
+	}
+
+	var companyEvents []*models.CompanyEvent
+	err := query.Order("start_time ASC, id ASC").Limit(filter.Limit).Offset(filter.Offset).Find(&companyEvents).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get company events: %w", err)
+	}
+	return companyEvents, total, nil
+}
+
+// GetUpcomingPublishedCompanyEvents retrieves published company events that have not ended yet
+func (r *companyEventRepository) GetUpcomingPublishedCompanyEvents(now time.Time) ([]*models.CompanyEvent, error) {
+	var companyEvents []*models.CompanyEvent
+	err := r.db.Where("status = ? AND event_id IS NOT NULL AND end_time > ?", models.CompanyEventStatusPublished, now).
+		Order("start_time ASC").
+		Find(&companyEvents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get published company events: %w", err)
+	}
+	return companyEvents, nil
+}
+
+// PublishCompanyEvent creates the calendar event of a company event with the audience as pending
+// participants and its creator as organizer, and links it to the company event in one transaction
+func (r *companyEventRepository) PublishCompanyEvent(companyEvent *models.CompanyEvent, event *models.Event, audience []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+
+		organizer := &models.EventParticipant{
+			EventID:     event.ID,
+			UserID:      event.CreatedBy,
+			Status:      models.ParticipantStatusAccepted,
+			IsOrganizer: true,
+		}
+		if err := tx.Create(organizer).Error; err != nil {
+			return fmt.Errorf("failed to add organizer: %w", err)
+		}
+
+		participants := audienceParticipants(event.ID, audience, map[uint]bool{event.CreatedBy: true})
+		if len(participants) > 0 {
+			if err := tx.CreateInBatches(participants, audienceBatchSize).Error; err != nil {
+				return fmt.Errorf("failed to add participants: %w", err)
+			}
+		}
+
+		companyEvent.EventID = &event.ID
+		if err := tx.Save(companyEvent).Error; err != nil {
+			return fmt.Errorf("failed to update company event: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdatePublishedCompanyEvent saves a published company event together with its calendar event
+func (r *companyEventRepository) UpdatePublishedCompanyEvent(companyEvent *models.CompanyEvent, event *models.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(companyEvent).Error; err != nil {
+			return fmt.Errorf("failed to update company event: %w", err)
+		}
+
+		if err := (&database.DB{DB: tx}).SaveVersioned(event); err != nil {
+			if errors.Is(err, database.ErrVersionConflict) {
+				return fmt.Errorf("version conflict: event was modified or deleted by another request")
+			}
+			return fmt.Errorf("failed to update event: %w", err)
+		}
+		return nil
+	})
+}
+
+// SyncCompanyEventAudience makes the audience the participants of a company event's calendar event.
+// Missing users are added as pending, other participants except the organizer are removed.
+// It returns the added user IDs and the number of removed participants.
+func (r *companyEventRepository) SyncCompanyEventAudience(eventID uint, audience []uint) ([]uint, int64, error) {
+	var added []uint
+	var removed int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var participants []*models.EventParticipant
+		if err := tx.Where("event_id = ?", eventID).Find(&participants).Error; err != nil {
+			return fmt.Errorf("failed to get participants: %w", err)
+		}
+
+		current := make(map[uint]bool, len(participants))
+		for _, participant := range participants {
+			current[participant.UserID] = true
+		}
+
+		wanted := make(map[uint]bool, len(audience))
+		for _, userID := range audience {
+			wanted[userID] = true
+		}
+
+		var stale []uint
+		for _, participant := range participants {
+			if !participant.IsOrganizer && !wanted[participant.UserID] {
+				stale = append(stale, participant.UserID)
+			}
+		}
+		if len(stale) > 0 {
+			result := tx.Where("event_id = ? AND is_organizer = ? AND user_id IN ?", eventID, false, stale).
+				Delete(&models.EventParticipant{})
+			if result.Error != nil {
+				return fmt.Errorf("failed to remove participants: %w", result.Error)
+			}
+			removed = result.RowsAffected
+		}
+
+		newParticipants := audienceParticipants(eventID, audience, current)
+		if len(newParticipants) > 0 {
+			if err := tx.CreateInBatches(newParticipants, audienceBatchSize).Error; err != nil {
+				return fmt.Errorf("failed to add participants: %w", err)
+			}
+		}
+
+		added = make([]uint, len(newParticipants))
+		for i, participant := range newParticipants {
+			added[i] = participant.UserID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return added, removed, nil
+}
+
+// audienceParticipants creates pending participants for distinct audience users not in skip
+func audienceParticipants(eventID uint, audience []uint, skip map[uint]bool) []*models.EventParticipant {
+	seen := make(map[uint]bool, len(audience))
+	participants := make([]*models.EventParticipant, 0, len(audience))
+	for _, userID := range audience {
+		if userID == 0 || skip[userID] || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		participants = append(participants, &models.EventParticipant{
+			EventID: eventID,
+			UserID:  userID,
+			Status:  models.ParticipantStatusPending,
+		})
+	}
+	return participants
+}
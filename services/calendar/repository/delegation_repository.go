@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// DelegationRepository defines the interface for calendar delegation data operations
+type DelegationRepository interface {
+	GetActiveDelegation(ownerID, delegateID uint) (*models.CalendarDelegation, error)
+	GetDelegationsByOwner(ownerID uint) ([]*models.CalendarDelegation, error)
+	GetDelegationsByDelegate(delegateID uint) ([]*models.CalendarDelegation, error)
+	SaveDelegation(delegation *models.CalendarDelegation, entry *models.CalendarDelegationAudit) error
+	CreateAuditEntry(entry *models.CalendarDelegationAudit) error
+	GetAuditLog(userID uint, limit, offset int) ([]*models.CalendarDelegationAudit, int64, error)
+}
+
+// delegationRepository implements DelegationRepository interface
+type delegationRepository struct {
+	db *database.DB
+}
+
+// NewDelegationRepository creates a new calendar delegation repository
+func NewDelegationRepository(db *database.DB) DelegationRepository {
+	return &delegationRepository{
+		db: db,
+	}
+}
+
+// GetActiveDelegation retrieves the delegation of the owner's calendar to the delegate that was not revoked
+func (r *delegationRepository) GetActiveDelegation(ownerID, delegateID uint) (*models.CalendarDelegation, error) {
+	var delegation models.CalendarDelegation
+	err := r.db.Where("owner_id = ? AND delegate_id = ? AND revoked_at IS NULL", ownerID, delegateID).
+		First(&delegation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("calendar delegation not found")
+		}
+		return nil, fmt.Errorf("failed to get calendar delegation: %w", err)
+	}
+	return &delegation, nil
+}
+
+// GetDelegationsByOwner retrieves active delegations the owner granted
+func (r *delegationRepository) GetDelegationsByOwner(ownerID uint) ([]*models.CalendarDelegation, error) {
+	delegations := []*models.CalendarDelegation{}
+	err := r.db.Where("owner_id = ? AND revoked_at IS NULL", ownerID).
+		Order("created_at").
+		Find(&delegations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get granted calendar delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// GetDelegationsByDelegate retrieves active delegations granted to the delegate
+func (r *delegationRepository) GetDelegationsByDelegate(delegateID uint) ([]*models.CalendarDelegation, error) {
+	delegations := []*models.CalendarDelegation{}
+	err := r.db.Where("delegate_id = ? AND revoked_at IS NULL", delegateID).
+		Order("created_at").
+		Find(&delegations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get received calendar delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// SaveDelegation creates or updates a delegation and records the change in the audit log
+func (r *delegationRepository) SaveDelegation(delegation *models.CalendarDelegation, entry *models.CalendarDelegationAudit) error {
+	if delegation == nil || entry == nil {
+		return errors.New("delegation and audit entry cannot be nil")
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(delegation).Error; err != nil {
+			return err
+		}
+		entry.DelegationID = delegation.ID
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save calendar delegation: %w", err)
+	}
+	return nil
+}
+
+// CreateAuditEntry records an action of a delegate in the audit log
+func (r *delegationRepository) CreateAuditEntry(entry *models.CalendarDelegationAudit) error {
+	if entry == nil {
+		return errors.New("audit entry cannot be nil")
+	}
+
+	if err := r.db.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create delegation audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetAuditLog retrieves audit entries of delegations the user granted or received, newest first
+func (r *delegationRepository) GetAuditLog(userID uint, limit, offset int) ([]*models.CalendarDelegationAudit, int64, error) {
+	query := r.db.Model(&models.CalendarDelegationAudit{}).
+		Where("owner_id = ? OR delegate_id = ?", userID, userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count delegation audit entries: %w", err)
+	}
+
+	entries := []*models.CalendarDelegationAudit{}
+	err := query.Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get delegation audit entries: %w", err)
+	}
+	return entries, total, nil
+}
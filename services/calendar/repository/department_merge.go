@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+
+	"tachyon-messenger/services/calendar/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeDepartments moves absences of a merged department to the department it was merged into and
+// retargets company events addressed to the merged department. It returns the number of moved
+// absences and the retargeted company events.
+func (r *eventRepository) MergeDepartments(departmentID, mergedID uint) (int64, []*models.CompanyEvent, error) {
+	var moved int64
+	var retargeted []*models.CompanyEvent
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Absence{}).Where("department_id = ?", mergedID).Update("department_id", departmentID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move department absences: %w", result.Error)
+		}
+		moved = result.RowsAffected
+
+		// Department IDs are stored as JSON, company events targeting departments are checked one by one
+		var companyEvents []*models.CompanyEvent
+		if err := tx.Where("department_ids IS NOT NULL AND department_ids NOT IN ?", []string{"", "[]", "null"}).
+			Find(&companyEvents).Error; err != nil {
+			return fmt.Errorf("failed to get company events: %w", err)
+		}
+
+		for _, companyEvent := range companyEvents {
+			departmentIDs, ok := replaceDepartment(companyEvent.DepartmentIDs, mergedID, departmentID)
+			if !ok {
+				continue
+			}
+			companyEvent.DepartmentIDs = departmentIDs
+			if err := tx.Model(companyEvent).Select("department_ids").Updates(companyEvent).Error; err != nil {
+				return fmt.Errorf("failed to retarget company event %d: %w", companyEvent.ID, err)
+			}
+			retargeted = append(retargeted, companyEvent)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return moved, retargeted, nil
+}
+
+// replaceDepartment replaces mergedID with departmentID in sorted department IDs, keeping them sorted
+// and unique. It reports false if mergedID is not among them.
+func replaceDepartment(departmentIDs []uint, mergedID, departmentID uint) ([]uint, bool) {
+	if !slices.Contains(departmentIDs, mergedID) {
+		return departmentIDs, false
+	}
+
+	result := make([]uint, 0, len(departmentIDs))
+	for _, id := range departmentIDs {
+		if id == mergedID {
+			id = departmentID
+		}
+		if !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	slices.Sort(result)
+	return result, true
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EscalationRepository defines the interface for RSVP reminder escalation data operations
+type EscalationRepository interface {
+	ReplaceRules(eventID uint, rules []*models.EventEscalationRule) error
+	GetRules(eventID uint) ([]*models.EventEscalationRule, error)
+	DeleteRules(eventID uint) error
+	GetDueRules(now time.Time) ([]*models.EventEscalationRule, error)
+	GetUnrespondedParticipants(eventID, ruleID uint) ([]uint, error)
+	RecordDeliveries(deliveries []*models.EventEscalationDelivery) error
+}
+
+// escalationRepository implements EscalationRepository interface
+type escalationRepository struct {
+	db *database.DB
+}
+
+// NewEscalationRepository creates a new escalation repository
+func NewEscalationRepository(db *database.DB) EscalationRepository {
+	return &escalationRepository{
+		db: db,
+	}
+}
+
+// ReplaceRules replaces escalation rules of an event in one transaction
+func (r *escalationRepository) ReplaceRules(eventID uint, rules []*models.EventEscalationRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", eventID).Delete(&models.EventEscalationRule{}).Error; err != nil {
+			return fmt.Errorf("failed to delete escalation rules: %w", err)
+		}
+
+		if len(rules) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(rules).Error; err != nil {
+			return fmt.Errorf("failed to create escalation rules: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetRules retrieves escalation rules of an event ordered by step
+func (r *escalationRepository) GetRules(eventID uint) ([]*models.EventEscalationRule, error) {
+	var rules []*models.EventEscalationRule
+	err := r.db.Where("event_id = ?", eventID).Order("step ASC").Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation rules: %w", err)
+	}
+	return rules, nil
+}
+
+// DeleteRules deletes escalation rules of an event
+func (r *escalationRepository) DeleteRules(eventID uint) error {
+	err := r.db.Where("event_id = ?", eventID).Delete(&models.EventEscalationRule{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation rules: %w", err)
+	}
+	return nil
+}
+
+// GetDueRules retrieves rules of upcoming events whose trigger time has passed
+func (r *escalationRepository) GetDueRules(now time.Time) ([]*models.EventEscalationRule, error) {
+	var rules []*models.EventEscalationRule
+	err := r.db.Model(&models.EventEscalationRule{}).
+		Joins("JOIN events ON events.id = event_escalation_rules.event_id AND events.deleted_at IS NULL").
+		Where("events.start_time > ? AND events.status <> ?", now, models.EventStatusCancelled).
+		Where("events.start_time - make_interval(mins => event_escalation_rules.minutes_before) <= ?", now).
+		Preload("Event").
+		Order("event_escalation_rules.event_id ASC, event_escalation_rules.step ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due escalation rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetUnrespondedParticipants returns participants of an event who have not responded yet
+// and were not reminded by the rule
+func (r *escalationRepository) GetUnrespondedParticipants(eventID, ruleID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.EventParticipant{}).
+		Where("event_id = ? AND status = ? AND is_organizer = ?", eventID, models.ParticipantStatusPending, false).
+		Where("user_id NOT IN (?)", r.db.Model(&models.EventEscalationDelivery{}).Select("user_id").Where("rule_id = ?", ruleID)).
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unresponded participants: %w", err)
+	}
+	return userIDs, nil
+}
+
+// RecordDeliveries records sent reminders, ignoring already recorded ones
+func (r *escalationRepository) RecordDeliveries(deliveries []*models.EventEscalationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(deliveries, r.db.BatchSizeFor(&models.EventEscalationDelivery{})).Error
+	if err != nil {
+		return fmt.Errorf("failed to record escalation deliveries: %w", err)
+	}
+	return nil
+}
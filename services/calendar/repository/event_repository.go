@@ -7,6 +7,7 @@ import (
 
 	"tachyon-messenger/services/calendar/models"
 	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
 )
@@ -19,7 +20,9 @@ type EventRepository interface {
 	UpdateEvent(event *models.Event) error
 	DeleteEvent(id uint) error
 	GetEventsByDateRange(userID uint, startDate, endDate time.Time) ([]*models.Event, error)
+	GetBusyPeriods(userIDs []uint, startTime, endTime time.Time) ([]*models.BusyPeriod, error)
 	CheckTimeConflict(userID uint, startTime, endTime time.Time, excludeEventID *uint) (bool, error)
+	GetConflictingEvents(userID uint, startTime, endTime time.Time, excludeEventIDs []uint, limit int) ([]*models.Event, error)
 	GetEventWithParticipants(id uint) (*models.Event, error)
 	GetEventWithReminders(id uint) (*models.Event, error)
 	GetEventWithAll(id uint) (*models.Event, error)
@@ -28,13 +31,34 @@ type EventRepository interface {
 	GetEventStats(userID uint) (*models.EventStatsResponse, error)
 	SearchEvents(userID uint, searchQuery string, filter *models.EventFilterRequest) ([]*models.Event, int64, error)
 	GetRecurringEvents(userID uint) ([]*models.Event, error)
+
+	// Visibility
+	GetDetailGrants(eventID uint) ([]uint, error)
+	ReplaceDetailGrants(eventID uint, userIDs []uint, grantedBy uint) error
+	GetEventsWithDetails(userID uint, eventIDs []uint) (map[uint]bool, error)
+
+	// Rescheduling
+	RescheduleEvent(event *models.Event, reschedule *models.EventReschedule) error
+	GetReschedules(eventID uint) ([]*models.EventReschedule, error)
+
+	// Trash operations
+	GetDeletedEventByID(id uint) (*models.Event, error)
+	GetDeletedEvents(creatorID uint, filter *models.EventFilterRequest) ([]*models.Event, int64, error)
+	RestoreEvent(id uint) error
+	PurgeDeletedEvents(deletedBefore time.Time) (int64, error)
+
+	// Account merge
+	MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error)
+
+	// Department merge
+	MergeDepartments(departmentID, mergedID uint) (int64, []*models.CompanyEvent, error)
 }
 
 // ParticipantRepository defines the interface for participant data operations
 type ParticipantRepository interface {
 	AddParticipant(participant *models.EventParticipant) error
 	RemoveParticipant(eventID, userID uint) error
-	UpdateParticipantStatus(eventID, userID uint, status models.ParticipantStatus) error
+	UpdateParticipantStatus(eventID, userID uint, status models.ParticipantStatus, respondedBy *uint) error
 	GetEventParticipants(eventID uint) ([]*models.EventParticipant, error)
 	GetUserParticipations(userID uint) ([]*models.EventParticipant, error)
 	IsParticipant(eventID, userID uint) (bool, error)
@@ -50,6 +74,10 @@ type ReminderRepository interface {
 	MarkReminderSent(id uint) error
 	DeleteReminder(id uint) error
 	UpdateReminder(reminder *models.EventReminder) error
+	GetUserEventReminders(eventID, userID uint) ([]*models.EventReminder, error)
+	ReplaceUserEventReminders(eventID, userID uint, reminders []*models.EventReminder) error
+	GetReminderDefaults(userID uint) ([]*models.ReminderDefault, error)
+	ReplaceReminderDefaults(userID uint, defaults []*models.ReminderDefault) error
 }
 
 // eventRepository implements EventRepository interface
@@ -100,10 +128,16 @@ func (r *participantRepository) RemoveParticipant(eventID, userID uint) error {
 	return r.db.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&models.EventParticipant{}).Error
 }
 
-func (r *participantRepository) UpdateParticipantStatus(eventID, userID uint, status models.ParticipantStatus) error {
+// UpdateParticipantStatus records the response of a participant, respondedBy is the delegate who
+// responded on behalf of the participant or nil
+func (r *participantRepository) UpdateParticipantStatus(eventID, userID uint, status models.ParticipantStatus, respondedBy *uint) error {
 	result := r.db.Model(&models.EventParticipant{}).
 		Where("event_id = ? AND user_id = ?", eventID, userID).
-		Update("status", status)
+		Updates(map[string]interface{}{
+			"status":       status,
+			"responded_at": time.Now(),
+			"responded_by": respondedBy,
+		})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -174,16 +208,43 @@ func (r *eventRepository) GetUserEvents(userID uint, filter *models.EventFilterR
 
 // UpdateEvent updates an existing event
 func (r *eventRepository) UpdateEvent(event *models.Event) error {
-	result := r.db.Save(event)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update event: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("event not found")
+	if err := r.db.SaveVersioned(event); err != nil {
+		if errors.Is(err, database.ErrVersionConflict) {
+			return fmt.Errorf("version conflict: event was modified or deleted by another request")
+		}
+		return fmt.Errorf("failed to update event: %w", err)
 	}
 	return nil
 }
 
+// RescheduleEvent saves the new event time and records the change in one transaction
+func (r *eventRepository) RescheduleEvent(event *models.Event, reschedule *models.EventReschedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := (&database.DB{DB: tx}).SaveVersioned(event); err != nil {
+			if errors.Is(err, database.ErrVersionConflict) {
+				return fmt.Errorf("version conflict: event was modified or deleted by another request")
+			}
+			return fmt.Errorf("failed to update event: %w", err)
+		}
+
+		reschedule.EventID = event.ID
+		if err := tx.Create(reschedule).Error; err != nil {
+			return fmt.Errorf("failed to record reschedule: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetReschedules retrieves reschedule history of an event, newest first
+func (r *eventRepository) GetReschedules(eventID uint) ([]*models.EventReschedule, error) {
+	var reschedules []*models.EventReschedule
+	err := r.db.Where("event_id = ?", eventID).Order("created_at DESC").Find(&reschedules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reschedule history: %w", err)
+	}
+	return reschedules, nil
+}
+
 // DeleteEvent soft deletes an event by ID
 func (r *eventRepository) DeleteEvent(id uint) error {
 	result := r.db.Delete(&models.Event{}, id)
@@ -196,6 +257,65 @@ func (r *eventRepository) DeleteEvent(id uint) error {
 	return nil
 }
 
+// GetDeletedEventByID retrieves a soft-deleted event by ID
+func (r *eventRepository) GetDeletedEventByID(id uint) (*models.Event, error) {
+	var event models.Event
+	err := r.db.Trashed().First(&event, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get deleted event: %w", err)
+	}
+	return &event, nil
+}
+
+// GetDeletedEvents retrieves soft-deleted events of a creator, most recently deleted first
+func (r *eventRepository) GetDeletedEvents(creatorID uint, filter *models.EventFilterRequest) ([]*models.Event, int64, error) {
+	query := r.db.Trashed().Model(&models.Event{}).Where("events.created_by = ?", creatorID)
+
+	// Apply filters
+	query = r.applyFilters(query, filter)
+
+	// Get total count
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted events: %w", err)
+	}
+
+	// Apply pagination and sorting
+	query = r.applySortingAndPagination(query, filter)
+
+	var events []*models.Event
+	if err := query.Find(&events).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// RestoreEvent restores a soft-deleted event by ID
+func (r *eventRepository) RestoreEvent(id uint) error {
+	restored, err := r.db.Restore(&models.Event{}, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore event: %w", err)
+	}
+	if !restored {
+		return fmt.Errorf("event not found")
+	}
+	return nil
+}
+
+// PurgeDeletedEvents permanently deletes events soft-deleted before deletedBefore,
+// participants and reminders are removed by cascade
+func (r *eventRepository) PurgeDeletedEvents(deletedBefore time.Time) (int64, error) {
+	purged, err := r.db.PurgeDeleted(&models.Event{}, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted events: %w", err)
+	}
+	return purged, nil
+}
+
 // GetEventsByDateRange retrieves events within a date range for a user
 func (r *eventRepository) GetEventsByDateRange(userID uint, startDate, endDate time.Time) ([]*models.Event, error) {
 	var events []*models.Event
@@ -218,12 +338,46 @@ func (r *eventRepository) GetEventsByDateRange(userID uint, startDate, endDate t
 	return events, nil
 }
 
+// GetBusyPeriods retrieves times the given users are occupied by events overlapping a period.
+// Cancelled events and declined invitations do not make a user busy.
+func (r *eventRepository) GetBusyPeriods(userIDs []uint, startTime, endTime time.Time) ([]*models.BusyPeriod, error) {
+	var periods []*models.BusyPeriod
+	if len(userIDs) == 0 {
+		return periods, nil
+	}
+
+	err := r.db.Raw(`
+		SELECT events.created_by AS user_id, events.start_time, events.end_time,
+			events.id AS event_id, events.title, events.visibility, events.is_private
+		FROM events
+		WHERE events.deleted_at IS NULL AND events.status <> ?
+			AND events.created_by IN ? AND events.start_time < ? AND events.end_time > ?
+		UNION
+		SELECT event_participants.user_id, events.start_time, events.end_time,
+			events.id AS event_id, events.title, events.visibility, events.is_private
+		FROM events
+		JOIN event_participants ON events.id = event_participants.event_id
+		WHERE events.deleted_at IS NULL AND events.status <> ?
+			AND event_participants.user_id IN ? AND event_participants.status <> ?
+			AND events.start_time < ? AND events.end_time > ?
+		ORDER BY start_time`,
+		models.EventStatusCancelled, userIDs, endTime, startTime,
+		models.EventStatusCancelled, userIDs, models.ParticipantStatusDeclined, endTime, startTime,
+	).Scan(&periods).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get busy periods: %w", err)
+	}
+
+	return periods, nil
+}
+
 // CheckTimeConflict checks if there's a time conflict for a user
 func (r *eventRepository) CheckTimeConflict(userID uint, startTime, endTime time.Time, excludeEventID *uint) (bool, error) {
 	query := r.db.Model(&models.Event{}).
 		Joins("LEFT JOIN event_participants ON events.id = event_participants.event_id").
 		Where("(events.created_by = ? OR (event_participants.user_id = ? AND event_participants.status = 'accepted'))", userID, userID).
-		Where("NOT (events.end_time <= ? OR events.start_time >= ?)", startTime, endTime)
+		Where("NOT (events.end_time <= ? OR events.start_time >= ?)", startTime, endTime).
+		Where("events.status <> ?", models.EventStatusCancelled)
 
 	// Exclude specific event if provided (for updates)
 	if excludeEventID != nil {
@@ -239,6 +393,31 @@ func (r *eventRepository) CheckTimeConflict(userID uint, startTime, endTime time
 	return count > 0, nil
 }
 
+// GetConflictingEvents returns up to limit active events of a user overlapping the period, ordered
+// by start time. Events with excludeEventIDs are skipped, e.g. events being moved together.
+func (r *eventRepository) GetConflictingEvents(userID uint, startTime, endTime time.Time, excludeEventIDs []uint, limit int) ([]*models.Event, error) {
+	accepted := r.db.Model(&models.EventParticipant{}).
+		Select("event_id").
+		Where("user_id = ? AND status = ?", userID, models.ParticipantStatusAccepted)
+
+	query := r.db.Model(&models.Event{}).
+		Where("events.created_by = ? OR events.id IN (?)", userID, accepted).
+		Where("NOT (events.end_time <= ? OR events.start_time >= ?)", startTime, endTime).
+		Where("events.status <> ?", models.EventStatusCancelled)
+
+	if len(excludeEventIDs) > 0 {
+		query = query.Where("events.id NOT IN ?", excludeEventIDs)
+	}
+
+	var events []*models.Event
+	err := query.Order("events.start_time ASC, events.id ASC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conflicting events: %w", err)
+	}
+
+	return events, nil
+}
+
 // GetEventWithParticipants retrieves an event with its participants
 func (r *eventRepository) GetEventWithParticipants(id uint) (*models.Event, error) {
 	var event models.Event
@@ -501,10 +680,18 @@ func (r *eventRepository) applyFilters(query *gorm.DB, filter *models.EventFilte
 		return query
 	}
 
+	if filter.Page != nil {
+		query = query.Scopes(filter.Page.FilterScope)
+	}
+
 	if filter.Type != nil {
 		query = query.Where("events.type = ?", *filter.Type)
 	}
 
+	if filter.Status != nil {
+		query = query.Where("events.status = ?", *filter.Status)
+	}
+
 	if filter.StartAfter != nil {
 		query = query.Where("events.start_time > ?", *filter.StartAfter)
 	}
@@ -552,39 +739,11 @@ func (r *eventRepository) applyFilters(query *gorm.DB, filter *models.EventFilte
 
 // applySortingAndPagination applies sorting and pagination to the query
 func (r *eventRepository) applySortingAndPagination(query *gorm.DB, filter *models.EventFilterRequest) *gorm.DB {
-	if filter == nil {
+	if filter == nil || filter.Page == nil {
 		return query.Order("events.start_time ASC").Limit(20)
 	}
 
-	// Apply sorting
-	sortBy := "events.start_time"
-	sortOrder := "ASC"
-
-	if filter.SortBy != "" {
-		sortBy = "events." + filter.SortBy
-	}
-
-	if filter.SortOrder != "" {
-		sortOrder = filter.SortOrder
-	}
-
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
-
-	// Apply pagination
-	limit := 20
-	if filter.Limit > 0 {
-		limit = filter.Limit
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	offset := 0
-	if filter.Offset > 0 {
-		offset = filter.Offset
-	}
-
-	return query.Limit(limit).Offset(offset)
+	return query.Scopes(filter.Page.PageScope)
 }
 
 // loadEventDetails loads participant counts and user status for events
@@ -720,10 +879,14 @@ func (r *reminderRepository) GetUserReminders(userID uint) ([]*models.EventRemin
 	return reminders, nil
 }
 
-// GetPendingReminders retrieves reminders that need to be sent
+// GetPendingReminders retrieves reminders that need to be sent with their events, earliest first
 func (r *reminderRepository) GetPendingReminders(before time.Time) ([]*models.EventReminder, error) {
 	var reminders []*models.EventReminder
-	err := r.db.Where("trigger_time <= ? AND is_sent = ?", before, false).
+	err := r.db.
+		Joins("JOIN events ON events.id = event_reminders.event_id AND events.deleted_at IS NULL").
+		Where("event_reminders.trigger_time <= ? AND event_reminders.is_sent = ?", before, false).
+		Preload("Event").
+		Order("event_reminders.trigger_time ASC").
 		Find(&reminders).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending reminders: %w", err)
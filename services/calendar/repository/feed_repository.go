@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// FeedRepository defines the interface for calendar feed data operations
+type FeedRepository interface {
+	CreateFeed(feed *models.CalendarFeed) error
+	GetFeedByUserID(userID uint) (*models.CalendarFeed, error)
+	GetFeedByTokenHash(tokenHash string) (*models.CalendarFeed, error)
+	UpdateFeed(feed *models.CalendarFeed) error
+	TouchFeed(id uint, accessedAt time.Time) error
+}
+
+// feedRepository implements FeedRepository interface
+type feedRepository struct {
+	db *database.DB
+}
+
+// NewFeedRepository creates a new calendar feed repository
+func NewFeedRepository(db *database.DB) FeedRepository {
+	return &feedRepository{
+		db: db,
+	}
+}
+
+// CreateFeed creates a new calendar feed
+func (r *feedRepository) CreateFeed(feed *models.CalendarFeed) error {
+	if feed == nil {
+		return errors.New("feed cannot be nil")
+	}
+
+	if err := r.db.Create(feed).Error; err != nil {
+		return fmt.Errorf("failed to create calendar feed: %w", err)
+	}
+	return nil
+}
+
+// GetFeedByUserID retrieves the calendar feed of a user
+func (r *feedRepository) GetFeedByUserID(userID uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.Where("user_id = ?", userID).First(&feed).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("calendar feed not found")
+		}
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return &feed, nil
+}
+
+// GetFeedByTokenHash retrieves a calendar feed by the hash of its secret token
+func (r *feedRepository) GetFeedByTokenHash(tokenHash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.Where("token = ?", tokenHash).First(&feed).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("calendar feed not found")
+		}
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return &feed, nil
+}
+
+// UpdateFeed updates an existing calendar feed
+func (r *feedRepository) UpdateFeed(feed *models.CalendarFeed) error {
+	result := r.db.Save(feed)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update calendar feed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("calendar feed not found")
+	}
+	return nil
+}
+
+// TouchFeed records the last time the feed was fetched without changing updated_at
+func (r *feedRepository) TouchFeed(id uint, accessedAt time.Time) error {
+	err := r.db.Model(&models.CalendarFeed{}).
+		Where("id = ?", id).
+		UpdateColumn("last_accessed_at", accessedAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to update feed access time: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/calendar/models"
+
+	"gorm.io/gorm"
+)
+
+// GetUserEventReminders retrieves reminders of a user for an event, earliest first
+func (r *reminderRepository) GetUserEventReminders(eventID, userID uint) ([]*models.EventReminder, error) {
+	var reminders []*models.EventReminder
+	err := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).
+		Order("trigger_time ASC, id ASC").
+		Find(&reminders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user event reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// ReplaceUserEventReminders replaces all reminders of a user for an event in one transaction
+func (r *reminderRepository) ReplaceUserEventReminders(eventID, userID uint, reminders []*models.EventReminder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&models.EventReminder{}).Error; err != nil {
+			return fmt.Errorf("failed to delete user event reminders: %w", err)
+		}
+		if len(reminders) == 0 {
+			return nil
+		}
+		if err := tx.Create(&reminders).Error; err != nil {
+			return fmt.Errorf("failed to create user event reminders: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetReminderDefaults retrieves default reminders of a user, earliest first
+func (r *reminderRepository) GetReminderDefaults(userID uint) ([]*models.ReminderDefault, error) {
+	var defaults []*models.ReminderDefault
+	err := r.db.Where("user_id = ?", userID).
+		Order("minutes_before DESC, id ASC").
+		Find(&defaults).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder defaults: %w", err)
+	}
+	return defaults, nil
+}
+
+// ReplaceReminderDefaults replaces all default reminders of a user in one transaction
+func (r *reminderRepository) ReplaceReminderDefaults(userID uint, defaults []*models.ReminderDefault) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.ReminderDefault{}).Error; err != nil {
+			return fmt.Errorf("failed to delete reminder defaults: %w", err)
+		}
+		if len(defaults) == 0 {
+			return nil
+		}
+		if err := tx.Create(&defaults).Error; err != nil {
+			return fmt.Errorf("failed to create reminder defaults: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+// Package repotest provides calendar repositories on an in-memory SQLite database and
+// fixture factories for usecase and repository tests
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/services/calendar/repository"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/database/dbtest"
+)
+
+// Repositories holds all calendar repositories backed by one test database
+type Repositories struct {
+	DB           *database.DB
+	Events       repository.EventRepository
+	Participants repository.ParticipantRepository
+	Reminders    repository.ReminderRepository
+	Escalations  repository.EscalationRepository
+	Feeds        repository.FeedRepository
+	Holidays     repository.HolidayRepository
+	Absences     repository.AbsenceRepository
+	Company      repository.CompanyEventRepository
+	Delegations  repository.DelegationRepository
+}
+
+// New creates repositories on a fresh test database
+func New(tb testing.TB) *Repositories {
+	tb.Helper()
+
+	db := dbtest.Open(tb, models.Models()...)
+	return &Repositories{
+		DB:           db,
+		Events:       repository.NewEventRepository(db),
+		Participants: repository.NewParticipantRepository(db),
+		Reminders:    repository.NewReminderRepository(db),
+		Escalations:  repository.NewEscalationRepository(db),
+		Feeds:        repository.NewFeedRepository(db),
+		Holidays:     repository.NewHolidayRepository(db),
+		Absences:     repository.NewAbsenceRepository(db),
+		Company:      repository.NewCompanyEventRepository(db),
+		Delegations:  repository.NewDelegationRepository(db),
+	}
+}
+
+// Event creates a one hour meeting of the user starting tomorrow, options override the defaults before saving
+func (r *Repositories) Event(tb testing.TB, createdBy uint, options ...func(*models.Event)) *models.Event {
+	tb.Helper()
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	event := &models.Event{
+		Title:     "Test event",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Type:      models.EventTypeMeeting,
+		Status:    models.EventStatusActive,
+		CreatedBy: createdBy,
+	}
+	for _, option := range options {
+		option(event)
+	}
+
+	if err := r.Events.CreateEvent(event); err != nil {
+		tb.Fatalf("failed to create event fixture: %v", err)
+	}
+	return event
+}
+
+// Participant invites the user to the event with the status
+func (r *Repositories) Participant(tb testing.TB, eventID, userID uint, status models.ParticipantStatus) *models.EventParticipant {
+	tb.Helper()
+
+	participant := &models.EventParticipant{
+		EventID: eventID,
+		UserID:  userID,
+		Status:  status,
+	}
+	if err := r.Participants.AddParticipant(participant); err != nil {
+		tb.Fatalf("failed to create participant fixture: %v", err)
+	}
+	return participant
+}
+
+// Reminder creates an in-app reminder of the user triggered minutesBefore the event start
+func (r *Repositories) Reminder(tb testing.TB, eventID, userID uint, minutesBefore int) *models.EventReminder {
+	tb.Helper()
+
+	reminder := &models.EventReminder{
+		EventID:       eventID,
+		UserID:        userID,
+		Type:          models.ReminderTypeNotification,
+		MinutesBefore: &minutesBefore,
+	}
+	if err := r.Reminders.CreateReminder(reminder); err != nil {
+		tb.Fatalf("failed to create reminder fixture: %v", err)
+	}
+	return reminder
+}
@@ -0,0 +1,356 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+)
+
+func TestCalendarFixtures(t *testing.T) {
+	repos := New(t)
+
+	event := repos.Event(t, 1, func(event *models.Event) { event.Title = "Sprint Planning" })
+	repos.Event(t, 1, func(event *models.Event) { event.Title = "Dentist" })
+	repos.Participant(t, event.ID, 2, models.ParticipantStatusAccepted)
+
+	reminder := repos.Reminder(t, event.ID, 2, 15)
+	if !reminder.TriggerTime.Equal(event.StartTime.Add(-15 * time.Minute)) {
+		t.Errorf("expected reminder 15 minutes before the event, got %v", reminder.TriggerTime)
+	}
+
+	// ILIKE search runs on SQLite
+	events, total, err := repos.Events.SearchEvents(2, "planning", &models.EventFilterRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(events) != 1 || events[0].ID != event.ID {
+		t.Errorf("expected the participant to find the event, got %d events (total %d)", len(events), total)
+	}
+}
+
+func TestReplaceUserEventReminders(t *testing.T) {
+	repos := New(t)
+
+	event := repos.Event(t, 1)
+	repos.Reminder(t, event.ID, 1, 15)
+	other := repos.Reminder(t, event.ID, 2, 15)
+
+	hour, day := 60, 24*60
+	replacement := []*models.EventReminder{
+		{EventID: event.ID, UserID: 1, Type: models.ReminderTypePush, MinutesBefore: &hour},
+		{EventID: event.ID, UserID: 1, Type: models.ReminderTypeEmail, MinutesBefore: &day},
+	}
+	if err := repos.Reminders.ReplaceUserEventReminders(event.ID, 1, replacement); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reminders, err := repos.Reminders.GetUserEventReminders(event.ID, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reminders) != 2 || reminders[0].Type != models.ReminderTypeEmail || reminders[1].Type != models.ReminderTypePush {
+		t.Fatalf("expected the email reminder before the push one, got %+v", reminders)
+	}
+	if !reminders[1].TriggerTime.Equal(event.StartTime.Add(-time.Hour)) {
+		t.Errorf("expected push reminder an hour before the event, got %v", reminders[1].TriggerTime)
+	}
+
+	kept, err := repos.Reminders.GetUserEventReminders(event.ID, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kept) != 1 || kept[0].ID != other.ID {
+		t.Errorf("expected reminders of other users to be kept, got %+v", kept)
+	}
+}
+
+func TestReplaceReminderDefaults(t *testing.T) {
+	repos := New(t)
+
+	for _, minutes := range [][]int{{10, 60}, {1440}} {
+		defaults := make([]*models.ReminderDefault, len(minutes))
+		for i, m := range minutes {
+			defaults[i] = &models.ReminderDefault{UserID: 1, Type: models.ReminderTypeNotification, MinutesBefore: m}
+		}
+		if err := repos.Reminders.ReplaceReminderDefaults(1, defaults); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	defaults, err := repos.Reminders.GetReminderDefaults(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(defaults) != 1 || defaults[0].MinutesBefore != 1440 {
+		t.Errorf("expected only the last defaults to be kept, got %+v", defaults)
+	}
+}
+
+func TestCompanyEventAudience(t *testing.T) {
+	repos := New(t)
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	companyEvent := &models.CompanyEvent{
+		Title:         "Townhall",
+		StartTime:     start,
+		EndTime:       start.Add(time.Hour),
+		DepartmentIDs: []uint{2, 5},
+		Status:        models.CompanyEventStatusPublished,
+		CreatedBy:     1,
+	}
+	if err := repos.Company.CreateCompanyEvent(companyEvent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	event := &models.Event{
+		Title:          companyEvent.Title,
+		StartTime:      companyEvent.StartTime,
+		EndTime:        companyEvent.EndTime,
+		Type:           models.EventTypeMeeting,
+		CreatedBy:      9,
+		CompanyEventID: &companyEvent.ID,
+	}
+	if err := repos.Company.PublishCompanyEvent(companyEvent, event, []uint{9, 10, 11, 11}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err := repos.Company.GetCompanyEventByID(companyEvent.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.EventID == nil || *stored.EventID != event.ID || len(stored.DepartmentIDs) != 2 {
+		t.Errorf("expected published event %d and two departments, got %+v", event.ID, stored)
+	}
+
+	participants, err := repos.Participants.GetEventParticipants(event.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(participants) != 3 {
+		t.Fatalf("expected organizer and two audience participants, got %d", len(participants))
+	}
+
+	added, removed, err := repos.Company.SyncCompanyEventAudience(event.ID, []uint{11, 12})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 1 || added[0] != 12 || removed != 1 {
+		t.Errorf("expected user 12 added and user 10 removed, got added %v removed %d", added, removed)
+	}
+
+	participants, err = repos.Participants.GetEventParticipants(event.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	users := make(map[uint]bool)
+	for _, participant := range participants {
+		users[participant.UserID] = true
+	}
+	if len(users) != 3 || !users[9] || !users[11] || !users[12] {
+		t.Errorf("expected organizer 9 and users 11 and 12 to remain, got %v", users)
+	}
+
+	upcoming, err := repos.Company.GetUpcomingPublishedCompanyEvents(time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upcoming) != 1 || upcoming[0].ID != companyEvent.ID {
+		t.Errorf("expected the published company event to be upcoming, got %+v", upcoming)
+	}
+}
+
+func TestEventDetailsVisibility(t *testing.T) {
+	repos := New(t)
+
+	busy := repos.Event(t, 1, func(event *models.Event) {
+		event.Title = "Interview"
+		event.SetVisibility(models.EventVisibilityBusy)
+	})
+	legacy := repos.Event(t, 1, func(event *models.Event) { event.IsPrivate = true })
+	repos.Participant(t, busy.ID, 2, models.ParticipantStatusAccepted)
+
+	if legacy.Visibility != models.EventVisibilityPrivate {
+		t.Errorf("expected an event marked private to get private visibility, got %q", legacy.Visibility)
+	}
+
+	if err := repos.Events.ReplaceDetailGrants(busy.ID, []uint{3, 4}, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repos.Events.ReplaceDetailGrants(busy.ID, []uint{3}, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	grants, err := repos.Events.GetDetailGrants(busy.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(grants) != 1 || grants[0] != 3 {
+		t.Errorf("expected details shared with user 3 only, got %v", grants)
+	}
+
+	for userID, expected := range map[uint]bool{1: true, 2: true, 3: true, 4: false} {
+		visible, err := repos.Events.GetEventsWithDetails(userID, []uint{busy.ID, legacy.ID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if visible[busy.ID] != expected {
+			t.Errorf("user %d: expected details visible %v, got %v", userID, expected, visible[busy.ID])
+		}
+		if visible[legacy.ID] != (userID == 1) {
+			t.Errorf("user %d: expected the private event visible to its creator only", userID)
+		}
+	}
+
+	periods, err := repos.Events.GetBusyPeriods([]uint{2}, busy.StartTime.Add(-time.Minute), busy.EndTime)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(periods) != 1 || periods[0].EventID != busy.ID || periods[0].Visibility != models.EventVisibilityBusy {
+		t.Errorf("expected the busy period of the participant with its event, got %+v", periods)
+	}
+}
+
+func TestConflictingEvents(t *testing.T) {
+	repos := New(t)
+
+	own := repos.Event(t, 1, func(event *models.Event) { event.Title = "Standup" })
+	invited := repos.Event(t, 2, func(event *models.Event) { event.Title = "Review" })
+	repos.Participant(t, invited.ID, 1, models.ParticipantStatusAccepted)
+	repos.Participant(t, invited.ID, 3, models.ParticipantStatusAccepted)
+	declined := repos.Event(t, 3)
+	repos.Participant(t, declined.ID, 1, models.ParticipantStatusDeclined)
+	repos.Event(t, 1, func(event *models.Event) { event.Status = models.EventStatusCancelled })
+	repos.Event(t, 1, func(event *models.Event) {
+		event.StartTime = event.EndTime
+		event.EndTime = event.EndTime.Add(time.Hour)
+	})
+
+	events, err := repos.Events.GetConflictingEvents(1, own.StartTime, own.EndTime, nil, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != own.ID || events[1].ID != invited.ID {
+		t.Errorf("expected the own and the accepted event once each, got %+v", events)
+	}
+
+	events, err = repos.Events.GetConflictingEvents(1, own.StartTime, own.EndTime, []uint{own.ID}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != invited.ID {
+		t.Errorf("expected the excluded event to be skipped, got %+v", events)
+	}
+}
+
+func TestMergeDepartments(t *testing.T) {
+	repos := New(t)
+
+	mergedID, departmentID := uint(3), uint(5)
+	day := time.Now().Truncate(24 * time.Hour)
+	absence := &models.Absence{
+		UserID:       2,
+		DepartmentID: &mergedID,
+		Type:         models.AbsenceTypeVacation,
+		StartDate:    day,
+		EndDate:      day.AddDate(0, 0, 2),
+		CreatedBy:    2,
+	}
+	if err := repos.Absences.CreateAbsence(absence); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := day.Add(48 * time.Hour)
+	targeted := &models.CompanyEvent{Title: "Offsite", StartTime: start, EndTime: start.Add(time.Hour),
+		DepartmentIDs: []uint{3, 5}, CreatedBy: 1}
+	companyWide := &models.CompanyEvent{Title: "Townhall", StartTime: start, EndTime: start.Add(time.Hour),
+		DepartmentIDs: []uint{}, CreatedBy: 1}
+	for _, companyEvent := range []*models.CompanyEvent{targeted, companyWide} {
+		if err := repos.Company.CreateCompanyEvent(companyEvent); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	moved, retargeted, err := repos.Events.MergeDepartments(departmentID, mergedID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("expected 1 moved absence, got %d", moved)
+	}
+	if len(retargeted) != 1 || retargeted[0].ID != targeted.ID {
+		t.Fatalf("expected only the targeted company event to be retargeted, got %d", len(retargeted))
+	}
+
+	stored, err := repos.Company.GetCompanyEventByID(targeted.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored.DepartmentIDs) != 1 || stored.DepartmentIDs[0] != departmentID {
+		t.Errorf("expected the company event to target only department %d, got %v", departmentID, stored.DepartmentIDs)
+	}
+
+	absences, err := repos.Absences.GetDepartmentAbsences(departmentID, day, day.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(absences) != 1 || absences[0].ID != absence.ID {
+		t.Errorf("expected the absence in the remaining department, got %d absences", len(absences))
+	}
+}
+
+func TestCalendarDelegations(t *testing.T) {
+	repos := New(t)
+
+	delegation := &models.CalendarDelegation{OwnerID: 1, DelegateID: 2, Permission: models.DelegationPermissionManage}
+	granted := &models.CalendarDelegationAudit{OwnerID: 1, DelegateID: 2, ActorID: 1, Action: models.DelegationActionGranted}
+	if err := repos.Delegations.SaveDelegation(delegation, granted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if granted.DelegationID != delegation.ID {
+		t.Errorf("expected the audit entry to reference delegation %d, got %d", delegation.ID, granted.DelegationID)
+	}
+
+	event := repos.Event(t, 1)
+	repos.Participant(t, event.ID, 1, models.ParticipantStatusPending)
+	delegateID := delegation.DelegateID
+	if err := repos.Participants.UpdateParticipantStatus(event.ID, 1, models.ParticipantStatusAccepted, &delegateID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	participants, err := repos.Participants.GetEventParticipants(event.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(participants) != 1 || participants[0].RespondedBy == nil || *participants[0].RespondedBy != delegateID {
+		t.Errorf("expected the response to be attributed to the delegate, got %+v", participants)
+	}
+
+	now := time.Now()
+	delegation.RevokedAt = &now
+	delegation.RevokedBy = &delegation.OwnerID
+	revoked := &models.CalendarDelegationAudit{OwnerID: 1, DelegateID: 2, ActorID: 1, Action: models.DelegationActionRevoked}
+	if err := repos.Delegations.SaveDelegation(delegation, revoked); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := repos.Delegations.GetActiveDelegation(1, 2); err == nil {
+		t.Error("expected the revoked delegation not to be active")
+	}
+	received, err := repos.Delegations.GetDelegationsByDelegate(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 0 {
+		t.Errorf("expected no active delegations, got %d", len(received))
+	}
+
+	// The audit log survives revocation and is visible to both sides
+	for _, userID := range []uint{1, 2} {
+		entries, total, err := repos.Delegations.GetAuditLog(userID, 10, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if total != 2 || len(entries) != 2 || entries[0].Action != models.DelegationActionRevoked {
+			t.Errorf("expected user %d to see the revocation first, got %d entries (total %d)", userID, len(entries), total)
+		}
+	}
+}
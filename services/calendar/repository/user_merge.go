@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeUsers moves created events, event participations, reminders, absences, drafted company events and event detail grants of a duplicate account
+// to the primary account. In events both accounts take part in, the primary participation
+// becomes organizer if the duplicate organized the event.
+func (r *eventRepository) MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error) {
+	result := sharedmodels.NewMergeUsersResult("calendar")
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		organizedEvents := tx.Model(&models.EventParticipant{}).Select("event_id").
+			Where("user_id = ? AND is_organizer = ?", duplicateID, true)
+		err := tx.Model(&models.EventParticipant{}).
+			Where("user_id = ? AND event_id IN (?)", primaryID, organizedEvents).
+			Update("is_organizer", true).Error
+		if err != nil {
+			return fmt.Errorf("failed to transfer event organizers: %w", err)
+		}
+
+		reassignments := []struct {
+			kind         string
+			model        interface{}
+			userColumn   string
+			scopeColumns []string
+		}{
+			{"created_events", &models.Event{}, "created_by", nil},
+			{"event_participations", &models.EventParticipant{}, "user_id", []string{"event_id"}},
+			{"event_reminders", &models.EventReminder{}, "user_id", []string{"event_id"}},
+			{"reminder_defaults", &models.ReminderDefault{}, "user_id", []string{"type", "minutes_before"}},
+			{"absences", &models.Absence{}, "user_id", nil},
+			{"created_company_events", &models.CompanyEvent{}, "created_by", nil},
+			{"event_detail_grants", &models.EventDetailGrant{}, "user_id", []string{"event_id"}},
+		}
+
+		for _, reassignment := range reassignments {
+			moved, dropped, err := database.ReassignUser(tx, reassignment.model, reassignment.userColumn,
+				reassignment.scopeColumns, duplicateID, primaryID)
+			if err != nil {
+				return fmt.Errorf("failed to merge %s: %w", reassignment.kind, err)
+			}
+			result.Moved[reassignment.kind] = moved
+			if dropped > 0 {
+				result.Dropped[reassignment.kind] = dropped
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/calendar/models"
+
+	"gorm.io/gorm"
+)
+
+// GetDetailGrants retrieves IDs of users the organizer shared details of the event with
+func (r *eventRepository) GetDetailGrants(eventID uint) ([]uint, error) {
+	userIDs := []uint{}
+	err := r.db.Model(&models.EventDetailGrant{}).
+		Where("event_id = ?", eventID).
+		Order("user_id").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event detail grants: %w", err)
+	}
+	return userIDs, nil
+}
+
+// ReplaceDetailGrants sets the users details of the event are shared with
+func (r *eventRepository) ReplaceDetailGrants(eventID uint, userIDs []uint, grantedBy uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", eventID).Delete(&models.EventDetailGrant{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		grants := make([]*models.EventDetailGrant, len(userIDs))
+		for i, userID := range userIDs {
+			grants[i] = &models.EventDetailGrant{EventID: eventID, UserID: userID, GrantedBy: grantedBy}
+		}
+		return tx.Create(&grants).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update event detail grants: %w", err)
+	}
+	return nil
+}
+
+// GetEventsWithDetails returns which of the events the user may see details of regardless of their
+// visibility: events the user created, was invited to or was granted by the organizer
+func (r *eventRepository) GetEventsWithDetails(userID uint, eventIDs []uint) (map[uint]bool, error) {
+	visible := make(map[uint]bool, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return visible, nil
+	}
+
+	var ids []uint
+	err := r.db.Raw(`
+		SELECT id FROM events WHERE id IN ? AND created_by = ?
+		UNION
+		SELECT event_id FROM event_participants
+		WHERE event_id IN ? AND user_id = ? AND deleted_at IS NULL
+		UNION
+		SELECT event_id FROM event_detail_grants WHERE event_id IN ? AND user_id = ?`,
+		eventIDs, userID,
+		eventIDs, userID,
+		eventIDs, userID,
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check event details access: %w", err)
+	}
+
+	for _, id := range ids {
+		visible[id] = true
+	}
+	return visible, nil
+}
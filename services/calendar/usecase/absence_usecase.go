@@ -0,0 +1,373 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/validation"
+)
+
+const (
+	// availabilityStep is the granularity of suggested meeting start times
+	availabilityStep = 30 * time.Minute
+
+	// maxAvailabilityRange limits the period searched for meeting times
+	maxAvailabilityRange = 31 * 24 * time.Hour
+
+	// maxAbsenceRange limits absence records and overview periods
+	maxAbsenceRange = 366 * 24 * time.Hour
+
+	defaultAvailabilityLimit = 10
+)
+
+// GetHolidays returns public holidays within a year, optionally for a single region
+func (u *calendarUsecase) GetHolidays(filter *models.HolidayFilterRequest) ([]*models.Holiday, error) {
+	year := filter.Year
+	if year == 0 {
+		year = time.Now().UTC().Year()
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	holidays, err := u.holidayRepo.GetHolidays(strings.TrimSpace(filter.Region), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	return holidays, nil
+}
+
+// CreateHoliday adds a public holiday to a regional calendar
+func (u *calendarUsecase) CreateHoliday(userID uint, req *models.CreateHolidayRequest) (*models.Holiday, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	holiday := &models.Holiday{
+		Region:    strings.ToLower(strings.TrimSpace(req.Region)),
+		Date:      dateOnly(req.Date),
+		Name:      strings.TrimSpace(req.Name),
+		CreatedBy: userID,
+	}
+
+	if err := u.holidayRepo.CreateHoliday(holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+// DeleteHoliday removes a public holiday
+func (u *calendarUsecase) DeleteHoliday(holidayID uint) error {
+	return u.holidayRepo.DeleteHoliday(holidayID)
+}
+
+// GetUserAbsences returns absences of a user overlapping a period
+func (u *calendarUsecase) GetUserAbsences(userID uint, from, to time.Time) ([]*models.Absence, error) {
+	from, to = dateOnly(from), dateOnly(to)
+	if err := validateAbsenceRange(from, to); err != nil {
+		return nil, err
+	}
+
+	absences, err := u.absenceRepo.GetUserAbsences(userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absences: %w", err)
+	}
+	return absences, nil
+}
+
+// CreateAbsence records a vacation, sick leave or other absence. Employees record their own
+// absences, managers and admins can record absences of any user.
+func (u *calendarUsecase) CreateAbsence(userID uint, canManage bool, req *models.CreateAbsenceRequest) (*models.Absence, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	ownerID := userID
+	if req.UserID != nil && *req.UserID != userID {
+		if !canManage {
+			return nil, fmt.Errorf("access denied: only managers can record absences of other users")
+		}
+		ownerID = *req.UserID
+	}
+
+	absence := &models.Absence{
+		UserID:       ownerID,
+		DepartmentID: req.DepartmentID,
+		Type:         req.Type,
+		StartDate:    dateOnly(req.StartDate),
+		EndDate:      dateOnly(req.EndDate),
+		Note:         strings.TrimSpace(req.Note),
+		Source:       models.AbsenceSourceManual,
+		CreatedBy:    userID,
+	}
+	if err := validateAbsenceRange(absence.StartDate, absence.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := u.absenceRepo.CreateAbsence(absence); err != nil {
+		return nil, fmt.Errorf("failed to create absence: %w", err)
+	}
+	return absence, nil
+}
+
+// DeleteAbsence removes an absence. Synced records can only be removed by managers,
+// otherwise the next HR sync would not restore them.
+func (u *calendarUsecase) DeleteAbsence(userID uint, canManage bool, absenceID uint) error {
+	absence, err := u.absenceRepo.GetAbsenceByID(absenceID)
+	if err != nil {
+		return err
+	}
+
+	if !canManage && (absence.UserID != userID || absence.Source != models.AbsenceSourceManual) {
+		return fmt.Errorf("access denied: you cannot delete this absence")
+	}
+
+	return u.absenceRepo.DeleteAbsence(absenceID)
+}
+
+// SyncAbsences creates or updates absences exported from an HR system
+func (u *calendarUsecase) SyncAbsences(userID uint, req *models.SyncAbsencesRequest) (*models.SyncAbsencesResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	if source == models.AbsenceSourceManual {
+		return nil, fmt.Errorf("validation failed: source %q is reserved", source)
+	}
+
+	absences := make([]*models.Absence, 0, len(req.Absences))
+	for i, item := range req.Absences {
+		externalID := strings.TrimSpace(item.ExternalID)
+		absence := &models.Absence{
+			UserID:       item.UserID,
+			DepartmentID: item.DepartmentID,
+			Type:         item.Type,
+			StartDate:    dateOnly(item.StartDate),
+			EndDate:      dateOnly(item.EndDate),
+			Note:         strings.TrimSpace(item.Note),
+			ExternalID:   &externalID,
+			CreatedBy:    userID,
+		}
+		if err := validateAbsenceRange(absence.StartDate, absence.EndDate); err != nil {
+			return nil, fmt.Errorf("%w (absence %d)", err, i)
+		}
+		absences = append(absences, absence)
+	}
+
+	created, updated, err := u.absenceRepo.UpsertExternalAbsences(source, absences)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SyncAbsencesResponse{
+		Created: created,
+		Updated: updated,
+	}, nil
+}
+
+// GetTeamAbsences returns absences of a department or a list of users within a period
+func (u *calendarUsecase) GetTeamAbsences(req *models.TeamAbsenceRequest) (*models.TeamAbsenceResponse, error) {
+	from, to := dateOnly(req.From), dateOnly(req.To)
+	if err := validateAbsenceRange(from, to); err != nil {
+		return nil, err
+	}
+
+	var absences []*models.Absence
+	var err error
+	switch {
+	case req.DepartmentID != nil:
+		absences, err = u.absenceRepo.GetDepartmentAbsences(*req.DepartmentID, from, to)
+	case len(req.UserIDs) > 0:
+		absences, err = u.absenceRepo.GetAbsencesForUsers(req.UserIDs, from, to)
+	default:
+		return nil, fmt.Errorf("validation failed: department_id or user_ids is required")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team absences: %w", err)
+	}
+
+	response := &models.TeamAbsenceResponse{
+		From:     from,
+		To:       to,
+		Absences: absences,
+		ByUser:   make(map[uint][]*models.Absence),
+		ByType:   make(map[models.AbsenceType]int),
+	}
+	for _, absence := range absences {
+		response.ByUser[absence.UserID] = append(response.ByUser[absence.UserID], absence)
+		response.ByType[absence.Type]++
+	}
+
+	return response, nil
+}
+
+// FindAvailability suggests meeting times within working hours when all present users are free.
+// Working days and hours are taken from organization settings in the organization timezone;
+// non-working days and holidays of the requested region are skipped. Users absent on a slot are
+// excluded from the busy check and reported, so the organizer knows who will miss the meeting.
+func (u *calendarUsecase) FindAvailability(userID uint, req *models.AvailabilityRequest) (*models.AvailabilityResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	from, to := req.From.UTC(), req.To.UTC()
+	if !to.After(from) {
+		return nil, fmt.Errorf("validation failed: end time must be after start time")
+	}
+	if to.Sub(from) > maxAvailabilityRange {
+		return nil, fmt.Errorf("validation failed: date range too large (maximum 31 days)")
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAvailabilityLimit
+	}
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+
+	userIDs := uniqueUserIDs(append([]uint{userID}, req.UserIDs...))
+
+	settings := u.orgSettings.Get()
+	location := settings.Location()
+	week := settings.WorkingWeek
+
+	// Working days in the organization timezone may start a day before or end a day after UTC dates
+	searchFrom, searchTo := dateOnly(from).AddDate(0, 0, -1), dateOnly(to).AddDate(0, 0, 1)
+
+	holidays := make(map[string]bool)
+	if region := strings.ToLower(strings.TrimSpace(req.Region)); region != "" {
+		list, err := u.holidayRepo.GetHolidays(region, searchFrom, searchTo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get holidays: %w", err)
+		}
+		for _, holiday := range list {
+			holidays[holiday.Date.Format(time.DateOnly)] = true
+		}
+	}
+
+	absences, err := u.absenceRepo.GetAbsencesForUsers(userIDs, searchFrom, searchTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absences: %w", err)
+	}
+
+	busy, err := u.eventRepo.GetBusyPeriods(userIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := u.busyBlocks(userID, busy)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]*models.AvailabilitySlot, 0, limit)
+	firstDay := from.In(location)
+	for day := time.Date(firstDay.Year(), firstDay.Month(), firstDay.Day(), 0, 0, 0, 0, location); day.Before(to) && len(slots) < limit; day = day.AddDate(0, 0, 1) {
+		if !week.IsWorkingDay(day.Weekday()) || holidays[day.Format(time.DateOnly)] {
+			continue
+		}
+
+		workdayStart := time.Date(day.Year(), day.Month(), day.Day(), week.StartHour, 0, 0, 0, location).UTC()
+		workdayEnd := time.Date(day.Year(), day.Month(), day.Day(), week.EndHour, 0, 0, 0, location).UTC()
+		for start := workdayStart; !start.Add(duration).After(workdayEnd) && len(slots) < limit; start = start.Add(availabilityStep) {
+			end := start.Add(duration)
+			if start.Before(from) || end.After(to) {
+				continue
+			}
+
+			absent := absentUsers(absences, start, end)
+			if len(absent) == len(userIDs) || !allFree(busy, absent, start, end) {
+				continue
+			}
+
+			slots = append(slots, &models.AvailabilitySlot{
+				StartTime:     start,
+				EndTime:       end,
+				AbsentUserIDs: absent,
+			})
+		}
+	}
+
+	return &models.AvailabilityResponse{Slots: slots, Busy: blocks}, nil
+}
+
+// absentUsers returns IDs of users absent during any part of the period
+func absentUsers(absences []*models.Absence, start, end time.Time) []uint {
+	seen := make(map[uint]bool)
+	var userIDs []uint
+	for _, absence := range absences {
+		if !seen[absence.UserID] && absence.Covers(start, end) {
+			seen[absence.UserID] = true
+			userIDs = append(userIDs, absence.UserID)
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	return userIDs
+}
+
+// allFree checks that no user except the absent ones has an event overlapping the period
+func allFree(busy []*models.BusyPeriod, absent []uint, start, end time.Time) bool {
+	for _, period := range busy {
+		if !period.StartTime.Before(end) || !period.EndTime.After(start) {
+			continue
+		}
+		if !containsUserID(absent, period.UserID) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsUserID checks if the user ID is in the list
+func containsUserID(userIDs []uint, userID uint) bool {
+	for _, id := range userIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueUserIDs removes duplicate user IDs keeping the original order
+func uniqueUserIDs(userIDs []uint) []uint {
+	seen := make(map[uint]bool, len(userIDs))
+	unique := make([]uint, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// validateAbsenceRange checks an inclusive date range of absences
+func validateAbsenceRange(from, to time.Time) error {
+	if to.Before(from) {
+		return fmt.Errorf("validation failed: end date must be after start date")
+	}
+	if to.Sub(from) > maxAbsenceRange {
+		return fmt.Errorf("validation failed: date range too large (maximum 366 days)")
+	}
+	return nil
+}
+
+// dateOnly truncates a time to the start of its UTC day
+func dateOnly(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
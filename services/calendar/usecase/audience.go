@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AudienceResolver resolves which users a company event is published to
+type AudienceResolver interface {
+	// Audience returns IDs of active users of the departments, or of all active users if none are given
+	Audience(departmentIDs []uint) ([]uint, error)
+}
+
+// httpAudienceResolver asks the user service for active users of departments
+type httpAudienceResolver struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPAudienceResolver creates an audience resolver for the user service at baseURL.
+// It returns nil if baseURL is empty, company events then cannot be published.
+func NewHTTPAudienceResolver(baseURL string) AudienceResolver {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpAudienceResolver{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Audience requests active users of the departments from the user service
+func (r *httpAudienceResolver) Audience(departmentIDs []uint) ([]uint, error) {
+	if departmentIDs == nil {
+		departmentIDs = []uint{}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"department_ids": departmentIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audience request: %w", err)
+	}
+
+	resp, err := r.client.Post(r.baseURL+"/api/v1/internal/users/audience", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request audience: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service responded with status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		UserIDs []uint `json:"user_ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode audience: %w", err)
+	}
+
+	return payload.UserIDs, nil
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/validation"
+)
+
+// BulkCreateEvents creates events in request order. Each event is validated and checked for
+// conflicts on its own, events that fail do not stop the rest.
+func (u *calendarUsecase) BulkCreateEvents(userID uint, req *models.BulkCreateEventsRequest) (*models.BulkEventsResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	response := &models.BulkEventsResponse{Results: make([]*models.BulkEventResult, 0, len(req.Events))}
+	for i := range req.Events {
+		response.Add(u.bulkCreateEvent(userID, i, &req.Events[i]))
+	}
+	return response, nil
+}
+
+// bulkCreateEvent creates one event of a bulk request
+func (u *calendarUsecase) bulkCreateEvent(userID uint, index int, req *models.CreateEventRequest) *models.BulkEventResult {
+	result := &models.BulkEventResult{Index: index}
+
+	if err := u.validateCreateEventRequest(req); err != nil {
+		return bulkFailure(result, fmt.Errorf("validation failed: %w", err))
+	}
+
+	conflicts, err := u.findConflicts(userID, req.StartTime, req.EndTime, nil)
+	if err != nil {
+		return bulkFailure(result, err)
+	}
+	if len(conflicts) > 0 {
+		return bulkConflict(result, conflicts)
+	}
+
+	event, err := u.createEvent(userID, nil, req)
+	if err != nil {
+		return bulkFailure(result, err)
+	}
+
+	result.EventID = event.ID
+	result.Status = models.BulkEventCreated
+	result.Event = event
+	return result
+}
+
+// BulkMoveEvents moves events by the same offset, recording each move in the reschedule history.
+// Events of the request move together, so they are not reported as conflicts of each other.
+func (u *calendarUsecase) BulkMoveEvents(userID uint, req *models.BulkUpdateEventsRequest) (*models.BulkEventsResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	offset, err := req.Duration()
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	response := &models.BulkEventsResponse{Results: make([]*models.BulkEventResult, 0, len(req.EventIDs))}
+	for i, eventID := range req.EventIDs {
+		response.Add(u.bulkMoveEvent(userID, i, eventID, offset, req))
+	}
+	return response, nil
+}
+
+// bulkMoveEvent moves one event of a bulk request
+func (u *calendarUsecase) bulkMoveEvent(userID uint, index int, eventID uint, offset time.Duration, req *models.BulkUpdateEventsRequest) *models.BulkEventResult {
+	result := &models.BulkEventResult{Index: index, EventID: eventID}
+
+	event, err := u.getEventForChange(userID, eventID, "reschedule")
+	if err != nil {
+		return bulkFailure(result, err)
+	}
+
+	startTime, endTime := event.StartTime.Add(offset), event.EndTime.Add(offset)
+	if startTime.Before(time.Now().Add(-5 * time.Minute)) {
+		return bulkFailure(result, fmt.Errorf("validation failed: start time cannot be in the past"))
+	}
+
+	conflicts, err := u.findConflicts(userID, startTime, endTime, req.EventIDs)
+	if err != nil {
+		return bulkFailure(result, err)
+	}
+	if len(conflicts) > 0 {
+		return bulkConflict(result, conflicts)
+	}
+
+	if err := u.saveReschedule(userID, event, startTime, endTime, req.Reason); err != nil {
+		return bulkFailure(result, err)
+	}
+
+	result.Status = models.BulkEventMoved
+	if moved, err := u.GetEventByID(userID, eventID); err == nil {
+		result.Event = moved
+	}
+	return result
+}
+
+// BulkCancelEvents cancels events with a shared reason. Each cancellation can be undone with its
+// own receipt, participants are notified once it can no longer be undone.
+func (u *calendarUsecase) BulkCancelEvents(userID uint, req *models.BulkCancelEventsRequest) (*models.BulkEventsResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	cancel := &models.CancelEventRequest{Reason: req.Reason}
+	response := &models.BulkEventsResponse{Results: make([]*models.BulkEventResult, 0, len(req.EventIDs))}
+	for i, eventID := range req.EventIDs {
+		result := &models.BulkEventResult{Index: i, EventID: eventID}
+
+		event, receipt, err := u.CancelEvent(userID, eventID, cancel)
+		if err != nil {
+			response.Add(bulkFailure(result, err))
+			continue
+		}
+
+		result.Status = models.BulkEventCancelled
+		result.Event = event
+		result.Undo = receipt
+		response.Add(result)
+	}
+	return response, nil
+}
+
+// findConflicts lists events of the user overlapping the period, except excludeEventIDs
+func (u *calendarUsecase) findConflicts(userID uint, startTime, endTime time.Time, excludeEventIDs []uint) ([]*models.EventConflict, error) {
+	events, err := u.eventRepo.GetConflictingEvents(userID, startTime, endTime, excludeEventIDs, models.MaxReportedConflicts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check time conflict: %w", err)
+	}
+	return models.NewEventConflicts(events), nil
+}
+
+// bulkConflict marks an item of a bulk request as conflicting with events of the user
+func bulkConflict(result *models.BulkEventResult, conflicts []*models.EventConflict) *models.BulkEventResult {
+	result.Status = models.BulkEventConflict
+	result.Error = "time conflict detected: you have another event scheduled at this time"
+	result.Conflicts = conflicts
+	return result
+}
+
+// bulkFailure marks an item of a bulk request as failed. A conflict found only on save, by an
+// event created meanwhile, is still reported as a conflict.
+func bulkFailure(result *models.BulkEventResult, err error) *models.BulkEventResult {
+	result.Status = models.BulkEventFailed
+	if strings.Contains(err.Error(), "time conflict") {
+		result.Status = models.BulkEventConflict
+	}
+	result.Error = err.Error()
+	return result
+}
@@ -8,6 +8,13 @@ import (
 
 	"tachyon-messenger/services/calendar/models"
 	"tachyon-messenger/services/calendar/repository"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/orgsettings"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/refs"
+	"tachyon-messenger/shared/undo"
+	"tachyon-messenger/shared/validation"
 
 	"gorm.io/gorm"
 )
@@ -21,6 +28,21 @@ type CalendarUsecase interface {
 	GetUserCalendar(userID uint, startDate, endDate time.Time) (*models.EventListResponse, error)
 	GetUserEvents(userID uint, filter *models.EventFilterRequest) (*models.EventListResponse, error)
 
+	// Trash management
+	GetDeletedEvents(userID uint, filter *models.EventFilterRequest) (*models.EventListResponse, error)
+	RestoreEvent(userID, eventID uint) (*models.EventResponse, error)
+	PurgeDeletedEvents(retention time.Duration) (int64, error)
+
+	// Cancellation and rescheduling
+	CancelEvent(userID, eventID uint, req *models.CancelEventRequest) (*models.EventResponse, *undo.Receipt, error)
+	RescheduleEvent(userID, eventID uint, req *models.RescheduleEventRequest) (*models.EventResponse, error)
+	GetEventReschedules(userID, eventID uint) ([]*models.EventReschedule, error)
+
+	// Bulk operations, with per-event results
+	BulkCreateEvents(userID uint, req *models.BulkCreateEventsRequest) (*models.BulkEventsResponse, error)
+	BulkMoveEvents(userID uint, req *models.BulkUpdateEventsRequest) (*models.BulkEventsResponse, error)
+	BulkCancelEvents(userID uint, req *models.BulkCancelEventsRequest) (*models.BulkEventsResponse, error)
+
 	// Participant management
 	InviteParticipants(userID, eventID uint, req *models.AddParticipantsRequest) error
 	RemoveParticipant(userID, eventID, participantID uint) error
@@ -29,39 +51,152 @@ type CalendarUsecase interface {
 	// Reminder management
 	SetReminder(userID, eventID uint, req *models.CreateReminderRequest) (*models.EventReminderResponse, error)
 	RemoveReminder(userID, eventID, reminderID uint) error
+	GetEventReminders(userID, eventID uint) ([]*models.EventReminderResponse, error)
+	SetEventReminders(userID, eventID uint, req *models.SetRemindersRequest) ([]*models.EventReminderResponse, error)
+	GetReminderDefaults(userID uint) ([]*models.ReminderDefaultResponse, error)
+	SetReminderDefaults(userID uint, req *models.SetRemindersRequest) ([]*models.ReminderDefaultResponse, error)
+	ProcessDueReminders(now time.Time) (int, error)
 
 	// Additional features
 	GetEventStats(userID uint) (*models.EventStatsResponse, error)
 	SearchEvents(userID uint, searchQuery string, filter *models.EventFilterRequest) (*models.EventListResponse, error)
 	CheckTimeConflict(userID uint, startTime, endTime time.Time, excludeEventID *uint) (bool, error)
+	QuickAdd(userID uint, req *models.QuickAddRequest) (*models.QuickAddDraft, error)
+
+	// Calendar delegation
+	GetDelegations(userID uint) (*models.DelegationListResponse, error)
+	GrantDelegation(userID, delegateID uint, req *models.GrantDelegationRequest) (*models.CalendarDelegation, error)
+	RevokeDelegation(userID, delegateID uint) error
+	GetDelegationAudit(userID uint, req *models.DelegationAuditRequest) (*models.DelegationAuditListResponse, error)
+	GetDelegatedCalendar(userID, ownerID uint, startDate, endDate time.Time) (*models.EventListResponse, error)
+	CreateEventOnBehalf(userID, ownerID uint, req *models.CreateEventRequest) (*models.EventResponse, error)
+	RespondOnBehalf(userID, ownerID, eventID uint, req *models.UpdateParticipantStatusRequest) error
+
+	// Event visibility
+	GetEventVisibility(userID, eventID uint) (*models.EventVisibilityResponse, error)
+	UpdateEventVisibility(userID, eventID uint, req *models.UpdateEventVisibilityRequest) (*models.EventVisibilityResponse, error)
+
+	// Calendar feed
+	GetCalendarFeed(userID uint) (*models.CalendarFeedResponse, error)
+	UpdateCalendarFeed(userID uint, req *models.UpdateCalendarFeedRequest) (*models.CalendarFeedResponse, error)
+	RegenerateCalendarFeedToken(userID uint) (*models.CalendarFeedResponse, error)
+	RenderCalendarFeed(token string) (*models.CalendarFeedContent, error)
+
+	// RSVP reminder escalation
+	SetEventEscalation(userID, eventID uint, req *models.SetEscalationRequest) (*models.EscalationResponse, error)
+	GetEventEscalation(userID, eventID uint) (*models.EscalationResponse, error)
+	RemoveEventEscalation(userID, eventID uint) error
+	ProcessReminderEscalations(now time.Time) (int, error)
+
+	// Holidays and absences
+	GetHolidays(filter *models.HolidayFilterRequest) ([]*models.Holiday, error)
+	CreateHoliday(userID uint, req *models.CreateHolidayRequest) (*models.Holiday, error)
+	DeleteHoliday(holidayID uint) error
+	GetUserAbsences(userID uint, from, to time.Time) ([]*models.Absence, error)
+	CreateAbsence(userID uint, canManage bool, req *models.CreateAbsenceRequest) (*models.Absence, error)
+	DeleteAbsence(userID uint, canManage bool, absenceID uint) error
+	SyncAbsences(userID uint, req *models.SyncAbsencesRequest) (*models.SyncAbsencesResponse, error)
+	GetTeamAbsences(req *models.TeamAbsenceRequest) (*models.TeamAbsenceResponse, error)
+	FindAvailability(userID uint, req *models.AvailabilityRequest) (*models.AvailabilityResponse, error)
+
+	// Company events
+	GetCompanyEvents(filter *models.CompanyEventFilterRequest) ([]*models.CompanyEvent, int64, error)
+	GetCompanyEvent(companyEventID uint) (*models.CompanyEvent, error)
+	CreateCompanyEvent(userID uint, req *models.CreateCompanyEventRequest) (*models.CompanyEvent, error)
+	UpdateCompanyEvent(userID uint, canPublish bool, companyEventID uint, req *models.UpdateCompanyEventRequest) (*models.CompanyEvent, error)
+	DeleteCompanyEvent(userID uint, canPublish bool, companyEventID uint) error
+	SubmitCompanyEvent(userID uint, canPublish bool, companyEventID uint) (*models.CompanyEvent, error)
+	RejectCompanyEvent(userID, companyEventID uint, req *models.RejectCompanyEventRequest) (*models.CompanyEvent, error)
+	PublishCompanyEvent(userID, companyEventID uint) (*models.CompanyEvent, error)
+	CancelCompanyEvent(userID, companyEventID uint, req *models.CancelEventRequest) (*models.CompanyEvent, error)
+	SyncCompanyEventAudiences(now time.Time) (int, error)
+
+	// Account merge
+	MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error)
+
+	// Daily digest
+	GetDigestStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.CalendarDigestStats, error)
+
+	// Department changes
+	HandleDepartmentEvent(event *sharedmodels.DepartmentEvent) error
 }
 
 // calendarUsecase implements CalendarUsecase interface
 type calendarUsecase struct {
-	eventRepo       repository.EventRepository
-	participantRepo repository.ParticipantRepository
-	reminderRepo    repository.ReminderRepository
+	eventRepo        repository.EventRepository
+	participantRepo  repository.ParticipantRepository
+	reminderRepo     repository.ReminderRepository
+	feedRepo         repository.FeedRepository
+	escalationRepo   repository.EscalationRepository
+	holidayRepo      repository.HolidayRepository
+	absenceRepo      repository.AbsenceRepository
+	companyEventRepo repository.CompanyEventRepository
+	delegationRepo   repository.DelegationRepository
+	notifier         EventNotifier    // nil disables event notifications
+	audience         AudienceResolver // nil disables publishing of company events
+	users            UserMatcher      // nil leaves names in quick add text unresolved
+	orgSettings      *orgsettings.Client
+	userRefs         *refs.Validator // nil stores participant IDs unchecked
+	undo             *undo.Manager
 }
 
-// NewCalendarUsecase creates a new calendar usecase
+// NewCalendarUsecase creates a new calendar usecase and registers its undoable actions with undoManager
 func NewCalendarUsecase(
 	eventRepo repository.EventRepository,
 	participantRepo repository.ParticipantRepository,
 	reminderRepo repository.ReminderRepository,
+	feedRepo repository.FeedRepository,
+	escalationRepo repository.EscalationRepository,
+	holidayRepo repository.HolidayRepository,
+	absenceRepo repository.AbsenceRepository,
+	companyEventRepo repository.CompanyEventRepository,
+	delegationRepo repository.DelegationRepository,
+	notifier EventNotifier,
+	audience AudienceResolver,
+	users UserMatcher,
+	orgSettings *orgsettings.Client,
+	userRefs *refs.Validator,
+	undoManager *undo.Manager,
 ) CalendarUsecase {
-	return &calendarUsecase{
-		eventRepo:       eventRepo,
-		participantRepo: participantRepo,
-		reminderRepo:    reminderRepo,
-	}
+	u := &calendarUsecase{
+		eventRepo:        eventRepo,
+		participantRepo:  participantRepo,
+		reminderRepo:     reminderRepo,
+		feedRepo:         feedRepo,
+		escalationRepo:   escalationRepo,
+		holidayRepo:      holidayRepo,
+		absenceRepo:      absenceRepo,
+		companyEventRepo: companyEventRepo,
+		delegationRepo:   delegationRepo,
+		notifier:         notifier,
+		audience:         audience,
+		users:            users,
+		orgSettings:      orgSettings,
+		userRefs:         userRefs,
+		undo:             undoManager,
+	}
+	undoManager.Register(ActionCancelEvent, undo.Operation{
+		Commit: u.commitEventCancellation,
+		Revert: u.revertEventCancellation,
+	})
+	return u
 }
 
 // CreateEvent creates a new event with conflict checking
 func (u *calendarUsecase) CreateEvent(userID uint, req *models.CreateEventRequest) (*models.EventResponse, error) {
+	return u.createEvent(userID, nil, req)
+}
+
+// createEvent creates an event organized by userID, delegateID is the delegate who creates it on behalf
+// of the user or nil
+func (u *calendarUsecase) createEvent(userID uint, delegateID *uint, req *models.CreateEventRequest) (*models.EventResponse, error) {
 	// Validate request
 	if err := u.validateCreateEventRequest(req); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := u.userRefs.CheckUsers(req.ParticipantIDs...); err != nil {
+		return nil, err
+	}
 
 	// Check for time conflicts
 	hasConflict, err := u.eventRepo.CheckTimeConflict(userID, req.StartTime, req.EndTime, nil)
@@ -86,7 +221,9 @@ func (u *calendarUsecase) CreateEvent(userID uint, req *models.CreateEventReques
 		IsRecurring:    req.IsRecurring,
 		RecurrenceRule: strings.TrimSpace(req.RecurrenceRule),
 		TaskID:         req.TaskID,
+		DelegateID:     delegateID,
 	}
+	event.SetVisibility(requestedVisibility(req.Visibility, req.IsPrivate))
 
 	// Set type (default to personal if not provided)
 	if req.Type != "" {
@@ -137,25 +274,22 @@ func (u *calendarUsecase) CreateEvent(userID uint, req *models.CreateEventReques
 		}
 	}
 
-	// Create reminders if provided
+	// Create reminders if provided, otherwise the creator gets their default reminders
 	if len(req.Reminders) > 0 {
-		for _, reminderReq := range req.Reminders {
-			reminder := &models.EventReminder{
-				EventID:       event.ID,
-				UserID:        userID,
-				Type:          reminderReq.Type,
-				MinutesBefore: &reminderReq.MinutesBefore,
-				Message:       strings.TrimSpace(reminderReq.Message),
-			}
-
-			// Calculate trigger time
-			reminder.TriggerTime = event.StartTime.Add(-time.Duration(reminderReq.MinutesBefore) * time.Minute)
-
-			if err := u.reminderRepo.CreateReminder(reminder); err != nil {
-				// Log error but don't fail the entire operation
-				continue
-			}
+		reminders, err := buildEventReminders(event, userID, req.Reminders)
+		if err == nil {
+			err = u.reminderRepo.ReplaceUserEventReminders(event.ID, userID, reminders)
 		}
+		if err != nil {
+			// Log error but don't fail the entire operation
+			logger.WithFields(map[string]interface{}{
+				"event_id": event.ID,
+				"user_id":  userID,
+				"error":    err.Error(),
+			}).Warn("Failed to create event reminders")
+		}
+	} else {
+		u.applyDefaultReminders(event, userID)
 	}
 
 	// Get the created event with all details
@@ -177,8 +311,11 @@ func (u *calendarUsecase) GetEventByID(userID, eventID uint) (*models.EventRespo
 		return nil, fmt.Errorf("failed to get event: %w", err)
 	}
 
-	// Check access rights
+	// Check access rights, busy-only events show as busy blocks to everyone else
 	if !u.hasEventAccess(userID, event) {
+		if event.EffectiveVisibility() == models.EventVisibilityBusy {
+			return event.ToBusyResponse(), nil
+		}
 		return nil, fmt.Errorf("access denied: insufficient permissions")
 	}
 
@@ -211,6 +348,9 @@ func (u *calendarUsecase) UpdateEvent(userID, eventID uint, req *models.UpdateEv
 	if event.CreatedBy != userID {
 		return nil, fmt.Errorf("access denied: only event creator can update the event")
 	}
+	if event.IsCompanyEvent() {
+		return nil, errCompanyEventReadOnly
+	}
 
 	// Update fields if provided
 	if req.Title != nil {
@@ -228,8 +368,10 @@ func (u *calendarUsecase) UpdateEvent(userID, eventID uint, req *models.UpdateEv
 	if req.Color != nil {
 		event.Color = *req.Color
 	}
-	if req.IsPrivate != nil {
-		event.IsPrivate = *req.IsPrivate
+	if req.Visibility != nil {
+		event.SetVisibility(*req.Visibility)
+	} else if req.IsPrivate != nil && *req.IsPrivate != event.IsPrivate {
+		event.SetVisibility(requestedVisibility(nil, *req.IsPrivate))
 	}
 	if req.IsRecurring != nil {
 		event.IsRecurring = *req.IsRecurring
@@ -267,6 +409,11 @@ func (u *calendarUsecase) UpdateEvent(userID, eventID uint, req *models.UpdateEv
 		event.EndTime = endTime
 	}
 
+	// Changes based on a stale version are rejected on save
+	if req.Version != nil {
+		event.Version = *req.Version
+	}
+
 	// Save updated event
 	if err := u.eventRepo.UpdateEvent(event); err != nil {
 		return nil, fmt.Errorf("failed to update event: %w", err)
@@ -296,6 +443,9 @@ func (u *calendarUsecase) DeleteEvent(userID, eventID uint) error {
 	if event.CreatedBy != userID {
 		return fmt.Errorf("access denied: only event creator can delete the event")
 	}
+	if event.IsCompanyEvent() {
+		return errCompanyEventReadOnly
+	}
 
 	// Delete event (cascades to participants and reminders)
 	if err := u.eventRepo.DeleteEvent(eventID); err != nil {
@@ -305,6 +455,72 @@ func (u *calendarUsecase) DeleteEvent(userID, eventID uint) error {
 	return nil
 }
 
+// GetDeletedEvents retrieves events deleted by the user that can still be restored
+func (u *calendarUsecase) GetDeletedEvents(userID uint, filter *models.EventFilterRequest) (*models.EventListResponse, error) {
+	if filter == nil {
+		filter = &models.EventFilterRequest{}
+	}
+	if filter.Page == nil {
+		filter.Page = query.Default(models.EventTrashListOptions)
+	}
+
+	events, total, err := u.eventRepo.GetDeletedEvents(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted events: %w", err)
+	}
+
+	// Convert to response format
+	responses := make([]*models.EventResponse, len(events))
+	for i, event := range events {
+		responses[i] = event.ToResponse()
+	}
+
+	return &models.EventListResponse{
+		Events:     responses,
+		Total:      total,
+		Limit:      filter.Page.Limit,
+		Offset:     filter.Page.Offset,
+		NextCursor: filter.Page.NextCursor(responses),
+		Filters:    filter,
+	}, nil
+}
+
+// RestoreEvent restores a deleted event from trash
+func (u *calendarUsecase) RestoreEvent(userID, eventID uint) (*models.EventResponse, error) {
+	event, err := u.eventRepo.GetDeletedEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	// Check permissions: only creator can restore, as only creator can delete
+	if event.CreatedBy != userID {
+		return nil, fmt.Errorf("access denied: only event creator can restore the event")
+	}
+
+	if err := u.eventRepo.RestoreEvent(eventID); err != nil {
+		return nil, fmt.Errorf("failed to restore event: %w", err)
+	}
+
+	restored, err := u.eventRepo.GetEventWithAll(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored event: %w", err)
+	}
+
+	return restored.ToResponse(), nil
+}
+
+// PurgeDeletedEvents permanently deletes events that stayed in trash longer than retention
+func (u *calendarUsecase) PurgeDeletedEvents(retention time.Duration) (int64, error) {
+	purged, err := u.eventRepo.PurgeDeletedEvents(time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted events: %w", err)
+	}
+	return purged, nil
+}
+
 // GetUserCalendar retrieves user's calendar for a date range
 func (u *calendarUsecase) GetUserCalendar(userID uint, startDate, endDate time.Time) (*models.EventListResponse, error) {
 	// Validate date range
@@ -341,17 +557,10 @@ func (u *calendarUsecase) GetUserCalendar(userID uint, startDate, endDate time.T
 func (u *calendarUsecase) GetUserEvents(userID uint, filter *models.EventFilterRequest) (*models.EventListResponse, error) {
 	// Set default pagination if not provided
 	if filter == nil {
-		filter = &models.EventFilterRequest{
-			Limit:  20,
-			Offset: 0,
-		}
-	}
-
-	if filter.Limit <= 0 {
-		filter.Limit = 20
+		filter = &models.EventFilterRequest{}
 	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
+	if filter.Page == nil {
+		filter.Page = query.Default(models.EventListOptions)
 	}
 
 	// Get events from repository
@@ -367,11 +576,12 @@ func (u *calendarUsecase) GetUserEvents(userID uint, filter *models.EventFilterR
 	}
 
 	return &models.EventListResponse{
-		Events:  responses,
-		Total:   total,
-		Limit:   filter.Limit,
-		Offset:  filter.Offset,
-		Filters: filter,
+		Events:     responses,
+		Total:      total,
+		Limit:      filter.Page.Limit,
+		Offset:     filter.Page.Offset,
+		NextCursor: filter.Page.NextCursor(responses),
+		Filters:    filter,
 	}, nil
 }
 
@@ -395,6 +605,12 @@ func (u *calendarUsecase) InviteParticipants(userID, eventID uint, req *models.A
 	if event.CreatedBy != userID {
 		return fmt.Errorf("access denied: only event creator can invite participants")
 	}
+	if event.IsCompanyEvent() {
+		return errCompanyEventReadOnly
+	}
+	if err := u.userRefs.CheckUsers(req.UserIDs...); err != nil {
+		return err
+	}
 
 	// Add participants
 	for _, participantID := range req.UserIDs {
@@ -438,6 +654,11 @@ func (u *calendarUsecase) RemoveParticipant(userID, eventID, participantID uint)
 		return fmt.Errorf("access denied: insufficient permissions")
 	}
 
+	// Audience of company events follows their targeted departments
+	if event.IsCompanyEvent() {
+		return errCompanyEventReadOnly
+	}
+
 	// Cannot remove the creator/organizer
 	if participantID == event.CreatedBy {
 		return fmt.Errorf("cannot remove event organizer")
@@ -453,6 +674,12 @@ func (u *calendarUsecase) RemoveParticipant(userID, eventID, participantID uint)
 
 // UpdateParticipantStatus updates a participant's status for an event
 func (u *calendarUsecase) UpdateParticipantStatus(userID, eventID uint, req *models.UpdateParticipantStatusRequest) error {
+	return u.updateParticipantStatus(userID, eventID, nil, req)
+}
+
+// updateParticipantStatus records the response of userID to an invitation, respondedBy is the delegate
+// who responds on behalf of the user or nil
+func (u *calendarUsecase) updateParticipantStatus(userID, eventID uint, respondedBy *uint, req *models.UpdateParticipantStatusRequest) error {
 	// Validate request
 	if err := u.validateUpdateParticipantStatusRequest(req); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
@@ -467,11 +694,23 @@ func (u *calendarUsecase) UpdateParticipantStatus(userID, eventID uint, req *mod
 		return fmt.Errorf("user is not a participant of this event")
 	}
 
+	previousStatus, err := u.participantRepo.GetParticipantStatus(eventID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get participant status: %w", err)
+	}
+
 	// Update participant status
-	if err := u.participantRepo.UpdateParticipantStatus(eventID, userID, req.Status); err != nil {
+	if err := u.participantRepo.UpdateParticipantStatus(eventID, userID, req.Status, respondedBy); err != nil {
 		return fmt.Errorf("failed to update participant status: %w", err)
 	}
 
+	// Accepting an invitation gives the user their default reminders
+	if req.Status == models.ParticipantStatusAccepted && previousStatus != models.ParticipantStatusAccepted {
+		if event, err := u.eventRepo.GetEventByID(eventID); err == nil {
+			u.applyDefaultReminders(event, userID)
+		}
+	}
+
 	return nil
 }
 
@@ -496,17 +735,22 @@ func (u *calendarUsecase) SetReminder(userID, eventID uint, req *models.CreateRe
 		return nil, fmt.Errorf("access denied: insufficient permissions")
 	}
 
-	// Create reminder
-	reminder := &models.EventReminder{
-		EventID:       eventID,
-		UserID:        userID,
-		Type:          req.Type,
-		MinutesBefore: &req.MinutesBefore,
-		Message:       strings.TrimSpace(req.Message),
+	// Create reminder next to the ones the user already set
+	existing, err := u.reminderRepo.GetUserEventReminders(eventID, userID)
+	if err != nil {
+		return nil, err
 	}
-
-	// Calculate trigger time
-	reminder.TriggerTime = event.StartTime.Add(-time.Duration(req.MinutesBefore) * time.Minute)
+	reqs := make([]models.CreateReminderRequest, 0, len(existing)+1)
+	for _, reminder := range existing {
+		if reminder.MinutesBefore != nil {
+			reqs = append(reqs, models.CreateReminderRequest{Type: reminder.Type, MinutesBefore: *reminder.MinutesBefore})
+		}
+	}
+	reminders, err := buildEventReminders(event, userID, append(reqs, *req))
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	reminder := reminders[len(reminders)-1]
 
 	if err := u.reminderRepo.CreateReminder(reminder); err != nil {
 		return nil, fmt.Errorf("failed to create reminder: %w", err)
@@ -564,17 +808,10 @@ func (u *calendarUsecase) SearchEvents(userID uint, searchQuery string, filter *
 
 	// Set default pagination
 	if filter == nil {
-		filter = &models.EventFilterRequest{
-			Limit:  20,
-			Offset: 0,
-		}
-	}
-
-	if filter.Limit <= 0 {
-		filter.Limit = 20
+		filter = &models.EventFilterRequest{}
 	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
+	if filter.Page == nil {
+		filter.Page = query.Default(models.EventListOptions)
 	}
 
 	// Search events
@@ -590,11 +827,12 @@ func (u *calendarUsecase) SearchEvents(userID uint, searchQuery string, filter *
 	}
 
 	return &models.EventListResponse{
-		Events:  responses,
-		Total:   total,
-		Limit:   filter.Limit,
-		Offset:  filter.Offset,
-		Filters: filter,
+		Events:     responses,
+		Total:      total,
+		Limit:      filter.Page.Limit,
+		Offset:     filter.Page.Offset,
+		NextCursor: filter.Page.NextCursor(responses),
+		Filters:    filter,
 	}, nil
 }
 
@@ -610,26 +848,18 @@ func (u *calendarUsecase) CheckTimeConflict(userID uint, startTime, endTime time
 
 // Helper methods
 
-// hasEventAccess checks if user has access to the event
+// hasEventAccess checks if user may see details of the event: anyone for public events, otherwise
+// the creator, participants and users the organizer shared the details with
 func (u *calendarUsecase) hasEventAccess(userID uint, event *models.Event) bool {
-	// User is creator
-	if event.CreatedBy == userID {
+	if event.CreatedBy == userID || event.EffectiveVisibility() == models.EventVisibilityPublic {
 		return true
 	}
 
-	// Check if user is a participant (non-declined)
-	isParticipant, err := u.participantRepo.IsParticipant(event.ID, userID)
+	visible, err := u.eventRepo.GetEventsWithDetails(userID, []uint{event.ID})
 	if err != nil {
 		return false
 	}
-
-	// If event is private, only participants can access
-	if event.IsPrivate {
-		return isParticipant
-	}
-
-	// Public events can be viewed by anyone (for now)
-	return true
+	return visible[event.ID]
 }
 
 // Validation methods
@@ -640,21 +870,8 @@ func (u *calendarUsecase) validateCreateEventRequest(req *models.CreateEventRequ
 		return fmt.Errorf("request is required")
 	}
 
-	// Validate title
-	title := strings.TrimSpace(req.Title)
-	if title == "" {
-		return fmt.Errorf("event title is required")
-	}
-	if len(title) > 255 {
-		return fmt.Errorf("event title must be less than 255 characters")
-	}
-
-	// Validate description if provided
-	if req.Description != "" {
-		description := strings.TrimSpace(req.Description)
-		if len(description) > 2000 {
-			return fmt.Errorf("event description must be less than 2000 characters")
-		}
+	if err := validation.Struct(req); err != nil {
+		return err
 	}
 
 	// Validate time logic
@@ -662,33 +879,15 @@ func (u *calendarUsecase) validateCreateEventRequest(req *models.CreateEventRequ
 		return fmt.Errorf("end time must be after start time")
 	}
 
-	// Validate location if provided
-	if req.Location != "" {
-		location := strings.TrimSpace(req.Location)
-		if len(location) > 500 {
-			return fmt.Errorf("location must be less than 500 characters")
-		}
-	}
-
-	// Validate type if provided
-	if req.Type != "" {
-		if !u.isValidEventType(req.Type) {
-			return fmt.Errorf("invalid event type")
-		}
-	}
-
-	// Validate color if provided
-	if req.Color != "" {
-		if len(req.Color) != 7 || req.Color[0] != '#' {
-			return fmt.Errorf("color must be a valid hex color code (e.g., #3788d8)")
-		}
-	}
-
 	// Validate that start time is not in the past (except for all-day events)
 	if !req.AllDay && req.StartTime.Before(time.Now().Add(-5*time.Minute)) {
 		return fmt.Errorf("start time cannot be in the past")
 	}
 
+	if _, err := buildEventReminders(&models.Event{}, 0, req.Reminders); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -698,15 +897,8 @@ func (u *calendarUsecase) validateUpdateEventRequest(req *models.UpdateEventRequ
 		return fmt.Errorf("request is required")
 	}
 
-	// Validate title if provided
-	if req.Title != nil {
-		title := strings.TrimSpace(*req.Title)
-		if title == "" {
-			return fmt.Errorf("event title cannot be empty")
-		}
-		if len(title) > 255 {
-			return fmt.Errorf("event title must be less than 255 characters")
-		}
+	if err := validation.Struct(req); err != nil {
+		return err
 	}
 
 	// Validate time logic if both times are provided
@@ -724,18 +916,7 @@ func (u *calendarUsecase) validateAddParticipantsRequest(req *models.AddParticip
 	if req == nil {
 		return fmt.Errorf("request is required")
 	}
-
-	if len(req.UserIDs) == 0 {
-		return fmt.Errorf("at least one user ID is required")
-	}
-
-	for _, userID := range req.UserIDs {
-		if userID == 0 {
-			return fmt.Errorf("invalid user ID")
-		}
-	}
-
-	return nil
+	return validation.Struct(req)
 }
 
 // validateUpdateParticipantStatusRequest validates participant status update request
@@ -743,12 +924,7 @@ func (u *calendarUsecase) validateUpdateParticipantStatusRequest(req *models.Upd
 	if req == nil {
 		return fmt.Errorf("request is required")
 	}
-
-	if !u.isValidParticipantStatus(req.Status) {
-		return fmt.Errorf("invalid participant status")
-	}
-
-	return nil
+	return validation.Struct(req)
 }
 
 // validateCreateReminderRequest validates reminder creation request
@@ -756,52 +932,17 @@ func (u *calendarUsecase) validateCreateReminderRequest(req *models.CreateRemind
 	if req == nil {
 		return fmt.Errorf("request is required")
 	}
-
-	// Validate reminder type
-	if !u.isValidReminderType(req.Type) {
-		return fmt.Errorf("invalid reminder type")
-	}
-
-	// Validate minutes before
-	if req.MinutesBefore < 0 {
-		return fmt.Errorf("minutes before cannot be negative")
-	}
-	if req.MinutesBefore > 43200 { // 30 days
-		return fmt.Errorf("minutes before cannot exceed 30 days (43200 minutes)")
+	if err := validation.Struct(req); err != nil {
+		return err
 	}
-
-	return nil
+	_, err := req.Minutes()
+	return err
 }
 
-// Helper validation methods
-
-// isValidEventType checks if the event type is valid
-func (u *calendarUsecase) isValidEventType(eventType models.EventType) bool {
-	switch eventType {
-	case models.EventTypePersonal, models.EventTypeMeeting, models.EventTypeDeadline:
-		return true
-	default:
-		return false
-	}
-}
-
-// isValidParticipantStatus checks if the participant status is valid
-func (u *calendarUsecase) isValidParticipantStatus(status models.ParticipantStatus) bool {
-	switch status {
-	case models.ParticipantStatusPending, models.ParticipantStatusAccepted,
-		models.ParticipantStatusDeclined, models.ParticipantStatusMaybe:
-		return true
-	default:
-		return false
-	}
-}
-
-// isValidReminderType checks if the reminder type is valid
-func (u *calendarUsecase) isValidReminderType(reminderType models.ReminderType) bool {
-	switch reminderType {
-	case models.ReminderTypeEmail, models.ReminderTypeNotification, models.ReminderTypeSMS:
-		return true
-	default:
-		return false
+// validateSetRemindersRequest validates request for replacing reminders
+func (u *calendarUsecase) validateSetRemindersRequest(req *models.SetRemindersRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
 	}
+	return validation.Struct(req)
 }
@@ -0,0 +1,482 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+)
+
+// defaultCompanyEventLimit is the page size of company event lists when no limit is given
+const defaultCompanyEventLimit = 20
+
+// errCompanyEventReadOnly is returned when a published company event is changed through the calendar event API
+var errCompanyEventReadOnly = errors.New("access denied: company events can only be changed through company event publishing")
+
+// GetCompanyEvents returns company events of all statuses by filter, soonest first
+func (u *calendarUsecase) GetCompanyEvents(filter *models.CompanyEventFilterRequest) ([]*models.CompanyEvent, int64, error) {
+	if filter == nil {
+		filter = &models.CompanyEventFilterRequest{}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultCompanyEventLimit
+	}
+	return u.companyEventRepo.GetCompanyEvents(filter)
+}
+
+// GetCompanyEvent returns a company event
+func (u *calendarUsecase) GetCompanyEvent(companyEventID uint) (*models.CompanyEvent, error) {
+	return u.companyEventRepo.GetCompanyEventByID(companyEventID)
+}
+
+// CreateCompanyEvent drafts a company event, it reaches calendars only after review and publishing
+func (u *calendarUsecase) CreateCompanyEvent(userID uint, req *models.CreateCompanyEventRequest) (*models.CompanyEvent, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validateCompanyEventTime(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+
+	category := req.Category
+	if category == "" {
+		category = models.CompanyEventCategoryOther
+	}
+
+	companyEvent := &models.CompanyEvent{
+		Title:         strings.TrimSpace(req.Title),
+		Description:   strings.TrimSpace(req.Description),
+		Location:      strings.TrimSpace(req.Location),
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+		AllDay:        req.AllDay,
+		Category:      category,
+		DepartmentIDs: normalizeDepartmentIDs(req.DepartmentIDs),
+		Status:        models.CompanyEventStatusDraft,
+		CreatedBy:     userID,
+	}
+
+	if err := u.companyEventRepo.CreateCompanyEvent(companyEvent); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"company_event_id": companyEvent.ID,
+		"user_id":          userID,
+	}).Info("Company event drafted")
+
+	return companyEvent, nil
+}
+
+// UpdateCompanyEvent changes a company event. Authors change their drafts and events in review,
+// admins change any company event. Changes of a published event are propagated to the calendar
+// event of its audience, participants are notified and reminders follow a new time.
+func (u *calendarUsecase) UpdateCompanyEvent(userID uint, canPublish bool, companyEventID uint, req *models.UpdateCompanyEventRequest) (*models.CompanyEvent, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	companyEvent, err := u.getCompanyEventForChange(userID, canPublish, companyEventID)
+	if err != nil {
+		return nil, err
+	}
+	if companyEvent.Status == models.CompanyEventStatusPublished && !canPublish {
+		return nil, fmt.Errorf("access denied: only admins can change published company events")
+	}
+
+	previousStart, previousEnd := companyEvent.StartTime, companyEvent.EndTime
+	previousDepartments := companyEvent.DepartmentIDs
+
+	if req.Title != nil {
+		companyEvent.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		companyEvent.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Location != nil {
+		companyEvent.Location = strings.TrimSpace(*req.Location)
+	}
+	if req.StartTime != nil {
+		companyEvent.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		companyEvent.EndTime = *req.EndTime
+	}
+	if req.AllDay != nil {
+		companyEvent.AllDay = *req.AllDay
+	}
+	if req.Category != nil {
+		companyEvent.Category = *req.Category
+	}
+	if req.DepartmentIDs != nil {
+		companyEvent.DepartmentIDs = normalizeDepartmentIDs(*req.DepartmentIDs)
+	}
+
+	rescheduled := !companyEvent.StartTime.Equal(previousStart) || !companyEvent.EndTime.Equal(previousEnd)
+	if rescheduled {
+		if err := validateCompanyEventTime(companyEvent.StartTime, companyEvent.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	if companyEvent.Status != models.CompanyEventStatusPublished || companyEvent.EventID == nil {
+		if err := u.companyEventRepo.UpdateCompanyEvent(companyEvent); err != nil {
+			return nil, err
+		}
+		return companyEvent, nil
+	}
+
+	event, err := u.eventRepo.GetEventByID(*companyEvent.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get published event: %w", err)
+	}
+	applyCompanyEvent(event, companyEvent)
+
+	if err := u.companyEventRepo.UpdatePublishedCompanyEvent(companyEvent, event); err != nil {
+		return nil, err
+	}
+
+	if rescheduled {
+		u.shiftReminders(event)
+		u.notifyParticipants(event, userID, "medium", "notification.calendar_event_rescheduled_title", "notification.calendar_event_rescheduled_message", map[string]interface{}{
+			"EventTitle": event.Title,
+			"StartTime":  event.StartTime.UTC().Format("02.01.2006 15:04 MST"),
+			"Reason":     "",
+		})
+	} else {
+		u.notifyParticipants(event, userID, "medium", "notification.company_event_updated_title", "notification.company_event_updated_message", companyEventArgs(event))
+	}
+
+	if !slices.Equal(previousDepartments, companyEvent.DepartmentIDs) {
+		if _, err := u.syncCompanyEventAudience(companyEvent, event); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"company_event_id": companyEvent.ID,
+				"error":            err.Error(),
+			}).Warn("Failed to sync company event audience, it is retried by the sync job")
+		}
+	}
+
+	return companyEvent, nil
+}
+
+// DeleteCompanyEvent deletes a company event that was not published, published events are cancelled instead
+func (u *calendarUsecase) DeleteCompanyEvent(userID uint, canPublish bool, companyEventID uint) error {
+	companyEvent, err := u.getCompanyEventForChange(userID, canPublish, companyEventID)
+	if err != nil {
+		return err
+	}
+	if companyEvent.Status == models.CompanyEventStatusPublished {
+		return fmt.Errorf("cannot delete published company event, cancel it instead")
+	}
+
+	return u.companyEventRepo.DeleteCompanyEvent(companyEventID)
+}
+
+// SubmitCompanyEvent sends a draft company event for review by admins
+func (u *calendarUsecase) SubmitCompanyEvent(userID uint, canPublish bool, companyEventID uint) (*models.CompanyEvent, error) {
+	companyEvent, err := u.getCompanyEventForChange(userID, canPublish, companyEventID)
+	if err != nil {
+		return nil, err
+	}
+	if companyEvent.Status != models.CompanyEventStatusDraft {
+		return nil, fmt.Errorf("cannot submit company event in status %s", companyEvent.Status)
+	}
+
+	now := time.Now()
+	companyEvent.Status = models.CompanyEventStatusReview
+	companyEvent.SubmittedAt = &now
+	companyEvent.ReviewNote = ""
+
+	if err := u.companyEventRepo.UpdateCompanyEvent(companyEvent); err != nil {
+		return nil, err
+	}
+	return companyEvent, nil
+}
+
+// RejectCompanyEvent returns a company event in review to draft with a note for its author
+func (u *calendarUsecase) RejectCompanyEvent(userID, companyEventID uint, req *models.RejectCompanyEventRequest) (*models.CompanyEvent, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	companyEvent, err := u.companyEventRepo.GetCompanyEventByID(companyEventID)
+	if err != nil {
+		return nil, err
+	}
+	if companyEvent.Status != models.CompanyEventStatusReview {
+		return nil, fmt.Errorf("cannot reject company event in status %s", companyEvent.Status)
+	}
+
+	companyEvent.Status = models.CompanyEventStatusDraft
+	companyEvent.ReviewedBy = &userID
+	companyEvent.ReviewNote = strings.TrimSpace(req.Note)
+
+	if err := u.companyEventRepo.UpdateCompanyEvent(companyEvent); err != nil {
+		return nil, err
+	}
+	return companyEvent, nil
+}
+
+// PublishCompanyEvent approves a company event in review and fans it out as a read-only event
+// to the calendars of all active users or of the targeted departments. Users see it as
+// an invitation they can accept or decline.
+func (u *calendarUsecase) PublishCompanyEvent(userID, companyEventID uint) (*models.CompanyEvent, error) {
+	companyEvent, err := u.companyEventRepo.GetCompanyEventByID(companyEventID)
+	if err != nil {
+		return nil, err
+	}
+	if companyEvent.Status != models.CompanyEventStatusReview {
+		return nil, fmt.Errorf("cannot publish company event in status %s, it must be submitted for review first", companyEvent.Status)
+	}
+	if !companyEvent.EndTime.After(time.Now()) {
+		return nil, fmt.Errorf("cannot publish company event that has already ended")
+	}
+	if u.audience == nil {
+		return nil, fmt.Errorf("cannot publish company event: user service is not configured")
+	}
+
+	audience, err := u.audience.Audience(companyEvent.DepartmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve company event audience: %w", err)
+	}
+
+	event := &models.Event{
+		Type:           models.EventTypeMeeting,
+		CreatedBy:      userID,
+		CompanyEventID: &companyEvent.ID,
+	}
+	applyCompanyEvent(event, companyEvent)
+
+	now := time.Now()
+	companyEvent.Status = models.CompanyEventStatusPublished
+	companyEvent.ReviewedBy = &userID
+	companyEvent.PublishedBy = &userID
+	companyEvent.PublishedAt = &now
+
+	if err := u.companyEventRepo.PublishCompanyEvent(companyEvent, event, audience); err != nil {
+		return nil, fmt.Errorf("failed to publish company event: %w", err)
+	}
+
+	u.notifyParticipants(event, userID, "medium", "notification.company_event_published_title", "notification.company_event_published_message", companyEventArgs(event))
+
+	logger.WithFields(map[string]interface{}{
+		"company_event_id": companyEvent.ID,
+		"event_id":         event.ID,
+		"user_id":          userID,
+		"audience_size":    len(audience),
+	}).Info("Company event published")
+
+	return companyEvent, nil
+}
+
+// CancelCompanyEvent cancels a company event. A published event is cancelled in the calendars
+// of its audience, who are notified with the reason.
+func (u *calendarUsecase) CancelCompanyEvent(userID, companyEventID uint, req *models.CancelEventRequest) (*models.CompanyEvent, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	companyEvent, err := u.companyEventRepo.GetCompanyEventByID(companyEventID)
+	if err != nil {
+		return nil, err
+	}
+	if companyEvent.Status == models.CompanyEventStatusCancelled {
+		return nil, fmt.Errorf("company event is already cancelled")
+	}
+
+	now := time.Now()
+	published := companyEvent.Status == models.CompanyEventStatusPublished && companyEvent.EventID != nil
+	companyEvent.Status = models.CompanyEventStatusCancelled
+	companyEvent.CancelReason = strings.TrimSpace(req.Reason)
+	companyEvent.CancelledAt = &now
+	companyEvent.CancelledBy = &userID
+
+	if !published {
+		if err := u.companyEventRepo.UpdateCompanyEvent(companyEvent); err != nil {
+			return nil, err
+		}
+		return companyEvent, nil
+	}
+
+	event, err := u.eventRepo.GetEventByID(*companyEvent.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get published event: %w", err)
+	}
+	event.Status = models.EventStatusCancelled
+	event.CancelReason = companyEvent.CancelReason
+	event.CancelledAt = &now
+	event.CancelledBy = &userID
+
+	if err := u.companyEventRepo.UpdatePublishedCompanyEvent(companyEvent, event); err != nil {
+		return nil, err
+	}
+
+	u.notifyParticipants(event, userID, "high", "notification.calendar_event_cancelled_title", "notification.calendar_event_cancelled_message", map[string]interface{}{
+		"EventTitle": event.Title,
+		"Reason":     event.CancelReason,
+	})
+
+	return companyEvent, nil
+}
+
+// SyncCompanyEventAudiences updates participants of upcoming published company events, so users
+// who joined a targeted department get the event and users who left or were deactivated lose it.
+// It returns the number of added participants.
+func (u *calendarUsecase) SyncCompanyEventAudiences(now time.Time) (int, error) {
+	if u.audience == nil {
+		return 0, nil
+	}
+
+	companyEvents, err := u.companyEventRepo.GetUpcomingPublishedCompanyEvents(now)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, companyEvent := range companyEvents {
+		event, err := u.eventRepo.GetEventByID(*companyEvent.EventID)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"company_event_id": companyEvent.ID,
+				"error":            err.Error(),
+			}).Warn("Failed to get published event of company event")
+			continue
+		}
+
+		count, err := u.syncCompanyEventAudience(companyEvent, event)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"company_event_id": companyEvent.ID,
+				"error":            err.Error(),
+			}).Warn("Failed to sync company event audience")
+			continue
+		}
+		added += count
+	}
+
+	return added, nil
+}
+
+// syncCompanyEventAudience makes the current audience of a published company event the participants
+// of its calendar event and notifies added users. It returns the number of added participants.
+func (u *calendarUsecase) syncCompanyEventAudience(companyEvent *models.CompanyEvent, event *models.Event) (int, error) {
+	if u.audience == nil {
+		return 0, nil
+	}
+
+	audience, err := u.audience.Audience(companyEvent.DepartmentIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve company event audience: %w", err)
+	}
+
+	added, removed, err := u.companyEventRepo.SyncCompanyEventAudience(event.ID, audience)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(added) > 0 && u.notifier != nil {
+		args := companyEventArgs(event)
+		notification := &EventNotification{
+			EventID:  event.ID,
+			UserIDs:  added,
+			Title:    i18n.T(i18n.DefaultLocale, "notification.company_event_published_title", args),
+			Message:  i18n.T(i18n.DefaultLocale, "notification.company_event_published_message", args),
+			Priority: "medium",
+		}
+		if err := u.notifier.Notify(notification); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"event_id":   event.ID,
+				"user_count": len(added),
+				"error":      err.Error(),
+			}).Warn("Failed to notify new company event participants")
+		}
+	}
+
+	if len(added) > 0 || removed > 0 {
+		logger.WithFields(map[string]interface{}{
+			"company_event_id": companyEvent.ID,
+			"event_id":         event.ID,
+			"added_count":      len(added),
+			"removed_count":    removed,
+		}).Info("Company event audience synced")
+	}
+
+	return len(added), nil
+}
+
+// getCompanyEventForChange returns a company event that is not cancelled if the user is its author or an admin
+func (u *calendarUsecase) getCompanyEventForChange(userID uint, canPublish bool, companyEventID uint) (*models.CompanyEvent, error) {
+	companyEvent, err := u.companyEventRepo.GetCompanyEventByID(companyEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canPublish && companyEvent.CreatedBy != userID {
+		return nil, fmt.Errorf("access denied: only the author or admins can change the company event")
+	}
+	if !companyEvent.IsEditable() {
+		return nil, fmt.Errorf("cannot change cancelled company event")
+	}
+
+	return companyEvent, nil
+}
+
+// applyCompanyEvent copies what the audience sees of a company event to its calendar event
+func applyCompanyEvent(event *models.Event, companyEvent *models.CompanyEvent) {
+	event.Title = companyEvent.Title
+	event.Description = companyEvent.Description
+	event.Location = companyEvent.Location
+	event.StartTime = companyEvent.StartTime
+	event.EndTime = companyEvent.EndTime
+	event.AllDay = companyEvent.AllDay
+}
+
+// companyEventArgs returns template arguments of company event notifications
+func companyEventArgs(event *models.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"EventTitle": event.Title,
+		"StartTime":  event.StartTime.UTC().Format("02.01.2006 15:04 MST"),
+		"Location":   event.Location,
+	}
+}
+
+// validateCompanyEventTime checks that a company event ends after it starts and does not start in the past
+func validateCompanyEventTime(start, end time.Time) error {
+	if !end.After(start) {
+		return fmt.Errorf("validation failed: end time must be after start time")
+	}
+	if start.Before(time.Now().Add(-5 * time.Minute)) {
+		return fmt.Errorf("validation failed: start time cannot be in the past")
+	}
+	return nil
+}
+
+// normalizeDepartmentIDs sorts department IDs and drops duplicates, so changes of targeting are detected
+func normalizeDepartmentIDs(departmentIDs []uint) []uint {
+	result := make([]uint, 0, len(departmentIDs))
+	for _, id := range departmentIDs {
+		if id != 0 && !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
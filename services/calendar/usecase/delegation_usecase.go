@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+)
+
+// GetDelegations returns delegations the user granted to others and received from them
+func (u *calendarUsecase) GetDelegations(userID uint) (*models.DelegationListResponse, error) {
+	granted, err := u.delegationRepo.GetDelegationsByOwner(userID)
+	if err != nil {
+		return nil, err
+	}
+	received, err := u.delegationRepo.GetDelegationsByDelegate(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DelegationListResponse{
+		Granted:  granted,
+		Received: received,
+	}, nil
+}
+
+// GrantDelegation gives the delegate access to the user's calendar or changes access of an existing delegation
+func (u *calendarUsecase) GrantDelegation(userID, delegateID uint, req *models.GrantDelegationRequest) (*models.CalendarDelegation, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if delegateID == userID {
+		return nil, fmt.Errorf("validation failed: calendar cannot be delegated to its owner")
+	}
+	if err := u.userRefs.CheckUsers(delegateID); err != nil {
+		return nil, err
+	}
+
+	action := models.DelegationActionUpdated
+	delegation, err := u.delegationRepo.GetActiveDelegation(userID, delegateID)
+	if err != nil {
+		if !strings.HasSuffix(err.Error(), "not found") {
+			return nil, err
+		}
+		action = models.DelegationActionGranted
+		delegation = &models.CalendarDelegation{
+			OwnerID:    userID,
+			DelegateID: delegateID,
+		}
+	}
+
+	if action == models.DelegationActionUpdated && delegation.Permission == req.Permission {
+		return delegation, nil
+	}
+	previous := delegation.Permission
+	delegation.Permission = req.Permission
+
+	entry := delegationAuditEntry(delegation, userID, action, nil)
+	if previous != "" {
+		entry.Details = fmt.Sprintf("permission %s -> %s", previous, req.Permission)
+	} else {
+		entry.Details = fmt.Sprintf("permission %s", req.Permission)
+	}
+	if err := u.delegationRepo.SaveDelegation(delegation, entry); err != nil {
+		return nil, err
+	}
+
+	return delegation, nil
+}
+
+// RevokeDelegation takes access to the user's calendar away from the delegate
+func (u *calendarUsecase) RevokeDelegation(userID, delegateID uint) error {
+	delegation, err := u.delegationRepo.GetActiveDelegation(userID, delegateID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	delegation.RevokedAt = &now
+	delegation.RevokedBy = &userID
+
+	return u.delegationRepo.SaveDelegation(delegation, delegationAuditEntry(delegation, userID, models.DelegationActionRevoked, nil))
+}
+
+// GetDelegationAudit returns changes of delegations the user granted or received and actions delegates took
+func (u *calendarUsecase) GetDelegationAudit(userID uint, req *models.DelegationAuditRequest) (*models.DelegationAuditListResponse, error) {
+	if req == nil {
+		req = &models.DelegationAuditRequest{}
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	entries, total, err := u.delegationRepo.GetAuditLog(userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DelegationAuditListResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	}, nil
+}
+
+// GetDelegatedCalendar returns the owner's calendar as the delegate sees it. Private events
+// the delegate doesn't take part in show as busy blocks.
+func (u *calendarUsecase) GetDelegatedCalendar(userID, ownerID uint, startDate, endDate time.Time) (*models.EventListResponse, error) {
+	if _, err := u.getDelegation(userID, ownerID, models.DelegationPermissionView); err != nil {
+		return nil, err
+	}
+
+	calendar, err := u.GetUserCalendar(ownerID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var private []uint
+	for _, event := range calendar.Events {
+		if event.Visibility == models.EventVisibilityPrivate {
+			private = append(private, event.ID)
+		}
+	}
+	visible, err := u.eventRepo.GetEventsWithDetails(userID, private)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, event := range calendar.Events {
+		if event.Visibility == models.EventVisibilityPrivate && !visible[event.ID] {
+			calendar.Events[i] = busyEventResponse(event)
+		}
+	}
+	return calendar, nil
+}
+
+// CreateEventOnBehalf creates an event organized by the owner, the delegate is kept on the event
+func (u *calendarUsecase) CreateEventOnBehalf(userID, ownerID uint, req *models.CreateEventRequest) (*models.EventResponse, error) {
+	delegation, err := u.getDelegation(userID, ownerID, models.DelegationPermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := u.createEvent(ownerID, &userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	u.recordDelegateAction(delegation, models.DelegationActionEventCreated, response.ID, "")
+
+	event := &models.Event{Title: response.Title, StartTime: response.StartTime}
+	event.ID = response.ID
+	args := delegationNotificationArgs(event)
+	u.notifyParticipants(event, ownerID, "medium", "notification.calendar_delegate_invite_title", "notification.calendar_delegate_invite_message", args)
+	u.notifyDelegationOwner(delegation, event.ID, "notification.calendar_delegate_created_title", "notification.calendar_delegate_created_message", args)
+
+	return response, nil
+}
+
+// RespondOnBehalf answers an invitation of the owner, the delegate is kept on the participation
+func (u *calendarUsecase) RespondOnBehalf(userID, ownerID, eventID uint, req *models.UpdateParticipantStatusRequest) error {
+	delegation, err := u.getDelegation(userID, ownerID, models.DelegationPermissionManage)
+	if err != nil {
+		return err
+	}
+
+	if err := u.updateParticipantStatus(ownerID, eventID, &userID, req); err != nil {
+		return err
+	}
+
+	u.recordDelegateAction(delegation, models.DelegationActionEventResponded, eventID, fmt.Sprintf("status %s", req.Status))
+
+	if event, err := u.eventRepo.GetEventByID(eventID); err == nil {
+		args := delegationNotificationArgs(event)
+		args["Status"] = req.Status
+		u.notifyDelegationOwner(delegation, eventID, "notification.calendar_delegate_replied_title", "notification.calendar_delegate_replied_message", args)
+	}
+
+	return nil
+}
+
+// getDelegation returns the active delegation of the owner's calendar to the user if it grants permission
+func (u *calendarUsecase) getDelegation(userID, ownerID uint, permission models.DelegationPermission) (*models.CalendarDelegation, error) {
+	delegation, err := u.delegationRepo.GetActiveDelegation(ownerID, userID)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			return nil, fmt.Errorf("access denied: calendar of user %d is not delegated to you", ownerID)
+		}
+		return nil, err
+	}
+	if !delegation.Permission.Allows(permission) {
+		return nil, fmt.Errorf("access denied: delegation doesn't allow to %s the calendar", permission)
+	}
+	return delegation, nil
+}
+
+// recordDelegateAction adds an action of the delegate to the audit log. Failures are logged, not
+// returned, since the action is already done.
+func (u *calendarUsecase) recordDelegateAction(delegation *models.CalendarDelegation, action models.DelegationAction, eventID uint, details string) {
+	entry := delegationAuditEntry(delegation, delegation.DelegateID, action, &eventID)
+	entry.Details = details
+
+	if err := u.delegationRepo.CreateAuditEntry(entry); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"delegation_id": delegation.ID,
+			"event_id":      eventID,
+			"action":        action,
+			"error":         err.Error(),
+		}).Error("Failed to record delegate action")
+	}
+}
+
+// notifyDelegationOwner tells the owner about an action the delegate took on their behalf
+func (u *calendarUsecase) notifyDelegationOwner(delegation *models.CalendarDelegation, eventID uint, titleKey, messageKey string, args map[string]interface{}) {
+	if u.notifier == nil {
+		return
+	}
+
+	notification := &EventNotification{
+		EventID:  eventID,
+		UserIDs:  []uint{delegation.OwnerID},
+		Title:    i18n.T(i18n.DefaultLocale, titleKey, args),
+		Message:  i18n.T(i18n.DefaultLocale, messageKey, args),
+		Priority: "low",
+	}
+
+	if err := u.notifier.Notify(notification); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"event_id": eventID,
+			"owner_id": delegation.OwnerID,
+			"error":    err.Error(),
+		}).Warn("Failed to notify calendar owner about delegate action")
+	}
+}
+
+// delegationAuditEntry builds an audit entry of a delegation
+func delegationAuditEntry(delegation *models.CalendarDelegation, actorID uint, action models.DelegationAction, eventID *uint) *models.CalendarDelegationAudit {
+	return &models.CalendarDelegationAudit{
+		DelegationID: delegation.ID,
+		OwnerID:      delegation.OwnerID,
+		DelegateID:   delegation.DelegateID,
+		ActorID:      actorID,
+		Action:       action,
+		EventID:      eventID,
+	}
+}
+
+// delegationNotificationArgs returns template arguments of notifications about delegate actions
+func delegationNotificationArgs(event *models.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"EventTitle": event.Title,
+		"StartTime":  event.StartTime.UTC().Format("02.01.2006 15:04 MST"),
+	}
+}
+
+// busyEventResponse hides details of an event response, leaving only the time it takes
+func busyEventResponse(event *models.EventResponse) *models.EventResponse {
+	return &models.EventResponse{
+		ID:         event.ID,
+		Title:      models.BusyTitle,
+		StartTime:  event.StartTime,
+		EndTime:    event.EndTime,
+		AllDay:     event.AllDay,
+		Type:       event.Type,
+		CreatedBy:  event.CreatedBy,
+		Status:     event.Status,
+		Color:      event.Color,
+		Visibility: event.Visibility,
+		Busy:       true,
+		CreatedAt:  event.CreatedAt,
+		UpdatedAt:  event.UpdatedAt,
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// HandleDepartmentEvent keeps calendar data in line with a department change published by the user
+// service. Absences and company events of a merged department move to the department it was merged
+// into. Other changes need nothing, calendars refer to departments by ID.
+func (u *calendarUsecase) HandleDepartmentEvent(event *sharedmodels.DepartmentEvent) error {
+	if event.Type != sharedmodels.DepartmentEventMerged {
+		return nil
+	}
+	if event.DepartmentID == nil || event.PreviousDepartmentID == nil {
+		return fmt.Errorf("validation failed: department_id and previous_department_id are required")
+	}
+
+	moved, retargeted, err := u.eventRepo.MergeDepartments(*event.DepartmentID, *event.PreviousDepartmentID)
+	if err != nil {
+		return fmt.Errorf("failed to merge department calendar data: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"department_id":          *event.DepartmentID,
+		"previous_department_id": *event.PreviousDepartmentID,
+		"moved_absences":         moved,
+		"company_events":         len(retargeted),
+	}).Info("Department calendar data merged")
+
+	now := time.Now()
+	for _, companyEvent := range retargeted {
+		if companyEvent.Status == models.CompanyEventStatusPublished && companyEvent.EventID != nil &&
+			companyEvent.EndTime.After(now) {
+			u.resyncCompanyEventAudience(companyEvent)
+		}
+		if companyEvent.IsEditable() {
+			u.notifyCompanyEventRetargeted(companyEvent, event)
+		}
+	}
+
+	return nil
+}
+
+// resyncCompanyEventAudience brings the audience of a retargeted published company event in line with
+// its departments right away instead of waiting for the audience sync job
+func (u *calendarUsecase) resyncCompanyEventAudience(companyEvent *models.CompanyEvent) {
+	event, err := u.eventRepo.GetEventByID(*companyEvent.EventID)
+	if err == nil {
+		_, err = u.syncCompanyEventAudience(companyEvent, event)
+	}
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"company_event_id": companyEvent.ID,
+			"error":            err.Error(),
+		}).Warn("Failed to sync audience of retargeted company event")
+	}
+}
+
+// notifyCompanyEventRetargeted tells the author of a company event that it now targets the department
+// its department was merged into
+func (u *calendarUsecase) notifyCompanyEventRetargeted(companyEvent *models.CompanyEvent, event *sharedmodels.DepartmentEvent) {
+	if u.notifier == nil {
+		return
+	}
+
+	var eventID uint
+	if companyEvent.EventID != nil {
+		eventID = *companyEvent.EventID
+	}
+	args := map[string]interface{}{
+		"EventTitle":         companyEvent.Title,
+		"Department":         event.DepartmentName,
+		"PreviousDepartment": event.PreviousDepartmentName,
+	}
+	notification := &EventNotification{
+		EventID:  eventID,
+		UserIDs:  []uint{companyEvent.CreatedBy},
+		Title:    i18n.T(i18n.DefaultLocale, "notification.department_merged_title", args),
+		Message:  i18n.T(i18n.DefaultLocale, "notification.company_event_retargeted_message", args),
+		Priority: "low",
+	}
+	if err := u.notifier.Notify(notification); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"company_event_id": companyEvent.ID,
+			"error":            err.Error(),
+		}).Warn("Failed to notify about retargeted company event")
+	}
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// GetDigestStats counts events of a user today and tomorrow for the daily digest of the
+// notification service. Days start at DayStart, so they follow the user's timezone; event times
+// are returned in it too.
+func (u *calendarUsecase) GetDigestStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.CalendarDigestStats, error) {
+	if req.UserID == 0 {
+		return nil, fmt.Errorf("validation failed: user_id is required")
+	}
+
+	tomorrow := req.DayStart.AddDate(0, 0, 1)
+	events, err := u.eventRepo.GetEventsByDateRange(req.UserID, req.DayStart, req.DayStart.AddDate(0, 0, 2).Add(-time.Nanosecond))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	now := time.Now()
+	stats := &sharedmodels.CalendarDigestStats{}
+	for _, event := range events {
+		if event.Status == models.EventStatusCancelled {
+			continue
+		}
+
+		if event.StartTime.Before(tomorrow) {
+			stats.TodayEvents++
+		} else {
+			stats.TomorrowEvents++
+		}
+
+		// Events are sorted by start time
+		if stats.NextEvent == nil && !event.StartTime.Before(now) {
+			stats.NextEvent = &sharedmodels.DigestEvent{
+				Title:     event.Title,
+				StartTime: event.StartTime.In(req.DayStart.Location()),
+			}
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"gorm.io/gorm"
+)
+
+// SetEventEscalation configures RSVP reminder escalation of an event. Only the event creator can configure it.
+func (u *calendarUsecase) SetEventEscalation(userID, eventID uint, req *models.SetEscalationRequest) (*models.EscalationResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := u.getOrganizedEvent(userID, eventID); err != nil {
+		return nil, err
+	}
+
+	rules := models.BuildEscalationChain(eventID, req.HoursBefore, req.Priority)
+	if err := u.escalationRepo.ReplaceRules(eventID, rules); err != nil {
+		return nil, fmt.Errorf("failed to save escalation rules: %w", err)
+	}
+
+	return buildEscalationResponse(eventID, rules), nil
+}
+
+// GetEventEscalation returns RSVP reminder escalation settings of an event
+func (u *calendarUsecase) GetEventEscalation(userID, eventID uint) (*models.EscalationResponse, error) {
+	if _, err := u.getOrganizedEvent(userID, eventID); err != nil {
+		return nil, err
+	}
+
+	rules, err := u.escalationRepo.GetRules(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation rules: %w", err)
+	}
+
+	return buildEscalationResponse(eventID, rules), nil
+}
+
+// RemoveEventEscalation disables RSVP reminder escalation of an event
+func (u *calendarUsecase) RemoveEventEscalation(userID, eventID uint) error {
+	if _, err := u.getOrganizedEvent(userID, eventID); err != nil {
+		return err
+	}
+
+	if err := u.escalationRepo.DeleteRules(eventID); err != nil {
+		return fmt.Errorf("failed to delete escalation rules: %w", err)
+	}
+
+	return nil
+}
+
+// ProcessReminderEscalations reminds participants who have not responded to invitations
+// through the latest due step of each event's chain and returns the number of reminded users.
+// Earlier steps that became due at the same time are recorded as sent, so a late configuration
+// or processing delay does not send the whole chain at once.
+func (u *calendarUsecase) ProcessReminderEscalations(now time.Time) (int, error) {
+	if u.notifier == nil {
+		return 0, nil
+	}
+
+	rules, err := u.escalationRepo.GetDueRules(now)
+	if err != nil {
+		return 0, err
+	}
+
+	// Group due rules by event, rules are ordered by event and step
+	var groups [][]*models.EventEscalationRule
+	for i, rule := range rules {
+		if i == 0 || rules[i-1].EventID != rule.EventID {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], rule)
+	}
+
+	reminded := 0
+	for _, group := range groups {
+		latest := group[len(group)-1]
+
+		userIDs, err := u.escalationRepo.GetUnrespondedParticipants(latest.EventID, latest.ID)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"event_id": latest.EventID,
+				"rule_id":  latest.ID,
+				"error":    err.Error(),
+			}).Error("Failed to get participants for RSVP reminder")
+			continue
+		}
+		if len(userIDs) == 0 {
+			continue
+		}
+
+		if err := u.notifier.Notify(buildRSVPReminder(latest, userIDs)); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"event_id":   latest.EventID,
+				"rule_id":    latest.ID,
+				"user_count": len(userIDs),
+				"error":      err.Error(),
+			}).Error("Failed to send RSVP reminder")
+			continue
+		}
+
+		var deliveries []*models.EventEscalationDelivery
+		for _, rule := range group {
+			for _, id := range userIDs {
+				deliveries = append(deliveries, &models.EventEscalationDelivery{
+					RuleID:  rule.ID,
+					UserID:  id,
+					EventID: rule.EventID,
+					SentAt:  now,
+				})
+			}
+		}
+		if err := u.escalationRepo.RecordDeliveries(deliveries); err != nil {
+			return reminded, err
+		}
+
+		reminded += len(userIDs)
+	}
+
+	return reminded, nil
+}
+
+// getOrganizedEvent returns an event if the user is its creator
+func (u *calendarUsecase) getOrganizedEvent(userID, eventID uint) (*models.Event, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if event.CreatedBy != userID {
+		return nil, fmt.Errorf("access denied: only event creator can configure the event")
+	}
+	if event.IsCompanyEvent() {
+		return nil, errCompanyEventReadOnly
+	}
+
+	return event, nil
+}
+
+// buildEscalationResponse builds escalation settings from the rules of an event
+func buildEscalationResponse(eventID uint, rules []*models.EventEscalationRule) *models.EscalationResponse {
+	response := &models.EscalationResponse{
+		EventID: eventID,
+		Enabled: len(rules) > 0,
+		Rules:   rules,
+	}
+
+	if len(rules) > 0 {
+		response.HoursBefore = rules[0].MinutesBefore / 60
+		response.Priority = rules[0].Priority
+	}
+	if response.Rules == nil {
+		response.Rules = []*models.EventEscalationRule{}
+	}
+
+	return response
+}
+
+// buildRSVPReminder builds the reminder sent by an escalation step
+func buildRSVPReminder(rule *models.EventEscalationRule, userIDs []uint) *EventNotification {
+	args := map[string]interface{}{}
+	if rule.Event != nil {
+		args["EventTitle"] = rule.Event.Title
+		args["StartTime"] = rule.Event.StartTime.UTC().Format("02.01.2006 15:04 MST")
+	}
+
+	channels := []string{"in_app"}
+	switch rule.Channel {
+	case models.ReminderTypeEmail:
+		channels = append(channels, "email")
+	case models.ReminderTypeSMS:
+		channels = append(channels, "sms")
+	}
+
+	return &EventNotification{
+		EventID:  rule.EventID,
+		UserIDs:  userIDs,
+		Title:    i18n.T(i18n.DefaultLocale, "notification.calendar_rsvp_reminder_title", args),
+		Message:  i18n.T(i18n.DefaultLocale, "notification.calendar_rsvp_reminder_message", args),
+		Channels: channels,
+		Priority: string(rule.Priority),
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+)
+
+const (
+	// feedPastWindow and feedFutureWindow limit events published in calendar feeds
+	feedPastWindow   = 30 * 24 * time.Hour
+	feedFutureWindow = 365 * 24 * time.Hour
+)
+
+// GetCalendarFeed returns the calendar feed of a user, creating it on first request.
+// The token is only returned when the feed is created.
+func (u *calendarUsecase) GetCalendarFeed(userID uint) (*models.CalendarFeedResponse, error) {
+	feed, token, err := u.getOrCreateFeed(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := feed.ToResponse()
+	response.Token = token
+	return response, nil
+}
+
+// UpdateCalendarFeed changes calendar feed settings of a user
+func (u *calendarUsecase) UpdateCalendarFeed(userID uint, req *models.UpdateCalendarFeedRequest) (*models.CalendarFeedResponse, error) {
+	feed, token, err := u.getOrCreateFeed(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BusyOnly != nil {
+		feed.BusyOnly = *req.BusyOnly
+	}
+
+	if err := u.feedRepo.UpdateFeed(feed); err != nil {
+		return nil, fmt.Errorf("failed to update calendar feed: %w", err)
+	}
+
+	response := feed.ToResponse()
+	response.Token = token
+	return response, nil
+}
+
+// RegenerateCalendarFeedToken replaces the feed token, so the previous feed URL stops working.
+// The new token is returned once, only its hash is stored.
+func (u *calendarUsecase) RegenerateCalendarFeedToken(userID uint) (*models.CalendarFeedResponse, error) {
+	feed, _, err := u.getOrCreateFeed(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	feed.TokenHash = hashFeedToken(token)
+	feed.LastAccessedAt = nil
+
+	if err := u.feedRepo.UpdateFeed(feed); err != nil {
+		return nil, fmt.Errorf("failed to update calendar feed: %w", err)
+	}
+
+	response := feed.ToResponse()
+	response.Token = token
+	return response, nil
+}
+
+// RenderCalendarFeed renders the iCalendar feed identified by its secret token
+func (u *calendarUsecase) RenderCalendarFeed(token string) (*models.CalendarFeedContent, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("calendar feed not found")
+	}
+
+	feed, err := u.feedRepo.GetFeedByTokenHash(hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events, err := u.eventRepo.GetEventsByDateRange(feed.UserID, now.Add(-feedPastWindow), now.Add(feedFutureWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed events: %w", err)
+	}
+
+	data := renderICalendar(events, feed.BusyOnly)
+	sum := sha256.Sum256(data)
+
+	// Access time is informational, failing to record it must not break the feed
+	_ = u.feedRepo.TouchFeed(feed.ID, now)
+
+	return &models.CalendarFeedContent{
+		Data:         data,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: latestUpdate(feed, events),
+	}, nil
+}
+
+// getOrCreateFeed returns the calendar feed of a user, creating it if it does not exist.
+// The token is only returned for a created feed, an existing feed keeps just its hash.
+func (u *calendarUsecase) getOrCreateFeed(userID uint) (*models.CalendarFeed, string, error) {
+	feed, err := u.feedRepo.GetFeedByUserID(userID)
+	if err == nil {
+		return feed, "", nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, "", err
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	feed = &models.CalendarFeed{
+		UserID:    userID,
+		TokenHash: hashFeedToken(token),
+	}
+	if err := u.feedRepo.CreateFeed(feed); err != nil {
+		return nil, "", fmt.Errorf("failed to create calendar feed: %w", err)
+	}
+
+	return feed, token, nil
+}
+
+// generateFeedToken generates a random calendar feed token
+func generateFeedToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// hashFeedToken returns the stored hash of a calendar feed token
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
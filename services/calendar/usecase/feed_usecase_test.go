@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"testing"
+
+	"tachyon-messenger/services/calendar/repository/repotest"
+)
+
+func TestCalendarFeedToken(t *testing.T) {
+	repos := repotest.New(t)
+	uc := &calendarUsecase{eventRepo: repos.Events, feedRepo: repos.Feeds}
+
+	// The token is shown once, when the feed is created
+	created, err := uc.GetCalendarFeed(1)
+	if err != nil {
+		t.Fatalf("GetCalendarFeed failed: %v", err)
+	}
+	if created.Token == "" {
+		t.Fatal("expected the token of a created feed")
+	}
+	again, err := uc.GetCalendarFeed(1)
+	if err != nil {
+		t.Fatalf("GetCalendarFeed failed: %v", err)
+	}
+	if again.Token != "" {
+		t.Errorf("expected no token for an existing feed, got %q", again.Token)
+	}
+
+	// Only the hash is stored and the feed is found by the token, not by its hash
+	feed, err := repos.Feeds.GetFeedByUserID(1)
+	if err != nil {
+		t.Fatalf("GetFeedByUserID failed: %v", err)
+	}
+	if feed.TokenHash == created.Token || feed.TokenHash != hashFeedToken(created.Token) {
+		t.Errorf("stored %q, want the hash of the token", feed.TokenHash)
+	}
+	if _, err := uc.RenderCalendarFeed(created.Token); err != nil {
+		t.Errorf("RenderCalendarFeed failed: %v", err)
+	}
+	if _, err := uc.RenderCalendarFeed(feed.TokenHash); err == nil {
+		t.Error("expected the stored hash not to open the feed")
+	}
+
+	// A regenerated token replaces the previous one
+	regenerated, err := uc.RegenerateCalendarFeedToken(1)
+	if err != nil {
+		t.Fatalf("RegenerateCalendarFeedToken failed: %v", err)
+	}
+	if regenerated.Token == "" || regenerated.Token == created.Token {
+		t.Fatalf("expected a new token, got %q", regenerated.Token)
+	}
+	if _, err := uc.RenderCalendarFeed(created.Token); err == nil {
+		t.Error("expected the previous token to stop working")
+	}
+	if _, err := uc.RenderCalendarFeed(regenerated.Token); err != nil {
+		t.Errorf("RenderCalendarFeed failed with the new token: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+)
+
+const (
+	icalDateTimeFormat = "20060102T150405Z"
+	icalDateFormat     = "20060102"
+
+	// icalLineLimit is the maximum line length in octets before folding (RFC 5545, 3.1)
+	icalLineLimit = 75
+
+	// feedRefreshInterval is how often subscribed clients are asked to refresh the feed
+	feedRefreshInterval = "PT15M"
+)
+
+// icalWriter builds an iCalendar document with CRLF line endings and folded lines
+type icalWriter struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it at icalLineLimit octets without splitting UTF-8 characters
+func (w *icalWriter) line(name, value string) {
+	content := name + ":" + value
+
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > icalLineLimit {
+			w.buf.WriteString("\r\n ")
+			width = 1
+		}
+		w.buf.WriteRune(r)
+		width += size
+	}
+	w.buf.WriteString("\r\n")
+}
+
+// text writes a content line with an escaped TEXT value, skipping empty values
+func (w *icalWriter) text(name, value string) {
+	if value == "" {
+		return
+	}
+	w.line(name, escapeICalText(value))
+}
+
+// escapeICalText escapes a TEXT property value (RFC 5545, 3.3.11)
+func escapeICalText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// renderICalendar renders events as an iCalendar feed. In busy-only mode events carry
+// only their time, so subscribers see when the user is busy but not what the events are.
+// Subscribers don't take part in the events, so busy-only and private events always carry only their time.
+func renderICalendar(events []*models.Event, busyOnly bool) []byte {
+	w := &icalWriter{}
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//Tachyon Messenger//Calendar//EN")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", "Tachyon Calendar")
+	w.line("X-PUBLISHED-TTL", feedRefreshInterval)
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", feedRefreshInterval)
+
+	for _, event := range events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", fmt.Sprintf("event-%d@tachyon-messenger", event.ID))
+		w.line("DTSTAMP", event.UpdatedAt.UTC().Format(icalDateTimeFormat))
+		w.line("LAST-MODIFIED", event.UpdatedAt.UTC().Format(icalDateTimeFormat))
+		w.line("SEQUENCE", fmt.Sprintf("%d", event.Version))
+
+		if event.AllDay {
+			start := event.StartTime.UTC()
+			end := event.EndTime.UTC()
+			if end.Before(start) {
+				end = start
+			}
+			// All-day DTEND is exclusive
+			w.line("DTSTART;VALUE=DATE", start.Format(icalDateFormat))
+			w.line("DTEND;VALUE=DATE", end.AddDate(0, 0, 1).Format(icalDateFormat))
+		} else {
+			w.line("DTSTART", event.StartTime.UTC().Format(icalDateTimeFormat))
+			w.line("DTEND", event.EndTime.UTC().Format(icalDateTimeFormat))
+		}
+
+		if busyOnly || event.EffectiveVisibility() != models.EventVisibilityPublic {
+			w.text("SUMMARY", models.BusyTitle)
+			w.line("CLASS", "PRIVATE")
+		} else {
+			w.text("SUMMARY", event.Title)
+			w.text("DESCRIPTION", event.Description)
+			w.text("LOCATION", event.Location)
+			w.line("CLASS", "PUBLIC")
+		}
+
+		switch {
+		case event.Status == models.EventStatusCancelled:
+			w.line("STATUS", "CANCELLED")
+		case event.UserStatus == models.ParticipantStatusPending, event.UserStatus == models.ParticipantStatusMaybe:
+			w.line("STATUS", "TENTATIVE")
+		default:
+			w.line("STATUS", "CONFIRMED")
+		}
+		w.line("TRANSP", "OPAQUE")
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+
+	return w.buf.Bytes()
+}
+
+// latestUpdate returns the most recent modification time of the feed and its events
+func latestUpdate(feed *models.CalendarFeed, events []*models.Event) time.Time {
+	latest := feed.UpdatedAt
+	for _, event := range events {
+		if event.UpdatedAt.After(latest) {
+			latest = event.UpdatedAt
+		}
+	}
+	return latest.UTC()
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/undo"
+	"tachyon-messenger/shared/validation"
+
+	"gorm.io/gorm"
+)
+
+// ActionCancelEvent is the undoable action kind of event cancellation
+const ActionCancelEvent = "cancel_event"
+
+// CancelEvent cancels an event keeping its record. Participants are notified with the reason
+// once the cancellation can no longer be undone with the returned receipt.
+func (u *calendarUsecase) CancelEvent(userID, eventID uint, req *models.CancelEventRequest) (*models.EventResponse, *undo.Receipt, error) {
+	if req == nil {
+		return nil, nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	event, err := u.getEventForChange(userID, eventID, "cancel")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previousStatus := event.Status
+	now := time.Now()
+	event.Status = models.EventStatusCancelled
+	event.CancelReason = strings.TrimSpace(req.Reason)
+	event.CancelledAt = &now
+	event.CancelledBy = &userID
+
+	if err := u.eventRepo.UpdateEvent(event); err != nil {
+		return nil, nil, fmt.Errorf("failed to cancel event: %w", err)
+	}
+
+	action, err := u.undo.Stage(userID, ActionCancelEvent, eventID, map[string]interface{}{
+		"previous_status": string(previousStatus),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to cancel event: %w", err)
+	}
+
+	response, err := u.GetEventByID(userID, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if action == nil {
+		return response, nil, nil
+	}
+	return response, action.Receipt(), nil
+}
+
+// commitEventCancellation notifies participants of a cancellation whose undo window elapsed
+func (u *calendarUsecase) commitEventCancellation(action *undo.PendingAction) error {
+	event, err := u.eventRepo.GetEventByID(action.ResourceID)
+	if err != nil {
+		// Deleted meanwhile, there is nothing to announce
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+	if event.Status != models.EventStatusCancelled {
+		return nil
+	}
+
+	u.notifyParticipants(event, action.UserID, "high", "notification.calendar_event_cancelled_title", "notification.calendar_event_cancelled_message", map[string]interface{}{
+		"EventTitle": event.Title,
+		"Reason":     event.CancelReason,
+	})
+	return nil
+}
+
+// revertEventCancellation puts a cancelled event back to its previous status
+func (u *calendarUsecase) revertEventCancellation(action *undo.PendingAction) error {
+	event, err := u.eventRepo.GetEventByID(action.ResourceID)
+	if err != nil {
+		return err
+	}
+	if event.Status != models.EventStatusCancelled {
+		return fmt.Errorf("event is not cancelled")
+	}
+
+	status, _ := action.Payload["previous_status"].(string)
+	if status == "" {
+		status = string(models.EventStatusActive)
+	}
+	event.Status = models.EventStatus(status)
+	event.CancelReason = ""
+	event.CancelledAt = nil
+	event.CancelledBy = nil
+
+	return u.eventRepo.UpdateEvent(event)
+}
+
+// RescheduleEvent moves an event to a new time, records the change and notifies participants
+func (u *calendarUsecase) RescheduleEvent(userID, eventID uint, req *models.RescheduleEventRequest) (*models.EventResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if !req.EndTime.After(req.StartTime) {
+		return nil, fmt.Errorf("validation failed: end time must be after start time")
+	}
+	if req.StartTime.Before(time.Now().Add(-5 * time.Minute)) {
+		return nil, fmt.Errorf("validation failed: start time cannot be in the past")
+	}
+
+	event, err := u.getEventForChange(userID, eventID, "reschedule")
+	if err != nil {
+		return nil, err
+	}
+
+	if event.StartTime.Equal(req.StartTime) && event.EndTime.Equal(req.EndTime) {
+		return nil, fmt.Errorf("validation failed: event is already scheduled at this time")
+	}
+
+	hasConflict, err := u.eventRepo.CheckTimeConflict(userID, req.StartTime, req.EndTime, &eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check time conflict: %w", err)
+	}
+	if hasConflict {
+		return nil, fmt.Errorf("time conflict detected: you have another event scheduled at this time")
+	}
+
+	// Changes based on a stale version are rejected on save
+	if req.Version != nil {
+		event.Version = *req.Version
+	}
+
+	if err := u.saveReschedule(userID, event, req.StartTime, req.EndTime, req.Reason); err != nil {
+		return nil, err
+	}
+
+	return u.GetEventByID(userID, eventID)
+}
+
+// saveReschedule moves an event to a new time recording the change, then shifts its reminders
+// and notifies participants
+func (u *calendarUsecase) saveReschedule(userID uint, event *models.Event, startTime, endTime time.Time, reason string) error {
+	reschedule := &models.EventReschedule{
+		ChangedBy:    userID,
+		OldStartTime: event.StartTime,
+		OldEndTime:   event.EndTime,
+		NewStartTime: startTime,
+		NewEndTime:   endTime,
+		Reason:       strings.TrimSpace(reason),
+	}
+
+	event.StartTime = startTime
+	event.EndTime = endTime
+
+	if err := u.eventRepo.RescheduleEvent(event, reschedule); err != nil {
+		return fmt.Errorf("failed to reschedule event: %w", err)
+	}
+
+	u.shiftReminders(event)
+
+	u.notifyParticipants(event, userID, "medium", "notification.calendar_event_rescheduled_title", "notification.calendar_event_rescheduled_message", map[string]interface{}{
+		"EventTitle": event.Title,
+		"StartTime":  event.StartTime.UTC().Format("02.01.2006 15:04 MST"),
+		"Reason":     reschedule.Reason,
+	})
+	return nil
+}
+
+// GetEventReschedules returns reschedule history of an event, newest first
+func (u *calendarUsecase) GetEventReschedules(userID, eventID uint) ([]*models.EventReschedule, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if !u.hasEventAccess(userID, event) {
+		return nil, fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	return u.eventRepo.GetReschedules(eventID)
+}
+
+// getEventForChange returns an active event if the user is its creator
+func (u *calendarUsecase) getEventForChange(userID, eventID uint, action string) (*models.Event, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if event.CreatedBy != userID {
+		return nil, fmt.Errorf("access denied: only event creator can %s the event", action)
+	}
+	if event.IsCompanyEvent() {
+		return nil, errCompanyEventReadOnly
+	}
+
+	if event.Status == models.EventStatusCancelled {
+		return nil, fmt.Errorf("event is already cancelled")
+	}
+
+	return event, nil
+}
+
+// shiftReminders moves relative reminders of a rescheduled event and makes them due again
+func (u *calendarUsecase) shiftReminders(event *models.Event) {
+	reminders, err := u.reminderRepo.GetEventReminders(event.ID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"event_id": event.ID,
+			"error":    err.Error(),
+		}).Warn("Failed to get reminders of rescheduled event")
+		return
+	}
+
+	for _, reminder := range reminders {
+		if reminder.MinutesBefore == nil {
+			continue
+		}
+
+		reminder.TriggerTime = event.StartTime.Add(-time.Duration(*reminder.MinutesBefore) * time.Minute)
+		reminder.IsSent = false
+		reminder.SentAt = nil
+
+		if err := u.reminderRepo.UpdateReminder(reminder); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"event_id":    event.ID,
+				"reminder_id": reminder.ID,
+				"error":       err.Error(),
+			}).Warn("Failed to shift reminder of rescheduled event")
+		}
+	}
+}
+
+// notifyParticipants notifies all event participants except the user who made the change.
+// Failures are logged, not returned, since the change is already saved.
+func (u *calendarUsecase) notifyParticipants(event *models.Event, userID uint, priority, titleKey, messageKey string, args map[string]interface{}) {
+	if u.notifier == nil {
+		return
+	}
+
+	participants, err := u.participantRepo.GetEventParticipants(event.ID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"event_id": event.ID,
+			"error":    err.Error(),
+		}).Warn("Failed to get participants for event notification")
+		return
+	}
+
+	var userIDs []uint
+	for _, participant := range participants {
+		if participant.UserID != userID {
+			userIDs = append(userIDs, participant.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	notification := &EventNotification{
+		EventID:  event.ID,
+		UserIDs:  userIDs,
+		Title:    i18n.T(i18n.DefaultLocale, titleKey, args),
+		Message:  i18n.T(i18n.DefaultLocale, messageKey, args),
+		Priority: priority,
+	}
+
+	if err := u.notifier.Notify(notification); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"event_id":   event.ID,
+			"user_count": len(userIDs),
+			"error":      err.Error(),
+		}).Warn("Failed to notify event participants")
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EventNotification represents a notification about an event sent to its participants
+type EventNotification struct {
+	EventID  uint
+	UserIDs  []uint
+	Title    string
+	Message  string
+	Channels []string
+	Priority string
+}
+
+// EventNotifier sends notifications about events to users
+type EventNotifier interface {
+	Notify(notification *EventNotification) error
+}
+
+// httpEventNotifier queues bulk notifications in the notification service
+type httpEventNotifier struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPEventNotifier creates a notifier for the notification service at baseURL.
+// It returns nil if baseURL is empty, which disables event notifications.
+func NewHTTPEventNotifier(baseURL string) EventNotifier {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpEventNotifier{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify queues one calendar notification for all users
+func (n *httpEventNotifier) Notify(notification *EventNotification) error {
+	if len(notification.UserIDs) == 0 {
+		return nil
+	}
+
+	task := map[string]interface{}{
+		"type":     "bulk",
+		"priority": notification.Priority,
+		"bulk_notification": map[string]interface{}{
+			"user_ids":     notification.UserIDs,
+			"type":         "calendar",
+			"title":        notification.Title,
+			"message":      notification.Message,
+			"priority":     notification.Priority,
+			"related_id":   notification.EventID,
+			"related_type": "event",
+			"channels":     notification.Channels,
+		},
+	}
+
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification task: %w", err)
+	}
+
+	resp, err := n.client.Post(n.baseURL+"/api/v1/internal/notifications/task", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification task: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
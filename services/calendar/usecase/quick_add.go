@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+)
+
+// quickAddMeetingWords mark quick add text as a meeting even without participants
+var quickAddMeetingWords = []string{"встреч", "созвон", "совещани", "планерк", "meeting", "call", "sync"}
+
+// QuickAdd parses free text into a draft of an event. Nothing is created: the draft is returned
+// for the user to review and confirm with CreateEvent.
+func (u *calendarUsecase) QuickAdd(userID uint, req *models.QuickAddRequest) (*models.QuickAddDraft, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	location := u.orgSettings.Get().Location()
+	if req.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, fmt.Errorf("validation failed: unknown timezone %q", req.Timezone)
+		}
+	}
+
+	now := time.Now().In(location)
+	text := parseQuickAdd(req.Text, now)
+
+	locale := i18n.LocaleEN
+	if isRussian(req.Text) {
+		locale = i18n.LocaleRU
+	}
+
+	draft := &models.QuickAddDraft{
+		Event: &models.CreateEventRequest{
+			Title:          text.title,
+			Type:           models.EventTypePersonal,
+			ParticipantIDs: []uint{},
+		},
+		Locale:       locale,
+		Recognized:   text.recognized,
+		Participants: []*models.QuickAddParticipant{},
+		Warnings:     []string{},
+	}
+	if draft.Event.Title == "" {
+		draft.Event.Title = i18n.T(locale, "calendar.quick_add_default_title", nil)
+	}
+	if runes := []rune(draft.Event.Title); len(runes) > 0 {
+		draft.Event.Title = strings.ToUpper(string(runes[0])) + string(runes[1:])
+	}
+	if len(text.names) > 0 || isMeetingText(req.Text) {
+		draft.Event.Type = models.EventTypeMeeting
+	}
+
+	scheduleQuickAdd(draft, text, now)
+	u.resolveQuickAddParticipants(userID, draft, text.names)
+
+	return draft, nil
+}
+
+// scheduleQuickAdd sets the start and end of the draft. Without a date the event is today, or
+// tomorrow if the time already passed. A date without a time makes an all-day event. Without
+// either the event starts at the next full hour.
+func scheduleQuickAdd(draft *models.QuickAddDraft, text *quickAddText, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	day := text.date
+	if day == nil && text.weekday != nil {
+		next := today.AddDate(0, 0, (int(*text.weekday)-int(today.Weekday())+7)%7)
+		if next.Equal(today) && text.start != nil && !today.Add(*text.start).After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+		day = &next
+	}
+
+	event := draft.Event
+	switch {
+	case day != nil && text.start == nil:
+		event.AllDay = true
+		event.StartTime = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+		event.EndTime = event.StartTime.Add(24*time.Hour - time.Minute)
+		return
+	case day == nil && text.start == nil:
+		event.StartTime = now.Truncate(time.Hour).Add(time.Hour)
+		draft.Warnings = append(draft.Warnings, models.QuickAddWarningTimeAssumed)
+	case day == nil:
+		event.StartTime = today.Add(*text.start)
+		if !event.StartTime.After(now) {
+			event.StartTime = today.AddDate(0, 0, 1).Add(*text.start)
+		}
+		draft.Warnings = append(draft.Warnings, models.QuickAddWarningDateAssumed)
+	default:
+		event.StartTime = day.Add(*text.start)
+	}
+
+	switch {
+	case text.end != nil:
+		event.EndTime = time.Date(event.StartTime.Year(), event.StartTime.Month(), event.StartTime.Day(), 0, 0, 0, 0, now.Location()).Add(*text.end)
+		if !event.EndTime.After(event.StartTime) {
+			event.EndTime = event.EndTime.Add(24 * time.Hour)
+		}
+	case text.duration > 0:
+		event.EndTime = event.StartTime.Add(text.duration)
+	default:
+		event.EndTime = event.StartTime.Add(defaultQuickAddDuration)
+	}
+}
+
+// resolveQuickAddParticipants looks up users for names mentioned in the text. A name is resolved
+// when exactly one user other than the author matches every word of it.
+func (u *calendarUsecase) resolveQuickAddParticipants(userID uint, draft *models.QuickAddDraft, names [][]string) {
+	if len(names) == 0 {
+		return
+	}
+
+	var words []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		for _, word := range name {
+			if stem := nameStem(word); !seen[stem] {
+				seen[stem] = true
+				words = append(words, stem)
+			}
+		}
+	}
+
+	var matches map[string][]*models.QuickAddUser
+	if u.users != nil {
+		var err error
+		if matches, err = u.users.MatchUsers(words); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"user_id": userID,
+				"error":   err.Error(),
+			}).Warn("Failed to match quick add participants")
+		}
+	}
+
+	for _, name := range names {
+		participant := &models.QuickAddParticipant{
+			Name:       strings.Join(name, " "),
+			Candidates: []*models.QuickAddUser{},
+		}
+		draft.Participants = append(draft.Participants, participant)
+		if matches == nil {
+			continue
+		}
+
+		// Users matching every word of the name, in the order of the first word
+		var candidates []*models.QuickAddUser
+		for _, user := range matches[nameStem(name[0])] {
+			if user.ID == userID || containsUser(candidates, user.ID) {
+				continue
+			}
+			matchesAll := true
+			for _, word := range name[1:] {
+				matchesAll = matchesAll && containsUser(matches[nameStem(word)], user.ID)
+			}
+			if matchesAll {
+				candidates = append(candidates, user)
+			}
+		}
+		participant.Candidates = append(participant.Candidates, candidates...)
+
+		switch len(candidates) {
+		case 0:
+			draft.Warnings = appendWarning(draft.Warnings, models.QuickAddWarningUnknownParticipant)
+		case 1:
+			participant.UserID = &candidates[0].ID
+			draft.Event.ParticipantIDs = append(draft.Event.ParticipantIDs, candidates[0].ID)
+		default:
+			draft.Warnings = appendWarning(draft.Warnings, models.QuickAddWarningAmbiguousParticipant)
+		}
+	}
+
+	if matches == nil {
+		draft.Warnings = appendWarning(draft.Warnings, models.QuickAddWarningParticipantsSkipped)
+	}
+}
+
+// isMeetingText checks if quick add text mentions a meeting
+func isMeetingText(text string) bool {
+	text = strings.ToLower(text)
+	for _, word := range quickAddMeetingWords {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsUser checks if users contain the user with id
+func containsUser(users []*models.QuickAddUser, id uint) bool {
+	for _, user := range users {
+		if user.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// appendWarning appends a warning unless the draft already has it
+func appendWarning(warnings []string, warning string) []string {
+	for _, existing := range warnings {
+		if existing == warning {
+			return warnings
+		}
+	}
+	return append(warnings, warning)
+}
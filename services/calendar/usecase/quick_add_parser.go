@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultQuickAddDuration is the duration of a quick add event without one in the text
+const defaultQuickAddDuration = time.Hour
+
+// Kinds of phrases recognized in quick add text, each kind is recognized once
+const (
+	quickAddDate = iota
+	quickAddTime
+	quickAddDuration
+)
+
+// quickAddText is quick add text with date, time and duration phrases recognized in it
+type quickAddText struct {
+	now        time.Time // Current time in the timezone of the text
+	date       *time.Time
+	weekday    *time.Weekday
+	start      *time.Duration // Time of day the event starts
+	end        *time.Duration // Time of day the event ends, for time ranges
+	duration   time.Duration
+	names      [][]string // Mentioned participants, each with the words of their name
+	recognized []string
+	title      string
+}
+
+// quickAddRule recognizes one kind of phrase. apply returns false when the phrase can't be read,
+// such as hour 25, it then stays in the title.
+type quickAddRule struct {
+	kind    int
+	pattern *regexp.Regexp
+	apply   func(text *quickAddText, groups []string) bool
+}
+
+// quickAddPhrase compiles a case-insensitive pattern matching expr as separate words. Group 1 is
+// the whole phrase, groups of expr follow.
+func quickAddPhrase(expr string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[\s,(])(` + expr + `)(?:$|[\s,.!?;)])`)
+}
+
+const (
+	quickAddTimeExpr  = `(\d{1,2})(?:[:.](\d{2}))?(?:\s*час(?:а|ов)?)?\s*(am|pm|утра|дня|вечера|ночи)?`
+	quickAddMonthExpr = `января|февраля|марта|апреля|мая|июня|июля|августа|сентября|октября|ноября|декабря|` +
+		`jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?`
+	quickAddWeekdayExpr = `понедельник|вторник|сред[уа]|четверг|пятниц[уа]|суббот[уа]|воскресенье|` +
+		`monday|tuesday|wednesday|thursday|friday|saturday|sunday`
+)
+
+// quickAddRules are Russian and English rules in the order they are tried: longer phrases first
+var quickAddRules = []quickAddRule{
+	// "с 15:00 до 16:30", "from 3 to 4pm"
+	{quickAddTime, quickAddPhrase(`(?:с|from)\s+` + quickAddTimeExpr + `\s*(?:до|to|until|till|-|–)\s*` + quickAddTimeExpr), func(t *quickAddText, g []string) bool {
+		startMeridiem := g[3]
+		if startMeridiem == "" && isAmPm(g[6]) {
+			startMeridiem = g[6]
+		}
+		start, ok := timeOfDay(g[1], g[2], startMeridiem)
+		if !ok {
+			return false
+		}
+		end, ok := timeOfDay(g[4], g[5], g[6])
+		if !ok {
+			return false
+		}
+		t.start, t.end = &start, &end
+		return true
+	}},
+	// "в 15:00", "в 3 часа дня", "at 3pm"
+	{quickAddTime, quickAddPhrase(`(?:в|во|к|at|@)\s*` + quickAddTimeExpr), func(t *quickAddText, g []string) bool {
+		return t.setStart(timeOfDay(g[1], g[2], g[3]))
+	}},
+	// "в полдень", "at noon"
+	{quickAddTime, quickAddPhrase(`(?:(?:в|at)\s+)?(?:полдень|noon)`), func(t *quickAddText, g []string) bool {
+		return t.setStart(12*time.Hour, true)
+	}},
+	// "15:00", "3pm"
+	{quickAddTime, quickAddPhrase(`(\d{1,2}):(\d{2})\s*(am|pm)?`), func(t *quickAddText, g []string) bool {
+		return t.setStart(timeOfDay(g[1], g[2], g[3]))
+	}},
+	{quickAddTime, quickAddPhrase(`(\d{1,2})\s*(am|pm)`), func(t *quickAddText, g []string) bool {
+		return t.setStart(timeOfDay(g[1], "", g[2]))
+	}},
+
+	// "на полчаса", "for half an hour"
+	{quickAddDuration, quickAddPhrase(`(?:на|for)\s+(?:полчаса|half\s+an\s+hour)`), func(t *quickAddText, g []string) bool {
+		t.duration = 30 * time.Minute
+		return true
+	}},
+	// "на полтора часа", "for an hour and a half"
+	{quickAddDuration, quickAddPhrase(`(?:на|for)\s+(?:полтора\s+часа|an\s+hour\s+and\s+a\s+half)`), func(t *quickAddText, g []string) bool {
+		t.duration = 90 * time.Minute
+		return true
+	}},
+	// "на час", "на 2 часа", "на 45 минут", "for an hour", "for 30 min"
+	{quickAddDuration, quickAddPhrase(`(?:на|for)\s+(?:(\d{1,3}(?:[.,]\d)?|an?|one)\s*)?(час(?:а|ов)?|ч|hours?|hrs?|h|минут[уы]?|мин|minutes?|mins?)`), func(t *quickAddText, g []string) bool {
+		amount := 1.0
+		if number, err := strconv.ParseFloat(strings.Replace(g[1], ",", ".", 1), 64); err == nil {
+			amount = number
+		}
+		unit := time.Hour
+		if unitName := strings.ToLower(g[2]); strings.HasPrefix(unitName, "мин") || strings.HasPrefix(unitName, "min") {
+			unit = time.Minute
+		}
+		duration := time.Duration(amount * float64(unit))
+		if duration <= 0 || duration > 24*time.Hour {
+			return false
+		}
+		t.duration = duration
+		return true
+	}},
+
+	// "послезавтра", "сегодня", "завтра" and English
+	{quickAddDate, quickAddPhrase(`послезавтра|day\s+after\s+tomorrow`), func(t *quickAddText, g []string) bool {
+		return t.setDate(t.now.AddDate(0, 0, 2))
+	}},
+	{quickAddDate, quickAddPhrase(`сегодня|today|tonight`), func(t *quickAddText, g []string) bool {
+		return t.setDate(t.now)
+	}},
+	{quickAddDate, quickAddPhrase(`завтра|tomorrow`), func(t *quickAddText, g []string) bool {
+		return t.setDate(t.now.AddDate(0, 0, 1))
+	}},
+	// "в пятницу", "on friday"
+	{quickAddDate, quickAddPhrase(`(?:(?:в|во|on|next|this)\s+)?(` + quickAddWeekdayExpr + `)`), func(t *quickAddText, g []string) bool {
+		weekday, ok := weekdayOf(g[1])
+		if ok {
+			t.weekday = &weekday
+		}
+		return ok
+	}},
+	// "12.05", "12.05.2026"
+	{quickAddDate, quickAddPhrase(`(\d{1,2})\.(\d{1,2})(?:\.(\d{4}|\d{2}))?`), func(t *quickAddText, g []string) bool {
+		month, _ := strconv.Atoi(g[2])
+		return t.setDayOfYear(g[1], time.Month(month), g[3])
+	}},
+	// "12 мая", "12th of May", "May 12"
+	{quickAddDate, quickAddPhrase(`(?:on\s+)?(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?(` + quickAddMonthExpr + `)`), func(t *quickAddText, g []string) bool {
+		return t.setDayOfYear(g[1], monthOf(g[2]), "")
+	}},
+	{quickAddDate, quickAddPhrase(`(?:on\s+)?(` + quickAddMonthExpr + `)\s+(\d{1,2})(?:st|nd|rd|th)?`), func(t *quickAddText, g []string) bool {
+		return t.setDayOfYear(g[2], monthOf(g[1]), "")
+	}},
+}
+
+var (
+	// quickAddNames match capitalized names after "с"/"with", several names separated by commas or "и"/"and"
+	quickAddNames = []*regexp.Regexp{
+		regexp.MustCompile(`(?:^|\s)[Сс]о?\s+(\p{Lu}\p{Ll}+(?:(?:\s*,\s*|\s+и\s+|\s+)\p{Lu}\p{Ll}+)*)`),
+		regexp.MustCompile(`(?:^|\s)[Ww]ith\s+(\p{Lu}\p{Ll}+(?:(?:\s*,\s*|\s+and\s+|\s+)\p{Lu}\p{Ll}+)*)`),
+	}
+	quickAddNameSeparator = regexp.MustCompile(`\s*,\s*|\s+(?:и|and)\s+`)
+
+	// russianNameEndings are case endings stripped from Russian names, "с Иваном Петровым" mentions Иван Петров
+	russianNameEndings = []string{"ой", "ей", "ёй", "ом", "ем", "ым", "им", "ою", "ею", "у", "ю", "а", "я", "е", "ы", "и"}
+
+	quickAddSpaces = regexp.MustCompile(`\s+`)
+)
+
+// parseQuickAdd recognizes date, time, duration and participants in quick add text. The rest of
+// the text is the title.
+func parseQuickAdd(input string, now time.Time) *quickAddText {
+	text := &quickAddText{now: now}
+	rest := input
+	found := make(map[int]bool)
+
+	for _, rule := range quickAddRules {
+		if found[rule.kind] {
+			continue
+		}
+		loc := rule.pattern.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			continue
+		}
+
+		groups := make([]string, len(loc)/2-1)
+		for i := range groups {
+			if start := loc[2*(i+1)]; start >= 0 {
+				groups[i] = rest[start:loc[2*(i+1)+1]]
+			}
+		}
+		if !rule.apply(text, groups) {
+			continue
+		}
+
+		found[rule.kind] = true
+		text.recognized = append(text.recognized, strings.TrimSpace(groups[0]))
+		rest = rest[:loc[2]] + " " + rest[loc[3]:]
+	}
+
+	for _, pattern := range quickAddNames {
+		for _, match := range pattern.FindAllStringSubmatch(input, -1) {
+			for _, name := range quickAddNameSeparator.Split(match[1], -1) {
+				if words := strings.Fields(name); len(words) > 0 {
+					text.names = append(text.names, words)
+				}
+			}
+		}
+	}
+
+	text.title = strings.Trim(quickAddSpaces.ReplaceAllString(rest, " "), " ,.;:-–—")
+	return text
+}
+
+// setStart sets the start time of day if it was read
+func (t *quickAddText) setStart(start time.Duration, ok bool) bool {
+	if ok {
+		t.start = &start
+	}
+	return ok
+}
+
+// setDate sets the date of the event to the day of date
+func (t *quickAddText) setDate(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	t.date = &day
+	return true
+}
+
+// setDayOfYear sets the date from day, month and optional year. Dates without a year that
+// already passed are next year.
+func (t *quickAddText) setDayOfYear(dayText string, month time.Month, yearText string) bool {
+	day, err := strconv.Atoi(dayText)
+	if err != nil || month < time.January || month > time.December {
+		return false
+	}
+
+	year := t.now.Year()
+	if yearText != "" {
+		year, _ = strconv.Atoi(yearText)
+		if year < 100 {
+			year += 2000
+		}
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, t.now.Location())
+	if date.Day() != day {
+		return false // Such as 31.02
+	}
+	today := time.Date(t.now.Year(), t.now.Month(), t.now.Day(), 0, 0, 0, 0, t.now.Location())
+	if yearText == "" && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return t.setDate(date)
+}
+
+// timeOfDay reads an hour, optional minutes and an optional "am"/"pm" or Russian part of day
+func timeOfDay(hourText, minuteText, meridiem string) (time.Duration, bool) {
+	hour, err := strconv.Atoi(hourText)
+	if err != nil {
+		return 0, false
+	}
+	minute := 0
+	if minuteText != "" {
+		if minute, err = strconv.Atoi(minuteText); err != nil || minute > 59 {
+			return 0, false
+		}
+	}
+
+	switch strings.ToLower(meridiem) {
+	case "am", "утра":
+		if hour > 12 {
+			return 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm", "дня", "вечера":
+		if hour > 12 {
+			return 0, false
+		}
+		if hour < 12 {
+			hour += 12
+		}
+	case "ночи":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+	if hour > 23 {
+		return 0, false
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+// isAmPm checks if meridiem is English, whose part of day applies to both ends of "from 3 to 4pm"
+func isAmPm(meridiem string) bool {
+	meridiem = strings.ToLower(meridiem)
+	return meridiem == "am" || meridiem == "pm"
+}
+
+// weekdayOf returns the weekday of a Russian or English weekday name
+func weekdayOf(name string) (time.Weekday, bool) {
+	prefixes := map[string]time.Weekday{
+		"пон": time.Monday, "вто": time.Tuesday, "сре": time.Wednesday, "чет": time.Thursday,
+		"пят": time.Friday, "суб": time.Saturday, "вос": time.Sunday,
+		"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+		"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+	}
+	weekday, ok := prefixes[runePrefix(strings.ToLower(name), 3)]
+	return weekday, ok
+}
+
+// monthOf returns the month of a Russian month name in genitive case or an English month name
+func monthOf(name string) time.Month {
+	prefixes := map[string]time.Month{
+		"янв": time.January, "фев": time.February, "мар": time.March, "апр": time.April,
+		"мая": time.May, "июн": time.June, "июл": time.July, "авг": time.August,
+		"сен": time.September, "окт": time.October, "ноя": time.November, "дек": time.December,
+		"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+		"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+		"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+	}
+	return prefixes[runePrefix(strings.ToLower(name), 3)]
+}
+
+// nameStem strips the case ending of a Russian name so that it matches the name in nominative
+func nameStem(word string) string {
+	if first, _ := utf8.DecodeRuneInString(word); !unicode.Is(unicode.Cyrillic, first) {
+		return word
+	}
+	for _, ending := range russianNameEndings {
+		if strings.HasSuffix(word, ending) && utf8.RuneCountInString(word)-utf8.RuneCountInString(ending) >= 3 {
+			return strings.TrimSuffix(word, ending)
+		}
+	}
+	return word
+}
+
+// isRussian checks if text is written in Cyrillic
+func isRussian(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// runePrefix returns the first n runes of s
+func runePrefix(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
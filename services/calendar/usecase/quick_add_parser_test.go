@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC) // Среда
+
+	hours := func(h, m int) *time.Duration {
+		d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+		return &d
+	}
+	day := func(month time.Month, d, year int) *time.Time {
+		date := time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	weekday := func(w time.Weekday) *time.Weekday { return &w }
+
+	cases := []struct {
+		input    string
+		date     *time.Time
+		weekday  *time.Weekday
+		start    *time.Duration
+		end      *time.Duration
+		duration time.Duration
+		title    string
+	}{
+		// Relative days
+		{input: "Созвон завтра в 15:00", date: day(time.October, 15, 2026), start: hours(15, 0), title: "Созвон"},
+		{input: "Ретро послезавтра", date: day(time.October, 16, 2026), title: "Ретро"},
+		{input: "Обед сегодня в полдень", date: day(time.October, 14, 2026), start: hours(12, 0), title: "Обед"},
+		{input: "Call tomorrow at 3pm", date: day(time.October, 15, 2026), start: hours(15, 0), title: "Call"},
+		{input: "Review day after tomorrow", date: day(time.October, 16, 2026), title: "Review"},
+		{input: "Dinner tonight at 7:30 pm", date: day(time.October, 14, 2026), start: hours(19, 30), title: "Dinner"},
+
+		// Weekdays
+		{input: "Планёрка в пятницу в 10", weekday: weekday(time.Friday), start: hours(10, 0), title: "Планёрка"},
+		{input: "Демо во вторник", weekday: weekday(time.Tuesday), title: "Демо"},
+		{input: "Standup on monday at 9am", weekday: weekday(time.Monday), start: hours(9, 0), title: "Standup"},
+		{input: "Sync next thursday", weekday: weekday(time.Thursday), title: "Sync"},
+
+		// Dates, passed dates without a year are next year
+		{input: "Отпуск 12 мая", date: day(time.May, 12, 2027), title: "Отпуск"},
+		{input: "Релиз 20.10", date: day(time.October, 20, 2026), title: "Релиз"},
+		{input: "Аудит 05.03.2027", date: day(time.March, 5, 2027), title: "Аудит"},
+		{input: "Party on December 24th", date: day(time.December, 24, 2026), title: "Party"},
+		{input: "Offsite 3rd of November", date: day(time.November, 3, 2026), title: "Offsite"},
+
+		// Time ranges
+		{input: "Встреча с 15:00 до 16:30", start: hours(15, 0), end: hours(16, 30), title: "Встреча"},
+		{input: "Workshop from 3 to 4pm", start: hours(15, 0), end: hours(16, 0), title: "Workshop"},
+		{input: "Бронь с 9 утра до 6 вечера", start: hours(9, 0), end: hours(18, 0), title: "Бронь"},
+
+		// Russian parts of day
+		{input: "Звонок в 3 часа дня", start: hours(15, 0), title: "Звонок"},
+		{input: "Деплой в 2 ночи", start: hours(2, 0), title: "Деплой"},
+
+		// Durations
+		{input: "Интервью на 45 минут", duration: 45 * time.Minute, title: "Интервью"},
+		{input: "Разбор на полтора часа", duration: 90 * time.Minute, title: "Разбор"},
+		{input: "Планирование на 2 часа", duration: 2 * time.Hour, title: "Планирование"},
+		{input: "Coffee for half an hour", duration: 30 * time.Minute, title: "Coffee"},
+		{input: "Pairing for an hour", duration: time.Hour, title: "Pairing"},
+		{input: "Training for 1.5 h", duration: 90 * time.Minute, title: "Training"},
+
+		// Input that does not parse stays in the title
+		{input: "Просто заметка", title: "Просто заметка"},
+		{input: "Meeting at 25:00", title: "Meeting at 25:00"},
+		{input: "Дедлайн 31.02", title: "Дедлайн 31.02"},
+		{input: "Backlog grooming for 30 hours", title: "Backlog grooming for 30 hours"},
+		{input: "", title: ""},
+	}
+
+	for _, tc := range cases {
+		text := parseQuickAdd(tc.input, now)
+
+		if !reflect.DeepEqual(text.date, tc.date) {
+			t.Errorf("parseQuickAdd(%q) date = %v, expected %v", tc.input, text.date, tc.date)
+		}
+		if !reflect.DeepEqual(text.weekday, tc.weekday) {
+			t.Errorf("parseQuickAdd(%q) weekday = %v, expected %v", tc.input, text.weekday, tc.weekday)
+		}
+		if !reflect.DeepEqual(text.start, tc.start) {
+			t.Errorf("parseQuickAdd(%q) start = %v, expected %v", tc.input, text.start, tc.start)
+		}
+		if !reflect.DeepEqual(text.end, tc.end) {
+			t.Errorf("parseQuickAdd(%q) end = %v, expected %v", tc.input, text.end, tc.end)
+		}
+		if text.duration != tc.duration {
+			t.Errorf("parseQuickAdd(%q) duration = %v, expected %v", tc.input, text.duration, tc.duration)
+		}
+		if text.title != tc.title {
+			t.Errorf("parseQuickAdd(%q) title = %q, expected %q", tc.input, text.title, tc.title)
+		}
+	}
+}
+
+func TestParseQuickAddNames(t *testing.T) {
+	cases := map[string][][]string{
+		"Обед с Иваном и Марией завтра": {{"Иваном"}, {"Марией"}},
+		"Sync with Anna, Bob and Carol": {{"Anna"}, {"Bob"}, {"Carol"}},
+		"Встреча с Петром Сидоровым":    {{"Петром", "Сидоровым"}},
+		"Созвон с командой в 15:00":     nil,
+		"Lunch with the team at 1pm":    nil,
+	}
+	for input, expected := range cases {
+		text := parseQuickAdd(input, time.Now())
+		if !reflect.DeepEqual(text.names, expected) {
+			t.Errorf("parseQuickAdd(%q) names = %v, expected %v", input, text.names, expected)
+		}
+	}
+
+	stems := map[string]string{"Иваном": "Иван", "Марией": "Мари", "Петром": "Петр", "Anna": "Anna", "Ией": "Ией"}
+	for word, expected := range stems {
+		if stem := nameStem(word); stem != expected {
+			t.Errorf("nameStem(%q) = %q, expected %q", word, stem, expected)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+// GetEventReminders returns reminders the user set on an event
+func (u *calendarUsecase) GetEventReminders(userID, eventID uint) ([]*models.EventReminderResponse, error) {
+	if _, err := u.getAccessibleEvent(userID, eventID); err != nil {
+		return nil, err
+	}
+
+	reminders, err := u.reminderRepo.GetUserEventReminders(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return reminderResponses(reminders), nil
+}
+
+// SetEventReminders replaces all reminders the user set on an event, an empty list removes them
+func (u *calendarUsecase) SetEventReminders(userID, eventID uint, req *models.SetRemindersRequest) ([]*models.EventReminderResponse, error) {
+	if err := u.validateSetRemindersRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	event, err := u.getAccessibleEvent(userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders, err := buildEventReminders(event, userID, req.Reminders)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := u.reminderRepo.ReplaceUserEventReminders(eventID, userID, reminders); err != nil {
+		return nil, err
+	}
+	return reminderResponses(reminders), nil
+}
+
+// GetReminderDefaults returns reminders the user gets on events they create or accept
+func (u *calendarUsecase) GetReminderDefaults(userID uint) ([]*models.ReminderDefaultResponse, error) {
+	defaults, err := u.reminderRepo.GetReminderDefaults(userID)
+	if err != nil {
+		return nil, err
+	}
+	return reminderDefaultResponses(defaults), nil
+}
+
+// SetReminderDefaults replaces default reminders of the user. Reminders of existing events are kept.
+func (u *calendarUsecase) SetReminderDefaults(userID uint, req *models.SetRemindersRequest) ([]*models.ReminderDefaultResponse, error) {
+	if err := u.validateSetRemindersRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Defaults go through the same checks as reminders of an event
+	reminders, err := buildEventReminders(&models.Event{}, userID, req.Reminders)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	defaults := make([]*models.ReminderDefault, len(reminders))
+	for i, reminder := range reminders {
+		defaults[i] = &models.ReminderDefault{
+			UserID:        userID,
+			Type:          reminder.Type,
+			MinutesBefore: *reminder.MinutesBefore,
+			Message:       reminder.Message,
+		}
+	}
+
+	if err := u.reminderRepo.ReplaceReminderDefaults(userID, defaults); err != nil {
+		return nil, err
+	}
+	return reminderDefaultResponses(defaults), nil
+}
+
+// applyDefaultReminders gives the user their default reminders on an event, unless they already
+// set reminders on it. Failures are logged, not returned, since the event change is already saved.
+func (u *calendarUsecase) applyDefaultReminders(event *models.Event, userID uint) {
+	fields := map[string]interface{}{
+		"event_id": event.ID,
+		"user_id":  userID,
+	}
+
+	defaults, err := u.reminderRepo.GetReminderDefaults(userID)
+	if err != nil {
+		fields["error"] = err.Error()
+		logger.WithFields(fields).Warn("Failed to get default reminders")
+		return
+	}
+	if len(defaults) == 0 {
+		return
+	}
+
+	existing, err := u.reminderRepo.GetUserEventReminders(event.ID, userID)
+	if err != nil {
+		fields["error"] = err.Error()
+		logger.WithFields(fields).Warn("Failed to get event reminders")
+		return
+	}
+	if len(existing) > 0 {
+		return
+	}
+
+	reminders := make([]*models.EventReminder, len(defaults))
+	for i, reminderDefault := range defaults {
+		minutes := reminderDefault.MinutesBefore
+		reminders[i] = &models.EventReminder{
+			EventID:       event.ID,
+			UserID:        userID,
+			Type:          reminderDefault.Type,
+			MinutesBefore: &minutes,
+			Message:       reminderDefault.Message,
+			TriggerTime:   event.StartTime.Add(-time.Duration(minutes) * time.Minute),
+		}
+	}
+
+	if err := u.reminderRepo.ReplaceUserEventReminders(event.ID, userID, reminders); err != nil {
+		fields["error"] = err.Error()
+		logger.WithFields(fields).Warn("Failed to apply default reminders")
+		return
+	}
+
+	fields["reminder_count"] = len(reminders)
+	logger.WithFields(fields).Debug("Default reminders applied")
+}
+
+// ProcessDueReminders sends reminders whose trigger time has come through the channel chosen for
+// each of them. Reminders of cancelled or already started events are marked sent without sending.
+// It returns the number of reminders sent.
+func (u *calendarUsecase) ProcessDueReminders(now time.Time) (int, error) {
+	if u.notifier == nil {
+		return 0, nil
+	}
+
+	reminders, err := u.reminderRepo.GetPendingReminders(now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		event := reminder.Event
+		if event != nil && event.Status != models.EventStatusCancelled && event.StartTime.After(now) {
+			if err := u.notifier.Notify(buildEventReminder(reminder)); err != nil {
+				logger.WithFields(map[string]interface{}{
+					"event_id":    reminder.EventID,
+					"reminder_id": reminder.ID,
+					"user_id":     reminder.UserID,
+					"type":        reminder.Type,
+					"error":       err.Error(),
+				}).Error("Failed to send event reminder")
+				continue
+			}
+			sent++
+		}
+
+		if err := u.reminderRepo.MarkReminderSent(reminder.ID); err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// buildEventReminder creates the notification of a due reminder
+func buildEventReminder(reminder *models.EventReminder) *EventNotification {
+	args := map[string]interface{}{
+		"EventTitle": reminder.Event.Title,
+		"StartTime":  reminder.Event.StartTime.UTC().Format("02.01.2006 15:04 MST"),
+	}
+
+	message := reminder.Message
+	if message == "" {
+		message = i18n.T(i18n.DefaultLocale, "notification.calendar_reminder_message", args)
+	}
+
+	return &EventNotification{
+		EventID:  reminder.EventID,
+		UserIDs:  []uint{reminder.UserID},
+		Title:    i18n.T(i18n.DefaultLocale, "notification.calendar_reminder_title", args),
+		Message:  message,
+		Channels: []string{reminder.Type.Channel()},
+		Priority: "medium",
+	}
+}
+
+// buildEventReminders converts reminder requests of a user to reminders of an event.
+// The same channel and offset may only be requested once.
+func buildEventReminders(event *models.Event, userID uint, reqs []models.CreateReminderRequest) ([]*models.EventReminder, error) {
+	if len(reqs) > models.MaxRemindersPerEvent {
+		return nil, fmt.Errorf("at most %d reminders per event are allowed", models.MaxRemindersPerEvent)
+	}
+
+	seen := make(map[string]bool, len(reqs))
+	reminders := make([]*models.EventReminder, 0, len(reqs))
+	for i := range reqs {
+		minutes, err := reqs[i].Minutes()
+		if err != nil {
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%s/%d", reqs[i].Type, minutes)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate %s reminder %s before the event", reqs[i].Type, models.FormatReminderOffset(minutes))
+		}
+		seen[key] = true
+
+		reminders = append(reminders, &models.EventReminder{
+			EventID:       event.ID,
+			UserID:        userID,
+			Type:          reqs[i].Type,
+			MinutesBefore: &minutes,
+			Message:       strings.TrimSpace(reqs[i].Message),
+			TriggerTime:   event.StartTime.Add(-time.Duration(minutes) * time.Minute),
+		})
+	}
+
+	return reminders, nil
+}
+
+// getAccessibleEvent returns an event if the user has access to it
+func (u *calendarUsecase) getAccessibleEvent(userID, eventID uint) (*models.Event, error) {
+	event, err := u.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if !u.hasEventAccess(userID, event) {
+		return nil, fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	return event, nil
+}
+
+func reminderResponses(reminders []*models.EventReminder) []*models.EventReminderResponse {
+	responses := make([]*models.EventReminderResponse, len(reminders))
+	for i, reminder := range reminders {
+		responses[i] = reminder.ToResponse()
+	}
+	return responses
+}
+
+func reminderDefaultResponses(defaults []*models.ReminderDefault) []*models.ReminderDefaultResponse {
+	responses := make([]*models.ReminderDefaultResponse, len(defaults))
+	for i, reminderDefault := range defaults {
+		responses[i] = reminderDefault.ToResponse()
+	}
+	return responses
+}
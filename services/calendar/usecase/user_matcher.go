@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/calendar/models"
+)
+
+// UserMatcher resolves names mentioned in free text to users
+type UserMatcher interface {
+	// MatchUsers returns active users whose first or last name starts with each of the names
+	MatchUsers(names []string) (map[string][]*models.QuickAddUser, error)
+}
+
+// httpUserMatcher asks the user service for users matching names
+type httpUserMatcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPUserMatcher creates a user matcher for the user service at baseURL.
+// It returns nil if baseURL is empty, names in quick add text then stay unresolved.
+func NewHTTPUserMatcher(baseURL string) UserMatcher {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpUserMatcher{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// MatchUsers requests users matching the names from the user service
+func (m *httpUserMatcher) MatchUsers(names []string) (map[string][]*models.QuickAddUser, error) {
+	body, err := json.Marshal(map[string]interface{}{"names": names})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode user match request: %w", err)
+	}
+
+	resp, err := m.client.Post(m.baseURL+"/api/v1/internal/users/match", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request user matches: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service responded with status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Matches map[string][]*models.QuickAddUser `json:"matches"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode user matches: %w", err)
+	}
+
+	return payload.Matches, nil
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"fmt"
+
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// MergeUsers moves calendar data of a duplicate account to the primary account
+func (u *calendarUsecase) MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error) {
+	if req.PrimaryUserID == req.DuplicateUserID {
+		return nil, fmt.Errorf("validation failed: cannot merge user into itself")
+	}
+
+	result, err := u.eventRepo.MergeUsers(req.PrimaryUserID, req.DuplicateUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/calendar/models"
+	"tachyon-messenger/shared/validation"
+)
+
+// GetEventVisibility returns visibility of an event and users its details are shared with (organizer only)
+func (u *calendarUsecase) GetEventVisibility(userID, eventID uint) (*models.EventVisibilityResponse, error) {
+	event, err := u.getOrganizedEvent(userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedWith, err := u.eventRepo.GetDetailGrants(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EventVisibilityResponse{
+		EventID:    event.ID,
+		Visibility: event.EffectiveVisibility(),
+		SharedWith: sharedWith,
+	}, nil
+}
+
+// UpdateEventVisibility changes visibility of an event and replaces users its details are shared with.
+// Shared users only matter for busy-only and private events, the list is cleared for public ones.
+func (u *calendarUsecase) UpdateEventVisibility(userID, eventID uint, req *models.UpdateEventVisibilityRequest) (*models.EventVisibilityResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	event, err := u.getOrganizedEvent(userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedWith := uniqueUserIDs(req.SharedWith)
+	if req.Visibility == models.EventVisibilityPublic {
+		sharedWith = []uint{}
+	}
+	if err := u.userRefs.CheckUsers(sharedWith...); err != nil {
+		return nil, err
+	}
+
+	if event.EffectiveVisibility() != req.Visibility {
+		event.SetVisibility(req.Visibility)
+		if err := u.eventRepo.UpdateEvent(event); err != nil {
+			return nil, fmt.Errorf("failed to update event visibility: %w", err)
+		}
+	}
+	if err := u.eventRepo.ReplaceDetailGrants(event.ID, sharedWith, userID); err != nil {
+		return nil, err
+	}
+
+	return &models.EventVisibilityResponse{
+		EventID:    event.ID,
+		Visibility: event.EffectiveVisibility(),
+		SharedWith: sharedWith,
+	}, nil
+}
+
+// busyBlocks lists busy periods as the user may see them: events the user may see details of keep
+// their title, the others are plain busy blocks
+func (u *calendarUsecase) busyBlocks(userID uint, periods []*models.BusyPeriod) ([]*models.BusyBlock, error) {
+	var hidden []uint
+	for _, period := range periods {
+		if periodVisibility(period) != models.EventVisibilityPublic {
+			hidden = append(hidden, period.EventID)
+		}
+	}
+
+	visible, err := u.eventRepo.GetEventsWithDetails(userID, uniqueUserIDs(hidden))
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*models.BusyBlock, 0, len(periods))
+	for _, period := range periods {
+		block := &models.BusyBlock{
+			UserID:    period.UserID,
+			StartTime: period.StartTime,
+			EndTime:   period.EndTime,
+			Title:     models.BusyTitle,
+		}
+		if periodVisibility(period) == models.EventVisibilityPublic || visible[period.EventID] {
+			eventID := period.EventID
+			block.EventID = &eventID
+			block.Title = period.Title
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// periodVisibility returns visibility of the event taking a busy period
+func periodVisibility(period *models.BusyPeriod) models.EventVisibility {
+	event := models.Event{Visibility: period.Visibility, IsPrivate: period.IsPrivate}
+	return event.EffectiveVisibility()
+}
+
+// requestedVisibility resolves visibility of a request, falling back to the deprecated is_private flag
+func requestedVisibility(visibility *models.EventVisibility, isPrivate bool) models.EventVisibility {
+	switch {
+	case visibility != nil:
+		return *visibility
+	case isPrivate:
+		return models.EventVisibilityPrivate
+	default:
+		return models.EventVisibilityPublic
+	}
+}
@@ -13,8 +13,11 @@ import (
 
 func main() {
 	var (
-		action = flag.String("action", "up", "Migration action: up, status, down")
-		help   = flag.Bool("help", false, "Show help")
+		action     = flag.String("action", "up", "Migration action: up, down, status, force")
+		steps      = flag.Int("steps", 0, "Number of migrations to apply or revert (down defaults to 1)")
+		target     = flag.String("target", "", "Migration version to migrate to")
+		notApplied = flag.Bool("not-applied", false, "With force: mark the dirty migration as not applied instead of applied")
+		help       = flag.Bool("help", false, "Show help")
 	)
 	flag.Parse()
 
@@ -50,11 +53,27 @@ func main() {
 	switch *action {
 	case "up":
 		log.Info("Running migrations...")
-		if err := manager.RunMigrations(); err != nil {
+		if err := manager.MigrateUp(*steps, *target); err != nil {
 			log.Fatalf("Migration failed: %v", err)
 		}
 		log.Info("✅ Migrations completed successfully!")
 
+	case "down":
+		log.Info("Reverting migrations...")
+		if err := manager.MigrateDown(*steps, *target); err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+		log.Info("✅ Rollback completed successfully!")
+
+	case "force":
+		if *target == "" {
+			log.Fatal("Force requires -target=VERSION of the dirty migration")
+		}
+		if err := manager.ForceVersion(*target, !*notApplied); err != nil {
+			log.Fatalf("Force failed: %v", err)
+		}
+		log.Infof("✅ Dirty state of migration %s cleared", *target)
+
 	case "status":
 		log.Info("Checking migration status...")
 		applied, err := manager.GetAppliedMigrations()
@@ -67,12 +86,16 @@ func main() {
 		} else {
 			log.Info("Applied migrations:")
 			for _, migration := range applied {
+				if migration.Dirty {
+					log.Warnf("  ✗ %s - %s (dirty: fix the schema manually, then run -action=force)", migration.Version, migration.Name)
+					continue
+				}
 				log.Infof("  ✓ %s - %s (applied: %s)", migration.Version, migration.Name, *migration.AppliedAt)
 			}
 		}
 
 	default:
-		log.Fatalf("Unknown action: %s. Use 'up' or 'status'", *action)
+		log.Fatalf("Unknown action: %s. Use 'up', 'down', 'status' or 'force'", *action)
 	}
 }
 
@@ -84,11 +107,26 @@ func showHelp() {
 	fmt.Println("")
 	fmt.Println("Options:")
 	fmt.Println("  -action string")
-	fmt.Println("        Migration action: up, status (default \"up\")")
+	fmt.Println("        Migration action: up, down, status, force (default \"up\")")
+	fmt.Println("  -steps int")
+	fmt.Println("        Number of migrations to apply or revert (down reverts 1 by default)")
+	fmt.Println("  -target string")
+	fmt.Println("        Version to migrate to: up applies through it, down reverts everything")
+	fmt.Println("        newer than it (0 reverts all), force clears its dirty state")
+	fmt.Println("  -not-applied")
+	fmt.Println("        With force: mark the dirty migration as not applied instead of applied")
 	fmt.Println("  -help")
 	fmt.Println("        Show this help message")
 	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  go run services/chat/cmd/migrate/main.go -action=up")
 	fmt.Println("  go run services/chat/cmd/migrate/main.go -action=status")
+	fmt.Println("  go run services/chat/cmd/migrate/main.go -action=down -steps=2")
+	fmt.Println("  go run services/chat/cmd/migrate/main.go -action=down -target=004")
+	fmt.Println("  go run services/chat/cmd/migrate/main.go -action=force -target=005")
+	fmt.Println("")
+	fmt.Println("Migrations run in a transaction unless the file starts with")
+	fmt.Println("\"-- +migrate notransaction\". A migration interrupted outside a transaction leaves")
+	fmt.Println("the database dirty; the tool refuses to run until the schema is fixed manually")
+	fmt.Println("and the state is cleared with -action=force.")
 }
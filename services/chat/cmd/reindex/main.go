@@ -0,0 +1,90 @@
+// File: services/chat/cmd/reindex/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/config"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/logger"
+)
+
+func main() {
+	help := flag.Bool("help", false, "Show help")
+	flag.Parse()
+
+	if *help {
+		showHelp()
+		return
+	}
+
+	// Initialize logger
+	log := logger.New(&logger.Config{
+		Level:       "info",
+		Format:      "text",
+		Environment: "development",
+	})
+
+	index := usecase.NewElasticsearchIndexFromEnv()
+	if index == nil {
+		log.Fatal("SEARCH_URL is not set, there is no search index to rebuild")
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to database
+	dbConfig := database.DefaultConfig(cfg.Database.URL)
+	db, err := database.Connect(dbConfig)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	searchUsecase := usecase.NewSearchUsecase(repository.NewMessageRepository(db), repository.NewChatRepository(db), index)
+
+	// Stop between batches on interrupt, the index keeps what was indexed so far
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Info("Reindexing messages...")
+	started := time.Now()
+	indexed, err := searchUsecase.Reindex(ctx, func(indexed int) {
+		if indexed%10000 == 0 {
+			log.Infof("  %d messages indexed", indexed)
+		}
+	})
+	if err != nil {
+		log.Errorf("Reindex stopped after %d messages: %v", indexed, err)
+		os.Exit(1)
+	}
+	log.Infof("✅ Indexed %d messages in %s", indexed, time.Since(started).Round(time.Second))
+}
+
+func showHelp() {
+	fmt.Println("Chat Service Search Reindex Tool")
+	fmt.Println("")
+	fmt.Println("Creates the message search index if needed and indexes all messages,")
+	fmt.Println("archived ones included. Messages changed meanwhile are updated by the")
+	fmt.Println("process_search_outbox job of the chat service.")
+	fmt.Println("")
+	fmt.Println("Usage:")
+	fmt.Println("  go run services/chat/cmd/reindex/main.go")
+	fmt.Println("")
+	fmt.Println("Environment:")
+	fmt.Println("  SEARCH_URL       Elasticsearch or OpenSearch URL (required)")
+	fmt.Println("  SEARCH_INDEX     Index name (default \"chat-messages\")")
+	fmt.Println("  SEARCH_USERNAME  Basic auth user")
+	fmt.Println("  SEARCH_PASSWORD  Basic auth password")
+}
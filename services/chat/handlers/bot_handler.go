@@ -0,0 +1,546 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// botContextKey is the gin context key of the authenticated bot
+const botContextKey = "bot"
+
+// BotHandler handles HTTP requests for bot management and the bot API
+type BotHandler struct {
+	botUsecase usecase.BotUsecase
+}
+
+// NewBotHandler creates a new bot handler
+func NewBotHandler(botUsecase usecase.BotUsecase) *BotHandler {
+	return &BotHandler{
+		botUsecase: botUsecase,
+	}
+}
+
+// BotAuthMiddleware authenticates bots by API token.
+// The token is read from "Authorization: Bot <token>" or the X-Bot-Token header.
+func (h *BotHandler) BotAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		token := c.GetHeader("X-Bot-Token")
+		if authHeader := c.GetHeader("Authorization"); token == "" && strings.HasPrefix(authHeader, "Bot ") {
+			token = strings.TrimPrefix(authHeader, "Bot ")
+		}
+
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":      "Bot token is required",
+				"request_id": requestID,
+			})
+			c.Abort()
+			return
+		}
+
+		bot, err := h.botUsecase.AuthenticateBot(token)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"error":      err.Error(),
+			}).Warn("Bot authentication failed")
+
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":      "Invalid bot token",
+				"request_id": requestID,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(botContextKey, bot)
+		c.Next()
+	}
+}
+
+// Bot management handlers (JWT authenticated users)
+
+// CreateBot handles bot creation
+func (h *BotHandler) CreateBot(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateBotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for create bot")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	credentials, err := h.botUsecase.CreateBot(userID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to create bot", map[string]interface{}{"user_id": userID})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Bot created successfully. Store the token, it will not be shown again",
+		"credentials": credentials,
+		"request_id":  requestID,
+	})
+}
+
+// GetBots handles getting bots owned by user
+func (h *BotHandler) GetBots(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	bots, err := h.botUsecase.GetUserBots(userID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get bots", map[string]interface{}{"user_id": userID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bots":       bots,
+		"count":      len(bots),
+		"request_id": requestID,
+	})
+}
+
+// GetBot handles getting a single bot
+func (h *BotHandler) GetBot(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	botID, ok := h.parseIDParam(c, requestID, "id", "Invalid bot ID")
+	if !ok {
+		return
+	}
+
+	bot, err := h.botUsecase.GetBot(userID, botID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get bot", map[string]interface{}{"user_id": userID, "bot_id": botID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bot":        bot,
+		"request_id": requestID,
+	})
+}
+
+// UpdateBot handles bot profile and webhook updates
+func (h *BotHandler) UpdateBot(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	botID, ok := h.parseIDParam(c, requestID, "id", "Invalid bot ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateBotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"bot_id":     botID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update bot")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	bot, err := h.botUsecase.UpdateBot(userID, botID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to update bot", map[string]interface{}{"user_id": userID, "bot_id": botID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Bot updated successfully",
+		"bot":        bot,
+		"request_id": requestID,
+	})
+}
+
+// DeleteBot handles bot deletion
+func (h *BotHandler) DeleteBot(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	botID, ok := h.parseIDParam(c, requestID, "id", "Invalid bot ID")
+	if !ok {
+		return
+	}
+
+	if err := h.botUsecase.DeleteBot(userID, botID); err != nil {
+		h.respondError(c, requestID, err, "Failed to delete bot", map[string]interface{}{"user_id": userID, "bot_id": botID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Bot deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// RegenerateToken handles bot token regeneration
+func (h *BotHandler) RegenerateToken(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	botID, ok := h.parseIDParam(c, requestID, "id", "Invalid bot ID")
+	if !ok {
+		return
+	}
+
+	credentials, err := h.botUsecase.RegenerateToken(userID, botID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to regenerate bot token", map[string]interface{}{"user_id": userID, "bot_id": botID})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"bot_id":     botID,
+	}).Info("Bot token regenerated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Bot token regenerated successfully",
+		"credentials": credentials,
+		"request_id":  requestID,
+	})
+}
+
+// GetDeliveries handles getting webhook delivery receipts of a bot
+func (h *BotHandler) GetDeliveries(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	botID, ok := h.parseIDParam(c, requestID, "id", "Invalid bot ID")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	deliveries, total, err := h.botUsecase.GetDeliveries(userID, botID, limit, offset)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get bot deliveries", map[string]interface{}{"user_id": userID, "bot_id": botID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"total":      total,
+		"request_id": requestID,
+	})
+}
+
+// GetChatBots handles getting bots installed in a chat
+func (h *BotHandler) GetChatBots(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseIDParam(c, requestID, "id", "Invalid chat ID")
+	if !ok {
+		return
+	}
+
+	bots, err := h.botUsecase.GetChatBots(userID, chatID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get chat bots", map[string]interface{}{"user_id": userID, "chat_id": chatID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bots":       bots,
+		"count":      len(bots),
+		"request_id": requestID,
+	})
+}
+
+// RemoveChatBot handles removing a bot from a chat
+func (h *BotHandler) RemoveChatBot(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseIDParam(c, requestID, "id", "Invalid chat ID")
+	if !ok {
+		return
+	}
+
+	botID, ok := h.parseIDParam(c, requestID, "botId", "Invalid bot ID")
+	if !ok {
+		return
+	}
+
+	if err := h.botUsecase.RemoveBotFromChat(userID, chatID, botID); err != nil {
+		h.respondError(c, requestID, err, "Failed to remove bot from chat", map[string]interface{}{"user_id": userID, "chat_id": chatID, "bot_id": botID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Bot removed from chat successfully",
+		"request_id": requestID,
+	})
+}
+
+// Bot API handlers (bot token authenticated)
+
+// GetMe handles getting the authenticated bot profile
+func (h *BotHandler) GetMe(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	bot, ok := h.getBot(c, requestID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bot":        bot.ToResponse(),
+		"request_id": requestID,
+	})
+}
+
+// JoinChat handles a bot joining a chat
+func (h *BotHandler) JoinChat(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	bot, ok := h.getBot(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseIDParam(c, requestID, "id", "Invalid chat ID")
+	if !ok {
+		return
+	}
+
+	if err := h.botUsecase.JoinChat(bot, chatID); err != nil {
+		h.respondError(c, requestID, err, "Failed to join chat", map[string]interface{}{"bot_id": bot.ID, "chat_id": chatID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Bot joined chat successfully",
+		"request_id": requestID,
+	})
+}
+
+// LeaveChat handles a bot leaving a chat
+func (h *BotHandler) LeaveChat(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	bot, ok := h.getBot(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseIDParam(c, requestID, "id", "Invalid chat ID")
+	if !ok {
+		return
+	}
+
+	if err := h.botUsecase.LeaveChat(bot, chatID); err != nil {
+		h.respondError(c, requestID, err, "Failed to leave chat", map[string]interface{}{"bot_id": bot.ID, "chat_id": chatID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Bot left chat successfully",
+		"request_id": requestID,
+	})
+}
+
+// SendMessage handles posting a message from a bot
+func (h *BotHandler) SendMessage(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	bot, ok := h.getBot(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseIDParam(c, requestID, "id", "Invalid chat ID")
+	if !ok {
+		return
+	}
+
+	var req models.BotMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"bot_id":     bot.ID,
+			"chat_id":    chatID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for bot message")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	message, err := h.botUsecase.SendMessage(bot, chatID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to send message", map[string]interface{}{"bot_id": bot.ID, "chat_id": chatID})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"bot_id":     bot.ID,
+		"chat_id":    chatID,
+		"message_id": message.ID,
+	}).Info("Bot message sent successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Message sent successfully",
+		"data":       message,
+		"request_id": requestID,
+	})
+}
+
+// getUserID extracts user ID from JWT context and responds with 401 if it's missing
+func (h *BotHandler) getUserID(c *gin.Context, requestID string) (uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// getBot extracts the authenticated bot from context
+func (h *BotHandler) getBot(c *gin.Context, requestID string) (*models.Bot, bool) {
+	value, exists := c.Get(botContextKey)
+	bot, ok := value.(*models.Bot)
+	if !exists || !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Bot not authenticated",
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+	return bot, true
+}
+
+// parseIDParam parses a numeric URL parameter and responds with 400 if it's invalid
+func (h *BotHandler) parseIDParam(c *gin.Context, requestID, name, errorMessage string) (uint, bool) {
+	idStr := c.Param(name)
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError logs usecase error and maps it to HTTP status
+func (h *BotHandler) respondError(c *gin.Context, requestID string, err error, defaultMessage string, fields map[string]interface{}) {
+	fields["request_id"] = requestID
+	fields["error"] = err.Error()
+	logger.WithFields(fields).Error(defaultMessage)
+
+	statusCode := http.StatusInternalServerError
+	errorMessage := defaultMessage
+
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "insufficient permissions"), strings.Contains(err.Error(), "not a member"):
+		statusCode = http.StatusForbidden
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "already in this chat"):
+		statusCode = http.StatusConflict
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "cannot join private"), strings.Contains(err.Error(), "same chat"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
@@ -7,8 +7,12 @@ import (
 
 	"tachyon-messenger/services/chat/models"
 	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -47,24 +51,13 @@ func (h *ChatHandler) GetChats(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	// Parse filter, sorting and pagination parameters
+	page, ok := bindChatListParams(c, requestID, userID, models.ChatListOptions)
+	if !ok {
+		return
 	}
 
-	chats, err := h.chatUsecase.GetUserChats(userID, limit, offset)
+	chats, err := h.chatUsecase.GetUserChats(userID, page)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -86,11 +79,54 @@ func (h *ChatHandler) GetChats(c *gin.Context) {
 	}).Info("User chats retrieved successfully")
 
 	c.JSON(http.StatusOK, gin.H{
-		"chats":      chats.Chats,
-		"total":      chats.Total,
-		"limit":      chats.Limit,
-		"offset":     chats.Offset,
-		"request_id": requestID,
+		"chats":       chats.Chats,
+		"total":       chats.Total,
+		"limit":       chats.Limit,
+		"offset":      chats.Offset,
+		"next_cursor": chats.NextCursor,
+		"request_id":  requestID,
+	})
+}
+
+// GetUnreadCounts handles getting unread message counts of all user's chats
+// GET /api/v1/chats/unread-counts
+func (h *ChatHandler) GetUnreadCounts(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	counts, err := h.chatUsecase.GetUnreadCounts(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get unread counts")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get unread counts",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chats":        counts.Chats,
+		"unread_count": counts.Total,
+		"request_id":   requestID,
 	})
 }
 
@@ -122,8 +158,8 @@ func (h *ChatHandler) CreateChat(c *gin.Context) {
 		}).Warn("Invalid request body for create chat")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -293,8 +329,8 @@ func (h *ChatHandler) UpdateChat(c *gin.Context) {
 		}).Warn("Invalid request body for update chat")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -416,6 +452,141 @@ func (h *ChatHandler) DeleteChat(c *gin.Context) {
 	})
 }
 
+// GetDeletedChats handles getting chats deleted by the user that can be restored
+// GET /api/v1/chats/trash
+func (h *ChatHandler) GetDeletedChats(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse filter, sorting and pagination parameters
+	page, ok := bindChatListParams(c, requestID, userID, models.ChatTrashListOptions)
+	if !ok {
+		return
+	}
+
+	chats, err := h.chatUsecase.GetDeletedChats(userID, page)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get deleted chats")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get deleted chats",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"count":      len(chats.Chats),
+	}).Info("Deleted chats retrieved successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"chats":       chats.Chats,
+		"total":       chats.Total,
+		"limit":       chats.Limit,
+		"offset":      chats.Offset,
+		"next_cursor": chats.NextCursor,
+		"request_id":  requestID,
+	})
+}
+
+// RestoreChat handles restoring a deleted chat from trash
+// POST /api/v1/chats/:id/restore
+func (h *ChatHandler) RestoreChat(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Get chat ID from URL parameter
+	idStr := c.Param("id")
+	chatID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"chat_id":    idStr,
+			"error":      err.Error(),
+		}).Warn("Invalid chat ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid chat ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	chat, err := h.chatUsecase.RestoreChat(userID, uint(chatID))
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"chat_id":    chatID,
+			"error":      err.Error(),
+		}).Error("Failed to restore chat")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to restore chat"
+
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+			errorMessage = "Chat not found in trash"
+		} else if strings.Contains(err.Error(), "only chat owner") {
+			statusCode = http.StatusForbidden
+			errorMessage = "Only chat owner can restore the chat"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"chat_id":    chatID,
+	}).Info("Chat restored successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Chat restored successfully",
+		"chat":       chat,
+		"request_id": requestID,
+	})
+}
+
 // GetChatMembers handles getting chat members
 func (h *ChatHandler) GetChatMembers(c *gin.Context) {
 	requestID := requestid.Get(c)
@@ -538,8 +709,8 @@ func (h *ChatHandler) AddChatMember(c *gin.Context) {
 		}).Warn("Invalid request body for add chat member")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -564,6 +735,9 @@ func (h *ChatHandler) AddChatMember(c *gin.Context) {
 		} else if strings.Contains(err.Error(), "already a member") {
 			statusCode = http.StatusConflict
 			errorMessage = "User is already a member"
+		} else if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
 		}
 
 		c.JSON(statusCode, gin.H{
@@ -741,6 +915,9 @@ func (h *ChatHandler) JoinChat(c *gin.Context) {
 		} else if strings.Contains(err.Error(), "private chat") {
 			statusCode = http.StatusForbidden
 			errorMessage = "Cannot join private chat"
+		} else if strings.Contains(err.Error(), "department chat") {
+			statusCode = http.StatusForbidden
+			errorMessage = "Cannot join department chat of another department"
 		} else if strings.Contains(err.Error(), "maximum member limit") {
 			statusCode = http.StatusForbidden
 			errorMessage = "Chat has reached maximum member limit"
@@ -767,3 +944,208 @@ func (h *ChatHandler) JoinChat(c *gin.Context) {
 		"request_id": requestID,
 	})
 }
+
+// bindChatListParams parses filter, sorting and pagination query parameters of chat lists
+func bindChatListParams(c *gin.Context, requestID string, userID uint, opts *query.Options) (*query.Params, bool) {
+	page, err := query.Parse(c.Request.URL.Query(), opts)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for chat list")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+
+	return page, true
+}
+
+// HandleDepartmentEvent handles a department change published by the user service
+// POST /api/v1/internal/departments/events
+func (h *ChatHandler) HandleDepartmentEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var event sharedmodels.DepartmentEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for department event")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.chatUsecase.HandleDepartmentEvent(&event); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":    requestID,
+			"type":          event.Type,
+			"department_id": event.DepartmentID,
+			"user_id":       event.UserID,
+			"error":         err.Error(),
+		}).Error("Failed to handle department event")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Department event handled",
+		"request_id": requestID,
+	})
+}
+
+// AddUserToChats handles adding a user to chats on behalf of another service
+// POST /api/v1/internal/chats/members
+func (h *ChatHandler) AddUserToChats(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.AddUserToChatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for add user to chats")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	joined, err := h.chatUsecase.AddUserToChats(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    req.UserID,
+			"error":      err.Error(),
+		}).Error("Failed to add user to chats")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"joined_chat_ids": joined,
+		"request_id":      requestID,
+	})
+}
+
+// GetDigestStats handles counting messages of a user for the daily digest of the notification service
+// POST /api/v1/internal/users/digest-stats
+func (h *ChatHandler) GetDigestStats(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req sharedmodels.DigestStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	stats, err := h.chatUsecase.GetDigestStats(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    req.UserID,
+			"error":      err.Error(),
+		}).Error("Failed to get digest stats")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to get digest stats",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats":      stats,
+		"request_id": requestID,
+	})
+}
+
+// MergeUsers handles moving chat data of a duplicate account to the primary account
+// POST /api/v1/internal/users/merge
+func (h *ChatHandler) MergeUsers(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req sharedmodels.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for merge users")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.chatUsecase.MergeUsers(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":        requestID,
+			"primary_user_id":   req.PrimaryUserID,
+			"duplicate_user_id": req.DuplicateUserID,
+			"error":             err.Error(),
+		}).Error("Failed to merge users")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":        requestID,
+		"primary_user_id":   req.PrimaryUserID,
+		"duplicate_user_id": req.DuplicateUserID,
+		"moved":             result.Moved,
+	}).Info("Users merged in chat service")
+
+	c.JSON(http.StatusOK, gin.H{
+		"result":     result,
+		"request_id": requestID,
+	})
+}
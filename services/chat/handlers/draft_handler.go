@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// DraftHandler handles HTTP requests for message drafts
+type DraftHandler struct {
+	draftUsecase usecase.DraftUsecase
+}
+
+// NewDraftHandler creates a new draft handler
+func NewDraftHandler(draftUsecase usecase.DraftUsecase) *DraftHandler {
+	return &DraftHandler{
+		draftUsecase: draftUsecase,
+	}
+}
+
+// GetDrafts handles getting drafts of all the user's chats, used by clients on reconnect
+// GET /api/v1/chats/drafts
+func (h *DraftHandler) GetDrafts(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	drafts, err := h.draftUsecase.GetDrafts(userID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get drafts", map[string]interface{}{"user_id": userID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"drafts":     drafts,
+		"count":      len(drafts),
+		"request_id": requestID,
+	})
+}
+
+// GetDraft handles getting the draft of a chat, draft is null if there is none
+// GET /api/v1/chats/:id/draft
+func (h *DraftHandler) GetDraft(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseChatID(c, requestID)
+	if !ok {
+		return
+	}
+
+	draft, err := h.draftUsecase.GetDraft(userID, chatID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get draft", map[string]interface{}{"user_id": userID, "chat_id": chatID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"draft":      draft,
+		"request_id": requestID,
+	})
+}
+
+// SaveDraft handles saving the draft of a chat, an empty draft clears it
+// PUT /api/v1/chats/:id/draft
+func (h *DraftHandler) SaveDraft(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseChatID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.SaveDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"chat_id":    chatID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for save draft")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	draft, err := h.draftUsecase.SaveDraft(userID, chatID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to save draft", map[string]interface{}{"user_id": userID, "chat_id": chatID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"draft":      draft,
+		"request_id": requestID,
+	})
+}
+
+// DeleteDraft handles clearing the draft of a chat
+// DELETE /api/v1/chats/:id/draft
+func (h *DraftHandler) DeleteDraft(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := h.getUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	chatID, ok := h.parseChatID(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.draftUsecase.DeleteDraft(userID, chatID); err != nil {
+		h.respondError(c, requestID, err, "Failed to delete draft", map[string]interface{}{"user_id": userID, "chat_id": chatID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Draft deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// Helper methods
+
+// getUserID extracts the authenticated user ID and responds with 401 if it's missing
+func (h *DraftHandler) getUserID(c *gin.Context, requestID string) (uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// parseChatID parses the chat ID URL parameter and responds with 400 if it's invalid
+func (h *DraftHandler) parseChatID(c *gin.Context, requestID string) (uint, bool) {
+	chatID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || chatID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid chat ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(chatID), true
+}
+
+// respondError logs usecase error and maps it to HTTP status
+func (h *DraftHandler) respondError(c *gin.Context, requestID string, err error, defaultMessage string, fields map[string]interface{}) {
+	fields["request_id"] = requestID
+	fields["error"] = err.Error()
+
+	statusCode := http.StatusInternalServerError
+	errorMessage := defaultMessage
+
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not a member"):
+		statusCode = http.StatusForbidden
+		errorMessage = "Access denied"
+	default:
+		logger.WithFields(fields).Error(defaultMessage)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetChatMembershipEvents handles getting the membership log of a chat for compliance
+// GET /api/v1/admin/chats/:id/membership-events
+func (h *ChatHandler) GetChatMembershipEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+	adminID, _ := middleware.GetUserIDFromContext(c)
+
+	chatID, ok := parseAdminID(c, requestID, "Invalid chat ID")
+	if !ok {
+		return
+	}
+
+	page, ok := bindChatListParams(c, requestID, adminID, models.MembershipEventListOptions)
+	if !ok {
+		return
+	}
+
+	events, err := h.chatUsecase.GetChatMembershipEvents(chatID, page)
+	if err != nil {
+		respondMembershipError(c, requestID, err, "Failed to get membership events", map[string]interface{}{"chat_id": chatID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":      events.Events,
+		"total":       events.Total,
+		"limit":       events.Limit,
+		"offset":      events.Offset,
+		"next_cursor": events.NextCursor,
+		"request_id":  requestID,
+	})
+}
+
+// GetUserMembershipEvents handles getting the membership log of a user across chats for compliance
+// GET /api/v1/admin/users/:id/membership-events
+func (h *ChatHandler) GetUserMembershipEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+	adminID, _ := middleware.GetUserIDFromContext(c)
+
+	userID, ok := parseAdminID(c, requestID, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	page, ok := bindChatListParams(c, requestID, adminID, models.MembershipEventListOptions)
+	if !ok {
+		return
+	}
+
+	events, err := h.chatUsecase.GetUserMembershipEvents(userID, page)
+	if err != nil {
+		respondMembershipError(c, requestID, err, "Failed to get membership events", map[string]interface{}{"user_id": userID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":      events.Events,
+		"total":       events.Total,
+		"limit":       events.Limit,
+		"offset":      events.Offset,
+		"next_cursor": events.NextCursor,
+		"request_id":  requestID,
+	})
+}
+
+// GetMessageAudience handles getting users who could see a message at a point in time, given in
+// the at query parameter (RFC 3339), when it was sent by default
+// GET /api/v1/admin/messages/:id/audience
+func (h *ChatHandler) GetMessageAudience(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	messageID, ok := parseAdminID(c, requestID, "Invalid message ID")
+	if !ok {
+		return
+	}
+
+	var at time.Time
+	if value := c.Query("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid at parameter, expected RFC 3339 time",
+				"request_id": requestID,
+			})
+			return
+		}
+		at = parsed
+	}
+
+	audience, err := h.chatUsecase.GetMessageAudience(messageID, at)
+	if err != nil {
+		respondMembershipError(c, requestID, err, "Failed to get message audience", map[string]interface{}{"message_id": messageID})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audience":   audience,
+		"request_id": requestID,
+	})
+}
+
+// parseAdminID parses the ID path parameter of an admin endpoint
+func parseAdminID(c *gin.Context, requestID, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      message,
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondMembershipError logs usecase error of a membership log endpoint and maps it to HTTP status
+func respondMembershipError(c *gin.Context, requestID string, err error, defaultMessage string, fields map[string]interface{}) {
+	fields["request_id"] = requestID
+	fields["error"] = err.Error()
+
+	statusCode := http.StatusInternalServerError
+	errorMessage := defaultMessage
+
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = err.Error()
+	}
+
+	if statusCode == http.StatusInternalServerError {
+		logger.WithFields(fields).Error(defaultMessage)
+	} else {
+		logger.WithFields(fields).Warn(defaultMessage)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
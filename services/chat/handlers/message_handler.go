@@ -7,8 +7,10 @@ import (
 
 	"tachyon-messenger/services/chat/models"
 	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -57,8 +59,8 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 		}).Warn("Invalid query parameters for get messages")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid query parameters",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -136,8 +138,8 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 		}).Warn("Invalid request body for send message")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -161,6 +163,9 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 		} else if strings.Contains(err.Error(), "validation failed") {
 			statusCode = http.StatusBadRequest
 			errorMessage = err.Error()
+		} else if strings.Contains(err.Error(), "command /") {
+			statusCode = http.StatusBadGateway
+			errorMessage = err.Error()
 		}
 
 		c.JSON(statusCode, gin.H{
@@ -306,8 +311,8 @@ func (h *MessageHandler) UpdateMessage(c *gin.Context) {
 		}).Warn("Invalid request body for update message")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -334,6 +339,9 @@ func (h *MessageHandler) UpdateMessage(c *gin.Context) {
 		} else if strings.Contains(err.Error(), "deleted message") {
 			statusCode = http.StatusBadRequest
 			errorMessage = "Cannot edit deleted message"
+		} else if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
 		}
 
 		c.JSON(statusCode, gin.H{
@@ -392,7 +400,7 @@ func (h *MessageHandler) DeleteMessage(c *gin.Context) {
 		return
 	}
 
-	err = h.messageUsecase.DeleteMessage(userID, uint(messageID))
+	receipt, err := h.messageUsecase.DeleteMessage(userID, uint(messageID))
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -425,6 +433,16 @@ func (h *MessageHandler) DeleteMessage(c *gin.Context) {
 		"message_id": messageID,
 	}).Info("Message deleted successfully")
 
+	// The deletion can be undone until the receipt expires
+	if receipt != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Message deleted successfully",
+			"undo":       receipt,
+			"request_id": requestID,
+		})
+		return
+	}
+
 	c.JSON(http.StatusNoContent, gin.H{
 		"message":    "Message deleted successfully",
 		"request_id": requestID,
@@ -573,8 +591,8 @@ func (h *MessageHandler) AddReaction(c *gin.Context) {
 		}).Warn("Invalid request body for add reaction")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
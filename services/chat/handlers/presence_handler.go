@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// PresenceHandler handles HTTP requests for user presence
+type PresenceHandler struct {
+	presenceUsecase usecase.PresenceUsecase
+}
+
+// NewPresenceHandler creates a new presence handler
+func NewPresenceHandler(presenceUsecase usecase.PresenceUsecase) *PresenceHandler {
+	return &PresenceHandler{
+		presenceUsecase: presenceUsecase,
+	}
+}
+
+// GetPresenceBatch handles getting presence of several users at once, used by clients rendering
+// member lists
+// POST /api/v1/presence/batch
+func (h *PresenceHandler) GetPresenceBatch(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.PresenceBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	presence, err := h.presenceUsecase.GetPresence(&req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		} else {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"user_count": len(req.UserIDs),
+				"error":      err.Error(),
+			}).Error("Failed to get presence")
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to get presence",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"presence":   presence,
+		"count":      len(presence),
+		"request_id": requestID,
+	})
+}
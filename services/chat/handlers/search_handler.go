@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler handles HTTP requests for message search
+type SearchHandler struct {
+	searchUsecase usecase.SearchUsecase
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchUsecase usecase.SearchUsecase) *SearchHandler {
+	return &SearchHandler{
+		searchUsecase: searchUsecase,
+	}
+}
+
+// SearchMessages handles searching messages in the user's chats
+// GET /api/v1/messages/search?q=...&chat_id=...&sender_id=...&from=...&to=...
+func (h *SearchHandler) SearchMessages(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.SearchMessagesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for search messages")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.searchUsecase.SearchMessages(userID, &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to search messages"
+
+		switch {
+		case strings.Contains(err.Error(), "not a member"):
+			statusCode = http.StatusForbidden
+			errorMessage = "Access denied"
+		case strings.Contains(err.Error(), "validation failed"):
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		default:
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"user_id":    userID,
+				"chat_id":    req.ChatID,
+				"error":      err.Error(),
+			}).Error("Failed to search messages")
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages":   result.Messages,
+		"total":      result.Total,
+		"limit":      result.Limit,
+		"offset":     result.Offset,
+		"source":     result.Source,
+		"request_id": requestID,
+	})
+}
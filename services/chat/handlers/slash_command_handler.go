@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// SlashCommandHandler handles HTTP requests for slash commands
+type SlashCommandHandler struct {
+	commandUsecase usecase.SlashCommandUsecase
+}
+
+// NewSlashCommandHandler creates a new slash command handler
+func NewSlashCommandHandler(commandUsecase usecase.SlashCommandUsecase) *SlashCommandHandler {
+	return &SlashCommandHandler{
+		commandUsecase: commandUsecase,
+	}
+}
+
+// GetCommands handles listing commands available in chats
+// GET /api/v1/chats/commands
+func (h *SlashCommandHandler) GetCommands(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	commands, err := h.commandUsecase.GetCommands()
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get commands")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"commands":   commands,
+		"request_id": requestID,
+	})
+}
+
+// GetCustomCommands handles listing custom commands including inactive ones
+// GET /api/v1/admin/commands
+func (h *SlashCommandHandler) GetCustomCommands(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	commands, err := h.commandUsecase.GetCustomCommands()
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get commands")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"commands":   commands,
+		"request_id": requestID,
+	})
+}
+
+// CreateCommand handles registering a custom webhook-backed command
+// POST /api/v1/admin/commands
+func (h *SlashCommandHandler) CreateCommand(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.CreateSlashCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	credentials, err := h.commandUsecase.CreateCommand(userID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to create command")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"command_id": credentials.Command.ID,
+		"command":    credentials.Command.Name,
+	}).Info("Slash command created successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Command created successfully. Store the webhook secret, it will not be shown again",
+		"credentials": credentials,
+		"request_id":  requestID,
+	})
+}
+
+// UpdateCommand handles updating a custom command
+// PUT /api/v1/admin/commands/:id
+func (h *SlashCommandHandler) UpdateCommand(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	commandID, ok := h.parseCommandID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSlashCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	command, err := h.commandUsecase.UpdateCommand(commandID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to update command")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Command updated successfully",
+		"command":    command,
+		"request_id": requestID,
+	})
+}
+
+// DeleteCommand handles deleting a custom command
+// DELETE /api/v1/admin/commands/:id
+func (h *SlashCommandHandler) DeleteCommand(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	commandID, ok := h.parseCommandID(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.commandUsecase.DeleteCommand(commandID); err != nil {
+		h.respondError(c, requestID, err, "Failed to delete command")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"command_id": commandID,
+	}).Info("Slash command deleted successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Command deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// parseCommandID parses the command ID URL parameter and responds with 400 if it's invalid
+func (h *SlashCommandHandler) parseCommandID(c *gin.Context, requestID string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid command ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError logs usecase error and maps it to HTTP status
+func (h *SlashCommandHandler) respondError(c *gin.Context, requestID string, err error, defaultMessage string) {
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"error":      err.Error(),
+	}).Error(defaultMessage)
+
+	statusCode := http.StatusInternalServerError
+	errorMessage := defaultMessage
+
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "already exists"):
+		statusCode = http.StatusConflict
+		errorMessage = err.Error()
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 
 	"tachyon-messenger/services/chat/usecase"
@@ -19,13 +19,15 @@ import (
 type WebSocketHandler struct {
 	hub            *websocket.Hub
 	messageUsecase usecase.MessageUsecase
+	jwtConfig      *middleware.JWTConfig
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
-func NewWebSocketHandler(hub *websocket.Hub, messageUsecase usecase.MessageUsecase) *WebSocketHandler {
+func NewWebSocketHandler(hub *websocket.Hub, messageUsecase usecase.MessageUsecase, jwtConfig *middleware.JWTConfig) *WebSocketHandler {
 	return &WebSocketHandler{
 		hub:            hub,
 		messageUsecase: messageUsecase,
+		jwtConfig:      jwtConfig,
 	}
 }
 
@@ -34,6 +36,16 @@ func NewWebSocketHandler(hub *websocket.Hub, messageUsecase usecase.MessageUseca
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	requestID := requestid.Get(c)
 
+	// A draining instance is shutting down, clients reconnect to another one
+	if notice := h.hub.DrainNotice(); notice != nil {
+		c.Header("Retry-After", strconv.Itoa(notice.ReconnectAfter))
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":      "Server is restarting, reconnect later",
+			"request_id": requestID,
+		})
+		return
+	}
+
 	// Authenticate user via JWT token
 	// WebSocket может получать токен из query параметра или заголовка
 	var tokenString string
@@ -64,11 +76,8 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
-	// Создаем временный JWT config для валидации
-	jwtConfig := middleware.DefaultJWTConfig(os.Getenv("JWT_SECRET"))
-
 	// Валидируем токен
-	claims, err := middleware.ValidateToken(tokenString, jwtConfig)
+	claims, err := middleware.ValidateToken(tokenString, h.jwtConfig)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -84,6 +93,24 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 
 	userID := claims.UserID
 
+	// Negotiate protocol version and capabilities before the upgrade, so unsupported clients get a plain HTTP error
+	session, err := websocket.Negotiate(c.Request)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Unsupported WebSocket protocol version")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       err.Error(),
+			"min_version": websocket.MinProtocolVersion,
+			"max_version": websocket.CurrentProtocolVersion,
+			"request_id":  requestID,
+		})
+		return
+	}
+
 	// Configure WebSocket upgrader
 	upgrader := gorilla_websocket.Upgrader{
 		ReadBufferSize:  1024,
@@ -94,6 +121,9 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 			return true
 		},
 	}
+	if session.Subprotocol != "" {
+		upgrader.Subprotocols = []string{session.Subprotocol}
+	}
 
 	// Upgrade HTTP connection to WebSocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
@@ -107,14 +137,16 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	}
 
 	// Create new WebSocket client
-	client := websocket.NewClient(conn, h.hub, userID)
+	client := websocket.NewClient(conn, h.hub, userID, session)
 
 	// Add client to hub
 	h.hub.RegisterClient(client)
 
 	logger.WithFields(map[string]interface{}{
-		"request_id": requestID,
-		"user_id":    userID,
+		"request_id":       requestID,
+		"user_id":          userID,
+		"protocol_version": session.Version,
+		"capabilities":     session.CapabilityList(),
 	}).Info("WebSocket client connected and registered")
 
 	// Start client message pumps in separate goroutines
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -17,8 +18,15 @@ import (
 	"tachyon-messenger/services/chat/websocket"
 	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/jobs"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/orgsettings"
+	"tachyon-messenger/shared/quota"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/refs"
+	"tachyon-messenger/shared/undo"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -37,6 +45,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Resolve other services through the registry, endpoints are refreshed in the background
+	registry, err := config.NewRegistryFromEnv(nil)
+	if err != nil {
+		log.Fatalf("Failed to set up service registry: %v", err)
+	}
+	http.DefaultTransport = registry.Transport(http.DefaultTransport)
+	registryCtx, stopRegistry := context.WithCancel(context.Background())
+	defer stopRegistry()
+	go registry.Run(registryCtx)
+
 	log.Info("Starting Chat service...")
 
 	// Connect to database
@@ -54,51 +72,125 @@ func main() {
 	}
 
 	// Run GORM migrations for model sync (ensures all indexes and constraints)
-	if err := db.Migrate(
-		&models.Chat{},
-		&models.ChatMember{},
-		&models.Message{},
-		&models.MessageReaction{},
-		&models.MessageReadReceipt{},
-	); err != nil {
+	if err := db.Migrate(models.Models()...); err != nil {
+		log.Fatalf("Failed to run GORM migrations: %v", err)
+	}
+	if err := db.Migrate(jobs.Models()...); err != nil {
+		log.Fatalf("Failed to run GORM migrations: %v", err)
+	}
+	if err := db.Migrate(undo.Models()...); err != nil {
 		log.Fatalf("Failed to run GORM migrations: %v", err)
 	}
 
 	log.Info("Database connected and migrations completed")
 
+	// Connect to Redis (optional, used for unread counters, WebSocket replay and presence)
+	var unreadCounter *redis.UnreadCounter
+	var replayBuffer *redis.ReplayBuffer
+	var presenceStore *redis.PresenceStore
+	redisClient, err := redis.ConnectRedis(redis.DefaultConfig(cfg.Redis.URL))
+	if err != nil {
+		log.Warnf("Failed to connect to Redis, unread counts will not be cached, WebSocket events not replayed and presence limited to this instance: %v", err)
+	} else {
+		defer redisClient.Close()
+		unreadCounter = redis.NewUnreadCounter(redisClient, 0)
+		replayBuffer = redis.NewReplayBuffer(redisClient, getReplayTTL(), getReplayMaxEvents())
+		presenceStore = redis.NewPresenceStore(redisClient, 0)
+		log.Info("Redis connected successfully")
+	}
+
+	// Per-user quotas of expensive endpoints, shared with other services through Redis when connected
+	quotas := quota.NewFromEnv(redisClient)
+
 	// Set Gin mode based on environment
 	if os.Getenv("ENVIRONMENT") == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Validate request DTOs with shared rules
+	validation.Install()
+
 	// Initialize dependencies
 	chatRepo := repository.NewChatRepository(db)
 	messageRepo := repository.NewMessageRepository(db)
+	botRepo := repository.NewBotRepository(db)
+	draftRepo := repository.NewDraftRepository(db)
+	commandRepo := repository.NewSlashCommandRepository(db)
+
+	// Organization settings from the user service
+	orgSettings := orgsettings.NewClient(registry.BaseURL(config.UserService), 0)
+
+	// Validation of member IDs against the user service
+	userRefs := refs.NewUserValidatorFromEnv(registry.BaseURL(config.UserService))
+
+	// Members of department chats from the user service
+	departments := usecase.NewHTTPDepartmentDirectory(registry.BaseURL(config.UserService))
 
 	// Create JWT config
 	jwtConfig := middleware.DefaultJWTConfig(cfg.JWT.Secret)
 
+	// Network restrictions of admin endpoints
+	adminAccess, err := middleware.LoadAdminAccessConfig()
+	if err != nil {
+		log.Fatalf("Failed to load admin access config: %v", err)
+	}
+
+	// Message deletion is staged for a short window in which it can be undone
+	undoManager := undo.NewManager("chat", db, undo.WindowFromEnv())
+
 	// Initialize usecases
-	chatUsecase := usecase.NewChatUsecase(chatRepo, messageRepo)
-	messageUsecase := usecase.NewMessageUsecase(messageRepo, chatRepo)
+	chatUsecase := usecase.NewChatUsecase(chatRepo, messageRepo, unreadCounter, userRefs, departments)
+	botUsecase := usecase.NewBotUsecase(botRepo, chatRepo, messageRepo, unreadCounter)
+
+	// Slash commands routed to the task, poll and notification services, and custom webhook-backed ones
+	commandUsecase := usecase.NewSlashCommandUsecase(
+		commandRepo,
+		usecase.NewHTTPTaskCreator(registry.BaseURL(config.TaskService)),
+		usecase.NewHTTPPollCreator(registry.BaseURL(config.PollService)),
+		usecase.NewHTTPReminderScheduler(registry.BaseURL(config.NotificationService)),
+		orgSettings,
+	)
+	messageUsecase := usecase.NewMessageUsecase(messageRepo, chatRepo, botUsecase, commandUsecase, unreadCounter, undoManager)
+	draftUsecase := usecase.NewDraftUsecase(draftRepo, chatRepo, messageRepo, getDraftTTL())
+
+	// Full-text search in Elasticsearch or OpenSearch when SEARCH_URL is set, in the database otherwise
+	searchUsecase := usecase.NewSearchUsecase(messageRepo, chatRepo, usecase.NewElasticsearchIndexFromEnv())
+
+	// Schedule background jobs
+	scheduler := jobs.NewScheduler("chat", db, redisClient)
+	registerJobs(scheduler, chatUsecase, messageUsecase, searchUsecase, draftUsecase, undoManager, unreadCounter != nil, orgSettings, log)
+	scheduler.Start()
 
 	// Initialize WebSocket hub С messageUsecase
-	wsHub := websocket.NewHub(messageUsecase)
+	wsHub := websocket.NewHub(messageUsecase, replayBuffer, presenceStore)
 	go wsHub.Run()
 
+	// Presence of users connected to any instance, only to this one without Redis
+	var presenceReader usecase.PresenceReader = wsHub
+	if presenceStore != nil {
+		presenceReader = presenceStore
+	}
+	presenceUsecase := usecase.NewPresenceUsecase(presenceReader, getPresenceCacheTTL())
+
 	// Initialize handlers
 	chatHandler := handlers.NewChatHandler(chatUsecase)
 	messageHandler := handlers.NewMessageHandler(messageUsecase)
-	wsHandler := handlers.NewWebSocketHandler(wsHub, messageUsecase)
+	wsHandler := handlers.NewWebSocketHandler(wsHub, messageUsecase, jwtConfig)
+	botHandler := handlers.NewBotHandler(botUsecase)
+	searchHandler := handlers.NewSearchHandler(searchUsecase)
+	draftHandler := handlers.NewDraftHandler(draftUsecase)
+	commandHandler := handlers.NewSlashCommandHandler(commandUsecase)
+	presenceHandler := handlers.NewPresenceHandler(presenceUsecase)
 
 	// Create Gin router
 	router := gin.New()
 
 	// Setup common middleware
 	middleware.SetupCommonMiddleware(router)
+	router.Use(middleware.BodyLimitMiddleware(middleware.DefaultBodyLimitConfig()))
 
 	// Setup routes
-	setupRoutes(router, chatHandler, messageHandler, wsHandler, jwtConfig)
+	setupRoutes(router, chatHandler, messageHandler, wsHandler, wsHub, botHandler, searchHandler, draftHandler, commandHandler, presenceHandler, undoManager, scheduler, quotas, jwtConfig, adminAccess)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -121,7 +213,8 @@ func main() {
 
 	log.Info("Shutting down Chat service...")
 
-	// Close WebSocket hub
+	// Tell WebSocket clients to reconnect to other instances, then close the hub
+	wsHub.Drain(getWSDrainPeriod(), getWSReconnectAfter())
 	wsHub.Close()
 
 	// Give outstanding requests 30 seconds to complete
@@ -132,35 +225,70 @@ func main() {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Wait for running background jobs
+	scheduler.Stop()
+
 	log.Info("Chat service stopped")
 }
 
 // setupRoutes configures all routes for the chat service
-func setupRoutes(router *gin.Engine, chatHandler *handlers.ChatHandler, messageHandler *handlers.MessageHandler, wsHandler *handlers.WebSocketHandler, jwtConfig *middleware.JWTConfig) {
+func setupRoutes(router *gin.Engine, chatHandler *handlers.ChatHandler, messageHandler *handlers.MessageHandler, wsHandler *handlers.WebSocketHandler, wsHub *websocket.Hub, botHandler *handlers.BotHandler, searchHandler *handlers.SearchHandler, draftHandler *handlers.DraftHandler, commandHandler *handlers.SlashCommandHandler, presenceHandler *handlers.PresenceHandler, undoManager *undo.Manager, scheduler *jobs.Scheduler, quotas *quota.Quotas, jwtConfig *middleware.JWTConfig, adminAccess *middleware.AdminAccessConfig) {
+	// Concurrency limits of route groups, requests over them are shed with 503
+	limits := middleware.NewConcurrencyLimits("chat-service")
+
 	// Health check endpoint
-	router.Any("/health", healthHandler)
+	router.Any("/health", healthHandler(wsHub, limits))
+	router.GET("/metrics", limits.MetricsHandler())
 
-	// WebSocket endpoint БЕЗ JWT middleware (обрабатывает аутентификацию самостоятельно)
+	// WebSocket endpoint БЕЗ JWT middleware (обрабатывает аутентификацию самостоятельно).
+	// Connections are long-lived, so they are not counted against concurrency limits
 	router.GET("/api/v1/ws", wsHandler.HandleWebSocket) // GET /api/v1/ws
 
 	// API v1 routes с JWT middleware
 	v1 := router.Group("/api/v1")
+	v1.Use(limits.Group("api"))
 	v1.Use(middleware.JWTMiddleware(jwtConfig)) // JWT middleware только для этих routes
 	{
 		// Chat routes
 		chats := v1.Group("/chats")
 		{
-			chats.GET("", chatHandler.GetChats)           // GET /api/v1/chats
-			chats.POST("", chatHandler.CreateChat)        // POST /api/v1/chats
-			chats.POST("/:id/join", chatHandler.JoinChat) // POST /api/v1/chats/:id/join
-			chats.GET("/:id", chatHandler.GetChat)        // GET /api/v1/chats/:id
-			chats.PUT("/:id", chatHandler.UpdateChat)     // PUT /api/v1/chats/:id
-			chats.DELETE("/:id", chatHandler.DeleteChat)  // DELETE /api/v1/chats/:id
+			chats.GET("", chatHandler.GetChats)                      // GET /api/v1/chats
+			chats.GET("/unread-counts", chatHandler.GetUnreadCounts) // GET /api/v1/chats/unread-counts
+			chats.GET("/trash", chatHandler.GetDeletedChats)         // GET /api/v1/chats/trash
+			chats.GET("/drafts", draftHandler.GetDrafts)             // GET /api/v1/chats/drafts
+			chats.GET("/commands", commandHandler.GetCommands)       // GET /api/v1/chats/commands
+			chats.POST("", chatHandler.CreateChat)                   // POST /api/v1/chats
+			chats.POST("/:id/join", chatHandler.JoinChat)            // POST /api/v1/chats/:id/join
+			chats.GET("/:id", chatHandler.GetChat)                   // GET /api/v1/chats/:id
+			chats.PUT("/:id", chatHandler.UpdateChat)                // PUT /api/v1/chats/:id
+			chats.DELETE("/:id", chatHandler.DeleteChat)             // DELETE /api/v1/chats/:id
+			chats.POST("/:id/restore", chatHandler.RestoreChat)      // POST /api/v1/chats/:id/restore
 
 			// Chat members
 			chats.GET("/:id/members", chatHandler.GetChatMembers)              // GET /api/v1/chats/:id/members
 			chats.POST("/:id/members", chatHandler.AddChatMember)              // POST /api/v1/chats/:id/members
 			chats.DELETE("/:id/members/:userId", chatHandler.RemoveChatMember) // DELETE /api/v1/chats/:id/members/:userId
+
+			// Chat bots
+			chats.GET("/:id/bots", botHandler.GetChatBots)             // GET /api/v1/chats/:id/bots
+			chats.DELETE("/:id/bots/:botId", botHandler.RemoveChatBot) // DELETE /api/v1/chats/:id/bots/:botId
+
+			// Message drafts synced across devices
+			chats.GET("/:id/draft", draftHandler.GetDraft)       // GET /api/v1/chats/:id/draft
+			chats.PUT("/:id/draft", draftHandler.SaveDraft)      // PUT /api/v1/chats/:id/draft
+			chats.DELETE("/:id/draft", draftHandler.DeleteDraft) // DELETE /api/v1/chats/:id/draft
+		}
+
+		// Bot management routes
+		bots := v1.Group("/bots")
+		{
+			bots.GET("", botHandler.GetBots)                      // GET /api/v1/bots
+			bots.POST("", botHandler.CreateBot)                   // POST /api/v1/bots
+			bots.GET("/:id", botHandler.GetBot)                   // GET /api/v1/bots/:id
+			bots.PUT("/:id", botHandler.UpdateBot)                // PUT /api/v1/bots/:id
+			bots.DELETE("/:id", botHandler.DeleteBot)             // DELETE /api/v1/bots/:id
+			bots.POST("/:id/token", botHandler.RegenerateToken)   // POST /api/v1/bots/:id/token
+			bots.GET("/:id/deliveries", botHandler.GetDeliveries) // GET /api/v1/bots/:id/deliveries
 		}
 
 		// Message routes
@@ -172,20 +300,292 @@ func setupRoutes(router *gin.Engine, chatHandler *handlers.ChatHandler, messageH
 			messages.PUT("/:id", messageHandler.UpdateMessage)    // PUT /api/v1/messages/:id
 			messages.DELETE("/:id", messageHandler.DeleteMessage) // DELETE /api/v1/messages/:id
 
+			// Full-text search scans message bodies, large pages cost more
+			messages.GET("/search", quotas.Middleware(quota.BucketSearch, quota.PerPage(2, 20)), searchHandler.SearchMessages) // GET /api/v1/messages/search
+
 			// Message by chat
 			messages.GET("/chat/:chatId", messageHandler.GetMessagesByChat) // GET /api/v1/messages/chat/:chatId
 		}
+
+		// Presence routes
+		presence := v1.Group("/presence")
+		{
+			presence.POST("/batch", presenceHandler.GetPresenceBatch) // POST /api/v1/presence/batch
+		}
+
+		// Undo of staged message deletions
+		undo.RegisterRoutes(v1, undoManager) // POST /api/v1/undo/:token
+
+		// Background job management (admin only)
+		admin := v1.Group("/admin")
+		admin.Use(middleware.AdminAccessMiddleware(adminAccess))
+		admin.Use(middleware.RequireAdminRole())
+		jobs.RegisterRoutes(admin, scheduler) // /api/v1/admin/jobs
+
+		// Custom webhook-backed slash commands
+		admin.GET("/commands", commandHandler.GetCustomCommands)    // GET /api/v1/admin/commands
+		admin.POST("/commands", commandHandler.CreateCommand)       // POST /api/v1/admin/commands
+		admin.PUT("/commands/:id", commandHandler.UpdateCommand)    // PUT /api/v1/admin/commands/:id
+		admin.DELETE("/commands/:id", commandHandler.DeleteCommand) // DELETE /api/v1/admin/commands/:id
+
+		// Chat membership log for compliance
+		admin.GET("/chats/:id/membership-events", chatHandler.GetChatMembershipEvents) // GET /api/v1/admin/chats/:id/membership-events
+		admin.GET("/users/:id/membership-events", chatHandler.GetUserMembershipEvents) // GET /api/v1/admin/users/:id/membership-events
+		admin.GET("/messages/:id/audience", chatHandler.GetMessageAudience)            // GET /api/v1/admin/messages/:id/audience
+	}
+
+	// Internal endpoints (for service-to-service communication)
+	internal := router.Group("/api/v1/internal")
+	internal.Use(limits.Group("internal"))
+	{
+		internal.POST("/users/merge", chatHandler.MergeUsers)                   // POST /api/v1/internal/users/merge
+		internal.POST("/departments/events", chatHandler.HandleDepartmentEvent) // POST /api/v1/internal/departments/events
+		internal.POST("/chats/members", chatHandler.AddUserToChats)             // POST /api/v1/internal/chats/members
+		internal.POST("/users/digest-stats", chatHandler.GetDigestStats)        // POST /api/v1/internal/users/digest-stats
+	}
+
+	// Bot API routes, authenticated by bot token instead of JWT
+	botAPI := router.Group("/api/v1/bot")
+	botAPI.Use(limits.Group("bot"))
+	botAPI.Use(botHandler.BotAuthMiddleware())
+	{
+		botAPI.GET("/me", botHandler.GetMe)                        // GET /api/v1/bot/me
+		botAPI.POST("/chats/:id/join", botHandler.JoinChat)        // POST /api/v1/bot/chats/:id/join
+		botAPI.DELETE("/chats/:id/leave", botHandler.LeaveChat)    // DELETE /api/v1/bot/chats/:id/leave
+		botAPI.POST("/chats/:id/messages", botHandler.SendMessage) // POST /api/v1/bot/chats/:id/messages
 	}
 }
 
 // healthHandler handles health check requests
-func healthHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "healthy",
-		"service":   "chat-service",
-		"timestamp": time.Now().UTC(),
-		"version":   "1.0.0",
+func healthHandler(wsHub *websocket.Hub, limits *middleware.ConcurrencyLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// A draining instance fails health checks, so load balancers stop routing to it
+		if wsHub.DrainNotice() != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":    "draining",
+				"service":   "chat-service",
+				"timestamp": time.Now().UTC(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":      "healthy",
+			"service":     "chat-service",
+			"timestamp":   time.Now().UTC(),
+			"version":     "1.0.0",
+			"concurrency": limits.Stats(),
+		})
+	}
+}
+
+// registerJobs schedules background jobs of the chat service
+func registerJobs(scheduler *jobs.Scheduler, chatUsecase usecase.ChatUsecase, messageUsecase usecase.MessageUsecase, searchUsecase usecase.SearchUsecase, draftUsecase usecase.DraftUsecase, undoManager *undo.Manager, cachedUnreadCounts bool, orgSettings *orgsettings.Client, log *logger.Logger) {
+	var chatJobs []jobs.Job
+
+	// Periodically fix drift of cached unread counters
+	if cachedUnreadCounts {
+		chatJobs = append(chatJobs, jobs.Job{
+			Name:     "reconcile_unread_counts",
+			Schedule: "@every " + getUnreadReconcileInterval().String(),
+			Run: func(ctx context.Context) error {
+				reconciled, err := chatUsecase.ReconcileUnreadCounts()
+				if err != nil {
+					return err
+				}
+				if reconciled > 0 {
+					log.WithField("reconciled_count", reconciled).Debug("Reconciled cached unread counts")
+				}
+				return nil
+			},
+		})
+	}
+
+	// Move old messages to the archive
+	if months := getMessageArchiveAfterMonths(); months > 0 {
+		chatJobs = append(chatJobs, jobs.Job{
+			Name:     "archive_messages",
+			Schedule: "@every " + getMessageArchiveInterval().String(),
+			Timeout:  2 * time.Hour,
+			Run: func(ctx context.Context) error {
+				olderThan := time.Now().AddDate(0, -months, 0)
+				archived, err := messageUsecase.ArchiveMessages(olderThan)
+				if err != nil {
+					return fmt.Errorf("archived %d messages: %w", archived, err)
+				}
+				if archived > 0 {
+					log.WithFields(map[string]interface{}{
+						"archived_count": archived,
+						"older_than":     olderThan,
+					}).Info("Archived old messages")
+				}
+				return nil
+			},
+		})
+	}
+
+	// Permanently delete chats that stayed in trash longer than retention
+	chatJobs = append(chatJobs, jobs.Job{
+		Name:     "purge_deleted_chats",
+		Schedule: trashPurgeSchedule,
+		Run: func(ctx context.Context) error {
+			retention := getTrashRetention(orgSettings)
+			jobs.Report(ctx, "retention_days", int(retention.Hours()/24))
+			purged, err := chatUsecase.PurgeDeletedChats(retention)
+			if err != nil {
+				return err
+			}
+			jobs.Report(ctx, "purged_count", purged)
+			if purged > 0 {
+				log.WithField("purged_count", purged).Info("Purged deleted chats")
+			}
+			return nil
+		},
+	})
+
+	// Catch up department chats on missed user service events
+	chatJobs = append(chatJobs, jobs.Job{
+		Name:     "reconcile_department_chats",
+		Schedule: "@hourly",
+		Run: func(ctx context.Context) error {
+			reconciled, err := chatUsecase.ReconcileDepartmentChats()
+			jobs.Report(ctx, "reconciled_count", reconciled)
+			if err != nil {
+				return err
+			}
+			if reconciled > 0 {
+				log.WithField("reconciled_count", reconciled).Info("Reconciled department chat members")
+			}
+			return nil
+		},
+	})
+
+	// Apply message changes from the outbox to the search index
+	chatJobs = append(chatJobs, jobs.Job{
+		Name:     "process_search_outbox",
+		Schedule: "@every 30s",
+		Run: func(ctx context.Context) error {
+			processed, err := searchUsecase.ProcessOutbox(ctx)
+			jobs.Report(ctx, "processed_count", processed)
+			return err
+		},
 	})
+
+	// Commit staged actions whose undo window elapsed
+	chatJobs = append(chatJobs, jobs.Job{
+		Name:     "commit_undo_actions",
+		Schedule: undoCommitSchedule,
+		Run: func(ctx context.Context) error {
+			committed, err := undoManager.CommitDue(time.Now())
+			jobs.Report(ctx, "committed_count", committed)
+			return err
+		},
+	})
+
+	// Remove drafts that were not updated within their TTL
+	chatJobs = append(chatJobs, jobs.Job{
+		Name:     "delete_expired_drafts",
+		Schedule: "@hourly",
+		Run: func(ctx context.Context) error {
+			deleted, err := draftUsecase.DeleteExpiredDrafts()
+			jobs.Report(ctx, "deleted_count", deleted)
+			return err
+		},
+	})
+
+	for _, job := range chatJobs {
+		if err := scheduler.Register(job); err != nil {
+			log.Fatalf("Failed to register background jobs: %v", err)
+		}
+	}
+}
+
+// getUnreadReconcileInterval returns unread counter reconciliation interval from environment or default
+func getUnreadReconcileInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("UNREAD_RECONCILE_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 10 * time.Minute
+}
+
+// getMessageArchiveAfterMonths returns message age in months before archiving, 0 disables archiving
+func getMessageArchiveAfterMonths() int {
+	if months, err := strconv.Atoi(os.Getenv("MESSAGE_ARCHIVE_AFTER_MONTHS")); err == nil {
+		return months
+	}
+	return 6
+}
+
+// getMessageArchiveInterval returns message archiving interval from environment or default
+func getMessageArchiveInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("MESSAGE_ARCHIVE_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 24 * time.Hour
+}
+
+// getDraftTTL returns how long drafts are kept after their last update from environment or default
+func getDraftTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("DRAFT_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return usecase.DefaultDraftTTL
+}
+
+// getWSDrainPeriod returns how long WebSocket clients have to reconnect elsewhere on shutdown from
+// environment or default, 0 closes connections right away
+func getWSDrainPeriod() time.Duration {
+	if period, err := time.ParseDuration(os.Getenv("WS_DRAIN_PERIOD")); err == nil && period >= 0 {
+		return period
+	}
+	return websocket.DefaultDrainPeriod
+}
+
+// getWSReconnectAfter returns the reconnect delay suggested to WebSocket clients on shutdown from
+// environment or default
+func getWSReconnectAfter() time.Duration {
+	if delay, err := time.ParseDuration(os.Getenv("WS_RECONNECT_AFTER")); err == nil && delay >= 0 {
+		return delay
+	}
+	return websocket.DefaultReconnectAfter
+}
+
+// getReplayTTL returns how long unacked WebSocket events are kept for resume from environment or default
+func getReplayTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("WS_REPLAY_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return redis.DefaultReplayTTL
+}
+
+// getPresenceCacheTTL returns how long presence read from the store is reused from environment or default
+func getPresenceCacheTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("PRESENCE_CACHE_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return usecase.DefaultPresenceCacheTTL
+}
+
+// getReplayMaxEvents returns how many unacked WebSocket events are kept per user from environment or default
+func getReplayMaxEvents() int64 {
+	if count, err := strconv.ParseInt(os.Getenv("WS_REPLAY_MAX_EVENTS"), 10, 64); err == nil && count > 0 {
+		return count
+	}
+	return redis.DefaultReplayMaxEvents
+}
+
+// undoCommitSchedule is how often staged actions are checked for an elapsed undo window
+const undoCommitSchedule = "@every 5s"
+
+// trashPurgeSchedule is how often expired records are purged from trash
+const trashPurgeSchedule = "@hourly"
+
+// getTrashRetention returns how long deleted chats stay in trash from environment or organization settings
+func getTrashRetention(orgSettings *orgsettings.Client) time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return orgSettings.Get().Retention.TrashRetention()
 }
 
 // getServerPort returns the server port from environment or default
@@ -0,0 +1,166 @@
+// File: services/chat/markdown/entities.go
+package markdown
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EntityType represents the type of an entity found in message content
+type EntityType string
+
+const (
+	EntityTypeMention  EntityType = "mention"   // @username
+	EntityTypeURL      EntityType = "url"       // Ссылка в тексте
+	EntityTypeTextLink EntityType = "text_link" // Markdown ссылка [текст](url)
+)
+
+// Entity limits
+const (
+	MaxEntities      = 100 // Максимальное количество сущностей в сообщении
+	MaxMentionLength = 64  // Максимальная длина упоминания без "@"
+)
+
+// Entity represents a mention or link inside message content.
+// Offset and Length are measured in characters (runes) of the original content.
+type Entity struct {
+	Type   EntityType `json:"type"`
+	Offset int        `json:"offset"`
+	Length int        `json:"length"`
+	Value  string     `json:"value"`         // Имя пользователя без "@" или ссылка
+	URL    string     `json:"url,omitempty"` // Цель ссылки для text_link
+}
+
+// ExtractEntities finds mentions and links in content.
+// Markdown links are only recognized when format is markdown.
+func ExtractEntities(content string, format Format) []Entity {
+	entities := make([]Entity, 0)
+
+	for i := 0; i < len(content) && len(entities) < MaxEntities; {
+		rest := content[i:]
+		atBoundary := i == 0 || !isWordByte(content[i-1])
+
+		if format == FormatMarkdown && rest[0] == '[' {
+			if label, target, n, ok := parseLink(rest); ok {
+				if href, safe := SafeURL(target); safe {
+					entities = append(entities, Entity{
+						Type:   EntityTypeTextLink,
+						Offset: runeOffset(content, i),
+						Length: utf8.RuneCountInString(rest[:n]),
+						Value:  label,
+						URL:    href,
+					})
+				}
+				i += n
+				continue
+			}
+		}
+
+		if atBoundary && hasURLPrefix(rest) {
+			if n := urlLength(rest); n > 0 {
+				if href, safe := SafeURL(rest[:n]); safe {
+					entities = append(entities, Entity{
+						Type:   EntityTypeURL,
+						Offset: runeOffset(content, i),
+						Length: utf8.RuneCountInString(rest[:n]),
+						Value:  href,
+					})
+				}
+				i += n
+				continue
+			}
+		}
+
+		if atBoundary && rest[0] == '@' {
+			if n := mentionLength(rest[1:]); n > 0 {
+				entities = append(entities, Entity{
+					Type:   EntityTypeMention,
+					Offset: runeOffset(content, i),
+					Length: utf8.RuneCountInString(rest[:n+1]),
+					Value:  rest[1 : n+1],
+				})
+				i += n + 1
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		i += size
+	}
+
+	return entities
+}
+
+// Mentions returns unique usernames mentioned in entities
+func Mentions(entities []Entity) []string {
+	seen := make(map[string]bool)
+	mentions := make([]string, 0)
+	for _, entity := range entities {
+		if entity.Type != EntityTypeMention {
+			continue
+		}
+		key := strings.ToLower(entity.Value)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		mentions = append(mentions, entity.Value)
+	}
+	return mentions
+}
+
+// hasURLPrefix checks if text starts with a link scheme recognized in plain text
+func hasURLPrefix(text string) bool {
+	lower := strings.ToLower(text[:min(len(text), 8)])
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// urlLength returns length in bytes of the URL at the start of text.
+// Trailing punctuation is not considered part of the URL.
+func urlLength(text string) int {
+	end := 0
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if unicode.IsSpace(r) || r == '<' || r == '>' || r == '"' || r == '`' {
+			break
+		}
+		end += size
+	}
+
+	for end > 0 && strings.ContainsRune(".,;:!?)'*_~", rune(text[end-1])) {
+		end--
+	}
+
+	if end > MaxURLLength {
+		return 0
+	}
+	return end
+}
+
+// mentionLength returns length in bytes of the username at the start of text
+func mentionLength(text string) int {
+	end := 0
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '-' {
+			break
+		}
+		end += size
+	}
+
+	// Trailing dots belong to the sentence, not the username
+	for end > 0 && (text[end-1] == '.' || text[end-1] == '-') {
+		end--
+	}
+
+	if end == 0 || utf8.RuneCountInString(text[:end]) > MaxMentionLength {
+		return 0
+	}
+	return end
+}
+
+// runeOffset converts byte offset to rune offset
+func runeOffset(text string, byteOffset int) int {
+	return utf8.RuneCountInString(text[:byteOffset])
+}
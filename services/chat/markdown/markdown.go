@@ -0,0 +1,279 @@
+// File: services/chat/markdown/markdown.go
+package markdown
+
+import (
+	"strings"
+)
+
+// Format represents the markup format of message content
+type Format string
+
+const (
+	FormatPlain    Format = "plain"
+	FormatMarkdown Format = "markdown"
+)
+
+// Limits applied to message content
+const (
+	MaxRenderedLength = 30000 // Максимальная длина HTML после рендеринга
+	MaxURLLength      = 2048  // Максимальная длина ссылки
+)
+
+// IsValidFormat checks if format is supported
+func IsValidFormat(format Format) bool {
+	return format == FormatPlain || format == FormatMarkdown
+}
+
+// Render converts markdown to HTML.
+//
+// Raw HTML in the source is never passed through: all text is escaped and only
+// a fixed set of tags is emitted (p, br, strong, em, del, code, pre, blockquote,
+// ul, ol, li, a). Links are limited to http, https and mailto targets.
+func Render(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	lines := strings.Split(source, "\n")
+
+	var b strings.Builder
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		b.WriteString("<p>")
+		for i, line := range paragraph {
+			if i > 0 {
+				b.WriteString("<br>")
+			}
+			renderInline(&b, line, true)
+		}
+		b.WriteString("</p>")
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+
+		case strings.HasPrefix(trimmed, "```"):
+			// Fenced code block, content is rendered verbatim
+			flushParagraph()
+			var code []string
+			i++
+			for ; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>")
+			b.WriteString(escapeHTML(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			var quote []string
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(t, ">") {
+					i--
+					break
+				}
+				quote = append(quote, strings.TrimSpace(strings.TrimPrefix(t, ">")))
+			}
+			b.WriteString("<blockquote>")
+			for j, q := range quote {
+				if j > 0 {
+					b.WriteString("<br>")
+				}
+				renderInline(&b, q, true)
+			}
+			b.WriteString("</blockquote>")
+
+		case isBulletItem(trimmed) || isOrderedItem(trimmed):
+			flushParagraph()
+			ordered := isOrderedItem(trimmed)
+			tag := "ul"
+			if ordered {
+				tag = "ol"
+			}
+			b.WriteString("<" + tag + ">")
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				var item string
+				var ok bool
+				if ordered {
+					item, ok = orderedItemText(t)
+				} else if isBulletItem(t) {
+					item, ok = t[2:], true
+				}
+				if !ok {
+					i--
+					break
+				}
+				b.WriteString("<li>")
+				renderInline(&b, item, true)
+				b.WriteString("</li>")
+			}
+			b.WriteString("</" + tag + ">")
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+
+	return b.String()
+}
+
+// isBulletItem checks if line is an unordered list item ("- item" or "* item")
+func isBulletItem(line string) bool {
+	return strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")
+}
+
+// isOrderedItem checks if line is an ordered list item ("1. item")
+func isOrderedItem(line string) bool {
+	_, ok := orderedItemText(line)
+	return ok
+}
+
+// orderedItemText returns text of an ordered list item
+func orderedItemText(line string) (string, bool) {
+	i := 0
+	for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+		i++
+	}
+	if i == 0 || i > 9 || !strings.HasPrefix(line[i:], ". ") {
+		return "", false
+	}
+	return line[i+2:], true
+}
+
+// renderInline renders inline markup of a single line.
+// Unmatched delimiters are written as plain text.
+func renderInline(b *strings.Builder, text string, allowLinks bool) {
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end > 0 {
+				b.WriteString("<code>")
+				b.WriteString(escapeHTML(rest[1 : end+1]))
+				b.WriteString("</code>")
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**"):
+			if end := strings.Index(rest[2:], "**"); end > 0 {
+				b.WriteString("<strong>")
+				renderInline(b, rest[2:end+2], allowLinks)
+				b.WriteString("</strong>")
+				i += end + 4
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if end := strings.Index(rest[2:], "~~"); end > 0 {
+				b.WriteString("<del>")
+				renderInline(b, rest[2:end+2], allowLinks)
+				b.WriteString("</del>")
+				i += end + 4
+				continue
+			}
+
+		case rest[0] == '*' || (rest[0] == '_' && (i == 0 || !isWordByte(text[i-1]))):
+			// "_" only opens emphasis at a word boundary so snake_case stays intact
+			if end := findEmphasisEnd(rest, rest[0]); end > 0 {
+				b.WriteString("<em>")
+				renderInline(b, rest[1:end], allowLinks)
+				b.WriteString("</em>")
+				i += end + 1
+				continue
+			}
+
+		case rest[0] == '[' && allowLinks:
+			if label, target, n, ok := parseLink(rest); ok {
+				if href, safe := SafeURL(target); safe {
+					writeLink(b, href, func() { renderInline(b, label, false) })
+				} else {
+					// Unsafe target: keep the label, drop the link
+					renderInline(b, label, false)
+				}
+				i += n
+				continue
+			}
+
+		case allowLinks && (i == 0 || !isWordByte(text[i-1])) && hasURLPrefix(rest):
+			if n := urlLength(rest); n > 0 {
+				if href, safe := SafeURL(rest[:n]); safe {
+					writeLink(b, href, func() { b.WriteString(escapeHTML(rest[:n])) })
+					i += n
+					continue
+				}
+			}
+		}
+
+		b.WriteString(escapeHTML(rest[:1]))
+		i++
+	}
+}
+
+// writeLink writes an anchor with a sanitized href
+func writeLink(b *strings.Builder, href string, body func()) {
+	b.WriteString(`<a href="`)
+	b.WriteString(escapeHTML(href))
+	b.WriteString(`" rel="nofollow noopener noreferrer" target="_blank">`)
+	body()
+	b.WriteString("</a>")
+}
+
+// findEmphasisEnd returns index of the closing emphasis delimiter
+func findEmphasisEnd(text string, delim byte) int {
+	end := strings.IndexByte(text[1:], delim)
+	if end <= 0 {
+		return -1
+	}
+	end++
+	if delim == '_' && end+1 < len(text) && isWordByte(text[end+1]) {
+		return -1
+	}
+	return end
+}
+
+// parseLink parses "[label](target)" and returns the number of consumed bytes
+func parseLink(text string) (label, target string, n int, ok bool) {
+	closeLabel := strings.Index(text, "](")
+	if closeLabel <= 1 {
+		return "", "", 0, false
+	}
+	// Balanced parentheses are allowed inside the target
+	closeTarget, depth := -1, 0
+	for j, c := range text[closeLabel+2:] {
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			if depth == 0 {
+				closeTarget = j
+				break
+			}
+			depth--
+		}
+	}
+	if closeTarget < 0 {
+		return "", "", 0, false
+	}
+	label = text[1:closeLabel]
+	target = text[closeLabel+2 : closeLabel+2+closeTarget]
+	return label, target, closeLabel + 3 + closeTarget, true
+}
+
+// isWordByte checks if b is an ASCII letter, digit or underscore
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
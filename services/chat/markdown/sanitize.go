@@ -0,0 +1,52 @@
+// File: services/chat/markdown/sanitize.go
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// allowedURLSchemes lists schemes that may be used in rendered links
+var allowedURLSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// escapeHTML escapes text so it can be safely placed into HTML content or attributes
+func escapeHTML(text string) string {
+	return html.EscapeString(text)
+}
+
+// SafeURL validates a link target and returns its normalized form.
+// Only absolute http, https and mailto URLs are allowed, everything else
+// (javascript:, data:, relative paths, URLs with control characters) is rejected.
+func SafeURL(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > MaxURLLength {
+		return "", false
+	}
+
+	for _, r := range raw {
+		// Control characters and whitespace are used to smuggle schemes past filters
+		if r < 0x20 || r == 0x7f || r == ' ' {
+			return "", false
+		}
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+
+	if !allowedURLSchemes[strings.ToLower(parsed.Scheme)] {
+		return "", false
+	}
+
+	if parsed.Scheme != "mailto" && parsed.Host == "" {
+		return "", false
+	}
+
+	return parsed.String(), true
+}
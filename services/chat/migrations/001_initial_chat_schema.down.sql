@@ -0,0 +1,4 @@
+-- Revert 001_initial_chat_schema.sql
+-- File: services/chat/migrations/001_initial_chat_schema.down.sql
+
+-- The up migration is empty, the initial schema is created by 002
@@ -0,0 +1,12 @@
+-- Revert initial migration for chat service
+-- File: services/chat/migrations/002_initial_chat_schema.down.sql
+
+DROP TRIGGER IF EXISTS update_messages_updated_at ON messages;
+DROP TRIGGER IF EXISTS update_chat_members_updated_at ON chat_members;
+DROP TRIGGER IF EXISTS update_chats_updated_at ON chats;
+
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS chat_members;
+DROP TABLE IF EXISTS chats;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Revert message read receipts functionality
+-- File: services/chat/migrations/003_add_read_receipts.down.sql
+
+DROP VIEW IF EXISTS unread_message_counts;
+DROP INDEX IF EXISTS idx_unread_messages_by_chat_user;
+DROP TABLE IF EXISTS message_read_receipts;
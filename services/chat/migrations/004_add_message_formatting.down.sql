@@ -0,0 +1,7 @@
+-- Revert markdown formatting and entities of messages
+-- File: services/chat/migrations/004_add_message_formatting.down.sql
+
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS chk_messages_content_format;
+ALTER TABLE messages DROP COLUMN IF EXISTS entities;
+ALTER TABLE messages DROP COLUMN IF EXISTS content_html;
+ALTER TABLE messages DROP COLUMN IF EXISTS content_format;
@@ -0,0 +1,15 @@
+-- Add markdown formatting and entities to messages
+-- File: services/chat/migrations/004_add_message_formatting.sql
+
+-- Content format of the message (plain text or markdown)
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_format VARCHAR(20) NOT NULL DEFAULT 'plain';
+
+-- Sanitized HTML rendered from markdown content
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_html TEXT NULL;
+
+-- Mentions and links extracted from content (JSON array)
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS entities TEXT NULL;
+
+-- Add constraints for enum-like fields
+ALTER TABLE messages ADD CONSTRAINT chk_messages_content_format
+    CHECK (content_format IN ('plain', 'markdown'));
@@ -0,0 +1,11 @@
+-- Revert bot and integration accounts
+-- File: services/chat/migrations/005_add_bots.down.sql
+
+DROP INDEX IF EXISTS idx_messages_bot_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS sender_avatar_url;
+ALTER TABLE messages DROP COLUMN IF EXISTS sender_name;
+ALTER TABLE messages DROP COLUMN IF EXISTS bot_id;
+
+DROP TABLE IF EXISTS bot_event_deliveries;
+DROP TABLE IF EXISTS chat_bots;
+DROP TABLE IF EXISTS bots;
@@ -0,0 +1,78 @@
+-- Add bot and integration accounts
+-- File: services/chat/migrations/005_add_bots.sql
+
+-- Create bots table
+CREATE TABLE IF NOT EXISTS bots (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    display_name VARCHAR(100) NOT NULL DEFAULT '',
+    avatar_url VARCHAR(500) NOT NULL DEFAULT '',
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL DEFAULT '',
+    webhook_url VARCHAR(500) NOT NULL DEFAULT '',
+    webhook_secret VARCHAR(64) NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+-- Create indexes for bots
+CREATE INDEX IF NOT EXISTS idx_bots_owner_id ON bots(owner_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bots_token_hash ON bots(token_hash);
+CREATE INDEX IF NOT EXISTS idx_bots_deleted_at ON bots(deleted_at);
+
+-- Create chat_bots table (bots installed in chats, separate from chat_members
+-- so bots never affect unread counts and presence)
+CREATE TABLE IF NOT EXISTS chat_bots (
+    id SERIAL PRIMARY KEY,
+    chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    bot_id INTEGER NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+    added_by INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+-- Create indexes for chat_bots
+CREATE INDEX IF NOT EXISTS idx_chat_bots_chat_id ON chat_bots(chat_id);
+CREATE INDEX IF NOT EXISTS idx_chat_bots_bot_id ON chat_bots(bot_id);
+CREATE INDEX IF NOT EXISTS idx_chat_bots_deleted_at ON chat_bots(deleted_at);
+
+-- Create bot_event_deliveries table (webhook delivery receipts)
+CREATE TABLE IF NOT EXISTS bot_event_deliveries (
+    id SERIAL PRIMARY KEY,
+    bot_id INTEGER NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+    chat_id INTEGER NOT NULL,
+    message_id INTEGER NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    success BOOLEAN NOT NULL DEFAULT FALSE,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+-- Create indexes for bot_event_deliveries
+CREATE INDEX IF NOT EXISTS idx_bot_event_deliveries_bot_id ON bot_event_deliveries(bot_id);
+CREATE INDEX IF NOT EXISTS idx_bot_event_deliveries_chat_id ON bot_event_deliveries(chat_id);
+CREATE INDEX IF NOT EXISTS idx_bot_event_deliveries_message_id ON bot_event_deliveries(message_id);
+CREATE INDEX IF NOT EXISTS idx_bot_event_deliveries_deleted_at ON bot_event_deliveries(deleted_at);
+
+-- Bot sender fields for messages
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS bot_id INTEGER NULL REFERENCES bots(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS sender_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS sender_avatar_url VARCHAR(500) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_messages_bot_id ON messages(bot_id);
+
+-- Create triggers for updated_at
+CREATE TRIGGER update_bots_updated_at BEFORE UPDATE ON bots
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_chat_bots_updated_at BEFORE UPDATE ON chat_bots
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
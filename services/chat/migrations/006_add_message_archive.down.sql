@@ -0,0 +1,6 @@
+-- Revert cold storage for old messages
+-- File: services/chat/migrations/006_add_message_archive.down.sql
+
+-- Archived messages are dropped; restore them into messages before rolling back
+DROP INDEX IF EXISTS idx_messages_created_id;
+DROP TABLE IF EXISTS archived_messages;
@@ -0,0 +1,59 @@
+-- Add cold storage for old messages
+-- File: services/chat/migrations/006_add_message_archive.sql
+
+-- Create archived_messages table. Rows keep their original message IDs so history
+-- paging by ID continues seamlessly; reactions are stored inline as JSON and read
+-- receipts are not archived.
+CREATE TABLE IF NOT EXISTS archived_messages (
+    id INTEGER PRIMARY KEY,
+    chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    sender_id INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    content_format VARCHAR(20) NOT NULL DEFAULT 'plain',
+    content_html TEXT NULL,
+    entities TEXT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'text',
+    status VARCHAR(20) NOT NULL DEFAULT 'sent',
+    reply_to_id INTEGER NULL,
+    edited_at TIMESTAMP NULL,
+    is_edited BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Bot sender
+    bot_id INTEGER NULL,
+    sender_name VARCHAR(100) NOT NULL DEFAULT '',
+    sender_avatar_url VARCHAR(500) NOT NULL DEFAULT '',
+
+    -- File-related fields
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    file_size BIGINT NOT NULL DEFAULT 0,
+    file_url VARCHAR(500) NOT NULL DEFAULT '',
+    thumbnail_url VARCHAR(500) NOT NULL DEFAULT '',
+    mime_type VARCHAR(100) NOT NULL DEFAULT '',
+
+    -- Location-related fields
+    latitude DECIMAL(10, 8) NULL,
+    longitude DECIMAL(11, 8) NULL,
+
+    -- System message metadata
+    system_data TEXT NOT NULL DEFAULT '',
+
+    -- Reactions at the moment of archiving (JSON array)
+    reactions TEXT NULL,
+
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for archived_messages
+CREATE INDEX IF NOT EXISTS idx_archived_messages_chat_id ON archived_messages(chat_id);
+CREATE INDEX IF NOT EXISTS idx_archived_messages_sender_id ON archived_messages(sender_id);
+CREATE INDEX IF NOT EXISTS idx_archived_messages_archived_at ON archived_messages(archived_at);
+
+-- Composite index for paging archived history
+CREATE INDEX IF NOT EXISTS idx_archived_messages_chat_created_desc
+    ON archived_messages(chat_id, created_at DESC);
+
+-- Composite index for selecting messages to archive
+CREATE INDEX IF NOT EXISTS idx_messages_created_id
+    ON messages(created_at, id) WHERE is_deleted = FALSE;
@@ -0,0 +1,7 @@
+-- Revert group chats provisioned for departments
+-- File: services/chat/migrations/007_add_department_chats.down.sql
+
+-- Department chats stay as regular group chats
+DROP INDEX IF EXISTS idx_chats_department_id;
+ALTER TABLE chat_members DROP COLUMN IF EXISTS managed;
+ALTER TABLE chats DROP COLUMN IF EXISTS department_id;
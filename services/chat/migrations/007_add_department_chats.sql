@@ -0,0 +1,11 @@
+-- Add group chats provisioned for departments of the user service
+-- File: services/chat/migrations/007_add_department_chats.sql
+
+-- Department the chat is provisioned for, NULL for regular chats
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS department_id INTEGER NULL;
+
+-- Membership maintained by department sync; such members join and leave with the department
+ALTER TABLE chat_members ADD COLUMN IF NOT EXISTS managed BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Create index for looking up the chat of a department
+CREATE INDEX IF NOT EXISTS idx_chats_department_id ON chats(department_id) WHERE department_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Revert outbox of message changes for the external search index
+-- File: services/chat/migrations/008_add_message_search_outbox.down.sql
+
+DROP INDEX IF EXISTS idx_message_search_outbox_available;
+DROP TABLE IF EXISTS message_search_outbox;
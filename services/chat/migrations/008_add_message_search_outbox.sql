@@ -0,0 +1,18 @@
+-- Add outbox of message changes for the external search index
+-- File: services/chat/migrations/008_add_message_search_outbox.sql
+
+-- Rows are written in the transaction changing the message and removed once the
+-- search index applied them, so index updates survive restarts and index outages
+CREATE TABLE IF NOT EXISTS message_search_outbox (
+    id SERIAL PRIMARY KEY,
+    message_id INTEGER NOT NULL,
+    operation VARCHAR(10) NOT NULL DEFAULT 'index',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(500) NOT NULL DEFAULT '',
+    available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for picking pending changes in order
+CREATE INDEX IF NOT EXISTS idx_message_search_outbox_available
+    ON message_search_outbox(available_at, id);
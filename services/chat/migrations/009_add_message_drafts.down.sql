@@ -0,0 +1,7 @@
+-- Revert message drafts synced across devices
+-- File: services/chat/migrations/009_add_message_drafts.down.sql
+
+DROP INDEX IF EXISTS idx_message_drafts_expires_at;
+DROP INDEX IF EXISTS idx_message_drafts_user_id;
+DROP INDEX IF EXISTS idx_message_drafts_chat_user;
+DROP TABLE IF EXISTS message_drafts;
@@ -0,0 +1,18 @@
+-- Add message drafts synced across devices
+-- File: services/chat/migrations/009_add_message_drafts.sql
+
+-- One draft per user and chat, cleared when the user sends a message to the chat
+CREATE TABLE IF NOT EXISTS message_drafts (
+    id SERIAL PRIMARY KEY,
+    chat_id INTEGER NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    reply_to_id INTEGER,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Create indexes for drafts
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_drafts_chat_user ON message_drafts(chat_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_message_drafts_user_id ON message_drafts(user_id);
+CREATE INDEX IF NOT EXISTS idx_message_drafts_expires_at ON message_drafts(expires_at);
@@ -0,0 +1,7 @@
+-- Revert the chat membership log kept for compliance
+-- File: services/chat/migrations/010_add_chat_membership_events.down.sql
+
+DROP INDEX IF EXISTS idx_chat_membership_events_actor_id;
+DROP INDEX IF EXISTS idx_chat_membership_events_user_id;
+DROP INDEX IF EXISTS idx_chat_membership_events_chat_created;
+DROP TABLE IF EXISTS chat_membership_events;
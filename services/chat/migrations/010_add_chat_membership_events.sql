@@ -0,0 +1,30 @@
+-- Add the chat membership log kept for compliance
+-- File: services/chat/migrations/010_add_chat_membership_events.sql
+
+-- Append-only log of joins, leaves, removals and role changes; actor_id is NULL for system changes
+CREATE TABLE IF NOT EXISTS chat_membership_events (
+    id SERIAL PRIMARY KEY,
+    chat_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    role VARCHAR(20),
+    previous_role VARCHAR(20),
+    actor_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for the membership log
+CREATE INDEX IF NOT EXISTS idx_chat_membership_events_chat_created ON chat_membership_events(chat_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_chat_membership_events_user_id ON chat_membership_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_chat_membership_events_actor_id ON chat_membership_events(actor_id);
+
+-- Seed the log with existing memberships, so it can be replayed from the start
+INSERT INTO chat_membership_events (chat_id, user_id, type, role, created_at)
+SELECT chat_id, user_id, 'joined', role, joined_at
+FROM chat_members
+WHERE deleted_at IS NULL;
+
+INSERT INTO chat_membership_events (chat_id, user_id, type, role, created_at)
+SELECT chat_id, user_id, 'left', role, left_at
+FROM chat_members
+WHERE deleted_at IS NULL AND is_active = FALSE AND left_at IS NOT NULL AND left_at >= joined_at;
@@ -3,6 +3,7 @@ package migrations
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"path/filepath"
@@ -11,19 +12,41 @@ import (
 
 	"tachyon-messenger/shared/database"
 	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
 )
 
 //go:embed *.sql
 var migrationFiles embed.FS
 
+const (
+	// downSuffix marks the file reverting the migration with the same version
+	downSuffix = ".down.sql"
+
+	// noTransactionDirective in the first line of a migration runs it outside a transaction,
+	// which is required for statements like CREATE INDEX CONCURRENTLY
+	noTransactionDirective = "-- +migrate notransaction"
+
+	// baseVersion as rollback target reverts every migration
+	baseVersion = "0"
+)
+
+// ErrDirty is returned when a previous migration was interrupted and the schema
+// may be partially changed
+var ErrDirty = errors.New("database is in a dirty migration state")
+
 // Migration represents a database migration
 type Migration struct {
-	Version   string
-	Name      string
-	Filename  string
-	SQL       string
-	Applied   bool
-	AppliedAt *string
+	Version       string
+	Name          string
+	Filename      string
+	SQL           string
+	DownSQL       string
+	HasDown       bool
+	NoTransaction bool
+	Applied       bool
+	Dirty         bool
+	AppliedAt     *string
 }
 
 // MigrationManager handles database migrations
@@ -42,34 +65,41 @@ func NewMigrationManager(db *database.DB, log *logger.Logger) *MigrationManager
 
 // RunMigrations executes all pending migrations
 func (m *MigrationManager) RunMigrations() error {
-	m.log.Info("Starting database migrations...")
+	return m.MigrateUp(0, "")
+}
 
-	// Create migrations tracking table
-	if err := m.createMigrationsTable(); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
-	}
+// MigrateUp applies pending migrations in version order. A positive steps limits the number
+// of applied migrations, a non-empty target stops after the migration with that version.
+func (m *MigrationManager) MigrateUp(steps int, target string) error {
+	m.log.Info("Starting database migrations...")
 
-	// Get all migration files
-	migrations, err := m.loadMigrations()
+	migrations, err := m.prepare()
 	if err != nil {
-		return fmt.Errorf("failed to load migrations: %w", err)
+		return err
 	}
 
-	// Check which migrations have been applied
-	if err := m.checkAppliedMigrations(migrations); err != nil {
-		return fmt.Errorf("failed to check applied migrations: %w", err)
+	if target != "" && findMigration(migrations, target) == nil {
+		return fmt.Errorf("unknown target version: %s", target)
 	}
 
 	// Apply pending migrations
 	pendingCount := 0
 	for _, migration := range migrations {
-		if !migration.Applied {
-			m.log.Infof("Applying migration: %s", migration.Name)
-			if err := m.applyMigration(migration); err != nil {
-				return fmt.Errorf("failed to apply migration %s: %w", migration.Name, err)
-			}
-			pendingCount++
+		if target != "" && migration.Version > target {
+			break
+		}
+		if steps > 0 && pendingCount >= steps {
+			break
+		}
+		if migration.Applied {
+			continue
+		}
+
+		m.log.Infof("Applying migration: %s", migration.Name)
+		if err := m.applyMigration(migration); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", migration.Name, err)
 		}
+		pendingCount++
 	}
 
 	if pendingCount == 0 {
@@ -81,6 +111,116 @@ func (m *MigrationManager) RunMigrations() error {
 	return nil
 }
 
+// MigrateDown reverts applied migrations starting from the latest. A positive steps limits
+// the number of reverted migrations, a non-empty target reverts every migration newer than
+// that version. Without either only the latest migration is reverted.
+func (m *MigrationManager) MigrateDown(steps int, target string) error {
+	m.log.Info("Starting database rollback...")
+
+	migrations, err := m.prepare()
+	if err != nil {
+		return err
+	}
+
+	if target != "" && target != baseVersion && findMigration(migrations, target) == nil {
+		return fmt.Errorf("unknown target version: %s", target)
+	}
+	if steps <= 0 && target == "" {
+		steps = 1
+	}
+
+	// Collect migrations to revert, latest first
+	var toRevert []*Migration
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if !migration.Applied {
+			continue
+		}
+		if target != "" && migration.Version <= target {
+			break
+		}
+		if steps > 0 && len(toRevert) >= steps {
+			break
+		}
+		toRevert = append(toRevert, migration)
+	}
+
+	// Check reversibility before changing anything
+	for _, migration := range toRevert {
+		if !migration.HasDown {
+			return fmt.Errorf("migration %s is irreversible: %s not found",
+				migration.Filename, strings.TrimSuffix(migration.Filename, ".sql")+downSuffix)
+		}
+	}
+
+	for _, migration := range toRevert {
+		m.log.Infof("Reverting migration: %s", migration.Name)
+		if err := m.revertMigration(migration); err != nil {
+			return fmt.Errorf("failed to revert migration %s: %w", migration.Name, err)
+		}
+	}
+
+	if len(toRevert) == 0 {
+		m.log.Info("No migrations to revert")
+	} else {
+		m.log.Infof("Reverted %d migrations successfully", len(toRevert))
+	}
+
+	return nil
+}
+
+// ForceVersion clears the dirty state of a migration after the schema was fixed manually.
+// When applied is false the migration is marked as not applied, so it runs again on next up.
+func (m *MigrationManager) ForceVersion(version string, applied bool) error {
+	if err := m.createMigrationsTable(); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	var result *gorm.DB
+	if applied {
+		result = m.db.Exec("UPDATE schema_migrations SET dirty = FALSE WHERE version = ?", version)
+	} else {
+		result = m.db.Exec("DELETE FROM schema_migrations WHERE version = ?", version)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to force migration version: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("migration %s is not recorded", version)
+	}
+
+	return nil
+}
+
+// prepare creates the tracking table, loads migrations with their state and
+// refuses to continue if a previous migration was interrupted
+func (m *MigrationManager) prepare() ([]*Migration, error) {
+	// Create migrations tracking table
+	if err := m.createMigrationsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	// Get all migration files
+	migrations, err := m.loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	// Check which migrations have been applied
+	if err := m.checkAppliedMigrations(migrations); err != nil {
+		return nil, fmt.Errorf("failed to check applied migrations: %w", err)
+	}
+
+	for _, migration := range migrations {
+		if migration.Dirty {
+			return nil, fmt.Errorf("%w at version %s (%s): fix the schema manually, then run -action=force -target=%s",
+				ErrDirty, migration.Version, migration.Name, migration.Version)
+		}
+	}
+
+	return migrations, nil
+}
+
 // createMigrationsTable creates the migrations tracking table
 func (m *MigrationManager) createMigrationsTable() error {
 	query := `
@@ -89,13 +229,15 @@ func (m *MigrationManager) createMigrationsTable() error {
 			name VARCHAR(255) NOT NULL,
 			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 		);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS dirty BOOLEAN NOT NULL DEFAULT FALSE;
 	`
 	return m.db.Exec(query).Error
 }
 
 // loadMigrations loads all migration files from the embedded filesystem
 func (m *MigrationManager) loadMigrations() ([]*Migration, error) {
-	var migrations []*Migration
+	byVersion := make(map[string]*Migration)
+	downs := make(map[string]string)
 
 	err := fs.WalkDir(migrationFiles, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -118,16 +260,22 @@ func (m *MigrationManager) loadMigrations() ([]*Migration, error) {
 		}
 
 		version := parts[0]
-		name := strings.TrimSuffix(parts[1], ".sql")
+		if strings.HasSuffix(filename, downSuffix) {
+			downs[version] = string(content)
+			return nil
+		}
 
-		migration := &Migration{
-			Version:  version,
-			Name:     name,
-			Filename: filename,
-			SQL:      string(content),
+		if existing, exists := byVersion[version]; exists {
+			return fmt.Errorf("duplicate migration version %s: %s and %s", version, existing.Filename, filename)
 		}
 
-		migrations = append(migrations, migration)
+		byVersion[version] = &Migration{
+			Version:       version,
+			Name:          strings.TrimSuffix(parts[1], ".sql"),
+			Filename:      filename,
+			SQL:           string(content),
+			NoTransaction: hasNoTransactionDirective(string(content)),
+		}
 		return nil
 	})
 
@@ -135,6 +283,20 @@ func (m *MigrationManager) loadMigrations() ([]*Migration, error) {
 		return nil, err
 	}
 
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, migration)
+	}
+
+	for version, sql := range downs {
+		migration, exists := byVersion[version]
+		if !exists {
+			return nil, fmt.Errorf("down migration %s has no matching up migration", version)
+		}
+		migration.DownSQL = sql
+		migration.HasDown = true
+	}
+
 	// Sort migrations by version
 	sort.Slice(migrations, func(i, j int) bool {
 		return migrations[i].Version < migrations[j].Version
@@ -145,70 +307,130 @@ func (m *MigrationManager) loadMigrations() ([]*Migration, error) {
 
 // checkAppliedMigrations checks which migrations have already been applied
 func (m *MigrationManager) checkAppliedMigrations(migrations []*Migration) error {
-	rows, err := m.db.Raw("SELECT version, applied_at FROM schema_migrations").Rows()
+	rows, err := m.db.Raw("SELECT version, applied_at, dirty FROM schema_migrations").Rows()
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 
-	appliedMigrations := make(map[string]string)
+	type appliedState struct {
+		appliedAt string
+		dirty     bool
+	}
+
+	appliedMigrations := make(map[string]appliedState)
 	for rows.Next() {
-		var version, appliedAt string
-		if err := rows.Scan(&version, &appliedAt); err != nil {
+		var state appliedState
+		var version string
+		if err := rows.Scan(&version, &state.appliedAt, &state.dirty); err != nil {
 			return err
 		}
-		appliedMigrations[version] = appliedAt
+		appliedMigrations[version] = state
 	}
 
 	// Mark applied migrations
 	for _, migration := range migrations {
-		if appliedAt, exists := appliedMigrations[migration.Version]; exists {
+		if state, exists := appliedMigrations[migration.Version]; exists {
 			migration.Applied = true
-			migration.AppliedAt = &appliedAt
+			migration.Dirty = state.dirty
+			migration.AppliedAt = &state.appliedAt
 		}
 	}
 
 	return nil
 }
 
-// applyMigration applies a single migration
+// applyMigration applies a single migration. The migration is marked dirty before it runs,
+// so an interrupted run is detected; a failed transactional migration is rolled back and
+// the mark is cleared.
 func (m *MigrationManager) applyMigration(migration *Migration) error {
-	// Start transaction
-	tx := m.db.Begin()
-	if tx.Error != nil {
-		return tx.Error
+	if err := m.db.Exec(
+		"INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, TRUE)",
+		migration.Version,
+		migration.Name,
+	).Error; err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
 	}
 
-	// Execute migration SQL
-	if err := tx.Exec(migration.SQL).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to execute migration SQL: %w", err)
+	err := m.execute(migration.SQL, migration.NoTransaction, func(tx *gorm.DB) error {
+		return tx.Exec(
+			"UPDATE schema_migrations SET dirty = FALSE, applied_at = CURRENT_TIMESTAMP WHERE version = ?",
+			migration.Version,
+		).Error
+	})
+	if err != nil {
+		if !migration.NoTransaction {
+			m.clearDirty(migration, "DELETE FROM schema_migrations WHERE version = ?")
+		}
+		return err
 	}
 
-	// Record migration as applied
-	if err := tx.Exec(
-		"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
-		migration.Version,
-		migration.Name,
-	).Error; err != nil {
-		tx.Rollback()
+	return nil
+}
+
+// revertMigration reverts a single migration with the same dirty-state protection as applyMigration
+func (m *MigrationManager) revertMigration(migration *Migration) error {
+	if err := m.db.Exec("UPDATE schema_migrations SET dirty = TRUE WHERE version = ?", migration.Version).Error; err != nil {
 		return fmt.Errorf("failed to record migration: %w", err)
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit migration transaction: %w", err)
+	err := m.execute(migration.DownSQL, migration.NoTransaction, func(tx *gorm.DB) error {
+		return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+	})
+	if err != nil {
+		if !migration.NoTransaction {
+			m.clearDirty(migration, "UPDATE schema_migrations SET dirty = FALSE WHERE version = ?")
+		}
+		return err
 	}
 
 	return nil
 }
 
+// execute runs migration SQL and records the result, inside a single transaction unless disabled
+func (m *MigrationManager) execute(sql string, noTransaction bool, record func(tx *gorm.DB) error) error {
+	if noTransaction {
+		if err := m.db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to execute migration SQL: %w", err)
+		}
+		if err := record(m.db.DB); err != nil {
+			return fmt.Errorf("failed to record migration: %w", err)
+		}
+		return nil
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		// Execute migration SQL
+		if err := tx.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to execute migration SQL: %w", err)
+		}
+
+		// Record migration result
+		if err := record(tx); err != nil {
+			return fmt.Errorf("failed to record migration: %w", err)
+		}
+		return nil
+	})
+}
+
+// clearDirty restores the migration record after a rolled back transaction.
+// If it fails, the dirty mark stays and the next run refuses to start.
+func (m *MigrationManager) clearDirty(migration *Migration, query string) {
+	if err := m.db.Exec(query, migration.Version).Error; err != nil {
+		m.log.Errorf("Failed to clear dirty state of migration %s: %v", migration.Name, err)
+	}
+}
+
 // GetAppliedMigrations returns a list of applied migrations
 func (m *MigrationManager) GetAppliedMigrations() ([]*Migration, error) {
+	if err := m.createMigrationsTable(); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
 	var migrations []*Migration
 
 	rows, err := m.db.Raw(`
-		SELECT version, name, applied_at 
+		SELECT version, name, applied_at, dirty
 		FROM schema_migrations 
 		ORDER BY version
 	`).Rows()
@@ -220,7 +442,7 @@ func (m *MigrationManager) GetAppliedMigrations() ([]*Migration, error) {
 	for rows.Next() {
 		var migration Migration
 		var appliedAt string
-		if err := rows.Scan(&migration.Version, &migration.Name, &appliedAt); err != nil {
+		if err := rows.Scan(&migration.Version, &migration.Name, &appliedAt, &migration.Dirty); err != nil {
 			return nil, err
 		}
 		migration.Applied = true
@@ -230,3 +452,19 @@ func (m *MigrationManager) GetAppliedMigrations() ([]*Migration, error) {
 
 	return migrations, nil
 }
+
+// findMigration returns the migration with the version or nil
+func findMigration(migrations []*Migration, version string) *Migration {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+// hasNoTransactionDirective checks if the migration opts out of transactional application
+func hasNoTransactionDirective(sql string) bool {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(sql), "\n")
+	return strings.EqualFold(strings.TrimSpace(firstLine), noTransactionDirective)
+}
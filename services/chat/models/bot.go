@@ -0,0 +1,185 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/services/chat/markdown"
+	"tachyon-messenger/shared/models"
+)
+
+// BotEventType represents the type of event delivered to bot webhooks
+type BotEventType string
+
+const (
+	BotEventMessageCreated BotEventType = "message.created"
+)
+
+// Bot represents a service account (CI bot, alerting integration) that works with chats through API tokens.
+// Bots are not chat members: they are not counted in unread counts and never appear in presence.
+type Bot struct {
+	models.BaseModel
+	OwnerID       uint       `gorm:"not null;index" json:"owner_id"` // Пользователь, создавший бота
+	Name          string     `gorm:"not null;size:100" json:"name" validate:"required,max=100"`
+	DisplayName   string     `gorm:"size:100" json:"display_name"`         // Имя по умолчанию для сообщений
+	AvatarURL     string     `gorm:"size:500" json:"avatar_url,omitempty"` // Аватар по умолчанию для сообщений
+	Description   string     `gorm:"size:500" json:"description,omitempty"`
+	TokenHash     string     `gorm:"not null;size:64;uniqueIndex" json:"-"` // SHA-256 от API токена
+	TokenPrefix   string     `gorm:"size:16" json:"token_prefix"`           // Начало токена для отображения
+	WebhookURL    string     `gorm:"size:500" json:"webhook_url,omitempty"` // Исходящий вебхук для событий
+	WebhookSecret string     `gorm:"size:64" json:"-"`                      // Секрет для подписи событий
+	IsActive      bool       `gorm:"not null;default:true" json:"is_active"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+
+	// Associations
+	Chats []ChatBot `gorm:"foreignKey:BotID" json:"chats,omitempty"`
+}
+
+// TableName returns the table name for Bot model
+func (Bot) TableName() string {
+	return "bots"
+}
+
+// ChatBot represents a bot installed in a chat
+type ChatBot struct {
+	models.BaseModel
+	ChatID  uint      `gorm:"not null;index" json:"chat_id"`
+	BotID   uint      `gorm:"not null;index" json:"bot_id"`
+	AddedBy uint      `gorm:"not null" json:"added_by"` // Пользователь, от имени которого бот добавлен
+	AddedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"added_at"`
+
+	// Associations
+	Chat *Chat `gorm:"foreignKey:ChatID" json:"chat,omitempty"`
+	Bot  *Bot  `gorm:"foreignKey:BotID" json:"bot,omitempty"`
+}
+
+// TableName returns the table name for ChatBot model
+func (ChatBot) TableName() string {
+	return "chat_bots"
+}
+
+// BotEventDelivery is a delivery receipt of a single webhook event sent to a bot
+type BotEventDelivery struct {
+	models.BaseModel
+	BotID      uint         `gorm:"not null;index" json:"bot_id"`
+	ChatID     uint         `gorm:"not null;index" json:"chat_id"`
+	MessageID  uint         `gorm:"not null;index" json:"message_id"`
+	Event      BotEventType `gorm:"not null;size:50" json:"event"`
+	Success    bool         `gorm:"not null;default:false" json:"success"`
+	StatusCode int          `json:"status_code,omitempty"` // HTTP статус ответа вебхука
+	Error      string       `gorm:"type:text" json:"error,omitempty"`
+	DurationMs int64        `json:"duration_ms"`
+}
+
+// TableName returns the table name for BotEventDelivery model
+func (BotEventDelivery) TableName() string {
+	return "bot_event_deliveries"
+}
+
+// Request/Response structures
+
+// CreateBotRequest represents request for creating a bot
+type CreateBotRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
+	DisplayName string `json:"display_name,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
+	AvatarURL   string `json:"avatar_url,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
+	Description string `json:"description,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	WebhookURL  string `json:"webhook_url,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
+}
+
+// UpdateBotRequest represents request for updating a bot
+type UpdateBotRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100" validate:"omitempty,min=1,max=100"`
+	DisplayName *string `json:"display_name,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
+	AvatarURL   *string `json:"avatar_url,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	WebhookURL  *string `json:"webhook_url,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"` // Пустая строка отключает вебхук
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// BotMessageRequest represents request for posting a message from a bot
+type BotMessageRequest struct {
+	Content       string          `json:"content" binding:"required,max=10000" validate:"required,max=10000"`
+	ContentFormat markdown.Format `json:"content_format,omitempty" binding:"omitempty,oneof=plain markdown" validate:"omitempty,oneof=plain markdown"`
+	ReplyToID     *uint           `json:"reply_to_id,omitempty" validate:"omitempty,min=1"`
+	DisplayName   string          `json:"display_name,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"` // Переопределяет имя бота для сообщения
+	AvatarURL     string          `json:"avatar_url,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
+}
+
+// BotResponse represents bot response
+type BotResponse struct {
+	ID          uint       `json:"id"`
+	OwnerID     uint       `json:"owner_id"`
+	Name        string     `json:"name"`
+	DisplayName string     `json:"display_name"`
+	AvatarURL   string     `json:"avatar_url,omitempty"`
+	Description string     `json:"description,omitempty"`
+	TokenPrefix string     `json:"token_prefix"`
+	WebhookURL  string     `json:"webhook_url,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BotCredentialsResponse is returned once when a bot is created or its token is regenerated
+type BotCredentialsResponse struct {
+	Bot           *BotResponse `json:"bot"`
+	Token         string       `json:"token"`
+	WebhookSecret string       `json:"webhook_secret,omitempty"`
+}
+
+// ChatBotResponse represents bot installed in a chat
+type ChatBotResponse struct {
+	ChatID      uint      `json:"chat_id"`
+	BotID       uint      `json:"bot_id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	AddedBy     uint      `json:"added_by"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// BotEventPayload represents event body sent to bot webhooks
+type BotEventPayload struct {
+	Event     BotEventType     `json:"event"`
+	BotID     uint             `json:"bot_id"`
+	ChatID    uint             `json:"chat_id"`
+	Message   *MessageResponse `json:"message"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// ToResponse converts Bot to BotResponse
+func (b *Bot) ToResponse() *BotResponse {
+	return &BotResponse{
+		ID:          b.ID,
+		OwnerID:     b.OwnerID,
+		Name:        b.Name,
+		DisplayName: b.DisplayName,
+		AvatarURL:   b.AvatarURL,
+		Description: b.Description,
+		TokenPrefix: b.TokenPrefix,
+		WebhookURL:  b.WebhookURL,
+		IsActive:    b.IsActive,
+		LastUsedAt:  b.LastUsedAt,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+	}
+}
+
+// ToResponse converts ChatBot to ChatBotResponse
+func (cb *ChatBot) ToResponse() *ChatBotResponse {
+	response := &ChatBotResponse{
+		ChatID:  cb.ChatID,
+		BotID:   cb.BotID,
+		AddedBy: cb.AddedBy,
+		AddedAt: cb.AddedAt,
+	}
+
+	if cb.Bot != nil {
+		response.Name = cb.Bot.Name
+		response.DisplayName = cb.Bot.DisplayName
+		response.AvatarURL = cb.Bot.AvatarURL
+	}
+
+	return response
+}
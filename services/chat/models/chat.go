@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
 
 	"gorm.io/gorm"
 )
@@ -27,6 +28,7 @@ type Chat struct {
 	Avatar        string     `gorm:"size:500" json:"avatar,omitempty" validate:"omitempty,url,max=500"`
 	IsActive      bool       `gorm:"not null;default:true" json:"is_active"`
 	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	DepartmentID  *uint      `gorm:"index" json:"department_id,omitempty"` // Отдел, для которого чат создан автоматически
 
 	// Associations
 	Members  []ChatMember `gorm:"foreignKey:ChatID" json:"members,omitempty"`
@@ -47,6 +49,7 @@ type ChatMember struct {
 	JoinedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"joined_at"`
 	LeftAt   *time.Time     `json:"left_at,omitempty"`
 	IsActive bool           `gorm:"not null;default:true" json:"is_active"`
+	Managed  bool           `gorm:"not null;default:false" json:"managed"` // Участие ведётся синхронизацией отдела
 
 	// Associations
 	Chat *Chat `gorm:"foreignKey:ChatID" json:"chat,omitempty"`
@@ -75,8 +78,18 @@ func (c *Chat) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsDepartmentChat checks if the chat is provisioned for a department
+func (c *Chat) IsDepartmentChat() bool {
+	return c.DepartmentID != nil
+}
+
 // AfterCreate hook is called after creating a chat
 func (c *Chat) AfterCreate(tx *gorm.DB) error {
+	// Department chats are created by the system and have no owner
+	if c.IsDepartmentChat() {
+		return nil
+	}
+
 	// Add creator as owner
 	member := ChatMember{
 		ChatID:   c.ID,
@@ -112,6 +125,12 @@ type AddChatMemberRequest struct {
 	Role   ChatMemberRole `json:"role,omitempty" binding:"omitempty,oneof=admin member" validate:"omitempty,oneof=admin member"`
 }
 
+// AddUserToChatsRequest represents an internal request to add a user to chats, such as default chats of new users
+type AddUserToChatsRequest struct {
+	UserID  uint   `json:"user_id" binding:"required,min=1" validate:"required,min=1"`
+	ChatIDs []uint `json:"chat_ids" binding:"required,min=1,max=20,dive,min=1" validate:"required,min=1,max=20,dive,min=1"`
+}
+
 // UpdateChatMemberRequest represents request for updating a chat member
 type UpdateChatMemberRequest struct {
 	Role ChatMemberRole `json:"role" binding:"required,oneof=owner admin member" validate:"required,oneof=owner admin member"`
@@ -127,10 +146,12 @@ type ChatResponse struct {
 	Avatar        string               `json:"avatar,omitempty"`
 	IsActive      bool                 `json:"is_active"`
 	LastMessageAt *time.Time           `json:"last_message_at,omitempty"`
+	DepartmentID  *uint                `json:"department_id,omitempty"`
 	MemberCount   int                  `json:"member_count"`
 	Members       []ChatMemberResponse `json:"members,omitempty"`
 	CreatedAt     time.Time            `json:"created_at"`
 	UpdatedAt     time.Time            `json:"updated_at"`
+	DeletedAt     *time.Time           `json:"deleted_at,omitempty"` // Только для чатов в корзине
 }
 
 // ChatMemberResponse represents chat member response
@@ -142,6 +163,7 @@ type ChatMemberResponse struct {
 	JoinedAt time.Time      `json:"joined_at"`
 	LeftAt   *time.Time     `json:"left_at,omitempty"`
 	IsActive bool           `json:"is_active"`
+	Managed  bool           `json:"managed,omitempty"`
 }
 
 // ToResponse converts Chat to ChatResponse
@@ -155,11 +177,16 @@ func (c *Chat) ToResponse() *ChatResponse {
 		Avatar:        c.Avatar,
 		IsActive:      c.IsActive,
 		LastMessageAt: c.LastMessageAt,
+		DepartmentID:  c.DepartmentID,
 		MemberCount:   len(c.Members),
 		CreatedAt:     c.CreatedAt,
 		UpdatedAt:     c.UpdatedAt,
 	}
 
+	if c.DeletedAt.Valid {
+		response.DeletedAt = &c.DeletedAt.Time
+	}
+
 	// Include members if loaded
 	if len(c.Members) > 0 {
 		response.Members = make([]ChatMemberResponse, len(c.Members))
@@ -172,6 +199,7 @@ func (c *Chat) ToResponse() *ChatResponse {
 				JoinedAt: member.JoinedAt,
 				LeftAt:   member.LeftAt,
 				IsActive: member.IsActive,
+				Managed:  member.Managed,
 			}
 		}
 	}
@@ -189,15 +217,52 @@ func (cm *ChatMember) ToResponse() *ChatMemberResponse {
 		JoinedAt: cm.JoinedAt,
 		LeftAt:   cm.LeftAt,
 		IsActive: cm.IsActive,
+		Managed:  cm.Managed,
 	}
 }
 
 // ChatListResponse represents paginated chat list response
 type ChatListResponse struct {
-	Chats  []ChatResponse `json:"chats"`
-	Total  int64          `json:"total"`
-	Limit  int            `json:"limit"`
-	Offset int            `json:"offset"`
+	Chats      []ChatResponse `json:"chats"`
+	Total      int64          `json:"total"`
+	Limit      int            `json:"limit"`
+	Offset     int            `json:"offset"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// ChatListOptions defines pagination, sorting and filtering of chat lists.
+// Chat activity also bumps updated_at, so it is the default sort.
+var ChatListOptions = &query.Options{
+	DefaultSort: "-updated_at",
+	SortFields: map[string]string{
+		"updated_at": "chats.updated_at",
+		"created_at": "chats.created_at",
+		"name":       "chats.name",
+	},
+	FilterFields: map[string]string{
+		"type":       "chats.type",
+		"name":       "chats.name",
+		"creator_id": "chats.creator_id",
+	},
+	TieBreaker: "chats.id",
+}
+
+// ChatTrashListOptions defines pagination, sorting and filtering of deleted chats
+var ChatTrashListOptions = &query.Options{
+	DefaultSort: "-deleted_at",
+	SortFields: map[string]string{
+		"deleted_at": "chats.deleted_at",
+		"created_at": "chats.created_at",
+		"name":       "chats.name",
+	},
+	FilterFields: ChatListOptions.FilterFields,
+	TieBreaker:   "chats.id",
+}
+
+// UnreadCountsResponse represents unread message counts of all user's chats
+type UnreadCountsResponse struct {
+	Chats map[uint]int64 `json:"chats"` // chat ID -> непрочитанные сообщения
+	Total int64          `json:"total"`
 }
 
 type CreateGroupChatRequest struct {
@@ -205,3 +270,22 @@ type CreateGroupChatRequest struct {
 	Description string `json:"description,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
 	MemberIDs   []uint `json:"member_ids" binding:"required,min=1" validate:"required,min=1,dive,min=1"`
 }
+
+// Models returns all database models of the service for migrations
+func Models() []interface{} {
+	return []interface{}{
+		&Chat{},
+		&ChatMember{},
+		&ChatMembershipEvent{},
+		&Message{},
+		&MessageReaction{},
+		&MessageReadReceipt{},
+		&ArchivedMessage{},
+		&Bot{},
+		&ChatBot{},
+		&BotEventDelivery{},
+		&SearchOutboxEntry{},
+		&MessageDraft{},
+		&SlashCommand{},
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// MessageDraft is an unsent message of a user in a chat, synced across the user's devices.
+// Drafts are cleared when the user sends a message to the chat and expire when not updated.
+type MessageDraft struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	ChatID    uint      `gorm:"not null;uniqueIndex:idx_message_drafts_chat_user,priority:1" json:"chat_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_message_drafts_chat_user,priority:2;index" json:"-"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	ReplyToID *uint     `json:"reply_to_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// TableName returns the table name for MessageDraft model
+func (MessageDraft) TableName() string {
+	return "message_drafts"
+}
+
+// SaveDraftRequest represents request for saving the draft of a chat, empty content clears it
+type SaveDraftRequest struct {
+	Content   string `json:"content" binding:"max=10000" validate:"max=10000"`
+	ReplyToID *uint  `json:"reply_to_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/query"
+)
+
+// MembershipEventType represents a change of chat membership
+type MembershipEventType string
+
+const (
+	MembershipEventJoined      MembershipEventType = "joined"
+	MembershipEventLeft        MembershipEventType = "left"
+	MembershipEventRemoved     MembershipEventType = "removed"
+	MembershipEventRoleChanged MembershipEventType = "role_changed"
+)
+
+// ChatMembershipEvent is an entry of the append-only log of chat membership changes kept for
+// compliance. Replaying the log of a chat gives its members at any point in time. Changes made
+// by the system, e.g. department sync, have no actor.
+type ChatMembershipEvent struct {
+	ID           uint                `gorm:"primarykey" json:"id"`
+	ChatID       uint                `gorm:"not null;index:idx_chat_membership_events_chat_created,priority:1" json:"chat_id"`
+	UserID       uint                `gorm:"not null;index" json:"user_id"`
+	Type         MembershipEventType `gorm:"not null;size:20" json:"type"`
+	Role         ChatMemberRole      `gorm:"size:20" json:"role,omitempty"`          // Роль после изменения
+	PreviousRole ChatMemberRole      `gorm:"size:20" json:"previous_role,omitempty"` // Роль до изменения
+	ActorID      *uint               `gorm:"index" json:"actor_id,omitempty"`
+	CreatedAt    time.Time           `gorm:"not null;index:idx_chat_membership_events_chat_created,priority:2" json:"created_at"`
+}
+
+// TableName returns the table name for ChatMembershipEvent model
+func (ChatMembershipEvent) TableName() string {
+	return "chat_membership_events"
+}
+
+// IsMembership reports whether the user is a member of the chat after the event
+func (e *ChatMembershipEvent) IsMembership() bool {
+	return e.Type == MembershipEventJoined || e.Type == MembershipEventRoleChanged
+}
+
+// MembershipEventListResponse represents a page of the chat membership log
+type MembershipEventListResponse struct {
+	Events     []*ChatMembershipEvent `json:"events"`
+	Total      int64                  `json:"total"`
+	Limit      int                    `json:"limit"`
+	Offset     int                    `json:"offset"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+// MembershipEventListOptions defines pagination, sorting and filtering of the chat membership log
+var MembershipEventListOptions = &query.Options{
+	DefaultLimit: 50,
+	MaxLimit:     500,
+	DefaultSort:  "-created_at",
+	SortFields: map[string]string{
+		"created_at": "created_at",
+	},
+	FilterFields: map[string]string{
+		"type":       "type",
+		"chat_id":    "chat_id",
+		"user_id":    "user_id",
+		"actor_id":   "actor_id",
+		"created_at": "created_at",
+	},
+}
+
+// ChatAudienceMember is a user who was a member of a chat at a point in time
+type ChatAudienceMember struct {
+	UserID   uint           `json:"user_id"`
+	Role     ChatMemberRole `json:"role"`
+	JoinedAt time.Time      `json:"joined_at"` // Начало членства, действовавшего в тот момент
+}
+
+// MessageAudienceResponse represents users who could see a message at a point in time
+type MessageAudienceResponse struct {
+	MessageID uint                 `json:"message_id"`
+	ChatID    uint                 `json:"chat_id"`
+	SentAt    time.Time            `json:"sent_at"`
+	At        time.Time            `json:"at"`
+	Members   []ChatAudienceMember `json:"members"`
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"tachyon-messenger/services/chat/markdown"
 	"tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
@@ -44,6 +45,19 @@ type Message struct {
 	IsEdited  bool          `gorm:"not null;default:false" json:"is_edited"`
 	IsDeleted bool          `gorm:"not null;default:false" json:"is_deleted"`
 
+	// Message was loaded from archived_messages and is read-only
+	IsArchived bool `gorm:"-" json:"is_archived,omitempty"`
+
+	// Bot sender, SenderID is 0 for bot messages
+	BotID           *uint  `gorm:"index" json:"bot_id,omitempty"`
+	SenderName      string `gorm:"size:100" json:"sender_name,omitempty"`       // Отображаемое имя бота
+	SenderAvatarURL string `gorm:"size:500" json:"sender_avatar_url,omitempty"` // Аватар бота
+
+	// Formatting and extracted entities
+	ContentFormat markdown.Format   `gorm:"not null;default:'plain';size:20" json:"content_format" validate:"oneof=plain markdown"`
+	ContentHTML   string            `gorm:"type:text" json:"content_html,omitempty"`             // Sanitized HTML, only for markdown messages
+	Entities      []markdown.Entity `gorm:"type:text;serializer:json" json:"entities,omitempty"` // Mentions and links
+
 	// File-related fields for non-text messages
 	FileName     string `gorm:"size:255" json:"file_name,omitempty"`
 	FileSize     int64  `json:"file_size,omitempty"`
@@ -113,6 +127,9 @@ func (m *Message) BeforeCreate(tx *gorm.DB) error {
 	if m.Status == "" {
 		m.Status = MessageStatusSent
 	}
+	if m.ContentFormat == "" {
+		m.ContentFormat = markdown.FormatPlain
+	}
 	return nil
 }
 
@@ -134,6 +151,9 @@ type SendMessageRequest struct {
 	Type      MessageType `json:"type,omitempty" binding:"omitempty,oneof=text image file video audio location system" validate:"omitempty,oneof=text image file video audio location system"`
 	ReplyToID *uint       `json:"reply_to_id,omitempty" validate:"omitempty,min=1"`
 
+	// Content format, plain by default
+	ContentFormat markdown.Format `json:"content_format,omitempty" binding:"omitempty,oneof=plain markdown" validate:"omitempty,oneof=plain markdown"`
+
 	// File-related fields
 	FileName     string `json:"file_name,omitempty" validate:"omitempty,max=255"`
 	FileSize     int64  `json:"file_size,omitempty" validate:"omitempty,min=0"`
@@ -148,7 +168,8 @@ type SendMessageRequest struct {
 
 // UpdateMessageRequest represents request for updating a message
 type UpdateMessageRequest struct {
-	Content string `json:"content" binding:"required,max=10000" validate:"required,max=10000"`
+	Content       string          `json:"content" binding:"required,max=10000" validate:"required,max=10000"`
+	ContentFormat markdown.Format `json:"content_format,omitempty" binding:"omitempty,oneof=plain markdown" validate:"omitempty,oneof=plain markdown"` // Keeps current format if empty
 }
 
 // AddReactionRequest represents request for adding a reaction
@@ -167,29 +188,36 @@ type GetMessagesRequest struct {
 
 // MessageResponse represents message response
 type MessageResponse struct {
-	ID           uint                         `json:"id"`
-	ChatID       uint                         `json:"chat_id"`
-	SenderID     uint                         `json:"sender_id"`
-	Content      string                       `json:"content"`
-	Type         MessageType                  `json:"type"`
-	Status       MessageStatus                `json:"status"`
-	ReplyToID    *uint                        `json:"reply_to_id,omitempty"`
-	EditedAt     *time.Time                   `json:"edited_at,omitempty"`
-	IsEdited     bool                         `json:"is_edited"`
-	IsDeleted    bool                         `json:"is_deleted"`
-	FileName     string                       `json:"file_name,omitempty"`
-	FileSize     int64                        `json:"file_size,omitempty"`
-	FileURL      string                       `json:"file_url,omitempty"`
-	ThumbnailURL string                       `json:"thumbnail_url,omitempty"`
-	MimeType     string                       `json:"mime_type,omitempty"`
-	Latitude     *float64                     `json:"latitude,omitempty"`
-	Longitude    *float64                     `json:"longitude,omitempty"`
-	SystemData   string                       `json:"system_data,omitempty"`
-	Reactions    []MessageReactionResponse    `json:"reactions,omitempty"`
-	ReadReceipts []MessageReadReceiptResponse `json:"read_receipts,omitempty"`
-	ReplyTo      *MessageResponse             `json:"reply_to,omitempty"`
-	CreatedAt    time.Time                    `json:"created_at"`
-	UpdatedAt    time.Time                    `json:"updated_at"`
+	ID              uint                         `json:"id"`
+	ChatID          uint                         `json:"chat_id"`
+	SenderID        uint                         `json:"sender_id"`
+	BotID           *uint                        `json:"bot_id,omitempty"`
+	SenderName      string                       `json:"sender_name,omitempty"`
+	SenderAvatarURL string                       `json:"sender_avatar_url,omitempty"`
+	Content         string                       `json:"content"`
+	ContentFormat   markdown.Format              `json:"content_format"`
+	ContentHTML     string                       `json:"content_html,omitempty"`
+	Entities        []markdown.Entity            `json:"entities,omitempty"`
+	Type            MessageType                  `json:"type"`
+	Status          MessageStatus                `json:"status"`
+	ReplyToID       *uint                        `json:"reply_to_id,omitempty"`
+	EditedAt        *time.Time                   `json:"edited_at,omitempty"`
+	IsEdited        bool                         `json:"is_edited"`
+	IsDeleted       bool                         `json:"is_deleted"`
+	IsArchived      bool                         `json:"is_archived,omitempty"`
+	FileName        string                       `json:"file_name,omitempty"`
+	FileSize        int64                        `json:"file_size,omitempty"`
+	FileURL         string                       `json:"file_url,omitempty"`
+	ThumbnailURL    string                       `json:"thumbnail_url,omitempty"`
+	MimeType        string                       `json:"mime_type,omitempty"`
+	Latitude        *float64                     `json:"latitude,omitempty"`
+	Longitude       *float64                     `json:"longitude,omitempty"`
+	SystemData      string                       `json:"system_data,omitempty"`
+	Reactions       []MessageReactionResponse    `json:"reactions,omitempty"`
+	ReadReceipts    []MessageReadReceiptResponse `json:"read_receipts,omitempty"`
+	ReplyTo         *MessageResponse             `json:"reply_to,omitempty"`
+	CreatedAt       time.Time                    `json:"created_at"`
+	UpdatedAt       time.Time                    `json:"updated_at"`
 }
 
 // MessageReactionResponse represents message reaction response
@@ -212,26 +240,33 @@ type MessageReadReceiptResponse struct {
 // ToResponse converts Message to MessageResponse
 func (m *Message) ToResponse() *MessageResponse {
 	response := &MessageResponse{
-		ID:           m.ID,
-		ChatID:       m.ChatID,
-		SenderID:     m.SenderID,
-		Content:      m.Content,
-		Type:         m.Type,
-		Status:       m.Status,
-		ReplyToID:    m.ReplyToID,
-		EditedAt:     m.EditedAt,
-		IsEdited:     m.IsEdited,
-		IsDeleted:    m.IsDeleted,
-		FileName:     m.FileName,
-		FileSize:     m.FileSize,
-		FileURL:      m.FileURL,
-		ThumbnailURL: m.ThumbnailURL,
-		MimeType:     m.MimeType,
-		Latitude:     m.Latitude,
-		Longitude:    m.Longitude,
-		SystemData:   m.SystemData,
-		CreatedAt:    m.CreatedAt,
-		UpdatedAt:    m.UpdatedAt,
+		ID:              m.ID,
+		ChatID:          m.ChatID,
+		SenderID:        m.SenderID,
+		BotID:           m.BotID,
+		SenderName:      m.SenderName,
+		SenderAvatarURL: m.SenderAvatarURL,
+		Content:         m.Content,
+		ContentFormat:   m.ContentFormat,
+		ContentHTML:     m.ContentHTML,
+		Entities:        m.Entities,
+		Type:            m.Type,
+		Status:          m.Status,
+		ReplyToID:       m.ReplyToID,
+		EditedAt:        m.EditedAt,
+		IsEdited:        m.IsEdited,
+		IsDeleted:       m.IsDeleted,
+		IsArchived:      m.IsArchived,
+		FileName:        m.FileName,
+		FileSize:        m.FileSize,
+		FileURL:         m.FileURL,
+		ThumbnailURL:    m.ThumbnailURL,
+		MimeType:        m.MimeType,
+		Latitude:        m.Latitude,
+		Longitude:       m.Longitude,
+		SystemData:      m.SystemData,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
 	}
 
 	// Include reply-to message if loaded
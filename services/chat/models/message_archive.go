@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/services/chat/markdown"
+	"tachyon-messenger/shared/models"
+)
+
+// ArchivedMessage is a message moved out of the messages table into cold storage.
+// It keeps the original message ID; archived messages are read-only.
+type ArchivedMessage struct {
+	ID        uint          `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	ChatID    uint          `gorm:"not null;index" json:"chat_id"`
+	SenderID  uint          `gorm:"not null;index" json:"sender_id"`
+	Content   string        `gorm:"type:text" json:"content"`
+	Type      MessageType   `gorm:"not null;default:'text';size:20" json:"type"`
+	Status    MessageStatus `gorm:"not null;default:'sent';size:20" json:"status"`
+	ReplyToID *uint         `json:"reply_to_id,omitempty"`
+	EditedAt  *time.Time    `json:"edited_at,omitempty"`
+	IsEdited  bool          `gorm:"not null;default:false" json:"is_edited"`
+
+	// Bot sender
+	BotID           *uint  `json:"bot_id,omitempty"`
+	SenderName      string `gorm:"size:100" json:"sender_name,omitempty"`
+	SenderAvatarURL string `gorm:"size:500" json:"sender_avatar_url,omitempty"`
+
+	// Formatting and extracted entities
+	ContentFormat markdown.Format   `gorm:"not null;default:'plain';size:20" json:"content_format"`
+	ContentHTML   string            `gorm:"type:text" json:"content_html,omitempty"`
+	Entities      []markdown.Entity `gorm:"type:text;serializer:json" json:"entities,omitempty"`
+
+	// File-related fields
+	FileName     string `gorm:"size:255" json:"file_name,omitempty"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	FileURL      string `gorm:"size:500" json:"file_url,omitempty"`
+	ThumbnailURL string `gorm:"size:500" json:"thumbnail_url,omitempty"`
+	MimeType     string `gorm:"size:100" json:"mime_type,omitempty"`
+
+	// Location-related fields
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+
+	// System message metadata
+	SystemData string `gorm:"type:text" json:"system_data,omitempty"`
+
+	// Reactions at the moment of archiving
+	Reactions []MessageReaction `gorm:"type:text;serializer:json" json:"reactions,omitempty"`
+
+	CreatedAt  time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"not null" json:"updated_at"`
+	ArchivedAt time.Time `gorm:"not null;index" json:"archived_at"`
+}
+
+// TableName returns the table name for ArchivedMessage model
+func (ArchivedMessage) TableName() string {
+	return "archived_messages"
+}
+
+// NewArchivedMessage creates an archive copy of a message with its loaded reactions
+func NewArchivedMessage(m *Message, archivedAt time.Time) *ArchivedMessage {
+	archived := &ArchivedMessage{
+		ID:              m.ID,
+		ChatID:          m.ChatID,
+		SenderID:        m.SenderID,
+		Content:         m.Content,
+		Type:            m.Type,
+		Status:          m.Status,
+		ReplyToID:       m.ReplyToID,
+		EditedAt:        m.EditedAt,
+		IsEdited:        m.IsEdited,
+		BotID:           m.BotID,
+		SenderName:      m.SenderName,
+		SenderAvatarURL: m.SenderAvatarURL,
+		ContentFormat:   m.ContentFormat,
+		ContentHTML:     m.ContentHTML,
+		Entities:        m.Entities,
+		FileName:        m.FileName,
+		FileSize:        m.FileSize,
+		FileURL:         m.FileURL,
+		ThumbnailURL:    m.ThumbnailURL,
+		MimeType:        m.MimeType,
+		Latitude:        m.Latitude,
+		Longitude:       m.Longitude,
+		SystemData:      m.SystemData,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		ArchivedAt:      archivedAt,
+	}
+
+	for _, reaction := range m.Reactions {
+		reaction.Message = nil
+		archived.Reactions = append(archived.Reactions, reaction)
+	}
+
+	return archived
+}
+
+// ToMessage converts an archived message back to a read-only Message
+func (a *ArchivedMessage) ToMessage() *Message {
+	return &Message{
+		BaseModel: models.BaseModel{
+			ID:        a.ID,
+			CreatedAt: a.CreatedAt,
+			UpdatedAt: a.UpdatedAt,
+		},
+		ChatID:          a.ChatID,
+		SenderID:        a.SenderID,
+		Content:         a.Content,
+		Type:            a.Type,
+		Status:          a.Status,
+		ReplyToID:       a.ReplyToID,
+		EditedAt:        a.EditedAt,
+		IsEdited:        a.IsEdited,
+		BotID:           a.BotID,
+		SenderName:      a.SenderName,
+		SenderAvatarURL: a.SenderAvatarURL,
+		ContentFormat:   a.ContentFormat,
+		ContentHTML:     a.ContentHTML,
+		Entities:        a.Entities,
+		FileName:        a.FileName,
+		FileSize:        a.FileSize,
+		FileURL:         a.FileURL,
+		ThumbnailURL:    a.ThumbnailURL,
+		MimeType:        a.MimeType,
+		Latitude:        a.Latitude,
+		Longitude:       a.Longitude,
+		SystemData:      a.SystemData,
+		Reactions:       a.Reactions,
+		IsArchived:      true,
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PresenceBatchRequest represents request for presence of several users, e.g. members of a roster
+type PresenceBatchRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required,min=1,max=200,dive,min=1" validate:"required,min=1,max=200,dive,min=1"`
+}
+
+// UserPresence represents whether a user is online, away or offline and when the user was last seen
+type UserPresence struct {
+	UserID   uint       `json:"user_id"`
+	Status   string     `json:"status"`              // online, away, offline
+	LastSeen *time.Time `json:"last_seen,omitempty"` // Отсутствует, если пользователь не подключался
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// SearchOperation represents a change the search index has to apply to a message
+type SearchOperation string
+
+const (
+	SearchOperationIndex  SearchOperation = "index"  // Сообщение создано или изменено
+	SearchOperationDelete SearchOperation = "delete" // Сообщение удалено
+)
+
+// Sources of message search results
+const (
+	SearchSourceIndex    = "index"
+	SearchSourceDatabase = "database"
+)
+
+// SearchOutboxEntry is a pending change of a message for the search index. Entries are
+// written with the message change and removed once the index applied them.
+type SearchOutboxEntry struct {
+	ID          uint            `gorm:"primarykey" json:"id"`
+	MessageID   uint            `gorm:"not null" json:"message_id"`
+	Operation   SearchOperation `gorm:"not null;size:10;default:'index'" json:"operation"`
+	Attempts    int             `gorm:"not null;default:0" json:"attempts"`
+	LastError   string          `gorm:"not null;size:500;default:''" json:"last_error,omitempty"`
+	AvailableAt time.Time       `gorm:"not null;index:idx_message_search_outbox_available,priority:1" json:"available_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// TableName returns the table name for SearchOutboxEntry model
+func (SearchOutboxEntry) TableName() string {
+	return "message_search_outbox"
+}
+
+// SearchMessagesRequest represents request parameters for searching messages of the user's chats
+type SearchMessagesRequest struct {
+	Query    string     `form:"q" validate:"required,min=2,max=200"`
+	ChatID   uint       `form:"chat_id" validate:"omitempty,min=1"`
+	SenderID uint       `form:"sender_id" validate:"omitempty,min=1"`
+	From     *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit    int        `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset   int        `form:"offset" validate:"omitempty,min=0,max=1000"`
+}
+
+// SearchMessagesResponse represents a page of message search results
+type SearchMessagesResponse struct {
+	Messages []*MessageResponse `json:"messages"`
+	Total    int64              `json:"total"`
+	Limit    int                `json:"limit"`
+	Offset   int                `json:"offset"`
+	Source   string             `json:"source"` // index или database, если индекс недоступен
+}
+
+// MessageSearchFilter restricts a message search to chats the user can read
+type MessageSearchFilter struct {
+	Query    string
+	ChatIDs  []uint
+	SenderID uint
+	From     *time.Time
+	To       *time.Time
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// Built-in slash commands
+const (
+	SlashCommandTask   = "task"   // /task "title" creates a task in the task service
+	SlashCommandPoll   = "poll"   // /poll "question" "option" ... creates a poll in the poll service
+	SlashCommandRemind = "remind" // /remind 15m "text" schedules a reminder for the sender
+)
+
+// SlashCommand is a custom command registered by an admin. Invocations are posted to its webhook,
+// which answers with the text of the result message.
+type SlashCommand struct {
+	models.BaseModel
+	Name          string `gorm:"not null;size:32;uniqueIndex" json:"name"` // Без ведущего "/"
+	Description   string `gorm:"size:255" json:"description,omitempty"`
+	Usage         string `gorm:"size:255" json:"usage,omitempty"`      // Подсказка по аргументам
+	WebhookURL    string `gorm:"not null;size:500" json:"webhook_url"` // Вызывается при каждом использовании команды
+	WebhookSecret string `gorm:"not null;size:64" json:"-"`            // Секрет для подписи вызовов
+	CreatedBy     uint   `gorm:"not null" json:"created_by"`           // Администратор, зарегистрировавший команду
+	IsActive      bool   `gorm:"not null;default:true" json:"is_active"`
+}
+
+// TableName returns the table name for SlashCommand model
+func (SlashCommand) TableName() string {
+	return "slash_commands"
+}
+
+// Request/Response structures
+
+// CreateSlashCommandRequest represents request for registering a custom slash command
+type CreateSlashCommandRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=32" validate:"required,min=2,max=32"`
+	Description string `json:"description,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"`
+	Usage       string `json:"usage,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"`
+	WebhookURL  string `json:"webhook_url" binding:"required,url,max=500" validate:"required,url,max=500"`
+}
+
+// UpdateSlashCommandRequest represents request for updating a custom slash command
+type UpdateSlashCommandRequest struct {
+	Description *string `json:"description,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"`
+	Usage       *string `json:"usage,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"`
+	WebhookURL  *string `json:"webhook_url,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// SlashCommandResponse represents a command available in chats
+type SlashCommandResponse struct {
+	ID          uint   `json:"id,omitempty"` // Пусто для встроенных команд
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Usage       string `json:"usage,omitempty"`
+	BuiltIn     bool   `json:"built_in"`
+	WebhookURL  string `json:"webhook_url,omitempty"`
+	IsActive    bool   `json:"is_active"`
+}
+
+// SlashCommandCredentialsResponse is returned once when a custom command is registered
+type SlashCommandCredentialsResponse struct {
+	Command       *SlashCommandResponse `json:"command"`
+	WebhookSecret string                `json:"webhook_secret"`
+}
+
+// SlashCommandResult is the outcome of a command, stored as system data of the result message
+type SlashCommandResult struct {
+	Command     string `json:"command"`
+	UserID      uint   `json:"user_id"` // Пользователь, вызвавший команду
+	Text        string `json:"text"`
+	RelatedType string `json:"related_type,omitempty"` // task, poll, reminder
+	RelatedID   *uint  `json:"related_id,omitempty"`
+}
+
+// SlashCommandInvocation is the payload posted to webhooks of custom commands
+type SlashCommandInvocation struct {
+	Command   string    `json:"command"`
+	Arguments []string  `json:"arguments"`
+	Text      string    `json:"text"` // Текст после имени команды как есть
+	ChatID    uint      `json:"chat_id"`
+	UserID    uint      `json:"user_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ToResponse converts SlashCommand to SlashCommandResponse
+func (c *SlashCommand) ToResponse() *SlashCommandResponse {
+	return &SlashCommandResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Usage:       c.Usage,
+		WebhookURL:  c.WebhookURL,
+		IsActive:    c.IsActive,
+	}
+}
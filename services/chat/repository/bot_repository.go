@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// BotRepository defines the interface for bot data operations
+type BotRepository interface {
+	Create(bot *models.Bot) error
+	GetByID(id uint) (*models.Bot, error)
+	GetByTokenHash(tokenHash string) (*models.Bot, error)
+	GetByOwnerID(ownerID uint) ([]*models.Bot, error)
+	Update(bot *models.Bot) error
+	Delete(id uint) error
+	TouchLastUsed(id uint) error
+
+	// Chat installation operations
+	AddToChat(chatBot *models.ChatBot) error
+	RemoveFromChat(chatID, botID uint) error
+	IsInChat(chatID, botID uint) (bool, error)
+	GetChatBots(chatID uint) ([]*models.ChatBot, error)
+	GetWebhookBotsForChat(chatID uint) ([]*models.Bot, error)
+
+	// Delivery receipts
+	CreateDelivery(delivery *models.BotEventDelivery) error
+	GetDeliveries(botID uint, limit, offset int) ([]*models.BotEventDelivery, int64, error)
+}
+
+// botRepository implements BotRepository interface
+type botRepository struct {
+	db *database.DB
+}
+
+// NewBotRepository creates a new bot repository
+func NewBotRepository(db *database.DB) BotRepository {
+	return &botRepository{
+		db: db,
+	}
+}
+
+// Create creates a new bot
+func (r *botRepository) Create(bot *models.Bot) error {
+	if err := r.db.Create(bot).Error; err != nil {
+		return fmt.Errorf("failed to create bot: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a bot by ID
+func (r *botRepository) GetByID(id uint) (*models.Bot, error) {
+	var bot models.Bot
+	err := r.db.First(&bot, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("bot not found")
+		}
+		return nil, fmt.Errorf("failed to get bot: %w", err)
+	}
+	return &bot, nil
+}
+
+// GetByTokenHash retrieves a bot by hash of its API token
+func (r *botRepository) GetByTokenHash(tokenHash string) (*models.Bot, error) {
+	var bot models.Bot
+	err := r.db.Where("token_hash = ?", tokenHash).First(&bot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("bot not found")
+		}
+		return nil, fmt.Errorf("failed to get bot: %w", err)
+	}
+	return &bot, nil
+}
+
+// GetByOwnerID retrieves all bots created by a user
+func (r *botRepository) GetByOwnerID(ownerID uint) ([]*models.Bot, error) {
+	var bots []*models.Bot
+	err := r.db.Where("owner_id = ?", ownerID).Order("created_at DESC").Find(&bots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bots: %w", err)
+	}
+	return bots, nil
+}
+
+// Update updates an existing bot
+func (r *botRepository) Update(bot *models.Bot) error {
+	result := r.db.Save(bot)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update bot: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("bot not found")
+	}
+	return nil
+}
+
+// Delete soft deletes a bot and removes it from all chats
+func (r *botRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bot_id = ?", id).Delete(&models.ChatBot{}).Error; err != nil {
+			return fmt.Errorf("failed to remove bot from chats: %w", err)
+		}
+
+		result := tx.Delete(&models.Bot{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete bot: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("bot not found")
+		}
+		return nil
+	})
+}
+
+// TouchLastUsed updates last API usage time of a bot
+func (r *botRepository) TouchLastUsed(id uint) error {
+	err := r.db.Model(&models.Bot{}).Where("id = ?", id).UpdateColumn("last_used_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to update bot last used time: %w", err)
+	}
+	return nil
+}
+
+// AddToChat installs a bot in a chat
+func (r *botRepository) AddToChat(chatBot *models.ChatBot) error {
+	isInChat, err := r.IsInChat(chatBot.ChatID, chatBot.BotID)
+	if err != nil {
+		return err
+	}
+	if isInChat {
+		return fmt.Errorf("bot is already in this chat")
+	}
+
+	if chatBot.AddedAt.IsZero() {
+		chatBot.AddedAt = time.Now()
+	}
+
+	if err := r.db.Create(chatBot).Error; err != nil {
+		return fmt.Errorf("failed to add bot to chat: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromChat removes a bot from a chat
+func (r *botRepository) RemoveFromChat(chatID, botID uint) error {
+	result := r.db.Where("chat_id = ? AND bot_id = ?", chatID, botID).Delete(&models.ChatBot{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove bot from chat: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("bot not found in chat")
+	}
+	return nil
+}
+
+// IsInChat checks if a bot is installed in a chat
+func (r *botRepository) IsInChat(chatID, botID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ChatBot{}).
+		Where("chat_id = ? AND bot_id = ?", chatID, botID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check bot in chat: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetChatBots retrieves bots installed in a chat
+func (r *botRepository) GetChatBots(chatID uint) ([]*models.ChatBot, error) {
+	var chatBots []*models.ChatBot
+	err := r.db.Preload("Bot").
+		Where("chat_id = ?", chatID).
+		Order("added_at ASC").
+		Find(&chatBots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat bots: %w", err)
+	}
+	return chatBots, nil
+}
+
+// GetWebhookBotsForChat retrieves active bots in a chat that have an outgoing webhook configured
+func (r *botRepository) GetWebhookBotsForChat(chatID uint) ([]*models.Bot, error) {
+	var bots []*models.Bot
+	err := r.db.
+		Joins("JOIN chat_bots ON chat_bots.bot_id = bots.id AND chat_bots.deleted_at IS NULL").
+		Where("chat_bots.chat_id = ?", chatID).
+		Where("bots.is_active = ? AND bots.webhook_url <> ''", true).
+		Find(&bots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook bots: %w", err)
+	}
+	return bots, nil
+}
+
+// CreateDelivery stores a webhook delivery receipt
+func (r *botRepository) CreateDelivery(delivery *models.BotEventDelivery) error {
+	if err := r.db.Create(delivery).Error; err != nil {
+		return fmt.Errorf("failed to create bot event delivery: %w", err)
+	}
+	return nil
+}
+
+// GetDeliveries retrieves webhook delivery receipts of a bot with pagination
+func (r *botRepository) GetDeliveries(botID uint, limit, offset int) ([]*models.BotEventDelivery, int64, error) {
+	var deliveries []*models.BotEventDelivery
+	var total int64
+
+	query := r.db.Model(&models.BotEventDelivery{}).Where("bot_id = ?", botID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count bot event deliveries: %w", err)
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get bot event deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"tachyon-messenger/services/chat/models"
 	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
 
 	"gorm.io/gorm"
 )
@@ -19,13 +22,21 @@ type ChatRepository interface {
 	Delete(id uint) error
 	Count() (int64, error)
 	GetWithMembers(id uint) (*models.Chat, error)
-	GetUserChats(userID uint, limit, offset int) ([]*models.Chat, int64, error)
+	GetUserChats(userID uint, page *query.Params) ([]*models.Chat, int64, error)
+
+	// Trash operations
+	GetDeletedByID(id uint) (*models.Chat, error)
+	GetDeletedChats(ownerID uint, page *query.Params) ([]*models.Chat, int64, error)
+	Restore(id uint) error
+	PurgeDeleted(deletedBefore time.Time) (int64, error)
 
 	// Chat member operations
 	AddMember(member *models.ChatMember) error
 	RemoveMember(chatID, userID uint) error
 	GetChatMembers(chatID uint) ([]*models.ChatMember, error)
+	GetMemberIDs(chatID uint) ([]uint, error)
 	IsMember(chatID, userID uint) (bool, error)
+	GetReadableChatIDs(userID uint) ([]uint, error)
 	GetMemberRole(chatID, userID uint) (models.ChatMemberRole, error)
 
 	// Access control methods
@@ -33,6 +44,24 @@ type ChatRepository interface {
 	HasWriteAccess(chatID, userID uint) (bool, error)
 	HasAdminAccess(chatID, userID uint) (bool, error)
 	HasOwnerAccess(chatID, userID uint) (bool, error)
+
+	// Department chats
+	GetByDepartmentID(departmentID uint) (*models.Chat, error)
+	MoveDepartmentChat(chatID, departmentID uint) error
+	GetDepartmentChats() ([]*models.Chat, error)
+	DetachDepartmentChat(chatID uint) error
+	AddDepartmentMember(chatID, userID uint) (bool, error)
+	RemoveDepartmentMember(chatID, userID uint) (bool, error)
+	SyncDepartmentMembers(chatID uint, userIDs []uint) ([]uint, []uint, error)
+	IsManagedMember(chatID, userID uint) (bool, error)
+
+	// Membership log
+	RecordMembershipEvents(events ...*models.ChatMembershipEvent) error
+	GetMembershipEvents(chatID, userID uint, page *query.Params) ([]*models.ChatMembershipEvent, int64, error)
+	GetMembersAt(chatID uint, at time.Time) ([]models.ChatAudienceMember, error)
+
+	// Account merge
+	MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error)
 }
 
 // chatRepository implements ChatRepository interface
@@ -138,8 +167,8 @@ func (r *chatRepository) GetWithMembers(id uint) (*models.Chat, error) {
 	return &chat, nil
 }
 
-// GetUserChats retrieves all chats for a user with pagination and sorting
-func (r *chatRepository) GetUserChats(userID uint, limit, offset int) ([]*models.Chat, int64, error) {
+// GetUserChats retrieves all chats for a user with filters, sorting and pagination
+func (r *chatRepository) GetUserChats(userID uint, page *query.Params) ([]*models.Chat, int64, error) {
 	var chats []*models.Chat
 	var total int64
 
@@ -148,13 +177,14 @@ func (r *chatRepository) GetUserChats(userID uint, limit, offset int) ([]*models
 		Joins("JOIN chat_members ON chats.id = chat_members.chat_id").
 		Where("chat_members.user_id = ? AND chat_members.is_active = ?", userID, true).
 		Where("chats.is_active = ?", true).
+		Scopes(page.FilterScope).
 		Count(&total).Error
 
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count user chats: %w", err)
 	}
 
-	// Get chats with members, sorted by last activity
+	// Get chats with members
 	err = r.db.
 		Preload("Members", func(db *gorm.DB) *gorm.DB {
 			return db.Where("is_active = ?", true).Order("role ASC, joined_at ASC")
@@ -162,9 +192,7 @@ func (r *chatRepository) GetUserChats(userID uint, limit, offset int) ([]*models
 		Joins("JOIN chat_members ON chats.id = chat_members.chat_id").
 		Where("chat_members.user_id = ? AND chat_members.is_active = ?", userID, true).
 		Where("chats.is_active = ?", true).
-		Limit(limit).
-		Offset(offset).
-		Order("chats.last_message_at DESC NULLS LAST, chats.updated_at DESC").
+		Scopes(page.FilterScope, page.PageScope).
 		Find(&chats).Error
 
 	if err != nil {
@@ -174,6 +202,108 @@ func (r *chatRepository) GetUserChats(userID uint, limit, offset int) ([]*models
 	return chats, total, nil
 }
 
+// Trash operations
+
+// GetDeletedByID retrieves a soft-deleted chat by ID
+func (r *chatRepository) GetDeletedByID(id uint) (*models.Chat, error) {
+	var chat models.Chat
+	err := r.db.Trashed().First(&chat, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("chat not found")
+		}
+		return nil, fmt.Errorf("failed to get deleted chat: %w", err)
+	}
+	return &chat, nil
+}
+
+// GetDeletedChats retrieves soft-deleted chats owned by a user with filters, sorting and pagination
+func (r *chatRepository) GetDeletedChats(ownerID uint, page *query.Params) ([]*models.Chat, int64, error) {
+	owned := func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("JOIN chat_members ON chats.id = chat_members.chat_id").
+			Where("chats.deleted_at IS NOT NULL").
+			Where("chat_members.user_id = ? AND chat_members.role = ? AND chat_members.is_active = ?",
+				ownerID, models.ChatMemberRoleOwner, true)
+	}
+
+	var total int64
+	if err := r.db.Unscoped().Model(&models.Chat{}).Scopes(owned, page.FilterScope).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted chats: %w", err)
+	}
+
+	var chats []*models.Chat
+	err := r.db.Unscoped().
+		Preload("Members", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("role ASC, joined_at ASC")
+		}).
+		Scopes(owned, page.FilterScope, page.PageScope).
+		Find(&chats).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted chats: %w", err)
+	}
+
+	return chats, total, nil
+}
+
+// Restore restores a soft-deleted chat by ID
+func (r *chatRepository) Restore(id uint) error {
+	restored, err := r.db.Restore(&models.Chat{}, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore chat: %w", err)
+	}
+	if !restored {
+		return fmt.Errorf("chat not found")
+	}
+	return nil
+}
+
+// PurgeDeleted permanently deletes chats soft-deleted before deletedBefore with their
+// members, messages and bot installations
+func (r *chatRepository) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	var chatIDs []uint
+	err := r.db.Trashed().Model(&models.Chat{}).
+		Where("deleted_at < ?", deletedBefore).
+		Pluck("id", &chatIDs).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chats to purge: %w", err)
+	}
+	if len(chatIDs) == 0 {
+		return 0, nil
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		messageIDs := tx.Unscoped().Model(&models.Message{}).Select("id").Where("chat_id IN ?", chatIDs)
+
+		// Children first, not all foreign keys cascade
+		children := []struct {
+			model interface{}
+			query string
+			args  interface{}
+		}{
+			{&models.MessageReaction{}, "message_id IN (?)", messageIDs},
+			{&models.MessageReadReceipt{}, "message_id IN (?)", messageIDs},
+			{&models.BotEventDelivery{}, "chat_id IN ?", chatIDs},
+			{&models.Message{}, "chat_id IN ?", chatIDs},
+			{&models.ArchivedMessage{}, "chat_id IN ?", chatIDs},
+			{&models.ChatBot{}, "chat_id IN ?", chatIDs},
+			{&models.ChatMember{}, "chat_id IN ?", chatIDs},
+		}
+		for _, child := range children {
+			if err := tx.Unscoped().Where(child.query, child.args).Delete(child.model).Error; err != nil {
+				return fmt.Errorf("failed to delete %T: %w", child.model, err)
+			}
+		}
+
+		return tx.Unscoped().Where("id IN ?", chatIDs).Delete(&models.Chat{}).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted chats: %w", err)
+	}
+
+	return int64(len(chatIDs)), nil
+}
+
 // AddMember adds a member to a chat
 func (r *chatRepository) AddMember(member *models.ChatMember) error {
 	// Check if member already exists
@@ -235,11 +365,26 @@ func (r *chatRepository) GetChatMembers(chatID uint) ([]*models.ChatMember, erro
 	return members, nil
 }
 
-// IsMember checks if a user is an active member of a chat
+// GetMemberIDs retrieves user IDs of all active members of a chat
+func (r *chatRepository) GetMemberIDs(chatID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.ChatMember{}).
+		Distinct().
+		Where("chat_id = ? AND is_active = ?", chatID, true).
+		Pluck("user_id", &userIDs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat member IDs: %w", err)
+	}
+	return userIDs, nil
+}
+
+// IsMember checks if a user is an active member of a chat that is not in trash
 func (r *chatRepository) IsMember(chatID, userID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.ChatMember{}).
-		Where("chat_id = ? AND user_id = ? AND is_active = ?", chatID, userID, true).
+		Joins("JOIN chats ON chats.id = chat_members.chat_id AND chats.deleted_at IS NULL").
+		Where("chat_members.chat_id = ? AND chat_members.user_id = ? AND chat_members.is_active = ?", chatID, userID, true).
 		Count(&count).Error
 
 	if err != nil {
@@ -248,6 +393,20 @@ func (r *chatRepository) IsMember(chatID, userID uint) (bool, error) {
 	return count > 0, nil
 }
 
+// GetReadableChatIDs retrieves IDs of chats not in trash the user is an active member of
+func (r *chatRepository) GetReadableChatIDs(userID uint) ([]uint, error) {
+	var chatIDs []uint
+	err := r.db.Model(&models.ChatMember{}).
+		Joins("JOIN chats ON chats.id = chat_members.chat_id AND chats.deleted_at IS NULL").
+		Where("chat_members.user_id = ? AND chat_members.is_active = ?", userID, true).
+		Pluck("chat_members.chat_id", &chatIDs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get readable chats: %w", err)
+	}
+	return chatIDs, nil
+}
+
 // GetMemberRole retrieves the role of a user in a chat
 func (r *chatRepository) GetMemberRole(chatID, userID uint) (models.ChatMemberRole, error) {
 	var member models.ChatMember
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+
+	"gorm.io/gorm"
+)
+
+// Department chats are group chats provisioned for departments of the user service. Users of
+// the department are managed members: they join and leave the chat with the department. Members
+// added by hand are never removed by department sync.
+
+// GetByDepartmentID retrieves the chat provisioned for a department
+func (r *chatRepository) GetByDepartmentID(departmentID uint) (*models.Chat, error) {
+	var chat models.Chat
+	err := r.db.Where("department_id = ?", departmentID).First(&chat).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("chat not found")
+		}
+		return nil, fmt.Errorf("failed to get department chat: %w", err)
+	}
+	return &chat, nil
+}
+
+// GetDepartmentChats retrieves chats of all departments
+func (r *chatRepository) GetDepartmentChats() ([]*models.Chat, error) {
+	var chats []*models.Chat
+	if err := r.db.Where("department_id IS NOT NULL").Order("id ASC").Find(&chats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get department chats: %w", err)
+	}
+	return chats, nil
+}
+
+// DetachDepartmentChat turns a department chat into a regular group chat. Members and history are kept.
+func (r *chatRepository) DetachDepartmentChat(chatID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Chat{}).Where("id = ?", chatID).Update("department_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to detach department chat: %w", err)
+		}
+		if err := tx.Model(&models.ChatMember{}).Where("chat_id = ? AND managed = ?", chatID, true).
+			Update("managed", false).Error; err != nil {
+			return fmt.Errorf("failed to release department chat members: %w", err)
+		}
+		return nil
+	})
+}
+
+// MoveDepartmentChat hands a department chat over to another department. Members are synced with
+// the new department separately.
+func (r *chatRepository) MoveDepartmentChat(chatID, departmentID uint) error {
+	result := r.db.Model(&models.Chat{}).Where("id = ?", chatID).Update("department_id", departmentID)
+	if result.Error != nil {
+		return fmt.Errorf("failed to move department chat: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("chat not found")
+	}
+	return nil
+}
+
+// AddDepartmentMember adds a user to a department chat as a managed member. It reports false if the
+// user already is an active member.
+func (r *chatRepository) AddDepartmentMember(chatID, userID uint) (bool, error) {
+	var existing []*models.ChatMember
+	if err := r.db.Where("chat_id = ? AND user_id = ?", chatID, userID).Find(&existing).Error; err != nil {
+		return false, fmt.Errorf("failed to check existing member: %w", err)
+	}
+
+	var member *models.ChatMember
+	if len(existing) > 0 {
+		member = existing[0]
+	}
+	return addDepartmentMember(r.db.DB, chatID, userID, member)
+}
+
+// RemoveDepartmentMember removes a managed member from a department chat. It reports false if the
+// user is not a managed member, members added by hand stay in the chat.
+func (r *chatRepository) RemoveDepartmentMember(chatID, userID uint) (bool, error) {
+	result := r.db.Model(&models.ChatMember{}).
+		Where("chat_id = ? AND user_id = ? AND managed = ? AND is_active = ?", chatID, userID, true, true).
+		Updates(map[string]interface{}{
+			"is_active": false,
+			"left_at":   time.Now(),
+			"managed":   false,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove department chat member: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SyncDepartmentMembers makes users of a department the managed members of its chat. Missing users
+// are added, managed members who left the department are removed. It returns IDs of added and
+// removed users.
+func (r *chatRepository) SyncDepartmentMembers(chatID uint, userIDs []uint) ([]uint, []uint, error) {
+	var added, removed []uint
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		added, removed = nil, nil
+
+		var members []*models.ChatMember
+		if err := tx.Where("chat_id = ?", chatID).Find(&members).Error; err != nil {
+			return fmt.Errorf("failed to get chat members: %w", err)
+		}
+		byUser := make(map[uint]*models.ChatMember, len(members))
+		for _, member := range members {
+			byUser[member.UserID] = member
+		}
+
+		inDepartment := make(map[uint]bool, len(userIDs))
+		for _, userID := range userIDs {
+			if inDepartment[userID] {
+				continue
+			}
+			inDepartment[userID] = true
+
+			ok, err := addDepartmentMember(tx, chatID, userID, byUser[userID])
+			if err != nil {
+				return err
+			}
+			if ok {
+				added = append(added, userID)
+			}
+		}
+
+		for _, member := range members {
+			if member.Managed && member.IsActive && !inDepartment[member.UserID] {
+				removed = append(removed, member.UserID)
+			}
+		}
+		if len(removed) == 0 {
+			return nil
+		}
+
+		err := tx.Model(&models.ChatMember{}).
+			Where("chat_id = ? AND user_id IN ? AND managed = ?", chatID, removed, true).
+			Updates(map[string]interface{}{
+				"is_active": false,
+				"left_at":   time.Now(),
+				"managed":   false,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to remove department chat members: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return added, removed, nil
+}
+
+// IsManagedMember checks if a user is an active member of a department chat because of the department
+func (r *chatRepository) IsManagedMember(chatID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ChatMember{}).
+		Where("chat_id = ? AND user_id = ? AND managed = ? AND is_active = ?", chatID, userID, true, true).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check chat membership: %w", err)
+	}
+	return count > 0, nil
+}
+
+// addDepartmentMember makes a user a managed member of a chat given the existing membership, if any.
+// Active members are left as they are. It reports whether the user joined the chat.
+func addDepartmentMember(tx *gorm.DB, chatID, userID uint, existing *models.ChatMember) (bool, error) {
+	if existing == nil {
+		member := &models.ChatMember{
+			ChatID:   chatID,
+			UserID:   userID,
+			Role:     models.ChatMemberRoleMember,
+			JoinedAt: time.Now(),
+			IsActive: true,
+			Managed:  true,
+		}
+		if err := tx.Create(member).Error; err != nil {
+			return false, fmt.Errorf("failed to add department chat member: %w", err)
+		}
+		return true, nil
+	}
+
+	if existing.IsActive {
+		return false, nil
+	}
+
+	err := tx.Model(existing).Updates(map[string]interface{}{
+		"is_active": true,
+		"left_at":   nil,
+		"joined_at": time.Now(),
+		"role":      models.ChatMemberRoleMember,
+		"managed":   true,
+	}).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to add department chat member: %w", err)
+	}
+	return true, nil
+}
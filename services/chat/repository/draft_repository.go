@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DraftRepository defines the interface for message draft data operations
+type DraftRepository interface {
+	Save(draft *models.MessageDraft) error
+	Get(chatID, userID uint, now time.Time) (*models.MessageDraft, error)
+	GetByUserID(userID uint, now time.Time) ([]*models.MessageDraft, error)
+	Delete(chatID, userID uint) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// draftRepository implements DraftRepository interface
+type draftRepository struct {
+	db *database.DB
+}
+
+// NewDraftRepository creates a new draft repository
+func NewDraftRepository(db *database.DB) DraftRepository {
+	return &draftRepository{
+		db: db,
+	}
+}
+
+// Save creates or replaces the draft of a user in a chat
+func (r *draftRepository) Save(draft *models.MessageDraft) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content", "reply_to_id", "updated_at", "expires_at"}),
+	}).Create(draft).Error
+	if err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves the draft of a user in a chat, nil if there is none or it expired
+func (r *draftRepository) Get(chatID, userID uint, now time.Time) (*models.MessageDraft, error) {
+	var draft models.MessageDraft
+	err := r.db.
+		Where("chat_id = ? AND user_id = ? AND expires_at > ?", chatID, userID, now).
+		First(&draft).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	return &draft, nil
+}
+
+// GetByUserID retrieves drafts of a user that have not expired, most recently updated first
+func (r *draftRepository) GetByUserID(userID uint, now time.Time) ([]*models.MessageDraft, error) {
+	var drafts []*models.MessageDraft
+	err := r.db.
+		Where("user_id = ? AND expires_at > ?", userID, now).
+		Order("updated_at DESC").
+		Find(&drafts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drafts: %w", err)
+	}
+	return drafts, nil
+}
+
+// Delete removes the draft of a user in a chat, a missing draft is not an error
+func (r *draftRepository) Delete(chatID, userID uint) error {
+	if err := r.db.Where("chat_id = ? AND user_id = ?", chatID, userID).Delete(&models.MessageDraft{}).Error; err != nil {
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes drafts that expired before now
+func (r *draftRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.MessageDraft{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired drafts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/query"
+
+	"gorm.io/gorm"
+)
+
+// The chat membership log is append-only: events are never updated, and they outlive purged
+// chats so the audience of a chat stays known for the retention period of compliance exports.
+
+// RecordMembershipEvents appends events to the chat membership log
+func (r *chatRepository) RecordMembershipEvents(events ...*models.ChatMembershipEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := r.db.Create(events).Error; err != nil {
+		return fmt.Errorf("failed to record chat membership events: %w", err)
+	}
+	return nil
+}
+
+// GetMembershipEvents retrieves a page of the chat membership log of a chat, a user or both,
+// zero IDs match any chat or user
+func (r *chatRepository) GetMembershipEvents(chatID, userID uint, page *query.Params) ([]*models.ChatMembershipEvent, int64, error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		if chatID != 0 {
+			db = db.Where("chat_id = ?", chatID)
+		}
+		if userID != 0 {
+			db = db.Where("user_id = ?", userID)
+		}
+		return db
+	}
+
+	var total int64
+	err := r.db.Model(&models.ChatMembershipEvent{}).
+		Scopes(scope, page.FilterScope).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count chat membership events: %w", err)
+	}
+
+	var events []*models.ChatMembershipEvent
+	err = r.db.
+		Scopes(scope, page.FilterScope, page.PageScope).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get chat membership events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// GetMembersAt replays the membership log of a chat up to a point in time and returns who was a
+// member then, sorted by user ID
+func (r *chatRepository) GetMembersAt(chatID uint, at time.Time) ([]models.ChatAudienceMember, error) {
+	var events []*models.ChatMembershipEvent
+	err := r.db.
+		Where("chat_id = ? AND created_at <= ?", chatID, at).
+		Order("created_at ASC, id ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat membership events: %w", err)
+	}
+
+	byUser := make(map[uint]*models.ChatAudienceMember)
+	for _, event := range events {
+		if !event.IsMembership() {
+			delete(byUser, event.UserID)
+			continue
+		}
+		if member, exists := byUser[event.UserID]; exists {
+			member.Role = event.Role
+			continue
+		}
+		byUser[event.UserID] = &models.ChatAudienceMember{
+			UserID:   event.UserID,
+			Role:     event.Role,
+			JoinedAt: event.CreatedAt,
+		}
+	}
+
+	members := make([]models.ChatAudienceMember, 0, len(byUser))
+	for _, member := range byUser {
+		members = append(members, *member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+	return members, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+
+	"gorm.io/gorm"
+)
+
+// Messages older than the archive horizon are moved to archived_messages in batches.
+// Read paths page through the messages table first and continue in the archive once
+// the hot history of a chat is exhausted, so clients don't need to know about it.
+
+// ArchiveMessages moves up to limit messages created before olderThan with ID greater
+// than afterID into the archive. It returns the number of archived messages and the ID
+// to continue from, which is zero when there is nothing left to scan.
+func (r *messageRepository) ArchiveMessages(olderThan time.Time, afterID uint, limit int) (int64, uint, error) {
+	var messages []*models.Message
+	err := r.db.
+		Preload("Reactions").
+		Where("id > ? AND created_at < ? AND is_deleted = ?", afterID, olderThan, false).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get messages to archive: %w", err)
+	}
+	if len(messages) == 0 {
+		return 0, 0, nil
+	}
+
+	next := messages[len(messages)-1].ID
+	if len(messages) < limit {
+		next = 0
+	}
+
+	messages, err = r.excludeRepliedMessages(messages)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(messages) == 0 {
+		return 0, next, nil
+	}
+
+	now := time.Now()
+	ids := make([]uint, len(messages))
+	archived := make([]*models.ArchivedMessage, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+		archived[i] = models.NewArchivedMessage(message, now)
+	}
+
+	batchSize := r.db.BatchSizeFor(&models.ArchivedMessage{})
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(archived, batchSize).Error; err != nil {
+			return fmt.Errorf("failed to copy messages to archive: %w", err)
+		}
+		if err := tx.Unscoped().Where("message_id IN ?", ids).Delete(&models.MessageReaction{}).Error; err != nil {
+			return fmt.Errorf("failed to delete archived message reactions: %w", err)
+		}
+		if err := tx.Unscoped().Where("message_id IN ?", ids).Delete(&models.MessageReadReceipt{}).Error; err != nil {
+			return fmt.Errorf("failed to delete archived message read receipts: %w", err)
+		}
+		if err := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Message{}).Error; err != nil {
+			return fmt.Errorf("failed to delete archived messages: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to archive messages: %w", err)
+	}
+
+	return int64(len(messages)), next, nil
+}
+
+// excludeRepliedMessages drops messages that are replied to by messages staying in the
+// messages table. Deleting them would reset reply_to_id of the replies, so they are
+// archived later together with or after their replies.
+func (r *messageRepository) excludeRepliedMessages(messages []*models.Message) ([]*models.Message, error) {
+	for len(messages) > 0 {
+		ids := make([]uint, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+
+		var replied []uint
+		err := r.db.Unscoped().Model(&models.Message{}).
+			Distinct("reply_to_id").
+			Where("reply_to_id IN ? AND id NOT IN ?", ids, ids).
+			Pluck("reply_to_id", &replied).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get replied messages: %w", err)
+		}
+		if len(replied) == 0 {
+			break
+		}
+
+		// Excluding a message may expose messages it replies to, repeat until stable
+		excluded := make(map[uint]bool, len(replied))
+		for _, id := range replied {
+			excluded[id] = true
+		}
+		kept := messages[:0]
+		for _, message := range messages {
+			if !excluded[message.ID] {
+				kept = append(kept, message)
+			}
+		}
+		messages = kept
+	}
+
+	return messages, nil
+}
+
+// getArchivedByID retrieves an archived message with its reply-to message
+func (r *messageRepository) getArchivedByID(id uint) (*models.Message, error) {
+	var archived models.ArchivedMessage
+	if err := r.db.First(&archived, id).Error; err != nil {
+		return nil, err
+	}
+
+	message := archived.ToMessage()
+	if err := r.loadReplies([]*models.Message{message}); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// getArchived retrieves archived messages matching scope, newest first
+func (r *messageRepository) getArchived(scope func(db *gorm.DB) *gorm.DB, limit, offset int) ([]*models.Message, error) {
+	var archived []*models.ArchivedMessage
+	err := r.db.
+		Scopes(scope).
+		Limit(limit).
+		Offset(offset).
+		Order("created_at DESC").
+		Find(&archived).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived messages: %w", err)
+	}
+
+	messages := make([]*models.Message, len(archived))
+	for i, message := range archived {
+		messages[i] = message.ToMessage()
+	}
+
+	if err := r.loadReplies(messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// fillFromArchive completes a newest-first page of messages with archived ones once
+// hot history is exhausted. Hot messages matching hotScope are only counted when the
+// page starts past them, to translate offset into the archive.
+func (r *messageRepository) fillFromArchive(messages []*models.Message, limit, offset int, hotScope, archiveScope func(db *gorm.DB) *gorm.DB) ([]*models.Message, error) {
+	if limit <= 0 || len(messages) >= limit {
+		return messages, nil
+	}
+
+	archiveOffset := 0
+	if len(messages) == 0 && offset > 0 {
+		var hotTotal int64
+		if err := r.db.Model(&models.Message{}).Scopes(hotScope).Count(&hotTotal).Error; err != nil {
+			return nil, fmt.Errorf("failed to count messages: %w", err)
+		}
+		archiveOffset = max(offset-int(hotTotal), 0)
+	}
+
+	archived, err := r.getArchived(archiveScope, limit-len(messages), archiveOffset)
+	if err != nil {
+		return nil, err
+	}
+	return append(messages, archived...), nil
+}
+
+// countArchived returns the number of archived messages in a chat
+func (r *messageRepository) countArchived(chatID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ArchivedMessage{}).
+		Where("chat_id = ?", chatID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count archived messages: %w", err)
+	}
+	return count, nil
+}
+
+// loadReplies sets reply-to messages of archived messages. A reply-to message may be
+// archived or, if it was kept for other replies, still in the messages table.
+func (r *messageRepository) loadReplies(messages []*models.Message) error {
+	var replyIDs []uint
+	for _, message := range messages {
+		if message.ReplyToID != nil && message.ReplyTo == nil {
+			replyIDs = append(replyIDs, *message.ReplyToID)
+		}
+	}
+	if len(replyIDs) == 0 {
+		return nil
+	}
+
+	replies := make(map[uint]*models.Message, len(replyIDs))
+
+	var hot []*models.Message
+	if err := r.db.Where("id IN ?", replyIDs).Find(&hot).Error; err != nil {
+		return fmt.Errorf("failed to get reply messages: %w", err)
+	}
+	for _, reply := range hot {
+		replies[reply.ID] = reply
+	}
+
+	var archived []*models.ArchivedMessage
+	if err := r.db.Where("id IN ?", replyIDs).Find(&archived).Error; err != nil {
+		return fmt.Errorf("failed to get archived reply messages: %w", err)
+	}
+	for _, reply := range archived {
+		replies[reply.ID] = reply.ToMessage()
+	}
+
+	for _, message := range messages {
+		if message.ReplyToID != nil && message.ReplyTo == nil {
+			message.ReplyTo = replies[*message.ReplyToID]
+		}
+	}
+	return nil
+}
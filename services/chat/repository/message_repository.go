@@ -19,6 +19,7 @@ type MessageRepository interface {
 	GetByChatIDWithPagination(chatID uint, limit, offset int) ([]*models.Message, int64, error)
 	Update(message *models.Message) error
 	Delete(id uint) error
+	SetDeleted(id uint, deleted bool) error
 	Count() (int64, error)
 	CountByChatID(chatID uint) (int64, error)
 	GetWithReactions(id uint) (*models.Message, error)
@@ -32,13 +33,26 @@ type MessageRepository interface {
 	GetReactions(messageID uint) ([]*models.MessageReaction, error)
 
 	// Read receipt operations
-	MarkAsRead(receipt *models.MessageReadReceipt) error
+	MarkAsRead(receipt *models.MessageReadReceipt) (bool, error)
 	GetReadReceipts(messageID uint) ([]*models.MessageReadReceipt, error)
 	GetUnreadCount(chatID, userID uint) (int64, error)
+	GetUnreadCounts(userID uint) (map[uint]int64, error)
+	GetNewMessageCounts(userID uint, since time.Time) (map[uint]int64, error)
 
 	// Search and filtering
 	SearchMessages(chatID uint, query string, limit, offset int) ([]*models.Message, error)
 	GetMessagesByType(chatID uint, messageType models.MessageType, limit, offset int) ([]*models.Message, error)
+
+	// Archive operations
+	ArchiveMessages(olderThan time.Time, afterID uint, limit int) (int64, uint, error)
+
+	// Search index operations
+	SearchUserMessages(filter *models.MessageSearchFilter, limit, offset int) ([]*models.Message, int64, error)
+	GetByIDsForSearch(ids []uint) ([]*models.Message, error)
+	GetMessagesForIndex(afterID uint, limit int) ([]*models.Message, error)
+	GetSearchOutbox(now time.Time, limit int) ([]*models.SearchOutboxEntry, error)
+	DeleteSearchOutbox(ids []uint) error
+	RetrySearchOutbox(ids []uint, lastError string, availableAt time.Time) error
 }
 
 // messageRepository implements MessageRepository interface
@@ -53,9 +67,20 @@ func NewMessageRepository(db *database.DB) MessageRepository {
 	}
 }
 
-// Create creates a new message
+// Create creates a new message and queues it for the search index
 func (r *messageRepository) Create(message *models.Message) error {
-	if err := r.db.Create(message).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		// The sender's draft of the chat is done with once the message is sent
+		if err := tx.Where("chat_id = ? AND user_id = ?", message.ChatID, message.SenderID).
+			Delete(&models.MessageDraft{}).Error; err != nil {
+			return fmt.Errorf("failed to clear draft: %w", err)
+		}
+		return enqueueSearchChange(tx, models.SearchOperationIndex, message.ID)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
 	}
 	return nil
@@ -91,7 +116,16 @@ func (r *messageRepository) GetByChatID(chatID uint, limit, offset int) ([]*mode
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
-	return messages, nil
+
+	// Continue in archived history
+	return r.fillFromArchive(messages, limit, offset,
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where("chat_id = ? AND is_deleted = ?", chatID, false)
+		},
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where("chat_id = ?", chatID)
+		},
+	)
 }
 
 // GetByChatIDWithPagination retrieves messages with total count for proper pagination
@@ -130,23 +164,52 @@ func (r *messageRepository) GetByChatIDWithPagination(chatID uint, limit, offset
 	return messages, total, nil
 }
 
-// Update updates an existing message
+// Update updates an existing message and queues it for the search index
 func (r *messageRepository) Update(message *models.Message) error {
-	result := r.db.Save(message)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update message: %w", result.Error)
+	var rowsAffected int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Save(message)
+		rowsAffected = result.RowsAffected
+		if result.Error != nil || rowsAffected == 0 {
+			return result.Error
+		}
+		return enqueueSearchChange(tx, models.SearchOperationIndex, message.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
 	}
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return fmt.Errorf("message not found")
 	}
 	return nil
 }
 
-// Delete soft deletes a message by ID
+// Delete soft deletes a message by ID and queues its removal from the search index
 func (r *messageRepository) Delete(id uint) error {
-	result := r.db.Model(&models.Message{}).Where("id = ?", id).Update("is_deleted", true)
+	var rowsAffected int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Message{}).Where("id = ?", id).Update("is_deleted", true)
+		rowsAffected = result.RowsAffected
+		if result.Error != nil || rowsAffected == 0 {
+			return result.Error
+		}
+		return enqueueSearchChange(tx, models.SearchOperationDelete, id)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("message not found")
+	}
+	return nil
+}
+
+// SetDeleted hides or shows a message without touching the search index,
+// used while its deletion can still be undone
+func (r *messageRepository) SetDeleted(id uint, deleted bool) error {
+	result := r.db.Model(&models.Message{}).Where("id = ?", id).Update("is_deleted", deleted)
 	if result.Error != nil {
-		return fmt.Errorf("failed to delete message: %w", result.Error)
+		return fmt.Errorf("failed to update message: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("message not found")
@@ -164,7 +227,7 @@ func (r *messageRepository) Count() (int64, error) {
 	return count, nil
 }
 
-// CountByChatID returns the number of messages in a chat
+// CountByChatID returns the number of messages in a chat including archived ones
 func (r *messageRepository) CountByChatID(chatID uint) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.Message{}).
@@ -174,7 +237,12 @@ func (r *messageRepository) CountByChatID(chatID uint) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("failed to count chat messages: %w", err)
 	}
-	return count, nil
+
+	archived, err := r.countArchived(chatID)
+	if err != nil {
+		return 0, err
+	}
+	return count + archived, nil
 }
 
 // GetWithReactions retrieves a message with all related data
@@ -190,6 +258,17 @@ func (r *messageRepository) GetWithReactions(id uint) (*models.Message, error) {
 		}).
 		First(&message, id).Error
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Archived messages are read-only and only available here
+		archived, archiveErr := r.getArchivedByID(id)
+		if archiveErr == nil {
+			return archived, nil
+		}
+		if !errors.Is(archiveErr, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get archived message: %w", archiveErr)
+		}
+	}
+
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("message not found")
@@ -234,6 +313,17 @@ func (r *messageRepository) GetMessagesBefore(chatID uint, before uint, limit in
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages before: %w", err)
 	}
+
+	// Continue in archived history, archived messages keep their IDs
+	if len(messages) < limit {
+		archived, err := r.getArchived(func(db *gorm.DB) *gorm.DB {
+			return db.Where("chat_id = ? AND id < ?", chatID, before)
+		}, limit-len(messages), 0)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, archived...)
+	}
 	return messages, nil
 }
 
@@ -310,8 +400,8 @@ func (r *messageRepository) GetReactions(messageID uint) ([]*models.MessageReact
 
 // Read receipt operations
 
-// MarkAsRead marks a message as read by a user
-func (r *messageRepository) MarkAsRead(receipt *models.MessageReadReceipt) error {
+// MarkAsRead marks a message as read by a user, returns true if the message was unread before
+func (r *messageRepository) MarkAsRead(receipt *models.MessageReadReceipt) (bool, error) {
 	// Check if already marked as read
 	var existing models.MessageReadReceipt
 	err := r.db.Where("message_id = ? AND user_id = ?", receipt.MessageID, receipt.UserID).
@@ -320,18 +410,18 @@ func (r *messageRepository) MarkAsRead(receipt *models.MessageReadReceipt) error
 	if err == nil {
 		// Already marked as read, update timestamp
 		existing.ReadAt = receipt.ReadAt
-		return r.db.Save(&existing).Error
+		return false, r.db.Save(&existing).Error
 	}
 
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return fmt.Errorf("failed to check existing read receipt: %w", err)
+		return false, fmt.Errorf("failed to check existing read receipt: %w", err)
 	}
 
 	// Create new read receipt
 	if err := r.db.Create(receipt).Error; err != nil {
-		return fmt.Errorf("failed to mark message as read: %w", err)
+		return false, fmt.Errorf("failed to mark message as read: %w", err)
 	}
-	return nil
+	return true, nil
 }
 
 // GetReadReceipts retrieves all read receipts for a message
@@ -367,6 +457,78 @@ func (r *messageRepository) GetUnreadCount(chatID, userID uint) (int64, error) {
 	return count, nil
 }
 
+// GetUnreadCounts returns the number of unread messages in every active chat of a user.
+// Chats without unread messages are included with 0.
+func (r *messageRepository) GetUnreadCounts(userID uint) (map[uint]int64, error) {
+	var chatIDs []uint
+	err := r.db.Model(&models.ChatMember{}).
+		Joins("JOIN chats ON chats.id = chat_members.chat_id").
+		Where("chat_members.user_id = ? AND chat_members.is_active = ?", userID, true).
+		Where("chats.is_active = ? AND chats.deleted_at IS NULL", true).
+		Pluck("chat_members.chat_id", &chatIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user chats: %w", err)
+	}
+
+	counts := make(map[uint]int64, len(chatIDs))
+	if len(chatIDs) == 0 {
+		return counts, nil
+	}
+	for _, chatID := range chatIDs {
+		counts[chatID] = 0
+	}
+
+	var rows []struct {
+		ChatID uint
+		Count  int64
+	}
+	err = r.db.Model(&models.Message{}).
+		Select("chat_id, COUNT(*) AS count").
+		Where("chat_id IN ? AND sender_id != ? AND is_deleted = ?", chatIDs, userID, false).
+		Where("NOT EXISTS (?)",
+			r.db.Table("message_read_receipts").
+				Select("1").
+				Where("message_read_receipts.message_id = messages.id AND message_read_receipts.user_id = ?", userID),
+		).
+		Group("chat_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.ChatID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetNewMessageCounts returns the number of messages other members sent since a time in active
+// chats of a user, only chats with new messages are included
+func (r *messageRepository) GetNewMessageCounts(userID uint, since time.Time) (map[uint]int64, error) {
+	var rows []struct {
+		ChatID uint
+		Count  int64
+	}
+	err := r.db.Model(&models.Message{}).
+		Select("messages.chat_id, COUNT(*) AS count").
+		Joins("JOIN chat_members ON chat_members.chat_id = messages.chat_id").
+		Joins("JOIN chats ON chats.id = messages.chat_id").
+		Where("chat_members.user_id = ? AND chat_members.is_active = ?", userID, true).
+		Where("chats.is_active = ? AND chats.deleted_at IS NULL", true).
+		Where("messages.sender_id != ? AND messages.is_deleted = ? AND messages.created_at >= ?", userID, false, since).
+		Group("messages.chat_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count new messages: %w", err)
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ChatID] = row.Count
+	}
+	return counts, nil
+}
+
 // Search and filtering operations
 
 // SearchMessages searches for messages containing a query string
@@ -383,7 +545,16 @@ func (r *messageRepository) SearchMessages(chatID uint, query string, limit, off
 	if err != nil {
 		return nil, fmt.Errorf("failed to search messages: %w", err)
 	}
-	return messages, nil
+
+	// Search continues in archived history
+	return r.fillFromArchive(messages, limit, offset,
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where("chat_id = ? AND content ILIKE ? AND is_deleted = ?", chatID, "%"+query+"%", false)
+		},
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where("chat_id = ? AND content ILIKE ?", chatID, "%"+query+"%")
+		},
+	)
 }
 
 // GetMessagesByType retrieves messages of a specific type
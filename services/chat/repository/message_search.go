@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+
+	"gorm.io/gorm"
+)
+
+// enqueueSearchChange queues a message change for the search index in the transaction
+// changing the message
+func enqueueSearchChange(tx *gorm.DB, operation models.SearchOperation, messageID uint) error {
+	entry := &models.SearchOutboxEntry{
+		MessageID:   messageID,
+		Operation:   operation,
+		AvailableAt: time.Now(),
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to queue search index change: %w", err)
+	}
+	return nil
+}
+
+// SearchUserMessages searches messages, archived ones included, in the chats of filter
+// with a substring match. It is the fallback used when the search index is unavailable.
+func (r *messageRepository) SearchUserMessages(filter *models.MessageSearchFilter, limit, offset int) ([]*models.Message, int64, error) {
+	if len(filter.ChatIDs) == 0 {
+		return []*models.Message{}, 0, nil
+	}
+
+	hotScope := func(db *gorm.DB) *gorm.DB {
+		return searchScope(db.Where("is_deleted = ?", false), filter)
+	}
+	archiveScope := func(db *gorm.DB) *gorm.DB {
+		return searchScope(db, filter)
+	}
+
+	var hotTotal, archivedTotal int64
+	if err := r.db.Model(&models.Message{}).Scopes(hotScope).Count(&hotTotal).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count matching messages: %w", err)
+	}
+	if err := r.db.Model(&models.ArchivedMessage{}).Scopes(archiveScope).Count(&archivedTotal).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count matching archived messages: %w", err)
+	}
+
+	var messages []*models.Message
+	err := r.db.
+		Preload("ReplyTo").
+		Scopes(hotScope).
+		Limit(limit).
+		Offset(offset).
+		Order("created_at DESC").
+		Find(&messages).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	messages, err = r.fillFromArchive(messages, limit, offset, hotScope, archiveScope)
+	if err != nil {
+		return nil, 0, err
+	}
+	return messages, hotTotal + archivedTotal, nil
+}
+
+// searchScope applies a message search filter to messages or archived messages
+func searchScope(db *gorm.DB, filter *models.MessageSearchFilter) *gorm.DB {
+	db = db.Where("chat_id IN ? AND content ILIKE ?", filter.ChatIDs, "%"+filter.Query+"%")
+	if filter.SenderID != 0 {
+		db = db.Where("sender_id = ?", filter.SenderID)
+	}
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at < ?", *filter.To)
+	}
+	return db
+}
+
+// GetByIDsForSearch retrieves messages found in the search index, archived ones included.
+// Deleted and unknown messages are left out, the order of ids is not kept.
+func (r *messageRepository) GetByIDsForSearch(ids []uint) ([]*models.Message, error) {
+	if len(ids) == 0 {
+		return []*models.Message{}, nil
+	}
+
+	var messages []*models.Message
+	err := r.db.
+		Preload("ReplyTo").
+		Where("id IN ? AND is_deleted = ?", ids, false).
+		Find(&messages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(messages) == len(ids) {
+		return messages, nil
+	}
+
+	found := make(map[uint]bool, len(messages))
+	for _, message := range messages {
+		found[message.ID] = true
+	}
+	var missing []uint
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	var archived []*models.ArchivedMessage
+	if err := r.db.Where("id IN ?", missing).Find(&archived).Error; err != nil {
+		return nil, fmt.Errorf("failed to get archived messages: %w", err)
+	}
+	archivedMessages := make([]*models.Message, len(archived))
+	for i, message := range archived {
+		archivedMessages[i] = message.ToMessage()
+	}
+	if err := r.loadReplies(archivedMessages); err != nil {
+		return nil, err
+	}
+
+	return append(messages, archivedMessages...), nil
+}
+
+// GetMessagesForIndex retrieves up to limit messages, archived ones included, with ID
+// greater than afterID in ID order. Archived messages keep their IDs, so both tables
+// are read as one sequence.
+func (r *messageRepository) GetMessagesForIndex(afterID uint, limit int) ([]*models.Message, error) {
+	var messages []*models.Message
+	err := r.db.
+		Where("id > ? AND is_deleted = ?", afterID, false).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages to index: %w", err)
+	}
+
+	var archived []*models.ArchivedMessage
+	err = r.db.
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&archived).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived messages to index: %w", err)
+	}
+	for _, message := range archived {
+		messages = append(messages, message.ToMessage())
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+// GetSearchOutbox retrieves up to limit pending search index changes available at now, oldest first
+func (r *messageRepository) GetSearchOutbox(now time.Time, limit int) ([]*models.SearchOutboxEntry, error) {
+	var entries []*models.SearchOutboxEntry
+	err := r.db.
+		Where("available_at <= ?", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search outbox: %w", err)
+	}
+	return entries, nil
+}
+
+// DeleteSearchOutbox removes search index changes that were applied
+func (r *messageRepository) DeleteSearchOutbox(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.Where("id IN ?", ids).Delete(&models.SearchOutboxEntry{}).Error; err != nil {
+		return fmt.Errorf("failed to delete search outbox entries: %w", err)
+	}
+	return nil
+}
+
+// RetrySearchOutbox postpones search index changes that failed until availableAt
+func (r *messageRepository) RetrySearchOutbox(ids []uint, lastError string, availableAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := r.db.Model(&models.SearchOutboxEntry{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   lastError,
+			"available_at": availableAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to postpone search outbox entries: %w", err)
+	}
+	return nil
+}
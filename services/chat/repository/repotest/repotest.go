@@ -0,0 +1,88 @@
+// Package repotest provides chat repositories on an in-memory SQLite database and
+// fixture factories for usecase and repository tests
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/database/dbtest"
+)
+
+// Repositories holds all chat repositories backed by one test database
+type Repositories struct {
+	DB       *database.DB
+	Chats    repository.ChatRepository
+	Messages repository.MessageRepository
+	Bots     repository.BotRepository
+	Drafts   repository.DraftRepository
+	Commands repository.SlashCommandRepository
+}
+
+// New creates repositories on a fresh test database
+func New(tb testing.TB) *Repositories {
+	tb.Helper()
+
+	db := dbtest.Open(tb, models.Models()...)
+	return &Repositories{
+		DB:       db,
+		Chats:    repository.NewChatRepository(db),
+		Messages: repository.NewMessageRepository(db),
+		Bots:     repository.NewBotRepository(db),
+		Drafts:   repository.NewDraftRepository(db),
+		Commands: repository.NewSlashCommandRepository(db),
+	}
+}
+
+// Chat creates a group chat of the creator with the members. The creator becomes its owner.
+func (r *Repositories) Chat(tb testing.TB, creatorID uint, memberIDs ...uint) *models.Chat {
+	tb.Helper()
+
+	chat := &models.Chat{
+		Name:      "Test chat",
+		Type:      models.ChatTypeGroup,
+		CreatorID: creatorID,
+		IsActive:  true,
+	}
+	if err := r.Chats.Create(chat); err != nil {
+		tb.Fatalf("failed to create chat fixture: %v", err)
+	}
+
+	for _, memberID := range memberIDs {
+		member := &models.ChatMember{
+			ChatID:   chat.ID,
+			UserID:   memberID,
+			Role:     models.ChatMemberRoleMember,
+			JoinedAt: time.Now(),
+			IsActive: true,
+		}
+		if err := r.Chats.AddMember(member); err != nil {
+			tb.Fatalf("failed to add chat member fixture: %v", err)
+		}
+	}
+	return chat
+}
+
+// Message creates a text message of the sender in the chat, options override the defaults before saving
+func (r *Repositories) Message(tb testing.TB, chatID, senderID uint, content string, options ...func(*models.Message)) *models.Message {
+	tb.Helper()
+
+	message := &models.Message{
+		ChatID:   chatID,
+		SenderID: senderID,
+		Content:  content,
+		Type:     models.MessageTypeText,
+		Status:   models.MessageStatusSent,
+	}
+	for _, option := range options {
+		option(message)
+	}
+
+	if err := r.Messages.Create(message); err != nil {
+		tb.Fatalf("failed to create message fixture: %v", err)
+	}
+	return message
+}
@@ -0,0 +1,333 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/query"
+)
+
+func TestChatFixtures(t *testing.T) {
+	repos := New(t)
+
+	chat := repos.Chat(t, 1, 2)
+	repos.Message(t, chat.ID, 1, "Release is ready")
+	repos.Message(t, chat.ID, 2, "Lunch?")
+
+	isMember, err := repos.Chats.IsMember(chat.ID, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isMember {
+		t.Error("expected user 2 to be a chat member")
+	}
+
+	// ILIKE search runs on SQLite
+	messages, err := repos.Messages.SearchMessages(chat.ID, "RELEASE", 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Content != "Release is ready" {
+		t.Errorf("expected 1 matching message, got %d", len(messages))
+	}
+}
+
+func TestDepartmentChatMembers(t *testing.T) {
+	repos := New(t)
+
+	departmentID := uint(7)
+	chat := &models.Chat{Name: "Sales", Type: models.ChatTypeGroup, IsActive: true, DepartmentID: &departmentID}
+	if err := repos.Chats.Create(chat); err != nil {
+		t.Fatalf("failed to create department chat: %v", err)
+	}
+
+	// User 5 was added by hand and must survive department sync
+	manual := &models.ChatMember{ChatID: chat.ID, UserID: 5, Role: models.ChatMemberRoleMember, JoinedAt: time.Now(), IsActive: true}
+	if err := repos.Chats.AddMember(manual); err != nil {
+		t.Fatalf("failed to add manual member: %v", err)
+	}
+
+	added, removed, err := repos.Chats.SyncDepartmentMembers(chat.ID, []uint{1, 2, 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 2 || len(removed) != 0 {
+		t.Errorf("expected 2 added and 0 removed members, got %v and %v", added, removed)
+	}
+
+	added, removed, err = repos.Chats.SyncDepartmentMembers(chat.ID, []uint{2, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 1 || added[0] != 3 || len(removed) != 1 || removed[0] != 1 {
+		t.Errorf("expected user 3 added and user 1 removed, got %v and %v", added, removed)
+	}
+
+	memberIDs, err := repos.Chats.GetMemberIDs(chat.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(memberIDs) != 3 {
+		t.Errorf("expected members 2, 3 and 5, got %v", memberIDs)
+	}
+
+	// Users who rejoin the department are reactivated as managed members
+	if ok, err := repos.Chats.AddDepartmentMember(chat.ID, 1); err != nil || !ok {
+		t.Fatalf("expected user 1 to rejoin, got %v, %v", ok, err)
+	}
+	if managed, _ := repos.Chats.IsManagedMember(chat.ID, 1); !managed {
+		t.Error("expected user 1 to be a managed member")
+	}
+	if ok, _ := repos.Chats.RemoveDepartmentMember(chat.ID, 5); ok {
+		t.Error("expected manual member to stay in the chat")
+	}
+
+	found, err := repos.Chats.GetByDepartmentID(departmentID)
+	if err != nil || found.ID != chat.ID {
+		t.Fatalf("expected department chat, got %v", err)
+	}
+
+	// A merged department hands its chat over to the department it was merged into
+	mergedInto := departmentID + 1
+	if err := repos.Chats.MoveDepartmentChat(chat.ID, mergedInto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found, err := repos.Chats.GetByDepartmentID(mergedInto); err != nil || found.ID != chat.ID {
+		t.Fatalf("expected the chat to move to department %d, got %v", mergedInto, err)
+	}
+	departmentID = mergedInto
+
+	if err := repos.Chats.DetachDepartmentChat(chat.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repos.Chats.GetByDepartmentID(departmentID); err == nil {
+		t.Error("expected detached chat to have no department")
+	}
+	if managed, _ := repos.Chats.IsManagedMember(chat.ID, 1); managed {
+		t.Error("expected members of a detached chat to be unmanaged")
+	}
+}
+
+func TestMessageSearchOutbox(t *testing.T) {
+	repos := New(t)
+
+	chat := repos.Chat(t, 1, 2)
+	other := repos.Chat(t, 3)
+	release := repos.Message(t, chat.ID, 1, "Release is ready")
+	repos.Message(t, chat.ID, 2, "Release notes are late")
+	repos.Message(t, other.ID, 3, "Release of another team")
+
+	if err := repos.Messages.Delete(release.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Every change is queued for the search index in order
+	entries, err := repos.Messages.GetSearchOutbox(time.Now(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 4 || entries[3].MessageID != release.ID || entries[3].Operation != models.SearchOperationDelete {
+		t.Fatalf("expected 3 index and 1 delete entries, got %d", len(entries))
+	}
+
+	// Postponed entries are not picked until they are available again
+	if err := repos.Messages.RetrySearchOutbox([]uint{entries[0].ID}, "index unavailable", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending, _ := repos.Messages.GetSearchOutbox(time.Now(), 10); len(pending) != 3 {
+		t.Errorf("expected 3 available entries, got %d", len(pending))
+	}
+	if err := repos.Messages.DeleteSearchOutbox([]uint{entries[1].ID, entries[2].ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending, _ := repos.Messages.GetSearchOutbox(time.Now(), 10); len(pending) != 1 {
+		t.Errorf("expected 1 available entry, got %d", len(pending))
+	}
+
+	// Database search only covers the given chats and skips deleted messages
+	filter := &models.MessageSearchFilter{Query: "release", ChatIDs: []uint{chat.ID}}
+	messages, total, err := repos.Messages.SearchUserMessages(filter, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(messages) != 1 || messages[0].Content != "Release notes are late" {
+		t.Errorf("expected 1 matching message, got %d of %d", len(messages), total)
+	}
+
+	indexed, err := repos.Messages.GetMessagesForIndex(0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(indexed) != 2 {
+		t.Errorf("expected 2 messages to index, got %d", len(indexed))
+	}
+}
+
+func TestMessageDrafts(t *testing.T) {
+	repos := New(t)
+	chat := repos.Chat(t, 1, 2)
+	now := time.Now()
+
+	draft := &models.MessageDraft{ChatID: chat.ID, UserID: 2, Content: "Half written", UpdatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	if err := repos.Drafts.Save(draft); err != nil {
+		t.Fatalf("failed to save draft: %v", err)
+	}
+	updated := &models.MessageDraft{ChatID: chat.ID, UserID: 2, Content: "Almost done", UpdatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	if err := repos.Drafts.Save(updated); err != nil {
+		t.Fatalf("failed to update draft: %v", err)
+	}
+
+	stored, err := repos.Drafts.Get(chat.ID, 2, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || stored.Content != "Almost done" {
+		t.Fatalf("expected updated draft, got %+v", stored)
+	}
+
+	drafts, err := repos.Drafts.GetByUserID(2, now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drafts) != 0 {
+		t.Fatalf("expected expired draft to be hidden, got %d", len(drafts))
+	}
+
+	deleted, err := repos.Drafts.DeleteExpired(now.Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 expired draft deleted, got %d", deleted)
+	}
+
+	// Sending a message clears the sender's draft of the chat
+	if err := repos.Drafts.Save(&models.MessageDraft{ChatID: chat.ID, UserID: 2, Content: "Sent soon", UpdatedAt: now, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("failed to save draft: %v", err)
+	}
+	repos.Message(t, chat.ID, 2, "Sent soon")
+
+	stored, err = repos.Drafts.Get(chat.ID, 2, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored != nil {
+		t.Fatalf("expected draft to be cleared after sending, got %+v", stored)
+	}
+}
+
+func TestSlashCommands(t *testing.T) {
+	repos := New(t)
+
+	deploy := &models.SlashCommand{Name: "deploy", WebhookURL: "https://ci.example.com/deploy", WebhookSecret: "secret", CreatedBy: 1, IsActive: true}
+	oncall := &models.SlashCommand{Name: "oncall", WebhookURL: "https://ops.example.com/oncall", WebhookSecret: "secret", CreatedBy: 1, IsActive: true}
+	for _, command := range []*models.SlashCommand{oncall, deploy} {
+		if err := repos.Commands.Create(command); err != nil {
+			t.Fatalf("failed to create command: %v", err)
+		}
+	}
+
+	oncall.IsActive = false
+	if err := repos.Commands.Update(oncall); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	active, err := repos.Commands.List(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(active) != 1 || active[0].Name != "deploy" {
+		t.Errorf("expected only the active command, got %d", len(active))
+	}
+
+	all, err := repos.Commands.List(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "deploy" || all[1].Name != "oncall" {
+		t.Errorf("expected 2 commands ordered by name, got %d", len(all))
+	}
+
+	// Deleted names can be registered again
+	if err := repos.Commands.Delete(deploy.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repos.Commands.GetByName("deploy"); err == nil {
+		t.Error("expected deleted command to be gone")
+	}
+	again := &models.SlashCommand{Name: "deploy", WebhookURL: "https://ci.example.com/v2", WebhookSecret: "secret", CreatedBy: 2, IsActive: true}
+	if err := repos.Commands.Create(again); err != nil {
+		t.Errorf("expected name of deleted command to be free: %v", err)
+	}
+}
+
+func TestChatMembershipEvents(t *testing.T) {
+	repos := New(t)
+
+	start := time.Now().Add(-time.Hour)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	actor := uint(1)
+	events := []*models.ChatMembershipEvent{
+		{ChatID: 10, UserID: 1, Type: models.MembershipEventJoined, Role: models.ChatMemberRoleOwner, ActorID: &actor, CreatedAt: at(0)},
+		{ChatID: 10, UserID: 2, Type: models.MembershipEventJoined, Role: models.ChatMemberRoleMember, ActorID: &actor, CreatedAt: at(0)},
+		{ChatID: 10, UserID: 3, Type: models.MembershipEventJoined, Role: models.ChatMemberRoleMember, ActorID: &actor, CreatedAt: at(10)},
+		{ChatID: 10, UserID: 3, Type: models.MembershipEventRemoved, PreviousRole: models.ChatMemberRoleMember, ActorID: &actor, CreatedAt: at(20)},
+		{ChatID: 10, UserID: 2, Type: models.MembershipEventRoleChanged, Role: models.ChatMemberRoleOwner, PreviousRole: models.ChatMemberRoleMember, ActorID: &actor, CreatedAt: at(30)},
+		{ChatID: 10, UserID: 1, Type: models.MembershipEventLeft, PreviousRole: models.ChatMemberRoleOwner, ActorID: &actor, CreatedAt: at(30)},
+		{ChatID: 11, UserID: 2, Type: models.MembershipEventJoined, Role: models.ChatMemberRoleMember, CreatedAt: at(5)},
+	}
+	if err := repos.Chats.RecordMembershipEvents(events...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	audience := func(minutes int) map[uint]models.ChatMemberRole {
+		members, err := repos.Chats.GetMembersAt(10, at(minutes))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		roles := make(map[uint]models.ChatMemberRole, len(members))
+		for _, member := range members {
+			roles[member.UserID] = member.Role
+		}
+		return roles
+	}
+
+	if roles := audience(15); len(roles) != 3 || roles[1] != models.ChatMemberRoleOwner {
+		t.Errorf("expected users 1, 2 and 3 with 1 as owner, got %v", roles)
+	}
+	if roles := audience(25); len(roles) != 2 || roles[3] != "" {
+		t.Errorf("expected removed user 3 to be gone, got %v", roles)
+	}
+	if roles := audience(40); len(roles) != 1 || roles[2] != models.ChatMemberRoleOwner {
+		t.Errorf("expected user 2 to be the only member as owner, got %v", roles)
+	}
+	if roles := audience(-5); len(roles) != 0 {
+		t.Errorf("expected no members before the chat existed, got %v", roles)
+	}
+
+	page := query.Default(models.MembershipEventListOptions)
+	byChat, total, err := repos.Chats.GetMembershipEvents(10, 0, page)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 6 || len(byChat) != 6 || byChat[0].CreatedAt.Before(byChat[5].CreatedAt) {
+		t.Errorf("expected 6 events of chat 10, newest first, got %d of %d", len(byChat), total)
+	}
+
+	byUser, total, err := repos.Chats.GetMembershipEvents(0, 2, page)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 3 || len(byUser) != 3 {
+		t.Errorf("expected 3 events of user 2 across chats, got %d of %d", len(byUser), total)
+	}
+
+	// The log follows a merged account
+	if _, err := repos.Chats.MergeUsers(4, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, total, _ := repos.Chats.GetMembershipEvents(0, 4, page); total != 3 {
+		t.Errorf("expected events of the duplicate to move to the primary account, got %d", total)
+	}
+}
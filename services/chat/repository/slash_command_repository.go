@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// SlashCommandRepository defines the interface for custom slash command data operations
+type SlashCommandRepository interface {
+	Create(command *models.SlashCommand) error
+	GetByID(id uint) (*models.SlashCommand, error)
+	GetByName(name string) (*models.SlashCommand, error)
+	List(activeOnly bool) ([]*models.SlashCommand, error)
+	Update(command *models.SlashCommand) error
+	Delete(id uint) error
+}
+
+// slashCommandRepository implements SlashCommandRepository interface
+type slashCommandRepository struct {
+	db *database.DB
+}
+
+// NewSlashCommandRepository creates a new slash command repository
+func NewSlashCommandRepository(db *database.DB) SlashCommandRepository {
+	return &slashCommandRepository{
+		db: db,
+	}
+}
+
+// Create registers a new custom command
+func (r *slashCommandRepository) Create(command *models.SlashCommand) error {
+	if err := r.db.Create(command).Error; err != nil {
+		return fmt.Errorf("failed to create slash command: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a custom command by ID
+func (r *slashCommandRepository) GetByID(id uint) (*models.SlashCommand, error) {
+	var command models.SlashCommand
+	err := r.db.First(&command, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("slash command not found")
+		}
+		return nil, fmt.Errorf("failed to get slash command: %w", err)
+	}
+	return &command, nil
+}
+
+// GetByName retrieves a custom command by its name without the leading slash
+func (r *slashCommandRepository) GetByName(name string) (*models.SlashCommand, error) {
+	var command models.SlashCommand
+	err := r.db.Where("name = ?", name).First(&command).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("slash command not found")
+		}
+		return nil, fmt.Errorf("failed to get slash command: %w", err)
+	}
+	return &command, nil
+}
+
+// List retrieves custom commands ordered by name
+func (r *slashCommandRepository) List(activeOnly bool) ([]*models.SlashCommand, error) {
+	query := r.db.Order("name")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var commands []*models.SlashCommand
+	if err := query.Find(&commands).Error; err != nil {
+		return nil, fmt.Errorf("failed to get slash commands: %w", err)
+	}
+	return commands, nil
+}
+
+// Update updates an existing custom command
+func (r *slashCommandRepository) Update(command *models.SlashCommand) error {
+	result := r.db.Save(command)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update slash command: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("slash command not found")
+	}
+	return nil
+}
+
+// Delete permanently deletes a custom command so that its name can be registered again
+func (r *slashCommandRepository) Delete(id uint) error {
+	result := r.db.Unscoped().Delete(&models.SlashCommand{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete slash command: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("slash command not found")
+	}
+	return nil
+}
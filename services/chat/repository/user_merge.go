@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeUsers moves chat memberships, the membership log, messages, reactions and read receipts of
+// a duplicate account to the primary account. In chats both accounts belong to, the primary
+// membership becomes owner if the duplicate owned the chat and stays active if either membership
+// was active.
+func (r *chatRepository) MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error) {
+	result := sharedmodels.NewMergeUsersResult("chat")
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Carry ownership and active state over to memberships the primary account already has
+		ownedChats := tx.Model(&models.ChatMember{}).Select("chat_id").
+			Where("user_id = ? AND role = ?", duplicateID, models.ChatMemberRoleOwner)
+		err := tx.Model(&models.ChatMember{}).
+			Where("user_id = ? AND chat_id IN (?)", primaryID, ownedChats).
+			Update("role", models.ChatMemberRoleOwner).Error
+		if err != nil {
+			return fmt.Errorf("failed to transfer chat ownership: %w", err)
+		}
+
+		activeChats := tx.Model(&models.ChatMember{}).Select("chat_id").
+			Where("user_id = ? AND is_active = ?", duplicateID, true)
+		err = tx.Model(&models.ChatMember{}).
+			Where("user_id = ? AND is_active = ? AND chat_id IN (?)", primaryID, false, activeChats).
+			Updates(map[string]interface{}{"is_active": true, "left_at": nil}).Error
+		if err != nil {
+			return fmt.Errorf("failed to reactivate chat memberships: %w", err)
+		}
+
+		reassignments := []struct {
+			kind         string
+			model        interface{}
+			userColumn   string
+			scopeColumns []string
+		}{
+			{"chat_memberships", &models.ChatMember{}, "user_id", []string{"chat_id"}},
+			{"created_chats", &models.Chat{}, "creator_id", nil},
+			{"messages", &models.Message{}, "sender_id", nil},
+			{"archived_messages", &models.ArchivedMessage{}, "sender_id", nil},
+			{"message_reactions", &models.MessageReaction{}, "user_id", []string{"message_id", "emoji"}},
+			{"read_receipts", &models.MessageReadReceipt{}, "user_id", []string{"message_id"}},
+			{"membership_events", &models.ChatMembershipEvent{}, "user_id", nil},
+			{"membership_event_actors", &models.ChatMembershipEvent{}, "actor_id", nil},
+		}
+
+		for _, reassignment := range reassignments {
+			moved, dropped, err := database.ReassignUser(tx, reassignment.model, reassignment.userColumn,
+				reassignment.scopeColumns, duplicateID, primaryID)
+			if err != nil {
+				return fmt.Errorf("failed to merge %s: %w", reassignment.kind, err)
+			}
+			result.Moved[reassignment.kind] = moved
+			if dropped > 0 {
+				result.Dropped[reassignment.kind] = dropped
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
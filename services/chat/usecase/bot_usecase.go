@@ -0,0 +1,527 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/redis"
+)
+
+const (
+	botTokenPrefix       = "tcb_" // Префикс API токенов ботов
+	botTokenPrefixLength = 12     // Сколько символов токена хранится для отображения
+)
+
+// BotUsecase defines the interface for bot business logic
+type BotUsecase interface {
+	// Bot management by owners
+	CreateBot(userID uint, req *models.CreateBotRequest) (*models.BotCredentialsResponse, error)
+	GetUserBots(userID uint) ([]*models.BotResponse, error)
+	GetBot(userID, botID uint) (*models.BotResponse, error)
+	UpdateBot(userID, botID uint, req *models.UpdateBotRequest) (*models.BotResponse, error)
+	DeleteBot(userID, botID uint) error
+	RegenerateToken(userID, botID uint) (*models.BotCredentialsResponse, error)
+	GetDeliveries(userID, botID uint, limit, offset int) ([]*models.BotEventDelivery, int64, error)
+
+	// Chat installations managed by users
+	GetChatBots(userID, chatID uint) ([]*models.ChatBotResponse, error)
+	RemoveBotFromChat(userID, chatID, botID uint) error
+
+	// Bot API, called with an authenticated bot
+	AuthenticateBot(token string) (*models.Bot, error)
+	JoinChat(bot *models.Bot, chatID uint) error
+	LeaveChat(bot *models.Bot, chatID uint) error
+	SendMessage(bot *models.Bot, chatID uint, req *models.BotMessageRequest) (*models.MessageResponse, error)
+
+	BotEventDispatcher
+}
+
+// botUsecase implements BotUsecase interface
+type botUsecase struct {
+	botRepo     repository.BotRepository
+	chatRepo    repository.ChatRepository
+	messageRepo repository.MessageRepository
+	webhooks    *botWebhookSender
+	unread      *redis.UnreadCounter
+}
+
+// NewBotUsecase creates a new bot usecase.
+// unread may be nil if unread counts are not cached.
+func NewBotUsecase(botRepo repository.BotRepository, chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, unread *redis.UnreadCounter) BotUsecase {
+	return &botUsecase{
+		botRepo:     botRepo,
+		chatRepo:    chatRepo,
+		messageRepo: messageRepo,
+		webhooks:    newBotWebhookSender(botRepo),
+		unread:      unread,
+	}
+}
+
+// CreateBot creates a bot owned by user and returns its credentials
+func (uc *botUsecase) CreateBot(userID uint, req *models.CreateBotRequest) (*models.BotCredentialsResponse, error) {
+	if err := uc.validateCreateBotRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	token, tokenHash, err := generateBotToken()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := strings.TrimSpace(req.DisplayName)
+	if displayName == "" {
+		displayName = strings.TrimSpace(req.Name)
+	}
+
+	bot := &models.Bot{
+		OwnerID:       userID,
+		Name:          strings.TrimSpace(req.Name),
+		DisplayName:   displayName,
+		AvatarURL:     strings.TrimSpace(req.AvatarURL),
+		Description:   strings.TrimSpace(req.Description),
+		TokenHash:     tokenHash,
+		TokenPrefix:   token[:botTokenPrefixLength],
+		WebhookURL:    strings.TrimSpace(req.WebhookURL),
+		WebhookSecret: secret,
+		IsActive:      true,
+	}
+
+	if err := uc.botRepo.Create(bot); err != nil {
+		return nil, fmt.Errorf("failed to create bot: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"bot_id":   bot.ID,
+		"owner_id": userID,
+	}).Info("Bot created")
+
+	return &models.BotCredentialsResponse{
+		Bot:           bot.ToResponse(),
+		Token:         token,
+		WebhookSecret: secret,
+	}, nil
+}
+
+// GetUserBots retrieves bots owned by user
+func (uc *botUsecase) GetUserBots(userID uint) ([]*models.BotResponse, error) {
+	bots, err := uc.botRepo.GetByOwnerID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bots: %w", err)
+	}
+
+	responses := make([]*models.BotResponse, len(bots))
+	for i, bot := range bots {
+		responses[i] = bot.ToResponse()
+	}
+	return responses, nil
+}
+
+// GetBot retrieves a bot owned by user
+func (uc *botUsecase) GetBot(userID, botID uint) (*models.BotResponse, error) {
+	bot, err := uc.getOwnedBot(userID, botID)
+	if err != nil {
+		return nil, err
+	}
+	return bot.ToResponse(), nil
+}
+
+// UpdateBot updates bot profile and webhook settings
+func (uc *botUsecase) UpdateBot(userID, botID uint, req *models.UpdateBotRequest) (*models.BotResponse, error) {
+	bot, err := uc.getOwnedBot(userID, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("validation failed: name cannot be empty")
+		}
+		bot.Name = name
+	}
+	if req.DisplayName != nil {
+		bot.DisplayName = strings.TrimSpace(*req.DisplayName)
+		if bot.DisplayName == "" {
+			bot.DisplayName = bot.Name
+		}
+	}
+	if req.AvatarURL != nil {
+		bot.AvatarURL = strings.TrimSpace(*req.AvatarURL)
+	}
+	if req.Description != nil {
+		bot.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.WebhookURL != nil {
+		webhookURL := strings.TrimSpace(*req.WebhookURL)
+		if webhookURL != "" {
+			if err := validateWebhookURL(webhookURL); err != nil {
+				return nil, fmt.Errorf("validation failed: %w", err)
+			}
+		}
+		bot.WebhookURL = webhookURL
+	}
+	if req.IsActive != nil {
+		bot.IsActive = *req.IsActive
+	}
+
+	if err := uc.botRepo.Update(bot); err != nil {
+		return nil, fmt.Errorf("failed to update bot: %w", err)
+	}
+
+	return bot.ToResponse(), nil
+}
+
+// DeleteBot deletes a bot and removes it from all chats
+func (uc *botUsecase) DeleteBot(userID, botID uint) error {
+	if _, err := uc.getOwnedBot(userID, botID); err != nil {
+		return err
+	}
+
+	if err := uc.botRepo.Delete(botID); err != nil {
+		return fmt.Errorf("failed to delete bot: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"bot_id":   botID,
+		"owner_id": userID,
+	}).Info("Bot deleted")
+
+	return nil
+}
+
+// RegenerateToken issues a new API token and webhook secret, the old ones stop working immediately
+func (uc *botUsecase) RegenerateToken(userID, botID uint) (*models.BotCredentialsResponse, error) {
+	bot, err := uc.getOwnedBot(userID, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := generateBotToken()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	bot.TokenHash = tokenHash
+	bot.TokenPrefix = token[:botTokenPrefixLength]
+	bot.WebhookSecret = secret
+
+	if err := uc.botRepo.Update(bot); err != nil {
+		return nil, fmt.Errorf("failed to update bot: %w", err)
+	}
+
+	return &models.BotCredentialsResponse{
+		Bot:           bot.ToResponse(),
+		Token:         token,
+		WebhookSecret: secret,
+	}, nil
+}
+
+// GetDeliveries retrieves webhook delivery receipts of a bot
+func (uc *botUsecase) GetDeliveries(userID, botID uint, limit, offset int) ([]*models.BotEventDelivery, int64, error) {
+	if _, err := uc.getOwnedBot(userID, botID); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, total, err := uc.botRepo.GetDeliveries(botID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// GetChatBots retrieves bots installed in a chat
+func (uc *botUsecase) GetChatBots(userID, chatID uint) ([]*models.ChatBotResponse, error) {
+	isMember, err := uc.chatRepo.IsMember(chatID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of this chat")
+	}
+
+	chatBots, err := uc.botRepo.GetChatBots(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat bots: %w", err)
+	}
+
+	responses := make([]*models.ChatBotResponse, len(chatBots))
+	for i, chatBot := range chatBots {
+		responses[i] = chatBot.ToResponse()
+	}
+	return responses, nil
+}
+
+// RemoveBotFromChat removes a bot from a chat. Allowed for chat admins and the bot owner.
+func (uc *botUsecase) RemoveBotFromChat(userID, chatID, botID uint) error {
+	bot, err := uc.botRepo.GetByID(botID)
+	if err != nil {
+		return err
+	}
+
+	if bot.OwnerID != userID {
+		hasAccess, err := uc.chatRepo.HasAdminAccess(chatID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to check permissions: %w", err)
+		}
+		if !hasAccess {
+			return fmt.Errorf("insufficient permissions to remove bot")
+		}
+	}
+
+	if err := uc.botRepo.RemoveFromChat(chatID, botID); err != nil {
+		return err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"bot_id":  botID,
+		"chat_id": chatID,
+		"user_id": userID,
+	}).Info("Bot removed from chat")
+
+	return nil
+}
+
+// AuthenticateBot resolves an active bot by its API token
+func (uc *botUsecase) AuthenticateBot(token string) (*models.Bot, error) {
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, botTokenPrefix) {
+		return nil, fmt.Errorf("invalid bot token")
+	}
+
+	bot, err := uc.botRepo.GetByTokenHash(hashBotToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bot token")
+	}
+
+	if !bot.IsActive {
+		return nil, fmt.Errorf("bot is inactive")
+	}
+
+	// Usage tracking must not block the request
+	if err := uc.botRepo.TouchLastUsed(bot.ID); err != nil {
+		logger.WithField("bot_id", bot.ID).Warnf("Failed to update bot last used time: %v", err)
+	}
+
+	return bot, nil
+}
+
+// JoinChat installs a bot in a group chat or channel.
+// The bot owner must be an admin of the chat.
+func (uc *botUsecase) JoinChat(bot *models.Bot, chatID uint) error {
+	chat, err := uc.chatRepo.GetByID(chatID)
+	if err != nil {
+		return err
+	}
+
+	if !chat.IsActive {
+		return fmt.Errorf("chat not found")
+	}
+
+	if chat.Type == models.ChatTypePrivate {
+		return fmt.Errorf("bots cannot join private chats")
+	}
+
+	hasAccess, err := uc.chatRepo.HasAdminAccess(chatID, bot.OwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if !hasAccess {
+		return fmt.Errorf("insufficient permissions: bot owner must be a chat admin")
+	}
+
+	chatBot := &models.ChatBot{
+		ChatID:  chatID,
+		BotID:   bot.ID,
+		AddedBy: bot.OwnerID,
+	}
+
+	if err := uc.botRepo.AddToChat(chatBot); err != nil {
+		return err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"bot_id":  bot.ID,
+		"chat_id": chatID,
+	}).Info("Bot joined chat")
+
+	return nil
+}
+
+// LeaveChat removes a bot from a chat
+func (uc *botUsecase) LeaveChat(bot *models.Bot, chatID uint) error {
+	return uc.botRepo.RemoveFromChat(chatID, bot.ID)
+}
+
+// SendMessage posts a message from a bot to a chat it has joined
+func (uc *botUsecase) SendMessage(bot *models.Bot, chatID uint, req *models.BotMessageRequest) (*models.MessageResponse, error) {
+	if strings.TrimSpace(req.Content) == "" {
+		return nil, fmt.Errorf("validation failed: content is required")
+	}
+
+	isInChat, err := uc.botRepo.IsInChat(chatID, bot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isInChat {
+		return nil, fmt.Errorf("bot is not a member of this chat")
+	}
+
+	if req.ReplyToID != nil {
+		replyMsg, err := uc.messageRepo.GetByID(*req.ReplyToID)
+		if err != nil {
+			return nil, fmt.Errorf("reply-to message not found")
+		}
+		if replyMsg.ChatID != chatID {
+			return nil, fmt.Errorf("reply-to message is not in the same chat")
+		}
+	}
+
+	senderName := strings.TrimSpace(req.DisplayName)
+	if senderName == "" {
+		senderName = bot.DisplayName
+	}
+	senderAvatar := strings.TrimSpace(req.AvatarURL)
+	if senderAvatar == "" {
+		senderAvatar = bot.AvatarURL
+	}
+
+	botID := bot.ID
+	message := &models.Message{
+		ChatID:          chatID,
+		BotID:           &botID,
+		SenderName:      senderName,
+		SenderAvatarURL: senderAvatar,
+		Content:         strings.TrimSpace(req.Content),
+		ContentFormat:   req.ContentFormat,
+		Type:            models.MessageTypeText,
+		Status:          models.MessageStatusSent,
+		ReplyToID:       req.ReplyToID,
+	}
+
+	if err := applyContentFormat(message); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := uc.messageRepo.Create(message); err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	countNewMessage(uc.unread, uc.chatRepo, message)
+	uc.DispatchMessageCreated(message)
+
+	return message.ToResponse(), nil
+}
+
+// DispatchMessageCreated sends message.created events to webhooks of bots installed in the chat.
+// Delivery happens in background, the author bot doesn't receive its own messages.
+func (uc *botUsecase) DispatchMessageCreated(message *models.Message) {
+	bots, err := uc.botRepo.GetWebhookBotsForChat(message.ChatID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"chat_id":    message.ChatID,
+			"message_id": message.ID,
+			"error":      err.Error(),
+		}).Error("Failed to get bots for webhook dispatch")
+		return
+	}
+
+	for _, bot := range bots {
+		if message.BotID != nil && *message.BotID == bot.ID {
+			continue
+		}
+		go uc.webhooks.deliver(bot, models.BotEventMessageCreated, message)
+	}
+}
+
+// getOwnedBot retrieves a bot and checks that user owns it
+func (uc *botUsecase) getOwnedBot(userID, botID uint) (*models.Bot, error) {
+	bot, err := uc.botRepo.GetByID(botID)
+	if err != nil {
+		return nil, err
+	}
+	if bot.OwnerID != userID {
+		return nil, fmt.Errorf("insufficient permissions: only bot owner can manage the bot")
+	}
+	return bot, nil
+}
+
+// validateCreateBotRequest validates bot creation request
+func (uc *botUsecase) validateCreateBotRequest(req *models.CreateBotRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if webhookURL := strings.TrimSpace(req.WebhookURL); webhookURL != "" {
+		if err := validateWebhookURL(webhookURL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateWebhookURL checks that webhook URL is an absolute http(s) URL outside the internal network
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" {
+		return fmt.Errorf("invalid webhook URL")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("webhook URL must use http or https")
+	}
+	return validateWebhookHost(parsed.Hostname())
+}
+
+// generateBotToken generates a new API token and its hash
+func generateBotToken() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate bot token: %w", err)
+	}
+	token := botTokenPrefix + hex.EncodeToString(bytes)
+	return token, hashBotToken(token), nil
+}
+
+// generateSecret generates a random webhook signing secret
+func generateSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// hashBotToken returns hex encoded SHA-256 of a token
+func hashBotToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/shared/logger"
+)
+
+const (
+	botWebhookTimeout = 10 * time.Second
+	maxWebhookError   = 500 // Максимальная длина сохраняемой ошибки доставки
+)
+
+// BotEventDispatcher delivers chat events to bots installed in the chat
+type BotEventDispatcher interface {
+	DispatchMessageCreated(message *models.Message)
+}
+
+// botWebhookSender sends signed events to bot webhooks and records delivery receipts
+type botWebhookSender struct {
+	botRepo repository.BotRepository
+	client  *http.Client
+}
+
+// newBotWebhookSender creates a new webhook sender
+func newBotWebhookSender(botRepo repository.BotRepository) *botWebhookSender {
+	return &botWebhookSender{
+		botRepo: botRepo,
+		client:  newWebhookHTTPClient(botWebhookTimeout),
+	}
+}
+
+// deliver sends a single event to bot webhook without retries.
+// The body is signed with HMAC-SHA256 of the bot webhook secret (X-Tachyon-Signature header).
+func (s *botWebhookSender) deliver(bot *models.Bot, event models.BotEventType, message *models.Message) {
+	delivery := &models.BotEventDelivery{
+		BotID:     bot.ID,
+		ChatID:    message.ChatID,
+		MessageID: message.ID,
+		Event:     event,
+	}
+
+	started := time.Now()
+	statusCode, err := s.post(bot, event, message)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.StatusCode = statusCode
+	delivery.Success = err == nil
+
+	if err != nil {
+		delivery.Error = err.Error()
+		if len(delivery.Error) > maxWebhookError {
+			delivery.Error = delivery.Error[:maxWebhookError]
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"bot_id":      bot.ID,
+			"chat_id":     message.ChatID,
+			"message_id":  message.ID,
+			"event":       event,
+			"status_code": statusCode,
+			"error":       err.Error(),
+		}).Warn("Bot webhook delivery failed")
+	}
+
+	if err := s.botRepo.CreateDelivery(delivery); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"bot_id":     bot.ID,
+			"message_id": message.ID,
+			"error":      err.Error(),
+		}).Error("Failed to save bot webhook delivery")
+	}
+}
+
+// post sends event payload and returns HTTP status code of the response
+func (s *botWebhookSender) post(bot *models.Bot, event models.BotEventType, message *models.Message) (int, error) {
+	payload := &models.BotEventPayload{
+		Event:     event,
+		BotID:     bot.ID,
+		ChatID:    message.ChatID,
+		Message:   message.ToResponse(),
+		Timestamp: time.Now().UTC(),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, bot.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tachyon-Bot-Webhook/1.0")
+	req.Header.Set("X-Tachyon-Event", string(event))
+	req.Header.Set("X-Tachyon-Signature", "sha256="+signWebhookPayload(bot.WebhookSecret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// signWebhookPayload returns hex encoded HMAC-SHA256 signature of body
+func signWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
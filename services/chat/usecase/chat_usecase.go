@@ -8,6 +8,10 @@ import (
 
 	"tachyon-messenger/services/chat/models"
 	"tachyon-messenger/services/chat/repository"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/refs"
 
 	"gorm.io/gorm"
 )
@@ -15,10 +19,13 @@ import (
 // ChatUsecase defines the interface for chat business logic
 type ChatUsecase interface {
 	CreateChat(userID uint, req *models.CreateChatRequest) (*models.ChatResponse, error)
-	GetUserChats(userID uint, limit, offset int) (*models.ChatListResponse, error)
+	GetUserChats(userID uint, page *query.Params) (*models.ChatListResponse, error)
 	GetChat(userID, chatID uint) (*models.ChatResponse, error)
 	UpdateChat(userID, chatID uint, req *models.UpdateChatRequest) (*models.ChatResponse, error)
 	DeleteChat(userID, chatID uint) error
+	GetDeletedChats(userID uint, page *query.Params) (*models.ChatListResponse, error)
+	RestoreChat(userID, chatID uint) (*models.ChatResponse, error)
+	PurgeDeletedChats(retention time.Duration) (int64, error)
 	AddMember(userID, chatID uint, req *models.AddChatMemberRequest) error
 	RemoveMember(userID, chatID, targetUserID uint) error
 	GetChatMembers(userID, chatID uint) ([]models.ChatMemberResponse, error)
@@ -26,12 +33,25 @@ type ChatUsecase interface {
 	CreatePersonalChat(userID, targetUserID uint) (*models.ChatResponse, error)
 	CreateGroupChat(userID uint, req *models.CreateGroupChatRequest) (*models.ChatResponse, error)
 	JoinChat(userID, chatID uint) error
+	GetUnreadCounts(userID uint) (*models.UnreadCountsResponse, error)
+	ReconcileUnreadCounts() (int, error)
+	MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error)
+	HandleDepartmentEvent(event *sharedmodels.DepartmentEvent) error
+	AddUserToChats(req *models.AddUserToChatsRequest) ([]uint, error)
+	ReconcileDepartmentChats() (int, error)
+	GetChatMembershipEvents(chatID uint, page *query.Params) (*models.MembershipEventListResponse, error)
+	GetUserMembershipEvents(userID uint, page *query.Params) (*models.MembershipEventListResponse, error)
+	GetMessageAudience(messageID uint, at time.Time) (*models.MessageAudienceResponse, error)
+	GetDigestStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.MessagesDigestStats, error)
 }
 
 // chatUsecase implements ChatUsecase interface
 type chatUsecase struct {
 	chatRepo    repository.ChatRepository
 	messageRepo repository.MessageRepository
+	unread      *redis.UnreadCounter
+	userRefs    *refs.Validator
+	departments DepartmentDirectory
 }
 
 func (uc *chatUsecase) CreatePersonalChat(userID, targetUserID uint) (*models.ChatResponse, error) {
@@ -39,6 +59,9 @@ func (uc *chatUsecase) CreatePersonalChat(userID, targetUserID uint) (*models.Ch
 	if userID == targetUserID {
 		return nil, fmt.Errorf("cannot create personal chat with yourself")
 	}
+	if err := uc.userRefs.CheckUsers(targetUserID); err != nil {
+		return nil, err
+	}
 
 	// Check if personal chat already exists between these users
 	existingChats, err := uc.chatRepo.GetByUserID(userID, 100, 0)
@@ -88,6 +111,7 @@ func (uc *chatUsecase) CreatePersonalChat(userID, targetUserID uint) (*models.Ch
 	if err := uc.chatRepo.AddMember(member); err != nil {
 		return nil, fmt.Errorf("failed to add target user to personal chat: %w", err)
 	}
+	uc.recordMembershipEvents(ownerJoinedEvent(chat), joinedEvent(member, userID))
 
 	// Get chat with members for response
 	chatWithMembers, err := uc.chatRepo.GetWithMembers(chat.ID)
@@ -112,6 +136,9 @@ func (uc *chatUsecase) CreateGroupChat(userID uint, req *models.CreateGroupChatR
 			memberIDs = append(memberIDs, memberID)
 		}
 	}
+	if err := uc.userRefs.CheckUsers(memberIDs...); err != nil {
+		return nil, err
+	}
 
 	// Create group chat
 	chat := &models.Chat{
@@ -127,6 +154,7 @@ func (uc *chatUsecase) CreateGroupChat(userID uint, req *models.CreateGroupChatR
 	}
 
 	// Add all members to the chat
+	events := []*models.ChatMembershipEvent{ownerJoinedEvent(chat)}
 	for _, memberID := range memberIDs {
 		member := &models.ChatMember{
 			ChatID:   chat.ID,
@@ -140,7 +168,9 @@ func (uc *chatUsecase) CreateGroupChat(userID uint, req *models.CreateGroupChatR
 			// Log error but continue adding other members
 			continue
 		}
+		events = append(events, joinedEvent(member, userID))
 	}
+	uc.recordMembershipEvents(events...)
 
 	// Get chat with members for response
 	chatWithMembers, err := uc.chatRepo.GetWithMembers(chat.ID)
@@ -177,6 +207,9 @@ func (uc *chatUsecase) JoinChat(userID, chatID uint) error {
 	if chat.Type == models.ChatTypePrivate {
 		return fmt.Errorf("cannot join private chat")
 	}
+	if chat.IsDepartmentChat() {
+		return fmt.Errorf("cannot join department chat of another department")
+	}
 
 	// For group chats, check if user can join (add business logic as needed)
 	if chat.Type == models.ChatTypeGroup {
@@ -204,6 +237,10 @@ func (uc *chatUsecase) JoinChat(userID, chatID uint) error {
 	if err := uc.chatRepo.AddMember(member); err != nil {
 		return fmt.Errorf("failed to join chat: %w", err)
 	}
+	uc.recordMembershipEvents(joinedEvent(member, userID))
+
+	// Chat history is unread for the new member
+	invalidateUnreadCounts(uc.unread, userID)
 
 	return nil
 }
@@ -219,6 +256,15 @@ func (uc *chatUsecase) LeaveChat(userID, chatID uint) error {
 		return fmt.Errorf("user is not a member of this chat")
 	}
 
+	// Members of a department stay in its chat until they leave the department
+	isManaged, err := uc.chatRepo.IsManagedMember(chatID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check membership: %w", err)
+	}
+	if isManaged {
+		return fmt.Errorf("cannot leave department chat while in the department")
+	}
+
 	// Get user role
 	role, err := uc.chatRepo.GetMemberRole(chatID, userID)
 	if err != nil {
@@ -268,6 +314,7 @@ func (uc *chatUsecase) LeaveChat(userID, chatID uint) error {
 
 		if newOwner != nil {
 			// Promote to owner
+			previousRole := newOwner.Role
 			newOwner.Role = models.ChatMemberRoleOwner
 			// Note: You might need to implement UpdateMember method in repository
 			// For now, we'll remove and re-add with new role
@@ -278,6 +325,8 @@ func (uc *chatUsecase) LeaveChat(userID, chatID uint) error {
 			if err := uc.chatRepo.AddMember(newOwner); err != nil {
 				return fmt.Errorf("failed to set new owner: %w", err)
 			}
+			uc.recordMembershipEvents(newMembershipEvent(models.MembershipEventRoleChanged, chatID, newOwner.UserID,
+				models.ChatMemberRoleOwner, previousRole, userID))
 		}
 	}
 
@@ -285,6 +334,11 @@ func (uc *chatUsecase) LeaveChat(userID, chatID uint) error {
 	if err := uc.chatRepo.RemoveMember(chatID, userID); err != nil {
 		return fmt.Errorf("failed to leave chat: %w", err)
 	}
+	uc.recordMembershipEvents(newMembershipEvent(models.MembershipEventLeft, chatID, userID, "", role, userID))
+
+	if err := uc.unread.RemoveChat(chatID, userID); err != nil {
+		invalidateUnreadCounts(uc.unread, userID)
+	}
 
 	return nil
 }
@@ -318,11 +372,16 @@ func (uc *chatUsecase) validateCreateGroupChatRequest(req *models.CreateGroupCha
 	return nil
 }
 
-// NewChatUsecase creates a new chat usecase
-func NewChatUsecase(chatRepo repository.ChatRepository, messageRepo repository.MessageRepository) ChatUsecase {
+// NewChatUsecase creates a new chat usecase.
+// unread may be nil if unread counts are not cached, userRefs may be nil to store member IDs unchecked.
+// departments may be nil, department chats then only follow user moves and are not reconciled.
+func NewChatUsecase(chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, unread *redis.UnreadCounter, userRefs *refs.Validator, departments DepartmentDirectory) ChatUsecase {
 	return &chatUsecase{
 		chatRepo:    chatRepo,
 		messageRepo: messageRepo,
+		unread:      unread,
+		userRefs:    userRefs,
+		departments: departments,
 	}
 }
 
@@ -334,6 +393,9 @@ func (uc *chatUsecase) CreateChat(userID uint, req *models.CreateChatRequest) (*
 	if err := uc.validateCreateChatRequest(req); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := uc.userRefs.CheckUsers(req.MemberIDs...); err != nil {
+		return nil, err
+	}
 
 	// For private chats, ensure only 2 members (including creator)
 	if req.Type == models.ChatTypePrivate {
@@ -384,6 +446,7 @@ func (uc *chatUsecase) CreateChat(userID uint, req *models.CreateChatRequest) (*
 
 	// ИСПРАВЛЕНИЕ: Исключаем создателя из списка участников
 	// AfterCreate хук уже добавляет создателя как owner
+	events := []*models.ChatMembershipEvent{ownerJoinedEvent(chat)}
 	for _, memberID := range req.MemberIDs {
 		// Пропускаем создателя чата - он уже добавлен как owner
 		if memberID == userID {
@@ -398,9 +461,13 @@ func (uc *chatUsecase) CreateChat(userID uint, req *models.CreateChatRequest) (*
 			IsActive: true,
 		}
 		if err := uc.chatRepo.AddMember(member); err != nil {
+			// Members added so far stay in the chat
+			uc.recordMembershipEvents(events...)
 			return nil, fmt.Errorf("failed to add member %d: %w", memberID, err)
 		}
+		events = append(events, joinedEvent(member, userID))
 	}
+	uc.recordMembershipEvents(events...)
 
 	// Get chat with members for response
 	chatWithMembers, err := uc.chatRepo.GetWithMembers(chat.ID)
@@ -412,32 +479,18 @@ func (uc *chatUsecase) CreateChat(userID uint, req *models.CreateChatRequest) (*
 }
 
 // GetUserChats retrieves all chats for a user
-func (uc *chatUsecase) GetUserChats(userID uint, limit, offset int) (*models.ChatListResponse, error) {
-	// Set default pagination
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
+func (uc *chatUsecase) GetUserChats(userID uint, page *query.Params) (*models.ChatListResponse, error) {
+	// Set default pagination and sorting
+	if page == nil {
+		page = query.Default(models.ChatListOptions)
 	}
 
-	chats, total, err := uc.chatRepo.GetUserChats(userID, limit, offset)
+	chats, total, err := uc.chatRepo.GetUserChats(userID, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user chats: %w", err)
 	}
 
-	// Convert to response format
-	chatResponses := make([]models.ChatResponse, len(chats))
-	for i, chat := range chats {
-		chatResponses[i] = *chat.ToResponse()
-	}
-
-	return &models.ChatListResponse{
-		Chats:  chatResponses,
-		Total:  total,
-		Limit:  limit,
-		Offset: offset,
-	}, nil
+	return newChatListResponse(chats, total, page), nil
 }
 
 // GetChat retrieves a specific chat
@@ -515,13 +568,95 @@ func (uc *chatUsecase) DeleteChat(userID, chatID uint) error {
 		return fmt.Errorf("only chat owner can delete the chat")
 	}
 
+	memberIDs, err := uc.chatRepo.GetMemberIDs(chatID)
+	if err != nil {
+		return fmt.Errorf("failed to get chat members: %w", err)
+	}
+
 	if err := uc.chatRepo.Delete(chatID); err != nil {
 		return fmt.Errorf("failed to delete chat: %w", err)
 	}
 
+	if err := uc.unread.RemoveChat(chatID, memberIDs...); err != nil {
+		invalidateUnreadCounts(uc.unread, memberIDs...)
+	}
+
 	return nil
 }
 
+// GetDeletedChats retrieves chats deleted by their owner that can still be restored
+func (uc *chatUsecase) GetDeletedChats(userID uint, page *query.Params) (*models.ChatListResponse, error) {
+	// Set default pagination and sorting
+	if page == nil {
+		page = query.Default(models.ChatTrashListOptions)
+	}
+
+	chats, total, err := uc.chatRepo.GetDeletedChats(userID, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted chats: %w", err)
+	}
+
+	return newChatListResponse(chats, total, page), nil
+}
+
+// newChatListResponse converts a page of chats to response format
+func newChatListResponse(chats []*models.Chat, total int64, page *query.Params) *models.ChatListResponse {
+	chatResponses := make([]models.ChatResponse, len(chats))
+	for i, chat := range chats {
+		chatResponses[i] = *chat.ToResponse()
+	}
+
+	return &models.ChatListResponse{
+		Chats:      chatResponses,
+		Total:      total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		NextCursor: page.NextCursor(chatResponses),
+	}
+}
+
+// RestoreChat restores a deleted chat from trash
+func (uc *chatUsecase) RestoreChat(userID, chatID uint) (*models.ChatResponse, error) {
+	if _, err := uc.chatRepo.GetDeletedByID(chatID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("chat not found")
+		}
+		return nil, fmt.Errorf("failed to get chat: %w", err)
+	}
+
+	// Only owner can restore, as only owner can delete
+	role, err := uc.chatRepo.GetMemberRole(chatID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user role: %w", err)
+	}
+	if role != models.ChatMemberRoleOwner {
+		return nil, fmt.Errorf("only chat owner can restore the chat")
+	}
+
+	if err := uc.chatRepo.Restore(chatID); err != nil {
+		return nil, fmt.Errorf("failed to restore chat: %w", err)
+	}
+
+	// Restored chat is missing from cached unread counters of its members
+	invalidateChatUnreadCounts(uc.unread, uc.chatRepo, chatID)
+
+	chat, err := uc.chatRepo.GetWithMembers(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored chat: %w", err)
+	}
+
+	return chat.ToResponse(), nil
+}
+
+// PurgeDeletedChats permanently deletes chats that stayed in trash longer than retention
+func (uc *chatUsecase) PurgeDeletedChats(retention time.Duration) (int64, error) {
+	purged, err := uc.chatRepo.PurgeDeleted(time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted chats: %w", err)
+	}
+	return purged, nil
+}
+
 // AddMember adds a member to a chat
 func (uc *chatUsecase) AddMember(userID, chatID uint, req *models.AddChatMemberRequest) error {
 	// Check if user has permission to add members
@@ -541,6 +676,9 @@ func (uc *chatUsecase) AddMember(userID, chatID uint, req *models.AddChatMemberR
 	if isMember {
 		return fmt.Errorf("user is already a member of this chat")
 	}
+	if err := uc.userRefs.CheckUsers(req.UserID); err != nil {
+		return err
+	}
 
 	// Set default role if not provided
 	memberRole := req.Role
@@ -560,10 +698,61 @@ func (uc *chatUsecase) AddMember(userID, chatID uint, req *models.AddChatMemberR
 	if err := uc.chatRepo.AddMember(member); err != nil {
 		return fmt.Errorf("failed to add member: %w", err)
 	}
+	uc.recordMembershipEvents(joinedEvent(member, userID))
+
+	// Chat history is unread for the new member
+	invalidateUnreadCounts(uc.unread, req.UserID)
 
 	return nil
 }
 
+// AddUserToChats adds a user to group chats and channels on behalf of another service. Chats that
+// don't exist, are private, inactive or provisioned for a department are skipped, as are chats
+// the user is already a member of. It returns IDs of chats the user joined.
+func (uc *chatUsecase) AddUserToChats(req *models.AddUserToChatsRequest) ([]uint, error) {
+	joined := []uint{}
+	for _, chatID := range req.ChatIDs {
+		chat, err := uc.chatRepo.GetByID(chatID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				continue
+			}
+			return joined, fmt.Errorf("failed to get chat: %w", err)
+		}
+		if !chat.IsActive || chat.Type == models.ChatTypePrivate || chat.IsDepartmentChat() {
+			continue
+		}
+
+		isMember, err := uc.chatRepo.IsMember(chatID, req.UserID)
+		if err != nil {
+			return joined, fmt.Errorf("failed to check membership: %w", err)
+		}
+		if isMember {
+			continue
+		}
+
+		member := &models.ChatMember{
+			ChatID:   chatID,
+			UserID:   req.UserID,
+			Role:     models.ChatMemberRoleMember,
+			JoinedAt: time.Now(),
+			IsActive: true,
+		}
+		if err := uc.chatRepo.AddMember(member); err != nil {
+			return joined, fmt.Errorf("failed to add member: %w", err)
+		}
+		uc.recordMembershipEvents(joinedEvent(member, 0))
+		joined = append(joined, chatID)
+	}
+
+	if len(joined) > 0 {
+		// Chat history is unread for the new member
+		invalidateUnreadCounts(uc.unread, req.UserID)
+	}
+
+	return joined, nil
+}
+
 // RemoveMember removes a member from a chat
 func (uc *chatUsecase) RemoveMember(userID, chatID, targetUserID uint) error {
 	// Check if user has permission to remove members
@@ -596,6 +785,11 @@ func (uc *chatUsecase) RemoveMember(userID, chatID, targetUserID uint) error {
 	if err := uc.chatRepo.RemoveMember(chatID, targetUserID); err != nil {
 		return fmt.Errorf("failed to remove member: %w", err)
 	}
+	uc.recordMembershipEvents(newMembershipEvent(models.MembershipEventRemoved, chatID, targetUserID, "", targetRole, userID))
+
+	if err := uc.unread.RemoveChat(chatID, targetUserID); err != nil {
+		invalidateUnreadCounts(uc.unread, targetUserID)
+	}
 
 	return nil
 }
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TaskCreator creates tasks in the task service on behalf of users
+type TaskCreator interface {
+	// CreateTask creates a task of the user and returns its ID
+	CreateTask(userID uint, title string) (uint, error)
+}
+
+// PollCreator creates polls in the poll service on behalf of users
+type PollCreator interface {
+	// CreatePoll creates a single choice poll of the user and returns its ID
+	CreatePoll(userID uint, question string, options []string) (uint, error)
+}
+
+// ReminderScheduler schedules reminder notifications in the notification service
+type ReminderScheduler interface {
+	// ScheduleReminder schedules a reminder of the user about a chat and returns the ID of the scheduled task
+	ScheduleReminder(userID, chatID uint, text string, at time.Time) (string, error)
+}
+
+// internalClient posts requests to internal endpoints of another service
+type internalClient struct {
+	service string
+	baseURL string
+	client  *http.Client
+}
+
+// newInternalClient creates a client of the service at baseURL, nil if baseURL is empty
+func newInternalClient(service, baseURL string) *internalClient {
+	if baseURL == "" {
+		return nil
+	}
+	return &internalClient{
+		service: service,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// post sends body to path and decodes the response into result
+func (c *internalClient) post(path string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", c.service, err)
+	}
+
+	resp, err := c.client.Post(c.baseURL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Details string `json:"details"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		if resp.StatusCode == http.StatusBadRequest && failure.Details != "" {
+			return fmt.Errorf("validation failed: %s", strings.TrimPrefix(failure.Details, "validation failed: "))
+		}
+		return fmt.Errorf("%s responded with status %d", c.service, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", c.service, err)
+	}
+	return nil
+}
+
+// httpTaskCreator creates tasks through the internal endpoint of the task service
+type httpTaskCreator struct {
+	client *internalClient
+}
+
+// NewHTTPTaskCreator creates a task creator for the task service at baseURL.
+// It returns nil if baseURL is empty, the /task command is then unavailable.
+func NewHTTPTaskCreator(baseURL string) TaskCreator {
+	client := newInternalClient("task service", baseURL)
+	if client == nil {
+		return nil
+	}
+	return &httpTaskCreator{client: client}
+}
+
+// CreateTask requests a new task from the task service
+func (c *httpTaskCreator) CreateTask(userID uint, title string) (uint, error) {
+	var response struct {
+		Task struct {
+			ID uint `json:"id"`
+		} `json:"task"`
+	}
+	err := c.client.post("/api/v1/internal/tasks", map[string]interface{}{
+		"user_id": userID,
+		"task":    map[string]interface{}{"title": title},
+	}, &response)
+	return response.Task.ID, err
+}
+
+// httpPollCreator creates polls through the internal endpoint of the poll service
+type httpPollCreator struct {
+	client *internalClient
+}
+
+// NewHTTPPollCreator creates a poll creator for the poll service at baseURL.
+// It returns nil if baseURL is empty, the /poll command is then unavailable.
+func NewHTTPPollCreator(baseURL string) PollCreator {
+	client := newInternalClient("poll service", baseURL)
+	if client == nil {
+		return nil
+	}
+	return &httpPollCreator{client: client}
+}
+
+// CreatePoll requests a new single choice poll from the poll service
+func (c *httpPollCreator) CreatePoll(userID uint, question string, options []string) (uint, error) {
+	pollOptions := make([]map[string]interface{}, 0, len(options))
+	for i, option := range options {
+		pollOptions = append(pollOptions, map[string]interface{}{"text": option, "position": i})
+	}
+
+	var response struct {
+		Poll struct {
+			ID uint `json:"id"`
+		} `json:"poll"`
+	}
+	err := c.client.post("/api/v1/internal/polls", map[string]interface{}{
+		"user_id": userID,
+		"poll": map[string]interface{}{
+			"title":        question,
+			"type":         "single_choice",
+			"show_results": true,
+			"options":      pollOptions,
+		},
+	}, &response)
+	return response.Poll.ID, err
+}
+
+// httpReminderScheduler schedules reminders through the internal endpoint of the notification service
+type httpReminderScheduler struct {
+	client *internalClient
+}
+
+// NewHTTPReminderScheduler creates a reminder scheduler for the notification service at baseURL.
+// It returns nil if baseURL is empty, the /remind command is then unavailable.
+func NewHTTPReminderScheduler(baseURL string) ReminderScheduler {
+	client := newInternalClient("notification service", baseURL)
+	if client == nil {
+		return nil
+	}
+	return &httpReminderScheduler{client: client}
+}
+
+// ScheduleReminder requests a reminder notification scheduled at the given time
+func (s *httpReminderScheduler) ScheduleReminder(userID, chatID uint, text string, at time.Time) (string, error) {
+	var response struct {
+		TaskID string `json:"task_id"`
+	}
+	err := s.client.post("/api/v1/internal/notifications/scheduled", map[string]interface{}{
+		"notification": map[string]interface{}{
+			"user_id":      userID,
+			"type":         "reminder",
+			"title":        text,
+			"related_id":   chatID,
+			"related_type": "chat",
+		},
+		"scheduled_at": at.UTC(),
+		"priority":     "medium",
+	}, &response)
+	return response.TaskID, err
+}
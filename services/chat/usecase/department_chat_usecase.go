@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// HandleDepartmentEvent keeps department chats in line with a department change published by the
+// user service. Departments get a group chat unless they opted out; users join the chat of their
+// department and leave the chat of the department they left. The chat of a merged department goes
+// over to the department it was merged into.
+func (uc *chatUsecase) HandleDepartmentEvent(event *sharedmodels.DepartmentEvent) error {
+	switch event.Type {
+	case sharedmodels.DepartmentEventCreated, sharedmodels.DepartmentEventUpdated:
+		if event.DepartmentID == nil {
+			return fmt.Errorf("validation failed: department_id is required")
+		}
+		if event.ChatDisabled {
+			return uc.detachDepartmentChat(*event.DepartmentID)
+		}
+
+		chat, err := uc.provisionDepartmentChat(*event.DepartmentID, event.DepartmentName)
+		if err != nil {
+			return err
+		}
+		_, err = uc.syncDepartmentChat(chat)
+		return err
+
+	case sharedmodels.DepartmentEventDeleted:
+		if event.DepartmentID == nil {
+			return fmt.Errorf("validation failed: department_id is required")
+		}
+		return uc.detachDepartmentChat(*event.DepartmentID)
+
+	case sharedmodels.DepartmentEventUserMoved:
+		if event.UserID == 0 {
+			return fmt.Errorf("validation failed: user_id is required")
+		}
+		if event.PreviousDepartmentID != nil {
+			if err := uc.leaveDepartmentChat(*event.PreviousDepartmentID, event.UserID); err != nil {
+				return err
+			}
+		}
+		if event.DepartmentID == nil || event.ChatDisabled {
+			return nil
+		}
+
+		chat, err := uc.provisionDepartmentChat(*event.DepartmentID, event.DepartmentName)
+		if err != nil {
+			return err
+		}
+		added, err := uc.chatRepo.AddDepartmentMember(chat.ID, event.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to add user to department chat: %w", err)
+		}
+		if added {
+			uc.recordMembershipEvents(newMembershipEvent(models.MembershipEventJoined, chat.ID, event.UserID,
+				models.ChatMemberRoleMember, "", 0))
+
+			// Chat history is unread for the new member
+			invalidateUnreadCounts(uc.unread, event.UserID)
+		}
+		return nil
+
+	case sharedmodels.DepartmentEventMerged:
+		if event.DepartmentID == nil || event.PreviousDepartmentID == nil {
+			return fmt.Errorf("validation failed: department_id and previous_department_id are required")
+		}
+		if event.ChatDisabled {
+			if err := uc.detachDepartmentChat(*event.PreviousDepartmentID); err != nil {
+				return err
+			}
+			return uc.detachDepartmentChat(*event.DepartmentID)
+		}
+		if err := uc.handOverDepartmentChat(*event.PreviousDepartmentID, *event.DepartmentID); err != nil {
+			return err
+		}
+
+		chat, err := uc.provisionDepartmentChat(*event.DepartmentID, event.DepartmentName)
+		if err != nil {
+			return err
+		}
+		_, err = uc.syncDepartmentChat(chat)
+		return err
+
+	default:
+		return fmt.Errorf("validation failed: unknown department event type %q", event.Type)
+	}
+}
+
+// ReconcileDepartmentChats syncs members of all department chats with the user service, catching up on
+// missed department events. It returns the number of users who joined or left department chats.
+func (uc *chatUsecase) ReconcileDepartmentChats() (int, error) {
+	if uc.departments == nil {
+		return 0, nil
+	}
+
+	chats, err := uc.chatRepo.GetDepartmentChats()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get department chats: %w", err)
+	}
+
+	reconciled, failed := 0, 0
+	for _, chat := range chats {
+		changed, err := uc.syncDepartmentChat(chat)
+		if err != nil {
+			failed++
+			logger.WithFields(map[string]interface{}{
+				"chat_id":       chat.ID,
+				"department_id": *chat.DepartmentID,
+				"error":         err.Error(),
+			}).Warn("Failed to sync department chat")
+			continue
+		}
+		reconciled += changed
+	}
+
+	if failed > 0 {
+		return reconciled, fmt.Errorf("failed to sync %d of %d department chats", failed, len(chats))
+	}
+	return reconciled, nil
+}
+
+// provisionDepartmentChat returns the chat of a department, creating it if the department has none.
+// An existing chat is renamed after the department.
+func (uc *chatUsecase) provisionDepartmentChat(departmentID uint, name string) (*models.Chat, error) {
+	name = strings.TrimSpace(name)
+
+	chat, err := uc.chatRepo.GetByDepartmentID(departmentID)
+	if err == nil {
+		if name != "" && chat.Name != name {
+			chat.Name = name
+			if err := uc.chatRepo.Update(chat); err != nil {
+				return nil, fmt.Errorf("failed to rename department chat: %w", err)
+			}
+		}
+		return chat, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, fmt.Errorf("failed to get department chat: %w", err)
+	}
+
+	chat = &models.Chat{
+		Name:         name,
+		Type:         models.ChatTypeGroup,
+		IsActive:     true,
+		DepartmentID: &departmentID,
+	}
+	if err := uc.chatRepo.Create(chat); err != nil {
+		return nil, fmt.Errorf("failed to create department chat: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"chat_id":       chat.ID,
+		"department_id": departmentID,
+	}).Info("Department chat created")
+
+	return chat, nil
+}
+
+// syncDepartmentChat makes active users of the department the managed members of its chat.
+// It returns the number of users who joined or left the chat.
+func (uc *chatUsecase) syncDepartmentChat(chat *models.Chat) (int, error) {
+	if uc.departments == nil {
+		return 0, nil
+	}
+
+	userIDs, err := uc.departments.Members(*chat.DepartmentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get department members: %w", err)
+	}
+
+	added, removed, err := uc.chatRepo.SyncDepartmentMembers(chat.ID, userIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync department chat members: %w", err)
+	}
+
+	events := make([]*models.ChatMembershipEvent, 0, len(added)+len(removed))
+	for _, userID := range added {
+		events = append(events, newMembershipEvent(models.MembershipEventJoined, chat.ID, userID, models.ChatMemberRoleMember, "", 0))
+	}
+	for _, userID := range removed {
+		events = append(events, newMembershipEvent(models.MembershipEventLeft, chat.ID, userID, "", "", 0))
+	}
+	uc.recordMembershipEvents(events...)
+
+	// Chat history is unread for new members
+	if len(added) > 0 {
+		invalidateUnreadCounts(uc.unread, added...)
+	}
+	if len(removed) > 0 {
+		if err := uc.unread.RemoveChat(chat.ID, removed...); err != nil {
+			invalidateUnreadCounts(uc.unread, removed...)
+		}
+	}
+
+	return len(added) + len(removed), nil
+}
+
+// leaveDepartmentChat removes a user who left a department from its chat, if the department has one
+func (uc *chatUsecase) leaveDepartmentChat(departmentID, userID uint) error {
+	chat, err := uc.chatRepo.GetByDepartmentID(departmentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return fmt.Errorf("failed to get department chat: %w", err)
+	}
+
+	removed, err := uc.chatRepo.RemoveDepartmentMember(chat.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove user from department chat: %w", err)
+	}
+	if removed {
+		uc.recordMembershipEvents(newMembershipEvent(models.MembershipEventLeft, chat.ID, userID, "", "", 0))
+
+		if err := uc.unread.RemoveChat(chat.ID, userID); err != nil {
+			invalidateUnreadCounts(uc.unread, userID)
+		}
+	}
+	return nil
+}
+
+// handOverDepartmentChat moves the chat of a merged department with its history to the department
+// it was merged into. If that department already has a chat, the merged one is kept as a regular
+// group chat.
+func (uc *chatUsecase) handOverDepartmentChat(mergedID, departmentID uint) error {
+	merged, err := uc.chatRepo.GetByDepartmentID(mergedID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return fmt.Errorf("failed to get department chat: %w", err)
+	}
+
+	_, err = uc.chatRepo.GetByDepartmentID(departmentID)
+	if err == nil {
+		return uc.detachDepartmentChat(mergedID)
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("failed to get department chat: %w", err)
+	}
+
+	if err := uc.chatRepo.MoveDepartmentChat(merged.ID, departmentID); err != nil {
+		return fmt.Errorf("failed to hand over department chat: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"chat_id":                merged.ID,
+		"department_id":          departmentID,
+		"previous_department_id": mergedID,
+	}).Info("Department chat handed over")
+
+	return nil
+}
+
+// detachDepartmentChat keeps the chat of a department that opted out or was deleted as a regular group chat
+func (uc *chatUsecase) detachDepartmentChat(departmentID uint) error {
+	chat, err := uc.chatRepo.GetByDepartmentID(departmentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return fmt.Errorf("failed to get department chat: %w", err)
+	}
+
+	if err := uc.chatRepo.DetachDepartmentChat(chat.ID); err != nil {
+		return fmt.Errorf("failed to detach department chat: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"chat_id":       chat.ID,
+		"department_id": departmentID,
+	}).Info("Department chat detached")
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DepartmentDirectory resolves which users belong to a department
+type DepartmentDirectory interface {
+	// Members returns IDs of active users of the department
+	Members(departmentID uint) ([]uint, error)
+}
+
+// httpDepartmentDirectory asks the user service for active users of a department
+type httpDepartmentDirectory struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPDepartmentDirectory creates a department directory for the user service at baseURL.
+// It returns nil if baseURL is empty, department chats then only follow user moves.
+func NewHTTPDepartmentDirectory(baseURL string) DepartmentDirectory {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpDepartmentDirectory{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Members requests active users of the department from the user service
+func (d *httpDepartmentDirectory) Members(departmentID uint) ([]uint, error) {
+	body, err := json.Marshal(map[string]interface{}{"department_ids": []uint{departmentID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode department members request: %w", err)
+	}
+
+	resp, err := d.client.Post(d.baseURL+"/api/v1/internal/users/audience", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request department members: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service responded with status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		UserIDs []uint `json:"user_ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode department members: %w", err)
+	}
+
+	return payload.UserIDs, nil
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/repository"
+)
+
+// DefaultDraftTTL is how long a draft is kept after its last update
+const DefaultDraftTTL = 30 * 24 * time.Hour
+
+// DraftUsecase syncs unsent messages of a user across devices, one draft per chat
+type DraftUsecase interface {
+	SaveDraft(userID, chatID uint, req *models.SaveDraftRequest) (*models.MessageDraft, error)
+	GetDraft(userID, chatID uint) (*models.MessageDraft, error)
+	GetDrafts(userID uint) ([]*models.MessageDraft, error)
+	DeleteDraft(userID, chatID uint) error
+	DeleteExpiredDrafts() (int64, error)
+}
+
+// draftUsecase implements DraftUsecase interface
+type draftUsecase struct {
+	draftRepo   repository.DraftRepository
+	chatRepo    repository.ChatRepository
+	messageRepo repository.MessageRepository
+	ttl         time.Duration
+}
+
+// NewDraftUsecase creates a new draft usecase. Drafts expire ttl after their last update,
+// a ttl of 0 uses DefaultDraftTTL.
+func NewDraftUsecase(draftRepo repository.DraftRepository, chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, ttl time.Duration) DraftUsecase {
+	if ttl <= 0 {
+		ttl = DefaultDraftTTL
+	}
+	return &draftUsecase{
+		draftRepo:   draftRepo,
+		chatRepo:    chatRepo,
+		messageRepo: messageRepo,
+		ttl:         ttl,
+	}
+}
+
+// SaveDraft stores the draft of a chat. A draft without text and reply-to reference is cleared.
+func (uc *draftUsecase) SaveDraft(userID, chatID uint, req *models.SaveDraftRequest) (*models.MessageDraft, error) {
+	if len(req.Content) > 10000 {
+		return nil, fmt.Errorf("validation failed: content must be at most 10000 characters")
+	}
+	if err := uc.checkMember(chatID, userID); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Content) == "" && req.ReplyToID == nil {
+		if err := uc.draftRepo.Delete(chatID, userID); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	if req.ReplyToID != nil {
+		replyMsg, err := uc.messageRepo.GetByID(*req.ReplyToID)
+		if err != nil {
+			return nil, fmt.Errorf("reply-to message not found")
+		}
+		if replyMsg.ChatID != chatID {
+			return nil, fmt.Errorf("validation failed: reply-to message is not in the same chat")
+		}
+	}
+
+	now := time.Now()
+	draft := &models.MessageDraft{
+		ChatID:    chatID,
+		UserID:    userID,
+		Content:   req.Content,
+		ReplyToID: req.ReplyToID,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(uc.ttl),
+	}
+	if err := uc.draftRepo.Save(draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// GetDraft returns the draft of a chat, nil if there is none
+func (uc *draftUsecase) GetDraft(userID, chatID uint) (*models.MessageDraft, error) {
+	if err := uc.checkMember(chatID, userID); err != nil {
+		return nil, err
+	}
+	return uc.draftRepo.Get(chatID, userID, time.Now())
+}
+
+// GetDrafts returns drafts of all chats the user is still a member of, most recent first
+func (uc *draftUsecase) GetDrafts(userID uint) ([]*models.MessageDraft, error) {
+	drafts, err := uc.draftRepo.GetByUserID(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(drafts) == 0 {
+		return drafts, nil
+	}
+
+	chatIDs, err := uc.chatRepo.GetReadableChatIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]*models.MessageDraft, 0, len(drafts))
+	for _, draft := range drafts {
+		if containsID(chatIDs, draft.ChatID) {
+			visible = append(visible, draft)
+		}
+	}
+	return visible, nil
+}
+
+// DeleteDraft clears the draft of a chat
+func (uc *draftUsecase) DeleteDraft(userID, chatID uint) error {
+	if err := uc.checkMember(chatID, userID); err != nil {
+		return err
+	}
+	return uc.draftRepo.Delete(chatID, userID)
+}
+
+// DeleteExpiredDrafts removes drafts that were not updated within the TTL
+func (uc *draftUsecase) DeleteExpiredDrafts() (int64, error) {
+	return uc.draftRepo.DeleteExpired(time.Now())
+}
+
+// checkMember checks that the user is a member of the chat
+func (uc *draftUsecase) checkMember(chatID, userID uint) error {
+	isMember, err := uc.chatRepo.IsMember(chatID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check membership: %w", err)
+	}
+	if !isMember {
+		return fmt.Errorf("user is not a member of this chat")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/query"
+)
+
+// GetChatMembershipEvents retrieves a page of the membership log of a chat, including chats
+// that were deleted since
+func (uc *chatUsecase) GetChatMembershipEvents(chatID uint, page *query.Params) (*models.MembershipEventListResponse, error) {
+	return uc.getMembershipEvents(chatID, 0, page)
+}
+
+// GetUserMembershipEvents retrieves a page of the membership log of a user across all chats
+func (uc *chatUsecase) GetUserMembershipEvents(userID uint, page *query.Params) (*models.MembershipEventListResponse, error) {
+	return uc.getMembershipEvents(0, userID, page)
+}
+
+// GetMessageAudience returns users who were members of the chat of a message at a point in time,
+// and so could see the message then. Zero at means when the message was sent.
+func (uc *chatUsecase) GetMessageAudience(messageID uint, at time.Time) (*models.MessageAudienceResponse, error) {
+	message, err := uc.messageRepo.GetByID(messageID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("message not found")
+		}
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if at.IsZero() {
+		at = message.CreatedAt
+	}
+	if at.Before(message.CreatedAt) {
+		return nil, fmt.Errorf("validation failed: message was sent after %s", at.Format(time.RFC3339))
+	}
+
+	members, err := uc.chatRepo.GetMembersAt(message.ChatID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat members: %w", err)
+	}
+
+	return &models.MessageAudienceResponse{
+		MessageID: message.ID,
+		ChatID:    message.ChatID,
+		SentAt:    message.CreatedAt,
+		At:        at,
+		Members:   members,
+	}, nil
+}
+
+// getMembershipEvents retrieves a page of the membership log, zero IDs match any chat or user
+func (uc *chatUsecase) getMembershipEvents(chatID, userID uint, page *query.Params) (*models.MembershipEventListResponse, error) {
+	if page == nil {
+		page = query.Default(models.MembershipEventListOptions)
+	}
+
+	events, total, err := uc.chatRepo.GetMembershipEvents(chatID, userID, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership events: %w", err)
+	}
+
+	return &models.MembershipEventListResponse{
+		Events:     events,
+		Total:      total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		NextCursor: page.NextCursor(events),
+	}, nil
+}
+
+// recordMembershipEvents appends events to the membership log. The membership change is already
+// made when it is recorded, so a failure is logged instead of failing the request.
+func (uc *chatUsecase) recordMembershipEvents(events ...*models.ChatMembershipEvent) {
+	if err := uc.chatRepo.RecordMembershipEvents(events...); err != nil {
+		for _, event := range events {
+			logger.WithFields(map[string]interface{}{
+				"chat_id": event.ChatID,
+				"user_id": event.UserID,
+				"type":    event.Type,
+				"error":   err.Error(),
+			}).Error("Failed to record chat membership event")
+		}
+	}
+}
+
+// newMembershipEvent creates a membership log event. Role is the role after the change, previous
+// role the one before it; actorID is zero for changes made by the system.
+func newMembershipEvent(eventType models.MembershipEventType, chatID, userID uint, role, previousRole models.ChatMemberRole, actorID uint) *models.ChatMembershipEvent {
+	event := &models.ChatMembershipEvent{
+		ChatID:       chatID,
+		UserID:       userID,
+		Type:         eventType,
+		Role:         role,
+		PreviousRole: previousRole,
+		CreatedAt:    time.Now(),
+	}
+	if actorID != 0 {
+		event.ActorID = &actorID
+	}
+	return event
+}
+
+// joinedEvent creates the membership log event of a member who joined a chat
+func joinedEvent(member *models.ChatMember, actorID uint) *models.ChatMembershipEvent {
+	return newMembershipEvent(models.MembershipEventJoined, member.ChatID, member.UserID, member.Role, "", actorID)
+}
+
+// ownerJoinedEvent creates the membership log event of the creator, who joins a new chat as owner
+func ownerJoinedEvent(chat *models.Chat) *models.ChatMembershipEvent {
+	return newMembershipEvent(models.MembershipEventJoined, chat.ID, chat.CreatorID, models.ChatMemberRoleOwner, "", chat.CreatorID)
+}
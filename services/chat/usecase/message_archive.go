@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+)
+
+// messageArchiveBatchSize is the number of messages moved to the archive per transaction
+const messageArchiveBatchSize = 500
+
+// ArchiveMessages moves messages created before olderThan to the archive.
+// Unread messages that get archived stop counting as unread; cached counters are
+// corrected by the unread counter reconciliation.
+func (uc *messageUsecase) ArchiveMessages(olderThan time.Time) (int64, error) {
+	var total int64
+	var afterID uint
+
+	for {
+		archived, next, err := uc.messageRepo.ArchiveMessages(olderThan, afterID, messageArchiveBatchSize)
+		total += archived
+		if err != nil {
+			return total, fmt.Errorf("failed to archive messages: %w", err)
+		}
+		if next == 0 {
+			return total, nil
+		}
+		afterID = next
+	}
+}
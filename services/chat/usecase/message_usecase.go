@@ -1,13 +1,17 @@
 package usecase
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"tachyon-messenger/services/chat/markdown"
 	"tachyon-messenger/services/chat/models"
 	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/undo"
 
 	"gorm.io/gorm"
 )
@@ -17,30 +21,50 @@ type MessageUsecase interface {
 	GetMessages(userID uint, req *models.GetMessagesRequest) (*models.MessageListResponse, error)
 	GetMessage(userID, messageID uint) (*models.MessageResponse, error)
 	UpdateMessage(userID, messageID uint, req *models.UpdateMessageRequest) (*models.MessageResponse, error)
-	DeleteMessage(userID, messageID uint) error
+	DeleteMessage(userID, messageID uint) (*undo.Receipt, error)
 	AddReaction(userID, messageID uint, req *models.AddReactionRequest) error
 	RemoveReaction(userID, messageID uint, emoji string) error
 	MarkAsRead(userID, messageID uint) error
 	GetMessagesByChat(userID, chatID uint, limit, offset int) (*models.MessageListResponse, error)
+	ArchiveMessages(olderThan time.Time) (int64, error)
 }
 
 // messageUsecase implements MessageUsecase interface
 type messageUsecase struct {
 	messageRepo repository.MessageRepository
 	chatRepo    repository.ChatRepository
+	botEvents   BotEventDispatcher
+	commands    SlashCommandRunner // nil sends slash commands as regular messages
+	unread      *redis.UnreadCounter
+	undo        *undo.Manager
 }
 
-// NewMessageUsecase creates a new message usecase
-func NewMessageUsecase(messageRepo repository.MessageRepository, chatRepo repository.ChatRepository) MessageUsecase {
-	return &messageUsecase{
+// ActionDeleteMessage is the undoable action kind of message deletion
+const ActionDeleteMessage = "delete_message"
+
+// NewMessageUsecase creates a new message usecase and registers its undoable actions with undoManager.
+// botEvents may be nil if bot webhooks are not used, commands may be nil if slash commands are not run,
+// unread may be nil if unread counts are not cached.
+func NewMessageUsecase(messageRepo repository.MessageRepository, chatRepo repository.ChatRepository, botEvents BotEventDispatcher, commands SlashCommandRunner, unread *redis.UnreadCounter, undoManager *undo.Manager) MessageUsecase {
+	uc := &messageUsecase{
 		messageRepo: messageRepo,
 		chatRepo:    chatRepo,
-	}
+		botEvents:   botEvents,
+		commands:    commands,
+		unread:      unread,
+		undo:        undoManager,
+	}
+	undoManager.Register(ActionDeleteMessage, undo.Operation{
+		Commit: uc.commitMessageDeletion,
+		Revert: uc.revertMessageDeletion,
+	})
+	return uc
 }
 
 // Message Usecase Methods
 
-// SendMessage sends a new message
+// SendMessage sends a new message. Text messages with a slash command run the command instead
+// and post a system message with its result.
 func (uc *messageUsecase) SendMessage(userID uint, req *models.SendMessageRequest) (*models.MessageResponse, error) {
 	// Validate request
 	if err := uc.validateSendMessageRequest(req); err != nil {
@@ -69,19 +93,20 @@ func (uc *messageUsecase) SendMessage(userID uint, req *models.SendMessageReques
 
 	// Create message
 	message := &models.Message{
-		ChatID:       req.ChatID,
-		SenderID:     userID,
-		Content:      strings.TrimSpace(req.Content),
-		Type:         req.Type,
-		Status:       models.MessageStatusSent,
-		ReplyToID:    req.ReplyToID,
-		FileName:     req.FileName,
-		FileSize:     req.FileSize,
-		FileURL:      req.FileURL,
-		ThumbnailURL: req.ThumbnailURL,
-		MimeType:     req.MimeType,
-		Latitude:     req.Latitude,
-		Longitude:    req.Longitude,
+		ChatID:        req.ChatID,
+		SenderID:      userID,
+		Content:       strings.TrimSpace(req.Content),
+		ContentFormat: req.ContentFormat,
+		Type:          req.Type,
+		Status:        models.MessageStatusSent,
+		ReplyToID:     req.ReplyToID,
+		FileName:      req.FileName,
+		FileSize:      req.FileSize,
+		FileURL:       req.FileURL,
+		ThumbnailURL:  req.ThumbnailURL,
+		MimeType:      req.MimeType,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
 	}
 
 	// Set default type if not provided
@@ -89,10 +114,38 @@ func (uc *messageUsecase) SendMessage(userID uint, req *models.SendMessageReques
 		message.Type = models.MessageTypeText
 	}
 
+	if uc.commands != nil && message.Type == models.MessageTypeText {
+		result, err := uc.commands.RunCommand(userID, req.ChatID, message.Content)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			systemData, err := json.Marshal(result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode command result: %w", err)
+			}
+			message.Type = models.MessageTypeSystem
+			message.Content = result.Text
+			message.ContentFormat = markdown.FormatPlain
+			message.SystemData = string(systemData)
+		}
+	}
+
+	if err := applyContentFormat(message); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
 	if err := uc.messageRepo.Create(message); err != nil {
 		return nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
+	countNewMessage(uc.unread, uc.chatRepo, message)
+
+	// Notify bots installed in the chat
+	if uc.botEvents != nil {
+		uc.botEvents.DispatchMessageCreated(message)
+	}
+
 	// Get message with relations for response
 	createdMessage, err := uc.messageRepo.GetWithReactions(message.ID)
 	if err != nil {
@@ -206,6 +259,15 @@ func (uc *messageUsecase) UpdateMessage(userID, messageID uint, req *models.Upda
 
 	// Update message
 	message.Content = strings.TrimSpace(req.Content)
+	if req.ContentFormat != "" {
+		if !markdown.IsValidFormat(req.ContentFormat) {
+			return nil, fmt.Errorf("validation failed: invalid content format")
+		}
+		message.ContentFormat = req.ContentFormat
+	}
+	if err := applyContentFormat(message); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
 	message.IsEdited = true
 	now := time.Now()
 	message.EditedAt = &now
@@ -223,29 +285,61 @@ func (uc *messageUsecase) UpdateMessage(userID, messageID uint, req *models.Upda
 	return updatedMessage.ToResponse(), nil
 }
 
-// DeleteMessage deletes a message
-func (uc *messageUsecase) DeleteMessage(userID, messageID uint) error {
+// DeleteMessage hides a message and stages its deletion, which can be undone with the returned receipt
+func (uc *messageUsecase) DeleteMessage(userID, messageID uint) (*undo.Receipt, error) {
 	// Get message
 	message, err := uc.messageRepo.GetByID(messageID)
 	if err != nil {
-		return fmt.Errorf("failed to get message: %w", err)
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if message.IsDeleted {
+		return nil, fmt.Errorf("message not found")
 	}
 
 	// Check if user is the sender or has admin/owner role in chat
 	if message.SenderID != userID {
 		role, err := uc.chatRepo.GetMemberRole(message.ChatID, userID)
 		if err != nil {
-			return fmt.Errorf("failed to get user role: %w", err)
+			return nil, fmt.Errorf("failed to get user role: %w", err)
 		}
 		if role != models.ChatMemberRoleOwner && role != models.ChatMemberRoleAdmin {
-			return fmt.Errorf("insufficient permissions to delete message")
+			return nil, fmt.Errorf("insufficient permissions to delete message")
 		}
 	}
 
-	if err := uc.messageRepo.Delete(messageID); err != nil {
-		return fmt.Errorf("failed to delete message: %w", err)
+	if err := uc.messageRepo.SetDeleted(messageID, true); err != nil {
+		return nil, fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	// Members who haven't read the message have stale counters
+	invalidateChatUnreadCounts(uc.unread, uc.chatRepo, message.ChatID)
+
+	action, err := uc.undo.Stage(userID, ActionDeleteMessage, messageID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete message: %w", err)
+	}
+	if action == nil {
+		return nil, nil
+	}
+	return action.Receipt(), nil
+}
+
+// commitMessageDeletion removes a message whose undo window elapsed from the search index
+func (uc *messageUsecase) commitMessageDeletion(action *undo.PendingAction) error {
+	return uc.messageRepo.Delete(action.ResourceID)
+}
+
+// revertMessageDeletion shows a message again
+func (uc *messageUsecase) revertMessageDeletion(action *undo.PendingAction) error {
+	message, err := uc.messageRepo.GetByID(action.ResourceID)
+	if err != nil {
+		return err
+	}
+	if err := uc.messageRepo.SetDeleted(message.ID, false); err != nil {
+		return err
 	}
 
+	invalidateChatUnreadCounts(uc.unread, uc.chatRepo, message.ChatID)
 	return nil
 }
 
@@ -328,10 +422,18 @@ func (uc *messageUsecase) MarkAsRead(userID, messageID uint) error {
 		ReadAt:    time.Now(),
 	}
 
-	if err := uc.messageRepo.MarkAsRead(receipt); err != nil {
+	wasUnread, err := uc.messageRepo.MarkAsRead(receipt)
+	if err != nil {
 		return fmt.Errorf("failed to mark message as read: %w", err)
 	}
 
+	// Own and deleted messages are not counted as unread
+	if wasUnread && message.SenderID != userID && !message.IsDeleted {
+		if err := uc.unread.IncrChat(message.ChatID, -1, userID); err != nil {
+			invalidateUnreadCounts(uc.unread, userID)
+		}
+	}
+
 	return nil
 }
 
@@ -396,6 +498,10 @@ func (uc *messageUsecase) validateSendMessageRequest(req *models.SendMessageRequ
 		return fmt.Errorf("content is required")
 	}
 
+	if req.ContentFormat != "" && !markdown.IsValidFormat(req.ContentFormat) {
+		return fmt.Errorf("invalid content format")
+	}
+
 	// Validate message type
 	if req.Type != "" {
 		validTypes := []models.MessageType{
@@ -438,3 +544,25 @@ func (uc *messageUsecase) validateSendMessageRequest(req *models.SendMessageRequ
 
 	return nil
 }
+
+// applyContentFormat renders markdown content and extracts mentions and links
+func applyContentFormat(message *models.Message) error {
+	if message.ContentFormat == "" {
+		message.ContentFormat = markdown.FormatPlain
+	}
+
+	message.Entities = markdown.ExtractEntities(message.Content, message.ContentFormat)
+
+	if message.ContentFormat != markdown.FormatMarkdown {
+		message.ContentHTML = ""
+		return nil
+	}
+
+	rendered := markdown.Render(message.Content)
+	if len(rendered) > markdown.MaxRenderedLength {
+		return fmt.Errorf("rendered content too long (max %d characters)", markdown.MaxRenderedLength)
+	}
+	message.ContentHTML = rendered
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/shared/cache"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/validation"
+)
+
+// DefaultPresenceCacheTTL is how long presence read from the store is reused, so rosters
+// rendered by many clients at once hit the store once
+const DefaultPresenceCacheTTL = 5 * time.Second
+
+// PresenceReader returns presence of users: the Redis presence store, or the WebSocket hub
+// of this instance when the service runs without Redis
+type PresenceReader interface {
+	GetPresence(userIDs []uint) (map[uint]redis.Presence, error)
+}
+
+// PresenceUsecase defines the interface for presence business logic
+type PresenceUsecase interface {
+	GetPresence(req *models.PresenceBatchRequest) ([]*models.UserPresence, error)
+}
+
+// presenceUsecase implements PresenceUsecase interface
+type presenceUsecase struct {
+	reader PresenceReader
+	cache  *cache.Cache[*models.UserPresence]
+}
+
+// NewPresenceUsecase creates a new presence usecase, zero cacheTTL uses the default
+func NewPresenceUsecase(reader PresenceReader, cacheTTL time.Duration) PresenceUsecase {
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultPresenceCacheTTL
+	}
+	return &presenceUsecase{
+		reader: reader,
+		cache:  cache.New[*models.UserPresence](nil, "presence", cacheTTL, 0),
+	}
+}
+
+// GetPresence returns presence of the users in request order, without duplicates
+func (uc *presenceUsecase) GetPresence(req *models.PresenceBatchRequest) ([]*models.UserPresence, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	userIDs := make([]uint, 0, len(req.UserIDs))
+	found := make(map[uint]*models.UserPresence, len(req.UserIDs))
+	var missing []uint
+	for _, userID := range req.UserIDs {
+		if _, seen := found[userID]; seen {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+
+		presence, ok := uc.cache.Get(presenceCacheKey(userID))
+		if !ok {
+			missing = append(missing, userID)
+		}
+		found[userID] = presence
+	}
+
+	if len(missing) > 0 {
+		stored, err := uc.reader.GetPresence(missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get presence: %w", err)
+		}
+
+		for _, userID := range missing {
+			presence := newUserPresence(userID, stored[userID])
+			uc.cache.Set(presenceCacheKey(userID), presence, cache.Tag(cache.KindUser, userID))
+			found[userID] = presence
+		}
+	}
+
+	response := make([]*models.UserPresence, 0, len(userIDs))
+	for _, userID := range userIDs {
+		response = append(response, found[userID])
+	}
+	return response, nil
+}
+
+// newUserPresence converts stored presence, users missing from the store are offline
+func newUserPresence(userID uint, stored redis.Presence) *models.UserPresence {
+	presence := &models.UserPresence{
+		UserID: userID,
+		Status: stored.Status,
+	}
+	if presence.Status == "" {
+		presence.Status = redis.PresenceOffline
+	}
+	if !stored.LastSeen.IsZero() {
+		lastSeen := stored.LastSeen.UTC()
+		presence.LastSeen = &lastSeen
+	}
+	return presence
+}
+
+func presenceCacheKey(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
+}
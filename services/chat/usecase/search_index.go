@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+)
+
+const defaultSearchIndexName = "chat-messages"
+
+// SearchIndex is an external full-text index of chat messages. Documents carry the chat ID,
+// queries are always restricted to chats the user can read.
+type SearchIndex interface {
+	EnsureIndex(ctx context.Context) error
+	IndexMessages(ctx context.Context, docs []*SearchDocument) error
+	DeleteMessages(ctx context.Context, ids []uint) error
+	Search(ctx context.Context, filter *models.MessageSearchFilter, limit, offset int) (*SearchHits, error)
+}
+
+// SearchDocument is a message as stored in the search index
+type SearchDocument struct {
+	ID        uint               `json:"-"`
+	ChatID    uint               `json:"chat_id"`
+	SenderID  uint               `json:"sender_id"`
+	BotID     *uint              `json:"bot_id,omitempty"`
+	Content   string             `json:"content"`
+	Type      models.MessageType `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// NewSearchDocument creates the search index document of a message
+func NewSearchDocument(message *models.Message) *SearchDocument {
+	return &SearchDocument{
+		ID:        message.ID,
+		ChatID:    message.ChatID,
+		SenderID:  message.SenderID,
+		BotID:     message.BotID,
+		Content:   message.Content,
+		Type:      message.Type,
+		CreatedAt: message.CreatedAt,
+	}
+}
+
+// SearchHits holds message IDs found by the search index in relevance order
+type SearchHits struct {
+	IDs   []uint
+	Total int64
+}
+
+// elasticsearchIndex stores messages in an Elasticsearch or OpenSearch index over its REST API
+type elasticsearchIndex struct {
+	baseURL  string
+	index    string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewElasticsearchIndex creates a search index in the Elasticsearch or OpenSearch cluster at
+// baseURL. It returns nil if baseURL is empty, search then uses the database.
+func NewElasticsearchIndex(baseURL, index, username, password string) SearchIndex {
+	if baseURL == "" {
+		return nil
+	}
+	if index == "" {
+		index = defaultSearchIndexName
+	}
+	return &elasticsearchIndex{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		index:    index,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewElasticsearchIndexFromEnv creates a search index from SEARCH_URL, SEARCH_INDEX,
+// SEARCH_USERNAME and SEARCH_PASSWORD. It returns nil if SEARCH_URL is not set.
+func NewElasticsearchIndexFromEnv() SearchIndex {
+	return NewElasticsearchIndex(os.Getenv("SEARCH_URL"), os.Getenv("SEARCH_INDEX"),
+		os.Getenv("SEARCH_USERNAME"), os.Getenv("SEARCH_PASSWORD"))
+}
+
+// EnsureIndex creates the index with its mapping if it does not exist
+func (e *elasticsearchIndex) EnsureIndex(ctx context.Context) error {
+	resp, err := e.do(ctx, http.MethodHead, "/"+e.index, "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	mapping := map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"chat_id":    map[string]string{"type": "long"},
+				"sender_id":  map[string]string{"type": "long"},
+				"bot_id":     map[string]string{"type": "long"},
+				"content":    map[string]string{"type": "text"},
+				"type":       map[string]string{"type": "keyword"},
+				"created_at": map[string]string{"type": "date"},
+			},
+		},
+	}
+	body, err := json.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("failed to encode search index mapping: %w", err)
+	}
+
+	resp, err = e.do(ctx, http.MethodPut, "/"+e.index, "application/json", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Another instance may have created the index in the meantime
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusBadRequest {
+		return e.responseError(resp)
+	}
+	return nil
+}
+
+// IndexMessages adds or replaces message documents
+func (e *elasticsearchIndex) IndexMessages(ctx context.Context, docs []*SearchDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, doc := range docs {
+		action := map[string]interface{}{"index": map[string]string{"_index": e.index, "_id": strconv.FormatUint(uint64(doc.ID), 10)}}
+		if err := encoder.Encode(action); err != nil {
+			return fmt.Errorf("failed to encode search index action: %w", err)
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("failed to encode search document: %w", err)
+		}
+	}
+	return e.bulk(ctx, body.Bytes())
+}
+
+// DeleteMessages removes message documents, unknown documents are ignored
+func (e *elasticsearchIndex) DeleteMessages(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, id := range ids {
+		action := map[string]interface{}{"delete": map[string]string{"_index": e.index, "_id": strconv.FormatUint(uint64(id), 10)}}
+		if err := encoder.Encode(action); err != nil {
+			return fmt.Errorf("failed to encode search index action: %w", err)
+		}
+	}
+	return e.bulk(ctx, body.Bytes())
+}
+
+// Search finds messages matching all words of the query in the chats of filter
+func (e *elasticsearchIndex) Search(ctx context.Context, filter *models.MessageSearchFilter, limit, offset int) (*SearchHits, error) {
+	if len(filter.ChatIDs) == 0 {
+		return &SearchHits{}, nil
+	}
+
+	filters := []interface{}{
+		map[string]interface{}{"terms": map[string]interface{}{"chat_id": filter.ChatIDs}},
+	}
+	if filter.SenderID != 0 {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"sender_id": filter.SenderID}})
+	}
+	if filter.From != nil || filter.To != nil {
+		createdAt := map[string]interface{}{}
+		if filter.From != nil {
+			createdAt["gte"] = filter.From.UTC().Format(time.RFC3339)
+		}
+		if filter.To != nil {
+			createdAt["lt"] = filter.To.UTC().Format(time.RFC3339)
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"created_at": createdAt}})
+	}
+
+	query := map[string]interface{}{
+		"from":             offset,
+		"size":             limit,
+		"track_total_hits": true,
+		"_source":          false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []interface{}{
+					map[string]interface{}{"match": map[string]interface{}{
+						"content": map[string]interface{}{"query": filter.Query, "operator": "and"},
+					}},
+				},
+				"filter": filters,
+			},
+		},
+		"sort": []interface{}{"_score", map[string]string{"created_at": "desc"}},
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search query: %w", err)
+	}
+
+	resp, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_search", "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, e.responseError(resp)
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	hits := &SearchHits{Total: result.Hits.Total.Value, IDs: make([]uint, 0, len(result.Hits.Hits))}
+	for _, hit := range result.Hits.Hits {
+		id, err := strconv.ParseUint(hit.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		hits.IDs = append(hits.IDs, uint(id))
+	}
+	return hits, nil
+}
+
+// bulk sends a bulk request and fails if any action failed. Deleting missing documents is not a failure.
+func (e *elasticsearchIndex) bulk(ctx context.Context, body []byte) error {
+	resp, err := e.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return e.responseError(resp)
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	failed := 0
+	for _, item := range result.Items {
+		for action, outcome := range item {
+			if outcome.Status >= 300 && !(action == "delete" && outcome.Status == http.StatusNotFound) {
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("search index rejected %d of %d documents", failed, len(result.Items))
+	}
+	return nil
+}
+
+// do sends a request to the search cluster
+func (e *elasticsearchIndex) do(ctx context.Context, method, path, contentType string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("search index is unavailable: %w", err)
+	}
+	return resp, nil
+}
+
+// responseError describes a failed response of the search cluster
+func (e *elasticsearchIndex) responseError(resp *http.Response) error {
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+	return fmt.Errorf("search index responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+}
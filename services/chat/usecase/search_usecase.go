@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/shared/logger"
+)
+
+const (
+	searchDefaultLimit     = 20
+	searchQueryTimeout     = 5 * time.Second
+	searchOutboxBatchSize  = 500
+	searchOutboxMaxBatches = 20
+	searchOutboxMaxDelay   = time.Hour
+	searchOutboxMaxError   = 500
+	searchReindexBatchSize = 500
+)
+
+// SearchUsecase searches messages of the user's chats. Queries go to the search index when
+// one is configured and fall back to the database when it is unavailable. The index is kept
+// up to date from the outbox written with every message change.
+type SearchUsecase interface {
+	SearchMessages(userID uint, req *models.SearchMessagesRequest) (*models.SearchMessagesResponse, error)
+	ProcessOutbox(ctx context.Context) (int, error)
+	Reindex(ctx context.Context, progress func(indexed int)) (int, error)
+}
+
+// searchUsecase implements SearchUsecase interface
+type searchUsecase struct {
+	messageRepo repository.MessageRepository
+	chatRepo    repository.ChatRepository
+	index       SearchIndex
+}
+
+// NewSearchUsecase creates a new search usecase. With a nil index search uses the database
+// and pending outbox changes are discarded.
+func NewSearchUsecase(messageRepo repository.MessageRepository, chatRepo repository.ChatRepository, index SearchIndex) SearchUsecase {
+	return &searchUsecase{
+		messageRepo: messageRepo,
+		chatRepo:    chatRepo,
+		index:       index,
+	}
+}
+
+// SearchMessages searches messages in the chats the user can read, or in one of them
+func (s *searchUsecase) SearchMessages(userID uint, req *models.SearchMessagesRequest) (*models.SearchMessagesResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if len([]rune(query)) < 2 {
+		return nil, fmt.Errorf("validation failed: query must be at least 2 characters")
+	}
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, fmt.Errorf("validation failed: from must be before to")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	}
+
+	chatIDs, err := s.chatRepo.GetReadableChatIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ChatID != 0 {
+		if !containsID(chatIDs, req.ChatID) {
+			return nil, fmt.Errorf("user is not a member of this chat")
+		}
+		chatIDs = []uint{req.ChatID}
+	}
+
+	filter := &models.MessageSearchFilter{
+		Query:    query,
+		ChatIDs:  chatIDs,
+		SenderID: req.SenderID,
+		From:     req.From,
+		To:       req.To,
+	}
+	response := &models.SearchMessagesResponse{
+		Messages: []*models.MessageResponse{},
+		Limit:    limit,
+		Offset:   req.Offset,
+	}
+
+	if s.index != nil {
+		messages, total, err := s.searchIndex(filter, limit, req.Offset)
+		if err == nil {
+			response.Messages = toMessageResponses(messages)
+			response.Total = total
+			response.Source = models.SearchSourceIndex
+			return response, nil
+		}
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Warn("Search index is unavailable, searching the database")
+	}
+
+	messages, total, err := s.messageRepo.SearchUserMessages(filter, limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	response.Messages = toMessageResponses(messages)
+	response.Total = total
+	response.Source = models.SearchSourceDatabase
+	return response, nil
+}
+
+// searchIndex queries the search index and loads the found messages in relevance order.
+// Messages that are gone or moved to chats outside the filter are left out.
+func (s *searchUsecase) searchIndex(filter *models.MessageSearchFilter, limit, offset int) ([]*models.Message, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), searchQueryTimeout)
+	defer cancel()
+
+	hits, err := s.index.Search(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	found, err := s.messageRepo.GetByIDsForSearch(hits.IDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]*models.Message, len(found))
+	for _, message := range found {
+		if containsID(filter.ChatIDs, message.ChatID) {
+			byID[message.ID] = message
+		}
+	}
+
+	messages := make([]*models.Message, 0, len(hits.IDs))
+	for _, id := range hits.IDs {
+		if message, ok := byID[id]; ok {
+			messages = append(messages, message)
+		}
+	}
+	return messages, hits.Total, nil
+}
+
+// ProcessOutbox applies pending message changes to the search index. Failed batches are
+// retried with a growing delay. It returns the number of applied changes.
+func (s *searchUsecase) ProcessOutbox(ctx context.Context) (int, error) {
+	processed := 0
+	for batch := 0; batch < searchOutboxMaxBatches && ctx.Err() == nil; batch++ {
+		now := time.Now()
+		entries, err := s.messageRepo.GetSearchOutbox(now, searchOutboxBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		if len(entries) == 0 {
+			return processed, nil
+		}
+
+		entryIDs := make([]uint, len(entries))
+		for i, entry := range entries {
+			entryIDs[i] = entry.ID
+		}
+
+		if s.index != nil {
+			if err := s.applyOutbox(ctx, entries); err != nil {
+				attempts := 0
+				for _, entry := range entries {
+					attempts = max(attempts, entry.Attempts)
+				}
+				delay := min(time.Duration(attempts+1)*time.Minute, searchOutboxMaxDelay)
+				lastError := err.Error()
+				if len(lastError) > searchOutboxMaxError {
+					lastError = lastError[:searchOutboxMaxError]
+				}
+				if retryErr := s.messageRepo.RetrySearchOutbox(entryIDs, lastError, now.Add(delay)); retryErr != nil {
+					return processed, retryErr
+				}
+				return processed, err
+			}
+		}
+
+		if err := s.messageRepo.DeleteSearchOutbox(entryIDs); err != nil {
+			return processed, err
+		}
+		processed += len(entries)
+
+		if len(entries) < searchOutboxBatchSize {
+			break
+		}
+	}
+	return processed, nil
+}
+
+// applyOutbox sends a batch of outbox changes to the search index. The last change of a
+// message wins, messages that are deleted by the time the batch runs are removed.
+func (s *searchUsecase) applyOutbox(ctx context.Context, entries []*models.SearchOutboxEntry) error {
+	operations := make(map[uint]models.SearchOperation, len(entries))
+	for _, entry := range entries {
+		operations[entry.MessageID] = entry.Operation
+	}
+
+	var indexIDs, deleteIDs []uint
+	for messageID, operation := range operations {
+		if operation == models.SearchOperationDelete {
+			deleteIDs = append(deleteIDs, messageID)
+		} else {
+			indexIDs = append(indexIDs, messageID)
+		}
+	}
+
+	messages, err := s.messageRepo.GetByIDsForSearch(indexIDs)
+	if err != nil {
+		return err
+	}
+	found := make(map[uint]bool, len(messages))
+	docs := make([]*SearchDocument, len(messages))
+	for i, message := range messages {
+		found[message.ID] = true
+		docs[i] = NewSearchDocument(message)
+	}
+	for _, messageID := range indexIDs {
+		if !found[messageID] {
+			deleteIDs = append(deleteIDs, messageID)
+		}
+	}
+
+	if err := s.index.IndexMessages(ctx, docs); err != nil {
+		return err
+	}
+	return s.index.DeleteMessages(ctx, deleteIDs)
+}
+
+// Reindex creates the search index if needed and indexes all messages, archived ones
+// included. Changes made meanwhile reach the index through the outbox.
+func (s *searchUsecase) Reindex(ctx context.Context, progress func(indexed int)) (int, error) {
+	if s.index == nil {
+		return 0, fmt.Errorf("search index is not configured")
+	}
+	if err := s.index.EnsureIndex(ctx); err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return indexed, err
+		}
+
+		messages, err := s.messageRepo.GetMessagesForIndex(afterID, searchReindexBatchSize)
+		if err != nil {
+			return indexed, err
+		}
+		if len(messages) == 0 {
+			return indexed, nil
+		}
+
+		docs := make([]*SearchDocument, len(messages))
+		for i, message := range messages {
+			docs[i] = NewSearchDocument(message)
+		}
+		if err := s.index.IndexMessages(ctx, docs); err != nil {
+			return indexed, err
+		}
+
+		indexed += len(docs)
+		afterID = messages[len(messages)-1].ID
+		if progress != nil {
+			progress(indexed)
+		}
+	}
+}
+
+// toMessageResponses converts messages to responses
+func toMessageResponses(messages []*models.Message) []*models.MessageResponse {
+	responses := make([]*models.MessageResponse, len(messages))
+	for i, message := range messages {
+		responses[i] = message.ToResponse()
+	}
+	return responses
+}
+
+// containsID checks if ids contains id
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
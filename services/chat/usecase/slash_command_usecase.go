@@ -0,0 +1,508 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/orgsettings"
+	"tachyon-messenger/shared/validation"
+)
+
+const (
+	slashCommandTimeout = 5 * time.Second
+	maxReminderDelay    = 30 * 24 * time.Hour
+	maxCommandText      = 255   // Максимальная длина названия задачи, вопроса опроса и текста напоминания
+	maxMessageLength    = 10000 // Максимальная длина сообщения с ответом вебхука
+)
+
+// slashCommandName matches names of commands, both built-in and custom
+var slashCommandName = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// SlashCommandRunner runs slash commands sent as chat messages
+type SlashCommandRunner interface {
+	// RunCommand runs the command in content sent by the user to the chat. It returns nil if
+	// content is not a known command, the content is then sent as a regular message.
+	RunCommand(userID, chatID uint, content string) (*models.SlashCommandResult, error)
+}
+
+// SlashCommandUsecase defines the interface for slash commands business logic
+type SlashCommandUsecase interface {
+	SlashCommandRunner
+
+	GetCommands() ([]*models.SlashCommandResponse, error)
+	GetCustomCommands() ([]*models.SlashCommandResponse, error)
+	CreateCommand(userID uint, req *models.CreateSlashCommandRequest) (*models.SlashCommandCredentialsResponse, error)
+	UpdateCommand(commandID uint, req *models.UpdateSlashCommandRequest) (*models.SlashCommandResponse, error)
+	DeleteCommand(commandID uint) error
+}
+
+// builtinCommand is a slash command routed to another service
+type builtinCommand struct {
+	description string
+	usage       string
+	available   bool
+	run         func(userID, chatID uint, args []string, locale i18n.Locale) (*models.SlashCommandResult, error)
+}
+
+// slashCommandUsecase implements SlashCommandUsecase interface
+type slashCommandUsecase struct {
+	commandRepo repository.SlashCommandRepository
+	tasks       TaskCreator       // nil disables /task
+	polls       PollCreator       // nil disables /poll
+	reminders   ReminderScheduler // nil disables /remind
+	orgSettings *orgsettings.Client
+	client      *http.Client
+	builtins    map[string]*builtinCommand
+}
+
+// NewSlashCommandUsecase creates a new slash command usecase. Built-in commands whose service
+// client is nil are not available.
+func NewSlashCommandUsecase(commandRepo repository.SlashCommandRepository, tasks TaskCreator, polls PollCreator, reminders ReminderScheduler, orgSettings *orgsettings.Client) SlashCommandUsecase {
+	uc := &slashCommandUsecase{
+		commandRepo: commandRepo,
+		tasks:       tasks,
+		polls:       polls,
+		reminders:   reminders,
+		orgSettings: orgSettings,
+		client:      newWebhookHTTPClient(slashCommandTimeout),
+	}
+	uc.builtins = map[string]*builtinCommand{
+		models.SlashCommandTask: {
+			description: "Create a task",
+			usage:       `/task "title"`,
+			available:   tasks != nil,
+			run:         uc.runTask,
+		},
+		models.SlashCommandPoll: {
+			description: "Create a poll, yes or no without options",
+			usage:       `/poll "question" ["option" ...]`,
+			available:   polls != nil,
+			run:         uc.runPoll,
+		},
+		models.SlashCommandRemind: {
+			description: "Remind yourself about this chat",
+			usage:       `/remind 15m ["text"]`,
+			available:   reminders != nil,
+			run:         uc.runRemind,
+		},
+	}
+	return uc
+}
+
+// RunCommand parses content as "/name arguments" and runs a built-in or an active custom command
+func (uc *slashCommandUsecase) RunCommand(userID, chatID uint, content string) (*models.SlashCommandResult, error) {
+	name, text, ok := parseSlashCommand(content)
+	if !ok {
+		return nil, nil
+	}
+	args := splitCommandArgs(text)
+
+	if builtin, exists := uc.builtins[name]; exists {
+		if !builtin.available {
+			return nil, fmt.Errorf("command /%s is not available", name)
+		}
+		result, err := builtin.run(userID, chatID, args, commandLocale(text))
+		if err != nil {
+			if strings.Contains(err.Error(), "validation failed") {
+				return nil, err
+			}
+			return nil, fmt.Errorf("command /%s failed: %w", name, err)
+		}
+		result.Command = name
+		result.UserID = userID
+		return result, nil
+	}
+
+	command, err := uc.commandRepo.GetByName(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !command.IsActive {
+		return nil, nil
+	}
+
+	reply, err := uc.invokeWebhook(command, &models.SlashCommandInvocation{
+		Command:   name,
+		Arguments: args,
+		Text:      text,
+		ChatID:    chatID,
+		UserID:    userID,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("command /%s failed: %w", name, err)
+	}
+
+	return &models.SlashCommandResult{
+		Command: name,
+		UserID:  userID,
+		Text:    reply,
+	}, nil
+}
+
+// GetCommands retrieves commands available in chats: built-in ones and active custom ones
+func (uc *slashCommandUsecase) GetCommands() ([]*models.SlashCommandResponse, error) {
+	responses := make([]*models.SlashCommandResponse, 0, len(uc.builtins))
+	for _, name := range []string{models.SlashCommandTask, models.SlashCommandPoll, models.SlashCommandRemind} {
+		builtin := uc.builtins[name]
+		if !builtin.available {
+			continue
+		}
+		responses = append(responses, &models.SlashCommandResponse{
+			Name:        name,
+			Description: builtin.description,
+			Usage:       builtin.usage,
+			BuiltIn:     true,
+			IsActive:    true,
+		})
+	}
+
+	commands, err := uc.commandRepo.List(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, command := range commands {
+		response := command.ToResponse()
+		response.WebhookURL = "" // Только для администраторов
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// GetCustomCommands retrieves all custom commands including inactive ones
+func (uc *slashCommandUsecase) GetCustomCommands() ([]*models.SlashCommandResponse, error) {
+	commands, err := uc.commandRepo.List(false)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*models.SlashCommandResponse, 0, len(commands))
+	for _, command := range commands {
+		responses = append(responses, command.ToResponse())
+	}
+	return responses, nil
+}
+
+// CreateCommand registers a custom command. The webhook secret is returned only once.
+func (uc *slashCommandUsecase) CreateCommand(userID uint, req *models.CreateSlashCommandRequest) (*models.SlashCommandCredentialsResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Name), "/"))
+	if !slashCommandName.MatchString(name) {
+		return nil, fmt.Errorf("validation failed: command name must start with a letter and contain only letters, digits, '-' and '_'")
+	}
+	if _, exists := uc.builtins[name]; exists {
+		return nil, fmt.Errorf("validation failed: /%s is a built-in command", name)
+	}
+	if err := validateWebhookURL(req.WebhookURL); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := uc.commandRepo.GetByName(name); err == nil {
+		return nil, fmt.Errorf("command /%s already exists", name)
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	command := &models.SlashCommand{
+		Name:          name,
+		Description:   strings.TrimSpace(req.Description),
+		Usage:         strings.TrimSpace(req.Usage),
+		WebhookURL:    req.WebhookURL,
+		WebhookSecret: secret,
+		CreatedBy:     userID,
+		IsActive:      true,
+	}
+	if err := uc.commandRepo.Create(command); err != nil {
+		return nil, err
+	}
+
+	return &models.SlashCommandCredentialsResponse{
+		Command:       command.ToResponse(),
+		WebhookSecret: secret,
+	}, nil
+}
+
+// UpdateCommand updates a custom command
+func (uc *slashCommandUsecase) UpdateCommand(commandID uint, req *models.UpdateSlashCommandRequest) (*models.SlashCommandResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	command, err := uc.commandRepo.GetByID(commandID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		command.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Usage != nil {
+		command.Usage = strings.TrimSpace(*req.Usage)
+	}
+	if req.WebhookURL != nil {
+		if err := validateWebhookURL(*req.WebhookURL); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		command.WebhookURL = *req.WebhookURL
+	}
+	if req.IsActive != nil {
+		command.IsActive = *req.IsActive
+	}
+
+	if err := uc.commandRepo.Update(command); err != nil {
+		return nil, err
+	}
+	return command.ToResponse(), nil
+}
+
+// DeleteCommand deletes a custom command
+func (uc *slashCommandUsecase) DeleteCommand(commandID uint) error {
+	return uc.commandRepo.Delete(commandID)
+}
+
+// runTask creates a task titled with all arguments
+func (uc *slashCommandUsecase) runTask(userID, chatID uint, args []string, locale i18n.Locale) (*models.SlashCommandResult, error) {
+	title := strings.Join(args, " ")
+	if title == "" {
+		return nil, fmt.Errorf("validation failed: usage: %s", uc.builtins[models.SlashCommandTask].usage)
+	}
+	if len([]rune(title)) > maxCommandText {
+		return nil, fmt.Errorf("validation failed: task title must be at most %d characters", maxCommandText)
+	}
+
+	taskID, err := uc.tasks.CreateTask(userID, title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+
+	return &models.SlashCommandResult{
+		Text:        i18n.T(locale, "chat.command_task_created", map[string]interface{}{"Title": title}),
+		RelatedType: "task",
+		RelatedID:   &taskID,
+	}, nil
+}
+
+// runPoll creates a poll with the first argument as the question and the rest as options
+func (uc *slashCommandUsecase) runPoll(userID, chatID uint, args []string, locale i18n.Locale) (*models.SlashCommandResult, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("validation failed: usage: %s", uc.builtins[models.SlashCommandPoll].usage)
+	}
+	question, options := args[0], args[1:]
+	if len([]rune(question)) > maxCommandText {
+		return nil, fmt.Errorf("validation failed: poll question must be at most %d characters", maxCommandText)
+	}
+
+	switch len(options) {
+	case 0:
+		options = []string{i18n.T(locale, "chat.command_poll_yes", nil), i18n.T(locale, "chat.command_poll_no", nil)}
+	case 1:
+		return nil, fmt.Errorf("validation failed: poll needs at least two options")
+	}
+
+	pollID, err := uc.polls.CreatePoll(userID, question, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	return &models.SlashCommandResult{
+		Text:        i18n.T(locale, "chat.command_poll_created", map[string]interface{}{"Question": question}),
+		RelatedType: "poll",
+		RelatedID:   &pollID,
+	}, nil
+}
+
+// runRemind schedules a reminder after the delay in the first argument
+func (uc *slashCommandUsecase) runRemind(userID, chatID uint, args []string, locale i18n.Locale) (*models.SlashCommandResult, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("validation failed: usage: %s", uc.builtins[models.SlashCommandRemind].usage)
+	}
+	delay, err := parseReminderDelay(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	text := strings.Join(args[1:], " ")
+	if text == "" {
+		text = i18n.T(locale, "chat.command_remind_default", nil)
+	}
+	if len([]rune(text)) > maxCommandText {
+		return nil, fmt.Errorf("validation failed: reminder text must be at most %d characters", maxCommandText)
+	}
+
+	at := time.Now().Add(delay)
+	if _, err := uc.reminders.ScheduleReminder(userID, chatID, text, at); err != nil {
+		return nil, fmt.Errorf("failed to schedule reminder: %w", err)
+	}
+
+	return &models.SlashCommandResult{
+		Text:        i18n.T(locale, "chat.command_remind_scheduled", map[string]interface{}{"Time": i18n.FormatDateTime(locale, at.In(uc.orgSettings.Get().Location()))}),
+		RelatedType: "reminder",
+	}, nil
+}
+
+// invokeWebhook posts the invocation to the command webhook and returns the text of its reply.
+// The body is signed like bot webhook events (X-Tachyon-Signature header).
+func (uc *slashCommandUsecase) invokeWebhook(command *models.SlashCommand, invocation *models.SlashCommandInvocation) (string, error) {
+	body, err := json.Marshal(invocation)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal invocation: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, command.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tachyon-Slash-Command/1.0")
+	req.Header.Set("X-Tachyon-Signature", "sha256="+signWebhookPayload(command.WebhookSecret, body))
+
+	resp, err := uc.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		return "", fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	var reply struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&reply); err != nil {
+		return "", fmt.Errorf("failed to decode webhook reply: %w", err)
+	}
+	reply.Text = strings.TrimSpace(reply.Text)
+	if reply.Text == "" {
+		return "", fmt.Errorf("webhook reply has no text")
+	}
+	if runes := []rune(reply.Text); len(runes) > maxMessageLength {
+		reply.Text = string(runes[:maxMessageLength])
+	}
+	return reply.Text, nil
+}
+
+// parseSlashCommand splits "/name text" into the lowercase name and the text after it
+func parseSlashCommand(content string) (string, string, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "/") {
+		return "", "", false
+	}
+
+	name, text := content[1:], ""
+	if i := strings.IndexFunc(name, unicode.IsSpace); i >= 0 {
+		name, text = name[:i], name[i:]
+	}
+	name = strings.ToLower(name)
+	if !slashCommandName.MatchString(name) {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(text), true
+}
+
+// splitCommandArgs splits command text into arguments on spaces. Quoted arguments ("...", «...»,
+// “...”) may contain spaces.
+func splitCommandArgs(text string) []string {
+	closing := map[rune]rune{'"': '"', '«': '»', '“': '”'}
+
+	args := []string{}
+	var current strings.Builder
+	var quote rune
+	quoted := false
+	for _, r := range text {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case closing[r] != 0 && current.Len() == 0:
+			quote, quoted = closing[r], true
+		case unicode.IsSpace(r):
+			if current.Len() > 0 || quoted {
+				args = append(args, strings.TrimSpace(current.String()))
+				current.Reset()
+				quoted = false
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 || quoted {
+		args = append(args, strings.TrimSpace(current.String()))
+	}
+
+	nonEmpty := args[:0]
+	for _, arg := range args {
+		if arg != "" {
+			nonEmpty = append(nonEmpty, arg)
+		}
+	}
+	return nonEmpty
+}
+
+// parseReminderDelay parses delays like 15m, 2h, 1h30m or 3d
+func parseReminderDelay(value string) (time.Duration, error) {
+	var delay time.Duration
+	var err error
+	if days, found := strings.CutSuffix(strings.ToLower(value), "d"); found {
+		var count int
+		count, err = strconv.Atoi(days)
+		delay = time.Duration(count) * 24 * time.Hour
+	} else {
+		delay, err = time.ParseDuration(strings.ToLower(value))
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid reminder delay %q, use e.g. 15m, 2h or 1d", value)
+	}
+	if delay < time.Minute || delay > maxReminderDelay {
+		return 0, fmt.Errorf("reminder delay must be between 1 minute and 30 days")
+	}
+	return delay, nil
+}
+
+// commandLocale returns the language of command text: Russian if it has Cyrillic words, English
+// if it has other words, the default locale otherwise. Words with digits such as 15m are skipped.
+func commandLocale(text string) i18n.Locale {
+	locale := i18n.DefaultLocale
+	for _, word := range strings.Fields(text) {
+		if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		for _, r := range word {
+			if unicode.Is(unicode.Cyrillic, r) {
+				return i18n.LocaleRU
+			}
+			if unicode.IsLetter(r) {
+				locale = i18n.LocaleEN
+			}
+		}
+	}
+	return locale
+}
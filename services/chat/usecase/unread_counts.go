@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/chat/models"
+	"tachyon-messenger/services/chat/repository"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/redis"
+)
+
+// Unread message counters are cached in Redis per user and adjusted on every message,
+// read receipt and membership change. Operations that can't be applied exactly
+// (message deletion, joining a chat with history) drop the cached counters instead,
+// and ReconcileUnreadCounts periodically fixes any remaining drift.
+
+// GetUnreadCounts returns unread message counts of all user's chats
+func (uc *chatUsecase) GetUnreadCounts(userID uint) (*models.UnreadCountsResponse, error) {
+	counts, ok, err := uc.unread.GetChats(userID)
+	if err != nil {
+		logUnreadCacheError(err, "Failed to get cached unread counts", userID)
+	}
+
+	if !ok {
+		counts, err = uc.messageRepo.GetUnreadCounts(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get unread counts: %w", err)
+		}
+
+		if err := uc.unread.SetChats(userID, counts); err != nil {
+			logUnreadCacheError(err, "Failed to cache unread counts", userID)
+		}
+	}
+
+	response := &models.UnreadCountsResponse{
+		Chats: counts,
+	}
+	for _, count := range counts {
+		response.Total += count
+	}
+
+	return response, nil
+}
+
+// GetDigestStats counts messages of a user for the daily digest of the notification service:
+// messages other members sent since the last digest, the chats they were sent to and messages
+// still unread
+func (uc *chatUsecase) GetDigestStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.MessagesDigestStats, error) {
+	if req.UserID == 0 {
+		return nil, fmt.Errorf("validation failed: user_id is required")
+	}
+
+	counts, err := uc.messageRepo.GetNewMessageCounts(req.UserID, req.Since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get new messages: %w", err)
+	}
+
+	unread, err := uc.GetUnreadCounts(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &sharedmodels.MessagesDigestStats{
+		UnreadMessages: unread.Total,
+		ActiveChats:    int64(len(counts)),
+	}
+	for _, count := range counts {
+		stats.NewMessages += count
+	}
+	return stats, nil
+}
+
+// ReconcileUnreadCounts overwrites cached unread counters with values from the database
+func (uc *chatUsecase) ReconcileUnreadCounts() (int, error) {
+	userIDs, err := uc.unread.CachedChatUsers()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cached unread counters: %w", err)
+	}
+
+	reconciled := 0
+	for _, userID := range userIDs {
+		counts, err := uc.messageRepo.GetUnreadCounts(userID)
+		if err != nil {
+			return reconciled, fmt.Errorf("failed to reconcile unread counts: %w", err)
+		}
+
+		if err := uc.unread.ReconcileChats(userID, counts); err != nil {
+			return reconciled, fmt.Errorf("failed to reconcile unread counts: %w", err)
+		}
+		reconciled++
+	}
+
+	return reconciled, nil
+}
+
+// countNewMessage increments unread counters of all chat members except the author
+func countNewMessage(unread *redis.UnreadCounter, chatRepo repository.ChatRepository, message *models.Message) {
+	if unread == nil {
+		return
+	}
+
+	memberIDs, err := chatRepo.GetMemberIDs(message.ChatID)
+	if err != nil {
+		logUnreadCacheError(err, "Failed to get chat members for unread counts", message.SenderID)
+		return
+	}
+
+	recipients := make([]uint, 0, len(memberIDs))
+	for _, memberID := range memberIDs {
+		if memberID != message.SenderID {
+			recipients = append(recipients, memberID)
+		}
+	}
+
+	if err := unread.IncrChat(message.ChatID, 1, recipients...); err != nil {
+		logUnreadCacheError(err, "Failed to update cached unread counts", message.SenderID)
+		invalidateUnreadCounts(unread, recipients...)
+	}
+}
+
+// invalidateChatUnreadCounts drops cached counters of all chat members
+func invalidateChatUnreadCounts(unread *redis.UnreadCounter, chatRepo repository.ChatRepository, chatID uint) {
+	if unread == nil {
+		return
+	}
+
+	memberIDs, err := chatRepo.GetMemberIDs(chatID)
+	if err != nil {
+		logUnreadCacheError(err, "Failed to get chat members for unread counts", 0)
+		return
+	}
+	invalidateUnreadCounts(unread, memberIDs...)
+}
+
+// invalidateUnreadCounts drops cached counters, they are reloaded on next read
+func invalidateUnreadCounts(unread *redis.UnreadCounter, userIDs ...uint) {
+	if err := unread.InvalidateChats(userIDs...); err != nil {
+		logUnreadCacheError(err, "Failed to invalidate cached unread counts", 0)
+	}
+}
+
+func logUnreadCacheError(err error, message string, userID uint) {
+	logger.WithFields(map[string]interface{}{
+		"user_id": userID,
+		"error":   err.Error(),
+	}).Warn(message)
+}
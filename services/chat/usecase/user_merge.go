@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"fmt"
+
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// MergeUsers moves chat data of a duplicate account to the primary account
+func (uc *chatUsecase) MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error) {
+	if req.PrimaryUserID == req.DuplicateUserID {
+		return nil, fmt.Errorf("validation failed: cannot merge user into itself")
+	}
+
+	result, err := uc.chatRepo.MergeUsers(req.PrimaryUserID, req.DuplicateUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+
+	// Both accounts changed chats, cached counters are reloaded on next read
+	if uc.unread != nil {
+		invalidateUnreadCounts(uc.unread, req.PrimaryUserID, req.DuplicateUserID)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// webhookLookupTimeout bounds resolving the webhook host when a bot is saved
+const webhookLookupTimeout = 3 * time.Second
+
+// blockedWebhookNetworks are address ranges bot webhooks must not reach besides loopback,
+// private and link-local ones: shared address space of carrier-grade NAT and IPv6 unique local
+var blockedWebhookNetworks = []*net.IPNet{
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fc00::/7"),
+}
+
+// blockedWebhookHostSuffixes are hostnames of the internal network
+var blockedWebhookHostSuffixes = []string{"localhost", ".local", ".internal", ".cluster.local"}
+
+// isBlockedWebhookIP checks if an address belongs to the service's own or an internal network
+func isBlockedWebhookIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range blockedWebhookNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWebhookHost rejects webhook hosts of the internal network: internal addresses,
+// hostnames without a domain such as docker service names, and names resolving to internal addresses.
+// A host that cannot be resolved yet is accepted, the dialer checks addresses on every delivery.
+func validateWebhookHost(host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if ip := net.ParseIP(host); ip != nil {
+		if isBlockedWebhookIP(ip) {
+			return fmt.Errorf("webhook URL must not point to an internal address")
+		}
+		return nil
+	}
+
+	if !strings.Contains(host, ".") {
+		return fmt.Errorf("webhook URL must use a public domain name")
+	}
+	for _, suffix := range blockedWebhookHostSuffixes {
+		if host == strings.TrimPrefix(suffix, ".") || strings.HasSuffix(host, suffix) {
+			return fmt.Errorf("webhook URL must not point to an internal address")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookLookupTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if isBlockedWebhookIP(addr.IP) {
+			return fmt.Errorf("webhook URL must not point to an internal address")
+		}
+	}
+	return nil
+}
+
+// newWebhookHTTPClient creates a client for bot and slash command webhooks that refuses to
+// connect to internal addresses. The address is checked after resolving, so a DNS record
+// changed after the webhook was saved or a redirect cannot reach the internal network either.
+func newWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isBlockedWebhookIP(ip) {
+				return fmt.Errorf("webhook address %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Without a proxy the dialer sees the webhook address itself
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// mustParseCIDR parses a network of the blocked ranges
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
@@ -3,9 +3,9 @@ package websocket
 import (
 	"encoding/json"
 	"log"
-	"strings"
 	"time"
 
+	"tachyon-messenger/services/chat/markdown"
 	"tachyon-messenger/services/chat/models"
 
 	"github.com/gorilla/websocket"
@@ -25,16 +25,23 @@ const (
 	maxMessageSize = 8192
 )
 
-// NewClient creates a new WebSocket client
-func NewClient(conn *websocket.Conn, hub *Hub, userID uint) *Client {
+// NewClient creates a new WebSocket client speaking the negotiated protocol, nil means protocol 1
+func NewClient(conn *websocket.Conn, hub *Hub, userID uint, session *Session) *Client {
+	if session == nil {
+		session = LegacySession()
+	}
+
 	return &Client{
-		conn:      conn,
-		send:      make(chan []byte, 512),
-		hub:       hub,
-		userID:    userID,
-		chatRooms: make(map[uint]bool),
-		lastSeen:  time.Now(),
-		status:    "online",
+		conn:             conn,
+		send:             make(chan []byte, 512),
+		hub:              hub,
+		userID:           userID,
+		chatRooms:        make(map[uint]bool),
+		lastSeen:         time.Now(),
+		lastActive:       time.Now(),
+		status:           "online",
+		session:          session,
+		deprecationsSent: make(map[string]bool),
 	}
 }
 
@@ -68,6 +75,7 @@ func (c *Client) ReadPump() {
 		// Update metrics and last seen
 		c.hub.metrics.MessagesReceived++
 		c.updateLastSeen()
+		c.markActive()
 
 		// Handle the message
 		c.handleIncomingMessage(messageBytes)
@@ -130,108 +138,99 @@ func (c *Client) WritePump() {
 // handleIncomingMessage handles incoming WebSocket messages
 func (c *Client) handleIncomingMessage(messageBytes []byte) {
 	// Parse incoming message
-	var wsMsg models.WSMessage
-	if err := json.Unmarshal(messageBytes, &wsMsg); err != nil {
+	eventType, id, payload, err := decodeCommand(c.session.Version, messageBytes)
+	if err != nil {
 		log.Printf("Error parsing WebSocket message from user %d: %v", c.userID, err)
-		c.sendErrorMessage("Invalid message format")
+		c.sendErrorMessage(id, "Invalid message format")
 		return
 	}
 
-	// Set user ID and timestamp
-	wsMsg.UserID = c.userID
-	wsMsg.SentAt = time.Now()
-
-	// Handle different message types
-	c.handleWebSocketMessage(&wsMsg)
-}
+	definition, deprecated := lookupClientEvent(c.session.Version, eventType)
+	if definition == nil {
+		log.Printf("Unknown message type %s from user %d", eventType, c.userID)
+		c.sendErrorMessage(id, "Unknown message type")
+		return
+	}
+	if deprecated {
+		c.warnDeprecated(string(eventType), definition.Type)
+	}
 
-// handleWebSocketMessage handles different types of WebSocket messages
-func (c *Client) handleWebSocketMessage(wsMsg *models.WSMessage) {
-	switch wsMsg.Type {
-	case models.WSMessageTypeTyping:
-		c.handleTypingMessage(wsMsg)
+	// Decode and validate the payload against the schema of the command
+	command := definition.NewPayload()
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, command); err != nil {
+			log.Printf("Invalid %s payload from user %d: %v", definition.Type, c.userID, err)
+			c.sendErrorMessage(id, "Invalid message data")
+			return
+		}
+	}
+	if err := command.Validate(); err != nil {
+		c.sendErrorMessage(id, err.Error())
+		return
+	}
 
-	case models.WSMessageTypeUserJoin:
-		c.handleJoinMessage(wsMsg)
+	// Handle different message types
+	switch definition.Type {
+	case EventTyping:
+		typing := command.(*TypingPayload)
+		c.hub.BroadcastTyping(typing.ChatID, c.userID, typing.IsTyping)
 
-	case models.WSMessageTypeUserLeave:
-		c.handleLeaveMessage(wsMsg)
+	case EventChatJoin:
+		c.handleJoinMessage(command.(*ChatPayload))
 
-	case models.WSMessageTypeRead:
-		c.handleReadMessage(wsMsg)
+	case EventChatLeave:
+		c.handleLeaveMessage(command.(*ChatPayload))
 
-	case models.WSMessageTypeNewMessage:
-		c.handleChatMessage(wsMsg)
+	case EventMessageRead:
+		c.handleReadMessage(command.(*MessageReadPayload))
 
-	default:
-		log.Printf("Unknown message type %s from user %d", wsMsg.Type, c.userID)
-		c.sendErrorMessage("Unknown message type")
-	}
-}
+	case EventMessageSend:
+		c.handleChatMessage(id, command.(*MessageSendPayload))
 
-// handleTypingMessage handles typing indicator messages
-func (c *Client) handleTypingMessage(wsMsg *models.WSMessage) {
-	if typingData, ok := wsMsg.Data.(map[string]interface{}); ok {
-		if isTyping, exists := typingData["is_typing"].(bool); exists {
-			c.hub.BroadcastTyping(wsMsg.ChatID, c.userID, isTyping)
-		}
+	case EventAck:
+		c.handleAck(command.(*AckPayload))
 	}
 }
 
 // handleJoinMessage handles user join messages
-func (c *Client) handleJoinMessage(wsMsg *models.WSMessage) {
-	c.joinChatRoom(wsMsg.ChatID)
-	log.Printf("User %d joined chat room %d", c.userID, wsMsg.ChatID)
+func (c *Client) handleJoinMessage(payload *ChatPayload) {
+	c.joinChatRoom(payload.ChatID)
+	log.Printf("User %d joined chat room %d", c.userID, payload.ChatID)
 }
 
 // handleLeaveMessage handles user leave messages
-func (c *Client) handleLeaveMessage(wsMsg *models.WSMessage) {
-	c.leaveChatRoom(wsMsg.ChatID)
-	log.Printf("User %d left chat room %d", c.userID, wsMsg.ChatID)
+func (c *Client) handleLeaveMessage(payload *ChatPayload) {
+	c.leaveChatRoom(payload.ChatID)
+	log.Printf("User %d left chat room %d", c.userID, payload.ChatID)
 }
 
 // handleReadMessage handles message read notifications
-func (c *Client) handleReadMessage(wsMsg *models.WSMessage) {
-	if readData, ok := wsMsg.Data.(map[string]interface{}); ok {
-		if messageID, exists := readData["message_id"]; exists {
-			log.Printf("User %d marked message %v as read in chat %d", c.userID, messageID, wsMsg.ChatID)
-			// Here you can implement message read tracking logic
-		}
-	}
+func (c *Client) handleReadMessage(payload *MessageReadPayload) {
+	log.Printf("User %d marked message %d as read in chat %d", c.userID, payload.MessageID, payload.ChatID)
+
+	payload.UserID = c.userID
+	c.hub.BroadcastToChatExcludeSender(payload.ChatID, payload, EventMessageRead, c.userID)
 }
 
 // handleChatMessage handles chat messages
-func (c *Client) handleChatMessage(wsMsg *models.WSMessage) {
-	// Validate message data
-	chatData, ok := wsMsg.Data.(map[string]interface{})
-	if !ok {
-		log.Printf("Invalid chat message data from user %d", c.userID)
-		c.sendErrorMessage("Invalid message data")
-		return
+func (c *Client) handleChatMessage(id string, payload *MessageSendPayload) {
+	if payload.Type == "" {
+		payload.Type = models.MessageTypeText
 	}
-
-	// Extract content from message data
-	content, exists := chatData["content"].(string)
-	if !exists || strings.TrimSpace(content) == "" {
-		log.Printf("Missing or empty content in message from user %d", c.userID)
-		c.sendErrorMessage("Message content is required")
-		return
+	if payload.ContentFormat == "" {
+		payload.ContentFormat = markdown.FormatPlain
 	}
 
-	// Extract message type (optional)
-	messageType := "text"
-	if msgType, exists := chatData["type"].(string); exists {
-		messageType = msgType
-	}
-
-	log.Printf("Chat message from user %d in chat %d: %s", c.userID, wsMsg.ChatID, content)
+	log.Printf("Chat message from user %d in chat %d: %s", c.userID, payload.ChatID, payload.Content)
 
 	// ВАЖНО: Сохраняем сообщение в базу данных через MessageUsecase
 	// Создаем request для сохранения сообщения
 	sendRequest := &models.SendMessageRequest{
-		ChatID:  wsMsg.ChatID,
-		Content: content,
-		Type:    models.MessageType(messageType),
+		ChatID:        payload.ChatID,
+		Content:       payload.Content,
+		ContentFormat: payload.ContentFormat,
+		Type:          payload.Type,
+		ReplyToID:     payload.ReplyToID,
 	}
 
 	// Получаем доступ к messageUsecase через хаб
@@ -240,52 +239,97 @@ func (c *Client) handleChatMessage(wsMsg *models.WSMessage) {
 		savedMessage, err := c.hub.messageUsecase.SendMessage(c.userID, sendRequest)
 		if err != nil {
 			log.Printf("Failed to save message to database for user %d: %v", c.userID, err)
-			c.sendErrorMessage("Failed to save message")
+			c.sendErrorMessage(id, "Failed to save message")
 			return
 		}
 
 		// Обновляем данные сообщения с сохраненной информацией
-		enhancedData := map[string]interface{}{
-			"id":         savedMessage.ID,
-			"content":    savedMessage.Content,
-			"type":       savedMessage.Type,
-			"sender_id":  savedMessage.SenderID,
-			"chat_id":    savedMessage.ChatID,
-			"created_at": savedMessage.CreatedAt,
-			"status":     savedMessage.Status,
+		createdAt := savedMessage.CreatedAt
+		created := &MessageCreatedPayload{
+			ID:            savedMessage.ID,
+			ChatID:        savedMessage.ChatID,
+			SenderID:      savedMessage.SenderID,
+			Content:       savedMessage.Content,
+			ContentFormat: savedMessage.ContentFormat,
+			ContentHTML:   savedMessage.ContentHTML,
+			Entities:      savedMessage.Entities,
+			Type:          savedMessage.Type,
+			Status:        savedMessage.Status,
+			CreatedAt:     &createdAt,
 		}
 
 		// Broadcast обогащенного сообщения всем пользователям в чате
-		c.hub.BroadcastToChat(wsMsg.ChatID, enhancedData, models.WSMessageTypeNewMessage, c.userID)
+		c.hub.BroadcastToChat(payload.ChatID, created, EventMessageCreated, c.userID)
 
-		log.Printf("Message saved to database with ID %d and broadcasted to chat %d", savedMessage.ID, wsMsg.ChatID)
+		log.Printf("Message saved to database with ID %d and broadcasted to chat %d", savedMessage.ID, payload.ChatID)
 	} else {
 		// Fallback: если messageUsecase недоступен, просто broadcast без сохранения
 		log.Printf("MessageUsecase not available, broadcasting without saving to database")
-		c.hub.BroadcastToChat(wsMsg.ChatID, chatData, models.WSMessageTypeNewMessage, c.userID)
+		c.hub.BroadcastToChat(payload.ChatID, &MessageCreatedPayload{
+			ChatID:        payload.ChatID,
+			SenderID:      c.userID,
+			Content:       payload.Content,
+			ContentFormat: payload.ContentFormat,
+			Type:          payload.Type,
+		}, EventMessageCreated, c.userID)
 	}
 }
 
-// sendErrorMessage sends an error message to the client
-func (c *Client) sendErrorMessage(errorMsg string) {
-	// Создаем inline константу или используем строку напрямую
-	const WSMessageTypeError = "error"
+// sendErrorMessage sends an error message to the client, replyTo is the ID of the rejected command
+func (c *Client) sendErrorMessage(replyTo, errorMsg string) {
+	c.sendEvent(EventError, &ErrorPayload{Error: errorMsg, ReplyTo: replyTo})
+}
+
+// warnDeprecated tells a protocol 2 client once per connection that it used a deprecated command name
+func (c *Client) warnDeprecated(name string, replacement EventType) {
+	c.hub.metrics.DeprecatedCommands++
+
+	c.mutex.Lock()
+	warned := c.deprecationsSent[name]
+	c.deprecationsSent[name] = true
+	c.mutex.Unlock()
 
-	errorMessage := map[string]interface{}{
-		"type":      WSMessageTypeError,
-		"data":      map[string]string{"error": errorMsg},
-		"timestamp": time.Now(),
+	if warned {
+		return
 	}
+	log.Printf("User %d used deprecated message type %s, replacement is %s", c.userID, name, replacement)
+	c.sendEvent(EventDeprecation, &DeprecationPayload{Type: name, Replacement: replacement})
+}
 
-	if messageBytes, err := json.Marshal(errorMessage); err == nil {
-		select {
-		case c.send <- messageBytes:
-		default:
-			log.Printf("Error message could not be sent to user %d: channel full", c.userID)
-		}
+// sendEvent sends an event to the client if its protocol and capabilities accept it
+func (c *Client) sendEvent(event EventType, payload interface{}) bool {
+	if definition := lookupServerEvent(event); definition == nil || !c.session.accepts(definition) {
+		return false
+	}
+
+	messageBytes, err := encodeEvent(c.session.Version, event, 0, 0, payload, time.Now())
+	if err != nil {
+		log.Printf("Error encoding %s event for user %d: %v", event, c.userID, err)
+		return false
+	}
+
+	select {
+	case c.send <- messageBytes:
+		return true
+	default:
+		log.Printf("Event %s could not be sent to user %d: channel full", event, c.userID)
+		return false
 	}
 }
 
+// sendHello confirms the negotiated protocol to protocol 2 clients with the last sequence number
+// sent to the user and how long unacked events are kept for resume
+func (c *Client) sendHello(lastSeq uint64, replayWindow time.Duration) {
+	c.sendEvent(EventHello, &HelloPayload{
+		Version:      c.session.Version,
+		MinVersion:   MinProtocolVersion,
+		Capabilities: c.session.CapabilityList(),
+		Deprecated:   deprecatedNames(),
+		LastSeq:      lastSeq,
+		ReplayWindow: int(replayWindow / time.Second),
+	})
+}
+
 // joinChatRoom adds the client to a chat room
 func (c *Client) joinChatRoom(chatID uint) {
 	c.mutex.Lock()
@@ -311,6 +355,13 @@ func (c *Client) updateLastSeen() {
 	c.lastSeen = time.Now()
 }
 
+// markActive records a message from the client
+func (c *Client) markActive() {
+	c.mutex.Lock()
+	c.lastActive = time.Now()
+	c.mutex.Unlock()
+}
+
 // GetStatus returns the current status of the client
 func (c *Client) GetStatus() string {
 	return c.status
@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultDrainPeriod is how long clients of a shutting down instance have to reconnect elsewhere
+	DefaultDrainPeriod = 15 * time.Second
+
+	// DefaultReconnectAfter is the delay clients are asked to wait before reconnecting, so the load
+	// balancer stops routing to the instance meanwhile
+	DefaultReconnectAfter = 2 * time.Second
+
+	// drainPollInterval is how often a draining hub checks whether all clients left
+	drainPollInterval = 100 * time.Millisecond
+)
+
+// Drain prepares the hub for shutdown on deploys. New connections are refused, connected clients
+// get a server_restarting event and have period to reconnect to another instance, the rest are
+// closed with the service restart close code. It returns once no client is left, Close stops
+// the hub afterwards.
+func (h *Hub) Drain(period, reconnectAfter time.Duration) {
+	h.mutex.Lock()
+	if h.drainNotice != nil {
+		h.mutex.Unlock()
+		return
+	}
+	h.drainNotice = &ServerRestartingPayload{
+		ReconnectAfter: int(reconnectAfter / time.Second),
+		CloseAt:        time.Now().Add(period),
+	}
+	for _, client := range h.clients {
+		client.sendEvent(EventServerRestarting, h.drainNotice)
+	}
+	notified := len(h.clients)
+	h.mutex.Unlock()
+
+	log.Printf("Draining WebSocket hub: %d clients notified, closing remaining connections in %s", notified, period)
+
+	deadline := time.NewTimer(period)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-deadline.C:
+			closed := h.closeClients(websocket.CloseServiceRestart, "server restarting")
+			log.Printf("Drain period is over, closed %d remaining connections", closed)
+			return
+
+		case <-ticker.C:
+			h.mutex.RLock()
+			remaining := len(h.clients)
+			h.mutex.RUnlock()
+			if remaining == 0 {
+				log.Println("All WebSocket clients reconnected elsewhere")
+				return
+			}
+		}
+	}
+}
+
+// DrainNotice returns the server_restarting event of a draining hub, nil while it accepts connections
+func (h *Hub) DrainNotice() *ServerRestartingPayload {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.drainNotice
+}
+
+// closeClients closes connections of all clients with a close code, their read pumps unregister them
+func (h *Hub) closeClients(code int, reason string) int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for _, client := range h.clients {
+		closeConnection(client.conn, code, reason)
+	}
+	return len(h.clients)
+}
+
+// closeConnection sends a close frame with code and closes the connection
+func closeConnection(conn *websocket.Conn, code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+	if err := conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait)); err != nil && err != websocket.ErrCloseSent {
+		log.Printf("Failed to send close frame: %v", err)
+	}
+	conn.Close()
+}
@@ -0,0 +1,284 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/chat/markdown"
+	"tachyon-messenger/services/chat/models"
+)
+
+// EventType is the type of a protocol 2 event
+type EventType string
+
+// Server events, the payload type is given for each
+const (
+	EventHello          EventType = "hello"           // HelloPayload, first event of a protocol 2 connection
+	EventMessageCreated EventType = "message.created" // MessageCreatedPayload
+	EventMessageUpdated EventType = "message.updated" // models.MessageResponse
+	EventMessageDeleted EventType = "message.deleted" // MessageRefPayload
+	EventMessageRead    EventType = "message.read"    // MessageReadPayload
+	EventTyping         EventType = "typing"          // TypingIndicator
+	EventPresence       EventType = "presence"        // UserPresence
+	EventReaction       EventType = "reaction"        // ReactionPayload
+	EventChatJoined     EventType = "chat.joined"     // ChatMembershipPayload
+	EventChatLeft       EventType = "chat.left"       // ChatMembershipPayload
+	EventError          EventType = "error"           // ErrorPayload
+	EventDeprecation    EventType = "deprecation"     // DeprecationPayload
+	EventResync         EventType = "resync"          // ResyncPayload, missed events can't be replayed
+
+	EventServerRestarting EventType = "server_restarting" // ServerRestartingPayload, the instance is shutting down
+)
+
+// Client commands, typing and message.read are sent by clients as well
+const (
+	EventMessageSend EventType = "message.send" // MessageSendPayload
+	EventChatJoin    EventType = "chat.join"    // ChatPayload
+	EventChatLeave   EventType = "chat.leave"   // ChatPayload
+	EventAck         EventType = "ack"          // AckPayload
+)
+
+// ClientPayload is the payload of a client command
+type ClientPayload interface {
+	Validate() error
+}
+
+// EventDefinition describes an event type of the protocol
+type EventDefinition struct {
+	Type       EventType
+	LegacyType models.WSMessageType // Type name in protocol 1, empty for events protocol 1 clients never get
+	Since      int                  // First protocol version with the event
+	Capability Capability           // Capability the client must negotiate, empty for events every client gets
+	Reliable   bool                 // Sequenced and replayed to protocol 2 clients until acked
+	NewPayload func() ClientPayload // Payload schema of client commands, nil for server events
+}
+
+// serverEvents is the registry of events sent by the server
+var serverEvents = []EventDefinition{
+	{Type: EventHello, Since: ProtocolV2},
+	{Type: EventMessageCreated, LegacyType: models.WSMessageTypeNewMessage, Since: ProtocolV1, Reliable: true},
+	{Type: EventMessageUpdated, LegacyType: models.WSMessageTypeMessageEdit, Since: ProtocolV1, Reliable: true},
+	{Type: EventMessageDeleted, LegacyType: models.WSMessageTypeMessageDelete, Since: ProtocolV1, Reliable: true},
+	{Type: EventMessageRead, LegacyType: models.WSMessageTypeRead, Since: ProtocolV1, Capability: CapabilityReadReceipts},
+	{Type: EventTyping, LegacyType: models.WSMessageTypeTyping, Since: ProtocolV1, Capability: CapabilityTyping},
+	{Type: EventPresence, LegacyType: "user_presence", Since: ProtocolV1, Capability: CapabilityPresence},
+	{Type: EventReaction, LegacyType: models.WSMessageTypeReaction, Since: ProtocolV1, Capability: CapabilityReactions, Reliable: true},
+	{Type: EventChatJoined, LegacyType: models.WSMessageTypeUserJoin, Since: ProtocolV1},
+	{Type: EventChatLeft, LegacyType: models.WSMessageTypeUserLeave, Since: ProtocolV1},
+	{Type: EventError, LegacyType: "error", Since: ProtocolV1},
+	{Type: EventDeprecation, Since: ProtocolV2},
+	{Type: EventResync, Since: ProtocolV2},
+	{Type: EventServerRestarting, LegacyType: "server_restarting", Since: ProtocolV1},
+}
+
+// clientEvents is the registry of commands sent by clients. Protocol 2 clients still sending
+// the legacy type names are served, but get a deprecation event pointing to the new name.
+var clientEvents = []EventDefinition{
+	{Type: EventMessageSend, LegacyType: models.WSMessageTypeNewMessage, Since: ProtocolV1,
+		NewPayload: func() ClientPayload { return &MessageSendPayload{} }},
+	{Type: EventTyping, LegacyType: models.WSMessageTypeTyping, Since: ProtocolV1,
+		NewPayload: func() ClientPayload { return &TypingPayload{} }},
+	{Type: EventChatJoin, LegacyType: models.WSMessageTypeUserJoin, Since: ProtocolV1,
+		NewPayload: func() ClientPayload { return &ChatPayload{} }},
+	{Type: EventChatLeave, LegacyType: models.WSMessageTypeUserLeave, Since: ProtocolV1,
+		NewPayload: func() ClientPayload { return &ChatPayload{} }},
+	{Type: EventMessageRead, LegacyType: models.WSMessageTypeRead, Since: ProtocolV1,
+		NewPayload: func() ClientPayload { return &MessageReadPayload{} }},
+	{Type: EventAck, Since: ProtocolV2,
+		NewPayload: func() ClientPayload { return &AckPayload{} }},
+}
+
+// lookupServerEvent returns the definition of a server event
+func lookupServerEvent(event EventType) *EventDefinition {
+	for i := range serverEvents {
+		if serverEvents[i].Type == event {
+			return &serverEvents[i]
+		}
+	}
+	return nil
+}
+
+// lookupClientEvent returns the definition of a client command. deprecated is set when
+// a protocol 2 client used the legacy name of the command.
+func lookupClientEvent(version int, event EventType) (definition *EventDefinition, deprecated bool) {
+	for i := range clientEvents {
+		definition := &clientEvents[i]
+		if version == ProtocolV1 {
+			if definition.LegacyType != "" && definition.LegacyType == models.WSMessageType(event) {
+				return definition, false
+			}
+			continue
+		}
+		if definition.Type == event {
+			return definition, false
+		}
+		if definition.LegacyType != "" && definition.LegacyType == models.WSMessageType(event) {
+			return definition, true
+		}
+	}
+	return nil, false
+}
+
+// deprecatedNames maps legacy command names to their protocol 2 replacements
+func deprecatedNames() map[string]EventType {
+	names := make(map[string]EventType)
+	for _, definition := range clientEvents {
+		if definition.LegacyType != "" && string(definition.LegacyType) != string(definition.Type) {
+			names[string(definition.LegacyType)] = definition.Type
+		}
+	}
+	return names
+}
+
+// Client command payloads
+
+// MessageSendPayload sends a message to a chat
+type MessageSendPayload struct {
+	ChatID        uint               `json:"chat_id"`
+	Content       string             `json:"content"`
+	ContentFormat markdown.Format    `json:"content_format,omitempty"`
+	Type          models.MessageType `json:"type,omitempty"`
+	ReplyToID     *uint              `json:"reply_to_id,omitempty"`
+}
+
+// Validate checks the payload
+func (p *MessageSendPayload) Validate() error {
+	if p.ChatID == 0 {
+		return errors.New("chat_id is required")
+	}
+	if strings.TrimSpace(p.Content) == "" {
+		return errors.New("Message content is required")
+	}
+	return nil
+}
+
+// TypingPayload reports that the user started or stopped typing
+type TypingPayload struct {
+	ChatID   uint `json:"chat_id"`
+	IsTyping bool `json:"is_typing"`
+}
+
+// Validate checks the payload
+func (p *TypingPayload) Validate() error {
+	if p.ChatID == 0 {
+		return errors.New("chat_id is required")
+	}
+	return nil
+}
+
+// ChatPayload joins or leaves a chat room
+type ChatPayload struct {
+	ChatID uint `json:"chat_id"`
+}
+
+// Validate checks the payload
+func (p *ChatPayload) Validate() error {
+	if p.ChatID == 0 {
+		return errors.New("chat_id is required")
+	}
+	return nil
+}
+
+// MessageReadPayload marks a message as read, sent by clients and relayed to the chat
+type MessageReadPayload struct {
+	ChatID    uint `json:"chat_id"`
+	MessageID uint `json:"message_id"`
+	UserID    uint `json:"user_id,omitempty"`
+}
+
+// Validate checks the payload
+func (p *MessageReadPayload) Validate() error {
+	if p.ChatID == 0 || p.MessageID == 0 {
+		return errors.New("chat_id and message_id are required")
+	}
+	return nil
+}
+
+// AckPayload acknowledges all reliable events up to seq
+type AckPayload struct {
+	Seq uint64 `json:"seq"`
+}
+
+// Validate checks the payload
+func (p *AckPayload) Validate() error {
+	if p.Seq == 0 {
+		return errors.New("seq is required")
+	}
+	return nil
+}
+
+// Server event payloads
+
+// HelloPayload confirms the negotiated protocol of a connection
+type HelloPayload struct {
+	Version      int                  `json:"version"`
+	MinVersion   int                  `json:"min_version"`
+	Capabilities []Capability         `json:"capabilities"`
+	Deprecated   map[string]EventType `json:"deprecated,omitempty"` // Legacy command names and their replacements
+	LastSeq      uint64               `json:"last_seq"`             // Last sequence number sent to the user
+	ReplayWindow int                  `json:"replay_window"`        // Seconds unacked events are kept for resume, 0 without replay
+}
+
+// MessageCreatedPayload is a new chat message
+type MessageCreatedPayload struct {
+	ID            uint                 `json:"id,omitempty"`
+	ChatID        uint                 `json:"chat_id"`
+	SenderID      uint                 `json:"sender_id"`
+	Content       string               `json:"content"`
+	ContentFormat markdown.Format      `json:"content_format,omitempty"`
+	ContentHTML   string               `json:"content_html,omitempty"`
+	Entities      []markdown.Entity    `json:"entities,omitempty"`
+	Type          models.MessageType   `json:"type"`
+	Status        models.MessageStatus `json:"status,omitempty"`
+	CreatedAt     *time.Time           `json:"created_at,omitempty"`
+}
+
+// MessageRefPayload references a message
+type MessageRefPayload struct {
+	ChatID    uint `json:"chat_id"`
+	MessageID uint `json:"message_id"`
+}
+
+// ReactionPayload is a reaction added to or removed from a message
+type ReactionPayload struct {
+	ChatID    uint   `json:"chat_id"`
+	MessageID uint   `json:"message_id"`
+	UserID    uint   `json:"user_id"`
+	Emoji     string `json:"emoji"`
+	Removed   bool   `json:"removed,omitempty"`
+}
+
+// ChatMembershipPayload reports a user joining or leaving a chat room
+type ChatMembershipPayload struct {
+	UserID uint   `json:"user_id"`
+	ChatID uint   `json:"chat_id"`
+	Action string `json:"action"` // join or leave
+}
+
+// ErrorPayload reports a rejected command
+type ErrorPayload struct {
+	Error   string `json:"error"`
+	ReplyTo string `json:"reply_to,omitempty"` // ID of the rejected command
+}
+
+// ResyncPayload tells a resuming client that events after from_seq can't be replayed, it has
+// to reload its chats and continue from last_seq
+type ResyncPayload struct {
+	FromSeq uint64 `json:"from_seq"`
+	LastSeq uint64 `json:"last_seq"`
+	Reason  string `json:"reason"` // expired, unavailable or overflow
+}
+
+// ServerRestartingPayload tells clients that the instance is shutting down. Clients reconnect
+// after reconnect_after seconds plus random jitter, connections left at close_at are closed.
+type ServerRestartingPayload struct {
+	ReconnectAfter int       `json:"reconnect_after"` // Секунды до переподключения
+	CloseAt        time.Time `json:"close_at"`
+}
+
+// DeprecationPayload warns a client that it used a deprecated command name
+type DeprecationPayload struct {
+	Type        string    `json:"type"`
+	Replacement EventType `json:"replacement"`
+}
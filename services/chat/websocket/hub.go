@@ -1,13 +1,12 @@
 package websocket
 
 import (
-	"encoding/json"
 	"log"
 	"net/http"
 	"time"
 
-	"tachyon-messenger/services/chat/models"
 	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/redis"
 
 	"github.com/gorilla/websocket"
 )
@@ -25,11 +24,16 @@ var Upgrader = websocket.Upgrader{
 
 // HubMetrics contains hub statistics
 type HubMetrics struct {
-	ConnectedClients int       `json:"connected_clients"`
-	ActiveChatRooms  int       `json:"active_chat_rooms"`
-	MessagesSent     int64     `json:"messages_sent"`
-	MessagesReceived int64     `json:"messages_received"`
-	Uptime           time.Time `json:"uptime"`
+	ConnectedClients int   `json:"connected_clients"`
+	ActiveChatRooms  int   `json:"active_chat_rooms"`
+	MessagesSent     int64 `json:"messages_sent"`
+	MessagesReceived int64 `json:"messages_received"`
+	// Commands sent by protocol 2 clients under deprecated names
+	DeprecatedCommands int64 `json:"deprecated_commands"`
+	// Events replayed to resuming clients and resumes that needed a resync
+	ReplayedEvents int64     `json:"replayed_events"`
+	Resyncs        int64     `json:"resyncs"`
+	Uptime         time.Time `json:"uptime"`
 }
 
 // TypingIndicator represents a typing status
@@ -48,8 +52,9 @@ type UserPresence struct {
 	ChatRooms []uint    `json:"chat_rooms,omitempty"`
 }
 
-// NewHub creates a new WebSocket hub
-func NewHub(messageUsecase usecase.MessageUsecase) *Hub {
+// NewHub creates a new WebSocket hub, replay buffers reliable events for resuming clients and presence
+// shares presence of connected users with other instances, both may be nil
+func NewHub(messageUsecase usecase.MessageUsecase, replay *redis.ReplayBuffer, presence *redis.PresenceStore) *Hub {
 	return &Hub{
 		clients:        make(map[uint]*Client),
 		chatRooms:      make(map[uint]map[uint]bool),
@@ -58,6 +63,9 @@ func NewHub(messageUsecase usecase.MessageUsecase) *Hub {
 		unregister:     make(chan *Client),
 		shutdown:       make(chan struct{}),
 		messageUsecase: messageUsecase, // ДОБАВЛЯЕМ messageUsecase
+		replay:         replay,
+		presence:       presence,
+		detached:       make(map[uint]*detachedClient),
 		metrics: &HubMetrics{
 			Uptime: time.Now(),
 		},
@@ -110,7 +118,14 @@ func (h *Hub) registerClient(client *Client) {
 	client.status = "online"
 	client.lastSeen = time.Now()
 
-	log.Printf("Client registered: user %d (total clients: %d)", client.userID, len(h.clients))
+	log.Printf("Client registered: user %d, protocol v%d (total clients: %d)", client.userID, client.session.Version, len(h.clients))
+	h.startDelivery(client)
+	h.recordOnline(client)
+
+	// A client that got through while the hub started draining is told to reconnect as well
+	if h.drainNotice != nil {
+		client.sendEvent(EventServerRestarting, h.drainNotice)
+	}
 
 	// Notify about user coming online
 	h.broadcastUserPresence(client.userID, "online")
@@ -124,6 +139,7 @@ func (h *Hub) unregisterClient(client *Client) {
 	if storedClient, exists := h.clients[client.userID]; exists && storedClient == client {
 		delete(h.clients, client.userID)
 		close(client.send)
+		h.detach(client)
 
 		// Remove user from all chat rooms
 		for chatID := range client.chatRooms {
@@ -137,6 +153,12 @@ func (h *Hub) unregisterClient(client *Client) {
 
 		log.Printf("Client unregistered: user %d (remaining clients: %d)", client.userID, len(h.clients))
 
+		// Clients leaving a draining instance reconnect to another one and stay online meanwhile
+		if h.drainNotice != nil {
+			return
+		}
+		h.recordOffline(client.userID)
+
 		// Notify about user going offline
 		h.broadcastUserPresence(client.userID, "offline")
 	}
@@ -160,45 +182,70 @@ func (h *Hub) forceDisconnectClient(client *Client) {
 
 // broadcastMessage broadcasts a message to relevant clients
 func (h *Hub) broadcastMessage(broadcastMsg *BroadcastMessage) {
-	h.mutex.RLock()
-	defer h.mutex.RUnlock()
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
 
-	broadcastMsg.Timestamp = time.Now()
-	message, err := json.Marshal(broadcastMsg)
-	if err != nil {
-		log.Printf("Error marshaling broadcast message: %v", err)
+	definition := lookupServerEvent(broadcastMsg.Event)
+	if definition == nil {
+		log.Printf("Unknown broadcast event type %s", broadcastMsg.Event)
 		return
 	}
 
-	sent := 0
+	// Encode once per protocol version in use
+	broadcastMsg.Timestamp = time.Now()
+	encoded := make(map[int][]byte)
 
 	// Get users in the chat room
-	if users, exists := h.chatRooms[broadcastMsg.ChatID]; exists {
-		for userID := range users {
-			// Skip excluded user (e.g., message sender)
-			if broadcastMsg.ExcludeUser != 0 && userID == broadcastMsg.ExcludeUser {
-				continue
-			}
+	users := h.chatRooms[broadcastMsg.ChatID]
+	recipients := make([]*Client, 0, len(users))
+	for userID := range users {
+		// Skip excluded user (e.g., message sender)
+		if broadcastMsg.ExcludeUser != 0 && userID == broadcastMsg.ExcludeUser {
+			continue
+		}
 
-			if client, clientExists := h.clients[userID]; clientExists {
-				select {
-				case client.send <- message:
-					sent++
-				default:
-					// Client's send channel is full, remove client
-					log.Printf("Client %d send channel full, removing", userID)
-					close(client.send)
-					delete(h.clients, userID)
-					delete(users, userID)
-				}
+		// Skip clients whose protocol or capabilities do not cover the event
+		if client, clientExists := h.clients[userID]; clientExists && client.session.accepts(definition) {
+			recipients = append(recipients, client)
+		}
+	}
+
+	// Reliable events carry a sequence number of each protocol 2 recipient
+	sequenced := h.sequenceEvent(definition, broadcastMsg, recipients)
+
+	sent := 0
+	for _, client := range recipients {
+		message, ok := sequenced[client.userID]
+		if !ok {
+			message, ok = encoded[client.session.Version]
+		}
+		if !ok {
+			var err error
+			message, err = encodeEvent(client.session.Version, broadcastMsg.Event, broadcastMsg.ChatID, broadcastMsg.UserID, broadcastMsg.Data, broadcastMsg.Timestamp)
+			if err != nil {
+				log.Printf("Error marshaling broadcast message: %v", err)
+				return
 			}
+			encoded[client.session.Version] = message
+		}
+
+		select {
+		case client.send <- message:
+			sent++
+		default:
+			// Client's send channel is full, remove client. Buffered events are replayed when it resumes.
+			log.Printf("Client %d send channel full, removing", client.userID)
+			close(client.send)
+			delete(h.clients, client.userID)
+			delete(users, client.userID)
+			h.detach(client)
 		}
 	}
 
 	h.metrics.MessagesSent += int64(sent)
 
 	if sent > 0 {
-		log.Printf("Broadcasted %s message to %d clients in chat %d", broadcastMsg.Type, sent, broadcastMsg.ChatID)
+		log.Printf("Broadcasted %s message to %d clients in chat %d", broadcastMsg.Event, sent, broadcastMsg.ChatID)
 	}
 }
 
@@ -225,7 +272,7 @@ func (h *Hub) broadcastUserPresence(userID uint, status string) {
 	// Broadcast to all chat rooms user is in
 	for chatID := range client.chatRooms {
 		broadcastMsg := &BroadcastMessage{
-			Type:        models.WSMessageType("user_presence"),
+			Event:       EventPresence,
 			ChatID:      chatID,
 			UserID:      userID,
 			Data:        presence,
@@ -260,14 +307,14 @@ func (h *Hub) JoinChatRoom(userID, chatID uint) {
 		log.Printf("User %d joined chat room %d (room has %d users)", userID, chatID, len(h.chatRooms[chatID]))
 
 		// Notify other users in the chat
-		joinData := map[string]interface{}{
-			"user_id": userID,
-			"chat_id": chatID,
-			"action":  "join",
+		joinData := &ChatMembershipPayload{
+			UserID: userID,
+			ChatID: chatID,
+			Action: "join",
 		}
 
 		broadcastMsg := &BroadcastMessage{
-			Type:        models.WSMessageTypeUserJoin,
+			Event:       EventChatJoined,
 			ChatID:      chatID,
 			UserID:      userID,
 			Data:        joinData,
@@ -304,14 +351,14 @@ func (h *Hub) LeaveChatRoom(userID, chatID uint) {
 		client.mutex.Unlock()
 
 		// Notify other users in the chat
-		leaveData := map[string]interface{}{
-			"user_id": userID,
-			"chat_id": chatID,
-			"action":  "leave",
+		leaveData := &ChatMembershipPayload{
+			UserID: userID,
+			ChatID: chatID,
+			Action: "leave",
 		}
 
 		broadcastMsg := &BroadcastMessage{
-			Type:        models.WSMessageTypeUserLeave,
+			Event:       EventChatLeft,
 			ChatID:      chatID,
 			UserID:      userID,
 			Data:        leaveData,
@@ -326,10 +373,10 @@ func (h *Hub) LeaveChatRoom(userID, chatID uint) {
 	}
 }
 
-// BroadcastToChat broadcasts a message to all users in a chat
-func (h *Hub) BroadcastToChat(chatID uint, data interface{}, msgType models.WSMessageType, senderID uint) {
+// BroadcastToChat broadcasts an event to all users in a chat
+func (h *Hub) BroadcastToChat(chatID uint, data interface{}, event EventType, senderID uint) {
 	broadcastMsg := &BroadcastMessage{
-		Type:        msgType,
+		Event:       event,
 		ChatID:      chatID,
 		UserID:      senderID,
 		Data:        data,
@@ -343,10 +390,10 @@ func (h *Hub) BroadcastToChat(chatID uint, data interface{}, msgType models.WSMe
 	}
 }
 
-// BroadcastToChatExcludeSender broadcasts a message to all users in a chat except sender
-func (h *Hub) BroadcastToChatExcludeSender(chatID uint, data interface{}, msgType models.WSMessageType, senderID uint) {
+// BroadcastToChatExcludeSender broadcasts an event to all users in a chat except sender
+func (h *Hub) BroadcastToChatExcludeSender(chatID uint, data interface{}, event EventType, senderID uint) {
 	broadcastMsg := &BroadcastMessage{
-		Type:        msgType,
+		Event:       event,
 		ChatID:      chatID,
 		UserID:      senderID,
 		Data:        data,
@@ -360,8 +407,8 @@ func (h *Hub) BroadcastToChatExcludeSender(chatID uint, data interface{}, msgTyp
 	}
 }
 
-// SendToUser sends a message to a specific user
-func (h *Hub) SendToUser(userID uint, data interface{}, msgType models.WSMessageType) {
+// SendToUser sends an event to a specific user
+func (h *Hub) SendToUser(userID uint, data interface{}, event EventType) {
 	h.mutex.RLock()
 	client, exists := h.clients[userID]
 	h.mutex.RUnlock()
@@ -371,13 +418,12 @@ func (h *Hub) SendToUser(userID uint, data interface{}, msgType models.WSMessage
 		return
 	}
 
-	message := map[string]interface{}{
-		"type":      msgType,
-		"data":      data,
-		"timestamp": time.Now(),
+	definition := lookupServerEvent(event)
+	if definition == nil || !client.session.accepts(definition) {
+		return
 	}
 
-	messageBytes, err := json.Marshal(message)
+	messageBytes, err := encodeEvent(client.session.Version, event, 0, 0, data, time.Now())
 	if err != nil {
 		log.Printf("Error marshaling message for user %d: %v", userID, err)
 		return
@@ -405,7 +451,7 @@ func (h *Hub) BroadcastTyping(chatID, userID uint, isTyping bool) {
 		Timestamp: time.Now(),
 	}
 
-	h.BroadcastToChatExcludeSender(chatID, typingData, models.WSMessageTypeTyping, userID)
+	h.BroadcastToChatExcludeSender(chatID, typingData, EventTyping, userID)
 }
 
 // GetConnectedUsers returns the list of connected user IDs
@@ -484,8 +530,11 @@ func (h *Hub) updateMetrics() {
 			h.mutex.Lock()
 			h.metrics.ConnectedClients = len(h.clients)
 			h.metrics.ActiveChatRooms = len(h.chatRooms)
+			h.pruneDetached(time.Now())
 			h.mutex.Unlock()
 
+			h.refreshPresence()
+
 		case <-h.shutdown:
 			return
 		}
@@ -500,13 +549,14 @@ func (h *Hub) cleanup() {
 	// Close all client connections
 	for userID, client := range h.clients {
 		close(client.send)
-		client.conn.Close()
+		closeConnection(client.conn, websocket.CloseGoingAway, "server shutting down")
 		log.Printf("Closed connection for user %d", userID)
 	}
 
 	// Clear all data structures
 	h.clients = make(map[uint]*Client)
 	h.chatRooms = make(map[uint]map[uint]bool)
+	h.detached = make(map[uint]*detachedClient)
 
 	log.Println("Hub cleanup completed")
 }
@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"log"
+	"time"
+
+	"tachyon-messenger/shared/redis"
+)
+
+// presenceAwayAfter is how long a connected client may send nothing before it shows as away
+const presenceAwayAfter = 5 * time.Minute
+
+// presenceOf returns presence of a connected client: away when the user set it or stays idle,
+// last seen is the last message from the client
+func presenceOf(client *Client, now time.Time) redis.Presence {
+	client.mutex.RLock()
+	lastActive := client.lastActive
+	client.mutex.RUnlock()
+
+	status := redis.PresenceOnline
+	if client.status == redis.PresenceAway || now.Sub(lastActive) >= presenceAwayAfter {
+		status = redis.PresenceAway
+	}
+	return redis.Presence{Status: status, LastSeen: lastActive}
+}
+
+// recordOnline stores presence of a registered client
+func (h *Hub) recordOnline(client *Client) {
+	presence := map[uint]redis.Presence{client.userID: presenceOf(client, time.Now())}
+	if err := h.presence.SetOnline(presence); err != nil {
+		log.Printf("Failed to store presence of user %d: %v", client.userID, err)
+	}
+}
+
+// recordOffline stores a user who disconnected from this instance
+func (h *Hub) recordOffline(userID uint) {
+	if err := h.presence.SetOffline(userID, time.Now()); err != nil {
+		log.Printf("Failed to store presence of user %d: %v", userID, err)
+	}
+}
+
+// refreshPresence stores presence of all connected clients again before it expires, turning
+// idle clients away
+func (h *Hub) refreshPresence() {
+	if h.presence == nil {
+		return
+	}
+
+	now := time.Now()
+	h.mutex.RLock()
+	presence := make(map[uint]redis.Presence, len(h.clients))
+	for userID, client := range h.clients {
+		presence[userID] = presenceOf(client, now)
+	}
+	h.mutex.RUnlock()
+
+	if err := h.presence.SetOnline(presence); err != nil {
+		log.Printf("Failed to refresh presence of %d users: %v", len(presence), err)
+	}
+}
+
+// GetPresence returns presence of users connected to this instance, other users are offline
+// without last seen. It stands in for the presence store when the service runs without Redis.
+func (h *Hub) GetPresence(userIDs []uint) (map[uint]redis.Presence, error) {
+	now := time.Now()
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	presence := make(map[uint]redis.Presence, len(userIDs))
+	for _, userID := range userIDs {
+		if client, exists := h.clients[userID]; exists {
+			presence[userID] = presenceOf(client, now)
+		} else {
+			presence[userID] = redis.Presence{Status: redis.PresenceOffline}
+		}
+	}
+	return presence, nil
+}
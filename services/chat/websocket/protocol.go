@@ -0,0 +1,296 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/chat/models"
+)
+
+// Protocol versions:
+//   - 1 is the original format {type, chat_id, user_id, data, timestamp} with legacy type names
+//     such as "new_message". Clients that do not negotiate a version get it, so they keep working.
+//   - 2 wraps every event in an Envelope {v, type, id, ts, payload} with dotted type names and
+//     a typed payload per event, see the registry in events.go. Connections start with a hello event.
+//
+// Delivery of protocol 2 events marked reliable in the registry (messages and reactions):
+//   - every reliable event carries seq, a sequence number per user that keeps counting across
+//     connections; other events have no seq
+//   - the event is kept in a replay buffer before it is sent, until the client acks it or the
+//     replay window of hello elapses. An ack {seq} is cumulative and covers all events up to seq.
+//   - events of a user who just disconnected are still buffered for the chat rooms they were in
+//   - a client reconnecting with ?resume=<last seq it processed> gets the buffered events after
+//     that seq right after hello and before any live event. If some of them are gone it gets
+//     resync instead and must reload its chats over REST.
+//   - delivery is at least once: a replay may repeat events that were received but not acked yet.
+//     Clients skip events with seq up to the last one processed and dedupe messages by message
+//     ID, which stays the same in replays and REST responses.
+const (
+	ProtocolV1 = 1
+	ProtocolV2 = 2
+
+	// CurrentProtocolVersion is the newest protocol version of the server
+	CurrentProtocolVersion = ProtocolV2
+
+	// MinProtocolVersion is the oldest protocol version the server still accepts
+	MinProtocolVersion = ProtocolV1
+
+	// SubprotocolPrefix prefixes versions negotiated via Sec-WebSocket-Protocol, e.g. "tachyon.v2"
+	SubprotocolPrefix = "tachyon.v"
+)
+
+// ErrUnsupportedVersion is returned when a client asks for a protocol version the server no longer speaks
+var ErrUnsupportedVersion = errors.New("unsupported protocol version")
+
+// Capability is an optional group of events a client can opt into at connect
+type Capability string
+
+const (
+	CapabilityTyping       Capability = "typing"
+	CapabilityPresence     Capability = "presence"
+	CapabilityReactions    Capability = "reactions"
+	CapabilityReadReceipts Capability = "read_receipts"
+)
+
+// SupportedCapabilities lists all capabilities of the server
+var SupportedCapabilities = []Capability{
+	CapabilityTyping,
+	CapabilityPresence,
+	CapabilityReactions,
+	CapabilityReadReceipts,
+}
+
+// Envelope is the frame of every protocol 2 event
+type Envelope struct {
+	V       int             `json:"v"`
+	Type    EventType       `json:"type"`
+	ID      string          `json:"id,omitempty"`  // Set by the sender, echoed as reply_to in errors
+	Seq     uint64          `json:"seq,omitempty"` // Delivery sequence of reliable events
+	TS      time.Time       `json:"ts"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// legacyMessage is the frame of protocol 1 events
+type legacyMessage struct {
+	Type      models.WSMessageType `json:"type"`
+	ChatID    uint                 `json:"chat_id,omitempty"`
+	UserID    uint                 `json:"user_id,omitempty"`
+	Data      interface{}          `json:"data"`
+	Timestamp time.Time            `json:"timestamp"`
+}
+
+// Session holds the protocol negotiated with a client at connect
+type Session struct {
+	Version      int
+	Capabilities map[Capability]bool
+	Subprotocol  string  // Sec-WebSocket-Protocol to confirm, empty when negotiated via query
+	Resume       *uint64 // Last sequence number the client processed, nil for a fresh connection
+}
+
+// LegacySession returns the session of clients that do not negotiate: protocol 1 with all capabilities
+func LegacySession() *Session {
+	return &Session{Version: ProtocolV1, Capabilities: allCapabilities()}
+}
+
+// Negotiate picks the protocol of a connection. The version comes from the "v" query parameter
+// or a "tachyon.vN" subprotocol, capabilities from the comma-separated "capabilities" parameter.
+// Versions newer than the server are downgraded to CurrentProtocolVersion, unknown capabilities
+// are ignored and no capabilities parameter means all of them. Protocol 2 clients pass the last
+// sequence number they processed in the "resume" parameter to get missed events replayed.
+func Negotiate(r *http.Request) (*Session, error) {
+	session := LegacySession()
+
+	if value := r.URL.Query().Get("v"); value != "" {
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedVersion, value)
+		}
+		session.Version = version
+	} else {
+		// The confirmed subprotocol must be one the client asked for, pick the newest we speak
+		requested := false
+		for _, protocol := range websocketSubprotocols(r) {
+			value, found := strings.CutPrefix(protocol, SubprotocolPrefix)
+			if !found {
+				continue
+			}
+			if !requested {
+				requested = true
+				session.Version = 0
+			}
+			if version, err := strconv.Atoi(value); err == nil && version <= CurrentProtocolVersion && version > session.Version {
+				session.Version = version
+				session.Subprotocol = protocol
+			}
+		}
+	}
+
+	if session.Version < MinProtocolVersion {
+		return nil, fmt.Errorf("%w: %d, minimum is %d", ErrUnsupportedVersion, session.Version, MinProtocolVersion)
+	}
+	if session.Version > CurrentProtocolVersion {
+		session.Version = CurrentProtocolVersion
+	}
+
+	if value, ok := r.URL.Query()["capabilities"]; ok {
+		session.Capabilities = make(map[Capability]bool)
+		for _, name := range strings.Split(strings.Join(value, ","), ",") {
+			capability := Capability(strings.TrimSpace(name))
+			for _, supported := range SupportedCapabilities {
+				if capability == supported {
+					session.Capabilities[capability] = true
+				}
+			}
+		}
+	}
+
+	// An invalid resume is treated as a fresh connection, the client reloads over REST anyway
+	if value := r.URL.Query().Get("resume"); value != "" && session.Version >= ProtocolV2 {
+		if seq, err := strconv.ParseUint(value, 10, 64); err == nil {
+			session.Resume = &seq
+		}
+	}
+
+	return session, nil
+}
+
+// Has checks if the client opted into a capability
+func (s *Session) Has(capability Capability) bool {
+	return capability == "" || s.Capabilities[capability]
+}
+
+// CapabilityList returns the negotiated capabilities in the order of SupportedCapabilities
+func (s *Session) CapabilityList() []Capability {
+	capabilities := make([]Capability, 0, len(s.Capabilities))
+	for _, capability := range SupportedCapabilities {
+		if s.Capabilities[capability] {
+			capabilities = append(capabilities, capability)
+		}
+	}
+	return capabilities
+}
+
+// accepts checks if an outbound event can be sent to the client
+func (s *Session) accepts(definition *EventDefinition) bool {
+	if s.Version < definition.Since || (s.Version == ProtocolV1 && definition.LegacyType == "") {
+		return false
+	}
+	return s.Has(definition.Capability)
+}
+
+// encodeEvent encodes an outbound event in the given protocol version
+func encodeEvent(version int, event EventType, chatID, userID uint, payload interface{}, timestamp time.Time) ([]byte, error) {
+	definition := lookupServerEvent(event)
+	if definition == nil {
+		return nil, fmt.Errorf("unknown event type %s", event)
+	}
+
+	if version == ProtocolV1 {
+		return json.Marshal(&legacyMessage{
+			Type:      definition.LegacyType,
+			ChatID:    chatID,
+			UserID:    userID,
+			Data:      payload,
+			Timestamp: timestamp,
+		})
+	}
+
+	envelope, err := newEnvelope(version, event, payload, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope)
+}
+
+// newEnvelope wraps an outbound event in a protocol 2 envelope
+func newEnvelope(version int, event EventType, payload interface{}, timestamp time.Time) (*Envelope, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", event, err)
+	}
+	return &Envelope{
+		V:       version,
+		Type:    event,
+		ID:      newEventID(),
+		TS:      timestamp,
+		Payload: raw,
+	}, nil
+}
+
+// withSeq sets the sequence number of an encoded envelope
+func withSeq(frame []byte, seq uint64) ([]byte, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(frame, &envelope); err != nil {
+		return nil, err
+	}
+	envelope.Seq = seq
+	return json.Marshal(&envelope)
+}
+
+// decodeCommand parses a client frame into its event type, ID and payload. Protocol 1 frames keep
+// chat_id next to data, it is moved into the payload so both versions share the payload schemas.
+func decodeCommand(version int, frame []byte) (EventType, string, json.RawMessage, error) {
+	if version == ProtocolV1 {
+		var message models.WSMessage
+		if err := json.Unmarshal(frame, &message); err != nil {
+			return "", "", nil, err
+		}
+
+		data, _ := message.Data.(map[string]interface{})
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		if _, exists := data["chat_id"]; !exists && message.ChatID != 0 {
+			data["chat_id"] = message.ChatID
+		}
+		payload, err := json.Marshal(data)
+		return EventType(message.Type), "", payload, err
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(frame, &envelope); err != nil {
+		return "", "", nil, err
+	}
+	if envelope.V != 0 && envelope.V != version {
+		return "", envelope.ID, nil, fmt.Errorf("%w: frame has v=%d, connection uses %d", ErrUnsupportedVersion, envelope.V, version)
+	}
+	return envelope.Type, envelope.ID, envelope.Payload, nil
+}
+
+// websocketSubprotocols returns the subprotocols requested by the client
+func websocketSubprotocols(r *http.Request) []string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if protocol = strings.TrimSpace(protocol); protocol != "" {
+				protocols = append(protocols, protocol)
+			}
+		}
+	}
+	return protocols
+}
+
+// allCapabilities returns a set of all supported capabilities
+func allCapabilities() map[Capability]bool {
+	capabilities := make(map[Capability]bool, len(SupportedCapabilities))
+	for _, capability := range SupportedCapabilities {
+		capabilities[capability] = true
+	}
+	return capabilities
+}
+
+// newEventID generates a random event ID
+func newEventID() string {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(bytes)
+}
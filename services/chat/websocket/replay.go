@@ -0,0 +1,204 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"tachyon-messenger/shared/redis"
+)
+
+// Resync reasons
+const (
+	resyncExpired     = "expired"     // Events after the resume sequence already left the buffer
+	resyncUnavailable = "unavailable" // The server runs without a replay buffer
+	resyncOverflow    = "overflow"    // Too many missed events to replay over the connection
+)
+
+// detachedClient is a recently disconnected protocol 2 client whose reliable events are still buffered
+type detachedClient struct {
+	session *Session
+	rooms   map[uint]bool
+	until   time.Time
+}
+
+// detach keeps buffering reliable events of a disconnected client for the rooms it was in,
+// so it can resume within the replay window
+func (h *Hub) detach(client *Client) {
+	if h.replay == nil || client.session.Version < ProtocolV2 {
+		return
+	}
+
+	h.detached[client.userID] = &detachedClient{
+		session: client.session,
+		rooms:   client.GetChatRooms(),
+		until:   time.Now().Add(h.replay.TTL()),
+	}
+}
+
+// pruneDetached forgets disconnected clients whose replay window elapsed
+func (h *Hub) pruneDetached(now time.Time) {
+	for userID, detached := range h.detached {
+		if detached.until.Before(now) {
+			delete(h.detached, userID)
+		}
+	}
+}
+
+// startDelivery sends hello to a registered client, puts it back into the rooms it was in
+// before a recent disconnect and replays the events it missed when resuming
+func (h *Hub) startDelivery(client *Client) {
+	if detached, exists := h.detached[client.userID]; exists {
+		delete(h.detached, client.userID)
+		for chatID := range detached.rooms {
+			if _, exists := h.chatRooms[chatID]; !exists {
+				h.chatRooms[chatID] = make(map[uint]bool)
+			}
+			h.chatRooms[chatID][client.userID] = true
+
+			client.mutex.Lock()
+			client.chatRooms[chatID] = true
+			client.mutex.Unlock()
+		}
+	}
+
+	if h.replay == nil || client.session.Version < ProtocolV2 {
+		client.sendHello(0, 0)
+		if client.session.Resume != nil {
+			h.resync(client, *client.session.Resume, 0, resyncUnavailable)
+		}
+		return
+	}
+
+	var events []redis.ReplayEvent
+	var lastSeq uint64
+	var err error
+	if client.session.Resume != nil {
+		events, lastSeq, err = h.replay.Since(client.userID, *client.session.Resume)
+	} else {
+		lastSeq, err = h.replay.LastSeq(client.userID)
+	}
+	if err != nil {
+		log.Printf("Failed to load replay events of user %d: %v", client.userID, err)
+		client.sendHello(0, 0)
+		if client.session.Resume != nil {
+			h.resync(client, *client.session.Resume, 0, resyncUnavailable)
+		}
+		return
+	}
+
+	client.sendHello(lastSeq, h.replay.TTL())
+	if client.session.Resume == nil {
+		return
+	}
+
+	resume := *client.session.Resume
+	switch {
+	case resume == lastSeq:
+	case resume > lastSeq || len(events) == 0 || events[0].Seq != resume+1:
+		h.resync(client, resume, lastSeq, resyncExpired)
+		return
+	case len(events) > cap(client.send)/2:
+		h.resync(client, resume, lastSeq, resyncOverflow)
+		return
+	default:
+		for _, event := range events {
+			frame, err := withSeq(event.Frame, event.Seq)
+			if err != nil {
+				log.Printf("Invalid replay event %d of user %d: %v", event.Seq, client.userID, err)
+				h.resync(client, resume, lastSeq, resyncExpired)
+				return
+			}
+			client.send <- frame
+		}
+		h.metrics.ReplayedEvents += int64(len(events))
+		log.Printf("Replayed %d events to user %d after seq %d", len(events), client.userID, resume)
+	}
+
+	// The client processed everything up to the resume sequence
+	if err := h.replay.Ack(client.userID, resume); err != nil {
+		log.Printf("Failed to ack replay events of user %d: %v", client.userID, err)
+	}
+}
+
+// resync tells a resuming client that its missed events can't be replayed
+func (h *Hub) resync(client *Client, fromSeq, lastSeq uint64, reason string) {
+	h.metrics.Resyncs++
+	log.Printf("User %d can't resume after seq %d: %s", client.userID, fromSeq, reason)
+
+	client.sendEvent(EventResync, &ResyncPayload{
+		FromSeq: fromSeq,
+		LastSeq: lastSeq,
+		Reason:  reason,
+	})
+}
+
+// sequenceEvent buffers a reliable event for its protocol 2 recipients and the recently
+// disconnected users of the chat, and returns it encoded with the sequence number of each
+// connected recipient. Nil means the event is sent without sequence numbers.
+func (h *Hub) sequenceEvent(definition *EventDefinition, broadcastMsg *BroadcastMessage, recipients []*Client) map[uint][]byte {
+	if h.replay == nil || !definition.Reliable {
+		return nil
+	}
+
+	userIDs := make([]uint, 0, len(recipients))
+	for _, client := range recipients {
+		if client.session.Version >= ProtocolV2 {
+			userIDs = append(userIDs, client.userID)
+		}
+	}
+
+	h.pruneDetached(broadcastMsg.Timestamp)
+	for userID, detached := range h.detached {
+		if broadcastMsg.ExcludeUser != 0 && userID == broadcastMsg.ExcludeUser {
+			continue
+		}
+		if detached.rooms[broadcastMsg.ChatID] && detached.session.accepts(definition) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	envelope, err := newEnvelope(CurrentProtocolVersion, broadcastMsg.Event, broadcastMsg.Data, broadcastMsg.Timestamp)
+	if err != nil {
+		log.Printf("Error encoding %s event for replay: %v", broadcastMsg.Event, err)
+		return nil
+	}
+	frame, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("Error encoding %s event for replay: %v", broadcastMsg.Event, err)
+		return nil
+	}
+
+	seqs, err := h.replay.Append(frame, userIDs)
+	if err != nil {
+		log.Printf("Failed to buffer %s event for replay: %v", broadcastMsg.Event, err)
+		return nil
+	}
+
+	messages := make(map[uint][]byte, len(recipients))
+	for _, client := range recipients {
+		seq, ok := seqs[client.userID]
+		if !ok {
+			continue
+		}
+
+		envelope.Seq = seq
+		message, err := json.Marshal(envelope)
+		if err != nil {
+			log.Printf("Error encoding %s event for user %d: %v", broadcastMsg.Event, client.userID, err)
+			continue
+		}
+		messages[client.userID] = message
+	}
+	return messages
+}
+
+// handleAck drops events acknowledged by the client from the replay buffer
+func (c *Client) handleAck(payload *AckPayload) {
+	if err := c.hub.replay.Ack(c.userID, payload.Seq); err != nil {
+		log.Printf("Failed to ack replay events of user %d: %v", c.userID, err)
+	}
+}
@@ -2,8 +2,8 @@ package websocket
 
 import (
 	"sync"
-	"tachyon-messenger/services/chat/models"
 	"tachyon-messenger/services/chat/usecase"
+	"tachyon-messenger/shared/redis"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -55,8 +55,17 @@ type Client struct {
 	// Last seen timestamp for presence tracking
 	lastSeen time.Time
 
+	// Last message from the client, pongs excluded, guarded by mutex. Idle clients show as away.
+	lastActive time.Time
+
 	// Client status (online, away, busy, offline)
 	status string
+
+	// Protocol negotiated at connect
+	session *Session
+
+	// Deprecated command names the client was already warned about
+	deprecationsSent map[string]bool
 }
 
 // Hub maintains the set of active clients and broadcasts messages to clients
@@ -88,17 +97,29 @@ type Hub struct {
 	metrics *HubMetrics
 
 	messageUsecase usecase.MessageUsecase
+
+	// Buffer of reliable events for resume, nil without Redis
+	replay *redis.ReplayBuffer
+
+	// Presence of connected users shared with other instances, nil without Redis
+	presence *redis.PresenceStore
+
+	// Recently disconnected clients whose reliable events are still buffered, by user ID
+	detached map[uint]*detachedClient
+
+	// Event sent to clients while the hub drains before shutdown, nil until Drain
+	drainNotice *ServerRestartingPayload
 }
 
-// BroadcastMessage represents a message to be broadcasted to a room
-// BroadcastMessage represents a message to be broadcasted
+// BroadcastMessage represents an event to be broadcasted to a room,
+// it is encoded for the protocol version of each client
 type BroadcastMessage struct {
-	Type        models.WSMessageType `json:"type"`
-	ChatID      uint                 `json:"chat_id"`
-	UserID      uint                 `json:"user_id,omitempty"`
-	Data        interface{}          `json:"data"`
-	Timestamp   time.Time            `json:"timestamp"`
-	ExcludeUser uint                 `json:"-"` // Don't send to this user
+	Event       EventType
+	ChatID      uint
+	UserID      uint
+	Data        interface{}
+	Timestamp   time.Time
+	ExcludeUser uint // Don't send to this user
 }
 
 // DirectMessage represents a message to be sent to a specific client
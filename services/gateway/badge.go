@@ -0,0 +1,154 @@
+// File: services/gateway/badge.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// badgeTimeout limits how long the badge waits for downstream services
+const badgeTimeout = 3 * time.Second
+
+// BadgeResponse represents aggregated unread counters for the app badge
+type BadgeResponse struct {
+	Total         int64            `json:"total"`
+	Notifications int64            `json:"notifications"`
+	Messages      int64            `json:"messages"`
+	Chats         map[string]int64 `json:"chats"`
+	Unavailable   []string         `json:"unavailable,omitempty"` // Сервисы, счётчики которых не получены
+	RequestID     string           `json:"request_id"`
+}
+
+// badgeSource describes one downstream unread counter
+type badgeSource struct {
+	service ServiceConfig
+	path    string
+	apply   func(badge *BadgeResponse, body map[string]json.RawMessage) error
+}
+
+// badgeResult holds a downstream response
+type badgeResult struct {
+	statusCode int
+	body       map[string]json.RawMessage
+	err        error
+}
+
+// badgeHandler returns notification and chat unread counts in one response.
+// Both services are queried in parallel with the caller's credentials; if one of them
+// fails the badge is still returned with the service listed as unavailable.
+func badgeHandler(c *gin.Context) {
+	requestID := requestid.Get(c)
+	proxyConfig := getProxyConfig()
+
+	sources := []badgeSource{
+		{service: proxyConfig.NotificationService, path: "/api/v1/notifications/unread-count", apply: applyNotificationCounter},
+		{service: proxyConfig.ChatService, path: "/api/v1/chats/unread-counts", apply: applyChatCounters},
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), badgeTimeout)
+	defer cancel()
+
+	results := make([]badgeResult, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source badgeSource) {
+			defer wg.Done()
+			results[i] = fetchBadgeCounter(ctx, c, requestID, source)
+		}(i, source)
+	}
+	wg.Wait()
+
+	badge := BadgeResponse{
+		Chats:     map[string]int64{},
+		RequestID: requestID,
+	}
+
+	for i, result := range results {
+		service := sources[i].service.Name
+
+		// Invalid or expired token - same answer from every service
+		if result.statusCode == http.StatusUnauthorized {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":      "User not authenticated",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		if result.err == nil && result.statusCode != http.StatusOK {
+			result.err = fmt.Errorf("HTTP %d", result.statusCode)
+		}
+		if result.err == nil {
+			result.err = sources[i].apply(&badge, result.body)
+		}
+		if result.err != nil {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"service":    service,
+				"error":      result.err.Error(),
+			}).Warn("Failed to get unread counter for badge")
+
+			badge.Unavailable = append(badge.Unavailable, service)
+		}
+	}
+
+	if len(badge.Unavailable) == len(sources) {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":      "Unread counters are unavailable",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	badge.Total = badge.Notifications + badge.Messages
+	c.JSON(http.StatusOK, badge)
+}
+
+// fetchBadgeCounter requests an unread counter from a downstream service
+func fetchBadgeCounter(ctx context.Context, c *gin.Context, requestID string, source badgeSource) badgeResult {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.service.URL+source.path, nil)
+	if err != nil {
+		return badgeResult{err: fmt.Errorf("failed to create request: %w", err)}
+	}
+
+	req.Header.Set("Authorization", c.GetHeader("Authorization"))
+	req.Header.Set("X-Request-ID", requestID)
+	req.Header.Set("X-Forwarded-For", c.ClientIP())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return badgeResult{err: fmt.Errorf("request failed: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	result := badgeResult{statusCode: resp.StatusCode}
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&result.body); err != nil {
+			result.err = fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return result
+}
+
+// applyNotificationCounter copies unread notification count into the badge
+func applyNotificationCounter(badge *BadgeResponse, body map[string]json.RawMessage) error {
+	return json.Unmarshal(body["unread_count"], &badge.Notifications)
+}
+
+// applyChatCounters copies unread message counts into the badge
+func applyChatCounters(badge *BadgeResponse, body map[string]json.RawMessage) error {
+	if err := json.Unmarshal(body["unread_count"], &badge.Messages); err != nil {
+		return err
+	}
+	return json.Unmarshal(body["chats"], &badge.Chats)
+}
@@ -7,7 +7,10 @@ import (
 	"net/http"
 	"time"
 
+	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/switches"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -21,6 +24,9 @@ type ServiceHealth struct {
 	Latency   string    `json:"latency"`
 	Error     string    `json:"error,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// Instances of the service known to the registry
+	Endpoints []config.EndpointStatus `json:"endpoints,omitempty"`
 }
 
 // GatewayHealth represents overall gateway health
@@ -30,90 +36,107 @@ type GatewayHealth struct {
 	Version   string          `json:"version"`
 	Timestamp time.Time       `json:"timestamp"`
 	Services  []ServiceHealth `json:"services,omitempty"`
+
+	// Active maintenance mode and kill switches
+	Maintenance  *switches.Maintenance  `json:"maintenance,omitempty"`
+	KillSwitches []*switches.KillSwitch `json:"kill_switches,omitempty"`
+
+	// Load of proxied route groups
+	Concurrency []middleware.ConcurrencyStats `json:"concurrency,omitempty"`
 }
 
 // healthHandler handles basic gateway health check
-func healthHandler(c *gin.Context) {
-	health := GatewayHealth{
-		Status:    "healthy",
-		Service:   "gateway",
-		Version:   "1.0.0",
-		Timestamp: time.Now().UTC(),
-	}
+func healthHandler(switchStore *switches.Store, limits *middleware.ConcurrencyLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		health := GatewayHealth{
+			Status:       "healthy",
+			Service:      "gateway",
+			Version:      "1.0.0",
+			Timestamp:    time.Now().UTC(),
+			Maintenance:  switchStore.Maintenance(),
+			KillSwitches: switchStore.Active(""),
+			Concurrency:  limits.Stats(),
+		}
 
-	c.JSON(http.StatusOK, health)
+		c.JSON(http.StatusOK, health)
+	}
 }
 
 // servicesHealthHandler checks health of all downstream services
-func servicesHealthHandler(c *gin.Context) {
-	requestID := requestid.Get(c)
-	proxyConfig := getProxyConfig()
-
-	logger.WithField("request_id", requestID).Info("Checking health of all services")
-
-	// List of services to check
-	services := []ServiceConfig{
-		proxyConfig.UserService,
-		proxyConfig.ChatService,
-		proxyConfig.TaskService,
-		proxyConfig.CalendarService,
-		proxyConfig.PollService,
-		proxyConfig.NotificationService,
-		// Add file and analytics services when they're implemented
-		// proxyConfig.FileService,
-		// proxyConfig.AnalyticsService,
-	}
+func servicesHealthHandler(switchStore *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+		proxyConfig := getProxyConfig()
+
+		logger.WithField("request_id", requestID).Info("Checking health of all services")
+
+		// List of services to check
+		services := []ServiceConfig{
+			proxyConfig.UserService,
+			proxyConfig.ChatService,
+			proxyConfig.TaskService,
+			proxyConfig.CalendarService,
+			proxyConfig.PollService,
+			proxyConfig.NotificationService,
+			// Add file and analytics services when they're implemented
+			// proxyConfig.FileService,
+			// proxyConfig.AnalyticsService,
+		}
 
-	// Check health of each service
-	serviceHealths := make([]ServiceHealth, len(services))
-	healthyCount := 0
+		// Check health of each service
+		serviceHealths := make([]ServiceHealth, len(services))
+		healthyCount := 0
 
-	for i, service := range services {
-		serviceHealths[i] = checkServiceHealth(service)
-		if serviceHealths[i].Status == "healthy" {
-			healthyCount++
+		for i, service := range services {
+			serviceHealths[i] = checkServiceHealth(service)
+			serviceHealths[i].Endpoints = serviceRegistry.Endpoints(service.Name)
+			if serviceHealths[i].Status == "healthy" {
+				healthyCount++
+			}
 		}
-	}
 
-	// Determine overall status
-	var overallStatus string
-	if healthyCount == len(services) {
-		overallStatus = "healthy"
-	} else if healthyCount > 0 {
-		overallStatus = "degraded"
-	} else {
-		overallStatus = "unhealthy"
-	}
+		// Determine overall status
+		var overallStatus string
+		if healthyCount == len(services) {
+			overallStatus = "healthy"
+		} else if healthyCount > 0 {
+			overallStatus = "degraded"
+		} else {
+			overallStatus = "unhealthy"
+		}
 
-	health := GatewayHealth{
-		Status:    overallStatus,
-		Service:   "gateway",
-		Version:   "1.0.0",
-		Timestamp: time.Now().UTC(),
-		Services:  serviceHealths,
-	}
+		health := GatewayHealth{
+			Status:       overallStatus,
+			Service:      "gateway",
+			Version:      "1.0.0",
+			Timestamp:    time.Now().UTC(),
+			Services:     serviceHealths,
+			Maintenance:  switchStore.Maintenance(),
+			KillSwitches: switchStore.Active(""),
+		}
 
-	// Return appropriate HTTP status
-	var httpStatus int
-	switch overallStatus {
-	case "healthy":
-		httpStatus = http.StatusOK
-	case "degraded":
-		httpStatus = http.StatusPartialContent // 206
-	case "unhealthy":
-		httpStatus = http.StatusServiceUnavailable // 503
-	default:
-		httpStatus = http.StatusInternalServerError
-	}
+		// Return appropriate HTTP status
+		var httpStatus int
+		switch overallStatus {
+		case "healthy":
+			httpStatus = http.StatusOK
+		case "degraded":
+			httpStatus = http.StatusPartialContent // 206
+		case "unhealthy":
+			httpStatus = http.StatusServiceUnavailable // 503
+		default:
+			httpStatus = http.StatusInternalServerError
+		}
 
-	logger.WithFields(map[string]interface{}{
-		"request_id":     requestID,
-		"overall_status": overallStatus,
-		"healthy_count":  healthyCount,
-		"total_services": len(services),
-	}).Info("Service health check completed")
+		logger.WithFields(map[string]interface{}{
+			"request_id":     requestID,
+			"overall_status": overallStatus,
+			"healthy_count":  healthyCount,
+			"total_services": len(services),
+		}).Info("Service health check completed")
 
-	c.JSON(httpStatus, health)
+		c.JSON(httpStatus, health)
+	}
 }
 
 // checkServiceHealth checks the health of a specific service
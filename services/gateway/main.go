@@ -13,6 +13,9 @@ import (
 	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/quota"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/switches"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,19 +36,59 @@ func main() {
 
 	log.Info("Starting Gateway service...")
 
+	// Resolve downstream services through the registry, endpoints are refreshed in the background
+	serviceRegistry, err = config.NewRegistryFromEnv(defaultServiceURLs)
+	if err != nil {
+		log.Fatalf("Failed to set up service registry: %v", err)
+	}
+	http.DefaultTransport = serviceRegistry.Transport(http.DefaultTransport)
+	registryCtx, stopRegistry := context.WithCancel(context.Background())
+	defer stopRegistry()
+	go serviceRegistry.Run(registryCtx)
+	log.Infof("Service registry backend: %s", serviceRegistry.Backend())
+
 	// Set Gin mode based on environment
 	if os.Getenv("ENVIRONMENT") == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Connect to Redis (optional, stores maintenance mode and kill switches)
+	redisClient, err := redis.ConnectRedis(redis.DefaultConfig(cfg.Redis.URL))
+	if err != nil {
+		log.Warnf("Failed to connect to Redis, maintenance mode and kill switches are unavailable: %v", err)
+	} else {
+		defer redisClient.Close()
+		log.Info("Redis connected successfully")
+	}
+	switchStore := switches.NewStore(redisClient, 0)
+
+	// Quotas of expensive endpoints are enforced by services, overrides are managed here
+	quotas := quota.NewFromEnv(redisClient)
+
+	// Network restrictions of admin endpoints
+	adminAccess, err := middleware.LoadAdminAccessConfig()
+	if err != nil {
+		log.Fatalf("Failed to load admin access config: %v", err)
+	}
+
+	// Public API for external partners, authenticated with API keys issued by the user service
+	publicAPI := newPublicAPI(getProxyConfig().UserService.URL, redisClient, middleware.DefaultJWTConfig(cfg.JWT.Secret))
+	usageCtx, stopUsageReporter := context.WithCancel(context.Background())
+	usageReporterDone := make(chan struct{})
+	go func() {
+		publicAPI.runUsageReporter(usageCtx)
+		close(usageReporterDone)
+	}()
+
 	// Create Gin router
 	router := gin.New()
 
 	// Setup common middleware
 	middleware.SetupCommonMiddleware(router)
+	router.Use(middleware.BodyLimitMiddleware(middleware.DefaultBodyLimitConfig(uploadPaths...)))
 
 	// Setup routes
-	setupRoutes(router, cfg)
+	setupRoutes(router, cfg, switchStore, quotas, adminAccess, publicAPI)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -76,23 +119,39 @@ func main() {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Report usage metered since the last report
+	stopUsageReporter()
+	<-usageReporterDone
+
 	log.Info("Gateway server stopped")
 }
 
 // setupRoutes configures all routes for the gateway
-func setupRoutes(router *gin.Engine, cfg *config.Config) {
+func setupRoutes(router *gin.Engine, cfg *config.Config, switchStore *switches.Store, quotas *quota.Quotas, adminAccess *middleware.AdminAccessConfig, publicAPI *publicAPI) {
 	// Get proxy configuration
 	proxyConfig := getProxyConfig()
+	jwtConfig := middleware.DefaultJWTConfig(cfg.JWT.Secret)
+
+	// Reject non-admin traffic while maintenance mode is on
+	router.Use(maintenanceMiddleware(switchStore, jwtConfig))
+
+	// Concurrency limits per downstream service, so a burst to one service doesn't take
+	// the gateway down with it. Requests over a limit are shed with 503.
+	limits := middleware.NewConcurrencyLimits("gateway")
 
 	// Health check endpoints
-	router.GET("/health", healthHandler)
-	router.GET("/health/services", servicesHealthHandler)
+	router.GET("/health", healthHandler(switchStore, limits))
+	router.GET("/metrics", limits.MetricsHandler())
+	router.GET("/health/services", servicesHealthHandler(switchStore))
 	router.GET("/health/ready", readinessHandler)
 	router.GET("/health/live", livenessHandler)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
+		// Aggregated unread counters for the app badge
+		v1.GET("/badge", limits.Group("badge"), badgeHandler) // GET /api/v1/badge
+
 		// Authentication routes (placeholder for now)
 		auth := v1.Group("/auth")
 		{
@@ -104,44 +163,91 @@ func setupRoutes(router *gin.Engine, cfg *config.Config) {
 
 		// User routes - proxy to user service
 		users := v1.Group("/users")
+		users.Use(limits.Group("user-service"))
 		{
 			users.Any("/*path", proxyRequest(proxyConfig.UserService.URL, proxyConfig.UserService.Name))
 		}
 
+		// Skill directory - proxy to user service
+		skills := v1.Group("/skills")
+		skills.Use(limits.Group("user-service"))
+		{
+			skills.Any("/*path", proxyRequest(proxyConfig.UserService.URL, proxyConfig.UserService.Name))
+		}
+
 		// Chat routes - proxy to chat service
 		chats := v1.Group("/chats")
+		chats.Use(limits.Group("chat-service"))
 		{
 			chats.Any("/*path", proxyRequest(proxyConfig.ChatService.URL, proxyConfig.ChatService.Name))
 		}
 
+		// Presence routes - proxy to chat service
+		presence := v1.Group("/presence")
+		presence.Use(limits.Group("chat-service"))
+		{
+			presence.Any("/*path", proxyRequest(proxyConfig.ChatService.URL, proxyConfig.ChatService.Name))
+		}
+
 		// Task routes - proxy to task service
 		tasks := v1.Group("/tasks")
+		tasks.Use(limits.Group("task-service"))
 		{
 			tasks.Any("/*path", proxyRequest(proxyConfig.TaskService.URL, proxyConfig.TaskService.Name))
 		}
 
+		// Sprint routes - proxy to task service
+		sprints := v1.Group("/sprints")
+		sprints.Use(limits.Group("task-service"))
+		{
+			sprints.Any("/*path", proxyRequest(proxyConfig.TaskService.URL, proxyConfig.TaskService.Name))
+		}
+
 		// Calendar routes - proxy to calendar service
 		calendar := v1.Group("/calendar")
+		calendar.Use(limits.Group("calendar-service"))
 		{
 			calendar.Any("/*path", proxyRequest(proxyConfig.CalendarService.URL, proxyConfig.CalendarService.Name))
 		}
 
 		// Poll routes - proxy to poll service
 		polls := v1.Group("/polls")
+		polls.Use(limits.Group("poll-service"))
 		{
 			polls.Any("/*path", proxyRequest(proxyConfig.PollService.URL, proxyConfig.PollService.Name))
 		}
 
 		// Notification routes - proxy to notification service
 		notifications := v1.Group("/notifications")
+		notifications.Use(unlessPath("/stream", limits.Group("notification-service")))
 		{
 			notifications.Any("/*path", proxyRequest(proxyConfig.NotificationService.URL, proxyConfig.NotificationService.Name))
 		}
 
+		// Undo of staged destructive actions - proxy to the service that issued the token
+		v1.POST("/undo/:token", undoHandler(proxyConfig)) // POST /api/v1/undo/:token
+
+		// Email bounce and complaint webhooks - proxy to notification service
+		emailWebhooks := v1.Group("/webhooks/email")
+		emailWebhooks.Use(limits.Group("notification-service"))
+		{
+			emailWebhooks.POST("/*path", proxyRequest(proxyConfig.NotificationService.URL, proxyConfig.NotificationService.Name))
+		}
+
+		// Public API for external partners (API key in the X-API-Key header, rate limited per key)
+		public := v1.Group("/public")
+		public.Use(limits.Group("public-api"))
+		{
+			public.POST("/tasks", publicAPI.authorize(scopeTasksWrite, "create_task"),
+				rewritePath("/api/v1/tasks"), proxyRequest(proxyConfig.TaskService.URL, proxyConfig.TaskService.Name)) // POST /api/v1/public/tasks
+			public.POST("/notifications", publicAPI.authorize(scopeNotificationsSend, "send_notification"),
+				publicNotificationHandler(proxyConfig.NotificationService.URL)) // POST /api/v1/public/notifications
+		}
+
 		// File routes - proxy to file service (placeholder for now)
 		files := v1.Group("/files")
 		{
-			files.POST("/upload", placeholderHandler("upload file"))
+			files.POST("/upload", killSwitchGuard(switchStore, switches.DisableFileUploads), placeholderHandler("upload file"))
 			files.GET("/:id", placeholderHandler("get file"))
 			files.DELETE("/:id", placeholderHandler("delete file"))
 		}
@@ -152,12 +258,67 @@ func setupRoutes(router *gin.Engine, cfg *config.Config) {
 			analytics.GET("/dashboard", placeholderHandler("get dashboard"))
 			analytics.GET("/reports", placeholderHandler("get reports"))
 		}
+
+		// Maintenance mode, kill switches and quota overrides, admin endpoints of services (admin only)
+		admin := v1.Group("/admin")
+		admin.Use(middleware.AdminAccessMiddleware(adminAccess))
+		admin.Use(middleware.JWTMiddleware(jwtConfig))
+		admin.Use(middleware.RequireAdminRole())
+		{
+			admin.GET("/maintenance", getMaintenanceHandler(switchStore))                                                        // GET /api/v1/admin/maintenance
+			admin.PUT("/maintenance", middleware.LogAdminAction("enable_maintenance"), setMaintenanceHandler(switchStore))       // PUT /api/v1/admin/maintenance
+			admin.DELETE("/maintenance", middleware.LogAdminAction("disable_maintenance"), clearMaintenanceHandler(switchStore)) // DELETE /api/v1/admin/maintenance
+
+			admin.GET("/switches", getKillSwitchesHandler(switchStore))                                                                    // GET /api/v1/admin/switches
+			admin.PUT("/switches/:name", middleware.LogAdminAction("activate_kill_switch"), activateKillSwitchHandler(switchStore))        // PUT /api/v1/admin/switches/:name
+			admin.DELETE("/switches/:name", middleware.LogAdminAction("deactivate_kill_switch"), deactivateKillSwitchHandler(switchStore)) // DELETE /api/v1/admin/switches/:name
+
+			admin.GET("/quotas", getQuotasHandler(quotas))                                                                                     // GET /api/v1/admin/quotas
+			admin.PUT("/quotas/overrides/:user_id", middleware.LogAdminAction("set_quota_override"), setQuotaOverrideHandler(quotas))          // PUT /api/v1/admin/quotas/overrides/:user_id
+			admin.DELETE("/quotas/overrides/:user_id", middleware.LogAdminAction("delete_quota_override"), deleteQuotaOverrideHandler(quotas)) // DELETE /api/v1/admin/quotas/overrides/:user_id
+
+			// Notification management, worker, delivery and campaign admin - proxy to notification service
+			notificationAdmin := proxyRequest(proxyConfig.NotificationService.URL, proxyConfig.NotificationService.Name)
+			for _, prefix := range notificationAdminPrefixes {
+				admin.Any(prefix, notificationAdmin)
+				admin.Any(prefix+"/*path", notificationAdmin)
+			}
+			admin.GET("/stats", notificationAdmin)                   // GET /api/v1/admin/stats
+			admin.GET("/users/:id/notifications", notificationAdmin) // GET /api/v1/admin/users/:id/notifications
+
+			// Custom slash commands and membership audits - proxy to chat service
+			chatAdmin := proxyRequest(proxyConfig.ChatService.URL, proxyConfig.ChatService.Name)
+			admin.Any("/commands", chatAdmin)                    // /api/v1/admin/commands
+			admin.Any("/commands/*path", chatAdmin)              // /api/v1/admin/commands/:id
+			admin.GET("/chats/:id/membership-events", chatAdmin) // GET /api/v1/admin/chats/:id/membership-events
+			admin.GET("/users/:id/membership-events", chatAdmin) // GET /api/v1/admin/users/:id/membership-events
+			admin.GET("/messages/:id/audience", chatAdmin)       // GET /api/v1/admin/messages/:id/audience
+
+			// Background jobs exist in every service, so /admin/jobs is called on the service directly
+		}
 	}
 
-	// WebSocket endpoint - proxy to chat service for real-time communication
+	// WebSocket endpoint - proxy to chat service for real-time communication.
+	// Connections are long-lived, so they are not counted against concurrency limits
 	router.GET("/ws", proxyRequest(proxyConfig.ChatService.URL, proxyConfig.ChatService.Name))
 }
 
+// notificationAdminPrefixes are admin route groups of the notification service under /api/v1/admin
+var notificationAdminPrefixes = []string{
+	"/notifications",
+	"/worker",
+	"/delivery-hold",
+	"/email-outbox",
+	"/email-suppressions",
+	"/notification-preferences",
+	"/notification-fallbacks",
+	"/template-experiments",
+	"/announcement-campaigns",
+}
+
+// uploadPaths are routes accepting large streamed request bodies
+var uploadPaths = []string{"/api/v1/files/upload", "/api/v1/tasks/import"}
+
 // placeholderHandler creates a placeholder handler for development
 func placeholderHandler(action string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,346 @@
+// File: services/gateway/maintenance.go
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/switches"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceExemptPaths stay available during maintenance: health checks and metrics for
+// orchestration and login so administrators can get a token
+var maintenanceExemptPaths = []string{
+	"/health",
+	"/metrics",
+	"/api/v1/auth/login",
+	"/api/v1/auth/refresh",
+}
+
+// MaintenanceRequest represents a request to turn maintenance mode on
+type MaintenanceRequest struct {
+	Message    string     `json:"message" binding:"omitempty,max=500"`
+	RetryAfter int        `json:"retry_after" binding:"omitempty,min=1,max=86400"` // Seconds, used when ends_at is not set
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+}
+
+// KillSwitchRequest represents a request to activate a kill switch
+type KillSwitchRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// KillSwitchStatus represents a known kill switch with its state
+type KillSwitchStatus struct {
+	switches.Definition
+	Active      bool       `json:"active"`
+	Reason      string     `json:"reason,omitempty"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+	ActivatedBy uint       `json:"activated_by,omitempty"`
+}
+
+// maintenanceMiddleware rejects non-admin traffic with 503 and retry hints while maintenance is active.
+// Administrators are recognized by a valid token, so they can check the system before reopening it.
+func maintenanceMiddleware(store *switches.Store, jwtConfig *middleware.JWTConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maintenance := store.Maintenance()
+		if maintenance == nil || isMaintenanceExempt(c.Request.URL.Path) || isAdminRequest(c, jwtConfig) {
+			c.Next()
+			return
+		}
+
+		retryAfter := maintenance.RetryAfterSeconds(time.Now())
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":       i18n.Message(c, "error.maintenance"),
+			"message":     maintenance.Message,
+			"maintenance": true,
+			"retry_after": retryAfter,
+			"ends_at":     maintenance.EndsAt,
+			"request_id":  requestid.Get(c),
+		})
+	}
+}
+
+// isMaintenanceExempt checks if a path stays available during maintenance
+func isMaintenanceExempt(path string) bool {
+	for _, exempt := range maintenanceExemptPaths {
+		if path == exempt || strings.HasPrefix(path, exempt+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdminRequest checks if the request carries a valid token of an administrator
+func isAdminRequest(c *gin.Context, jwtConfig *middleware.JWTConfig) bool {
+	tokenString, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+
+	claims, err := middleware.ValidateToken(tokenString, jwtConfig)
+	if err != nil {
+		return false
+	}
+	return claims.Role == models.RoleAdmin || claims.Role == models.RoleSuperAdmin
+}
+
+// killSwitchGuard rejects requests to a feature turned off by a kill switch
+func killSwitchGuard(store *switches.Store, name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !store.IsActive(name) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":       i18n.Message(c, "error.feature_disabled"),
+			"kill_switch": name,
+			"request_id":  requestid.Get(c),
+		})
+	}
+}
+
+// getMaintenanceHandler returns maintenance mode state
+// GET /api/v1/admin/maintenance
+func getMaintenanceHandler(store *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		maintenance := store.Maintenance()
+		c.JSON(http.StatusOK, gin.H{
+			"enabled":     maintenance != nil,
+			"maintenance": maintenance,
+			"request_id":  requestID,
+		})
+	}
+}
+
+// setMaintenanceHandler turns maintenance mode on
+// PUT /api/v1/admin/maintenance
+func setMaintenanceHandler(store *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		adminID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":      "Unauthorized",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		var req MaintenanceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      i18n.Message(c, "error.invalid_request_body"),
+				"details":    validation.Details(c, err),
+				"request_id": requestID,
+			})
+			return
+		}
+
+		now := time.Now().UTC()
+		if req.EndsAt != nil && !req.EndsAt.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "ends_at must be in the future",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		maintenance := &switches.Maintenance{
+			Enabled:    true,
+			Message:    strings.TrimSpace(req.Message),
+			RetryAfter: req.RetryAfter,
+			EndsAt:     req.EndsAt,
+			StartedAt:  now,
+			StartedBy:  adminID,
+		}
+		if err := store.SetMaintenance(maintenance); err != nil {
+			writeSwitchError(c, requestID, "Failed to enable maintenance mode", err)
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"ends_at":    req.EndsAt,
+		}).Warn("Maintenance mode enabled")
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Maintenance mode enabled",
+			"maintenance": maintenance,
+			"request_id":  requestID,
+		})
+	}
+}
+
+// clearMaintenanceHandler turns maintenance mode off
+// DELETE /api/v1/admin/maintenance
+func clearMaintenanceHandler(store *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		if err := store.ClearMaintenance(); err != nil {
+			writeSwitchError(c, requestID, "Failed to disable maintenance mode", err)
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+		}).Warn("Maintenance mode disabled")
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Maintenance mode disabled",
+			"request_id": requestID,
+		})
+	}
+}
+
+// getKillSwitchesHandler returns all known kill switches with their state
+// GET /api/v1/admin/switches
+func getKillSwitchesHandler(store *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		active := make(map[string]*switches.KillSwitch)
+		for _, killSwitch := range store.Active("") {
+			active[killSwitch.Name] = killSwitch
+		}
+
+		definitions := switches.Definitions()
+		statuses := make([]KillSwitchStatus, 0, len(definitions))
+		for _, definition := range definitions {
+			status := KillSwitchStatus{Definition: definition}
+			if killSwitch, ok := active[definition.Name]; ok {
+				activatedAt := killSwitch.ActivatedAt
+				status.Active = true
+				status.Reason = killSwitch.Reason
+				status.ActivatedAt = &activatedAt
+				status.ActivatedBy = killSwitch.ActivatedBy
+			}
+			statuses = append(statuses, status)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"switches":   statuses,
+			"request_id": requestID,
+		})
+	}
+}
+
+// activateKillSwitchHandler turns a kill switch on
+// PUT /api/v1/admin/switches/:name
+func activateKillSwitchHandler(store *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+		name := c.Param("name")
+
+		adminID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":      "Unauthorized",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		var req KillSwitchRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":      i18n.Message(c, "error.invalid_request_body"),
+					"details":    validation.Details(c, err),
+					"request_id": requestID,
+				})
+				return
+			}
+		}
+
+		killSwitch, err := store.Activate(name, strings.TrimSpace(req.Reason), adminID)
+		if err != nil {
+			writeSwitchError(c, requestID, "Failed to activate kill switch", err)
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"admin_id":    adminID,
+			"kill_switch": name,
+			"reason":      killSwitch.Reason,
+		}).Warn("Kill switch activated")
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Kill switch activated",
+			"kill_switch": killSwitch,
+			"request_id":  requestID,
+		})
+	}
+}
+
+// deactivateKillSwitchHandler turns a kill switch off
+// DELETE /api/v1/admin/switches/:name
+func deactivateKillSwitchHandler(store *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+		name := c.Param("name")
+
+		if err := store.Deactivate(name); err != nil {
+			writeSwitchError(c, requestID, "Failed to deactivate kill switch", err)
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"admin_id":    adminID,
+			"kill_switch": name,
+		}).Warn("Kill switch deactivated")
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Kill switch deactivated",
+			"request_id": requestID,
+		})
+	}
+}
+
+// writeSwitchError maps switch store errors to HTTP responses
+func writeSwitchError(c *gin.Context, requestID, message string, err error) {
+	switch {
+	case errors.Is(err, switches.ErrUnknownSwitch):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":      "Kill switch not found",
+			"request_id": requestID,
+		})
+	case errors.Is(err, switches.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":      "Switches require Redis, which is not connected",
+			"request_id": requestID,
+		})
+	default:
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error(message)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      message,
+			"request_id": requestID,
+		})
+	}
+}
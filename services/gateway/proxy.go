@@ -2,16 +2,16 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
+	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -35,50 +35,42 @@ type ProxyConfig struct {
 	AnalyticsService    ServiceConfig
 }
 
-// getProxyConfig returns service URLs configuration
+// serviceRegistry resolves downstream services, set up in main
+var serviceRegistry *config.Registry
+
+// defaultServiceURLs are used for services without a URL in the environment
+var defaultServiceURLs = map[string]string{
+	config.UserService:         "http://localhost:8081",
+	config.ChatService:         "http://localhost:8082",
+	config.TaskService:         "http://localhost:8083",
+	config.CalendarService:     "http://localhost:8084",
+	config.PollService:         "http://localhost:8085",
+	config.NotificationService: "http://localhost:8087",
+	config.FileService:         "http://localhost:8088",
+	config.AnalyticsService:    "http://localhost:8086",
+}
+
+// getProxyConfig returns service URLs configuration. URLs are logical service URLs
+// resolved to a healthy endpoint by the registry transport on every request.
 func getProxyConfig() *ProxyConfig {
 	return &ProxyConfig{
-		UserService: ServiceConfig{
-			Name: "user-service",
-			URL:  getEnvOrDefault("USER_SERVICE_URL", "http://localhost:8081"),
-		},
-		ChatService: ServiceConfig{
-			Name: "chat-service",
-			URL:  getEnvOrDefault("CHAT_SERVICE_URL", "http://localhost:8082"),
-		},
-		TaskService: ServiceConfig{
-			Name: "task-service",
-			URL:  getEnvOrDefault("TASK_SERVICE_URL", "http://localhost:8083"),
-		},
-		CalendarService: ServiceConfig{
-			Name: "calendar-service",
-			URL:  getEnvOrDefault("CALENDAR_SERVICE_URL", "http://localhost:8084"),
-		},
-		PollService: ServiceConfig{
-			Name: "poll-service",
-			URL:  getEnvOrDefault("POLL_SERVICE_URL", "http://localhost:8085"),
-		},
-		NotificationService: ServiceConfig{
-			Name: "notification-service",
-			URL:  getEnvOrDefault("NOTIFICATION_SERVICE_URL", "http://localhost:8087"),
-		},
-		FileService: ServiceConfig{
-			Name: "file-service",
-			URL:  getEnvOrDefault("FILE_SERVICE_URL", "http://localhost:8088"),
-		},
-		AnalyticsService: ServiceConfig{
-			Name: "analytics-service",
-			URL:  getEnvOrDefault("ANALYTICS_SERVICE_URL", "http://localhost:8086"),
-		},
+		UserService:         serviceConfig(config.UserService),
+		ChatService:         serviceConfig(config.ChatService),
+		TaskService:         serviceConfig(config.TaskService),
+		CalendarService:     serviceConfig(config.CalendarService),
+		PollService:         serviceConfig(config.PollService),
+		NotificationService: serviceConfig(config.NotificationService),
+		FileService:         serviceConfig(config.FileService),
+		AnalyticsService:    serviceConfig(config.AnalyticsService),
 	}
 }
 
-// getEnvOrDefault returns environment variable value or default
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// serviceConfig returns configuration of a service from the registry
+func serviceConfig(name string) ServiceConfig {
+	return ServiceConfig{
+		Name: name,
+		URL:  serviceRegistry.BaseURL(name),
 	}
-	return defaultValue
 }
 
 // proxyRequest handles proxying HTTP requests to microservices
@@ -112,27 +104,8 @@ func proxyRequest(targetURL, serviceName string) gin.HandlerFunc {
 			RawQuery: c.Request.URL.RawQuery,
 		}
 
-		// Read request body
-		var bodyBytes []byte
-		if c.Request.Body != nil {
-			bodyBytes, err = io.ReadAll(c.Request.Body)
-			if err != nil {
-				logger.WithFields(map[string]interface{}{
-					"request_id": requestID,
-					"service":    serviceName,
-					"error":      err.Error(),
-				}).Error("Failed to read request body")
-
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error":      "Failed to read request body",
-					"request_id": requestID,
-				})
-				return
-			}
-		}
-
-		// Create proxy request
-		proxyReq, err := http.NewRequest(c.Request.Method, proxyURL.String(), bytes.NewReader(bodyBytes))
+		// Create proxy request, the body is streamed to the service without buffering
+		proxyReq, err := http.NewRequest(c.Request.Method, proxyURL.String(), c.Request.Body)
 		if err != nil {
 			logger.WithFields(map[string]interface{}{
 				"request_id": requestID,
@@ -148,16 +121,19 @@ func proxyRequest(targetURL, serviceName string) gin.HandlerFunc {
 			return
 		}
 
+		proxyReq.ContentLength = c.Request.ContentLength
+
 		// Copy headers from original request
 		copyHeaders(c.Request.Header, proxyReq.Header)
 
-		// Add request ID to forwarded request
+		// Add request ID and correlation to forwarded request
 		proxyReq.Header.Set("X-Request-ID", requestID)
+		logger.CorrelationFromContext(c.Request.Context()).SetHeaders(proxyReq.Header)
 		proxyReq.Header.Set("X-Forwarded-For", c.ClientIP())
 		proxyReq.Header.Set("X-Forwarded-Proto", c.Request.Header.Get("X-Forwarded-Proto"))
 
 		// Log proxy request
-		logger.WithFields(map[string]interface{}{
+		logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
 			"request_id": requestID,
 			"service":    serviceName,
 			"method":     c.Request.Method,
@@ -171,10 +147,21 @@ func proxyRequest(targetURL, serviceName string) gin.HandlerFunc {
 			Timeout: 30 * time.Second,
 		}
 
+		// Event streams stay open while the client listens, they end with the client's request
+		streaming := strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+		if streaming {
+			proxyReq = proxyReq.WithContext(c.Request.Context())
+			client.Timeout = 0
+		}
+
 		resp, err := client.Do(proxyReq)
+		if err != nil && middleware.IsBodyTooLarge(err) {
+			middleware.AbortBodyTooLarge(c)
+			return
+		}
 		if err != nil {
 			duration := time.Since(startTime)
-			logger.WithFields(map[string]interface{}{
+			logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
 				"request_id": requestID,
 				"service":    serviceName,
 				"error":      err.Error(),
@@ -189,11 +176,16 @@ func proxyRequest(targetURL, serviceName string) gin.HandlerFunc {
 		}
 		defer resp.Body.Close()
 
+		if streaming && strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+			streamResponse(c, resp)
+			return
+		}
+
 		// Read response body
 		respBody, err := io.ReadAll(resp.Body)
 		if err != nil {
 			duration := time.Since(startTime)
-			logger.WithFields(map[string]interface{}{
+			logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
 				"request_id": requestID,
 				"service":    serviceName,
 				"error":      err.Error(),
@@ -212,7 +204,7 @@ func proxyRequest(targetURL, serviceName string) gin.HandlerFunc {
 
 		// Log successful proxy response
 		duration := time.Since(startTime)
-		logger.WithFields(map[string]interface{}{
+		logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
 			"request_id":    requestID,
 			"service":       serviceName,
 			"status_code":   resp.StatusCode,
@@ -225,6 +217,38 @@ func proxyRequest(targetURL, serviceName string) gin.HandlerFunc {
 	}
 }
 
+// streamResponse copies an event stream to the client as it arrives instead of buffering it
+func streamResponse(c *gin.Context, resp *http.Response) {
+	copyHeaders(resp.Header, c.Writer.Header())
+	c.Status(resp.StatusCode)
+	c.Writer.Flush()
+
+	buffer := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(buffer)
+		if n > 0 {
+			if _, writeErr := c.Writer.Write(buffer[:n]); writeErr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// unlessPath runs handler for requests of a catch-all route except the one to path,
+// e.g. to keep long-lived streams out of concurrency limits
+func unlessPath(path string, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param("path") == path {
+			return
+		}
+		handler(c)
+	}
+}
+
 // copyHeaders copies headers from source to destination
 func copyHeaders(src, dst http.Header) {
 	for key, values := range src {
@@ -0,0 +1,583 @@
+// File: services/gateway/publicapi.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// API key scopes, issued by the user service
+const (
+	scopeTasksWrite        = "tasks:write"
+	scopeNotificationsSend = "notifications:send"
+)
+
+const (
+	apiKeyHeader          = "X-API-Key"
+	apiKeyCacheTTL        = time.Minute // Revoked keys stop working at most this long after revocation
+	apiKeyCacheMaxEntries = 10000
+	apiKeyTokenTTL        = time.Minute // Lifetime of tokens minted for proxied public API requests
+	usageFlushInterval    = time.Minute
+	rateLimitKeyPrefix    = "gateway:ratelimit:"
+	maxPublicUpstreamBody = 64 << 10 // Responses of services passed to partners are cut to this size
+)
+
+// apiKeyIdentity describes a valid API key and the user its requests act as
+type apiKeyIdentity struct {
+	KeyID     uint        `json:"key_id"`
+	Name      string      `json:"name"`
+	Scopes    []string    `json:"scopes"`
+	RateLimit int         `json:"rate_limit"`
+	UserID    uint        `json:"user_id"`
+	Email     string      `json:"email"`
+	Role      models.Role `json:"role"`
+	Locale    string      `json:"locale"`
+}
+
+// hasScope checks if the key grants scope
+func (i *apiKeyIdentity) hasScope(scope string) bool {
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicNotificationRequest represents a notification sent by a partner through the public API
+type PublicNotificationRequest struct {
+	UserID    uint   `json:"user_id" binding:"required,min=1"`
+	Title     string `json:"title" binding:"required,min=1,max=255"`
+	Message   string `json:"message,omitempty" binding:"omitempty,max=2000"`
+	Priority  string `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`
+	ActionURL string `json:"action_url,omitempty" binding:"omitempty,url,max=500"`
+}
+
+// publicAPI authenticates partner requests with API keys, applies per-key rate limits
+// and meters usage. Keys and usage are stored by the user service.
+type publicAPI struct {
+	userServiceURL string
+	client         *http.Client
+	jwtConfig      *middleware.JWTConfig
+	limiter        *rateLimiter
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedAPIKey
+
+	usageMu sync.Mutex
+	usage   map[usageKey]*usageCounts
+}
+
+// cachedAPIKey is a verification result, identity is nil for rejected keys
+type cachedAPIKey struct {
+	identity  *apiKeyIdentity
+	status    int
+	expiresAt time.Time
+}
+
+// usageKey identifies a usage counter reported to the user service
+type usageKey struct {
+	KeyID    uint
+	Month    string
+	Endpoint string
+}
+
+// usageCounts holds metered requests since the last report
+type usageCounts struct {
+	Requests  int64
+	Throttled int64
+	Errors    int64
+}
+
+// newPublicAPI creates the public API of the gateway. Rate limits are shared through
+// Redis when it is available and kept per gateway instance otherwise.
+func newPublicAPI(userServiceURL string, redisClient *redis.Client, jwtConfig *middleware.JWTConfig) *publicAPI {
+	return &publicAPI{
+		userServiceURL: strings.TrimRight(userServiceURL, "/"),
+		client:         &http.Client{Timeout: 5 * time.Second},
+		jwtConfig:      jwtConfig,
+		limiter:        newRateLimiter(redisClient),
+		cache:          make(map[string]cachedAPIKey),
+		usage:          make(map[usageKey]*usageCounts),
+	}
+}
+
+// authorize authenticates the API key of a request, checks its scope and rate limit, and
+// replaces the key with a short-lived token of the key's user for the target service
+func (p *publicAPI) authorize(scope, endpoint string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		key := strings.TrimSpace(c.GetHeader(apiKeyHeader))
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":      "API key is required in the " + apiKeyHeader + " header",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		identity, status := p.verify(key)
+		if identity == nil {
+			errorMessage := "Invalid API key"
+			if status == http.StatusServiceUnavailable {
+				errorMessage = "API key verification is unavailable"
+			}
+			c.AbortWithStatusJSON(status, gin.H{
+				"error":      errorMessage,
+				"request_id": requestID,
+			})
+			return
+		}
+
+		if !identity.hasScope(scope) {
+			p.record(identity.KeyID, endpoint, http.StatusForbidden, false)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":      fmt.Sprintf("API key does not have the %s scope", scope),
+				"request_id": requestID,
+			})
+			return
+		}
+
+		allowed, remaining, retryAfter := p.limiter.allow(identity.KeyID, identity.RateLimit, time.Now())
+		c.Header("X-RateLimit-Limit", strconv.Itoa(identity.RateLimit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !allowed {
+			p.record(identity.KeyID, endpoint, http.StatusTooManyRequests, true)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Rate limit exceeded",
+				"retry_after": retryAfter,
+				"request_id":  requestID,
+			})
+			return
+		}
+
+		token, err := middleware.GenerateAccessToken(identity.UserID, identity.Email, identity.Role, identity.Locale, apiKeyTokenTTL, p.jwtConfig)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"key_id":     identity.KeyID,
+				"error":      err.Error(),
+			}).Error("Failed to generate token for API key")
+
+			p.record(identity.KeyID, endpoint, http.StatusInternalServerError, false)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":      "Failed to authorize request",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		c.Request.Header.Del(apiKeyHeader)
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+		c.Set("api_key_id", identity.KeyID)
+		c.Set("user_id", identity.UserID)
+
+		c.Next()
+
+		p.record(identity.KeyID, endpoint, c.Writer.Status(), false)
+	}
+}
+
+// verify checks an API key with the user service. Results, rejections included, are cached
+// for apiKeyCacheTTL. It returns the HTTP status to answer with when the key is not accepted.
+func (p *publicAPI) verify(key string) (*apiKeyIdentity, int) {
+	sum := sha256.Sum256([]byte(key))
+	cacheKey := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	p.cacheMu.Lock()
+	cached, ok := p.cache[cacheKey]
+	p.cacheMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.identity, cached.status
+	}
+
+	identity, status, err := p.requestVerification(key)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"error": err.Error(),
+		}).Error("Failed to verify API key")
+		return nil, http.StatusServiceUnavailable
+	}
+
+	p.cacheMu.Lock()
+	if len(p.cache) >= apiKeyCacheMaxEntries {
+		p.cache = make(map[string]cachedAPIKey)
+	}
+	p.cache[cacheKey] = cachedAPIKey{identity: identity, status: status, expiresAt: now.Add(apiKeyCacheTTL)}
+	p.cacheMu.Unlock()
+
+	return identity, status
+}
+
+// requestVerification asks the user service about an API key
+func (p *publicAPI) requestVerification(key string) (*apiKeyIdentity, int, error) {
+	body, err := json.Marshal(map[string]string{"key": key})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode API key verification: %w", err)
+	}
+
+	resp, err := p.client.Post(p.userServiceURL+"/api/v1/internal/api-keys/verify", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to request API key verification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusBadRequest:
+		return nil, http.StatusUnauthorized, nil
+	case http.StatusForbidden:
+		return nil, http.StatusForbidden, nil
+	default:
+		return nil, 0, fmt.Errorf("user service responded with status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Identity *apiKeyIdentity `json:"identity"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode API key verification: %w", err)
+	}
+	if result.Identity == nil {
+		return nil, 0, fmt.Errorf("user service returned no API key identity")
+	}
+	return result.Identity, http.StatusOK, nil
+}
+
+// record meters a public API request of a key
+func (p *publicAPI) record(keyID uint, endpoint string, status int, throttled bool) {
+	key := usageKey{KeyID: keyID, Month: time.Now().UTC().Format("2006-01"), Endpoint: endpoint}
+
+	p.usageMu.Lock()
+	defer p.usageMu.Unlock()
+
+	counts, ok := p.usage[key]
+	if !ok {
+		counts = &usageCounts{}
+		p.usage[key] = counts
+	}
+	switch {
+	case throttled:
+		counts.Throttled++
+	case status >= http.StatusBadRequest:
+		counts.Requests++
+		counts.Errors++
+	default:
+		counts.Requests++
+	}
+}
+
+// runUsageReporter reports metered usage to the user service every usageFlushInterval
+// until ctx is done, then reports what is left
+func (p *publicAPI) runUsageReporter(ctx context.Context) {
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.flushUsage()
+			return
+		case <-ticker.C:
+			p.flushUsage()
+		}
+	}
+}
+
+// flushUsage sends metered usage to the user service. Counters are kept for the next
+// report if the user service cannot be reached.
+func (p *publicAPI) flushUsage() {
+	p.usageMu.Lock()
+	pending := p.usage
+	p.usage = make(map[usageKey]*usageCounts)
+	p.usageMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	entries := make([]map[string]interface{}, 0, len(pending))
+	for key, counts := range pending {
+		entries = append(entries, map[string]interface{}{
+			"key_id":    key.KeyID,
+			"month":     key.Month,
+			"endpoint":  key.Endpoint,
+			"requests":  counts.Requests,
+			"throttled": counts.Throttled,
+			"errors":    counts.Errors,
+		})
+	}
+
+	if err := p.sendUsage(entries); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"entries": len(entries),
+			"error":   err.Error(),
+		}).Error("Failed to report API key usage")
+
+		p.usageMu.Lock()
+		for key, counts := range pending {
+			current, ok := p.usage[key]
+			if !ok {
+				p.usage[key] = counts
+				continue
+			}
+			current.Requests += counts.Requests
+			current.Throttled += counts.Throttled
+			current.Errors += counts.Errors
+		}
+		p.usageMu.Unlock()
+	}
+}
+
+// sendUsage posts usage counters to the user service
+func (p *publicAPI) sendUsage(entries []map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"entries": entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode API key usage: %w", err)
+	}
+
+	resp, err := p.client.Post(p.userServiceURL+"/api/v1/internal/api-keys/usage", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send API key usage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("user service responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// rateLimiter counts requests per API key in fixed one-minute windows
+type rateLimiter struct {
+	redisClient *redis.Client
+
+	mu      sync.Mutex
+	windows map[uint]*rateWindow
+}
+
+// rateWindow is the local request count of a key in the current minute
+type rateWindow struct {
+	minute int64
+	count  int
+}
+
+// newRateLimiter creates a rate limiter, counting in Redis when redisClient is not nil
+func newRateLimiter(redisClient *redis.Client) *rateLimiter {
+	return &rateLimiter{
+		redisClient: redisClient,
+		windows:     make(map[uint]*rateWindow),
+	}
+}
+
+// allow counts a request of a key and checks it against limit requests per minute.
+// It returns the requests left in the window and the seconds until the window resets.
+func (l *rateLimiter) allow(keyID uint, limit int, now time.Time) (bool, int, int) {
+	minute := now.Unix() / 60
+	retryAfter := int(60 - now.Unix()%60)
+
+	var count int
+	if l.redisClient != nil {
+		var err error
+		if count, err = l.countRedis(keyID, minute); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"key_id": keyID,
+				"error":  err.Error(),
+			}).Warn("Failed to count request in Redis, using local rate limit")
+			count = l.countLocal(keyID, minute)
+		}
+	} else {
+		count = l.countLocal(keyID, minute)
+	}
+
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return count <= limit, remaining, retryAfter
+}
+
+// countRedis increments the shared request count of a key in a minute
+func (l *rateLimiter) countRedis(keyID uint, minute int64) (int, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("%s%d:%d", rateLimitKeyPrefix, keyID, minute)
+	count, err := l.redisClient.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := l.redisClient.Client.Expire(ctx, key, 2*time.Minute).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return int(count), nil
+}
+
+// countLocal increments the request count of a key in a minute on this gateway instance
+func (l *rateLimiter) countLocal(keyID uint, minute int64) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window, ok := l.windows[keyID]
+	if !ok || window.minute != minute {
+		if len(l.windows) >= apiKeyCacheMaxEntries {
+			l.windows = make(map[uint]*rateWindow)
+		}
+		window = &rateWindow{minute: minute}
+		l.windows[keyID] = window
+	}
+	window.count++
+	return window.count
+}
+
+// rewritePath sets the path a public API request is proxied to
+func rewritePath(path string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.URL.Path = path
+		c.Next()
+	}
+}
+
+// publicNotificationHandler queues a notification sent through the public API in the notification service
+func publicNotificationHandler(notificationServiceURL string) gin.HandlerFunc {
+	client := &http.Client{Timeout: 10 * time.Second, Transport: logger.NewTransport(nil)}
+	taskURL := strings.TrimRight(notificationServiceURL, "/") + "/api/v1/internal/notifications/task"
+
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		var req PublicNotificationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      i18n.Message(c, "error.invalid_request_body"),
+				"details":    validation.Details(c, err),
+				"request_id": requestID,
+			})
+			return
+		}
+
+		priority := req.Priority
+		if priority == "" {
+			priority = "medium"
+		}
+		notification := map[string]interface{}{
+			"user_id":      req.UserID,
+			"type":         "system",
+			"title":        req.Title,
+			"message":      req.Message,
+			"related_type": "api_key",
+		}
+		if keyID, ok := c.Get("api_key_id"); ok {
+			notification["related_id"] = keyID
+		}
+		if req.ActionURL != "" {
+			notification["action_url"] = req.ActionURL
+		}
+
+		body, err := json.Marshal(map[string]interface{}{
+			"type":         "single",
+			"priority":     priority,
+			"notification": notification,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":      "Failed to queue notification",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		taskReq, err := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, taskURL, bytes.NewReader(body))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":      "Failed to queue notification",
+				"request_id": requestID,
+			})
+			return
+		}
+		taskReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(taskReq)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"error":      err.Error(),
+			}).Error("Failed to queue public API notification")
+
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":      "Service notification-service is unavailable",
+				"request_id": requestID,
+			})
+			return
+		}
+		defer resp.Body.Close()
+
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxPublicUpstreamBody))
+
+		// Rejections of the request itself reach the partner as they are, so they can fix the
+		// request or back off after 429 with Retry-After
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			logger.WithFields(map[string]interface{}{
+				"request_id":  requestID,
+				"status_code": resp.StatusCode,
+			}).Warn("Notification service rejected public API notification")
+
+			if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+				c.Header("Retry-After", retryAfter)
+			}
+			contentType := resp.Header.Get("Content-Type")
+			if contentType == "" {
+				contentType = "application/json; charset=utf-8"
+			}
+			c.Data(resp.StatusCode, contentType, data)
+			return
+		}
+
+		if resp.StatusCode >= 300 {
+			logger.WithFields(map[string]interface{}{
+				"request_id":  requestID,
+				"status_code": resp.StatusCode,
+			}).Error("Notification service failed to queue public API notification")
+
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":      "Failed to queue notification",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		var result struct {
+			TaskID string `json:"task_id"`
+		}
+		_ = json.Unmarshal(data, &result)
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":    "Notification queued for processing",
+			"task_id":    result.TaskID,
+			"request_id": requestID,
+		})
+	}
+}
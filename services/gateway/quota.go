@@ -0,0 +1,181 @@
+// File: services/gateway/quota.go
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/quota"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaOverrideRequest represents a request to exempt a user from quotas or change their limits
+type QuotaOverrideRequest struct {
+	Exempt     bool       `json:"exempt"`
+	Multiplier float64    `json:"multiplier" binding:"omitempty,gt=0,max=1000"` // Required when not exempt
+	Reason     string     `json:"reason" binding:"omitempty,max=500"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// getQuotasHandler returns quota limits and overrides
+// GET /api/v1/admin/quotas
+func getQuotasHandler(quotas *quota.Quotas) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		overrides, err := quotas.Overrides()
+		if err != nil {
+			writeQuotaError(c, requestID, "Failed to get quota overrides", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"limits":     quotas.Limits(),
+			"overrides":  overrides,
+			"request_id": requestID,
+		})
+	}
+}
+
+// setQuotaOverrideHandler exempts a user from quotas or multiplies their limits
+// PUT /api/v1/admin/quotas/overrides/:user_id
+func setQuotaOverrideHandler(quotas *quota.Quotas) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		adminID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":      "Unauthorized",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+		if err != nil || userID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid user ID",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		var req QuotaOverrideRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      i18n.Message(c, "error.invalid_request_body"),
+				"details":    validation.Details(c, err),
+				"request_id": requestID,
+			})
+			return
+		}
+
+		now := time.Now().UTC()
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "expires_at must be in the future",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		override := &quota.Override{
+			UserID:     uint(userID),
+			Exempt:     req.Exempt,
+			Multiplier: req.Multiplier,
+			Reason:     strings.TrimSpace(req.Reason),
+			ExpiresAt:  req.ExpiresAt,
+			CreatedAt:  now,
+			CreatedBy:  adminID,
+		}
+		if err := quotas.SetOverride(override); err != nil {
+			writeQuotaError(c, requestID, "Failed to set quota override", err)
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"user_id":    userID,
+			"exempt":     req.Exempt,
+			"multiplier": req.Multiplier,
+			"expires_at": req.ExpiresAt,
+		}).Warn("Quota override set")
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Quota override set",
+			"override":   override,
+			"request_id": requestID,
+		})
+	}
+}
+
+// deleteQuotaOverrideHandler returns a user to default quota limits
+// DELETE /api/v1/admin/quotas/overrides/:user_id
+func deleteQuotaOverrideHandler(quotas *quota.Quotas) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+		if err != nil || userID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid user ID",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		if err := quotas.DeleteOverride(uint(userID)); err != nil {
+			writeQuotaError(c, requestID, "Failed to delete quota override", err)
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"user_id":    userID,
+		}).Warn("Quota override deleted")
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Quota override deleted",
+			"request_id": requestID,
+		})
+	}
+}
+
+// writeQuotaError maps quota errors to HTTP responses
+func writeQuotaError(c *gin.Context, requestID, message string, err error) {
+	switch {
+	case errors.Is(err, quota.ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Override must exempt the user or set a positive multiplier",
+			"request_id": requestID,
+		})
+	case errors.Is(err, quota.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":      "Quota overrides require Redis, which is not connected",
+			"request_id": requestID,
+		})
+	default:
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error(message)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      message,
+			"request_id": requestID,
+		})
+	}
+}
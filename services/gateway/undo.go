@@ -0,0 +1,40 @@
+// File: services/gateway/undo.go
+package main
+
+import (
+	"net/http"
+
+	"tachyon-messenger/shared/undo"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// undoHandler forwards an undo request to the service that staged the action.
+// Undo tokens are prefixed with the name of the issuing service.
+func undoHandler(proxyConfig *ProxyConfig) gin.HandlerFunc {
+	services := map[string]ServiceConfig{
+		"chat":     proxyConfig.ChatService,
+		"task":     proxyConfig.TaskService,
+		"calendar": proxyConfig.CalendarService,
+	}
+
+	proxies := make(map[string]gin.HandlerFunc, len(services))
+	for name, service := range services {
+		proxies[name] = proxyRequest(service.URL, service.Name)
+	}
+
+	return func(c *gin.Context) {
+		service, err := undo.ServiceOf(c.Param("token"))
+		proxy, ok := proxies[service]
+		if err != nil || !ok {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":      "Undo token not found",
+				"request_id": requestid.Get(c),
+			})
+			return
+		}
+
+		proxy(c)
+	}
+}
@@ -0,0 +1,143 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// Headers added to notification emails. Bounce reports return the headers of the original
+// message, so bounces can be matched to the user without knowing the address.
+const (
+	HeaderUserID         = "X-Tachyon-User-ID"
+	HeaderNotificationID = "X-Tachyon-Notification-ID"
+)
+
+// ErrNotBounce is returned for messages that are not delivery status notifications
+var ErrNotBounce = errors.New("message is not a delivery status notification")
+
+// BounceReport represents a failed recipient of a delivery status notification (RFC 3464)
+type BounceReport struct {
+	Recipient      string `json:"recipient"`
+	Status         string `json:"status"`               // Расширенный код SMTP, например 5.1.1
+	Diagnostic     string `json:"diagnostic,omitempty"` // Ответ почтового сервера получателя
+	Permanent      bool   `json:"permanent"`            // Код 5.x.x, адрес не примет почту и при повторе
+	UserID         uint   `json:"user_id,omitempty"`    // Из заголовков исходного письма, 0 если неизвестен
+	NotificationID uint   `json:"notification_id,omitempty"`
+}
+
+// ParseBounce parses a bounce message returned by the mail server. Only multipart/report
+// messages with a message/delivery-status part are understood. Recipients with an action
+// other than failed (delayed, delivered, relayed) are skipped, so the result can be empty.
+func ParseBounce(r io.Reader) ([]BounceReport, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bounce message: %w", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || params["boundary"] == "" {
+		return nil, ErrNotBounce
+	}
+
+	var status []byte
+	var original textproto.MIMEHeader
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bounce message part: %w", err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			if status, err = io.ReadAll(part); err != nil {
+				return nil, fmt.Errorf("failed to read delivery status: %w", err)
+			}
+		case "text/rfc822-headers", "message/rfc822", "message/global-headers":
+			// Only the headers of the original message are needed
+			original, _ = textproto.NewReader(bufio.NewReader(part)).ReadMIMEHeader()
+		}
+	}
+
+	if status == nil {
+		return nil, ErrNotBounce
+	}
+
+	userID := headerID(original, HeaderUserID)
+	notificationID := headerID(original, HeaderNotificationID)
+
+	// The delivery status is a block of per-message fields followed by a block per recipient
+	var reports []BounceReport
+	fields := textproto.NewReader(bufio.NewReader(bytes.NewReader(status)))
+	for block := 0; ; block++ {
+		header, err := fields.ReadMIMEHeader()
+		if block > 0 && len(header) > 0 {
+			if report, ok := parseRecipientFields(header); ok {
+				report.UserID = userID
+				report.NotificationID = notificationID
+				reports = append(reports, report)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	return reports, nil
+}
+
+// parseRecipientFields converts per-recipient delivery status fields to a report of a failed recipient
+func parseRecipientFields(header textproto.MIMEHeader) (BounceReport, bool) {
+	if !strings.EqualFold(strings.TrimSpace(header.Get("Action")), "failed") {
+		return BounceReport{}, false
+	}
+
+	recipient := typedValue(header.Get("Final-Recipient"))
+	if recipient == "" {
+		recipient = typedValue(header.Get("Original-Recipient"))
+	}
+	recipient = strings.ToLower(strings.Trim(recipient, "<>"))
+	if recipient == "" {
+		return BounceReport{}, false
+	}
+
+	// Status may carry a comment, e.g. "5.1.1 (bad destination mailbox)"
+	status, _, _ := strings.Cut(strings.TrimSpace(header.Get("Status")), " ")
+
+	return BounceReport{
+		Recipient:  recipient,
+		Status:     status,
+		Diagnostic: typedValue(header.Get("Diagnostic-Code")),
+		Permanent:  strings.HasPrefix(status, "5"),
+	}, true
+}
+
+// typedValue strips the type of a delivery status field, e.g. "rfc822; user@example.com"
+func typedValue(value string) string {
+	if _, rest, found := strings.Cut(value, ";"); found {
+		value = rest
+	}
+	return strings.TrimSpace(value)
+}
+
+// headerID parses a numeric ID header of the original message, 0 if missing
+func headerID(header textproto.MIMEHeader, name string) uint {
+	id, err := strconv.ParseUint(strings.TrimSpace(header.Get(name)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
@@ -0,0 +1,221 @@
+// File: services/notification/email/diagnostics.go
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// SMTPTranscriptStep describes a single step of an SMTP conversation
+type SMTPTranscriptStep struct {
+	Step       string `json:"step"`
+	Success    bool   `json:"success"`
+	Code       int    `json:"code,omitempty"` // SMTP response code, if the server returned one
+	Message    string `json:"message,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SMTPDiagnostics holds a summary of a diagnostic SMTP send
+type SMTPDiagnostics struct {
+	Host            string               `json:"host"`
+	Port            int                  `json:"port"`
+	UseTLS          bool                 `json:"use_tls"`
+	UseSSL          bool                 `json:"use_ssl"`
+	FromEmail       string               `json:"from_email"`
+	Recipient       string               `json:"recipient"`
+	Success         bool                 `json:"success"`
+	Error           string               `json:"error,omitempty"`
+	Transcript      []SMTPTranscriptStep `json:"transcript"`
+	TotalDurationMs int64                `json:"total_duration_ms"`
+}
+
+// SendDiagnosticEmail sends a single email without retries and records every SMTP step.
+// Credentials are never included in the transcript.
+func (s *smtpSender) SendDiagnosticEmail(to, subject, htmlBody, textBody string) *SMTPDiagnostics {
+	diag := &SMTPDiagnostics{
+		Host:       s.config.Host,
+		Port:       s.config.Port,
+		UseTLS:     s.config.UseTLS,
+		UseSSL:     s.config.UseSSL,
+		FromEmail:  s.config.FromEmail,
+		Recipient:  to,
+		Transcript: make([]SMTPTranscriptStep, 0, 8),
+	}
+
+	started := time.Now()
+	defer func() {
+		diag.TotalDurationMs = time.Since(started).Milliseconds()
+	}()
+
+	if !isValidEmail(to) {
+		diag.Error = fmt.Sprintf("invalid recipient email: %s", to)
+		return diag
+	}
+
+	message, err := s.buildEmailMessage([]string{to}, nil, nil, subject, htmlBody, textBody, nil)
+	if err != nil {
+		diag.Error = fmt.Sprintf("failed to build message: %v", err)
+		return diag
+	}
+
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	// Connect
+	var client *smtp.Client
+	err = diag.record("CONNECT "+addr, func() error {
+		dialer := &net.Dialer{Timeout: s.config.Timeout}
+		var conn net.Conn
+		var dialErr error
+		if s.config.UseSSL {
+			conn, dialErr = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: s.config.Host})
+		} else {
+			conn, dialErr = dialer.Dial("tcp", addr)
+		}
+		if dialErr != nil {
+			return dialErr
+		}
+		client, dialErr = smtp.NewClient(conn, s.config.Host)
+		if dialErr != nil {
+			conn.Close()
+		}
+		return dialErr
+	})
+	if err != nil {
+		diag.Error = fmt.Sprintf("failed to connect to SMTP server: %v", err)
+		return diag
+	}
+	defer client.Close()
+
+	// STARTTLS (only for plain connections)
+	if s.config.UseTLS && !s.config.UseSSL {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			diag.Transcript = append(diag.Transcript, SMTPTranscriptStep{
+				Step:    "STARTTLS",
+				Message: "server does not advertise STARTTLS",
+			})
+			diag.Error = "TLS is enabled but server does not support STARTTLS"
+			return diag
+		}
+		if err := diag.record("STARTTLS", func() error {
+			return client.StartTLS(&tls.Config{ServerName: s.config.Host})
+		}); err != nil {
+			diag.Error = fmt.Sprintf("failed to start TLS: %v", err)
+			return diag
+		}
+	}
+
+	// AUTH
+	if err := diag.record("AUTH", func() error {
+		return client.Auth(s.auth)
+	}); err != nil {
+		diag.Error = fmt.Sprintf("SMTP authentication failed: %v", err)
+		return diag
+	}
+
+	// MAIL FROM / RCPT TO
+	if err := diag.record("MAIL FROM", func() error {
+		return client.Mail(s.config.FromEmail)
+	}); err != nil {
+		diag.Error = fmt.Sprintf("failed to set sender: %v", err)
+		return diag
+	}
+
+	if err := diag.record("RCPT TO", func() error {
+		return client.Rcpt(to)
+	}); err != nil {
+		diag.Error = fmt.Sprintf("failed to set recipient: %v", err)
+		return diag
+	}
+
+	// DATA
+	if err := diag.record("DATA", func() error {
+		writer, err := client.Data()
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(message); err != nil {
+			writer.Close()
+			return err
+		}
+		return writer.Close()
+	}); err != nil {
+		diag.Error = fmt.Sprintf("failed to transmit message: %v", err)
+		return diag
+	}
+
+	// QUIT failure doesn't affect delivery, it is only recorded
+	diag.record("QUIT", client.Quit)
+
+	diag.Success = true
+	return diag
+}
+
+// CheckConnection connects to the SMTP server and ends the session without sending anything.
+// It is used as a health probe of the server.
+func (s *smtpSender) CheckConnection() error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	var conn net.Conn
+	var err error
+	if s.config.UseSSL {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: s.config.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if s.config.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.config.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if s.config.UseTLS && !s.config.UseSSL {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+				return fmt.Errorf("failed to start TLS: %w", err)
+			}
+		}
+	}
+
+	if err := client.Noop(); err != nil {
+		return fmt.Errorf("SMTP server is not responding: %w", err)
+	}
+	return client.Quit()
+}
+
+// record runs a single SMTP step and appends its outcome to the transcript
+func (d *SMTPDiagnostics) record(step string, fn func() error) error {
+	started := time.Now()
+	err := fn()
+
+	entry := SMTPTranscriptStep{
+		Step:       step,
+		Success:    err == nil,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		entry.Message = err.Error()
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) {
+			entry.Code = protoErr.Code
+			entry.Message = protoErr.Msg
+		}
+	}
+
+	d.Transcript = append(d.Transcript, entry)
+	return err
+}
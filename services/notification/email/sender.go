@@ -8,11 +8,13 @@ import (
 	"html/template"
 	"net/smtp"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 )
 
@@ -21,6 +23,8 @@ type EmailSender interface {
 	SendEmail(req *SendEmailRequest) error
 	SendTemplatedEmail(req *TemplatedEmailRequest) error
 	SendBulkEmail(req *BulkEmailRequest) error
+	SendDiagnosticEmail(to, subject, htmlBody, textBody string) *SMTPDiagnostics
+	CheckConnection() error
 	ValidateConfig() error
 }
 
@@ -34,6 +38,7 @@ type SendEmailRequest struct {
 	TextBody    string                      `json:"text_body,omitempty"`
 	Attachments []string                    `json:"attachments,omitempty"` // File paths
 	Priority    models.NotificationPriority `json:"priority,omitempty"`
+	Headers     map[string]string           `json:"headers,omitempty"` // Extra headers, e.g. HeaderUserID
 }
 
 // TemplatedEmailRequest represents an email request using template
@@ -44,6 +49,8 @@ type TemplatedEmailRequest struct {
 	TemplateName string                      `json:"template_name" validate:"required"`
 	Variables    map[string]interface{}      `json:"variables,omitempty"`
 	Priority     models.NotificationPriority `json:"priority,omitempty"`
+	Locale       i18n.Locale                 `json:"locale,omitempty"`  // Язык получателя, по умолчанию i18n.DefaultLocale
+	Subject      string                      `json:"subject,omitempty"` // Шаблон темы варианта эксперимента, заменяет тему шаблона
 }
 
 // BulkEmailRequest represents a bulk email sending request
@@ -182,7 +189,7 @@ func (s *smtpSender) SendEmail(req *SendEmailRequest) error {
 	}
 
 	// Build email message
-	message, err := s.buildEmailMessage(req.To, req.CC, req.BCC, req.Subject, req.HTMLBody, req.TextBody)
+	message, err := s.buildEmailMessage(req.To, req.CC, req.BCC, req.Subject, req.HTMLBody, req.TextBody, req.Headers)
 	if err != nil {
 		return fmt.Errorf("failed to build email message: %w", err)
 	}
@@ -201,14 +208,24 @@ func (s *smtpSender) SendTemplatedEmail(req *TemplatedEmailRequest) error {
 		return fmt.Errorf("invalid templated email request: %w", err)
 	}
 
-	// Get template
-	tmpl, exists := s.templates[req.TemplateName]
+	// Get template in the recipient locale, falling back to the default one
+	tmpl, exists := s.templates[LocalizedTemplateName(req.TemplateName, req.Locale)]
+	if !exists {
+		tmpl, exists = s.templates[req.TemplateName]
+	}
 	if !exists {
 		return fmt.Errorf("template not found: %s", req.TemplateName)
 	}
 
-	// Render subject
-	subject, err := s.renderTemplate(tmpl.Subject, req.Variables)
+	// Dates and numbers are formatted in the recipient locale
+	variables := LocalizeVariables(req.Locale, req.Variables)
+
+	// Render subject, a tested variant replaces the one of the template
+	subjectTemplate := tmpl.Subject
+	if req.Subject != "" {
+		subjectTemplate = req.Subject
+	}
+	subject, err := s.renderTemplate(subjectTemplate, variables)
 	if err != nil {
 		return fmt.Errorf("failed to render subject template: %w", err)
 	}
@@ -216,7 +233,7 @@ func (s *smtpSender) SendTemplatedEmail(req *TemplatedEmailRequest) error {
 	// Render HTML body
 	var htmlBody string
 	if tmpl.HTMLTemplate != nil {
-		htmlBody, err = s.renderHTMLTemplate(tmpl.HTMLTemplate, req.Variables)
+		htmlBody, err = s.renderHTMLTemplate(tmpl.HTMLTemplate, variables)
 		if err != nil {
 			return fmt.Errorf("failed to render HTML template: %w", err)
 		}
@@ -225,14 +242,14 @@ func (s *smtpSender) SendTemplatedEmail(req *TemplatedEmailRequest) error {
 	// Render text body
 	var textBody string
 	if tmpl.TextTemplate != nil {
-		textBody, err = s.renderHTMLTemplate(tmpl.TextTemplate, req.Variables)
+		textBody, err = s.renderHTMLTemplate(tmpl.TextTemplate, variables)
 		if err != nil {
 			return fmt.Errorf("failed to render text template: %w", err)
 		}
 	}
 
 	// Build email message
-	message, err := s.buildEmailMessage(req.To, req.CC, req.BCC, subject, htmlBody, textBody)
+	message, err := s.buildEmailMessage(req.To, req.CC, req.BCC, subject, htmlBody, textBody, nil)
 	if err != nil {
 		return fmt.Errorf("failed to build email message: %w", err)
 	}
@@ -281,7 +298,7 @@ func (s *smtpSender) SendBulkEmail(req *BulkEmailRequest) error {
 		}
 
 		// Build message
-		message, err := s.buildEmailMessage([]string{recipient.Email}, nil, nil, subject, htmlBody, textBody)
+		message, err := s.buildEmailMessage([]string{recipient.Email}, nil, nil, subject, htmlBody, textBody, nil)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("failed to build message for %s: %v", recipient.Email, err))
 			continue
@@ -350,7 +367,7 @@ func (s *smtpSender) sendWithRetry(recipients []string, message []byte, priority
 		lastErr = err
 
 		// Check if error is retryable
-		if !s.isRetryableError(err) {
+		if !IsTemporaryFailure(err) {
 			logger.WithFields(map[string]interface{}{
 				"error":      err.Error(),
 				"recipients": len(recipients),
@@ -438,7 +455,7 @@ func (s *smtpSender) sendWithSSL(addr string, recipients []string, message []byt
 }
 
 // buildEmailMessage builds the email message
-func (s *smtpSender) buildEmailMessage(to, cc, bcc []string, subject, htmlBody, textBody string) ([]byte, error) {
+func (s *smtpSender) buildEmailMessage(to, cc, bcc []string, subject, htmlBody, textBody string, headers map[string]string) ([]byte, error) {
 	var msg bytes.Buffer
 
 	// Headers
@@ -453,6 +470,16 @@ func (s *smtpSender) buildEmailMessage(to, cc, bcc []string, subject, htmlBody,
 	msg.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
 	msg.WriteString("MIME-Version: 1.0\r\n")
 
+	// Extra headers in a stable order
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		msg.WriteString(fmt.Sprintf("%s: %s\r\n", name, headers[name]))
+	}
+
 	// Content type based on available bodies
 	if htmlBody != "" && textBody != "" {
 		// Multipart alternative
@@ -520,6 +547,39 @@ func (s *smtpSender) renderTemplate(templateStr string, variables map[string]int
 	return buf.String(), nil
 }
 
+// LocalizeVariables returns template variables with dates and numbers formatted in locale.
+// Variables decoded from JSON carry dates as RFC 3339 strings and numbers as float64.
+func LocalizeVariables(locale i18n.Locale, variables map[string]interface{}) map[string]interface{} {
+	if len(variables) == 0 {
+		return variables
+	}
+
+	localized := make(map[string]interface{}, len(variables))
+	for key, value := range variables {
+		switch v := value.(type) {
+		case time.Time:
+			localized[key] = i18n.FormatDateTime(locale, v)
+		case *time.Time:
+			if v != nil {
+				localized[key] = i18n.FormatDateTime(locale, *v)
+			} else {
+				localized[key] = ""
+			}
+		case string:
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				localized[key] = i18n.FormatDateTime(locale, t)
+			} else {
+				localized[key] = v
+			}
+		case float32, float64:
+			localized[key] = i18n.FormatNumber(locale, v)
+		default:
+			localized[key] = value
+		}
+	}
+	return localized
+}
+
 // renderHTMLTemplate renders an HTML template with variables
 func (s *smtpSender) renderHTMLTemplate(tmpl *template.Template, variables map[string]interface{}) (string, error) {
 	var buf bytes.Buffer
@@ -529,8 +589,9 @@ func (s *smtpSender) renderHTMLTemplate(tmpl *template.Template, variables map[s
 	return buf.String(), nil
 }
 
-// isRetryableError checks if an error is retryable
-func (s *smtpSender) isRetryableError(err error) bool {
+// IsTemporaryFailure checks if a send error is caused by an unreachable or temporarily
+// failing SMTP server, so the email can be sent later
+func IsTemporaryFailure(err error) bool {
 	if err == nil {
 		return false
 	}
@@ -603,6 +664,11 @@ func (s *smtpSender) validateSendEmailRequest(req *SendEmailRequest) error {
 	if req.HTMLBody == "" && req.TextBody == "" {
 		return fmt.Errorf("either HTML or text body is required")
 	}
+	for name, value := range req.Headers {
+		if name == "" || strings.ContainsAny(name, ": \r\n") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header: %q", name)
+		}
+	}
 	return nil
 }
 
@@ -856,9 +856,11 @@ func NewTemplateLoader(sender EmailSender) *TemplateLoader {
 func (tl *TemplateLoader) LoadDefaultTemplates() error {
 	var errors []string
 
-	for name, tmpl := range DefaultEmailTemplates {
-		if err := tl.loadTemplate(tmpl); err != nil {
-			errors = append(errors, fmt.Sprintf("failed to load template %s: %v", name, err))
+	for _, templates := range []map[string]*models.EmailTemplate{DefaultEmailTemplates, DefaultEmailTemplatesEN} {
+		for _, tmpl := range templates {
+			if err := tl.loadTemplate(tmpl); err != nil {
+				errors = append(errors, fmt.Sprintf("failed to load template %s: %v", tmpl.Name, err))
+			}
 		}
 	}
 
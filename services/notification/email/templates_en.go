@@ -0,0 +1,223 @@
+// File: services/notification/email/templates_en.go
+package email
+
+import (
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/i18n"
+)
+
+// DefaultEmailTemplatesEN contains English versions of built-in email templates.
+// They are plain text; recipients with other locales get DefaultEmailTemplates.
+var DefaultEmailTemplatesEN = map[string]*models.EmailTemplate{
+	"welcome": {
+		Name:    LocalizedTemplateName("welcome", i18n.LocaleEN),
+		Type:    models.NotificationTypeSystem,
+		Subject: "Welcome to Tachyon Messenger, {{.UserName}}!",
+		TextTemplate: `
+Welcome to Tachyon Messenger, {{.UserName}}!
+
+We are glad to welcome you to the Tachyon Messenger corporate community!
+Your account has been created and is ready to use.
+
+Now you can chat with colleagues, manage tasks and keep up with all important company events.
+
+Tachyon Messenger features:
+• Messenger - chat with colleagues in direct and group chats
+• Task management - create, assign and track tasks
+• Calendar - plan meetings and keep track of important events
+• Polls - take part in corporate polls and votes
+
+Get started: {{.AppURL}}
+
+If you have any questions, feel free to contact our support team.
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+
+	"task_assigned": {
+		Name:    LocalizedTemplateName("task_assigned", i18n.LocaleEN),
+		Type:    models.NotificationTypeTask,
+		Subject: "You have been assigned a new task: {{.TaskTitle}}",
+		TextTemplate: `
+You have been assigned a new task
+
+Title: {{.TaskTitle}}
+{{if .TaskDescription}}Description: {{.TaskDescription}}{{end}}
+Priority: {{if eq .TaskPriority "high"}}High{{else if eq .TaskPriority "medium"}}Medium{{else if eq .TaskPriority "low"}}Low{{else if eq .TaskPriority "critical"}}Critical{{end}}
+Assigned by: {{.AssignerName}}
+{{if .DueDate}}Due date: {{.DueDate}}{{end}}
+Created at: {{.CreatedAt}}
+
+View task: {{.TaskURL}}
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+
+	"message_notification": {
+		Name:    LocalizedTemplateName("message_notification", i18n.LocaleEN),
+		Type:    models.NotificationTypeMessage,
+		Subject: "New message from {{.SenderName}}",
+		TextTemplate: `
+New message from {{.SenderName}}
+
+Chat: {{.ChatName}}
+Message: {{.MessageContent}}
+Time: {{.CreatedAt}}
+
+Open chat: {{.ChatURL}}
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+
+	"calendar_reminder": {
+		Name:    LocalizedTemplateName("calendar_reminder", i18n.LocaleEN),
+		Type:    models.NotificationTypeCalendar,
+		Subject: "Event reminder: {{.EventTitle}}",
+		TextTemplate: `
+Event reminder: {{.EventTitle}}
+
+{{if .EventDescription}}Description: {{.EventDescription}}{{end}}
+Starts: {{.StartTime}}
+{{if .EndTime}}Ends: {{.EndTime}}{{end}}
+{{if .Location}}Location: {{.Location}}{{end}}
+{{if .Participants}}Participants: {{.Participants}}{{end}}
+
+View event: {{.EventURL}}
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+
+	"system_announcement": {
+		Name:    LocalizedTemplateName("system_announcement", i18n.LocaleEN),
+		Type:    models.NotificationTypeAnnounce,
+		Subject: "{{.AnnouncementTitle}}",
+		TextTemplate: `
+{{.AnnouncementTitle}}
+
+{{.AnnouncementContent}}
+
+{{if .IsImportant}}⚠️ IMPORTANT ANNOUNCEMENT! Please read this information carefully.{{end}}
+
+{{if .ActionRequired}}📋 ACTION REQUIRED: {{.ActionRequired}}{{end}}
+
+{{if .ReadMoreURL}}Read more: {{.ReadMoreURL}}{{end}}
+
+Published at: {{.PublishedAt}}
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+
+	"poll_notification": {
+		Name:    LocalizedTemplateName("poll_notification", i18n.LocaleEN),
+		Type:    models.NotificationTypePoll,
+		Subject: "New poll: {{.PollTitle}}",
+		TextTemplate: `
+New poll: {{.PollTitle}}
+
+{{if .PollDescription}}Description: {{.PollDescription}}{{end}}
+Poll type: {{.PollType}}
+Created by: {{.CreatorName}}
+{{if .DeadlineDate}}Deadline: {{.DeadlineDate}}{{end}}
+Created at: {{.CreatedAt}}
+
+Take part: {{.PollURL}}
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+
+	"password_reset": {
+		Name:    LocalizedTemplateName("password_reset", i18n.LocaleEN),
+		Type:    models.NotificationTypeSystem,
+		Subject: "Tachyon Messenger password reset",
+		TextTemplate: `
+Tachyon Messenger password reset
+
+Hello, {{.UserName}}!
+
+We received a request to reset the password of your Tachyon Messenger account.
+
+Request details:
+Request time: {{.RequestTime}}
+IP address: {{.RequestIP}}
+
+{{if .ResetCode}}
+Use the following code to reset your password: {{.ResetCode}}
+The code is valid for {{.CodeExpiration}} minutes.
+{{else}}
+To reset your password follow the link: {{.ResetURL}}
+The link is valid for {{.LinkExpiration}} hours.
+{{end}}
+
+IMPORTANT SECURITY INFORMATION:
+• If you did not request a password reset, just ignore this email
+• Never share the reset code or link with anyone
+• We recommend choosing a strong password after the reset
+
+If you have any problems, contact the support team.
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+
+	"daily_digest": {
+		Name:    LocalizedTemplateName("daily_digest", i18n.LocaleEN),
+		Type:    models.NotificationTypeSystem,
+		Subject: "Daily digest for {{.Date}}",
+		TextTemplate: `
+Daily digest for {{.Date}}
+
+Welcome to your daily digest, {{.UserName}}!
+
+{{if .MessagesStats}}
+💬 MESSAGES
+New messages: {{.MessagesStats.NewMessages}}
+Unread: {{.MessagesStats.UnreadMessages}}
+Active chats: {{.MessagesStats.ActiveChats}}
+{{end}}
+
+{{if .TasksStats}}
+📋 TASKS
+New tasks: {{.TasksStats.NewTasks}}
+Completed: {{.TasksStats.CompletedTasks}}
+Overdue: {{.TasksStats.OverdueTasks}}
+{{if .TasksStats.UpcomingDeadlines}}⏰ Upcoming deadlines: {{.TasksStats.UpcomingDeadlines}}{{end}}
+{{end}}
+
+{{if .CalendarStats}}
+📅 EVENTS
+Events today: {{.CalendarStats.TodayEvents}}
+Events tomorrow: {{.CalendarStats.TomorrowEvents}}
+{{if .CalendarStats.NextEvent}}📌 Next event: {{.CalendarStats.NextEvent}}{{end}}
+{{end}}
+
+Open Tachyon Messenger: {{.AppURL}}
+
+To change digest settings, go to notification settings.
+
+Best regards, the Tachyon Messenger team
+This is an automated message, please do not reply.`,
+		IsActive: true,
+	},
+}
+
+// LocalizedTemplateName returns the name of a template version for locale.
+// DefaultEmailTemplates are in i18n.DefaultLocale and keep plain names.
+func LocalizedTemplateName(name string, locale i18n.Locale) string {
+	if locale == "" || locale == i18n.DefaultLocale {
+		return name
+	}
+	return name + "." + string(locale)
+}
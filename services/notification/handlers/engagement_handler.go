@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// TrackEngagement handles an open or click of a notification reported by a client. Only
+// notifications sent with a variant of a template experiment are recorded, others are ignored.
+// POST /api/v1/notifications/:id/engagement
+func (h *NotificationHandler) TrackEngagement(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || notificationID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid notification ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.TrackEngagementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	recorded, err := h.notificationUsecase.TrackNotificationEngagement(userID, uint(notificationID), &req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to track notification engagement"
+
+		switch {
+		case strings.Contains(err.Error(), "validation failed"):
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+			errorMessage = "Notification not found"
+		}
+
+		if statusCode == http.StatusInternalServerError {
+			logger.WithFields(map[string]interface{}{
+				"request_id":      requestID,
+				"user_id":         userID,
+				"notification_id": notificationID,
+				"error":           err.Error(),
+			}).Error(errorMessage)
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recorded":   recorded,
+		"request_id": requestID,
+	})
+}
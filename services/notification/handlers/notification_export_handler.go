@@ -0,0 +1,132 @@
+// File: services/notification/handlers/notification_export_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/spreadsheet"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportNotifications handles downloading the user's notification history as a CSV or JSON
+// file, the file is streamed while notifications are loaded
+// GET /api/v1/notifications/export?format=csv|json&from=&to=
+func (h *NotificationHandler) ExportNotifications(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.ExportNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query parameters",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+	if req.Format == "" {
+		req.Format = models.ExportFormatCSV
+	}
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query parameters",
+			"details":    "to must not be before from",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == models.ExportFormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	filename := fmt.Sprintf("notifications-%s.%s", time.Now().UTC().Format("2006-01-02"), req.Format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// Headers are sent with the first row, failures past that point can only cut the file short
+	exported := 0
+	err := writeNotificationExport(c.Writer, req.Format, func(write func(*models.NotificationResponse) error) error {
+		return h.notificationUsecase.ExportNotifications(userID, &req, func(notification *models.NotificationResponse) error {
+			exported++
+			return write(notification)
+		})
+	})
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"format":     req.Format,
+			"exported":   exported,
+			"error":      err.Error(),
+		}).Error("Failed to export notifications")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"format":     req.Format,
+		"exported":   exported,
+	}).Info("Notifications exported")
+}
+
+// writeNotificationExport writes the notifications produced by export as a CSV file with a
+// header row or as a JSON array
+func writeNotificationExport(w io.Writer, format string, export func(write func(*models.NotificationResponse) error) error) error {
+	if format == models.ExportFormatJSON {
+		return writeNotificationJSON(w, export)
+	}
+
+	writer, err := spreadsheet.NewWriter(w, spreadsheet.FormatCSV, "Notifications")
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRow(models.ExportColumns); err != nil {
+		return err
+	}
+	if err := export(func(notification *models.NotificationResponse) error {
+		return writer.WriteRow(notification.ExportRow())
+	}); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// writeNotificationJSON writes the notifications produced by export as a JSON array, one
+// element at a time
+func writeNotificationJSON(w io.Writer, export func(write func(*models.NotificationResponse) error) error) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	separator := ""
+	if err := export(func(notification *models.NotificationResponse) error {
+		data, err := json.Marshal(notification)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		separator = ","
+		_, err = w.Write(data)
+		return err
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
@@ -8,8 +8,11 @@ import (
 
 	"tachyon-messenger/services/notification/models"
 	"tachyon-messenger/services/notification/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -47,40 +50,12 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		return
 	}
 
-	// Parse query parameters
-	filter := &models.NotificationFilterRequest{}
-	if err := c.ShouldBindQuery(filter); err != nil {
-		logger.WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"user_id":    userID,
-			"error":      err.Error(),
-		}).Warn("Invalid query parameters for get notifications")
-
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid query parameters",
-			"details":    err.Error(),
-			"request_id": requestID,
-		})
+	// Parse filter, sorting and pagination parameters
+	filter, ok := h.bindNotificationFilter(c, requestID, userID)
+	if !ok {
 		return
 	}
 
-	// Set default values if not provided
-	if filter.Limit <= 0 {
-		filter.Limit = 20
-	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
-	}
-	if filter.Offset < 0 {
-		filter.Offset = 0
-	}
-	if filter.SortBy == "" {
-		filter.SortBy = "created_at"
-	}
-	if filter.SortOrder == "" {
-		filter.SortOrder = "desc"
-	}
-
 	// Get notifications
 	notifications, err := h.notificationUsecase.GetUserNotifications(userID, filter)
 	if err != nil {
@@ -110,6 +85,7 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		"limit":         notifications.Limit,
 		"offset":        notifications.Offset,
 		"has_more":      notifications.HasMore,
+		"next_cursor":   notifications.NextCursor,
 		"request_id":    requestID,
 	})
 }
@@ -305,8 +281,8 @@ func (h *NotificationHandler) MarkMultipleAsRead(c *gin.Context) {
 		}).Warn("Invalid request body for mark as read")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -350,6 +326,79 @@ func (h *NotificationHandler) MarkMultipleAsRead(c *gin.Context) {
 	})
 }
 
+// MarkAsReadByFilter handles marking notifications matching a filter as read
+// PUT /api/v1/notifications/read-by-filter
+func (h *NotificationHandler) MarkAsReadByFilter(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse request body
+	var req models.MarkAsReadByFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for mark as read by filter")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	count, err := h.notificationUsecase.MarkAsReadByFilter(userID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to mark notifications as read by filter")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to mark notifications as read"
+
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"count":      count,
+	}).Info("Notifications marked as read by filter")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Notifications marked as read",
+		"count":      count,
+		"request_id": requestID,
+	})
+}
+
 // MarkAllAsRead handles marking all notifications as read for a user
 // PUT /api/v1/notifications/read-all
 func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
@@ -549,8 +598,8 @@ func (h *NotificationHandler) SearchNotifications(c *gin.Context) {
 	}
 
 	// Get search query
-	query := strings.TrimSpace(c.Query("q"))
-	if query == "" {
+	searchQuery := strings.TrimSpace(c.Query("q"))
+	if searchQuery == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":      "Search query is required",
 			"request_id": requestID,
@@ -558,41 +607,19 @@ func (h *NotificationHandler) SearchNotifications(c *gin.Context) {
 		return
 	}
 
-	// Parse filter parameters
-	filter := &models.NotificationFilterRequest{}
-	if err := c.ShouldBindQuery(filter); err != nil {
-		logger.WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"user_id":    userID,
-			"error":      err.Error(),
-		}).Warn("Invalid query parameters for search")
-
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid query parameters",
-			"details":    err.Error(),
-			"request_id": requestID,
-		})
+	// Parse filter, sorting and pagination parameters
+	filter, ok := h.bindNotificationFilter(c, requestID, userID)
+	if !ok {
 		return
 	}
 
-	// Set default values
-	if filter.Limit <= 0 {
-		filter.Limit = 20
-	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
-	}
-	if filter.Offset < 0 {
-		filter.Offset = 0
-	}
-
 	// Search notifications
-	notifications, err := h.notificationUsecase.SearchNotifications(userID, query, filter)
+	notifications, err := h.notificationUsecase.SearchNotifications(userID, searchQuery, filter)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
 			"user_id":    userID,
-			"query":      query,
+			"query":      searchQuery,
 			"error":      err.Error(),
 		}).Error("Failed to search notifications")
 
@@ -606,7 +633,7 @@ func (h *NotificationHandler) SearchNotifications(c *gin.Context) {
 	logger.WithFields(map[string]interface{}{
 		"request_id":   requestID,
 		"user_id":      userID,
-		"query":        query,
+		"query":        searchQuery,
 		"result_count": len(notifications.Notifications),
 		"total":        notifications.Total,
 	}).Info("Notifications searched successfully")
@@ -614,10 +641,11 @@ func (h *NotificationHandler) SearchNotifications(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"notifications": notifications.Notifications,
 		"total":         notifications.Total,
-		"query":         query,
+		"query":         searchQuery,
 		"limit":         notifications.Limit,
 		"offset":        notifications.Offset,
 		"has_more":      notifications.HasMore,
+		"next_cursor":   notifications.NextCursor,
 		"request_id":    requestID,
 	})
 }
@@ -665,8 +693,19 @@ func (h *NotificationHandler) GetUserPreferences(c *gin.Context) {
 	}).Info("User preferences retrieved successfully")
 
 	c.JSON(http.StatusOK, gin.H{
-		"preferences": preferences,
-		"request_id":  requestID,
+		"preferences":    preferences,
+		"email_delivery": h.emailDeliveryStatus(requestID, userID),
+		"request_id":     requestID,
+	})
+}
+
+// GetPresentationCatalog handles getting sounds and banner styles allowed in preferences
+// together with the presentation defaults of notification types
+// GET /api/v1/notifications/preferences/catalog
+func (h *NotificationHandler) GetPresentationCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"catalog":    models.PresentationCatalog(),
+		"request_id": requestid.Get(c),
 	})
 }
 
@@ -704,8 +743,8 @@ func (h *NotificationHandler) UpdateUserPreference(c *gin.Context) {
 		}).Warn("Invalid request body for update preference")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -746,8 +785,73 @@ func (h *NotificationHandler) UpdateUserPreference(c *gin.Context) {
 	}).Info("User preference updated successfully")
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "User preference updated successfully",
-		"type":       notificationType,
-		"request_id": requestID,
+		"message":        "User preference updated successfully",
+		"type":           notificationType,
+		"email_delivery": h.emailDeliveryStatus(requestID, userID),
+		"request_id":     requestID,
 	})
 }
+
+// emailDeliveryStatus returns whether email notifications reach the user, so clients can explain
+// why enabled email notifications are not arriving. Nil if the status could not be loaded.
+func (h *NotificationHandler) emailDeliveryStatus(requestID string, userID uint) *models.EmailDeliveryStatus {
+	status, err := h.notificationUsecase.GetEmailDeliveryStatus(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Failed to get email delivery status")
+		return nil
+	}
+	return status
+}
+
+// bindNotificationFilter binds filter, sorting and pagination query parameters. With view_id the filter
+// of the saved view is combined with the request filters and its sort is used unless the request has one.
+func (h *NotificationHandler) bindNotificationFilter(c *gin.Context, requestID string, userID uint) (*models.NotificationFilterRequest, bool) {
+	filter := &models.NotificationFilterRequest{}
+	values := c.Request.URL.Query()
+	err := c.ShouldBindQuery(filter)
+	if err == nil && filter.ViewID != nil {
+		view, viewErr := h.notificationUsecase.GetNotificationView(userID, *filter.ViewID)
+		if viewErr != nil {
+			statusCode := http.StatusInternalServerError
+			errorMessage := "Failed to get notification view"
+			if strings.Contains(viewErr.Error(), "not found") {
+				statusCode = http.StatusNotFound
+				errorMessage = "Notification view not found"
+			}
+
+			c.JSON(statusCode, gin.H{
+				"error":      errorMessage,
+				"request_id": requestID,
+			})
+			return nil, false
+		}
+
+		filter.View = &view.Filter
+		if view.Filter.Sort != "" && values.Get("sort") == "" && values.Get("sort_by") == "" {
+			values.Set("sort", view.Filter.Sort)
+		}
+	}
+	if err == nil {
+		filter.Page, err = query.Parse(values, models.NotificationListOptions)
+	}
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for notifications")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+
+	return filter, true
+}
@@ -0,0 +1,114 @@
+// File: services/notification/handlers/notification_sync_handler.go
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive is how often an idle event stream sends a comment, so proxies keep it open
+const streamKeepAlive = 25 * time.Second
+
+// SyncNotifications handles fetching notification changes since the previous sync
+// GET /api/v1/notifications/sync?since=&after_id=&limit=
+func (h *NotificationHandler) SyncNotifications(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.NotificationSyncRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query parameters",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	response, err := h.notificationUsecase.SyncNotifications(userID, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      err.Error(),
+				"request_id": requestID,
+			})
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to sync notifications")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to sync notifications",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sync":       response,
+		"request_id": requestID,
+	})
+}
+
+// StreamEvents handles the real-time stream of notification events as server-sent events.
+// Every device of the user gets new notifications and read changes made on other devices.
+// GET /api/v1/notifications/stream
+func (h *NotificationHandler) StreamEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	events, err := h.notificationUsecase.SubscribeEvents(c.Request.Context(), userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to subscribe to notification events")
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":      "Real-time events are unavailable",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", string(event))
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+		}
+		return true
+	})
+}
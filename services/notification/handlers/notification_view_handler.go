@@ -0,0 +1,218 @@
+// File: services/notification/handlers/notification_view_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotificationViews handles listing saved notification center views of the user
+// GET /api/v1/notifications/views
+func (h *NotificationHandler) GetNotificationViews(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	views, err := h.notificationUsecase.GetNotificationViews(userID)
+	if err != nil {
+		writeNotificationViewError(c, requestID, userID, err, "Failed to get notification views")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"views":      views,
+		"request_id": requestID,
+	})
+}
+
+// GetNotificationView handles getting a saved view
+// GET /api/v1/notifications/views/:view_id
+func (h *NotificationHandler) GetNotificationView(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+	viewID, ok := parseNotificationViewID(c, requestID)
+	if !ok {
+		return
+	}
+
+	view, err := h.notificationUsecase.GetNotificationView(userID, viewID)
+	if err != nil {
+		writeNotificationViewError(c, requestID, userID, err, "Failed to get notification view")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"view":       view,
+		"request_id": requestID,
+	})
+}
+
+// CreateNotificationView handles saving a notification center view
+// POST /api/v1/notifications/views
+func (h *NotificationHandler) CreateNotificationView(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateNotificationViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	view, err := h.notificationUsecase.CreateNotificationView(userID, &req)
+	if err != nil {
+		writeNotificationViewError(c, requestID, userID, err, "Failed to create notification view")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"view":       view,
+		"request_id": requestID,
+	})
+}
+
+// UpdateNotificationView handles renaming a saved view or replacing its filter
+// PUT /api/v1/notifications/views/:view_id
+func (h *NotificationHandler) UpdateNotificationView(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+	viewID, ok := parseNotificationViewID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateNotificationViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	view, err := h.notificationUsecase.UpdateNotificationView(userID, viewID, &req)
+	if err != nil {
+		writeNotificationViewError(c, requestID, userID, err, "Failed to update notification view")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"view":       view,
+		"request_id": requestID,
+	})
+}
+
+// DeleteNotificationView handles deleting a saved view
+// DELETE /api/v1/notifications/views/:view_id
+func (h *NotificationHandler) DeleteNotificationView(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+	viewID, ok := parseNotificationViewID(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationUsecase.DeleteNotificationView(userID, viewID); err != nil {
+		writeNotificationViewError(c, requestID, userID, err, "Failed to delete notification view")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Notification view deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// authenticatedUserID returns the user ID from the JWT token, responding with 401 if it is missing
+func authenticatedUserID(c *gin.Context, requestID string) (uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// parseNotificationViewID parses the view ID URL parameter, responding with 400 if it is invalid
+func parseNotificationViewID(c *gin.Context, requestID string) (uint, bool) {
+	viewID, err := strconv.ParseUint(c.Param("view_id"), 10, 32)
+	if err != nil || viewID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid view ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(viewID), true
+}
+
+// writeNotificationViewError maps usecase errors of saved views to HTTP responses
+func writeNotificationViewError(c *gin.Context, requestID string, userID uint, err error, message string) {
+	statusCode := http.StatusInternalServerError
+	errorMessage := message
+
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = "Notification view not found"
+	case strings.Contains(err.Error(), "already exists"):
+		statusCode = http.StatusConflict
+		errorMessage = err.Error()
+	}
+
+	if statusCode == http.StatusInternalServerError {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error(message)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
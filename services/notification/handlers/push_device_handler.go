@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPushDevices handles listing devices of the user registered for push notifications
+// GET /api/v1/notifications/devices
+func (h *NotificationHandler) GetPushDevices(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	devices, err := h.notificationUsecase.GetPushDevices(userID)
+	if err != nil {
+		writePushDeviceError(c, requestID, userID, err, "Failed to get push devices")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices":    devices,
+		"request_id": requestID,
+	})
+}
+
+// RegisterPushDevice handles registering an APNs or FCM token of the user's device.
+// Apps call it on every start, so the token stays fresh.
+// POST /api/v1/notifications/devices
+func (h *NotificationHandler) RegisterPushDevice(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.RegisterPushDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	device, err := h.notificationUsecase.RegisterPushDevice(userID, &req)
+	if err != nil {
+		writePushDeviceError(c, requestID, userID, err, "Failed to register push device")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"device":     device,
+		"request_id": requestID,
+	})
+}
+
+// DeletePushDevice handles unregistering a device, e.g. on logout
+// DELETE /api/v1/notifications/devices/:device_id
+func (h *NotificationHandler) DeletePushDevice(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := authenticatedUserID(c, requestID)
+	if !ok {
+		return
+	}
+
+	deviceID, err := strconv.ParseUint(c.Param("device_id"), 10, 32)
+	if err != nil || deviceID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid device ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.notificationUsecase.DeletePushDevice(userID, uint(deviceID)); err != nil {
+		writePushDeviceError(c, requestID, userID, err, "Failed to delete push device")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Push device deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// writePushDeviceError maps usecase errors of push devices to HTTP responses
+func writePushDeviceError(c *gin.Context, requestID string, userID uint, err error, message string) {
+	statusCode := http.StatusInternalServerError
+	errorMessage := message
+
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = "Push device not found"
+	}
+
+	if statusCode == http.StatusInternalServerError {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error(message)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
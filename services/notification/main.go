@@ -2,25 +2,42 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"tachyon-messenger/services/notification/email"
 	"tachyon-messenger/services/notification/handlers"
 	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/services/notification/push"
 	"tachyon-messenger/services/notification/repository"
 	"tachyon-messenger/services/notification/usecase"
 	"tachyon-messenger/services/notification/worker"
 	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/jobs"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/orgsettings"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/quota"
 	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/switches"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -42,6 +59,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Resolve other services through the registry, endpoints are refreshed in the background
+	registry, err := config.NewRegistryFromEnv(nil)
+	if err != nil {
+		log.Fatalf("Failed to set up service registry: %v", err)
+	}
+	http.DefaultTransport = registry.Transport(http.DefaultTransport)
+	registryCtx, stopRegistry := context.WithCancel(context.Background())
+	defer stopRegistry()
+	go registry.Run(registryCtx)
+
 	// Connect to database
 	db, err := database.Connect(database.DefaultConfig(cfg.Database.URL))
 	if err != nil {
@@ -50,13 +77,10 @@ func main() {
 	defer db.Close()
 
 	// Run GORM migrations
-	if err := db.Migrate(
-		&models.Notification{},
-		&models.NotificationDelivery{},
-		&models.EmailTemplate{},
-		&models.UserNotificationPreference{},
-		&models.NotificationTemplate{},
-	); err != nil {
+	if err := db.Migrate(models.Models()...); err != nil {
+		log.Fatalf("Failed to run GORM migrations: %v", err)
+	}
+	if err := db.Migrate(jobs.Models()...); err != nil {
 		log.Fatalf("Failed to run GORM migrations: %v", err)
 	}
 
@@ -69,6 +93,12 @@ func main() {
 	}
 	defer redisClient.Close()
 
+	// Kill switches set by administrators through the gateway
+	switchStore := switches.NewStore(redisClient, 0)
+
+	// Per-user quotas of expensive endpoints, shared with other services
+	quotas := quota.NewFromEnv(redisClient)
+
 	log.Info("Redis connected successfully")
 
 	// Set Gin mode based on environment
@@ -76,10 +106,18 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Validate request DTOs with shared rules
+	validation.Install()
+
 	// Initialize email sender
 	var emailSender email.EmailSender
 	if isEmailEnabled() {
 		emailConfig := email.GetSMTPConfigFromEnv()
+		if password, err := cfg.Secrets.Get(context.Background(), config.SecretSMTPPassword); err != nil {
+			log.Warnf("Failed to get SMTP password from %s: %v", cfg.Secrets.Backend(), err)
+		} else if password != "" {
+			emailConfig.Password = password
+		}
 		emailSender, err = email.NewEmailSender(emailConfig)
 		if err != nil {
 			log.Warnf("Failed to initialize email sender: %v", err)
@@ -97,19 +135,50 @@ func main() {
 		log.Info("Email notifications disabled by configuration")
 	}
 
+	// Initialize push sender, push deliveries fail without a gateway
+	pushSender := push.NewGatewaySender(push.GetGatewayConfigFromEnv())
+	if pushSender == nil {
+		log.Info("Push notifications disabled: PUSH_GATEWAY_URL is not set")
+	}
+
 	// Initialize repositories
 	notificationRepo := repository.NewNotificationRepository(db)
 
 	// Create JWT config
 	jwtConfig := middleware.DefaultJWTConfig(cfg.JWT.Secret)
 
+	// Network restrictions of admin endpoints
+	adminAccess, err := middleware.LoadAdminAccessConfig()
+	if err != nil {
+		log.Fatalf("Failed to load admin access config: %v", err)
+	}
+
+	// Organization settings from the user service
+	orgSettings := orgsettings.NewClient(registry.BaseURL(config.UserService), 0)
+
+	// Activity of users in other services for daily digests
+	digestStats := usecase.NewHTTPDigestStatsSource(
+		registry.BaseURL(config.ChatService),
+		registry.BaseURL(config.TaskService),
+		registry.BaseURL(config.CalendarService),
+	)
+
 	// Initialize usecases
-	notificationUC := usecase.NewNotificationUsecase(notificationRepo, emailSender)
+	notificationUC := usecase.NewNotificationUsecase(notificationRepo, emailSender, pushSender, getDedupWindow(), redis.NewUnreadCounter(redisClient, 0), redis.NewUserEvents(redisClient, "notifications:events:"), orgSettings, switchStore, digestStats)
 
 	// Initialize background worker
 	workerConfig := worker.DefaultWorkerConfig()
 	workerConfig.WorkerID = fmt.Sprintf("notification-worker-%s", getServerPort())
 	workerConfig.ConcurrentWorkers = getConcurrentWorkers()
+	workerConfig.MinConcurrentWorkers = getWorkerLimit("NOTIFICATION_MIN_WORKERS", workerConfig.ConcurrentWorkers)
+	workerConfig.MaxConcurrentWorkers = getWorkerLimit("NOTIFICATION_MAX_WORKERS", workerConfig.ConcurrentWorkers)
+	workerConfig.TargetBacklogPerWorker = getWorkerLimit("NOTIFICATION_TARGET_BACKLOG_PER_WORKER", workerConfig.TargetBacklogPerWorker)
+	workerConfig.BacklogSoftLimit = getWorkerLimit("NOTIFICATION_BACKLOG_SOFT_LIMIT", workerConfig.BacklogSoftLimit)
+	workerConfig.BacklogHardLimit = getWorkerLimit("NOTIFICATION_BACKLOG_HARD_LIMIT", workerConfig.BacklogHardLimit)
+	workerConfig.RedisKeyPrefix = getQueueKey("NOTIFICATION_REDIS_KEY_PREFIX", workerConfig.RedisKeyPrefix)
+	workerConfig.QueueName = getQueueKey("NOTIFICATION_QUEUE_NAME", workerConfig.QueueName)
+	workerConfig.RetryQueueName = getQueueKey("NOTIFICATION_RETRY_QUEUE_NAME", workerConfig.RetryQueueName)
+	workerConfig.ScheduledQueueName = getQueueKey("NOTIFICATION_SCHEDULED_QUEUE_NAME", workerConfig.ScheduledQueueName)
 
 	notificationWorker := worker.NewNotificationWorker(notificationUC, redisClient, workerConfig)
 
@@ -129,8 +198,11 @@ func main() {
 	// Setup common middleware
 	setupCommonMiddleware(router)
 
+	// Background job scheduler
+	scheduler := jobs.NewScheduler("notification", db, redisClient)
+
 	// Setup routes
-	setupRoutes(router, notificationHandler, jwtConfig, notificationWorker, redisClient, workerConfig, notificationUC)
+	setupRoutes(router, notificationHandler, jwtConfig, notificationWorker, redisClient, workerConfig, notificationUC, scheduler, switchStore, quotas, orgSettings, adminAccess)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -147,7 +219,7 @@ func main() {
 	}()
 
 	// Start background tasks
-	startBackgroundTasks(notificationUC, notificationWorker)
+	startBackgroundTasks(scheduler, notificationUC, orgSettings, worker.NewQueueManager(redisClient, workerConfig))
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
@@ -169,6 +241,9 @@ func main() {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Wait for running background jobs
+	scheduler.Stop()
+
 	log.Info("Notification service stopped")
 }
 
@@ -184,6 +259,10 @@ func setupCommonMiddleware(router *gin.Engine) {
 
 	// Request ID middleware
 	router.Use(requestid.New())
+	router.Use(middleware.CorrelationMiddleware())
+
+	// Request body size limit
+	router.Use(middleware.BodyLimitMiddleware(middleware.DefaultBodyLimitConfig()))
 
 	// CORS middleware
 	router.Use(func(c *gin.Context) {
@@ -194,7 +273,7 @@ func setupCommonMiddleware(router *gin.Engine) {
 
 		c.Header("Access-Control-Allow-Origin", origin)
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-Correlation-ID")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
@@ -225,81 +304,218 @@ func setupRoutes(
 	redisClient *redis.Client,
 	workerConfig *worker.WorkerConfig,
 	notificationUC usecase.NotificationUsecase,
+	scheduler *jobs.Scheduler,
+	switchStore *switches.Store,
+	quotas *quota.Quotas,
+	orgSettings *orgsettings.Client,
+	adminAccess *middleware.AdminAccessConfig,
 ) {
+	// Concurrency limits of route groups, requests over them are shed with 503.
+	// Admin routes are not limited, so deliveries can be managed under load.
+	limits := middleware.NewConcurrencyLimits("notification-service")
+
 	// Health check endpoint
-	router.GET("/health", healthHandler)
+	router.GET("/health", healthHandler(switchStore, limits))
+
+	// Worker and concurrency metrics for HorizontalPodAutoscaler
+	router.GET("/metrics", createMetricsHandler(notificationWorker, limits))
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 
+	// Real-time stream of notification events, long-lived so it is not counted by concurrency limits
+	v1.GET("/notifications/stream", middleware.JWTMiddleware(jwtConfig), notificationHandler.StreamEvents) // GET /api/v1/notifications/stream
+
 	// Protected notification routes (require JWT authentication)
 	notifications := v1.Group("/notifications")
+	notifications.Use(limits.Group("api"))
 	notifications.Use(middleware.JWTMiddleware(jwtConfig))
 	{
 		// User notification endpoints
 		notifications.GET("", notificationHandler.GetNotifications)            // GET /api/v1/notifications
-		notifications.GET("/search", notificationHandler.SearchNotifications)  // GET /api/v1/notifications/search
 		notifications.GET("/stats", notificationHandler.GetNotificationStats)  // GET /api/v1/notifications/stats
 		notifications.GET("/unread-count", notificationHandler.GetUnreadCount) // GET /api/v1/notifications/unread-count
+		notifications.GET("/sync", notificationHandler.SyncNotifications)      // GET /api/v1/notifications/sync
 		notifications.GET("/:id", notificationHandler.GetNotificationByID)     // GET /api/v1/notifications/:id
 
+		// Search spends the user's search quota
+		notifications.GET("/search", quotas.Middleware(quota.BucketSearch, quota.PerPage(1, 20)), notificationHandler.SearchNotifications) // GET /api/v1/notifications/search
+
+		// Self-service history export spends the user's export quota
+		notifications.GET("/export", quotas.Middleware(quota.BucketExport, quota.Fixed(1)), notificationHandler.ExportNotifications) // GET /api/v1/notifications/export?format=csv|json
+
 		// Mark as read endpoints
-		notifications.PUT("/:id/read", notificationHandler.MarkAsRead)     // PUT /api/v1/notifications/:id/read
-		notifications.PUT("/read", notificationHandler.MarkMultipleAsRead) // PUT /api/v1/notifications/read
-		notifications.PUT("/read-all", notificationHandler.MarkAllAsRead)  // PUT /api/v1/notifications/read-all
+		notifications.PUT("/:id/read", notificationHandler.MarkAsRead)               // PUT /api/v1/notifications/:id/read
+		notifications.PUT("/read", notificationHandler.MarkMultipleAsRead)           // PUT /api/v1/notifications/read
+		notifications.PUT("/read-all", notificationHandler.MarkAllAsRead)            // PUT /api/v1/notifications/read-all
+		notifications.PUT("/read-by-filter", notificationHandler.MarkAsReadByFilter) // PUT /api/v1/notifications/read-by-filter
+
+		// Opens and clicks of notifications sent with a template experiment variant
+		notifications.POST("/:id/engagement", notificationHandler.TrackEngagement) // POST /api/v1/notifications/:id/engagement
 
 		// User preferences endpoints
-		notifications.GET("/preferences", notificationHandler.GetUserPreferences)         // GET /api/v1/notifications/preferences
-		notifications.PUT("/preferences/:type", notificationHandler.UpdateUserPreference) // PUT /api/v1/notifications/preferences/:type
+		notifications.GET("/preferences", notificationHandler.GetUserPreferences)             // GET /api/v1/notifications/preferences
+		notifications.GET("/preferences/catalog", notificationHandler.GetPresentationCatalog) // GET /api/v1/notifications/preferences/catalog
+		notifications.PUT("/preferences/:type", notificationHandler.UpdateUserPreference)     // PUT /api/v1/notifications/preferences/:type
+
+		// Saved views, applied to the list and search endpoints with view_id
+		notifications.GET("/views", notificationHandler.GetNotificationViews)               // GET /api/v1/notifications/views
+		notifications.POST("/views", notificationHandler.CreateNotificationView)            // POST /api/v1/notifications/views
+		notifications.GET("/views/:view_id", notificationHandler.GetNotificationView)       // GET /api/v1/notifications/views/:view_id
+		notifications.PUT("/views/:view_id", notificationHandler.UpdateNotificationView)    // PUT /api/v1/notifications/views/:view_id
+		notifications.DELETE("/views/:view_id", notificationHandler.DeleteNotificationView) // DELETE /api/v1/notifications/views/:view_id
+
+		// Mobile devices receiving push notifications
+		notifications.GET("/devices", notificationHandler.GetPushDevices)                 // GET /api/v1/notifications/devices
+		notifications.POST("/devices", notificationHandler.RegisterPushDevice)            // POST /api/v1/notifications/devices
+		notifications.DELETE("/devices/:device_id", notificationHandler.DeletePushDevice) // DELETE /api/v1/notifications/devices/:device_id
 	}
 
 	// Admin routes (require admin role)
 	admin := v1.Group("/admin")
+	admin.Use(middleware.AdminAccessMiddleware(adminAccess))
 	admin.Use(middleware.JWTMiddleware(jwtConfig))
 	admin.Use(middleware.RequireRole("admin", "super_admin"))
 	{
 		// Notification management
 		adminNotifications := admin.Group("/notifications")
 		{
-			adminNotifications.POST("/send", createSendNotificationHandler(notificationWorker))           // POST /api/v1/admin/notifications/send
-			adminNotifications.POST("/send-bulk", createSendBulkNotificationHandler(notificationWorker))  // POST /api/v1/admin/notifications/send-bulk
-			adminNotifications.POST("/announcement", createSystemAnnouncementHandler(notificationWorker)) // POST /api/v1/admin/notifications/announcement
-			adminNotifications.DELETE("/cleanup", createCleanupHandler(notificationUC))                   // DELETE /api/v1/admin/notifications/cleanup
+			adminNotifications.POST("/send", createSendNotificationHandler(notificationUC, notificationWorker))           // POST /api/v1/admin/notifications/send
+			adminNotifications.POST("/send-bulk", createSendBulkNotificationHandler(notificationWorker))                  // POST /api/v1/admin/notifications/send-bulk
+			adminNotifications.POST("/announcement", createSystemAnnouncementHandler(notificationUC, notificationWorker)) // POST /api/v1/admin/notifications/announcement
+			adminNotifications.DELETE("/cleanup", createCleanupHandler(notificationUC, orgSettings))                      // DELETE /api/v1/admin/notifications/cleanup
+			adminNotifications.POST("/test", createTestNotificationHandler(notificationUC))                               // POST /api/v1/admin/notifications/test
+			adminNotifications.GET("/query", createQueryNotificationsHandler(notificationUC))                             // GET /api/v1/admin/notifications/query
+			adminNotifications.POST("/resend", createResendNotificationsHandler(notificationUC, notificationWorker))      // POST /api/v1/admin/notifications/resend
+
+			// Ops dashboard: stats, queues, worker heartbeats and delivery failures in one call
+			adminNotifications.GET("/dashboard", createDashboardHandler(notificationUC, redisClient, workerConfig)) // GET /api/v1/admin/notifications/dashboard
+		}
+
+		// Support inspection of notifications received by a user, recorded in the audit log
+		adminUsers := admin.Group("/users")
+		{
+			adminUsers.GET("/:id/notifications", middleware.LogAdminAction("inspect_user_notifications"), createUserNotificationsHandler(notificationUC)) // GET /api/v1/admin/users/:id/notifications
 		}
 
 		// Worker management
 		adminWorker := admin.Group("/worker")
 		{
-			adminWorker.GET("/stats", createWorkerStatsHandler(notificationWorker))                // GET /api/v1/admin/worker/stats
-			adminWorker.GET("/queues", createQueueStatsHandler(redisClient, workerConfig))         // GET /api/v1/admin/worker/queues
-			adminWorker.POST("/queues/purge", createPurgeQueuesHandler(redisClient, workerConfig)) // POST /api/v1/admin/worker/queues/purge
-			adminWorker.POST("/queues/requeue", createRequeueHandler(redisClient, workerConfig))   // POST /api/v1/admin/worker/queues/requeue
+			adminWorker.GET("/stats", createWorkerStatsHandler(notificationWorker))                    // GET /api/v1/admin/worker/stats
+			adminWorker.GET("/queues", createQueueStatsHandler(redisClient, workerConfig))             // GET /api/v1/admin/worker/queues
+			adminWorker.GET("/claims", createTaskClaimsHandler(redisClient, workerConfig))             // GET /api/v1/admin/worker/claims
+			adminWorker.POST("/queues/purge", createPurgeQueuesHandler(redisClient, workerConfig))     // POST /api/v1/admin/worker/queues/purge
+			adminWorker.POST("/queues/requeue", createRequeueHandler(redisClient, workerConfig))       // POST /api/v1/admin/worker/queues/requeue
+			adminWorker.POST("/queues/migrate", createMigrateQueuesHandler(redisClient, workerConfig)) // POST /api/v1/admin/worker/queues/migrate
+		}
+
+		// Outbound delivery hold for provider outages
+		adminDeliveryHold := admin.Group("/delivery-hold")
+		{
+			adminDeliveryHold.GET("", createDeliveryHoldStatusHandler(notificationUC))                  // GET /api/v1/admin/delivery-hold
+			adminDeliveryHold.PUT("", createEnableDeliveryHoldHandler(notificationUC, switchStore))     // PUT /api/v1/admin/delivery-hold
+			adminDeliveryHold.DELETE("", createDisableDeliveryHoldHandler(notificationUC, switchStore)) // DELETE /api/v1/admin/delivery-hold
+			adminDeliveryHold.POST("/release", createReleaseHeldDeliveriesHandler(notificationUC))      // POST /api/v1/admin/delivery-hold/release
+			adminDeliveryHold.POST("/drain", createDrainHeldDeliveriesHandler(notificationUC))          // POST /api/v1/admin/delivery-hold/drain
+		}
+
+		// Emails waiting for the SMTP server to come back
+		admin.GET("/email-outbox", createEmailOutboxStatusHandler(notificationUC)) // GET /api/v1/admin/email-outbox
+
+		// Addresses suppressed after email bounces and complaints
+		adminEmailSuppressions := admin.Group("/email-suppressions")
+		{
+			adminEmailSuppressions.GET("", createListEmailSuppressionsHandler(notificationUC))        // GET /api/v1/admin/email-suppressions
+			adminEmailSuppressions.DELETE("/:id", createClearEmailSuppressionHandler(notificationUC)) // DELETE /api/v1/admin/email-suppressions/:id
+		}
+
+		// Organization default notification preferences, applied beneath user preferences
+		adminPreferences := admin.Group("/notification-preferences")
+		{
+			adminPreferences.GET("/defaults", createListPreferenceDefaultsHandler(notificationUC))           // GET /api/v1/admin/notification-preferences/defaults
+			adminPreferences.PUT("/defaults/:type", createSetPreferenceDefaultHandler(notificationUC))       // PUT /api/v1/admin/notification-preferences/defaults/:type
+			adminPreferences.DELETE("/defaults/:type", createDeletePreferenceDefaultHandler(notificationUC)) // DELETE /api/v1/admin/notification-preferences/defaults/:type
+			adminPreferences.POST("/reset", createResetUserPreferencesHandler(notificationUC))               // POST /api/v1/admin/notification-preferences/reset
+			adminPreferences.GET("/export", createExportUserPreferencesHandler(notificationUC))              // GET /api/v1/admin/notification-preferences/export
+		}
+
+		// Channel fallback chains by priority, e.g. push, then SMS and email while unread
+		adminFallbacks := admin.Group("/notification-fallbacks")
+		{
+			adminFallbacks.GET("", createListFallbackPoliciesHandler(notificationUC))              // GET /api/v1/admin/notification-fallbacks
+			adminFallbacks.PUT("/:priority", createSetFallbackPolicyHandler(notificationUC))       // PUT /api/v1/admin/notification-fallbacks/:priority
+			adminFallbacks.DELETE("/:priority", createDeleteFallbackPolicyHandler(notificationUC)) // DELETE /api/v1/admin/notification-fallbacks/:priority
+		}
+
+		// A/B tests of template subject lines
+		adminExperiments := admin.Group("/template-experiments")
+		{
+			adminExperiments.GET("", createListTemplateExperimentsHandler(notificationUC))                   // GET /api/v1/admin/template-experiments
+			adminExperiments.PUT("/:template", createSetTemplateExperimentHandler(notificationUC))           // PUT /api/v1/admin/template-experiments/:template
+			adminExperiments.DELETE("/:template", createDeleteTemplateExperimentHandler(notificationUC))     // DELETE /api/v1/admin/template-experiments/:template
+			adminExperiments.GET("/:template/report", createTemplateExperimentReportHandler(notificationUC)) // GET /api/v1/admin/template-experiments/:template/report
+		}
+
+		// Announcement campaigns: series of announcement sends to audience segments
+		adminCampaigns := admin.Group("/announcement-campaigns")
+		{
+			adminCampaigns.GET("", createListCampaignsHandler(notificationUC))                            // GET /api/v1/admin/announcement-campaigns
+			adminCampaigns.POST("", createCampaignHandler(notificationUC))                                // POST /api/v1/admin/announcement-campaigns
+			adminCampaigns.GET("/calendar", createCampaignCalendarHandler(notificationUC))                // GET /api/v1/admin/announcement-campaigns/calendar
+			adminCampaigns.GET("/:id", createGetCampaignHandler(notificationUC))                          // GET /api/v1/admin/announcement-campaigns/:id
+			adminCampaigns.POST("/:id/cancel", createCancelCampaignHandler(notificationUC))               // POST /api/v1/admin/announcement-campaigns/:id/cancel
+			adminCampaigns.DELETE("/:id/sends/:send_id", createCancelCampaignSendHandler(notificationUC)) // DELETE /api/v1/admin/announcement-campaigns/:id/sends/:send_id
 		}
 
 		// System statistics
 		admin.GET("/stats", createSystemStatsHandler(notificationUC)) // GET /api/v1/admin/stats
+
+		// Background job management
+		jobs.RegisterRoutes(admin, scheduler) // /api/v1/admin/jobs
+	}
+
+	// Email bounce and complaint webhooks (signed by the mail server or provider)
+	emailWebhooks := v1.Group("/webhooks/email")
+	emailWebhooks.Use(limits.Group("webhooks"))
+	emailWebhooks.Use(emailWebhookAuthMiddleware(getEmailWebhookSecret()))
+	{
+		emailWebhooks.POST("/bounce", createEmailBounceHandler(notificationUC))     // POST /api/v1/webhooks/email/bounce
+		emailWebhooks.POST("/feedback", createEmailFeedbackHandler(notificationUC)) // POST /api/v1/webhooks/email/feedback
 	}
 
 	// Internal endpoints (for service-to-service communication)
 	internal := v1.Group("/internal")
+	internal.Use(limits.Group("internal"))
 	{
-		internal.POST("/notifications/task", createAddTaskHandler(notificationWorker))            // POST /api/v1/internal/notifications/task
-		internal.POST("/notifications/scheduled", createScheduledTaskHandler(notificationWorker)) // POST /api/v1/internal/notifications/scheduled
+		internal.POST("/notifications/task", createAddTaskHandler(notificationWorker))             // POST /api/v1/internal/notifications/task
+		internal.GET("/notifications/task/:id", createTaskStatusHandler(notificationWorker))       // GET /api/v1/internal/notifications/task/:id
+		internal.GET("/notifications/backlog", createBacklogHandler(notificationWorker))           // GET /api/v1/internal/notifications/backlog
+		internal.POST("/notifications/scheduled", createScheduledTaskHandler(notificationWorker))  // POST /api/v1/internal/notifications/scheduled
+		internal.POST("/notifications/resolve", createResolveNotificationsHandler(notificationUC)) // POST /api/v1/internal/notifications/resolve
+		internal.POST("/users/merge", createMergeUsersHandler(notificationUC))                     // POST /api/v1/internal/users/merge
 	}
 }
 
-// Health check handler
-func healthHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "healthy",
-		"service":   "notification-service",
-		"timestamp": time.Now().Format(time.RFC3339),
-		"version":   getServiceVersion(),
-	})
+// Health check handler, reports kill switches active for the service and load of its route groups
+func healthHandler(switchStore *switches.Store, limits *middleware.ConcurrencyLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := gin.H{
+			"status":      "healthy",
+			"service":     "notification-service",
+			"timestamp":   time.Now().Format(time.RFC3339),
+			"version":     getServiceVersion(),
+			"concurrency": limits.Stats(),
+		}
+		if active := switchStore.Active("notification-service"); len(active) > 0 {
+			response["kill_switches"] = active
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
 }
 
-// startBackgroundTasks starts background maintenance tasks
-func startBackgroundTasks(notificationUC usecase.NotificationUsecase, notificationWorker *worker.Worker) {
+// startBackgroundTasks registers background maintenance jobs and starts the scheduler
+func startBackgroundTasks(scheduler *jobs.Scheduler, notificationUC usecase.NotificationUsecase, orgSettings *orgsettings.Client, queueManager *worker.QueueManager) {
 	// Initialize logger for background tasks
 	log := logger.New(&logger.Config{
 		Level:       getLogLevel(),
@@ -307,59 +523,125 @@ func startBackgroundTasks(notificationUC usecase.NotificationUsecase, notificati
 		Environment: os.Getenv("ENVIRONMENT"),
 	})
 
-	// Start scheduled notification processor
-	go func() {
-		ticker := time.NewTicker(1 * time.Minute) // Check every minute
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				if err := notificationUC.ProcessScheduledNotifications(); err != nil {
-					log.WithField("error", err.Error()).Error("Failed to process scheduled notifications")
-				}
-			}
-		}
-	}()
-
-	// Start failed delivery retry processor
-	go func() {
-		ticker := time.NewTicker(5 * time.Minute) // Check every 5 minutes
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				if err := notificationUC.RetryFailedDeliveries(); err != nil {
-					log.WithField("error", err.Error()).Error("Failed to retry failed deliveries")
-				}
-			}
-		}
-	}()
-
-	// Start old notification cleanup (daily)
-	go func() {
-		ticker := time.NewTicker(24 * time.Hour) // Run daily
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				// Clean up notifications older than 30 days
-				cutoffDate := time.Now().Add(-30 * 24 * time.Hour)
+	backgroundJobs := []jobs.Job{
+		// Send scheduled notifications that are due
+		{
+			Name:     "process_scheduled_notifications",
+			Schedule: "* * * * *",
+			Run: func(ctx context.Context) error {
+				return notificationUC.ProcessScheduledNotifications()
+			},
+		},
+		// Send emails that wait in the outbox for the SMTP server to come back
+		{
+			Name:     "process_email_outbox",
+			Schedule: "* * * * *",
+			Run: func(ctx context.Context) error {
+				sent, err := notificationUC.ProcessEmailOutbox()
+				jobs.Report(ctx, "sent_count", sent)
+				return err
+			},
+		},
+		// Deliver announcement campaign sends that are due
+		{
+			Name:     "process_campaign_sends",
+			Schedule: "* * * * *",
+			Run: func(ctx context.Context) error {
+				sent, err := notificationUC.ProcessCampaignSends(time.Now())
+				jobs.Report(ctx, "sent_count", sent)
+				return err
+			},
+		},
+		// Send daily digests to users whose local time has reached the digest hour
+		{
+			Name:     "send_daily_digests",
+			Schedule: "*/15 * * * *",
+			Run: func(ctx context.Context) error {
+				sent, err := notificationUC.SendDailyDigests(time.Now(), getDailyDigestHour())
+				jobs.Report(ctx, "sent_count", sent)
+				return err
+			},
+		},
+		// Retry failed deliveries
+		{
+			Name:     "retry_failed_deliveries",
+			Schedule: "*/5 * * * *",
+			Run: func(ctx context.Context) error {
+				return notificationUC.RetryFailedDeliveries()
+			},
+		},
+		// Clean up notifications older than the notification retention policy
+		{
+			Name:     "cleanup_old_notifications",
+			Schedule: "@daily",
+			Run: func(ctx context.Context) error {
+				cutoffDate := reportRetention(ctx, orgSettings, sharedmodels.RetentionNotifications)
 				deletedCount, err := notificationUC.DeleteOldNotifications(cutoffDate)
 				if err != nil {
-					log.WithField("error", err.Error()).Error("Failed to cleanup old notifications")
-				} else if deletedCount > 0 {
+					return err
+				}
+				jobs.Report(ctx, "deleted_count", deletedCount)
+				if deletedCount > 0 {
 					log.WithField("deleted_count", deletedCount).Info("Cleaned up old notifications")
 				}
-			}
+				return nil
+			},
+		},
+		// Drop dead letter tasks older than their retention policy
+		{
+			Name:     "cleanup_dead_letter_tasks",
+			Schedule: "@hourly",
+			Run: func(ctx context.Context) error {
+				cutoffDate := reportRetention(ctx, orgSettings, sharedmodels.RetentionDeadLetterTasks)
+				removed, err := queueManager.CleanupDeadLetterTasks(ctx, cutoffDate)
+				if err != nil {
+					return err
+				}
+				jobs.Report(ctx, "deleted_count", removed)
+				if removed > 0 {
+					log.WithField("removed_tasks", removed).Info("Cleaned up old dead letter tasks")
+				}
+				return nil
+			},
+		},
+		// Fix drift of cached unread counters
+		{
+			Name:     "reconcile_unread_counts",
+			Schedule: "@every " + getUnreadReconcileInterval().String(),
+			Run: func(ctx context.Context) error {
+				reconciled, err := notificationUC.ReconcileUnreadCounts()
+				if err != nil {
+					return err
+				}
+				if reconciled > 0 {
+					log.WithField("reconciled_count", reconciled).Debug("Reconciled cached unread counts")
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, job := range backgroundJobs {
+		if err := scheduler.Register(job); err != nil {
+			log.Fatalf("Failed to register background jobs: %v", err)
 		}
-	}()
+	}
+	scheduler.Start()
 
 	log.Info("Background tasks started")
 }
 
+// reportRetention returns the cutoff date of a data type and adds its retention policy to the job run report
+func reportRetention(ctx context.Context, orgSettings *orgsettings.Client, dataType sharedmodels.RetentionDataType) time.Time {
+	policy := orgSettings.Get().Retention.Policy(dataType)
+	cutoffDate := time.Now().AddDate(0, 0, -policy.Days)
+
+	jobs.Report(ctx, "data_type", policy.DataType)
+	jobs.Report(ctx, "retention_days", policy.Days)
+	jobs.Report(ctx, "cutoff_date", cutoffDate)
+	return cutoffDate
+}
+
 // Configuration helper functions
 
 func getServerPort() string {
@@ -420,14 +702,64 @@ func getConcurrentWorkers() int {
 	return 5
 }
 
+func getWorkerLimit(envKey string, defaultValue int) int {
+	var count int
+	if _, err := fmt.Sscanf(os.Getenv(envKey), "%d", &count); err == nil && count > 0 {
+		return count
+	}
+	return defaultValue
+}
+
+// getQueueKey returns a Redis key name of the worker queues from environment or the default
+func getQueueKey(envKey, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(envKey)); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDedupWindow() time.Duration {
+	window := os.Getenv("NOTIFICATION_DEDUP_WINDOW")
+	if window == "" {
+		return 5 * time.Minute // Default
+	}
+
+	// "0" disables deduplication
+	if duration, err := time.ParseDuration(window); err == nil && duration >= 0 {
+		return duration
+	}
+	return 5 * time.Minute
+}
+
+func getUnreadReconcileInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("UNREAD_RECONCILE_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 10 * time.Minute // Default
+}
+
+// getDailyDigestHour returns the local hour of users from which daily digests are sent
+func getDailyDigestHour() int {
+	if hour, err := strconv.Atoi(os.Getenv("DAILY_DIGEST_HOUR")); err == nil && hour >= 0 && hour <= 23 {
+		return hour
+	}
+	return 8 // Default
+}
+
 func isEmailEnabled() bool {
 	enabled := os.Getenv("EMAIL_ENABLED")
 	return enabled != "false" && enabled != "0"
 }
 
+func getEmailWebhookSecret() string {
+	return strings.TrimSpace(os.Getenv("EMAIL_WEBHOOK_SECRET"))
+}
+
 // Admin handler creators
 
-func createSendNotificationHandler(w *worker.Worker) gin.HandlerFunc {
+// createSendNotificationHandler queues a notification, or with ?preview=ru|en|all renders
+// its email in the locales without sending it
+func createSendNotificationHandler(notificationUC usecase.NotificationUsecase, w *worker.Worker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req models.CreateNotificationRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -438,6 +770,19 @@ func createSendNotificationHandler(w *worker.Worker) gin.HandlerFunc {
 			return
 		}
 
+		if locales, preview, err := parsePreviewLocales(c.Query("preview")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid preview parameter",
+				"details": err.Error(),
+			})
+			return
+		} else if preview {
+			writeEmailPreviews(c, func() ([]*models.EmailPreview, error) {
+				return notificationUC.PreviewNotificationEmail(&req, locales)
+			})
+			return
+		}
+
 		// Handle priority - if nil, use medium as default
 		priority := models.NotificationPriorityMedium
 		if req.Priority != nil {
@@ -445,7 +790,7 @@ func createSendNotificationHandler(w *worker.Worker) gin.HandlerFunc {
 		}
 
 		task := worker.CreateSingleNotificationTask(&req, priority)
-		if err := w.AddTask(task); err != nil {
+		if err := w.AddTask(c.Request.Context(), task); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Failed to queue notification",
 				"details": err.Error(),
@@ -477,7 +822,7 @@ func createSendBulkNotificationHandler(w *worker.Worker) gin.HandlerFunc {
 		}
 
 		task := worker.CreateBulkNotificationTask(&req, priority)
-		if err := w.AddTask(task); err != nil {
+		if err := w.AddTask(c.Request.Context(), task); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Failed to queue bulk notification",
 				"details": err.Error(),
@@ -493,7 +838,9 @@ func createSendBulkNotificationHandler(w *worker.Worker) gin.HandlerFunc {
 	}
 }
 
-func createSystemAnnouncementHandler(w *worker.Worker) gin.HandlerFunc {
+// createSystemAnnouncementHandler queues an announcement, or with ?preview=ru|en|all renders
+// its email in the locales, with their translations, without sending it
+func createSystemAnnouncementHandler(notificationUC usecase.NotificationUsecase, w *worker.Worker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req usecase.SystemAnnouncementRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -504,8 +851,21 @@ func createSystemAnnouncementHandler(w *worker.Worker) gin.HandlerFunc {
 			return
 		}
 
+		if locales, preview, err := parsePreviewLocales(c.Query("preview")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid preview parameter",
+				"details": err.Error(),
+			})
+			return
+		} else if preview {
+			writeEmailPreviews(c, func() ([]*models.EmailPreview, error) {
+				return notificationUC.PreviewAnnouncementEmail(&req, locales)
+			})
+			return
+		}
+
 		task := worker.CreateSystemAnnouncementTask(&req, req.Priority)
-		if err := w.AddTask(task); err != nil {
+		if err := w.AddTask(c.Request.Context(), task); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Failed to queue system announcement",
 				"details": err.Error(),
@@ -520,12 +880,51 @@ func createSystemAnnouncementHandler(w *worker.Worker) gin.HandlerFunc {
 	}
 }
 
-func createCleanupHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+// parsePreviewLocales parses the preview parameter of send endpoints: a comma-separated list
+// of locales or "all". It reports false when the parameter is not set.
+func parsePreviewLocales(value string) ([]i18n.Locale, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, false, nil
+	}
+	if value == "all" {
+		return i18n.Supported(), true, nil
+	}
+
+	var locales []i18n.Locale
+	for _, part := range strings.Split(value, ",") {
+		locale := i18n.Locale(strings.ToLower(strings.TrimSpace(part)))
+		if !locale.IsValid() {
+			return nil, false, fmt.Errorf("unsupported locale: %s", part)
+		}
+		locales = append(locales, locale)
+	}
+	return locales, true, nil
+}
+
+// writeEmailPreviews responds with rendered email previews
+func writeEmailPreviews(c *gin.Context, render func() ([]*models.EmailPreview, error)) {
+	previews, err := render()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to render email preview",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"previews": previews,
+	})
+}
+
+// createCleanupHandler deletes old notifications, by default those older than the notification retention policy
+func createCleanupHandler(notificationUC usecase.NotificationUsecase, orgSettings *orgsettings.Client) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		daysStr := c.DefaultQuery("days", "30")
+		retentionDays := orgSettings.Get().Retention.Policy(sharedmodels.RetentionNotifications).Days
 		var days int
-		if _, err := fmt.Sscanf(daysStr, "%d", &days); err != nil || days < 1 {
-			days = 30
+		if _, err := fmt.Sscanf(c.Query("days"), "%d", &days); err != nil || days < 1 {
+			days = retentionDays
 		}
 
 		cutoffDate := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
@@ -546,150 +945,1194 @@ func createCleanupHandler(notificationUC usecase.NotificationUsecase) gin.Handle
 	}
 }
 
-func createWorkerStatsHandler(w *worker.Worker) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		stats := w.GetStats()
-		c.JSON(http.StatusOK, gin.H{
-			"worker_stats": stats,
-		})
-	}
-}
-
-func createQueueStatsHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+func createDeliveryHoldStatusHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		queueManager := worker.NewQueueManager(redisClient, workerConfig)
-		stats, err := queueManager.GetQueueStats(c.Request.Context())
+		status, err := notificationUC.GetDeliveryHoldStatus()
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to get queue stats",
+				"error":   "Failed to get delivery hold status",
 				"details": err.Error(),
 			})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"queue_stats": stats,
-		})
+		c.JSON(http.StatusOK, status)
 	}
 }
 
-func createPurgeQueuesHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+// createEnableDeliveryHoldHandler holds email, push and SMS deliveries on all instances.
+// Notifications are still accepted, stored and shown in the app.
+func createEnableDeliveryHoldHandler(notificationUC usecase.NotificationUsecase, switchStore *switches.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		queueManager := worker.NewQueueManager(redisClient, workerConfig)
-		if err := queueManager.PurgeQueues(c.Request.Context()); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to purge queues",
+		var req models.DeliveryHoldRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid request body",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		if _, err := switchStore.Activate(switches.HoldOutboundNotifications, strings.TrimSpace(req.Reason), adminID); err != nil {
+			writeDeliveryHoldError(c, "Failed to enable delivery hold", err)
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"admin_id": adminID,
+			"reason":   req.Reason,
+		}).Warn("Outbound notification deliveries held")
+
+		respondDeliveryHoldStatus(c, notificationUC, "Outbound deliveries are held")
+	}
+}
+
+// createDisableDeliveryHoldHandler lets new deliveries through again. Deliveries held so far
+// stay queued until released or drained, so a recovering provider is not flooded.
+func createDisableDeliveryHoldHandler(notificationUC usecase.NotificationUsecase, switchStore *switches.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := switchStore.Deactivate(switches.HoldOutboundNotifications); err != nil {
+			writeDeliveryHoldError(c, "Failed to disable delivery hold", err)
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		logger.WithField("admin_id", adminID).Warn("Outbound notification deliveries resumed")
+
+		respondDeliveryHoldStatus(c, notificationUC, "Outbound deliveries resumed, release held deliveries to send them")
+	}
+}
+
+func createReleaseHeldDeliveriesHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.ReleaseHeldDeliveriesRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid request body",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		response, err := notificationUC.ReleaseHeldDeliveries(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to release held deliveries",
 				"details": err.Error(),
 			})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "All queues purged successfully",
-		})
+		c.JSON(http.StatusOK, response)
 	}
 }
 
-func createRequeueHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+func createDrainHeldDeliveriesHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		limitStr := c.DefaultQuery("limit", "100")
-		var limit int
-		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil || limit < 1 {
-			limit = 100
+		var req models.DrainHeldDeliveriesRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid request body",
+					"details": err.Error(),
+				})
+				return
+			}
 		}
 
-		queueManager := worker.NewQueueManager(redisClient, workerConfig)
-		requeued, err := queueManager.RequeueDeadLetterTasks(c.Request.Context(), limit)
+		drained, err := notificationUC.DrainHeldDeliveries(&req)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to requeue tasks",
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to drain held deliveries",
 				"details": err.Error(),
 			})
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"message":        "Tasks requeued successfully",
-			"requeued_count": requeued,
+			"message": "Held deliveries drained",
+			"drained": drained,
 		})
 	}
 }
 
-func createSystemStatsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+// respondDeliveryHoldStatus responds with a message and the current delivery hold status
+func respondDeliveryHoldStatus(c *gin.Context, notificationUC usecase.NotificationUsecase, message string) {
+	status, err := notificationUC.GetDeliveryHoldStatus()
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       message,
+		"delivery_hold": status,
+	})
+}
+
+// writeDeliveryHoldError maps switch store errors of the delivery hold to HTTP responses
+func writeDeliveryHoldError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	if errors.Is(err, switches.ErrUnavailable) {
+		statusCode = http.StatusServiceUnavailable
+	}
+	c.JSON(statusCode, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
+
+// createEmailOutboxStatusHandler reports the SMTP server state and the number of emails waiting for it
+func createEmailOutboxStatusHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		stats, err := notificationUC.GetSystemStats()
+		status, err := notificationUC.GetEmailOutboxStatus()
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to get system stats",
+				"error":   "Failed to get email outbox status",
 				"details": err.Error(),
 			})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"system_stats": stats,
-		})
+		c.JSON(http.StatusOK, status)
 	}
 }
 
-func createAddTaskHandler(w *worker.Worker) gin.HandlerFunc {
+func createListEmailSuppressionsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var task worker.NotificationTask
-		if err := c.ShouldBindJSON(&task); err != nil {
+		var filter models.EmailSuppressionFilter
+		if err := c.ShouldBindQuery(&filter); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid task data",
+				"error":   "Invalid query parameters",
 				"details": err.Error(),
 			})
 			return
 		}
 
-		if err := w.AddTask(&task); err != nil {
+		suppressions, total, err := notificationUC.ListEmailSuppressions(&filter)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to add task",
+				"error":   "Failed to list email suppressions",
 				"details": err.Error(),
 			})
 			return
 		}
 
-		c.JSON(http.StatusAccepted, gin.H{
-			"message": "Task added successfully",
-			"task_id": task.ID,
+		c.JSON(http.StatusOK, gin.H{
+			"suppressions": suppressions,
+			"total":        total,
+			"limit":        filter.Limit,
+			"offset":       filter.Offset,
 		})
 	}
 }
 
-func createScheduledTaskHandler(w *worker.Worker) gin.HandlerFunc {
+// createClearEmailSuppressionHandler lets email through to a suppressed address again
+func createClearEmailSuppressionHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var req struct {
-			Notification *models.CreateNotificationRequest `json:"notification"`
-			ScheduledAt  time.Time                         `json:"scheduled_at"`
-			Priority     models.NotificationPriority       `json:"priority"`
-		}
-
-		if err := c.ShouldBindJSON(&req); err != nil {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || id == 0 {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request body",
-				"details": err.Error(),
+				"error": "Invalid suppression ID",
 			})
 			return
 		}
 
-		if req.Notification == nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Notification data is required",
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		suppression, err := notificationUC.ClearEmailSuppression(uint(id), adminID)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "not found") {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to clear email suppression",
+				"details": err.Error(),
 			})
 			return
 		}
 
-		if req.ScheduledAt.Before(time.Now()) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Scheduled time must be in the future",
-			})
-			return
-		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Email suppression cleared",
+			"suppression": suppression,
+		})
+	}
+}
+
+// createListPreferenceDefaultsHandler lists organization default preferences by notification type
+func createListPreferenceDefaultsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defaults, err := notificationUC.GetPreferenceDefaults()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get preference defaults",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"defaults": defaults,
+		})
+	}
+}
+
+// createSetPreferenceDefaultHandler sets the organization default preference of a notification type
+func createSetPreferenceDefaultHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.PreferenceDefaultRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		preferenceDefault, err := notificationUC.SetPreferenceDefault(models.NotificationType(c.Param("type")), &req, adminID)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to set preference default",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Preference default updated",
+			"default": preferenceDefault,
+		})
+	}
+}
+
+// createDeletePreferenceDefaultHandler returns a notification type to the built-in defaults
+func createDeletePreferenceDefaultHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := notificationUC.DeletePreferenceDefault(models.NotificationType(c.Param("type"))); err != nil {
+			statusCode := http.StatusInternalServerError
+			switch {
+			case strings.Contains(err.Error(), "validation failed"):
+				statusCode = http.StatusBadRequest
+			case strings.Contains(err.Error(), "not found"):
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to delete preference default",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Preference default deleted",
+		})
+	}
+}
+
+// createResetUserPreferencesHandler resets preferences of the selected users to the organization defaults
+func createResetUserPreferencesHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.ResetUserPreferencesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		reset, err := notificationUC.ResetUserPreferences(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to reset user preferences",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "User preferences reset to defaults",
+			"users":   len(req.UserIDs),
+			"reset":   reset,
+		})
+	}
+}
+
+// createExportUserPreferencesHandler exports effective preferences of users per notification type
+func createExportUserPreferencesHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.PreferenceExportRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		preferences, err := notificationUC.ExportUserPreferences(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to export user preferences",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"preferences": preferences,
+			"total":       len(preferences),
+		})
+	}
+}
+
+// createListFallbackPoliciesHandler lists channel fallback policies by priority
+func createListFallbackPoliciesHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies, err := notificationUC.GetFallbackPolicies()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get fallback policies",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"policies": policies,
+		})
+	}
+}
+
+// createSetFallbackPolicyHandler sets the channel fallback policy of a priority
+func createSetFallbackPolicyHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.FallbackPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		policy, err := notificationUC.SetFallbackPolicy(models.NotificationPriority(c.Param("priority")), &req, adminID)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to set fallback policy",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Fallback policy updated",
+			"policy":  policy,
+		})
+	}
+}
+
+// createDeleteFallbackPolicyHandler removes the channel fallback policy of a priority
+func createDeleteFallbackPolicyHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := notificationUC.DeleteFallbackPolicy(models.NotificationPriority(c.Param("priority"))); err != nil {
+			statusCode := http.StatusInternalServerError
+			switch {
+			case strings.Contains(err.Error(), "validation failed"):
+				statusCode = http.StatusBadRequest
+			case strings.Contains(err.Error(), "not found"):
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to delete fallback policy",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Fallback policy deleted",
+		})
+	}
+}
+
+// createListTemplateExperimentsHandler lists subject experiments of templates
+func createListTemplateExperimentsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		experiments, err := notificationUC.GetTemplateExperiments()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get template experiments",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"experiments": experiments,
+		})
+	}
+}
+
+// createSetTemplateExperimentHandler sets the subject experiment of a template
+func createSetTemplateExperimentHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.TemplateExperimentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		experiment, err := notificationUC.SetTemplateExperiment(c.Param("template"), &req, adminID)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to set template experiment",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Template experiment updated",
+			"experiment": experiment,
+		})
+	}
+}
+
+// createDeleteTemplateExperimentHandler removes the subject experiment of a template
+func createDeleteTemplateExperimentHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := notificationUC.DeleteTemplateExperiment(c.Param("template")); err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "not found") {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to delete template experiment",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Template experiment deleted",
+		})
+	}
+}
+
+// createTemplateExperimentReportHandler compares sends, opens and clicks of the variants of a template
+func createTemplateExperimentReportHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report, err := notificationUC.GetTemplateExperimentReport(c.Param("template"))
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "not found") {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to get template experiment report",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"report": report,
+		})
+	}
+}
+
+// createListCampaignsHandler lists announcement campaigns
+func createListCampaignsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter models.CampaignFilterRequest
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		campaigns, total, err := notificationUC.ListCampaigns(&filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to list campaigns",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"campaigns": campaigns,
+			"total":     total,
+			"limit":     filter.Limit,
+			"offset":    filter.Offset,
+		})
+	}
+}
+
+// createCampaignHandler schedules an announcement campaign and warns about conflicts with other campaigns
+func createCampaignHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateCampaignRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		adminID, _ := middleware.GetUserIDFromContext(c)
+		campaign, conflicts, err := notificationUC.CreateCampaign(&req, adminID)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to schedule campaign",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message":   "Campaign scheduled",
+			"campaign":  campaign,
+			"conflicts": conflicts,
+		})
+	}
+}
+
+// createCampaignCalendarHandler shows scheduled announcement sends by day with conflict warnings
+func createCampaignCalendarHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CampaignCalendarRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		calendar, err := notificationUC.GetCampaignCalendar(&req, time.Now())
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to get campaign calendar",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"calendar": calendar,
+		})
+	}
+}
+
+// createGetCampaignHandler returns an announcement campaign with its sends
+func createGetCampaignHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid campaign ID",
+			})
+			return
+		}
+
+		campaign, err := notificationUC.GetCampaign(uint(id))
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "not found") {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to get campaign",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"campaign": campaign,
+		})
+	}
+}
+
+// createCancelCampaignHandler cancels pending sends of an announcement campaign
+func createCancelCampaignHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid campaign ID",
+			})
+			return
+		}
+
+		campaign, err := notificationUC.CancelCampaign(uint(id))
+		if err != nil {
+			c.JSON(campaignErrorStatus(err), gin.H{
+				"error":   "Failed to cancel campaign",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Campaign cancelled",
+			"campaign": campaign,
+		})
+	}
+}
+
+// createCancelCampaignSendHandler cancels one pending send of an announcement campaign
+func createCancelCampaignSendHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid campaign ID",
+			})
+			return
+		}
+		sendID, err := strconv.ParseUint(c.Param("send_id"), 10, 32)
+		if err != nil || sendID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid send ID",
+			})
+			return
+		}
+
+		campaign, err := notificationUC.CancelCampaignSend(uint(id), uint(sendID))
+		if err != nil {
+			c.JSON(campaignErrorStatus(err), gin.H{
+				"error":   "Failed to cancel campaign send",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Campaign send cancelled",
+			"campaign": campaign,
+		})
+	}
+}
+
+// campaignErrorStatus maps errors of campaign cancellation to HTTP statuses
+func campaignErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "cannot"):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// emailWebhookAuthMiddleware verifies the HMAC-SHA256 signature of email webhooks
+// (X-Tachyon-Signature: sha256=<hex>). Webhooks are disabled while no secret is configured.
+func emailWebhookAuthMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Email webhooks are not configured",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if middleware.IsBodyTooLarge(err) {
+				middleware.AbortBodyTooLarge(c)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to read request body",
+			})
+			return
+		}
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(c.GetHeader("X-Tachyon-Signature")), []byte(expected)) {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestid.Get(c),
+				"path":       c.Request.URL.Path,
+				"client_ip":  c.ClientIP(),
+			}).Warn("Rejected email webhook with invalid signature")
+
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid signature",
+			})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// createEmailBounceHandler processes a bounce message (RFC 3464 delivery status notification)
+// piped from the mail server as the raw request body
+func createEmailBounceHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := notificationUC.ProcessBounceMessage(c.Request.Body)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to process bounce message",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// createEmailFeedbackHandler processes bounce and complaint events reported by the email provider
+func createEmailFeedbackHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.EmailFeedbackRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		result, err := notificationUC.ProcessEmailFeedback(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to process email feedback",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func createTestNotificationHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req usecase.TestNotificationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		userID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "User not authenticated",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		// Test notifications always go to the calling admin
+		req.UserID = userID
+		req.Recipient = c.GetString("user_email")
+
+		result, err := notificationUC.SendTestNotification(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Failed to send test notification",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"test_result": result,
+		})
+	}
+}
+
+func createWorkerStatsHandler(w *worker.Worker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats := w.GetStats()
+		c.JSON(http.StatusOK, gin.H{
+			"worker_stats": stats,
+		})
+	}
+}
+
+func createMetricsHandler(w *worker.Worker, limits *middleware.ConcurrencyLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4")
+		c.Status(http.StatusOK)
+		if err := w.WriteMetrics(c.Writer); err != nil {
+			logger.WithField("error", err.Error()).Error("Failed to write worker metrics")
+			return
+		}
+		if err := limits.WriteMetrics(c.Writer); err != nil {
+			logger.WithField("error", err.Error()).Error("Failed to write concurrency metrics")
+		}
+	}
+}
+
+func createQueueStatsHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		queueManager := worker.NewQueueManager(redisClient, workerConfig)
+		stats, err := queueManager.GetQueueStats(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get queue stats",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"queue_stats": stats,
+		})
+	}
+}
+
+// createTaskClaimsHandler lists tasks held by workers with their lease expiry, so tasks of a
+// crashed worker can be told apart from slow ones
+func createTaskClaimsHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		queueManager := worker.NewQueueManager(redisClient, workerConfig)
+		claims, err := queueManager.GetClaims(c.Request.Context(), time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get task claims",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"claims": claims,
+			"total":  len(claims),
+		})
+	}
+}
+
+func createPurgeQueuesHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		queueManager := worker.NewQueueManager(redisClient, workerConfig)
+		if err := queueManager.PurgeQueues(c.Request.Context()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to purge queues",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "All queues purged successfully",
+		})
+	}
+}
+
+func createRequeueHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limitStr := c.DefaultQuery("limit", "100")
+		var limit int
+		if _, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil || limit < 1 {
+			limit = 100
+		}
+
+		queueManager := worker.NewQueueManager(redisClient, workerConfig)
+		requeued, err := queueManager.RequeueDeadLetterTasks(c.Request.Context(), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to requeue tasks",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":        "Tasks requeued successfully",
+			"requeued_count": requeued,
+		})
+	}
+}
+
+// createMigrateQueuesHandler moves tasks from queue keys of a previous prefix or queue names
+// into the current ones, refusing while workers still consume the old keys
+func createMigrateQueuesHandler(redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req worker.QueueMigrationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		queueManager := worker.NewQueueManager(redisClient, workerConfig)
+		report, err := queueManager.MigrateQueues(c.Request.Context(), &req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			} else if strings.Contains(err.Error(), "cannot") {
+				statusCode = http.StatusConflict
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to migrate queues",
+				"details": err.Error(),
+				"report":  report,
+			})
+			return
+		}
+
+		message := "Queues migrated"
+		if report.DryRun {
+			message = "Dry run, nothing was migrated"
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message": message,
+			"report":  report,
+		})
+	}
+}
+
+func createSystemStatsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := notificationUC.GetSystemStats()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get system stats",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"system_stats": stats,
+		})
+	}
+}
+
+// createDashboardHandler combines the admin stats endpoints for the ops dashboard. A section that
+// fails is left out and reported under errors, so a Redis outage still shows the database stats.
+func createDashboardHandler(notificationUC usecase.NotificationUsecase, redisClient *redis.Client, workerConfig *worker.WorkerConfig) gin.HandlerFunc {
+	const (
+		failurePeriod   = 24 * time.Hour
+		topFailingTypes = 5
+	)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		queueManager := worker.NewQueueManager(redisClient, workerConfig)
+		dashboard := gin.H{"generated_at": now}
+		errors := gin.H{}
+
+		if stats, err := notificationUC.GetSystemStats(); err != nil {
+			errors["system_stats"] = err.Error()
+		} else {
+			dashboard["system_stats"] = stats
+		}
+
+		if stats, err := queueManager.GetQueueStats(c.Request.Context()); err != nil {
+			errors["queue_stats"] = err.Error()
+		} else {
+			dashboard["queue_stats"] = stats
+		}
+
+		if heartbeats, err := queueManager.GetWorkerHeartbeats(c.Request.Context(), now); err != nil {
+			errors["workers"] = err.Error()
+		} else {
+			dashboard["workers"] = heartbeats
+		}
+
+		if stats, err := notificationUC.GetDeliveryFailureStats(failurePeriod, topFailingTypes); err != nil {
+			errors["delivery_failures"] = err.Error()
+		} else {
+			dashboard["delivery_failures"] = stats
+		}
+
+		if len(errors) > 0 {
+			logger.WithField("errors", errors).Warn("Notification dashboard is incomplete")
+			dashboard["errors"] = errors
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"dashboard": dashboard,
+		})
+	}
+}
+
+func createAddTaskHandler(w *worker.Worker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var task worker.NotificationTask
+		if err := c.ShouldBindJSON(&task); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid task data",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		backlog := w.BacklogState()
+		setBacklogHeaders(c, backlog)
+		if task.ScheduledAt == nil && !backlog.Admits(task.Priority) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Notification backlog is over capacity",
+				"backlog": backlog,
+			})
+			return
+		}
+
+		if err := w.AddTask(c.Request.Context(), &task); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to add task",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Task added successfully",
+			"task_id": task.ID,
+		})
+	}
+}
+
+func createTaskStatusHandler(w *worker.Worker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state, err := w.GetTaskState(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "not found") {
+				statusCode = http.StatusNotFound
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to get task status",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"task": state,
+		})
+	}
+}
+
+func createBacklogHandler(w *worker.Worker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		backlog := w.BacklogState()
+		setBacklogHeaders(c, backlog)
+		c.JSON(http.StatusOK, gin.H{
+			"backlog": backlog,
+		})
+	}
+}
+
+// setBacklogHeaders tells producers the current backlog and, above the soft limit, how long to back off
+func setBacklogHeaders(c *gin.Context, backlog *worker.BacklogState) {
+	c.Header("X-Notification-Backlog", strconv.FormatInt(backlog.Backlog, 10))
+	c.Header("X-Notification-Backlog-Level", string(backlog.Level))
+	if backlog.RetryAfterSeconds > 0 {
+		c.Header("Retry-After", strconv.Itoa(backlog.RetryAfterSeconds))
+	}
+}
+
+func createScheduledTaskHandler(w *worker.Worker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Notification *models.CreateNotificationRequest `json:"notification"`
+			ScheduledAt  time.Time                         `json:"scheduled_at"`
+			Priority     models.NotificationPriority       `json:"priority"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if req.Notification == nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Notification data is required",
+			})
+			return
+		}
+
+		if req.ScheduledAt.Before(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Scheduled time must be in the future",
+			})
+			return
+		}
+
+		setBacklogHeaders(c, w.BacklogState())
 
 		task := worker.CreateScheduledNotificationTask(req.Notification, req.ScheduledAt, req.Priority)
-		if err := w.AddTask(task); err != nil {
+		if err := w.AddTask(c.Request.Context(), task); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":   "Failed to schedule notification",
 				"details": err.Error(),
@@ -704,3 +2147,196 @@ func createScheduledTaskHandler(w *worker.Worker) gin.HandlerFunc {
 		})
 	}
 }
+
+func createQueryNotificationsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.AdminNotificationQueryRequest
+		err := c.ShouldBindQuery(&req)
+		if err == nil {
+			req.Page, err = query.Parse(c.Request.URL.Query(), models.NotificationListOptions)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		response, err := notificationUC.QueryNotifications(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to query notifications",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// createUserNotificationsHandler lists notifications of one user with their delivery attempts,
+// filtered like the admin query endpoint
+func createUserNotificationsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil || userID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid user ID",
+			})
+			return
+		}
+
+		var req models.AdminNotificationQueryRequest
+		err = c.ShouldBindQuery(&req)
+		if err == nil {
+			req.Page, err = query.Parse(c.Request.URL.Query(), models.NotificationListOptions)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid query parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		response, err := notificationUC.InspectUserNotifications(uint(userID), &req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to get user notifications",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func createResendNotificationsHandler(notificationUC usecase.NotificationUsecase, w *worker.Worker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.ResendNotificationsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		ids, matched, err := notificationUC.FindResendCandidates(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to find failed notifications",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if req.DryRun {
+			c.JSON(http.StatusOK, models.ResendNotificationsResponse{
+				Matched: matched,
+				DryRun:  true,
+			})
+			return
+		}
+
+		queued := 0
+		for _, id := range ids {
+			task := worker.CreateResendNotificationTask(id, models.NotificationPriorityLow)
+			if err := w.AddTask(c.Request.Context(), task); err != nil {
+				logger.WithFields(map[string]interface{}{
+					"notification_id": id,
+					"error":           err.Error(),
+				}).Error("Failed to queue notification resend")
+				continue
+			}
+			queued++
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"matched": matched,
+			"queued":  queued,
+		}).Info("Failed notifications queued for resend")
+
+		c.JSON(http.StatusAccepted, models.ResendNotificationsResponse{
+			Matched: matched,
+			Queued:  queued,
+		})
+	}
+}
+
+func createResolveNotificationsHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.ResolveNotificationsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		resolved, err := notificationUC.ResolveRelatedNotifications(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to resolve notifications",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":        "Notifications resolved",
+			"resolved_count": resolved,
+		})
+	}
+}
+
+func createMergeUsersHandler(notificationUC usecase.NotificationUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req sharedmodels.MergeUsersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		result, err := notificationUC.MergeUsers(&req)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if strings.Contains(err.Error(), "validation failed") {
+				statusCode = http.StatusBadRequest
+			}
+			c.JSON(statusCode, gin.H{
+				"error":   "Failed to merge users",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Users merged",
+			"result":  result,
+		})
+	}
+}
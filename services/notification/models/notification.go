@@ -2,9 +2,14 @@
 package models
 
 import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
 	"time"
 
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
 
 	"gorm.io/gorm"
 )
@@ -21,8 +26,24 @@ const (
 	NotificationTypePoll     NotificationType = "poll"     // Уведомления об опросах
 	NotificationTypeReminder NotificationType = "reminder" // Напоминания
 	NotificationTypeAnnounce NotificationType = "announce" // Объявления
+	NotificationTypeSecurity NotificationType = "security" // События безопасности аккаунта, не отключаются полностью
 )
 
+// NotificationTypes returns all notification types
+func NotificationTypes() []NotificationType {
+	return []NotificationType{
+		NotificationTypeMessage,
+		NotificationTypeTask,
+		NotificationTypeCalendar,
+		NotificationTypeSystem,
+		NotificationTypeMention,
+		NotificationTypePoll,
+		NotificationTypeReminder,
+		NotificationTypeAnnounce,
+		NotificationTypeSecurity,
+	}
+}
+
 // NotificationPriority represents the priority level of notification
 type NotificationPriority string
 
@@ -41,8 +62,12 @@ const (
 	NotificationStatusDelivered NotificationStatus = "delivered" // Доставлено
 	NotificationStatusRead      NotificationStatus = "read"      // Прочитано
 	NotificationStatusFailed    NotificationStatus = "failed"    // Ошибка доставки
+	NotificationStatusHeld      NotificationStatus = "held"      // Доставка по внешнему каналу отложена до снятия удержания, только для NotificationDelivery
 )
 
+// MaxDeliveryAttempts is the number of attempts after which a failed delivery is no longer retried
+const MaxDeliveryAttempts = 3
+
 // DeliveryChannel represents the delivery channel for notifications
 type DeliveryChannel string
 
@@ -55,11 +80,39 @@ const (
 	DeliveryChannelWebhook DeliveryChannel = "webhook" // Webhook уведомления
 )
 
+// EmailAddressStatus represents deliverability of an email address reported by bounces and complaints
+type EmailAddressStatus string
+
+const (
+	EmailAddressStatusOK         EmailAddressStatus = "ok"         // Письма доставляются
+	EmailAddressStatusBouncing   EmailAddressStatus = "bouncing"   // Письма возвращаются почтовым сервером получателя
+	EmailAddressStatusComplained EmailAddressStatus = "complained" // Получатель пометил письмо как спам
+)
+
+// EmailFeedbackType represents the kind of feedback about a sent email
+type EmailFeedbackType string
+
+const (
+	EmailFeedbackBounce    EmailFeedbackType = "bounce"    // Письмо не доставлено
+	EmailFeedbackComplaint EmailFeedbackType = "complaint" // Жалоба на спам от почтового провайдера
+)
+
+// EmailBounceType represents whether a bounce is permanent
+type EmailBounceType string
+
+const (
+	EmailBounceHard EmailBounceType = "hard" // Адрес не существует или отклоняет почту, подавляется сразу
+	EmailBounceSoft EmailBounceType = "soft" // Временная ошибка, например переполненный ящик
+)
+
+// SoftBounceSuppressThreshold is the number of soft bounces after which an address is suppressed
+const SoftBounceSuppressThreshold = 3
+
 // Notification represents a notification in the system
 type Notification struct {
 	models.BaseModel
 	UserID   uint                 `gorm:"not null;index" json:"user_id" validate:"required,min=1"`
-	Type     NotificationType     `gorm:"not null;size:20;index" json:"type" validate:"required,oneof=message task calendar system mention poll reminder announce"`
+	Type     NotificationType     `gorm:"not null;size:20;index" json:"type" validate:"required,oneof=message task calendar system mention poll reminder announce security"`
 	Title    string               `gorm:"not null;size:255" json:"title" validate:"required,min=1,max=255"`
 	Message  string               `gorm:"type:text" json:"message,omitempty" validate:"omitempty,max=2000"`
 	Priority NotificationPriority `gorm:"not null;default:'medium';size:20" json:"priority" validate:"required,oneof=low medium high critical"`
@@ -73,12 +126,20 @@ type Notification struct {
 	ActionURL   string `gorm:"size:500" json:"action_url,omitempty"`  // URL для действия
 	ImageURL    string `gorm:"size:500" json:"image_url,omitempty"`   // URL изображения
 
+	// Язык получателя для писем и других каналов, пусто — i18n.DefaultLocale
+	Locale i18n.Locale `gorm:"size:5" json:"locale,omitempty"`
+
 	// Delivery tracking
 	DeliveryChannels []NotificationDelivery `gorm:"foreignKey:NotificationID;constraint:OnDelete:CASCADE" json:"delivery_channels,omitempty"`
 
 	// Scheduling
 	ScheduledAt *time.Time `gorm:"index" json:"scheduled_at,omitempty"` // Время запланированной отправки
 	ExpiresAt   *time.Time `gorm:"index" json:"expires_at,omitempty"`   // Время истечения актуальности
+
+	// Deduplication
+	DedupKey        string     `gorm:"size:64;index" json:"-"`                    // Хэш (тип, связанный объект, заголовок) для подавления дубликатов
+	DuplicateCount  int        `gorm:"not null;default:0" json:"duplicate_count"` // Количество подавленных повторов
+	LastDuplicateAt *time.Time `json:"last_duplicate_at,omitempty"`               // Время последнего подавленного повтора
 }
 
 // NotificationDelivery represents delivery attempt for specific channel
@@ -86,7 +147,7 @@ type NotificationDelivery struct {
 	models.BaseModel
 	NotificationID uint               `gorm:"not null;index" json:"notification_id"`
 	Channel        DeliveryChannel    `gorm:"not null;size:20" json:"channel" validate:"required,oneof=in_app email push sms slack webhook"`
-	Status         NotificationStatus `gorm:"not null;default:'pending';size:20" json:"status" validate:"required,oneof=pending delivered read failed"`
+	Status         NotificationStatus `gorm:"not null;default:'pending';size:20" json:"status" validate:"required,oneof=pending delivered read failed held"`
 	AttemptCount   int                `gorm:"not null;default:0" json:"attempt_count"`
 	LastAttemptAt  *time.Time         `json:"last_attempt_at,omitempty"`
 	DeliveredAt    *time.Time         `json:"delivered_at,omitempty"`
@@ -97,11 +158,183 @@ type NotificationDelivery struct {
 	ChannelData string `gorm:"type:jsonb" json:"channel_data,omitempty"` // Дополнительные данные канала
 }
 
+// EmailOutboxEntry is an email delivery waiting for the SMTP server to come back. Entries
+// are retried on their own schedule, independent of the worker task that created the delivery.
+type EmailOutboxEntry struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	DeliveryID     uint      `gorm:"uniqueIndex;not null" json:"delivery_id"`
+	NotificationID uint      `gorm:"not null;index" json:"notification_id"`
+	Attempts       int       `gorm:"not null;default:0" json:"attempts"`
+	LastError      string    `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt  time.Time `gorm:"not null;index" json:"next_attempt_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// EmailSuppression tracks bounces and complaints of an email address. Email deliveries to
+// suppressed addresses are skipped until an administrator clears the suppression.
+type EmailSuppression struct {
+	models.BaseModel
+	Email      string             `gorm:"uniqueIndex;not null;size:255" json:"email"` // В нижнем регистре
+	UserID     *uint              `gorm:"index" json:"user_id,omitempty"`             // Известен из заголовков письма или вебхука провайдера
+	Status     EmailAddressStatus `gorm:"not null;default:'ok';size:20;index" json:"status"`
+	Suppressed bool               `gorm:"not null;default:false;index" json:"suppressed"`
+
+	// Counters since the suppression was last cleared
+	HardBounces int `gorm:"not null;default:0" json:"hard_bounces"`
+	SoftBounces int `gorm:"not null;default:0" json:"soft_bounces"`
+	Complaints  int `gorm:"not null;default:0" json:"complaints"`
+
+	LastEventAt  *time.Time `json:"last_event_at,omitempty"`
+	LastDetail   string     `gorm:"type:text" json:"last_detail,omitempty"` // Диагностика почтового сервера или провайдера
+	Source       string     `gorm:"size:50" json:"source,omitempty"`        // smtp или имя провайдера
+	SuppressedAt *time.Time `json:"suppressed_at,omitempty"`
+	ClearedAt    *time.Time `json:"cleared_at,omitempty"`
+	ClearedBy    *uint      `json:"cleared_by,omitempty"`
+}
+
+// MaxNotificationViews limits the number of saved views of a user
+const MaxNotificationViews = 50
+
+// NotificationView represents a saved notification center view of a user, e.g. "mentions only"
+type NotificationView struct {
+	models.BaseModel
+	UserID uint                   `gorm:"not null;uniqueIndex:idx_notification_views_user_name" json:"user_id"`
+	Name   string                 `gorm:"not null;size:100;uniqueIndex:idx_notification_views_user_name" json:"name"`
+	Filter NotificationViewFilter `gorm:"type:text;serializer:json" json:"filter"`
+}
+
+// NotificationViewFilter represents the stored filter of a saved view. Empty fields match everything.
+type NotificationViewFilter struct {
+	Types        []NotificationType     `json:"types,omitempty" binding:"omitempty,max=20,dive,oneof=message task calendar system mention poll reminder announce security"`
+	Priorities   []NotificationPriority `json:"priorities,omitempty" binding:"omitempty,max=4,dive,oneof=low medium high critical"`
+	IsRead       *bool                  `json:"is_read,omitempty"`
+	RelatedTypes []string               `json:"related_types,omitempty" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Sort         string                 `json:"sort,omitempty" binding:"omitempty,max=100"` // Сортировка по умолчанию в формате параметра sort, например -priority,created_at
+}
+
+// PushPlatform represents the platform of a device receiving push notifications
+type PushPlatform string
+
+const (
+	PushPlatformIOS     PushPlatform = "ios"     // APNs
+	PushPlatformAndroid PushPlatform = "android" // FCM
+)
+
+// MaxPushDevices limits the number of push devices of a user, the least recently seen is replaced
+const MaxPushDevices = 10
+
+// PushDevice represents a mobile device of a user registered for push notifications
+type PushDevice struct {
+	models.BaseModel
+	UserID     uint         `gorm:"not null;index" json:"user_id"`
+	Platform   PushPlatform `gorm:"not null;size:20" json:"platform"`
+	Token      string       `gorm:"not null;size:255;uniqueIndex" json:"token"` // Токен APNs или FCM
+	AppVersion string       `gorm:"size:50" json:"app_version,omitempty"`
+	LastSeenAt time.Time    `gorm:"not null" json:"last_seen_at"`
+}
+
+// BannerStyle represents how a notification is shown on a device
+type BannerStyle string
+
+const (
+	BannerStyleBanner BannerStyle = "banner" // Временный баннер, исчезает сам
+	BannerStyleAlert  BannerStyle = "alert"  // Остается на экране до действия пользователя
+	BannerStyleNone   BannerStyle = "none"   // Только в центре уведомлений, без баннера и звука
+)
+
+// Notification sounds bundled with the apps
+const (
+	SoundDefault = "default" // Системный звук устройства
+	SoundNone    = "none"
+)
+
+// NotificationSound represents a sound of the catalog users can pick from
+type NotificationSound struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// NotificationSounds is the catalog of sounds, the apps bundle a sound file for every ID
+var NotificationSounds = []NotificationSound{
+	{ID: SoundDefault, Name: "System default"},
+	{ID: SoundNone, Name: "Silent"},
+	{ID: "chime", Name: "Chime"},
+	{ID: "ping", Name: "Ping"},
+	{ID: "pop", Name: "Pop"},
+	{ID: "bell", Name: "Bell"},
+	{ID: "knock", Name: "Knock"},
+	{ID: "alarm", Name: "Alarm"},
+}
+
+// BannerStyles lists the allowed banner styles
+var BannerStyles = []BannerStyle{BannerStyleBanner, BannerStyleAlert, BannerStyleNone}
+
+// NotificationPresentation represents how notifications of a type are presented on devices
+type NotificationPresentation struct {
+	Sound          string      `json:"sound"`
+	BannerStyle    BannerStyle `json:"banner_style"`
+	IncludeInBadge bool        `json:"include_in_badge"`
+}
+
+// DefaultPresentations are presentations of notification types users have not changed
+var DefaultPresentations = map[NotificationType]NotificationPresentation{
+	NotificationTypeMessage:  {Sound: "ping", BannerStyle: BannerStyleBanner, IncludeInBadge: true},
+	NotificationTypeMention:  {Sound: "chime", BannerStyle: BannerStyleBanner, IncludeInBadge: true},
+	NotificationTypeTask:     {Sound: "pop", BannerStyle: BannerStyleBanner, IncludeInBadge: true},
+	NotificationTypeCalendar: {Sound: "bell", BannerStyle: BannerStyleBanner, IncludeInBadge: true},
+	NotificationTypeReminder: {Sound: "bell", BannerStyle: BannerStyleAlert, IncludeInBadge: true},
+	NotificationTypePoll:     {Sound: SoundDefault, BannerStyle: BannerStyleBanner, IncludeInBadge: false},
+	NotificationTypeAnnounce: {Sound: SoundDefault, BannerStyle: BannerStyleBanner, IncludeInBadge: true},
+	NotificationTypeSecurity: {Sound: "alarm", BannerStyle: BannerStyleAlert, IncludeInBadge: true},
+	NotificationTypeSystem:   {Sound: SoundNone, BannerStyle: BannerStyleNone, IncludeInBadge: false},
+}
+
+// DefaultPresentation returns the presentation of a notification type users have not changed
+func DefaultPresentation(notificationType NotificationType) NotificationPresentation {
+	if presentation, ok := DefaultPresentations[notificationType]; ok {
+		return presentation
+	}
+	return NotificationPresentation{Sound: SoundDefault, BannerStyle: BannerStyleBanner, IncludeInBadge: true}
+}
+
+// PresentationCatalog returns the allowed presentation values with the defaults of all notification types
+func PresentationCatalog() *PresentationCatalogResponse {
+	defaults := make(map[NotificationType]NotificationPresentation, len(DefaultPresentations))
+	for _, notificationType := range NotificationTypes() {
+		defaults[notificationType] = DefaultPresentation(notificationType)
+	}
+	return &PresentationCatalogResponse{
+		Sounds:       NotificationSounds,
+		BannerStyles: BannerStyles,
+		Defaults:     defaults,
+	}
+}
+
+// IsNotificationSound checks if id is a sound of the catalog
+func IsNotificationSound(id string) bool {
+	for _, sound := range NotificationSounds {
+		if sound.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid checks if the banner style is allowed
+func (s BannerStyle) IsValid() bool {
+	for _, style := range BannerStyles {
+		if s == style {
+			return true
+		}
+	}
+	return false
+}
+
 // EmailTemplate represents email notification template
 type EmailTemplate struct {
 	models.BaseModel
 	Name         string           `gorm:"uniqueIndex;not null;size:100" json:"name" validate:"required,min=1,max=100"`
-	Type         NotificationType `gorm:"not null;size:20;index" json:"type" validate:"required,oneof=message task calendar system mention poll reminder announce"`
+	Type         NotificationType `gorm:"not null;size:20;index" json:"type" validate:"required,oneof=message task calendar system mention poll reminder announce security"`
 	Subject      string           `gorm:"not null;size:255" json:"subject" validate:"required,min=1,max=255"`
 	HTMLTemplate string           `gorm:"type:text;not null" json:"html_template" validate:"required"`
 	TextTemplate string           `gorm:"type:text" json:"text_template,omitempty"`
@@ -116,7 +349,7 @@ type EmailTemplate struct {
 type UserNotificationPreference struct {
 	models.BaseModel
 	UserID           uint             `gorm:"not null;index" json:"user_id" validate:"required,min=1"`
-	NotificationType NotificationType `gorm:"not null;size:20" json:"notification_type" validate:"required,oneof=message task calendar system mention poll reminder announce"`
+	NotificationType NotificationType `gorm:"not null;size:20" json:"notification_type" validate:"required,oneof=message task calendar system mention poll reminder announce security"`
 
 	// Channel preferences
 	InAppEnabled bool `gorm:"not null;default:true" json:"in_app_enabled"`
@@ -128,20 +361,249 @@ type UserNotificationPreference struct {
 	MinPriority NotificationPriority `gorm:"not null;default:'low';size:20" json:"min_priority" validate:"required,oneof=low medium high critical"`
 
 	// Time preferences
-	QuietHoursStart *int `json:"quiet_hours_start,omitempty" validate:"omitempty,min=0,max=23"` // Час начала тихого времени (0-23)
-	QuietHoursEnd   *int `json:"quiet_hours_end,omitempty" validate:"omitempty,min=0,max=23"`   // Час окончания тихого времени (0-23)
-	WeekendEnabled  bool `gorm:"not null;default:true" json:"weekend_enabled"`
+	QuietHoursStart       *int   `json:"quiet_hours_start,omitempty" validate:"omitempty,min=0,max=23"`        // Час начала тихого времени (0-23)
+	QuietHoursStartMinute *int   `json:"quiet_hours_start_minute,omitempty" validate:"omitempty,min=0,max=59"` // Минута начала тихого времени (0-59)
+	QuietHoursEnd         *int   `json:"quiet_hours_end,omitempty" validate:"omitempty,min=0,max=23"`          // Час окончания тихого времени (0-23)
+	QuietHoursEndMinute   *int   `json:"quiet_hours_end_minute,omitempty" validate:"omitempty,min=0,max=59"`   // Минута окончания тихого времени (0-59)
+	Timezone              string `gorm:"size:64;not null;default:'UTC'" json:"timezone"`                       // IANA часовой пояс пользователя, например Europe/Moscow
+	WeekendEnabled        bool   `gorm:"not null;default:true" json:"weekend_enabled"`
 
 	// Frequency limits
 	DigestEnabled   bool `gorm:"not null;default:false" json:"digest_enabled"`                    // Группировка уведомлений
 	DigestFrequency *int `json:"digest_frequency,omitempty" validate:"omitempty,min=15,max=1440"` // Частота дайджеста в минутах
+
+	// Presentation on devices, unset values follow the default of the type
+	Sound          string      `gorm:"size:32" json:"sound"`        // ID звука из каталога
+	BannerStyle    BannerStyle `gorm:"size:20" json:"banner_style"` // banner, alert или none
+	IncludeInBadge *bool       `json:"include_in_badge"`            // Учитывать ли непрочитанные этого типа в значке приложения
+}
+
+// Presentation returns how notifications of the preference type are presented, unset values
+// taken from the type default
+func (p *UserNotificationPreference) Presentation() NotificationPresentation {
+	presentation := DefaultPresentation(p.NotificationType)
+	if p.Sound != "" {
+		presentation.Sound = p.Sound
+	}
+	if p.BannerStyle != "" {
+		presentation.BannerStyle = p.BannerStyle
+	}
+	if p.IncludeInBadge != nil {
+		presentation.IncludeInBadge = *p.IncludeInBadge
+	}
+	return presentation
+}
+
+// FillPresentation sets unset presentation fields to the type default, so clients always get them
+func (p *UserNotificationPreference) FillPresentation() {
+	presentation := p.Presentation()
+	p.Sound = presentation.Sound
+	p.BannerStyle = presentation.BannerStyle
+	p.IncludeInBadge = &presentation.IncludeInBadge
+}
+
+// NotificationPreferenceDefault represents the organization-wide default preference for a notification type.
+// It applies to users who have not set their own preference for the type.
+type NotificationPreferenceDefault struct {
+	models.BaseModel
+	NotificationType NotificationType `gorm:"uniqueIndex;not null;size:20" json:"notification_type"`
+
+	// Preference fields have no column defaults, so disabled channels are stored as given
+
+	// Channel preferences
+	InAppEnabled bool `gorm:"not null" json:"in_app_enabled"`
+	EmailEnabled bool `gorm:"not null" json:"email_enabled"`
+	PushEnabled  bool `gorm:"not null" json:"push_enabled"`
+	SMSEnabled   bool `gorm:"not null" json:"sms_enabled"`
+
+	// Priority filters
+	MinPriority NotificationPriority `gorm:"not null;default:'low';size:20" json:"min_priority"`
+
+	// Time preferences, evaluated in the user's timezone
+	QuietHoursStart       *int `json:"quiet_hours_start,omitempty"`
+	QuietHoursStartMinute *int `json:"quiet_hours_start_minute,omitempty"`
+	QuietHoursEnd         *int `json:"quiet_hours_end,omitempty"`
+	QuietHoursEndMinute   *int `json:"quiet_hours_end_minute,omitempty"`
+	WeekendEnabled        bool `gorm:"not null" json:"weekend_enabled"`
+
+	// Frequency limits
+	DigestEnabled   bool `gorm:"not null" json:"digest_enabled"`
+	DigestFrequency *int `json:"digest_frequency,omitempty"`
+
+	UpdatedBy uint `gorm:"not null" json:"updated_by"` // Администратор, изменивший значение по умолчанию
+}
+
+// FallbackStep is a step of a channel fallback chain
+type FallbackStep struct {
+	Channel      DeliveryChannel `json:"channel" binding:"required,oneof=email push sms slack webhook"`
+	DelayMinutes int             `json:"delay_minutes" binding:"min=0,max=1440"` // Минуты с момента создания уведомления
+}
+
+// NotificationFallbackPolicy defines the channel fallback chain of notifications of a priority.
+// Steps are tried in order while the notification stays unread.
+type NotificationFallbackPolicy struct {
+	models.BaseModel
+	Priority  NotificationPriority `gorm:"uniqueIndex;not null;size:20" json:"priority"`
+	Enabled   bool                 `gorm:"not null" json:"enabled"`
+	Steps     []FallbackStep       `gorm:"type:text;serializer:json" json:"steps"`
+	UpdatedBy uint                 `gorm:"not null" json:"updated_by"` // Администратор, изменивший политику
+}
+
+// FallbackState represents the state of a notification fallback chain
+type FallbackState string
+
+const (
+	FallbackStateActive    FallbackState = "active"    // Ожидает следующего шага
+	FallbackStateCompleted FallbackState = "completed" // Все шаги выполнены
+	FallbackStateCancelled FallbackState = "cancelled" // Уведомление прочитано
+)
+
+// NotificationFallback tracks the fallback chain of a notification. Steps are copied from the
+// policy when the notification is sent, so policy changes don't affect started chains.
+type NotificationFallback struct {
+	ID             uint            `gorm:"primarykey" json:"id"`
+	NotificationID uint            `gorm:"uniqueIndex;not null" json:"notification_id"`
+	UserID         uint            `gorm:"not null;index" json:"user_id"`
+	Steps          []FallbackStep  `gorm:"type:text;serializer:json" json:"steps"`
+	NextStep       int             `gorm:"not null;default:0" json:"next_step"`
+	State          FallbackState   `gorm:"not null;size:20;index" json:"state"`
+	NextAttemptAt  *time.Time      `gorm:"index" json:"next_attempt_at,omitempty"`
+	LastChannel    DeliveryChannel `gorm:"size:20" json:"last_channel,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// TemplateVariant is a subject line tested by a template experiment
+type TemplateVariant struct {
+	Key     string `json:"key" binding:"required,min=1,max=20,alphanum"`
+	Subject string `json:"subject" binding:"required,min=1,max=255"` // Шаблон темы, например "{{.TaskTitle}}: новая задача"
+	Weight  int    `json:"weight" binding:"required,min=1,max=100"`  // Доля трафика относительно других вариантов
+}
+
+// TemplateExperiment splits recipients of a notification template between subject variants.
+// Each user always gets the same variant while the experiment runs.
+type TemplateExperiment struct {
+	models.BaseModel
+	TemplateName string            `gorm:"uniqueIndex;not null;size:100" json:"template_name"`
+	Enabled      bool              `gorm:"not null" json:"enabled"`
+	Variants     []TemplateVariant `gorm:"type:text;serializer:json" json:"variants"`
+	UpdatedBy    uint              `gorm:"not null" json:"updated_by"` // Администратор, изменивший эксперимент
+}
+
+// AssignVariant returns the variant of a user. The user is placed by a hash of the experiment
+// and user IDs, so assignment is stable and restarted experiments shuffle users again.
+func (e *TemplateExperiment) AssignVariant(userID uint) *TemplateVariant {
+	total := 0
+	for _, variant := range e.Variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	hash := fnv.New32a()
+	fmt.Fprintf(hash, "%d:%d", e.ID, userID)
+	point := int(hash.Sum32() % uint32(total))
+	for i := range e.Variants {
+		if point < e.Variants[i].Weight {
+			return &e.Variants[i]
+		}
+		point -= e.Variants[i].Weight
+	}
+	return nil
+}
+
+// TemplateEngagement represents an event tracked for notifications of a template experiment
+type TemplateEngagement string
+
+const (
+	TemplateEngagementSent  TemplateEngagement = "sent"
+	TemplateEngagementOpen  TemplateEngagement = "open"  // Уведомление или письмо открыто
+	TemplateEngagementClick TemplateEngagement = "click" // Переход по ссылке действия
+)
+
+// TemplateEngagementEvent records an event of a notification sent with a template variant.
+// Each event is counted once per notification.
+type TemplateEngagementEvent struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	ExperimentID   uint               `gorm:"not null;index" json:"experiment_id"`
+	Variant        string             `gorm:"not null;size:20" json:"variant"`
+	NotificationID uint               `gorm:"not null;uniqueIndex:idx_template_engagement_event" json:"notification_id"`
+	UserID         uint               `gorm:"not null;index" json:"user_id"`
+	Event          TemplateEngagement `gorm:"not null;size:10;uniqueIndex:idx_template_engagement_event" json:"event"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// CampaignStatus represents the status of an announcement campaign
+type CampaignStatus string
+
+const (
+	CampaignStatusScheduled CampaignStatus = "scheduled" // Есть отправки в ожидании
+	CampaignStatusCompleted CampaignStatus = "completed" // Все отправки выполнены
+	CampaignStatusCancelled CampaignStatus = "cancelled" // Отменена до завершения
+)
+
+// CampaignSendStatus represents the status of a scheduled announcement send
+type CampaignSendStatus string
+
+const (
+	CampaignSendStatusPending   CampaignSendStatus = "pending"
+	CampaignSendStatusSending   CampaignSendStatus = "sending"
+	CampaignSendStatusSent      CampaignSendStatus = "sent"
+	CampaignSendStatusFailed    CampaignSendStatus = "failed"
+	CampaignSendStatusCancelled CampaignSendStatus = "cancelled"
+)
+
+// MaxCampaignSends limits scheduled sends of one announcement campaign
+const MaxCampaignSends = 50
+
+// CampaignDailyLimit is how many campaigns may reach the same user in one day before the
+// campaign calendar warns about a conflict
+const CampaignDailyLimit = 2
+
+// AnnouncementCampaign is a series of announcement sends to audience segments planned by
+// the comms team. Each send delivers the announcement to its segment at its scheduled time.
+type AnnouncementCampaign struct {
+	models.BaseModel
+	Name      string               `gorm:"not null;size:100" json:"name"`
+	Title     string               `gorm:"not null;size:255" json:"title"`
+	Content   string               `gorm:"type:text;not null" json:"content"`
+	Priority  NotificationPriority `gorm:"not null;default:'medium';size:20" json:"priority"`
+	Channels  []DeliveryChannel    `gorm:"type:text;serializer:json" json:"channels,omitempty"`
+	Status    CampaignStatus       `gorm:"not null;default:'scheduled';size:20;index" json:"status"`
+	CreatedBy uint                 `gorm:"not null;index" json:"created_by"`
+
+	Sends []*CampaignSend `gorm:"foreignKey:CampaignID;constraint:OnDelete:CASCADE" json:"sends,omitempty"`
+}
+
+// CampaignSend is one scheduled send of an announcement campaign to an audience segment
+type CampaignSend struct {
+	ID          uint               `gorm:"primarykey" json:"id"`
+	CampaignID  uint               `gorm:"not null;index" json:"campaign_id"`
+	Segment     string             `gorm:"not null;size:100" json:"segment"` // Название сегмента аудитории
+	UserIDs     []uint             `gorm:"type:text;serializer:json" json:"user_ids"`
+	ScheduledAt time.Time          `gorm:"not null;index" json:"scheduled_at"`
+	Status      CampaignSendStatus `gorm:"not null;default:'pending';size:20;index" json:"status"`
+	SentAt      *time.Time         `json:"sent_at,omitempty"`
+	Error       string             `gorm:"size:500" json:"error,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// DailyDigest records the daily summary sent to a user, one per local day of the user
+type DailyDigest struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_daily_digests_user_date,priority:1" json:"user_id"`
+	Date           string    `gorm:"not null;size:10;uniqueIndex:idx_daily_digests_user_date,priority:2" json:"date"` // Дата сводки в часовом поясе пользователя, YYYY-MM-DD
+	NotificationID *uint     `json:"notification_id,omitempty"`
+	SentAt         time.Time `gorm:"not null" json:"sent_at"`
 }
 
 // NotificationTemplate represents a reusable notification template
 type NotificationTemplate struct {
 	models.BaseModel
 	Name            string               `gorm:"uniqueIndex;not null;size:100" json:"name" validate:"required,min=1,max=100"`
-	Type            NotificationType     `gorm:"not null;size:20;index" json:"type" validate:"required,oneof=message task calendar system mention poll reminder announce"`
+	Type            NotificationType     `gorm:"not null;size:20;index" json:"type" validate:"required,oneof=message task calendar system mention poll reminder announce security"`
 	TitleTemplate   string               `gorm:"not null;size:255" json:"title_template" validate:"required,min=1,max=255"`
 	MessageTemplate string               `gorm:"type:text" json:"message_template,omitempty" validate:"omitempty,max=2000"`
 	Priority        NotificationPriority `gorm:"not null;default:'medium';size:20" json:"priority" validate:"required,oneof=low medium high critical"`
@@ -166,10 +628,30 @@ func (EmailTemplate) TableName() string {
 	return "email_templates"
 }
 
+func (NotificationView) TableName() string {
+	return "notification_views"
+}
+
+func (PushDevice) TableName() string {
+	return "push_devices"
+}
+
+func (EmailSuppression) TableName() string {
+	return "email_suppressions"
+}
+
+func (EmailOutboxEntry) TableName() string {
+	return "email_outbox"
+}
+
 func (UserNotificationPreference) TableName() string {
 	return "user_notification_preferences"
 }
 
+func (NotificationPreferenceDefault) TableName() string {
+	return "notification_preference_defaults"
+}
+
 func (NotificationTemplate) TableName() string {
 	return "notification_templates"
 }
@@ -214,6 +696,14 @@ func (p *UserNotificationPreference) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// BeforeCreate hook for NotificationPreferenceDefault
+func (d *NotificationPreferenceDefault) BeforeCreate(tx *gorm.DB) error {
+	if d.MinPriority == "" {
+		d.MinPriority = NotificationPriorityLow
+	}
+	return nil
+}
+
 // BeforeCreate hook for NotificationTemplate
 func (nt *NotificationTemplate) BeforeCreate(tx *gorm.DB) error {
 	if nt.Priority == "" {
@@ -227,7 +717,7 @@ func (nt *NotificationTemplate) BeforeCreate(tx *gorm.DB) error {
 // CreateNotificationRequest represents request for creating a notification
 type CreateNotificationRequest struct {
 	UserID      uint                  `json:"user_id" binding:"required,min=1" validate:"required,min=1"`
-	Type        NotificationType      `json:"type" binding:"required,oneof=message task calendar system mention poll reminder announce" validate:"required,oneof=message task calendar system mention poll reminder announce"`
+	Type        NotificationType      `json:"type" binding:"required,oneof=message task calendar system mention poll reminder announce security" validate:"required,oneof=message task calendar system mention poll reminder announce security"`
 	Title       string                `json:"title" binding:"required,min=1,max=255" validate:"required,min=1,max=255"`
 	Message     string                `json:"message,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
 	Priority    *NotificationPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical" validate:"omitempty,oneof=low medium high critical"`
@@ -238,12 +728,13 @@ type CreateNotificationRequest struct {
 	ScheduledAt *time.Time            `json:"scheduled_at,omitempty"`
 	ExpiresAt   *time.Time            `json:"expires_at,omitempty"`
 	Channels    []DeliveryChannel     `json:"channels,omitempty" validate:"omitempty,dive,oneof=in_app email push sms slack webhook"`
+	Locale      i18n.Locale           `json:"locale,omitempty" binding:"omitempty,oneof=ru en" validate:"omitempty,enum"`
 }
 
 // BulkCreateNotificationRequest represents request for creating multiple notifications
 type BulkCreateNotificationRequest struct {
 	UserIDs     []uint                `json:"user_ids" binding:"required,min=1,dive,min=1" validate:"required,min=1,dive,min=1"`
-	Type        NotificationType      `json:"type" binding:"required,oneof=message task calendar system mention poll reminder announce" validate:"required,oneof=message task calendar system mention poll reminder announce"`
+	Type        NotificationType      `json:"type" binding:"required,oneof=message task calendar system mention poll reminder announce security" validate:"required,oneof=message task calendar system mention poll reminder announce security"`
 	Title       string                `json:"title" binding:"required,min=1,max=255" validate:"required,min=1,max=255"`
 	Message     string                `json:"message,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
 	Priority    *NotificationPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical" validate:"omitempty,oneof=low medium high critical"`
@@ -254,6 +745,8 @@ type BulkCreateNotificationRequest struct {
 	ScheduledAt *time.Time            `json:"scheduled_at,omitempty"`
 	ExpiresAt   *time.Time            `json:"expires_at,omitempty"`
 	Channels    []DeliveryChannel     `json:"channels,omitempty" validate:"omitempty,dive,oneof=in_app email push sms slack webhook"`
+	Locale      i18n.Locale           `json:"locale,omitempty" binding:"omitempty,oneof=ru en" validate:"omitempty,enum"`
+	UserLocales map[uint]i18n.Locale  `json:"user_locales,omitempty"` // Язык получателей из профиля, вместо Locale
 }
 
 // UpdateNotificationRequest represents request for updating a notification
@@ -273,24 +766,231 @@ type MarkAsReadRequest struct {
 	NotificationIDs []uint `json:"notification_ids" binding:"required,min=1,dive,min=1" validate:"required,min=1,dive,min=1"`
 }
 
+// MarkAsReadByFilterRequest represents request to mark notifications as read by criteria
+type MarkAsReadByFilterRequest struct {
+	Type        *NotificationType `json:"type,omitempty" binding:"omitempty,oneof=message task calendar system mention poll reminder announce security" validate:"omitempty,oneof=message task calendar system mention poll reminder announce security"`
+	RelatedType string            `json:"related_type,omitempty" binding:"omitempty,max=50" validate:"omitempty,max=50"`
+	RelatedID   *uint             `json:"related_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+	Before      *time.Time        `json:"before,omitempty"` // Только уведомления, созданные до этого момента
+}
+
+// ResolveNotificationsRequest represents internal request to auto-resolve notifications
+// when the related object was acted upon (chat opened, task completed etc.)
+type ResolveNotificationsRequest struct {
+	RelatedType string            `json:"related_type" binding:"required,max=50" validate:"required,max=50"`
+	RelatedID   uint              `json:"related_id" binding:"required,min=1" validate:"required,min=1"`
+	UserIDs     []uint            `json:"user_ids,omitempty" binding:"omitempty,dive,min=1"` // If empty, resolve for all users
+	Type        *NotificationType `json:"type,omitempty" binding:"omitempty,oneof=message task calendar system mention poll reminder announce security" validate:"omitempty,oneof=message task calendar system mention poll reminder announce security"`
+}
+
 // NotificationFilterRequest represents filtering parameters for notifications
 type NotificationFilterRequest struct {
-	Type          *NotificationType     `form:"type" binding:"omitempty,oneof=message task calendar system mention poll reminder announce"`
-	Priority      *NotificationPriority `form:"priority" binding:"omitempty,oneof=low medium high critical"`
-	Status        *NotificationStatus   `form:"status" binding:"omitempty,oneof=pending delivered read failed"`
-	IsRead        *bool                 `form:"is_read"`
-	RelatedType   string                `form:"related_type" binding:"omitempty,max=50"`
-	CreatedAfter  *time.Time            `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
-	CreatedBefore *time.Time            `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
-	Limit         int                   `form:"limit" binding:"omitempty,min=1,max=100"`
-	Offset        int                   `form:"offset" binding:"omitempty,min=0"`
-	SortBy        string                `form:"sort_by" binding:"omitempty,oneof=created_at updated_at priority type"`
-	SortOrder     string                `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Type          *NotificationType       `form:"type" binding:"omitempty,oneof=message task calendar system mention poll reminder announce security"`
+	Priority      *NotificationPriority   `form:"priority" binding:"omitempty,oneof=low medium high critical"`
+	Status        *NotificationStatus     `form:"status" binding:"omitempty,oneof=pending delivered read failed"`
+	IsRead        *bool                   `form:"is_read"`
+	RelatedType   string                  `form:"related_type" binding:"omitempty,max=50"`
+	CreatedAfter  *time.Time              `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time              `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	ViewID        *uint                   `form:"view_id" binding:"omitempty,min=1"`
+	View          *NotificationViewFilter `form:"-"` // Filter of the saved view, combined with the request filters
+	Page          *query.Params           `form:"-"` // Pagination, sorting and generic filters
+}
+
+// NotificationListOptions defines pagination, sorting and filtering of notification lists
+var NotificationListOptions = &query.Options{
+	DefaultSort: "-created_at",
+	SortFields: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"priority":   "priority",
+		"type":       "type",
+	},
+	FilterFields: map[string]string{
+		"type":         "type",
+		"priority":     "priority",
+		"status":       "status",
+		"is_read":      "is_read",
+		"related_type": "related_type",
+		"related_id":   "related_id",
+		"created_at":   "created_at",
+	},
+}
+
+// AdminNotificationFilter represents admin filters over notification history of all users
+type AdminNotificationFilter struct {
+	Type          *NotificationType     `form:"type" json:"type,omitempty" binding:"omitempty,oneof=message task calendar system mention poll reminder announce security"`
+	Priority      *NotificationPriority `form:"priority" json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	Status        *NotificationStatus   `form:"status" json:"status,omitempty" binding:"omitempty,oneof=pending delivered read failed"`
+	Channel       *DeliveryChannel      `form:"channel" json:"channel,omitempty" binding:"omitempty,oneof=in_app email push sms slack webhook"` // Есть попытка доставки через канал
+	RelatedType   string                `form:"related_type" json:"related_type,omitempty" binding:"omitempty,max=50"`
+	CreatedAfter  *time.Time            `form:"created_after" json:"created_after,omitempty" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time            `form:"created_before" json:"created_before,omitempty" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// User segment
+	UserIDs []uint       `form:"user_ids" json:"user_ids,omitempty" binding:"omitempty,max=1000,dive,min=1"`
+	Locale  *i18n.Locale `form:"locale" json:"locale,omitempty" binding:"omitempty,oneof=ru en"`
+}
+
+// AdminNotificationQueryRequest represents admin query of notification history with pagination
+type AdminNotificationQueryRequest struct {
+	AdminNotificationFilter
+	Page *query.Params `form:"-"` // Pagination, sorting and generic filters
+}
+
+// ResendNotificationsRequest represents admin request to requeue failed notifications
+// matching the filter. Status is always failed.
+type ResendNotificationsRequest struct {
+	AdminNotificationFilter
+	Limit  int  `json:"limit,omitempty" binding:"omitempty,min=1,max=10000"` // Максимум уведомлений за запрос, по умолчанию 1000
+	DryRun bool `json:"dry_run"`                                             // Только посчитать подходящие уведомления
+}
+
+// ResendNotificationsResponse represents result of admin resend request
+type ResendNotificationsResponse struct {
+	Matched int64 `json:"matched"`
+	Queued  int   `json:"queued"`
+	DryRun  bool  `json:"dry_run"`
+}
+
+// DeliveryHoldRequest represents admin request to hold outbound deliveries, e.g. during a provider outage
+type DeliveryHoldRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// DeliveryHoldStatus represents the state of the outbound delivery hold and its queue
+type DeliveryHoldStatus struct {
+	Enabled     bool                      `json:"enabled"`
+	Reason      string                    `json:"reason,omitempty"`
+	ActivatedAt *time.Time                `json:"activated_at,omitempty"`
+	ActivatedBy uint                      `json:"activated_by,omitempty"`
+	Held        map[DeliveryChannel]int64 `json:"held"` // Отложенные доставки по каналам
+	TotalHeld   int64                     `json:"total_held"`
+}
+
+// EmailPreview represents an email rendered in one locale without sending it
+type EmailPreview struct {
+	Locale   i18n.Locale `json:"locale"`
+	Subject  string      `json:"subject"`
+	HTMLBody string      `json:"html_body"`
+	TextBody string      `json:"text_body"`
+}
+
+// EmailOutboxStatus represents the state of the SMTP server and the emails waiting for it
+type EmailOutboxStatus struct {
+	SMTPHealthy      bool       `json:"smtp_healthy"`
+	SMTPCheckedAt    *time.Time `json:"smtp_checked_at,omitempty"` // Последняя проверка сервера, пусто до первой проверки
+	SMTPError        string     `json:"smtp_error,omitempty"`
+	Pending          int64      `json:"pending"`
+	Due              int64      `json:"due"` // Письма, время повторной отправки которых уже наступило
+	OldestQueuedAt   *time.Time `json:"oldest_queued_at,omitempty"`
+	NextAttemptAt    *time.Time `json:"next_attempt_at,omitempty"`
+	MaxQueueAgeHours int        `json:"max_queue_age_hours"` // Письма старше этого срока помечаются как неотправленные
+}
+
+// DeliveryFailureStats represents delivery failures over a recent period
+type DeliveryFailureStats struct {
+	Since           time.Time              `json:"since"`
+	Deliveries      int64                  `json:"deliveries"`
+	Failed          int64                  `json:"failed"`
+	FailureRate     float64                `json:"failure_rate"` // Доля неудачных доставок, от 0 до 1
+	ByChannel       []*DeliveryFailureRate `json:"by_channel"`
+	TopFailingTypes []*DeliveryFailureRate `json:"top_failing_types"` // Типы уведомлений с наибольшим числом ошибок
+}
+
+// DeliveryFailureRate represents delivery failures of one channel or notification type
+type DeliveryFailureRate struct {
+	Channel     DeliveryChannel  `json:"channel,omitempty"`
+	Type        NotificationType `json:"type,omitempty"`
+	Deliveries  int64            `json:"deliveries"`
+	Failed      int64            `json:"failed"`
+	FailureRate float64          `json:"failure_rate"`
+}
+
+// ReleaseHeldDeliveriesRequest represents admin request to send held deliveries, oldest first
+type ReleaseHeldDeliveriesRequest struct {
+	Channel *DeliveryChannel `json:"channel,omitempty" binding:"omitempty,oneof=email push sms slack webhook"`
+	Limit   int              `json:"limit,omitempty" binding:"omitempty,min=1,max=10000"` // Максимум доставок за запрос, по умолчанию 500
+}
+
+// ReleaseHeldDeliveriesResponse represents result of releasing held deliveries. Provider errors
+// of released deliveries are recorded on the delivery and retried like other failed deliveries.
+type ReleaseHeldDeliveriesResponse struct {
+	Released  int   `json:"released"` // Передано в канал доставки
+	Failed    int   `json:"failed"`   // Не удалось передать, например уведомление удалено
+	Remaining int64 `json:"remaining"`
+}
+
+// DrainHeldDeliveriesRequest represents admin request to drop held deliveries without sending them,
+// e.g. when they are outdated after a long outage
+type DrainHeldDeliveriesRequest struct {
+	Channel *DeliveryChannel `json:"channel,omitempty" binding:"omitempty,oneof=email push sms slack webhook"`
+	Before  *time.Time       `json:"before,omitempty"` // Только доставки, отложенные раньше этого времени
+}
+
+// CreateNotificationViewRequest represents request for saving a notification center view
+type CreateNotificationViewRequest struct {
+	Name   string                 `json:"name" binding:"required,min=1,max=100"`
+	Filter NotificationViewFilter `json:"filter"`
+}
+
+// RegisterPushDeviceRequest represents request for registering a device for push notifications.
+// Registering a known token again moves it to the current user and refreshes it.
+type RegisterPushDeviceRequest struct {
+	Platform   PushPlatform `json:"platform" binding:"required,oneof=ios android"`
+	Token      string       `json:"token" binding:"required,min=1,max=255"`
+	AppVersion string       `json:"app_version,omitempty" binding:"omitempty,max=50"`
+}
+
+// UpdateNotificationViewRequest represents request for updating a saved view
+type UpdateNotificationViewRequest struct {
+	Name   *string                 `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Filter *NotificationViewFilter `json:"filter,omitempty"`
+}
+
+// EmailFeedbackEvent represents one bounce or complaint reported by the email provider
+type EmailFeedbackEvent struct {
+	Type       EmailFeedbackType `json:"type" binding:"required,oneof=bounce complaint"`
+	Email      string            `json:"email" binding:"required,email,max=255"`
+	BounceType EmailBounceType   `json:"bounce_type,omitempty" binding:"omitempty,oneof=hard soft"` // Для bounce, по умолчанию hard
+	UserID     *uint             `json:"user_id,omitempty" binding:"omitempty,min=1"`
+	Detail     string            `json:"detail,omitempty" binding:"omitempty,max=1000"`
+	OccurredAt *time.Time        `json:"occurred_at,omitempty"`
+}
+
+// EmailFeedbackRequest represents a provider webhook delivering bounces and complaints
+type EmailFeedbackRequest struct {
+	Provider string               `json:"provider,omitempty" binding:"omitempty,max=50"`
+	Events   []EmailFeedbackEvent `json:"events" binding:"required,min=1,max=1000,dive"`
+}
+
+// EmailFeedbackResult represents result of processing bounces and complaints
+type EmailFeedbackResult struct {
+	Processed  int `json:"processed"`
+	Suppressed int `json:"suppressed"` // Адреса, подавленные этим запросом
+	Ignored    int `json:"ignored"`    // Например отложенная доставка в отчёте о недоставке
+}
+
+// EmailSuppressionFilter represents admin filters over email suppressions
+type EmailSuppressionFilter struct {
+	Email      string              `form:"email" binding:"omitempty,max=255"` // Часть адреса
+	UserID     *uint               `form:"user_id" binding:"omitempty,min=1"`
+	Status     *EmailAddressStatus `form:"status" binding:"omitempty,oneof=ok bouncing complained"`
+	Suppressed *bool               `form:"suppressed"`
+	Limit      int                 `form:"limit" binding:"omitempty,min=1,max=100"` // По умолчанию 50
+	Offset     int                 `form:"offset" binding:"omitempty,min=0"`
+}
+
+// EmailDeliveryStatus represents deliverability of the user's email address in preference responses
+type EmailDeliveryStatus struct {
+	Status       EmailAddressStatus `json:"status"`
+	Suppressed   bool               `json:"suppressed"` // Email-уведомления не отправляются, пока администратор не снимет подавление
+	SuppressedAt *time.Time         `json:"suppressed_at,omitempty"`
 }
 
 // UserPreferenceRequest represents request for updating user notification preferences
 type UserPreferenceRequest struct {
-	NotificationType NotificationType      `json:"notification_type" binding:"required,oneof=message task calendar system mention poll reminder announce" validate:"required,oneof=message task calendar system mention poll reminder announce"`
+	NotificationType NotificationType      `json:"notification_type" binding:"required,oneof=message task calendar system mention poll reminder announce security" validate:"required,oneof=message task calendar system mention poll reminder announce security"`
 	InAppEnabled     *bool                 `json:"in_app_enabled,omitempty"`
 	EmailEnabled     *bool                 `json:"email_enabled,omitempty"`
 	PushEnabled      *bool                 `json:"push_enabled,omitempty"`
@@ -298,13 +998,137 @@ type UserPreferenceRequest struct {
 	MinPriority      *NotificationPriority `json:"min_priority,omitempty" binding:"omitempty,oneof=low medium high critical" validate:"omitempty,oneof=low medium high critical"`
 	QuietHoursStart  *int                  `json:"quiet_hours_start,omitempty" binding:"omitempty,min=0,max=23" validate:"omitempty,min=0,max=23"`
 	QuietHoursEnd    *int                  `json:"quiet_hours_end,omitempty" binding:"omitempty,min=0,max=23" validate:"omitempty,min=0,max=23"`
+	QuietStartMinute *int                  `json:"quiet_hours_start_minute,omitempty" binding:"omitempty,min=0,max=59" validate:"omitempty,min=0,max=59"`
+	QuietEndMinute   *int                  `json:"quiet_hours_end_minute,omitempty" binding:"omitempty,min=0,max=59" validate:"omitempty,min=0,max=59"`
+	Timezone         *string               `json:"timezone,omitempty" binding:"omitempty,max=64" validate:"omitempty,max=64"`
 	WeekendEnabled   *bool                 `json:"weekend_enabled,omitempty"`
 	DigestEnabled    *bool                 `json:"digest_enabled,omitempty"`
 	DigestFrequency  *int                  `json:"digest_frequency,omitempty" binding:"omitempty,min=15,max=1440" validate:"omitempty,min=15,max=1440"`
+	Sound            *string               `json:"sound,omitempty" binding:"omitempty,max=32" validate:"omitempty,max=32"`
+	BannerStyle      *BannerStyle          `json:"banner_style,omitempty" binding:"omitempty,oneof=banner alert none" validate:"omitempty,oneof=banner alert none"`
+	IncludeInBadge   *bool                 `json:"include_in_badge,omitempty"`
 }
 
+// PreferenceDefaultRequest represents request for setting the organization default preference of a notification type.
+// Omitted fields get the built-in defaults.
+type PreferenceDefaultRequest struct {
+	InAppEnabled     *bool                 `json:"in_app_enabled,omitempty"`
+	EmailEnabled     *bool                 `json:"email_enabled,omitempty"`
+	PushEnabled      *bool                 `json:"push_enabled,omitempty"`
+	SMSEnabled       *bool                 `json:"sms_enabled,omitempty"`
+	MinPriority      *NotificationPriority `json:"min_priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	QuietHoursStart  *int                  `json:"quiet_hours_start,omitempty" binding:"omitempty,min=0,max=23"`
+	QuietHoursEnd    *int                  `json:"quiet_hours_end,omitempty" binding:"omitempty,min=0,max=23"`
+	QuietStartMinute *int                  `json:"quiet_hours_start_minute,omitempty" binding:"omitempty,min=0,max=59"`
+	QuietEndMinute   *int                  `json:"quiet_hours_end_minute,omitempty" binding:"omitempty,min=0,max=59"`
+	WeekendEnabled   *bool                 `json:"weekend_enabled,omitempty"`
+	DigestEnabled    *bool                 `json:"digest_enabled,omitempty"`
+	DigestFrequency  *int                  `json:"digest_frequency,omitempty" binding:"omitempty,min=15,max=1440"`
+}
+
+// ResetUserPreferencesRequest represents request for resetting preferences of users to the organization defaults
+type ResetUserPreferencesRequest struct {
+	UserIDs []uint             `json:"user_ids" binding:"required,min=1,max=1000,dive,min=1"`
+	Types   []NotificationType `json:"types,omitempty" binding:"omitempty,dive,oneof=message task calendar system mention poll reminder announce security"` // Если пусто, сбрасываются все типы
+}
+
+// PreferenceExportRequest represents query parameters for exporting effective preferences of users
+type PreferenceExportRequest struct {
+	UserIDs []uint             `form:"user_ids" binding:"required,min=1,max=1000,dive,min=1"`
+	Types   []NotificationType `form:"types" binding:"omitempty,dive,oneof=message task calendar system mention poll reminder announce security"` // Если пусто, экспортируются все типы
+}
+
+// FallbackPolicyRequest represents request for setting the fallback policy of a priority
+type FallbackPolicyRequest struct {
+	Enabled *bool          `json:"enabled,omitempty"` // По умолчанию true
+	Steps   []FallbackStep `json:"steps" binding:"required,min=1,max=5,dive"`
+}
+
+// TemplateExperimentRequest represents request for setting the subject experiment of a template.
+// Changing the variants restarts the experiment, changing only enabled pauses or resumes it.
+type TemplateExperimentRequest struct {
+	Enabled  *bool             `json:"enabled,omitempty"` // По умолчанию true
+	Variants []TemplateVariant `json:"variants" binding:"required,min=2,max=5,dive"`
+}
+
+// TrackEngagementRequest represents an engagement event reported by a client for a notification
+type TrackEngagementRequest struct {
+	Event TemplateEngagement `json:"event" binding:"required,oneof=open click"`
+}
+
+// TemplateVariantStats represents engagement with one variant of a template experiment
+type TemplateVariantStats struct {
+	Variant   string  `json:"variant"`
+	Subject   string  `json:"subject"`
+	Weight    int     `json:"weight"`
+	Sent      int64   `json:"sent"`
+	Opened    int64   `json:"opened"`
+	Clicked   int64   `json:"clicked"`
+	OpenRate  float64 `json:"open_rate"`  // Доля открытых от отправленных, 0..1
+	ClickRate float64 `json:"click_rate"` // Доля переходов от отправленных, 0..1
+}
+
+// TemplateExperimentReport compares variants of a template experiment
+type TemplateExperimentReport struct {
+	Experiment *TemplateExperiment     `json:"experiment"`
+	Variants   []*TemplateVariantStats `json:"variants"`
+	Leader     string                  `json:"leader,omitempty"` // Вариант с лучшей долей открытий, пусто пока ничего не отправлено
+}
+
+// CreateCampaignRequest represents request for scheduling an announcement campaign
+type CreateCampaignRequest struct {
+	Name     string                `json:"name" binding:"required,min=1,max=100"`
+	Title    string                `json:"title" binding:"required,min=1,max=255"`
+	Content  string                `json:"content" binding:"required,min=1,max=5000"`
+	Priority *NotificationPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical"`
+	Channels []DeliveryChannel     `json:"channels,omitempty" binding:"omitempty,max=6,dive,oneof=in_app email push sms slack webhook"`
+	Sends    []CampaignSendRequest `json:"sends" binding:"required,min=1,max=50,dive"`
+}
+
+// CampaignSendRequest represents a scheduled send of a campaign to an audience segment
+type CampaignSendRequest struct {
+	Segment     string    `json:"segment" binding:"required,min=1,max=100"`
+	UserIDs     []uint    `json:"user_ids" binding:"required,min=1,max=10000,dive,min=1"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+}
+
+// CampaignFilterRequest represents filtering parameters for announcement campaigns
+type CampaignFilterRequest struct {
+	Status *CampaignStatus `form:"status" binding:"omitempty,oneof=scheduled completed cancelled"`
+	Limit  int             `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int             `form:"offset" binding:"omitempty,min=0"`
+}
+
+// CampaignCalendarRequest represents the period of the campaign calendar, by default the next 30 days
+type CampaignCalendarRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// PreferenceSource tells which layer an effective preference comes from
+type PreferenceSource string
+
+const (
+	PreferenceSourceUser    PreferenceSource = "user"    // Настройка пользователя
+	PreferenceSourceDefault PreferenceSource = "default" // Значение по умолчанию организации
+	PreferenceSourceSystem  PreferenceSource = "system"  // Встроенное значение по умолчанию
+)
+
 // Response Models
 
+// PresentationCatalogResponse represents the allowed presentation values and the defaults per notification type
+type PresentationCatalogResponse struct {
+	Sounds       []NotificationSound                           `json:"sounds"`
+	BannerStyles []BannerStyle                                 `json:"banner_styles"`
+	Defaults     map[NotificationType]NotificationPresentation `json:"defaults"`
+}
+
+// EffectivePreferenceResponse represents the preference applied to a user's notifications of one type
+type EffectivePreferenceResponse struct {
+	Source PreferenceSource `json:"source"`
+	*UserNotificationPreference
+}
+
 // NotificationResponse represents a notification in API responses
 type NotificationResponse struct {
 	ID               uint                           `json:"id"`
@@ -322,6 +1146,8 @@ type NotificationResponse struct {
 	ImageURL         string                         `json:"image_url,omitempty"`
 	ScheduledAt      *time.Time                     `json:"scheduled_at,omitempty"`
 	ExpiresAt        *time.Time                     `json:"expires_at,omitempty"`
+	DuplicateCount   int                            `json:"duplicate_count,omitempty"`
+	LastDuplicateAt  *time.Time                     `json:"last_duplicate_at,omitempty"`
 	CreatedAt        time.Time                      `json:"created_at"`
 	UpdatedAt        time.Time                      `json:"updated_at"`
 	DeliveryChannels []NotificationDeliveryResponse `json:"delivery_channels,omitempty"`
@@ -368,6 +1194,9 @@ func (n *Notification) ToResponse() *NotificationResponse {
 		ExpiresAt:   n.ExpiresAt,
 		CreatedAt:   n.CreatedAt,
 		UpdatedAt:   n.UpdatedAt,
+
+		DuplicateCount:  n.DuplicateCount,
+		LastDuplicateAt: n.LastDuplicateAt,
 	}
 
 	// Convert delivery channels if loaded
@@ -388,3 +1217,150 @@ func (n *Notification) ToResponse() *NotificationResponse {
 
 	return response
 }
+
+// DeliveryStatus converts EmailSuppression model to EmailDeliveryStatus
+func (s *EmailSuppression) DeliveryStatus() *EmailDeliveryStatus {
+	return &EmailDeliveryStatus{
+		Status:       s.Status,
+		Suppressed:   s.Suppressed,
+		SuppressedAt: s.SuppressedAt,
+	}
+}
+
+// CampaignCalendarSend is a scheduled send in the campaign calendar
+type CampaignCalendarSend struct {
+	SendID       uint               `json:"send_id"`
+	CampaignID   uint               `json:"campaign_id"`
+	CampaignName string             `json:"campaign_name"`
+	Segment      string             `json:"segment"`
+	Recipients   int                `json:"recipients"`
+	ScheduledAt  time.Time          `json:"scheduled_at"`
+	Status       CampaignSendStatus `json:"status"`
+}
+
+// CampaignConflict warns that users are reached by too many campaigns in one day
+type CampaignConflict struct {
+	Date        time.Time `json:"date"`
+	CampaignIDs []uint    `json:"campaign_ids"`
+	Users       int       `json:"users"`        // Сколько пользователей получат больше CampaignDailyLimit кампаний
+	MaxPerUser  int       `json:"max_per_user"` // Наибольшее число кампаний у одного пользователя
+}
+
+// CampaignCalendarDay represents scheduled sends of one day
+type CampaignCalendarDay struct {
+	Date     time.Time               `json:"date"`
+	Sends    []*CampaignCalendarSend `json:"sends"`
+	Conflict *CampaignConflict       `json:"conflict,omitempty"`
+}
+
+// CampaignCalendar represents scheduled announcement sends by day
+type CampaignCalendar struct {
+	From      time.Time              `json:"from"`
+	To        time.Time              `json:"to"`
+	Days      []*CampaignCalendarDay `json:"days"`
+	Conflicts []*CampaignConflict    `json:"conflicts"`
+}
+
+// NotificationEventType represents the type of a real-time notification event
+type NotificationEventType string
+
+const (
+	NotificationEventCreated     NotificationEventType = "notification.created"      // Новое уведомление
+	NotificationEventRead        NotificationEventType = "notification.read"         // Уведомления прочитаны на другом устройстве
+	NotificationEventUnreadCount NotificationEventType = "notification.unread_count" // Массовое изменение, детали через sync
+)
+
+// MaxSyncLimit bounds how many changes one sync request returns
+const MaxSyncLimit = 500
+
+// NotificationEvent is sent to all connected devices of a user when their notifications change
+type NotificationEvent struct {
+	Type            NotificationEventType `json:"type"`
+	Notification    *NotificationResponse `json:"notification,omitempty"`
+	NotificationIDs []uint                `json:"notification_ids,omitempty"`
+	UnreadCount     int64                 `json:"unread_count"`
+	At              time.Time             `json:"at"`
+}
+
+// NotificationSyncRequest represents a request for notification changes since the last sync
+type NotificationSyncRequest struct {
+	Since   *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"` // synced_at предыдущего ответа
+	AfterID uint       `form:"after_id"`                                      // after_id предыдущего ответа
+	Limit   int        `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// NotificationReadState is a compact read change of a notification the device already has
+type NotificationReadState struct {
+	ID     uint       `json:"id"`
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
+
+// NotificationSyncResponse represents notification changes since the last sync.
+// Synced_at and after_id are passed to the next sync request.
+type NotificationSyncResponse struct {
+	Notifications []*NotificationResponse `json:"notifications"` // Новые и изменённые уведомления
+	Read          []NotificationReadState `json:"read"`          // Прочитанные уведомления, известные устройству
+	UnreadCount   int64                   `json:"unread_count"`
+	SyncedAt      time.Time               `json:"synced_at"`
+	AfterID       uint                    `json:"after_id,omitempty"`
+	HasMore       bool                    `json:"has_more"`
+}
+
+// Notification history export formats
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// ExportNotificationsRequest represents a request for the user's notification history.
+// Without a range the whole history kept by the retention policy is exported.
+type ExportNotificationsRequest struct {
+	Format string     `form:"format" binding:"omitempty,oneof=csv json"`
+	From   *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"` // Первый день, включительно
+	To     *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`   // Последний день, включительно
+}
+
+// ExportColumns are header cells of CSV notification history exports
+var ExportColumns = []string{"id", "type", "title", "message", "priority", "status", "is_read", "read_at", "created_at"}
+
+// ExportRow returns cells of the notification in ExportColumns order
+func (n *NotificationResponse) ExportRow() []string {
+	readAt := ""
+	if n.ReadAt != nil {
+		readAt = n.ReadAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		strconv.FormatUint(uint64(n.ID), 10),
+		string(n.Type),
+		n.Title,
+		n.Message,
+		string(n.Priority),
+		string(n.Status),
+		strconv.FormatBool(n.IsRead),
+		readAt,
+		n.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// Models returns all database models of the service for migrations
+func Models() []interface{} {
+	return []interface{}{
+		&Notification{},
+		&NotificationDelivery{},
+		&EmailTemplate{},
+		&UserNotificationPreference{},
+		&NotificationTemplate{},
+		&EmailSuppression{},
+		&NotificationView{},
+		&PushDevice{},
+		&NotificationPreferenceDefault{},
+		&EmailOutboxEntry{},
+		&NotificationFallbackPolicy{},
+		&NotificationFallback{},
+		&TemplateExperiment{},
+		&TemplateEngagementEvent{},
+		&AnnouncementCampaign{},
+		&CampaignSend{},
+		&DailyDigest{},
+	}
+}
@@ -0,0 +1,47 @@
+package push
+
+import (
+	"tachyon-messenger/services/notification/models"
+)
+
+// Sender defines the interface for sending push notifications to devices
+type Sender interface {
+	// Send sends the payload to the devices. Tokens rejected by APNs or FCM are reported in the
+	// result, so the devices can be removed.
+	Send(devices []*models.PushDevice, payload *Payload) (*SendResult, error)
+}
+
+// Payload represents a push notification independent of the platform
+type Payload struct {
+	Title      string            `json:"title,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	DeepLink   string            `json:"deep_link,omitempty"`   // Ссылка, открываемая по нажатию, например tachyon://tasks/42
+	CollapseID string            `json:"collapse_id,omitempty"` // Новое уведомление с тем же ключом заменяет предыдущее
+	Badge      *int64            `json:"badge,omitempty"`       // Число непрочитанных на иконке приложения
+	Silent     bool              `json:"silent"`                // Только данные, приложение синхронизируется без показа уведомления
+	Data       map[string]string `json:"data,omitempty"`
+	APNS       APNSOverrides     `json:"apns"`
+	FCM        FCMOverrides      `json:"fcm"`
+}
+
+// APNSOverrides holds payload fields applied only on iOS
+type APNSOverrides struct {
+	Category          string `json:"category,omitempty"`           // Категория с действиями уведомления
+	ThreadID          string `json:"thread_id,omitempty"`          // Группировка уведомлений в центре уведомлений
+	Sound             string `json:"sound,omitempty"`              // Файл звука в приложении, пусто - без звука
+	InterruptionLevel string `json:"interruption_level,omitempty"` // passive, active или time-sensitive
+}
+
+// FCMOverrides holds payload fields applied only on Android
+type FCMOverrides struct {
+	ChannelID string `json:"channel_id,omitempty"` // Канал уведомлений Android
+	Priority  string `json:"priority,omitempty"`   // high или normal
+	Sound     string `json:"sound,omitempty"`      // Ресурс звука в приложении, пусто - без звука
+}
+
+// SendResult represents the outcome of sending a payload to several devices
+type SendResult struct {
+	Sent          int      `json:"sent"`
+	InvalidTokens []string `json:"invalid_tokens,omitempty"`
+	Errors        []string `json:"errors,omitempty"`
+}
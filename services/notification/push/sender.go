@@ -0,0 +1,235 @@
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+)
+
+// Platforms of the push gateway API
+const (
+	gatewayPlatformIOS     = 1
+	gatewayPlatformAndroid = 2
+)
+
+// invalidTokenErrors are APNs and FCM errors meaning the token will never work again
+var invalidTokenErrors = []string{
+	"BadDeviceToken",
+	"Unregistered",
+	"DeviceTokenNotForTopic",
+	"NotRegistered",
+	"InvalidRegistration",
+	"registration-token-not-registered",
+}
+
+// GatewayConfig holds configuration of the push gateway
+type GatewayConfig struct {
+	URL       string        `json:"url"`        // Адрес шлюза, совместимого с gorush
+	APNSTopic string        `json:"apns_topic"` // Bundle ID iOS приложения
+	Timeout   time.Duration `json:"timeout"`
+}
+
+// GetGatewayConfigFromEnv loads push gateway configuration from environment variables
+func GetGatewayConfigFromEnv() *GatewayConfig {
+	config := &GatewayConfig{
+		URL:       strings.TrimRight(strings.TrimSpace(os.Getenv("PUSH_GATEWAY_URL")), "/"),
+		APNSTopic: os.Getenv("PUSH_APNS_TOPIC"),
+		Timeout:   10 * time.Second,
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("PUSH_GATEWAY_TIMEOUT")); err == nil && timeout > 0 {
+		config.Timeout = timeout
+	}
+	return config
+}
+
+// gatewaySender sends push notifications through a gorush compatible gateway, which holds the
+// APNs and FCM credentials. The gateway must run in sync mode to report rejected tokens.
+type gatewaySender struct {
+	config *GatewayConfig
+	client *http.Client
+}
+
+// NewGatewaySender creates a sender for the push gateway. It returns nil if the gateway URL is
+// not configured, push deliveries then fail.
+func NewGatewaySender(config *GatewayConfig) Sender {
+	if config == nil || config.URL == "" {
+		return nil
+	}
+	return &gatewaySender{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// gatewayNotification is a notification of the push gateway API
+type gatewayNotification struct {
+	Tokens           []string               `json:"tokens"`
+	Platform         int                    `json:"platform"`
+	Title            string                 `json:"title,omitempty"`
+	Message          string                 `json:"message,omitempty"`
+	Badge            *int64                 `json:"badge,omitempty"`
+	Data             map[string]interface{} `json:"data,omitempty"`
+	ContentAvailable bool                   `json:"content_available,omitempty"`
+	Priority         string                 `json:"priority,omitempty"`
+
+	// iOS
+	Topic             string `json:"topic,omitempty"`
+	Category          string `json:"category,omitempty"`
+	ThreadID          string `json:"thread-id,omitempty"`
+	Sound             string `json:"sound,omitempty"`
+	InterruptionLevel string `json:"interruption_level,omitempty"`
+	CollapseID        string `json:"collapse_id,omitempty"`
+	PushType          string `json:"push_type,omitempty"`
+
+	// Android
+	CollapseKey  string                 `json:"collapse_key,omitempty"`
+	Notification map[string]interface{} `json:"notification,omitempty"`
+}
+
+// gatewayResponse is the response of the push gateway in sync mode
+type gatewayResponse struct {
+	Logs []struct {
+		Type  string `json:"type"`
+		Token string `json:"token"`
+		Error string `json:"error"`
+	} `json:"logs"`
+}
+
+// Send sends the payload to the devices in one gateway request per platform
+func (s *gatewaySender) Send(devices []*models.PushDevice, payload *Payload) (*SendResult, error) {
+	tokens := make(map[models.PushPlatform][]string)
+	for _, device := range devices {
+		tokens[device.Platform] = append(tokens[device.Platform], device.Token)
+	}
+
+	var notifications []*gatewayNotification
+	if len(tokens[models.PushPlatformIOS]) > 0 {
+		notifications = append(notifications, s.iosNotification(tokens[models.PushPlatformIOS], payload))
+	}
+	if len(tokens[models.PushPlatformAndroid]) > 0 {
+		notifications = append(notifications, s.androidNotification(tokens[models.PushPlatformAndroid], payload))
+	}
+	if len(notifications) == 0 {
+		return &SendResult{}, nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"notifications": notifications})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode push request: %w", err)
+	}
+
+	resp, err := s.client.Post(s.config.URL+"/api/push", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach push gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("push gateway responded with status %d", resp.StatusCode)
+	}
+
+	var response gatewayResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode push gateway response: %w", err)
+	}
+
+	result := &SendResult{Sent: len(devices)}
+	for _, log := range response.Logs {
+		if log.Type != "failed-push" {
+			continue
+		}
+		result.Sent--
+		result.Errors = append(result.Errors, log.Error)
+		if isInvalidTokenError(log.Error) {
+			result.InvalidTokens = append(result.InvalidTokens, log.Token)
+		}
+	}
+	return result, nil
+}
+
+// iosNotification builds the gateway notification for APNs. Silent pushes are background pushes
+// with content-available and nothing to display.
+func (s *gatewaySender) iosNotification(tokens []string, payload *Payload) *gatewayNotification {
+	notification := &gatewayNotification{
+		Tokens:     tokens,
+		Platform:   gatewayPlatformIOS,
+		Badge:      payload.Badge,
+		Data:       gatewayData(payload),
+		Topic:      s.config.APNSTopic,
+		CollapseID: payload.CollapseID,
+	}
+	if payload.Silent {
+		notification.ContentAvailable = true
+		notification.PushType = "background"
+		notification.Priority = "normal"
+		return notification
+	}
+
+	notification.Title = payload.Title
+	notification.Message = payload.Body
+	notification.Category = payload.APNS.Category
+	notification.ThreadID = payload.APNS.ThreadID
+	notification.Sound = payload.APNS.Sound
+	notification.InterruptionLevel = payload.APNS.InterruptionLevel
+	notification.PushType = "alert"
+	notification.Priority = "high"
+	return notification
+}
+
+// androidNotification builds the gateway notification for FCM. Silent pushes are data messages,
+// the app handles them without showing anything.
+func (s *gatewaySender) androidNotification(tokens []string, payload *Payload) *gatewayNotification {
+	notification := &gatewayNotification{
+		Tokens:      tokens,
+		Platform:    gatewayPlatformAndroid,
+		Data:        gatewayData(payload),
+		Priority:    payload.FCM.Priority,
+		CollapseKey: payload.CollapseID,
+	}
+	if payload.Badge != nil {
+		notification.Data["badge"] = *payload.Badge
+	}
+	if payload.Silent {
+		return notification
+	}
+
+	notification.Notification = map[string]interface{}{
+		"title":        payload.Title,
+		"body":         payload.Body,
+		"channel_id":   payload.FCM.ChannelID,
+		"click_action": payload.DeepLink,
+		"tag":          payload.CollapseID,
+	}
+	if payload.FCM.Sound != "" {
+		notification.Notification["sound"] = payload.FCM.Sound
+	}
+	return notification
+}
+
+// gatewayData returns the custom data of the payload
+func gatewayData(payload *Payload) map[string]interface{} {
+	data := make(map[string]interface{}, len(payload.Data)+1)
+	for key, value := range payload.Data {
+		data[key] = value
+	}
+	if payload.DeepLink != "" {
+		data["deep_link"] = payload.DeepLink
+	}
+	return data
+}
+
+// isInvalidTokenError checks if a provider error means the token must be removed
+func isInvalidTokenError(message string) bool {
+	for _, invalid := range invalidTokenErrors {
+		if strings.Contains(message, invalid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package push
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"tachyon-messenger/services/notification/models"
+)
+
+// maxBodyLength limits the body of a push notification, longer text is cut by the devices anyway
+const maxBodyLength = 200
+
+// SyncCollapseID collapses silent sync pushes, only the latest one is kept while a device is offline
+const SyncCollapseID = "sync"
+
+// Template describes the push payload of a notification type. Fields are text/template
+// strings over TemplateData.
+type Template struct {
+	Title        string
+	Body         string
+	DeepLink     string
+	CollapseID   string // Пусто — уведомления не заменяют друг друга
+	APNSCategory string
+	APNSThreadID string
+	FCMChannelID string
+}
+
+// TemplateData contains the notification fields available in templates
+type TemplateData struct {
+	ID          uint
+	Type        models.NotificationType
+	Title       string
+	Message     string
+	RelatedID   uint // 0 если уведомление не связано с объектом
+	RelatedType string
+	ActionURL   string
+}
+
+// notificationDeepLink opens the notification itself, links needing a related object fall back to it
+const notificationDeepLink = "tachyon://notifications/{{.ID}}"
+
+// DefaultTemplates contains push templates of notification types
+var DefaultTemplates = map[models.NotificationType]*Template{
+	models.NotificationTypeMessage: {
+		DeepLink:     "tachyon://chats/{{.RelatedID}}",
+		CollapseID:   "chat-{{.RelatedID}}",
+		APNSCategory: "MESSAGE",
+		APNSThreadID: "chat-{{.RelatedID}}",
+		FCMChannelID: "messages",
+	},
+	models.NotificationTypeMention: {
+		DeepLink:     "tachyon://chats/{{.RelatedID}}",
+		APNSCategory: "MESSAGE",
+		APNSThreadID: "chat-{{.RelatedID}}",
+		FCMChannelID: "mentions",
+	},
+	models.NotificationTypeTask: {
+		DeepLink:     "tachyon://tasks/{{.RelatedID}}",
+		CollapseID:   "task-{{.RelatedID}}",
+		APNSCategory: "TASK",
+		APNSThreadID: "tasks",
+		FCMChannelID: "tasks",
+	},
+	models.NotificationTypeCalendar: {
+		DeepLink:     "tachyon://calendar/events/{{.RelatedID}}",
+		CollapseID:   "event-{{.RelatedID}}",
+		APNSCategory: "EVENT",
+		APNSThreadID: "calendar",
+		FCMChannelID: "calendar",
+	},
+	models.NotificationTypePoll: {
+		DeepLink:     "tachyon://polls/{{.RelatedID}}",
+		CollapseID:   "poll-{{.RelatedID}}",
+		APNSCategory: "POLL",
+		FCMChannelID: "polls",
+	},
+	models.NotificationTypeReminder: {
+		DeepLink:     `{{if eq .RelatedType "chat"}}tachyon://chats/{{.RelatedID}}{{else if eq .RelatedType "event"}}tachyon://calendar/events/{{.RelatedID}}{{end}}`,
+		APNSCategory: "REMINDER",
+		FCMChannelID: "reminders",
+	},
+	models.NotificationTypeAnnounce: {
+		DeepLink:     notificationDeepLink,
+		FCMChannelID: "announcements",
+	},
+	models.NotificationTypeSecurity: {
+		DeepLink:     "tachyon://settings/security",
+		CollapseID:   "security",
+		FCMChannelID: "security",
+	},
+	models.NotificationTypeSystem: {
+		DeepLink:     notificationDeepLink,
+		FCMChannelID: "system",
+	},
+}
+
+// defaultTemplate is used for notification types without a template
+var defaultTemplate = &Template{DeepLink: notificationDeepLink, FCMChannelID: "system"}
+
+// Render builds the push payload of a notification with its type template and the presentation
+// the user chose for the type. Title and body default to the notification title and message.
+// Links and keys of templates that need a related object fall back to the notification itself
+// when there is none.
+func Render(notification *models.Notification, badge int64, presentation models.NotificationPresentation) (*Payload, error) {
+	tmpl, ok := DefaultTemplates[notification.Type]
+	if !ok {
+		tmpl = defaultTemplate
+	}
+
+	data := &TemplateData{
+		ID:          notification.ID,
+		Type:        notification.Type,
+		Title:       notification.Title,
+		Message:     notification.Message,
+		RelatedType: notification.RelatedType,
+		ActionURL:   notification.ActionURL,
+	}
+	if notification.RelatedID != nil {
+		data.RelatedID = *notification.RelatedID
+	}
+
+	r := &fieldRenderer{data: data}
+	payload := &Payload{
+		Title:      r.render("title", tmpl.Title),
+		Body:       r.render("body", tmpl.Body),
+		DeepLink:   r.render("deep_link", tmpl.DeepLink),
+		CollapseID: r.render("collapse_id", tmpl.CollapseID),
+		Badge:      &badge,
+		Data: map[string]string{
+			"notification_id": strconv.FormatUint(uint64(notification.ID), 10),
+			"type":            string(notification.Type),
+		},
+		APNS: APNSOverrides{
+			Category: r.render("apns_category", tmpl.APNSCategory),
+			ThreadID: r.render("apns_thread_id", tmpl.APNSThreadID),
+		},
+		FCM: FCMOverrides{
+			ChannelID: r.render("fcm_channel_id", tmpl.FCMChannelID),
+			Priority:  "normal",
+		},
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if payload.Title == "" {
+		payload.Title = notification.Title
+	}
+	if payload.Body == "" {
+		payload.Body = notification.Message
+	}
+	if runes := []rune(payload.Body); len(runes) > maxBodyLength {
+		payload.Body = string(runes[:maxBodyLength-1]) + "…"
+	}
+	if payload.DeepLink == "" {
+		payload.DeepLink = fmt.Sprintf("tachyon://notifications/%d", notification.ID)
+	}
+	if data.RelatedID != 0 {
+		payload.Data["related_id"] = strconv.FormatUint(uint64(data.RelatedID), 10)
+		payload.Data["related_type"] = data.RelatedType
+	}
+
+	switch notification.Priority {
+	case models.NotificationPriorityHigh, models.NotificationPriorityCritical:
+		payload.FCM.Priority = "high"
+	}
+	applyPresentation(payload, presentation)
+
+	return payload, nil
+}
+
+// applyPresentation sets the sound and interruption level of the payload. Apps get the chosen
+// values in data as well, Android apps showing notifications themselves rely on them.
+func applyPresentation(payload *Payload, presentation models.NotificationPresentation) {
+	sound := presentation.Sound
+	switch presentation.BannerStyle {
+	case models.BannerStyleAlert:
+		payload.APNS.InterruptionLevel = "time-sensitive"
+	case models.BannerStyleNone:
+		payload.APNS.InterruptionLevel = "passive"
+		sound = models.SoundNone
+	default:
+		payload.APNS.InterruptionLevel = "active"
+	}
+
+	switch sound {
+	case "", models.SoundDefault:
+		payload.APNS.Sound = models.SoundDefault
+		payload.FCM.Sound = models.SoundDefault
+	case models.SoundNone:
+	default:
+		payload.APNS.Sound = sound + ".caf"
+		payload.FCM.Sound = sound
+	}
+
+	payload.Data["sound"] = sound
+	payload.Data["banner_style"] = string(presentation.BannerStyle)
+}
+
+// SyncPayload builds a silent data-only push telling the apps of the user to sync notifications,
+// e.g. after they were read on another device. The badge may count fewer notifications than
+// unreadCount when the user excluded some types from it.
+func SyncPayload(event models.NotificationEventType, unreadCount, badge int64) *Payload {
+	return &Payload{
+		CollapseID: SyncCollapseID,
+		Badge:      &badge,
+		Silent:     true,
+		Data: map[string]string{
+			"event":        string(event),
+			"unread_count": strconv.FormatInt(unreadCount, 10),
+		},
+		FCM: FCMOverrides{Priority: "normal"},
+	}
+}
+
+// fieldRenderer renders template fields of one notification, keeping the first error
+type fieldRenderer struct {
+	data *TemplateData
+	err  error
+}
+
+// render executes a template field. Fields referring to the related object are empty when the
+// notification has none.
+func (r *fieldRenderer) render(name, text string) string {
+	if text == "" || r.err != nil || (r.data.RelatedID == 0 && strings.Contains(text, ".RelatedID")) {
+		return ""
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		r.err = fmt.Errorf("failed to parse push template %s: %w", name, err)
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r.data); err != nil {
+		r.err = fmt.Errorf("failed to render push template %s: %w", name, err)
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,175 @@
+// File: services/notification/repository/announcement_campaign.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateCampaign creates an announcement campaign with its scheduled sends
+func (r *notificationRepository) CreateCampaign(campaign *models.AnnouncementCampaign) error {
+	if err := r.db.Create(campaign).Error; err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+	return nil
+}
+
+// GetCampaign retrieves an announcement campaign with its sends in schedule order
+func (r *notificationRepository) GetCampaign(id uint) (*models.AnnouncementCampaign, error) {
+	var campaign models.AnnouncementCampaign
+	err := r.db.Preload("Sends", func(db *gorm.DB) *gorm.DB {
+		return db.Order("scheduled_at ASC, id ASC")
+	}).First(&campaign, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("campaign not found")
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+// GetCampaignsByIDs retrieves announcement campaigns without their sends
+func (r *notificationRepository) GetCampaignsByIDs(ids []uint) ([]*models.AnnouncementCampaign, error) {
+	var campaigns []*models.AnnouncementCampaign
+	if len(ids) == 0 {
+		return campaigns, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&campaigns).Error; err != nil {
+		return nil, fmt.Errorf("failed to get campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+// ListCampaigns lists announcement campaigns without their sends, latest first
+func (r *notificationRepository) ListCampaigns(filter *models.CampaignFilterRequest) ([]*models.AnnouncementCampaign, int64, error) {
+	query := r.db.Model(&models.AnnouncementCampaign{})
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+
+	var campaigns []*models.AnnouncementCampaign
+	if err := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Offset(filter.Offset).
+		Find(&campaigns).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get campaigns: %w", err)
+	}
+	return campaigns, total, nil
+}
+
+// GetCampaignSendsBetween returns sends scheduled in [from, to) that were not cancelled, in schedule order
+func (r *notificationRepository) GetCampaignSendsBetween(from, to time.Time) ([]*models.CampaignSend, error) {
+	var sends []*models.CampaignSend
+	err := r.db.Where("scheduled_at >= ? AND scheduled_at < ? AND status <> ?", from, to, models.CampaignSendStatusCancelled).
+		Order("scheduled_at ASC, id ASC").
+		Find(&sends).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign sends: %w", err)
+	}
+	return sends, nil
+}
+
+// ClaimDueCampaignSends returns pending sends that are due and marks them as sending,
+// so other worker instances skip them
+func (r *notificationRepository) ClaimDueCampaignSends(now time.Time, limit int) ([]*models.CampaignSend, error) {
+	var due []*models.CampaignSend
+	err := r.db.Where("status = ? AND scheduled_at <= ?", models.CampaignSendStatusPending, now).
+		Order("scheduled_at ASC, id ASC").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due campaign sends: %w", err)
+	}
+
+	claimed := make([]*models.CampaignSend, 0, len(due))
+	for _, send := range due {
+		result := r.db.Model(&models.CampaignSend{}).
+			Where("id = ? AND status = ?", send.ID, models.CampaignSendStatusPending).
+			Updates(map[string]interface{}{
+				"status":     models.CampaignSendStatusSending,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return claimed, fmt.Errorf("failed to claim campaign send: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			send.Status = models.CampaignSendStatusSending
+			claimed = append(claimed, send)
+		}
+	}
+	return claimed, nil
+}
+
+// UpdateCampaignSend saves the state of a campaign send
+func (r *notificationRepository) UpdateCampaignSend(send *models.CampaignSend) error {
+	if err := r.db.Save(send).Error; err != nil {
+		return fmt.Errorf("failed to update campaign send: %w", err)
+	}
+	return nil
+}
+
+// CancelCampaignSends cancels pending sends of a campaign, or only sendID if given.
+// It returns the number of cancelled sends.
+func (r *notificationRepository) CancelCampaignSends(campaignID uint, sendID *uint) (int64, error) {
+	query := r.db.Model(&models.CampaignSend{}).
+		Where("campaign_id = ? AND status = ?", campaignID, models.CampaignSendStatusPending)
+	if sendID != nil {
+		query = query.Where("id = ?", *sendID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":     models.CampaignSendStatusCancelled,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to cancel campaign sends: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// FinishCampaign closes a scheduled campaign that has no pending or running sends left. It is marked
+// cancelled if cancelled is set or none of its sends went out, completed otherwise.
+func (r *notificationRepository) FinishCampaign(campaignID uint, cancelled bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var open int64
+		if err := tx.Model(&models.CampaignSend{}).
+			Where("campaign_id = ? AND status IN ?", campaignID,
+				[]models.CampaignSendStatus{models.CampaignSendStatusPending, models.CampaignSendStatusSending}).
+			Count(&open).Error; err != nil {
+			return fmt.Errorf("failed to count open campaign sends: %w", err)
+		}
+		if open > 0 {
+			return nil
+		}
+
+		status := models.CampaignStatusCancelled
+		if !cancelled {
+			var delivered int64
+			if err := tx.Model(&models.CampaignSend{}).
+				Where("campaign_id = ? AND status IN ?", campaignID,
+					[]models.CampaignSendStatus{models.CampaignSendStatusSent, models.CampaignSendStatusFailed}).
+				Count(&delivered).Error; err != nil {
+				return fmt.Errorf("failed to count finished campaign sends: %w", err)
+			}
+			if delivered > 0 {
+				status = models.CampaignStatusCompleted
+			}
+		}
+
+		if err := tx.Model(&models.AnnouncementCampaign{}).
+			Where("id = ? AND status = ?", campaignID, models.CampaignStatusScheduled).
+			Update("status", status).Error; err != nil {
+			return fmt.Errorf("failed to finish campaign: %w", err)
+		}
+		return nil
+	})
+}
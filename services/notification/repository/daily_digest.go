@@ -0,0 +1,50 @@
+// File: services/notification/repository/daily_digest.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetDigestRecipients returns system notification preferences of users who enabled the daily digest
+func (r *notificationRepository) GetDigestRecipients() ([]*models.UserNotificationPreference, error) {
+	var preferences []*models.UserNotificationPreference
+	err := r.db.
+		Where("notification_type = ? AND digest_enabled = ?", models.NotificationTypeSystem, true).
+		Order("user_id ASC").
+		Find(&preferences).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest recipients: %w", err)
+	}
+	return preferences, nil
+}
+
+// GetLastDailyDigest returns the latest daily digest sent to a user, nil if none was sent
+func (r *notificationRepository) GetLastDailyDigest(userID uint) (*models.DailyDigest, error) {
+	var digest models.DailyDigest
+	err := r.db.Where("user_id = ?", userID).Order("sent_at DESC").First(&digest).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last daily digest: %w", err)
+	}
+	return &digest, nil
+}
+
+// RecordDailyDigest records a sent daily digest, a digest already recorded for the day is kept as is
+func (r *notificationRepository) RecordDailyDigest(digest *models.DailyDigest) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoNothing: true,
+	}).Create(digest).Error
+	if err != nil {
+		return fmt.Errorf("failed to record daily digest: %w", err)
+	}
+	return nil
+}
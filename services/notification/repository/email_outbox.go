@@ -0,0 +1,87 @@
+// File: services/notification/repository/email_outbox.go
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueEmailOutbox adds an email delivery to the outbox, a delivery already waiting there is kept as is
+func (r *notificationRepository) EnqueueEmailOutbox(entry *models.EmailOutboxEntry) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "delivery_id"}},
+		DoNothing: true,
+	}).Create(entry).Error
+	if err != nil {
+		return fmt.Errorf("failed to queue email in outbox: %w", err)
+	}
+	return nil
+}
+
+// GetEmailOutbox returns up to limit outbox entries oldest first. With dueOnly set only entries
+// whose next attempt is at or before now are returned.
+func (r *notificationRepository) GetEmailOutbox(now time.Time, dueOnly bool, limit int) ([]*models.EmailOutboxEntry, error) {
+	var entries []*models.EmailOutboxEntry
+	query := r.db.Order("id ASC").Limit(limit)
+	if dueOnly {
+		query = query.Where("next_attempt_at <= ?", now)
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get email outbox: %w", err)
+	}
+	return entries, nil
+}
+
+// RetryEmailOutboxEntry postpones an outbox entry that failed again until nextAttemptAt
+func (r *notificationRepository) RetryEmailOutboxEntry(id uint, lastError string, nextAttemptAt time.Time) error {
+	err := r.db.Model(&models.EmailOutboxEntry{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to postpone email outbox entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteEmailOutboxEntry removes an outbox entry that was sent or given up
+func (r *notificationRepository) DeleteEmailOutboxEntry(id uint) error {
+	if err := r.db.Delete(&models.EmailOutboxEntry{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete email outbox entry: %w", err)
+	}
+	return nil
+}
+
+// GetEmailOutboxStats returns the size of the outbox and its oldest and next entries
+func (r *notificationRepository) GetEmailOutboxStats(now time.Time) (*models.EmailOutboxStatus, error) {
+	status := &models.EmailOutboxStatus{}
+	if err := r.db.Model(&models.EmailOutboxEntry{}).Count(&status.Pending).Error; err != nil {
+		return nil, fmt.Errorf("failed to count email outbox: %w", err)
+	}
+	if status.Pending == 0 {
+		return status, nil
+	}
+
+	if err := r.db.Model(&models.EmailOutboxEntry{}).Where("next_attempt_at <= ?", now).Count(&status.Due).Error; err != nil {
+		return nil, fmt.Errorf("failed to count due email outbox entries: %w", err)
+	}
+
+	var oldest, next models.EmailOutboxEntry
+	if err := r.db.Order("created_at ASC").First(&oldest).Error; err != nil {
+		return nil, fmt.Errorf("failed to get oldest email outbox entry: %w", err)
+	}
+	if err := r.db.Order("next_attempt_at ASC").First(&next).Error; err != nil {
+		return nil, fmt.Errorf("failed to get next email outbox entry: %w", err)
+	}
+	status.OldestQueuedAt = &oldest.CreatedAt
+	status.NextAttemptAt = &next.NextAttemptAt
+	return status, nil
+}
@@ -0,0 +1,112 @@
+// File: services/notification/repository/email_suppression.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+)
+
+// GetEmailSuppression returns the suppression record of an email address, nil if the address never bounced
+func (r *notificationRepository) GetEmailSuppression(email string) (*models.EmailSuppression, error) {
+	var suppression models.EmailSuppression
+	err := r.db.Where("email = ?", strings.ToLower(strings.TrimSpace(email))).First(&suppression).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email suppression: %w", err)
+	}
+	return &suppression, nil
+}
+
+// GetEmailSuppressionByID returns a suppression record by ID
+func (r *notificationRepository) GetEmailSuppressionByID(id uint) (*models.EmailSuppression, error) {
+	var suppression models.EmailSuppression
+	err := r.db.First(&suppression, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("email suppression not found")
+		}
+		return nil, fmt.Errorf("failed to get email suppression: %w", err)
+	}
+	return &suppression, nil
+}
+
+// SaveEmailSuppression creates or updates a suppression record
+func (r *notificationRepository) SaveEmailSuppression(suppression *models.EmailSuppression) error {
+	suppression.Email = strings.ToLower(strings.TrimSpace(suppression.Email))
+	if err := r.db.Save(suppression).Error; err != nil {
+		return fmt.Errorf("failed to save email suppression: %w", err)
+	}
+	return nil
+}
+
+// FindActiveEmailSuppression returns the suppression that blocks email to a user or address, nil if none
+func (r *notificationRepository) FindActiveEmailSuppression(userID uint, email string) (*models.EmailSuppression, error) {
+	var suppression models.EmailSuppression
+	err := r.db.Where("suppressed = ?", true).
+		Where("user_id = ? OR email = ?", userID, strings.ToLower(strings.TrimSpace(email))).
+		Order("suppressed_at DESC").
+		First(&suppression).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find email suppression: %w", err)
+	}
+	return &suppression, nil
+}
+
+// GetUserEmailSuppression returns the most relevant suppression record of a user: a suppressed
+// address first, then the latest bouncing one. Nil if no address of the user has problems.
+func (r *notificationRepository) GetUserEmailSuppression(userID uint) (*models.EmailSuppression, error) {
+	var suppression models.EmailSuppression
+	err := r.db.Where("user_id = ? AND status <> ?", userID, models.EmailAddressStatusOK).
+		Order("suppressed DESC, last_event_at DESC").
+		First(&suppression).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user email suppression: %w", err)
+	}
+	return &suppression, nil
+}
+
+// ListEmailSuppressions returns suppression records matching the filter, latest events first
+func (r *notificationRepository) ListEmailSuppressions(filter *models.EmailSuppressionFilter) ([]*models.EmailSuppression, int64, error) {
+	query := r.db.Model(&models.EmailSuppression{})
+	if email := strings.ToLower(strings.TrimSpace(filter.Email)); email != "" {
+		query = query.Where("email LIKE ?", "%"+email+"%")
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.Suppressed != nil {
+		query = query.Where("suppressed = ?", *filter.Suppressed)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count email suppressions: %w", err)
+	}
+
+	var suppressions []*models.EmailSuppression
+	err := query.Order("last_event_at DESC, id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&suppressions).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list email suppressions: %w", err)
+	}
+
+	return suppressions, total, nil
+}
@@ -0,0 +1,132 @@
+// File: services/notification/repository/fallback.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+)
+
+// GetFallbackPolicies returns fallback policies of all priorities that have one
+func (r *notificationRepository) GetFallbackPolicies() ([]*models.NotificationFallbackPolicy, error) {
+	var policies []*models.NotificationFallbackPolicy
+	if err := r.db.Order("priority ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to get fallback policies: %w", err)
+	}
+	return policies, nil
+}
+
+// GetFallbackPolicy returns the fallback policy of a priority, nil if none is set
+func (r *notificationRepository) GetFallbackPolicy(priority models.NotificationPriority) (*models.NotificationFallbackPolicy, error) {
+	var policy models.NotificationFallbackPolicy
+	err := r.db.Where("priority = ?", priority).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get fallback policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// SaveFallbackPolicy creates or replaces the fallback policy of a priority
+func (r *notificationRepository) SaveFallbackPolicy(policy *models.NotificationFallbackPolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.NotificationFallbackPolicy
+		err := tx.Where("priority = ?", policy.Priority).First(&existing).Error
+		switch {
+		case err == nil:
+			policy.ID = existing.ID
+			policy.CreatedAt = existing.CreatedAt
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to get fallback policy: %w", err)
+		}
+
+		if err := tx.Save(policy).Error; err != nil {
+			return fmt.Errorf("failed to save fallback policy: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteFallbackPolicy removes the fallback policy of a priority. Started chains run to the end.
+func (r *notificationRepository) DeleteFallbackPolicy(priority models.NotificationPriority) error {
+	// Deleted permanently, so the priority can get a new policy under the unique index
+	result := r.db.Unscoped().Where("priority = ?", priority).Delete(&models.NotificationFallbackPolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete fallback policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("fallback policy not found")
+	}
+	return nil
+}
+
+// CreateFallback starts the fallback chain of a notification
+func (r *notificationRepository) CreateFallback(fallback *models.NotificationFallback) error {
+	if err := r.db.Create(fallback).Error; err != nil {
+		return fmt.Errorf("failed to create fallback: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueFallbacks returns active chains whose next step is due and postpones them to leaseUntil,
+// so other worker instances skip them. A chain left by a crashed worker is due again after the lease.
+func (r *notificationRepository) ClaimDueFallbacks(now, leaseUntil time.Time, limit int) ([]*models.NotificationFallback, error) {
+	var due []*models.NotificationFallback
+	err := r.db.Where("state = ? AND next_attempt_at <= ?", models.FallbackStateActive, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due fallbacks: %w", err)
+	}
+
+	claimed := make([]*models.NotificationFallback, 0, len(due))
+	for _, fallback := range due {
+		result := r.db.Model(&models.NotificationFallback{}).
+			Where("id = ? AND state = ? AND next_attempt_at = ?", fallback.ID, models.FallbackStateActive, fallback.NextAttemptAt).
+			Update("next_attempt_at", leaseUntil)
+		if result.Error != nil {
+			return claimed, fmt.Errorf("failed to claim fallback: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			fallback.NextAttemptAt = &leaseUntil
+			claimed = append(claimed, fallback)
+		}
+	}
+	return claimed, nil
+}
+
+// UpdateFallback saves the state of a fallback chain
+func (r *notificationRepository) UpdateFallback(fallback *models.NotificationFallback) error {
+	if err := r.db.Save(fallback).Error; err != nil {
+		return fmt.Errorf("failed to update fallback: %w", err)
+	}
+	return nil
+}
+
+// CancelReadFallbacks cancels active chains of notifications that have been read.
+// Without user IDs chains of all users are checked.
+func (r *notificationRepository) CancelReadFallbacks(userIDs ...uint) (int64, error) {
+	readNotifications := r.db.Model(&models.Notification{}).Select("id").Where("is_read = ?", true)
+	query := r.db.Model(&models.NotificationFallback{}).
+		Where("state = ? AND notification_id IN (?)", models.FallbackStateActive, readNotifications)
+	if len(userIDs) > 0 {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"state":           models.FallbackStateCancelled,
+		"next_attempt_at": nil,
+		"updated_at":      time.Now(),
+	})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to cancel fallbacks: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
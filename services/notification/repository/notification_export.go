@@ -0,0 +1,25 @@
+// File: services/notification/repository/notification_export.go
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+)
+
+// GetNotificationsForExport returns user's notifications created in [from, to) after afterID,
+// ordered by ID. Deleted notifications and those expired at now are left out.
+func (r *notificationRepository) GetNotificationsForExport(userID uint, from, to, now time.Time, afterID uint, limit int) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	err := r.db.Where("user_id = ? AND id > ?", userID, afterID).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications for export: %w", err)
+	}
+	return notifications, nil
+}
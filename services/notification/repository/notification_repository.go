@@ -8,6 +8,8 @@ import (
 
 	"tachyon-messenger/services/notification/models"
 	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/i18n"
+	sharedmodels "tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
 )
@@ -25,12 +27,21 @@ type NotificationRepository interface {
 	GetUserNotifications(userID uint, filter *models.NotificationFilterRequest) ([]*models.Notification, int64, error)
 	GetUnreadCount(userID uint) (int64, error)
 	GetUnreadCountByType(userID uint, notificationType models.NotificationType) (int64, error)
+	GetUnreadCountsByUsers(userIDs []uint) (map[uint]int64, error)
+	GetNotificationChanges(userID uint, since time.Time, afterID uint, limit int) ([]*models.Notification, error)
+	GetNotificationsForExport(userID uint, from, to, now time.Time, afterID uint, limit int) ([]*models.Notification, error)
+
+	// Deduplication
+	FindDuplicateNotification(userID uint, dedupKey string, since time.Time) (*models.Notification, error)
+	IncrementDuplicateCount(notificationID uint, message string) error
 
 	// Mark as read operations
 	MarkAsRead(notificationID, userID uint) error
-	MarkMultipleAsRead(notificationIDs []uint, userID uint) error
+	MarkMultipleAsRead(notificationIDs []uint, userID uint) (int64, error)
 	MarkAllAsRead(userID uint) error
-	MarkAllAsReadByType(userID uint, notificationType models.NotificationType) error
+	MarkAllAsReadByType(userID uint, notificationType models.NotificationType) (int64, error)
+	MarkAsReadByFilter(userID uint, filter *models.MarkAsReadByFilterRequest) (int64, error)
+	MarkAsReadByRelatedObject(req *models.ResolveNotificationsRequest) (int64, error)
 
 	// Scheduled notifications
 	GetScheduledNotifications(before time.Time, limit int) ([]*models.Notification, error)
@@ -39,6 +50,7 @@ type NotificationRepository interface {
 	// Statistics and analytics
 	GetNotificationStats(userID uint) (*models.NotificationStatsResponse, error)
 	GetSystemStats() (*SystemNotificationStats, error)
+	GetDeliveryFailureStats(since time.Time, topTypes int) (*models.DeliveryFailureStats, error)
 
 	// Cleanup operations
 	DeleteOldNotifications(beforeDate time.Time) (int64, error)
@@ -50,16 +62,101 @@ type NotificationRepository interface {
 	UpdateDeliveryStatus(deliveryID uint, status models.NotificationStatus, errorMsg string) error
 	GetPendingDeliveries(limit int) ([]*models.NotificationDelivery, error)
 	GetFailedDeliveries(maxAttempts int, limit int) ([]*models.NotificationDelivery, error)
+	GetHeldDeliveries(channel *models.DeliveryChannel, limit int) ([]*models.NotificationDelivery, error)
+	CountHeldDeliveries() (map[models.DeliveryChannel]int64, error)
+	DiscardHeldDeliveries(channel *models.DeliveryChannel, before *time.Time, reason string) (int64, error)
+	DiscardDelivery(deliveryID uint, reason string) error
+
+	// Email outbox
+	EnqueueEmailOutbox(entry *models.EmailOutboxEntry) error
+	GetEmailOutbox(now time.Time, dueOnly bool, limit int) ([]*models.EmailOutboxEntry, error)
+	RetryEmailOutboxEntry(id uint, lastError string, nextAttemptAt time.Time) error
+	DeleteEmailOutboxEntry(id uint) error
+	GetEmailOutboxStats(now time.Time) (*models.EmailOutboxStatus, error)
 
 	// Search and filtering
 	SearchNotifications(userID uint, query string, filter *models.NotificationFilterRequest) ([]*models.Notification, int64, error)
 	GetNotificationsByRelatedObject(relatedType string, relatedID uint, userID *uint) ([]*models.Notification, error)
 
+	// Admin history queries
+	QueryNotifications(req *models.AdminNotificationQueryRequest) ([]*models.Notification, int64, error)
+	CountNotificationsByFilter(filter *models.AdminNotificationFilter) (int64, error)
+	GetNotificationIDsByFilter(filter *models.AdminNotificationFilter, limit int) ([]uint, error)
+
 	// Preferences
 	GetUserPreferences(userID uint) ([]*models.UserNotificationPreference, error)
 	GetUserPreference(userID uint, notificationType models.NotificationType) (*models.UserNotificationPreference, error)
 	UpsertUserPreference(preference *models.UserNotificationPreference) error
 	DeleteUserPreference(userID uint, notificationType models.NotificationType) error
+	GetPreferencesOfUsers(userIDs []uint, types []models.NotificationType) ([]*models.UserNotificationPreference, error)
+	DeletePreferencesOfUsers(userIDs []uint, types []models.NotificationType) (int64, error)
+
+	// Organization default preferences
+	GetPreferenceDefaults() ([]*models.NotificationPreferenceDefault, error)
+	GetPreferenceDefault(notificationType models.NotificationType) (*models.NotificationPreferenceDefault, error)
+	SavePreferenceDefault(preferenceDefault *models.NotificationPreferenceDefault) error
+	DeletePreferenceDefault(notificationType models.NotificationType) error
+
+	// Channel fallback chains
+	GetFallbackPolicies() ([]*models.NotificationFallbackPolicy, error)
+	GetFallbackPolicy(priority models.NotificationPriority) (*models.NotificationFallbackPolicy, error)
+	SaveFallbackPolicy(policy *models.NotificationFallbackPolicy) error
+	DeleteFallbackPolicy(priority models.NotificationPriority) error
+	CreateFallback(fallback *models.NotificationFallback) error
+	ClaimDueFallbacks(now, leaseUntil time.Time, limit int) ([]*models.NotificationFallback, error)
+	UpdateFallback(fallback *models.NotificationFallback) error
+	CancelReadFallbacks(userIDs ...uint) (int64, error)
+
+	// Template subject experiments
+	GetTemplateExperiments() ([]*models.TemplateExperiment, error)
+	GetTemplateExperiment(templateName string) (*models.TemplateExperiment, error)
+	SaveTemplateExperiment(experiment *models.TemplateExperiment) error
+	DeleteTemplateExperiment(templateName string) error
+	RecordTemplateEngagement(event *models.TemplateEngagementEvent) (bool, error)
+	GetTemplateAssignment(notificationID uint) (*models.TemplateEngagementEvent, error)
+	CountTemplateEngagement(experimentID uint) (map[string]map[models.TemplateEngagement]int64, error)
+
+	// Announcement campaigns
+	CreateCampaign(campaign *models.AnnouncementCampaign) error
+	GetCampaign(id uint) (*models.AnnouncementCampaign, error)
+	GetCampaignsByIDs(ids []uint) ([]*models.AnnouncementCampaign, error)
+	ListCampaigns(filter *models.CampaignFilterRequest) ([]*models.AnnouncementCampaign, int64, error)
+	GetCampaignSendsBetween(from, to time.Time) ([]*models.CampaignSend, error)
+	ClaimDueCampaignSends(now time.Time, limit int) ([]*models.CampaignSend, error)
+	UpdateCampaignSend(send *models.CampaignSend) error
+	CancelCampaignSends(campaignID uint, sendID *uint) (int64, error)
+	FinishCampaign(campaignID uint, cancelled bool) error
+
+	// Daily digests
+	GetDigestRecipients() ([]*models.UserNotificationPreference, error)
+	GetLastDailyDigest(userID uint) (*models.DailyDigest, error)
+	RecordDailyDigest(digest *models.DailyDigest) error
+
+	// Saved views
+	CreateNotificationView(view *models.NotificationView) error
+	GetNotificationView(userID, viewID uint) (*models.NotificationView, error)
+	GetUserNotificationViews(userID uint) ([]*models.NotificationView, error)
+	NotificationViewNameExists(userID uint, name string, excludeID uint) (bool, error)
+	CountUserNotificationViews(userID uint) (int64, error)
+	UpdateNotificationView(view *models.NotificationView) error
+	DeleteNotificationView(userID, viewID uint) error
+
+	// Push devices
+	SavePushDevice(device *models.PushDevice) error
+	GetUserPushDevices(userID uint) ([]*models.PushDevice, error)
+	DeleteUserPushDevice(userID, deviceID uint) error
+	DeletePushDevicesByToken(tokens []string) (int64, error)
+
+	// Email suppressions
+	GetEmailSuppression(email string) (*models.EmailSuppression, error)
+	GetEmailSuppressionByID(id uint) (*models.EmailSuppression, error)
+	SaveEmailSuppression(suppression *models.EmailSuppression) error
+	FindActiveEmailSuppression(userID uint, email string) (*models.EmailSuppression, error)
+	GetUserEmailSuppression(userID uint) (*models.EmailSuppression, error)
+	ListEmailSuppressions(filter *models.EmailSuppressionFilter) ([]*models.EmailSuppression, int64, error)
+
+	// Account merge
+	MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error)
 }
 
 // notificationRepository implements NotificationRepository interface
@@ -69,15 +166,17 @@ type notificationRepository struct {
 
 // SystemNotificationStats represents system-wide notification statistics
 type SystemNotificationStats struct {
-	TotalNotifications     int64   `json:"total_notifications"`
-	PendingNotifications   int64   `json:"pending_notifications"`
-	DeliveredNotifications int64   `json:"delivered_notifications"`
-	FailedNotifications    int64   `json:"failed_notifications"`
-	TodayNotifications     int64   `json:"today_notifications"`
-	WeekNotifications      int64   `json:"week_notifications"`
-	MonthNotifications     int64   `json:"month_notifications"`
-	ActiveUsers            int64   `json:"active_users"`
-	AverageDeliveryTime    float64 `json:"average_delivery_time_minutes"`
+	TotalNotifications        int64   `json:"total_notifications"`
+	PendingNotifications      int64   `json:"pending_notifications"`
+	DeliveredNotifications    int64   `json:"delivered_notifications"`
+	FailedNotifications       int64   `json:"failed_notifications"`
+	TodayNotifications        int64   `json:"today_notifications"`
+	WeekNotifications         int64   `json:"week_notifications"`
+	MonthNotifications        int64   `json:"month_notifications"`
+	ActiveUsers               int64   `json:"active_users"`
+	AverageDeliveryTime       float64 `json:"average_delivery_time_minutes"`
+	SuppressedDuplicates      int64   `json:"suppressed_duplicates"`
+	DeduplicatedNotifications int64   `json:"deduplicated_notifications"`
 }
 
 // NewNotificationRepository creates a new notification repository
@@ -103,18 +202,9 @@ func (r *notificationRepository) CreateBulkNotifications(notifications []*models
 		return nil
 	}
 
-	// Use batch insert for better performance
-	batchSize := 100
-	for i := 0; i < len(notifications); i += batchSize {
-		end := i + batchSize
-		if end > len(notifications) {
-			end = len(notifications)
-		}
-
-		batch := notifications[i:end]
-		if err := r.db.CreateInBatches(batch, batchSize).Error; err != nil {
-			return fmt.Errorf("failed to create notifications batch: %w", err)
-		}
+	// Multi-row INSERTs, batch size capped by the bind parameter limit
+	if err := r.db.CreateInBatches(notifications, r.db.BatchSizeFor(&models.Notification{})).Error; err != nil {
+		return fmt.Errorf("failed to create notifications batch: %w", err)
 	}
 
 	return nil
@@ -189,6 +279,35 @@ func (r *notificationRepository) GetUnreadCount(userID uint) (int64, error) {
 	return count, nil
 }
 
+// GetUnreadCountsByUsers returns unread counts for several users in one query, users without unread notifications get 0
+func (r *notificationRepository) GetUnreadCountsByUsers(userIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		UserID uint
+		Count  int64
+	}
+	err := r.db.Model(&models.Notification{}).
+		Select("user_id, COUNT(*) AS count").
+		Where("user_id IN ? AND is_read = ?", userIDs, false).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread counts: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		counts[userID] = 0
+	}
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+	return counts, nil
+}
+
 // GetUnreadCountByType returns the count of unread notifications by type for a user
 func (r *notificationRepository) GetUnreadCountByType(userID uint, notificationType models.NotificationType) (int64, error) {
 	var count int64
@@ -203,6 +322,46 @@ func (r *notificationRepository) GetUnreadCountByType(userID uint, notificationT
 
 // Mark as read operations
 
+// FindDuplicateNotification returns the latest unread notification with the same dedup key
+// touched after since, or nil if there is none
+func (r *notificationRepository) FindDuplicateNotification(userID uint, dedupKey string, since time.Time) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.Where("user_id = ? AND dedup_key = ? AND is_read = ? AND updated_at >= ?", userID, dedupKey, false, since).
+		Order("updated_at DESC").
+		First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find duplicate notification: %w", err)
+	}
+	return &notification, nil
+}
+
+// IncrementDuplicateCount bumps duplicate counter and timestamp of an existing notification
+func (r *notificationRepository) IncrementDuplicateCount(notificationID uint, message string) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"duplicate_count":   gorm.Expr("duplicate_count + 1"),
+		"last_duplicate_at": now,
+	}
+	if message != "" {
+		updates["message"] = message
+	}
+
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ?", notificationID).
+		Updates(updates)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to increment duplicate count: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
 // MarkAsRead marks a single notification as read
 func (r *notificationRepository) MarkAsRead(notificationID, userID uint) error {
 	now := time.Now()
@@ -224,10 +383,10 @@ func (r *notificationRepository) MarkAsRead(notificationID, userID uint) error {
 	return nil
 }
 
-// MarkMultipleAsRead marks multiple notifications as read
-func (r *notificationRepository) MarkMultipleAsRead(notificationIDs []uint, userID uint) error {
+// MarkMultipleAsRead marks multiple notifications as read and returns how many were unread
+func (r *notificationRepository) MarkMultipleAsRead(notificationIDs []uint, userID uint) (int64, error) {
 	if len(notificationIDs) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	now := time.Now()
@@ -239,10 +398,10 @@ func (r *notificationRepository) MarkMultipleAsRead(notificationIDs []uint, user
 		})
 
 	if result.Error != nil {
-		return fmt.Errorf("failed to mark notifications as read: %w", result.Error)
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", result.Error)
 	}
 
-	return nil
+	return result.RowsAffected, nil
 }
 
 // MarkAllAsRead marks all notifications as read for a user
@@ -262,8 +421,8 @@ func (r *notificationRepository) MarkAllAsRead(userID uint) error {
 	return nil
 }
 
-// MarkAllAsReadByType marks all notifications of a specific type as read for a user
-func (r *notificationRepository) MarkAllAsReadByType(userID uint, notificationType models.NotificationType) error {
+// MarkAllAsReadByType marks all notifications of a specific type as read for a user and returns how many were unread
+func (r *notificationRepository) MarkAllAsReadByType(userID uint, notificationType models.NotificationType) (int64, error) {
 	now := time.Now()
 	result := r.db.Model(&models.Notification{}).
 		Where("user_id = ? AND type = ? AND is_read = ?", userID, notificationType, false).
@@ -273,10 +432,66 @@ func (r *notificationRepository) MarkAllAsReadByType(userID uint, notificationTy
 		})
 
 	if result.Error != nil {
-		return fmt.Errorf("failed to mark notifications as read by type: %w", result.Error)
+		return 0, fmt.Errorf("failed to mark notifications as read by type: %w", result.Error)
 	}
 
-	return nil
+	return result.RowsAffected, nil
+}
+
+// MarkAsReadByFilter marks user's unread notifications matching the filter as read
+func (r *notificationRepository) MarkAsReadByFilter(userID uint, filter *models.MarkAsReadByFilterRequest) (int64, error) {
+	query := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false)
+
+	if filter.Type != nil {
+		query = query.Where("type = ?", *filter.Type)
+	}
+	if filter.RelatedType != "" {
+		query = query.Where("related_type = ?", filter.RelatedType)
+	}
+	if filter.RelatedID != nil {
+		query = query.Where("related_id = ?", *filter.RelatedID)
+	}
+	if filter.Before != nil {
+		query = query.Where("created_at <= ?", *filter.Before)
+	}
+
+	now := time.Now()
+	result := query.Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": now,
+	})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read by filter: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// MarkAsReadByRelatedObject marks unread notifications about a related object as read
+func (r *notificationRepository) MarkAsReadByRelatedObject(req *models.ResolveNotificationsRequest) (int64, error) {
+	query := r.db.Model(&models.Notification{}).
+		Where("related_type = ? AND related_id = ? AND is_read = ?", req.RelatedType, req.RelatedID, false)
+
+	if len(req.UserIDs) > 0 {
+		query = query.Where("user_id IN ?", req.UserIDs)
+	}
+	if req.Type != nil {
+		query = query.Where("type = ?", *req.Type)
+	}
+
+	now := time.Now()
+	result := query.Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": now,
+	})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read by related object: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
 }
 
 // Scheduled notifications
@@ -439,6 +654,61 @@ func (r *notificationRepository) GetSystemStats() (*SystemNotificationStats, err
 	}
 	stats.AverageDeliveryTime = result.AvgTime
 
+	// Deduplication statistics
+	if err := r.db.Model(&models.Notification{}).
+		Select("COALESCE(SUM(duplicate_count), 0)").
+		Scan(&stats.SuppressedDuplicates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get suppressed duplicates count: %w", err)
+	}
+
+	if err := r.db.Model(&models.Notification{}).
+		Where("duplicate_count > 0").
+		Count(&stats.DeduplicatedNotifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to get deduplicated notifications count: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetDeliveryFailureStats counts deliveries created since the given time and the failed ones,
+// by channel and for the notification types that failed most. Rates are left to the caller.
+func (r *notificationRepository) GetDeliveryFailureStats(since time.Time, topTypes int) (*models.DeliveryFailureStats, error) {
+	const counts = "COUNT(*) AS deliveries, COALESCE(SUM(CASE WHEN notification_deliveries.status = ? THEN 1 ELSE 0 END), 0) AS failed"
+
+	stats := &models.DeliveryFailureStats{
+		Since:           since,
+		ByChannel:       []*models.DeliveryFailureRate{},
+		TopFailingTypes: []*models.DeliveryFailureRate{},
+	}
+	if err := r.db.Model(&models.NotificationDelivery{}).
+		Select("channel, "+counts, models.NotificationStatusFailed).
+		Where("created_at >= ?", since).
+		Group("channel").
+		Order("channel ASC").
+		Scan(&stats.ByChannel).Error; err != nil {
+		return nil, fmt.Errorf("failed to get delivery failures by channel: %w", err)
+	}
+
+	for _, channel := range stats.ByChannel {
+		stats.Deliveries += channel.Deliveries
+		stats.Failed += channel.Failed
+	}
+	if stats.Failed == 0 {
+		return stats, nil
+	}
+
+	if err := r.db.Model(&models.NotificationDelivery{}).
+		Select("notifications.type AS type, "+counts, models.NotificationStatusFailed).
+		Joins("JOIN notifications ON notifications.id = notification_deliveries.notification_id").
+		Where("notification_deliveries.created_at >= ?", since).
+		Group("notifications.type").
+		Having("SUM(CASE WHEN notification_deliveries.status = ? THEN 1 ELSE 0 END) > 0", models.NotificationStatusFailed).
+		Order("failed DESC, type ASC").
+		Limit(topTypes).
+		Scan(&stats.TopFailingTypes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get top failing notification types: %w", err)
+	}
+
 	return stats, nil
 }
 
@@ -523,7 +793,7 @@ func (r *notificationRepository) UpdateDeliveryStatus(deliveryID uint, status mo
 // GetPendingDeliveries returns pending notification deliveries
 func (r *notificationRepository) GetPendingDeliveries(limit int) ([]*models.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
-	err := r.db.Preload("Notification").
+	err := r.db.
 		Where("status = ?", models.NotificationStatusPending).
 		Limit(limit).
 		Order("created_at ASC").
@@ -539,7 +809,7 @@ func (r *notificationRepository) GetPendingDeliveries(limit int) ([]*models.Noti
 // GetFailedDeliveries returns failed notification deliveries that can be retried
 func (r *notificationRepository) GetFailedDeliveries(maxAttempts int, limit int) ([]*models.NotificationDelivery, error) {
 	var deliveries []*models.NotificationDelivery
-	err := r.db.Preload("Notification").
+	err := r.db.
 		Where("status = ? AND attempt_count < ?", models.NotificationStatusFailed, maxAttempts).
 		Limit(limit).
 		Order("last_attempt_at ASC").
@@ -552,6 +822,83 @@ func (r *notificationRepository) GetFailedDeliveries(maxAttempts int, limit int)
 	return deliveries, nil
 }
 
+// GetHeldDeliveries returns deliveries held by the outbound delivery hold, oldest first
+func (r *notificationRepository) GetHeldDeliveries(channel *models.DeliveryChannel, limit int) ([]*models.NotificationDelivery, error) {
+	query := r.db.Where("status = ?", models.NotificationStatusHeld)
+	if channel != nil {
+		query = query.Where("channel = ?", *channel)
+	}
+
+	var deliveries []*models.NotificationDelivery
+	if err := query.Order("created_at ASC, id ASC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get held deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// CountHeldDeliveries returns the number of held deliveries per channel
+func (r *notificationRepository) CountHeldDeliveries() (map[models.DeliveryChannel]int64, error) {
+	var rows []struct {
+		Channel models.DeliveryChannel
+		Count   int64
+	}
+	err := r.db.Model(&models.NotificationDelivery{}).
+		Select("channel, COUNT(*) AS count").
+		Where("status = ?", models.NotificationStatusHeld).
+		Group("channel").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count held deliveries: %w", err)
+	}
+
+	counts := make(map[models.DeliveryChannel]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Channel] = row.Count
+	}
+	return counts, nil
+}
+
+// DiscardHeldDeliveries marks held deliveries as failed without sending them.
+// They get the maximum attempt count, so failed delivery retries skip them.
+func (r *notificationRepository) DiscardHeldDeliveries(channel *models.DeliveryChannel, before *time.Time, reason string) (int64, error) {
+	query := r.db.Model(&models.NotificationDelivery{}).Where("status = ?", models.NotificationStatusHeld)
+	if channel != nil {
+		query = query.Where("channel = ?", *channel)
+	}
+	if before != nil {
+		query = query.Where("created_at < ?", *before)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":          models.NotificationStatusFailed,
+		"error_message":   reason,
+		"attempt_count":   models.MaxDeliveryAttempts,
+		"last_attempt_at": time.Now(),
+	})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to discard held deliveries: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DiscardDelivery marks a delivery as failed without sending it. Like discarded held deliveries
+// it gets the maximum attempt count, so failed delivery retries skip it.
+func (r *notificationRepository) DiscardDelivery(deliveryID uint, reason string) error {
+	err := r.db.Model(&models.NotificationDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]interface{}{
+			"status":          models.NotificationStatusFailed,
+			"error_message":   reason,
+			"attempt_count":   models.MaxDeliveryAttempts,
+			"last_attempt_at": time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to discard delivery: %w", err)
+	}
+	return nil
+}
+
 // Search and filtering
 
 // SearchNotifications searches notifications by title and message content
@@ -604,6 +951,53 @@ func (r *notificationRepository) GetNotificationsByRelatedObject(relatedType str
 	return notifications, nil
 }
 
+// Admin history queries
+
+// QueryNotifications retrieves notifications of all users matching admin filters with pagination
+func (r *notificationRepository) QueryNotifications(req *models.AdminNotificationQueryRequest) ([]*models.Notification, int64, error) {
+	query := r.applyAdminFilters(r.db.Model(&models.Notification{}), &req.AdminNotificationFilter)
+	if req.Page != nil {
+		query = query.Scopes(req.Page.FilterScope)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	query = r.applySortingAndPagination(query, &models.NotificationFilterRequest{Page: req.Page})
+
+	var notifications []*models.Notification
+	if err := query.Preload("DeliveryChannels").Find(&notifications).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query notifications: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+// CountNotificationsByFilter counts notifications of all users matching admin filters
+func (r *notificationRepository) CountNotificationsByFilter(filter *models.AdminNotificationFilter) (int64, error) {
+	var count int64
+	err := r.applyAdminFilters(r.db.Model(&models.Notification{}), filter).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return count, nil
+}
+
+// GetNotificationIDsByFilter returns IDs of notifications matching admin filters, oldest first
+func (r *notificationRepository) GetNotificationIDsByFilter(filter *models.AdminNotificationFilter, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.applyAdminFilters(r.db.Model(&models.Notification{}), filter).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification IDs: %w", err)
+	}
+	return ids, nil
+}
+
 // Preferences
 
 // GetUserPreferences returns all notification preferences for a user
@@ -632,10 +1026,34 @@ func (r *notificationRepository) GetUserPreference(userID uint, notificationType
 
 // UpsertUserPreference creates or updates a user notification preference
 func (r *notificationRepository) UpsertUserPreference(preference *models.UserNotificationPreference) error {
-	err := r.db.Where("user_id = ? AND notification_type = ?",
-		preference.UserID, preference.NotificationType).
-		Assign(preference).
-		FirstOrCreate(&preference).Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.UserNotificationPreference
+		err := tx.Where("user_id = ? AND notification_type = ?", preference.UserID, preference.NotificationType).
+			First(&existing).Error
+		if err == nil {
+			// Save writes all fields, so disabled channels are stored too
+			preference.ID = existing.ID
+			preference.CreatedAt = existing.CreatedAt
+			return tx.Save(preference).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Create skips false values of columns with a default, they are written explicitly afterwards
+		flags := map[string]interface{}{
+			"in_app_enabled":  preference.InAppEnabled,
+			"email_enabled":   preference.EmailEnabled,
+			"push_enabled":    preference.PushEnabled,
+			"sms_enabled":     preference.SMSEnabled,
+			"weekend_enabled": preference.WeekendEnabled,
+			"digest_enabled":  preference.DigestEnabled,
+		}
+		if err := tx.Create(preference).Error; err != nil {
+			return err
+		}
+		return tx.Model(preference).Updates(flags).Error
+	})
 	if err != nil {
 		return fmt.Errorf("failed to upsert user preference: %w", err)
 	}
@@ -660,6 +1078,10 @@ func (r *notificationRepository) applyFilters(query *gorm.DB, filter *models.Not
 		return query
 	}
 
+	if filter.Page != nil {
+		query = query.Scopes(filter.Page.FilterScope)
+	}
+
 	if filter.Type != nil {
 		query = query.Where("type = ?", *filter.Type)
 	}
@@ -688,42 +1110,66 @@ func (r *notificationRepository) applyFilters(query *gorm.DB, filter *models.Not
 		query = query.Where("created_at < ?", *filter.CreatedBefore)
 	}
 
+	if view := filter.View; view != nil {
+		if len(view.Types) > 0 {
+			query = query.Where("type IN ?", view.Types)
+		}
+		if len(view.Priorities) > 0 {
+			query = query.Where("priority IN ?", view.Priorities)
+		}
+		if view.IsRead != nil {
+			query = query.Where("is_read = ?", *view.IsRead)
+		}
+		if len(view.RelatedTypes) > 0 {
+			query = query.Where("related_type IN ?", view.RelatedTypes)
+		}
+	}
+
 	return query
 }
 
-// applySortingAndPagination applies sorting and pagination to the query
-func (r *notificationRepository) applySortingAndPagination(query *gorm.DB, filter *models.NotificationFilterRequest) *gorm.DB {
+// applyAdminFilters applies admin history filters to notifications query
+func (r *notificationRepository) applyAdminFilters(query *gorm.DB, filter *models.AdminNotificationFilter) *gorm.DB {
 	if filter == nil {
-		return query.Order("created_at DESC").Limit(20)
+		return query
 	}
 
-	// Apply sorting
-	sortBy := "created_at"
-	sortOrder := "DESC"
+	query = r.applyFilters(query, &models.NotificationFilterRequest{
+		Type:          filter.Type,
+		Priority:      filter.Priority,
+		Status:        filter.Status,
+		RelatedType:   filter.RelatedType,
+		CreatedAfter:  filter.CreatedAfter,
+		CreatedBefore: filter.CreatedBefore,
+	})
 
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
+	if filter.Channel != nil {
+		query = query.Where("id IN (?)", r.db.Model(&models.NotificationDelivery{}).
+			Select("notification_id").
+			Where("channel = ?", *filter.Channel))
 	}
 
-	if filter.SortOrder != "" {
-		sortOrder = filter.SortOrder
+	if len(filter.UserIDs) > 0 {
+		query = query.Where("user_id IN ?", filter.UserIDs)
 	}
 
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
-
-	// Apply pagination
-	limit := 20
-	if filter.Limit > 0 {
-		limit = filter.Limit
-	}
-	if limit > 100 {
-		limit = 100 // Maximum limit
+	if filter.Locale != nil {
+		if *filter.Locale == i18n.DefaultLocale {
+			// Notifications without locale are sent in the default one
+			query = query.Where("locale = ? OR locale = '' OR locale IS NULL", *filter.Locale)
+		} else {
+			query = query.Where("locale = ?", *filter.Locale)
+		}
 	}
 
-	offset := 0
-	if filter.Offset > 0 {
-		offset = filter.Offset
+	return query
+}
+
+// applySortingAndPagination applies sorting and pagination to the query
+func (r *notificationRepository) applySortingAndPagination(query *gorm.DB, filter *models.NotificationFilterRequest) *gorm.DB {
+	if filter == nil || filter.Page == nil {
+		return query.Order("created_at DESC").Limit(20)
 	}
 
-	return query.Limit(limit).Offset(offset)
+	return query.Scopes(filter.Page.PageScope)
 }
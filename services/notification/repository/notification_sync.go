@@ -0,0 +1,25 @@
+// File: services/notification/repository/notification_sync.go
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+)
+
+// GetNotificationChanges returns user's notifications created or updated after the
+// (since, afterID) position, ordered by update time and ID. Rows updated at the same
+// moment by a bulk update are split between pages by ID.
+func (r *notificationRepository) GetNotificationChanges(userID uint, since time.Time, afterID uint, limit int) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	err := r.db.Where("user_id = ?", userID).
+		Where("updated_at > ? OR (updated_at = ? AND id > ?)", since, since, afterID).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification changes: %w", err)
+	}
+	return notifications, nil
+}
@@ -0,0 +1,83 @@
+// File: services/notification/repository/notification_view.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateNotificationView creates a saved view
+func (r *notificationRepository) CreateNotificationView(view *models.NotificationView) error {
+	if err := r.db.Create(view).Error; err != nil {
+		return fmt.Errorf("failed to create notification view: %w", err)
+	}
+	return nil
+}
+
+// GetNotificationView returns a saved view of the user
+func (r *notificationRepository) GetNotificationView(userID, viewID uint) (*models.NotificationView, error) {
+	var view models.NotificationView
+	err := r.db.Where("id = ? AND user_id = ?", viewID, userID).First(&view).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("notification view not found")
+		}
+		return nil, fmt.Errorf("failed to get notification view: %w", err)
+	}
+	return &view, nil
+}
+
+// GetUserNotificationViews returns saved views of the user in creation order
+func (r *notificationRepository) GetUserNotificationViews(userID uint) ([]*models.NotificationView, error) {
+	var views []*models.NotificationView
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&views).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification views: %w", err)
+	}
+	return views, nil
+}
+
+// NotificationViewNameExists checks if the user has another view with the name
+func (r *notificationRepository) NotificationViewNameExists(userID uint, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.NotificationView{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, excludeID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check notification view name: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CountUserNotificationViews returns the number of saved views of the user
+func (r *notificationRepository) CountUserNotificationViews(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.NotificationView{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count notification views: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateNotificationView saves changes of a view
+func (r *notificationRepository) UpdateNotificationView(view *models.NotificationView) error {
+	if err := r.db.Save(view).Error; err != nil {
+		return fmt.Errorf("failed to update notification view: %w", err)
+	}
+	return nil
+}
+
+// DeleteNotificationView permanently deletes a saved view of the user, so its name can be reused
+func (r *notificationRepository) DeleteNotificationView(userID, viewID uint) error {
+	result := r.db.Unscoped().Where("id = ? AND user_id = ?", viewID, userID).Delete(&models.NotificationView{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete notification view: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notification view not found")
+	}
+	return nil
+}
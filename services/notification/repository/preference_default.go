@@ -0,0 +1,103 @@
+// File: services/notification/repository/preference_default.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+)
+
+// GetPreferenceDefaults returns organization default preferences of all notification types that have one
+func (r *notificationRepository) GetPreferenceDefaults() ([]*models.NotificationPreferenceDefault, error) {
+	var defaults []*models.NotificationPreferenceDefault
+	if err := r.db.Order("notification_type ASC").Find(&defaults).Error; err != nil {
+		return nil, fmt.Errorf("failed to get preference defaults: %w", err)
+	}
+	return defaults, nil
+}
+
+// GetPreferenceDefault returns the organization default preference of a notification type, nil if none is set
+func (r *notificationRepository) GetPreferenceDefault(notificationType models.NotificationType) (*models.NotificationPreferenceDefault, error) {
+	var preferenceDefault models.NotificationPreferenceDefault
+	err := r.db.Where("notification_type = ?", notificationType).First(&preferenceDefault).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get preference default: %w", err)
+	}
+	return &preferenceDefault, nil
+}
+
+// SavePreferenceDefault creates or replaces the organization default preference of a notification type
+func (r *notificationRepository) SavePreferenceDefault(preferenceDefault *models.NotificationPreferenceDefault) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.NotificationPreferenceDefault
+		err := tx.Where("notification_type = ?", preferenceDefault.NotificationType).First(&existing).Error
+		switch {
+		case err == nil:
+			preferenceDefault.ID = existing.ID
+			preferenceDefault.CreatedAt = existing.CreatedAt
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to get preference default: %w", err)
+		}
+
+		if err := tx.Save(preferenceDefault).Error; err != nil {
+			return fmt.Errorf("failed to save preference default: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeletePreferenceDefault removes the organization default preference of a notification type,
+// users without their own preference get the built-in defaults again
+func (r *notificationRepository) DeletePreferenceDefault(notificationType models.NotificationType) error {
+	// Deleted permanently, so the type can get a new default under the unique index
+	result := r.db.Unscoped().Where("notification_type = ?", notificationType).
+		Delete(&models.NotificationPreferenceDefault{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete preference default: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("preference default not found")
+	}
+	return nil
+}
+
+// GetPreferencesOfUsers returns notification preferences of the users, limited to types if any are given
+func (r *notificationRepository) GetPreferencesOfUsers(userIDs []uint, types []models.NotificationType) ([]*models.UserNotificationPreference, error) {
+	var preferences []*models.UserNotificationPreference
+	if len(userIDs) == 0 {
+		return preferences, nil
+	}
+
+	query := r.db.Where("user_id IN ?", userIDs)
+	if len(types) > 0 {
+		query = query.Where("notification_type IN ?", types)
+	}
+	if err := query.Order("user_id ASC, notification_type ASC").Find(&preferences).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+	return preferences, nil
+}
+
+// DeletePreferencesOfUsers deletes notification preferences of the users, limited to types if any are given,
+// so the organization defaults apply to them again. It returns the number of deleted preferences.
+func (r *notificationRepository) DeletePreferencesOfUsers(userIDs []uint, types []models.NotificationType) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	query := r.db.Where("user_id IN ?", userIDs)
+	if len(types) > 0 {
+		query = query.Where("notification_type IN ?", types)
+	}
+	result := query.Delete(&models.UserNotificationPreference{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete user preferences: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+)
+
+// SavePushDevice registers a device token for the user. A token registered before, possibly by
+// another user of the same phone, is moved to the user. Beyond models.MaxPushDevices the least
+// recently seen devices of the user are removed.
+func (r *notificationRepository) SavePushDevice(device *models.PushDevice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.PushDevice
+		err := tx.Where("token = ?", device.Token).First(&existing).Error
+		switch {
+		case err == nil:
+			device.ID = existing.ID
+			device.CreatedAt = existing.CreatedAt
+			if err := tx.Save(device).Error; err != nil {
+				return fmt.Errorf("failed to update push device: %w", err)
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(device).Error; err != nil {
+				return fmt.Errorf("failed to create push device: %w", err)
+			}
+		default:
+			return fmt.Errorf("failed to get push device: %w", err)
+		}
+
+		var stale []uint
+		err = tx.Model(&models.PushDevice{}).
+			Where("user_id = ?", device.UserID).
+			Order("last_seen_at DESC, id DESC").
+			Offset(models.MaxPushDevices).
+			Pluck("id", &stale).Error
+		if err != nil {
+			return fmt.Errorf("failed to get stale push devices: %w", err)
+		}
+		if len(stale) > 0 {
+			if err := tx.Unscoped().Delete(&models.PushDevice{}, stale).Error; err != nil {
+				return fmt.Errorf("failed to delete stale push devices: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetUserPushDevices returns push devices of the user, most recently seen first
+func (r *notificationRepository) GetUserPushDevices(userID uint) ([]*models.PushDevice, error) {
+	var devices []*models.PushDevice
+	err := r.db.Where("user_id = ?", userID).Order("last_seen_at DESC, id DESC").Find(&devices).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push devices: %w", err)
+	}
+	return devices, nil
+}
+
+// DeleteUserPushDevice permanently deletes a push device of the user, so its token can be registered again
+func (r *notificationRepository) DeleteUserPushDevice(userID, deviceID uint) error {
+	result := r.db.Unscoped().Where("id = ? AND user_id = ?", deviceID, userID).Delete(&models.PushDevice{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete push device: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("push device not found")
+	}
+	return nil
+}
+
+// DeletePushDevicesByToken permanently deletes devices whose tokens were rejected by APNs or FCM
+func (r *notificationRepository) DeletePushDevicesByToken(tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	result := r.db.Unscoped().Where("token IN ?", tokens).Delete(&models.PushDevice{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete push devices: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,50 @@
+// Package repotest provides the notification repository on an in-memory SQLite database and
+// fixture factories for usecase and repository tests
+package repotest
+
+import (
+	"testing"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/services/notification/repository"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/database/dbtest"
+)
+
+// Repositories holds the notification repository backed by a test database
+type Repositories struct {
+	DB            *database.DB
+	Notifications repository.NotificationRepository
+}
+
+// New creates repositories on a fresh test database
+func New(tb testing.TB) *Repositories {
+	tb.Helper()
+
+	db := dbtest.Open(tb, models.Models()...)
+	return &Repositories{
+		DB:            db,
+		Notifications: repository.NewNotificationRepository(db),
+	}
+}
+
+// Notification creates an unread system notification of the user, options override the defaults before saving
+func (r *Repositories) Notification(tb testing.TB, userID uint, title string, options ...func(*models.Notification)) *models.Notification {
+	tb.Helper()
+
+	notification := &models.Notification{
+		UserID:   userID,
+		Type:     models.NotificationTypeSystem,
+		Title:    title,
+		Priority: models.NotificationPriorityMedium,
+		Status:   models.NotificationStatusPending,
+	}
+	for _, option := range options {
+		option(notification)
+	}
+
+	if err := r.Notifications.CreateNotification(notification); err != nil {
+		tb.Fatalf("failed to create notification fixture: %v", err)
+	}
+	return notification
+}
@@ -0,0 +1,857 @@
+package repotest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+)
+
+func TestNotificationFixtures(t *testing.T) {
+	repos := New(t)
+
+	repos.Notification(t, 1, "Task assigned", func(notification *models.Notification) {
+		notification.Type = models.NotificationTypeTask
+	})
+	repos.Notification(t, 1, "Scheduled maintenance")
+	repos.Notification(t, 2, "Task assigned")
+
+	count, err := repos.Notifications.GetUnreadCount(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 unread notifications, got %d", count)
+	}
+
+	// ILIKE search runs on SQLite
+	notifications, total, err := repos.Notifications.SearchNotifications(1, "TASK", &models.NotificationFilterRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(notifications) != 1 {
+		t.Errorf("expected 1 matching notification, got %d (total %d)", len(notifications), total)
+	}
+}
+
+func TestHeldDeliveries(t *testing.T) {
+	repos := New(t)
+
+	notification := repos.Notification(t, 1, "Weekly digest")
+	for _, channel := range []models.DeliveryChannel{models.DeliveryChannelEmail, models.DeliveryChannelEmail, models.DeliveryChannelPush} {
+		delivery := &models.NotificationDelivery{NotificationID: notification.ID, Channel: channel, Status: models.NotificationStatusHeld}
+		if err := repos.Notifications.CreateDelivery(delivery); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	counts, err := repos.Notifications.CountHeldDeliveries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts[models.DeliveryChannelEmail] != 2 || counts[models.DeliveryChannelPush] != 1 {
+		t.Errorf("unexpected held counts: %v", counts)
+	}
+
+	push := models.DeliveryChannelPush
+	drained, err := repos.Notifications.DiscardHeldDeliveries(&push, nil, "outdated")
+	if err != nil || drained != 1 {
+		t.Fatalf("expected 1 drained delivery, got %d (error %v)", drained, err)
+	}
+
+	// Drained deliveries are not retried
+	failed, err := repos.Notifications.GetFailedDeliveries(models.MaxDeliveryAttempts, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Errorf("expected drained deliveries to be skipped by retries, got %d", len(failed))
+	}
+
+	held, err := repos.Notifications.GetHeldDeliveries(nil, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(held) != 2 || held[0].Channel != models.DeliveryChannelEmail {
+		t.Errorf("expected 2 held email deliveries, got %d", len(held))
+	}
+}
+
+func TestEmailSuppressions(t *testing.T) {
+	repos := New(t)
+
+	userID := uint(7)
+	now := time.Now()
+	suppression := &models.EmailSuppression{
+		Email:        " Bounced@Example.com",
+		UserID:       &userID,
+		Status:       models.EmailAddressStatusBouncing,
+		Suppressed:   true,
+		HardBounces:  1,
+		LastEventAt:  &now,
+		SuppressedAt: &now,
+	}
+	if err := repos.Notifications.SaveEmailSuppression(suppression); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Addresses are matched case-insensitively
+	found, err := repos.Notifications.GetEmailSuppression("bounced@example.COM")
+	if err != nil || found == nil || found.ID != suppression.ID {
+		t.Fatalf("expected suppression by email, got %v (error %v)", found, err)
+	}
+
+	// Suppressions block the user even when the address is not known
+	active, err := repos.Notifications.FindActiveEmailSuppression(userID, "other@example.com")
+	if err != nil || active == nil {
+		t.Fatalf("expected active suppression for user, got %v (error %v)", active, err)
+	}
+	active, err = repos.Notifications.FindActiveEmailSuppression(8, "other@example.com")
+	if err != nil || active != nil {
+		t.Fatalf("expected no suppression for another user, got %v (error %v)", active, err)
+	}
+
+	suppressed := true
+	list, total, err := repos.Notifications.ListEmailSuppressions(&models.EmailSuppressionFilter{Email: "bounced", Suppressed: &suppressed, Limit: 10})
+	if err != nil || total != 1 || len(list) != 1 {
+		t.Fatalf("expected 1 listed suppression, got %d (total %d, error %v)", len(list), total, err)
+	}
+
+	suppression.Status = models.EmailAddressStatusOK
+	suppression.Suppressed = false
+	if err := repos.Notifications.SaveEmailSuppression(suppression); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status, err := repos.Notifications.GetUserEmailSuppression(userID)
+	if err != nil || status != nil {
+		t.Errorf("expected no problems after clearing, got %v (error %v)", status, err)
+	}
+
+	notification := repos.Notification(t, userID, "Weekly digest")
+	delivery := &models.NotificationDelivery{NotificationID: notification.ID, Channel: models.DeliveryChannelEmail}
+	if err := repos.Notifications.CreateDelivery(delivery); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repos.Notifications.DiscardDelivery(delivery.ID, "suppressed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failed, err := repos.Notifications.GetFailedDeliveries(models.MaxDeliveryAttempts, 10)
+	if err != nil || len(failed) != 0 {
+		t.Errorf("expected discarded delivery to be skipped by retries, got %d (error %v)", len(failed), err)
+	}
+}
+
+func TestNotificationViews(t *testing.T) {
+	repos := New(t)
+
+	unread := false
+	view := &models.NotificationView{
+		UserID: 1,
+		Name:   "Urgent tasks",
+		Filter: models.NotificationViewFilter{
+			Types:      []models.NotificationType{models.NotificationTypeTask},
+			Priorities: []models.NotificationPriority{models.NotificationPriorityHigh, models.NotificationPriorityCritical},
+			IsRead:     &unread,
+		},
+	}
+	if err := repos.Notifications.CreateNotificationView(view); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repos.Notification(t, 1, "Deploy blocked", func(notification *models.Notification) {
+		notification.Type = models.NotificationTypeTask
+		notification.Priority = models.NotificationPriorityCritical
+	})
+	repos.Notification(t, 1, "Docs review", func(notification *models.Notification) {
+		notification.Type = models.NotificationTypeTask
+	})
+	repos.Notification(t, 1, "Mentioned in #general", func(notification *models.Notification) {
+		notification.Type = models.NotificationTypeMention
+		notification.Priority = models.NotificationPriorityHigh
+	})
+
+	// The stored filter is loaded back and applied by the list query
+	stored, err := repos.Notifications.GetNotificationView(1, view.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notifications, total, err := repos.Notifications.GetUserNotifications(1, &models.NotificationFilterRequest{View: &stored.Filter})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(notifications) != 1 || notifications[0].Title != "Deploy blocked" {
+		t.Errorf("expected only the critical task, got %d (total %d)", len(notifications), total)
+	}
+
+	if _, err := repos.Notifications.GetNotificationView(2, view.ID); err == nil {
+		t.Error("expected views of other users to be hidden")
+	}
+
+	// Deleted names can be reused
+	if err := repos.Notifications.DeleteNotificationView(1, view.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exists, err := repos.Notifications.NotificationViewNameExists(1, "Urgent tasks", 0)
+	if err != nil || exists {
+		t.Errorf("expected deleted view name to be free, got %v (error %v)", exists, err)
+	}
+	if err := repos.Notifications.CreateNotificationView(&models.NotificationView{UserID: 1, Name: "Urgent tasks"}); err != nil {
+		t.Errorf("expected to reuse the name of a deleted view: %v", err)
+	}
+}
+
+func TestPushDevices(t *testing.T) {
+	repos := New(t)
+
+	now := time.Now()
+	for i := 0; i < models.MaxPushDevices+1; i++ {
+		device := &models.PushDevice{
+			UserID:     1,
+			Platform:   models.PushPlatformIOS,
+			Token:      fmt.Sprintf("token-%d", i),
+			LastSeenAt: now.Add(time.Duration(i) * time.Minute),
+		}
+		if err := repos.Notifications.SavePushDevice(device); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The least recently seen device is replaced
+	devices, err := repos.Notifications.GetUserPushDevices(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(devices) != models.MaxPushDevices || devices[len(devices)-1].Token != "token-1" {
+		t.Fatalf("expected %d devices without token-0, got %d", models.MaxPushDevices, len(devices))
+	}
+
+	// A token registered again moves to the new user
+	moved := &models.PushDevice{UserID: 2, Platform: models.PushPlatformIOS, Token: "token-5", LastSeenAt: now}
+	if err := repos.Notifications.SavePushDevice(moved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	devices, err = repos.Notifications.GetUserPushDevices(2)
+	if err != nil || len(devices) != 1 || devices[0].ID != moved.ID {
+		t.Fatalf("expected the token to move to user 2, got %d devices (error %v)", len(devices), err)
+	}
+
+	if err := repos.Notifications.DeleteUserPushDevice(1, moved.ID); err == nil {
+		t.Error("expected devices of other users to be hidden")
+	}
+
+	removed, err := repos.Notifications.DeletePushDevicesByToken([]string{"token-5", "token-6", "unknown"})
+	if err != nil || removed != 2 {
+		t.Errorf("expected 2 devices removed, got %d (error %v)", removed, err)
+	}
+	if err := repos.Notifications.SavePushDevice(&models.PushDevice{UserID: 1, Platform: models.PushPlatformAndroid, Token: "token-6", LastSeenAt: now}); err != nil {
+		t.Errorf("expected to register a removed token again: %v", err)
+	}
+}
+
+func TestPreferenceDefaults(t *testing.T) {
+	repos := New(t)
+
+	for _, preferenceDefault := range []*models.NotificationPreferenceDefault{
+		{NotificationType: models.NotificationTypeTask, InAppEnabled: true, EmailEnabled: true, UpdatedBy: 1},
+		{NotificationType: models.NotificationTypeTask, InAppEnabled: true, EmailEnabled: false, UpdatedBy: 2},
+	} {
+		if err := repos.Notifications.SavePreferenceDefault(preferenceDefault); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Saving a type again replaces its default
+	preferenceDefault, err := repos.Notifications.GetPreferenceDefault(models.NotificationTypeTask)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preferenceDefault == nil || preferenceDefault.EmailEnabled || preferenceDefault.UpdatedBy != 2 {
+		t.Errorf("expected the second task default, got %+v", preferenceDefault)
+	}
+	defaults, err := repos.Notifications.GetPreferenceDefaults()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(defaults) != 1 {
+		t.Errorf("expected 1 preference default, got %d", len(defaults))
+	}
+
+	// A deleted type can get a new default under the unique index
+	if err := repos.Notifications.DeletePreferenceDefault(models.NotificationTypeTask); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repos.Notifications.DeletePreferenceDefault(models.NotificationTypeTask); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if err := repos.Notifications.SavePreferenceDefault(&models.NotificationPreferenceDefault{NotificationType: models.NotificationTypeTask, UpdatedBy: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestResetPreferencesOfUsers(t *testing.T) {
+	repos := New(t)
+
+	for _, userID := range []uint{1, 2, 3} {
+		for _, notificationType := range []models.NotificationType{models.NotificationTypeTask, models.NotificationTypeMessage} {
+			preference := &models.UserNotificationPreference{UserID: userID, NotificationType: notificationType, InAppEnabled: true, EmailEnabled: false, Timezone: "UTC"}
+			if err := repos.Notifications.UpsertUserPreference(preference); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	reset, err := repos.Notifications.DeletePreferencesOfUsers([]uint{1, 2}, []models.NotificationType{models.NotificationTypeTask})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reset != 2 {
+		t.Errorf("expected 2 reset preferences, got %d", reset)
+	}
+
+	preferences, err := repos.Notifications.GetPreferencesOfUsers([]uint{1, 2, 3}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(preferences) != 4 {
+		t.Errorf("expected 4 remaining preferences, got %d", len(preferences))
+	}
+	for _, preference := range preferences {
+		if preference.UserID != 3 && preference.NotificationType == models.NotificationTypeTask {
+			t.Errorf("expected task preference of user %d to be reset", preference.UserID)
+		}
+		if preference.EmailEnabled {
+			t.Errorf("expected disabled email of user %d to be stored", preference.UserID)
+		}
+	}
+}
+
+func TestPreferencePresentation(t *testing.T) {
+	repos := New(t)
+
+	// Preferences stored before presentation settings existed follow the type defaults
+	preference := &models.UserNotificationPreference{UserID: 1, NotificationType: models.NotificationTypePoll, InAppEnabled: true, Timezone: "UTC"}
+	if err := repos.Notifications.UpsertUserPreference(preference); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := repos.Notifications.GetUserPreference(1, models.NotificationTypePoll)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if presentation := stored.Presentation(); presentation != models.DefaultPresentation(models.NotificationTypePoll) {
+		t.Errorf("expected default presentation, got %+v", presentation)
+	}
+
+	excluded := false
+	preference.Sound = "knock"
+	preference.BannerStyle = models.BannerStyleNone
+	preference.IncludeInBadge = &excluded
+	if err := repos.Notifications.UpsertUserPreference(preference); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err = repos.Notifications.GetUserPreference(1, models.NotificationTypePoll)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.NotificationPresentation{Sound: "knock", BannerStyle: models.BannerStyleNone, IncludeInBadge: false}
+	if presentation := stored.Presentation(); presentation != want {
+		t.Errorf("expected presentation %+v, got %+v", want, presentation)
+	}
+}
+
+func TestEmailOutbox(t *testing.T) {
+	repos := New(t)
+
+	now := time.Now()
+	for _, entry := range []*models.EmailOutboxEntry{
+		{DeliveryID: 1, NotificationID: 1, NextAttemptAt: now.Add(-time.Minute)},
+		{DeliveryID: 1, NotificationID: 1, NextAttemptAt: now.Add(-time.Minute)}, // already queued
+		{DeliveryID: 2, NotificationID: 2, NextAttemptAt: now.Add(time.Hour)},
+	} {
+		if err := repos.Notifications.EnqueueEmailOutbox(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	due, err := repos.Notifications.GetEmailOutbox(now, true, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 || due[0].DeliveryID != 1 {
+		t.Fatalf("expected the first delivery to be due, got %d entries", len(due))
+	}
+	all, err := repos.Notifications.GetEmailOutbox(now, false, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected 2 outbox entries, got %d", len(all))
+	}
+
+	if err := repos.Notifications.RetryEmailOutboxEntry(due[0].ID, "connection refused", now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status, err := repos.Notifications.GetEmailOutboxStats(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Pending != 2 || status.Due != 0 {
+		t.Errorf("expected 2 pending and none due, got %d pending and %d due", status.Pending, status.Due)
+	}
+	if status.NextAttemptAt == nil || !status.NextAttemptAt.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("expected next attempt of the retried entry, got %v", status.NextAttemptAt)
+	}
+
+	for _, entry := range all {
+		if err := repos.Notifications.DeleteEmailOutboxEntry(entry.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	status, err = repos.Notifications.GetEmailOutboxStats(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Pending != 0 || status.OldestQueuedAt != nil {
+		t.Errorf("expected an empty outbox, got %+v", status)
+	}
+}
+
+func TestFallbackChains(t *testing.T) {
+	repos := New(t)
+
+	policy := &models.NotificationFallbackPolicy{
+		Priority:  models.NotificationPriorityCritical,
+		Enabled:   true,
+		Steps:     []models.FallbackStep{{Channel: models.DeliveryChannelPush}, {Channel: models.DeliveryChannelSMS, DelayMinutes: 5}},
+		UpdatedBy: 1,
+	}
+	if err := repos.Notifications.SaveFallbackPolicy(policy); err != nil {
+		t.Fatalf("failed to save fallback policy: %v", err)
+	}
+	stored, err := repos.Notifications.GetFallbackPolicy(models.NotificationPriorityCritical)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored == nil || len(stored.Steps) != 2 || stored.Steps[1].DelayMinutes != 5 {
+		t.Fatalf("expected policy with 2 steps, got %+v", stored)
+	}
+
+	now := time.Now()
+	read := repos.Notification(t, 1, "Server down")
+	unread := repos.Notification(t, 1, "Disk full")
+	for _, notification := range []*models.Notification{read, unread} {
+		nextAttemptAt := now.Add(-time.Minute)
+		fallback := &models.NotificationFallback{
+			NotificationID: notification.ID,
+			UserID:         1,
+			Steps:          policy.Steps,
+			NextStep:       1,
+			State:          models.FallbackStateActive,
+			NextAttemptAt:  &nextAttemptAt,
+		}
+		if err := repos.Notifications.CreateFallback(fallback); err != nil {
+			t.Fatalf("failed to create fallback: %v", err)
+		}
+	}
+
+	if err := repos.Notifications.MarkAsRead(read.ID, 1); err != nil {
+		t.Fatalf("failed to mark notification as read: %v", err)
+	}
+	cancelled, err := repos.Notifications.CancelReadFallbacks(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled != 1 {
+		t.Fatalf("expected 1 cancelled chain, got %d", cancelled)
+	}
+
+	claimed, err := repos.Notifications.ClaimDueFallbacks(now, now.Add(5*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].NotificationID != unread.ID {
+		t.Fatalf("expected the unread chain to be claimed, got %d chains", len(claimed))
+	}
+
+	// Claimed chains are leased to the claiming worker
+	again, err := repos.Notifications.ClaimDueFallbacks(now, now.Add(5*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("expected no chain to be claimed twice, got %d", len(again))
+	}
+}
+
+func TestTemplateExperiments(t *testing.T) {
+	repos := New(t)
+
+	variants := []models.TemplateVariant{
+		{Key: "a", Subject: "New task: {{.TaskTitle}}", Weight: 50},
+		{Key: "b", Subject: "{{.TaskTitle}} is waiting for you", Weight: 50},
+	}
+	experiment := &models.TemplateExperiment{TemplateName: "task_assigned", Enabled: true, Variants: variants, UpdatedBy: 1}
+	if err := repos.Notifications.SaveTemplateExperiment(experiment); err != nil {
+		t.Fatalf("failed to save template experiment: %v", err)
+	}
+
+	// Assignment is stable for a user
+	first := experiment.AssignVariant(7)
+	if first == nil || experiment.AssignVariant(7).Key != first.Key {
+		t.Fatalf("expected a stable variant, got %+v", first)
+	}
+
+	events := []models.TemplateEngagementEvent{
+		{Variant: "a", Event: models.TemplateEngagementSent},
+		{Variant: "a", Event: models.TemplateEngagementOpen},
+		{Variant: "a", Event: models.TemplateEngagementOpen}, // Повторное открытие не учитывается
+		{Variant: "b", Event: models.TemplateEngagementSent},
+	}
+	notification := repos.Notification(t, 1, "New task: Report")
+	other := repos.Notification(t, 2, "Report is waiting for you")
+	for _, event := range events {
+		event.ExperimentID = experiment.ID
+		event.NotificationID, event.UserID = notification.ID, 1
+		if event.Variant == "b" {
+			event.NotificationID, event.UserID = other.ID, 2
+		}
+		if _, err := repos.Notifications.RecordTemplateEngagement(&event); err != nil {
+			t.Fatalf("failed to record engagement: %v", err)
+		}
+	}
+
+	counts, err := repos.Notifications.CountTemplateEngagement(experiment.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts["a"][models.TemplateEngagementSent] != 1 || counts["a"][models.TemplateEngagementOpen] != 1 || counts["b"][models.TemplateEngagementSent] != 1 {
+		t.Fatalf("unexpected engagement counts: %v", counts)
+	}
+
+	assignment, err := repos.Notifications.GetTemplateAssignment(other.ID)
+	if err != nil || assignment == nil || assignment.Variant != "b" {
+		t.Fatalf("expected variant b to be assigned, got %+v (%v)", assignment, err)
+	}
+
+	// Pausing keeps the experiment and its events
+	paused := &models.TemplateExperiment{TemplateName: "task_assigned", Variants: variants, UpdatedBy: 1}
+	if err := repos.Notifications.SaveTemplateExperiment(paused); err != nil {
+		t.Fatalf("failed to pause template experiment: %v", err)
+	}
+	if paused.ID != experiment.ID {
+		t.Fatalf("expected experiment %d to be kept, got %d", experiment.ID, paused.ID)
+	}
+
+	// New variants restart it
+	restarted := &models.TemplateExperiment{TemplateName: "task_assigned", Enabled: true, Variants: variants[:1], UpdatedBy: 1}
+	if err := repos.Notifications.SaveTemplateExperiment(restarted); err != nil {
+		t.Fatalf("failed to restart template experiment: %v", err)
+	}
+	if restarted.ID == experiment.ID {
+		t.Fatal("expected a new experiment after changing variants")
+	}
+	counts, err = repos.Notifications.CountTemplateEngagement(experiment.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(counts) != 0 {
+		t.Errorf("expected events of the old experiment to be removed, got %v", counts)
+	}
+
+	if err := repos.Notifications.DeleteTemplateExperiment("task_assigned"); err != nil {
+		t.Fatalf("failed to delete template experiment: %v", err)
+	}
+	if err := repos.Notifications.DeleteTemplateExperiment("task_assigned"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestDeliveryFailureStats(t *testing.T) {
+	repos := New(t)
+
+	task := repos.Notification(t, 1, "Task assigned", func(notification *models.Notification) {
+		notification.Type = models.NotificationTypeTask
+	})
+	system := repos.Notification(t, 1, "Scheduled maintenance")
+	deliveries := []*models.NotificationDelivery{
+		{NotificationID: task.ID, Channel: models.DeliveryChannelEmail, Status: models.NotificationStatusFailed},
+		{NotificationID: task.ID, Channel: models.DeliveryChannelPush, Status: models.NotificationStatusFailed},
+		{NotificationID: system.ID, Channel: models.DeliveryChannelEmail, Status: models.NotificationStatusDelivered},
+		{NotificationID: system.ID, Channel: models.DeliveryChannelPush, Status: models.NotificationStatusDelivered},
+	}
+	for _, delivery := range deliveries {
+		if err := repos.Notifications.CreateDelivery(delivery); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats, err := repos.Notifications.GetDeliveryFailureStats(time.Now().Add(-time.Hour), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Deliveries != 4 || stats.Failed != 2 {
+		t.Errorf("expected 2 of 4 deliveries failed, got %d of %d", stats.Failed, stats.Deliveries)
+	}
+	if len(stats.ByChannel) != 2 || stats.ByChannel[0].Channel != models.DeliveryChannelEmail || stats.ByChannel[0].Failed != 1 {
+		t.Errorf("unexpected failures by channel: %+v", stats.ByChannel)
+	}
+	if len(stats.TopFailingTypes) != 1 || stats.TopFailingTypes[0].Type != models.NotificationTypeTask || stats.TopFailingTypes[0].Failed != 2 {
+		t.Errorf("expected only task notifications to be failing, got %+v", stats.TopFailingTypes)
+	}
+
+	// Deliveries before the period are not counted
+	stats, err = repos.Notifications.GetDeliveryFailureStats(time.Now().Add(time.Hour), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Deliveries != 0 || len(stats.TopFailingTypes) != 0 {
+		t.Errorf("expected no deliveries in the period, got %d", stats.Deliveries)
+	}
+}
+
+func TestAnnouncementCampaigns(t *testing.T) {
+	repos := New(t)
+
+	now := time.Now()
+	campaign := &models.AnnouncementCampaign{
+		Name:      "Office move",
+		Title:     "We are moving",
+		Content:   "The office moves next week",
+		Priority:  models.NotificationPriorityMedium,
+		Status:    models.CampaignStatusScheduled,
+		CreatedBy: 1,
+		Sends: []*models.CampaignSend{
+			{Segment: "Engineering", UserIDs: []uint{1, 2}, ScheduledAt: now.Add(-time.Minute), Status: models.CampaignSendStatusPending},
+			{Segment: "Sales", UserIDs: []uint{3}, ScheduledAt: now.Add(time.Hour), Status: models.CampaignSendStatusPending},
+		},
+	}
+	if err := repos.Notifications.CreateCampaign(campaign); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claimed, err := repos.Notifications.ClaimDueCampaignSends(now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].Segment != "Engineering" {
+		t.Fatalf("expected only the due send to be claimed, got %d sends", len(claimed))
+	}
+	// Claimed sends are not handed out twice
+	again, err := repos.Notifications.ClaimDueCampaignSends(now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("expected no sends left to claim, got %d", len(again))
+	}
+
+	sentAt := now
+	claimed[0].Status = models.CampaignSendStatusSent
+	claimed[0].SentAt = &sentAt
+	if err := repos.Notifications.UpdateCampaignSend(claimed[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cancelled, err := repos.Notifications.CancelCampaignSends(campaign.ID, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled != 1 {
+		t.Errorf("expected 1 pending send to be cancelled, got %d", cancelled)
+	}
+
+	if err := repos.Notifications.FinishCampaign(campaign.ID, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := repos.Notifications.GetCampaign(campaign.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Status != models.CampaignStatusCompleted {
+		t.Errorf("expected a partly sent campaign to be completed, got %s", stored.Status)
+	}
+	if len(stored.Sends) != 2 || len(stored.Sends[0].UserIDs) != 2 {
+		t.Errorf("expected sends with their recipients, got %+v", stored.Sends)
+	}
+}
+
+func TestDailyDigests(t *testing.T) {
+	repos := New(t)
+
+	for userID, enabled := range map[uint]bool{1: true, 2: false} {
+		err := repos.Notifications.UpsertUserPreference(&models.UserNotificationPreference{
+			UserID:           userID,
+			NotificationType: models.NotificationTypeSystem,
+			InAppEnabled:     true,
+			MinPriority:      models.NotificationPriorityLow,
+			Timezone:         "UTC",
+			DigestEnabled:    enabled,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	recipients, err := repos.Notifications.GetDigestRecipients()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recipients) != 1 || recipients[0].UserID != 1 {
+		t.Fatalf("expected only the user with digests enabled, got %d recipients", len(recipients))
+	}
+
+	last, err := repos.Notifications.GetLastDailyDigest(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last != nil {
+		t.Fatalf("expected no digest before the first one, got %+v", last)
+	}
+
+	now := time.Now()
+	for _, digest := range []*models.DailyDigest{
+		{UserID: 1, Date: "2026-03-01", SentAt: now.Add(-24 * time.Hour)},
+		{UserID: 1, Date: "2026-03-02", SentAt: now},
+		// A second digest of the same day is ignored
+		{UserID: 1, Date: "2026-03-02", SentAt: now.Add(time.Hour)},
+	} {
+		if err := repos.Notifications.RecordDailyDigest(digest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	last, err = repos.Notifications.GetLastDailyDigest(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last == nil || last.Date != "2026-03-02" || !last.SentAt.Equal(now) {
+		t.Errorf("expected the first digest of the latest day, got %+v", last)
+	}
+}
+
+func TestConcurrentMarkAsRead(t *testing.T) {
+	repos := New(t)
+
+	var ids []uint
+	for i := 0; i < 5; i++ {
+		ids = append(ids, repos.Notification(t, 1, "Task assigned").ID)
+	}
+
+	// Devices marking overlapping notifications at once count each notification once,
+	// so unread counters adjusted by the results never drift
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		marked int64
+	)
+	for device := 0; device < 4; device++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count, err := repos.Notifications.MarkMultipleAsRead(ids, 1)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			mu.Lock()
+			marked += count
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if marked != int64(len(ids)) {
+		t.Errorf("expected %d notifications marked in total, got %d", len(ids), marked)
+	}
+	count, err := repos.Notifications.GetUnreadCount(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no unread notifications, got %d", count)
+	}
+
+	// Marking a read notification again is not an error and changes nothing
+	count, err = repos.Notifications.MarkMultipleAsRead(ids[:1], 1)
+	if err != nil || count != 0 {
+		t.Errorf("expected a no-op for read notifications, got %d, %v", count, err)
+	}
+}
+
+func TestNotificationChanges(t *testing.T) {
+	repos := New(t)
+
+	read := repos.Notification(t, 1, "Task assigned")
+	repos.Notification(t, 2, "Task assigned")
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	created := repos.Notification(t, 1, "Meeting moved")
+	if _, err := repos.Notifications.MarkMultipleAsRead([]uint{read.ID, created.ID}, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	changes, err := repos.Notifications.GetNotificationChanges(1, since, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 2 || changes[0].ID != read.ID || !changes[0].IsRead {
+		t.Fatalf("expected both notifications of the user changed, got %d", len(changes))
+	}
+
+	// Rows updated at the same moment are paged by ID
+	page, err := repos.Notifications.GetNotificationChanges(1, changes[0].UpdatedAt, changes[0].ID, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != created.ID {
+		t.Errorf("expected only the notification after the position, got %d", len(page))
+	}
+}
+
+func TestNotificationsForExport(t *testing.T) {
+	repos := New(t)
+	now := time.Now()
+
+	first := repos.Notification(t, 1, "Task assigned")
+	repos.Notification(t, 2, "Task assigned")
+	deleted := repos.Notification(t, 1, "Deleted")
+	if err := repos.Notifications.DeleteNotification(deleted.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expired := repos.Notification(t, 1, "Expired")
+	expiresAt := now.Add(-time.Minute)
+	expired.ExpiresAt = &expiresAt
+	if err := repos.Notifications.UpdateNotification(expired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := repos.Notification(t, 1, "Meeting moved")
+
+	from, to := now.Add(-time.Hour), now.Add(time.Hour)
+	notifications, err := repos.Notifications.GetNotificationsForExport(1, from, to, now, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifications) != 2 || notifications[0].ID != first.ID || notifications[1].ID != last.ID {
+		t.Fatalf("expected only live notifications of the user, got %d", len(notifications))
+	}
+
+	page, err := repos.Notifications.GetNotificationsForExport(1, from, to, now, first.ID, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != last.ID {
+		t.Errorf("expected only the notification after the position, got %d", len(page))
+	}
+
+	// Notifications created outside the range are left out
+	outside, err := repos.Notifications.GetNotificationsForExport(1, to, to.Add(time.Hour), now, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outside) != 0 {
+		t.Errorf("expected no notifications outside the range, got %d", len(outside))
+	}
+}
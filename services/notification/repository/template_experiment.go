@@ -0,0 +1,139 @@
+// File: services/notification/repository/template_experiment.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"tachyon-messenger/services/notification/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetTemplateExperiments returns subject experiments of all templates that have one
+func (r *notificationRepository) GetTemplateExperiments() ([]*models.TemplateExperiment, error) {
+	var experiments []*models.TemplateExperiment
+	if err := r.db.Order("template_name ASC").Find(&experiments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get template experiments: %w", err)
+	}
+	return experiments, nil
+}
+
+// GetTemplateExperiment returns the subject experiment of a template, nil if none is set
+func (r *notificationRepository) GetTemplateExperiment(templateName string) (*models.TemplateExperiment, error) {
+	var experiment models.TemplateExperiment
+	err := r.db.Where("template_name = ?", templateName).First(&experiment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get template experiment: %w", err)
+	}
+	return &experiment, nil
+}
+
+// SaveTemplateExperiment creates or replaces the subject experiment of a template. With the same
+// variants the experiment keeps its ID and events, other variants restart it from scratch.
+func (r *notificationRepository) SaveTemplateExperiment(experiment *models.TemplateExperiment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.TemplateExperiment
+		err := tx.Where("template_name = ?", experiment.TemplateName).First(&existing).Error
+		switch {
+		case err == nil:
+			if slices.Equal(existing.Variants, experiment.Variants) {
+				experiment.ID = existing.ID
+				experiment.CreatedAt = existing.CreatedAt
+				break
+			}
+			if err := deleteTemplateExperiment(tx, existing.ID); err != nil {
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to get template experiment: %w", err)
+		}
+
+		if err := tx.Save(experiment).Error; err != nil {
+			return fmt.Errorf("failed to save template experiment: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteTemplateExperiment removes the subject experiment of a template with its events
+func (r *notificationRepository) DeleteTemplateExperiment(templateName string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var experiment models.TemplateExperiment
+		err := tx.Where("template_name = ?", templateName).First(&experiment).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("template experiment not found")
+			}
+			return fmt.Errorf("failed to get template experiment: %w", err)
+		}
+		return deleteTemplateExperiment(tx, experiment.ID)
+	})
+}
+
+// deleteTemplateExperiment removes an experiment and its events. The experiment is deleted
+// permanently, so the template can get a new one under the unique index.
+func deleteTemplateExperiment(tx *gorm.DB, experimentID uint) error {
+	if err := tx.Where("experiment_id = ?", experimentID).Delete(&models.TemplateEngagementEvent{}).Error; err != nil {
+		return fmt.Errorf("failed to delete template engagement events: %w", err)
+	}
+	if err := tx.Unscoped().Delete(&models.TemplateExperiment{}, experimentID).Error; err != nil {
+		return fmt.Errorf("failed to delete template experiment: %w", err)
+	}
+	return nil
+}
+
+// RecordTemplateEngagement stores an engagement event and reports whether it is new, an event
+// already recorded for the notification is skipped
+func (r *notificationRepository) RecordTemplateEngagement(event *models.TemplateEngagementEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record template engagement: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetTemplateAssignment returns the sent event of a notification, nil if it was not sent
+// as part of an experiment
+func (r *notificationRepository) GetTemplateAssignment(notificationID uint) (*models.TemplateEngagementEvent, error) {
+	var event models.TemplateEngagementEvent
+	err := r.db.Where("notification_id = ? AND event = ?", notificationID, models.TemplateEngagementSent).First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get template assignment: %w", err)
+	}
+	return &event, nil
+}
+
+// CountTemplateEngagement returns the number of events of an experiment by variant and event
+func (r *notificationRepository) CountTemplateEngagement(experimentID uint) (map[string]map[models.TemplateEngagement]int64, error) {
+	var rows []struct {
+		Variant string
+		Event   models.TemplateEngagement
+		Count   int64
+	}
+	err := r.db.Model(&models.TemplateEngagementEvent{}).
+		Select("variant, event, COUNT(*) AS count").
+		Where("experiment_id = ?", experimentID).
+		Group("variant, event").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count template engagement: %w", err)
+	}
+
+	counts := make(map[string]map[models.TemplateEngagement]int64)
+	for _, row := range rows {
+		if counts[row.Variant] == nil {
+			counts[row.Variant] = make(map[models.TemplateEngagement]int64)
+		}
+		counts[row.Variant][row.Event] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,75 @@
+// File: services/notification/repository/user_merge.go
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeUsers moves notifications of a duplicate account to the primary account, together with
+// preferences for notification types the primary account has not configured
+func (r *notificationRepository) MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error) {
+	result := sharedmodels.NewMergeUsersResult("notification")
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		moved, _, err := database.ReassignUser(tx, &models.Notification{}, "user_id", nil, duplicateID, primaryID)
+		if err != nil {
+			return fmt.Errorf("failed to merge notifications: %w", err)
+		}
+		result.Moved["notifications"] = moved
+
+		moved, dropped, err := database.ReassignUser(tx, &models.UserNotificationPreference{}, "user_id",
+			[]string{"notification_type"}, duplicateID, primaryID)
+		if err != nil {
+			return fmt.Errorf("failed to merge preferences: %w", err)
+		}
+		result.Moved["preferences"] = moved
+		if dropped > 0 {
+			result.Dropped["preferences"] = dropped
+		}
+
+		moved, _, err = database.ReassignUser(tx, &models.EmailSuppression{}, "user_id", nil, duplicateID, primaryID)
+		if err != nil {
+			return fmt.Errorf("failed to merge email suppressions: %w", err)
+		}
+		result.Moved["email_suppressions"] = moved
+
+		moved, dropped, err = database.ReassignUser(tx, &models.NotificationView{}, "user_id",
+			[]string{"name"}, duplicateID, primaryID)
+		if err != nil {
+			return fmt.Errorf("failed to merge notification views: %w", err)
+		}
+		result.Moved["notification_views"] = moved
+		if dropped > 0 {
+			result.Dropped["notification_views"] = dropped
+		}
+
+		moved, _, err = database.ReassignUser(tx, &models.PushDevice{}, "user_id", nil, duplicateID, primaryID)
+		if err != nil {
+			return fmt.Errorf("failed to merge push devices: %w", err)
+		}
+		result.Moved["push_devices"] = moved
+
+		moved, dropped, err = database.ReassignUser(tx, &models.DailyDigest{}, "user_id",
+			[]string{"date"}, duplicateID, primaryID)
+		if err != nil {
+			return fmt.Errorf("failed to merge daily digests: %w", err)
+		}
+		result.Moved["daily_digests"] = moved
+		if dropped > 0 {
+			result.Dropped["daily_digests"] = dropped
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+)
+
+const (
+	// campaignCalendarDays is the default period of the campaign calendar
+	campaignCalendarDays = 30
+
+	// maxCampaignCalendarDays limits the period of the campaign calendar
+	maxCampaignCalendarDays = 92
+
+	// campaignSendBatchSize is the number of due campaign sends dispatched in one run
+	campaignSendBatchSize = 20
+
+	// defaultCampaignLimit is the page size of campaign lists
+	defaultCampaignLimit = 20
+)
+
+// CreateCampaign schedules an announcement campaign. Conflicts on the days of its sends are returned
+// as warnings, the campaign is scheduled anyway.
+func (u *notificationUsecase) CreateCampaign(req *models.CreateCampaignRequest, adminID uint) (*models.AnnouncementCampaign, []*models.CampaignConflict, error) {
+	now := time.Now()
+	if err := validateCreateCampaignRequest(req, now); err != nil {
+		return nil, nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	campaign := &models.AnnouncementCampaign{
+		Name:      strings.TrimSpace(req.Name),
+		Title:     strings.TrimSpace(req.Title),
+		Content:   req.Content,
+		Priority:  models.NotificationPriorityMedium,
+		Channels:  req.Channels,
+		Status:    models.CampaignStatusScheduled,
+		CreatedBy: adminID,
+	}
+	if req.Priority != nil {
+		campaign.Priority = *req.Priority
+	}
+	for _, sendReq := range req.Sends {
+		campaign.Sends = append(campaign.Sends, &models.CampaignSend{
+			Segment:     strings.TrimSpace(sendReq.Segment),
+			UserIDs:     uniqueUserIDs(sendReq.UserIDs),
+			ScheduledAt: sendReq.ScheduledAt.UTC(),
+			Status:      models.CampaignSendStatusPending,
+		})
+	}
+	slices.SortStableFunc(campaign.Sends, func(a, b *models.CampaignSend) int {
+		return a.ScheduledAt.Compare(b.ScheduledAt)
+	})
+
+	if err := u.notificationRepo.CreateCampaign(campaign); err != nil {
+		return nil, nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"campaign_id": campaign.ID,
+		"sends":       len(campaign.Sends),
+		"admin_id":    adminID,
+	}).Info("Announcement campaign scheduled")
+
+	conflicts, err := u.campaignConflicts(campaign)
+	if err != nil {
+		// The campaign is scheduled, only the warnings are missing
+		logger.WithFields(map[string]interface{}{
+			"campaign_id": campaign.ID,
+			"error":       err.Error(),
+		}).Warn("Failed to check campaign conflicts")
+	}
+
+	return campaign, conflicts, nil
+}
+
+// GetCampaign retrieves an announcement campaign with its sends
+func (u *notificationUsecase) GetCampaign(id uint) (*models.AnnouncementCampaign, error) {
+	return u.notificationRepo.GetCampaign(id)
+}
+
+// ListCampaigns lists announcement campaigns, latest first
+func (u *notificationUsecase) ListCampaigns(filter *models.CampaignFilterRequest) ([]*models.AnnouncementCampaign, int64, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultCampaignLimit
+	}
+	return u.notificationRepo.ListCampaigns(filter)
+}
+
+// GetCampaignCalendar returns scheduled announcement sends by day with conflict warnings.
+// Days are UTC dates, the period includes both ends and defaults to the next 30 days.
+func (u *notificationUsecase) GetCampaignCalendar(req *models.CampaignCalendarRequest, now time.Time) (*models.CampaignCalendar, error) {
+	from := campaignDate(now)
+	if req.From != nil {
+		from = campaignDate(*req.From)
+	}
+	to := from.AddDate(0, 0, campaignCalendarDays-1)
+	if req.To != nil {
+		to = campaignDate(*req.To)
+	}
+
+	if to.Before(from) {
+		return nil, fmt.Errorf("validation failed: to must not be before from")
+	}
+	if to.Sub(from) >= maxCampaignCalendarDays*24*time.Hour {
+		return nil, fmt.Errorf("validation failed: period can't be longer than %d days", maxCampaignCalendarDays)
+	}
+
+	return u.campaignCalendar(from, to.AddDate(0, 0, 1))
+}
+
+// CancelCampaign cancels pending sends of a campaign. Sends that already went out stay sent.
+func (u *notificationUsecase) CancelCampaign(id uint) (*models.AnnouncementCampaign, error) {
+	campaign, err := u.notificationRepo.GetCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.CampaignStatusScheduled {
+		return nil, fmt.Errorf("cannot cancel a %s campaign", campaign.Status)
+	}
+
+	cancelled, err := u.notificationRepo.CancelCampaignSends(id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.notificationRepo.FinishCampaign(id, true); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"campaign_id":     id,
+		"cancelled_sends": cancelled,
+	}).Info("Announcement campaign cancelled")
+
+	return u.notificationRepo.GetCampaign(id)
+}
+
+// CancelCampaignSend cancels one pending send of a campaign
+func (u *notificationUsecase) CancelCampaignSend(campaignID, sendID uint) (*models.AnnouncementCampaign, error) {
+	campaign, err := u.notificationRepo.GetCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(campaign.Sends, func(send *models.CampaignSend) bool { return send.ID == sendID })
+	if index < 0 {
+		return nil, fmt.Errorf("campaign send not found")
+	}
+	if status := campaign.Sends[index].Status; status != models.CampaignSendStatusPending {
+		return nil, fmt.Errorf("cannot cancel a %s send", status)
+	}
+
+	cancelled, err := u.notificationRepo.CancelCampaignSends(campaignID, &sendID)
+	if err != nil {
+		return nil, err
+	}
+	if cancelled == 0 {
+		return nil, fmt.Errorf("cannot cancel a send that already started")
+	}
+	if err := u.notificationRepo.FinishCampaign(campaignID, false); err != nil {
+		return nil, err
+	}
+
+	return u.notificationRepo.GetCampaign(campaignID)
+}
+
+// ProcessCampaignSends delivers due campaign sends to their segments. It returns the number of
+// sends that went out.
+func (u *notificationUsecase) ProcessCampaignSends(now time.Time) (int, error) {
+	sends, err := u.notificationRepo.ClaimDueCampaignSends(now, campaignSendBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	campaigns := make(map[uint]*models.AnnouncementCampaign)
+	sent, failed := 0, 0
+	for _, send := range sends {
+		campaign, ok := campaigns[send.CampaignID]
+		if !ok {
+			campaign, err = u.notificationRepo.GetCampaign(send.CampaignID)
+			if err != nil {
+				campaign = nil
+			}
+			campaigns[send.CampaignID] = campaign
+		}
+
+		if campaign == nil {
+			err = fmt.Errorf("campaign not found")
+		} else {
+			err = u.SendSystemAnnouncement(&SystemAnnouncementRequest{
+				UserIDs:  send.UserIDs,
+				Title:    campaign.Title,
+				Content:  campaign.Content,
+				Priority: campaign.Priority,
+				Channels: campaign.Channels,
+			})
+		}
+
+		send.UpdatedAt = time.Now()
+		if err != nil {
+			failed++
+			send.Status = models.CampaignSendStatusFailed
+			send.Error = campaignSendError(err)
+			logger.WithFields(map[string]interface{}{
+				"campaign_id": send.CampaignID,
+				"send_id":     send.ID,
+				"error":       err.Error(),
+			}).Error("Failed to deliver campaign send")
+		} else {
+			sent++
+			sentAt := send.UpdatedAt
+			send.Status = models.CampaignSendStatusSent
+			send.SentAt = &sentAt
+		}
+
+		if err := u.notificationRepo.UpdateCampaignSend(send); err != nil {
+			return sent, err
+		}
+		if err := u.notificationRepo.FinishCampaign(send.CampaignID, false); err != nil {
+			return sent, err
+		}
+	}
+
+	if failed > 0 {
+		return sent, fmt.Errorf("failed to deliver %d of %d campaign sends", failed, len(sends))
+	}
+	return sent, nil
+}
+
+// campaignConflicts returns conflicts on the days of the sends of a campaign that involve it
+func (u *notificationUsecase) campaignConflicts(campaign *models.AnnouncementCampaign) ([]*models.CampaignConflict, error) {
+	conflicts := []*models.CampaignConflict{}
+	if len(campaign.Sends) == 0 {
+		return conflicts, nil
+	}
+
+	// Sends are in schedule order
+	from := campaignDate(campaign.Sends[0].ScheduledAt)
+	to := campaignDate(campaign.Sends[len(campaign.Sends)-1].ScheduledAt).AddDate(0, 0, 1)
+	calendar, err := u.campaignCalendar(from, to)
+	if err != nil {
+		return conflicts, err
+	}
+
+	for _, conflict := range calendar.Conflicts {
+		if slices.Contains(conflict.CampaignIDs, campaign.ID) {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts, nil
+}
+
+// campaignCalendar builds the campaign calendar of sends scheduled in [from, to)
+func (u *notificationUsecase) campaignCalendar(from, to time.Time) (*models.CampaignCalendar, error) {
+	sends, err := u.notificationRepo.GetCampaignSendsBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var campaignIDs []uint
+	for _, send := range sends {
+		if !slices.Contains(campaignIDs, send.CampaignID) {
+			campaignIDs = append(campaignIDs, send.CampaignID)
+		}
+	}
+	campaigns, err := u.notificationRepo.GetCampaignsByIDs(campaignIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(campaigns))
+	for _, campaign := range campaigns {
+		names[campaign.ID] = campaign.Name
+	}
+
+	calendar := &models.CampaignCalendar{
+		From:      from,
+		To:        to.AddDate(0, 0, -1),
+		Days:      []*models.CampaignCalendarDay{},
+		Conflicts: []*models.CampaignConflict{},
+	}
+
+	// Sends come in schedule order, so days are built one after another
+	var day *models.CampaignCalendarDay
+	var daySends []*models.CampaignSend
+	closeDay := func() {
+		if day == nil {
+			return
+		}
+		if conflict := dayConflict(day.Date, daySends); conflict != nil {
+			day.Conflict = conflict
+			calendar.Conflicts = append(calendar.Conflicts, conflict)
+		}
+		calendar.Days = append(calendar.Days, day)
+	}
+
+	for _, send := range sends {
+		date := campaignDate(send.ScheduledAt)
+		if day == nil || !day.Date.Equal(date) {
+			closeDay()
+			day = &models.CampaignCalendarDay{Date: date, Sends: []*models.CampaignCalendarSend{}}
+			daySends = nil
+		}
+		day.Sends = append(day.Sends, &models.CampaignCalendarSend{
+			SendID:       send.ID,
+			CampaignID:   send.CampaignID,
+			CampaignName: names[send.CampaignID],
+			Segment:      send.Segment,
+			Recipients:   len(send.UserIDs),
+			ScheduledAt:  send.ScheduledAt,
+			Status:       send.Status,
+		})
+		daySends = append(daySends, send)
+	}
+	closeDay()
+
+	return calendar, nil
+}
+
+// dayConflict returns the conflict of a day if some users are reached by more than
+// models.CampaignDailyLimit campaigns that day, nil otherwise
+func dayConflict(date time.Time, sends []*models.CampaignSend) *models.CampaignConflict {
+	userCampaigns := make(map[uint][]uint)
+	for _, send := range sends {
+		for _, userID := range send.UserIDs {
+			if !slices.Contains(userCampaigns[userID], send.CampaignID) {
+				userCampaigns[userID] = append(userCampaigns[userID], send.CampaignID)
+			}
+		}
+	}
+
+	conflict := &models.CampaignConflict{Date: date, CampaignIDs: []uint{}}
+	for _, campaignIDs := range userCampaigns {
+		if len(campaignIDs) <= models.CampaignDailyLimit {
+			continue
+		}
+		conflict.Users++
+		conflict.MaxPerUser = max(conflict.MaxPerUser, len(campaignIDs))
+		for _, campaignID := range campaignIDs {
+			if !slices.Contains(conflict.CampaignIDs, campaignID) {
+				conflict.CampaignIDs = append(conflict.CampaignIDs, campaignID)
+			}
+		}
+	}
+
+	if conflict.Users == 0 {
+		return nil
+	}
+	slices.Sort(conflict.CampaignIDs)
+	return conflict
+}
+
+// validateCreateCampaignRequest validates an announcement campaign
+func validateCreateCampaignRequest(req *models.CreateCampaignRequest, now time.Time) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		return fmt.Errorf("content is required")
+	}
+	if len(req.Sends) == 0 {
+		return fmt.Errorf("at least one send is required")
+	}
+	if len(req.Sends) > models.MaxCampaignSends {
+		return fmt.Errorf("at most %d sends can be scheduled", models.MaxCampaignSends)
+	}
+
+	for i, send := range req.Sends {
+		if strings.TrimSpace(send.Segment) == "" {
+			return fmt.Errorf("send %d: segment is required", i+1)
+		}
+		if len(send.UserIDs) == 0 {
+			return fmt.Errorf("send %d: at least one user is required", i+1)
+		}
+		if !send.ScheduledAt.After(now) {
+			return fmt.Errorf("send %d: scheduled time must be in the future", i+1)
+		}
+	}
+	return nil
+}
+
+// uniqueUserIDs sorts user IDs and drops duplicates
+func uniqueUserIDs(userIDs []uint) []uint {
+	result := slices.Clone(userIDs)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// campaignSendError returns the error of a failed send cut to the size of its column
+func campaignSendError(err error) string {
+	message := []rune(err.Error())
+	if len(message) > 500 {
+		message = message[:500]
+	}
+	return string(message)
+}
+
+// campaignDate truncates t to the start of its day in UTC
+func campaignDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// dailyDigestTemplate is the template of the daily summary notification
+const dailyDigestTemplate = "daily_digest"
+
+// DigestStatsSource provides activity of a user from other services for the daily digest.
+// A nil section means the service is not configured.
+type DigestStatsSource interface {
+	MessagesStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.MessagesDigestStats, error)
+	TasksStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.TasksDigestStats, error)
+	CalendarStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.CalendarDigestStats, error)
+}
+
+// httpDigestStatsSource calls the internal digest stats endpoints of chat, task and calendar services
+type httpDigestStatsSource struct {
+	chatURL     string
+	taskURL     string
+	calendarURL string
+	client      *http.Client
+}
+
+// NewHTTPDigestStatsSource creates a stats source for services at the given base URLs.
+// Services with an empty URL are left out of digests; it returns nil if all URLs are empty.
+func NewHTTPDigestStatsSource(chatURL, taskURL, calendarURL string) DigestStatsSource {
+	if chatURL == "" && taskURL == "" && calendarURL == "" {
+		return nil
+	}
+	return &httpDigestStatsSource{
+		chatURL:     strings.TrimRight(chatURL, "/"),
+		taskURL:     strings.TrimRight(taskURL, "/"),
+		calendarURL: strings.TrimRight(calendarURL, "/"),
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// MessagesStats requests chat activity of the user
+func (s *httpDigestStatsSource) MessagesStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.MessagesDigestStats, error) {
+	if s.chatURL == "" {
+		return nil, nil
+	}
+	var stats sharedmodels.MessagesDigestStats
+	if err := s.fetch("chat", s.chatURL, req, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// TasksStats requests task activity of the user
+func (s *httpDigestStatsSource) TasksStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.TasksDigestStats, error) {
+	if s.taskURL == "" {
+		return nil, nil
+	}
+	var stats sharedmodels.TasksDigestStats
+	if err := s.fetch("task", s.taskURL, req, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// CalendarStats requests calendar of the user for today and tomorrow
+func (s *httpDigestStatsSource) CalendarStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.CalendarDigestStats, error) {
+	if s.calendarURL == "" {
+		return nil, nil
+	}
+	var stats sharedmodels.CalendarDigestStats
+	if err := s.fetch("calendar", s.calendarURL, req, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// fetch posts the request to the digest stats endpoint of a service and decodes its stats into out
+func (s *httpDigestStatsSource) fetch(service, baseURL string, req *sharedmodels.DigestStatsRequest, out interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest stats request: %w", err)
+	}
+
+	resp, err := s.client.Post(baseURL+"/api/v1/internal/users/digest-stats", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to request %s digest stats: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s service responded with status %d", service, resp.StatusCode)
+	}
+
+	var payload struct {
+		Stats json.RawMessage `json:"stats"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("failed to decode %s digest stats: %w", service, err)
+	}
+	if len(payload.Stats) == 0 || string(payload.Stats) == "null" {
+		return fmt.Errorf("%s service returned no digest stats", service)
+	}
+	if err := json.Unmarshal(payload.Stats, out); err != nil {
+		return fmt.Errorf("failed to decode %s digest stats: %w", service, err)
+	}
+	return nil
+}
+
+// SendDailyDigests sends the daily summary to users who enabled digests and whose local time has
+// reached hour, once per local day. The summary covers activity since the previous digest. A user
+// in quiet hours gets the digest on a later run of the same day. It returns the number of digests sent.
+func (u *notificationUsecase) SendDailyDigests(now time.Time, hour int) (int, error) {
+	if u.digestStats == nil {
+		return 0, nil
+	}
+
+	recipients, err := u.notificationRepo.GetDigestRecipients()
+	if err != nil {
+		return 0, err
+	}
+
+	sent, failed := 0, 0
+	for _, preference := range recipients {
+		ok, err := u.sendDailyDigest(preference, now, hour)
+		if err != nil {
+			failed++
+			logger.WithFields(map[string]interface{}{
+				"user_id": preference.UserID,
+				"error":   err.Error(),
+			}).Error("Failed to send daily digest")
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	if failed > 0 {
+		return sent, fmt.Errorf("failed to send %d of %d daily digests", failed, len(recipients))
+	}
+	return sent, nil
+}
+
+// sendDailyDigest sends the daily summary to one user if it is due, reporting whether it was sent
+func (u *notificationUsecase) sendDailyDigest(preference *models.UserNotificationPreference, now time.Time, hour int) (bool, error) {
+	local := now.In(userLocation(preference))
+	if local.Hour() < hour {
+		return false, nil
+	}
+
+	date := local.Format("2006-01-02")
+	last, err := u.notificationRepo.GetLastDailyDigest(preference.UserID)
+	if err != nil {
+		return false, err
+	}
+	if last != nil && last.Date >= date {
+		return false, nil
+	}
+
+	req := &sharedmodels.DigestStatsRequest{
+		UserID:   preference.UserID,
+		Since:    local.Add(-24 * time.Hour),
+		DayStart: time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location()),
+	}
+	if last != nil {
+		req.Since = last.SentAt.In(local.Location())
+	}
+
+	variables, err := u.dailyDigestVariables(req)
+	if err != nil {
+		return false, err
+	}
+	variables["Date"] = local.Format("02.01.2006")
+
+	response, err := u.SendTemplatedNotification(&TemplatedNotificationRequest{
+		UserID:       preference.UserID,
+		Type:         models.NotificationTypeSystem,
+		TemplateName: dailyDigestTemplate,
+		Variables:    variables,
+	})
+	if err != nil {
+		return false, err
+	}
+	if response == nil {
+		// Blocked by preferences, e.g. quiet hours, the next run tries again
+		return false, nil
+	}
+
+	notificationID := response.ID
+	return true, u.notificationRepo.RecordDailyDigest(&models.DailyDigest{
+		UserID:         preference.UserID,
+		Date:           date,
+		NotificationID: &notificationID,
+		SentAt:         now,
+	})
+}
+
+// dailyDigestVariables collects stats of the user from other services into template variables.
+// A section whose service fails is left out of the digest; the digest fails only if all do.
+func (u *notificationUsecase) dailyDigestVariables(req *sharedmodels.DigestStatsRequest) (map[string]interface{}, error) {
+	variables := map[string]interface{}{
+		"NewMessages":    int64(0),
+		"UnreadMessages": int64(0),
+		"NewTasks":       int64(0),
+		"OverdueTasks":   int64(0),
+		"TodayEvents":    int64(0),
+	}
+	sections, failures := 0, 0
+
+	logFailure := func(service string, err error) {
+		failures++
+		logger.WithFields(map[string]interface{}{
+			"user_id": req.UserID,
+			"service": service,
+			"error":   err.Error(),
+		}).Warn("Failed to get digest stats, section left out of daily digest")
+	}
+
+	if messages, err := u.digestStats.MessagesStats(req); err != nil {
+		logFailure("chat", err)
+	} else if messages != nil {
+		sections++
+		variables["MessagesStats"] = messages
+		variables["NewMessages"] = messages.NewMessages
+		variables["UnreadMessages"] = messages.UnreadMessages
+	}
+
+	if tasks, err := u.digestStats.TasksStats(req); err != nil {
+		logFailure("task", err)
+	} else if tasks != nil {
+		sections++
+		variables["TasksStats"] = tasks
+		variables["NewTasks"] = tasks.NewTasks
+		variables["OverdueTasks"] = tasks.OverdueTasks
+	}
+
+	if calendar, err := u.digestStats.CalendarStats(req); err != nil {
+		logFailure("calendar", err)
+	} else if calendar != nil {
+		sections++
+		variables["CalendarStats"] = calendar
+		variables["TodayEvents"] = calendar.TodayEvents
+	}
+
+	if sections == 0 && failures > 0 {
+		return nil, fmt.Errorf("failed to get digest stats from %d services", failures)
+	}
+	return variables, nil
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/email"
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+)
+
+// emailSuppressedError is the delivery error of emails skipped because the address bounced or complained
+const emailSuppressedError = "Email address is suppressed after bounces or a spam complaint"
+
+// bounceSource is the source of bounces parsed from delivery status notifications of the mail server
+const bounceSource = "smtp"
+
+// ProcessEmailFeedback records bounces and complaints reported by the email provider and
+// suppresses addresses that must not receive email any more
+func (u *notificationUsecase) ProcessEmailFeedback(req *models.EmailFeedbackRequest) (*models.EmailFeedbackResult, error) {
+	if len(req.Events) == 0 {
+		return nil, fmt.Errorf("validation failed: at least one event is required")
+	}
+
+	source := strings.TrimSpace(req.Provider)
+	if source == "" {
+		source = "provider"
+	}
+
+	result := &models.EmailFeedbackResult{}
+	for i := range req.Events {
+		suppressed, err := u.recordEmailFeedback(&req.Events[i], source)
+		if err != nil {
+			return result, err
+		}
+		result.Processed++
+		if suppressed {
+			result.Suppressed++
+		}
+	}
+
+	return result, nil
+}
+
+// ProcessBounceMessage records the failed recipients of a bounce message returned by the mail server
+func (u *notificationUsecase) ProcessBounceMessage(message io.Reader) (*models.EmailFeedbackResult, error) {
+	reports, err := email.ParseBounce(message)
+	if err != nil {
+		// Malformed or non-bounce messages are rejected, so the mail server keeps them for inspection
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	result := &models.EmailFeedbackResult{}
+	if len(reports) == 0 {
+		// Delay warnings and success notices carry no failed recipients
+		result.Ignored++
+		return result, nil
+	}
+
+	for _, report := range reports {
+		event := &models.EmailFeedbackEvent{
+			Type:       models.EmailFeedbackBounce,
+			Email:      report.Recipient,
+			BounceType: models.EmailBounceSoft,
+			Detail:     strings.TrimSpace(report.Status + " " + report.Diagnostic),
+		}
+		if report.Permanent {
+			event.BounceType = models.EmailBounceHard
+		}
+		if report.UserID != 0 {
+			userID := report.UserID
+			event.UserID = &userID
+		}
+
+		suppressed, err := u.recordEmailFeedback(event, bounceSource)
+		if err != nil {
+			return result, err
+		}
+		result.Processed++
+		if suppressed {
+			result.Suppressed++
+		}
+	}
+
+	return result, nil
+}
+
+// recordEmailFeedback applies a bounce or complaint to the suppression record of its address.
+// It reports whether the address became suppressed.
+func (u *notificationUsecase) recordEmailFeedback(event *models.EmailFeedbackEvent, source string) (bool, error) {
+	suppression, err := u.notificationRepo.GetEmailSuppression(event.Email)
+	if err != nil {
+		return false, err
+	}
+	if suppression == nil {
+		suppression = &models.EmailSuppression{Email: event.Email, Status: models.EmailAddressStatusOK}
+	}
+
+	suppressed := applyEmailFeedback(suppression, event, source, time.Now())
+	if err := u.notificationRepo.SaveEmailSuppression(suppression); err != nil {
+		return false, err
+	}
+
+	fields := map[string]interface{}{
+		"email":       suppression.Email,
+		"user_id":     suppression.UserID,
+		"type":        event.Type,
+		"bounce_type": event.BounceType,
+		"source":      source,
+		"status":      suppression.Status,
+	}
+	if suppressed {
+		logger.WithFields(fields).Warn("Email address suppressed")
+	} else {
+		logger.WithFields(fields).Info("Email feedback recorded")
+	}
+
+	return suppressed, nil
+}
+
+// applyEmailFeedback updates a suppression record with a bounce or complaint. Complaints and hard
+// bounces suppress the address at once, soft bounces after SoftBounceSuppressThreshold of them.
+// It reports whether the address became suppressed.
+func applyEmailFeedback(suppression *models.EmailSuppression, event *models.EmailFeedbackEvent, source string, now time.Time) bool {
+	wasSuppressed := suppression.Suppressed
+
+	switch event.Type {
+	case models.EmailFeedbackComplaint:
+		suppression.Complaints++
+		suppression.Status = models.EmailAddressStatusComplained
+		suppression.Suppressed = true
+
+	case models.EmailFeedbackBounce:
+		if suppression.Status != models.EmailAddressStatusComplained {
+			suppression.Status = models.EmailAddressStatusBouncing
+		}
+		if event.BounceType == models.EmailBounceSoft {
+			suppression.SoftBounces++
+			if suppression.SoftBounces >= models.SoftBounceSuppressThreshold {
+				suppression.Suppressed = true
+			}
+		} else {
+			suppression.HardBounces++
+			suppression.Suppressed = true
+		}
+	}
+
+	if event.UserID != nil {
+		userID := *event.UserID
+		suppression.UserID = &userID
+	}
+
+	occurredAt := now
+	if event.OccurredAt != nil {
+		occurredAt = *event.OccurredAt
+	}
+	if suppression.LastEventAt == nil || occurredAt.After(*suppression.LastEventAt) {
+		suppression.LastEventAt = &occurredAt
+	}
+	if event.Detail != "" {
+		suppression.LastDetail = event.Detail
+	}
+	suppression.Source = source
+
+	if suppression.Suppressed && !wasSuppressed {
+		suppression.SuppressedAt = &now
+		return true
+	}
+	return false
+}
+
+// GetEmailDeliveryStatus returns whether email notifications reach the user
+func (u *notificationUsecase) GetEmailDeliveryStatus(userID uint) (*models.EmailDeliveryStatus, error) {
+	suppression, err := u.notificationRepo.GetUserEmailSuppression(userID)
+	if err != nil {
+		return nil, err
+	}
+	if suppression == nil {
+		return &models.EmailDeliveryStatus{Status: models.EmailAddressStatusOK}, nil
+	}
+	return suppression.DeliveryStatus(), nil
+}
+
+// ListEmailSuppressions returns suppression records for administrators
+func (u *notificationUsecase) ListEmailSuppressions(filter *models.EmailSuppressionFilter) ([]*models.EmailSuppression, int64, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50 // default
+	}
+	return u.notificationRepo.ListEmailSuppressions(filter)
+}
+
+// ClearEmailSuppression lets email through to an address again, e.g. after the user fixed their
+// mailbox. Counters are reset, the record is kept for audit.
+func (u *notificationUsecase) ClearEmailSuppression(id, adminID uint) (*models.EmailSuppression, error) {
+	suppression, err := u.notificationRepo.GetEmailSuppressionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	suppression.Status = models.EmailAddressStatusOK
+	suppression.Suppressed = false
+	suppression.HardBounces = 0
+	suppression.SoftBounces = 0
+	suppression.Complaints = 0
+	suppression.SuppressedAt = nil
+	suppression.ClearedAt = &now
+	suppression.ClearedBy = &adminID
+
+	if err := u.notificationRepo.SaveEmailSuppression(suppression); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"suppression_id": suppression.ID,
+		"email":          suppression.Email,
+		"user_id":        suppression.UserID,
+		"admin_id":       adminID,
+	}).Warn("Email suppression cleared")
+
+	return suppression, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/i18n"
+)
+
+// defaultLocale returns the organization locale of recipients without a locale in their profile
+func (u *notificationUsecase) defaultLocale() i18n.Locale {
+	if locale := u.orgSettings.Get().DefaultLocale; locale.IsValid() {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+// recipientLocale returns the locale of a recipient, the organization default if it is not set
+func (u *notificationUsecase) recipientLocale(locale i18n.Locale) i18n.Locale {
+	if locale.IsValid() {
+		return locale
+	}
+	return u.defaultLocale()
+}
+
+// PreviewNotificationEmail renders the email of a notification in each of the locales
+func (u *notificationUsecase) PreviewNotificationEmail(req *models.CreateNotificationRequest, locales []i18n.Locale) ([]*models.EmailPreview, error) {
+	if err := u.validateCreateNotificationRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	previews := make([]*models.EmailPreview, 0, len(locales))
+	for _, locale := range locales {
+		notification := previewNotification(req.UserID, req.Type, req.Title, req.Message, req.ActionURL)
+		if req.Priority != nil {
+			notification.Priority = *req.Priority
+		}
+		notification.Locale = locale
+		previews = append(previews, u.previewEmail(notification))
+	}
+	return previews, nil
+}
+
+// PreviewAnnouncementEmail renders the email of an announcement in each of the locales,
+// with the translation recipients of the locale get
+func (u *notificationUsecase) PreviewAnnouncementEmail(req *SystemAnnouncementRequest, locales []i18n.Locale) ([]*models.EmailPreview, error) {
+	if err := u.validateSystemAnnouncementRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	previews := make([]*models.EmailPreview, 0, len(locales))
+	for _, locale := range locales {
+		title, content := req.localized(locale)
+		notification := previewNotification(0, models.NotificationTypeAnnounce, title, content, req.ReadMoreURL)
+		notification.Priority = req.Priority
+		notification.Locale = locale
+		previews = append(previews, u.previewEmail(notification))
+	}
+	return previews, nil
+}
+
+// previewNotification builds an in-memory notification, it is only used for rendering
+func previewNotification(userID uint, notificationType models.NotificationType, title, message, actionURL string) *models.Notification {
+	now := time.Now()
+	notification := &models.Notification{
+		UserID:    userID,
+		Type:      notificationType,
+		Priority:  models.NotificationPriorityMedium,
+		Status:    models.NotificationStatusPending,
+		Title:     strings.TrimSpace(title),
+		Message:   strings.TrimSpace(message),
+		ActionURL: actionURL,
+	}
+	notification.CreatedAt = now
+	notification.UpdatedAt = now
+	return notification
+}
+
+// previewEmail renders the email of a notification as it would be sent
+func (u *notificationUsecase) previewEmail(notification *models.Notification) *models.EmailPreview {
+	return &models.EmailPreview{
+		Locale:   u.recipientLocale(notification.Locale),
+		Subject:  notification.Title,
+		HTMLBody: u.buildEmailHTML(notification),
+		TextBody: u.buildEmailText(notification),
+	}
+}
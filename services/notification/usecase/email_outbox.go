@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"tachyon-messenger/services/notification/email"
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/switches"
+)
+
+const (
+	emailOutboxBatchSize = 200
+	emailOutboxMaxDelay  = time.Hour
+	emailOutboxMaxAge    = 72 * time.Hour
+)
+
+// emailOutboxExpiredError is the delivery error of emails that waited in the outbox for too long
+const emailOutboxExpiredError = "SMTP server was unavailable for too long, email was not sent"
+
+// smtpHealth tracks whether the SMTP server accepts connections, as seen by sends and health probes
+type smtpHealth struct {
+	mu        sync.Mutex
+	healthy   bool
+	checkedAt *time.Time
+	lastError string
+}
+
+// record updates the server state after a send attempt or probe, nil err means the server is up
+func (h *smtpHealth) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.checkedAt = &now
+	h.healthy = err == nil
+	h.lastError = ""
+	if err != nil {
+		h.lastError = err.Error()
+	}
+}
+
+// status returns whether the server is up and the last error if it is not
+func (h *smtpHealth) status() (bool, string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.healthy, h.lastError
+}
+
+// queueEmail keeps an email delivery in the outbox until the SMTP server is back. The delivery
+// stays pending, so it is neither counted as failed nor retried by the worker.
+func (u *notificationUsecase) queueEmail(delivery *models.NotificationDelivery, lastError string) error {
+	entry := &models.EmailOutboxEntry{
+		DeliveryID:     delivery.ID,
+		NotificationID: delivery.NotificationID,
+		LastError:      lastError,
+		NextAttemptAt:  time.Now().Add(emailOutboxDelay(0)),
+	}
+	if err := u.notificationRepo.EnqueueEmailOutbox(entry); err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"delivery_id":     delivery.ID,
+		"notification_id": delivery.NotificationID,
+		"error":           lastError,
+	}).Warn("SMTP server is unavailable, email queued in outbox")
+
+	return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusPending, "Queued in email outbox: "+lastError)
+}
+
+// ProcessEmailOutbox sends emails waiting in the outbox. The SMTP server is probed first, when it
+// comes back after an outage all waiting emails are sent regardless of their retry schedule.
+// It returns the number of sent emails.
+func (u *notificationUsecase) ProcessEmailOutbox() (int, error) {
+	if u.emailSender == nil {
+		return 0, nil
+	}
+	// Waiting emails are kept while sending is switched off
+	if u.killSwitches.IsActive(switches.DisableEmailSending) || u.killSwitches.IsActive(switches.HoldOutboundNotifications) {
+		return 0, nil
+	}
+
+	now := time.Now()
+	pending, err := u.notificationRepo.GetEmailOutbox(now, false, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	wasHealthy, _ := u.smtp.status()
+	if err := u.emailSender.CheckConnection(); err != nil {
+		u.smtp.record(err)
+		logger.WithFields(map[string]interface{}{
+			"error": err.Error(),
+		}).Warn("SMTP server is still unavailable, email outbox is kept")
+		return 0, nil
+	}
+	u.smtp.record(nil)
+
+	resumed := !wasHealthy
+	if resumed {
+		logger.Info("SMTP server is available again, sending emails from outbox")
+	}
+
+	sent := 0
+	for {
+		entries, err := u.notificationRepo.GetEmailOutbox(now, !resumed, emailOutboxBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, entry := range entries {
+			delivered, err := u.sendOutboxEmail(entry, now)
+			if err != nil {
+				return sent, err
+			}
+			if delivered {
+				sent++
+			}
+			// The server failed again, the rest waits for the next run
+			if healthy, _ := u.smtp.status(); !healthy {
+				return sent, nil
+			}
+		}
+
+		if len(entries) < emailOutboxBatchSize {
+			break
+		}
+	}
+
+	if sent > 0 {
+		logger.WithFields(map[string]interface{}{
+			"sent":    sent,
+			"resumed": resumed,
+		}).Info("Emails sent from outbox")
+	}
+
+	return sent, nil
+}
+
+// sendOutboxEmail sends a single email from the outbox and removes or postpones its entry.
+// It reports whether the email was sent.
+func (u *notificationUsecase) sendOutboxEmail(entry *models.EmailOutboxEntry, now time.Time) (bool, error) {
+	if now.Sub(entry.CreatedAt) > emailOutboxMaxAge {
+		if err := u.notificationRepo.DiscardDelivery(entry.DeliveryID, emailOutboxExpiredError); err != nil {
+			return false, err
+		}
+		return false, u.notificationRepo.DeleteEmailOutboxEntry(entry.ID)
+	}
+
+	notification, err := u.notificationRepo.GetNotificationByID(entry.NotificationID)
+	if err != nil {
+		// The notification was deleted meanwhile, there is nothing to send
+		if err := u.notificationRepo.DiscardDelivery(entry.DeliveryID, err.Error()); err != nil {
+			return false, err
+		}
+		return false, u.notificationRepo.DeleteEmailOutboxEntry(entry.ID)
+	}
+
+	// TODO: Get user email from user service
+	userEmail := "user@example.com" // This should be fetched from user service
+
+	suppression, err := u.notificationRepo.FindActiveEmailSuppression(notification.UserID, userEmail)
+	if err != nil {
+		return false, err
+	}
+	if suppression != nil {
+		if err := u.notificationRepo.DiscardDelivery(entry.DeliveryID, emailSuppressedError); err != nil {
+			return false, err
+		}
+		return false, u.notificationRepo.DeleteEmailOutboxEntry(entry.ID)
+	}
+
+	sendErr := u.emailSender.SendEmail(u.buildNotificationEmail(notification, userEmail))
+	switch {
+	case sendErr == nil:
+		if err := u.notificationRepo.UpdateDeliveryStatus(entry.DeliveryID, models.NotificationStatusDelivered, ""); err != nil {
+			return false, err
+		}
+		return true, u.notificationRepo.DeleteEmailOutboxEntry(entry.ID)
+
+	case email.IsTemporaryFailure(sendErr):
+		u.smtp.record(sendErr)
+		return false, u.notificationRepo.RetryEmailOutboxEntry(entry.ID, sendErr.Error(), now.Add(emailOutboxDelay(entry.Attempts+1)))
+
+	default:
+		// Rejected by the server, the worker retries it like any other failed delivery
+		if err := u.notificationRepo.UpdateDeliveryStatus(entry.DeliveryID, models.NotificationStatusFailed, sendErr.Error()); err != nil {
+			return false, err
+		}
+		return false, u.notificationRepo.DeleteEmailOutboxEntry(entry.ID)
+	}
+}
+
+// GetEmailOutboxStatus returns the SMTP server state and the size of the email outbox
+func (u *notificationUsecase) GetEmailOutboxStatus() (*models.EmailOutboxStatus, error) {
+	status, err := u.notificationRepo.GetEmailOutboxStats(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	u.smtp.mu.Lock()
+	status.SMTPHealthy = u.smtp.healthy
+	status.SMTPCheckedAt = u.smtp.checkedAt
+	status.SMTPError = u.smtp.lastError
+	u.smtp.mu.Unlock()
+	status.MaxQueueAgeHours = int(emailOutboxMaxAge / time.Hour)
+
+	return status, nil
+}
+
+// emailOutboxDelay returns the delay before the next attempt of an outbox entry, doubling from
+// a minute up to an hour
+func emailOutboxDelay(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 0; i < attempts && delay < emailOutboxMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, emailOutboxMaxDelay)
+}
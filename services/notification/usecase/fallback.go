@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+)
+
+// fallbackLease is how long a claimed chain is hidden from other workers while its step is delivered
+const fallbackLease = 5 * time.Minute
+
+// GetFallbackPolicies returns channel fallback policies of all priorities that have one
+func (u *notificationUsecase) GetFallbackPolicies() ([]*models.NotificationFallbackPolicy, error) {
+	return u.notificationRepo.GetFallbackPolicies()
+}
+
+// SetFallbackPolicy sets the channel fallback policy of a priority. It applies to notifications
+// sent afterwards, chains that already started keep their steps.
+func (u *notificationUsecase) SetFallbackPolicy(priority models.NotificationPriority, req *models.FallbackPolicyRequest, adminID uint) (*models.NotificationFallbackPolicy, error) {
+	if err := validateFallbackPolicyRequest(priority, req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	policy := &models.NotificationFallbackPolicy{
+		Priority:  priority,
+		Enabled:   true,
+		Steps:     req.Steps,
+		UpdatedBy: adminID,
+	}
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+
+	if err := u.notificationRepo.SaveFallbackPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"priority": priority,
+		"enabled":  policy.Enabled,
+		"steps":    len(policy.Steps),
+		"admin_id": adminID,
+	}).Info("Notification fallback policy updated")
+
+	return policy, nil
+}
+
+// DeleteFallbackPolicy removes the channel fallback policy of a priority
+func (u *notificationUsecase) DeleteFallbackPolicy(priority models.NotificationPriority) error {
+	if !isNotificationPriority(priority) {
+		return fmt.Errorf("validation failed: unknown priority %q", priority)
+	}
+	return u.notificationRepo.DeleteFallbackPolicy(priority)
+}
+
+// ClaimDueFallbacks returns fallback chains whose next step is due, claimed for the caller
+func (u *notificationUsecase) ClaimDueFallbacks(now time.Time, limit int) ([]*models.NotificationFallback, error) {
+	return u.notificationRepo.ClaimDueFallbacks(now, now.Add(fallbackLease), limit)
+}
+
+// UpdateFallback saves the state of a fallback chain
+func (u *notificationUsecase) UpdateFallback(fallback *models.NotificationFallback) error {
+	return u.notificationRepo.UpdateFallback(fallback)
+}
+
+// DeliverFallbackStep sends a notification through the channel of a fallback step
+func (u *notificationUsecase) DeliverFallbackStep(notificationID uint, channel models.DeliveryChannel) error {
+	notification, err := u.notificationRepo.GetNotificationByID(notificationID)
+	if err != nil {
+		return err
+	}
+	return u.sendThroughChannel(notification, channel)
+}
+
+// startFallback starts the fallback chain of a notification if its priority has a policy, and returns
+// the channels to send through right away. Steps use only channels the user allows; steps without
+// delay at the head of the chain are sent right away, the rest are left to the worker.
+func (u *notificationUsecase) startFallback(notification *models.Notification, channels []models.DeliveryChannel) []models.DeliveryChannel {
+	policy, err := u.notificationRepo.GetFallbackPolicy(notification.Priority)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"notification_id": notification.ID,
+			"priority":        notification.Priority,
+			"error":           err.Error(),
+		}).Warn("Failed to get fallback policy, sending through all channels")
+		return channels
+	}
+	if policy == nil || !policy.Enabled {
+		return channels
+	}
+
+	allowed := make(map[models.DeliveryChannel]bool, len(channels))
+	for _, channel := range channels {
+		allowed[channel] = true
+	}
+
+	// Channels of the policy are used only by its steps
+	var steps []models.FallbackStep
+	inChain := make(map[models.DeliveryChannel]bool)
+	for _, step := range policy.Steps {
+		inChain[step.Channel] = true
+		if allowed[step.Channel] {
+			steps = append(steps, step)
+		}
+	}
+
+	immediate := make([]models.DeliveryChannel, 0, len(channels))
+	for _, channel := range channels {
+		if !inChain[channel] {
+			immediate = append(immediate, channel)
+		}
+	}
+
+	next := 0
+	for next < len(steps) && steps[next].DelayMinutes == 0 {
+		immediate = append(immediate, steps[next].Channel)
+		next++
+	}
+	if next == len(steps) {
+		return immediate
+	}
+
+	nextAttemptAt := notification.CreatedAt.Add(time.Duration(steps[next].DelayMinutes) * time.Minute)
+	fallback := &models.NotificationFallback{
+		NotificationID: notification.ID,
+		UserID:         notification.UserID,
+		Steps:          steps,
+		NextStep:       next,
+		State:          models.FallbackStateActive,
+		NextAttemptAt:  &nextAttemptAt,
+		CreatedAt:      notification.CreatedAt,
+	}
+	if next > 0 {
+		fallback.LastChannel = steps[next-1].Channel
+	}
+
+	if err := u.notificationRepo.CreateFallback(fallback); err != nil {
+		// Without the chain the delayed channels would never be tried
+		logger.WithFields(map[string]interface{}{
+			"notification_id": notification.ID,
+			"error":           err.Error(),
+		}).Error("Failed to start fallback chain, sending through all channels")
+		return channels
+	}
+
+	return immediate
+}
+
+// cancelReadFallbacks stops fallback chains of notifications the users have read
+func (u *notificationUsecase) cancelReadFallbacks(userIDs ...uint) {
+	cancelled, err := u.notificationRepo.CancelReadFallbacks(userIDs...)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_ids": userIDs,
+			"error":    err.Error(),
+		}).Warn("Failed to cancel fallback chains of read notifications")
+		return
+	}
+	if cancelled > 0 {
+		logger.WithField("cancelled_count", cancelled).Debug("Cancelled fallback chains of read notifications")
+	}
+}
+
+// validateFallbackPolicyRequest validates a fallback policy of a priority
+func validateFallbackPolicyRequest(priority models.NotificationPriority, req *models.FallbackPolicyRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+	if !isNotificationPriority(priority) {
+		return fmt.Errorf("unknown priority %q", priority)
+	}
+	if len(req.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+
+	seen := make(map[models.DeliveryChannel]bool, len(req.Steps))
+	for i, step := range req.Steps {
+		switch step.Channel {
+		case models.DeliveryChannelEmail, models.DeliveryChannelPush, models.DeliveryChannelSMS,
+			models.DeliveryChannelSlack, models.DeliveryChannelWebhook:
+		default:
+			// In-app delivery is immediate and never part of a chain
+			return fmt.Errorf("step %d: channel %q can't be used in a fallback chain", i+1, step.Channel)
+		}
+		if seen[step.Channel] {
+			return fmt.Errorf("step %d: channel %q is already used", i+1, step.Channel)
+		}
+		seen[step.Channel] = true
+
+		if step.DelayMinutes < 0 {
+			return fmt.Errorf("step %d: delay can't be negative", i+1)
+		}
+		if i > 0 && step.DelayMinutes < req.Steps[i-1].DelayMinutes {
+			return fmt.Errorf("step %d: delay must not be shorter than the previous step's", i+1)
+		}
+	}
+	return nil
+}
+
+// isNotificationPriority reports whether the priority is a known notification priority
+func isNotificationPriority(priority models.NotificationPriority) bool {
+	switch priority {
+	case models.NotificationPriorityLow, models.NotificationPriorityMedium,
+		models.NotificationPriorityHigh, models.NotificationPriorityCritical:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// exportBatchSize is how many notifications are loaded at a time while exporting
+const exportBatchSize = 500
+
+// ExportNotifications passes notifications of the user created in the requested days to write
+// in creation order, loading them in batches so exports of any size use bounded memory.
+// Notifications past the retention policy, deleted or expired ones are not exported.
+func (u *notificationUsecase) ExportNotifications(userID uint, req *models.ExportNotificationsRequest, write func(*models.NotificationResponse) error) error {
+	now := time.Now()
+	policy := u.orgSettings.Get().Retention.Policy(sharedmodels.RetentionNotifications)
+	from := now.AddDate(0, 0, -policy.Days)
+	to := now
+
+	if req != nil {
+		if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+			return fmt.Errorf("validation failed: to must not be before from")
+		}
+		if req.From != nil && req.From.After(from) {
+			from = *req.From
+		}
+		if req.To != nil {
+			// The last day is included
+			if end := req.To.AddDate(0, 0, 1); end.Before(to) {
+				to = end
+			}
+		}
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	var afterID uint
+	for {
+		notifications, err := u.notificationRepo.GetNotificationsForExport(userID, from, to, now, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, notification := range notifications {
+			if err := write(notification.ToResponse()); err != nil {
+				return err
+			}
+		}
+		if len(notifications) < exportBatchSize {
+			return nil
+		}
+		afterID = notifications[len(notifications)-1].ID
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+)
+
+const (
+	// defaultSyncLimit is the number of changes returned by a sync request without a limit
+	defaultSyncLimit = 100
+
+	// syncOverlap is how far synced_at lags behind the sync, so changes committed while it ran
+	// are returned again by the next sync. Applying a change twice is harmless.
+	syncOverlap = 2 * time.Second
+)
+
+// SyncNotifications returns user's notification changes since the previous sync. Notifications
+// the device already has and that were only read are returned as compact read states.
+// Without since only the unread count and a position to sync from are returned.
+func (u *notificationUsecase) SyncNotifications(userID uint, req *models.NotificationSyncRequest) (*models.NotificationSyncResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSyncLimit
+	}
+	if limit < 0 || limit > models.MaxSyncLimit {
+		return nil, fmt.Errorf("validation failed: limit must be between 1 and %d", models.MaxSyncLimit)
+	}
+
+	now := time.Now()
+	unreadCount, err := u.GetUnreadCount(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync notifications: %w", err)
+	}
+
+	response := &models.NotificationSyncResponse{
+		Notifications: []*models.NotificationResponse{},
+		Read:          []models.NotificationReadState{},
+		UnreadCount:   unreadCount,
+		SyncedAt:      now.Add(-syncOverlap),
+	}
+	if req.Since == nil {
+		return response, nil
+	}
+	since := *req.Since
+
+	changes, err := u.notificationRepo.GetNotificationChanges(userID, since, req.AfterID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync notifications: %w", err)
+	}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		last := changes[len(changes)-1]
+		response.HasMore = true
+		response.SyncedAt = last.UpdatedAt
+		response.AfterID = last.ID
+	}
+
+	for _, notification := range changes {
+		if notification.IsRead && !notification.CreatedAt.After(since) {
+			response.Read = append(response.Read, models.NotificationReadState{
+				ID:     notification.ID,
+				ReadAt: notification.ReadAt,
+			})
+			continue
+		}
+		response.Notifications = append(response.Notifications, notification.ToResponse())
+	}
+
+	return response, nil
+}
+
+// SubscribeEvents returns real-time notification events of the user until ctx is done
+func (u *notificationUsecase) SubscribeEvents(ctx context.Context, userID uint) (<-chan []byte, error) {
+	return u.events.Subscribe(ctx, userID)
+}
+
+// publishEvent sends an event with the current unread count to all connected devices of the user.
+// Reads and unread count changes also go out as silent pushes, so mobile apps in the background
+// update the badge. New notifications reach them through the push channel.
+func (u *notificationUsecase) publishEvent(userID uint, event *models.NotificationEvent) {
+	syncPush := u.pushSender != nil && event.Type != models.NotificationEventCreated
+	if u.events == nil && !syncPush {
+		return
+	}
+
+	count, err := u.GetUnreadCount(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Warn("Failed to get unread count for notification event")
+		return
+	}
+	event.UnreadCount = count
+	event.At = time.Now()
+
+	if syncPush {
+		go u.sendSyncPush(userID, event.Type, count)
+	}
+	if u.events == nil {
+		return
+	}
+
+	if err := u.events.Publish(userID, event); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"type":    event.Type,
+			"error":   err.Error(),
+		}).Warn("Failed to publish notification event")
+	}
+}
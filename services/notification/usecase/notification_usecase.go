@@ -2,19 +2,37 @@
 package usecase
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"html"
+	"io"
 	"strings"
 	"time"
 
 	"tachyon-messenger/services/notification/email"
 	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/services/notification/push"
 	"tachyon-messenger/services/notification/repository"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/orgsettings"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/switches"
 
 	"gorm.io/gorm"
 )
 
+// bulkNotificationChunkSize limits how many recipients of a bulk request are held in memory at once
+const bulkNotificationChunkSize = 1000
+
+// unreadReconcileBatchSize is the number of users whose unread counts are loaded in one query
+const unreadReconcileBatchSize = 500
+
 // NotificationUsecase defines the interface for notification business logic
 type NotificationUsecase interface {
 	// Send notifications
@@ -23,6 +41,10 @@ type NotificationUsecase interface {
 	SendTemplatedNotification(req *TemplatedNotificationRequest) (*models.NotificationResponse, error)
 	SendSystemAnnouncement(req *SystemAnnouncementRequest) error
 
+	// Email previews in recipient locales, nothing is sent or stored
+	PreviewNotificationEmail(req *models.CreateNotificationRequest, locales []i18n.Locale) ([]*models.EmailPreview, error)
+	PreviewAnnouncementEmail(req *SystemAnnouncementRequest, locales []i18n.Locale) ([]*models.EmailPreview, error)
+
 	// Get notifications
 	GetUserNotifications(userID uint, filter *models.NotificationFilterRequest) (*NotificationListResponse, error)
 	GetNotificationByID(userID, notificationID uint) (*models.NotificationResponse, error)
@@ -33,27 +55,112 @@ type NotificationUsecase interface {
 	MarkAsRead(userID uint, req *models.MarkAsReadRequest) error
 	MarkAllAsRead(userID uint) error
 	MarkAllAsReadByType(userID uint, notificationType models.NotificationType) error
+	MarkAsReadByFilter(userID uint, req *models.MarkAsReadByFilterRequest) (int64, error)
+	ResolveRelatedNotifications(req *models.ResolveNotificationsRequest) (int64, error)
+
+	// Cross-device sync
+	SyncNotifications(userID uint, req *models.NotificationSyncRequest) (*models.NotificationSyncResponse, error)
+	SubscribeEvents(ctx context.Context, userID uint) (<-chan []byte, error)
+
+	// Self-service export
+	ExportNotifications(userID uint, req *models.ExportNotificationsRequest, write func(*models.NotificationResponse) error) error
 
 	// Search and filtering
-	SearchNotifications(userID uint, query string, filter *models.NotificationFilterRequest) (*NotificationListResponse, error)
+	SearchNotifications(userID uint, searchQuery string, filter *models.NotificationFilterRequest) (*NotificationListResponse, error)
 	GetNotificationsByRelatedObject(relatedType string, relatedID uint, userID *uint) ([]*models.NotificationResponse, error)
 
 	// User preferences
 	GetUserPreferences(userID uint) ([]*models.UserNotificationPreference, error)
 	UpdateUserPreference(userID uint, req *models.UserPreferenceRequest) error
 	GetUserPreference(userID uint, notificationType models.NotificationType) (*models.UserNotificationPreference, error)
+	GetEmailDeliveryStatus(userID uint) (*models.EmailDeliveryStatus, error)
+
+	// Organization default preferences
+	GetPreferenceDefaults() ([]*models.NotificationPreferenceDefault, error)
+	SetPreferenceDefault(notificationType models.NotificationType, req *models.PreferenceDefaultRequest, adminID uint) (*models.NotificationPreferenceDefault, error)
+	DeletePreferenceDefault(notificationType models.NotificationType) error
+	ResetUserPreferences(req *models.ResetUserPreferencesRequest) (int64, error)
+	ExportUserPreferences(req *models.PreferenceExportRequest) ([]*models.EffectivePreferenceResponse, error)
+
+	// Saved views
+	GetNotificationViews(userID uint) ([]*models.NotificationView, error)
+	GetNotificationView(userID, viewID uint) (*models.NotificationView, error)
+	CreateNotificationView(userID uint, req *models.CreateNotificationViewRequest) (*models.NotificationView, error)
+	UpdateNotificationView(userID, viewID uint, req *models.UpdateNotificationViewRequest) (*models.NotificationView, error)
+	DeleteNotificationView(userID, viewID uint) error
+
+	// Push devices
+	RegisterPushDevice(userID uint, req *models.RegisterPushDeviceRequest) (*models.PushDevice, error)
+	GetPushDevices(userID uint) ([]*models.PushDevice, error)
+	DeletePushDevice(userID, deviceID uint) error
+
+	// Email bounces and complaints
+	ProcessEmailFeedback(req *models.EmailFeedbackRequest) (*models.EmailFeedbackResult, error)
+	ProcessBounceMessage(message io.Reader) (*models.EmailFeedbackResult, error)
+	ListEmailSuppressions(filter *models.EmailSuppressionFilter) ([]*models.EmailSuppression, int64, error)
+	ClearEmailSuppression(id, adminID uint) (*models.EmailSuppression, error)
+
+	// Email outbox
+	ProcessEmailOutbox() (int, error)
+	GetEmailOutboxStatus() (*models.EmailOutboxStatus, error)
+
+	// Channel fallback chains
+	GetFallbackPolicies() ([]*models.NotificationFallbackPolicy, error)
+	SetFallbackPolicy(priority models.NotificationPriority, req *models.FallbackPolicyRequest, adminID uint) (*models.NotificationFallbackPolicy, error)
+	DeleteFallbackPolicy(priority models.NotificationPriority) error
+	ClaimDueFallbacks(now time.Time, limit int) ([]*models.NotificationFallback, error)
+	DeliverFallbackStep(notificationID uint, channel models.DeliveryChannel) error
+	UpdateFallback(fallback *models.NotificationFallback) error
+
+	// Template subject experiments
+	GetTemplateExperiments() ([]*models.TemplateExperiment, error)
+	SetTemplateExperiment(templateName string, req *models.TemplateExperimentRequest, adminID uint) (*models.TemplateExperiment, error)
+	DeleteTemplateExperiment(templateName string) error
+	GetTemplateExperimentReport(templateName string) (*models.TemplateExperimentReport, error)
+	TrackNotificationEngagement(userID, notificationID uint, req *models.TrackEngagementRequest) (bool, error)
+
+	// Announcement campaigns
+	CreateCampaign(req *models.CreateCampaignRequest, adminID uint) (*models.AnnouncementCampaign, []*models.CampaignConflict, error)
+	GetCampaign(id uint) (*models.AnnouncementCampaign, error)
+	ListCampaigns(filter *models.CampaignFilterRequest) ([]*models.AnnouncementCampaign, int64, error)
+	GetCampaignCalendar(req *models.CampaignCalendarRequest, now time.Time) (*models.CampaignCalendar, error)
+	CancelCampaign(id uint) (*models.AnnouncementCampaign, error)
+	CancelCampaignSend(campaignID, sendID uint) (*models.AnnouncementCampaign, error)
+	ProcessCampaignSends(now time.Time) (int, error)
+
+	// Daily digests
+	SendDailyDigests(now time.Time, hour int) (int, error)
 
 	// Admin operations
 	DeleteOldNotifications(beforeDate time.Time) (int64, error)
 	GetSystemStats() (*repository.SystemNotificationStats, error)
+	GetDeliveryFailureStats(period time.Duration, topTypes int) (*models.DeliveryFailureStats, error)
 	ProcessScheduledNotifications() error
 	RetryFailedDeliveries() error
+	GetDeliveryHoldStatus() (*models.DeliveryHoldStatus, error)
+	ReleaseHeldDeliveries(req *models.ReleaseHeldDeliveriesRequest) (*models.ReleaseHeldDeliveriesResponse, error)
+	DrainHeldDeliveries(req *models.DrainHeldDeliveriesRequest) (int64, error)
+	QueryNotifications(req *models.AdminNotificationQueryRequest) (*NotificationListResponse, error)
+	InspectUserNotifications(userID uint, req *models.AdminNotificationQueryRequest) (*NotificationListResponse, error)
+	FindResendCandidates(req *models.ResendNotificationsRequest) ([]uint, int64, error)
+	ResendNotification(notificationID uint) error
+	ReconcileUnreadCounts() (int, error)
+	SendTestNotification(req *TestNotificationRequest) (*TestNotificationResult, error)
+	MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error)
 }
 
 // notificationUsecase implements NotificationUsecase interface
 type notificationUsecase struct {
 	notificationRepo repository.NotificationRepository
 	emailSender      email.EmailSender
+	pushSender       push.Sender          // nil fails push deliveries
+	dedupWindow      time.Duration        // 0 disables deduplication
+	unread           *redis.UnreadCounter // nil disables unread count caching
+	events           *redis.UserEvents    // nil disables real-time events
+	orgSettings      *orgsettings.Client  // nil uses default organization settings
+	killSwitches     *switches.Store      // nil never disables features
+	smtp             *smtpHealth          // SMTP server state seen by sends and the email outbox
+	digestStats      DigestStatsSource    // nil disables daily digests
 }
 
 // Custom request/response models for usecase layer
@@ -72,6 +179,7 @@ type TemplatedNotificationRequest struct {
 	ScheduledAt  *time.Time                   `json:"scheduled_at,omitempty"`
 	ExpiresAt    *time.Time                   `json:"expires_at,omitempty"`
 	Channels     []models.DeliveryChannel     `json:"channels,omitempty"`
+	Locale       i18n.Locale                  `json:"locale,omitempty"` // Язык получателя, по умолчанию язык организации
 }
 
 // SystemAnnouncementRequest represents a system announcement request
@@ -85,6 +193,24 @@ type SystemAnnouncementRequest struct {
 	ReadMoreURL    string                      `json:"read_more_url,omitempty"`
 	ExpiresAt      *time.Time                  `json:"expires_at,omitempty"`
 	Channels       []models.DeliveryChannel    `json:"channels,omitempty"`
+
+	// Localization: Title and Content are used for locales without a translation
+	Translations map[i18n.Locale]AnnouncementTranslation `json:"translations,omitempty"`
+	UserLocales  map[uint]string                         `json:"user_locales,omitempty"` // Язык получателей из профиля, по умолчанию язык организации
+}
+
+// AnnouncementTranslation represents announcement content in one locale
+type AnnouncementTranslation struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// localized returns announcement title and content for locale
+func (r *SystemAnnouncementRequest) localized(locale i18n.Locale) (string, string) {
+	if translation, exists := r.Translations[locale]; exists && strings.TrimSpace(translation.Title) != "" {
+		return translation.Title, translation.Content
+	}
+	return r.Title, r.Content
 }
 
 // NotificationListResponse represents a paginated list of notifications
@@ -94,16 +220,52 @@ type NotificationListResponse struct {
 	Limit         int                            `json:"limit"`
 	Offset        int                            `json:"offset"`
 	HasMore       bool                           `json:"has_more"`
+	NextCursor    string                         `json:"next_cursor,omitempty"`
+}
+
+// newNotificationListResponse builds a notification page; a cursor page has more
+// results when it is full, an offset page when it ends before the total
+func newNotificationListResponse(responses []*models.NotificationResponse, total int64, page *query.Params) *NotificationListResponse {
+	nextCursor := page.NextCursor(responses)
+
+	hasMore := int64(page.Offset+len(responses)) < total
+	if page.HasCursor() {
+		hasMore = nextCursor != ""
+	}
+
+	return &NotificationListResponse{
+		Notifications: responses,
+		Total:         total,
+		Limit:         page.Limit,
+		Offset:        page.Offset,
+		HasMore:       hasMore,
+		NextCursor:    nextCursor,
+	}
 }
 
 // NewNotificationUsecase creates a new notification usecase
 func NewNotificationUsecase(
 	notificationRepo repository.NotificationRepository,
 	emailSender email.EmailSender,
+	pushSender push.Sender,
+	dedupWindow time.Duration,
+	unread *redis.UnreadCounter,
+	events *redis.UserEvents,
+	orgSettings *orgsettings.Client,
+	killSwitches *switches.Store,
+	digestStats DigestStatsSource,
 ) NotificationUsecase {
 	return &notificationUsecase{
 		notificationRepo: notificationRepo,
 		emailSender:      emailSender,
+		pushSender:       pushSender,
+		dedupWindow:      dedupWindow,
+		unread:           unread,
+		events:           events,
+		orgSettings:      orgSettings,
+		killSwitches:     killSwitches,
+		smtp:             &smtpHealth{healthy: true},
+		digestStats:      digestStats,
 	}
 }
 
@@ -130,6 +292,18 @@ func (u *notificationUsecase) SendNotification(req *models.CreateNotificationReq
 		return nil, nil
 	}
 
+	// Collapse rapid repeated triggers into the existing notification
+	dedupKey := buildDedupKey(req)
+	if duplicate, err := u.findDuplicate(req, dedupKey); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": req.UserID,
+			"type":    req.Type,
+			"error":   err.Error(),
+		}).Warn("Failed to check notification duplicates")
+	} else if duplicate != nil {
+		return duplicate, nil
+	}
+
 	// Create notification
 	notification := &models.Notification{
 		UserID:      req.UserID,
@@ -144,6 +318,8 @@ func (u *notificationUsecase) SendNotification(req *models.CreateNotificationReq
 		ImageURL:    req.ImageURL,
 		ScheduledAt: req.ScheduledAt,
 		ExpiresAt:   req.ExpiresAt,
+		Locale:      req.Locale,
+		DedupKey:    dedupKey,
 	}
 
 	// Set priority if provided
@@ -155,6 +331,14 @@ func (u *notificationUsecase) SendNotification(req *models.CreateNotificationReq
 	if err := u.notificationRepo.CreateNotification(notification); err != nil {
 		return nil, fmt.Errorf("failed to create notification: %w", err)
 	}
+	u.adjustUnreadCount(notification.UserID, 1)
+	u.publishEvent(notification.UserID, &models.NotificationEvent{
+		Type:         models.NotificationEventCreated,
+		Notification: notification.ToResponse(),
+	})
+
+	// Channels of the priority's fallback chain are tried one by one while the notification stays unread
+	channels = u.startFallback(notification, channels)
 
 	// Send through channels
 	if err := u.sendThroughChannels(notification, channels); err != nil {
@@ -186,6 +370,55 @@ func (u *notificationUsecase) SendNotification(req *models.CreateNotificationReq
 	return notification.ToResponse(), nil
 }
 
+// findDuplicate looks for a recent unread notification with the same dedup key and,
+// if found, updates its counter instead of creating a new one
+func (u *notificationUsecase) findDuplicate(req *models.CreateNotificationRequest, dedupKey string) (*models.NotificationResponse, error) {
+	// Scheduled notifications are explicit, never collapse them
+	if u.dedupWindow <= 0 || req.ScheduledAt != nil {
+		return nil, nil
+	}
+
+	existing, err := u.notificationRepo.FindDuplicateNotification(req.UserID, dedupKey, time.Now().Add(-u.dedupWindow))
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	message := strings.TrimSpace(req.Message)
+	if err := u.notificationRepo.IncrementDuplicateCount(existing.ID, message); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	existing.DuplicateCount++
+	existing.LastDuplicateAt = &now
+	existing.UpdatedAt = now
+	if message != "" {
+		existing.Message = message
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"notification_id": existing.ID,
+		"user_id":         req.UserID,
+		"type":            req.Type,
+		"duplicate_count": existing.DuplicateCount,
+		"dedup_window":    u.dedupWindow.String(),
+	}).Info("Duplicate notification suppressed")
+
+	return existing.ToResponse(), nil
+}
+
+// buildDedupKey builds deduplication key from type, related object and title
+func buildDedupKey(req *models.CreateNotificationRequest) string {
+	var relatedID uint
+	if req.RelatedID != nil {
+		relatedID = *req.RelatedID
+	}
+
+	raw := fmt.Sprintf("%s|%s|%d|%s", req.Type, req.RelatedType, relatedID, strings.TrimSpace(req.Title))
+	hash := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(hash[:])
+}
+
 // SendBulkNotification sends notifications to multiple users
 func (u *notificationUsecase) SendBulkNotification(req *models.BulkCreateNotificationRequest) error {
 	// Validate request
@@ -193,9 +426,57 @@ func (u *notificationUsecase) SendBulkNotification(req *models.BulkCreateNotific
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Create notifications for each user
-	notifications := make([]*models.Notification, 0, len(req.UserIDs))
-	for _, userID := range req.UserIDs {
+	channels := req.Channels
+	if len(channels) == 0 {
+		channels = []models.DeliveryChannel{models.DeliveryChannelInApp}
+	}
+
+	// Process recipients in chunks to keep memory bounded for large audiences
+	created, successCount := 0, 0
+	for start := 0; start < len(req.UserIDs); start += bulkNotificationChunkSize {
+		end := min(start+bulkNotificationChunkSize, len(req.UserIDs))
+
+		notifications := u.buildBulkNotifications(req, req.UserIDs[start:end])
+		if len(notifications) > 0 {
+			if err := u.notificationRepo.CreateBulkNotifications(notifications); err != nil {
+				return fmt.Errorf("failed to create bulk notifications (%d of %d recipients processed): %w", start, len(req.UserIDs), err)
+			}
+			created += len(notifications)
+			for _, notification := range notifications {
+				u.adjustUnreadCount(notification.UserID, 1)
+				u.publishEvent(notification.UserID, &models.NotificationEvent{
+					Type:         models.NotificationEventCreated,
+					Notification: notification.ToResponse(),
+				})
+			}
+			successCount += u.deliverBulkNotifications(notifications, channels)
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"processed": end,
+			"total":     len(req.UserIDs),
+			"created":   created,
+		}).Debug("Bulk notification progress")
+	}
+
+	if created == 0 {
+		return fmt.Errorf("no notifications to send after filtering")
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"recipients":          len(req.UserIDs),
+		"total_notifications": created,
+		"successful":          successCount,
+		"failed":              created - successCount,
+	}).Info("Bulk notification sending completed")
+
+	return nil
+}
+
+// buildBulkNotifications creates notifications for users allowed by their preferences
+func (u *notificationUsecase) buildBulkNotifications(req *models.BulkCreateNotificationRequest, userIDs []uint) []*models.Notification {
+	notifications := make([]*models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
 		// Check user preferences
 		shouldSend, _, err := u.checkUserPreferences(userID, req.Type, req.Channels)
 		if err != nil {
@@ -223,8 +504,12 @@ func (u *notificationUsecase) SendBulkNotification(req *models.BulkCreateNotific
 			ImageURL:    req.ImageURL,
 			ScheduledAt: req.ScheduledAt,
 			ExpiresAt:   req.ExpiresAt,
+			Locale:      req.Locale,
 		}
 
+		if locale, exists := req.UserLocales[userID]; exists {
+			notification.Locale = locale
+		}
 		if req.Priority != nil {
 			notification.Priority = *req.Priority
 		}
@@ -232,23 +517,14 @@ func (u *notificationUsecase) SendBulkNotification(req *models.BulkCreateNotific
 		notifications = append(notifications, notification)
 	}
 
-	if len(notifications) == 0 {
-		return fmt.Errorf("no notifications to send after filtering")
-	}
-
-	// Bulk create notifications
-	if err := u.notificationRepo.CreateBulkNotifications(notifications); err != nil {
-		return fmt.Errorf("failed to create bulk notifications: %w", err)
-	}
+	return notifications
+}
 
+// deliverBulkNotifications sends created notifications and returns the number of successful deliveries
+func (u *notificationUsecase) deliverBulkNotifications(notifications []*models.Notification, channels []models.DeliveryChannel) int {
 	// Send through channels (async processing could be implemented here)
 	successCount := 0
 	for _, notification := range notifications {
-		channels := req.Channels
-		if len(channels) == 0 {
-			channels = []models.DeliveryChannel{models.DeliveryChannelInApp}
-		}
-
 		if err := u.sendThroughChannels(notification, channels); err != nil {
 			logger.WithFields(map[string]interface{}{
 				"notification_id": notification.ID,
@@ -265,13 +541,7 @@ func (u *notificationUsecase) SendBulkNotification(req *models.BulkCreateNotific
 		u.notificationRepo.UpdateNotification(notification)
 	}
 
-	logger.WithFields(map[string]interface{}{
-		"total_notifications": len(notifications),
-		"successful":          successCount,
-		"failed":              len(notifications) - successCount,
-	}).Info("Bulk notification sending completed")
-
-	return nil
+	return successCount
 }
 
 // SendTemplatedNotification sends a notification using a template
@@ -291,14 +561,23 @@ func (u *notificationUsecase) SendTemplatedNotification(req *TemplatedNotificati
 		return nil, nil
 	}
 
+	locale := u.recipientLocale(req.Locale)
+
+	// A running subject experiment replaces the template subject with the variant of the user
+	experiment, variant := u.assignTemplateVariant(req.TemplateName, req.UserID)
+
 	// For templated emails, we'll send directly through email sender
 	// and create a simple in-app notification
 	if u.shouldSendEmail(channels) {
 		emailReq := &email.TemplatedEmailRequest{
 			To:           []string{}, // This would need user email lookup
 			TemplateName: req.TemplateName,
-			Variables:    req.Variables,
+			Variables:    u.withBranding(req.Variables),
 			Priority:     u.convertPriorityForEmail(req.Priority),
+			Locale:       locale,
+		}
+		if variant != nil {
+			emailReq.Subject = variant.Subject
 		}
 
 		// TODO: Get user email from user service
@@ -307,12 +586,15 @@ func (u *notificationUsecase) SendTemplatedNotification(req *TemplatedNotificati
 	}
 
 	// Create in-app notification with rendered title
-	title, err := u.renderTemplateString(req.TemplateName+"_title", req.Variables)
+	title, err := u.renderTemplateString(locale, req.TemplateName+"_title", req.Variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render notification title: %w", err)
 	}
+	if variant != nil {
+		title = renderVariantSubject(variant.Subject, req.Variables)
+	}
 
-	message, err := u.renderTemplateString(req.TemplateName+"_message", req.Variables)
+	message, err := u.renderTemplateString(locale, req.TemplateName+"_message", req.Variables)
 	if err != nil {
 		// If message template fails, use empty message
 		message = ""
@@ -331,12 +613,19 @@ func (u *notificationUsecase) SendTemplatedNotification(req *TemplatedNotificati
 		ScheduledAt: req.ScheduledAt,
 		ExpiresAt:   req.ExpiresAt,
 		Channels:    []models.DeliveryChannel{models.DeliveryChannelInApp},
+		Locale:      req.Locale,
 	}
 
-	return u.SendNotification(createReq)
+	response, err := u.SendNotification(createReq)
+	if err != nil || response == nil || variant == nil {
+		return response, err
+	}
+
+	u.recordTemplateSent(experiment, variant, response)
+	return response, nil
 }
 
-// SendSystemAnnouncement sends a system-wide announcement
+// SendSystemAnnouncement sends a system-wide announcement in the locale of each recipient
 func (u *notificationUsecase) SendSystemAnnouncement(req *SystemAnnouncementRequest) error {
 	// Validate request
 	if err := u.validateSystemAnnouncementRequest(req); err != nil {
@@ -351,24 +640,52 @@ func (u *notificationUsecase) SendSystemAnnouncement(req *SystemAnnouncementRequ
 		return fmt.Errorf("sending to all users not implemented yet")
 	}
 
-	// Send bulk notification
-	bulkReq := &models.BulkCreateNotificationRequest{
-		UserIDs:   userIDs,
-		Type:      models.NotificationTypeAnnounce,
-		Title:     req.Title,
-		Message:   req.Content,
-		Priority:  &req.Priority,
-		ExpiresAt: req.ExpiresAt,
-		Channels:  req.Channels,
+	// Group recipients by locale so that each group gets its translation
+	groups := make(map[i18n.Locale][]uint)
+	for _, userID := range userIDs {
+		locale, ok := i18n.Detect(req.UserLocales[userID])
+		if !ok {
+			locale = u.defaultLocale()
+		}
+		groups[locale] = append(groups[locale], userID)
+	}
+
+	for _, locale := range i18n.Supported() {
+		if len(groups[locale]) == 0 {
+			continue
+		}
+
+		title, content := req.localized(locale)
+		bulkReq := &models.BulkCreateNotificationRequest{
+			UserIDs:   groups[locale],
+			Type:      models.NotificationTypeAnnounce,
+			Title:     title,
+			Message:   content,
+			Priority:  &req.Priority,
+			ExpiresAt: req.ExpiresAt,
+			Channels:  req.Channels,
+			Locale:    locale,
+		}
+
+		if err := u.SendBulkNotification(bulkReq); err != nil {
+			return fmt.Errorf("failed to send announcement to %s recipients: %w", locale, err)
+		}
 	}
 
-	return u.SendBulkNotification(bulkReq)
+	return nil
 }
 
 // Get notifications
 
 // GetUserNotifications retrieves notifications for a user with filtering and pagination
 func (u *notificationUsecase) GetUserNotifications(userID uint, filter *models.NotificationFilterRequest) (*NotificationListResponse, error) {
+	if filter == nil {
+		filter = &models.NotificationFilterRequest{}
+	}
+	if filter.Page == nil {
+		filter.Page = query.Default(models.NotificationListOptions)
+	}
+
 	notifications, total, err := u.notificationRepo.GetUserNotifications(userID, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user notifications: %w", err)
@@ -380,27 +697,7 @@ func (u *notificationUsecase) GetUserNotifications(userID uint, filter *models.N
 		responses[i] = notification.ToResponse()
 	}
 
-	// Calculate pagination info
-	limit := 20 // default
-	offset := 0
-	if filter != nil {
-		if filter.Limit > 0 {
-			limit = filter.Limit
-		}
-		if filter.Offset > 0 {
-			offset = filter.Offset
-		}
-	}
-
-	hasMore := int64(offset+len(responses)) < total
-
-	return &NotificationListResponse{
-		Notifications: responses,
-		Total:         total,
-		Limit:         limit,
-		Offset:        offset,
-		HasMore:       hasMore,
-	}, nil
+	return newNotificationListResponse(responses, total, filter.Page), nil
 }
 
 // GetNotificationByID retrieves a single notification by ID
@@ -423,10 +720,27 @@ func (u *notificationUsecase) GetNotificationByID(userID, notificationID uint) (
 
 // GetUnreadCount returns the count of unread notifications for a user
 func (u *notificationUsecase) GetUnreadCount(userID uint) (int64, error) {
+	if count, ok, err := u.unread.GetNotifications(userID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Warn("Failed to get cached unread count")
+	} else if ok {
+		return count, nil
+	}
+
 	count, err := u.notificationRepo.GetUnreadCount(userID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get unread count: %w", err)
 	}
+
+	if err := u.unread.SetNotifications(userID, count); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Warn("Failed to cache unread count")
+	}
+
 	return count, nil
 }
 
@@ -447,9 +761,18 @@ func (u *notificationUsecase) MarkAsRead(userID uint, req *models.MarkAsReadRequ
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	if err := u.notificationRepo.MarkMultipleAsRead(req.NotificationIDs, userID); err != nil {
+	count, err := u.notificationRepo.MarkMultipleAsRead(req.NotificationIDs, userID)
+	if err != nil {
 		return fmt.Errorf("failed to mark notifications as read: %w", err)
 	}
+	u.adjustUnreadCount(userID, -count)
+	u.cancelReadFallbacks(userID)
+	if count > 0 {
+		u.publishEvent(userID, &models.NotificationEvent{
+			Type:            models.NotificationEventRead,
+			NotificationIDs: req.NotificationIDs,
+		})
+	}
 
 	logger.WithFields(map[string]interface{}{
 		"user_id":            userID,
@@ -465,15 +788,27 @@ func (u *notificationUsecase) MarkAllAsRead(userID uint) error {
 		return fmt.Errorf("failed to mark all notifications as read: %w", err)
 	}
 
+	if err := u.unread.SetNotifications(userID, 0); err != nil {
+		u.invalidateUnreadCount(userID)
+	}
+	u.cancelReadFallbacks(userID)
+	u.publishEvent(userID, &models.NotificationEvent{Type: models.NotificationEventUnreadCount})
+
 	logger.WithField("user_id", userID).Info("All notifications marked as read")
 	return nil
 }
 
 // MarkAllAsReadByType marks all notifications of a specific type as read
 func (u *notificationUsecase) MarkAllAsReadByType(userID uint, notificationType models.NotificationType) error {
-	if err := u.notificationRepo.MarkAllAsReadByType(userID, notificationType); err != nil {
+	count, err := u.notificationRepo.MarkAllAsReadByType(userID, notificationType)
+	if err != nil {
 		return fmt.Errorf("failed to mark notifications as read by type: %w", err)
 	}
+	u.adjustUnreadCount(userID, -count)
+	u.cancelReadFallbacks(userID)
+	if count > 0 {
+		u.publishEvent(userID, &models.NotificationEvent{Type: models.NotificationEventUnreadCount})
+	}
 
 	logger.WithFields(map[string]interface{}{
 		"user_id": userID,
@@ -483,11 +818,149 @@ func (u *notificationUsecase) MarkAllAsReadByType(userID uint, notificationType
 	return nil
 }
 
+// MarkAsReadByFilter marks all user's notifications matching the filter as read
+func (u *notificationUsecase) MarkAsReadByFilter(userID uint, req *models.MarkAsReadByFilterRequest) (int64, error) {
+	if err := u.validateMarkAsReadByFilterRequest(req); err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+
+	count, err := u.notificationRepo.MarkAsReadByFilter(userID, req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read by filter: %w", err)
+	}
+	u.adjustUnreadCount(userID, -count)
+	u.cancelReadFallbacks(userID)
+	if count > 0 {
+		u.publishEvent(userID, &models.NotificationEvent{Type: models.NotificationEventUnreadCount})
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"user_id":      userID,
+		"type":         req.Type,
+		"related_type": req.RelatedType,
+		"related_id":   req.RelatedID,
+		"marked_count": count,
+	}).Info("Notifications marked as read by filter")
+
+	return count, nil
+}
+
+// ResolveRelatedNotifications marks notifications about an object as read once the object was acted upon
+func (u *notificationUsecase) ResolveRelatedNotifications(req *models.ResolveNotificationsRequest) (int64, error) {
+	if req == nil {
+		return 0, fmt.Errorf("validation failed: request is required")
+	}
+	if strings.TrimSpace(req.RelatedType) == "" {
+		return 0, fmt.Errorf("validation failed: related type is required")
+	}
+	if req.RelatedID == 0 {
+		return 0, fmt.Errorf("validation failed: related ID is required")
+	}
+
+	count, err := u.notificationRepo.MarkAsReadByRelatedObject(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve related notifications: %w", err)
+	}
+	// Without explicit users affected counters are fixed by reconciliation
+	u.invalidateUnreadCount(req.UserIDs...)
+	u.cancelReadFallbacks(req.UserIDs...)
+	if count > 0 {
+		for _, userID := range req.UserIDs {
+			u.publishEvent(userID, &models.NotificationEvent{Type: models.NotificationEventUnreadCount})
+		}
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"related_type":   req.RelatedType,
+		"related_id":     req.RelatedID,
+		"user_count":     len(req.UserIDs),
+		"resolved_count": count,
+	}).Info("Related notifications resolved")
+
+	return count, nil
+}
+
+// Unread count cache
+
+// ReconcileUnreadCounts overwrites cached unread counters with values from the database.
+// Counters drift when notifications are removed by cleanup or resolved for unknown users.
+func (u *notificationUsecase) ReconcileUnreadCounts() (int, error) {
+	userIDs, err := u.unread.CachedNotificationUsers()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cached unread counters: %w", err)
+	}
+
+	reconciled := 0
+	for start := 0; start < len(userIDs); start += unreadReconcileBatchSize {
+		end := min(start+unreadReconcileBatchSize, len(userIDs))
+
+		counts, err := u.notificationRepo.GetUnreadCountsByUsers(userIDs[start:end])
+		if err != nil {
+			return reconciled, fmt.Errorf("failed to reconcile unread counts: %w", err)
+		}
+
+		for userID, count := range counts {
+			if err := u.unread.ReconcileNotifications(userID, count); err != nil {
+				return reconciled, fmt.Errorf("failed to reconcile unread counts: %w", err)
+			}
+			reconciled++
+		}
+	}
+
+	return reconciled, nil
+}
+
+// adjustUnreadCount changes cached unread counter, the counter is dropped if Redis fails
+func (u *notificationUsecase) adjustUnreadCount(userID uint, delta int64) {
+	if err := u.unread.IncrNotifications(userID, delta); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"delta":   delta,
+			"error":   err.Error(),
+		}).Warn("Failed to update cached unread count")
+		u.invalidateUnreadCount(userID)
+	}
+}
+
+// invalidateUnreadCount drops cached unread counters, they are reloaded on next read
+func (u *notificationUsecase) invalidateUnreadCount(userIDs ...uint) {
+	if err := u.unread.InvalidateNotifications(userIDs...); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_ids": userIDs,
+			"error":    err.Error(),
+		}).Warn("Failed to invalidate cached unread count")
+	}
+}
+
+// MergeUsers moves notifications of a duplicate account to the primary account
+func (u *notificationUsecase) MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error) {
+	if req.PrimaryUserID == req.DuplicateUserID {
+		return nil, fmt.Errorf("validation failed: cannot merge user into itself")
+	}
+
+	result, err := u.notificationRepo.MergeUsers(req.PrimaryUserID, req.DuplicateUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+
+	// Unread counts of both accounts changed
+	u.invalidateUnreadCount(req.PrimaryUserID, req.DuplicateUserID)
+
+	return result, nil
+}
+
 // Search and filtering
 
 // SearchNotifications searches notifications for a user
-func (u *notificationUsecase) SearchNotifications(userID uint, query string, filter *models.NotificationFilterRequest) (*NotificationListResponse, error) {
-	notifications, total, err := u.notificationRepo.SearchNotifications(userID, query, filter)
+func (u *notificationUsecase) SearchNotifications(userID uint, searchQuery string, filter *models.NotificationFilterRequest) (*NotificationListResponse, error) {
+	if filter == nil {
+		filter = &models.NotificationFilterRequest{}
+	}
+	if filter.Page == nil {
+		filter.Page = query.Default(models.NotificationListOptions)
+	}
+
+	notifications, total, err := u.notificationRepo.SearchNotifications(userID, searchQuery, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search notifications: %w", err)
 	}
@@ -498,27 +971,7 @@ func (u *notificationUsecase) SearchNotifications(userID uint, query string, fil
 		responses[i] = notification.ToResponse()
 	}
 
-	// Calculate pagination info
-	limit := 20
-	offset := 0
-	if filter != nil {
-		if filter.Limit > 0 {
-			limit = filter.Limit
-		}
-		if filter.Offset > 0 {
-			offset = filter.Offset
-		}
-	}
-
-	hasMore := int64(offset+len(responses)) < total
-
-	return &NotificationListResponse{
-		Notifications: responses,
-		Total:         total,
-		Limit:         limit,
-		Offset:        offset,
-		HasMore:       hasMore,
-	}, nil
+	return newNotificationListResponse(responses, total, filter.Page), nil
 }
 
 // GetNotificationsByRelatedObject returns notifications related to a specific object
@@ -544,6 +997,9 @@ func (u *notificationUsecase) GetUserPreferences(userID uint) ([]*models.UserNot
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user preferences: %w", err)
 	}
+	for _, preference := range preferences {
+		preference.FillPresentation()
+	}
 	return preferences, nil
 }
 
@@ -553,16 +1009,10 @@ func (u *notificationUsecase) UpdateUserPreference(userID uint, req *models.User
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	preference := &models.UserNotificationPreference{
-		UserID:           userID,
-		NotificationType: req.NotificationType,
-		InAppEnabled:     true,                           // default
-		EmailEnabled:     true,                           // default
-		PushEnabled:      true,                           // default
-		SMSEnabled:       false,                          // default
-		MinPriority:      models.NotificationPriorityLow, // default
-		WeekendEnabled:   true,                           // default
-		DigestEnabled:    false,                          // default
+	// Fields left out of the request get the organization defaults
+	preference, _, err := u.defaultPreference(userID, req.NotificationType)
+	if err != nil {
+		return err
 	}
 
 	// Update fields if provided
@@ -587,6 +1037,15 @@ func (u *notificationUsecase) UpdateUserPreference(userID uint, req *models.User
 	if req.QuietHoursEnd != nil {
 		preference.QuietHoursEnd = req.QuietHoursEnd
 	}
+	if req.QuietStartMinute != nil {
+		preference.QuietHoursStartMinute = req.QuietStartMinute
+	}
+	if req.QuietEndMinute != nil {
+		preference.QuietHoursEndMinute = req.QuietEndMinute
+	}
+	if req.Timezone != nil && strings.TrimSpace(*req.Timezone) != "" {
+		preference.Timezone = strings.TrimSpace(*req.Timezone)
+	}
 	if req.WeekendEnabled != nil {
 		preference.WeekendEnabled = *req.WeekendEnabled
 	}
@@ -596,6 +1055,15 @@ func (u *notificationUsecase) UpdateUserPreference(userID uint, req *models.User
 	if req.DigestFrequency != nil {
 		preference.DigestFrequency = req.DigestFrequency
 	}
+	if req.Sound != nil {
+		preference.Sound = *req.Sound
+	}
+	if req.BannerStyle != nil {
+		preference.BannerStyle = *req.BannerStyle
+	}
+	if req.IncludeInBadge != nil {
+		preference.IncludeInBadge = req.IncludeInBadge
+	}
 
 	if err := u.notificationRepo.UpsertUserPreference(preference); err != nil {
 		return fmt.Errorf("failed to update user preference: %w", err)
@@ -618,18 +1086,12 @@ func (u *notificationUsecase) GetUserPreference(userID uint, notificationType mo
 
 	// Return default preferences if not found
 	if preference == nil {
-		preference = &models.UserNotificationPreference{
-			UserID:           userID,
-			NotificationType: notificationType,
-			InAppEnabled:     true,
-			EmailEnabled:     true,
-			PushEnabled:      true,
-			SMSEnabled:       false,
-			MinPriority:      models.NotificationPriorityLow,
-			WeekendEnabled:   true,
-			DigestEnabled:    false,
+		preference, _, err = u.defaultPreference(userID, notificationType)
+		if err != nil {
+			return nil, err
 		}
 	}
+	preference.FillPresentation()
 
 	return preference, nil
 }
@@ -660,6 +1122,32 @@ func (u *notificationUsecase) GetSystemStats() (*repository.SystemNotificationSt
 	return stats, nil
 }
 
+// GetDeliveryFailureStats returns delivery failure rates over the last period, by channel and
+// for the notification types that failed most
+func (u *notificationUsecase) GetDeliveryFailureStats(period time.Duration, topTypes int) (*models.DeliveryFailureStats, error) {
+	stats, err := u.notificationRepo.GetDeliveryFailureStats(time.Now().Add(-period), topTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery failure stats: %w", err)
+	}
+
+	stats.FailureRate = failureRate(stats.Failed, stats.Deliveries)
+	for _, rate := range stats.ByChannel {
+		rate.FailureRate = failureRate(rate.Failed, rate.Deliveries)
+	}
+	for _, rate := range stats.TopFailingTypes {
+		rate.FailureRate = failureRate(rate.Failed, rate.Deliveries)
+	}
+	return stats, nil
+}
+
+// failureRate returns the share of failed deliveries, zero without deliveries
+func failureRate(failed, deliveries int64) float64 {
+	if deliveries == 0 {
+		return 0
+	}
+	return float64(failed) / float64(deliveries)
+}
+
 // ProcessScheduledNotifications processes notifications that are scheduled to be sent
 func (u *notificationUsecase) ProcessScheduledNotifications() error {
 	now := time.Now()
@@ -700,16 +1188,147 @@ func (u *notificationUsecase) ProcessScheduledNotifications() error {
 	return nil
 }
 
+// QueryNotifications retrieves notification history of all users matching admin filters
+func (u *notificationUsecase) QueryNotifications(req *models.AdminNotificationQueryRequest) (*NotificationListResponse, error) {
+	if err := u.validateAdminNotificationFilter(&req.AdminNotificationFilter); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if req.Page == nil {
+		req.Page = query.Default(models.NotificationListOptions)
+	}
+
+	notifications, total, err := u.notificationRepo.QueryNotifications(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+
+	responses := make([]*models.NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = notification.ToResponse()
+	}
+
+	return newNotificationListResponse(responses, total, req.Page), nil
+}
+
+// InspectUserNotifications retrieves notifications received by a user for support staff, with
+// delivery attempts of every channel. User segment filters of the request are ignored.
+func (u *notificationUsecase) InspectUserNotifications(userID uint, req *models.AdminNotificationQueryRequest) (*NotificationListResponse, error) {
+	if userID == 0 {
+		return nil, fmt.Errorf("validation failed: user ID is required")
+	}
+
+	req.UserIDs = []uint{userID}
+	req.Locale = nil
+
+	return u.QueryNotifications(req)
+}
+
+// FindResendCandidates returns IDs of failed notifications matching admin filters (up to the
+// request limit) and the total number of matching notifications
+func (u *notificationUsecase) FindResendCandidates(req *models.ResendNotificationsRequest) ([]uint, int64, error) {
+	filter := req.AdminNotificationFilter
+	failed := models.NotificationStatusFailed
+	filter.Status = &failed
+
+	if err := u.validateAdminNotificationFilter(&filter); err != nil {
+		return nil, 0, fmt.Errorf("validation failed: %w", err)
+	}
+
+	matched, err := u.notificationRepo.CountNotificationsByFilter(&filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count failed notifications: %w", err)
+	}
+
+	if req.DryRun || matched == 0 {
+		return nil, matched, nil
+	}
+
+	limit := 1000 // default
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	ids, err := u.notificationRepo.GetNotificationIDsByFilter(&filter, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get failed notifications: %w", err)
+	}
+
+	return ids, matched, nil
+}
+
+// ResendNotification sends a failed notification again through the channels that failed.
+// Notifications that are no longer failed are skipped.
+func (u *notificationUsecase) ResendNotification(notificationID uint) error {
+	notification, err := u.notificationRepo.GetNotificationByID(notificationID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	if notification.Status != models.NotificationStatusFailed {
+		logger.WithFields(map[string]interface{}{
+			"notification_id": notification.ID,
+			"status":          notification.Status,
+		}).Info("Skipping resend of notification that is no longer failed")
+		return nil
+	}
+
+	var channels []models.DeliveryChannel
+	seen := make(map[models.DeliveryChannel]bool)
+	for _, delivery := range notification.DeliveryChannels {
+		if delivery.Status == models.NotificationStatusFailed && !seen[delivery.Channel] {
+			seen[delivery.Channel] = true
+			channels = append(channels, delivery.Channel)
+		}
+	}
+	if len(channels) == 0 {
+		channels = []models.DeliveryChannel{models.DeliveryChannelInApp} // default
+	}
+
+	sendErr := u.sendThroughChannels(notification, channels)
+	if sendErr != nil {
+		notification.Status = models.NotificationStatusFailed
+	} else {
+		notification.Status = models.NotificationStatusDelivered
+	}
+
+	if err := u.notificationRepo.UpdateNotification(notification); err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("failed to resend notification: %w", sendErr)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"notification_id": notification.ID,
+		"user_id":         notification.UserID,
+		"channels":        channels,
+	}).Info("Notification resent")
+
+	return nil
+}
+
 // RetryFailedDeliveries retries failed notification deliveries
 func (u *notificationUsecase) RetryFailedDeliveries() error {
-	maxAttempts := 3
-	deliveries, err := u.notificationRepo.GetFailedDeliveries(maxAttempts, 50)
+	deliveries, err := u.notificationRepo.GetFailedDeliveries(models.MaxDeliveryAttempts, 50)
 	if err != nil {
 		return fmt.Errorf("failed to get failed deliveries: %w", err)
 	}
 
+	emailDisabled := u.killSwitches.IsActive(switches.DisableEmailSending)
+	outboundHeld := u.killSwitches.IsActive(switches.HoldOutboundNotifications)
+
 	retriedCount := 0
 	for _, delivery := range deliveries {
+		// Keep email attempts for when sending is turned back on
+		if emailDisabled && delivery.Channel == models.DeliveryChannelEmail {
+			continue
+		}
+		// Providers are down while outbound deliveries are held, retrying would only burn attempts
+		if outboundHeld && delivery.Channel != models.DeliveryChannelInApp {
+			continue
+		}
+
 		// Get the notification for this delivery
 		notification, err := u.notificationRepo.GetNotificationByID(delivery.NotificationID)
 		if err != nil {
@@ -747,6 +1366,108 @@ func (u *notificationUsecase) RetryFailedDeliveries() error {
 	return nil
 }
 
+// heldDeliveryDrainedError is the delivery error of held deliveries dropped by an administrator
+const heldDeliveryDrainedError = "Held delivery discarded by an administrator"
+
+// GetDeliveryHoldStatus returns whether outbound deliveries are held and how many are waiting
+func (u *notificationUsecase) GetDeliveryHoldStatus() (*models.DeliveryHoldStatus, error) {
+	held, err := u.notificationRepo.CountHeldDeliveries()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.DeliveryHoldStatus{Held: held}
+	for _, count := range held {
+		status.TotalHeld += count
+	}
+
+	for _, killSwitch := range u.killSwitches.Active("notification-service") {
+		if killSwitch.Name == switches.HoldOutboundNotifications {
+			activatedAt := killSwitch.ActivatedAt
+			status.Enabled = true
+			status.Reason = killSwitch.Reason
+			status.ActivatedAt = &activatedAt
+			status.ActivatedBy = killSwitch.ActivatedBy
+		}
+	}
+
+	return status, nil
+}
+
+// ReleaseHeldDeliveries sends held deliveries oldest first. It works while the hold is still on,
+// so administrators can release the queue in batches as a recovering provider allows.
+func (u *notificationUsecase) ReleaseHeldDeliveries(req *models.ReleaseHeldDeliveriesRequest) (*models.ReleaseHeldDeliveriesResponse, error) {
+	if req.Channel != nil && *req.Channel == models.DeliveryChannelInApp {
+		return nil, fmt.Errorf("validation failed: in-app deliveries are never held")
+	}
+
+	limit := 500 // default
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	deliveries, err := u.notificationRepo.GetHeldDeliveries(req.Channel, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ReleaseHeldDeliveriesResponse{}
+	for _, delivery := range deliveries {
+		notification, err := u.notificationRepo.GetNotificationByID(delivery.NotificationID)
+		if err != nil {
+			u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+			response.Failed++
+			continue
+		}
+
+		if err := u.deliver(notification, delivery); err != nil {
+			response.Failed++
+			continue
+		}
+		response.Released++
+	}
+
+	held, err := u.notificationRepo.CountHeldDeliveries()
+	if err != nil {
+		return nil, err
+	}
+	for channel, count := range held {
+		if req.Channel == nil || *req.Channel == channel {
+			response.Remaining += count
+		}
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"channel":   req.Channel,
+		"released":  response.Released,
+		"failed":    response.Failed,
+		"remaining": response.Remaining,
+	}).Info("Held deliveries released")
+
+	return response, nil
+}
+
+// DrainHeldDeliveries drops held deliveries without sending them, they are marked as failed
+// and not retried
+func (u *notificationUsecase) DrainHeldDeliveries(req *models.DrainHeldDeliveriesRequest) (int64, error) {
+	if req.Channel != nil && *req.Channel == models.DeliveryChannelInApp {
+		return 0, fmt.Errorf("validation failed: in-app deliveries are never held")
+	}
+
+	drained, err := u.notificationRepo.DiscardHeldDeliveries(req.Channel, req.Before, heldDeliveryDrainedError)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"channel": req.Channel,
+		"before":  req.Before,
+		"drained": drained,
+	}).Warn("Held deliveries drained")
+
+	return drained, nil
+}
+
 // Helper methods
 
 // checkUserPreferences checks if notification should be sent based on user preferences
@@ -756,6 +1477,14 @@ func (u *notificationUsecase) checkUserPreferences(userID uint, notificationType
 		return false, nil, err
 	}
 
+	// Security notifications ignore quiet hours and always go in-app
+	if notificationType == models.NotificationTypeSecurity {
+		preference.InAppEnabled = true
+		preference.QuietHoursStart = nil
+		preference.QuietHoursEnd = nil
+		preference.WeekendEnabled = true
+	}
+
 	// Check if notification type is enabled
 	if !preference.InAppEnabled && !preference.EmailEnabled && !preference.PushEnabled && !preference.SMSEnabled {
 		return false, nil, nil
@@ -767,7 +1496,7 @@ func (u *notificationUsecase) checkUserPreferences(userID uint, notificationType
 	}
 
 	// Check weekend preferences
-	if !preference.WeekendEnabled && u.isWeekend() {
+	if !preference.WeekendEnabled && u.isWeekend(preference) {
 		return false, nil, nil
 	}
 
@@ -810,31 +1539,65 @@ func (u *notificationUsecase) checkUserPreferences(userID uint, notificationType
 	return true, finalChannels, nil
 }
 
-// isInQuietHours checks if current time is within user's quiet hours
+// isInQuietHours checks if current time is within user's quiet hours.
+// Quiet hours are evaluated in the user's timezone with minute granularity.
 func (u *notificationUsecase) isInQuietHours(preference *models.UserNotificationPreference) bool {
+	return isInQuietHoursAt(preference, time.Now())
+}
+
+// isWeekend checks if current time is weekend in the user's timezone
+func (u *notificationUsecase) isWeekend(preference *models.UserNotificationPreference) bool {
+	weekday := time.Now().In(userLocation(preference)).Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// isInQuietHoursAt checks if the given moment falls into user's quiet hours
+func isInQuietHoursAt(preference *models.UserNotificationPreference, now time.Time) bool {
 	if preference.QuietHoursStart == nil || preference.QuietHoursEnd == nil {
 		return false
 	}
 
-	now := time.Now()
-	currentHour := now.Hour()
+	local := now.In(userLocation(preference))
+	current := local.Hour()*60 + local.Minute()
 
-	start := *preference.QuietHoursStart
-	end := *preference.QuietHoursEnd
+	start := *preference.QuietHoursStart * 60
+	if preference.QuietHoursStartMinute != nil {
+		start += *preference.QuietHoursStartMinute
+	}
+	end := *preference.QuietHoursEnd * 60
+	if preference.QuietHoursEndMinute != nil {
+		end += *preference.QuietHoursEndMinute
+	}
+
+	if start == end {
+		return false
+	}
 
 	// Handle quiet hours that span midnight
 	if start > end {
-		return currentHour >= start || currentHour < end
+		return current >= start || current < end
 	}
 
-	return currentHour >= start && currentHour < end
+	return current >= start && current < end
 }
 
-// isWeekend checks if current time is weekend
-func (u *notificationUsecase) isWeekend() bool {
-	now := time.Now()
-	weekday := now.Weekday()
-	return weekday == time.Saturday || weekday == time.Sunday
+// userLocation resolves user's timezone, falling back to UTC for unknown zones
+func userLocation(preference *models.UserNotificationPreference) *time.Location {
+	if preference == nil || preference.Timezone == "" {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(preference.Timezone)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id":  preference.UserID,
+			"timezone": preference.Timezone,
+			"error":    err.Error(),
+		}).Warn("Unknown user timezone, falling back to UTC")
+		return time.UTC
+	}
+
+	return location
 }
 
 // sendThroughChannels sends notification through multiple channels
@@ -854,7 +1617,8 @@ func (u *notificationUsecase) sendThroughChannels(notification *models.Notificat
 	return nil
 }
 
-// sendThroughChannel sends notification through a specific channel
+// sendThroughChannel sends notification through a specific channel. While outbound deliveries
+// are held, deliveries through external channels are stored as held until an administrator releases them.
 func (u *notificationUsecase) sendThroughChannel(notification *models.Notification, channel models.DeliveryChannel) error {
 	// Create delivery record
 	delivery := &models.NotificationDelivery{
@@ -864,11 +1628,24 @@ func (u *notificationUsecase) sendThroughChannel(notification *models.Notificati
 		AttemptCount:   0,
 	}
 
+	held := channel != models.DeliveryChannelInApp && u.killSwitches.IsActive(switches.HoldOutboundNotifications)
+	if held {
+		delivery.Status = models.NotificationStatusHeld
+	}
+
 	if err := u.notificationRepo.CreateDelivery(delivery); err != nil {
 		return fmt.Errorf("failed to create delivery record: %w", err)
 	}
 
-	switch channel {
+	if held {
+		return nil
+	}
+	return u.deliver(notification, delivery)
+}
+
+// deliver sends notification through the channel of a delivery record and updates its status
+func (u *notificationUsecase) deliver(notification *models.Notification, delivery *models.NotificationDelivery) error {
+	switch delivery.Channel {
 	case models.DeliveryChannelInApp:
 		// In-app notifications are stored in database, no additional action needed
 		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusDelivered, "")
@@ -877,8 +1654,7 @@ func (u *notificationUsecase) sendThroughChannel(notification *models.Notificati
 		return u.sendEmailNotification(notification, delivery)
 
 	case models.DeliveryChannelPush:
-		// TODO: Implement push notification sending
-		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, "Push notifications not implemented")
+		return u.sendPushNotification(notification, delivery)
 
 	case models.DeliveryChannelSMS:
 		// TODO: Implement SMS sending
@@ -897,36 +1673,67 @@ func (u *notificationUsecase) sendThroughChannel(notification *models.Notificati
 	}
 }
 
+// emailDisabledError is the delivery error while the email kill switch is active
+const emailDisabledError = "Email sending is disabled by an administrator"
+
 // sendEmailNotification sends notification via email
 func (u *notificationUsecase) sendEmailNotification(notification *models.Notification, delivery *models.NotificationDelivery) error {
 	if u.emailSender == nil {
 		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, "Email sender not configured")
 	}
+	if u.killSwitches.IsActive(switches.DisableEmailSending) {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, emailDisabledError)
+	}
 
 	// TODO: Get user email from user service
 	// For now, we'll simulate email sending
 	userEmail := "user@example.com" // This should be fetched from user service
 
-	emailReq := &email.SendEmailRequest{
-		To:       []string{userEmail},
-		Subject:  notification.Title,
-		HTMLBody: u.buildEmailHTML(notification),
-		TextBody: u.buildEmailText(notification),
-		Priority: u.convertPriorityForEmail(&notification.Priority),
+	// Addresses that bounced or complained are not retried, until an administrator clears them
+	suppression, err := u.notificationRepo.FindActiveEmailSuppression(notification.UserID, userEmail)
+	if err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+	if suppression != nil {
+		return u.notificationRepo.DiscardDelivery(delivery.ID, emailSuppressedError)
+	}
+
+	// While the SMTP server is down emails go straight to the outbox instead of waiting for retries
+	if healthy, lastError := u.smtp.status(); !healthy {
+		return u.queueEmail(delivery, lastError)
 	}
 
-	if err := u.emailSender.SendEmail(emailReq); err != nil {
+	if err := u.emailSender.SendEmail(u.buildNotificationEmail(notification, userEmail)); err != nil {
+		if email.IsTemporaryFailure(err) {
+			u.smtp.record(err)
+			return u.queueEmail(delivery, err.Error())
+		}
 		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
 	}
 
 	return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusDelivered, "")
 }
 
+// buildNotificationEmail builds the email of a notification
+func (u *notificationUsecase) buildNotificationEmail(notification *models.Notification, userEmail string) *email.SendEmailRequest {
+	return &email.SendEmailRequest{
+		To:       []string{userEmail},
+		Subject:  notification.Title,
+		HTMLBody: u.buildEmailHTML(notification),
+		TextBody: u.buildEmailText(notification),
+		Priority: u.convertPriorityForEmail(&notification.Priority),
+		Headers: map[string]string{
+			email.HeaderUserID:         fmt.Sprint(notification.UserID),
+			email.HeaderNotificationID: fmt.Sprint(notification.ID),
+		},
+	}
+}
+
 // buildEmailHTML builds HTML email content
 func (u *notificationUsecase) buildEmailHTML(notification *models.Notification) string {
 	html := fmt.Sprintf(`
 <!DOCTYPE html>
-<html>
+<html lang="%s">
 <head>
     <meta charset="UTF-8">
     <title>%s</title>
@@ -949,15 +1756,17 @@ func (u *notificationUsecase) buildEmailHTML(notification *models.Notification)
             %s
         </div>
         <div class="footer">
-            <p>Это автоматическое сообщение от Tachyon Messenger</p>
+            <p>%s</p>
         </div>
     </div>
 </body>
 </html>`,
+		u.recipientLocale(notification.Locale),
 		notification.Title,
 		notification.Title,
 		notification.Message,
 		u.buildActionButton(notification),
+		i18n.T(u.recipientLocale(notification.Locale), "email.automated_footer", nil)+u.brandingFooterHTML(),
 	)
 
 	return html
@@ -967,14 +1776,43 @@ func (u *notificationUsecase) buildEmailHTML(notification *models.Notification)
 func (u *notificationUsecase) buildEmailText(notification *models.Notification) string {
 	text := fmt.Sprintf("%s\n\n%s", notification.Title, notification.Message)
 
+	locale := u.recipientLocale(notification.Locale)
 	if notification.ActionURL != "" {
-		text += fmt.Sprintf("\n\nДля получения дополнительной информации перейдите по ссылке: %s", notification.ActionURL)
+		text += "\n\n" + i18n.T(locale, "email.more_info", map[string]interface{}{"URL": notification.ActionURL})
 	}
 
-	text += "\n\n---\nЭто автоматическое сообщение от Tachyon Messenger"
+	text += "\n\n---\n" + i18n.T(locale, "email.automated_footer", nil)
+	if footer := u.orgSettings.Get().Branding.EmailFooter; footer != "" {
+		text += "\n" + footer
+	}
 	return text
 }
 
+// brandingFooterHTML returns the organization email footer as HTML, empty if it is not configured
+func (u *notificationUsecase) brandingFooterHTML() string {
+	footer := u.orgSettings.Get().Branding.EmailFooter
+	if footer == "" {
+		return ""
+	}
+	return "<br>" + html.EscapeString(footer)
+}
+
+// withBranding adds organization branding strings to email template variables without overriding given ones
+func (u *notificationUsecase) withBranding(variables map[string]interface{}) map[string]interface{} {
+	branding := u.orgSettings.Get().Branding
+
+	result := map[string]interface{}{
+		"CompanyName":  branding.CompanyName,
+		"ProductName":  branding.ProductName,
+		"SupportEmail": branding.SupportEmail,
+		"EmailFooter":  branding.EmailFooter,
+	}
+	for key, value := range variables {
+		result[key] = value
+	}
+	return result
+}
+
 // buildActionButton builds action button HTML if action URL exists
 func (u *notificationUsecase) buildActionButton(notification *models.Notification) string {
 	if notification.ActionURL == "" {
@@ -983,9 +1821,9 @@ func (u *notificationUsecase) buildActionButton(notification *models.Notificatio
 
 	return fmt.Sprintf(`
 		<div style="text-align: center; margin: 20px 0;">
-			<a href="%s" class="button">Открыть</a>
+			<a href="%s" class="button">%s</a>
 		</div>
-	`, notification.ActionURL)
+	`, notification.ActionURL, i18n.T(u.recipientLocale(notification.Locale), "email.open", nil))
 }
 
 // convertPriorityForEmail converts notification priority to email priority
@@ -1006,35 +1844,14 @@ func (u *notificationUsecase) shouldSendEmail(channels []models.DeliveryChannel)
 	return false
 }
 
-// renderTemplateString renders a simple template string (basic implementation)
-func (u *notificationUsecase) renderTemplateString(templateName string, variables map[string]interface{}) (string, error) {
-	// This is a simplified template rendering
-	// In a real implementation, you'd use proper templating
-
-	templates := map[string]string{
-		"welcome_title":                "Добро пожаловать, {{.UserName}}!",
-		"welcome_message":              "Ваш аккаунт успешно создан в Tachyon Messenger",
-		"task_assigned_title":          "Новая задача: {{.TaskTitle}}",
-		"task_assigned_message":        "Вам назначена задача с приоритетом {{.TaskPriority}}",
-		"message_notification_title":   "Новое сообщение от {{.SenderName}}",
-		"message_notification_message": "{{.MessageContent}}",
-		"calendar_reminder_title":      "Напоминание: {{.EventTitle}}",
-		"calendar_reminder_message":    "Событие начинается {{.StartTime}}",
-	}
-
-	template, exists := templates[templateName]
-	if !exists {
+// renderTemplateString renders a notification title or message in locale
+func (u *notificationUsecase) renderTemplateString(locale i18n.Locale, templateName string, variables map[string]interface{}) (string, error) {
+	key := "notification." + templateName
+	if !i18n.Has(locale, key) {
 		return templateName, nil // Return template name if not found
 	}
 
-	// Simple variable substitution (in real implementation, use proper templating)
-	result := template
-	for key, value := range variables {
-		placeholder := fmt.Sprintf("{{.%s}}", key)
-		result = strings.ReplaceAll(result, placeholder, fmt.Sprintf("%v", value))
-	}
-
-	return result, nil
+	return i18n.T(locale, key, variables), nil
 }
 
 // Validation methods
@@ -1121,6 +1938,12 @@ func (u *notificationUsecase) validateBulkCreateNotificationRequest(req *models.
 		return fmt.Errorf("message too long (max 2000 characters)")
 	}
 
+	for userID, locale := range req.UserLocales {
+		if !locale.IsValid() {
+			return fmt.Errorf("unsupported locale of user %d: %s", userID, locale)
+		}
+	}
+
 	return nil
 }
 
@@ -1142,6 +1965,10 @@ func (u *notificationUsecase) validateTemplatedNotificationRequest(req *Template
 		return fmt.Errorf("template name is required")
 	}
 
+	if req.Locale != "" && !req.Locale.IsValid() {
+		return fmt.Errorf("unsupported locale: %s", req.Locale)
+	}
+
 	return nil
 }
 
@@ -1167,6 +1994,18 @@ func (u *notificationUsecase) validateSystemAnnouncementRequest(req *SystemAnnou
 		return fmt.Errorf("content too long (max 5000 characters)")
 	}
 
+	for locale, translation := range req.Translations {
+		if !locale.IsValid() {
+			return fmt.Errorf("unsupported translation locale: %s", locale)
+		}
+		if len(translation.Title) > 255 {
+			return fmt.Errorf("%s title too long (max 255 characters)", locale)
+		}
+		if len(translation.Content) > 5000 {
+			return fmt.Errorf("%s content too long (max 5000 characters)", locale)
+		}
+	}
+
 	return nil
 }
 
@@ -1193,6 +2032,37 @@ func (u *notificationUsecase) validateMarkAsReadRequest(req *models.MarkAsReadRe
 	return nil
 }
 
+// validateMarkAsReadByFilterRequest validates mark as read by filter request
+func (u *notificationUsecase) validateMarkAsReadByFilterRequest(req *models.MarkAsReadByFilterRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+
+	// Empty filter would mark everything as read, read-all endpoint exists for that
+	if req.Type == nil && req.RelatedType == "" && req.RelatedID == nil && req.Before == nil {
+		return fmt.Errorf("at least one filter criterion is required")
+	}
+
+	if req.RelatedID != nil && req.RelatedType == "" {
+		return fmt.Errorf("related type is required when related ID is specified")
+	}
+
+	return nil
+}
+
+// validateAdminNotificationFilter validates admin notification history filter
+func (u *notificationUsecase) validateAdminNotificationFilter(filter *models.AdminNotificationFilter) error {
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return fmt.Errorf("created_after must be before created_before")
+	}
+
+	if filter.Locale != nil && !filter.Locale.IsValid() {
+		return fmt.Errorf("unsupported locale: %s", *filter.Locale)
+	}
+
+	return nil
+}
+
 // validateUserPreferenceRequest validates user preference request
 func (u *notificationUsecase) validateUserPreferenceRequest(req *models.UserPreferenceRequest) error {
 	if req == nil {
@@ -1203,6 +2073,19 @@ func (u *notificationUsecase) validateUserPreferenceRequest(req *models.UserPref
 		return fmt.Errorf("notification type is required")
 	}
 
+	// Security notifications can be moved off other channels, but always reach the app
+	if req.NotificationType == models.NotificationTypeSecurity && req.InAppEnabled != nil && !*req.InAppEnabled {
+		return fmt.Errorf("security notifications cannot be disabled in-app")
+	}
+
+	// Presentation values must come from the catalog, the apps only bundle these sounds
+	if req.Sound != nil && !models.IsNotificationSound(*req.Sound) {
+		return fmt.Errorf("unknown sound %q", *req.Sound)
+	}
+	if req.BannerStyle != nil && !req.BannerStyle.IsValid() {
+		return fmt.Errorf("unknown banner style %q", *req.BannerStyle)
+	}
+
 	// Validate quiet hours
 	if req.QuietHoursStart != nil {
 		if *req.QuietHoursStart < 0 || *req.QuietHoursStart > 23 {
@@ -1216,6 +2099,25 @@ func (u *notificationUsecase) validateUserPreferenceRequest(req *models.UserPref
 		}
 	}
 
+	if req.QuietStartMinute != nil {
+		if *req.QuietStartMinute < 0 || *req.QuietStartMinute > 59 {
+			return fmt.Errorf("quiet hours start minute must be between 0 and 59")
+		}
+	}
+
+	if req.QuietEndMinute != nil {
+		if *req.QuietEndMinute < 0 || *req.QuietEndMinute > 59 {
+			return fmt.Errorf("quiet hours end minute must be between 0 and 59")
+		}
+	}
+
+	// Validate timezone
+	if req.Timezone != nil && strings.TrimSpace(*req.Timezone) != "" {
+		if _, err := time.LoadLocation(strings.TrimSpace(*req.Timezone)); err != nil {
+			return fmt.Errorf("invalid timezone: %s", *req.Timezone)
+		}
+	}
+
 	// Validate digest frequency
 	if req.DigestFrequency != nil {
 		if *req.DigestFrequency < 15 || *req.DigestFrequency > 1440 {
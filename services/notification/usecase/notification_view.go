@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/query"
+)
+
+// GetNotificationViews returns saved notification center views of the user
+func (u *notificationUsecase) GetNotificationViews(userID uint) ([]*models.NotificationView, error) {
+	return u.notificationRepo.GetUserNotificationViews(userID)
+}
+
+// GetNotificationView returns a saved view of the user
+func (u *notificationUsecase) GetNotificationView(userID, viewID uint) (*models.NotificationView, error) {
+	return u.notificationRepo.GetNotificationView(userID, viewID)
+}
+
+// CreateNotificationView saves a notification center view for the user
+func (u *notificationUsecase) CreateNotificationView(userID uint, req *models.CreateNotificationViewRequest) (*models.NotificationView, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("validation failed: name is required")
+	}
+	if err := validateNotificationViewFilter(&req.Filter); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	count, err := u.notificationRepo.CountUserNotificationViews(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= models.MaxNotificationViews {
+		return nil, fmt.Errorf("validation failed: at most %d views are allowed", models.MaxNotificationViews)
+	}
+
+	if err := u.checkNotificationViewName(userID, name, 0); err != nil {
+		return nil, err
+	}
+
+	view := &models.NotificationView{
+		UserID: userID,
+		Name:   name,
+		Filter: req.Filter,
+	}
+	if err := u.notificationRepo.CreateNotificationView(view); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"user_id": userID,
+		"view_id": view.ID,
+	}).Info("Notification view created")
+
+	return view, nil
+}
+
+// UpdateNotificationView renames a saved view or replaces its filter
+func (u *notificationUsecase) UpdateNotificationView(userID, viewID uint, req *models.UpdateNotificationViewRequest) (*models.NotificationView, error) {
+	view, err := u.notificationRepo.GetNotificationView(userID, viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("validation failed: name is required")
+		}
+		if err := u.checkNotificationViewName(userID, name, view.ID); err != nil {
+			return nil, err
+		}
+		view.Name = name
+	}
+	if req.Filter != nil {
+		if err := validateNotificationViewFilter(req.Filter); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		view.Filter = *req.Filter
+	}
+
+	if err := u.notificationRepo.UpdateNotificationView(view); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+// DeleteNotificationView deletes a saved view of the user
+func (u *notificationUsecase) DeleteNotificationView(userID, viewID uint) error {
+	return u.notificationRepo.DeleteNotificationView(userID, viewID)
+}
+
+// checkNotificationViewName rejects names of other views of the user
+func (u *notificationUsecase) checkNotificationViewName(userID uint, name string, excludeID uint) error {
+	exists, err := u.notificationRepo.NotificationViewNameExists(userID, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("notification view %q already exists", name)
+	}
+	return nil
+}
+
+// validateNotificationViewFilter checks the default sort of a view against the sortable fields of notification lists
+func validateNotificationViewFilter(filter *models.NotificationViewFilter) error {
+	filter.Sort = strings.TrimSpace(filter.Sort)
+	if filter.Sort == "" {
+		return nil
+	}
+	if _, err := query.Parse(url.Values{"sort": {filter.Sort}}, models.NotificationListOptions); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+)
+
+// GetPreferenceDefaults returns organization default preferences of all notification types that have one
+func (u *notificationUsecase) GetPreferenceDefaults() ([]*models.NotificationPreferenceDefault, error) {
+	return u.notificationRepo.GetPreferenceDefaults()
+}
+
+// SetPreferenceDefault sets the organization default preference of a notification type. It applies
+// to users without their own preference for the type, omitted fields get the built-in defaults.
+func (u *notificationUsecase) SetPreferenceDefault(notificationType models.NotificationType, req *models.PreferenceDefaultRequest, adminID uint) (*models.NotificationPreferenceDefault, error) {
+	if err := validatePreferenceDefaultRequest(notificationType, req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	preferenceDefault := &models.NotificationPreferenceDefault{
+		NotificationType: notificationType,
+		InAppEnabled:     true,
+		EmailEnabled:     true,
+		PushEnabled:      true,
+		SMSEnabled:       false,
+		MinPriority:      models.NotificationPriorityLow,
+		QuietHoursStart:  req.QuietHoursStart,
+		QuietHoursEnd:    req.QuietHoursEnd,
+		WeekendEnabled:   true,
+		DigestEnabled:    false,
+		DigestFrequency:  req.DigestFrequency,
+		UpdatedBy:        adminID,
+	}
+	if req.InAppEnabled != nil {
+		preferenceDefault.InAppEnabled = *req.InAppEnabled
+	}
+	if req.EmailEnabled != nil {
+		preferenceDefault.EmailEnabled = *req.EmailEnabled
+	}
+	if req.PushEnabled != nil {
+		preferenceDefault.PushEnabled = *req.PushEnabled
+	}
+	if req.SMSEnabled != nil {
+		preferenceDefault.SMSEnabled = *req.SMSEnabled
+	}
+	if req.MinPriority != nil {
+		preferenceDefault.MinPriority = *req.MinPriority
+	}
+	if req.QuietHoursStart != nil && req.QuietHoursEnd != nil {
+		preferenceDefault.QuietHoursStartMinute = req.QuietStartMinute
+		preferenceDefault.QuietHoursEndMinute = req.QuietEndMinute
+	}
+	if req.WeekendEnabled != nil {
+		preferenceDefault.WeekendEnabled = *req.WeekendEnabled
+	}
+	if req.DigestEnabled != nil {
+		preferenceDefault.DigestEnabled = *req.DigestEnabled
+	}
+
+	if err := u.notificationRepo.SavePreferenceDefault(preferenceDefault); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"type":     notificationType,
+		"admin_id": adminID,
+	}).Info("Notification preference default updated")
+
+	return preferenceDefault, nil
+}
+
+// DeletePreferenceDefault removes the organization default preference of a notification type
+func (u *notificationUsecase) DeletePreferenceDefault(notificationType models.NotificationType) error {
+	if !isNotificationType(notificationType) {
+		return fmt.Errorf("validation failed: unknown notification type %q", notificationType)
+	}
+	return u.notificationRepo.DeletePreferenceDefault(notificationType)
+}
+
+// ResetUserPreferences deletes preferences of the selected users, so the organization defaults
+// apply to them again. It returns the number of deleted preferences.
+func (u *notificationUsecase) ResetUserPreferences(req *models.ResetUserPreferencesRequest) (int64, error) {
+	if len(req.UserIDs) == 0 {
+		return 0, fmt.Errorf("validation failed: at least one user is required")
+	}
+	for _, notificationType := range req.Types {
+		if !isNotificationType(notificationType) {
+			return 0, fmt.Errorf("validation failed: unknown notification type %q", notificationType)
+		}
+	}
+
+	reset, err := u.notificationRepo.DeletePreferencesOfUsers(req.UserIDs, req.Types)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"users": len(req.UserIDs),
+		"types": req.Types,
+		"reset": reset,
+	}).Info("User notification preferences reset to defaults")
+
+	return reset, nil
+}
+
+// ExportUserPreferences returns the effective preference of every selected user and notification type
+// together with the layer it comes from
+func (u *notificationUsecase) ExportUserPreferences(req *models.PreferenceExportRequest) ([]*models.EffectivePreferenceResponse, error) {
+	if len(req.UserIDs) == 0 {
+		return nil, fmt.Errorf("validation failed: at least one user is required")
+	}
+	types := req.Types
+	if len(types) == 0 {
+		types = models.NotificationTypes()
+	}
+	for _, notificationType := range types {
+		if !isNotificationType(notificationType) {
+			return nil, fmt.Errorf("validation failed: unknown notification type %q", notificationType)
+		}
+	}
+
+	preferences, err := u.notificationRepo.GetPreferencesOfUsers(req.UserIDs, types)
+	if err != nil {
+		return nil, err
+	}
+	type preferenceKey struct {
+		userID           uint
+		notificationType models.NotificationType
+	}
+	own := make(map[preferenceKey]*models.UserNotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		own[preferenceKey{preference.UserID, preference.NotificationType}] = preference
+	}
+
+	defaults, err := u.preferenceDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	export := make([]*models.EffectivePreferenceResponse, 0, len(req.UserIDs)*len(types))
+	for _, userID := range req.UserIDs {
+		for _, notificationType := range types {
+			if preference, ok := own[preferenceKey{userID, notificationType}]; ok {
+				preference.FillPresentation()
+				export = append(export, &models.EffectivePreferenceResponse{
+					Source:                     models.PreferenceSourceUser,
+					UserNotificationPreference: preference,
+				})
+				continue
+			}
+			preference, source := u.applyPreferenceDefault(userID, notificationType, defaults[notificationType])
+			preference.FillPresentation()
+			export = append(export, &models.EffectivePreferenceResponse{
+				Source:                     source,
+				UserNotificationPreference: preference,
+			})
+		}
+	}
+
+	return export, nil
+}
+
+// defaultPreference returns the preference of a user without an own preference for the type:
+// the organization default if one is set, the built-in defaults otherwise
+func (u *notificationUsecase) defaultPreference(userID uint, notificationType models.NotificationType) (*models.UserNotificationPreference, models.PreferenceSource, error) {
+	preferenceDefault, err := u.notificationRepo.GetPreferenceDefault(notificationType)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get preference default: %w", err)
+	}
+	preference, source := u.applyPreferenceDefault(userID, notificationType, preferenceDefault)
+	return preference, source, nil
+}
+
+// applyPreferenceDefault builds the preference of a user from the organization default of the type, nil uses the built-in defaults
+func (u *notificationUsecase) applyPreferenceDefault(userID uint, notificationType models.NotificationType, preferenceDefault *models.NotificationPreferenceDefault) (*models.UserNotificationPreference, models.PreferenceSource) {
+	preference := &models.UserNotificationPreference{
+		UserID:           userID,
+		NotificationType: notificationType,
+		InAppEnabled:     true,
+		EmailEnabled:     true,
+		PushEnabled:      true,
+		SMSEnabled:       false,
+		MinPriority:      models.NotificationPriorityLow,
+		WeekendEnabled:   true,
+		DigestEnabled:    false,
+		Timezone:         u.orgSettings.Get().DefaultTimezone,
+	}
+	if preferenceDefault == nil {
+		return preference, models.PreferenceSourceSystem
+	}
+
+	preference.InAppEnabled = preferenceDefault.InAppEnabled
+	preference.EmailEnabled = preferenceDefault.EmailEnabled
+	preference.PushEnabled = preferenceDefault.PushEnabled
+	preference.SMSEnabled = preferenceDefault.SMSEnabled
+	preference.MinPriority = preferenceDefault.MinPriority
+	preference.QuietHoursStart = preferenceDefault.QuietHoursStart
+	preference.QuietHoursStartMinute = preferenceDefault.QuietHoursStartMinute
+	preference.QuietHoursEnd = preferenceDefault.QuietHoursEnd
+	preference.QuietHoursEndMinute = preferenceDefault.QuietHoursEndMinute
+	preference.WeekendEnabled = preferenceDefault.WeekendEnabled
+	preference.DigestEnabled = preferenceDefault.DigestEnabled
+	preference.DigestFrequency = preferenceDefault.DigestFrequency
+	return preference, models.PreferenceSourceDefault
+}
+
+// preferenceDefaults returns organization default preferences by notification type
+func (u *notificationUsecase) preferenceDefaults() (map[models.NotificationType]*models.NotificationPreferenceDefault, error) {
+	defaults, err := u.notificationRepo.GetPreferenceDefaults()
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[models.NotificationType]*models.NotificationPreferenceDefault, len(defaults))
+	for _, preferenceDefault := range defaults {
+		byType[preferenceDefault.NotificationType] = preferenceDefault
+	}
+	return byType, nil
+}
+
+// validatePreferenceDefaultRequest validates an organization default preference
+func validatePreferenceDefaultRequest(notificationType models.NotificationType, req *models.PreferenceDefaultRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+	if !isNotificationType(notificationType) {
+		return fmt.Errorf("unknown notification type %q", notificationType)
+	}
+
+	// Security notifications always reach the app, the organization cannot turn that off either
+	if notificationType == models.NotificationTypeSecurity && req.InAppEnabled != nil && !*req.InAppEnabled {
+		return fmt.Errorf("security notifications cannot be disabled in-app")
+	}
+
+	if (req.QuietHoursStart == nil) != (req.QuietHoursEnd == nil) {
+		return fmt.Errorf("quiet hours need both start and end")
+	}
+	if req.QuietHoursStart == nil && (req.QuietStartMinute != nil || req.QuietEndMinute != nil) {
+		return fmt.Errorf("quiet hours minutes need start and end hours")
+	}
+	if req.DigestEnabled != nil && *req.DigestEnabled && req.DigestFrequency == nil {
+		return fmt.Errorf("digest frequency is required when digest is enabled")
+	}
+	return nil
+}
+
+// isNotificationType checks if t is a known notification type
+func isNotificationType(t models.NotificationType) bool {
+	for _, known := range models.NotificationTypes() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
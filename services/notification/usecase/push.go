@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/services/notification/push"
+	"tachyon-messenger/shared/logger"
+)
+
+// RegisterPushDevice registers a mobile device of the user for push notifications
+func (u *notificationUsecase) RegisterPushDevice(userID uint, req *models.RegisterPushDeviceRequest) (*models.PushDevice, error) {
+	device := &models.PushDevice{
+		UserID:     userID,
+		Platform:   req.Platform,
+		Token:      strings.TrimSpace(req.Token),
+		AppVersion: req.AppVersion,
+		LastSeenAt: time.Now(),
+	}
+	if device.Token == "" {
+		return nil, fmt.Errorf("validation failed: token is required")
+	}
+	if err := u.notificationRepo.SavePushDevice(device); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"user_id":   userID,
+		"device_id": device.ID,
+		"platform":  device.Platform,
+	}).Info("Push device registered")
+
+	return device, nil
+}
+
+// GetPushDevices returns push devices of the user
+func (u *notificationUsecase) GetPushDevices(userID uint) ([]*models.PushDevice, error) {
+	return u.notificationRepo.GetUserPushDevices(userID)
+}
+
+// DeletePushDevice unregisters a push device of the user, e.g. on logout
+func (u *notificationUsecase) DeletePushDevice(userID, deviceID uint) error {
+	return u.notificationRepo.DeleteUserPushDevice(userID, deviceID)
+}
+
+// sendPushNotification sends notification to all push devices of the user with the payload
+// template of its type, presented as the user chose for the type. The badge shows the unread
+// count of the user without types excluded from it.
+func (u *notificationUsecase) sendPushNotification(notification *models.Notification, delivery *models.NotificationDelivery) error {
+	if u.pushSender == nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, "Push sender not configured")
+	}
+
+	devices, err := u.notificationRepo.GetUserPushDevices(notification.UserID)
+	if err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+	if len(devices) == 0 {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, "No push devices registered")
+	}
+
+	preference, err := u.GetUserPreference(notification.UserID, notification.Type)
+	if err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+	unreadCount, err := u.GetUnreadCount(notification.UserID)
+	if err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+	badge, err := u.badgeCount(notification.UserID, unreadCount)
+	if err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+	payload, err := push.Render(notification, badge, preference.Presentation())
+	if err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+
+	result, err := u.pushSender.Send(devices, payload)
+	if err != nil {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, err.Error())
+	}
+	u.removeInvalidPushTokens(notification.UserID, result.InvalidTokens)
+
+	if result.Sent == 0 {
+		return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusFailed, strings.Join(result.Errors, "; "))
+	}
+	return u.notificationRepo.UpdateDeliveryStatus(delivery.ID, models.NotificationStatusDelivered, "")
+}
+
+// sendSyncPush sends a silent push to the devices of the user, so apps in the background update
+// the badge and sync notifications changed elsewhere
+func (u *notificationUsecase) sendSyncPush(userID uint, event models.NotificationEventType, unreadCount int64) {
+	devices, err := u.notificationRepo.GetUserPushDevices(userID)
+	if err != nil || len(devices) == 0 {
+		return
+	}
+
+	badge, err := u.badgeCount(userID, unreadCount)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Warn("Failed to get badge count, using unread count")
+		badge = unreadCount
+	}
+
+	result, err := u.pushSender.Send(devices, push.SyncPayload(event, unreadCount, badge))
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"event":   event,
+			"error":   err.Error(),
+		}).Warn("Failed to send sync push")
+		return
+	}
+	u.removeInvalidPushTokens(userID, result.InvalidTokens)
+}
+
+// badgeCount returns the app badge of the user: the unread count without unread notifications
+// of types the user excluded from the badge
+func (u *notificationUsecase) badgeCount(userID uint, unreadCount int64) (int64, error) {
+	if unreadCount == 0 {
+		return 0, nil
+	}
+
+	preferences, err := u.notificationRepo.GetUserPreferences(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+	own := make(map[models.NotificationType]*models.UserNotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		own[preference.NotificationType] = preference
+	}
+
+	badge := unreadCount
+	for _, notificationType := range models.NotificationTypes() {
+		presentation := models.DefaultPresentation(notificationType)
+		if preference, ok := own[notificationType]; ok {
+			presentation = preference.Presentation()
+		}
+		if presentation.IncludeInBadge {
+			continue
+		}
+
+		excluded, err := u.notificationRepo.GetUnreadCountByType(userID, notificationType)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get unread count by type: %w", err)
+		}
+		badge -= excluded
+	}
+	return max(badge, 0), nil
+}
+
+// removeInvalidPushTokens deletes devices whose tokens were rejected by APNs or FCM
+func (u *notificationUsecase) removeInvalidPushTokens(userID uint, tokens []string) {
+	if len(tokens) == 0 {
+		return
+	}
+	removed, err := u.notificationRepo.DeletePushDevicesByToken(tokens)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		}).Warn("Failed to remove invalid push tokens")
+		return
+	}
+	logger.WithFields(map[string]interface{}{
+		"user_id": userID,
+		"removed": removed,
+	}).Info("Removed push devices with invalid tokens")
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+)
+
+func TestIsInQuietHoursAt(t *testing.T) {
+	clock := func(v int) *int { return &v }
+	// quietHours returns preferences with quiet hours from start to end in the timezone
+	quietHours := func(startHour, startMinute, endHour, endMinute int, timezone string) *models.UserNotificationPreference {
+		return &models.UserNotificationPreference{
+			QuietHoursStart:       clock(startHour),
+			QuietHoursStartMinute: clock(startMinute),
+			QuietHoursEnd:         clock(endHour),
+			QuietHoursEndMinute:   clock(endMinute),
+			Timezone:              timezone,
+		}
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.October, 14, hour, minute, 0, 0, time.UTC)
+	}
+
+	overnight := quietHours(22, 30, 7, 0, "UTC")
+	daytime := quietHours(13, 0, 14, 15, "UTC")
+	moscow := quietHours(22, 0, 8, 0, "Europe/Moscow")     // UTC+3
+	newYork := quietHours(22, 0, 8, 0, "America/New_York") // UTC-4 in October
+	invalid := quietHours(22, 0, 8, 0, "Mars/Olympus_Mons")
+
+	cases := []struct {
+		name       string
+		preference *models.UserNotificationPreference
+		now        time.Time
+		quiet      bool
+	}{
+		// A window that crosses midnight
+		{"overnight before start", overnight, at(22, 29), false},
+		{"overnight at start", overnight, at(22, 30), true},
+		{"overnight at midnight", overnight, at(0, 0), true},
+		{"overnight before end", overnight, at(6, 59), true},
+		{"overnight at end", overnight, at(7, 0), false},
+		{"overnight in the day", overnight, at(12, 0), false},
+
+		// A window within one day
+		{"daytime before start", daytime, at(12, 59), false},
+		{"daytime at start", daytime, at(13, 0), true},
+		{"daytime before end", daytime, at(14, 14), true},
+		{"daytime at end", daytime, at(14, 15), false},
+
+		// Non-UTC timezones
+		{"Moscow evening", moscow, at(19, 0), true}, // 22:00 in Moscow
+		{"Moscow before evening", moscow, at(18, 59), false},
+		{"Moscow morning", moscow, at(4, 59), true}, // 07:59 in Moscow
+		{"Moscow at end", moscow, at(5, 0), false},
+		{"New York night", newYork, at(3, 0), true}, // 23:00 the day before in New York
+		{"New York morning", newYork, at(12, 0), false},
+
+		// Invalid timezone falls back to UTC
+		{"invalid timezone at UTC night", invalid, at(23, 0), true},
+		{"invalid timezone at UTC day", invalid, at(19, 0), false},
+
+		// No or empty quiet hours
+		{"no quiet hours", &models.UserNotificationPreference{}, at(23, 0), false},
+		{"empty window", quietHours(22, 0, 22, 0, "UTC"), at(22, 0), false},
+	}
+
+	for _, tc := range cases {
+		if quiet := isInQuietHoursAt(tc.preference, tc.now); quiet != tc.quiet {
+			t.Errorf("%s: isInQuietHoursAt(%s) = %v, want %v", tc.name, tc.now.Format("15:04"), quiet, tc.quiet)
+		}
+	}
+}
+
+func TestUserLocation(t *testing.T) {
+	cases := []struct {
+		name       string
+		preference *models.UserNotificationPreference
+		location   string
+	}{
+		{"no preferences", nil, "UTC"},
+		{"no timezone", &models.UserNotificationPreference{}, "UTC"},
+		{"valid timezone", &models.UserNotificationPreference{Timezone: "Europe/Moscow"}, "Europe/Moscow"},
+		{"invalid timezone", &models.UserNotificationPreference{Timezone: "Mars/Olympus_Mons"}, "UTC"},
+		{"offset instead of zone", &models.UserNotificationPreference{Timezone: "+03:00"}, "UTC"},
+	}
+
+	for _, tc := range cases {
+		if location := userLocation(tc.preference).String(); location != tc.location {
+			t.Errorf("%s: userLocation = %s, want %s", tc.name, location, tc.location)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/notification/email"
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+)
+
+// GetTemplateExperiments returns subject experiments of all templates that have one
+func (u *notificationUsecase) GetTemplateExperiments() ([]*models.TemplateExperiment, error) {
+	return u.notificationRepo.GetTemplateExperiments()
+}
+
+// SetTemplateExperiment sets the subject experiment of a template. It applies to notifications
+// sent afterwards; new variants restart the experiment and drop its collected events.
+func (u *notificationUsecase) SetTemplateExperiment(templateName string, req *models.TemplateExperimentRequest, adminID uint) (*models.TemplateExperiment, error) {
+	if err := validateTemplateExperimentRequest(templateName, req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	experiment := &models.TemplateExperiment{
+		TemplateName: templateName,
+		Enabled:      true,
+		Variants:     req.Variants,
+		UpdatedBy:    adminID,
+	}
+	if req.Enabled != nil {
+		experiment.Enabled = *req.Enabled
+	}
+
+	if err := u.notificationRepo.SaveTemplateExperiment(experiment); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"template":      templateName,
+		"experiment_id": experiment.ID,
+		"enabled":       experiment.Enabled,
+		"variants":      len(experiment.Variants),
+		"admin_id":      adminID,
+	}).Info("Template experiment updated")
+
+	return experiment, nil
+}
+
+// DeleteTemplateExperiment removes the subject experiment of a template with its events
+func (u *notificationUsecase) DeleteTemplateExperiment(templateName string) error {
+	return u.notificationRepo.DeleteTemplateExperiment(templateName)
+}
+
+// GetTemplateExperimentReport compares open and click rates of the variants of a template experiment
+func (u *notificationUsecase) GetTemplateExperimentReport(templateName string) (*models.TemplateExperimentReport, error) {
+	experiment, err := u.notificationRepo.GetTemplateExperiment(templateName)
+	if err != nil {
+		return nil, err
+	}
+	if experiment == nil {
+		return nil, fmt.Errorf("template experiment not found")
+	}
+
+	counts, err := u.notificationRepo.CountTemplateEngagement(experiment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.TemplateExperimentReport{
+		Experiment: experiment,
+		Variants:   make([]*models.TemplateVariantStats, 0, len(experiment.Variants)),
+	}
+	var leader *models.TemplateVariantStats
+	for _, variant := range experiment.Variants {
+		stats := &models.TemplateVariantStats{
+			Variant: variant.Key,
+			Subject: variant.Subject,
+			Weight:  variant.Weight,
+			Sent:    counts[variant.Key][models.TemplateEngagementSent],
+			Opened:  counts[variant.Key][models.TemplateEngagementOpen],
+			Clicked: counts[variant.Key][models.TemplateEngagementClick],
+		}
+		if stats.Sent > 0 {
+			stats.OpenRate = float64(stats.Opened) / float64(stats.Sent)
+			stats.ClickRate = float64(stats.Clicked) / float64(stats.Sent)
+		}
+		if stats.Sent > 0 && (leader == nil || stats.OpenRate > leader.OpenRate) {
+			leader = stats
+		}
+		report.Variants = append(report.Variants, stats)
+	}
+	if leader != nil {
+		report.Leader = leader.Variant
+	}
+
+	return report, nil
+}
+
+// TrackNotificationEngagement records an open or click of a notification of the user. Clients may
+// report every notification, it reports whether the notification is part of an experiment and the
+// event was not recorded before.
+func (u *notificationUsecase) TrackNotificationEngagement(userID, notificationID uint, req *models.TrackEngagementRequest) (bool, error) {
+	if req == nil {
+		return false, fmt.Errorf("validation failed: request is required")
+	}
+	if req.Event != models.TemplateEngagementOpen && req.Event != models.TemplateEngagementClick {
+		return false, fmt.Errorf("validation failed: unknown event %q", req.Event)
+	}
+
+	notification, err := u.notificationRepo.GetNotificationByID(notificationID)
+	if err != nil {
+		return false, err
+	}
+	if notification.UserID != userID {
+		return false, fmt.Errorf("notification not found")
+	}
+
+	assignment, err := u.notificationRepo.GetTemplateAssignment(notificationID)
+	if err != nil || assignment == nil {
+		return false, err
+	}
+
+	return u.notificationRepo.RecordTemplateEngagement(&models.TemplateEngagementEvent{
+		ExperimentID:   assignment.ExperimentID,
+		Variant:        assignment.Variant,
+		NotificationID: notificationID,
+		UserID:         userID,
+		Event:          req.Event,
+	})
+}
+
+// assignTemplateVariant returns the running experiment of a template and the variant of the user,
+// nil if the template has none. Errors are logged, the template is then sent as is.
+func (u *notificationUsecase) assignTemplateVariant(templateName string, userID uint) (*models.TemplateExperiment, *models.TemplateVariant) {
+	experiment, err := u.notificationRepo.GetTemplateExperiment(templateName)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"template": templateName,
+			"error":    err.Error(),
+		}).Warn("Failed to get template experiment, sending the template subject")
+		return nil, nil
+	}
+	if experiment == nil || !experiment.Enabled {
+		return nil, nil
+	}
+
+	variant := experiment.AssignVariant(userID)
+	if variant == nil {
+		return nil, nil
+	}
+	return experiment, variant
+}
+
+// recordTemplateSent records a notification sent with a variant of an experiment
+func (u *notificationUsecase) recordTemplateSent(experiment *models.TemplateExperiment, variant *models.TemplateVariant, notification *models.NotificationResponse) {
+	_, err := u.notificationRepo.RecordTemplateEngagement(&models.TemplateEngagementEvent{
+		ExperimentID:   experiment.ID,
+		Variant:        variant.Key,
+		NotificationID: notification.ID,
+		UserID:         notification.UserID,
+		Event:          models.TemplateEngagementSent,
+	})
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"template":        experiment.TemplateName,
+			"variant":         variant.Key,
+			"notification_id": notification.ID,
+			"error":           err.Error(),
+		}).Warn("Failed to record template variant")
+	}
+}
+
+// renderVariantSubject renders the subject of a variant as a notification title. Variants are
+// written in one language and replace the localized title in every locale.
+func renderVariantSubject(subject string, variables map[string]interface{}) string {
+	for name, value := range variables {
+		subject = strings.ReplaceAll(subject, fmt.Sprintf("{{.%s}}", name), fmt.Sprintf("%v", value))
+	}
+	return subject
+}
+
+// validateTemplateExperimentRequest validates the subject experiment of a template
+func validateTemplateExperimentRequest(templateName string, req *models.TemplateExperimentRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+	if !isKnownTemplate(templateName) {
+		return fmt.Errorf("unknown template %q", templateName)
+	}
+	if len(req.Variants) < 2 {
+		return fmt.Errorf("at least two variants are required")
+	}
+
+	seen := make(map[string]bool, len(req.Variants))
+	for i, variant := range req.Variants {
+		if strings.TrimSpace(variant.Key) == "" {
+			return fmt.Errorf("variant %d: key is required", i+1)
+		}
+		if seen[variant.Key] {
+			return fmt.Errorf("variant %d: key %q is already used", i+1, variant.Key)
+		}
+		seen[variant.Key] = true
+
+		if strings.TrimSpace(variant.Subject) == "" {
+			return fmt.Errorf("variant %d: subject is required", i+1)
+		}
+		if variant.Weight <= 0 {
+			return fmt.Errorf("variant %d: weight must be positive", i+1)
+		}
+	}
+	return nil
+}
+
+// isKnownTemplate checks if templated notifications can be sent with the template
+func isKnownTemplate(templateName string) bool {
+	if _, exists := email.DefaultEmailTemplates[templateName]; exists {
+		return true
+	}
+	return i18n.Has(i18n.DefaultLocale, "notification."+templateName+"_title")
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/email"
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/services/notification/push"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/switches"
+)
+
+// TestNotificationRequest represents a request to send a diagnostic notification
+type TestNotificationRequest struct {
+	UserID    uint                   `json:"-"`
+	Recipient string                 `json:"-"` // Email of the calling admin
+	Channel   models.DeliveryChannel `json:"channel" binding:"required" validate:"required"`
+	Title     string                 `json:"title,omitempty" validate:"omitempty,max=255"`
+	Message   string                 `json:"message,omitempty" validate:"omitempty,max=2000"`
+	Locale    i18n.Locale            `json:"locale,omitempty" validate:"omitempty,enum"` // По умолчанию язык организации
+}
+
+// TestNotificationResult holds verbose diagnostics of a test notification
+type TestNotificationResult struct {
+	Channel    models.DeliveryChannel       `json:"channel"`
+	Recipient  string                       `json:"recipient,omitempty"`
+	Success    bool                         `json:"success"`
+	Persisted  bool                         `json:"persisted"` // Test notifications are never stored
+	Message    string                       `json:"message,omitempty"`
+	Error      string                       `json:"error,omitempty"`
+	Preview    *models.NotificationResponse `json:"preview"`
+	Push       *push.Payload                `json:"push,omitempty"` // Payload sent to the devices of the caller
+	SMTP       *email.SMTPDiagnostics       `json:"smtp,omitempty"`
+	DurationMs int64                        `json:"duration_ms"`
+	SentAt     time.Time                    `json:"sent_at"`
+}
+
+// SendTestNotification sends a test notification to the caller through a single channel.
+// Nothing is written to the notifications or deliveries tables.
+func (u *notificationUsecase) SendTestNotification(req *TestNotificationRequest) (*TestNotificationResult, error) {
+	if err := u.validateTestNotificationRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = "Tachyon test notification"
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		message = fmt.Sprintf("This is a test notification sent through the %s channel.", req.Channel)
+	}
+
+	// Build in-memory notification, it is only used for rendering
+	now := time.Now()
+	notification := &models.Notification{
+		UserID:   req.UserID,
+		Type:     models.NotificationTypeSystem,
+		Priority: models.NotificationPriorityLow,
+		Status:   models.NotificationStatusPending,
+		Title:    title,
+		Message:  message,
+		Locale:   req.Locale,
+	}
+	notification.CreatedAt = now
+	notification.UpdatedAt = now
+
+	result := &TestNotificationResult{
+		Channel:   req.Channel,
+		Recipient: req.Recipient,
+		Persisted: false,
+		Preview:   notification.ToResponse(),
+		SentAt:    now,
+	}
+
+	switch req.Channel {
+	case models.DeliveryChannelInApp:
+		// In-app delivery is a database write, there is no external provider to check
+		result.Success = true
+		result.Message = "In-app channel has no external provider; preview rendered without persisting"
+
+	case models.DeliveryChannelEmail:
+		u.sendTestEmail(notification, result)
+
+	case models.DeliveryChannelPush:
+		u.sendTestPush(notification, result)
+
+	case models.DeliveryChannelSMS:
+		result.Error = "SMS notifications not implemented"
+
+	case models.DeliveryChannelSlack:
+		result.Error = "Slack notifications not implemented"
+
+	case models.DeliveryChannelWebhook:
+		result.Error = "Webhook notifications not implemented"
+	}
+
+	result.DurationMs = time.Since(now).Milliseconds()
+
+	logger.WithFields(map[string]interface{}{
+		"user_id":     req.UserID,
+		"channel":     req.Channel,
+		"success":     result.Success,
+		"duration_ms": result.DurationMs,
+	}).Info("Test notification processed")
+
+	return result, nil
+}
+
+// sendTestEmail sends the test notification by email and attaches the SMTP transcript
+func (u *notificationUsecase) sendTestEmail(notification *models.Notification, result *TestNotificationResult) {
+	if u.emailSender == nil {
+		result.Error = "Email sender not configured (EMAIL_ENABLED=false or invalid SMTP config)"
+		return
+	}
+	if u.killSwitches.IsActive(switches.DisableEmailSending) {
+		result.Error = emailDisabledError
+		return
+	}
+
+	if err := u.emailSender.ValidateConfig(); err != nil {
+		result.Error = fmt.Sprintf("invalid SMTP config: %v", err)
+		return
+	}
+
+	diagnostics := u.emailSender.SendDiagnosticEmail(
+		result.Recipient,
+		notification.Title,
+		u.buildEmailHTML(notification),
+		u.buildEmailText(notification),
+	)
+
+	result.SMTP = diagnostics
+	result.Success = diagnostics.Success
+	if diagnostics.Success {
+		result.Message = fmt.Sprintf("Test email accepted by %s for %s", diagnostics.Host, result.Recipient)
+	} else {
+		result.Error = diagnostics.Error
+	}
+}
+
+// sendTestPush sends the test notification to the push devices of the caller and attaches the payload
+func (u *notificationUsecase) sendTestPush(notification *models.Notification, result *TestNotificationResult) {
+	if u.pushSender == nil {
+		result.Error = "Push sender not configured (PUSH_GATEWAY_URL is empty)"
+		return
+	}
+
+	devices, err := u.notificationRepo.GetUserPushDevices(notification.UserID)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if len(devices) == 0 {
+		result.Error = "No push devices registered for the caller"
+		return
+	}
+
+	preference, err := u.GetUserPreference(notification.UserID, notification.Type)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	payload, err := push.Render(notification, 0, preference.Presentation())
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Push = payload
+
+	sent, err := u.pushSender.Send(devices, payload)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	u.removeInvalidPushTokens(notification.UserID, sent.InvalidTokens)
+
+	result.Success = sent.Sent > 0
+	if result.Success {
+		result.Message = fmt.Sprintf("Test push accepted for %d of %d devices", sent.Sent, len(devices))
+	} else {
+		result.Error = strings.Join(sent.Errors, "; ")
+	}
+}
+
+// validateTestNotificationRequest validates test notification request
+func (u *notificationUsecase) validateTestNotificationRequest(req *TestNotificationRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+
+	if req.UserID == 0 {
+		return fmt.Errorf("user ID is required")
+	}
+
+	if !u.isValidChannel(req.Channel) {
+		return fmt.Errorf("invalid delivery channel: %s", req.Channel)
+	}
+
+	if req.Channel == models.DeliveryChannelEmail && req.Recipient == "" {
+		return fmt.Errorf("caller email is required for email channel")
+	}
+
+	if len(req.Title) > 255 {
+		return fmt.Errorf("title too long (max 255 characters)")
+	}
+
+	if req.Locale != "" && !req.Locale.IsValid() {
+		return fmt.Errorf("unsupported locale: %s", req.Locale)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"math"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+)
+
+// maxRetryAfter caps the delay suggested to producers
+const maxRetryAfter = 5 * time.Minute
+
+// BacklogLevel tells producers how loaded the notification queues are
+type BacklogLevel string
+
+const (
+	BacklogLevelNormal     BacklogLevel = "normal"     // Below the soft limit
+	BacklogLevelElevated   BacklogLevel = "elevated"   // Above the soft limit, producers should slow down
+	BacklogLevelOverloaded BacklogLevel = "overloaded" // Above the hard limit, low-priority tasks are rejected
+)
+
+// BacklogState is the backlog seen by the worker and the admission decision derived from it
+type BacklogState struct {
+	Backlog           int64        `json:"backlog"`
+	SoftLimit         int          `json:"soft_limit"`
+	HardLimit         int          `json:"hard_limit"`
+	Level             BacklogLevel `json:"level"`
+	RetryAfterSeconds int          `json:"retry_after_seconds"`
+	ConcurrentWorkers int          `json:"concurrent_workers"`
+	AvgProcessingMs   int64        `json:"avg_processing_ms"`
+}
+
+// Admits returns whether a task of the priority should be queued. Only low-priority tasks are
+// rejected, and only when the backlog is above the hard limit.
+func (s *BacklogState) Admits(priority models.NotificationPriority) bool {
+	return s.Level != BacklogLevelOverloaded || priority != models.NotificationPriorityLow
+}
+
+// BacklogState returns the backlog last observed by the autoscaler with its level and the delay
+// producers should wait before queueing more tasks
+func (w *Worker) BacklogState() *BacklogState {
+	w.scaleMu.Lock()
+	defer w.scaleMu.Unlock()
+
+	state := &BacklogState{
+		Backlog:           w.backlog,
+		SoftLimit:         w.config.BacklogSoftLimit,
+		HardLimit:         w.config.BacklogHardLimit,
+		Level:             BacklogLevelNormal,
+		ConcurrentWorkers: len(w.processors),
+		AvgProcessingMs:   w.avgLatency.Milliseconds(),
+	}
+
+	switch {
+	case state.HardLimit > 0 && state.Backlog >= int64(state.HardLimit):
+		state.Level = BacklogLevelOverloaded
+	case state.SoftLimit > 0 && state.Backlog >= int64(state.SoftLimit):
+		state.Level = BacklogLevelElevated
+	default:
+		return state
+	}
+
+	state.RetryAfterSeconds = w.retryAfter(state.Backlog - int64(state.SoftLimit))
+	return state
+}
+
+// retryAfter estimates how long the running processors take to work off excess tasks.
+// Caller must hold scaleMu.
+func (w *Worker) retryAfter(excess int64) int {
+	latency := w.avgLatency
+	if latency <= 0 {
+		latency = w.config.ScaleInterval
+	}
+	processors := len(w.processors)
+	if processors == 0 {
+		processors = 1
+	}
+
+	wait := time.Duration(float64(excess) * float64(latency) / float64(processors))
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	return int(math.Max(1, math.Ceil(wait.Seconds())))
+}
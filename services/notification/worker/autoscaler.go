@@ -0,0 +1,215 @@
+// File: services/notification/worker/autoscaler.go
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+)
+
+// latencySmoothing is the weight of the latest task in the average processing latency
+const latencySmoothing = 0.2
+
+// startProcessor starts one more task processor. Caller must hold scaleMu.
+func (w *Worker) startProcessor() {
+	w.processorSeq++
+	stop := make(chan struct{})
+	w.processors = append(w.processors, stop)
+
+	w.wg.Add(1)
+	go w.taskProcessor(w.processorSeq, stop)
+}
+
+// stopProcessor stops the most recently started task processor after its current task.
+// Caller must hold scaleMu.
+func (w *Worker) stopProcessor() {
+	last := len(w.processors) - 1
+	close(w.processors[last])
+	w.processors = w.processors[:last]
+}
+
+// Concurrency returns the number of running task processors
+func (w *Worker) Concurrency() int {
+	w.scaleMu.Lock()
+	defer w.scaleMu.Unlock()
+	return len(w.processors)
+}
+
+// scaleTo changes the number of task processors to n within configured limits
+func (w *Worker) scaleTo(n int) {
+	w.scaleMu.Lock()
+	defer w.scaleMu.Unlock()
+
+	if w.ctx.Err() != nil {
+		return
+	}
+
+	n = w.clampConcurrency(n)
+	current := len(w.processors)
+	if n == current {
+		return
+	}
+
+	for len(w.processors) < n {
+		w.startProcessor()
+	}
+	for len(w.processors) > n {
+		w.stopProcessor()
+	}
+	w.lastScaleAt = time.Now()
+
+	logger.WithFields(map[string]interface{}{
+		"worker_id": w.id,
+		"from":      current,
+		"to":        n,
+		"backlog":   w.backlog,
+		"latency":   w.avgLatency,
+	}).Info("Notification worker concurrency changed")
+}
+
+// clampConcurrency limits n to configured min/max concurrency
+func (w *Worker) clampConcurrency(n int) int {
+	if n < w.config.MinConcurrentWorkers {
+		n = w.config.MinConcurrentWorkers
+	}
+	if n > w.config.MaxConcurrentWorkers {
+		n = w.config.MaxConcurrentWorkers
+	}
+	return n
+}
+
+// recordLatency updates the average processing latency with a finished task
+func (w *Worker) recordLatency(duration time.Duration) {
+	w.scaleMu.Lock()
+	defer w.scaleMu.Unlock()
+
+	w.processedTasks++
+	if w.avgLatency == 0 {
+		w.avgLatency = duration
+		return
+	}
+	w.avgLatency = time.Duration(latencySmoothing*float64(duration) + (1-latencySmoothing)*float64(w.avgLatency))
+}
+
+// autoscaler periodically observes queue backlog and adjusts concurrency. Backlog is
+// observed even with a fixed pool so that it is reported in stats and metrics.
+func (w *Worker) autoscaler() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.ScaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.autoscale()
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// autoscale runs one scaling step
+func (w *Worker) autoscale() {
+	backlog, err := w.observeBacklog()
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"worker_id": w.id,
+			"error":     err.Error(),
+		}).Warn("Failed to observe notification backlog")
+		return
+	}
+
+	w.scaleMu.Lock()
+	w.backlog = backlog
+	current := len(w.processors)
+	desired := w.desiredConcurrency(current, backlog, w.avgLatency)
+	w.desired = desired
+	cooldownPassed := time.Since(w.lastScaleAt) >= w.config.ScaleDownCooldown
+	w.scaleMu.Unlock()
+
+	if !w.config.AutoscalingEnabled() {
+		return
+	}
+
+	switch {
+	case desired > current:
+		w.scaleTo(desired)
+	case desired < current && cooldownPassed:
+		// Scale down one processor at a time to avoid flapping on bursty queues
+		w.scaleTo(current - 1)
+	}
+}
+
+// desiredConcurrency returns concurrency that keeps backlog per processor at target.
+// Slow processing with tasks still waiting adds one more processor.
+func (w *Worker) desiredConcurrency(current int, backlog int64, latency time.Duration) int {
+	target := int64(w.config.TargetBacklogPerWorker)
+	if target <= 0 {
+		target = 1
+	}
+
+	desired := int((backlog + target - 1) / target)
+	if w.config.TargetLatency > 0 && latency > w.config.TargetLatency && backlog > 0 && desired <= current {
+		desired = current + 1
+	}
+
+	return w.clampConcurrency(desired)
+}
+
+// observeBacklog returns the number of tasks waiting in the worker channel and Redis queues
+func (w *Worker) observeBacklog() (int64, error) {
+	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
+	defer cancel()
+
+	mainLen, err := w.redisClient.LLen(ctx, w.config.QueueName).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get main queue length: %w", err)
+	}
+
+	retryLen, err := w.redisClient.LLen(ctx, w.config.RetryQueueName).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get retry queue length: %w", err)
+	}
+
+	return mainLen + retryLen + int64(len(w.taskChan)), nil
+}
+
+// WriteMetrics writes worker metrics in Prometheus text format. Backlog per worker is
+// the metric to target with HorizontalPodAutoscaler through a custom metrics adapter.
+func (w *Worker) WriteMetrics(out io.Writer) error {
+	stats := w.GetStats()
+
+	backlogPerWorker := float64(stats.Backlog)
+	if stats.ConcurrentWorkers > 0 {
+		backlogPerWorker /= float64(stats.ConcurrentWorkers)
+	}
+
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value float64
+	}{
+		{"notification_worker_concurrency", "gauge", "Running task processors", float64(stats.ConcurrentWorkers)},
+		{"notification_worker_concurrency_desired", "gauge", "Task processors wanted for current backlog", float64(stats.DesiredConcurrentWorkers)},
+		{"notification_worker_concurrency_min", "gauge", "Minimum task processors", float64(stats.MinConcurrentWorkers)},
+		{"notification_worker_concurrency_max", "gauge", "Maximum task processors", float64(stats.MaxConcurrentWorkers)},
+		{"notification_worker_backlog", "gauge", "Tasks waiting in queues", float64(stats.Backlog)},
+		{"notification_worker_backlog_per_worker", "gauge", "Tasks waiting per running task processor", backlogPerWorker},
+		{"notification_worker_processing_latency_seconds", "gauge", "Average task processing latency", float64(stats.AvgProcessingMs) / 1000},
+		{"notification_worker_processed_tasks_total", "counter", "Tasks processed by this worker", float64(stats.ProcessedTasks)},
+	}
+
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n%s{worker_id=%q} %g\n",
+			metric.name, metric.help, metric.name, metric.kind, metric.name, stats.WorkerID, metric.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+// File: services/notification/worker/fallback.go
+package worker
+
+import (
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/notification/models"
+	"tachyon-messenger/shared/logger"
+)
+
+const (
+	// fallbackBatchSize bounds the number of chains advanced in one pass
+	fallbackBatchSize = 100
+
+	// fallbackRetryDelay is how long a chain waits when its notification couldn't be checked
+	fallbackRetryDelay = time.Minute
+)
+
+// fallbackProcessor advances channel fallback chains whose next step is due
+func (w *Worker) fallbackProcessor() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.FallbackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.processFallbacks(time.Now())
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// processFallbacks claims due chains and moves each one step forward
+func (w *Worker) processFallbacks(now time.Time) {
+	fallbacks, err := w.notificationUC.ClaimDueFallbacks(now, fallbackBatchSize)
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to claim due fallback chains")
+	}
+
+	for _, fallback := range fallbacks {
+		w.advanceFallback(fallback, now)
+
+		if err := w.notificationUC.UpdateFallback(fallback); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"notification_id": fallback.NotificationID,
+				"error":           err.Error(),
+			}).Error("Failed to save fallback chain")
+		}
+	}
+}
+
+// advanceFallback runs one transition of a chain:
+//
+//	active --notification read or deleted--> cancelled
+//	active --step sent, more steps left----> active (next step scheduled)
+//	active --last step sent----------------> completed
+//
+// A step whose channel fails still counts as tried, the next channel is the fallback for it.
+func (w *Worker) advanceFallback(fallback *models.NotificationFallback, now time.Time) {
+	notification, err := w.notificationUC.GetNotificationByID(fallback.UserID, fallback.NotificationID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			w.finishFallback(fallback, models.FallbackStateCancelled)
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"notification_id": fallback.NotificationID,
+			"error":           err.Error(),
+		}).Warn("Failed to check notification of fallback chain, will retry")
+
+		retryAt := now.Add(fallbackRetryDelay)
+		fallback.NextAttemptAt = &retryAt
+		return
+	}
+	if notification.IsRead {
+		w.finishFallback(fallback, models.FallbackStateCancelled)
+		return
+	}
+	if fallback.NextStep >= len(fallback.Steps) {
+		w.finishFallback(fallback, models.FallbackStateCompleted)
+		return
+	}
+
+	step := fallback.Steps[fallback.NextStep]
+	if err := w.notificationUC.DeliverFallbackStep(fallback.NotificationID, step.Channel); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"notification_id": fallback.NotificationID,
+			"channel":         step.Channel,
+			"step":            fallback.NextStep + 1,
+			"error":           err.Error(),
+		}).Warn("Fallback step failed, moving on to the next channel")
+	}
+
+	fallback.LastChannel = step.Channel
+	fallback.NextStep++
+	if fallback.NextStep >= len(fallback.Steps) {
+		w.finishFallback(fallback, models.FallbackStateCompleted)
+		return
+	}
+
+	// Delays count from the notification, a late step doesn't push the following ones back
+	nextAttemptAt := fallback.CreatedAt.Add(time.Duration(fallback.Steps[fallback.NextStep].DelayMinutes) * time.Minute)
+	if nextAttemptAt.Before(now) {
+		nextAttemptAt = now
+	}
+	fallback.NextAttemptAt = &nextAttemptAt
+}
+
+// finishFallback moves a chain to a final state
+func (w *Worker) finishFallback(fallback *models.NotificationFallback, state models.FallbackState) {
+	fallback.State = state
+	fallback.NextAttemptAt = nil
+
+	logger.WithFields(map[string]interface{}{
+		"notification_id": fallback.NotificationID,
+		"state":           state,
+		"last_channel":    fallback.LastChannel,
+	}).Debug("Fallback chain finished")
+}
@@ -0,0 +1,364 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"tachyon-messenger/shared/logger"
+)
+
+// Tasks are claimed with a lease: popping a task records a claim with the worker ID and the
+// original task data in the processing hash, and the lease expiry in a sorted set. Workers renew
+// leases of the tasks they hold, so a task whose lease expired belongs to a worker that crashed
+// or lost Redis and is pushed back to its queue by the reaper of any instance.
+
+// claimTaskScript pops a task and claims it for the worker.
+// KEYS: queue, processing hash, leases; ARGV: worker ID, now (ms), lease duration (ms).
+// Tasks without an ID cannot be claimed and are returned as is.
+var claimTaskScript = goredis.NewScript(`
+local data = redis.call('RPOP', KEYS[1])
+if not data then
+	return false
+end
+
+local ok, task = pcall(cjson.decode, data)
+if not ok or type(task) ~= 'table' or type(task.id) ~= 'string' or task.id == '' then
+	return data
+end
+
+redis.call('HSET', KEYS[2], task.id, cjson.encode({
+	id = task.id,
+	type = task.type,
+	worker_id = ARGV[1],
+	queue = KEYS[1],
+	claimed_at = ARGV[2],
+	task = data,
+}))
+redis.call('ZADD', KEYS[3], tonumber(ARGV[2]) + tonumber(ARGV[3]), task.id)
+return data
+`)
+
+// renewLeasesScript extends leases still owned by the worker and returns IDs of tasks it lost.
+// KEYS: processing hash, leases; ARGV: worker ID, lease expiry (ms), task IDs...
+var renewLeasesScript = goredis.NewScript(`
+local lost = {}
+for i = 3, #ARGV do
+	local claim = redis.call('HGET', KEYS[1], ARGV[i])
+	local ok, info = false, nil
+	if claim then
+		ok, info = pcall(cjson.decode, claim)
+	end
+	if ok and info.worker_id == ARGV[1] then
+		redis.call('ZADD', KEYS[2], 'XX', ARGV[2], ARGV[i])
+	else
+		table.insert(lost, ARGV[i])
+	end
+end
+return lost
+`)
+
+// releaseClaimScript removes the claim if the worker still owns it. A task whose lease expired
+// may have been requeued and claimed by another worker meanwhile.
+// KEYS: processing hash, leases; ARGV: worker ID, task ID.
+var releaseClaimScript = goredis.NewScript(`
+local claim = redis.call('HGET', KEYS[1], ARGV[2])
+if not claim then
+	return 0
+end
+
+local ok, info = pcall(cjson.decode, claim)
+if ok and info.worker_id ~= ARGV[1] then
+	return 0
+end
+
+redis.call('HDEL', KEYS[1], ARGV[2])
+redis.call('ZREM', KEYS[2], ARGV[2])
+return 1
+`)
+
+// reapLeasesScript requeues tasks with expired leases and returns their claims. Tasks go back
+// to the consuming end of the queue they were claimed from, the main queue if it is unknown.
+// KEYS: leases, processing hash, main queue, retry queue; ARGV: now (ms), limit.
+var reapLeasesScript = goredis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+local reaped = {}
+for _, id in ipairs(expired) do
+	redis.call('ZREM', KEYS[1], id)
+	local claim = redis.call('HGET', KEYS[2], id)
+	if claim then
+		redis.call('HDEL', KEYS[2], id)
+		local ok, info = pcall(cjson.decode, claim)
+		if ok and type(info.task) == 'string' then
+			local queue = KEYS[3]
+			if info.queue == KEYS[4] then
+				queue = KEYS[4]
+			end
+			redis.call('RPUSH', queue, info.task)
+			table.insert(reaped, claim)
+		end
+	end
+end
+return reaped
+`)
+
+// maxReapBatch limits the number of tasks requeued by one reaper run
+const maxReapBatch = 500
+
+// TaskClaim represents a task held by a worker
+type TaskClaim struct {
+	TaskID     string    `json:"task_id"`
+	Type       TaskType  `json:"type,omitempty"`
+	WorkerID   string    `json:"worker_id"`
+	Queue      string    `json:"queue,omitempty"`
+	ClaimedAt  time.Time `json:"claimed_at"`
+	LeaseUntil time.Time `json:"lease_until,omitempty"`
+	Expired    bool      `json:"expired"`
+}
+
+// claimRecord is a claim as stored in the processing hash
+type claimRecord struct {
+	ID        string   `json:"id"`
+	Type      TaskType `json:"type"`
+	WorkerID  string   `json:"worker_id"`
+	Queue     string   `json:"queue"`
+	ClaimedAt int64    `json:"claimed_at,string"`
+	Task      string   `json:"task"`
+}
+
+func (qm *QueueManager) processingKey() string {
+	return qm.config.RedisKeyPrefix + ":processing"
+}
+
+func (qm *QueueManager) leasesKey() string {
+	return qm.config.RedisKeyPrefix + ":leases"
+}
+
+// GetClaims returns tasks currently claimed by workers, oldest claim first. Entries left by
+// workers that predate leases have no lease and are reported with their start time.
+func (qm *QueueManager) GetClaims(ctx context.Context, now time.Time) ([]*TaskClaim, error) {
+	entries, err := qm.redisClient.HGetAll(ctx, qm.processingKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claims: %w", err)
+	}
+
+	leases, err := qm.redisClient.ZRangeWithScores(ctx, qm.leasesKey(), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leases: %w", err)
+	}
+	leaseUntil := make(map[string]time.Time, len(leases))
+	for _, lease := range leases {
+		if id, ok := lease.Member.(string); ok {
+			leaseUntil[id] = time.UnixMilli(int64(lease.Score))
+		}
+	}
+
+	claims := make([]*TaskClaim, 0, len(entries))
+	for id, data := range entries {
+		var record struct {
+			claimRecord
+			StartedAt int64 `json:"started_at"`
+		}
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			continue
+		}
+
+		claim := &TaskClaim{
+			TaskID:   id,
+			Type:     record.Type,
+			WorkerID: record.WorkerID,
+			Queue:    record.Queue,
+		}
+		if record.ClaimedAt > 0 {
+			claim.ClaimedAt = time.UnixMilli(record.ClaimedAt)
+		} else {
+			claim.ClaimedAt = time.Unix(record.StartedAt, 0)
+		}
+		if until, ok := leaseUntil[id]; ok {
+			claim.LeaseUntil = until
+			claim.Expired = !until.After(now)
+		}
+		claims = append(claims, claim)
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].ClaimedAt.Before(claims[j].ClaimedAt)
+	})
+	return claims, nil
+}
+
+// ReapExpiredLeases requeues up to limit tasks whose lease expired by now and returns their claims
+func (qm *QueueManager) ReapExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*TaskClaim, error) {
+	keys := []string{qm.leasesKey(), qm.processingKey(), qm.config.QueueName, qm.config.RetryQueueName}
+	result, err := reapLeasesScript.Run(ctx, qm.redisClient.Client, keys, now.UnixMilli(), limit).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reap expired leases: %w", err)
+	}
+
+	taskStatus := NewTaskStatusStore(qm.redisClient, qm.config)
+	claims := make([]*TaskClaim, 0, len(result))
+	for _, data := range result {
+		var record claimRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			continue
+		}
+		claims = append(claims, &TaskClaim{
+			TaskID:    record.ID,
+			Type:      record.Type,
+			WorkerID:  record.WorkerID,
+			Queue:     record.Queue,
+			ClaimedAt: time.UnixMilli(record.ClaimedAt),
+			Expired:   true,
+		})
+
+		var task NotificationTask
+		if err := json.Unmarshal([]byte(record.Task), &task); err != nil {
+			continue
+		}
+		task.LastError = fmt.Sprintf("lease of worker %s expired", record.WorkerID)
+		if _, err := taskStatus.Save(ctx, &task, TaskStatusQueued, ""); err != nil {
+			logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
+				"task_id": task.ID,
+				"error":   err.Error(),
+			}).Warn("Failed to record reaped task status")
+		}
+	}
+
+	return claims, nil
+}
+
+// claimFromQueue pops a task from the queue and claims it with a lease held by the worker
+func (w *Worker) claimFromQueue(ctx context.Context, queueName string) (string, error) {
+	keys := []string{queueName, w.config.RedisKeyPrefix + ":processing", w.config.RedisKeyPrefix + ":leases"}
+	return claimTaskScript.Run(ctx, w.redisClient.Client, keys, w.id, time.Now().UnixMilli(), w.config.LeaseDuration.Milliseconds()).Text()
+}
+
+// holdClaim remembers a claimed task so its lease is renewed until it is released
+func (w *Worker) holdClaim(taskID string) {
+	w.claimsMu.Lock()
+	w.claims[taskID] = struct{}{}
+	w.claimsMu.Unlock()
+}
+
+// releaseClaim removes the claim of a task the worker is done with
+func (w *Worker) releaseClaim(taskID string) {
+	w.claimsMu.Lock()
+	delete(w.claims, taskID)
+	w.claimsMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := []string{w.config.RedisKeyPrefix + ":processing", w.config.RedisKeyPrefix + ":leases"}
+	if err := releaseClaimScript.Run(ctx, w.redisClient.Client, keys, w.id, taskID).Err(); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"task_id": taskID,
+			"error":   err.Error(),
+		}).Error("Failed to release task claim")
+	}
+}
+
+// heldClaims returns IDs of tasks claimed by the worker
+func (w *Worker) heldClaims() []interface{} {
+	w.claimsMu.Lock()
+	defer w.claimsMu.Unlock()
+
+	ids := make([]interface{}, 0, len(w.claims))
+	for id := range w.claims {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// renewLeases extends the leases of held tasks until until. Tasks whose lease already expired
+// have been requeued by a reaper and may be delivered twice, they are only reported.
+func (w *Worker) renewLeases(ctx context.Context, until time.Time) {
+	ids := w.heldClaims()
+	if len(ids) == 0 {
+		return
+	}
+
+	keys := []string{w.config.RedisKeyPrefix + ":processing", w.config.RedisKeyPrefix + ":leases"}
+	args := append([]interface{}{w.id, until.UnixMilli()}, ids...)
+	lost, err := renewLeasesScript.Run(ctx, w.redisClient.Client, keys, args...).StringSlice()
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"worker_id": w.id,
+			"tasks":     len(ids),
+			"error":     err.Error(),
+		}).Error("Failed to renew task leases")
+		return
+	}
+
+	if len(lost) > 0 {
+		w.claimsMu.Lock()
+		for _, id := range lost {
+			delete(w.claims, id)
+		}
+		w.claimsMu.Unlock()
+
+		logger.WithFields(map[string]interface{}{
+			"worker_id": w.id,
+			"task_ids":  lost,
+		}).Warn("Task leases expired before renewal, tasks were requeued")
+	}
+}
+
+// leaseKeeper renews the leases of held tasks and requeues tasks of dead workers
+func (w *Worker) leaseKeeper() {
+	defer w.wg.Done()
+
+	renewTicker := time.NewTicker(w.config.LeaseDuration / 3)
+	defer renewTicker.Stop()
+	reapTicker := time.NewTicker(w.config.LeaseReapInterval)
+	defer reapTicker.Stop()
+
+	for {
+		select {
+		case <-renewTicker.C:
+			ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
+			w.renewLeases(ctx, time.Now().Add(w.config.LeaseDuration))
+			cancel()
+		case <-reapTicker.C:
+			w.reapExpiredLeases()
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// reapExpiredLeases requeues tasks whose workers stopped renewing their leases
+func (w *Worker) reapExpiredLeases() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	claims, err := NewQueueManager(w.redisClient, w.config).ReapExpiredLeases(ctx, time.Now(), maxReapBatch)
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to reap expired task leases")
+		return
+	}
+
+	for _, claim := range claims {
+		logger.WithFields(map[string]interface{}{
+			"task_id":    claim.TaskID,
+			"task_type":  claim.Type,
+			"worker_id":  claim.WorkerID,
+			"queue":      claim.Queue,
+			"claimed_at": claim.ClaimedAt,
+		}).Warn("Task lease expired, task requeued")
+	}
+}
+
+// returnClaims expires the leases of tasks the worker still holds after stopping, so they are
+// requeued right away instead of waiting for the lease to run out
+func (w *Worker) returnClaims() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	w.renewLeases(ctx, time.Now().Add(-time.Millisecond))
+	w.reapExpiredLeases()
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"tachyon-messenger/shared/redis"
+)
+
+// newTestRedis returns a client of an in-memory Redis server that lives for the test
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client, err := redis.ConnectRedis(redis.DefaultConfig("redis://" + server.Addr()))
+	if err != nil {
+		t.Fatalf("failed to connect to test Redis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// newTestWorker returns a worker that isn't started, so leases are only taken and renewed by the test
+func newTestWorker(redisClient *redis.Client, id string) *Worker {
+	config := DefaultWorkerConfig()
+	config.WorkerID = id
+	config.LeaseDuration = time.Minute
+	return NewNotificationWorker(nil, redisClient, config)
+}
+
+// pushTask queues a task with the ID on the main queue
+func pushTask(t *testing.T, redisClient *redis.Client, config *WorkerConfig, id string) {
+	t.Helper()
+
+	if err := redisClient.LPush(context.Background(), config.QueueName, `{"id":"`+id+`","type":"notification"}`).Err(); err != nil {
+		t.Fatalf("failed to queue task: %v", err)
+	}
+}
+
+func TestClaimIsExclusive(t *testing.T) {
+	ctx := context.Background()
+	redisClient := newTestRedis(t)
+	first, second := newTestWorker(redisClient, "worker-1"), newTestWorker(redisClient, "worker-2")
+	pushTask(t, redisClient, first.config, "task-1")
+
+	data, err := first.claimFromQueue(ctx, first.config.QueueName)
+	if err != nil {
+		t.Fatalf("claimFromQueue failed: %v", err)
+	}
+	if data == "" {
+		t.Fatal("expected the task data")
+	}
+	if _, err := second.claimFromQueue(ctx, second.config.QueueName); err != goredis.Nil {
+		t.Fatalf("expected the second worker to find no task, got %v", err)
+	}
+
+	claims, err := NewQueueManager(redisClient, first.config).GetClaims(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetClaims failed: %v", err)
+	}
+	if len(claims) != 1 || claims[0].TaskID != "task-1" || claims[0].WorkerID != "worker-1" || claims[0].Expired {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+	if until := claims[0].LeaseUntil; until.Before(time.Now().Add(50 * time.Second)) {
+		t.Errorf("lease until %v, expected about a minute from now", until)
+	}
+}
+
+func TestExpiredLeaseIsReaped(t *testing.T) {
+	ctx := context.Background()
+	redisClient := newTestRedis(t)
+	first, second := newTestWorker(redisClient, "worker-1"), newTestWorker(redisClient, "worker-2")
+	queueManager := NewQueueManager(redisClient, first.config)
+	pushTask(t, redisClient, first.config, "task-1")
+
+	if _, err := first.claimFromQueue(ctx, first.config.QueueName); err != nil {
+		t.Fatalf("claimFromQueue failed: %v", err)
+	}
+
+	// A lease that hasn't expired stays with its worker
+	claims, err := queueManager.ReapExpiredLeases(ctx, time.Now(), maxReapBatch)
+	if err != nil {
+		t.Fatalf("ReapExpiredLeases failed: %v", err)
+	}
+	if len(claims) != 0 {
+		t.Fatalf("expected no reaped claims, got %+v", claims)
+	}
+
+	claims, err = queueManager.ReapExpiredLeases(ctx, time.Now().Add(2*time.Minute), maxReapBatch)
+	if err != nil {
+		t.Fatalf("ReapExpiredLeases failed: %v", err)
+	}
+	if len(claims) != 1 || claims[0].TaskID != "task-1" || claims[0].WorkerID != "worker-1" || !claims[0].Expired {
+		t.Fatalf("unexpected reaped claims: %+v", claims)
+	}
+
+	if length := redisClient.LLen(ctx, first.config.QueueName).Val(); length != 1 {
+		t.Errorf("expected the task back in the queue, queue length %d", length)
+	}
+	if held := redisClient.HLen(ctx, queueManager.processingKey()).Val(); held != 0 {
+		t.Errorf("expected no claims after reaping, got %d", held)
+	}
+	state, err := NewTaskStatusStore(redisClient, first.config).Get(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("failed to get task status: %v", err)
+	}
+	if state.Status != TaskStatusQueued {
+		t.Errorf("task status = %s, want %s", state.Status, TaskStatusQueued)
+	}
+
+	// The requeued task can be claimed by another worker
+	if _, err := second.claimFromQueue(ctx, second.config.QueueName); err != nil {
+		t.Fatalf("expected another worker to claim the requeued task: %v", err)
+	}
+	claims, err = queueManager.GetClaims(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetClaims failed: %v", err)
+	}
+	if len(claims) != 1 || claims[0].WorkerID != "worker-2" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestLeaseOfAnotherWorker(t *testing.T) {
+	ctx := context.Background()
+	redisClient := newTestRedis(t)
+	owner, other := newTestWorker(redisClient, "worker-1"), newTestWorker(redisClient, "worker-2")
+	queueManager := NewQueueManager(redisClient, owner.config)
+	pushTask(t, redisClient, owner.config, "task-1")
+
+	if _, err := owner.claimFromQueue(ctx, owner.config.QueueName); err != nil {
+		t.Fatalf("claimFromQueue failed: %v", err)
+	}
+	owner.holdClaim("task-1")
+	leaseUntil := redisClient.ZScore(ctx, queueManager.leasesKey(), "task-1").Val()
+
+	// Another worker can't renew the lease and forgets the task as lost
+	other.holdClaim("task-1")
+	other.renewLeases(ctx, time.Now().Add(time.Hour))
+	if score := redisClient.ZScore(ctx, queueManager.leasesKey(), "task-1").Val(); score != leaseUntil {
+		t.Errorf("lease renewed by another worker: %v, want %v", score, leaseUntil)
+	}
+	if len(other.heldClaims()) != 0 {
+		t.Error("expected the other worker to drop the task it doesn't hold")
+	}
+
+	// Nor release it
+	other.releaseClaim("task-1")
+	if !redisClient.HExists(ctx, queueManager.processingKey(), "task-1").Val() {
+		t.Fatal("claim released by another worker")
+	}
+
+	// The owner renews and releases its lease
+	until := time.Now().Add(time.Hour)
+	owner.renewLeases(ctx, until)
+	if score := redisClient.ZScore(ctx, queueManager.leasesKey(), "task-1").Val(); int64(score) != until.UnixMilli() {
+		t.Errorf("lease = %v, want %v", int64(score), until.UnixMilli())
+	}
+	owner.releaseClaim("task-1")
+	if redisClient.HExists(ctx, queueManager.processingKey(), "task-1").Val() {
+		t.Error("expected the owner to release the claim")
+	}
+	if count := redisClient.ZCard(ctx, queueManager.leasesKey()).Val(); count != 0 {
+		t.Errorf("expected no leases after release, got %d", count)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -27,12 +29,21 @@ type NotificationTask struct {
 	BulkNotification      *models.BulkCreateNotificationRequest `json:"bulk_notification,omitempty"`
 	TemplatedNotification *usecase.TemplatedNotificationRequest `json:"templated_notification,omitempty"`
 	SystemAnnouncement    *usecase.SystemAnnouncementRequest    `json:"system_announcement,omitempty"`
+	NotificationID        *uint                                 `json:"notification_id,omitempty"` // Existing notification for resend task
 	Priority              models.NotificationPriority           `json:"priority"`
 	CreatedAt             time.Time                             `json:"created_at"`
 	ScheduledAt           *time.Time                            `json:"scheduled_at,omitempty"`
 	AttemptCount          int                                   `json:"attempt_count"`
 	LastError             string                                `json:"last_error,omitempty"`
 	MaxRetries            int                                   `json:"max_retries"`
+	CallbackURL           string                                `json:"callback_url,omitempty" binding:"omitempty,url"` // Receives final task state
+	CorrelationID         string                                `json:"correlation_id,omitempty"`                       // User action that caused the task
+	ParentID              string                                `json:"parent_id,omitempty"`                            // Request that queued the task
+}
+
+// Correlation returns the correlation of the task for logging
+func (t *NotificationTask) Correlation() logger.Correlation {
+	return logger.Correlation{ID: t.CorrelationID, ParentID: t.ParentID, RequestID: t.ID}
 }
 
 // TaskType represents the type of notification task
@@ -45,22 +56,39 @@ const (
 	TaskTypeAnnouncement TaskType = "announcement" // System announcement
 	TaskTypeScheduled    TaskType = "scheduled"    // Scheduled notification
 	TaskTypeRetry        TaskType = "retry"        // Retry failed notification
+	TaskTypeResend       TaskType = "resend"       // Resend existing failed notification
 )
 
 // Worker represents the notification worker
 type Worker struct {
-	id                string
-	ctx               context.Context
-	cancel            context.CancelFunc
-	notificationUC    usecase.NotificationUsecase
-	redisClient       *redis.Client
-	taskChan          chan *NotificationTask
-	retryTaskChan     chan *NotificationTask
-	scheduledTaskChan chan *NotificationTask
-	wg                sync.WaitGroup
-	config            *WorkerConfig
-	isRunning         bool
-	mu                sync.RWMutex
+	id             string
+	ctx            context.Context
+	cancel         context.CancelFunc
+	notificationUC usecase.NotificationUsecase
+	redisClient    *redis.Client
+	taskChan       chan *NotificationTask
+	retryTaskChan  chan *NotificationTask
+	scheduledWake  chan struct{} // Signals that the scheduled queue head may have changed
+	scheduledCount atomic.Int64  // Scheduled queue length seen by the dispatcher
+	taskStatus     *TaskStatusStore
+	wg             sync.WaitGroup
+	config         *WorkerConfig
+	isRunning      bool
+	mu             sync.RWMutex
+
+	// IDs of claimed tasks whose leases the worker renews, guarded by claimsMu
+	claimsMu sync.Mutex
+	claims   map[string]struct{}
+
+	// Autoscaling state, guarded by scaleMu
+	scaleMu        sync.Mutex
+	processors     []chan struct{} // Stop channels of running task processors
+	processorSeq   int
+	desired        int
+	backlog        int64
+	avgLatency     time.Duration
+	processedTasks int64
+	lastScaleAt    time.Time
 }
 
 // WorkerConfig holds worker configuration
@@ -69,16 +97,40 @@ type WorkerConfig struct {
 	ConcurrentWorkers    int           `json:"concurrent_workers"`
 	TaskChannelSize      int           `json:"task_channel_size"`
 	RetryChannelSize     int           `json:"retry_channel_size"`
-	ScheduledChannelSize int           `json:"scheduled_channel_size"`
 	RedisKeyPrefix       string        `json:"redis_key_prefix"`
 	QueueName            string        `json:"queue_name"`
 	RetryQueueName       string        `json:"retry_queue_name"`
 	ScheduledQueueName   string        `json:"scheduled_queue_name"`
+	ScheduledWakeChannel string        `json:"scheduled_wake_channel"` // Pub/sub channel announcing new scheduled tasks
+	ScheduledMaxSleep    time.Duration `json:"scheduled_max_sleep"`    // Longest dispatcher sleep without a wake-up
 	ProcessingTimeout    time.Duration `json:"processing_timeout"`
+	LeaseDuration        time.Duration `json:"lease_duration"`      // How long a claimed task stays with a worker without renewal
+	LeaseReapInterval    time.Duration `json:"lease_reap_interval"` // How often tasks with expired leases are requeued
 	RetryDelay           time.Duration `json:"retry_delay"`
 	MaxRetries           int           `json:"max_retries"`
 	HealthCheckInterval  time.Duration `json:"health_check_interval"`
 	CleanupInterval      time.Duration `json:"cleanup_interval"`
+	TaskStatusTTL        time.Duration `json:"task_status_ttl"`   // How long task lifecycle states are kept
+	FallbackInterval     time.Duration `json:"fallback_interval"` // How often due channel fallback steps are checked
+
+	// Autoscaling: ConcurrentWorkers is the initial pool size, scaled within
+	// MinConcurrentWorkers..MaxConcurrentWorkers by backlog and processing latency
+	MinConcurrentWorkers   int           `json:"min_concurrent_workers"`
+	MaxConcurrentWorkers   int           `json:"max_concurrent_workers"`
+	TargetBacklogPerWorker int           `json:"target_backlog_per_worker"`
+	TargetLatency          time.Duration `json:"target_latency"`
+	ScaleInterval          time.Duration `json:"scale_interval"`
+	ScaleDownCooldown      time.Duration `json:"scale_down_cooldown"`
+
+	// Admission control: above BacklogSoftLimit producers are asked to slow down,
+	// above BacklogHardLimit low-priority tasks are rejected. Zero disables a limit.
+	BacklogSoftLimit int `json:"backlog_soft_limit"`
+	BacklogHardLimit int `json:"backlog_hard_limit"`
+}
+
+// AutoscalingEnabled returns whether concurrency can change at runtime
+func (c *WorkerConfig) AutoscalingEnabled() bool {
+	return c.MaxConcurrentWorkers > c.MinConcurrentWorkers
 }
 
 // DefaultWorkerConfig returns default worker configuration
@@ -89,16 +141,31 @@ func DefaultWorkerConfig() *WorkerConfig {
 		ConcurrentWorkers:    5,
 		TaskChannelSize:      1000,
 		RetryChannelSize:     500,
-		ScheduledChannelSize: 500,
 		RedisKeyPrefix:       "tachyon:notification",
 		QueueName:            "notifications:queue",
 		RetryQueueName:       "notifications:retry",
 		ScheduledQueueName:   "notifications:scheduled",
+		ScheduledWakeChannel: "notifications:scheduled:wake",
+		ScheduledMaxSleep:    30 * time.Second,
 		ProcessingTimeout:    30 * time.Second,
+		LeaseDuration:        time.Minute,
+		LeaseReapInterval:    15 * time.Second,
 		RetryDelay:           30 * time.Second,
 		MaxRetries:           3,
 		HealthCheckInterval:  30 * time.Second,
 		CleanupInterval:      5 * time.Minute,
+		TaskStatusTTL:        7 * 24 * time.Hour,
+		FallbackInterval:     30 * time.Second,
+
+		MinConcurrentWorkers:   5,
+		MaxConcurrentWorkers:   5,
+		TargetBacklogPerWorker: 20,
+		TargetLatency:          5 * time.Second,
+		ScaleInterval:          10 * time.Second,
+		ScaleDownCooldown:      time.Minute,
+
+		BacklogSoftLimit: 1000,
+		BacklogHardLimit: 5000,
 	}
 }
 
@@ -112,19 +179,44 @@ func NewNotificationWorker(
 		config = DefaultWorkerConfig()
 	}
 
+	// Keep concurrency limits consistent with the initial pool size
+	if config.MinConcurrentWorkers <= 0 || config.MinConcurrentWorkers > config.ConcurrentWorkers {
+		config.MinConcurrentWorkers = config.ConcurrentWorkers
+	}
+	if config.MaxConcurrentWorkers < config.ConcurrentWorkers {
+		config.MaxConcurrentWorkers = config.ConcurrentWorkers
+	}
+	if config.ScaleInterval <= 0 {
+		config.ScaleInterval = 10 * time.Second
+	}
+	if config.ScheduledMaxSleep <= 0 {
+		config.ScheduledMaxSleep = 30 * time.Second
+	}
+	if config.FallbackInterval <= 0 {
+		config.FallbackInterval = 30 * time.Second
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = 2 * config.ProcessingTimeout
+	}
+	if config.LeaseReapInterval <= 0 {
+		config.LeaseReapInterval = config.LeaseDuration / 4
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Worker{
-		id:                config.WorkerID,
-		ctx:               ctx,
-		cancel:            cancel,
-		notificationUC:    notificationUC,
-		redisClient:       redisClient,
-		taskChan:          make(chan *NotificationTask, config.TaskChannelSize),
-		retryTaskChan:     make(chan *NotificationTask, config.RetryChannelSize),
-		scheduledTaskChan: make(chan *NotificationTask, config.ScheduledChannelSize),
-		config:            config,
-		isRunning:         false,
+		id:             config.WorkerID,
+		ctx:            ctx,
+		cancel:         cancel,
+		notificationUC: notificationUC,
+		redisClient:    redisClient,
+		taskChan:       make(chan *NotificationTask, config.TaskChannelSize),
+		retryTaskChan:  make(chan *NotificationTask, config.RetryChannelSize),
+		scheduledWake:  make(chan struct{}, 1),
+		taskStatus:     NewTaskStatusStore(redisClient, config),
+		config:         config,
+		isRunning:      false,
+		claims:         make(map[string]struct{}),
 	}
 }
 
@@ -148,23 +240,38 @@ func (w *Worker) Start() error {
 	w.startBackgroundTasks()
 
 	// Start worker goroutines
+	w.scaleMu.Lock()
 	for i := 0; i < w.config.ConcurrentWorkers; i++ {
-		w.wg.Add(1)
-		go w.taskProcessor(i + 1)
+		w.startProcessor()
 	}
+	w.desired = w.config.ConcurrentWorkers
+	w.scaleMu.Unlock()
+
+	// Start autoscaler
+	w.wg.Add(1)
+	go w.autoscaler()
 
 	// Start retry processor
 	w.wg.Add(1)
 	go w.retryProcessor()
 
-	// Start scheduled task processor
-	w.wg.Add(1)
-	go w.scheduledTaskProcessor()
+	// Start scheduled task dispatcher and its cross-instance wake-ups
+	w.wg.Add(2)
+	go w.scheduledTaskDispatcher()
+	go w.scheduledWakeSubscriber()
 
 	// Start queue consumers
 	w.wg.Add(1)
 	go w.queueConsumer()
 
+	// Start channel fallback chains
+	w.wg.Add(1)
+	go w.fallbackProcessor()
+
+	// Start lease renewal and reaping of tasks held by dead workers
+	w.wg.Add(1)
+	go w.leaseKeeper()
+
 	w.isRunning = true
 
 	logger.WithField("worker_id", w.id).Info("Notification worker started successfully")
@@ -188,7 +295,6 @@ func (w *Worker) Stop() error {
 	// Close channels
 	close(w.taskChan)
 	close(w.retryTaskChan)
-	close(w.scheduledTaskChan)
 
 	// Wait for all goroutines to finish with timeout
 	done := make(chan struct{})
@@ -204,6 +310,9 @@ func (w *Worker) Stop() error {
 		logger.WithField("worker_id", w.id).Warn("Worker shutdown timeout exceeded")
 	}
 
+	// Tasks left in the channels go back to their queues
+	w.returnClaims()
+
 	// Unregister worker from Redis
 	if err := w.unregisterWorker(); err != nil {
 		logger.WithFields(map[string]interface{}{
@@ -217,11 +326,17 @@ func (w *Worker) Stop() error {
 	return nil
 }
 
-// AddTask adds a notification task to the queue
-func (w *Worker) AddTask(task *NotificationTask) error {
+// AddTask adds a notification task to the queue. Tasks without a correlation ID are correlated
+// with the request carried by ctx.
+func (w *Worker) AddTask(ctx context.Context, task *NotificationTask) error {
 	if task == nil {
 		return fmt.Errorf("task cannot be nil")
 	}
+	if task.CorrelationID == "" {
+		correlation := logger.CorrelationFromContext(ctx)
+		task.CorrelationID = correlation.ID
+		task.ParentID = correlation.RequestID
+	}
 
 	// Set default values
 	if task.ID == "" {
@@ -240,11 +355,22 @@ func (w *Worker) AddTask(task *NotificationTask) error {
 		return fmt.Errorf("failed to serialize task: %w", err)
 	}
 
-	// Determine which queue to use
-	queueName := w.config.QueueName
+	// Future tasks wait in the scheduled queue
 	if task.ScheduledAt != nil && task.ScheduledAt.After(time.Now()) {
-		queueName = w.config.ScheduledQueueName
+		if err := w.scheduleTask(task, taskData); err != nil {
+			return fmt.Errorf("failed to add task to scheduled queue: %w", err)
+		}
+
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
+			"task_id":      task.ID,
+			"task_type":    task.Type,
+			"scheduled_at": task.ScheduledAt,
+		}).Info("Task scheduled")
+
+		w.setTaskStatus(task, TaskStatusScheduled)
+		return nil
 	}
+	queueName := w.config.QueueName
 
 	// Add to Redis queue
 	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
@@ -254,12 +380,14 @@ func (w *Worker) AddTask(task *NotificationTask) error {
 		return fmt.Errorf("failed to add task to queue: %w", err)
 	}
 
-	logger.WithFields(map[string]interface{}{
+	logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 		"task_id":   task.ID,
 		"task_type": task.Type,
 		"queue":     queueName,
 	}).Info("Task added to queue")
 
+	w.setTaskStatus(task, TaskStatusQueued)
+
 	return nil
 }
 
@@ -267,7 +395,7 @@ func (w *Worker) AddTask(task *NotificationTask) error {
 func (w *Worker) ProcessNotification(task *NotificationTask) error {
 	startTime := time.Now()
 
-	logger.WithFields(map[string]interface{}{
+	logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 		"task_id":     task.ID,
 		"task_type":   task.Type,
 		"attempt":     task.AttemptCount + 1,
@@ -311,6 +439,12 @@ func (w *Worker) ProcessNotification(task *NotificationTask) error {
 			return fmt.Errorf("no valid notification data for scheduled task")
 		}
 
+	case TaskTypeResend:
+		if task.NotificationID == nil {
+			return fmt.Errorf("notification ID is required for resend task")
+		}
+		err = w.notificationUC.ResendNotification(*task.NotificationID)
+
 	case TaskTypeRetry:
 		// Same processing as original task type
 		return w.ProcessNotification(&NotificationTask{
@@ -333,7 +467,7 @@ func (w *Worker) ProcessNotification(task *NotificationTask) error {
 	duration := time.Since(startTime)
 
 	if err != nil {
-		logger.WithFields(map[string]interface{}{
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 			"task_id":     task.ID,
 			"task_type":   task.Type,
 			"attempt":     task.AttemptCount + 1,
@@ -344,7 +478,7 @@ func (w *Worker) ProcessNotification(task *NotificationTask) error {
 		return err
 	}
 
-	logger.WithFields(map[string]interface{}{
+	logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 		"task_id":     task.ID,
 		"task_type":   task.Type,
 		"attempt":     task.AttemptCount + 1,
@@ -354,8 +488,8 @@ func (w *Worker) ProcessNotification(task *NotificationTask) error {
 	return nil
 }
 
-// taskProcessor processes tasks from the task channel
-func (w *Worker) taskProcessor(workerNum int) {
+// taskProcessor processes tasks from the task channel until stop is closed on scale down
+func (w *Worker) taskProcessor(workerNum int, stop <-chan struct{}) {
 	defer w.wg.Done()
 
 	logger.WithFields(map[string]interface{}{
@@ -373,6 +507,10 @@ func (w *Worker) taskProcessor(workerNum int) {
 
 			w.processTaskWithTimeout(task)
 
+		case <-stop:
+			logger.WithField("worker_num", workerNum).Info("Scaled down, stopping processor")
+			return
+
 		case <-w.ctx.Done():
 			logger.WithField("worker_num", workerNum).Info("Context cancelled, stopping processor")
 			return
@@ -397,7 +535,7 @@ func (w *Worker) retryProcessor() {
 			// Wait for retry delay
 			if task.AttemptCount > 0 {
 				delay := w.calculateRetryDelay(task.AttemptCount)
-				logger.WithFields(map[string]interface{}{
+				logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 					"task_id": task.ID,
 					"delay":   delay,
 					"attempt": task.AttemptCount + 1,
@@ -420,43 +558,6 @@ func (w *Worker) retryProcessor() {
 	}
 }
 
-// scheduledTaskProcessor processes scheduled tasks
-func (w *Worker) scheduledTaskProcessor() {
-	defer w.wg.Done()
-
-	logger.WithField("worker_id", w.id).Info("Scheduled task processor started")
-
-	ticker := time.NewTicker(30 * time.Second) // Check every 30 seconds
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			w.processScheduledTasks()
-
-		case task, ok := <-w.scheduledTaskChan:
-			if !ok {
-				logger.Info("Scheduled channel closed, stopping scheduled processor")
-				return
-			}
-
-			// Check if it's time to process the task
-			if task.ScheduledAt != nil && task.ScheduledAt.After(time.Now()) {
-				// Not yet time, put it back in the scheduled queue
-				w.addToScheduledQueue(task)
-				continue
-			}
-
-			// Process immediately
-			w.processTaskWithTimeout(task)
-
-		case <-w.ctx.Done():
-			logger.Info("Context cancelled, stopping scheduled processor")
-			return
-		}
-	}
-}
-
 // queueConsumer consumes tasks from Redis queues
 func (w *Worker) queueConsumer() {
 	defer w.wg.Done()
@@ -480,6 +581,7 @@ func (w *Worker) queueConsumer() {
 				default:
 					// Channel is full, put task back to queue
 					w.addToQueue(w.config.QueueName, task)
+					w.releaseClaim(task.ID)
 				}
 				continue
 			}
@@ -494,6 +596,7 @@ func (w *Worker) queueConsumer() {
 				default:
 					// Channel is full, put task back to queue
 					w.addToQueue(w.config.RetryQueueName, task)
+					w.releaseClaim(task.ID)
 				}
 				continue
 			}
@@ -509,6 +612,11 @@ func (w *Worker) processTaskWithTimeout(task *NotificationTask) {
 	ctx, cancel := context.WithTimeout(w.ctx, w.config.ProcessingTimeout)
 	defer cancel()
 
+	w.setTaskStatus(task, TaskStatusProcessing)
+
+	startTime := time.Now()
+	defer func() { w.recordLatency(time.Since(startTime)) }()
+
 	done := make(chan error, 1)
 	go func() {
 		done <- w.ProcessNotification(task)
@@ -524,7 +632,7 @@ func (w *Worker) processTaskWithTimeout(task *NotificationTask) {
 
 	case <-ctx.Done():
 		err := fmt.Errorf("task processing timeout")
-		logger.WithFields(map[string]interface{}{
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 			"task_id": task.ID,
 			"timeout": w.config.ProcessingTimeout,
 		}).Error("Task processing timed out")
@@ -535,14 +643,16 @@ func (w *Worker) processTaskWithTimeout(task *NotificationTask) {
 
 // handleTaskSuccess handles successful task completion
 func (w *Worker) handleTaskSuccess(task *NotificationTask) {
-	logger.WithFields(map[string]interface{}{
+	logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 		"task_id":   task.ID,
 		"task_type": task.Type,
 		"attempts":  task.AttemptCount + 1,
 	}).Info("Task completed successfully")
 
-	// Remove from processing set
-	w.removeFromProcessingSet(task.ID)
+	task.AttemptCount++
+	w.setTaskStatus(task, TaskStatusDone)
+
+	w.releaseClaim(task.ID)
 }
 
 // handleTaskError handles task processing errors
@@ -551,7 +661,7 @@ func (w *Worker) handleTaskError(task *NotificationTask, err error) {
 	task.LastError = err.Error()
 
 	if task.AttemptCount >= task.MaxRetries {
-		logger.WithFields(map[string]interface{}{
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 			"task_id":     task.ID,
 			"task_type":   task.Type,
 			"attempts":    task.AttemptCount,
@@ -561,11 +671,12 @@ func (w *Worker) handleTaskError(task *NotificationTask, err error) {
 
 		// Move to dead letter queue
 		w.addToDeadLetterQueue(task)
-		w.removeFromProcessingSet(task.ID)
+		w.setTaskStatus(task, TaskStatusDeadLetter)
+		w.releaseClaim(task.ID)
 		return
 	}
 
-	logger.WithFields(map[string]interface{}{
+	logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 		"task_id":     task.ID,
 		"task_type":   task.Type,
 		"attempts":    task.AttemptCount,
@@ -576,15 +687,17 @@ func (w *Worker) handleTaskError(task *NotificationTask, err error) {
 	// Add to retry queue
 	task.Type = TaskTypeRetry
 	w.addToQueue(w.config.RetryQueueName, task)
-	w.removeFromProcessingSet(task.ID)
+	w.setTaskStatus(task, TaskStatusFailed)
+	w.releaseClaim(task.ID)
 }
 
-// consumeFromQueue consumes a task from Redis queue
+// consumeFromQueue claims a task from Redis queue, the task stays leased to the worker
+// until it is released
 func (w *Worker) consumeFromQueue(queueName string) *NotificationTask {
 	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
 	defer cancel()
 
-	result, err := w.redisClient.BRPop(ctx, 1*time.Second, queueName).Result()
+	taskData, err := w.claimFromQueue(ctx, queueName)
 	if err != nil {
 		if err != goredis.Nil {
 			logger.WithFields(map[string]interface{}{
@@ -595,22 +708,25 @@ func (w *Worker) consumeFromQueue(queueName string) *NotificationTask {
 		return nil
 	}
 
-	if len(result) < 2 {
-		return nil
-	}
-
 	var task NotificationTask
-	if err := json.Unmarshal([]byte(result[1]), &task); err != nil {
+	if err := json.Unmarshal([]byte(taskData), &task); err != nil {
 		logger.WithFields(map[string]interface{}{
 			"queue": queueName,
 			"error": err.Error(),
-			"data":  result[1],
+			"data":  taskData,
 		}).Error("Failed to unmarshal task")
+
+		// Drop the claim, otherwise the reaper would requeue the broken task forever
+		var ref struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal([]byte(taskData), &ref) == nil && ref.ID != "" {
+			w.releaseClaim(ref.ID)
+		}
 		return nil
 	}
 
-	// Add to processing set for tracking
-	w.addToProcessingSet(&task)
+	w.holdClaim(task.ID)
 
 	return &task
 }
@@ -619,7 +735,7 @@ func (w *Worker) consumeFromQueue(queueName string) *NotificationTask {
 func (w *Worker) addToQueue(queueName string, task *NotificationTask) {
 	taskData, err := json.Marshal(task)
 	if err != nil {
-		logger.WithFields(map[string]interface{}{
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 			"task_id": task.ID,
 			"error":   err.Error(),
 		}).Error("Failed to marshal task for queue")
@@ -630,7 +746,7 @@ func (w *Worker) addToQueue(queueName string, task *NotificationTask) {
 	defer cancel()
 
 	if err := w.redisClient.LPush(ctx, queueName, taskData).Err(); err != nil {
-		logger.WithFields(map[string]interface{}{
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 			"task_id": task.ID,
 			"queue":   queueName,
 			"error":   err.Error(),
@@ -638,44 +754,12 @@ func (w *Worker) addToQueue(queueName string, task *NotificationTask) {
 	}
 }
 
-// addToScheduledQueue adds a task to scheduled queue with delay
-func (w *Worker) addToScheduledQueue(task *NotificationTask) {
-	if task.ScheduledAt == nil {
-		w.addToQueue(w.config.QueueName, task)
-		return
-	}
-
-	taskData, err := json.Marshal(task)
-	if err != nil {
-		logger.WithFields(map[string]interface{}{
-			"task_id": task.ID,
-			"error":   err.Error(),
-		}).Error("Failed to marshal scheduled task")
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
-	defer cancel()
-
-	score := float64(task.ScheduledAt.Unix())
-	if err := w.redisClient.ZAdd(ctx, w.config.ScheduledQueueName, goredis.Z{
-		Score:  score,
-		Member: taskData,
-	}).Err(); err != nil {
-		logger.WithFields(map[string]interface{}{
-			"task_id":      task.ID,
-			"scheduled_at": task.ScheduledAt,
-			"error":        err.Error(),
-		}).Error("Failed to add scheduled task")
-	}
-}
-
 // addToDeadLetterQueue adds a failed task to dead letter queue
 func (w *Worker) addToDeadLetterQueue(task *NotificationTask) {
 	deadLetterQueue := w.config.RedisKeyPrefix + ":dead_letter"
 	taskData, err := json.Marshal(task)
 	if err != nil {
-		logger.WithFields(map[string]interface{}{
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 			"task_id": task.ID,
 			"error":   err.Error(),
 		}).Error("Failed to marshal dead letter task")
@@ -686,19 +770,19 @@ func (w *Worker) addToDeadLetterQueue(task *NotificationTask) {
 	defer cancel()
 
 	if err := w.redisClient.LPush(ctx, deadLetterQueue, taskData).Err(); err != nil {
-		logger.WithFields(map[string]interface{}{
+		logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 			"task_id": task.ID,
 			"error":   err.Error(),
 		}).Error("Failed to add task to dead letter queue")
 	}
 }
 
-// processScheduledTasks processes tasks that are ready to be executed
+// processScheduledTasks moves tasks that are due from the scheduled queue to the main queue
 func (w *Worker) processScheduledTasks() {
 	ctx, cancel := context.WithTimeout(w.ctx, 10*time.Second)
 	defer cancel()
 
-	now := time.Now().Unix()
+	now := time.Now().UnixMilli()
 	result, err := w.redisClient.ZRangeByScore(ctx, w.config.ScheduledQueueName, &goredis.ZRangeBy{
 		Min:    "0",
 		Max:    fmt.Sprintf("%d", now),
@@ -723,18 +807,16 @@ func (w *Worker) processScheduledTasks() {
 			continue
 		}
 
-		// Remove from scheduled queue
-		w.redisClient.ZRem(ctx, w.config.ScheduledQueueName, taskData)
+		// Remove from scheduled queue, another instance may have claimed it first
+		removed, err := w.redisClient.ZRem(ctx, w.config.ScheduledQueueName, taskData).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
 
 		// Add to main processing queue
 		task.Type = TaskTypeScheduled
-		select {
-		case w.scheduledTaskChan <- &task:
-			// Task sent to scheduled channel
-		default:
-			// Channel is full, add back to main queue
-			w.addToQueue(w.config.QueueName, &task)
-		}
+		w.addToQueue(w.config.QueueName, &task)
+		w.setTaskStatus(&task, TaskStatusQueued)
 	}
 
 	if len(result) > 0 {
@@ -744,33 +826,6 @@ func (w *Worker) processScheduledTasks() {
 	}
 }
 
-// addToProcessingSet adds task to processing set for tracking
-func (w *Worker) addToProcessingSet(task *NotificationTask) {
-	processingKey := w.config.RedisKeyPrefix + ":processing"
-	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
-	defer cancel()
-
-	taskInfo := map[string]interface{}{
-		"id":         task.ID,
-		"type":       task.Type,
-		"worker_id":  w.id,
-		"started_at": time.Now().Unix(),
-	}
-
-	taskData, _ := json.Marshal(taskInfo)
-	w.redisClient.HSet(ctx, processingKey, task.ID, taskData)
-	w.redisClient.Expire(ctx, processingKey, w.config.ProcessingTimeout*2)
-}
-
-// removeFromProcessingSet removes task from processing set
-func (w *Worker) removeFromProcessingSet(taskID string) {
-	processingKey := w.config.RedisKeyPrefix + ":processing"
-	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
-	defer cancel()
-
-	w.redisClient.HDel(ctx, processingKey, taskID)
-}
-
 // registerWorker registers worker in Redis
 func (w *Worker) registerWorker() error {
 	workerKey := w.config.RedisKeyPrefix + ":workers"
@@ -781,6 +836,9 @@ func (w *Worker) registerWorker() error {
 		"id":                 w.id,
 		"started_at":         time.Now().Unix(),
 		"concurrent_workers": w.config.ConcurrentWorkers,
+		"min_workers":        w.config.MinConcurrentWorkers,
+		"max_workers":        w.config.MaxConcurrentWorkers,
+		"queues":             w.queues(),
 		"status":             "running",
 	}
 
@@ -847,20 +905,29 @@ func (w *Worker) updateWorkerHealth() {
 	workerInfo := map[string]interface{}{
 		"id":                   w.id,
 		"last_heartbeat":       time.Now().Unix(),
-		"concurrent_workers":   w.config.ConcurrentWorkers,
+		"concurrent_workers":   w.Concurrency(),
 		"status":               "running",
 		"task_queue_size":      len(w.taskChan),
 		"retry_queue_size":     len(w.retryTaskChan),
-		"scheduled_queue_size": len(w.scheduledTaskChan),
+		"scheduled_queue_size": w.scheduledCount.Load(),
+		"queues":               w.queues(),
 	}
 
 	workerData, _ := json.Marshal(workerInfo)
 	w.redisClient.HSet(ctx, workerKey, w.id, workerData)
 }
 
-// cleanupOldTasks cleans up old completed and failed tasks
+// queues returns the queue keys the worker consumes, reported with heartbeats so queue
+// migrations can tell whether old keys are still in use
+func (w *Worker) queues() []string {
+	return []string{w.config.QueueName, w.config.RetryQueueName, w.config.ScheduledQueueName}
+}
+
+// cleanupOldTasks cleans up processing entries left by workers that predate task leases, leased
+// tasks are requeued by the reaper, see leaseKeeper. Old dead letter tasks are removed by a
+// scheduled job according to the retention policy, see QueueManager.CleanupDeadLetterTasks.
 func (w *Worker) cleanupOldTasks() {
-	// Clean up processing set (remove stuck tasks)
+	// Clean up processing set (remove stuck tasks without a lease)
 	processingKey := w.config.RedisKeyPrefix + ":processing"
 	ctx, cancel := context.WithTimeout(w.ctx, 10*time.Second)
 	defer cancel()
@@ -899,36 +966,6 @@ func (w *Worker) cleanupOldTasks() {
 			"stuck_tasks": len(stuckTasks),
 		}).Info("Cleaned up stuck tasks")
 	}
-
-	// Clean up old dead letter tasks (older than 7 days)
-	deadLetterQueue := w.config.RedisKeyPrefix + ":dead_letter"
-	deadLetterTasks, err := w.redisClient.LRange(ctx, deadLetterQueue, 0, -1).Result()
-	if err != nil {
-		return
-	}
-
-	cutoffTime := time.Now().Add(-7 * 24 * time.Hour)
-	tasksToRemove := 0
-
-	for _, taskDataStr := range deadLetterTasks {
-		var task NotificationTask
-		if err := json.Unmarshal([]byte(taskDataStr), &task); err != nil {
-			continue
-		}
-
-		if task.CreatedAt.Before(cutoffTime) {
-			tasksToRemove++
-		} else {
-			break // Tasks are ordered by creation time
-		}
-	}
-
-	if tasksToRemove > 0 {
-		w.redisClient.LTrim(ctx, deadLetterQueue, int64(tasksToRemove), -1)
-		logger.WithFields(map[string]interface{}{
-			"removed_tasks": tasksToRemove,
-		}).Info("Cleaned up old dead letter tasks")
-	}
 }
 
 // calculateRetryDelay calculates retry delay with exponential backoff
@@ -977,13 +1014,23 @@ func (w *Worker) GetStats() WorkerStats {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
+	w.scaleMu.Lock()
+	defer w.scaleMu.Unlock()
+
 	return WorkerStats{
-		WorkerID:           w.id,
-		IsRunning:          w.isRunning,
-		TaskQueueSize:      len(w.taskChan),
-		RetryQueueSize:     len(w.retryTaskChan),
-		ScheduledQueueSize: len(w.scheduledTaskChan),
-		ConcurrentWorkers:  w.config.ConcurrentWorkers,
+		WorkerID:                 w.id,
+		IsRunning:                w.isRunning,
+		TaskQueueSize:            len(w.taskChan),
+		RetryQueueSize:           len(w.retryTaskChan),
+		ScheduledQueueSize:       int(w.scheduledCount.Load()),
+		ConcurrentWorkers:        len(w.processors),
+		MinConcurrentWorkers:     w.config.MinConcurrentWorkers,
+		MaxConcurrentWorkers:     w.config.MaxConcurrentWorkers,
+		DesiredConcurrentWorkers: w.desired,
+		AutoscalingEnabled:       w.config.AutoscalingEnabled(),
+		Backlog:                  w.backlog,
+		AvgProcessingMs:          w.avgLatency.Milliseconds(),
+		ProcessedTasks:           w.processedTasks,
 	}
 }
 
@@ -995,6 +1042,15 @@ type WorkerStats struct {
 	RetryQueueSize     int    `json:"retry_queue_size"`
 	ScheduledQueueSize int    `json:"scheduled_queue_size"`
 	ConcurrentWorkers  int    `json:"concurrent_workers"`
+
+	// Autoscaling
+	MinConcurrentWorkers     int   `json:"min_concurrent_workers"`
+	MaxConcurrentWorkers     int   `json:"max_concurrent_workers"`
+	DesiredConcurrentWorkers int   `json:"desired_concurrent_workers"`
+	AutoscalingEnabled       bool  `json:"autoscaling_enabled"`
+	Backlog                  int64 `json:"backlog"`
+	AvgProcessingMs          int64 `json:"avg_processing_ms"`
+	ProcessedTasks           int64 `json:"processed_tasks"`
 }
 
 // GracefulShutdown handles graceful shutdown with signal handling
@@ -1074,6 +1130,13 @@ func (qm *QueueManager) GetQueueStats(ctx context.Context) (*QueueStats, error)
 	}
 	stats.ProcessingTasksCount = processingLen
 
+	// Get claimed tasks whose lease expired and that wait for the reaper
+	expiredLeases, err := qm.redisClient.ZCount(ctx, qm.leasesKey(), "-inf", fmt.Sprintf("%d", time.Now().UnixMilli())).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired leases count: %w", err)
+	}
+	stats.ExpiredLeasesCount = expiredLeases
+
 	// Get active workers count
 	workersKey := qm.config.RedisKeyPrefix + ":workers"
 	workersLen, err := qm.redisClient.HLen(ctx, workersKey).Result()
@@ -1085,6 +1148,64 @@ func (qm *QueueManager) GetQueueStats(ctx context.Context) (*QueueStats, error)
 	return stats, nil
 }
 
+// GetWorkerHeartbeats returns the last heartbeats of workers registered in Redis, oldest first.
+// A worker that missed three health checks is reported as stale, it has likely crashed
+// without unregistering.
+func (qm *QueueManager) GetWorkerHeartbeats(ctx context.Context, now time.Time) ([]*WorkerHeartbeat, error) {
+	workersKey := qm.config.RedisKeyPrefix + ":workers"
+	workers, err := qm.redisClient.HGetAll(ctx, workersKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workers: %w", err)
+	}
+
+	staleAfter := 3 * qm.config.HealthCheckInterval
+	heartbeats := make([]*WorkerHeartbeat, 0, len(workers))
+	for id, data := range workers {
+		var info struct {
+			Status             string   `json:"status"`
+			StartedAt          int64    `json:"started_at"`
+			LastHeartbeat      int64    `json:"last_heartbeat"`
+			ConcurrentWorkers  int      `json:"concurrent_workers"`
+			TaskQueueSize      int      `json:"task_queue_size"`
+			RetryQueueSize     int      `json:"retry_queue_size"`
+			ScheduledQueueSize int64    `json:"scheduled_queue_size"`
+			Queues             []string `json:"queues"`
+		}
+		if err := json.Unmarshal([]byte(data), &info); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"worker_id": id,
+				"error":     err.Error(),
+			}).Warn("Failed to unmarshal worker heartbeat")
+			continue
+		}
+
+		// Registration counts as the first heartbeat
+		lastSeen := info.LastHeartbeat
+		if lastSeen == 0 {
+			lastSeen = info.StartedAt
+		}
+		lastHeartbeat := time.Unix(lastSeen, 0)
+
+		heartbeats = append(heartbeats, &WorkerHeartbeat{
+			WorkerID:           id,
+			Status:             info.Status,
+			LastHeartbeat:      lastHeartbeat,
+			SecondsSince:       int64(now.Sub(lastHeartbeat).Seconds()),
+			Stale:              now.Sub(lastHeartbeat) > staleAfter,
+			ConcurrentWorkers:  info.ConcurrentWorkers,
+			TaskQueueSize:      info.TaskQueueSize,
+			RetryQueueSize:     info.RetryQueueSize,
+			ScheduledQueueSize: info.ScheduledQueueSize,
+			Queues:             info.Queues,
+		})
+	}
+
+	sort.Slice(heartbeats, func(i, j int) bool {
+		return heartbeats[i].LastHeartbeat.Before(heartbeats[j].LastHeartbeat)
+	})
+	return heartbeats, nil
+}
+
 // PurgeQueues removes all tasks from queues (use with caution)
 func (qm *QueueManager) PurgeQueues(ctx context.Context) error {
 	queues := []string{
@@ -1104,9 +1225,43 @@ func (qm *QueueManager) PurgeQueues(ctx context.Context) error {
 	return nil
 }
 
+// CleanupDeadLetterTasks removes dead letter tasks created before olderThan and returns their number.
+// Tasks are pushed to the head of the queue, so the oldest ones are trimmed from its tail.
+func (qm *QueueManager) CleanupDeadLetterTasks(ctx context.Context, olderThan time.Time) (int, error) {
+	deadLetterQueue := qm.config.RedisKeyPrefix + ":dead_letter"
+	deadLetterTasks, err := qm.redisClient.LRange(ctx, deadLetterQueue, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get dead letter tasks: %w", err)
+	}
+
+	tasksToRemove := 0
+	for i := len(deadLetterTasks) - 1; i >= 0; i-- {
+		var task NotificationTask
+		if err := json.Unmarshal([]byte(deadLetterTasks[i]), &task); err != nil {
+			tasksToRemove++ // Unreadable tasks cannot be requeued either
+			continue
+		}
+		if !task.CreatedAt.Before(olderThan) {
+			break
+		}
+		tasksToRemove++
+	}
+
+	if tasksToRemove == 0 {
+		return 0, nil
+	}
+	// Negative end index keeps tasks pushed to the head meanwhile
+	if err := qm.redisClient.LTrim(ctx, deadLetterQueue, 0, -int64(tasksToRemove)-1).Err(); err != nil {
+		return 0, fmt.Errorf("failed to trim dead letter queue: %w", err)
+	}
+
+	return tasksToRemove, nil
+}
+
 // RequeueDeadLetterTasks moves tasks from dead letter queue back to main queue
 func (qm *QueueManager) RequeueDeadLetterTasks(ctx context.Context, limit int) (int, error) {
 	deadLetterQueue := qm.config.RedisKeyPrefix + ":dead_letter"
+	taskStatus := NewTaskStatusStore(qm.redisClient, qm.config)
 
 	requeued := 0
 	for i := 0; i < limit; i++ {
@@ -1132,12 +1287,18 @@ func (qm *QueueManager) RequeueDeadLetterTasks(ctx context.Context, limit int) (
 		// Add back to main queue
 		if newTaskData, err := json.Marshal(task); err == nil {
 			if err := qm.redisClient.LPush(ctx, qm.config.QueueName, newTaskData).Err(); err != nil {
-				logger.WithFields(map[string]interface{}{
+				logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
 					"task_id": task.ID,
 					"error":   err.Error(),
 				}).Error("Failed to requeue dead letter task")
 				continue
 			}
+			if _, err := taskStatus.Save(ctx, &task, TaskStatusQueued, ""); err != nil {
+				logger.WithCorrelation(task.Correlation()).WithFields(map[string]interface{}{
+					"task_id": task.ID,
+					"error":   err.Error(),
+				}).Warn("Failed to record requeued task status")
+			}
 			requeued++
 		}
 	}
@@ -1156,9 +1317,24 @@ type QueueStats struct {
 	ScheduledQueueLength  int64 `json:"scheduled_queue_length"`
 	DeadLetterQueueLength int64 `json:"dead_letter_queue_length"`
 	ProcessingTasksCount  int64 `json:"processing_tasks_count"`
+	ExpiredLeasesCount    int64 `json:"expired_leases_count"`
 	ActiveWorkersCount    int64 `json:"active_workers_count"`
 }
 
+// WorkerHeartbeat represents the last health report of a worker
+type WorkerHeartbeat struct {
+	WorkerID           string    `json:"worker_id"`
+	Status             string    `json:"status"`
+	LastHeartbeat      time.Time `json:"last_heartbeat"`
+	SecondsSince       int64     `json:"seconds_since_heartbeat"`
+	Stale              bool      `json:"stale"`
+	ConcurrentWorkers  int       `json:"concurrent_workers"`
+	TaskQueueSize      int       `json:"task_queue_size"`
+	RetryQueueSize     int       `json:"retry_queue_size"`
+	ScheduledQueueSize int64     `json:"scheduled_queue_size"`
+	Queues             []string  `json:"queues,omitempty"` // Ключи очередей, из которых берет задачи воркер
+}
+
 // Helper functions for creating different types of tasks
 
 // CreateSingleNotificationTask creates a task for single notification
@@ -1210,6 +1386,18 @@ func CreateScheduledNotificationTask(req *models.CreateNotificationRequest, sche
 	}
 }
 
+// CreateResendNotificationTask creates a task for resending an existing failed notification
+func CreateResendNotificationTask(notificationID uint, priority models.NotificationPriority) *NotificationTask {
+	return &NotificationTask{
+		ID:             generateTaskID(),
+		Type:           TaskTypeResend,
+		NotificationID: &notificationID,
+		Priority:       priority,
+		CreatedAt:      time.Now(),
+		MaxRetries:     3,
+	}
+}
+
 // CreateSystemAnnouncementTask creates a task for system announcement
 func CreateSystemAnnouncementTask(req *usecase.SystemAnnouncementRequest, priority models.NotificationPriority) *NotificationTask {
 	return &NotificationTask{
@@ -0,0 +1,287 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// queueMigrationLockTTL bounds how long a crashed migration blocks the next one
+const queueMigrationLockTTL = 5 * time.Minute
+
+// Methods of moving a queue key
+const (
+	QueueMigrationRename = "rename" // Новый ключ не существовал, старый переименован
+	QueueMigrationMerge  = "merge"  // Задачи старого ключа добавлены к существующему новому
+)
+
+// moveQueueScript moves a list or sorted set key in one step. A missing destination is renamed to,
+// otherwise tasks are appended behind the destination tasks: list tasks keep their order and are
+// consumed first as the older ones, scheduled tasks keep their earliest due time.
+// It returns the number of moved tasks and the method.
+var moveQueueScript = goredis.NewScript(`
+local kind = redis.call('TYPE', KEYS[1]).ok
+if kind == 'none' then
+	return {0, ''}
+end
+if kind ~= 'list' and kind ~= 'zset' then
+	return redis.error_reply('key ' .. KEYS[1] .. ' is a ' .. kind .. ', not a queue')
+end
+
+local count
+if kind == 'list' then
+	count = redis.call('LLEN', KEYS[1])
+else
+	count = redis.call('ZCARD', KEYS[1])
+end
+
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	redis.call('RENAME', KEYS[1], KEYS[2])
+	return {count, 'rename'}
+end
+local destKind = redis.call('TYPE', KEYS[2]).ok
+if destKind ~= kind then
+	return redis.error_reply('key ' .. KEYS[2] .. ' is a ' .. destKind .. ', expected a ' .. kind)
+end
+
+if kind == 'list' then
+	local tasks = redis.call('LRANGE', KEYS[1], 0, -1)
+	for i = 1, #tasks do
+		redis.call('RPUSH', KEYS[2], tasks[i])
+	end
+else
+	redis.call('ZUNIONSTORE', KEYS[2], 2, KEYS[2], KEYS[1], 'AGGREGATE', 'MIN')
+end
+redis.call('DEL', KEYS[1])
+return {count, 'merge'}
+`)
+
+// QueueMigrationRequest names the keys tasks are moved from into the keys of the current
+// configuration. Empty names are unchanged.
+type QueueMigrationRequest struct {
+	FromPrefix             string `json:"from_prefix"`
+	FromQueueName          string `json:"from_queue_name"`
+	FromRetryQueueName     string `json:"from_retry_queue_name"`
+	FromScheduledQueueName string `json:"from_scheduled_queue_name"`
+	DryRun                 bool   `json:"dry_run"` // Только подсчитать задачи, ничего не переносить
+}
+
+// QueueKeyMigration reports the move of one key
+type QueueKeyMigration struct {
+	Name   string `json:"name"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Tasks  int64  `json:"tasks"`
+	Method string `json:"method,omitempty"` // rename или merge, пусто если ключ был пуст или это пробный запуск
+}
+
+// QueueMigrationReport summarizes a queue migration
+type QueueMigrationReport struct {
+	DryRun bool                 `json:"dry_run"`
+	Keys   []*QueueKeyMigration `json:"keys"`
+	Moved  int64                `json:"moved"`
+}
+
+// MigrateQueues moves tasks stranded in queue keys of a previous key prefix or queue names into
+// the keys of the current configuration. Every queue is moved atomically, by renaming it if the
+// new key does not exist yet or by appending its tasks otherwise. Task states follow a prefix
+// change key by key. The migration is refused while workers with a recent heartbeat consume
+// the old keys, they would keep taking and putting tasks there.
+func (qm *QueueManager) MigrateQueues(ctx context.Context, req *QueueMigrationRequest) (*QueueMigrationReport, error) {
+	from := qm.migrationSource(req)
+	keys := qm.migrationKeys(from)
+	prefixChanged := from.RedisKeyPrefix != qm.config.RedisKeyPrefix
+	if len(keys) == 0 && !prefixChanged {
+		return nil, fmt.Errorf("validation failed: old keys are the same as the current ones, nothing to migrate")
+	}
+
+	attached, err := qm.attachedWorkers(ctx, from, keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(attached) > 0 {
+		return nil, fmt.Errorf("cannot migrate queues while workers consume the old keys: %s", strings.Join(attached, ", "))
+	}
+
+	report := &QueueMigrationReport{DryRun: req.DryRun, Keys: keys}
+	if req.DryRun {
+		for _, key := range keys {
+			if key.Tasks, err = qm.queueLength(ctx, key.From); err != nil {
+				return nil, err
+			}
+			report.Moved += key.Tasks
+		}
+		if prefixChanged {
+			states, err := qm.migrateTaskStates(ctx, from.RedisKeyPrefix, true)
+			if err != nil {
+				return nil, err
+			}
+			report.Keys = append(report.Keys, states)
+		}
+		return report, nil
+	}
+
+	release, ok, err := qm.redisClient.TryLock(qm.config.RedisKeyPrefix+":queue_migration", queueMigrationLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot migrate queues: another migration is running")
+	}
+	defer release()
+
+	for _, key := range keys {
+		result, err := moveQueueScript.Run(ctx, qm.redisClient.Client, []string{key.From, key.To}).Slice()
+		if err != nil {
+			return report, fmt.Errorf("failed to migrate %s queue: %w", key.Name, err)
+		}
+		key.Tasks, _ = result[0].(int64)
+		key.Method, _ = result[1].(string)
+		report.Moved += key.Tasks
+	}
+	if prefixChanged {
+		states, err := qm.migrateTaskStates(ctx, from.RedisKeyPrefix, false)
+		if err != nil {
+			return report, err
+		}
+		report.Keys = append(report.Keys, states)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"from_prefix": from.RedisKeyPrefix,
+		"to_prefix":   qm.config.RedisKeyPrefix,
+		"moved":       report.Moved,
+	}).Info("Notification queues migrated")
+
+	return report, nil
+}
+
+// migrationSource returns the configuration of the old keys, unset names are taken from the current one
+func (qm *QueueManager) migrationSource(req *QueueMigrationRequest) *WorkerConfig {
+	from := *qm.config
+	if prefix := strings.TrimSpace(req.FromPrefix); prefix != "" {
+		from.RedisKeyPrefix = prefix
+	}
+	if name := strings.TrimSpace(req.FromQueueName); name != "" {
+		from.QueueName = name
+	}
+	if name := strings.TrimSpace(req.FromRetryQueueName); name != "" {
+		from.RetryQueueName = name
+	}
+	if name := strings.TrimSpace(req.FromScheduledQueueName); name != "" {
+		from.ScheduledQueueName = name
+	}
+	return &from
+}
+
+// migrationKeys returns the queues whose keys differ between the old and the current configuration
+func (qm *QueueManager) migrationKeys(from *WorkerConfig) []*QueueKeyMigration {
+	candidates := []*QueueKeyMigration{
+		{Name: "main", From: from.QueueName, To: qm.config.QueueName},
+		{Name: "retry", From: from.RetryQueueName, To: qm.config.RetryQueueName},
+		{Name: "scheduled", From: from.ScheduledQueueName, To: qm.config.ScheduledQueueName},
+		{Name: "dead_letter", From: from.RedisKeyPrefix + ":dead_letter", To: qm.config.RedisKeyPrefix + ":dead_letter"},
+	}
+
+	keys := make([]*QueueKeyMigration, 0, len(candidates))
+	for _, key := range candidates {
+		if key.From != key.To {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// attachedWorkers returns IDs of live workers registered under the old prefix that consume
+// old keys. Workers that do not report their queues are assumed to consume them.
+func (qm *QueueManager) attachedWorkers(ctx context.Context, from *WorkerConfig, keys []*QueueKeyMigration) ([]string, error) {
+	heartbeats, err := NewQueueManager(qm.redisClient, from).GetWorkerHeartbeats(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	oldKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		oldKeys[key.From] = true
+	}
+	prefixChanged := from.RedisKeyPrefix != qm.config.RedisKeyPrefix
+
+	var attached []string
+	for _, heartbeat := range heartbeats {
+		if heartbeat.Stale {
+			continue
+		}
+		consumesOld := prefixChanged || len(heartbeat.Queues) == 0
+		for _, queue := range heartbeat.Queues {
+			consumesOld = consumesOld || oldKeys[queue]
+		}
+		if consumesOld {
+			attached = append(attached, heartbeat.WorkerID)
+		}
+	}
+	return attached, nil
+}
+
+// queueLength returns the number of tasks of a list or sorted set queue
+func (qm *QueueManager) queueLength(ctx context.Context, key string) (int64, error) {
+	kind, err := qm.redisClient.Type(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get type of %s: %w", key, err)
+	}
+	switch kind {
+	case "none":
+		return 0, nil
+	case "list":
+		return qm.redisClient.LLen(ctx, key).Result()
+	case "zset":
+		return qm.redisClient.ZCard(ctx, key).Result()
+	default:
+		return 0, fmt.Errorf("key %s is a %s, not a queue", key, kind)
+	}
+}
+
+// migrateTaskStates renames task state keys of the old prefix, keeping their expiry. States that
+// already exist under the new prefix are newer and win. A dry run only counts the keys.
+func (qm *QueueManager) migrateTaskStates(ctx context.Context, fromPrefix string, dryRun bool) (*QueueKeyMigration, error) {
+	migration := &QueueKeyMigration{
+		Name: "task_states",
+		From: fromPrefix + ":task:*",
+		To:   qm.config.RedisKeyPrefix + ":task:*",
+	}
+
+	iter := qm.redisClient.Scan(ctx, 0, migration.From, 500).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if dryRun {
+			migration.Tasks++
+			continue
+		}
+
+		newKey := qm.config.RedisKeyPrefix + strings.TrimPrefix(key, fromPrefix)
+		renamed, err := qm.redisClient.RenameNX(ctx, key, newKey).Result()
+		if err != nil && strings.Contains(err.Error(), "no such key") {
+			continue // Expired meanwhile
+		}
+		if err != nil {
+			return migration, fmt.Errorf("failed to migrate task state %s: %w", key, err)
+		}
+		if renamed {
+			migration.Tasks++
+		} else {
+			qm.redisClient.Del(ctx, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return migration, fmt.Errorf("failed to scan task states: %w", err)
+	}
+
+	if migration.Tasks > 0 && !dryRun {
+		migration.Method = QueueMigrationRename
+	}
+	return migration, nil
+}
@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"tachyon-messenger/shared/redis"
+)
+
+// legacyQueues is a migration from keys of the previous prefix and queue names
+var legacyQueues = &QueueMigrationRequest{
+	FromPrefix:             "legacy:notification",
+	FromQueueName:          "legacy:queue",
+	FromRetryQueueName:     "legacy:retry",
+	FromScheduledQueueName: "legacy:scheduled",
+}
+
+// seedLegacyQueues fills the legacy keys with tasks and a task state, and the current main queue
+// with a task of its own
+func seedLegacyQueues(t *testing.T, redisClient *redis.Client, config *WorkerConfig) {
+	t.Helper()
+
+	ctx := context.Background()
+	pipe := redisClient.TxPipeline()
+	pipe.LPush(ctx, "legacy:queue", "task-a", "task-b")
+	pipe.LPush(ctx, "legacy:retry", "task-c")
+	pipe.ZAdd(ctx, "legacy:scheduled", goredis.Z{Score: 100, Member: "task-d"})
+	pipe.LPush(ctx, "legacy:notification:dead_letter", "task-e")
+	pipe.Set(ctx, "legacy:notification:task:task-a", `{"id":"task-a"}`, time.Hour)
+	pipe.LPush(ctx, config.QueueName, "task-x")
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.Fatalf("failed to seed legacy queues: %v", err)
+	}
+}
+
+// queueSnapshot returns the tasks of all current queues
+func queueSnapshot(t *testing.T, redisClient *redis.Client, config *WorkerConfig) map[string][]string {
+	t.Helper()
+
+	ctx := context.Background()
+	return map[string][]string{
+		"main":        redisClient.LRange(ctx, config.QueueName, 0, -1).Val(),
+		"retry":       redisClient.LRange(ctx, config.RetryQueueName, 0, -1).Val(),
+		"scheduled":   redisClient.ZRange(ctx, config.ScheduledQueueName, 0, -1).Val(),
+		"dead_letter": redisClient.LRange(ctx, config.RedisKeyPrefix+":dead_letter", 0, -1).Val(),
+	}
+}
+
+func TestMigrateQueuesMovesLegacyTasks(t *testing.T) {
+	ctx := context.Background()
+	redisClient := newTestRedis(t)
+	config := DefaultWorkerConfig()
+	queueManager := NewQueueManager(redisClient, config)
+	seedLegacyQueues(t, redisClient, config)
+
+	// A dry run only counts the tasks
+	dryRun := *legacyQueues
+	dryRun.DryRun = true
+	report, err := queueManager.MigrateQueues(ctx, &dryRun)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if report.Moved != 5 || redisClient.Client.Exists(ctx, "legacy:queue").Val() != 1 {
+		t.Fatalf("dry run moved %d tasks, want 5 counted and none moved", report.Moved)
+	}
+
+	report, err = queueManager.MigrateQueues(ctx, legacyQueues)
+	if err != nil {
+		t.Fatalf("MigrateQueues failed: %v", err)
+	}
+	if report.Moved != 5 {
+		t.Errorf("moved %d tasks, want 5", report.Moved)
+	}
+	methods := make(map[string]string)
+	for _, key := range report.Keys {
+		methods[key.Name] = key.Method
+	}
+	wantMethods := map[string]string{
+		"main":        QueueMigrationMerge,
+		"retry":       QueueMigrationRename,
+		"scheduled":   QueueMigrationRename,
+		"dead_letter": QueueMigrationRename,
+		"task_states": QueueMigrationRename,
+	}
+	if !reflect.DeepEqual(methods, wantMethods) {
+		t.Errorf("methods = %v, want %v", methods, wantMethods)
+	}
+
+	// Legacy tasks of the main queue are consumed before the task already in the current one
+	want := map[string][]string{
+		"main":        {"task-x", "task-b", "task-a"},
+		"retry":       {"task-c"},
+		"scheduled":   {"task-d"},
+		"dead_letter": {"task-e"},
+	}
+	if got := queueSnapshot(t, redisClient, config); !reflect.DeepEqual(got, want) {
+		t.Errorf("queues = %v, want %v", got, want)
+	}
+	if legacy := redisClient.Keys(ctx, "legacy:*").Val(); len(legacy) != 0 {
+		t.Errorf("expected no legacy keys left, got %v", legacy)
+	}
+	stateKey := config.RedisKeyPrefix + ":task:task-a"
+	if ttl := redisClient.TTL(ctx, stateKey).Val(); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("task state TTL = %v, expected its expiry kept", ttl)
+	}
+}
+
+func TestMigrateQueuesTwice(t *testing.T) {
+	ctx := context.Background()
+	redisClient := newTestRedis(t)
+	config := DefaultWorkerConfig()
+	queueManager := NewQueueManager(redisClient, config)
+	seedLegacyQueues(t, redisClient, config)
+
+	if _, err := queueManager.MigrateQueues(ctx, legacyQueues); err != nil {
+		t.Fatalf("MigrateQueues failed: %v", err)
+	}
+	migrated := queueSnapshot(t, redisClient, config)
+
+	report, err := queueManager.MigrateQueues(ctx, legacyQueues)
+	if err != nil {
+		t.Fatalf("second MigrateQueues failed: %v", err)
+	}
+	if report.Moved != 0 {
+		t.Errorf("second run moved %d tasks, want 0", report.Moved)
+	}
+	for _, key := range report.Keys {
+		if key.Tasks != 0 || key.Method != "" {
+			t.Errorf("second run changed %s: %d tasks by %q", key.Name, key.Tasks, key.Method)
+		}
+	}
+	if got := queueSnapshot(t, redisClient, config); !reflect.DeepEqual(got, migrated) {
+		t.Errorf("queues changed by the second run: %v, want %v", got, migrated)
+	}
+}
+
+func TestMigrateQueuesLeavesCurrentQueues(t *testing.T) {
+	ctx := context.Background()
+	redisClient := newTestRedis(t)
+	config := DefaultWorkerConfig()
+	queueManager := NewQueueManager(redisClient, config)
+
+	pipe := redisClient.TxPipeline()
+	pipe.LPush(ctx, config.QueueName, "task-a", "task-b")
+	pipe.ZAdd(ctx, config.ScheduledQueueName, goredis.Z{Score: 100, Member: "task-c"})
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.Fatalf("failed to seed queues: %v", err)
+	}
+	current := queueSnapshot(t, redisClient, config)
+
+	// Old keys that are the current ones are refused
+	if _, err := queueManager.MigrateQueues(ctx, &QueueMigrationRequest{FromPrefix: config.RedisKeyPrefix, FromQueueName: config.QueueName}); err == nil {
+		t.Error("expected an error for a migration from the current keys")
+	}
+
+	// Legacy keys that no longer exist move nothing
+	report, err := queueManager.MigrateQueues(ctx, legacyQueues)
+	if err != nil {
+		t.Fatalf("MigrateQueues failed: %v", err)
+	}
+	if report.Moved != 0 {
+		t.Errorf("moved %d tasks, want 0", report.Moved)
+	}
+	if got := queueSnapshot(t, redisClient, config); !reflect.DeepEqual(got, current) {
+		t.Errorf("queues = %v, want %v untouched", got, current)
+	}
+}
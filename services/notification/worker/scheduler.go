@@ -0,0 +1,131 @@
+// File: services/notification/worker/scheduler.go
+package worker
+
+import (
+	"context"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// scheduledTaskDispatcher sleeps until the earliest task in the scheduled queue is due
+// and moves due tasks to the main queue. New earlier tasks wake it up through
+// scheduledWake, so tasks are dispatched within about a second of their time.
+func (w *Worker) scheduledTaskDispatcher() {
+	defer w.wg.Done()
+
+	logger.WithField("worker_id", w.id).Info("Scheduled task dispatcher started")
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			w.processScheduledTasks()
+
+		case <-w.scheduledWake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+
+		case <-w.ctx.Done():
+			logger.Info("Context cancelled, stopping scheduled task dispatcher")
+			return
+		}
+
+		timer.Reset(w.untilNextScheduledTask())
+	}
+}
+
+// untilNextScheduledTask returns how long to sleep until the scheduled queue head is due,
+// at most ScheduledMaxSleep so that missed wake-ups are recovered
+func (w *Worker) untilNextScheduledTask() time.Duration {
+	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
+	defer cancel()
+
+	if count, err := w.redisClient.ZCard(ctx, w.config.ScheduledQueueName).Result(); err == nil {
+		w.scheduledCount.Store(count)
+	}
+
+	head, err := w.redisClient.ZRangeWithScores(ctx, w.config.ScheduledQueueName, 0, 0).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WithField("error", err.Error()).Error("Failed to get next scheduled task")
+		}
+		return w.config.ScheduledMaxSleep
+	}
+	if len(head) == 0 {
+		return w.config.ScheduledMaxSleep
+	}
+
+	delay := time.Until(time.UnixMilli(int64(head[0].Score)))
+	if delay < 0 {
+		return 0
+	}
+	if delay > w.config.ScheduledMaxSleep {
+		return w.config.ScheduledMaxSleep
+	}
+	return delay
+}
+
+// scheduleTask adds a serialized task to the scheduled queue and wakes up dispatchers
+// of all worker instances
+func (w *Worker) scheduleTask(task *NotificationTask, taskData []byte) error {
+	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := w.redisClient.ZAdd(ctx, w.config.ScheduledQueueName, goredis.Z{
+		Score:  float64(task.ScheduledAt.UnixMilli()),
+		Member: taskData,
+	}).Err(); err != nil {
+		return err
+	}
+
+	w.wakeScheduler()
+	if err := w.redisClient.Publish(ctx, w.config.ScheduledWakeChannel, task.ScheduledAt.UnixMilli()).Err(); err != nil {
+		// Other instances still pick the task up within ScheduledMaxSleep
+		logger.WithFields(map[string]interface{}{
+			"task_id": task.ID,
+			"error":   err.Error(),
+		}).Warn("Failed to publish scheduled task wake-up")
+	}
+
+	return nil
+}
+
+// wakeScheduler makes the dispatcher recompute its sleep
+func (w *Worker) wakeScheduler() {
+	select {
+	case w.scheduledWake <- struct{}{}:
+	default:
+		// Wake-up already pending
+	}
+}
+
+// scheduledWakeSubscriber wakes the dispatcher when another instance schedules a task
+func (w *Worker) scheduledWakeSubscriber() {
+	defer w.wg.Done()
+
+	pubsub := w.redisClient.Subscribe(w.ctx, w.config.ScheduledWakeChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case _, ok := <-messages:
+			if !ok {
+				return
+			}
+			w.wakeScheduler()
+
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// File: services/notification/worker/task_status.go
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// TaskStatus represents the lifecycle state of a notification task
+type TaskStatus string
+
+const (
+	TaskStatusQueued     TaskStatus = "queued"      // Waiting in the main or retry queue
+	TaskStatusScheduled  TaskStatus = "scheduled"   // Waiting in the scheduled queue
+	TaskStatusProcessing TaskStatus = "processing"  // Being processed by a worker
+	TaskStatusFailed     TaskStatus = "failed"      // Attempt failed, retry is pending
+	TaskStatusDone       TaskStatus = "done"        // Processed successfully
+	TaskStatusDeadLetter TaskStatus = "dead_letter" // Failed permanently after max retries
+)
+
+// IsFinal checks if no further state changes are expected without manual requeue
+func (s TaskStatus) IsFinal() bool {
+	return s == TaskStatusDone || s == TaskStatusDeadLetter
+}
+
+// TaskState represents the stored lifecycle state of a task
+type TaskState struct {
+	ID          string     `json:"id"`
+	Type        TaskType   `json:"type"`
+	Status      TaskStatus `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxRetries  int        `json:"max_retries"`
+	LastError   string     `json:"last_error,omitempty"`
+	WorkerID    string     `json:"worker_id,omitempty"`
+	CallbackURL string     `json:"callback_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TaskStatusStore keeps task lifecycle states in Redis
+type TaskStatusStore struct {
+	redisClient *redis.Client
+	config      *WorkerConfig
+}
+
+// NewTaskStatusStore creates a new task status store
+func NewTaskStatusStore(redisClient *redis.Client, config *WorkerConfig) *TaskStatusStore {
+	return &TaskStatusStore{
+		redisClient: redisClient,
+		config:      config,
+	}
+}
+
+// key returns the Redis key of a task state
+func (s *TaskStatusStore) key(taskID string) string {
+	return s.config.RedisKeyPrefix + ":task:" + taskID
+}
+
+// Save stores the current state of task
+func (s *TaskStatusStore) Save(ctx context.Context, task *NotificationTask, status TaskStatus, workerID string) (*TaskState, error) {
+	state := &TaskState{
+		ID:          task.ID,
+		Type:        task.Type,
+		Status:      status,
+		Attempts:    task.AttemptCount,
+		MaxRetries:  task.MaxRetries,
+		LastError:   task.LastError,
+		WorkerID:    workerID,
+		CallbackURL: task.CallbackURL,
+		CreatedAt:   task.CreatedAt,
+		ScheduledAt: task.ScheduledAt,
+		UpdatedAt:   time.Now(),
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task state: %w", err)
+	}
+
+	if err := s.redisClient.Client.Set(ctx, s.key(task.ID), data, s.config.TaskStatusTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to save task state: %w", err)
+	}
+
+	return state, nil
+}
+
+// Get returns the stored state of a task
+func (s *TaskStatusStore) Get(ctx context.Context, taskID string) (*TaskState, error) {
+	data, err := s.redisClient.Client.Get(ctx, s.key(taskID)).Result()
+	if err != nil {
+		if err == goredis.Nil {
+			return nil, fmt.Errorf("task not found")
+		}
+		return nil, fmt.Errorf("failed to get task state: %w", err)
+	}
+
+	var state TaskState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// GetTaskState returns the lifecycle state of a task
+func (w *Worker) GetTaskState(ctx context.Context, taskID string) (*TaskState, error) {
+	return w.taskStatus.Get(ctx, taskID)
+}
+
+// setTaskStatus records a task state change and notifies the callback on final states
+func (w *Worker) setTaskStatus(task *NotificationTask, status TaskStatus) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, err := w.taskStatus.Save(ctx, task, status, w.id)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"task_id": task.ID,
+			"status":  status,
+			"error":   err.Error(),
+		}).Error("Failed to record task status")
+		return
+	}
+
+	if status.IsFinal() && state.CallbackURL != "" {
+		go w.sendTaskWebhook(state)
+	}
+}
+
+// sendTaskWebhook posts the final task state to its callback URL with a few retries
+func (w *Worker) sendTaskWebhook(state *TaskState) {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	var lastErr error
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		resp, err := client.Post(state.CallbackURL, "application/json", bytes.NewReader(payload))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+			err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		lastErr = err
+
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"task_id":      state.ID,
+		"status":       state.Status,
+		"callback_url": state.CallbackURL,
+		"error":        lastErr.Error(),
+	}).Error("Failed to deliver task status webhook")
+}
@@ -0,0 +1,61 @@
+// File: services/poll/handlers/department_events.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleDepartmentEvent handles a department change published by the user service
+// POST /api/v1/internal/departments/events
+func (h *PollHandler) HandleDepartmentEvent(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var event sharedmodels.DepartmentEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for department event")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.pollUsecase.HandleDepartmentEvent(&event); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":    requestID,
+			"type":          event.Type,
+			"department_id": event.DepartmentID,
+			"error":         err.Error(),
+		}).Error("Failed to handle department event")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Department event handled",
+		"request_id": requestID,
+	})
+}
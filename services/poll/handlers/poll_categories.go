@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPollCategories handles listing categories of the taxonomy. Archived categories are
+// listed with include_archived=true.
+// GET /api/v1/polls/categories
+func (h *PollHandler) GetPollCategories(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	includeArchived := c.Query("include_archived") == "true"
+	categories, err := h.pollUsecase.GetPollCategories(includeArchived)
+	if err != nil {
+		respondCategoryError(c, requestID, err, "Failed to get poll categories")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+		"total":      len(categories),
+		"request_id": requestID,
+	})
+}
+
+// CreatePollCategory handles adding a category to the taxonomy (admin only)
+// POST /api/v1/polls/categories
+func (h *PollHandler) CreatePollCategory(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.CreatePollCategoryRequest
+	if !bindCategoryRequest(c, requestID, &req) {
+		return
+	}
+
+	category, err := h.pollUsecase.CreatePollCategory(&req)
+	if err != nil {
+		respondCategoryError(c, requestID, err, "Failed to create poll category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Poll category created successfully",
+		"category":   category,
+		"request_id": requestID,
+	})
+}
+
+// UpdatePollCategory handles renaming, archiving or restoring a category (admin only)
+// PUT /api/v1/polls/categories/:category_id
+func (h *PollHandler) UpdatePollCategory(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	categoryID, err := strconv.ParseUint(c.Param("category_id"), 10, 32)
+	if err != nil || categoryID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid category ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.UpdatePollCategoryRequest
+	if !bindCategoryRequest(c, requestID, &req) {
+		return
+	}
+
+	category, err := h.pollUsecase.UpdatePollCategory(uint(categoryID), &req)
+	if err != nil {
+		respondCategoryError(c, requestID, err, "Failed to update poll category")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Poll category updated successfully",
+		"category":   category,
+		"request_id": requestID,
+	})
+}
+
+// ReorderPollCategories handles changing the order of categories (admin only)
+// PUT /api/v1/polls/categories/order
+func (h *PollHandler) ReorderPollCategories(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.ReorderPollCategoriesRequest
+	if !bindCategoryRequest(c, requestID, &req) {
+		return
+	}
+
+	categories, err := h.pollUsecase.ReorderPollCategories(&req)
+	if err != nil {
+		respondCategoryError(c, requestID, err, "Failed to reorder poll categories")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Poll categories reordered successfully",
+		"categories": categories,
+		"request_id": requestID,
+	})
+}
+
+// MigratePollCategories handles moving free-text categories of existing polls to the taxonomy
+// and reports how each value was mapped (admin only)
+// POST /api/v1/polls/categories/migrate
+func (h *PollHandler) MigratePollCategories(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.MigratePollCategoriesRequest
+	if !bindCategoryRequest(c, requestID, &req) {
+		return
+	}
+
+	report, err := h.pollUsecase.MigratePollCategories(&req)
+	if err != nil {
+		respondCategoryError(c, requestID, err, "Failed to migrate poll categories")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report":     report,
+		"request_id": requestID,
+	})
+}
+
+// bindCategoryRequest binds a category request body, responding with an error if it is invalid
+func bindCategoryRequest(c *gin.Context, requestID string, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for poll category")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return false
+	}
+	return true
+}
+
+// respondCategoryError maps usecase errors of the category taxonomy to HTTP responses
+func respondCategoryError(c *gin.Context, requestID string, err error, message string) {
+	statusCode := optionErrorStatus(err)
+	if strings.Contains(err.Error(), "already exists") {
+		statusCode = http.StatusConflict
+	}
+
+	if statusCode == http.StatusInternalServerError {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error(message)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      message,
+		"details":    err.Error(),
+		"request_id": requestID,
+	})
+}
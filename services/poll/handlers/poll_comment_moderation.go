@@ -0,0 +1,187 @@
+// File: services/poll/handlers/poll_comment_moderation.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportComment handles reporting a poll comment to the poll moderators
+// POST /api/v1/polls/:id/comments/:comment_id/report
+func (h *PollHandler) ReportComment(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	commentID, ok := parseCommentID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.ReportCommentRequest
+	if !bindModerationRequest(c, requestID, userID, &req) {
+		return
+	}
+
+	report, err := h.pollUsecase.ReportComment(userID, pollID, commentID, &req)
+	if err != nil {
+		respondModerationError(c, requestID, pollID, commentID, err, "Failed to report comment")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"comment_id": commentID,
+		"reason":     req.Reason,
+	}).Info("Comment reported")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Comment reported successfully",
+		"report":     report,
+		"request_id": requestID,
+	})
+}
+
+// GetCommentReportQueue handles getting reported comments waiting for a decision: on polls
+// created by the user, or on all polls for admins
+// GET /api/v1/polls/comments/reports
+func (h *PollHandler) GetCommentReportQueue(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := parseDelegationUser(c, requestID)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 0 || limit > models.MaxLimit {
+		limit = models.DefaultLimit
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	items, total, err := h.pollUsecase.GetCommentReportQueue(userID, isAdminRequest(c), limit, offset)
+	if err != nil {
+		respondModerationError(c, requestID, 0, 0, err, "Failed to get comment report queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":      items,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+		"request_id": requestID,
+	})
+}
+
+// ModerateComment handles hiding, deleting or dismissing reports of a poll comment (poll creator
+// or admin)
+// POST /api/v1/polls/:id/comments/:comment_id/moderate
+func (h *PollHandler) ModerateComment(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	commentID, ok := parseCommentID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.ModerateCommentRequest
+	if !bindModerationRequest(c, requestID, userID, &req) {
+		return
+	}
+
+	result, err := h.pollUsecase.ModerateComment(userID, isAdminRequest(c), pollID, commentID, &req)
+	if err != nil {
+		respondModerationError(c, requestID, pollID, commentID, err, "Failed to moderate comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Comment moderated successfully",
+		"result":     result,
+		"request_id": requestID,
+	})
+}
+
+// parseCommentID gets the comment ID from URL parameter
+func parseCommentID(c *gin.Context, requestID string) (uint, bool) {
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid comment ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(commentID), true
+}
+
+// isAdminRequest checks if the current user moderates comments of all polls
+func isAdminRequest(c *gin.Context) bool {
+	role, err := middleware.GetUserRoleFromContext(c)
+	if err != nil {
+		return false
+	}
+	return role == sharedmodels.RoleAdmin || role == sharedmodels.RoleSuperAdmin
+}
+
+// bindModerationRequest binds a report or moderation request body, responding with an error if it is invalid
+func bindModerationRequest(c *gin.Context, requestID string, userID uint, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for comment moderation")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return false
+	}
+	return true
+}
+
+// respondModerationError responds with the status of a comment moderation error
+func respondModerationError(c *gin.Context, requestID string, pollID, commentID uint, err error, message string) {
+	statusCode := optionErrorStatus(err)
+	if statusCode == http.StatusInternalServerError {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"poll_id":    pollID,
+			"comment_id": commentID,
+			"error":      err.Error(),
+		}).Error(message)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      message,
+		"details":    err.Error(),
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,116 @@
+// File: services/poll/handlers/poll_deadline.go
+package handlers
+
+import (
+	"net/http"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdatePollDeadline handles changing the end time of an active poll
+// PATCH /api/v1/polls/:id/deadline
+func (h *PollHandler) UpdatePollDeadline(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePollDeadlineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update poll deadline")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	response, err := h.pollUsecase.UpdatePollDeadline(userID, pollID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error("Failed to update poll deadline")
+
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, pollID, err)
+			return
+		}
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to update poll deadline",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"end_time":   req.EndTime,
+		"notified":   response.Change.Notified,
+	}).Info("Poll deadline updated successfully")
+
+	middleware.SetVersionETag(c, response.Poll.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Poll deadline updated successfully",
+		"poll":       response.Poll,
+		"change":     response.Change,
+		"history":    response.History,
+		"request_id": requestID,
+	})
+}
+
+// GetPollDeadlineHistory handles getting deadline changes of a poll
+// GET /api/v1/polls/:id/deadline/history
+func (h *PollHandler) GetPollDeadlineHistory(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	history, err := h.pollUsecase.GetPollDeadlineHistory(userID, pollID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error("Failed to get poll deadline history")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to get poll deadline history",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history":    history,
+		"total":      len(history),
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,266 @@
+// File: services/poll/handlers/poll_delegation.go
+package handlers
+
+import (
+	"net/http"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// DelegateVote handles delegating the user's vote in a poll to another participant
+// PUT /api/v1/polls/:id/delegation
+func (h *PollHandler) DelegateVote(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	req, ok := bindDelegateVoteRequest(c, requestID, userID)
+	if !ok {
+		return
+	}
+
+	delegation, err := h.pollUsecase.DelegateVote(userID, pollID, req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"user_id":     userID,
+			"poll_id":     pollID,
+			"delegate_id": req.DelegateID,
+			"error":       err.Error(),
+		}).Error("Failed to delegate vote")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to delegate vote",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Vote delegated successfully",
+		"delegation": delegation,
+		"request_id": requestID,
+	})
+}
+
+// RevokeDelegation handles revoking the delegation of the user's vote in a poll
+// DELETE /api/v1/polls/:id/delegation
+func (h *PollHandler) RevokeDelegation(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.pollUsecase.RevokeDelegation(userID, pollID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error("Failed to revoke delegation")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to revoke delegation",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Delegation revoked successfully",
+		"request_id": requestID,
+	})
+}
+
+// GetPollDelegations handles getting the attribution of delegated votes of a poll
+// GET /api/v1/polls/:id/delegations
+func (h *PollHandler) GetPollDelegations(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	delegations, err := h.pollUsecase.GetPollDelegations(userID, pollID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error("Failed to get poll delegations")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to get poll delegations",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delegations": delegations,
+		"total":       len(delegations),
+		"request_id":  requestID,
+	})
+}
+
+// GetMyDelegations handles getting delegations the user made or received
+// GET /api/v1/polls/delegations
+func (h *PollHandler) GetMyDelegations(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := parseDelegationUser(c, requestID)
+	if !ok {
+		return
+	}
+
+	delegations, err := h.pollUsecase.GetMyDelegations(userID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get user delegations")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get delegations",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delegations": delegations,
+		"total":       len(delegations),
+		"request_id":  requestID,
+	})
+}
+
+// DelegateCategory handles delegating the user's votes in all polls of a category
+// PUT /api/v1/polls/delegations/categories/:category
+func (h *PollHandler) DelegateCategory(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := parseDelegationUser(c, requestID)
+	if !ok {
+		return
+	}
+
+	req, ok := bindDelegateVoteRequest(c, requestID, userID)
+	if !ok {
+		return
+	}
+
+	category := c.Param("category")
+	delegation, err := h.pollUsecase.DelegateCategory(userID, category, req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"user_id":     userID,
+			"category":    category,
+			"delegate_id": req.DelegateID,
+			"error":       err.Error(),
+		}).Error("Failed to delegate category votes")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to delegate category votes",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Category votes delegated successfully",
+		"delegation": delegation,
+		"request_id": requestID,
+	})
+}
+
+// RevokeCategoryDelegation handles revoking the delegation of the user's votes in a category
+// DELETE /api/v1/polls/delegations/categories/:category
+func (h *PollHandler) RevokeCategoryDelegation(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, ok := parseDelegationUser(c, requestID)
+	if !ok {
+		return
+	}
+
+	category := c.Param("category")
+	if err := h.pollUsecase.RevokeCategoryDelegation(userID, category); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"category":   category,
+			"error":      err.Error(),
+		}).Error("Failed to revoke category delegation")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to revoke category delegation",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Category delegation revoked successfully",
+		"request_id": requestID,
+	})
+}
+
+// parseDelegationUser gets the user ID of a delegation request, responding with an error if it is missing
+func parseDelegationUser(c *gin.Context, requestID string) (uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// bindDelegateVoteRequest binds a delegation request body, responding with an error if it is invalid
+func bindDelegateVoteRequest(c *gin.Context, requestID string, userID uint) (*models.DelegateVoteRequest, bool) {
+	var req models.DelegateVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for vote delegation")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+	return &req, true
+}
@@ -9,8 +9,11 @@ import (
 
 	"tachyon-messenger/services/poll/models"
 	"tachyon-messenger/services/poll/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -58,8 +61,8 @@ func (h *PollHandler) CreatePoll(c *gin.Context) {
 		}).Warn("Invalid request body for create poll")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -100,6 +103,54 @@ func (h *PollHandler) CreatePoll(c *gin.Context) {
 	})
 }
 
+// CreatePollForUser handles creating a poll by another service on behalf of a user
+// POST /api/v1/internal/polls
+func (h *PollHandler) CreatePollForUser(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.InternalCreatePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	poll, err := h.pollUsecase.CreatePoll(req.UserID, &req.Poll)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    req.UserID,
+			"error":      err.Error(),
+		}).Error("Failed to create poll for user")
+
+		statusCode := http.StatusInternalServerError
+		if containsValidationError(err.Error()) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to create poll",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    req.UserID,
+		"poll_id":    poll.ID,
+	}).Info("Poll created for user")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"poll":       poll,
+		"request_id": requestID,
+	})
+}
+
 // GetPoll handles getting a single poll by ID
 // GET /api/v1/polls/:id
 func (h *PollHandler) GetPoll(c *gin.Context) {
@@ -162,6 +213,7 @@ func (h *PollHandler) GetPoll(c *gin.Context) {
 		return
 	}
 
+	middleware.SetVersionETag(c, poll.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"poll":       poll,
 		"request_id": requestID,
@@ -216,7 +268,18 @@ func (h *PollHandler) UpdatePoll(c *gin.Context) {
 		}).Warn("Invalid request body for update poll")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Version the changes are based on may also be passed in If-Match header
+	req.Version, err = middleware.GetExpectedVersion(c, req.Version)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid poll version",
 			"details":    err.Error(),
 			"request_id": requestID,
 		})
@@ -232,6 +295,11 @@ func (h *PollHandler) UpdatePoll(c *gin.Context) {
 			"error":      err.Error(),
 		}).Error("Failed to update poll")
 
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, uint(pollID), err)
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "poll not found" {
 			statusCode = http.StatusNotFound
@@ -255,6 +323,7 @@ func (h *PollHandler) UpdatePoll(c *gin.Context) {
 		"poll_id":    pollID,
 	}).Info("Poll updated successfully")
 
+	middleware.SetVersionETag(c, poll.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Poll updated successfully",
 		"poll":       poll,
@@ -357,37 +426,12 @@ func (h *PollHandler) GetPolls(c *gin.Context) {
 	}
 
 	// Parse filter parameters
-	var filter models.PollFilterRequest
-	if err := c.ShouldBindQuery(&filter); err != nil {
-		logger.WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"user_id":    userID,
-			"error":      err.Error(),
-		}).Warn("Invalid filter parameters")
-
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid filter parameters",
-			"details":    err.Error(),
-			"request_id": requestID,
-		})
+	filter, ok := bindPollFilter(c, requestID, userID)
+	if !ok {
 		return
 	}
 
-	// Set default values
-	if filter.Limit <= 0 {
-		filter.Limit = models.DefaultLimit
-	}
-	if filter.Limit > models.MaxLimit {
-		filter.Limit = models.MaxLimit
-	}
-	if filter.SortBy == "" {
-		filter.SortBy = "created_at"
-	}
-	if filter.SortOrder == "" {
-		filter.SortOrder = "desc"
-	}
-
-	pollList, err := h.pollUsecase.GetPolls(userID, &filter)
+	pollList, err := h.pollUsecase.GetPolls(userID, filter)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -409,12 +453,14 @@ func (h *PollHandler) GetPolls(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"polls":      pollList.Polls,
-		"total":      pollList.Total,
-		"limit":      pollList.Limit,
-		"offset":     pollList.Offset,
-		"filters":    pollList.Filters,
-		"request_id": requestID,
+		"polls":           pollList.Polls,
+		"total":           pollList.Total,
+		"limit":           pollList.Limit,
+		"offset":          pollList.Offset,
+		"next_cursor":     pollList.NextCursor,
+		"filters":         pollList.Filters,
+		"category_facets": pollList.CategoryFacets,
+		"request_id":      requestID,
 	})
 }
 
@@ -449,24 +495,12 @@ func (h *PollHandler) SearchPolls(c *gin.Context) {
 	}
 
 	// Parse filter parameters
-	var filter models.PollFilterRequest
-	if err := c.ShouldBindQuery(&filter); err != nil {
-		logger.WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"user_id":    userID,
-			"query":      searchQuery,
-			"error":      err.Error(),
-		}).Warn("Invalid filter parameters")
-
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid filter parameters",
-			"details":    err.Error(),
-			"request_id": requestID,
-		})
+	filter, ok := bindPollFilter(c, requestID, userID)
+	if !ok {
 		return
 	}
 
-	pollList, err := h.pollUsecase.SearchPolls(userID, searchQuery, &filter)
+	pollList, err := h.pollUsecase.SearchPolls(userID, searchQuery, filter)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -489,12 +523,14 @@ func (h *PollHandler) SearchPolls(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"polls":      pollList.Polls,
-		"total":      pollList.Total,
-		"limit":      pollList.Limit,
-		"offset":     pollList.Offset,
-		"query":      searchQuery,
-		"request_id": requestID,
+		"polls":           pollList.Polls,
+		"total":           pollList.Total,
+		"limit":           pollList.Limit,
+		"offset":          pollList.Offset,
+		"next_cursor":     pollList.NextCursor,
+		"query":           searchQuery,
+		"category_facets": pollList.CategoryFacets,
+		"request_id":      requestID,
 	})
 }
 
@@ -546,14 +582,14 @@ func (h *PollHandler) VotePoll(c *gin.Context) {
 		}).Warn("Invalid request body for vote poll")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
 	}
 
-	votes, err := h.pollUsecase.VotePoll(userID, uint(pollID), &req)
+	votes, receipt, err := h.pollUsecase.VotePoll(userID, uint(pollID), &req)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -586,11 +622,17 @@ func (h *PollHandler) VotePoll(c *gin.Context) {
 		"vote_count": len(votes),
 	}).Info("Vote submitted successfully")
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"message":    "Vote submitted successfully",
 		"votes":      votes,
 		"request_id": requestID,
-	})
+	}
+	if receipt != "" {
+		// Shown only once, the receipt verifies the ballot at GET /polls/:id/verify/:receipt
+		response["receipt"] = receipt
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
 // GetPollResults handles getting poll results
@@ -704,3 +746,51 @@ func containsAccessDeniedError(errMsg string) bool {
 	}
 	return false
 }
+
+// containsVersionConflictError checks if error message reports a stale poll version
+func containsVersionConflictError(errMsg string) bool {
+	return strings.Contains(strings.ToLower(errMsg), "version conflict")
+}
+
+// respondVersionConflict responds with 409 and the current poll so the client can reapply its changes
+func (h *PollHandler) respondVersionConflict(c *gin.Context, requestID string, userID, pollID uint, err error) {
+	response := gin.H{
+		"error":      "Poll was modified by another request",
+		"details":    err.Error(),
+		"request_id": requestID,
+	}
+
+	current, getErr := h.pollUsecase.GetPoll(userID, pollID)
+	if getErr == nil {
+		middleware.SetVersionETag(c, current.Version)
+		response["poll"] = current
+	}
+
+	c.JSON(http.StatusConflict, response)
+}
+
+// bindPollFilter binds poll filter parameters together with shared pagination,
+// sorting and filtering parameters, responding with 400 if they are invalid
+func bindPollFilter(c *gin.Context, requestID string, userID uint) (*models.PollFilterRequest, bool) {
+	var filter models.PollFilterRequest
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		filter.Page, err = query.Parse(c.Request.URL.Query(), models.PollListOptions)
+	}
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid filter parameters")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_filter_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+
+	return &filter, true
+}
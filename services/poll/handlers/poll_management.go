@@ -4,10 +4,14 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -61,8 +65,8 @@ func (h *PollHandler) AddParticipants(c *gin.Context) {
 		}).Warn("Invalid request body for add participants")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -251,8 +255,8 @@ func (h *PollHandler) CreateComment(c *gin.Context) {
 		}).Warn("Invalid request body for create comment")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -478,6 +482,346 @@ func (h *PollHandler) DeleteComment(c *gin.Context) {
 	})
 }
 
+// UpdateComment handles editing a comment
+// PUT /api/v1/polls/:id/comments/:comment_id
+func (h *PollHandler) UpdateComment(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse poll ID from URL parameter
+	pollIDStr := c.Param("id")
+	pollID, err := strconv.ParseUint(pollIDStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollIDStr,
+			"error":      err.Error(),
+		}).Warn("Invalid poll ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid poll ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse comment ID from URL parameter
+	commentIDStr := c.Param("comment_id")
+	commentID, err := strconv.ParseUint(commentIDStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"comment_id": commentIDStr,
+			"error":      err.Error(),
+		}).Warn("Invalid comment ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid comment ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req sharedmodels.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"comment_id": commentID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update comment")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	comment, err := h.pollUsecase.UpdateComment(userID, uint(pollID), uint(commentID), &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"comment_id": commentID,
+			"error":      err.Error(),
+		}).Error("Failed to update comment")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if containsAccessDeniedError(err.Error()) {
+			statusCode = http.StatusForbidden
+		} else if containsValidationError(err.Error()) || strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to update comment",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"comment_id": commentID,
+	}).Info("Comment updated successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Comment updated successfully",
+		"comment":    comment,
+		"request_id": requestID,
+	})
+}
+
+// AddCommentReaction handles adding an emoji reaction to a comment
+// POST /api/v1/polls/:id/comments/:comment_id/reactions
+func (h *PollHandler) AddCommentReaction(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse poll ID from URL parameter
+	pollIDStr := c.Param("id")
+	pollID, err := strconv.ParseUint(pollIDStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollIDStr,
+			"error":      err.Error(),
+		}).Warn("Invalid poll ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid poll ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse comment ID from URL parameter
+	commentIDStr := c.Param("comment_id")
+	commentID, err := strconv.ParseUint(commentIDStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"comment_id": commentIDStr,
+			"error":      err.Error(),
+		}).Warn("Invalid comment ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid comment ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req sharedmodels.CommentReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"comment_id": commentID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for add comment reaction")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	reactions, err := h.pollUsecase.AddCommentReaction(userID, uint(pollID), uint(commentID), &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"comment_id": commentID,
+			"error":      err.Error(),
+		}).Error("Failed to add comment reaction")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if containsAccessDeniedError(err.Error()) {
+			statusCode = http.StatusForbidden
+		} else if containsValidationError(err.Error()) || strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to add reaction",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"comment_id": commentID,
+		"emoji":      req.Emoji,
+	}).Info("Comment reaction added successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reaction added successfully",
+		"reactions":  reactions,
+		"request_id": requestID,
+	})
+}
+
+// RemoveCommentReaction handles removing an emoji reaction from a comment
+// DELETE /api/v1/polls/:id/comments/:comment_id/reactions?emoji=
+func (h *PollHandler) RemoveCommentReaction(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse poll ID from URL parameter
+	pollIDStr := c.Param("id")
+	pollID, err := strconv.ParseUint(pollIDStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollIDStr,
+			"error":      err.Error(),
+		}).Warn("Invalid poll ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid poll ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse comment ID from URL parameter
+	commentIDStr := c.Param("comment_id")
+	commentID, err := strconv.ParseUint(commentIDStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"comment_id": commentIDStr,
+			"error":      err.Error(),
+		}).Warn("Invalid comment ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid comment ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	emoji := c.Query("emoji")
+	if emoji == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Emoji is required",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	reactions, err := h.pollUsecase.RemoveCommentReaction(userID, uint(pollID), uint(commentID), emoji)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"comment_id": commentID,
+			"emoji":      emoji,
+			"error":      err.Error(),
+		}).Error("Failed to remove comment reaction")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if containsAccessDeniedError(err.Error()) {
+			statusCode = http.StatusForbidden
+		} else if containsValidationError(err.Error()) || strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to remove reaction",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"comment_id": commentID,
+		"emoji":      emoji,
+	}).Info("Comment reaction removed successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reaction removed successfully",
+		"reactions":  reactions,
+		"request_id": requestID,
+	})
+}
+
 // GetPollStats handles getting poll statistics
 // GET /api/v1/polls/stats
 func (h *PollHandler) GetPollStats(c *gin.Context) {
@@ -571,8 +915,8 @@ func (h *PollHandler) UpdatePollStatus(c *gin.Context) {
 		}).Warn("Invalid request body for update poll status")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -685,3 +1029,41 @@ func (h *PollHandler) GetMyVotes(c *gin.Context) {
 		"request_id": requestID,
 	})
 }
+
+// RetractVote handles retracting user's votes before the poll closes
+// DELETE /api/v1/polls/:id/my-votes
+func (h *PollHandler) RetractVote(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.pollUsecase.RetractVote(userID, pollID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error("Failed to retract vote")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to retract vote",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+	}).Info("Vote retracted successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Vote retracted successfully",
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,304 @@
+// File: services/poll/handlers/poll_options.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// AddOption handles adding an option to an existing poll
+// POST /api/v1/polls/:id/options
+func (h *PollHandler) AddOption(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePollOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for add option")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	option, err := h.pollUsecase.AddOption(userID, pollID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error("Failed to add poll option")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to add option",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"option_id":  option.ID,
+	}).Info("Poll option added successfully")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Option added successfully",
+		"option":     option,
+		"request_id": requestID,
+	})
+}
+
+// UpdateOption handles editing a poll option
+// PUT /api/v1/polls/:id/options/:option_id
+func (h *PollHandler) UpdateOption(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	optionID, ok := parseOptionID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePollOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"option_id":  optionID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update option")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	option, err := h.pollUsecase.UpdateOption(userID, pollID, optionID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"option_id":  optionID,
+			"error":      err.Error(),
+		}).Error("Failed to update poll option")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to update option",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"option_id":  optionID,
+	}).Info("Poll option updated successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Option updated successfully",
+		"option":     option,
+		"request_id": requestID,
+	})
+}
+
+// DeleteOption handles removing a poll option
+// DELETE /api/v1/polls/:id/options/:option_id
+func (h *PollHandler) DeleteOption(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	optionID, ok := parseOptionID(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.pollUsecase.DeleteOption(userID, pollID, optionID); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"option_id":  optionID,
+			"error":      err.Error(),
+		}).Error("Failed to delete poll option")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to delete option",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"poll_id":    pollID,
+		"option_id":  optionID,
+	}).Info("Poll option deleted successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Option deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// ReorderOptions handles changing the order of poll options
+// PUT /api/v1/polls/:id/options
+func (h *PollHandler) ReorderOptions(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.ReorderPollOptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for reorder options")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	options, err := h.pollUsecase.ReorderOptions(userID, pollID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error("Failed to reorder poll options")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to reorder options",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Options reordered successfully",
+		"options":    options,
+		"request_id": requestID,
+	})
+}
+
+// parsePollRequest gets the user ID from JWT token and the poll ID from URL parameter,
+// responding with an error if either is missing
+func (h *PollHandler) parsePollRequest(c *gin.Context, requestID string) (uint, uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	idStr := c.Param("id")
+	pollID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    idStr,
+			"error":      err.Error(),
+		}).Warn("Invalid poll ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid poll ID",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	return userID, uint(pollID), true
+}
+
+// parseOptionID gets the option ID from URL parameter
+func parseOptionID(c *gin.Context, requestID string) (uint, bool) {
+	idStr := c.Param("option_id")
+	optionID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid option ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(optionID), true
+}
+
+// optionErrorStatus maps option management errors to HTTP status codes
+func optionErrorStatus(err error) int {
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		return http.StatusNotFound
+	case containsAccessDeniedError(err.Error()):
+		return http.StatusForbidden
+	case containsValidationError(err.Error()):
+		return http.StatusBadRequest
+	case strings.HasPrefix(err.Error(), "cannot"):
+		// Poll status or existing votes do not permit the change
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
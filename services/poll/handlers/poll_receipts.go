@@ -0,0 +1,44 @@
+// File: services/poll/handlers/poll_receipts.go
+package handlers
+
+import (
+	"net/http"
+
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyVoteReceipt handles verifying that an anonymous ballot was recorded
+// GET /api/v1/polls/:id/verify/:receipt
+func (h *PollHandler) VerifyVoteReceipt(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	verification, err := h.pollUsecase.VerifyVoteReceipt(userID, pollID, c.Param("receipt"))
+	if err != nil {
+		// Neither the receipt nor the user is logged, so that logs can't link them
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Warn("Failed to verify vote receipt")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to verify vote receipt",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"verification": verification,
+		"request_id":   requestID,
+	})
+}
@@ -0,0 +1,89 @@
+// File: services/poll/handlers/poll_snapshots.go
+package handlers
+
+import (
+	"net/http"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPollResultSnapshots handles listing results snapshots of a poll, latest first (admin only)
+// GET /api/v1/polls/:id/results/snapshots
+func (h *PollHandler) GetPollResultSnapshots(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	_, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	snapshots, err := h.pollUsecase.GetPollResultSnapshots(pollID)
+	if err != nil {
+		respondSnapshotError(c, requestID, pollID, err, "Failed to get poll result snapshots")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"snapshots":  snapshots,
+		"total":      len(snapshots),
+		"request_id": requestID,
+	})
+}
+
+// RecomputePollResults handles taking a new results snapshot of a closed poll with an audit
+// note (admin only)
+// POST /api/v1/polls/:id/results/recompute
+func (h *PollHandler) RecomputePollResults(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.RecomputePollResultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	response, err := h.pollUsecase.RecomputePollResults(adminID, pollID, &req)
+	if err != nil {
+		respondSnapshotError(c, requestID, pollID, err, "Failed to recompute poll results")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Poll results recomputed successfully",
+		"snapshot":   response.Snapshot,
+		"previous":   response.Previous,
+		"changed":    response.Changed,
+		"request_id": requestID,
+	})
+}
+
+// respondSnapshotError responds with the status of a snapshot error
+func respondSnapshotError(c *gin.Context, requestID string, pollID uint, err error, message string) {
+	statusCode := optionErrorStatus(err)
+	if statusCode == http.StatusInternalServerError {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"poll_id":    pollID,
+			"error":      err.Error(),
+		}).Error(message)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      message,
+		"details":    err.Error(),
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,48 @@
+// File: services/poll/handlers/poll_timeline.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPollResultsTimeline handles getting vote counts of a poll over time for charts
+// GET /api/v1/polls/:id/results/timeline?bucket=hour|day
+func (h *PollHandler) GetPollResultsTimeline(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, pollID, ok := h.parsePollRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	bucket := models.TimelineBucket(strings.ToLower(strings.TrimSpace(c.Query("bucket"))))
+	timeline, err := h.pollUsecase.GetPollResultsTimeline(userID, pollID, bucket)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"poll_id":    pollID,
+			"bucket":     bucket,
+			"error":      err.Error(),
+		}).Error("Failed to get poll results timeline")
+
+		c.JSON(optionErrorStatus(err), gin.H{
+			"error":      "Failed to get poll results timeline",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timeline":   timeline,
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,70 @@
+// File: services/poll/handlers/user_merge.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// MergeUsers handles moving poll data of a duplicate account to the primary account
+// POST /api/v1/internal/users/merge
+func (h *PollHandler) MergeUsers(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req sharedmodels.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for merge users")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.pollUsecase.MergeUsers(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":        requestID,
+			"primary_user_id":   req.PrimaryUserID,
+			"duplicate_user_id": req.DuplicateUserID,
+			"error":             err.Error(),
+		}).Error("Failed to merge users")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to merge users",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":        requestID,
+		"primary_user_id":   req.PrimaryUserID,
+		"duplicate_user_id": req.DuplicateUserID,
+		"moved":             result.Moved,
+	}).Info("Users merged in poll service")
+
+	c.JSON(http.StatusOK, gin.H{
+		"result":     result,
+		"request_id": requestID,
+	})
+}
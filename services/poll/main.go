@@ -16,8 +16,13 @@ import (
 	"tachyon-messenger/services/poll/usecase"
 	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/jobs"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/quota"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/refs"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -37,6 +42,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Resolve other services through the registry, endpoints are refreshed in the background
+	registry, err := config.NewRegistryFromEnv(nil)
+	if err != nil {
+		log.Fatalf("Failed to set up service registry: %v", err)
+	}
+	http.DefaultTransport = registry.Transport(http.DefaultTransport)
+	registryCtx, stopRegistry := context.WithCancel(context.Background())
+	defer stopRegistry()
+	go registry.Run(registryCtx)
+
 	log.Info("Starting Poll service...")
 
 	// Connect to database
@@ -48,18 +63,35 @@ func main() {
 	defer db.Close()
 
 	// Run migrations
-	if err := db.Migrate(
-		&models.Poll{},
-		&models.PollOption{},
-		&models.PollVote{},
-		&models.PollParticipant{},
-		&models.PollComment{},
-	); err != nil {
+	migrationModels := append(models.Models(), jobs.Models()...)
+	if err := db.Migrate(migrationModels...); err != nil {
 		log.Fatalf("Failed to run database migrations: %v", err)
 	}
 
 	log.Info("Database migrations completed successfully")
 
+	// Connect to Redis (optional, used to share search quotas with other services)
+	redisClient, err := redis.ConnectRedis(redis.DefaultConfig(cfg.Redis.URL))
+	if err != nil {
+		log.Warnf("Failed to connect to Redis, quotas will be counted by this instance only: %v", err)
+	} else {
+		defer redisClient.Close()
+		log.Info("Redis connected successfully")
+	}
+
+	// Per-user quotas of expensive endpoints
+	quotas := quota.NewFromEnv(redisClient)
+
+	// Validate request DTOs with shared rules
+	validation.Install()
+
+	// Key linking anonymous votes to voters, falls back to JWT secret
+	voterHashKey := os.Getenv("POLL_VOTER_HASH_KEY")
+	if voterHashKey == "" {
+		voterHashKey = cfg.JWT.Secret
+	}
+	models.SetVoterHashKey(voterHashKey)
+
 	// Initialize JWT config
 	jwtConfig := middleware.DefaultJWTConfig(cfg.JWT.Secret)
 
@@ -69,15 +101,27 @@ func main() {
 	voteRepo := repository.NewPollVoteRepository(db)
 	participantRepo := repository.NewPollParticipantRepository(db)
 	commentRepo := repository.NewPollCommentRepository(db)
+	delegationRepo := repository.NewPollDelegationRepository(db)
+	categoryRepo := repository.NewPollCategoryRepository(db)
+	snapshotRepo := repository.NewPollResultSnapshotRepository(db)
+
+	// Validation of participant IDs against the user service
+	userRefs := refs.NewUserValidatorFromEnv(registry.BaseURL(config.UserService))
 
 	// Initialize usecases
-	pollUsecase := usecase.NewPollUsecase(pollRepo, optionRepo, voteRepo, participantRepo, commentRepo)
+	notifier := usecase.NewHTTPPollNotifier(registry.BaseURL(config.NotificationService))
+	pollUsecase := usecase.NewPollUsecase(pollRepo, optionRepo, voteRepo, participantRepo, commentRepo, delegationRepo, categoryRepo, snapshotRepo, notifier, userRefs)
+
+	// Background jobs
+	scheduler := jobs.NewScheduler("poll", db, nil)
+	registerJobs(scheduler, pollUsecase, log)
+	scheduler.Start()
 
 	// Initialize handlers
 	pollHandler := handlers.NewPollHandler(pollUsecase)
 
 	// Setup routes
-	r := setupRoutes(pollHandler, jwtConfig)
+	r := setupRoutes(pollHandler, quotas, jwtConfig)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -114,11 +158,35 @@ func main() {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Wait for running background jobs
+	scheduler.Stop()
+
 	log.Info("Poll service stopped")
 }
 
+// registerJobs schedules background jobs of the poll service
+func registerJobs(scheduler *jobs.Scheduler, pollUsecase usecase.PollUsecase, log *logger.Logger) {
+	// Close polls past their end time, resolving their quorum
+	err := scheduler.Register(jobs.Job{
+		Name:     "close_expired_polls",
+		Schedule: "* * * * *",
+		Run: func(ctx context.Context) error {
+			closed, err := pollUsecase.CloseExpiredPolls()
+			jobs.Report(ctx, "closed_count", closed)
+			if closed > 0 {
+				log.WithField("closed_count", closed).Info("Closed expired polls")
+			}
+			return err
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to register background jobs: %v", err)
+	}
+}
+
 func setupRoutes(
 	pollHandler *handlers.PollHandler,
+	quotas *quota.Quotas,
 	jwtConfig *middleware.JWTConfig,
 ) *gin.Engine {
 	r := gin.New()
@@ -127,12 +195,18 @@ func setupRoutes(
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Use(requestid.New())
+	r.Use(middleware.CorrelationMiddleware())
+	r.Use(middleware.BodyLimitMiddleware(middleware.DefaultBodyLimitConfig()))
+
+	// Concurrency limits of route groups, requests over them are shed with 503
+	limits := middleware.NewConcurrencyLimits("poll-service")
 
 	// CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, If-Match")
+		c.Header("Access-Control-Expose-Headers", "ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -145,18 +219,28 @@ func setupRoutes(
 	// Health endpoint (no auth required)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"service":   "poll-service",
-			"timestamp": time.Now().UTC(),
-			"version":   "1.0.0",
+			"status":      "healthy",
+			"service":     "poll-service",
+			"timestamp":   time.Now().UTC(),
+			"version":     "1.0.0",
+			"concurrency": limits.Stats(),
 		})
 	})
 
+	// Load of route groups for monitoring
+	r.GET("/metrics", limits.MetricsHandler())
+
 	// API routes
 	api := r.Group("/api/v1")
 
+	// Internal endpoints (for service-to-service communication)
+	api.POST("/internal/users/merge", limits.Group("internal"), pollHandler.MergeUsers)
+	api.POST("/internal/departments/events", limits.Group("internal"), pollHandler.HandleDepartmentEvent)
+	api.POST("/internal/polls", limits.Group("internal"), pollHandler.CreatePollForUser)
+
 	// Protected routes (require JWT)
 	protected := api.Group("")
+	protected.Use(limits.Group("api"))
 	protected.Use(middleware.JWTMiddleware(jwtConfig))
 	{
 		// Poll CRUD operations
@@ -166,17 +250,49 @@ func setupRoutes(
 		protected.PUT("/polls/:id", pollHandler.UpdatePoll)
 		protected.DELETE("/polls/:id", pollHandler.DeletePoll)
 
+		// Category taxonomy, managed by admins
+		protected.GET("/polls/categories", pollHandler.GetPollCategories)
+		protected.POST("/polls/categories", middleware.RequireAdminRole(), pollHandler.CreatePollCategory)
+		protected.PUT("/polls/categories/order", middleware.RequireAdminRole(), pollHandler.ReorderPollCategories)
+		protected.POST("/polls/categories/migrate", middleware.RequireAdminRole(), pollHandler.MigratePollCategories)
+		protected.PUT("/polls/categories/:category_id", middleware.RequireAdminRole(), pollHandler.UpdatePollCategory)
+
 		// Poll search and stats
-		protected.GET("/polls/search", pollHandler.SearchPolls)
+		protected.GET("/polls/search", quotas.Middleware(quota.BucketSearch, quota.PerPage(1, 20)), pollHandler.SearchPolls)
 		protected.GET("/polls/stats", pollHandler.GetPollStats)
 
+		// Vote delegation
+		protected.PUT("/polls/:id/delegation", pollHandler.DelegateVote)
+		protected.DELETE("/polls/:id/delegation", pollHandler.RevokeDelegation)
+		protected.GET("/polls/:id/delegations", pollHandler.GetPollDelegations)
+		protected.GET("/polls/delegations", pollHandler.GetMyDelegations)
+		protected.PUT("/polls/delegations/categories/:category", pollHandler.DelegateCategory)
+		protected.DELETE("/polls/delegations/categories/:category", pollHandler.RevokeCategoryDelegation)
+
 		// Poll status management
 		protected.PATCH("/polls/:id/status", pollHandler.UpdatePollStatus)
 
+		// Deadline management
+		protected.PATCH("/polls/:id/deadline", pollHandler.UpdatePollDeadline)
+		protected.GET("/polls/:id/deadline/history", pollHandler.GetPollDeadlineHistory)
+
 		// Voting
 		protected.POST("/polls/:id/vote", pollHandler.VotePoll)
 		protected.GET("/polls/:id/my-votes", pollHandler.GetMyVotes)
+		protected.DELETE("/polls/:id/my-votes", pollHandler.RetractVote)
+		protected.GET("/polls/:id/verify/:receipt", pollHandler.VerifyVoteReceipt)
 		protected.GET("/polls/:id/results", pollHandler.GetPollResults)
+		protected.GET("/polls/:id/results/timeline", pollHandler.GetPollResultsTimeline)
+
+		// Results snapshots of closed polls, recomputed by admins with an audit note
+		protected.GET("/polls/:id/results/snapshots", middleware.RequireAdminRole(), pollHandler.GetPollResultSnapshots)
+		protected.POST("/polls/:id/results/recompute", middleware.RequireAdminRole(), middleware.LogAdminAction("recompute_poll_results"), pollHandler.RecomputePollResults)
+
+		// Option management
+		protected.POST("/polls/:id/options", pollHandler.AddOption)
+		protected.PUT("/polls/:id/options", pollHandler.ReorderOptions)
+		protected.PUT("/polls/:id/options/:option_id", pollHandler.UpdateOption)
+		protected.DELETE("/polls/:id/options/:option_id", pollHandler.DeleteOption)
 
 		// Participant management
 		protected.POST("/polls/:id/participants", pollHandler.AddParticipants)
@@ -186,6 +302,14 @@ func setupRoutes(
 		protected.GET("/polls/:id/comments", pollHandler.GetComments)
 		protected.POST("/polls/:id/comments", pollHandler.CreateComment)
 		protected.DELETE("/polls/:id/comments/:comment_id", pollHandler.DeleteComment)
+		protected.PUT("/polls/:id/comments/:comment_id", pollHandler.UpdateComment)
+		protected.POST("/polls/:id/comments/:comment_id/reactions", pollHandler.AddCommentReaction)
+		protected.DELETE("/polls/:id/comments/:comment_id/reactions", pollHandler.RemoveCommentReaction)
+
+		// Comment reports and moderation by poll creators and admins
+		protected.GET("/polls/comments/reports", pollHandler.GetCommentReportQueue)
+		protected.POST("/polls/:id/comments/:comment_id/report", pollHandler.ReportComment)
+		protected.POST("/polls/:id/comments/:comment_id/moderate", pollHandler.ModerateComment)
 	}
 
 	return r
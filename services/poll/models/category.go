@@ -0,0 +1,95 @@
+// File: services/poll/models/category.go
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// PollCategory is a category of the managed taxonomy. Polls and category delegations store the
+// slug, which never changes; the name can be renamed. Categories are archived instead of deleted,
+// archived categories keep their polls but can't be chosen for new ones.
+type PollCategory struct {
+	models.BaseModel
+	Slug        string     `gorm:"not null;size:100;uniqueIndex" json:"slug"`
+	Name        string     `gorm:"not null;size:100" json:"name"`
+	Description string     `gorm:"size:255" json:"description,omitempty"`
+	Position    int        `gorm:"not null;default:0;index" json:"position"` // Порядок в списках и фасетах
+	IsArchived  bool       `gorm:"not null;default:false" json:"is_archived"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+// TableName returns the table name for PollCategory model
+func (PollCategory) TableName() string {
+	return "poll_categories"
+}
+
+// CreatePollCategoryRequest represents request for adding a category to the taxonomy
+type CreatePollCategoryRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
+	Slug        string `json:"slug,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"` // По умолчанию из названия
+	Description string `json:"description,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"`
+}
+
+// UpdatePollCategoryRequest represents request for renaming, describing or archiving a category
+type UpdatePollCategoryRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"`
+	IsArchived  *bool   `json:"is_archived,omitempty"`
+}
+
+// ReorderPollCategoriesRequest represents the new order of categories. Categories not listed
+// keep their relative order after the listed ones.
+type ReorderPollCategoriesRequest struct {
+	CategoryIDs []uint `json:"category_ids" binding:"required,min=1,dive,min=1" validate:"required,min=1,dive,min=1"`
+}
+
+// Actions of the category migration report
+const (
+	CategoryMigrationUnchanged = "unchanged" // Значение уже является slug категории
+	CategoryMigrationMatched   = "matched"   // Совпало с названием или slug без учёта регистра
+	CategoryMigrationMapped    = "mapped"    // Указано в mapping запроса
+	CategoryMigrationCreated   = "created"   // Создана новая категория, create_missing
+	CategoryMigrationUnmapped  = "unmapped"  // Оставлено как есть
+)
+
+// MigratePollCategoriesRequest represents request for moving free-text categories of existing
+// polls to the taxonomy
+type MigratePollCategoriesRequest struct {
+	Mapping       map[string]string `json:"mapping,omitempty"`        // Свободный текст -> slug или название категории
+	CreateMissing bool              `json:"create_missing,omitempty"` // Создать категории для значений без соответствия
+	DryRun        bool              `json:"dry_run,omitempty"`        // Только отчёт, без изменений
+}
+
+// PollCategoryMapping reports how one free-text category value is migrated
+type PollCategoryMapping struct {
+	Value    string `json:"value"`
+	Polls    int64  `json:"polls"`
+	Category string `json:"category,omitempty"` // Slug категории, пусто если не сопоставлено
+	Action   string `json:"action"`
+}
+
+// PollCategoryMigrationReport represents result of the category migration
+type PollCategoryMigrationReport struct {
+	DryRun             bool                   `json:"dry_run"`
+	Mappings           []*PollCategoryMapping `json:"mappings"`
+	CategoriesCreated  int                    `json:"categories_created"`
+	PollsUpdated       int64                  `json:"polls_updated"`
+	DelegationsUpdated int64                  `json:"delegations_updated"`
+	DelegationsDropped int64                  `json:"delegations_dropped"` // Дубликаты делегирования в целевой категории
+}
+
+// PollCategoryValue is a distinct category value of polls with the number of polls using it
+type PollCategoryValue struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// PollCategoryFacet represents the number of polls of a category matching the other filters of a list
+type PollCategoryFacet struct {
+	Category   string `json:"category"` // Пусто для опросов без категории
+	Name       string `json:"name,omitempty"`
+	IsArchived bool   `json:"is_archived,omitempty"`
+	Count      int64  `json:"count"`
+}
@@ -0,0 +1,92 @@
+// File: services/poll/models/comment_report.go
+package models
+
+import "time"
+
+// CommentReportReason represents why a participant reported a poll comment
+type CommentReportReason string
+
+const (
+	CommentReportReasonSpam       CommentReportReason = "spam"
+	CommentReportReasonHarassment CommentReportReason = "harassment"
+	CommentReportReasonOffensive  CommentReportReason = "offensive"
+	CommentReportReasonOffTopic   CommentReportReason = "off_topic"
+	CommentReportReasonOther      CommentReportReason = "other"
+)
+
+// CommentReportStatus represents the moderation outcome of a comment report
+type CommentReportStatus string
+
+const (
+	CommentReportStatusPending   CommentReportStatus = "pending"
+	CommentReportStatusUpheld    CommentReportStatus = "upheld"    // Комментарий скрыт или удалён модератором
+	CommentReportStatusDismissed CommentReportStatus = "dismissed" // Модератор не нашёл нарушений
+)
+
+// CommentModerationAction represents a decision of a moderator on a reported comment
+type CommentModerationAction string
+
+const (
+	CommentModerationHide    CommentModerationAction = "hide"
+	CommentModerationDelete  CommentModerationAction = "delete"
+	CommentModerationDismiss CommentModerationAction = "dismiss" // Отклоняет жалобы и возвращает скрытый комментарий
+)
+
+// PollCommentReport is a report of a poll comment by a participant. Pending reports of a comment
+// form its entry in the moderation queue of the poll creator and admins.
+type PollCommentReport struct {
+	ID         uint                `gorm:"primarykey" json:"id"`
+	CommentID  uint                `gorm:"not null;uniqueIndex:idx_poll_comment_reports_reporter,priority:1" json:"comment_id"`
+	PollID     uint                `gorm:"not null;index" json:"poll_id"`
+	ReporterID uint                `gorm:"not null;uniqueIndex:idx_poll_comment_reports_reporter,priority:2" json:"reporter_id"`
+	Reason     CommentReportReason `gorm:"not null;size:20" json:"reason"`
+	Details    string              `gorm:"size:500" json:"details,omitempty"`
+	Status     CommentReportStatus `gorm:"not null;default:'pending';size:20;index" json:"status"`
+	ResolvedBy *uint               `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time          `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// TableName returns the table name for PollCommentReport model
+func (PollCommentReport) TableName() string {
+	return "poll_comment_reports"
+}
+
+// ReportCommentRequest represents request for reporting a poll comment
+type ReportCommentRequest struct {
+	Reason  CommentReportReason `json:"reason" binding:"required,oneof=spam harassment offensive off_topic other" validate:"required,oneof=spam harassment offensive off_topic other"`
+	Details string              `json:"details,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// ModerateCommentRequest represents a moderation decision on a reported poll comment
+type ModerateCommentRequest struct {
+	Action CommentModerationAction `json:"action" binding:"required,oneof=hide delete dismiss" validate:"required,oneof=hide delete dismiss"`
+	Note   string                  `json:"note,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"` // Пояснение для автора комментария
+}
+
+// CommentReportSummary aggregates pending reports of one comment
+type CommentReportSummary struct {
+	CommentID    uint
+	PollID       uint
+	ReportCount  int64
+	LastReportID uint
+}
+
+// CommentModerationItem represents a reported comment in the moderation queue
+type CommentModerationItem struct {
+	Comment        *PollCommentResponse          `json:"comment"`
+	PollTitle      string                        `json:"poll_title"`
+	ReportCount    int64                         `json:"report_count"`
+	Reasons        map[CommentReportReason]int64 `json:"reasons"`
+	Reports        []*PollCommentReport          `json:"reports"`
+	LastReportedAt time.Time                     `json:"last_reported_at"`
+}
+
+// CommentModerationResult represents the outcome of a moderation decision
+type CommentModerationResult struct {
+	CommentID       uint                    `json:"comment_id"`
+	Action          CommentModerationAction `json:"action"`
+	ResolvedReports int64                   `json:"resolved_reports"`
+	IsHidden        bool                    `json:"is_hidden"`
+	Deleted         bool                    `json:"deleted"`
+}
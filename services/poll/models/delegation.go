@@ -0,0 +1,45 @@
+// File: services/poll/models/delegation.go
+package models
+
+import "time"
+
+// DelegationVisibility represents who can see who delegated their vote to whom in a poll.
+// Vote counts always include delegated votes.
+type DelegationVisibility string
+
+const (
+	DelegationVisibilityPrivate      DelegationVisibility = "private"      // Только делегирующий и его представитель
+	DelegationVisibilityCreator      DelegationVisibility = "creator"      // Также создатель опроса
+	DelegationVisibilityParticipants DelegationVisibility = "participants" // Все участники опроса
+)
+
+// PollDelegation represents a participant's vote delegated to another user, either for one poll
+// or for all polls of a category. Delegations are deleted permanently when revoked.
+type PollDelegation struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	DelegatorID uint      `gorm:"not null;uniqueIndex:idx_poll_delegations_scope,priority:1" json:"delegator_id"`
+	DelegateID  uint      `gorm:"not null;index" json:"delegate_id"`
+	PollID      uint      `gorm:"not null;default:0;uniqueIndex:idx_poll_delegations_scope,priority:2" json:"poll_id,omitempty"` // Ноль для делегирования по категории
+	Category    string    `gorm:"not null;default:'';size:100;uniqueIndex:idx_poll_delegations_scope,priority:3" json:"category,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for PollDelegation model
+func (PollDelegation) TableName() string {
+	return "poll_delegations"
+}
+
+// DelegateVoteRequest represents request for delegating a vote to another user
+type DelegateVoteRequest struct {
+	DelegateID uint `json:"delegate_id" binding:"required,min=1" validate:"required,min=1"`
+}
+
+// DelegatedVote attributes the vote of a participant who did not vote to the participant
+// whose vote counts for them
+type DelegatedVote struct {
+	DelegatorID uint `json:"delegator_id"`
+	DelegateID  uint `json:"delegate_id"`        // Непосредственный представитель
+	VotedBy     uint `json:"voted_by,omitempty"` // Участник, чей голос засчитан, пусто если никто в цепочке не голосовал
+	Counted     bool `json:"counted"`
+}
@@ -41,9 +41,18 @@ const (
 	PollVisibilityPrivate    PollVisibility = "private"     // Только создатель
 )
 
+// PollOutcome represents how a poll with a quorum was resolved when it closed
+type PollOutcome string
+
+const (
+	PollOutcomeQuorumReached    PollOutcome = "quorum_reached"     // Кворум набран, результаты действительны
+	PollOutcomeQuorumNotReached PollOutcome = "quorum_not_reached" // Кворум не набран, результаты недействительны
+)
+
 // Poll represents a poll/survey in the system
 type Poll struct {
 	models.BaseModel
+	models.VersionMeta
 	Title       string         `gorm:"not null;size:255" json:"title" validate:"required,min=1,max=255"`
 	Description string         `gorm:"type:text" json:"description,omitempty" validate:"omitempty,max=2000"`
 	Type        PollType       `gorm:"not null;size:20" json:"type" validate:"required,oneof=single_choice multiple_choice ranking rating open_text"`
@@ -52,16 +61,27 @@ type Poll struct {
 	CreatedBy   uint           `gorm:"not null;index" json:"created_by" validate:"required,min=1"`
 
 	// Timing settings
-	StartTime *time.Time `gorm:"index" json:"start_time,omitempty"`
-	EndTime   *time.Time `gorm:"index" json:"end_time,omitempty"`
+	StartTime   *time.Time `gorm:"index" json:"start_time,omitempty"`
+	EndTime     *time.Time `gorm:"index" json:"end_time,omitempty"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"` // Первая активация, начало динамики голосов
 
 	// Poll settings
 	AllowAnonymous    bool `gorm:"not null;default:false" json:"allow_anonymous"`
 	AllowMultipleVote bool `gorm:"not null;default:false" json:"allow_multiple_vote"`
+	AllowVoteChange   bool `gorm:"not null;default:false" json:"allow_vote_change"` // Разрешить изменение и отзыв голоса до закрытия
 	RequireComment    bool `gorm:"not null;default:false" json:"require_comment"`
 	ShowResults       bool `gorm:"not null;default:true" json:"show_results"`
 	ShowResultsAfter  bool `gorm:"not null;default:false" json:"show_results_after"` // Показывать результаты только после голосования
 
+	// Voting rules for invite-only polls
+	WeightedVoting bool        `gorm:"not null;default:false" json:"weighted_voting"` // Голоса участников учитываются с их весом
+	QuorumPercent  *float64    `json:"quorum_percent,omitempty"`                      // Доля веса участников, которые должны проголосовать
+	Outcome        PollOutcome `gorm:"size:30" json:"outcome,omitempty"`              // Итог по кворуму, определяется при закрытии
+
+	// Delegated voting: a participant who doesn't vote is represented by their delegate
+	AllowDelegation      bool                 `gorm:"not null;default:false" json:"allow_delegation"`
+	DelegationVisibility DelegationVisibility `gorm:"not null;default:'creator';size:20" json:"delegation_visibility"`
+
 	// Department restriction (if visibility is 'department')
 	DepartmentID *uint `gorm:"index" json:"department_id,omitempty" validate:"omitempty,min=1"`
 
@@ -138,6 +158,11 @@ func (p *Poll) IsActive() bool {
 	return true
 }
 
+// CanChangeVote checks if a voter can replace or retract their vote
+func (p *Poll) CanChangeVote() bool {
+	return p.AllowVoteChange || p.AllowMultipleVote
+}
+
 // PollOption represents an option in a poll
 type PollOption struct {
 	models.BaseModel
@@ -157,6 +182,9 @@ type PollOption struct {
 	VotePercent float64 `gorm:"-" json:"vote_percent,omitempty"`
 	RatingAvg   float64 `gorm:"-" json:"rating_avg,omitempty"`  // Средняя оценка для rating polls
 	RankingAvg  float64 `gorm:"-" json:"ranking_avg,omitempty"` // Средний ранг для ranking polls
+
+	WeightedVotes   float64 `gorm:"-" json:"weighted_votes,omitempty"`   // Суммарный вес голосов для weighted polls
+	WeightedPercent float64 `gorm:"-" json:"weighted_percent,omitempty"` // Доля веса голосов для weighted polls
 }
 
 // TableName returns the table name for PollOption model
@@ -167,10 +195,11 @@ func (PollOption) TableName() string {
 // PollVote represents a vote on a poll
 type PollVote struct {
 	models.BaseModel
-	PollID      uint  `gorm:"not null;index" json:"poll_id" validate:"required"`
-	OptionID    *uint `gorm:"index" json:"option_id,omitempty"` // Null для open_text polls
-	UserID      *uint `gorm:"index" json:"user_id,omitempty"`   // Null для анонимных голосов
-	IsAnonymous bool  `gorm:"not null;default:false" json:"is_anonymous"`
+	PollID      uint   `gorm:"not null;index;index:idx_poll_votes_poll_option,priority:1" json:"poll_id" validate:"required"`
+	OptionID    *uint  `gorm:"index;index:idx_poll_votes_poll_option,priority:2" json:"option_id,omitempty"` // Null для open_text polls
+	UserID      *uint  `gorm:"index" json:"user_id,omitempty"`                                               // Null для анонимных голосов
+	IsAnonymous bool   `gorm:"not null;default:false" json:"is_anonymous"`
+	VoterHash   string `gorm:"size:64;index" json:"-"` // Связывает голос с голосующим без раскрытия user_id
 
 	// Different vote types
 	TextValue    string `gorm:"type:text" json:"text_value,omitempty"` // Для open_text polls
@@ -200,6 +229,11 @@ func (pv *PollVote) BeforeCreate(tx *gorm.DB) error {
 		return gorm.ErrInvalidValue
 	}
 
+	// Anonymous vote must be linked to its voter so that it can be changed or retracted
+	if pv.IsAnonymous && pv.VoterHash == "" {
+		return gorm.ErrInvalidValue
+	}
+
 	return nil
 }
 
@@ -210,6 +244,7 @@ type PollParticipant struct {
 	UserID     uint       `gorm:"not null;index" json:"user_id" validate:"required"`
 	InvitedBy  uint       `gorm:"not null;index" json:"invited_by" validate:"required"`
 	InvitedAt  time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"invited_at"`
+	Weight     float64    `gorm:"not null;default:1" json:"weight"` // Вес голоса при weighted voting
 	VotedAt    *time.Time `json:"voted_at,omitempty"`
 	NotifiedAt *time.Time `json:"notified_at,omitempty"`
 
@@ -222,6 +257,22 @@ func (PollParticipant) TableName() string {
 	return "poll_participants"
 }
 
+// PollDeadlineChange records a change of poll end time
+type PollDeadlineChange struct {
+	models.BaseModel
+	PollID     uint       `gorm:"not null;index" json:"poll_id" validate:"required"`
+	ChangedBy  uint       `gorm:"not null" json:"changed_by" validate:"required"`
+	OldEndTime *time.Time `json:"old_end_time,omitempty"`
+	NewEndTime time.Time  `gorm:"not null" json:"new_end_time"`
+	Reason     string     `gorm:"size:500" json:"reason,omitempty" validate:"omitempty,max=500"`
+	Notified   int        `gorm:"not null;default:0" json:"notified"` // Сколько участников получили уведомление
+}
+
+// TableName returns the table name for PollDeadlineChange model
+func (PollDeadlineChange) TableName() string {
+	return "poll_deadline_changes"
+}
+
 // PollComment represents a comment on a poll
 type PollComment struct {
 	models.BaseModel
@@ -229,11 +280,17 @@ type PollComment struct {
 	UserID   uint   `gorm:"not null;index" json:"user_id" validate:"required"`
 	Content  string `gorm:"not null;type:text" json:"content" validate:"required,min=1,max=1000"`
 	ParentID *uint  `gorm:"index" json:"parent_id,omitempty" validate:"omitempty,min=1"`
+	models.CommentMeta
+
+	// Moderation
+	IsHidden bool       `gorm:"not null;default:false" json:"is_hidden"` // Скрыт модератором или после жалоб
+	HiddenAt *time.Time `json:"hidden_at,omitempty"`
 
 	// Associations
-	Poll    *Poll         `gorm:"foreignKey:PollID" json:"poll,omitempty"`
-	Parent  *PollComment  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
-	Replies []PollComment `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"replies,omitempty"`
+	Poll      *Poll                 `gorm:"foreignKey:PollID" json:"poll,omitempty"`
+	Parent    *PollComment          `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Replies   []PollComment         `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"replies,omitempty"`
+	Reactions []PollCommentReaction `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"reactions,omitempty"`
 }
 
 // TableName returns the table name for PollComment model
@@ -241,6 +298,16 @@ func (PollComment) TableName() string {
 	return "poll_comments"
 }
 
+// PollCommentReaction represents an emoji reaction on a poll comment
+type PollCommentReaction struct {
+	models.CommentReaction
+}
+
+// TableName returns the table name for PollCommentReaction model
+func (PollCommentReaction) TableName() string {
+	return "poll_comment_reactions"
+}
+
 // BeforeCreate hook is called before creating a poll comment
 func (pc *PollComment) BeforeCreate(tx *gorm.DB) error {
 	// Validate that parent comment belongs to the same poll if ParentID is set
@@ -252,3 +319,21 @@ func (pc *PollComment) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// Models returns all database models of the service for migrations
+func Models() []interface{} {
+	return []interface{}{
+		&Poll{},
+		&PollOption{},
+		&PollVote{},
+		&PollParticipant{},
+		&PollComment{},
+		&PollCommentReaction{},
+		&PollCommentReport{},
+		&PollDeadlineChange{},
+		&PollDelegation{},
+		&PollVoteReceipt{},
+		&PollCategory{},
+		&PollResultSnapshot{},
+	}
+}
@@ -0,0 +1,176 @@
+// File: services/poll/models/receipt.go
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// receiptSize is the number of random bytes in a vote receipt
+const receiptSize = 32
+
+// ErrInvalidReceipt is returned when a receipt can't open the ballot it was issued for
+var ErrInvalidReceipt = errors.New("invalid vote receipt")
+
+// PollVoteReceipt lets an anonymous voter verify that their ballot was recorded. Only a hash of
+// the receipt is stored and the ballot is sealed with a key derived from the receipt, so the
+// choice can be read only by the receipt holder. Receipts have no voter, vote ID, sequential ID
+// or timestamp, which could link them to votes and so to voters.
+type PollVoteReceipt struct {
+	ReceiptHash string `gorm:"primaryKey;size:64" json:"-"`
+	PollID      uint   `gorm:"not null;index" json:"poll_id"`
+	Ballot      []byte `gorm:"not null" json:"-"` // Nonce followed by the sealed VoteReceiptBallot
+}
+
+// TableName returns the table name for PollVoteReceipt model
+func (PollVoteReceipt) TableName() string {
+	return "poll_vote_receipts"
+}
+
+// VoteReceiptBallot is the choice recorded by a vote receipt
+type VoteReceiptBallot struct {
+	OptionIDs     []uint       `json:"option_ids,omitempty"`
+	TextValue     string       `json:"text_value,omitempty"`
+	RatingValues  map[uint]int `json:"rating_values,omitempty"`
+	RankingValues map[uint]int `json:"ranking_values,omitempty"`
+}
+
+// Matches reports whether votes of a voter are the anonymous votes the ballot was cast with,
+// so a receipt of a ballot that was changed or retracted since doesn't verify
+func (b *VoteReceiptBallot) Matches(votes []*PollVote) bool {
+	if len(votes) == 0 {
+		return false
+	}
+
+	var optionIDs []uint
+	var textValue string
+	ratings, rankings := make(map[uint]int), make(map[uint]int)
+	for _, vote := range votes {
+		if !vote.IsAnonymous {
+			return false
+		}
+		switch {
+		case vote.OptionID == nil:
+			textValue = vote.TextValue
+		case vote.RatingValue != nil:
+			ratings[*vote.OptionID] = *vote.RatingValue
+		case vote.RankingValue != nil:
+			rankings[*vote.OptionID] = *vote.RankingValue
+		default:
+			optionIDs = append(optionIDs, *vote.OptionID)
+		}
+	}
+
+	ballotOptionIDs := slices.Clone(b.OptionIDs)
+	slices.Sort(ballotOptionIDs)
+	slices.Sort(optionIDs)
+	return slices.Equal(ballotOptionIDs, optionIDs) && b.TextValue == textValue &&
+		maps.Equal(b.RatingValues, ratings) && maps.Equal(b.RankingValues, rankings)
+}
+
+// NewVoteReceipt issues a receipt for the ballot of a poll. The receipt is returned to the voter
+// once and is not stored.
+func NewVoteReceipt(pollID uint, ballot *VoteReceiptBallot) (string, *PollVoteReceipt, error) {
+	secret := make([]byte, receiptSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate vote receipt: %w", err)
+	}
+	receipt := base64.RawURLEncoding.EncodeToString(secret)
+
+	plaintext, err := json.Marshal(ballot)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode ballot: %w", err)
+	}
+
+	aead, err := receiptCipher(receipt)
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, fmt.Errorf("failed to generate ballot nonce: %w", err)
+	}
+
+	return receipt, &PollVoteReceipt{
+		ReceiptHash: ReceiptHash(receipt),
+		PollID:      pollID,
+		Ballot:      aead.Seal(nonce, nonce, plaintext, receiptPollData(pollID)),
+	}, nil
+}
+
+// Open returns the ballot sealed with the receipt
+func (r *PollVoteReceipt) Open(receipt string) (*VoteReceiptBallot, error) {
+	aead, err := receiptCipher(receipt)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Ballot) < aead.NonceSize() {
+		return nil, ErrInvalidReceipt
+	}
+
+	nonce, sealed := r.Ballot[:aead.NonceSize()], r.Ballot[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, receiptPollData(r.PollID))
+	if err != nil {
+		return nil, ErrInvalidReceipt
+	}
+
+	var ballot VoteReceiptBallot
+	if err := json.Unmarshal(plaintext, &ballot); err != nil {
+		return nil, fmt.Errorf("failed to decode ballot: %w", err)
+	}
+	return &ballot, nil
+}
+
+// ReceiptHash returns the hash a receipt is stored and looked up by
+func ReceiptHash(receipt string) string {
+	sum := sha256.Sum256([]byte("receipt:" + receipt))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsValidReceipt checks that a receipt has the format of an issued one
+func IsValidReceipt(receipt string) bool {
+	secret, err := base64.RawURLEncoding.DecodeString(receipt)
+	return err == nil && len(secret) == receiptSize
+}
+
+// receiptCipher returns the cipher sealing ballots with a key derived from the receipt
+func receiptCipher(receipt string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("ballot:" + receipt))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ballot cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// receiptPollData binds a sealed ballot to its poll
+func receiptPollData(pollID uint) []byte {
+	return []byte(strconv.FormatUint(uint64(pollID), 10))
+}
+
+// VoteReceiptOption is an option chosen in a verified ballot
+type VoteReceiptOption struct {
+	ID   uint   `json:"id"`
+	Text string `json:"text"`
+}
+
+// VoteReceiptResponse represents a verified vote receipt. Receipts are not linked to voters, so
+// the ballot is compared with the current votes of the user verifying it: a ballot the voter has
+// changed or retracted since is superseded and no longer counts.
+type VoteReceiptResponse struct {
+	PollID     uint                 `json:"poll_id"`
+	Recorded   bool                 `json:"recorded"`   // The ballot is the voter's current vote in a poll that was not cancelled
+	Superseded bool                 `json:"superseded"` // The voter has changed or retracted the ballot since
+	Ballot     *VoteReceiptBallot   `json:"ballot"`
+	Options    []*VoteReceiptOption `json:"options"` // Options of the ballot that still exist
+}
@@ -3,14 +3,17 @@ package models
 
 import (
 	"time"
+
+	"tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
 )
 
 // CreatePollRequest represents request for creating a poll
 type CreatePollRequest struct {
-	Title       string         `json:"title" binding:"required,min=1,max=255" validate:"required,min=1,max=255"`
+	Title       string         `json:"title" binding:"required,min=1,max=255" validate:"required,notblank,max=255"`
 	Description string         `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
-	Type        PollType       `json:"type" binding:"required,oneof=single_choice multiple_choice ranking rating open_text" validate:"required,oneof=single_choice multiple_choice ranking rating open_text"`
-	Visibility  PollVisibility `json:"visibility" binding:"omitempty,oneof=public department invite_only private" validate:"omitempty,oneof=public department invite_only private"`
+	Type        PollType       `json:"type" binding:"required,oneof=single_choice multiple_choice ranking rating open_text" validate:"required,enum"`
+	Visibility  PollVisibility `json:"visibility" binding:"omitempty,oneof=public department invite_only private" validate:"omitempty,enum"`
 	Category    string         `json:"category,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
 
 	// Timing settings
@@ -20,10 +23,19 @@ type CreatePollRequest struct {
 	// Poll settings
 	AllowAnonymous    bool `json:"allow_anonymous"`
 	AllowMultipleVote bool `json:"allow_multiple_vote"`
+	AllowVoteChange   bool `json:"allow_vote_change"`
 	RequireComment    bool `json:"require_comment"`
 	ShowResults       bool `json:"show_results"`
 	ShowResultsAfter  bool `json:"show_results_after"`
 
+	// Voting rules (for invite-only polls)
+	WeightedVoting bool     `json:"weighted_voting"`
+	QuorumPercent  *float64 `json:"quorum_percent,omitempty" binding:"omitempty,gt=0,lte=100" validate:"omitempty,gt=0,lte=100"`
+
+	// Delegated voting (for invite-only polls)
+	AllowDelegation      bool                 `json:"allow_delegation"`
+	DelegationVisibility DelegationVisibility `json:"delegation_visibility,omitempty" binding:"omitempty,oneof=private creator participants"`
+
 	// Department restriction (if visibility is 'department')
 	DepartmentID *uint `json:"department_id,omitempty" validate:"omitempty,min=1"`
 
@@ -31,33 +43,60 @@ type CreatePollRequest struct {
 	Options []CreatePollOptionRequest `json:"options" binding:"required,min=1,max=20" validate:"required,min=1,max=20,dive"`
 
 	// Participants (for invite-only polls)
-	ParticipantIDs []uint `json:"participant_ids,omitempty" validate:"omitempty,dive,min=1"`
+	ParticipantIDs     []uint           `json:"participant_ids,omitempty" validate:"omitempty,dive,min=1"`
+	ParticipantWeights map[uint]float64 `json:"participant_weights,omitempty"` // user_id -> weight, по умолчанию 1
+}
+
+// InternalCreatePollRequest represents request of another service for creating a poll on behalf of a user
+type InternalCreatePollRequest struct {
+	UserID uint              `json:"user_id" binding:"required,min=1"`
+	Poll   CreatePollRequest `json:"poll" binding:"required"`
 }
 
 // CreatePollOptionRequest represents request for creating a poll option
 type CreatePollOptionRequest struct {
-	Text        string `json:"text" binding:"required,min=1,max=500" validate:"required,min=1,max=500"`
+	Text        string `json:"text" binding:"required,min=1,max=500" validate:"required,notblank,max=500"`
 	Description string `json:"description,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
 	Position    int    `json:"position"`
-	Color       string `json:"color,omitempty" binding:"omitempty,len=7" validate:"omitempty,len=7"`
+	Color       string `json:"color,omitempty" binding:"omitempty,len=7" validate:"omitempty,len=7,hexcolor"`
 	ImageURL    string `json:"image_url,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
 }
 
+// UpdatePollOptionRequest represents request for editing a poll option.
+// Only text and description can change while the poll is active.
+type UpdatePollOptionRequest struct {
+	Text        *string `json:"text,omitempty" binding:"omitempty,min=1,max=500" validate:"omitempty,notblank,max=500"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	Color       *string `json:"color,omitempty" binding:"omitempty,len=7" validate:"omitempty,len=7,hexcolor"`
+	ImageURL    *string `json:"image_url,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// ReorderPollOptionsRequest represents the new order of all poll options
+type ReorderPollOptionsRequest struct {
+	OptionIDs []uint `json:"option_ids" binding:"required,min=1,max=20,dive,min=1" validate:"required,min=1,max=20,unique"`
+}
+
 // UpdatePollRequest represents request for updating a poll
 type UpdatePollRequest struct {
-	Title             *string         `json:"title,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,min=1,max=255"`
-	Description       *string         `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
-	Status            *PollStatus     `json:"status,omitempty" binding:"omitempty,oneof=draft active closed archived cancelled" validate:"omitempty,oneof=draft active closed archived cancelled"`
-	Visibility        *PollVisibility `json:"visibility,omitempty" binding:"omitempty,oneof=public department invite_only private" validate:"omitempty,oneof=public department invite_only private"`
-	Category          *string         `json:"category,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
-	StartTime         *time.Time      `json:"start_time,omitempty"`
-	EndTime           *time.Time      `json:"end_time,omitempty"`
-	AllowAnonymous    *bool           `json:"allow_anonymous,omitempty"`
-	AllowMultipleVote *bool           `json:"allow_multiple_vote,omitempty"`
-	RequireComment    *bool           `json:"require_comment,omitempty"`
-	ShowResults       *bool           `json:"show_results,omitempty"`
-	ShowResultsAfter  *bool           `json:"show_results_after,omitempty"`
-	DepartmentID      *uint           `json:"department_id,omitempty" validate:"omitempty,min=1"`
+	Title                *string               `json:"title,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,notblank,max=255"`
+	Description          *string               `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	Status               *PollStatus           `json:"status,omitempty" binding:"omitempty,oneof=draft active closed archived cancelled" validate:"omitempty,enum"`
+	Visibility           *PollVisibility       `json:"visibility,omitempty" binding:"omitempty,oneof=public department invite_only private" validate:"omitempty,enum"`
+	Category             *string               `json:"category,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
+	StartTime            *time.Time            `json:"start_time,omitempty"`
+	EndTime              *time.Time            `json:"end_time,omitempty"`
+	AllowAnonymous       *bool                 `json:"allow_anonymous,omitempty"`
+	AllowMultipleVote    *bool                 `json:"allow_multiple_vote,omitempty"`
+	AllowVoteChange      *bool                 `json:"allow_vote_change,omitempty"`
+	RequireComment       *bool                 `json:"require_comment,omitempty"`
+	ShowResults          *bool                 `json:"show_results,omitempty"`
+	ShowResultsAfter     *bool                 `json:"show_results_after,omitempty"`
+	WeightedVoting       *bool                 `json:"weighted_voting,omitempty"`
+	QuorumPercent        *float64              `json:"quorum_percent,omitempty" binding:"omitempty,gte=0,lte=100" validate:"omitempty,gte=0,lte=100"` // 0 отменяет кворум
+	AllowDelegation      *bool                 `json:"allow_delegation,omitempty"`
+	DelegationVisibility *DelegationVisibility `json:"delegation_visibility,omitempty" binding:"omitempty,oneof=private creator participants"`
+	DepartmentID         *uint                 `json:"department_id,omitempty" validate:"omitempty,min=1"`
+	Version              *uint                 `json:"version,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"` // Версия, на основе которой сделаны изменения
 }
 
 // VotePollRequest represents request for voting on a poll
@@ -72,8 +111,22 @@ type VotePollRequest struct {
 
 // AddParticipantsRequest represents request for adding participants to a poll
 type AddParticipantsRequest struct {
-	UserIDs []uint `json:"user_ids" binding:"required,min=1" validate:"required,min=1,dive,min=1"`
-	Message string `json:"message,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+	UserIDs []uint           `json:"user_ids" binding:"required,min=1" validate:"required,min=1,dive,min=1"`
+	Weights map[uint]float64 `json:"weights,omitempty"` // user_id -> weight, по умолчанию 1
+	Message string           `json:"message,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// UpdatePollDeadlineRequest represents request for changing the end time of an active poll
+type UpdatePollDeadlineRequest struct {
+	EndTime time.Time `json:"end_time" binding:"required" validate:"required,future"`
+	Reason  string    `json:"reason,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// PollDeadlineResponse represents the result of a deadline change
+type PollDeadlineResponse struct {
+	Poll    *PollResponse         `json:"poll"`
+	Change  *PollDeadlineChange   `json:"change"`
+	History []*PollDeadlineChange `json:"history"`
 }
 
 // CreateCommentRequest represents request for creating a comment
@@ -84,9 +137,9 @@ type CreateCommentRequest struct {
 
 // PollFilterRequest represents request for filtering polls
 type PollFilterRequest struct {
-	Status       PollStatus     `json:"status,omitempty" validate:"omitempty,oneof=draft active closed archived cancelled"`
-	Type         PollType       `json:"type,omitempty" validate:"omitempty,oneof=single_choice multiple_choice ranking rating open_text"`
-	Visibility   PollVisibility `json:"visibility,omitempty" validate:"omitempty,oneof=public department invite_only private"`
+	Status       PollStatus     `json:"status,omitempty" validate:"omitempty,enum"`
+	Type         PollType       `json:"type,omitempty" validate:"omitempty,enum"`
+	Visibility   PollVisibility `json:"visibility,omitempty" validate:"omitempty,enum"`
 	Category     string         `json:"category,omitempty" validate:"omitempty,max=100"`
 	CreatedBy    *uint          `json:"created_by,omitempty" validate:"omitempty,min=1"`
 	DepartmentID *uint          `json:"department_id,omitempty" validate:"omitempty,min=1"`
@@ -101,13 +154,33 @@ type PollFilterRequest struct {
 	UserHasVoted  *bool `json:"user_has_voted,omitempty"`
 	UserIsInvited *bool `json:"user_is_invited,omitempty"`
 
-	// Pagination
-	Limit  int `json:"limit" validate:"omitempty,min=1,max=100"`
-	Offset int `json:"offset" validate:"omitempty,min=0"`
+	// Pagination, sorting and generic filters
+	Page *query.Params `form:"-" json:"-"`
+}
 
-	// Sorting
-	SortBy    string `json:"sort_by,omitempty" validate:"omitempty,oneof=created_at updated_at title start_time end_time total_votes"`
-	SortOrder string `json:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+// PollListOptions defines pagination, sorting and filtering of poll lists
+var PollListOptions = &query.Options{
+	DefaultLimit: DefaultLimit,
+	MaxLimit:     MaxLimit,
+	DefaultSort:  "-created_at",
+	SortFields: map[string]string{
+		"created_at": "polls.created_at",
+		"updated_at": "polls.updated_at",
+		"title":      "polls.title",
+		"start_time": "polls.start_time",
+		"end_time":   "polls.end_time",
+	},
+	FilterFields: map[string]string{
+		"status":     "polls.status",
+		"type":       "polls.type",
+		"visibility": "polls.visibility",
+		"category":   "polls.category",
+		"created_by": "polls.created_by",
+		"created_at": "polls.created_at",
+		"end_time":   "polls.end_time",
+		"title":      "polls.title",
+	},
+	TieBreaker: "polls.id",
 }
 
 // File: services/poll/models/responses.go
@@ -124,16 +197,27 @@ type PollResponse struct {
 	CreatedBy   uint           `json:"created_by"`
 
 	// Timing
-	StartTime *time.Time `json:"start_time,omitempty"`
-	EndTime   *time.Time `json:"end_time,omitempty"`
+	StartTime   *time.Time `json:"start_time,omitempty"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
 
 	// Settings
 	AllowAnonymous    bool `json:"allow_anonymous"`
 	AllowMultipleVote bool `json:"allow_multiple_vote"`
+	AllowVoteChange   bool `json:"allow_vote_change"`
 	RequireComment    bool `json:"require_comment"`
 	ShowResults       bool `json:"show_results"`
 	ShowResultsAfter  bool `json:"show_results_after"`
 
+	// Voting rules
+	WeightedVoting bool        `json:"weighted_voting"`
+	QuorumPercent  *float64    `json:"quorum_percent,omitempty"`
+	Outcome        PollOutcome `json:"outcome,omitempty"`
+
+	// Delegated voting
+	AllowDelegation      bool                 `json:"allow_delegation"`
+	DelegationVisibility DelegationVisibility `json:"delegation_visibility,omitempty"`
+
 	// Department
 	DepartmentID *uint `json:"department_id,omitempty"`
 
@@ -149,6 +233,7 @@ type PollResponse struct {
 	Comments     []*PollCommentResponse     `json:"comments,omitempty"`
 
 	// Metadata
+	Version   uint      `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -168,6 +253,9 @@ type PollOptionResponse struct {
 	RankingAvg  float64   `json:"ranking_avg,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	WeightedVotes   float64 `json:"weighted_votes,omitempty"`
+	WeightedPercent float64 `json:"weighted_percent,omitempty"`
 }
 
 // PollVoteResponse represents a vote in API responses
@@ -192,6 +280,7 @@ type PollParticipantResponse struct {
 	UserID     uint       `json:"user_id"`
 	InvitedBy  uint       `json:"invited_by"`
 	InvitedAt  time.Time  `json:"invited_at"`
+	Weight     float64    `json:"weight"`
 	VotedAt    *time.Time `json:"voted_at,omitempty"`
 	NotifiedAt *time.Time `json:"notified_at,omitempty"`
 	CreatedAt  time.Time  `json:"created_at"`
@@ -200,23 +289,32 @@ type PollParticipantResponse struct {
 
 // PollCommentResponse represents a comment in API responses
 type PollCommentResponse struct {
-	ID        uint                   `json:"id"`
-	PollID    uint                   `json:"poll_id"`
-	UserID    uint                   `json:"user_id"`
-	Content   string                 `json:"content"`
-	ParentID  *uint                  `json:"parent_id,omitempty"`
-	Replies   []*PollCommentResponse `json:"replies,omitempty"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
+	ID        uint                     `json:"id"`
+	PollID    uint                     `json:"poll_id"`
+	UserID    uint                     `json:"user_id"`
+	Content   string                   `json:"content"`
+	ParentID  *uint                    `json:"parent_id,omitempty"`
+	Depth     int                      `json:"depth"`
+	IsEdited  bool                     `json:"is_edited"`
+	EditedAt  *time.Time               `json:"edited_at,omitempty"`
+	IsHidden  bool                     `json:"is_hidden,omitempty"`
+	Reactions []models.ReactionSummary `json:"reactions,omitempty"`
+	Replies   []*PollCommentResponse   `json:"replies,omitempty"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
 }
 
 // PollListResponse represents a list of polls with pagination
 type PollListResponse struct {
-	Polls   []*PollResponse    `json:"polls"`
-	Total   int64              `json:"total"`
-	Limit   int                `json:"limit"`
-	Offset  int                `json:"offset"`
-	Filters *PollFilterRequest `json:"filters,omitempty"`
+	Polls      []*PollResponse    `json:"polls"`
+	Total      int64              `json:"total"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Filters    *PollFilterRequest `json:"filters,omitempty"`
+
+	// Number of matching polls per category, regardless of the category filter
+	CategoryFacets []*PollCategoryFacet `json:"category_facets"`
 }
 
 // PollStatsResponse represents poll statistics
@@ -252,6 +350,31 @@ type PollResultsResponse struct {
 	TextResponses []string                     `json:"text_responses,omitempty"` // Для open_text polls
 	RatingStats   map[uint]*RatingStats        `json:"rating_stats,omitempty"`   // Статистика по рейтингам
 	RankingStats  map[uint]*RankingStats       `json:"ranking_stats,omitempty"`  // Статистика по рейтингам
+
+	WeightedVotesByOption map[uint]float64 `json:"weighted_votes_by_option,omitempty"` // Для weighted polls
+	Quorum                *PollQuorum      `json:"quorum,omitempty"`                   // Для опросов с кворумом
+
+	DelegatedVoters int              `json:"delegated_voters,omitempty"` // Участники, за которых засчитан голос представителя
+	Delegations     []*DelegatedVote `json:"delegations,omitempty"`      // С учетом видимости делегирования
+
+	Snapshot *PollResultSnapshot `json:"snapshot,omitempty"` // Снимок, из которого взяты результаты закрытого опроса
+}
+
+// PollQuorum represents turnout of a poll with a quorum. Turnout is weighted for weighted polls,
+// otherwise each participant counts once.
+type PollQuorum struct {
+	RequiredPercent float64 `json:"required_percent"`
+	TotalWeight     float64 `json:"total_weight"` // Суммарный вес приглашенных участников
+	VotedWeight     float64 `json:"voted_weight"` // Вес проголосовавших участников
+	TurnoutPercent  float64 `json:"turnout_percent"`
+	Reached         bool    `json:"reached"`
+}
+
+// VoterVote identifies the voter and option of a vote row, for weighting votes by voter
+type VoterVote struct {
+	VoterHash string
+	UserID    *uint
+	OptionID  *uint
 }
 
 // RatingStats represents rating statistics for an option
@@ -274,6 +397,52 @@ type RankingStats struct {
 	RankDistribution map[int]int `json:"rank_distribution"` // rank -> count
 }
 
+// TimelineBucket represents the width of time buckets of a results timeline
+type TimelineBucket string
+
+const (
+	TimelineBucketHour TimelineBucket = "hour"
+	TimelineBucketDay  TimelineBucket = "day"
+)
+
+// Duration returns the width of the bucket, zero for unknown buckets
+func (b TimelineBucket) Duration() time.Duration {
+	switch b {
+	case TimelineBucketHour:
+		return time.Hour
+	case TimelineBucketDay:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// VoteBucketCount represents the number of votes for an option cast within a time bucket
+type VoteBucketCount struct {
+	Bucket   int64 // Номер интервала от начала эпохи Unix (UTC)
+	OptionID *uint // Null для open_text polls
+	Count    int64
+}
+
+// PollTimelinePoint represents votes of one time bucket of a results timeline
+type PollTimelinePoint struct {
+	Start          time.Time    `json:"start"`
+	Votes          int          `json:"votes"`
+	VotesByOption  map[uint]int `json:"votes_by_option"`
+	TotalVotes     int          `json:"total_votes"`      // Нарастающий итог на конец интервала
+	TotalsByOption map[uint]int `json:"totals_by_option"` // Нарастающий итог на конец интервала
+}
+
+// PollResultsTimelineResponse represents vote counts of a poll over time since its activation
+type PollResultsTimelineResponse struct {
+	PollID  uint                  `json:"poll_id"`
+	Bucket  TimelineBucket        `json:"bucket"`
+	From    time.Time             `json:"from"`
+	To      time.Time             `json:"to"`
+	Options []*PollOptionResponse `json:"options"`
+	Points  []*PollTimelinePoint  `json:"points"`
+}
+
 // ToResponse converts Poll model to PollResponse
 func (p *Poll) ToResponse() *PollResponse {
 	response := &PollResponse{
@@ -287,18 +456,27 @@ func (p *Poll) ToResponse() *PollResponse {
 		CreatedBy:         p.CreatedBy,
 		StartTime:         p.StartTime,
 		EndTime:           p.EndTime,
+		ActivatedAt:       p.ActivatedAt,
 		AllowAnonymous:    p.AllowAnonymous,
 		AllowMultipleVote: p.AllowMultipleVote,
+		AllowVoteChange:   p.AllowVoteChange,
 		RequireComment:    p.RequireComment,
 		ShowResults:       p.ShowResults,
 		ShowResultsAfter:  p.ShowResultsAfter,
-		DepartmentID:      p.DepartmentID,
-		TotalVotes:        p.TotalVotes,
-		TotalVoters:       p.TotalVoters,
-		UserHasVoted:      p.UserHasVoted,
-		ParticipantRate:   p.ParticipantRate,
-		CreatedAt:         p.CreatedAt,
-		UpdatedAt:         p.UpdatedAt,
+		WeightedVoting:    p.WeightedVoting,
+		QuorumPercent:     p.QuorumPercent,
+		Outcome:           p.Outcome,
+
+		AllowDelegation:      p.AllowDelegation,
+		DelegationVisibility: p.DelegationVisibility,
+		DepartmentID:         p.DepartmentID,
+		TotalVotes:           p.TotalVotes,
+		TotalVoters:          p.TotalVoters,
+		UserHasVoted:         p.UserHasVoted,
+		ParticipantRate:      p.ParticipantRate,
+		Version:              p.Version,
+		CreatedAt:            p.CreatedAt,
+		UpdatedAt:            p.UpdatedAt,
 	}
 
 	// Convert options if they exist
@@ -344,6 +522,9 @@ func (po *PollOption) ToResponse() *PollOptionResponse {
 		RankingAvg:  po.RankingAvg,
 		CreatedAt:   po.CreatedAt,
 		UpdatedAt:   po.UpdatedAt,
+
+		WeightedVotes:   po.WeightedVotes,
+		WeightedPercent: po.WeightedPercent,
 	}
 }
 
@@ -372,6 +553,7 @@ func (pp *PollParticipant) ToResponse() *PollParticipantResponse {
 		UserID:     pp.UserID,
 		InvitedBy:  pp.InvitedBy,
 		InvitedAt:  pp.InvitedAt,
+		Weight:     pp.Weight,
 		VotedAt:    pp.VotedAt,
 		NotifiedAt: pp.NotifiedAt,
 		CreatedAt:  pp.CreatedAt,
@@ -381,21 +563,40 @@ func (pp *PollParticipant) ToResponse() *PollParticipantResponse {
 
 // ToResponse converts PollComment model to PollCommentResponse
 func (pc *PollComment) ToResponse() *PollCommentResponse {
+	return pc.ToResponseForUser(0)
+}
+
+// ToResponseForUser converts PollComment model to PollCommentResponse,
+// marking reactions left by the given user
+func (pc *PollComment) ToResponseForUser(userID uint) *PollCommentResponse {
 	response := &PollCommentResponse{
 		ID:        pc.ID,
 		PollID:    pc.PollID,
 		UserID:    pc.UserID,
 		Content:   pc.Content,
 		ParentID:  pc.ParentID,
+		Depth:     pc.Depth,
+		IsEdited:  pc.IsEdited,
+		EditedAt:  pc.EditedAt,
+		IsHidden:  pc.IsHidden,
 		CreatedAt: pc.CreatedAt,
 		UpdatedAt: pc.UpdatedAt,
 	}
 
+	// Aggregate reactions if loaded
+	if len(pc.Reactions) > 0 {
+		reactions := make([]models.CommentReaction, len(pc.Reactions))
+		for i, reaction := range pc.Reactions {
+			reactions[i] = reaction.CommentReaction
+		}
+		response.Reactions = models.AggregateReactions(reactions, userID)
+	}
+
 	// Convert replies if they exist
 	if len(pc.Replies) > 0 {
 		response.Replies = make([]*PollCommentResponse, len(pc.Replies))
 		for i, reply := range pc.Replies {
-			response.Replies[i] = reply.ToResponse()
+			response.Replies[i] = reply.ToResponseForUser(userID)
 		}
 	}
 
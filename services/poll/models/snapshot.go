@@ -0,0 +1,107 @@
+// File: services/poll/models/snapshot.go
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrSnapshotNotFound is returned for polls closed without a results snapshot
+var ErrSnapshotNotFound = errors.New("poll result snapshot not found")
+
+// SnapshotReason represents why a results snapshot was taken
+type SnapshotReason string
+
+const (
+	SnapshotReasonClosed     SnapshotReason = "closed"     // Снят при закрытии опроса
+	SnapshotReasonBackfilled SnapshotReason = "backfilled" // Снят при первом просмотре опроса, закрытого без снимка
+	SnapshotReasonRecomputed SnapshotReason = "recomputed" // Пересчитан администратором
+)
+
+// PollResultSnapshot is the immutable results of a poll taken when it closed. Results of closed
+// polls are served from their latest snapshot, so votes deleted afterwards do not change them.
+// Snapshots are never updated: a recompute adds the next version with the admin's note.
+type PollResultSnapshot struct {
+	ID                  uint           `gorm:"primarykey" json:"id"`
+	PollID              uint           `gorm:"not null;uniqueIndex:idx_poll_result_snapshots_version,priority:1" json:"poll_id"`
+	Version             int            `gorm:"not null;uniqueIndex:idx_poll_result_snapshots_version,priority:2" json:"version"`
+	Reason              SnapshotReason `gorm:"not null;size:20" json:"reason"`
+	Note                string         `gorm:"type:text" json:"note,omitempty"` // Обоснование пересчета для аудита
+	CreatedBy           *uint          `json:"created_by,omitempty"`            // Администратор, пересчитавший результаты
+	TotalVotes          int            `gorm:"not null;default:0" json:"total_votes"`
+	TotalVoters         int            `gorm:"not null;default:0" json:"total_voters"`
+	TextResponseCount   int            `gorm:"not null;default:0" json:"text_response_count"`
+	TextResponsesDigest string         `gorm:"size:64" json:"text_responses_digest,omitempty"` // SHA-256 ответов open_text опроса
+	Data                string         `gorm:"type:text;not null" json:"-"`                    // Результаты без данных конкретного пользователя, JSON
+	CreatedAt           time.Time      `json:"created_at"`
+}
+
+// TableName returns the table name for PollResultSnapshot model
+func (PollResultSnapshot) TableName() string {
+	return "poll_result_snapshots"
+}
+
+// NewPollResultSnapshot captures results of a poll. Results must be computed for no particular
+// viewer, with all delegations; the poll and options are left out, they are read from the poll.
+func NewPollResultSnapshot(pollID uint, results *PollResultsResponse, reason SnapshotReason) (*PollResultSnapshot, error) {
+	data := *results
+	data.Poll = nil
+	data.Options = nil
+	data.VotesByUser = nil
+	data.Snapshot = nil
+
+	encoded, err := json.Marshal(&data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode poll results: %w", err)
+	}
+
+	snapshot := &PollResultSnapshot{
+		PollID:            pollID,
+		Reason:            reason,
+		TotalVotes:        results.TotalVotes,
+		TotalVoters:       results.TotalVoters,
+		TextResponseCount: len(results.TextResponses),
+		Data:              string(encoded),
+	}
+	if len(results.TextResponses) > 0 {
+		snapshot.TextResponsesDigest = TextResponsesDigest(results.TextResponses)
+	}
+	return snapshot, nil
+}
+
+// Results decodes the captured results
+func (s *PollResultSnapshot) Results() (*PollResultsResponse, error) {
+	var results PollResultsResponse
+	if err := json.Unmarshal([]byte(s.Data), &results); err != nil {
+		return nil, fmt.Errorf("failed to decode poll result snapshot %d: %w", s.ID, err)
+	}
+	return &results, nil
+}
+
+// TextResponsesDigest returns the SHA-256 of text responses regardless of their order, so a
+// recompute shows whether responses changed since the snapshot
+func TextResponsesDigest(responses []string) string {
+	sorted := append([]string(nil), responses...)
+	sort.Strings(sorted)
+
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecomputePollResultsRequest represents admin request to take a new results snapshot of a closed poll
+type RecomputePollResultsRequest struct {
+	Note string `json:"note" binding:"required,min=3,max=1000" validate:"required,min=3,max=1000"` // Обоснование пересчета, сохраняется в аудите
+}
+
+// RecomputePollResultsResponse represents the new results snapshot and the one it replaced
+type RecomputePollResultsResponse struct {
+	Snapshot *PollResultSnapshot `json:"snapshot"`
+	Previous *PollResultSnapshot `json:"previous,omitempty"`
+	Changed  bool                `json:"changed"` // Результаты отличаются от предыдущего снимка
+}
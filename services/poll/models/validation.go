@@ -3,7 +3,10 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"tachyon-messenger/shared/validation"
 )
 
 // Validation constants
@@ -17,6 +20,9 @@ const (
 	MaxTextResponse      = 2000
 	MaxParticipants      = 1000
 
+	DefaultParticipantWeight = 1
+	MaxParticipantWeight     = 1000000
+
 	MinRatingValue  = 1
 	MaxRatingValue  = 10
 	MinRankingValue = 1
@@ -25,9 +31,16 @@ const (
 	DefaultLimit = 20
 	MaxLimit     = 100
 
+	// Results timeline limits
+	MaxTimelineBuckets = 1000
+
+	// Pending reports after which a comment is hidden until a moderator decides
+	CommentAutoHideReports = 3
+
 	// Cache TTL
-	PollCacheTTL    = 5 * time.Minute
-	ResultsCacheTTL = 1 * time.Minute
+	PollCacheTTL     = 5 * time.Minute
+	ResultsCacheTTL  = 1 * time.Minute
+	TimelineCacheTTL = 1 * time.Hour // Для закрытых опросов, их голоса больше не меняются
 )
 
 // Poll validation errors
@@ -53,25 +66,8 @@ var (
 
 // ValidateCreatePollRequest validates poll creation request
 func (req *CreatePollRequest) Validate() error {
-	// Basic validation
-	if req.Title == "" {
-		return errors.New("title is required")
-	}
-	if len(req.Title) > MaxPollTitle {
-		return errors.New("title is too long")
-	}
-	if len(req.Description) > MaxPollDescription {
-		return errors.New("description is too long")
-	}
-
-	// Validate poll type
-	if !isValidPollType(req.Type) {
-		return errors.New("invalid poll type")
-	}
-
-	// Validate visibility
-	if req.Visibility != "" && !isValidPollVisibility(req.Visibility) {
-		return errors.New("invalid poll visibility")
+	if err := validation.Struct(req); err != nil {
+		return err
 	}
 
 	// Validate time range
@@ -81,20 +77,6 @@ func (req *CreatePollRequest) Validate() error {
 		}
 	}
 
-	// Validate options
-	if len(req.Options) == 0 {
-		return errors.New("at least one option is required")
-	}
-	if len(req.Options) > MaxPollOptions {
-		return errors.New("too many options")
-	}
-
-	for i, option := range req.Options {
-		if err := option.Validate(); err != nil {
-			return errors.New("option " + string(rune(i+1)) + ": " + err.Error())
-		}
-	}
-
 	// Type-specific validations
 	switch req.Type {
 	case PollTypeOpenText:
@@ -112,23 +94,141 @@ func (req *CreatePollRequest) Validate() error {
 		return errors.New("department_id is required for department visibility")
 	}
 
+	rules := &Poll{
+		Type:                 req.Type,
+		Visibility:           req.Visibility,
+		WeightedVoting:       req.WeightedVoting,
+		QuorumPercent:        req.QuorumPercent,
+		AllowDelegation:      req.AllowDelegation,
+		DelegationVisibility: req.DelegationVisibility,
+		AllowAnonymous:       req.AllowAnonymous,
+	}
+	if err := rules.ValidateVotingRules(); err != nil {
+		return err
+	}
+	if len(req.ParticipantWeights) > 0 {
+		if !req.WeightedVoting {
+			return errors.New("participant weights require weighted voting")
+		}
+		invited := make(map[uint]bool, len(req.ParticipantIDs))
+		for _, userID := range req.ParticipantIDs {
+			invited[userID] = true
+		}
+		for userID, weight := range req.ParticipantWeights {
+			if !invited[userID] {
+				return fmt.Errorf("weight is set for user %d who is not a participant", userID)
+			}
+			if err := ValidateParticipantWeight(weight); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateVotingRules checks that weighted voting, quorum and delegation are used by invite-only
+// polls, whose participants form the electorate, and that weights and delegated votes are counted
+// by choice polls only
+func (p *Poll) ValidateVotingRules() error {
+	if !p.WeightedVoting && p.QuorumPercent == nil && !p.AllowDelegation {
+		return nil
+	}
+	if p.Visibility != PollVisibilityInviteOnly {
+		return errors.New("weighted voting, quorum and delegation require invite-only visibility")
+	}
+	choice := p.Type == PollTypeSingleChoice || p.Type == PollTypeMultipleChoice
+	if p.WeightedVoting && !choice {
+		return errors.New("weighted voting is only available for single and multiple choice polls")
+	}
+	if p.AllowDelegation && !choice {
+		return errors.New("delegation is only available for single and multiple choice polls")
+	}
+	if p.AllowDelegation && p.AllowAnonymous {
+		// Delegated votes are attributed to the delegate who cast them
+		return errors.New("delegation can't be used with anonymous voting")
+	}
+	if p.QuorumPercent != nil && (*p.QuorumPercent <= 0 || *p.QuorumPercent > 100) {
+		return errors.New("quorum percent must be greater than 0 and at most 100")
+	}
+	switch p.DelegationVisibility {
+	case "", DelegationVisibilityPrivate, DelegationVisibilityCreator, DelegationVisibilityParticipants:
+	default:
+		return fmt.Errorf("invalid delegation visibility %q", p.DelegationVisibility)
+	}
+	return nil
+}
+
+// ValidateParticipantWeight validates the voting weight of a poll participant
+func ValidateParticipantWeight(weight float64) error {
+	if weight <= 0 || weight > MaxParticipantWeight {
+		return fmt.Errorf("participant weight must be greater than 0 and at most %d", MaxParticipantWeight)
+	}
 	return nil
 }
 
 // ValidateCreatePollOptionRequest validates poll option creation request
 func (req *CreatePollOptionRequest) Validate() error {
-	if req.Text == "" {
-		return errors.New("option text is required")
+	return validation.Struct(req)
+}
+
+// ValidateUpdatePollOptionRequest validates poll option update request
+func (req *UpdatePollOptionRequest) Validate() error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	if req.Text == nil && req.Description == nil && req.Color == nil && req.ImageURL == nil {
+		return errors.New("at least one field is required")
 	}
-	if len(req.Text) > MaxOptionText {
-		return errors.New("option text is too long")
+
+	return nil
+}
+
+// ValidateReorderPollOptionsRequest validates poll options reorder request
+func (req *ReorderPollOptionsRequest) Validate() error {
+	return validation.Struct(req)
+}
+
+// ValidateAddParticipantsRequest validates request for adding participants
+func (req *AddParticipantsRequest) Validate() error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	added := make(map[uint]bool, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		added[userID] = true
 	}
-	if len(req.Description) > MaxOptionDescription {
-		return errors.New("option description is too long")
+	for userID, weight := range req.Weights {
+		if !added[userID] {
+			return fmt.Errorf("weight is set for user %d who is not being added", userID)
+		}
+		if err := ValidateParticipantWeight(weight); err != nil {
+			return err
+		}
 	}
-	if req.Color != "" && len(req.Color) != 7 {
-		return errors.New("invalid color format")
+
+	return nil
+}
+
+// ValidateUpdatePollDeadlineRequest validates poll deadline change request
+func (req *UpdatePollDeadlineRequest) Validate() error {
+	return validation.Struct(req)
+}
+
+// ValidateUpdatePollRequest validates poll update request
+func (req *UpdatePollRequest) Validate() error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	if req.StartTime != nil && req.EndTime != nil {
+		if req.EndTime.Before(*req.StartTime) {
+			return ErrPollInvalidTimeRange
+		}
 	}
+
 	return nil
 }
 
@@ -194,35 +294,8 @@ func (req *VotePollRequest) Validate(poll *Poll) error {
 
 // ValidatePollFilterRequest validates poll filter request
 func (req *PollFilterRequest) Validate() error {
-	// Validate status
-	if req.Status != "" && !isValidPollStatus(req.Status) {
-		return errors.New("invalid poll status")
-	}
-
-	// Validate type
-	if req.Type != "" && !isValidPollType(req.Type) {
-		return errors.New("invalid poll type")
-	}
-
-	// Validate visibility
-	if req.Visibility != "" && !isValidPollVisibility(req.Visibility) {
-		return errors.New("invalid poll visibility")
-	}
-
-	// Validate pagination
-	if req.Limit < 0 || req.Limit > MaxLimit {
-		return errors.New("invalid limit")
-	}
-	if req.Offset < 0 {
-		return errors.New("invalid offset")
-	}
-
-	// Validate sorting
-	if req.SortBy != "" && !isValidSortField(req.SortBy) {
-		return errors.New("invalid sort field")
-	}
-	if req.SortOrder != "" && req.SortOrder != "asc" && req.SortOrder != "desc" {
-		return errors.New("invalid sort order")
+	if err := validation.Struct(req); err != nil {
+		return err
 	}
 
 	// Validate date ranges
@@ -240,69 +313,34 @@ func (req *PollFilterRequest) Validate() error {
 	return nil
 }
 
-// Helper validation functions
-func isValidPollType(pollType PollType) bool {
-	validTypes := []PollType{
-		PollTypeSingleChoice,
-		PollTypeMultipleChoice,
-		PollTypeRanking,
-		PollTypeRating,
-		PollTypeOpenText,
-	}
-	for _, valid := range validTypes {
-		if pollType == valid {
-			return true
-		}
-	}
-	return false
-}
-
-func isValidPollStatus(status PollStatus) bool {
-	validStatuses := []PollStatus{
-		PollStatusDraft,
-		PollStatusActive,
-		PollStatusClosed,
-		PollStatusArchived,
-		PollStatusCancelled,
-	}
-	for _, valid := range validStatuses {
-		if status == valid {
-			return true
-		}
+// IsValid checks if the poll type is a known type
+func (t PollType) IsValid() bool {
+	switch t {
+	case PollTypeSingleChoice, PollTypeMultipleChoice, PollTypeRanking, PollTypeRating, PollTypeOpenText:
+		return true
+	default:
+		return false
 	}
-	return false
 }
 
-func isValidPollVisibility(visibility PollVisibility) bool {
-	validVisibilities := []PollVisibility{
-		PollVisibilityPublic,
-		PollVisibilityDepartment,
-		PollVisibilityInviteOnly,
-		PollVisibilityPrivate,
+// IsValid checks if the poll status is a known status
+func (s PollStatus) IsValid() bool {
+	switch s {
+	case PollStatusDraft, PollStatusActive, PollStatusClosed, PollStatusArchived, PollStatusCancelled:
+		return true
+	default:
+		return false
 	}
-	for _, valid := range validVisibilities {
-		if visibility == valid {
-			return true
-		}
-	}
-	return false
 }
 
-func isValidSortField(field string) bool {
-	validFields := []string{
-		"created_at",
-		"updated_at",
-		"title",
-		"start_time",
-		"end_time",
-		"total_votes",
-	}
-	for _, valid := range validFields {
-		if field == valid {
-			return true
-		}
+// IsValid checks if the poll visibility is a known visibility
+func (v PollVisibility) IsValid() bool {
+	switch v {
+	case PollVisibilityPublic, PollVisibilityDepartment, PollVisibilityInviteOnly, PollVisibilityPrivate:
+		return true
+	default:
+		return false
 	}
-	return false
 }
 
 // File: services/poll/models/utils.go
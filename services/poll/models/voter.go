@@ -0,0 +1,35 @@
+// File: services/poll/models/voter.go
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+)
+
+var (
+	voterHashMu  sync.RWMutex
+	voterHashKey []byte
+)
+
+// SetVoterHashKey sets the secret key used to link votes to voters.
+// It must be set once at startup, before any vote is created.
+func SetVoterHashKey(key string) {
+	voterHashMu.Lock()
+	defer voterHashMu.Unlock()
+	voterHashKey = []byte(key)
+}
+
+// VoterHash returns a keyed hash identifying the voter within a poll. Anonymous votes
+// store only this hash, so they can be changed or retracted by their voter and counted
+// once per voter, while the hash of one poll cannot be matched against another.
+func VoterHash(pollID, userID uint) string {
+	voterHashMu.RLock()
+	defer voterHashMu.RUnlock()
+
+	mac := hmac.New(sha256.New, voterHashKey)
+	mac.Write([]byte(strconv.FormatUint(uint64(pollID), 10) + ":" + strconv.FormatUint(uint64(userID), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
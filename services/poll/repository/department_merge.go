@@ -0,0 +1,39 @@
+// File: services/poll/repository/department_merge.go
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/poll/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeDepartments moves polls of a merged department to the department it was merged into.
+// It returns the moved polls.
+func (r *pollRepository) MergeDepartments(departmentID, mergedID uint) ([]*models.Poll, error) {
+	var polls []*models.Poll
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("department_id = ?", mergedID).Find(&polls).Error; err != nil {
+			return fmt.Errorf("failed to get department polls: %w", err)
+		}
+		if len(polls) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Poll{}).Where("department_id = ?", mergedID).
+			Update("department_id", departmentID).Error; err != nil {
+			return fmt.Errorf("failed to move department polls: %w", err)
+		}
+		for _, poll := range polls {
+			poll.DepartmentID = &departmentID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return polls, nil
+}
@@ -0,0 +1,214 @@
+// File: services/poll/repository/poll_category_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// PollCategoryRepository defines the interface for poll category data operations
+type PollCategoryRepository interface {
+	Create(category *models.PollCategory) error
+	GetByID(id uint) (*models.PollCategory, error)
+	Find(value string) (*models.PollCategory, error)
+	List(includeArchived bool) ([]*models.PollCategory, error)
+	Update(category *models.PollCategory) error
+	Reorder(ids []uint) error
+	GetPollCategoryValues() ([]*models.PollCategoryValue, error)
+	Rename(from, to string) (*models.PollCategoryMigrationReport, error)
+}
+
+// pollCategoryRepository implements PollCategoryRepository interface
+type pollCategoryRepository struct {
+	db *database.DB
+}
+
+// NewPollCategoryRepository creates a new poll category repository
+func NewPollCategoryRepository(db *database.DB) PollCategoryRepository {
+	return &pollCategoryRepository{
+		db: db,
+	}
+}
+
+// Create adds a category after the existing ones
+func (r *pollCategoryRepository) Create(category *models.PollCategory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.PollCategory{}).Where("slug = ?", category.Slug).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check poll category slug: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("poll category %q already exists", category.Slug)
+		}
+
+		var position int
+		if err := tx.Model(&models.PollCategory{}).Select("COALESCE(MAX(position), 0)").Scan(&position).Error; err != nil {
+			return fmt.Errorf("failed to get poll category position: %w", err)
+		}
+		category.Position = position + 1
+
+		if err := tx.Create(category).Error; err != nil {
+			return fmt.Errorf("failed to create poll category: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a category by ID
+func (r *pollCategoryRepository) GetByID(id uint) (*models.PollCategory, error) {
+	var category models.PollCategory
+	err := r.db.First(&category, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("poll category not found")
+		}
+		return nil, fmt.Errorf("failed to get poll category: %w", err)
+	}
+	return &category, nil
+}
+
+// Find returns the category with the slug or name, ignoring case. An exact slug wins over names.
+func (r *pollCategoryRepository) Find(value string) (*models.PollCategory, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	var categories []*models.PollCategory
+	err := r.db.Where("LOWER(slug) = ? OR LOWER(name) = ?", value, value).
+		Order("id ASC").
+		Find(&categories).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find poll category: %w", err)
+	}
+	for _, category := range categories {
+		if strings.ToLower(category.Slug) == value {
+			return category, nil
+		}
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("poll category not found")
+	}
+	return categories[0], nil
+}
+
+// List returns categories in their order
+func (r *pollCategoryRepository) List(includeArchived bool) ([]*models.PollCategory, error) {
+	query := r.db.Model(&models.PollCategory{})
+	if !includeArchived {
+		query = query.Where("is_archived = ?", false)
+	}
+
+	var categories []*models.PollCategory
+	if err := query.Order("position ASC, id ASC").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to list poll categories: %w", err)
+	}
+	return categories, nil
+}
+
+// Update saves changes of a category
+func (r *pollCategoryRepository) Update(category *models.PollCategory) error {
+	if err := r.db.Save(category).Error; err != nil {
+		return fmt.Errorf("failed to update poll category: %w", err)
+	}
+	return nil
+}
+
+// Reorder moves the categories to the front in the given order, the rest follow in their current order
+func (r *pollCategoryRepository) Reorder(ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var categories []*models.PollCategory
+		if err := tx.Order("position ASC, id ASC").Find(&categories).Error; err != nil {
+			return fmt.Errorf("failed to get poll categories: %w", err)
+		}
+
+		byID := make(map[uint]*models.PollCategory, len(categories))
+		for _, category := range categories {
+			byID[category.ID] = category
+		}
+
+		ordered := make([]*models.PollCategory, 0, len(categories))
+		listed := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			category, ok := byID[id]
+			if !ok {
+				return fmt.Errorf("poll category not found")
+			}
+			if listed[id] {
+				return fmt.Errorf("validation failed: category %d is listed twice", id)
+			}
+			listed[id] = true
+			ordered = append(ordered, category)
+		}
+		for _, category := range categories {
+			if !listed[category.ID] {
+				ordered = append(ordered, category)
+			}
+		}
+
+		for i, category := range ordered {
+			if category.Position == i+1 {
+				continue
+			}
+			if err := tx.Model(category).Update("position", i+1).Error; err != nil {
+				return fmt.Errorf("failed to reorder poll categories: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetPollCategoryValues returns distinct non-empty categories of polls, most used first
+func (r *pollCategoryRepository) GetPollCategoryValues() ([]*models.PollCategoryValue, error) {
+	var values []*models.PollCategoryValue
+	err := r.db.Model(&models.Poll{}).
+		Select("category, COUNT(*) as count").
+		Where("category <> ''").
+		Group("category").
+		Order("count DESC, category ASC").
+		Scan(&values).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll category values: %w", err)
+	}
+	return values, nil
+}
+
+// Rename moves polls and category delegations from one category value to another. A delegator
+// who already delegated the target category keeps that delegation, the other one is dropped.
+func (r *pollCategoryRepository) Rename(from, to string) (*models.PollCategoryMigrationReport, error) {
+	report := &models.PollCategoryMigrationReport{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Poll{}).Where("category = ?", from).Update("category", to)
+		if result.Error != nil {
+			return fmt.Errorf("failed to rename poll category: %w", result.Error)
+		}
+		report.PollsUpdated = result.RowsAffected
+
+		result = tx.Model(&models.PollDelegation{}).
+			Where("poll_id = 0 AND category = ?", from).
+			Where("delegator_id NOT IN (?)", tx.Model(&models.PollDelegation{}).
+				Select("delegator_id").
+				Where("poll_id = 0 AND category = ?", to)).
+			Update("category", to)
+		if result.Error != nil {
+			return fmt.Errorf("failed to rename delegation category: %w", result.Error)
+		}
+		report.DelegationsUpdated = result.RowsAffected
+
+		result = tx.Where("poll_id = 0 AND category = ?", from).Delete(&models.PollDelegation{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to drop duplicate delegations: %w", result.Error)
+		}
+		report.DelegationsDropped = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
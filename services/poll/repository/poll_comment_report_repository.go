@@ -0,0 +1,121 @@
+// File: services/poll/repository/poll_comment_report_repository.go
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/poll/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateReport records a report of a poll comment, each user reports a comment once
+func (r *pollCommentRepository) CreateReport(report *models.PollCommentReport) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "comment_id"}, {Name: "reporter_id"}},
+		DoNothing: true,
+	}).Create(report)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create comment report: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("cannot report a comment twice")
+	}
+	return nil
+}
+
+// CountPendingReports returns the number of reports of a comment waiting for a moderator
+func (r *pollCommentRepository) CountPendingReports(commentID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PollCommentReport{}).
+		Where("comment_id = ? AND status = ?", commentID, models.CommentReportStatusPending).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comment reports: %w", err)
+	}
+	return count, nil
+}
+
+// GetPendingReports returns reports of a comment waiting for a moderator, oldest first
+func (r *pollCommentRepository) GetPendingReports(commentID uint) ([]*models.PollCommentReport, error) {
+	var reports []*models.PollCommentReport
+	err := r.db.Where("comment_id = ? AND status = ?", commentID, models.CommentReportStatusPending).
+		Order("id ASC").
+		Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment reports: %w", err)
+	}
+	return reports, nil
+}
+
+// GetReportQueue returns comments with pending reports, most reported first. With a non-zero
+// pollCreatorID only comments on polls of that user are returned. Deleted comments are skipped.
+func (r *pollCommentRepository) GetReportQueue(pollCreatorID uint, limit, offset int) ([]*models.CommentReportSummary, int64, error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Table("poll_comment_reports AS r").
+			Joins("JOIN poll_comments c ON c.id = r.comment_id AND c.deleted_at IS NULL").
+			Where("r.status = ?", models.CommentReportStatusPending)
+		if pollCreatorID != 0 {
+			db = db.Joins("JOIN polls p ON p.id = r.poll_id").Where("p.created_by = ?", pollCreatorID)
+		}
+		return db
+	}
+
+	var total int64
+	err := r.db.Scopes(scope).Distinct("r.comment_id").Count(&total).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reported comments: %w", err)
+	}
+
+	var summaries []*models.CommentReportSummary
+	err = r.db.Scopes(scope).
+		Select("r.comment_id, r.poll_id, COUNT(*) AS report_count, MAX(r.id) AS last_report_id").
+		Group("r.comment_id, r.poll_id").
+		Order("report_count DESC, last_report_id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get reported comments: %w", err)
+	}
+
+	return summaries, total, nil
+}
+
+// ResolveReports closes pending reports of a comment with the moderator's decision and returns
+// how many were closed
+func (r *pollCommentRepository) ResolveReports(commentID uint, status models.CommentReportStatus, resolvedBy uint) (int64, error) {
+	result := r.db.Model(&models.PollCommentReport{}).
+		Where("comment_id = ? AND status = ?", commentID, models.CommentReportStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": resolvedBy,
+			"resolved_at": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to resolve comment reports: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// SetHidden hides a comment from participants or shows it again
+func (r *pollCommentRepository) SetHidden(commentID uint, hidden bool) error {
+	updates := map[string]interface{}{
+		"is_hidden": hidden,
+		"hidden_at": nil,
+	}
+	if hidden {
+		updates["hidden_at"] = time.Now()
+	}
+
+	result := r.db.Model(&models.PollComment{}).Where("id = ?", commentID).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update comment visibility: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("poll comment not found")
+	}
+	return nil
+}
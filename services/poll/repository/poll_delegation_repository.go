@@ -0,0 +1,105 @@
+// File: services/poll/repository/poll_delegation_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// PollDelegationRepository defines the interface for vote delegation data operations
+type PollDelegationRepository interface {
+	Save(delegation *models.PollDelegation) error
+	Delete(delegatorID, pollID uint, category string) error
+	GetForPoll(pollID uint, category string) ([]*models.PollDelegation, error)
+	GetForCategory(category string) ([]*models.PollDelegation, error)
+	GetByUser(userID uint) ([]*models.PollDelegation, error)
+}
+
+// pollDelegationRepository implements PollDelegationRepository interface
+type pollDelegationRepository struct {
+	db *database.DB
+}
+
+// NewPollDelegationRepository creates a new vote delegation repository
+func NewPollDelegationRepository(db *database.DB) PollDelegationRepository {
+	return &pollDelegationRepository{
+		db: db,
+	}
+}
+
+// Save creates the delegation of its scope or replaces the delegate of an existing one
+func (r *pollDelegationRepository) Save(delegation *models.PollDelegation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.PollDelegation
+		err := tx.Where("delegator_id = ? AND poll_id = ? AND category = ?",
+			delegation.DelegatorID, delegation.PollID, delegation.Category).
+			First(&existing).Error
+		switch {
+		case err == nil:
+			delegation.ID = existing.ID
+			delegation.CreatedAt = existing.CreatedAt
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to get delegation: %w", err)
+		}
+
+		if err := tx.Save(delegation).Error; err != nil {
+			return fmt.Errorf("failed to save delegation: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete revokes a delegation of a poll, or of a category when pollID is zero
+func (r *pollDelegationRepository) Delete(delegatorID, pollID uint, category string) error {
+	result := r.db.Where("delegator_id = ? AND poll_id = ? AND category = ?", delegatorID, pollID, category).
+		Delete(&models.PollDelegation{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete delegation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("delegation not found")
+	}
+	return nil
+}
+
+// GetForPoll returns delegations that apply to a poll: those made for the poll itself and
+// those made for its category
+func (r *pollDelegationRepository) GetForPoll(pollID uint, category string) ([]*models.PollDelegation, error) {
+	query := r.db.Where("poll_id = ?", pollID)
+	if category != "" {
+		query = query.Or("poll_id = 0 AND category = ?", category)
+	}
+
+	var delegations []*models.PollDelegation
+	if err := query.Order("id ASC").Find(&delegations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get poll delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// GetForCategory returns delegations made for a category
+func (r *pollDelegationRepository) GetForCategory(category string) ([]*models.PollDelegation, error) {
+	var delegations []*models.PollDelegation
+	err := r.db.Where("poll_id = 0 AND category = ?", category).Order("id ASC").Find(&delegations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// GetByUser returns delegations the user made or received
+func (r *pollDelegationRepository) GetByUser(userID uint) ([]*models.PollDelegation, error) {
+	var delegations []*models.PollDelegation
+	err := r.db.Where("delegator_id = ? OR delegate_id = ?", userID, userID).
+		Order("created_at DESC").
+		Find(&delegations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user delegations: %w", err)
+	}
+	return delegations, nil
+}
@@ -8,10 +8,16 @@ import (
 
 	"tachyon-messenger/services/poll/models"
 	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// voterIdentitySQL identifies the voter of a vote row: the voter hash, or the user ID
+// and vote ID for votes created before voter hashes were stored
+const voterIdentitySQL = "COALESCE(NULLIF(voter_hash, ''), 'u' || user_id, 'v' || id)"
+
 // PollOptionRepository defines the interface for poll option data operations
 type PollOptionRepository interface {
 	Create(option *models.PollOption) error
@@ -47,7 +53,11 @@ func (r *pollOptionRepository) Create(option *models.PollOption) error {
 
 // CreateMultiple creates multiple poll options
 func (r *pollOptionRepository) CreateMultiple(options []*models.PollOption) error {
-	if err := r.db.Create(&options).Error; err != nil {
+	if len(options) == 0 {
+		return nil
+	}
+
+	if err := r.db.CreateInBatches(options, r.db.BatchSizeFor(&models.PollOption{})).Error; err != nil {
 		return fmt.Errorf("failed to create poll options: %w", err)
 	}
 	return nil
@@ -156,14 +166,20 @@ type PollVoteRepository interface {
 	GetByOptionID(optionID uint) ([]*models.PollVote, error)
 	Update(vote *models.PollVote) error
 	Delete(id uint) error
-	DeleteByUserAndPoll(userID uint, pollID uint) error
+	DeleteByUserAndPoll(userID uint, pollID uint) (int64, error)
+	ReplaceUserVotes(userID uint, pollID uint, votes []*models.PollVote, receipt *models.PollVoteReceipt) error
+	GetReceipt(pollID uint, receiptHash string) (*models.PollVoteReceipt, error)
 	HasUserVoted(userID uint, pollID uint) (bool, error)
 	GetVoteCount(pollID uint) (int64, error)
 	GetVoterCount(pollID uint) (int64, error)
+	GetVoteTotals(pollID uint) (votes int64, voters int64, err error)
 	GetOptionVoteCounts(pollID uint) (map[uint]int64, error)
-	GetRatingStats(pollID uint, optionID uint) (*models.RatingStats, error)
-	GetRankingStats(pollID uint, optionID uint) (*models.RankingStats, error)
+	GetVoterVotes(pollID uint) ([]models.VoterVote, error)
+	GetVoteTimeline(pollID uint, bucket time.Duration) ([]models.VoteBucketCount, error)
+	GetRatingStats(pollID uint) (map[uint]*models.RatingStats, error)
+	GetRankingStats(pollID uint) (map[uint]*models.RankingStats, error)
 	GetTextResponses(pollID uint) ([]string, error)
+	WithQueryCounter(counter *database.QueryCounter) PollVoteRepository
 }
 
 // pollVoteRepository implements PollVoteRepository interface
@@ -178,6 +194,13 @@ func NewPollVoteRepository(db *database.DB) PollVoteRepository {
 	}
 }
 
+// WithQueryCounter returns a repository whose queries are counted by counter
+func (r *pollVoteRepository) WithQueryCounter(counter *database.QueryCounter) PollVoteRepository {
+	return &pollVoteRepository{
+		db: r.db.WithQueryCounter(counter),
+	}
+}
+
 // Create creates a new poll vote
 func (r *pollVoteRepository) Create(vote *models.PollVote) error {
 	if err := r.db.Create(vote).Error; err != nil {
@@ -188,7 +211,11 @@ func (r *pollVoteRepository) Create(vote *models.PollVote) error {
 
 // CreateMultiple creates multiple poll votes
 func (r *pollVoteRepository) CreateMultiple(votes []*models.PollVote) error {
-	if err := r.db.Create(&votes).Error; err != nil {
+	if len(votes) == 0 {
+		return nil
+	}
+
+	if err := r.db.CreateInBatches(votes, r.db.BatchSizeFor(&models.PollVote{})).Error; err != nil {
 		return fmt.Errorf("failed to create poll votes: %w", err)
 	}
 	return nil
@@ -217,10 +244,17 @@ func (r *pollVoteRepository) GetByPollID(pollID uint) ([]*models.PollVote, error
 	return votes, nil
 }
 
-// GetByUserID retrieves all votes by a user for a specific poll
+// userVotesScope selects votes of a user in a poll, including anonymous ones
+func userVotesScope(userID uint, pollID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("poll_id = ? AND (user_id = ? OR voter_hash = ?)", pollID, userID, models.VoterHash(pollID, userID))
+	}
+}
+
+// GetByUserID retrieves all votes by a user for a specific poll, including anonymous ones
 func (r *pollVoteRepository) GetByUserID(userID uint, pollID uint) ([]*models.PollVote, error) {
 	var votes []*models.PollVote
-	err := r.db.Where("user_id = ? AND poll_id = ?", userID, pollID).Find(&votes).Error
+	err := r.db.Scopes(userVotesScope(userID, pollID)).Find(&votes).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user votes: %w", err)
 	}
@@ -261,20 +295,64 @@ func (r *pollVoteRepository) Delete(id uint) error {
 	return nil
 }
 
-// DeleteByUserAndPoll deletes all votes by a user for a specific poll
-func (r *pollVoteRepository) DeleteByUserAndPoll(userID uint, pollID uint) error {
-	err := r.db.Where("user_id = ? AND poll_id = ?", userID, pollID).Delete(&models.PollVote{}).Error
+// DeleteByUserAndPoll deletes all votes by a user for a specific poll and returns how many were deleted
+func (r *pollVoteRepository) DeleteByUserAndPoll(userID uint, pollID uint) (int64, error) {
+	result := r.db.Scopes(userVotesScope(userID, pollID)).Delete(&models.PollVote{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete user votes: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ReplaceUserVotes atomically replaces all votes of a user in a poll with votes.
+// A non-nil receipt of the new ballot is saved in the same transaction.
+// The poll row is locked so that concurrent votes of the same user are serialized.
+func (r *pollVoteRepository) ReplaceUserVotes(userID uint, pollID uint, votes []*models.PollVote, receipt *models.PollVoteReceipt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var poll models.Poll
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&poll, pollID).Error; err != nil {
+			return fmt.Errorf("failed to lock poll: %w", err)
+		}
+
+		if err := tx.Scopes(userVotesScope(userID, pollID)).Delete(&models.PollVote{}).Error; err != nil {
+			return fmt.Errorf("failed to delete previous votes: %w", err)
+		}
+
+		if len(votes) == 0 {
+			return nil
+		}
+
+		if err := tx.CreateInBatches(votes, r.db.BatchSizeFor(&models.PollVote{})).Error; err != nil {
+			return fmt.Errorf("failed to create poll votes: %w", err)
+		}
+
+		if receipt != nil {
+			if err := tx.Create(receipt).Error; err != nil {
+				return fmt.Errorf("failed to create vote receipt: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetReceipt retrieves a vote receipt of a poll by the hash of the receipt
+func (r *pollVoteRepository) GetReceipt(pollID uint, receiptHash string) (*models.PollVoteReceipt, error) {
+	var receipt models.PollVoteReceipt
+	err := r.db.Where("receipt_hash = ? AND poll_id = ?", receiptHash, pollID).First(&receipt).Error
 	if err != nil {
-		return fmt.Errorf("failed to delete user votes: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("vote receipt not found")
+		}
+		return nil, fmt.Errorf("failed to get vote receipt: %w", err)
 	}
-	return nil
+	return &receipt, nil
 }
 
-// HasUserVoted checks if a user has voted in a poll
+// HasUserVoted checks if a user has voted in a poll, including anonymously
 func (r *pollVoteRepository) HasUserVoted(userID uint, pollID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.PollVote{}).
-		Where("user_id = ? AND poll_id = ?", userID, pollID).
+		Scopes(userVotesScope(userID, pollID)).
 		Count(&count).Error
 	if err != nil {
 		return false, fmt.Errorf("failed to check if user voted: %w", err)
@@ -299,7 +377,7 @@ func (r *pollVoteRepository) GetVoterCount(pollID uint) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.PollVote{}).
 		Where("poll_id = ?", pollID).
-		Distinct("COALESCE(user_id, id)").
+		Distinct(voterIdentitySQL).
 		Count(&count).Error
 	if err != nil {
 		return 0, fmt.Errorf("failed to get voter count: %w", err)
@@ -307,6 +385,22 @@ func (r *pollVoteRepository) GetVoterCount(pollID uint) (int64, error) {
 	return count, nil
 }
 
+// GetVoteTotals returns total number of votes and unique voters for a poll in one query
+func (r *pollVoteRepository) GetVoteTotals(pollID uint) (int64, int64, error) {
+	var totals struct {
+		Votes  int64
+		Voters int64
+	}
+	err := r.db.Model(&models.PollVote{}).
+		Select("COUNT(*) AS votes, COUNT(DISTINCT "+voterIdentitySQL+") AS voters").
+		Where("poll_id = ?", pollID).
+		Scan(&totals).Error
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get vote totals: %w", err)
+	}
+	return totals.Votes, totals.Voters, nil
+}
+
 // GetOptionVoteCounts returns vote counts for each option in a poll
 func (r *pollVoteRepository) GetOptionVoteCounts(pollID uint) (map[uint]int64, error) {
 	type optionCount struct {
@@ -332,94 +426,155 @@ func (r *pollVoteRepository) GetOptionVoteCounts(pollID uint) (map[uint]int64, e
 	return result, nil
 }
 
-// GetRatingStats calculates rating statistics for an option
-func (r *pollVoteRepository) GetRatingStats(pollID uint, optionID uint) (*models.RatingStats, error) {
-	var ratings []int
+// GetVoterVotes returns the voter and option of every vote in a poll, for weighting votes by voter
+func (r *pollVoteRepository) GetVoterVotes(pollID uint) ([]models.VoterVote, error) {
+	var votes []models.VoterVote
 	err := r.db.Model(&models.PollVote{}).
-		Select("rating_value").
-		Where("poll_id = ? AND option_id = ? AND rating_value IS NOT NULL", pollID, optionID).
-		Pluck("rating_value", &ratings).Error
+		Select("voter_hash, user_id, option_id").
+		Where("poll_id = ?", pollID).
+		Scan(&votes).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rating values: %w", err)
+		return nil, fmt.Errorf("failed to get voter votes: %w", err)
 	}
+	return votes, nil
+}
 
-	if len(ratings) == 0 {
-		return &models.RatingStats{
-			OptionID:     optionID,
-			TotalRatings: 0,
-		}, nil
+// GetVoteTimeline returns vote counts of a poll grouped by option and time bucket in one query.
+// Buckets are numbered from the Unix epoch, so bucket n starts at n*bucket in UTC.
+func (r *pollVoteRepository) GetVoteTimeline(pollID uint, bucket time.Duration) ([]models.VoteBucketCount, error) {
+	seconds := int64(bucket / time.Second)
+	if seconds <= 0 {
+		return nil, fmt.Errorf("invalid timeline bucket: %s", bucket)
 	}
 
-	stats := &models.RatingStats{
-		OptionID:     optionID,
-		TotalRatings: len(ratings),
-		Distribution: make(map[int]int),
+	bucketSQL := r.epochBucketSQL(seconds)
+	var counts []models.VoteBucketCount
+	err := r.db.Model(&models.PollVote{}).
+		Select(bucketSQL+" AS bucket, option_id, COUNT(*) AS count").
+		Where("poll_id = ?", pollID).
+		Group(bucketSQL + ", option_id").
+		Order("bucket").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote timeline: %w", err)
 	}
 
-	// Calculate min, max, sum
-	sum := 0
-	stats.Min = ratings[0]
-	stats.Max = ratings[0]
+	return counts, nil
+}
+
+// epochBucketSQL returns the SQL expression numbering the bucket of the given width the vote was cast in.
+// SQLite has no EXTRACT, it is only used by tests.
+func (r *pollVoteRepository) epochBucketSQL(seconds int64) string {
+	if r.db.Dialector.Name() == "sqlite" {
+		return fmt.Sprintf("CAST(strftime('%%s', created_at) AS INTEGER) / %d", seconds)
+	}
+	return fmt.Sprintf("CAST(FLOOR(EXTRACT(EPOCH FROM created_at) / %d) AS BIGINT)", seconds)
+}
+
+// GetRatingStats calculates rating statistics for every option of a poll in one query
+func (r *pollVoteRepository) GetRatingStats(pollID uint) (map[uint]*models.RatingStats, error) {
+	values, err := r.getOptionValueCounts(pollID, "rating_value")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating values: %w", err)
+	}
 
-	for _, rating := range ratings {
-		sum += rating
-		if rating < stats.Min {
-			stats.Min = rating
+	result := make(map[uint]*models.RatingStats, len(values))
+	for optionID, counts := range values {
+		summary := summarizeValueCounts(counts)
+		result[optionID] = &models.RatingStats{
+			OptionID:     optionID,
+			Average:      summary.average,
+			Min:          summary.min,
+			Max:          summary.max,
+			TotalRatings: summary.total,
+			Distribution: summary.distribution,
 		}
-		if rating > stats.Max {
-			stats.Max = rating
+	}
+
+	return result, nil
+}
+
+// GetRankingStats calculates ranking statistics for every option of a poll in one query
+func (r *pollVoteRepository) GetRankingStats(pollID uint) (map[uint]*models.RankingStats, error) {
+	values, err := r.getOptionValueCounts(pollID, "ranking_value")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ranking values: %w", err)
+	}
+
+	result := make(map[uint]*models.RankingStats, len(values))
+	for optionID, counts := range values {
+		summary := summarizeValueCounts(counts)
+		result[optionID] = &models.RankingStats{
+			OptionID:         optionID,
+			AverageRank:      summary.average,
+			BestRank:         summary.min,
+			WorstRank:        summary.max,
+			TotalRankings:    summary.total,
+			RankDistribution: summary.distribution,
 		}
-		stats.Distribution[rating]++
 	}
 
-	stats.Average = float64(sum) / float64(len(ratings))
+	return result, nil
+}
 
-	return stats, nil
+// optionValueCount is a number of votes with the same value for an option
+type optionValueCount struct {
+	OptionID uint
+	Value    int
+	Count    int
 }
 
-// GetRankingStats calculates ranking statistics for an option
-func (r *pollVoteRepository) GetRankingStats(pollID uint, optionID uint) (*models.RankingStats, error) {
-	var rankings []int
+// getOptionValueCounts groups votes of a poll by option and value of column
+func (r *pollVoteRepository) getOptionValueCounts(pollID uint, column string) (map[uint][]optionValueCount, error) {
+	var rows []optionValueCount
 	err := r.db.Model(&models.PollVote{}).
-		Select("ranking_value").
-		Where("poll_id = ? AND option_id = ? AND ranking_value IS NOT NULL", pollID, optionID).
-		Pluck("ranking_value", &rankings).Error
+		Select("option_id, "+column+" AS value, COUNT(*) AS count").
+		Where("poll_id = ? AND option_id IS NOT NULL AND "+column+" IS NOT NULL", pollID).
+		Group("option_id, " + column).
+		Scan(&rows).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get ranking values: %w", err)
+		return nil, err
 	}
 
-	if len(rankings) == 0 {
-		return &models.RankingStats{
-			OptionID:      optionID,
-			TotalRankings: 0,
-		}, nil
+	result := make(map[uint][]optionValueCount)
+	for _, row := range rows {
+		result[row.OptionID] = append(result[row.OptionID], row)
 	}
+	return result, nil
+}
+
+// valueSummary holds min, max, average and distribution of option values
+type valueSummary struct {
+	total        int
+	min          int
+	max          int
+	average      float64
+	distribution map[int]int
+}
 
-	stats := &models.RankingStats{
-		OptionID:         optionID,
-		TotalRankings:    len(rankings),
-		RankDistribution: make(map[int]int),
+// summarizeValueCounts aggregates grouped values of one option
+func summarizeValueCounts(counts []optionValueCount) valueSummary {
+	summary := valueSummary{
+		distribution: make(map[int]int, len(counts)),
 	}
 
-	// Calculate best, worst, sum
 	sum := 0
-	stats.BestRank = rankings[0]
-	stats.WorstRank = rankings[0]
-
-	for _, ranking := range rankings {
-		sum += ranking
-		if ranking < stats.BestRank {
-			stats.BestRank = ranking
+	for i, count := range counts {
+		if i == 0 || count.Value < summary.min {
+			summary.min = count.Value
 		}
-		if ranking > stats.WorstRank {
-			stats.WorstRank = ranking
+		if i == 0 || count.Value > summary.max {
+			summary.max = count.Value
 		}
-		stats.RankDistribution[ranking]++
+		summary.total += count.Count
+		sum += count.Value * count.Count
+		summary.distribution[count.Value] += count.Count
 	}
 
-	stats.AverageRank = float64(sum) / float64(len(rankings))
-
-	return stats, nil
+	if summary.total > 0 {
+		summary.average = float64(sum) / float64(summary.total)
+	}
+	return summary
 }
 
 // GetTextResponses returns all text responses for a poll
@@ -449,8 +604,11 @@ type PollParticipantRepository interface {
 	DeleteByUserAndPoll(userID uint, pollID uint) error
 	IsParticipant(userID uint, pollID uint) (bool, error)
 	MarkAsVoted(userID uint, pollID uint) error
+	ClearVoted(userID uint, pollID uint) error
+	UpdateWeight(userID uint, pollID uint, weight float64) error
 	MarkAsNotified(userID uint, pollID uint) error
 	GetParticipantCount(pollID uint) (int64, error)
+	WithQueryCounter(counter *database.QueryCounter) PollParticipantRepository
 }
 
 // pollParticipantRepository implements PollParticipantRepository interface
@@ -465,6 +623,13 @@ func NewPollParticipantRepository(db *database.DB) PollParticipantRepository {
 	}
 }
 
+// WithQueryCounter returns a repository whose queries are counted by counter
+func (r *pollParticipantRepository) WithQueryCounter(counter *database.QueryCounter) PollParticipantRepository {
+	return &pollParticipantRepository{
+		db: r.db.WithQueryCounter(counter),
+	}
+}
+
 // Create creates a new poll participant
 func (r *pollParticipantRepository) Create(participant *models.PollParticipant) error {
 	if err := r.db.Create(participant).Error; err != nil {
@@ -475,7 +640,11 @@ func (r *pollParticipantRepository) Create(participant *models.PollParticipant)
 
 // CreateMultiple creates multiple poll participants
 func (r *pollParticipantRepository) CreateMultiple(participants []*models.PollParticipant) error {
-	if err := r.db.Create(&participants).Error; err != nil {
+	if len(participants) == 0 {
+		return nil
+	}
+
+	if err := r.db.CreateInBatches(participants, r.db.BatchSizeFor(&models.PollParticipant{})).Error; err != nil {
 		return fmt.Errorf("failed to create poll participants: %w", err)
 	}
 	return nil
@@ -571,6 +740,31 @@ func (r *pollParticipantRepository) MarkAsVoted(userID uint, pollID uint) error
 	return nil
 }
 
+// UpdateWeight sets the voting weight of a participant
+func (r *pollParticipantRepository) UpdateWeight(userID uint, pollID uint, weight float64) error {
+	result := r.db.Model(&models.PollParticipant{}).
+		Where("user_id = ? AND poll_id = ?", userID, pollID).
+		Update("weight", weight)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update participant weight: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("poll participant not found")
+	}
+	return nil
+}
+
+// ClearVoted marks a participant as not having voted after vote retraction
+func (r *pollParticipantRepository) ClearVoted(userID uint, pollID uint) error {
+	result := r.db.Model(&models.PollParticipant{}).
+		Where("user_id = ? AND poll_id = ?", userID, pollID).
+		Update("voted_at", nil)
+	if result.Error != nil {
+		return fmt.Errorf("failed to clear participant vote: %w", result.Error)
+	}
+	return nil
+}
+
 // MarkAsNotified marks a participant as having been notified
 func (r *pollParticipantRepository) MarkAsNotified(userID uint, pollID uint) error {
 	now := time.Now()
@@ -607,6 +801,19 @@ type PollCommentRepository interface {
 	Delete(id uint) error
 	CountByPollID(pollID uint) (int64, error)
 	GetByUserID(userID uint, limit, offset int) ([]*models.PollComment, error)
+
+	// Reactions
+	AddReaction(reaction *models.PollCommentReaction) error
+	RemoveReaction(commentID, userID uint, emoji string) error
+	GetReactions(commentID uint) ([]*models.PollCommentReaction, error)
+
+	// Moderation
+	CreateReport(report *models.PollCommentReport) error
+	CountPendingReports(commentID uint) (int64, error)
+	GetPendingReports(commentID uint) ([]*models.PollCommentReport, error)
+	GetReportQueue(pollCreatorID uint, limit, offset int) ([]*models.CommentReportSummary, int64, error)
+	ResolveReports(commentID uint, status models.CommentReportStatus, resolvedBy uint) (int64, error)
+	SetHidden(commentID uint, hidden bool) error
 }
 
 // pollCommentRepository implements PollCommentRepository interface
@@ -677,9 +884,8 @@ func (r *pollCommentRepository) GetWithReplies(pollID uint, limit, offset int) (
 	}
 
 	var comments []*models.PollComment
-	err := r.db.Preload("Replies", func(db *gorm.DB) *gorm.DB {
-		return db.Order("created_at ASC")
-	}).Where("poll_id = ? AND parent_id IS NULL", pollID).
+	err := preloadPollCommentThread(r.db.DB).
+		Where("poll_id = ? AND parent_id IS NULL", pollID).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -740,3 +946,66 @@ func (r *pollCommentRepository) GetByUserID(userID uint, limit, offset int) ([]*
 	}
 	return comments, nil
 }
+
+// AddReaction adds an emoji reaction to a poll comment
+func (r *pollCommentRepository) AddReaction(reaction *models.PollCommentReaction) error {
+	// Check if reaction already exists
+	var existing models.PollCommentReaction
+	err := r.db.Where("comment_id = ? AND user_id = ? AND emoji = ?",
+		reaction.CommentID, reaction.UserID, reaction.Emoji).First(&existing).Error
+
+	if err == nil {
+		return fmt.Errorf("reaction already exists")
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check existing reaction: %w", err)
+	}
+
+	if err := r.db.Create(reaction).Error; err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveReaction removes user's emoji reaction from a poll comment
+func (r *pollCommentRepository) RemoveReaction(commentID, userID uint, emoji string) error {
+	result := r.db.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, emoji).
+		Delete(&models.PollCommentReaction{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove reaction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("reaction not found")
+	}
+	return nil
+}
+
+// GetReactions retrieves all reactions for a poll comment
+func (r *pollCommentRepository) GetReactions(commentID uint) ([]*models.PollCommentReaction, error) {
+	var reactions []*models.PollCommentReaction
+	err := r.db.Where("comment_id = ?", commentID).
+		Order("created_at ASC").
+		Find(&reactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment reactions: %w", err)
+	}
+	return reactions, nil
+}
+
+// preloadPollCommentThread preloads replies down to MaxCommentDepth along with reactions on every level
+func preloadPollCommentThread(db *gorm.DB) *gorm.DB {
+	orderByCreated := func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}
+
+	db = db.Preload("Reactions")
+	path := ""
+	for depth := 1; depth <= sharedmodels.MaxCommentDepth; depth++ {
+		path += "Replies"
+		db = db.Preload(path, orderByCreated).Preload(path + ".Reactions")
+		path += "."
+	}
+	return db
+}
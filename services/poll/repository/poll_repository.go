@@ -9,6 +9,7 @@ import (
 
 	"tachyon-messenger/services/poll/models"
 	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
 )
@@ -23,15 +24,27 @@ type PollRepository interface {
 	Delete(id uint) error
 	GetPolls(userID uint, filter *models.PollFilterRequest) ([]*models.Poll, int64, error)
 	SearchPolls(userID uint, query string, filter *models.PollFilterRequest) ([]*models.Poll, int64, error)
+	CountByCategory(userID uint, query string, filter *models.PollFilterRequest) ([]*models.PollCategoryValue, error)
 	GetPollStats(userID uint) (*models.PollStatsResponse, error)
 	GetUserPolls(userID uint, filter *models.PollFilterRequest) ([]*models.Poll, int64, error)
 	GetParticipatedPolls(userID uint, filter *models.PollFilterRequest) ([]*models.Poll, int64, error)
 	GetPollsByStatus(status models.PollStatus, filter *models.PollFilterRequest) ([]*models.Poll, int64, error)
 	GetExpiredPolls() ([]*models.Poll, error)
 	UpdateStatus(id uint, status models.PollStatus) error
+	Close(id uint, outcome models.PollOutcome) error
+	UpdateDeadline(poll *models.Poll, change *models.PollDeadlineChange) error
+	UpdateDeadlineChange(change *models.PollDeadlineChange) error
+	GetDeadlineChanges(pollID uint) ([]*models.PollDeadlineChange, error)
 	Count() (int64, error)
 	CountByCreator(userID uint) (int64, error)
 	CountByStatus(status models.PollStatus) (int64, error)
+	WithQueryCounter(counter *database.QueryCounter) PollRepository
+
+	// Account merge
+	MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error)
+
+	// Department merge
+	MergeDepartments(departmentID, mergedID uint) ([]*models.Poll, error)
 }
 
 // pollRepository implements PollRepository interface
@@ -46,6 +59,13 @@ func NewPollRepository(db *database.DB) PollRepository {
 	}
 }
 
+// WithQueryCounter returns a repository whose queries are counted by counter
+func (r *pollRepository) WithQueryCounter(counter *database.QueryCounter) PollRepository {
+	return &pollRepository{
+		db: r.db.WithQueryCounter(counter),
+	}
+}
+
 // Create creates a new poll
 func (r *pollRepository) Create(poll *models.Poll) error {
 	if err := r.db.Create(poll).Error; err != nil {
@@ -109,12 +129,11 @@ func (r *pollRepository) GetByIDWithAll(id uint) (*models.Poll, error) {
 
 // Update updates an existing poll
 func (r *pollRepository) Update(poll *models.Poll) error {
-	result := r.db.Save(poll)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update poll: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("poll not found")
+	if err := r.db.SaveVersioned(poll); err != nil {
+		if errors.Is(err, database.ErrVersionConflict) {
+			return fmt.Errorf("version conflict: poll was modified or deleted by another request")
+		}
+		return fmt.Errorf("failed to update poll: %w", err)
 	}
 	return nil
 }
@@ -175,8 +194,7 @@ func (r *pollRepository) SearchPolls(userID uint, searchQuery string, filter *mo
 	query = r.applyVisibilityFilter(query, userID)
 
 	// Apply search filter
-	searchTerm := "%" + strings.ToLower(searchQuery) + "%"
-	query = query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
+	query = r.applySearchFilter(query, searchQuery)
 
 	// Apply other filters
 	query = r.applyFilters(query, filter)
@@ -201,6 +219,30 @@ func (r *pollRepository) SearchPolls(userID uint, searchQuery string, filter *mo
 	return polls, total, nil
 }
 
+// CountByCategory counts polls visible to the user per category, with the filters and search
+// query of a list except the category filter. An empty query counts without searching.
+func (r *pollRepository) CountByCategory(userID uint, searchQuery string, filter *models.PollFilterRequest) ([]*models.PollCategoryValue, error) {
+	query := r.applyVisibilityFilter(r.db.Model(&models.Poll{}), userID)
+	if searchQuery != "" {
+		query = r.applySearchFilter(query, searchQuery)
+	}
+	if filter != nil {
+		facetFilter := *filter
+		facetFilter.Category = ""
+		query = r.applyFilters(query, &facetFilter)
+	}
+
+	var values []*models.PollCategoryValue
+	err := query.Select("polls.category, COUNT(*) as count").
+		Group("polls.category").
+		Order("count DESC, polls.category ASC").
+		Scan(&values).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count polls by category: %w", err)
+	}
+	return values, nil
+}
+
 // GetPollStats retrieves poll statistics for a user
 func (r *pollRepository) GetPollStats(userID uint) (*models.PollStatsResponse, error) {
 	stats := &models.PollStatsResponse{}
@@ -397,9 +439,19 @@ func (r *pollRepository) GetExpiredPolls() ([]*models.Poll, error) {
 	return polls, nil
 }
 
-// UpdateStatus updates poll status
+// UpdateStatus updates poll status. The first activation time is kept when a poll is reopened,
+// the quorum outcome is cleared since the poll takes votes again.
 func (r *pollRepository) UpdateStatus(id uint, status models.PollStatus) error {
-	result := r.db.Model(&models.Poll{}).Where("id = ?", id).Update("status", status)
+	updates := map[string]interface{}{
+		"status":  status,
+		"version": gorm.Expr("version + 1"),
+	}
+	if status == models.PollStatusActive {
+		updates["activated_at"] = gorm.Expr("COALESCE(activated_at, ?)", time.Now())
+		updates["outcome"] = ""
+	}
+
+	result := r.db.Model(&models.Poll{}).Where("id = ?", id).Updates(updates)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update poll status: %w", result.Error)
 	}
@@ -409,6 +461,61 @@ func (r *pollRepository) UpdateStatus(id uint, status models.PollStatus) error {
 	return nil
 }
 
+// Close closes an active poll and records its quorum outcome, empty for polls without a quorum.
+// A poll closed meanwhile by another request is reported as not active.
+func (r *pollRepository) Close(id uint, outcome models.PollOutcome) error {
+	result := r.db.Model(&models.Poll{}).
+		Where("id = ? AND status = ?", id, models.PollStatusActive).
+		Updates(map[string]interface{}{
+			"status":  models.PollStatusClosed,
+			"outcome": outcome,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to close poll: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrPollNotActive
+	}
+	return nil
+}
+
+// UpdateDeadline saves the new poll end time and records the change in one transaction
+func (r *pollRepository) UpdateDeadline(poll *models.Poll, change *models.PollDeadlineChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := (&database.DB{DB: tx}).SaveVersioned(poll); err != nil {
+			if errors.Is(err, database.ErrVersionConflict) {
+				return fmt.Errorf("version conflict: poll was modified or deleted by another request")
+			}
+			return fmt.Errorf("failed to update poll deadline: %w", err)
+		}
+
+		change.PollID = poll.ID
+		if err := tx.Create(change).Error; err != nil {
+			return fmt.Errorf("failed to record poll deadline change: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateDeadlineChange updates a recorded deadline change
+func (r *pollRepository) UpdateDeadlineChange(change *models.PollDeadlineChange) error {
+	if err := r.db.Save(change).Error; err != nil {
+		return fmt.Errorf("failed to update poll deadline change: %w", err)
+	}
+	return nil
+}
+
+// GetDeadlineChanges returns deadline changes of a poll, newest first
+func (r *pollRepository) GetDeadlineChanges(pollID uint) ([]*models.PollDeadlineChange, error) {
+	var changes []*models.PollDeadlineChange
+	err := r.db.Where("poll_id = ?", pollID).Order("created_at DESC").Find(&changes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll deadline changes: %w", err)
+	}
+	return changes, nil
+}
+
 // Count returns the total number of polls
 func (r *pollRepository) Count() (int64, error) {
 	var count int64
@@ -449,12 +556,22 @@ func (r *pollRepository) applyVisibilityFilter(query *gorm.DB, userID uint) *gor
 	)
 }
 
+// applySearchFilter matches the query against title and description
+func (r *pollRepository) applySearchFilter(query *gorm.DB, searchQuery string) *gorm.DB {
+	searchTerm := "%" + strings.ToLower(searchQuery) + "%"
+	return query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
+}
+
 // applyFilters applies filters to the query
 func (r *pollRepository) applyFilters(query *gorm.DB, filter *models.PollFilterRequest) *gorm.DB {
 	if filter == nil {
 		return query
 	}
 
+	if filter.Page != nil {
+		query = query.Scopes(filter.Page.FilterScope)
+	}
+
 	if filter.Status != "" {
 		query = query.Where("status = ?", filter.Status)
 	}
@@ -500,38 +617,11 @@ func (r *pollRepository) applyFilters(query *gorm.DB, filter *models.PollFilterR
 
 // applySortingAndPagination applies sorting and pagination to the query
 func (r *pollRepository) applySortingAndPagination(query *gorm.DB, filter *models.PollFilterRequest) *gorm.DB {
-	if filter == nil {
-		return query.Order("created_at DESC").Limit(models.DefaultLimit)
-	}
-
-	// Apply sorting
-	sortBy := filter.SortBy
-	if sortBy == "" {
-		sortBy = "created_at"
-	}
-
-	sortOrder := filter.SortOrder
-	if sortOrder == "" {
-		sortOrder = "desc"
+	if filter == nil || filter.Page == nil {
+		return query.Order("polls.created_at DESC").Limit(models.DefaultLimit)
 	}
 
-	query = query.Order(sortBy + " " + sortOrder)
-
-	// Apply pagination
-	limit := filter.Limit
-	if limit <= 0 {
-		limit = models.DefaultLimit
-	}
-	if limit > models.MaxLimit {
-		limit = models.MaxLimit
-	}
-
-	offset := filter.Offset
-	if offset < 0 {
-		offset = 0
-	}
-
-	return query.Limit(limit).Offset(offset)
+	return query.Scopes(filter.Page.PageScope)
 }
 
 // loadPollStatistics loads computed statistics for polls
@@ -566,7 +656,7 @@ func (r *pollRepository) loadPollStatistics(polls []*models.Poll, userID uint) {
 
 	var voterCounts []voterCount
 	r.db.Model(&models.PollVote{}).
-		Select("poll_id, COUNT(DISTINCT COALESCE(user_id, id)) as count").
+		Select("poll_id, COUNT(DISTINCT "+voterIdentitySQL+") as count").
 		Where("poll_id IN ?", pollIDs).
 		Group("poll_id").
 		Scan(&voterCounts)
@@ -576,10 +666,15 @@ func (r *pollRepository) loadPollStatistics(polls []*models.Poll, userID uint) {
 		PollID uint
 	}
 
+	voterHashes := make([]string, len(pollIDs))
+	for i, pollID := range pollIDs {
+		voterHashes[i] = models.VoterHash(pollID, userID)
+	}
+
 	var userVotes []userVote
 	r.db.Model(&models.PollVote{}).
 		Select("DISTINCT poll_id").
-		Where("poll_id IN ? AND user_id = ?", pollIDs, userID).
+		Where("poll_id IN ? AND (user_id = ? OR voter_hash IN ?)", pollIDs, userID, voterHashes).
 		Scan(&userVotes)
 
 	// Create maps for quick lookup
@@ -0,0 +1,75 @@
+// File: services/poll/repository/poll_result_snapshot_repository.go
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// PollResultSnapshotRepository defines the interface for poll results snapshot data operations
+type PollResultSnapshotRepository interface {
+	Create(snapshot *models.PollResultSnapshot) error
+	GetLatest(pollID uint) (*models.PollResultSnapshot, error)
+	GetByPoll(pollID uint) ([]*models.PollResultSnapshot, error)
+}
+
+// pollResultSnapshotRepository implements PollResultSnapshotRepository interface
+type pollResultSnapshotRepository struct {
+	db *database.DB
+}
+
+// NewPollResultSnapshotRepository creates a new poll results snapshot repository
+func NewPollResultSnapshotRepository(db *database.DB) PollResultSnapshotRepository {
+	return &pollResultSnapshotRepository{
+		db: db,
+	}
+}
+
+// Create saves a snapshot as the next version of the poll's snapshots. Concurrent snapshots
+// of a poll collide on the version index, only one of them is saved.
+func (r *pollResultSnapshotRepository) Create(snapshot *models.PollResultSnapshot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var version int
+		err := tx.Model(&models.PollResultSnapshot{}).
+			Where("poll_id = ?", snapshot.PollID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&version).Error
+		if err != nil {
+			return fmt.Errorf("failed to get snapshot version: %w", err)
+		}
+
+		snapshot.Version = version + 1
+		if err := tx.Create(snapshot).Error; err != nil {
+			return fmt.Errorf("failed to create poll result snapshot: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetLatest returns the latest snapshot of a poll
+func (r *pollResultSnapshotRepository) GetLatest(pollID uint) (*models.PollResultSnapshot, error) {
+	var snapshot models.PollResultSnapshot
+	err := r.db.Where("poll_id = ?", pollID).Order("version DESC").First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrSnapshotNotFound
+		}
+		return nil, fmt.Errorf("failed to get poll result snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// GetByPoll returns all snapshots of a poll, latest first
+func (r *pollResultSnapshotRepository) GetByPoll(pollID uint) ([]*models.PollResultSnapshot, error) {
+	var snapshots []*models.PollResultSnapshot
+	err := r.db.Where("poll_id = ?", pollID).Order("version DESC").Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll result snapshots: %w", err)
+	}
+	return snapshots, nil
+}
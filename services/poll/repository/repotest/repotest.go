@@ -0,0 +1,114 @@
+// Package repotest provides poll repositories on an in-memory SQLite database and
+// fixture factories for usecase and repository tests
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/services/poll/repository"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/database/dbtest"
+)
+
+// Repositories holds all poll repositories backed by one test database
+type Repositories struct {
+	DB           *database.DB
+	Polls        repository.PollRepository
+	Options      repository.PollOptionRepository
+	Votes        repository.PollVoteRepository
+	Participants repository.PollParticipantRepository
+	Comments     repository.PollCommentRepository
+	Delegations  repository.PollDelegationRepository
+	Categories   repository.PollCategoryRepository
+	Snapshots    repository.PollResultSnapshotRepository
+}
+
+// New creates repositories on a fresh test database
+func New(tb testing.TB) *Repositories {
+	tb.Helper()
+
+	db := dbtest.Open(tb, models.Models()...)
+	return &Repositories{
+		DB:           db,
+		Polls:        repository.NewPollRepository(db),
+		Options:      repository.NewPollOptionRepository(db),
+		Votes:        repository.NewPollVoteRepository(db),
+		Participants: repository.NewPollParticipantRepository(db),
+		Comments:     repository.NewPollCommentRepository(db),
+		Delegations:  repository.NewPollDelegationRepository(db),
+		Categories:   repository.NewPollCategoryRepository(db),
+		Snapshots:    repository.NewPollResultSnapshotRepository(db),
+	}
+}
+
+// Poll creates an active public single choice poll of the user with the options,
+// "Option 1" and "Option 2" when none are given. Poll options override the defaults before saving.
+func (r *Repositories) Poll(tb testing.TB, createdBy uint, options []string, pollOptions ...func(*models.Poll)) *models.Poll {
+	tb.Helper()
+
+	poll := &models.Poll{
+		Title:       "Test poll",
+		Type:        models.PollTypeSingleChoice,
+		Status:      models.PollStatusActive,
+		Visibility:  models.PollVisibilityPublic,
+		CreatedBy:   createdBy,
+		ShowResults: true,
+	}
+	for _, option := range pollOptions {
+		option(poll)
+	}
+
+	if err := r.Polls.Create(poll); err != nil {
+		tb.Fatalf("failed to create poll fixture: %v", err)
+	}
+
+	if len(options) == 0 {
+		options = []string{"Option 1", "Option 2"}
+	}
+	pollOptionModels := make([]*models.PollOption, len(options))
+	for i, text := range options {
+		pollOptionModels[i] = &models.PollOption{PollID: poll.ID, Text: text, Position: i}
+	}
+	if err := r.Options.CreateMultiple(pollOptionModels); err != nil {
+		tb.Fatalf("failed to create poll option fixtures: %v", err)
+	}
+
+	poll.Options = make([]models.PollOption, len(pollOptionModels))
+	for i, option := range pollOptionModels {
+		poll.Options[i] = *option
+	}
+	return poll
+}
+
+// Vote records a vote of the user for the option
+func (r *Repositories) Vote(tb testing.TB, pollID, optionID, userID uint) *models.PollVote {
+	tb.Helper()
+
+	vote := &models.PollVote{
+		PollID:   pollID,
+		OptionID: &optionID,
+		UserID:   &userID,
+	}
+	if err := r.Votes.Create(vote); err != nil {
+		tb.Fatalf("failed to create vote fixture: %v", err)
+	}
+	return vote
+}
+
+// Participant invites the user to the poll
+func (r *Repositories) Participant(tb testing.TB, pollID, userID, invitedBy uint) *models.PollParticipant {
+	tb.Helper()
+
+	participant := &models.PollParticipant{
+		PollID:    pollID,
+		UserID:    userID,
+		InvitedBy: invitedBy,
+		InvitedAt: time.Now(),
+	}
+	if err := r.Participants.Create(participant); err != nil {
+		tb.Fatalf("failed to create participant fixture: %v", err)
+	}
+	return participant
+}
@@ -0,0 +1,532 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/poll/models"
+)
+
+func TestPollFixtures(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, []string{"Tea", "Coffee"})
+	if len(poll.Options) != 2 {
+		t.Fatalf("expected 2 options, got %d", len(poll.Options))
+	}
+
+	repos.Vote(t, poll.ID, poll.Options[1].ID, 2)
+	repos.Vote(t, poll.ID, poll.Options[1].ID, 3)
+	repos.Participant(t, poll.ID, 2, 1)
+
+	counts, err := repos.Votes.GetOptionVoteCounts(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if counts[poll.Options[1].ID] != 2 || counts[poll.Options[0].ID] != 0 {
+		t.Errorf("unexpected vote counts: %v", counts)
+	}
+}
+
+func TestVoteTimeline(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, []string{"Tea", "Coffee"})
+	tea, coffee := poll.Options[0].ID, poll.Options[1].ID
+
+	// Votes stored with a zone offset must land in their UTC hour
+	zone := time.FixedZone("UTC+3", 3*60*60)
+	castAt := map[*models.PollVote]time.Time{
+		repos.Vote(t, poll.ID, tea, 2):    time.Date(2026, 3, 2, 12, 5, 0, 0, zone),
+		repos.Vote(t, poll.ID, coffee, 3): time.Date(2026, 3, 2, 12, 55, 0, 0, zone),
+		repos.Vote(t, poll.ID, coffee, 4): time.Date(2026, 3, 2, 14, 30, 0, 0, zone),
+	}
+	for vote, at := range castAt {
+		if err := repos.DB.Model(vote).UpdateColumn("created_at", at).Error; err != nil {
+			t.Fatalf("failed to backdate vote: %v", err)
+		}
+	}
+
+	counts, err := repos.Votes.GetVoteTimeline(poll.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hour := func(h int) int64 { return time.Date(2026, 3, 2, h, 0, 0, 0, time.UTC).Unix() / 3600 }
+	expected := []struct {
+		bucket   int64
+		optionID uint
+	}{
+		{hour(9), tea},
+		{hour(9), coffee},
+		{hour(11), coffee},
+	}
+	if len(counts) != len(expected) {
+		t.Fatalf("expected %d bucket counts, got %+v", len(expected), counts)
+	}
+	for _, want := range expected {
+		found := false
+		for _, count := range counts {
+			if count.Bucket == want.bucket && count.OptionID != nil && *count.OptionID == want.optionID && count.Count == 1 {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing count of option %d in bucket %d: %+v", want.optionID, want.bucket, counts)
+		}
+	}
+
+	daily, err := repos.Votes.GetVoteTimeline(poll.ID, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(daily) != 2 {
+		t.Errorf("expected one daily bucket per option, got %+v", daily)
+	}
+}
+
+func TestUpdateStatusKeepsFirstActivation(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, nil, func(p *models.Poll) { p.Status = models.PollStatusDraft })
+	if err := repos.Polls.UpdateStatus(poll.ID, models.PollStatusActive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	activated, err := repos.Polls.GetByID(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if activated.ActivatedAt == nil {
+		t.Fatal("expected activation time to be set")
+	}
+
+	for _, status := range []models.PollStatus{models.PollStatusClosed, models.PollStatusActive} {
+		if err := repos.Polls.UpdateStatus(poll.ID, status); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	reopened, err := repos.Polls.GetByID(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reopened.ActivatedAt == nil || !reopened.ActivatedAt.Equal(*activated.ActivatedAt) {
+		t.Errorf("expected activation time %v to be kept, got %v", activated.ActivatedAt, reopened.ActivatedAt)
+	}
+}
+
+func TestCloseRecordsOutcome(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, nil)
+	if err := repos.Polls.Close(poll.ID, models.PollOutcomeQuorumNotReached); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	closed, err := repos.Polls.GetByID(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if closed.Status != models.PollStatusClosed || closed.Outcome != models.PollOutcomeQuorumNotReached {
+		t.Errorf("expected closed poll with quorum not reached, got %s %q", closed.Status, closed.Outcome)
+	}
+
+	// A poll closed meanwhile is not closed twice
+	if err := repos.Polls.Close(poll.ID, models.PollOutcomeQuorumReached); !errors.Is(err, models.ErrPollNotActive) {
+		t.Errorf("expected ErrPollNotActive, got %v", err)
+	}
+
+	// Reopening clears the outcome
+	if err := repos.Polls.UpdateStatus(poll.ID, models.PollStatusActive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reopened, err := repos.Polls.GetByID(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reopened.Outcome != "" {
+		t.Errorf("expected outcome to be cleared, got %q", reopened.Outcome)
+	}
+}
+
+func TestParticipantWeights(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, nil, func(p *models.Poll) { p.Visibility = models.PollVisibilityInviteOnly })
+	participant := repos.Participant(t, poll.ID, 2, 1)
+	if participant.Weight != models.DefaultParticipantWeight {
+		t.Errorf("expected default weight, got %v", participant.Weight)
+	}
+
+	if err := repos.Participants.UpdateWeight(2, poll.ID, 2.5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repos.Participants.UpdateWeight(3, poll.ID, 2.5); err == nil {
+		t.Error("expected error for a user who is not a participant")
+	}
+	participants, err := repos.Participants.GetByPollID(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(participants) != 1 || participants[0].Weight != 2.5 {
+		t.Errorf("expected weight 2.5, got %+v", participants)
+	}
+
+	repos.Vote(t, poll.ID, poll.Options[0].ID, 2)
+	votes, err := repos.Votes.GetVoterVotes(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(votes) != 1 || votes[0].UserID == nil || *votes[0].UserID != 2 || *votes[0].OptionID != poll.Options[0].ID {
+		t.Errorf("unexpected voter votes: %+v", votes)
+	}
+}
+
+func TestDelegations(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, nil, func(p *models.Poll) { p.Category = "budget" })
+	other := repos.Poll(t, 1, nil, func(p *models.Poll) { p.Category = "budget" })
+
+	delegations := []*models.PollDelegation{
+		{DelegatorID: 2, DelegateID: 3, PollID: poll.ID},
+		{DelegatorID: 2, DelegateID: 4, Category: "budget"},
+		{DelegatorID: 5, DelegateID: 3, PollID: other.ID},
+		{DelegatorID: 6, DelegateID: 3, Category: "hiring"},
+	}
+	for _, delegation := range delegations {
+		if err := repos.Delegations.Save(delegation); err != nil {
+			t.Fatalf("failed to save delegation: %v", err)
+		}
+	}
+
+	// Saving the same scope again replaces the delegate
+	replaced := &models.PollDelegation{DelegatorID: 2, DelegateID: 5, PollID: poll.ID}
+	if err := repos.Delegations.Save(replaced); err != nil {
+		t.Fatalf("failed to replace delegation: %v", err)
+	}
+	if replaced.ID != delegations[0].ID {
+		t.Errorf("expected delegation %d to be replaced, got %d", delegations[0].ID, replaced.ID)
+	}
+
+	forPoll, err := repos.Delegations.GetForPoll(poll.ID, poll.Category)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forPoll) != 2 || forPoll[0].DelegateID != 5 || forPoll[1].Category != "budget" {
+		t.Errorf("expected the poll and category delegations, got %+v", forPoll)
+	}
+
+	received, err := repos.Delegations.GetByUser(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 2 {
+		t.Errorf("expected 2 delegations of user 3, got %d", len(received))
+	}
+
+	if err := repos.Delegations.Delete(2, 0, "budget"); err != nil {
+		t.Fatalf("failed to delete delegation: %v", err)
+	}
+	if err := repos.Delegations.Delete(2, 0, "budget"); err == nil {
+		t.Error("expected error for a revoked delegation")
+	}
+
+	// Merging the delegate into the delegator drops the delegation between them
+	if _, err := repos.Polls.MergeUsers(2, 5); err != nil {
+		t.Fatalf("failed to merge users: %v", err)
+	}
+	forPoll, err = repos.Delegations.GetForPoll(poll.ID, poll.Category)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forPoll) != 0 {
+		t.Errorf("expected self-delegation to be dropped, got %+v", forPoll)
+	}
+}
+
+func TestCommentReports(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, nil)
+	other := repos.Poll(t, 2, nil)
+
+	comments := []*models.PollComment{
+		{PollID: poll.ID, UserID: 3, Content: "Spam link"},
+		{PollID: poll.ID, UserID: 4, Content: "Fair point"},
+		{PollID: other.ID, UserID: 3, Content: "Off topic"},
+	}
+	for _, comment := range comments {
+		if err := repos.Comments.Create(comment); err != nil {
+			t.Fatalf("failed to create comment: %v", err)
+		}
+	}
+
+	reports := []*models.PollCommentReport{
+		{CommentID: comments[0].ID, PollID: poll.ID, ReporterID: 5, Reason: models.CommentReportReasonSpam},
+		{CommentID: comments[0].ID, PollID: poll.ID, ReporterID: 6, Reason: models.CommentReportReasonSpam},
+		{CommentID: comments[1].ID, PollID: poll.ID, ReporterID: 5, Reason: models.CommentReportReasonOther},
+		{CommentID: comments[2].ID, PollID: other.ID, ReporterID: 5, Reason: models.CommentReportReasonOffTopic},
+	}
+	for _, report := range reports {
+		report.Status = models.CommentReportStatusPending
+		if err := repos.Comments.CreateReport(report); err != nil {
+			t.Fatalf("failed to create report: %v", err)
+		}
+	}
+
+	// Each user reports a comment once
+	again := &models.PollCommentReport{CommentID: comments[0].ID, PollID: poll.ID, ReporterID: 5, Reason: models.CommentReportReasonOther, Status: models.CommentReportStatusPending}
+	if err := repos.Comments.CreateReport(again); err == nil {
+		t.Error("expected error for a second report of the same user")
+	}
+
+	pending, err := repos.Comments.CountPendingReports(comments[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending != 2 {
+		t.Errorf("expected 2 pending reports, got %d", pending)
+	}
+
+	queue, total, err := repos.Comments.GetReportQueue(1, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 || len(queue) != 2 || queue[0].CommentID != comments[0].ID || queue[0].ReportCount != 2 {
+		t.Fatalf("expected both comments of the creator's poll, most reported first, got %d (total %d)", len(queue), total)
+	}
+
+	all, total, err := repos.Comments.GetReportQueue(0, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 3 || len(all) != 3 {
+		t.Errorf("expected reported comments of all polls, got %d (total %d)", len(all), total)
+	}
+
+	if err := repos.Comments.SetHidden(comments[0].ID, true); err != nil {
+		t.Fatalf("failed to hide comment: %v", err)
+	}
+	hidden, err := repos.Comments.GetByID(comments[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hidden.IsHidden || hidden.HiddenAt == nil {
+		t.Errorf("expected comment to be hidden, got %+v", hidden)
+	}
+
+	resolved, err := repos.Comments.ResolveReports(comments[0].ID, models.CommentReportStatusUpheld, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved != 2 {
+		t.Errorf("expected 2 resolved reports, got %d", resolved)
+	}
+
+	// Resolved and deleted comments leave the queue
+	if err := repos.Comments.Delete(comments[1].ID); err != nil {
+		t.Fatalf("failed to delete comment: %v", err)
+	}
+	queue, total, err = repos.Comments.GetReportQueue(1, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 0 || len(queue) != 0 {
+		t.Errorf("expected an empty queue, got %d (total %d)", len(queue), total)
+	}
+}
+
+func TestMergeDepartmentPolls(t *testing.T) {
+	repos := New(t)
+
+	mergedID, departmentID := uint(3), uint(5)
+	poll := repos.Poll(t, 1, []string{"Yes", "No"}, func(poll *models.Poll) {
+		poll.Visibility = models.PollVisibilityDepartment
+		poll.DepartmentID = &mergedID
+	})
+	repos.Poll(t, 1, []string{"Yes", "No"})
+
+	moved, err := repos.Polls.MergeDepartments(departmentID, mergedID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(moved) != 1 || moved[0].ID != poll.ID {
+		t.Fatalf("expected only the department poll to move, got %d polls", len(moved))
+	}
+
+	stored, err := repos.Polls.GetByID(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.DepartmentID == nil || *stored.DepartmentID != departmentID {
+		t.Errorf("expected the poll in department %d, got %v", departmentID, stored.DepartmentID)
+	}
+}
+
+func TestVoteReceipts(t *testing.T) {
+	repos := New(t)
+
+	poll := repos.Poll(t, 1, []string{"Tea", "Coffee"}, func(p *models.Poll) { p.AllowAnonymous = true })
+	coffee := poll.Options[1].ID
+
+	receipt, voteReceipt, err := models.NewVoteReceipt(poll.ID, &models.VoteReceiptBallot{OptionIDs: []uint{coffee}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !models.IsValidReceipt(receipt) {
+		t.Fatalf("issued receipt %q is not valid", receipt)
+	}
+
+	vote := &models.PollVote{PollID: poll.ID, OptionID: &coffee, IsAnonymous: true, VoterHash: models.VoterHash(poll.ID, 2)}
+	if err := repos.Votes.ReplaceUserVotes(2, poll.ID, []*models.PollVote{vote}, voteReceipt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err := repos.Votes.GetReceipt(poll.ID, models.ReceiptHash(receipt))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ballot, err := stored.Open(receipt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ballot.OptionIDs) != 1 || ballot.OptionIDs[0] != coffee {
+		t.Errorf("unexpected ballot: %+v", ballot)
+	}
+
+	// Another receipt can't open the ballot and the receipt isn't valid for another poll
+	other, _, err := models.NewVoteReceipt(poll.ID, &models.VoteReceiptBallot{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stored.Open(other); !errors.Is(err, models.ErrInvalidReceipt) {
+		t.Errorf("expected invalid receipt error, got %v", err)
+	}
+	if _, err := repos.Votes.GetReceipt(poll.ID+1, models.ReceiptHash(receipt)); err == nil {
+		t.Error("expected receipt of another poll not to be found")
+	}
+}
+
+func TestPollCategories(t *testing.T) {
+	repos := New(t)
+
+	for _, category := range []*models.PollCategory{
+		{Slug: "hr", Name: "HR"},
+		{Slug: "office", Name: "Office life"},
+		{Slug: "it", Name: "IT"},
+	} {
+		if err := repos.Categories.Create(category); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := repos.Categories.Create(&models.PollCategory{Slug: "hr", Name: "Human resources"}); err == nil {
+		t.Error("expected duplicate slug to be rejected")
+	}
+
+	// Names match ignoring case
+	found, err := repos.Categories.Find("office LIFE")
+	if err != nil || found.Slug != "office" {
+		t.Fatalf("expected office category, got %+v (error %v)", found, err)
+	}
+
+	office, it := found.ID, found.ID+1
+	if err := repos.Categories.Reorder([]uint{it, office}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	categories, err := repos.Categories.List(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories) != 3 || categories[0].Slug != "it" || categories[1].Slug != "office" || categories[2].Slug != "hr" {
+		t.Errorf("unexpected order: %s, %s, %s", categories[0].Slug, categories[1].Slug, categories[2].Slug)
+	}
+
+	// Free-text values are counted and renamed together with category delegations
+	for _, category := range []string{"Office", "Office", "office", ""} {
+		repos.Poll(t, 1, nil, func(poll *models.Poll) { poll.Category = category })
+	}
+	values, err := repos.Categories.GetPollCategoryValues()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 2 || values[0].Category != "Office" || values[0].Count != 2 {
+		t.Fatalf("expected Office used twice and office once, got %d values", len(values))
+	}
+
+	for _, delegation := range []*models.PollDelegation{
+		{DelegatorID: 1, DelegateID: 2, Category: "Office"},
+		{DelegatorID: 3, DelegateID: 2, Category: "Office"},
+		{DelegatorID: 3, DelegateID: 4, Category: "office"},
+	} {
+		if err := repos.Delegations.Save(delegation); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	report, err := repos.Categories.Rename("Office", "office")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.PollsUpdated != 2 || report.DelegationsUpdated != 1 || report.DelegationsDropped != 1 {
+		t.Errorf("unexpected rename report: %+v", report)
+	}
+
+	facets, err := repos.Polls.CountByCategory(1, "", &models.PollFilterRequest{Category: "hr"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(facets) != 2 || facets[0].Category != "office" || facets[0].Count != 3 {
+		t.Errorf("expected 3 office polls and 1 without category regardless of the filter, got %d facets", len(facets))
+	}
+}
+
+func TestResultSnapshots(t *testing.T) {
+	repos := New(t)
+	poll := repos.Poll(t, 1, []string{"Tea", "Coffee"})
+
+	if _, err := repos.Snapshots.GetLatest(poll.ID); !errors.Is(err, models.ErrSnapshotNotFound) {
+		t.Fatalf("expected ErrSnapshotNotFound, got %v", err)
+	}
+
+	results := &models.PollResultsResponse{
+		TotalVotes:    3,
+		TotalVoters:   3,
+		VotesByOption: map[uint]int{poll.Options[0].ID: 1, poll.Options[1].ID: 2},
+		TextResponses: []string{"b", "a"},
+	}
+	for _, reason := range []models.SnapshotReason{models.SnapshotReasonClosed, models.SnapshotReasonRecomputed} {
+		snapshot, err := models.NewPollResultSnapshot(poll.ID, results, reason)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := repos.Snapshots.Create(snapshot); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		results.VotesByOption[poll.Options[1].ID]-- // A vote deleted after the poll closed
+	}
+
+	latest, err := repos.Snapshots.GetLatest(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if latest.Version != 2 || latest.Reason != models.SnapshotReasonRecomputed {
+		t.Errorf("expected recomputed version 2, got %s version %d", latest.Reason, latest.Version)
+	}
+	if latest.TextResponsesDigest != models.TextResponsesDigest([]string{"a", "b"}) {
+		t.Errorf("digest depends on the order of text responses")
+	}
+
+	snapshots, err := repos.Snapshots.GetByPoll(poll.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snapshots))
+	}
+	first, err := snapshots[1].Results()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.VotesByOption[poll.Options[1].ID] != 2 || first.Poll != nil {
+		t.Errorf("first snapshot changed: %+v", first.VotesByOption)
+	}
+}
@@ -0,0 +1,120 @@
+// File: services/poll/repository/user_merge.go
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeUsers moves created polls, votes, invitations, comments, comment reactions and reports and
+// vote delegations of a duplicate account to the primary account. Votes of the duplicate in polls
+// the primary account already voted in are dropped, so every voter is still counted once.
+func (r *pollRepository) MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error) {
+	result := sharedmodels.NewMergeUsersResult("poll")
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		reassignments := []struct {
+			kind         string
+			model        interface{}
+			userColumn   string
+			scopeColumns []string
+		}{
+			{"created_polls", &models.Poll{}, "created_by", nil},
+			{"votes", &models.PollVote{}, "user_id", []string{"poll_id"}},
+			{"poll_invitations", &models.PollParticipant{}, "user_id", []string{"poll_id"}},
+			{"comments", &models.PollComment{}, "user_id", nil},
+			{"comment_reactions", &models.PollCommentReaction{}, "user_id", []string{"comment_id", "emoji"}},
+			{"comment_reports", &models.PollCommentReport{}, "reporter_id", []string{"comment_id"}},
+			{"delegations", &models.PollDelegation{}, "delegator_id", []string{"poll_id", "category"}},
+			{"received_delegations", &models.PollDelegation{}, "delegate_id", nil},
+		}
+
+		for _, reassignment := range reassignments {
+			moved, dropped, err := database.ReassignUser(tx, reassignment.model, reassignment.userColumn,
+				reassignment.scopeColumns, duplicateID, primaryID)
+			if err != nil {
+				return fmt.Errorf("failed to merge %s: %w", reassignment.kind, err)
+			}
+			result.Moved[reassignment.kind] = moved
+			if dropped > 0 {
+				result.Dropped[reassignment.kind] = dropped
+			}
+		}
+
+		// A delegation between the two accounts now points at itself
+		selfDelegations := tx.Where("delegator_id = ? AND delegate_id = ?", primaryID, primaryID).
+			Delete(&models.PollDelegation{})
+		if selfDelegations.Error != nil {
+			return fmt.Errorf("failed to merge delegations: %w", selfDelegations.Error)
+		}
+		if selfDelegations.RowsAffected > 0 {
+			result.Dropped["delegations"] += selfDelegations.RowsAffected
+		}
+
+		moved, dropped, err := mergeAnonymousVotes(tx, primaryID, duplicateID)
+		if err != nil {
+			return fmt.Errorf("failed to merge anonymous votes: %w", err)
+		}
+		result.Moved["anonymous_votes"] = moved
+		if dropped > 0 {
+			result.Dropped["anonymous_votes"] = dropped
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mergeAnonymousVotes relinks anonymous votes of the duplicate to the primary voter hash.
+// Voter hashes differ per poll, so every poll with anonymous votes is checked.
+func mergeAnonymousVotes(tx *gorm.DB, primaryID, duplicateID uint) (int64, int64, error) {
+	var pollIDs []uint
+	err := tx.Unscoped().Model(&models.PollVote{}).
+		Where("is_anonymous = ?", true).
+		Distinct().
+		Pluck("poll_id", &pollIDs).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var moved, dropped int64
+	for _, pollID := range pollIDs {
+		duplicateHash := models.VoterHash(pollID, duplicateID)
+		primaryHash := models.VoterHash(pollID, primaryID)
+
+		var primaryVotes int64
+		err := tx.Model(&models.PollVote{}).
+			Where("poll_id = ? AND (user_id = ? OR voter_hash = ?)", pollID, primaryID, primaryHash).
+			Count(&primaryVotes).Error
+		if err != nil {
+			return 0, 0, err
+		}
+
+		duplicateVotes := tx.Unscoped().Where("poll_id = ? AND voter_hash = ?", pollID, duplicateHash)
+		if primaryVotes > 0 {
+			result := duplicateVotes.Delete(&models.PollVote{})
+			if result.Error != nil {
+				return 0, 0, result.Error
+			}
+			dropped += result.RowsAffected
+			continue
+		}
+
+		result := duplicateVotes.Model(&models.PollVote{}).Update("voter_hash", primaryHash)
+		if result.Error != nil {
+			return 0, 0, result.Error
+		}
+		moved += result.RowsAffected
+	}
+
+	return moved, dropped, nil
+}
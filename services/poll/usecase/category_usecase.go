@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/logger"
+)
+
+// GetPollCategories returns categories of the taxonomy in their order
+func (u *pollUsecase) GetPollCategories(includeArchived bool) ([]*models.PollCategory, error) {
+	return u.categoryRepo.List(includeArchived)
+}
+
+// CreatePollCategory adds a category at the end of the taxonomy
+func (u *pollUsecase) CreatePollCategory(req *models.CreatePollCategoryRequest) (*models.PollCategory, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("validation failed: name is required")
+	}
+	slug := categorySlug(req.Slug)
+	if slug == "" {
+		slug = categorySlug(name)
+	}
+	if slug == "" {
+		return nil, fmt.Errorf("validation failed: slug must contain letters or digits")
+	}
+
+	category := &models.PollCategory{
+		Slug:        slug,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := u.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"category_id": category.ID,
+		"slug":        category.Slug,
+	}).Info("Poll category created")
+
+	return category, nil
+}
+
+// UpdatePollCategory renames, describes, archives or restores a category. The slug stored in
+// polls never changes.
+func (u *pollUsecase) UpdatePollCategory(categoryID uint, req *models.UpdatePollCategoryRequest) (*models.PollCategory, error) {
+	category, err := u.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("validation failed: name is required")
+		}
+		category.Name = name
+	}
+	if req.Description != nil {
+		category.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.IsArchived != nil && *req.IsArchived != category.IsArchived {
+		category.IsArchived = *req.IsArchived
+		category.ArchivedAt = nil
+		if category.IsArchived {
+			now := time.Now()
+			category.ArchivedAt = &now
+		}
+	}
+
+	if err := u.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// ReorderPollCategories changes the order of categories and returns all of them in the new order
+func (u *pollUsecase) ReorderPollCategories(req *models.ReorderPollCategoriesRequest) ([]*models.PollCategory, error) {
+	if err := u.categoryRepo.Reorder(req.CategoryIDs); err != nil {
+		return nil, err
+	}
+	return u.categoryRepo.List(true)
+}
+
+// MigratePollCategories moves free-text categories of polls to the taxonomy. Each distinct value
+// is mapped by the request mapping, else matched to a category by slug or name ignoring case,
+// else a category is created for it if requested. Values left unmapped stay as they are.
+func (u *pollUsecase) MigratePollCategories(req *models.MigratePollCategoriesRequest) (*models.PollCategoryMigrationReport, error) {
+	mapping := make(map[string]*models.PollCategory, len(req.Mapping))
+	for value, target := range req.Mapping {
+		category, err := u.categoryRepo.Find(target)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				return nil, fmt.Errorf("validation failed: mapping of %q refers to unknown category %q", value, target)
+			}
+			return nil, err
+		}
+		mapping[strings.TrimSpace(value)] = category
+	}
+
+	values, err := u.categoryRepo.GetPollCategoryValues()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PollCategoryMigrationReport{
+		DryRun:   req.DryRun,
+		Mappings: make([]*models.PollCategoryMapping, 0, len(values)),
+	}
+	created := make(map[string]bool)
+
+	for _, value := range values {
+		entry := &models.PollCategoryMapping{Value: value.Category, Polls: value.Count}
+		report.Mappings = append(report.Mappings, entry)
+
+		category, action, err := u.matchCategory(value.Category, mapping)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil && req.CreateMissing {
+			category, action, err = u.createMigratedCategory(value.Category, req.DryRun, created)
+			if err != nil {
+				return nil, err
+			}
+			if action == models.CategoryMigrationCreated {
+				report.CategoriesCreated++
+			}
+		}
+
+		entry.Action = action
+		if category == nil {
+			continue
+		}
+		entry.Category = category.Slug
+		if category.Slug == value.Category {
+			entry.Action = models.CategoryMigrationUnchanged
+			continue
+		}
+		if req.DryRun {
+			continue
+		}
+
+		renamed, err := u.categoryRepo.Rename(value.Category, category.Slug)
+		if err != nil {
+			return nil, err
+		}
+		report.PollsUpdated += renamed.PollsUpdated
+		report.DelegationsUpdated += renamed.DelegationsUpdated
+		report.DelegationsDropped += renamed.DelegationsDropped
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"dry_run":            req.DryRun,
+		"values":             len(values),
+		"categories_created": report.CategoriesCreated,
+		"polls_updated":      report.PollsUpdated,
+	}).Info("Poll categories migrated")
+
+	return report, nil
+}
+
+// matchCategory finds the category of a free-text value by the mapping or by slug and name
+func (u *pollUsecase) matchCategory(value string, mapping map[string]*models.PollCategory) (*models.PollCategory, string, error) {
+	if category, ok := mapping[value]; ok {
+		return category, models.CategoryMigrationMapped, nil
+	}
+
+	category, err := u.categoryRepo.Find(value)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, models.CategoryMigrationUnmapped, nil
+		}
+		return nil, "", err
+	}
+	return category, models.CategoryMigrationMatched, nil
+}
+
+// createMigratedCategory creates a category named after a free-text value. Values with the same
+// slug share one category. In a dry run nothing is created.
+func (u *pollUsecase) createMigratedCategory(value string, dryRun bool, created map[string]bool) (*models.PollCategory, string, error) {
+	slug := categorySlug(value)
+	if slug == "" {
+		return nil, models.CategoryMigrationUnmapped, nil
+	}
+
+	existing, err := u.categoryRepo.Find(slug)
+	if err == nil {
+		return existing, models.CategoryMigrationMatched, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, "", err
+	}
+
+	category := &models.PollCategory{Slug: slug, Name: value}
+	if dryRun {
+		if created[slug] {
+			return category, models.CategoryMigrationMatched, nil
+		}
+		created[slug] = true
+		return category, models.CategoryMigrationCreated, nil
+	}
+	if err := u.categoryRepo.Create(category); err != nil {
+		return nil, "", err
+	}
+	return category, models.CategoryMigrationCreated, nil
+}
+
+// resolveCategory returns the slug of the taxonomy category chosen for a poll by slug or name.
+// The current category of a poll is kept even if it was archived or never migrated.
+func (u *pollUsecase) resolveCategory(value, current string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == current {
+		return value, nil
+	}
+
+	category, err := u.categoryRepo.Find(value)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return "", fmt.Errorf("validation failed: unknown poll category %q", value)
+		}
+		return "", err
+	}
+	if category.Slug == current {
+		return current, nil
+	}
+	if category.IsArchived {
+		return "", fmt.Errorf("validation failed: poll category %q is archived", category.Name)
+	}
+	return category.Slug, nil
+}
+
+// categoryFacets counts polls of a list per category: taxonomy categories in their order, then
+// values not in the taxonomy, then polls without a category
+func (u *pollUsecase) categoryFacets(userID uint, searchQuery string, filter *models.PollFilterRequest) ([]*models.PollCategoryFacet, error) {
+	values, err := u.pollRepo.CountByCategory(userID, searchQuery, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category facets: %w", err)
+	}
+	categories, err := u.categoryRepo.List(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category facets: %w", err)
+	}
+
+	counts := make(map[string]int64, len(values))
+	for _, value := range values {
+		counts[value.Category] += value.Count
+	}
+
+	facets := make([]*models.PollCategoryFacet, 0, len(values))
+	for _, category := range categories {
+		if count := counts[category.Slug]; count > 0 {
+			facets = append(facets, &models.PollCategoryFacet{
+				Category:   category.Slug,
+				Name:       category.Name,
+				IsArchived: category.IsArchived,
+				Count:      count,
+			})
+			delete(counts, category.Slug)
+		}
+	}
+	for _, value := range values {
+		if value.Category != "" && counts[value.Category] > 0 {
+			facets = append(facets, &models.PollCategoryFacet{Category: value.Category, Name: value.Category, Count: value.Count})
+		}
+	}
+	if count := counts[""]; count > 0 {
+		facets = append(facets, &models.PollCategoryFacet{Count: count})
+	}
+	return facets, nil
+}
+
+// categorySlug makes a slug of lowercase letters and digits separated by dashes
+func categorySlug(value string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	if runes := []rune(slug.String()); len(runes) > 100 {
+		return strings.TrimRight(string(runes[:100]), "-")
+	}
+	return slug.String()
+}
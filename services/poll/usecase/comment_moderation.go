@@ -0,0 +1,265 @@
+// File: services/poll/usecase/comment_moderation.go
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+// ReportComment reports a poll comment to the moderators of the poll. A comment is hidden once
+// it collects CommentAutoHideReports pending reports, until a moderator decides on it.
+func (u *pollUsecase) ReportComment(userID, pollID, commentID uint, req *models.ReportCommentRequest) (*models.PollCommentReport, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+
+	comment, err := u.getPollComment(pollID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID == userID {
+		return nil, fmt.Errorf("validation failed: cannot report your own comment")
+	}
+
+	poll, err := u.getModeratedPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.hasPollAccess(userID, poll) {
+		return nil, fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	report := &models.PollCommentReport{
+		CommentID:  comment.ID,
+		PollID:     pollID,
+		ReporterID: userID,
+		Reason:     req.Reason,
+		Details:    strings.TrimSpace(req.Details),
+		Status:     models.CommentReportStatusPending,
+	}
+	if err := u.commentRepo.CreateReport(report); err != nil {
+		return nil, err
+	}
+
+	if comment.IsHidden {
+		return report, nil
+	}
+
+	pending, err := u.commentRepo.CountPendingReports(comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comment reports: %w", err)
+	}
+	if pending >= models.CommentAutoHideReports {
+		if err := u.commentRepo.SetHidden(comment.ID, true); err != nil {
+			return nil, fmt.Errorf("failed to hide comment: %w", err)
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"poll_id":    pollID,
+			"comment_id": comment.ID,
+			"reports":    pending,
+		}).Info("Poll comment hidden after reports")
+
+		u.notifyCommentAuthor(poll, comment, "notification.poll_comment_hidden_title", "notification.poll_comment_auto_hidden_message", "")
+	}
+
+	return report, nil
+}
+
+// GetCommentReportQueue returns reported comments waiting for a moderator: on polls created by
+// the user, or on all polls for admins
+func (u *pollUsecase) GetCommentReportQueue(userID uint, isAdmin bool, limit, offset int) ([]*models.CommentModerationItem, int64, error) {
+	if limit <= 0 {
+		limit = models.DefaultLimit
+	}
+	if limit > models.MaxLimit {
+		limit = models.MaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	pollCreatorID := userID
+	if isAdmin {
+		pollCreatorID = 0
+	}
+
+	summaries, total, err := u.commentRepo.GetReportQueue(pollCreatorID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get report queue: %w", err)
+	}
+
+	polls := make(map[uint]*models.Poll)
+	items := make([]*models.CommentModerationItem, 0, len(summaries))
+	for _, summary := range summaries {
+		comment, err := u.commentRepo.GetByID(summary.CommentID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get reported comment: %w", err)
+		}
+
+		reports, err := u.commentRepo.GetPendingReports(summary.CommentID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		poll, ok := polls[summary.PollID]
+		if !ok {
+			poll, err = u.getModeratedPoll(summary.PollID)
+			if err != nil {
+				return nil, 0, err
+			}
+			polls[summary.PollID] = poll
+		}
+
+		item := &models.CommentModerationItem{
+			Comment:     comment.ToResponse(),
+			PollTitle:   poll.Title,
+			ReportCount: summary.ReportCount,
+			Reasons:     make(map[models.CommentReportReason]int64),
+			Reports:     reports,
+		}
+		for _, report := range reports {
+			item.Reasons[report.Reason]++
+			if report.CreatedAt.After(item.LastReportedAt) {
+				item.LastReportedAt = report.CreatedAt
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, total, nil
+}
+
+// ModerateComment applies the decision of the poll creator or an admin to a poll comment and
+// resolves its pending reports. The comment author is notified when the comment is hidden,
+// removed or restored.
+func (u *pollUsecase) ModerateComment(userID uint, isAdmin bool, pollID, commentID uint, req *models.ModerateCommentRequest) (*models.CommentModerationResult, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+
+	comment, err := u.getPollComment(pollID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := u.getModeratedPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && poll.CreatedBy != userID {
+		return nil, fmt.Errorf("access denied: only poll creator or admin can moderate comments")
+	}
+
+	note := strings.TrimSpace(req.Note)
+	result := &models.CommentModerationResult{
+		CommentID: comment.ID,
+		Action:    req.Action,
+		IsHidden:  comment.IsHidden,
+	}
+
+	switch req.Action {
+	case models.CommentModerationHide:
+		if !comment.IsHidden {
+			if err := u.commentRepo.SetHidden(comment.ID, true); err != nil {
+				return nil, fmt.Errorf("failed to hide comment: %w", err)
+			}
+		}
+		// A comment hidden after reports stays hidden, the author learns the decision is final
+		u.notifyCommentAuthor(poll, comment, "notification.poll_comment_hidden_title", "notification.poll_comment_hidden_message", note)
+		result.IsHidden = true
+		result.ResolvedReports, err = u.commentRepo.ResolveReports(comment.ID, models.CommentReportStatusUpheld, userID)
+
+	case models.CommentModerationDelete:
+		result.ResolvedReports, err = u.commentRepo.ResolveReports(comment.ID, models.CommentReportStatusUpheld, userID)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.commentRepo.Delete(comment.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete comment: %w", err)
+		}
+		result.Deleted = true
+		u.notifyCommentAuthor(poll, comment, "notification.poll_comment_removed_title", "notification.poll_comment_removed_message", note)
+
+	case models.CommentModerationDismiss:
+		if comment.IsHidden {
+			if err := u.commentRepo.SetHidden(comment.ID, false); err != nil {
+				return nil, fmt.Errorf("failed to restore comment: %w", err)
+			}
+			u.notifyCommentAuthor(poll, comment, "notification.poll_comment_restored_title", "notification.poll_comment_restored_message", note)
+		}
+		result.IsHidden = false
+		result.ResolvedReports, err = u.commentRepo.ResolveReports(comment.ID, models.CommentReportStatusDismissed, userID)
+
+	default:
+		return nil, fmt.Errorf("validation failed: unknown moderation action %q", req.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"poll_id":          pollID,
+		"comment_id":       comment.ID,
+		"moderator_id":     userID,
+		"action":           req.Action,
+		"resolved_reports": result.ResolvedReports,
+	}).Info("Poll comment moderated")
+
+	return result, nil
+}
+
+// getModeratedPoll retrieves the poll of a moderated comment
+func (u *pollUsecase) getModeratedPoll(pollID uint) (*models.Poll, error) {
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+	return poll, nil
+}
+
+// notifyCommentAuthor tells the author of a comment about a moderation decision. The decision is
+// already applied, so a failure is logged instead of failing the request.
+func (u *pollUsecase) notifyCommentAuthor(poll *models.Poll, comment *models.PollComment, titleKey, messageKey, note string) {
+	if u.notifier == nil {
+		return
+	}
+
+	args := map[string]interface{}{
+		"PollTitle": poll.Title,
+		"Note":      note,
+	}
+	title := i18n.T(i18n.DefaultLocale, titleKey, args)
+	message := strings.TrimSpace(i18n.T(i18n.DefaultLocale, messageKey, args))
+
+	if err := u.notifier.NotifyUsers(poll.ID, []uint{comment.UserID}, title, message); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"poll_id":    poll.ID,
+			"comment_id": comment.ID,
+			"user_id":    comment.UserID,
+			"error":      err.Error(),
+		}).Warn("Failed to notify comment author about moderation")
+	}
+}
+
+// maskHiddenComments removes the content of hidden comments in a thread, except for their authors
+// and poll moderators, keeping replies in place
+func maskHiddenComments(comments []*models.PollCommentResponse, userID uint, canModerate bool) {
+	for _, comment := range comments {
+		if comment.IsHidden && !canModerate && comment.UserID != userID {
+			comment.Content = ""
+			comment.Reactions = nil
+		}
+		maskHiddenComments(comment.Replies, userID, canModerate)
+	}
+}
@@ -0,0 +1,148 @@
+// File: services/poll/usecase/comment_usecase.go
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// UpdateComment edits a poll comment, only the author can edit
+func (u *pollUsecase) UpdateComment(userID, pollID, commentID uint, req *sharedmodels.UpdateCommentRequest) (*models.PollCommentResponse, error) {
+	// Validate request
+	if req == nil || strings.TrimSpace(req.Content) == "" {
+		return nil, fmt.Errorf("validation failed: comment content is required")
+	}
+	if len(strings.TrimSpace(req.Content)) > models.MaxCommentLength {
+		return nil, fmt.Errorf("validation failed: comment is too long")
+	}
+
+	comment, err := u.getPollComment(pollID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != userID {
+		return nil, fmt.Errorf("access denied: only comment author can edit the comment")
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == comment.Content {
+		return comment.ToResponseForUser(userID), nil
+	}
+
+	comment.Content = content
+	comment.MarkEdited()
+
+	if err := u.commentRepo.Update(comment); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return comment.ToResponseForUser(userID), nil
+}
+
+// AddCommentReaction adds an emoji reaction to a poll comment and returns updated aggregates
+func (u *pollUsecase) AddCommentReaction(userID, pollID, commentID uint, req *sharedmodels.CommentReactionRequest) ([]sharedmodels.ReactionSummary, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	emoji := strings.TrimSpace(req.Emoji)
+	if err := sharedmodels.ValidateReactionEmoji(emoji); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := u.checkCommentAccess(userID, pollID, commentID); err != nil {
+		return nil, err
+	}
+
+	reaction := &models.PollCommentReaction{}
+	reaction.CommentID = commentID
+	reaction.UserID = userID
+	reaction.Emoji = emoji
+
+	if err := u.commentRepo.AddReaction(reaction); err != nil {
+		return nil, fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	return u.getReactionSummary(userID, commentID)
+}
+
+// RemoveCommentReaction removes user's emoji reaction from a poll comment and returns updated aggregates
+func (u *pollUsecase) RemoveCommentReaction(userID, pollID, commentID uint, emoji string) ([]sharedmodels.ReactionSummary, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" {
+		return nil, fmt.Errorf("validation failed: emoji is required")
+	}
+
+	if err := u.checkCommentAccess(userID, pollID, commentID); err != nil {
+		return nil, err
+	}
+
+	if err := u.commentRepo.RemoveReaction(commentID, userID, emoji); err != nil {
+		return nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return u.getReactionSummary(userID, commentID)
+}
+
+// getPollComment retrieves a comment and verifies it belongs to the poll
+func (u *pollUsecase) getPollComment(pollID, commentID uint) (*models.PollComment, error) {
+	comment, err := u.commentRepo.GetByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	if comment.PollID != pollID {
+		return nil, fmt.Errorf("comment not found")
+	}
+
+	return comment, nil
+}
+
+// checkCommentAccess verifies that the comment exists and the user can see the poll
+func (u *pollUsecase) checkCommentAccess(userID, pollID, commentID uint) error {
+	if _, err := u.getPollComment(pollID, commentID); err != nil {
+		return err
+	}
+
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("poll not found")
+		}
+		return fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if !u.hasPollAccess(userID, poll) {
+		return fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	return nil
+}
+
+// getReactionSummary returns aggregated reactions for a comment
+func (u *pollUsecase) getReactionSummary(userID, commentID uint) ([]sharedmodels.ReactionSummary, error) {
+	reactions, err := u.commentRepo.GetReactions(commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+
+	plain := make([]sharedmodels.CommentReaction, len(reactions))
+	for i, reaction := range reactions {
+		plain[i] = reaction.CommentReaction
+	}
+
+	summary := sharedmodels.AggregateReactions(plain, userID)
+	if summary == nil {
+		summary = []sharedmodels.ReactionSummary{}
+	}
+	return summary, nil
+}
@@ -0,0 +1,168 @@
+// File: services/poll/usecase/deadline_usecase.go
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+// UpdatePollDeadline changes the end time of an active poll. Only the creator can change it,
+// the new end time must be in the future. When the deadline is extended, invited participants
+// who have not voted yet are notified.
+func (u *pollUsecase) UpdatePollDeadline(userID, pollID uint, req *models.UpdatePollDeadlineRequest) (*models.PollDeadlineResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if poll.CreatedBy != userID {
+		return nil, fmt.Errorf("access denied: only poll creator can change the deadline")
+	}
+
+	if poll.Status != models.PollStatusActive {
+		return nil, fmt.Errorf("cannot change deadline of a %s poll", poll.Status)
+	}
+
+	if poll.StartTime != nil && !req.EndTime.After(*poll.StartTime) {
+		return nil, fmt.Errorf("validation failed: end time must be after start time")
+	}
+
+	if poll.EndTime != nil && poll.EndTime.Equal(req.EndTime) {
+		return nil, fmt.Errorf("validation failed: end time is unchanged")
+	}
+
+	oldEndTime := poll.EndTime
+	endTime := req.EndTime
+	poll.EndTime = &endTime
+
+	change := &models.PollDeadlineChange{
+		ChangedBy:  userID,
+		OldEndTime: oldEndTime,
+		NewEndTime: endTime,
+		Reason:     strings.TrimSpace(req.Reason),
+	}
+
+	if err := u.pollRepo.UpdateDeadline(poll, change); err != nil {
+		return nil, fmt.Errorf("failed to update poll deadline: %w", err)
+	}
+
+	if oldEndTime != nil && endTime.After(*oldEndTime) {
+		change.Notified = u.notifyDeadlineExtended(poll)
+		if change.Notified > 0 {
+			if err := u.pollRepo.UpdateDeadlineChange(change); err != nil {
+				logger.WithFields(map[string]interface{}{
+					"poll_id": poll.ID,
+					"error":   err.Error(),
+				}).Warn("Failed to record notified participants count")
+			}
+		}
+	}
+
+	history, err := u.pollRepo.GetDeadlineChanges(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deadline history: %w", err)
+	}
+
+	updatedPoll, err := u.pollRepo.GetByIDWithAll(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated poll: %w", err)
+	}
+
+	return &models.PollDeadlineResponse{
+		Poll:    updatedPoll.ToResponse(),
+		Change:  change,
+		History: history,
+	}, nil
+}
+
+// GetPollDeadlineHistory returns deadline changes of a poll, newest first
+func (u *pollUsecase) GetPollDeadlineHistory(userID, pollID uint) ([]*models.PollDeadlineChange, error) {
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if !u.hasPollAccess(userID, poll) {
+		return nil, fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	history, err := u.pollRepo.GetDeadlineChanges(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deadline history: %w", err)
+	}
+
+	return history, nil
+}
+
+// notifyDeadlineExtended notifies invited participants who have not voted yet about the
+// new deadline and returns how many were notified. Failures are logged, not returned,
+// since the deadline is already changed.
+func (u *pollUsecase) notifyDeadlineExtended(poll *models.Poll) int {
+	if u.notifier == nil {
+		return 0
+	}
+
+	participants, err := u.participantRepo.GetByPollID(poll.ID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"poll_id": poll.ID,
+			"error":   err.Error(),
+		}).Warn("Failed to get participants for deadline notification")
+		return 0
+	}
+
+	var userIDs []uint
+	for _, participant := range participants {
+		if participant.VotedAt == nil && participant.UserID != poll.CreatedBy {
+			userIDs = append(userIDs, participant.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return 0
+	}
+
+	args := map[string]interface{}{
+		"PollTitle": poll.Title,
+		"EndTime":   poll.EndTime.Format("02.01.2006 15:04 MST"),
+	}
+	title := i18n.T(i18n.DefaultLocale, "notification.poll_deadline_extended_title", args)
+	message := i18n.T(i18n.DefaultLocale, "notification.poll_deadline_extended_message", args)
+
+	if err := u.notifier.NotifyUsers(poll.ID, userIDs, title, message); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"poll_id":    poll.ID,
+			"user_count": len(userIDs),
+			"error":      err.Error(),
+		}).Warn("Failed to notify participants about deadline extension")
+		return 0
+	}
+
+	for _, id := range userIDs {
+		if err := u.participantRepo.MarkAsNotified(id, poll.ID); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"poll_id": poll.ID,
+				"user_id": id,
+				"error":   err.Error(),
+			}).Warn("Failed to mark participant as notified")
+		}
+	}
+
+	return len(userIDs)
+}
@@ -0,0 +1,276 @@
+// File: services/poll/usecase/delegation_usecase.go
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+// Delegated votes are attributed by these rules:
+//
+//   - a participant's own vote always wins over their delegation;
+//   - a delegation for the poll overrides one for the poll's category;
+//   - a delegation is followed along the chain of delegates to the first participant who voted,
+//     whose vote then counts for the delegator as well, with the delegator's weight;
+//   - a delegation is not counted when nobody in the chain voted or the chain runs in a cycle.
+//
+// Only invited participants of polls that allow delegation can delegate or be delegates.
+
+// DelegateVote delegates the vote of a participant in a poll to another participant
+func (u *pollUsecase) DelegateVote(userID, pollID uint, req *models.DelegateVoteRequest) (*models.PollDelegation, error) {
+	poll, err := u.getDelegationPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DelegateID == userID {
+		return nil, fmt.Errorf("validation failed: you can't delegate your vote to yourself")
+	}
+	if isParticipant, err := u.participantRepo.IsParticipant(userID, pollID); err != nil || !isParticipant {
+		return nil, fmt.Errorf("access denied: only invited participants can delegate their vote")
+	}
+	if isParticipant, err := u.participantRepo.IsParticipant(req.DelegateID, pollID); err != nil || !isParticipant {
+		return nil, fmt.Errorf("validation failed: delegate must be a participant of the poll")
+	}
+
+	delegations, err := u.delegationRepo.GetForPoll(poll.ID, poll.Category)
+	if err != nil {
+		return nil, err
+	}
+	if createsDelegationCycle(effectiveDelegates(delegations), userID, req.DelegateID) {
+		return nil, fmt.Errorf("validation failed: delegation would create a cycle")
+	}
+
+	delegation := &models.PollDelegation{
+		DelegatorID: userID,
+		DelegateID:  req.DelegateID,
+		PollID:      pollID,
+	}
+	if err := u.delegationRepo.Save(delegation); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"poll_id":      pollID,
+		"delegator_id": userID,
+		"delegate_id":  req.DelegateID,
+	}).Info("Poll vote delegated")
+
+	return delegation, nil
+}
+
+// RevokeDelegation revokes the delegation of a participant's vote in a poll
+func (u *pollUsecase) RevokeDelegation(userID, pollID uint) error {
+	if _, err := u.getDelegationPoll(pollID); err != nil {
+		return err
+	}
+	return u.delegationRepo.Delete(userID, pollID, "")
+}
+
+// DelegateCategory delegates the user's votes in all polls of a category that allow delegation.
+// It applies in polls where both users are participants.
+func (u *pollUsecase) DelegateCategory(userID uint, category string, req *models.DelegateVoteRequest) (*models.PollDelegation, error) {
+	category, err := u.resolveCategory(category, "")
+	if err != nil {
+		return nil, err
+	}
+	if category == "" {
+		return nil, fmt.Errorf("validation failed: category is required")
+	}
+	if req.DelegateID == userID {
+		return nil, fmt.Errorf("validation failed: you can't delegate your vote to yourself")
+	}
+	if err := u.userRefs.CheckUsers(req.DelegateID); err != nil {
+		return nil, err
+	}
+
+	delegations, err := u.delegationRepo.GetForCategory(category)
+	if err != nil {
+		return nil, err
+	}
+	if createsDelegationCycle(effectiveDelegates(delegations), userID, req.DelegateID) {
+		return nil, fmt.Errorf("validation failed: delegation would create a cycle")
+	}
+
+	delegation := &models.PollDelegation{
+		DelegatorID: userID,
+		DelegateID:  req.DelegateID,
+		Category:    category,
+	}
+	if err := u.delegationRepo.Save(delegation); err != nil {
+		return nil, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"category":     category,
+		"delegator_id": userID,
+		"delegate_id":  req.DelegateID,
+	}).Info("Poll category votes delegated")
+
+	return delegation, nil
+}
+
+// RevokeCategoryDelegation revokes the delegation of the user's votes in a category
+func (u *pollUsecase) RevokeCategoryDelegation(userID uint, category string) error {
+	return u.delegationRepo.Delete(userID, 0, strings.TrimSpace(category))
+}
+
+// GetPollDelegations returns how delegated votes of a poll are attributed, as far as the
+// delegation visibility of the poll lets the user see them
+func (u *pollUsecase) GetPollDelegations(userID, pollID uint) ([]*models.DelegatedVote, error) {
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if !u.hasPollAccess(userID, poll) {
+		return nil, fmt.Errorf("access denied: insufficient permissions")
+	}
+	if !poll.AllowDelegation {
+		return []*models.DelegatedVote{}, nil
+	}
+
+	voters, err := u.getPollVoters(poll)
+	if err != nil {
+		return nil, err
+	}
+	return visibleDelegations(userID, poll, voters.delegated), nil
+}
+
+// GetMyDelegations returns delegations the user made or received, newest first
+func (u *pollUsecase) GetMyDelegations(userID uint) ([]*models.PollDelegation, error) {
+	return u.delegationRepo.GetByUser(userID)
+}
+
+// getDelegationPoll returns a poll whose delegations can be changed: it must allow delegation
+// and still be open, delegations of a closed poll are part of its results
+func (u *pollUsecase) getDelegationPoll(pollID uint) (*models.Poll, error) {
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if !poll.AllowDelegation {
+		return nil, fmt.Errorf("validation failed: poll does not allow delegation")
+	}
+	if poll.Status != models.PollStatusDraft && poll.Status != models.PollStatusActive {
+		return nil, fmt.Errorf("cannot change delegations of a %s poll", poll.Status)
+	}
+	return poll, nil
+}
+
+// getDelegatedVotes resolves delegations of participants of a poll who didn't vote
+func (u *pollUsecase) getDelegatedVotes(poll *models.Poll, voters *pollVoters) ([]*models.DelegatedVote, error) {
+	delegations, err := u.delegationRepo.GetForPoll(poll.ID, poll.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	isParticipant := make(map[uint]bool, len(voters.participants))
+	for _, participant := range voters.participants {
+		isParticipant[participant.UserID] = true
+	}
+
+	delegates := effectiveDelegates(delegations)
+	votes := make([]*models.DelegatedVote, 0, len(delegates))
+	for delegatorID, delegateID := range delegates {
+		if !isParticipant[delegatorID] {
+			continue
+		}
+		if _, voted := voters.options[delegatorID]; voted {
+			continue
+		}
+
+		vote := &models.DelegatedVote{DelegatorID: delegatorID, DelegateID: delegateID}
+		vote.VotedBy, vote.Counted = resolveDelegate(delegates, voters.options, delegatorID)
+		votes = append(votes, vote)
+	}
+
+	sort.Slice(votes, func(i, j int) bool {
+		return votes[i].DelegatorID < votes[j].DelegatorID
+	})
+	return votes, nil
+}
+
+// effectiveDelegates maps delegators to their delegates, a delegation for a poll takes
+// precedence over one for its category
+func effectiveDelegates(delegations []*models.PollDelegation) map[uint]uint {
+	delegates := make(map[uint]uint, len(delegations))
+	for _, delegation := range delegations {
+		if _, exists := delegates[delegation.DelegatorID]; exists && delegation.PollID == 0 {
+			continue
+		}
+		delegates[delegation.DelegatorID] = delegation.DelegateID
+	}
+	return delegates
+}
+
+// resolveDelegate follows the chain of delegates of a delegator to the first one who voted
+func resolveDelegate(delegates map[uint]uint, voted map[uint][]uint, delegatorID uint) (uint, bool) {
+	seen := map[uint]bool{delegatorID: true}
+	current := delegates[delegatorID]
+	for !seen[current] {
+		if _, ok := voted[current]; ok {
+			return current, true
+		}
+		seen[current] = true
+
+		next, ok := delegates[current]
+		if !ok {
+			break
+		}
+		current = next
+	}
+	return 0, false
+}
+
+// createsDelegationCycle reports whether delegating from delegatorID to delegateID would
+// lead the chain of delegates back to the delegator
+func createsDelegationCycle(delegates map[uint]uint, delegatorID, delegateID uint) bool {
+	seen := make(map[uint]bool)
+	for current := delegateID; !seen[current]; {
+		if current == delegatorID {
+			return true
+		}
+		seen[current] = true
+
+		next, ok := delegates[current]
+		if !ok {
+			return false
+		}
+		current = next
+	}
+	return false
+}
+
+// visibleDelegations filters delegated votes by the delegation visibility of a poll. Users who
+// can't see all of them still see the ones they are part of.
+func visibleDelegations(userID uint, poll *models.Poll, votes []*models.DelegatedVote) []*models.DelegatedVote {
+	switch {
+	case poll.DelegationVisibility == models.DelegationVisibilityParticipants:
+		return votes
+	case poll.CreatedBy == userID && poll.DelegationVisibility != models.DelegationVisibilityPrivate:
+		return votes
+	}
+
+	visible := make([]*models.DelegatedVote, 0)
+	for _, vote := range votes {
+		if vote.DelegatorID == userID || vote.DelegateID == userID || vote.VotedBy == userID {
+			visible = append(visible, vote)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,60 @@
+// File: services/poll/usecase/department_events.go
+package usecase
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// HandleDepartmentEvent keeps department polls in line with a department change published by the
+// user service. Polls of a merged department move to the department it was merged into and authors
+// of open polls are notified. Other changes need nothing, polls refer to departments by ID.
+func (u *pollUsecase) HandleDepartmentEvent(event *sharedmodels.DepartmentEvent) error {
+	if event.Type != sharedmodels.DepartmentEventMerged {
+		return nil
+	}
+	if event.DepartmentID == nil || event.PreviousDepartmentID == nil {
+		return fmt.Errorf("validation failed: department_id and previous_department_id are required")
+	}
+
+	polls, err := u.pollRepo.MergeDepartments(*event.DepartmentID, *event.PreviousDepartmentID)
+	if err != nil {
+		return fmt.Errorf("failed to merge department polls: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"department_id":          *event.DepartmentID,
+		"previous_department_id": *event.PreviousDepartmentID,
+		"moved_polls":            len(polls),
+	}).Info("Department polls merged")
+
+	if u.notifier == nil {
+		return nil
+	}
+	for _, poll := range polls {
+		if poll.Status != models.PollStatusDraft && poll.Status != models.PollStatusActive {
+			continue
+		}
+
+		args := map[string]interface{}{
+			"PollTitle":          poll.Title,
+			"Department":         event.DepartmentName,
+			"PreviousDepartment": event.PreviousDepartmentName,
+		}
+		title := i18n.T(i18n.DefaultLocale, "notification.department_merged_title", args)
+		message := i18n.T(i18n.DefaultLocale, "notification.poll_retargeted_message", args)
+
+		if err := u.notifier.NotifyUsers(poll.ID, []uint{poll.CreatedBy}, title, message); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"poll_id": poll.ID,
+				"error":   err.Error(),
+			}).Warn("Failed to notify about moved department poll")
+		}
+	}
+
+	return nil
+}
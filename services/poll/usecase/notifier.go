@@ -0,0 +1,71 @@
+// File: services/poll/usecase/notifier.go
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PollNotifier sends notifications about polls to users
+type PollNotifier interface {
+	NotifyUsers(pollID uint, userIDs []uint, title, message string) error
+}
+
+// httpPollNotifier queues bulk notifications in the notification service
+type httpPollNotifier struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPPollNotifier creates a notifier for the notification service at baseURL.
+// It returns nil if baseURL is empty, which disables poll notifications.
+func NewHTTPPollNotifier(baseURL string) PollNotifier {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpPollNotifier{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NotifyUsers queues one poll notification for all users
+func (n *httpPollNotifier) NotifyUsers(pollID uint, userIDs []uint, title, message string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	task := map[string]interface{}{
+		"type":     "bulk",
+		"priority": "medium",
+		"bulk_notification": map[string]interface{}{
+			"user_ids":     userIDs,
+			"type":         "poll",
+			"title":        title,
+			"message":      message,
+			"related_id":   pollID,
+			"related_type": "poll",
+		},
+	}
+
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification task: %w", err)
+	}
+
+	resp, err := n.client.Post(n.baseURL+"/api/v1/internal/notifications/task", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification task: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+// File: services/poll/usecase/option_usecase.go
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+
+	"gorm.io/gorm"
+)
+
+// AddOption adds an option to a draft or active poll, only the creator can add options
+func (u *pollUsecase) AddOption(userID, pollID uint, req *models.CreatePollOptionRequest) (*models.PollOptionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	poll, options, err := u.getEditablePollOptions(userID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options) >= models.MaxPollOptions {
+		return nil, fmt.Errorf("validation failed: poll cannot have more than %d options", models.MaxPollOptions)
+	}
+	if poll.Type == models.PollTypeOpenText && len(options) >= 1 {
+		return nil, fmt.Errorf("validation failed: open text polls can have only one option")
+	}
+
+	// Options without explicit position go last
+	position := req.Position
+	if position == 0 {
+		for _, option := range options {
+			if option.Position >= position {
+				position = option.Position + 1
+			}
+		}
+	}
+
+	option := &models.PollOption{
+		PollID:      pollID,
+		Text:        strings.TrimSpace(req.Text),
+		Description: strings.TrimSpace(req.Description),
+		Position:    position,
+		Color:       req.Color,
+		ImageURL:    req.ImageURL,
+	}
+
+	if err := u.optionRepo.Create(option); err != nil {
+		return nil, fmt.Errorf("failed to add poll option: %w", err)
+	}
+
+	return option.ToResponse(), nil
+}
+
+// UpdateOption edits a poll option. Draft polls allow any change, active polls allow
+// only text and description changes so that existing votes keep their meaning.
+func (u *pollUsecase) UpdateOption(userID, pollID, optionID uint, req *models.UpdatePollOptionRequest) (*models.PollOptionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	poll, options, err := u.getEditablePollOptions(userID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	option := findOption(options, optionID)
+	if option == nil {
+		return nil, fmt.Errorf("poll option not found")
+	}
+
+	if poll.Status == models.PollStatusActive && (req.Color != nil || req.ImageURL != nil) {
+		return nil, fmt.Errorf("cannot change option color or image of an active poll, only text can be edited")
+	}
+
+	if req.Text != nil {
+		option.Text = strings.TrimSpace(*req.Text)
+	}
+	if req.Description != nil {
+		option.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Color != nil {
+		option.Color = *req.Color
+	}
+	if req.ImageURL != nil {
+		option.ImageURL = *req.ImageURL
+	}
+
+	if err := u.optionRepo.Update(option); err != nil {
+		return nil, fmt.Errorf("failed to update poll option: %w", err)
+	}
+
+	return option.ToResponse(), nil
+}
+
+// DeleteOption removes a poll option. Options of active polls can be removed only
+// while nobody voted for them.
+func (u *pollUsecase) DeleteOption(userID, pollID, optionID uint) error {
+	poll, options, err := u.getEditablePollOptions(userID, pollID)
+	if err != nil {
+		return err
+	}
+
+	if findOption(options, optionID) == nil {
+		return fmt.Errorf("poll option not found")
+	}
+
+	minOptions := 1
+	if poll.Type == models.PollTypeRating || poll.Type == models.PollTypeRanking {
+		minOptions = 2
+	}
+	if len(options) <= minOptions {
+		return fmt.Errorf("cannot delete option: %s polls require at least %d options", poll.Type, minOptions)
+	}
+
+	if poll.Status == models.PollStatusActive {
+		voteCounts, err := u.voteRepo.GetOptionVoteCounts(pollID)
+		if err != nil {
+			return fmt.Errorf("failed to check option votes: %w", err)
+		}
+		if voteCounts[optionID] > 0 {
+			return fmt.Errorf("cannot delete option with existing votes")
+		}
+	}
+
+	if err := u.optionRepo.Delete(optionID); err != nil {
+		return fmt.Errorf("failed to delete poll option: %w", err)
+	}
+
+	return nil
+}
+
+// ReorderOptions sets option positions to the order of req.OptionIDs, which must list
+// every option of the poll
+func (u *pollUsecase) ReorderOptions(userID, pollID uint, req *models.ReorderPollOptionsRequest) ([]*models.PollOptionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	_, options, err := u.getEditablePollOptions(userID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.OptionIDs) != len(options) {
+		return nil, fmt.Errorf("validation failed: option_ids must list all %d poll options", len(options))
+	}
+
+	positions := make(map[uint]int, len(req.OptionIDs))
+	for i, optionID := range req.OptionIDs {
+		if findOption(options, optionID) == nil {
+			return nil, fmt.Errorf("validation failed: option %d does not belong to the poll", optionID)
+		}
+		positions[optionID] = i + 1
+	}
+
+	if err := u.optionRepo.UpdatePositions(positions); err != nil {
+		return nil, fmt.Errorf("failed to reorder poll options: %w", err)
+	}
+
+	reordered, err := u.optionRepo.GetByPollID(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll options: %w", err)
+	}
+
+	responses := make([]*models.PollOptionResponse, len(reordered))
+	for i, option := range reordered {
+		responses[i] = option.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// getEditablePollOptions loads a poll and its options for option management by the creator.
+// Options can be managed only in draft and active polls.
+func (u *pollUsecase) getEditablePollOptions(userID, pollID uint) (*models.Poll, []*models.PollOption, error) {
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, nil, fmt.Errorf("poll not found")
+		}
+		return nil, nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if poll.CreatedBy != userID {
+		return nil, nil, fmt.Errorf("access denied: only poll creator can manage options")
+	}
+
+	if poll.Status != models.PollStatusDraft && poll.Status != models.PollStatusActive {
+		return nil, nil, fmt.Errorf("cannot change options of a %s poll", poll.Status)
+	}
+
+	options, err := u.optionRepo.GetByPollID(pollID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get poll options: %w", err)
+	}
+
+	return poll, options, nil
+}
+
+// findOption returns the option with id from options or nil
+func findOption(options []*models.PollOption, id uint) *models.PollOption {
+	for _, option := range options {
+		if option.ID == id {
+			return option
+		}
+	}
+	return nil
+}
@@ -9,6 +9,11 @@ import (
 
 	"tachyon-messenger/services/poll/models"
 	"tachyon-messenger/services/poll/repository"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/refs"
 
 	"gorm.io/gorm"
 )
@@ -21,15 +26,48 @@ type PollUsecase interface {
 	UpdatePoll(userID, pollID uint, req *models.UpdatePollRequest) (*models.PollResponse, error)
 	DeletePoll(userID, pollID uint) error
 	GetPolls(userID uint, filter *models.PollFilterRequest) (*models.PollListResponse, error)
-	SearchPolls(userID uint, query string, filter *models.PollFilterRequest) (*models.PollListResponse, error)
+	SearchPolls(userID uint, searchQuery string, filter *models.PollFilterRequest) (*models.PollListResponse, error)
 
 	// Poll status management
 	UpdatePollStatus(userID, pollID uint, status models.PollStatus) error
+	CloseExpiredPolls() (int, error)
 
 	// Voting operations
-	VotePoll(userID, pollID uint, req *models.VotePollRequest) ([]*models.PollVoteResponse, error)
+	VotePoll(userID, pollID uint, req *models.VotePollRequest) ([]*models.PollVoteResponse, string, error)
 	GetUserVotes(userID, pollID uint) ([]*models.PollVoteResponse, error)
+	RetractVote(userID, pollID uint) error
+	VerifyVoteReceipt(userID, pollID uint, receipt string) (*models.VoteReceiptResponse, error)
 	GetPollResults(userID, pollID uint) (*models.PollResultsResponse, error)
+	GetPollResultsTimeline(userID, pollID uint, bucket models.TimelineBucket) (*models.PollResultsTimelineResponse, error)
+
+	// Deadline management
+	UpdatePollDeadline(userID, pollID uint, req *models.UpdatePollDeadlineRequest) (*models.PollDeadlineResponse, error)
+	GetPollDeadlineHistory(userID, pollID uint) ([]*models.PollDeadlineChange, error)
+
+	// Vote delegation
+	DelegateVote(userID, pollID uint, req *models.DelegateVoteRequest) (*models.PollDelegation, error)
+	RevokeDelegation(userID, pollID uint) error
+	DelegateCategory(userID uint, category string, req *models.DelegateVoteRequest) (*models.PollDelegation, error)
+	RevokeCategoryDelegation(userID uint, category string) error
+	GetPollDelegations(userID, pollID uint) ([]*models.DelegatedVote, error)
+	GetMyDelegations(userID uint) ([]*models.PollDelegation, error)
+
+	// Category taxonomy
+	GetPollCategories(includeArchived bool) ([]*models.PollCategory, error)
+	CreatePollCategory(req *models.CreatePollCategoryRequest) (*models.PollCategory, error)
+	UpdatePollCategory(categoryID uint, req *models.UpdatePollCategoryRequest) (*models.PollCategory, error)
+	ReorderPollCategories(req *models.ReorderPollCategoriesRequest) ([]*models.PollCategory, error)
+	MigratePollCategories(req *models.MigratePollCategoriesRequest) (*models.PollCategoryMigrationReport, error)
+
+	// Results snapshots of closed polls
+	GetPollResultSnapshots(pollID uint) ([]*models.PollResultSnapshot, error)
+	RecomputePollResults(adminID, pollID uint, req *models.RecomputePollResultsRequest) (*models.RecomputePollResultsResponse, error)
+
+	// Option management
+	AddOption(userID, pollID uint, req *models.CreatePollOptionRequest) (*models.PollOptionResponse, error)
+	UpdateOption(userID, pollID, optionID uint, req *models.UpdatePollOptionRequest) (*models.PollOptionResponse, error)
+	DeleteOption(userID, pollID, optionID uint) error
+	ReorderOptions(userID, pollID uint, req *models.ReorderPollOptionsRequest) ([]*models.PollOptionResponse, error)
 
 	// Participant management
 	AddParticipants(userID, pollID uint, req *models.AddParticipantsRequest) error
@@ -39,9 +77,23 @@ type PollUsecase interface {
 	CreateComment(userID, pollID uint, req *models.CreateCommentRequest) (*models.PollCommentResponse, error)
 	GetComments(userID, pollID uint, limit, offset int) ([]*models.PollCommentResponse, int64, error)
 	DeleteComment(userID, pollID, commentID uint) error
+	UpdateComment(userID, pollID, commentID uint, req *sharedmodels.UpdateCommentRequest) (*models.PollCommentResponse, error)
+	AddCommentReaction(userID, pollID, commentID uint, req *sharedmodels.CommentReactionRequest) ([]sharedmodels.ReactionSummary, error)
+	RemoveCommentReaction(userID, pollID, commentID uint, emoji string) ([]sharedmodels.ReactionSummary, error)
+
+	// Comment moderation
+	ReportComment(userID, pollID, commentID uint, req *models.ReportCommentRequest) (*models.PollCommentReport, error)
+	GetCommentReportQueue(userID uint, isAdmin bool, limit, offset int) ([]*models.CommentModerationItem, int64, error)
+	ModerateComment(userID uint, isAdmin bool, pollID, commentID uint, req *models.ModerateCommentRequest) (*models.CommentModerationResult, error)
 
 	// Statistics
 	GetPollStats(userID uint) (*models.PollStatsResponse, error)
+
+	// Account merge
+	MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error)
+
+	// Department changes
+	HandleDepartmentEvent(event *sharedmodels.DepartmentEvent) error
 }
 
 // pollUsecase implements PollUsecase interface
@@ -51,6 +103,12 @@ type pollUsecase struct {
 	voteRepo        repository.PollVoteRepository
 	participantRepo repository.PollParticipantRepository
 	commentRepo     repository.PollCommentRepository
+	delegationRepo  repository.PollDelegationRepository
+	categoryRepo    repository.PollCategoryRepository
+	snapshotRepo    repository.PollResultSnapshotRepository
+	notifier        PollNotifier    // nil disables poll notifications
+	userRefs        *refs.Validator // nil stores participant IDs unchecked
+	timelines       *timelineCache
 }
 
 // NewPollUsecase creates a new poll usecase
@@ -60,6 +118,11 @@ func NewPollUsecase(
 	voteRepo repository.PollVoteRepository,
 	participantRepo repository.PollParticipantRepository,
 	commentRepo repository.PollCommentRepository,
+	delegationRepo repository.PollDelegationRepository,
+	categoryRepo repository.PollCategoryRepository,
+	snapshotRepo repository.PollResultSnapshotRepository,
+	notifier PollNotifier,
+	userRefs *refs.Validator,
 ) PollUsecase {
 	return &pollUsecase{
 		pollRepo:        pollRepo,
@@ -67,6 +130,12 @@ func NewPollUsecase(
 		voteRepo:        voteRepo,
 		participantRepo: participantRepo,
 		commentRepo:     commentRepo,
+		delegationRepo:  delegationRepo,
+		categoryRepo:    categoryRepo,
+		snapshotRepo:    snapshotRepo,
+		notifier:        notifier,
+		userRefs:        userRefs,
+		timelines:       newTimelineCache(),
 	}
 }
 
@@ -76,6 +145,14 @@ func (u *pollUsecase) CreatePoll(userID uint, req *models.CreatePollRequest) (*m
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := u.userRefs.CheckUsers(req.ParticipantIDs...); err != nil {
+		return nil, err
+	}
+
+	category, err := u.resolveCategory(req.Category, "")
+	if err != nil {
+		return nil, err
+	}
 
 	// Create poll model
 	poll := &models.Poll{
@@ -87,11 +164,21 @@ func (u *pollUsecase) CreatePoll(userID uint, req *models.CreatePollRequest) (*m
 		EndTime:           req.EndTime,
 		AllowAnonymous:    req.AllowAnonymous,
 		AllowMultipleVote: req.AllowMultipleVote,
+		AllowVoteChange:   req.AllowVoteChange,
 		RequireComment:    req.RequireComment,
 		ShowResults:       req.ShowResults,
 		ShowResultsAfter:  req.ShowResultsAfter,
+		WeightedVoting:    req.WeightedVoting,
+		QuorumPercent:     req.QuorumPercent,
+		AllowDelegation:   req.AllowDelegation,
 		DepartmentID:      req.DepartmentID,
-		Category:          strings.TrimSpace(req.Category),
+		Category:          category,
+	}
+
+	// Delegation attribution is visible to the creator unless set otherwise
+	poll.DelegationVisibility = models.DelegationVisibilityCreator
+	if req.DelegationVisibility != "" {
+		poll.DelegationVisibility = req.DelegationVisibility
 	}
 
 	// Set visibility (default to public if not provided)
@@ -136,6 +223,10 @@ func (u *pollUsecase) CreatePoll(userID uint, req *models.CreatePollRequest) (*m
 				UserID:    participantID,
 				InvitedBy: userID,
 				InvitedAt: time.Now(),
+				Weight:    models.DefaultParticipantWeight,
+			}
+			if weight, ok := req.ParticipantWeights[participantID]; ok {
+				participant.Weight = weight
 			}
 			participants[i] = participant
 		}
@@ -178,6 +269,11 @@ func (u *pollUsecase) GetPoll(userID, pollID uint) (*models.PollResponse, error)
 
 // UpdatePoll updates an existing poll
 func (u *pollUsecase) UpdatePoll(userID, pollID uint, req *models.UpdatePollRequest) (*models.PollResponse, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
 	// Get existing poll
 	poll, err := u.pollRepo.GetByID(pollID)
 	if err != nil {
@@ -197,6 +293,12 @@ func (u *pollUsecase) UpdatePoll(userID, pollID uint, req *models.UpdatePollRequ
 		return nil, fmt.Errorf("cannot update closed or archived poll")
 	}
 
+	// Changing voting rules while votes are cast would change their meaning
+	if (req.WeightedVoting != nil || req.QuorumPercent != nil || req.AllowDelegation != nil) && poll.Status != models.PollStatusDraft {
+		return nil, fmt.Errorf("validation failed: voting rules can only be changed while the poll is a draft")
+	}
+	closing := req.Status != nil && *req.Status == models.PollStatusClosed && poll.Status != models.PollStatusClosed
+
 	// Update fields if provided
 	if req.Title != nil {
 		poll.Title = strings.TrimSpace(*req.Title)
@@ -215,7 +317,11 @@ func (u *pollUsecase) UpdatePoll(userID, pollID uint, req *models.UpdatePollRequ
 		poll.Visibility = *req.Visibility
 	}
 	if req.Category != nil {
-		poll.Category = strings.TrimSpace(*req.Category)
+		category, err := u.resolveCategory(*req.Category, poll.Category)
+		if err != nil {
+			return nil, err
+		}
+		poll.Category = category
 	}
 	if req.StartTime != nil {
 		poll.StartTime = req.StartTime
@@ -229,6 +335,9 @@ func (u *pollUsecase) UpdatePoll(userID, pollID uint, req *models.UpdatePollRequ
 	if req.AllowMultipleVote != nil {
 		poll.AllowMultipleVote = *req.AllowMultipleVote
 	}
+	if req.AllowVoteChange != nil {
+		poll.AllowVoteChange = *req.AllowVoteChange
+	}
 	if req.RequireComment != nil {
 		poll.RequireComment = *req.RequireComment
 	}
@@ -241,16 +350,49 @@ func (u *pollUsecase) UpdatePoll(userID, pollID uint, req *models.UpdatePollRequ
 	if req.DepartmentID != nil {
 		poll.DepartmentID = req.DepartmentID
 	}
+	if req.WeightedVoting != nil {
+		poll.WeightedVoting = *req.WeightedVoting
+	}
+	if req.QuorumPercent != nil {
+		poll.QuorumPercent = req.QuorumPercent
+		if *req.QuorumPercent == 0 {
+			poll.QuorumPercent = nil
+		}
+	}
+	if req.AllowDelegation != nil {
+		poll.AllowDelegation = *req.AllowDelegation
+	}
+	if req.DelegationVisibility != nil {
+		poll.DelegationVisibility = *req.DelegationVisibility
+	}
 
 	// Validate time logic if times are being updated
 	if poll.StartTime != nil && poll.EndTime != nil && poll.EndTime.Before(*poll.StartTime) {
 		return nil, fmt.Errorf("end time must be after start time")
 	}
+	if err := poll.ValidateVotingRules(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Closing resolves the quorum of the poll
+	if closing {
+		if poll.Outcome, err = u.resolveOutcome(poll); err != nil {
+			return nil, err
+		}
+	}
+
+	// Changes based on a stale version are rejected on save
+	if req.Version != nil {
+		poll.Version = *req.Version
+	}
 
 	// Save updated poll
 	if err := u.pollRepo.Update(poll); err != nil {
 		return nil, fmt.Errorf("failed to update poll: %w", err)
 	}
+	if closing {
+		u.snapshotClosedPoll(poll.ID)
+	}
 
 	// Get updated poll with all details
 	updatedPoll, err := u.pollRepo.GetByIDWithAll(poll.ID)
@@ -303,14 +445,8 @@ func (u *pollUsecase) GetPolls(userID uint, filter *models.PollFilterRequest) (*
 	}
 
 	// Set defaults
-	if filter.Limit <= 0 {
-		filter.Limit = models.DefaultLimit
-	}
-	if filter.SortBy == "" {
-		filter.SortBy = "created_at"
-	}
-	if filter.SortOrder == "" {
-		filter.SortOrder = "desc"
+	if filter.Page == nil {
+		filter.Page = query.Default(models.PollListOptions)
 	}
 
 	// Get polls from repository
@@ -318,6 +454,10 @@ func (u *pollUsecase) GetPolls(userID uint, filter *models.PollFilterRequest) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to get polls: %w", err)
 	}
+	facets, err := u.categoryFacets(userID, "", filter)
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert to response format
 	responses := make([]*models.PollResponse, len(polls))
@@ -326,18 +466,20 @@ func (u *pollUsecase) GetPolls(userID uint, filter *models.PollFilterRequest) (*
 	}
 
 	return &models.PollListResponse{
-		Polls:   responses,
-		Total:   total,
-		Limit:   filter.Limit,
-		Offset:  filter.Offset,
-		Filters: filter,
+		Polls:          responses,
+		Total:          total,
+		Limit:          filter.Page.Limit,
+		Offset:         filter.Page.Offset,
+		NextCursor:     filter.Page.NextCursor(responses),
+		Filters:        filter,
+		CategoryFacets: facets,
 	}, nil
 }
 
 // SearchPolls searches polls by query with filtering
-func (u *pollUsecase) SearchPolls(userID uint, query string, filter *models.PollFilterRequest) (*models.PollListResponse, error) {
+func (u *pollUsecase) SearchPolls(userID uint, searchQuery string, filter *models.PollFilterRequest) (*models.PollListResponse, error) {
 	// Validate inputs
-	if strings.TrimSpace(query) == "" {
+	if strings.TrimSpace(searchQuery) == "" {
 		return nil, fmt.Errorf("search query is required")
 	}
 
@@ -350,15 +492,19 @@ func (u *pollUsecase) SearchPolls(userID uint, query string, filter *models.Poll
 	}
 
 	// Set defaults
-	if filter.Limit <= 0 {
-		filter.Limit = models.DefaultLimit
+	if filter.Page == nil {
+		filter.Page = query.Default(models.PollListOptions)
 	}
 
 	// Search polls
-	polls, total, err := u.pollRepo.SearchPolls(userID, query, filter)
+	polls, total, err := u.pollRepo.SearchPolls(userID, searchQuery, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search polls: %w", err)
 	}
+	facets, err := u.categoryFacets(userID, searchQuery, filter)
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert to response format
 	responses := make([]*models.PollResponse, len(polls))
@@ -367,11 +513,13 @@ func (u *pollUsecase) SearchPolls(userID uint, query string, filter *models.Poll
 	}
 
 	return &models.PollListResponse{
-		Polls:   responses,
-		Total:   total,
-		Limit:   filter.Limit,
-		Offset:  filter.Offset,
-		Filters: filter,
+		Polls:          responses,
+		Total:          total,
+		Limit:          filter.Page.Limit,
+		Offset:         filter.Page.Offset,
+		NextCursor:     filter.Page.NextCursor(responses),
+		Filters:        filter,
+		CategoryFacets: facets,
 	}, nil
 }
 
@@ -396,6 +544,14 @@ func (u *pollUsecase) UpdatePollStatus(userID, pollID uint, status models.PollSt
 		return fmt.Errorf("invalid status transition: %w", err)
 	}
 
+	// Closing resolves the quorum of the poll
+	if status == models.PollStatusClosed {
+		if err := u.closePoll(poll); err != nil {
+			return fmt.Errorf("failed to close poll: %w", err)
+		}
+		return nil
+	}
+
 	// Update status
 	if err := u.pollRepo.UpdateStatus(pollID, status); err != nil {
 		return fmt.Errorf("failed to update poll status: %w", err)
@@ -404,57 +560,62 @@ func (u *pollUsecase) UpdatePollStatus(userID, pollID uint, status models.PollSt
 	return nil
 }
 
-// VotePoll handles voting on a poll
-func (u *pollUsecase) VotePoll(userID, pollID uint, req *models.VotePollRequest) ([]*models.PollVoteResponse, error) {
+// VotePoll handles voting on a poll. Anonymous votes also return a receipt the voter can
+// verify the ballot with, it is not stored and can't be retrieved again.
+func (u *pollUsecase) VotePoll(userID, pollID uint, req *models.VotePollRequest) ([]*models.PollVoteResponse, string, error) {
 	// Get poll with options
 	poll, err := u.pollRepo.GetByIDWithOptions(pollID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
-			return nil, fmt.Errorf("poll not found")
+			return nil, "", fmt.Errorf("poll not found")
 		}
-		return nil, fmt.Errorf("failed to get poll: %w", err)
+		return nil, "", fmt.Errorf("failed to get poll: %w", err)
 	}
 
 	// Check access rights
 	if !u.hasPollAccess(userID, poll) {
-		return nil, fmt.Errorf("access denied: insufficient permissions")
+		return nil, "", fmt.Errorf("access denied: insufficient permissions")
 	}
 
 	// Check if poll is active
 	if !poll.IsActive() {
-		return nil, fmt.Errorf("poll is not active")
+		return nil, "", fmt.Errorf("poll is not active")
 	}
 
 	// Validate vote request
 	if err := req.Validate(poll); err != nil {
-		return nil, fmt.Errorf("invalid vote: %w", err)
+		return nil, "", fmt.Errorf("invalid vote: %w", err)
 	}
 
-	// Check if user has already voted (if multiple votes not allowed)
-	if !poll.AllowMultipleVote {
+	// Check if user has already voted (if re-voting not allowed)
+	if !poll.CanChangeVote() {
 		hasVoted, err := u.voteRepo.HasUserVoted(userID, pollID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check if user voted: %w", err)
+			return nil, "", fmt.Errorf("failed to check if user voted: %w", err)
 		}
 		if hasVoted {
-			return nil, fmt.Errorf("user has already voted on this poll")
-		}
-	} else {
-		// If multiple votes allowed, delete previous votes first
-		if err := u.voteRepo.DeleteByUserAndPoll(userID, pollID); err != nil {
-			return nil, fmt.Errorf("failed to delete previous votes: %w", err)
+			return nil, "", fmt.Errorf("user has already voted on this poll")
 		}
 	}
 
 	// Create votes based on poll type
 	votes, err := u.createVotes(userID, poll, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create votes: %w", err)
+		return nil, "", fmt.Errorf("failed to create votes: %w", err)
 	}
 
-	// Save votes
-	if err := u.voteRepo.CreateMultiple(votes); err != nil {
-		return nil, fmt.Errorf("failed to save votes: %w", err)
+	var receipt string
+	var voteReceipt *models.PollVoteReceipt
+	if req.IsAnonymous {
+		receipt, voteReceipt, err = models.NewVoteReceipt(pollID, ballotOf(req))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Save votes, replacing previous votes of the user in one transaction
+	if err := u.voteRepo.ReplaceUserVotes(userID, pollID, votes, voteReceipt); err != nil {
+		return nil, "", fmt.Errorf("failed to save votes: %w", err)
 	}
 
 	// Mark participant as voted (for invite-only polls)
@@ -468,7 +629,46 @@ func (u *pollUsecase) VotePoll(userID, pollID uint, req *models.VotePollRequest)
 		responses[i] = vote.ToResponse()
 	}
 
-	return responses, nil
+	return responses, receipt, nil
+}
+
+// RetractVote removes user's votes from a poll before it closes.
+// Only polls that allow changing votes permit retraction.
+func (u *pollUsecase) RetractVote(userID, pollID uint) error {
+	poll, err := u.pollRepo.GetByID(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("poll not found")
+		}
+		return fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	if !u.hasPollAccess(userID, poll) {
+		return fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	if !poll.IsActive() {
+		return fmt.Errorf("cannot retract vote: poll is not active")
+	}
+
+	if !poll.CanChangeVote() {
+		return fmt.Errorf("cannot retract vote: poll does not allow changing votes")
+	}
+
+	deleted, err := u.voteRepo.DeleteByUserAndPoll(userID, pollID)
+	if err != nil {
+		return fmt.Errorf("failed to retract vote: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("vote not found")
+	}
+
+	// Participant has no vote anymore (for invite-only polls)
+	if poll.Visibility == models.PollVisibilityInviteOnly {
+		u.participantRepo.ClearVoted(userID, pollID) // Ignore error
+	}
+
+	return nil
 }
 
 // GetUserVotes retrieves user's votes for a poll
@@ -501,10 +701,15 @@ func (u *pollUsecase) GetUserVotes(userID, pollID uint) ([]*models.PollVoteRespo
 	return responses, nil
 }
 
-// GetPollResults retrieves poll results with statistics
+// GetPollResults retrieves poll results with statistics, results of closed polls come from
+// their snapshot. Statistics are computed with a fixed number of grouped queries regardless
+// of the number of options; the count is logged at debug level to catch N+1 regressions.
 func (u *pollUsecase) GetPollResults(userID, pollID uint) (*models.PollResultsResponse, error) {
-	// Get poll with all data
-	poll, err := u.pollRepo.GetByIDWithAll(pollID)
+	counter := database.NewQueryCounter()
+	uc := u.withQueryCounter(counter)
+
+	// Votes, participants and comments have their own endpoints, results only need options
+	poll, err := uc.pollRepo.GetByIDWithOptions(pollID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
 			return nil, fmt.Errorf("poll not found")
@@ -513,93 +718,52 @@ func (u *pollUsecase) GetPollResults(userID, pollID uint) (*models.PollResultsRe
 	}
 
 	// Check access rights
-	if !u.hasPollAccess(userID, poll) {
+	if !uc.hasPollAccess(userID, poll) {
 		return nil, fmt.Errorf("access denied: insufficient permissions")
 	}
 
 	// Check if user can view results
-	if !u.canViewResults(userID, poll) {
+	if !uc.canViewResults(userID, poll) {
 		return nil, fmt.Errorf("access denied: results not available")
 	}
 
-	// Get basic statistics
-	totalVotes, err := u.voteRepo.GetVoteCount(pollID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get vote count: %w", err)
-	}
-
-	totalVoters, err := u.voteRepo.GetVoterCount(pollID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get voter count: %w", err)
-	}
-
-	// Load poll statistics
-	u.loadPollStatistics(poll, userID)
-
-	// Create response
-	results := &models.PollResultsResponse{
-		Poll:        poll.ToResponse(),
-		TotalVotes:  int(totalVotes),
-		TotalVoters: int(totalVoters),
+	// Closed polls are served from their results snapshot, votes may have been deleted since
+	var results *models.PollResultsResponse
+	if poll.Status == models.PollStatusClosed || poll.Status == models.PollStatusArchived {
+		results, err = uc.snapshotResults(poll)
+	} else {
+		results, err = uc.computeResults(poll)
 	}
-
-	// Get option-specific data
-	optionVoteCounts, err := u.voteRepo.GetOptionVoteCounts(pollID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get option vote counts: %w", err)
-	}
-
-	results.VotesByOption = make(map[uint]int)
-	for optionID, count := range optionVoteCounts {
-		results.VotesByOption[optionID] = int(count)
+		return nil, err
 	}
 
-	// Load options with statistics
-	options := make([]*models.PollOptionResponse, len(poll.Options))
-	for i, option := range poll.Options {
-		optionResp := option.ToResponse()
-		optionResp.VoteCount = int(optionVoteCounts[option.ID])
-		if totalVotes > 0 {
-			optionResp.VotePercent = models.CalculateVotePercent(optionResp.VoteCount, int(totalVotes))
-		}
-		options[i] = optionResp
+	uc.loadUserStatistics(poll, userID)
+	if poll.AllowDelegation {
+		results.Delegations = visibleDelegations(userID, poll, results.Delegations)
 	}
-	results.Options = options
-
-	// Type-specific data
-	switch poll.Type {
-	case models.PollTypeOpenText:
-		textResponses, err := u.voteRepo.GetTextResponses(pollID)
-		if err == nil {
-			results.TextResponses = textResponses
-		}
-
-	case models.PollTypeRating:
-		results.RatingStats = make(map[uint]*models.RatingStats)
-		for _, option := range poll.Options {
-			stats, err := u.voteRepo.GetRatingStats(pollID, option.ID)
-			if err == nil {
-				results.RatingStats[option.ID] = stats
-			}
-		}
 
-	case models.PollTypeRanking:
-		results.RankingStats = make(map[uint]*models.RankingStats)
-		for _, option := range poll.Options {
-			stats, err := u.voteRepo.GetRankingStats(pollID, option.ID)
-			if err == nil {
-				results.RankingStats[option.ID] = stats
-			}
-		}
+	results.Poll = poll.ToResponse()
+	results.Options = results.Poll.Options
+	if results.Options == nil {
+		results.Options = []*models.PollOptionResponse{}
 	}
 
 	// Include vote details for non-anonymous polls (if allowed)
-	if !poll.AllowAnonymous && u.canViewDetailedResults(userID, poll) {
+	if !poll.AllowAnonymous && uc.canViewDetailedResults(userID, poll) {
 		results.VotesByUser = make(map[uint][]*models.PollVoteResponse)
 		// This would require additional repository method to get votes by user
 		// Skipping for now to keep complexity manageable
 	}
 
+	logger.WithFields(map[string]interface{}{
+		"poll_id":     pollID,
+		"user_id":     userID,
+		"poll_type":   poll.Type,
+		"options":     len(poll.Options),
+		"query_count": counter.Count(),
+	}).Debug("Poll results loaded")
+
 	return results, nil
 }
 
@@ -623,16 +787,35 @@ func (u *pollUsecase) AddParticipants(userID, pollID uint, req *models.AddPartic
 	if poll.Visibility != models.PollVisibilityInviteOnly {
 		return fmt.Errorf("can only add participants to invite-only polls")
 	}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if len(req.Weights) > 0 && !poll.WeightedVoting {
+		return fmt.Errorf("validation failed: participant weights require weighted voting")
+	}
+	if err := u.userRefs.CheckUsers(req.UserIDs...); err != nil {
+		return err
+	}
 
 	// Add participants
 	participants := make([]*models.PollParticipant, 0)
+	reweighted := make(map[uint]float64)
 	for _, participantID := range req.UserIDs {
+		weight, hasWeight := req.Weights[participantID]
+
 		// Check if user is already a participant
 		isParticipant, err := u.participantRepo.IsParticipant(participantID, pollID)
 		if err != nil {
 			continue // Skip on error
 		}
 		if isParticipant {
+			// Weights of invited participants change only before voting starts
+			if hasWeight {
+				if poll.Status != models.PollStatusDraft {
+					return fmt.Errorf("validation failed: weights of invited participants can only be changed while the poll is a draft")
+				}
+				reweighted[participantID] = weight
+			}
 			continue // Skip if already participant
 		}
 
@@ -641,10 +824,20 @@ func (u *pollUsecase) AddParticipants(userID, pollID uint, req *models.AddPartic
 			UserID:    participantID,
 			InvitedBy: userID,
 			InvitedAt: time.Now(),
+			Weight:    models.DefaultParticipantWeight,
+		}
+		if hasWeight {
+			participant.Weight = weight
 		}
 		participants = append(participants, participant)
 	}
 
+	for participantID, weight := range reweighted {
+		if err := u.participantRepo.UpdateWeight(participantID, pollID, weight); err != nil {
+			return fmt.Errorf("failed to update participant weight: %w", err)
+		}
+	}
+
 	if len(participants) > 0 {
 		if err := u.participantRepo.CreateMultiple(participants); err != nil {
 			return fmt.Errorf("failed to add participants: %w", err)
@@ -707,6 +900,7 @@ func (u *pollUsecase) CreateComment(userID, pollID uint, req *models.CreateComme
 	}
 
 	// Validate parent comment if provided
+	depth := 0
 	if req.ParentID != nil {
 		parentComment, err := u.commentRepo.GetByID(*req.ParentID)
 		if err != nil {
@@ -715,6 +909,10 @@ func (u *pollUsecase) CreateComment(userID, pollID uint, req *models.CreateComme
 		if parentComment.PollID != pollID {
 			return nil, fmt.Errorf("parent comment does not belong to this poll")
 		}
+		if err := sharedmodels.ValidateReplyDepth(parentComment.Depth); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		depth = parentComment.Depth + 1
 	}
 
 	// Create comment
@@ -724,6 +922,7 @@ func (u *pollUsecase) CreateComment(userID, pollID uint, req *models.CreateComme
 		Content:  strings.TrimSpace(req.Content),
 		ParentID: req.ParentID,
 	}
+	comment.Depth = depth
 
 	if err := u.commentRepo.Create(comment); err != nil {
 		return nil, fmt.Errorf("failed to create comment: %w", err)
@@ -767,8 +966,9 @@ func (u *pollUsecase) GetComments(userID, pollID uint, limit, offset int) ([]*mo
 	// Convert to response format
 	responses := make([]*models.PollCommentResponse, len(comments))
 	for i, comment := range comments {
-		responses[i] = comment.ToResponse()
+		responses[i] = comment.ToResponseForUser(userID)
 	}
+	maskHiddenComments(responses, userID, poll.CreatedBy == userID)
 
 	return responses, total, nil
 }
@@ -914,11 +1114,13 @@ func (u *pollUsecase) validateStatusTransition(from, to models.PollStatus) error
 func (u *pollUsecase) createVotes(userID uint, poll *models.Poll, req *models.VotePollRequest) ([]*models.PollVote, error) {
 	var votes []*models.PollVote
 
-	// Determine if vote should be anonymous
+	// Determine if vote should be anonymous. Voter hash links anonymous votes
+	// to the voter for changing and retracting without storing the user ID.
 	var voteUserID *uint
 	if !req.IsAnonymous {
 		voteUserID = &userID
 	}
+	voterHash := models.VoterHash(poll.ID, userID)
 
 	switch poll.Type {
 	case models.PollTypeSingleChoice, models.PollTypeMultipleChoice:
@@ -933,6 +1135,7 @@ func (u *pollUsecase) createVotes(userID uint, poll *models.Poll, req *models.Vo
 				OptionID:    &optionID,
 				UserID:      voteUserID,
 				IsAnonymous: req.IsAnonymous,
+				VoterHash:   voterHash,
 				Comment:     req.Comment,
 			}
 			votes = append(votes, vote)
@@ -943,6 +1146,7 @@ func (u *pollUsecase) createVotes(userID uint, poll *models.Poll, req *models.Vo
 			PollID:      poll.ID,
 			UserID:      voteUserID,
 			IsAnonymous: req.IsAnonymous,
+			VoterHash:   voterHash,
 			TextValue:   req.TextValue,
 			Comment:     req.Comment,
 		}
@@ -960,6 +1164,7 @@ func (u *pollUsecase) createVotes(userID uint, poll *models.Poll, req *models.Vo
 				OptionID:    &optionID,
 				UserID:      voteUserID,
 				IsAnonymous: req.IsAnonymous,
+				VoterHash:   voterHash,
 				RatingValue: &rating,
 				Comment:     req.Comment,
 			}
@@ -978,6 +1183,7 @@ func (u *pollUsecase) createVotes(userID uint, poll *models.Poll, req *models.Vo
 				OptionID:     &optionID,
 				UserID:       voteUserID,
 				IsAnonymous:  req.IsAnonymous,
+				VoterHash:    voterHash,
 				RankingValue: &ranking,
 				Comment:      req.Comment,
 			}
@@ -1000,16 +1206,20 @@ func (u *pollUsecase) isValidOption(poll *models.Poll, optionID uint) bool {
 
 // loadPollStatistics loads computed statistics for a poll
 func (u *pollUsecase) loadPollStatistics(poll *models.Poll, userID uint) {
-	// Load vote count
-	if voteCount, err := u.voteRepo.GetVoteCount(poll.ID); err == nil {
-		poll.TotalVotes = int(voteCount)
+	totalVotes, totalVoters, err := u.voteRepo.GetVoteTotals(poll.ID)
+	if err != nil {
+		return
 	}
 
-	// Load voter count
-	if voterCount, err := u.voteRepo.GetVoterCount(poll.ID); err == nil {
-		poll.TotalVoters = int(voterCount)
-	}
+	// Option counts are best effort, options are shown without votes on failure
+	optionVoteCounts, _ := u.voteRepo.GetOptionVoteCounts(poll.ID)
+
+	applyVoteCounts(poll, totalVotes, totalVoters, optionVoteCounts)
+	u.loadUserStatistics(poll, userID)
+}
 
+// loadUserStatistics loads voting status of the user and participation rate
+func (u *pollUsecase) loadUserStatistics(poll *models.Poll, userID uint) {
 	// Check if user has voted
 	if hasVoted, err := u.voteRepo.HasUserVoted(userID, poll.ID); err == nil {
 		poll.UserHasVoted = hasVoted
@@ -1021,16 +1231,31 @@ func (u *pollUsecase) loadPollStatistics(poll *models.Poll, userID uint) {
 			poll.ParticipantRate = models.CalculateParticipantRate(poll.TotalVoters, int(participantCount))
 		}
 	}
+}
 
-	// Load option statistics
-	if optionVoteCounts, err := u.voteRepo.GetOptionVoteCounts(poll.ID); err == nil {
-		for _, option := range poll.Options {
-			if count, exists := optionVoteCounts[option.ID]; exists {
-				option.VoteCount = int(count)
-				if poll.TotalVotes > 0 {
-					option.VotePercent = models.CalculateVotePercent(option.VoteCount, poll.TotalVotes)
-				}
-			}
+// applyVoteCounts sets vote totals of a poll and vote counts of its options
+func applyVoteCounts(poll *models.Poll, totalVotes, totalVoters int64, optionVoteCounts map[uint]int64) {
+	poll.TotalVotes = int(totalVotes)
+	poll.TotalVoters = int(totalVoters)
+
+	for i := range poll.Options {
+		option := &poll.Options[i]
+		option.VoteCount = int(optionVoteCounts[option.ID])
+		if poll.TotalVotes > 0 {
+			option.VotePercent = models.CalculateVotePercent(option.VoteCount, poll.TotalVotes)
 		}
 	}
 }
+
+// withQueryCounter returns a copy of the usecase whose repository queries are counted
+func (u *pollUsecase) withQueryCounter(counter *database.QueryCounter) *pollUsecase {
+	return &pollUsecase{
+		pollRepo:        u.pollRepo.WithQueryCounter(counter),
+		optionRepo:      u.optionRepo,
+		voteRepo:        u.voteRepo.WithQueryCounter(counter),
+		participantRepo: u.participantRepo.WithQueryCounter(counter),
+		commentRepo:     u.commentRepo,
+		delegationRepo:  u.delegationRepo,
+		snapshotRepo:    u.snapshotRepo,
+	}
+}
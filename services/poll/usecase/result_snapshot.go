@@ -0,0 +1,277 @@
+// File: services/poll/usecase/result_snapshot.go
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+// computeResults counts the votes of a poll for no particular viewer: option statistics are set
+// on the poll and all delegations are returned
+func (u *pollUsecase) computeResults(poll *models.Poll) (*models.PollResultsResponse, error) {
+	// Get basic statistics
+	totalVotes, totalVoters, err := u.voteRepo.GetVoteTotals(poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote totals: %w", err)
+	}
+
+	optionVoteCounts, err := u.voteRepo.GetOptionVoteCounts(poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option vote counts: %w", err)
+	}
+
+	// Participant votes of invite-only polls with voting rules
+	var voters *pollVoters
+	if poll.WeightedVoting || poll.QuorumPercent != nil || poll.AllowDelegation {
+		if voters, err = u.getPollVoters(poll); err != nil {
+			return nil, fmt.Errorf("failed to get poll voters: %w", err)
+		}
+	}
+
+	// A counted delegated vote repeats the vote of its delegate
+	delegatedVoters := 0
+	if voters != nil {
+		for _, vote := range voters.delegated {
+			if !vote.Counted {
+				continue
+			}
+			delegatedVoters++
+			totalVoters++
+			for _, optionID := range voters.options[vote.VotedBy] {
+				optionVoteCounts[optionID]++
+				totalVotes++
+			}
+		}
+	}
+
+	applyVoteCounts(poll, totalVotes, totalVoters, optionVoteCounts)
+
+	// Create response
+	results := &models.PollResultsResponse{
+		TotalVotes:    int(totalVotes),
+		TotalVoters:   int(totalVoters),
+		VotesByOption: make(map[uint]int, len(optionVoteCounts)),
+	}
+	for optionID, count := range optionVoteCounts {
+		results.VotesByOption[optionID] = int(count)
+	}
+
+	// Weighted totals, quorum and delegations of invite-only polls
+	if voters != nil {
+		applyWeightedResults(poll, voters, results)
+		if poll.AllowDelegation {
+			results.DelegatedVoters = delegatedVoters
+			results.Delegations = voters.delegated // Filtered by visibility for the viewer
+		}
+	}
+
+	// Type-specific data
+	switch poll.Type {
+	case models.PollTypeOpenText:
+		textResponses, err := u.voteRepo.GetTextResponses(poll.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get text responses: %w", err)
+		}
+		results.TextResponses = textResponses
+
+	case models.PollTypeRating:
+		ratingStats, err := u.voteRepo.GetRatingStats(poll.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rating stats: %w", err)
+		}
+
+		results.RatingStats = make(map[uint]*models.RatingStats, len(poll.Options))
+		for i := range poll.Options {
+			option := &poll.Options[i]
+			stats, exists := ratingStats[option.ID]
+			if !exists {
+				stats = &models.RatingStats{OptionID: option.ID}
+			}
+			option.RatingAvg = stats.Average
+			results.RatingStats[option.ID] = stats
+		}
+
+	case models.PollTypeRanking:
+		rankingStats, err := u.voteRepo.GetRankingStats(poll.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ranking stats: %w", err)
+		}
+
+		results.RankingStats = make(map[uint]*models.RankingStats, len(poll.Options))
+		for i := range poll.Options {
+			option := &poll.Options[i]
+			stats, exists := rankingStats[option.ID]
+			if !exists {
+				stats = &models.RankingStats{OptionID: option.ID}
+			}
+			option.RankingAvg = stats.AverageRank
+			results.RankingStats[option.ID] = stats
+		}
+	}
+
+	return results, nil
+}
+
+// snapshotResults returns results of a closed poll from its latest snapshot. A poll closed
+// before snapshots were taken, or whose snapshot failed when it closed, gets one now.
+func (u *pollUsecase) snapshotResults(poll *models.Poll) (*models.PollResultsResponse, error) {
+	snapshot, err := u.snapshotRepo.GetLatest(poll.ID)
+	if errors.Is(err, models.ErrSnapshotNotFound) {
+		results, snapshot, err := u.takeSnapshot(poll, models.SnapshotReasonBackfilled, "", nil)
+		if results == nil {
+			return nil, err
+		}
+		if err != nil {
+			// Another request may be taking the snapshot, serve the votes counted meanwhile
+			logger.WithFields(map[string]interface{}{
+				"poll_id": poll.ID,
+				"error":   err.Error(),
+			}).Warn("Failed to backfill poll result snapshot")
+			return results, nil
+		}
+		results.Snapshot = snapshot
+		return results, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll result snapshot: %w", err)
+	}
+
+	results, err := snapshot.Results()
+	if err != nil {
+		return nil, err
+	}
+	applySnapshotResults(poll, results)
+	results.Snapshot = snapshot
+	return results, nil
+}
+
+// takeSnapshot counts the votes of a poll and saves them as its next results snapshot. The
+// computed results are returned even if saving fails.
+func (u *pollUsecase) takeSnapshot(poll *models.Poll, reason models.SnapshotReason, note string, createdBy *uint) (*models.PollResultsResponse, *models.PollResultSnapshot, error) {
+	results, err := u.computeResults(poll)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot, err := models.NewPollResultSnapshot(poll.ID, results, reason)
+	if err != nil {
+		return results, nil, err
+	}
+	snapshot.Note = note
+	snapshot.CreatedBy = createdBy
+
+	if err := u.snapshotRepo.Create(snapshot); err != nil {
+		return results, nil, err
+	}
+	return results, snapshot, nil
+}
+
+// snapshotClosedPoll takes the results snapshot of a poll that has just closed. A failure is
+// only logged, the snapshot is then taken when results are first requested.
+func (u *pollUsecase) snapshotClosedPoll(pollID uint) {
+	poll, err := u.pollRepo.GetByIDWithOptions(pollID)
+	if err == nil {
+		_, _, err = u.takeSnapshot(poll, models.SnapshotReasonClosed, "", nil)
+	}
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"poll_id": pollID,
+			"error":   err.Error(),
+		}).Error("Failed to take poll result snapshot")
+	}
+}
+
+// applySnapshotResults sets option statistics of a poll from snapshot results
+func applySnapshotResults(poll *models.Poll, results *models.PollResultsResponse) {
+	optionVoteCounts := make(map[uint]int64, len(results.VotesByOption))
+	for optionID, count := range results.VotesByOption {
+		optionVoteCounts[optionID] = int64(count)
+	}
+	applyVoteCounts(poll, int64(results.TotalVotes), int64(results.TotalVoters), optionVoteCounts)
+
+	var totalWeight float64
+	for _, weight := range results.WeightedVotesByOption {
+		totalWeight += weight
+	}
+
+	for i := range poll.Options {
+		option := &poll.Options[i]
+		if results.WeightedVotesByOption != nil {
+			option.WeightedVotes = results.WeightedVotesByOption[option.ID]
+			if totalWeight > 0 {
+				option.WeightedPercent = option.WeightedVotes / totalWeight * 100.0
+			}
+		}
+		if stats, ok := results.RatingStats[option.ID]; ok {
+			option.RatingAvg = stats.Average
+		}
+		if stats, ok := results.RankingStats[option.ID]; ok {
+			option.RankingAvg = stats.AverageRank
+		}
+	}
+}
+
+// GetPollResultSnapshots returns all results snapshots of a poll, latest first (admin only)
+func (u *pollUsecase) GetPollResultSnapshots(pollID uint) ([]*models.PollResultSnapshot, error) {
+	if _, err := u.pollRepo.GetByID(pollID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	return u.snapshotRepo.GetByPoll(pollID)
+}
+
+// RecomputePollResults counts the votes of a closed poll again and saves them as a new results
+// snapshot with the admin's note, for the rare case the snapshot taken at close is wrong.
+// Previous snapshots are kept for audit.
+func (u *pollUsecase) RecomputePollResults(adminID, pollID uint, req *models.RecomputePollResultsRequest) (*models.RecomputePollResultsResponse, error) {
+	note := strings.TrimSpace(req.Note)
+	if len(note) < 3 {
+		return nil, fmt.Errorf("validation failed: note is required")
+	}
+
+	poll, err := u.pollRepo.GetByIDWithOptions(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+	if poll.Status != models.PollStatusClosed && poll.Status != models.PollStatusArchived {
+		return nil, fmt.Errorf("cannot recompute results: poll is not closed")
+	}
+
+	previous, err := u.snapshotRepo.GetLatest(pollID)
+	if err != nil && !errors.Is(err, models.ErrSnapshotNotFound) {
+		return nil, fmt.Errorf("failed to get poll result snapshot: %w", err)
+	}
+
+	_, snapshot, err := u.takeSnapshot(poll, models.SnapshotReasonRecomputed, note, &adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recompute poll results: %w", err)
+	}
+
+	response := &models.RecomputePollResultsResponse{
+		Snapshot: snapshot,
+		Previous: previous,
+		Changed:  previous == nil || previous.Data != snapshot.Data,
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"poll_id":  pollID,
+		"admin_id": adminID,
+		"version":  snapshot.Version,
+		"changed":  response.Changed,
+		"note":     note,
+	}).Info("Poll results recomputed")
+
+	return response, nil
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+// timelineCacheKey identifies a cached timeline. The poll version changes when a poll is
+// reopened or edited, so such polls never get a stale timeline.
+type timelineCacheKey struct {
+	pollID  uint
+	version uint
+	bucket  models.TimelineBucket
+}
+
+type timelineCacheEntry struct {
+	timeline  *models.PollResultsTimelineResponse
+	expiresAt time.Time
+}
+
+// timelineCache keeps results timelines of finished polls, whose votes no longer change
+type timelineCache struct {
+	mu      sync.Mutex
+	entries map[timelineCacheKey]timelineCacheEntry
+}
+
+func newTimelineCache() *timelineCache {
+	return &timelineCache{entries: make(map[timelineCacheKey]timelineCacheEntry)}
+}
+
+func (c *timelineCache) get(key timelineCacheKey) (*models.PollResultsTimelineResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.timeline, true
+}
+
+func (c *timelineCache) put(key timelineCacheKey, timeline *models.PollResultsTimelineResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = timelineCacheEntry{timeline: timeline, expiresAt: now.Add(models.TimelineCacheTTL)}
+}
+
+// GetPollResultsTimeline returns vote counts of a poll per option bucketed by hour or day since its
+// activation, with running totals for charts. An empty bucket picks hours, or days when the voting
+// window has more than MaxTimelineBuckets hours. Timelines of finished polls are cached.
+func (u *pollUsecase) GetPollResultsTimeline(userID, pollID uint, bucket models.TimelineBucket) (*models.PollResultsTimelineResponse, error) {
+	if bucket != "" && bucket.Duration() == 0 {
+		return nil, fmt.Errorf("validation failed: bucket must be %s or %s", models.TimelineBucketHour, models.TimelineBucketDay)
+	}
+
+	poll, err := u.pollRepo.GetByIDWithOptions(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	// Check access rights
+	if !u.hasPollAccess(userID, poll) {
+		return nil, fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	// Check if user can view results
+	if !u.canViewResults(userID, poll) {
+		return nil, fmt.Errorf("access denied: results not available")
+	}
+
+	finished := poll.Status != models.PollStatusDraft && poll.Status != models.PollStatusActive
+	key := timelineCacheKey{pollID: poll.ID, version: poll.Version, bucket: bucket}
+	if finished {
+		if timeline, ok := u.timelines.get(key); ok {
+			return timeline, nil
+		}
+	}
+
+	from, to := votingWindow(poll)
+	if bucket == "" {
+		bucket = models.TimelineBucketHour
+		if to.Sub(from) >= models.MaxTimelineBuckets*time.Hour {
+			bucket = models.TimelineBucketDay
+		}
+	}
+
+	counts, err := u.voteRepo.GetVoteTimeline(pollID, bucket.Duration())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote timeline: %w", err)
+	}
+
+	timeline, err := buildTimeline(poll, bucket, from, to, counts)
+	if err != nil {
+		return nil, err
+	}
+
+	if finished {
+		u.timelines.put(key, timeline)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"poll_id": pollID,
+		"user_id": userID,
+		"bucket":  bucket,
+		"points":  len(timeline.Points),
+	}).Debug("Poll results timeline computed")
+
+	return timeline, nil
+}
+
+// votingWindow returns when voting on the poll started and ended, now for active polls.
+// Polls activated before activation times were stored start at their start or creation time.
+func votingWindow(poll *models.Poll) (time.Time, time.Time) {
+	from := poll.CreatedAt
+	if poll.ActivatedAt != nil {
+		from = *poll.ActivatedAt
+	} else if poll.StartTime != nil {
+		from = *poll.StartTime
+	}
+
+	to := time.Now()
+	if poll.Status != models.PollStatusActive {
+		// The status change to closed is the last update of a finished poll
+		to = poll.UpdatedAt
+	}
+	if poll.EndTime != nil && poll.EndTime.Before(to) {
+		to = *poll.EndTime
+	}
+	if to.Before(from) {
+		to = from
+	}
+	return from, to
+}
+
+// buildTimeline converts grouped vote counts to consecutive points from the bucket of from to the
+// bucket of to, widened to cover every vote. Empty buckets are included so charts keep their scale.
+func buildTimeline(poll *models.Poll, bucket models.TimelineBucket, from, to time.Time, counts []models.VoteBucketCount) (*models.PollResultsTimelineResponse, error) {
+	width := int64(bucket.Duration() / time.Second)
+	first := from.Unix() / width
+	last := to.Unix() / width
+	for _, count := range counts {
+		if count.Bucket < first {
+			first = count.Bucket
+		}
+		if count.Bucket > last {
+			last = count.Bucket
+		}
+	}
+
+	if last-first+1 > models.MaxTimelineBuckets {
+		return nil, fmt.Errorf("validation failed: timeline has more than %d buckets, use a wider bucket", models.MaxTimelineBuckets)
+	}
+
+	response := poll.ToResponse()
+	timeline := &models.PollResultsTimelineResponse{
+		PollID:  poll.ID,
+		Bucket:  bucket,
+		From:    time.Unix(first*width, 0).UTC(),
+		To:      time.Unix((last+1)*width, 0).UTC(),
+		Options: response.Options,
+		Points:  make([]*models.PollTimelinePoint, 0, last-first+1),
+	}
+	if timeline.Options == nil {
+		timeline.Options = []*models.PollOptionResponse{}
+	}
+
+	byBucket := make(map[int64][]models.VoteBucketCount)
+	for _, count := range counts {
+		byBucket[count.Bucket] = append(byBucket[count.Bucket], count)
+	}
+
+	totals := make(map[uint]int, len(poll.Options))
+	for _, option := range poll.Options {
+		totals[option.ID] = 0
+	}
+	total := 0
+
+	for n := first; n <= last; n++ {
+		point := &models.PollTimelinePoint{
+			Start:          time.Unix(n*width, 0).UTC(),
+			VotesByOption:  make(map[uint]int, len(totals)),
+			TotalsByOption: make(map[uint]int, len(totals)),
+		}
+		for optionID := range totals {
+			point.VotesByOption[optionID] = 0
+		}
+
+		for _, count := range byBucket[n] {
+			point.Votes += int(count.Count)
+			if count.OptionID != nil {
+				point.VotesByOption[*count.OptionID] += int(count.Count)
+				totals[*count.OptionID] += int(count.Count)
+			}
+		}
+
+		total += point.Votes
+		point.TotalVotes = total
+		for optionID, optionTotal := range totals {
+			point.TotalsByOption[optionID] = optionTotal
+		}
+		timeline.Points = append(timeline.Points, point)
+	}
+
+	return timeline, nil
+}
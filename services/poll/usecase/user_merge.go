@@ -0,0 +1,22 @@
+// File: services/poll/usecase/user_merge.go
+package usecase
+
+import (
+	"fmt"
+
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// MergeUsers moves poll data of a duplicate account to the primary account
+func (u *pollUsecase) MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error) {
+	if req.PrimaryUserID == req.DuplicateUserID {
+		return nil, fmt.Errorf("validation failed: cannot merge user into itself")
+	}
+
+	result, err := u.pollRepo.MergeUsers(req.PrimaryUserID, req.DuplicateUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+
+	return result, nil
+}
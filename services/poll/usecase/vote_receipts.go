@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tachyon-messenger/services/poll/models"
+
+	"gorm.io/gorm"
+)
+
+// VerifyVoteReceipt confirms that the ballot a receipt was issued for is recorded in the poll and
+// shows the choice to the receipt holder. The ballot is compared with the current votes of the
+// user, a ballot they have changed or retracted since is reported as superseded.
+func (u *pollUsecase) VerifyVoteReceipt(userID, pollID uint, receipt string) (*models.VoteReceiptResponse, error) {
+	if !models.IsValidReceipt(receipt) {
+		return nil, fmt.Errorf("validation failed: malformed vote receipt")
+	}
+
+	poll, err := u.pollRepo.GetByIDWithOptions(pollID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("poll not found")
+		}
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+	if !u.hasPollAccess(userID, poll) {
+		return nil, fmt.Errorf("access denied: insufficient permissions")
+	}
+
+	voteReceipt, err := u.voteRepo.GetReceipt(pollID, models.ReceiptHash(receipt))
+	if err != nil {
+		return nil, err
+	}
+	ballot, err := voteReceipt.Open(receipt)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidReceipt) {
+			return nil, fmt.Errorf("vote receipt not found")
+		}
+		return nil, err
+	}
+
+	votes, err := u.voteRepo.GetByUserID(userID, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user votes: %w", err)
+	}
+	current := ballot.Matches(votes)
+
+	return &models.VoteReceiptResponse{
+		PollID:     pollID,
+		Recorded:   current && poll.Status != models.PollStatusCancelled,
+		Superseded: !current,
+		Ballot:     ballot,
+		Options:    ballotOptions(poll, ballot),
+	}, nil
+}
+
+// ballotOf returns the choice of a vote request recorded by its receipt
+func ballotOf(req *models.VotePollRequest) *models.VoteReceiptBallot {
+	return &models.VoteReceiptBallot{
+		OptionIDs:     req.OptionIDs,
+		TextValue:     req.TextValue,
+		RatingValues:  req.RatingValues,
+		RankingValues: req.RankingValues,
+	}
+}
+
+// ballotOptions returns options of the poll chosen, rated or ranked in the ballot
+func ballotOptions(poll *models.Poll, ballot *models.VoteReceiptBallot) []*models.VoteReceiptOption {
+	chosen := make(map[uint]bool)
+	for _, id := range ballot.OptionIDs {
+		chosen[id] = true
+	}
+	for id := range ballot.RatingValues {
+		chosen[id] = true
+	}
+	for id := range ballot.RankingValues {
+		chosen[id] = true
+	}
+
+	options := make([]*models.VoteReceiptOption, 0, len(chosen))
+	for _, option := range poll.Options {
+		if chosen[option.ID] {
+			options = append(options, &models.VoteReceiptOption{ID: option.ID, Text: option.Text})
+		}
+	}
+	return options
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"testing"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/services/poll/repository/repotest"
+)
+
+func TestVerifyVoteReceipt(t *testing.T) {
+	repos := repotest.New(t)
+	uc := NewPollUsecase(repos.Polls, repos.Options, repos.Votes, repos.Participants, repos.Comments,
+		repos.Delegations, repos.Categories, repos.Snapshots, nil, nil)
+
+	poll := repos.Poll(t, 1, []string{"Tea", "Coffee"}, func(p *models.Poll) {
+		p.AllowAnonymous = true
+		p.AllowVoteChange = true
+	})
+	tea, coffee := poll.Options[0].ID, poll.Options[1].ID
+
+	vote := func(optionID uint) string {
+		t.Helper()
+		_, receipt, err := uc.VotePoll(2, poll.ID, &models.VotePollRequest{OptionIDs: []uint{optionID}, IsAnonymous: true})
+		if err != nil {
+			t.Fatalf("VotePoll failed: %v", err)
+		}
+		return receipt
+	}
+	verify := func(userID uint, receipt string) *models.VoteReceiptResponse {
+		t.Helper()
+		response, err := uc.VerifyVoteReceipt(userID, poll.ID, receipt)
+		if err != nil {
+			t.Fatalf("VerifyVoteReceipt failed: %v", err)
+		}
+		return response
+	}
+
+	teaReceipt := vote(tea)
+	if response := verify(2, teaReceipt); !response.Recorded || response.Superseded {
+		t.Errorf("current ballot: recorded = %v, superseded = %v", response.Recorded, response.Superseded)
+	}
+	if response := verify(3, teaReceipt); response.Recorded {
+		t.Error("expected the ballot not to verify against the votes of another user")
+	}
+
+	// Re-voting supersedes the previous ballot but not the new one
+	coffeeReceipt := vote(coffee)
+	if response := verify(2, teaReceipt); response.Recorded || !response.Superseded {
+		t.Errorf("re-voted ballot: recorded = %v, superseded = %v", response.Recorded, response.Superseded)
+	}
+	if response := verify(2, coffeeReceipt); !response.Recorded || response.Superseded {
+		t.Errorf("new ballot: recorded = %v, superseded = %v", response.Recorded, response.Superseded)
+	}
+
+	// Retracting supersedes the ballot, the receipt still shows the choice
+	if err := uc.RetractVote(2, poll.ID); err != nil {
+		t.Fatalf("RetractVote failed: %v", err)
+	}
+	response := verify(2, coffeeReceipt)
+	if response.Recorded || !response.Superseded {
+		t.Errorf("retracted ballot: recorded = %v, superseded = %v", response.Recorded, response.Superseded)
+	}
+	if len(response.Options) != 1 || response.Options[0].ID != coffee {
+		t.Errorf("unexpected options of the retracted ballot: %+v", response.Options)
+	}
+}
+
+func TestVoteReceiptBallotMatches(t *testing.T) {
+	option := func(id uint) *uint { return &id }
+	value := func(v int) *int { return &v }
+
+	cases := []struct {
+		name   string
+		ballot models.VoteReceiptBallot
+		votes  []*models.PollVote
+		want   bool
+	}{
+		{
+			name:   "options in another order",
+			ballot: models.VoteReceiptBallot{OptionIDs: []uint{2, 1}},
+			votes:  []*models.PollVote{{OptionID: option(1), IsAnonymous: true}, {OptionID: option(2), IsAnonymous: true}},
+			want:   true,
+		},
+		{
+			name:   "missing option",
+			ballot: models.VoteReceiptBallot{OptionIDs: []uint{1, 2}},
+			votes:  []*models.PollVote{{OptionID: option(1), IsAnonymous: true}},
+		},
+		{
+			name:   "ratings",
+			ballot: models.VoteReceiptBallot{RatingValues: map[uint]int{1: 4}},
+			votes:  []*models.PollVote{{OptionID: option(1), RatingValue: value(4), IsAnonymous: true}},
+			want:   true,
+		},
+		{
+			name:   "changed ranking",
+			ballot: models.VoteReceiptBallot{RankingValues: map[uint]int{1: 1, 2: 2}},
+			votes:  []*models.PollVote{{OptionID: option(1), RankingValue: value(2), IsAnonymous: true}, {OptionID: option(2), RankingValue: value(1), IsAnonymous: true}},
+		},
+		{
+			name:   "text",
+			ballot: models.VoteReceiptBallot{TextValue: "More tea"},
+			votes:  []*models.PollVote{{TextValue: "More tea", IsAnonymous: true}},
+			want:   true,
+		},
+		{
+			name:   "same choice voted openly",
+			ballot: models.VoteReceiptBallot{OptionIDs: []uint{1}},
+			votes:  []*models.PollVote{{OptionID: option(1)}},
+		},
+		{
+			name:   "no votes",
+			ballot: models.VoteReceiptBallot{OptionIDs: []uint{1}},
+		},
+	}
+
+	for _, tc := range cases {
+		if got := tc.ballot.Matches(tc.votes); got != tc.want {
+			t.Errorf("%s: Matches = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// File: services/poll/usecase/voting_rules.go
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"tachyon-messenger/services/poll/models"
+	"tachyon-messenger/shared/logger"
+)
+
+// pollWeights holds the weight of participants of a poll and of the votes they cast.
+// Without weighted voting every participant weighs 1.
+type pollWeights struct {
+	total    float64          // Вес всех приглашенных участников
+	voted    float64          // Вес проголосовавших участников
+	byOption map[uint]float64 // Вес голосов за каждый вариант
+}
+
+// pollVoters holds the invited participants of a poll, the options each of them voted for and
+// the votes of participants who delegated their vote instead of voting
+type pollVoters struct {
+	participants []*models.PollParticipant
+	options      map[uint][]uint         // Варианты по ID проголосовавшего участника
+	delegated    []*models.DelegatedVote // Только для опросов с делегированием
+}
+
+// getPollVoters collects the votes of the invited participants of a poll. Votes of users who are
+// not participants, e.g. the creator, are left out.
+func (u *pollUsecase) getPollVoters(poll *models.Poll) (*pollVoters, error) {
+	participants, err := u.participantRepo.GetByPollID(poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	votes, err := u.voteRepo.GetVoterVotes(poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes: %w", err)
+	}
+
+	voters := &pollVoters{
+		participants: participants,
+		options:      make(map[uint][]uint, len(participants)),
+	}
+	byKey := make(map[string]uint, 2*len(participants))
+	for _, participant := range participants {
+		// Votes created before voter hashes were stored are matched by user ID
+		byKey[models.VoterHash(poll.ID, participant.UserID)] = participant.UserID
+		byKey[userVoterKey(participant.UserID)] = participant.UserID
+	}
+
+	for _, vote := range votes {
+		key := vote.VoterHash
+		if key == "" && vote.UserID != nil {
+			key = userVoterKey(*vote.UserID)
+		}
+		userID, ok := byKey[key]
+		if !ok {
+			continue
+		}
+
+		options := voters.options[userID]
+		if vote.OptionID != nil {
+			options = append(options, *vote.OptionID)
+		}
+		voters.options[userID] = options
+	}
+
+	if poll.AllowDelegation {
+		if voters.delegated, err = u.getDelegatedVotes(poll, voters); err != nil {
+			return nil, err
+		}
+	}
+
+	return voters, nil
+}
+
+// getPollWeights sums participant weights of an invite-only poll and weighs its votes
+func (u *pollUsecase) getPollWeights(poll *models.Poll) (*pollWeights, error) {
+	voters, err := u.getPollVoters(poll)
+	if err != nil {
+		return nil, err
+	}
+	return voters.weights(poll), nil
+}
+
+// weights weighs the votes of participants. A counted delegated vote adds the weight of the
+// delegator to the options its delegate voted for.
+func (v *pollVoters) weights(poll *models.Poll) *pollWeights {
+	weights := &pollWeights{byOption: make(map[uint]float64)}
+	weightOf := make(map[uint]float64, len(v.participants))
+	for _, participant := range v.participants {
+		weight := float64(models.DefaultParticipantWeight)
+		if poll.WeightedVoting {
+			weight = participant.Weight
+		}
+		weights.total += weight
+		weightOf[participant.UserID] = weight
+	}
+
+	for userID, options := range v.options {
+		weights.add(weightOf[userID], options)
+	}
+	for _, vote := range v.delegated {
+		if vote.Counted {
+			weights.add(weightOf[vote.DelegatorID], v.options[vote.VotedBy])
+		}
+	}
+
+	return weights
+}
+
+// add counts a voter of the given weight who voted for the options
+func (w *pollWeights) add(weight float64, options []uint) {
+	w.voted += weight
+	for _, optionID := range options {
+		w.byOption[optionID] += weight
+	}
+}
+
+// quorum returns the turnout of a poll against its quorum, nil for polls without a quorum
+func (w *pollWeights) quorum(poll *models.Poll) *models.PollQuorum {
+	if poll.QuorumPercent == nil {
+		return nil
+	}
+
+	quorum := &models.PollQuorum{
+		RequiredPercent: *poll.QuorumPercent,
+		TotalWeight:     w.total,
+		VotedWeight:     w.voted,
+	}
+	if w.total > 0 {
+		quorum.TurnoutPercent = w.voted / w.total * 100.0
+		quorum.Reached = quorum.TurnoutPercent >= quorum.RequiredPercent
+	}
+	return quorum
+}
+
+// applyWeightedResults adds weighted vote totals and the quorum state to poll results
+func applyWeightedResults(poll *models.Poll, voters *pollVoters, results *models.PollResultsResponse) {
+	if !poll.WeightedVoting && poll.QuorumPercent == nil {
+		return
+	}
+
+	weights := voters.weights(poll)
+	results.Quorum = weights.quorum(poll)
+
+	if poll.WeightedVoting {
+		var totalWeight float64
+		for _, weight := range weights.byOption {
+			totalWeight += weight
+		}
+
+		results.WeightedVotesByOption = make(map[uint]float64, len(poll.Options))
+		for i := range poll.Options {
+			option := &poll.Options[i]
+			option.WeightedVotes = weights.byOption[option.ID]
+			if totalWeight > 0 {
+				option.WeightedPercent = option.WeightedVotes / totalWeight * 100.0
+			}
+			results.WeightedVotesByOption[option.ID] = option.WeightedVotes
+		}
+	}
+}
+
+// resolveOutcome returns the quorum outcome of a closing poll with the votes cast so far,
+// empty for polls without a quorum
+func (u *pollUsecase) resolveOutcome(poll *models.Poll) (models.PollOutcome, error) {
+	if poll.QuorumPercent == nil {
+		return "", nil
+	}
+
+	weights, err := u.getPollWeights(poll)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve quorum: %w", err)
+	}
+	if weights.quorum(poll).Reached {
+		return models.PollOutcomeQuorumReached, nil
+	}
+	return models.PollOutcomeQuorumNotReached, nil
+}
+
+// closePoll closes an active poll, resolving it as "quorum not reached" when turnout of a poll
+// with a quorum is below the threshold, and takes the snapshot its results are served from
+func (u *pollUsecase) closePoll(poll *models.Poll) error {
+	outcome, err := u.resolveOutcome(poll)
+	if err != nil {
+		return err
+	}
+
+	if err := u.pollRepo.Close(poll.ID, outcome); err != nil {
+		return err
+	}
+	poll.Status = models.PollStatusClosed
+	poll.Outcome = outcome
+	u.snapshotClosedPoll(poll.ID)
+
+	if outcome != "" {
+		logger.WithFields(map[string]interface{}{
+			"poll_id": poll.ID,
+			"outcome": outcome,
+		}).Info("Poll closed with quorum outcome")
+	}
+	return nil
+}
+
+// CloseExpiredPolls closes active polls whose end time has passed and returns their number
+func (u *pollUsecase) CloseExpiredPolls() (int, error) {
+	polls, err := u.pollRepo.GetExpiredPolls()
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, poll := range polls {
+		if err := u.closePoll(poll); err != nil {
+			if errors.Is(err, models.ErrPollNotActive) {
+				continue // Closed meanwhile by its creator
+			}
+			return closed, fmt.Errorf("failed to close poll %d: %w", poll.ID, err)
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+// userVoterKey identifies votes created before voter hashes were stored by their user ID
+func userVoterKey(userID uint) string {
+	return "u" + strconv.FormatUint(uint64(userID), 10)
+}
@@ -3,10 +3,14 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -62,8 +66,8 @@ func (h *TaskHandler) AddComment(c *gin.Context) {
 		}).Warn("Invalid request body for add comment")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -158,8 +162,8 @@ func (h *TaskHandler) GetTaskComments(c *gin.Context) {
 		}).Warn("Invalid filter parameters")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid filter parameters",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_filter_parameters"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -254,8 +258,8 @@ func (h *TaskHandler) UpdateComment(c *gin.Context) {
 		}).Warn("Invalid request body for update comment")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -373,3 +377,186 @@ func (h *TaskHandler) DeleteComment(c *gin.Context) {
 		"request_id": requestID,
 	})
 }
+
+// AddCommentReaction handles adding an emoji reaction to a task comment
+// POST /api/v1/comments/:id/reactions
+func (h *TaskHandler) AddCommentReaction(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse comment ID from URL parameter
+	idStr := c.Param("id")
+	commentID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"comment_id": idStr,
+			"error":      err.Error(),
+		}).Warn("Invalid comment ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid comment ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req sharedmodels.CommentReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"comment_id": commentID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for add comment reaction")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	reactions, err := h.taskUsecase.AddCommentReaction(userID, uint(commentID), &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"comment_id": commentID,
+			"error":      err.Error(),
+		}).Error("Failed to add comment reaction")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if containsAccessDeniedError(err.Error()) {
+			statusCode = http.StatusForbidden
+		} else if containsValidationError(err.Error()) || strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to add reaction",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"comment_id": commentID,
+		"emoji":      req.Emoji,
+	}).Info("Comment reaction added successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reaction added successfully",
+		"reactions":  reactions,
+		"request_id": requestID,
+	})
+}
+
+// RemoveCommentReaction handles removing an emoji reaction from a task comment
+// DELETE /api/v1/comments/:id/reactions?emoji=
+func (h *TaskHandler) RemoveCommentReaction(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse comment ID from URL parameter
+	idStr := c.Param("id")
+	commentID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"comment_id": idStr,
+			"error":      err.Error(),
+		}).Warn("Invalid comment ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid comment ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	emoji := c.Query("emoji")
+	if emoji == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Emoji is required",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	reactions, err := h.taskUsecase.RemoveCommentReaction(userID, uint(commentID), emoji)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"comment_id": commentID,
+			"emoji":      emoji,
+			"error":      err.Error(),
+		}).Error("Failed to remove comment reaction")
+
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if containsAccessDeniedError(err.Error()) {
+			statusCode = http.StatusForbidden
+		} else if containsValidationError(err.Error()) || strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to remove reaction",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"comment_id": commentID,
+		"emoji":      emoji,
+	}).Info("Comment reaction removed successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Reaction removed successfully",
+		"reactions":  reactions,
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDependencies handles getting tasks a task depends on and tasks blocked by it
+// GET /api/v1/tasks/:id/dependencies
+func (h *TaskHandler) GetDependencies(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, taskID, ok := parseTaskRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	dependencies, err := h.taskUsecase.GetDependencies(userID, taskID)
+	if err != nil {
+		respondDependencyError(c, requestID, userID, taskID, err, "Failed to get task dependencies")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dependencies": dependencies,
+		"request_id":   requestID,
+	})
+}
+
+// AddDependency handles making a task depend on another one
+// POST /api/v1/tasks/:id/dependencies
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, taskID, ok := parseTaskRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.AddTaskDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	dependencies, err := h.taskUsecase.AddDependency(userID, taskID, &req)
+	if err != nil {
+		respondDependencyError(c, requestID, userID, taskID, err, "Failed to add task dependency")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":    requestID,
+		"user_id":       userID,
+		"task_id":       taskID,
+		"depends_on_id": req.DependsOnID,
+	}).Info("Task dependency added successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Task dependency added successfully",
+		"dependencies": dependencies,
+		"request_id":   requestID,
+	})
+}
+
+// RemoveDependency handles removing the dependency of a task on another one
+// DELETE /api/v1/tasks/:id/dependencies/:depends_on_id
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, taskID, ok := parseTaskRequest(c, requestID)
+	if !ok {
+		return
+	}
+
+	dependsOnID, err := strconv.ParseUint(c.Param("depends_on_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid dependency task ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.taskUsecase.RemoveDependency(userID, taskID, uint(dependsOnID)); err != nil {
+		respondDependencyError(c, requestID, userID, taskID, err, "Failed to remove task dependency")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":    requestID,
+		"user_id":       userID,
+		"task_id":       taskID,
+		"depends_on_id": dependsOnID,
+	}).Info("Task dependency removed successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Task dependency removed successfully",
+		"request_id": requestID,
+	})
+}
+
+// parseTaskRequest extracts the user ID and the task ID URL parameter, responding with an error if either is invalid
+func parseTaskRequest(c *gin.Context, requestID string) (uint, uint, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid task ID",
+			"request_id": requestID,
+		})
+		return 0, 0, false
+	}
+
+	return userID, uint(taskID), true
+}
+
+// respondDependencyError maps task dependency errors to HTTP statuses
+func respondDependencyError(c *gin.Context, requestID string, userID, taskID uint, err error, message string) {
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"task_id":    taskID,
+		"error":      err.Error(),
+	}).Error(message)
+
+	statusCode := http.StatusInternalServerError
+	switch {
+	case containsKeyword(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+	case containsAccessDeniedError(err.Error()):
+		statusCode = http.StatusForbidden
+	case containsValidationError(err.Error()):
+		statusCode = http.StatusBadRequest
+	case containsKeyword(err.Error(), "cannot"):
+		statusCode = http.StatusConflict
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      message,
+		"details":    err.Error(),
+		"request_id": requestID,
+	})
+}
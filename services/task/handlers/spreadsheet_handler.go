@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/spreadsheet"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportTasks handles creating tasks from an uploaded CSV or XLSX file. The multipart form has
+// the file, an optional JSON mapping of column headers to task fields and a dry_run flag.
+// POST /api/v1/tasks/import
+func (h *TaskHandler) ImportTasks(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	req, err := readImportRequest(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid task import file")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	report, err := h.taskUsecase.ImportTasks(userID, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if containsValidationError(err.Error()) {
+			statusCode = http.StatusBadRequest
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to import tasks")
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to import tasks",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"dry_run":    report.DryRun,
+		"total":      report.Total,
+		"invalid":    report.Invalid,
+		"created":    report.Created,
+	}).Info("Tasks imported")
+
+	c.JSON(http.StatusOK, gin.H{
+		"report":     report,
+		"request_id": requestID,
+	})
+}
+
+// ExportTasks handles downloading tasks matching the list filters as a CSV or XLSX file,
+// the file is streamed while tasks are loaded
+// GET /api/v1/tasks/export?format=csv|xlsx
+func (h *TaskHandler) ExportTasks(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_filter_parameters"),
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Same filters and sorting as the task list, the export is never paginated
+	filter, ok := bindTaskFilter(c, requestID, userID, models.TaskListOptions)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("tasks-%s.%s", time.Now().UTC().Format("2006-01-02"), format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// Headers are sent with the first row, failures past that point can only cut the file short
+	exported := 0
+	err = writeTaskExport(c.Writer, format, func(write func(*models.TaskResponse) error) error {
+		return h.taskUsecase.ExportTasks(userID, filter, func(task *models.TaskResponse) error {
+			exported++
+			return write(task)
+		})
+	})
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"format":     format,
+			"exported":   exported,
+			"error":      err.Error(),
+		}).Error("Failed to export tasks")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"format":     format,
+		"exported":   exported,
+	}).Info("Tasks exported")
+}
+
+// writeTaskExport writes the export header and the tasks produced by export
+func writeTaskExport(w io.Writer, format spreadsheet.Format, export func(write func(*models.TaskResponse) error) error) error {
+	writer, err := spreadsheet.NewWriter(w, format, "Tasks")
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRow(models.ExportColumns); err != nil {
+		return err
+	}
+	if err := export(func(task *models.TaskResponse) error {
+		return writer.WriteRow(task.ExportRow())
+	}); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// readImportRequest reads the uploaded file and import options from the multipart form
+func readImportRequest(c *gin.Context) (*models.ImportTasksRequest, error) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("file is required")
+	}
+	if header.Size > models.MaxImportFileSize {
+		return nil, fmt.Errorf("file must not be larger than %d MB", models.MaxImportFileSize>>20)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// The header row comes on top of the task rows
+	rows, err := spreadsheet.Read(data, spreadsheet.DetectFormat(header.Filename, data), models.MaxImportRows+1)
+	if errors.Is(err, spreadsheet.ErrTooManyRows) {
+		return nil, fmt.Errorf("file must not contain more than %d tasks", models.MaxImportRows)
+	}
+	if errors.Is(err, spreadsheet.ErrTooLarge) {
+		return nil, fmt.Errorf("file is too large to import")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req := &models.ImportTasksRequest{Rows: rows}
+	if raw := strings.TrimSpace(c.PostForm("mapping")); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Mapping); err != nil {
+			return nil, fmt.Errorf("mapping must be a JSON object of column headers to task fields")
+		}
+	}
+	if raw := c.PostForm("dry_run"); raw != "" {
+		if req.DryRun, err = strconv.ParseBool(raw); err != nil {
+			return nil, fmt.Errorf("dry_run must be a boolean")
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/services/task/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// SprintHandler handles HTTP requests for sprints and sprint planning
+type SprintHandler struct {
+	sprintUsecase usecase.SprintUsecase
+}
+
+// NewSprintHandler creates a new sprint handler
+func NewSprintHandler(sprintUsecase usecase.SprintUsecase) *SprintHandler {
+	return &SprintHandler{
+		sprintUsecase: sprintUsecase,
+	}
+}
+
+// CreateSprint handles creating a sprint
+// POST /api/v1/sprints
+func (h *SprintHandler) CreateSprint(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.CreateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	sprint, err := h.sprintUsecase.CreateSprint(userID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to create sprint")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"sprint_id":  sprint.ID,
+	}).Info("Sprint created")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"sprint":     sprint,
+		"request_id": requestID,
+	})
+}
+
+// GetSprints handles listing sprints
+// GET /api/v1/sprints?status=active
+func (h *SprintHandler) GetSprints(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var filter models.SprintFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	sprints, total, err := h.sprintUsecase.ListSprints(&filter)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get sprints")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sprints":    sprints,
+		"total":      total,
+		"limit":      filter.Limit,
+		"offset":     filter.Offset,
+		"request_id": requestID,
+	})
+}
+
+// GetSprint handles getting a sprint
+// GET /api/v1/sprints/:id
+func (h *SprintHandler) GetSprint(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.sprintUsecase.GetSprint(sprintID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get sprint")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sprint":     sprint,
+		"request_id": requestID,
+	})
+}
+
+// UpdateSprint handles updating a sprint
+// PUT /api/v1/sprints/:id
+func (h *SprintHandler) UpdateSprint(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	sprint, err := h.sprintUsecase.UpdateSprint(sprintID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to update sprint")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sprint":     sprint,
+		"request_id": requestID,
+	})
+}
+
+// DeleteSprint handles deleting a sprint, its tasks go back to the backlog
+// DELETE /api/v1/sprints/:id
+func (h *SprintHandler) DeleteSprint(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	if err := h.sprintUsecase.DeleteSprint(sprintID); err != nil {
+		h.respondError(c, requestID, err, "Failed to delete sprint")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"sprint_id":  sprintID,
+	}).Info("Sprint deleted")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Sprint deleted successfully",
+		"request_id": requestID,
+	})
+}
+
+// AddSprintTasks handles adding tasks to a sprint
+// POST /api/v1/sprints/:id/tasks
+func (h *SprintHandler) AddSprintTasks(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.SprintTasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	planning, err := h.sprintUsecase.AddTasks(sprintID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to add tasks to sprint")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"planning":   planning,
+		"request_id": requestID,
+	})
+}
+
+// RemoveSprintTask handles taking a task out of a sprint
+// DELETE /api/v1/sprints/:id/tasks/:task_id
+func (h *SprintHandler) RemoveSprintTask(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("task_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid task ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.sprintUsecase.RemoveTask(sprintID, uint(taskID)); err != nil {
+		h.respondError(c, requestID, err, "Failed to remove task from sprint")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Task removed from sprint",
+		"request_id": requestID,
+	})
+}
+
+// GetSprintPlanning handles comparing sprint capacity with committed story points (managers and above)
+// GET /api/v1/sprints/:id/planning
+func (h *SprintHandler) GetSprintPlanning(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	planning, err := h.sprintUsecase.GetPlanning(sprintID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get sprint planning")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"planning":   planning,
+		"request_id": requestID,
+	})
+}
+
+// GetSprintSummary handles getting sprint progress statistics (managers and above)
+// GET /api/v1/sprints/:id/summary
+func (h *SprintHandler) GetSprintSummary(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	summary, err := h.sprintUsecase.GetSummary(sprintID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get sprint summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary":    summary,
+		"request_id": requestID,
+	})
+}
+
+// GetSprintBurndown handles getting remaining story points of a sprint by day (managers and above)
+// GET /api/v1/sprints/:id/burndown
+func (h *SprintHandler) GetSprintBurndown(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	burndown, err := h.sprintUsecase.GetBurndown(sprintID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get sprint burndown")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"burndown":   burndown,
+		"request_id": requestID,
+	})
+}
+
+// GetSprintVelocity handles getting tasks and story points completed per sprint by assignee
+// over the last completed sprints (managers and above)
+// GET /api/v1/sprints/velocity?sprints=6
+func (h *SprintHandler) GetSprintVelocity(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.SprintVelocityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	velocity, err := h.sprintUsecase.GetVelocity(&req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get sprint velocity")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"velocity":   velocity,
+		"request_id": requestID,
+	})
+}
+
+// respondError maps sprint usecase errors to HTTP statuses
+func (h *SprintHandler) respondError(c *gin.Context, requestID string, err error, message string) {
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"error":      err.Error(),
+	}).Error(message)
+
+	statusCode := http.StatusInternalServerError
+	errorMessage := message
+	switch {
+	case containsValidationError(err.Error()):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case containsKeyword(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = err.Error()
+	case containsKeyword(err.Error(), "cannot"):
+		statusCode = http.StatusConflict
+		errorMessage = err.Error()
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
+
+// parseSprintID parses the sprint ID URL parameter, responding with 400 if it is invalid
+func parseSprintID(c *gin.Context, requestID string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid sprint ID",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetSprintGantt handles getting sprint tasks on a timeline with dependencies and the critical path
+// (managers and above)
+// GET /api/v1/sprints/:id/gantt
+func (h *SprintHandler) GetSprintGantt(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	sprintID, ok := parseSprintID(c, requestID)
+	if !ok {
+		return
+	}
+
+	gantt, err := h.sprintUsecase.GetGantt(sprintID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get sprint Gantt chart")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"gantt":      gantt,
+		"request_id": requestID,
+	})
+}
@@ -3,11 +3,17 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"tachyon-messenger/services/task/models"
 	"tachyon-messenger/services/task/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -54,8 +60,8 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		}).Warn("Invalid request body for create task")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -97,6 +103,54 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	})
 }
 
+// CreateTaskForUser handles creating a task by another service on behalf of a user
+// POST /api/v1/internal/tasks
+func (h *TaskHandler) CreateTaskForUser(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.InternalCreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	task, err := h.taskUsecase.CreateTask(req.UserID, &req.Task)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    req.UserID,
+			"error":      err.Error(),
+		}).Error("Failed to create task for user")
+
+		statusCode := http.StatusInternalServerError
+		if containsValidationError(err.Error()) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to create task",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    req.UserID,
+		"task_id":    task.ID,
+	}).Info("Task created for user")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"task":       task,
+		"request_id": requestID,
+	})
+}
+
 // GetTask handles getting a single task by ID
 // GET /api/v1/tasks/:id
 func (h *TaskHandler) GetTask(c *gin.Context) {
@@ -158,6 +212,7 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
+	middleware.SetVersionETag(c, task.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"task":       task,
 		"request_id": requestID,
@@ -212,7 +267,18 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		}).Warn("Invalid request body for update task")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Version the changes are based on may also be passed in If-Match header
+	req.Version, err = middleware.GetExpectedVersion(c, req.Version)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid task version",
 			"details":    err.Error(),
 			"request_id": requestID,
 		})
@@ -228,6 +294,11 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 			"error":      err.Error(),
 		}).Error("Failed to update task")
 
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, uint(taskID), err)
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "task not found" {
 			statusCode = http.StatusNotFound
@@ -251,6 +322,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		"task_id":    taskID,
 	}).Info("Task updated successfully")
 
+	middleware.SetVersionETag(c, task.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Task updated successfully",
 		"task":       task,
@@ -306,8 +378,8 @@ func (h *TaskHandler) AssignTask(c *gin.Context) {
 		}).Warn("Invalid request body for assign task")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -323,6 +395,11 @@ func (h *TaskHandler) AssignTask(c *gin.Context) {
 			"error":      err.Error(),
 		}).Error("Failed to assign task")
 
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, uint(taskID), err)
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "task not found" {
 			statusCode = http.StatusNotFound
@@ -401,6 +478,11 @@ func (h *TaskHandler) UnassignTask(c *gin.Context) {
 			"error":      err.Error(),
 		}).Error("Failed to unassign task")
 
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, uint(taskID), err)
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "task not found" {
 			statusCode = http.StatusNotFound
@@ -449,32 +531,13 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 		return
 	}
 
-	// Parse filter parameters
-	var filter models.TaskFilterRequest
-	if err := c.ShouldBindQuery(&filter); err != nil {
-		logger.WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"user_id":    userID,
-			"error":      err.Error(),
-		}).Warn("Invalid filter parameters")
-
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid filter parameters",
-			"details":    err.Error(),
-			"request_id": requestID,
-		})
+	// Parse filter, sorting and pagination parameters
+	filter, ok := bindTaskFilter(c, requestID, userID, models.TaskListOptions)
+	if !ok {
 		return
 	}
 
-	// Set default pagination if not provided
-	if filter.Limit <= 0 {
-		filter.Limit = 20
-	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
-	}
-
-	tasks, total, err := h.taskUsecase.GetUserTasks(userID, &filter)
+	tasks, total, err := h.taskUsecase.GetUserTasks(userID, filter)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -491,11 +554,12 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"tasks":      tasks,
-		"total":      total,
-		"limit":      filter.Limit,
-		"offset":     filter.Offset,
-		"request_id": requestID,
+		"tasks":       tasks,
+		"total":       total,
+		"limit":       filter.Page.Limit,
+		"offset":      filter.Page.Offset,
+		"next_cursor": filter.Page.NextCursor(tasks),
+		"request_id":  requestID,
 	})
 }
 
@@ -547,8 +611,8 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 		}).Warn("Invalid request body for update task status")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -564,6 +628,11 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 			"error":      err.Error(),
 		}).Error("Failed to update task status")
 
+		if containsVersionConflictError(err.Error()) {
+			h.respondVersionConflict(c, requestID, userID, uint(taskID), err)
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 		if err.Error() == "task not found" {
 			statusCode = http.StatusNotFound
@@ -633,7 +702,7 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	err = h.taskUsecase.DeleteTask(userID, uint(taskID))
+	receipt, err := h.taskUsecase.DeleteTask(userID, uint(taskID))
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -663,8 +732,137 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		"task_id":    taskID,
 	}).Info("Task deleted successfully")
 
+	// The deletion can be undone until the receipt expires
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Task deleted successfully",
+		"undo":       receipt,
+		"request_id": requestID,
+	})
+}
+
+// GetDeletedTasks handles listing tasks in trash
+// GET /api/v1/tasks/trash
+func (h *TaskHandler) GetDeletedTasks(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse filter, sorting and pagination parameters
+	filter, ok := bindTaskFilter(c, requestID, userID, models.TaskTrashListOptions)
+	if !ok {
+		return
+	}
+
+	tasks, total, err := h.taskUsecase.GetDeletedTasks(userID, filter)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to get deleted tasks")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get deleted tasks",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks":       tasks,
+		"total":       total,
+		"limit":       filter.Page.Limit,
+		"offset":      filter.Page.Offset,
+		"next_cursor": filter.Page.NextCursor(tasks),
+		"request_id":  requestID,
+	})
+}
+
+// RestoreTask handles restoring a task from trash
+// POST /api/v1/tasks/:id/restore
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get user ID from JWT token
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Unauthorized",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	// Parse task ID from URL parameter
+	idStr := c.Param("id")
+	taskID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"task_id":    idStr,
+			"error":      err.Error(),
+		}).Warn("Invalid task ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid task ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	task, err := h.taskUsecase.RestoreTask(userID, uint(taskID))
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"task_id":    taskID,
+			"error":      err.Error(),
+		}).Error("Failed to restore task")
+
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "task not found" {
+			statusCode = http.StatusNotFound
+		} else if containsAccessDeniedError(err.Error()) {
+			statusCode = http.StatusForbidden
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to restore task",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"task_id":    taskID,
+	}).Info("Task restored successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Task restored successfully",
+		"task":       task,
 		"request_id": requestID,
 	})
 }
@@ -711,6 +909,229 @@ func (h *TaskHandler) GetTaskStats(c *gin.Context) {
 	})
 }
 
+// SuggestAssignees handles suggesting assignees of a new task by skills and workload
+// GET /api/v1/tasks/assignee-suggestions?skills=go,postgres&due=2026-11-01
+func (h *TaskHandler) SuggestAssignees(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.AssigneeSuggestionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	for _, value := range c.QueryArray("skills") {
+		for _, skill := range strings.Split(value, ",") {
+			if skill = strings.TrimSpace(skill); skill != "" {
+				req.Skills = append(req.Skills, skill)
+			}
+		}
+	}
+
+	if value := c.Query("due"); value != "" {
+		due, err := parseSuggestionDue(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid due date, use YYYY-MM-DD or RFC 3339",
+				"request_id": requestID,
+			})
+			return
+		}
+		req.Due = &due
+	}
+
+	suggestions, err := h.taskUsecase.SuggestAssignees(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"skills":     req.Skills,
+			"error":      err.Error(),
+		}).Error("Failed to suggest assignees")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to suggest assignees"
+		switch {
+		case containsValidationError(err.Error()):
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		case containsKeyword(err.Error(), "not available"):
+			statusCode = http.StatusServiceUnavailable
+			errorMessage = "Assignee suggestions are not available"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suggestions": suggestions,
+		"request_id":  requestID,
+	})
+}
+
+// parseSuggestionDue parses the due date of assignee suggestions, a date means the end of that day
+func parseSuggestionDue(value string) (time.Time, error) {
+	if due, err := time.Parse(time.RFC3339, value); err == nil {
+		return due, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+// GetWorkloadReport handles reporting open task load of a department or of users
+// GET /api/v1/tasks/workload?department_id=1&upcoming_days=7
+func (h *TaskHandler) GetWorkloadReport(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.WorkloadReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	report, err := h.taskUsecase.GetWorkloadReport(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":    requestID,
+			"department_id": req.DepartmentID,
+			"error":         err.Error(),
+		}).Error("Failed to get workload report")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get workload report"
+		switch {
+		case containsValidationError(err.Error()):
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		case containsKeyword(err.Error(), "not available"):
+			statusCode = http.StatusServiceUnavailable
+			errorMessage = "Department workload is not available"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report":     report,
+		"request_id": requestID,
+	})
+}
+
+// GetDigestStats handles counting tasks of a user for the daily digest of the notification service
+// POST /api/v1/internal/users/digest-stats
+func (h *TaskHandler) GetDigestStats(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req sharedmodels.DigestStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	stats, err := h.taskUsecase.GetDigestStats(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    req.UserID,
+			"error":      err.Error(),
+		}).Error("Failed to get digest stats")
+
+		statusCode := http.StatusInternalServerError
+		if containsValidationError(err.Error()) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to get digest stats",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stats":      stats,
+		"request_id": requestID,
+	})
+}
+
+// MergeUsers handles moving task data of a duplicate account to the primary account
+// POST /api/v1/internal/users/merge
+func (h *TaskHandler) MergeUsers(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req sharedmodels.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for merge users")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.taskUsecase.MergeUsers(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":        requestID,
+			"primary_user_id":   req.PrimaryUserID,
+			"duplicate_user_id": req.DuplicateUserID,
+			"error":             err.Error(),
+		}).Error("Failed to merge users")
+
+		statusCode := http.StatusInternalServerError
+		if containsValidationError(err.Error()) {
+			statusCode = http.StatusBadRequest
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      "Failed to merge users",
+			"details":    err.Error(),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":        requestID,
+		"primary_user_id":   req.PrimaryUserID,
+		"duplicate_user_id": req.DuplicateUserID,
+		"moved":             result.Moved,
+	}).Info("Users merged in task service")
+
+	c.JSON(http.StatusOK, gin.H{
+		"result":     result,
+		"request_id": requestID,
+	})
+}
+
 // Helper functions
 
 // containsValidationError checks if the error message contains validation-related keywords
@@ -750,6 +1171,28 @@ func containsAccessDeniedError(errMsg string) bool {
 	return false
 }
 
+// containsVersionConflictError checks if the error message reports a stale task version
+func containsVersionConflictError(errMsg string) bool {
+	return containsKeyword(errMsg, "version conflict")
+}
+
+// respondVersionConflict responds with 409 and the current task so the client can reapply its changes
+func (h *TaskHandler) respondVersionConflict(c *gin.Context, requestID string, userID, taskID uint, err error) {
+	response := gin.H{
+		"error":      "Task was modified by another request",
+		"details":    err.Error(),
+		"request_id": requestID,
+	}
+
+	current, getErr := h.taskUsecase.GetTaskByID(userID, taskID)
+	if getErr == nil {
+		middleware.SetVersionETag(c, current.Version)
+		response["task"] = current
+	}
+
+	c.JSON(http.StatusConflict, response)
+}
+
 // containsKeyword checks if a string contains a keyword (case-insensitive)
 func containsKeyword(text, keyword string) bool {
 	return len(text) >= len(keyword) &&
@@ -767,3 +1210,29 @@ func containsSubstring(text, substring string) bool {
 	}
 	return false
 }
+
+// bindTaskFilter binds task filter parameters together with shared pagination,
+// sorting and filtering parameters, responding with 400 if they are invalid
+func bindTaskFilter(c *gin.Context, requestID string, userID uint, opts *query.Options) (*models.TaskFilterRequest, bool) {
+	var filter models.TaskFilterRequest
+	err := c.ShouldBindQuery(&filter)
+	if err == nil {
+		filter.Page, err = query.Parse(c.Request.URL.Query(), opts)
+	}
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Warn("Invalid filter parameters")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_filter_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+
+	return &filter, true
+}
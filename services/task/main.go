@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -15,8 +16,13 @@ import (
 	"tachyon-messenger/services/task/usecase"
 	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/jobs"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/orgsettings"
+	"tachyon-messenger/shared/refs"
+	"tachyon-messenger/shared/undo"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -36,6 +42,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Resolve other services through the registry, endpoints are refreshed in the background
+	registry, err := config.NewRegistryFromEnv(nil)
+	if err != nil {
+		log.Fatalf("Failed to set up service registry: %v", err)
+	}
+	http.DefaultTransport = registry.Transport(http.DefaultTransport)
+	registryCtx, stopRegistry := context.WithCancel(context.Background())
+	defer stopRegistry()
+	go registry.Run(registryCtx)
+
 	log.Info("Starting Task service...")
 
 	// Connect to database
@@ -47,7 +63,9 @@ func main() {
 	defer db.Close()
 
 	// Run database migrations
-	if err := db.Migrate(&models.Task{}, &models.TaskComment{}); err != nil {
+	migrationModels := append(models.Models(), jobs.Models()...)
+	migrationModels = append(migrationModels, undo.Models()...)
+	if err := db.Migrate(migrationModels...); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -58,21 +76,54 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Validate request DTOs with shared rules
+	validation.Install()
+
 	// Initialize dependencies
 	taskRepo := repository.NewTaskRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
+	sprintRepo := repository.NewSprintRepository(db)
+
+	// Organization settings from the user service
+	orgSettings := orgsettings.NewClient(registry.BaseURL(config.UserService), 0)
+
+	// Validation of assignee IDs against the user service
+	userRefs := refs.NewUserValidatorFromEnv(registry.BaseURL(config.UserService))
+
+	// Users by skills from the user service, for assignee suggestions
+	skillDirectory := usecase.NewHTTPSkillDirectory(registry.BaseURL(config.UserService))
+
+	// Department members from the user service, for workload reports
+	departmentDirectory := usecase.NewHTTPDepartmentDirectory(registry.BaseURL(config.UserService))
 
 	// Create JWT config
 	jwtConfig := middleware.DefaultJWTConfig(cfg.JWT.Secret)
 
+	// Network restrictions of admin endpoints
+	adminAccess, err := middleware.LoadAdminAccessConfig()
+	if err != nil {
+		log.Fatalf("Failed to load admin access config: %v", err)
+	}
+
+	// Task deletion is staged for a short window in which it can be undone
+	undoManager := undo.NewManager("task", db, undo.WindowFromEnv())
+
 	// Initialize usecases
-	taskUsecase := usecase.NewTaskUsecase(taskRepo, commentRepo)
+	taskUsecase := usecase.NewTaskUsecase(taskRepo, commentRepo, userRefs, skillDirectory, departmentDirectory, undoManager, getWorkloadThreshold())
+
+	sprintUsecase := usecase.NewSprintUsecase(sprintRepo)
+
+	// Schedule background jobs
+	scheduler := jobs.NewScheduler("task", db, nil)
+	registerJobs(scheduler, taskUsecase, undoManager, orgSettings, log)
+	scheduler.Start()
 
 	// Initialize handlers
 	taskHandler := handlers.NewTaskHandler(taskUsecase)
+	sprintHandler := handlers.NewSprintHandler(sprintUsecase)
 
 	// Setup routes
-	r := setupRoutes(taskHandler, jwtConfig)
+	r := setupRoutes(taskHandler, sprintHandler, undoManager, scheduler, jwtConfig, adminAccess)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -109,12 +160,84 @@ func main() {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Wait for running background jobs
+	scheduler.Stop()
+
 	log.Info("Task service stopped")
 }
 
+// registerJobs schedules background jobs of the task service
+func registerJobs(scheduler *jobs.Scheduler, taskUsecase usecase.TaskUsecase, undoManager *undo.Manager, orgSettings *orgsettings.Client, log *logger.Logger) {
+	// Permanently delete tasks that stayed in trash longer than retention
+	err := scheduler.Register(jobs.Job{
+		Name:     "purge_deleted_tasks",
+		Schedule: trashPurgeSchedule,
+		Run: func(ctx context.Context) error {
+			retention := getTrashRetention(orgSettings)
+			jobs.Report(ctx, "retention_days", int(retention.Hours()/24))
+			purged, err := taskUsecase.PurgeDeletedTasks(retention)
+			if err != nil {
+				return err
+			}
+			jobs.Report(ctx, "purged_count", purged)
+			if purged > 0 {
+				log.WithField("purged_count", purged).Info("Purged deleted tasks")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to register background jobs: %v", err)
+	}
+
+	// Commit staged actions whose undo window elapsed
+	err = scheduler.Register(jobs.Job{
+		Name:     "commit_undo_actions",
+		Schedule: undoCommitSchedule,
+		Run: func(ctx context.Context) error {
+			committed, err := undoManager.CommitDue(time.Now())
+			jobs.Report(ctx, "committed_count", committed)
+			return err
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to register background jobs: %v", err)
+	}
+}
+
+// undoCommitSchedule is how often staged actions are checked for an elapsed undo window
+const undoCommitSchedule = "@every 5s"
+
+// getWorkloadThreshold returns the load above which assignees are reported as overloaded from environment or default
+func getWorkloadThreshold() models.WorkloadThreshold {
+	threshold := models.DefaultWorkloadThreshold
+	if open, err := strconv.ParseInt(os.Getenv("TASK_WORKLOAD_MAX_OPEN"), 10, 64); err == nil && open >= 0 {
+		threshold.OpenTasks = open
+	}
+	if hours, err := strconv.ParseFloat(os.Getenv("TASK_WORKLOAD_MAX_HOURS"), 64); err == nil && hours >= 0 {
+		threshold.EstimateHours = hours
+	}
+	return threshold
+}
+
+// trashPurgeSchedule is how often expired records are purged from trash
+const trashPurgeSchedule = "@hourly"
+
+// getTrashRetention returns how long deleted tasks stay in trash from environment or organization settings
+func getTrashRetention(orgSettings *orgsettings.Client) time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return orgSettings.Get().Retention.TrashRetention()
+}
+
 func setupRoutes(
 	taskHandler *handlers.TaskHandler,
+	sprintHandler *handlers.SprintHandler,
+	undoManager *undo.Manager,
+	scheduler *jobs.Scheduler,
 	jwtConfig *middleware.JWTConfig,
+	adminAccess *middleware.AdminAccessConfig,
 ) *gin.Engine {
 	r := gin.New()
 
@@ -122,12 +245,18 @@ func setupRoutes(
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Use(requestid.New())
+	r.Use(middleware.CorrelationMiddleware())
+	r.Use(middleware.BodyLimitMiddleware(middleware.DefaultBodyLimitConfig("/api/v1/tasks/import")))
+
+	// Concurrency limits of route groups, requests over them are shed with 503
+	limits := middleware.NewConcurrencyLimits("task-service")
 
 	// CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, If-Match")
+		c.Header("Access-Control-Expose-Headers", "ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -140,18 +269,28 @@ func setupRoutes(
 	// Health endpoint (no auth required)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"service":   "task-service",
-			"timestamp": time.Now().UTC(),
-			"version":   "1.0.0",
+			"status":      "healthy",
+			"service":     "task-service",
+			"timestamp":   time.Now().UTC(),
+			"version":     "1.0.0",
+			"concurrency": limits.Stats(),
 		})
 	})
 
+	// Load of route groups for monitoring
+	r.GET("/metrics", limits.MetricsHandler())
+
 	// API routes
 	api := r.Group("/api/v1")
 
+	// Internal endpoints (for service-to-service communication)
+	api.POST("/internal/users/merge", limits.Group("internal"), taskHandler.MergeUsers)
+	api.POST("/internal/tasks", limits.Group("internal"), taskHandler.CreateTaskForUser)
+	api.POST("/internal/users/digest-stats", limits.Group("internal"), taskHandler.GetDigestStats)
+
 	// Protected routes (require JWT)
 	protected := api.Group("")
+	protected.Use(limits.Group("api"))
 	protected.Use(middleware.JWTMiddleware(jwtConfig))
 	{
 		// Task endpoints
@@ -162,13 +301,35 @@ func setupRoutes(
 		protected.DELETE("/tasks/:id", taskHandler.DeleteTask)
 		protected.PATCH("/tasks/:id/status", taskHandler.UpdateTaskStatus)
 
+		// Task trash
+		protected.GET("/tasks/trash", taskHandler.GetDeletedTasks)
+		protected.POST("/tasks/:id/restore", taskHandler.RestoreTask)
+
+		// Undo of staged task deletions
+		undo.RegisterRoutes(protected, undoManager)
+
 		// Task statistics
 		protected.GET("/tasks/stats", taskHandler.GetTaskStats)
 
+		// Spreadsheet import with dry run and export of the filtered list
+		protected.POST("/tasks/import", taskHandler.ImportTasks)
+		protected.GET("/tasks/export", taskHandler.ExportTasks)
+
+		// Assignees of a new task by skills and workload
+		protected.GET("/tasks/assignee-suggestions", taskHandler.SuggestAssignees)
+
+		// Open task load of a department or of users (managers and above)
+		protected.GET("/tasks/workload", middleware.RequireManagerOrAbove(), taskHandler.GetWorkloadReport)
+
 		// Task assignments
 		protected.POST("/tasks/:id/assign", taskHandler.AssignTask)
 		protected.DELETE("/tasks/:id/assign", taskHandler.UnassignTask)
 
+		// Task dependencies, a task starts after the tasks it depends on are finished
+		protected.GET("/tasks/:id/dependencies", taskHandler.GetDependencies)
+		protected.POST("/tasks/:id/dependencies", taskHandler.AddDependency)
+		protected.DELETE("/tasks/:id/dependencies/:depends_on_id", taskHandler.RemoveDependency)
+
 		// Task comments
 		protected.POST("/tasks/:id/comments", taskHandler.AddComment)
 		protected.GET("/tasks/:id/comments", taskHandler.GetTaskComments)
@@ -176,7 +337,36 @@ func setupRoutes(
 		// Comment management
 		protected.PUT("/comments/:id", taskHandler.UpdateComment)
 		protected.DELETE("/comments/:id", taskHandler.DeleteComment)
+		protected.POST("/comments/:id/reactions", taskHandler.AddCommentReaction)
+		protected.DELETE("/comments/:id/reactions", taskHandler.RemoveCommentReaction)
+
+		// Sprints, changes are for managers and above
+		sprints := protected.Group("/sprints")
+		{
+			sprints.GET("", sprintHandler.GetSprints)
+			sprints.GET("/:id", sprintHandler.GetSprint)
+
+			// Planning, reports, velocity and the Gantt chart cover every task of the sprint,
+			// including tasks of other users
+			sprints.GET("/:id/planning", middleware.RequireManagerOrAbove(), sprintHandler.GetSprintPlanning)
+			sprints.GET("/:id/summary", middleware.RequireManagerOrAbove(), sprintHandler.GetSprintSummary)
+			sprints.GET("/:id/burndown", middleware.RequireManagerOrAbove(), sprintHandler.GetSprintBurndown)
+			sprints.GET("/:id/gantt", middleware.RequireManagerOrAbove(), sprintHandler.GetSprintGantt)
+			sprints.GET("/velocity", middleware.RequireManagerOrAbove(), sprintHandler.GetSprintVelocity)
+
+			sprints.POST("", middleware.RequireManagerOrAbove(), sprintHandler.CreateSprint)
+			sprints.PUT("/:id", middleware.RequireManagerOrAbove(), sprintHandler.UpdateSprint)
+			sprints.DELETE("/:id", middleware.RequireManagerOrAbove(), sprintHandler.DeleteSprint)
+			sprints.POST("/:id/tasks", middleware.RequireManagerOrAbove(), sprintHandler.AddSprintTasks)
+			sprints.DELETE("/:id/tasks/:task_id", middleware.RequireManagerOrAbove(), sprintHandler.RemoveSprintTask)
+		}
 	}
 
+	// Background job management (admin only)
+	admin := protected.Group("/admin")
+	admin.Use(middleware.AdminAccessMiddleware(adminAccess))
+	admin.Use(middleware.RequireAdminRole())
+	jobs.RegisterRoutes(admin, scheduler)
+
 	return r
 }
@@ -15,11 +15,13 @@ type TaskComment struct {
 	UserID   uint   `gorm:"not null;index" json:"user_id" validate:"required"`
 	Content  string `gorm:"not null;type:text" json:"content" validate:"required,min=1,max=1000"`
 	ParentID *uint  `gorm:"index" json:"parent_id,omitempty" validate:"omitempty,min=1"`
+	models.CommentMeta
 
 	// Associations
-	Task    *Task         `gorm:"foreignKey:TaskID" json:"task,omitempty"`
-	Parent  *TaskComment  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
-	Replies []TaskComment `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"replies,omitempty"`
+	Task      *Task                 `gorm:"foreignKey:TaskID" json:"task,omitempty"`
+	Parent    *TaskComment          `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Replies   []TaskComment         `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE" json:"replies,omitempty"`
+	Reactions []TaskCommentReaction `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE" json:"reactions,omitempty"`
 }
 
 // TableName returns the table name for TaskComment model
@@ -27,6 +29,16 @@ func (TaskComment) TableName() string {
 	return "task_comments"
 }
 
+// TaskCommentReaction represents an emoji reaction on a task comment
+type TaskCommentReaction struct {
+	models.CommentReaction
+}
+
+// TableName returns the table name for TaskCommentReaction model
+func (TaskCommentReaction) TableName() string {
+	return "task_comment_reactions"
+}
+
 // BeforeCreate hook is called before creating a task comment
 func (tc *TaskComment) BeforeCreate(tx *gorm.DB) error {
 	// Validate that parent comment belongs to the same task if ParentID is set
@@ -43,46 +55,66 @@ func (tc *TaskComment) BeforeCreate(tx *gorm.DB) error {
 
 // CreateTaskCommentRequest represents request for creating a task comment
 type CreateTaskCommentRequest struct {
-	Content  string `json:"content" binding:"required,min=1,max=1000" validate:"required,min=1,max=1000"`
+	Content  string `json:"content" binding:"required,min=1,max=1000" validate:"required,notblank,max=1000"`
 	ParentID *uint  `json:"parent_id,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
 }
 
 // UpdateTaskCommentRequest represents request for updating a task comment
-type UpdateTaskCommentRequest struct {
-	Content string `json:"content" binding:"required,min=1,max=1000" validate:"required,min=1,max=1000"`
-}
+type UpdateTaskCommentRequest = models.UpdateCommentRequest
 
 // Response Models for Comments
 
 // TaskCommentResponse represents a task comment in API responses
 type TaskCommentResponse struct {
-	ID        uint                   `json:"id"`
-	TaskID    uint                   `json:"task_id"`
-	UserID    uint                   `json:"user_id"`
-	Content   string                 `json:"content"`
-	ParentID  *uint                  `json:"parent_id,omitempty"`
-	Replies   []*TaskCommentResponse `json:"replies,omitempty"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
+	ID        uint                     `json:"id"`
+	TaskID    uint                     `json:"task_id"`
+	UserID    uint                     `json:"user_id"`
+	Content   string                   `json:"content"`
+	ParentID  *uint                    `json:"parent_id,omitempty"`
+	Depth     int                      `json:"depth"`
+	IsEdited  bool                     `json:"is_edited"`
+	EditedAt  *time.Time               `json:"edited_at,omitempty"`
+	Reactions []models.ReactionSummary `json:"reactions,omitempty"`
+	Replies   []*TaskCommentResponse   `json:"replies,omitempty"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
 }
 
 // ToResponse converts TaskComment model to TaskCommentResponse
 func (tc *TaskComment) ToResponse() *TaskCommentResponse {
+	return tc.ToResponseForUser(0)
+}
+
+// ToResponseForUser converts TaskComment model to TaskCommentResponse,
+// marking reactions left by the given user
+func (tc *TaskComment) ToResponseForUser(userID uint) *TaskCommentResponse {
 	response := &TaskCommentResponse{
 		ID:        tc.ID,
 		TaskID:    tc.TaskID,
 		UserID:    tc.UserID,
 		Content:   tc.Content,
 		ParentID:  tc.ParentID,
+		Depth:     tc.Depth,
+		IsEdited:  tc.IsEdited,
+		EditedAt:  tc.EditedAt,
 		CreatedAt: tc.CreatedAt,
 		UpdatedAt: tc.UpdatedAt,
 	}
 
+	// Aggregate reactions if loaded
+	if len(tc.Reactions) > 0 {
+		reactions := make([]models.CommentReaction, len(tc.Reactions))
+		for i, reaction := range tc.Reactions {
+			reactions[i] = reaction.CommentReaction
+		}
+		response.Reactions = models.AggregateReactions(reactions, userID)
+	}
+
 	// Convert replies if they exist
 	if len(tc.Replies) > 0 {
 		response.Replies = make([]*TaskCommentResponse, len(tc.Replies))
 		for i, reply := range tc.Replies {
-			response.Replies[i] = reply.ToResponse()
+			response.Replies[i] = reply.ToResponseForUser(userID)
 		}
 	}
 
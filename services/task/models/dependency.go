@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// MaxTaskDependencies limits tasks one task can depend on
+const MaxTaskDependencies = 50
+
+// TaskDependency means the task can start only after the task it depends on is finished
+type TaskDependency struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TaskID      uint      `gorm:"not null;uniqueIndex:idx_task_dependency" json:"task_id"`
+	DependsOnID uint      `gorm:"not null;uniqueIndex:idx_task_dependency;index" json:"depends_on_id"`
+	CreatedBy   uint      `gorm:"not null" json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for TaskDependency model
+func (TaskDependency) TableName() string {
+	return "task_dependencies"
+}
+
+// AddTaskDependencyRequest represents request for making a task depend on another one
+type AddTaskDependencyRequest struct {
+	DependsOnID uint `json:"depends_on_id" binding:"required,min=1" validate:"required,min=1"`
+}
+
+// TaskDependenciesResponse represents tasks a task depends on and tasks blocked by it
+type TaskDependenciesResponse struct {
+	TaskID    uint   `json:"task_id"`
+	DependsOn []uint `json:"depends_on"`
+	Blocks    []uint `json:"blocks"`
+}
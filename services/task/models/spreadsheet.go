@@ -0,0 +1,133 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// MaxImportRows is the most tasks a single spreadsheet import may contain
+const MaxImportRows = 1000
+
+// MaxImportFileSize is the largest accepted import file in bytes
+const MaxImportFileSize = 5 << 20
+
+// Task fields spreadsheet columns can be mapped to
+const (
+	ImportFieldTitle         = "title"
+	ImportFieldDescription   = "description"
+	ImportFieldStatus        = "status"
+	ImportFieldPriority      = "priority"
+	ImportFieldAssignedTo    = "assigned_to"
+	ImportFieldStartDate     = "start_date"
+	ImportFieldDueDate       = "due_date"
+	ImportFieldEstimateHours = "estimate_hours"
+	ImportFieldStoryPoints   = "story_points"
+)
+
+// ImportFields lists task fields importable from spreadsheets in export column order
+var ImportFields = []string{
+	ImportFieldTitle,
+	ImportFieldDescription,
+	ImportFieldStatus,
+	ImportFieldPriority,
+	ImportFieldAssignedTo,
+	ImportFieldStartDate,
+	ImportFieldDueDate,
+	ImportFieldEstimateHours,
+	ImportFieldStoryPoints,
+}
+
+// ImportTasksRequest represents a parsed spreadsheet to import tasks from
+type ImportTasksRequest struct {
+	Rows    [][]string        // Первая строка - заголовки колонок
+	Mapping map[string]string // Заголовок колонки -> поле задачи, пустое поле пропускает колонку
+	DryRun  bool
+}
+
+// TaskImportRow reports the outcome of a spreadsheet row
+type TaskImportRow struct {
+	Row    int      `json:"row"` // Номер строки в файле, заголовок - строка 1
+	Title  string   `json:"title,omitempty"`
+	TaskID *uint    `json:"task_id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// TaskImportReport summarizes a spreadsheet import or its dry run
+type TaskImportReport struct {
+	DryRun         bool              `json:"dry_run"`
+	Mapping        map[string]string `json:"mapping"` // Примененное сопоставление колонок
+	IgnoredColumns []string          `json:"ignored_columns,omitempty"`
+	Total          int               `json:"total"`
+	Valid          int               `json:"valid"`
+	Invalid        int               `json:"invalid"`
+	Created        int               `json:"created"`
+	Rows           []*TaskImportRow  `json:"rows"`
+}
+
+// ExportColumns are header cells of task exports, importable columns keep their field names
+// so an export can be imported back
+var ExportColumns = append(append([]string{"id"}, ImportFields...), "sprint_id", "created_by", "created_at", "completed_at")
+
+// ExportRow returns cells of the task in ExportColumns order
+func (t *TaskResponse) ExportRow() []string {
+	return []string{
+		strconv.FormatUint(uint64(t.ID), 10),
+		t.Title,
+		t.Description,
+		string(t.Status),
+		string(t.Priority),
+		formatExportID(t.AssignedTo),
+		formatExportDate(t.StartDate),
+		formatExportDate(t.DueDate),
+		formatExportFloat(t.EstimateHours),
+		formatExportInt(t.StoryPoints),
+		formatExportID(t.SprintID),
+		strconv.FormatUint(uint64(t.CreatedBy), 10),
+		t.CreatedAt.UTC().Format(time.RFC3339),
+		formatExportTime(t.CompletedAt),
+	}
+}
+
+// formatExportID formats an optional ID, empty if not set
+func formatExportID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// formatExportInt formats an optional number, empty if not set
+func formatExportInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// formatExportFloat formats an optional decimal, empty if not set
+func formatExportFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// formatExportDate formats a planning date as a plain date when it has no time of day
+func formatExportDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	utc := date.UTC()
+	if utc.Equal(utc.Truncate(24 * time.Hour)) {
+		return utc.Format("2006-01-02")
+	}
+	return utc.Format(time.RFC3339)
+}
+
+// formatExportTime formats an optional timestamp, empty if not set
+func formatExportTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
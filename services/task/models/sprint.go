@@ -0,0 +1,251 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// SprintStatus represents the status of a sprint
+type SprintStatus string
+
+const (
+	SprintStatusPlanned   SprintStatus = "planned"
+	SprintStatusActive    SprintStatus = "active"
+	SprintStatusCompleted SprintStatus = "completed"
+)
+
+// IsValid checks if sprint status is valid
+func (s SprintStatus) IsValid() bool {
+	switch s {
+	case SprintStatusPlanned, SprintStatusActive, SprintStatusCompleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxSprintDays is the longest allowed sprint
+const MaxSprintDays = 90
+
+// MaxSprintTasks limits tasks added to a sprint in one request
+const MaxSprintTasks = 100
+
+// MaxVelocitySprints limits completed sprints the velocity is reported over
+const MaxVelocitySprints = 20
+
+// Sprint is a time box tasks are planned into
+type Sprint struct {
+	models.BaseModel
+	Name           string       `gorm:"not null;size:100" json:"name"`
+	Goal           string       `gorm:"type:text" json:"goal,omitempty"`
+	Status         SprintStatus `gorm:"not null;default:'planned';size:20;index" json:"status"`
+	StartDate      time.Time    `gorm:"not null" json:"start_date"`
+	EndDate        time.Time    `gorm:"not null" json:"end_date"`
+	CapacityPoints int          `gorm:"not null;default:0" json:"capacity_points"` // Сколько story points команда может взять в спринт
+	CreatedBy      uint         `gorm:"not null;index" json:"created_by"`
+}
+
+// TableName returns the table name for Sprint model
+func (Sprint) TableName() string {
+	return "sprints"
+}
+
+// FirstDay returns the start of the first day of the sprint in UTC
+func (s *Sprint) FirstDay() time.Time {
+	return dateOf(s.StartDate)
+}
+
+// Days returns the number of calendar days of the sprint, counting both start and end
+func (s *Sprint) Days() int {
+	return int(dateOf(s.EndDate).Sub(s.FirstDay()).Hours()/24) + 1
+}
+
+// DaysElapsed returns how many days of the sprint have started by now, the current day included
+func (s *Sprint) DaysElapsed(now time.Time) int {
+	if now.Before(s.FirstDay()) {
+		return 0
+	}
+	elapsed := int(dateOf(now).Sub(s.FirstDay()).Hours()/24) + 1
+	if days := s.Days(); elapsed > days {
+		return days
+	}
+	return elapsed
+}
+
+// dateOf truncates t to the start of its day in UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CreateSprintRequest represents request for creating a sprint
+type CreateSprintRequest struct {
+	Name           string    `json:"name" binding:"required,min=1,max=100" validate:"required,notblank,max=100"`
+	Goal           string    `json:"goal,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	StartDate      time.Time `json:"start_date" binding:"required"`
+	EndDate        time.Time `json:"end_date" binding:"required"`
+	CapacityPoints int       `json:"capacity_points" binding:"omitempty,min=0,max=10000" validate:"omitempty,min=0,max=10000"`
+}
+
+// UpdateSprintRequest represents request for updating a sprint
+type UpdateSprintRequest struct {
+	Name           *string       `json:"name,omitempty" binding:"omitempty,min=1,max=100" validate:"omitempty,notblank,max=100"`
+	Goal           *string       `json:"goal,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	Status         *SprintStatus `json:"status,omitempty" binding:"omitempty,oneof=planned active completed" validate:"omitempty,enum"`
+	StartDate      *time.Time    `json:"start_date,omitempty"`
+	EndDate        *time.Time    `json:"end_date,omitempty"`
+	CapacityPoints *int          `json:"capacity_points,omitempty" binding:"omitempty,min=0,max=10000" validate:"omitempty,min=0,max=10000"`
+}
+
+// SprintFilterRequest represents filtering parameters for sprints
+type SprintFilterRequest struct {
+	Status *SprintStatus `form:"status" binding:"omitempty,oneof=planned active completed"`
+	Limit  int           `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int           `form:"offset" binding:"omitempty,min=0"`
+}
+
+// SprintVelocityRequest represents parameters of the velocity report
+type SprintVelocityRequest struct {
+	Sprints int `form:"sprints" binding:"omitempty,min=1,max=20"` // Сколько последних завершённых спринтов учитывать
+}
+
+// SprintTasksRequest represents request for adding tasks to a sprint
+type SprintTasksRequest struct {
+	TaskIDs []uint `json:"task_ids" binding:"required,min=1,max=100,dive,min=1"`
+}
+
+// SprintTaskStats aggregates tasks of a sprint with one assignee and status
+type SprintTaskStats struct {
+	AssignedTo  *uint      `json:"assigned_to"`
+	Status      TaskStatus `json:"status"`
+	Tasks       int64      `json:"tasks"`
+	Points      int64      `json:"points"`
+	Unestimated int64      `json:"unestimated"` // Задачи без story points
+}
+
+// SprintCompletion is a completed task of a sprint for the burndown
+type SprintCompletion struct {
+	CompletedAt time.Time `json:"completed_at"`
+	Points      int64     `json:"points"`
+}
+
+// SprintAssigneeCompletion aggregates done tasks of a sprint with one assignee
+type SprintAssigneeCompletion struct {
+	SprintID   uint  `json:"sprint_id"`
+	AssignedTo *uint `json:"assigned_to"`
+	Tasks      int64 `json:"tasks"`
+	Points     int64 `json:"points"`
+}
+
+// SprintAssigneeLoad is the points committed to one assignee in a sprint
+type SprintAssigneeLoad struct {
+	AssignedTo      *uint `json:"assigned_to"` // nil - задачи без исполнителя
+	Tasks           int64 `json:"tasks"`
+	CommittedPoints int64 `json:"committed_points"`
+	Unestimated     int64 `json:"unestimated"`
+}
+
+// SprintPlanning compares the capacity of a sprint with the points committed to it
+type SprintPlanning struct {
+	SprintID          uint                  `json:"sprint_id"`
+	CapacityPoints    int64                 `json:"capacity_points"`
+	CommittedPoints   int64                 `json:"committed_points"`
+	RemainingCapacity int64                 `json:"remaining_capacity"`
+	OverCapacity      bool                  `json:"over_capacity"`
+	Unestimated       int64                 `json:"unestimated"`
+	Assignees         []*SprintAssigneeLoad `json:"assignees"`
+}
+
+// SprintSummary represents progress statistics of a sprint
+type SprintSummary struct {
+	SprintID        uint                 `json:"sprint_id"`
+	Status          SprintStatus         `json:"status"`
+	StartDate       time.Time            `json:"start_date"`
+	EndDate         time.Time            `json:"end_date"`
+	DaysTotal       int                  `json:"days_total"`
+	DaysElapsed     int                  `json:"days_elapsed"`
+	DaysRemaining   int                  `json:"days_remaining"`
+	TotalTasks      int64                `json:"total_tasks"`
+	CompletedTasks  int64                `json:"completed_tasks"`
+	TotalPoints     int64                `json:"total_points"`
+	CompletedPoints int64                `json:"completed_points"`
+	RemainingPoints int64                `json:"remaining_points"`
+	Progress        float64              `json:"progress"` // Доля выполненных story points, 0..1
+	ByStatus        map[TaskStatus]int64 `json:"by_status"`
+	Unestimated     int64                `json:"unestimated"`
+}
+
+// BurndownDay is the remaining work of a sprint at the end of a day
+type BurndownDay struct {
+	Date      time.Time `json:"date"`
+	Ideal     float64   `json:"ideal"`
+	Remaining *int64    `json:"remaining,omitempty"` // Пусто для ещё не наступивших дней
+}
+
+// SprintBurndown represents remaining story points of a sprint by day
+type SprintBurndown struct {
+	SprintID    uint           `json:"sprint_id"`
+	TotalPoints int64          `json:"total_points"`
+	Days        []*BurndownDay `json:"days"`
+}
+
+// VelocitySprint is the work completed in a sprint of the velocity report
+type VelocitySprint struct {
+	SprintID        uint      `json:"sprint_id"`
+	Name            string    `json:"name"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	CompletedTasks  int64     `json:"completed_tasks"`
+	CompletedPoints int64     `json:"completed_points"`
+}
+
+// AssigneeSprintVelocity is the work one assignee completed in a sprint
+type AssigneeSprintVelocity struct {
+	SprintID        uint  `json:"sprint_id"`
+	CompletedTasks  int64 `json:"completed_tasks"`
+	CompletedPoints int64 `json:"completed_points"`
+}
+
+// AssigneeVelocity is the work one assignee completed per sprint
+type AssigneeVelocity struct {
+	AssignedTo    *uint                     `json:"assigned_to"` // nil - задачи без исполнителя
+	Sprints       []*AssigneeSprintVelocity `json:"sprints"`     // По спринтам отчёта, включая спринты без выполненных задач
+	AverageTasks  float64                   `json:"average_tasks"`
+	AveragePoints float64                   `json:"average_points"`
+}
+
+// SprintVelocity represents tasks and story points completed per sprint by assignee over the last
+// completed sprints, oldest first
+type SprintVelocity struct {
+	Sprints       []*VelocitySprint   `json:"sprints"`
+	Assignees     []*AssigneeVelocity `json:"assignees"`
+	AverageTasks  float64             `json:"average_tasks"`
+	AveragePoints float64             `json:"average_points"`
+}
+
+// GanttTask is a bar of the sprint Gantt chart. Dates are days in UTC, both inclusive.
+type GanttTask struct {
+	TaskID        uint         `json:"task_id"`
+	Title         string       `json:"title"`
+	Status        TaskStatus   `json:"status"`
+	Priority      TaskPriority `json:"priority"`
+	AssignedTo    *uint        `json:"assigned_to,omitempty"`
+	StartDate     time.Time    `json:"start_date"`
+	EndDate       time.Time    `json:"end_date"`
+	DurationDays  int          `json:"duration_days"`
+	StartInferred bool         `json:"start_inferred"` // Начало выведено из зависимостей, срока или начала спринта
+	EndInferred   bool         `json:"end_inferred"`   // Окончание выведено из оценки
+	DependsOn     []uint       `json:"depends_on"`
+	SlackDays     int          `json:"slack_days"` // На сколько дней задачу можно сдвинуть без сдвига окончания, меньше нуля - задача опаздывает
+	Critical      bool         `json:"critical"`
+}
+
+// SprintGantt represents tasks of a sprint on a timeline with dependencies and the critical path
+type SprintGantt struct {
+	SprintID     uint         `json:"sprint_id"`
+	StartDate    time.Time    `json:"start_date"`
+	EndDate      time.Time    `json:"end_date"`
+	FinishDate   *time.Time   `json:"finish_date,omitempty"` // Окончание последней задачи
+	Tasks        []*GanttTask `json:"tasks"`
+	CriticalPath []uint       `json:"critical_path"`
+}
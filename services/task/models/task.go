@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
 
 	"gorm.io/gorm"
 )
@@ -19,6 +20,16 @@ const (
 	TaskStatusCancelled  TaskStatus = "cancelled"
 )
 
+// IsValid checks if the status is a known task status
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case TaskStatusNew, TaskStatusInProgress, TaskStatusReview, TaskStatusDone, TaskStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
 // TaskPriority represents the priority level of a task
 type TaskPriority string
 
@@ -29,16 +40,32 @@ const (
 	TaskPriorityCritical TaskPriority = "critical"
 )
 
+// IsValid checks if the priority is a known task priority
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh, TaskPriorityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
 // Task represents a task in the system
 type Task struct {
 	models.BaseModel
-	Title       string       `gorm:"not null;size:255" json:"title" validate:"required,min=1,max=255"`
-	Description string       `gorm:"type:text" json:"description,omitempty" validate:"omitempty,max=2000"`
-	Status      TaskStatus   `gorm:"not null;default:'new';size:20" json:"status" validate:"required,oneof=new in_progress review done cancelled"`
-	Priority    TaskPriority `gorm:"not null;default:'medium';size:20" json:"priority" validate:"required,oneof=low medium high critical"`
-	AssignedTo  *uint        `gorm:"index" json:"assigned_to,omitempty" validate:"omitempty,min=1"`
-	CreatedBy   uint         `gorm:"not null;index" json:"created_by" validate:"required,min=1"`
-	DueDate     *time.Time   `json:"due_date,omitempty"`
+	models.VersionMeta
+	Title         string       `gorm:"not null;size:255" json:"title" validate:"required,min=1,max=255"`
+	Description   string       `gorm:"type:text" json:"description,omitempty" validate:"omitempty,max=2000"`
+	Status        TaskStatus   `gorm:"not null;default:'new';size:20" json:"status" validate:"required,oneof=new in_progress review done cancelled"`
+	Priority      TaskPriority `gorm:"not null;default:'medium';size:20" json:"priority" validate:"required,oneof=low medium high critical"`
+	AssignedTo    *uint        `gorm:"index" json:"assigned_to,omitempty" validate:"omitempty,min=1"`
+	CreatedBy     uint         `gorm:"not null;index" json:"created_by" validate:"required,min=1"`
+	StartDate     *time.Time   `json:"start_date,omitempty"` // Плановое начало, для диаграммы Ганта
+	DueDate       *time.Time   `json:"due_date,omitempty"`
+	EstimateHours *float64     `json:"estimate_hours,omitempty" validate:"omitempty,gt=0,lte=1000"` // Оценка трудозатрат в часах
+	StoryPoints   *int         `json:"story_points,omitempty" validate:"omitempty,min=0,max=100"`
+	SprintID      *uint        `gorm:"index" json:"sprint_id,omitempty"`
+	CompletedAt   *time.Time   `json:"completed_at,omitempty"` // Время перевода в done, для burndown спринта
 
 	// Associations
 	Comments []TaskComment `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE" json:"comments,omitempty"`
@@ -64,30 +91,53 @@ func (t *Task) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// SetStatus changes the status of the task and records when it was completed
+func (t *Task) SetStatus(status TaskStatus, now time.Time) {
+	if status == TaskStatusDone && t.Status != TaskStatusDone {
+		t.CompletedAt = &now
+	} else if status != TaskStatusDone {
+		t.CompletedAt = nil
+	}
+	t.Status = status
+}
+
 // Request/Response Models
 
 // CreateTaskRequest represents request for creating a task
 type CreateTaskRequest struct {
-	Title       string        `json:"title" binding:"required,min=1,max=255" validate:"required,min=1,max=255"`
-	Description string        `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
-	Priority    *TaskPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical" validate:"omitempty,oneof=low medium high critical"`
-	AssignedTo  *uint         `json:"assigned_to,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
-	DueDate     *time.Time    `json:"due_date,omitempty"`
+	Title         string        `json:"title" binding:"required,min=1,max=255" validate:"required,notblank,max=255"`
+	Description   string        `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	Priority      *TaskPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical" validate:"omitempty,enum"`
+	AssignedTo    *uint         `json:"assigned_to,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+	StartDate     *time.Time    `json:"start_date,omitempty"`
+	DueDate       *time.Time    `json:"due_date,omitempty" validate:"omitempty,future"`
+	EstimateHours *float64      `json:"estimate_hours,omitempty" binding:"omitempty,gt=0,lte=1000" validate:"omitempty,gt=0,lte=1000"`
+	StoryPoints   *int          `json:"story_points,omitempty" binding:"omitempty,min=0,max=100" validate:"omitempty,min=0,max=100"`
+}
+
+// InternalCreateTaskRequest represents request of another service for creating a task on behalf of a user
+type InternalCreateTaskRequest struct {
+	UserID uint              `json:"user_id" binding:"required,min=1"`
+	Task   CreateTaskRequest `json:"task" binding:"required"`
 }
 
 // UpdateTaskRequest represents request for updating a task
 type UpdateTaskRequest struct {
-	Title       *string       `json:"title,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,min=1,max=255"`
-	Description *string       `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
-	Status      *TaskStatus   `json:"status,omitempty" binding:"omitempty,oneof=new in_progress review done cancelled" validate:"omitempty,oneof=new in_progress review done cancelled"`
-	Priority    *TaskPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical" validate:"omitempty,oneof=low medium high critical"`
-	AssignedTo  *uint         `json:"assigned_to,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
-	DueDate     *time.Time    `json:"due_date,omitempty"`
+	Title         *string       `json:"title,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,notblank,max=255"`
+	Description   *string       `json:"description,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	Status        *TaskStatus   `json:"status,omitempty" binding:"omitempty,oneof=new in_progress review done cancelled" validate:"omitempty,enum"`
+	Priority      *TaskPriority `json:"priority,omitempty" binding:"omitempty,oneof=low medium high critical" validate:"omitempty,enum"`
+	AssignedTo    *uint         `json:"assigned_to,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"`
+	StartDate     *time.Time    `json:"start_date,omitempty"`
+	DueDate       *time.Time    `json:"due_date,omitempty"`
+	EstimateHours *float64      `json:"estimate_hours,omitempty" binding:"omitempty,gt=0,lte=1000" validate:"omitempty,gt=0,lte=1000"`
+	StoryPoints   *int          `json:"story_points,omitempty" binding:"omitempty,min=0,max=100" validate:"omitempty,min=0,max=100"`
+	Version       *uint         `json:"version,omitempty" binding:"omitempty,min=1" validate:"omitempty,min=1"` // Версия, на основе которой сделаны изменения
 }
 
 // UpdateTaskStatusRequest represents request for updating task status only
 type UpdateTaskStatusRequest struct {
-	Status TaskStatus `json:"status" binding:"required,oneof=new in_progress review done cancelled" validate:"required,oneof=new in_progress review done cancelled"`
+	Status TaskStatus `json:"status" binding:"required,oneof=new in_progress review done cancelled" validate:"required,enum"`
 }
 
 // AssignTaskRequest represents request for assigning a task to a user
@@ -99,34 +149,53 @@ type AssignTaskRequest struct {
 
 // TaskResponse represents a task in API responses
 type TaskResponse struct {
-	ID           uint         `json:"id"`
-	Title        string       `json:"title"`
-	Description  string       `json:"description,omitempty"`
-	Status       TaskStatus   `json:"status"`
-	Priority     TaskPriority `json:"priority"`
-	AssignedTo   *uint        `json:"assigned_to,omitempty"`
-	CreatedBy    uint         `json:"created_by"`
-	DueDate      *time.Time   `json:"due_date,omitempty"`
-	CommentCount int          `json:"comment_count"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
+	ID            uint         `json:"id"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description,omitempty"`
+	Status        TaskStatus   `json:"status"`
+	Priority      TaskPriority `json:"priority"`
+	AssignedTo    *uint        `json:"assigned_to,omitempty"`
+	CreatedBy     uint         `json:"created_by"`
+	StartDate     *time.Time   `json:"start_date,omitempty"`
+	DueDate       *time.Time   `json:"due_date,omitempty"`
+	EstimateHours *float64     `json:"estimate_hours,omitempty"`
+	StoryPoints   *int         `json:"story_points,omitempty"`
+	SprintID      *uint        `json:"sprint_id,omitempty"`
+	CompletedAt   *time.Time   `json:"completed_at,omitempty"`
+	CommentCount  int          `json:"comment_count"`
+	Version       uint         `json:"version"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	DeletedAt     *time.Time   `json:"deleted_at,omitempty"` // Только для задач в корзине
 }
 
 // ToResponse converts Task model to TaskResponse
 func (t *Task) ToResponse() *TaskResponse {
-	return &TaskResponse{
-		ID:           t.ID,
-		Title:        t.Title,
-		Description:  t.Description,
-		Status:       t.Status,
-		Priority:     t.Priority,
-		AssignedTo:   t.AssignedTo,
-		CreatedBy:    t.CreatedBy,
-		DueDate:      t.DueDate,
-		CommentCount: t.CommentCount,
-		CreatedAt:    t.CreatedAt,
-		UpdatedAt:    t.UpdatedAt,
+	response := &TaskResponse{
+		ID:            t.ID,
+		Title:         t.Title,
+		Description:   t.Description,
+		Status:        t.Status,
+		Priority:      t.Priority,
+		AssignedTo:    t.AssignedTo,
+		CreatedBy:     t.CreatedBy,
+		StartDate:     t.StartDate,
+		DueDate:       t.DueDate,
+		EstimateHours: t.EstimateHours,
+		StoryPoints:   t.StoryPoints,
+		SprintID:      t.SprintID,
+		CompletedAt:   t.CompletedAt,
+		CommentCount:  t.CommentCount,
+		Version:       t.Version,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
 	}
+
+	if t.DeletedAt.Valid {
+		response.DeletedAt = &t.DeletedAt.Time
+	}
+
+	return response
 }
 
 // TaskStatsResponse represents task statistics
@@ -142,6 +211,96 @@ type TaskStatsResponse struct {
 	TasksCreatedByMe  int `json:"tasks_created_by_me"`
 }
 
+// AssigneeSuggestionRequest asks for assignees of a new task by skills and due date
+type AssigneeSuggestionRequest struct {
+	Skills []string   `form:"-"`
+	Due    *time.Time `form:"-"`
+	Limit  int        `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// AssigneeWorkload is the current load of a possible assignee
+type AssigneeWorkload struct {
+	UserID    uint  `json:"user_id"`
+	OpenTasks int64 `json:"open_tasks"` // Назначенные задачи в работе
+	DueBefore int64 `json:"due_before"` // Из них со сроком не позже срока новой задачи
+}
+
+// AssigneeSuggestion is a user suggested as assignee, higher scores come first
+type AssigneeSuggestion struct {
+	UserID        uint     `json:"user_id"`
+	Name          string   `json:"name"`
+	DepartmentID  *uint    `json:"department_id,omitempty"`
+	MatchedSkills []string `json:"matched_skills"`
+	SkillMatch    float64  `json:"skill_match"` // Доля запрошенных навыков, 0..1
+	OpenTasks     int64    `json:"open_tasks"`
+	DueBefore     int64    `json:"due_before"`
+	Score         float64  `json:"score"`
+}
+
+// Workload warnings
+const (
+	WorkloadWarningOpenTasks = "open_tasks_exceeded"
+	WorkloadWarningEstimate  = "estimate_exceeded"
+)
+
+// WorkloadThreshold is the load above which an assignee is reported as overloaded.
+// A zero value disables the check.
+type WorkloadThreshold struct {
+	OpenTasks     int64   `json:"open_tasks,omitempty"`
+	EstimateHours float64 `json:"estimate_hours,omitempty"`
+}
+
+// DefaultWorkloadThreshold is used when no threshold is configured
+var DefaultWorkloadThreshold = WorkloadThreshold{OpenTasks: 10, EstimateHours: 40}
+
+// WorkloadReportRequest selects assignees of the workload report
+type WorkloadReportRequest struct {
+	DepartmentID *uint  `form:"department_id" binding:"omitempty,min=1"`
+	UserIDs      []uint `form:"user_ids" binding:"omitempty,max=200,dive,min=1"`
+	UpcomingDays int    `form:"upcoming_days" binding:"omitempty,min=1,max=90"`
+}
+
+// AssigneeLoad is the aggregated open tasks of an assignee with one priority
+type AssigneeLoad struct {
+	UserID        uint         `json:"user_id"`
+	Priority      TaskPriority `json:"priority"`
+	OpenTasks     int64        `json:"open_tasks"`
+	EstimateHours float64      `json:"estimate_hours"`
+	Unestimated   int64        `json:"unestimated"`
+	Overdue       int64        `json:"overdue"`
+}
+
+// WorkloadDueTask is an open task due soon
+type WorkloadDueTask struct {
+	TaskID     uint         `json:"task_id"`
+	Title      string       `json:"title"`
+	Priority   TaskPriority `json:"priority"`
+	AssignedTo uint         `json:"-"`
+	DueDate    time.Time    `json:"due_date"`
+}
+
+// UserWorkload is the load of one assignee in the workload report
+type UserWorkload struct {
+	UserID        uint                   `json:"user_id"`
+	OpenTasks     int64                  `json:"open_tasks"`
+	ByPriority    map[TaskPriority]int64 `json:"by_priority"`
+	EstimateHours float64                `json:"estimate_hours"`
+	Unestimated   int64                  `json:"unestimated"` // Открытые задачи без оценки
+	Overdue       int64                  `json:"overdue"`
+	Upcoming      []*WorkloadDueTask     `json:"upcoming"`
+	Warnings      []string               `json:"warnings"`
+}
+
+// WorkloadReport represents open task load of a department or a list of users
+type WorkloadReport struct {
+	DepartmentID  *uint             `json:"department_id,omitempty"`
+	Threshold     WorkloadThreshold `json:"threshold"`
+	UpcomingUntil time.Time         `json:"upcoming_until"`
+	Users         []*UserWorkload   `json:"users"`
+	Overloaded    int               `json:"overloaded"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+}
+
 // TaskFilterRequest represents filtering parameters for tasks
 type TaskFilterRequest struct {
 	Status     *TaskStatus   `form:"status" binding:"omitempty,oneof=new in_progress review done cancelled"`
@@ -150,8 +309,52 @@ type TaskFilterRequest struct {
 	CreatedBy  *uint         `form:"created_by" binding:"omitempty,min=1"`
 	DueBefore  *time.Time    `form:"due_before" time_format:"2006-01-02"`
 	DueAfter   *time.Time    `form:"due_after" time_format:"2006-01-02"`
-	Limit      int           `form:"limit" binding:"omitempty,min=1,max=100"`
-	Offset     int           `form:"offset" binding:"omitempty,min=0"`
-	SortBy     string        `form:"sort_by" binding:"omitempty,oneof=created_at updated_at due_date priority title"`
-	SortOrder  string        `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	SprintID   *uint         `form:"sprint_id" binding:"omitempty,min=1"`
+	Page       *query.Params `form:"-"` // Pagination, sorting and generic filters
+}
+
+// TaskListOptions defines pagination, sorting and filtering of task lists
+var TaskListOptions = &query.Options{
+	DefaultSort: "-created_at",
+	SortFields: map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"due_date":   "due_date",
+		"priority":   "priority",
+		"title":      "title",
+	},
+	FilterFields: map[string]string{
+		"status":      "status",
+		"priority":    "priority",
+		"assigned_to": "assigned_to",
+		"created_by":  "created_by",
+		"due_date":    "due_date",
+		"created_at":  "created_at",
+		"title":       "title",
+		"sprint_id":   "sprint_id",
+	},
+}
+
+// TaskTrashListOptions defines pagination, sorting and filtering of deleted tasks
+var TaskTrashListOptions = &query.Options{
+	DefaultSort: "-deleted_at",
+	SortFields: map[string]string{
+		"deleted_at": "deleted_at",
+		"created_at": "created_at",
+		"due_date":   "due_date",
+		"priority":   "priority",
+		"title":      "title",
+	},
+	FilterFields: TaskListOptions.FilterFields,
+}
+
+// Models returns all database models of the service for migrations
+func Models() []interface{} {
+	return []interface{}{
+		&Task{},
+		&TaskComment{},
+		&TaskCommentReaction{},
+		&Sprint{},
+		&TaskDependency{},
+	}
 }
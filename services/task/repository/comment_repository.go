@@ -6,6 +6,7 @@ import (
 
 	"tachyon-messenger/services/task/models"
 	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
 )
@@ -20,6 +21,11 @@ type CommentRepository interface {
 	GetCommentsWithReplies(taskID uint, filter *models.CommentFilterRequest) ([]*models.TaskComment, int64, error)
 	GetCommentsByUser(userID uint, limit, offset int) ([]*models.TaskComment, error)
 	CountByTaskID(taskID uint) (int64, error)
+
+	// Reactions
+	AddReaction(reaction *models.TaskCommentReaction) error
+	RemoveReaction(commentID, userID uint, emoji string) error
+	GetReactions(commentID uint) ([]*models.TaskCommentReaction, error)
 }
 
 // commentRepository implements CommentRepository interface
@@ -131,9 +137,7 @@ func (r *commentRepository) GetCommentsWithReplies(taskID uint, filter *models.C
 	query = query.Limit(limit).Offset(offset)
 
 	var comments []*models.TaskComment
-	err := query.Preload("Replies", func(db *gorm.DB) *gorm.DB {
-		return db.Order("created_at ASC")
-	}).Order("created_at ASC").Find(&comments).Error
+	err := preloadTaskCommentThread(query).Order("created_at ASC").Find(&comments).Error
 
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get task comments with replies: %w", err)
@@ -167,8 +171,71 @@ func (r *commentRepository) CountByTaskID(taskID uint) (int64, error) {
 	return count, nil
 }
 
+// AddReaction adds an emoji reaction to a task comment
+func (r *commentRepository) AddReaction(reaction *models.TaskCommentReaction) error {
+	// Check if reaction already exists
+	var existing models.TaskCommentReaction
+	err := r.db.Where("comment_id = ? AND user_id = ? AND emoji = ?",
+		reaction.CommentID, reaction.UserID, reaction.Emoji).First(&existing).Error
+
+	if err == nil {
+		return fmt.Errorf("reaction already exists")
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check existing reaction: %w", err)
+	}
+
+	if err := r.db.Create(reaction).Error; err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveReaction removes user's emoji reaction from a task comment
+func (r *commentRepository) RemoveReaction(commentID, userID uint, emoji string) error {
+	result := r.db.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, emoji).
+		Delete(&models.TaskCommentReaction{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove reaction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("reaction not found")
+	}
+	return nil
+}
+
+// GetReactions retrieves all reactions for a task comment
+func (r *commentRepository) GetReactions(commentID uint) ([]*models.TaskCommentReaction, error) {
+	var reactions []*models.TaskCommentReaction
+	err := r.db.Where("comment_id = ?", commentID).
+		Order("created_at ASC").
+		Find(&reactions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment reactions: %w", err)
+	}
+	return reactions, nil
+}
+
 // Helper methods
 
+// preloadTaskCommentThread preloads replies down to MaxCommentDepth along with reactions on every level
+func preloadTaskCommentThread(db *gorm.DB) *gorm.DB {
+	orderByCreated := func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}
+
+	db = db.Preload("Reactions")
+	path := ""
+	for depth := 1; depth <= sharedmodels.MaxCommentDepth; depth++ {
+		path += "Replies"
+		db = db.Preload(path, orderByCreated).Preload(path + ".Reactions")
+		path += "."
+	}
+	return db
+}
+
 // getPaginationParams extracts and validates pagination parameters
 func (r *commentRepository) getPaginationParams(filter *models.CommentFilterRequest) (limit, offset int) {
 	limit = 20 // default
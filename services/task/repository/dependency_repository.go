@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/task/models"
+)
+
+// AddDependency makes a task depend on another one
+func (r *taskRepository) AddDependency(dependency *models.TaskDependency) error {
+	if err := r.db.Create(dependency).Error; err != nil {
+		return fmt.Errorf("failed to add task dependency: %w", err)
+	}
+	return nil
+}
+
+// RemoveDependency removes the dependency of a task on another one
+func (r *taskRepository) RemoveDependency(taskID, dependsOnID uint) error {
+	result := r.db.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove task dependency: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task dependency not found")
+	}
+	return nil
+}
+
+// GetDependencies retrieves IDs of tasks the task depends on and of tasks that depend on it
+func (r *taskRepository) GetDependencies(taskID uint) ([]uint, []uint, error) {
+	dependsOn := []uint{}
+	err := r.db.Model(&models.TaskDependency{}).
+		Where("task_id = ?", taskID).
+		Order("depends_on_id").
+		Pluck("depends_on_id", &dependsOn).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get task dependencies: %w", err)
+	}
+
+	blocks := []uint{}
+	err = r.db.Model(&models.TaskDependency{}).
+		Where("depends_on_id = ?", taskID).
+		Order("task_id").
+		Pluck("task_id", &blocks).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dependent tasks: %w", err)
+	}
+	return dependsOn, blocks, nil
+}
+
+// HasDependencyPath checks if fromID depends on toID directly or through other tasks
+func (r *taskRepository) HasDependencyPath(fromID, toID uint) (bool, error) {
+	var count int64
+	err := r.db.Raw(`
+		WITH RECURSIVE chain(id) AS (
+			SELECT depends_on_id FROM task_dependencies WHERE task_id = ?
+			UNION
+			SELECT d.depends_on_id FROM task_dependencies d JOIN chain c ON d.task_id = c.id
+		)
+		SELECT COUNT(*) FROM chain WHERE id = ?`,
+		fromID, toID,
+	).Scan(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check task dependency chain: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,68 @@
+// Package repotest provides task repositories on an in-memory SQLite database and
+// fixture factories for usecase and repository tests
+package repotest
+
+import (
+	"testing"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/services/task/repository"
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/database/dbtest"
+)
+
+// Repositories holds all task repositories backed by one test database
+type Repositories struct {
+	DB       *database.DB
+	Tasks    repository.TaskRepository
+	Comments repository.CommentRepository
+	Sprints  repository.SprintRepository
+}
+
+// New creates repositories on a fresh test database
+func New(tb testing.TB) *Repositories {
+	tb.Helper()
+
+	db := dbtest.Open(tb, models.Models()...)
+	return &Repositories{
+		DB:       db,
+		Tasks:    repository.NewTaskRepository(db),
+		Comments: repository.NewCommentRepository(db),
+		Sprints:  repository.NewSprintRepository(db),
+	}
+}
+
+// Task creates a task of the user, options override the defaults before saving
+func (r *Repositories) Task(tb testing.TB, createdBy uint, options ...func(*models.Task)) *models.Task {
+	tb.Helper()
+
+	task := &models.Task{
+		Title:     "Test task",
+		Status:    models.TaskStatusNew,
+		Priority:  models.TaskPriorityMedium,
+		CreatedBy: createdBy,
+	}
+	for _, option := range options {
+		option(task)
+	}
+
+	if err := r.Tasks.Create(task); err != nil {
+		tb.Fatalf("failed to create task fixture: %v", err)
+	}
+	return task
+}
+
+// Comment creates a comment of the user on the task
+func (r *Repositories) Comment(tb testing.TB, taskID, userID uint, content string) *models.TaskComment {
+	tb.Helper()
+
+	comment := &models.TaskComment{
+		TaskID:  taskID,
+		UserID:  userID,
+		Content: content,
+	}
+	if err := r.Comments.Create(comment); err != nil {
+		tb.Fatalf("failed to create comment fixture: %v", err)
+	}
+	return comment
+}
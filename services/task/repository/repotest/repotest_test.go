@@ -0,0 +1,240 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/task/models"
+)
+
+func TestTaskFixtures(t *testing.T) {
+	repos := New(t)
+
+	assignee := uint(2)
+	task := repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &assignee })
+	repos.Task(t, 3)
+	repos.Comment(t, task.ID, 2, "Looks good")
+
+	tasks, total, err := repos.Tasks.GetUserTasks(2, &models.TaskFilterRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(tasks) != 1 || tasks[0].ID != task.ID {
+		t.Fatalf("expected only the assigned task, got %d tasks (total %d)", len(tasks), total)
+	}
+
+	count, err := repos.Comments.CountByTaskID(task.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 comment, got %d", count)
+	}
+}
+
+func TestAssigneeWorkload(t *testing.T) {
+	repos := New(t)
+
+	busy, idle := uint(2), uint(3)
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(10 * 24 * time.Hour)
+	repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &busy; task.DueDate = &soon })
+	repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &busy; task.DueDate = &later })
+	repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &busy })
+	repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &busy; task.Status = models.TaskStatusDone })
+
+	dueBy := time.Now().Add(3 * 24 * time.Hour)
+	workload, err := repos.Tasks.GetAssigneeWorkload([]uint{busy, idle}, &dueBy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := workload[busy]; got.OpenTasks != 3 || got.DueBefore != 1 {
+		t.Errorf("expected 3 open tasks with 1 due by the date, got %+v", got)
+	}
+	if got := workload[idle]; got == nil || got.OpenTasks != 0 {
+		t.Errorf("expected an empty workload of the idle user, got %+v", got)
+	}
+
+	workload, err = repos.Tasks.GetAssigneeWorkload([]uint{busy}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := workload[busy]; got.OpenTasks != 3 || got.DueBefore != 0 {
+		t.Errorf("expected no due count without a due date, got %+v", got)
+	}
+}
+
+func TestAssigneeLoad(t *testing.T) {
+	repos := New(t)
+
+	user := uint(2)
+	now := time.Now()
+	overdue := now.Add(-24 * time.Hour)
+	soon := now.Add(2 * 24 * time.Hour)
+	later := now.Add(30 * 24 * time.Hour)
+	estimate := 4.5
+	repos.Task(t, 1, func(task *models.Task) {
+		task.AssignedTo = &user
+		task.Priority = models.TaskPriorityHigh
+		task.DueDate = &overdue
+		task.EstimateHours = &estimate
+	})
+	repos.Task(t, 1, func(task *models.Task) {
+		task.AssignedTo = &user
+		task.Priority = models.TaskPriorityHigh
+		task.DueDate = &soon
+		task.EstimateHours = &estimate
+	})
+	repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &user; task.DueDate = &later })
+	repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &user; task.Status = models.TaskStatusDone })
+
+	load, err := repos.Tasks.GetAssigneeLoad([]uint{user}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byPriority := make(map[models.TaskPriority]*models.AssigneeLoad)
+	for _, row := range load {
+		byPriority[row.Priority] = row
+	}
+	if got := byPriority[models.TaskPriorityHigh]; got == nil || got.OpenTasks != 2 || got.EstimateHours != 9 || got.Overdue != 1 {
+		t.Errorf("expected 2 high priority tasks of 9 hours with 1 overdue, got %+v", got)
+	}
+	if got := byPriority[models.TaskPriorityMedium]; got == nil || got.OpenTasks != 1 || got.Unestimated != 1 {
+		t.Errorf("expected 1 unestimated medium priority task, got %+v", got)
+	}
+
+	upcoming, err := repos.Tasks.GetUpcomingDue([]uint{user}, now, now.Add(7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upcoming) != 1 || upcoming[0].AssignedTo != user {
+		t.Errorf("expected 1 upcoming task of the user, got %+v", upcoming)
+	}
+}
+
+func TestSprintTasks(t *testing.T) {
+	repos := New(t)
+	sprints := repos.Sprints
+
+	sprint := &models.Sprint{Name: "Sprint 1", StartDate: time.Now(), EndDate: time.Now().Add(14 * 24 * time.Hour), CreatedBy: 1}
+	if err := sprints.Create(sprint); err != nil {
+		t.Fatalf("failed to create sprint: %v", err)
+	}
+
+	user := uint(2)
+	three, five := 3, 5
+	done := repos.Task(t, 1, func(task *models.Task) {
+		task.AssignedTo = &user
+		task.StoryPoints = &three
+		task.SetStatus(models.TaskStatusDone, time.Now())
+	})
+	open := repos.Task(t, 1, func(task *models.Task) { task.AssignedTo = &user; task.StoryPoints = &five })
+	unestimated := repos.Task(t, 1)
+
+	missing, err := sprints.AddTasks(sprint.ID, []uint{done.ID, open.ID, unestimated.ID, 999})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != 999 {
+		t.Fatalf("expected unknown task 999, got %v", missing)
+	}
+
+	if _, err := sprints.AddTasks(sprint.ID, []uint{done.ID, open.ID, unestimated.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats, err := sprints.GetTaskStats(sprint.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var points, unestimatedTasks int64
+	for _, row := range stats {
+		points += row.Points
+		unestimatedTasks += row.Unestimated
+	}
+	if points != 8 || unestimatedTasks != 1 {
+		t.Errorf("expected 8 points with 1 unestimated task, got %d points and %d unestimated", points, unestimatedTasks)
+	}
+
+	completions, err := sprints.GetCompletions(sprint.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(completions) != 1 || completions[0].Points != 3 {
+		t.Errorf("expected 1 completion of 3 points, got %+v", completions)
+	}
+
+	if err := sprints.RemoveTask(sprint.ID, open.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sprints.RemoveTask(sprint.ID, open.ID); err == nil {
+		t.Error("expected an error removing a task that isn't in the sprint")
+	}
+
+	if err := sprints.Delete(sprint.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task, err := repos.Tasks.GetByID(done.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.SprintID != nil {
+		t.Errorf("expected tasks of a deleted sprint back in the backlog, got sprint %d", *task.SprintID)
+	}
+}
+
+func TestTaskDependencies(t *testing.T) {
+	repos := New(t)
+
+	sprint := &models.Sprint{Name: "Sprint 1", StartDate: time.Now(), EndDate: time.Now().Add(14 * 24 * time.Hour), CreatedBy: 1}
+	if err := repos.Sprints.Create(sprint); err != nil {
+		t.Fatalf("failed to create sprint: %v", err)
+	}
+
+	design := repos.Task(t, 1, func(task *models.Task) { task.SprintID = &sprint.ID })
+	build := repos.Task(t, 1, func(task *models.Task) { task.SprintID = &sprint.ID })
+	release := repos.Task(t, 1, func(task *models.Task) { task.SprintID = &sprint.ID })
+	backlog := repos.Task(t, 1)
+
+	for _, dependency := range []*models.TaskDependency{
+		{TaskID: build.ID, DependsOnID: design.ID, CreatedBy: 1},
+		{TaskID: release.ID, DependsOnID: build.ID, CreatedBy: 1},
+		{TaskID: release.ID, DependsOnID: backlog.ID, CreatedBy: 1},
+	} {
+		if err := repos.Tasks.AddDependency(dependency); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Release depends on design through build, the reverse would be a cycle
+	if found, err := repos.Tasks.HasDependencyPath(release.ID, design.ID); err != nil || !found {
+		t.Errorf("expected release to depend on design transitively, got %v (%v)", found, err)
+	}
+	if found, err := repos.Tasks.HasDependencyPath(design.ID, release.ID); err != nil || found {
+		t.Errorf("expected design not to depend on release, got %v (%v)", found, err)
+	}
+
+	dependsOn, blocks, err := repos.Tasks.GetDependencies(build.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dependsOn) != 1 || dependsOn[0] != design.ID || len(blocks) != 1 || blocks[0] != release.ID {
+		t.Errorf("expected build to depend on design and block release, got %v and %v", dependsOn, blocks)
+	}
+
+	// Dependencies on tasks outside of the sprint are left out of its chart
+	dependencies, err := repos.Sprints.GetDependencies(sprint.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dependencies) != 2 {
+		t.Errorf("expected 2 dependencies within the sprint, got %d", len(dependencies))
+	}
+
+	if err := repos.Tasks.RemoveDependency(release.ID, backlog.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repos.Tasks.RemoveDependency(release.ID, backlog.ID); err == nil {
+		t.Error("expected an error removing a missing dependency")
+	}
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// SprintRepository defines the interface for sprint data operations
+type SprintRepository interface {
+	Create(sprint *models.Sprint) error
+	GetByID(id uint) (*models.Sprint, error)
+	List(filter *models.SprintFilterRequest) ([]*models.Sprint, int64, error)
+	Update(sprint *models.Sprint) error
+	Delete(id uint) error
+
+	// Sprint tasks
+	AddTasks(sprintID uint, taskIDs []uint) ([]uint, error)
+	RemoveTask(sprintID, taskID uint) error
+	GetTaskStats(sprintID uint) ([]*models.SprintTaskStats, error)
+	GetCompletions(sprintID uint) ([]*models.SprintCompletion, error)
+	GetCompletedStats(sprintIDs []uint) ([]*models.SprintAssigneeCompletion, error)
+	GetTasks(sprintID uint) ([]*models.Task, error)
+	GetDependencies(sprintID uint) ([]*models.TaskDependency, error)
+}
+
+// sprintRepository implements SprintRepository interface
+type sprintRepository struct {
+	db *database.DB
+}
+
+// NewSprintRepository creates a new sprint repository
+func NewSprintRepository(db *database.DB) SprintRepository {
+	return &sprintRepository{
+		db: db,
+	}
+}
+
+// Create creates a new sprint
+func (r *sprintRepository) Create(sprint *models.Sprint) error {
+	if err := r.db.Create(sprint).Error; err != nil {
+		return fmt.Errorf("failed to create sprint: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a sprint by ID
+func (r *sprintRepository) GetByID(id uint) (*models.Sprint, error) {
+	var sprint models.Sprint
+	if err := r.db.First(&sprint, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("sprint not found")
+		}
+		return nil, fmt.Errorf("failed to get sprint: %w", err)
+	}
+	return &sprint, nil
+}
+
+// List retrieves sprints, latest first
+func (r *sprintRepository) List(filter *models.SprintFilterRequest) ([]*models.Sprint, int64, error) {
+	query := r.db.Model(&models.Sprint{})
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count sprints: %w", err)
+	}
+
+	var sprints []*models.Sprint
+	err := query.Order("start_date DESC, id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&sprints).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get sprints: %w", err)
+	}
+	return sprints, total, nil
+}
+
+// Update updates a sprint
+func (r *sprintRepository) Update(sprint *models.Sprint) error {
+	if err := r.db.Save(sprint).Error; err != nil {
+		return fmt.Errorf("failed to update sprint: %w", err)
+	}
+	return nil
+}
+
+// Delete soft deletes a sprint and takes its tasks out of it
+func (r *sprintRepository) Delete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Task{}).Where("sprint_id = ?", id).Update("sprint_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Sprint{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("sprint not found")
+		}
+		return fmt.Errorf("failed to delete sprint: %w", err)
+	}
+	return nil
+}
+
+// AddTasks moves existing tasks to the sprint and returns IDs of the tasks that don't exist
+func (r *sprintRepository) AddTasks(sprintID uint, taskIDs []uint) ([]uint, error) {
+	var missing []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&models.Task{}).Where("id IN ?", taskIDs).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		found := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			found[id] = true
+		}
+		for _, id := range taskIDs {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			return nil
+		}
+
+		return tx.Model(&models.Task{}).Where("id IN ?", taskIDs).Update("sprint_id", sprintID).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add tasks to sprint: %w", err)
+	}
+	return missing, nil
+}
+
+// RemoveTask takes a task out of the sprint
+func (r *sprintRepository) RemoveTask(sprintID, taskID uint) error {
+	result := r.db.Model(&models.Task{}).
+		Where("id = ? AND sprint_id = ?", taskID, sprintID).
+		Update("sprint_id", nil)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove task from sprint: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task not found in sprint")
+	}
+	return nil
+}
+
+// GetTaskStats aggregates tasks of the sprint by assignee and status
+func (r *sprintRepository) GetTaskStats(sprintID uint) ([]*models.SprintTaskStats, error) {
+	stats := []*models.SprintTaskStats{}
+	err := r.db.Model(&models.Task{}).
+		Select("assigned_to, status, COUNT(*) AS tasks, "+
+			"COALESCE(SUM(story_points), 0) AS points, "+
+			"SUM(CASE WHEN story_points IS NULL THEN 1 ELSE 0 END) AS unestimated").
+		Where("sprint_id = ?", sprintID).
+		Group("assigned_to, status").
+		Order("assigned_to, status").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint task stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetCompletions retrieves completion times and points of done tasks of the sprint, earliest first
+func (r *sprintRepository) GetCompletions(sprintID uint) ([]*models.SprintCompletion, error) {
+	var tasks []*models.Task
+	err := r.db.Select("id, story_points, completed_at").
+		Where("sprint_id = ? AND status = ? AND completed_at IS NOT NULL", sprintID, models.TaskStatusDone).
+		Order("completed_at").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint completions: %w", err)
+	}
+
+	completions := make([]*models.SprintCompletion, 0, len(tasks))
+	for _, task := range tasks {
+		completion := &models.SprintCompletion{CompletedAt: *task.CompletedAt}
+		if task.StoryPoints != nil {
+			completion.Points = int64(*task.StoryPoints)
+		}
+		completions = append(completions, completion)
+	}
+	return completions, nil
+}
+
+// GetCompletedStats aggregates done tasks of the sprints by sprint and assignee
+func (r *sprintRepository) GetCompletedStats(sprintIDs []uint) ([]*models.SprintAssigneeCompletion, error) {
+	stats := []*models.SprintAssigneeCompletion{}
+	if len(sprintIDs) == 0 {
+		return stats, nil
+	}
+
+	err := r.db.Model(&models.Task{}).
+		Select("sprint_id, assigned_to, COUNT(*) AS tasks, COALESCE(SUM(story_points), 0) AS points").
+		Where("sprint_id IN ? AND status = ?", sprintIDs, models.TaskStatusDone).
+		Group("sprint_id, assigned_to").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint completed stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetTasks retrieves tasks of the sprint that are not cancelled
+func (r *sprintRepository) GetTasks(sprintID uint) ([]*models.Task, error) {
+	var tasks []*models.Task
+	err := r.db.Where("sprint_id = ? AND status <> ?", sprintID, models.TaskStatusCancelled).
+		Order("id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetDependencies retrieves dependencies between tasks of the sprint
+func (r *sprintRepository) GetDependencies(sprintID uint) ([]*models.TaskDependency, error) {
+	sprintTasks := r.db.Model(&models.Task{}).Select("id").Where("sprint_id = ?", sprintID)
+
+	var dependencies []*models.TaskDependency
+	err := r.db.Where("task_id IN (?) AND depends_on_id IN (?)", sprintTasks, sprintTasks).
+		Order("task_id, depends_on_id").
+		Find(&dependencies).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint task dependencies: %w", err)
+	}
+	return dependencies, nil
+}
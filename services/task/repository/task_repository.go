@@ -7,10 +7,14 @@ import (
 
 	"tachyon-messenger/services/task/models"
 	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
 )
 
+// ErrTaskNotFound is returned when a task does not exist
+var ErrTaskNotFound = errors.New("task not found")
+
 // TaskRepository defines the interface for task data operations
 type TaskRepository interface {
 	Create(task *models.Task) error
@@ -24,6 +28,25 @@ type TaskRepository interface {
 	Count() (int64, error)
 	GetOverdueTasks(userID *uint) ([]*models.Task, error)
 	GetTasksWithComments(taskIDs []uint) ([]*models.Task, error)
+	GetAssigneeWorkload(userIDs []uint, dueBy *time.Time) (map[uint]*models.AssigneeWorkload, error)
+	GetAssigneeLoad(userIDs []uint, now time.Time) ([]*models.AssigneeLoad, error)
+	GetUpcomingDue(userIDs []uint, from, until time.Time) ([]*models.WorkloadDueTask, error)
+	GetDigestStats(userID uint, since, now, deadlinesUntil time.Time) (*sharedmodels.TasksDigestStats, error)
+
+	// Dependencies
+	AddDependency(dependency *models.TaskDependency) error
+	RemoveDependency(taskID, dependsOnID uint) error
+	GetDependencies(taskID uint) ([]uint, []uint, error)
+	HasDependencyPath(fromID, toID uint) (bool, error)
+
+	// Trash operations
+	GetDeletedByID(id uint) (*models.Task, error)
+	GetDeletedTasks(creatorID uint, filter *models.TaskFilterRequest) ([]*models.Task, int64, error)
+	Restore(id uint) error
+	PurgeDeleted(deletedBefore time.Time) (int64, error)
+
+	// Account merge
+	MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error)
 }
 
 // TaskCommentRepository defines the interface for task comment data operations
@@ -69,7 +92,7 @@ func (r *taskRepository) GetByID(id uint) (*models.Task, error) {
 	err := r.db.First(&task, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("task not found")
+			return nil, ErrTaskNotFound
 		}
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
@@ -84,12 +107,11 @@ func (r *taskRepository) GetByID(id uint) (*models.Task, error) {
 
 // Update updates an existing task
 func (r *taskRepository) Update(task *models.Task) error {
-	result := r.db.Save(task)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update task: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("task not found")
+	if err := r.db.SaveVersioned(task); err != nil {
+		if errors.Is(err, database.ErrVersionConflict) {
+			return fmt.Errorf("version conflict: task was modified or deleted by another request")
+		}
+		return fmt.Errorf("failed to update task: %w", err)
 	}
 	return nil
 }
@@ -106,6 +128,64 @@ func (r *taskRepository) Delete(id uint) error {
 	return nil
 }
 
+// GetDeletedByID retrieves a soft-deleted task by ID
+func (r *taskRepository) GetDeletedByID(id uint) (*models.Task, error) {
+	var task models.Task
+	err := r.db.Trashed().First(&task, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task not found")
+		}
+		return nil, fmt.Errorf("failed to get deleted task: %w", err)
+	}
+	return &task, nil
+}
+
+// GetDeletedTasks retrieves soft-deleted tasks of a creator, most recently deleted first
+func (r *taskRepository) GetDeletedTasks(creatorID uint, filter *models.TaskFilterRequest) ([]*models.Task, int64, error) {
+	query := r.db.Trashed().Model(&models.Task{}).Where("created_by = ?", creatorID)
+
+	// Apply filters
+	query = r.applyFilters(query, filter)
+
+	// Get total count
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted tasks: %w", err)
+	}
+
+	// Apply pagination and sorting
+	query = r.applySortingAndPagination(query, filter)
+
+	var tasks []*models.Task
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted tasks: %w", err)
+	}
+
+	return tasks, total, nil
+}
+
+// Restore restores a soft-deleted task by ID
+func (r *taskRepository) Restore(id uint) error {
+	restored, err := r.db.Restore(&models.Task{}, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+	if !restored {
+		return fmt.Errorf("task not found")
+	}
+	return nil
+}
+
+// PurgeDeleted permanently deletes tasks soft-deleted before deletedBefore, comments are removed by cascade
+func (r *taskRepository) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	purged, err := r.db.PurgeDeleted(&models.Task{}, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+	return purged, nil
+}
+
 // GetUserTasks retrieves tasks for a user (either assigned to or created by)
 func (r *taskRepository) GetUserTasks(userID uint, filter *models.TaskFilterRequest) ([]*models.Task, int64, error) {
 	query := r.db.Model(&models.Task{}).Where("assigned_to = ? OR created_by = ?", userID, userID)
@@ -250,6 +330,123 @@ func (r *taskRepository) GetTaskStats(userID uint) (*models.TaskStatsResponse, e
 	return stats, nil
 }
 
+// GetAssigneeWorkload counts open tasks assigned to each user and how many of them are due by dueBy.
+// Every requested user gets an entry, with zero counts if nothing is assigned.
+func (r *taskRepository) GetAssigneeWorkload(userIDs []uint, dueBy *time.Time) (map[uint]*models.AssigneeWorkload, error) {
+	workload := make(map[uint]*models.AssigneeWorkload, len(userIDs))
+	for _, userID := range userIDs {
+		workload[userID] = &models.AssigneeWorkload{UserID: userID}
+	}
+	if len(userIDs) == 0 {
+		return workload, nil
+	}
+
+	dueBefore := "0"
+	args := []interface{}{}
+	if dueBy != nil {
+		dueBefore = "SUM(CASE WHEN due_date IS NOT NULL AND due_date <= ? THEN 1 ELSE 0 END)"
+		args = append(args, *dueBy)
+	}
+
+	var rows []models.AssigneeWorkload
+	err := r.db.Model(&models.Task{}).
+		Select("assigned_to AS user_id, COUNT(*) AS open_tasks, "+dueBefore+" AS due_before", args...).
+		Where("assigned_to IN ? AND status NOT IN ?", userIDs, []models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}).
+		Group("assigned_to").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignee workload: %w", err)
+	}
+
+	for _, row := range rows {
+		if entry, exists := workload[row.UserID]; exists {
+			entry.OpenTasks = row.OpenTasks
+			entry.DueBefore = row.DueBefore
+		}
+	}
+	return workload, nil
+}
+
+// GetAssigneeLoad aggregates open tasks of the users by assignee and priority: their number, total estimate,
+// how many have no estimate and how many are overdue at now
+func (r *taskRepository) GetAssigneeLoad(userIDs []uint, now time.Time) ([]*models.AssigneeLoad, error) {
+	load := []*models.AssigneeLoad{}
+	if len(userIDs) == 0 {
+		return load, nil
+	}
+
+	err := r.db.Model(&models.Task{}).
+		Select("assigned_to AS user_id, priority, COUNT(*) AS open_tasks, "+
+			"COALESCE(SUM(estimate_hours), 0) AS estimate_hours, "+
+			"SUM(CASE WHEN estimate_hours IS NULL THEN 1 ELSE 0 END) AS unestimated, "+
+			"SUM(CASE WHEN due_date IS NOT NULL AND due_date < ? THEN 1 ELSE 0 END) AS overdue", now).
+		Where("assigned_to IN ? AND status NOT IN ?", userIDs, []models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}).
+		Group("assigned_to, priority").
+		Order("assigned_to, priority").
+		Scan(&load).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignee load: %w", err)
+	}
+	return load, nil
+}
+
+// GetUpcomingDue retrieves open tasks of the users due within [from, until], soonest first
+func (r *taskRepository) GetUpcomingDue(userIDs []uint, from, until time.Time) ([]*models.WorkloadDueTask, error) {
+	var tasks []*models.Task
+	if len(userIDs) > 0 {
+		err := r.db.Select("id, title, priority, assigned_to, due_date").
+			Where("assigned_to IN ? AND status NOT IN ?", userIDs, []models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}).
+			Where("due_date >= ? AND due_date <= ?", from, until).
+			Order("due_date, id").
+			Find(&tasks).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get upcoming tasks: %w", err)
+		}
+	}
+
+	upcoming := make([]*models.WorkloadDueTask, 0, len(tasks))
+	for _, task := range tasks {
+		upcoming = append(upcoming, &models.WorkloadDueTask{
+			TaskID:     task.ID,
+			Title:      task.Title,
+			Priority:   task.Priority,
+			AssignedTo: *task.AssignedTo,
+			DueDate:    *task.DueDate,
+		})
+	}
+	return upcoming, nil
+}
+
+// GetDigestStats counts tasks of a user for the daily digest: tasks assigned to the user and tasks of
+// the user completed since the last digest, open assigned tasks that are overdue and those due by
+// deadlinesUntil
+func (r *taskRepository) GetDigestStats(userID uint, since, now, deadlinesUntil time.Time) (*sharedmodels.TasksDigestStats, error) {
+	stats := &sharedmodels.TasksDigestStats{}
+	closedStatuses := []models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}
+
+	counts := []struct {
+		kind  string
+		count *int64
+		query *gorm.DB
+	}{
+		{"new", &stats.NewTasks, r.db.Model(&models.Task{}).
+			Where("assigned_to = ? AND created_at >= ?", userID, since)},
+		{"completed", &stats.CompletedTasks, r.db.Model(&models.Task{}).
+			Where("(assigned_to = ? OR created_by = ?) AND status = ? AND completed_at >= ?", userID, userID, models.TaskStatusDone, since)},
+		{"overdue", &stats.OverdueTasks, r.db.Model(&models.Task{}).
+			Where("assigned_to = ? AND status NOT IN ? AND due_date < ?", userID, closedStatuses, now)},
+		{"upcoming", &stats.UpcomingDeadlines, r.db.Model(&models.Task{}).
+			Where("assigned_to = ? AND status NOT IN ? AND due_date >= ? AND due_date < ?", userID, closedStatuses, now, deadlinesUntil)},
+	}
+	for _, c := range counts {
+		if err := c.query.Count(c.count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s tasks: %w", c.kind, err)
+		}
+	}
+
+	return stats, nil
+}
+
 // Count returns the total number of tasks
 func (r *taskRepository) Count() (int64, error) {
 	var count int64
@@ -305,6 +502,10 @@ func (r *taskRepository) applyFilters(query *gorm.DB, filter *models.TaskFilterR
 		return query
 	}
 
+	if filter.Page != nil {
+		query = query.Scopes(filter.Page.FilterScope)
+	}
+
 	if filter.Status != nil {
 		query = query.Where("status = ?", *filter.Status)
 	}
@@ -329,44 +530,20 @@ func (r *taskRepository) applyFilters(query *gorm.DB, filter *models.TaskFilterR
 		query = query.Where("due_date > ?", *filter.DueAfter)
 	}
 
+	if filter.SprintID != nil {
+		query = query.Where("sprint_id = ?", *filter.SprintID)
+	}
+
 	return query
 }
 
 // applySortingAndPagination applies sorting and pagination to the query
 func (r *taskRepository) applySortingAndPagination(query *gorm.DB, filter *models.TaskFilterRequest) *gorm.DB {
-	if filter == nil {
+	if filter == nil || filter.Page == nil {
 		return query.Order("created_at DESC").Limit(20)
 	}
 
-	// Apply sorting
-	sortBy := "created_at"
-	sortOrder := "DESC"
-
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
-	}
-
-	if filter.SortOrder != "" {
-		sortOrder = filter.SortOrder
-	}
-
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
-
-	// Apply pagination
-	limit := 20
-	if filter.Limit > 0 {
-		limit = filter.Limit
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	offset := 0
-	if filter.Offset > 0 {
-		offset = filter.Offset
-	}
-
-	return query.Limit(limit).Offset(offset)
+	return query.Scopes(filter.Page.PageScope)
 }
 
 // loadCommentCounts loads comment counts for tasks
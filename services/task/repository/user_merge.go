@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/shared/database"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeUsers moves created and assigned tasks, comments, comment reactions and created sprints of a duplicate account
+// to the primary account
+func (r *taskRepository) MergeUsers(primaryID, duplicateID uint) (*sharedmodels.MergeUsersResult, error) {
+	result := sharedmodels.NewMergeUsersResult("task")
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		reassignments := []struct {
+			kind         string
+			model        interface{}
+			userColumn   string
+			scopeColumns []string
+		}{
+			{"assigned_tasks", &models.Task{}, "assigned_to", nil},
+			{"created_tasks", &models.Task{}, "created_by", nil},
+			{"comments", &models.TaskComment{}, "user_id", nil},
+			{"comment_reactions", &models.TaskCommentReaction{}, "user_id", []string{"comment_id", "emoji"}},
+			{"sprints", &models.Sprint{}, "created_by", nil},
+			{"task_dependencies", &models.TaskDependency{}, "created_by", nil},
+		}
+
+		for _, reassignment := range reassignments {
+			moved, dropped, err := database.ReassignUser(tx, reassignment.model, reassignment.userColumn,
+				reassignment.scopeColumns, duplicateID, primaryID)
+			if err != nil {
+				return fmt.Errorf("failed to merge %s: %w", reassignment.kind, err)
+			}
+			result.Moved[reassignment.kind] = moved
+			if dropped > 0 {
+				result.Dropped[reassignment.kind] = dropped
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tachyon-messenger/services/task/models"
+)
+
+// Assignee suggestions rank users with the requested skills by:
+//   - the share of requested skills they have, which counts most
+//   - their open tasks, each one lowers the score
+//   - open tasks due by the due date of the new task, which compete for the same time and
+//     lower the score more
+const (
+	suggestionSkillWeight     = 100.0
+	suggestionOpenTaskPenalty = 5.0
+	suggestionDuePenalty      = 10.0
+
+	defaultSuggestionLimit = 5
+	maxSuggestionSkills    = 10
+
+	// suggestionCandidates is how many users with the skills are ranked by workload
+	suggestionCandidates = 50
+)
+
+// SuggestAssignees suggests assignees of a new task by matching skills and current workload
+func (u *taskUsecase) SuggestAssignees(req *models.AssigneeSuggestionRequest) ([]*models.AssigneeSuggestion, error) {
+	skills := normalizeSuggestionSkills(req.Skills)
+	if len(skills) == 0 {
+		return nil, fmt.Errorf("validation failed: at least one skill is required")
+	}
+	if len(skills) > maxSuggestionSkills {
+		return nil, fmt.Errorf("validation failed: at most %d skills are allowed", maxSuggestionSkills)
+	}
+	if u.skills == nil {
+		return nil, fmt.Errorf("assignee suggestions are not available")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+
+	candidates, err := u.skills.Candidates(skills, suggestionCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by skills: %w", err)
+	}
+
+	userIDs := make([]uint, len(candidates))
+	for i, candidate := range candidates {
+		userIDs[i] = candidate.UserID
+	}
+	workload, err := u.taskRepo.GetAssigneeWorkload(userIDs, req.Due)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*models.AssigneeSuggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		load := workload[candidate.UserID]
+		if load == nil {
+			load = &models.AssigneeWorkload{UserID: candidate.UserID}
+		}
+
+		skillMatch := float64(len(candidate.MatchedSkills)) / float64(len(skills))
+		suggestions = append(suggestions, &models.AssigneeSuggestion{
+			UserID:        candidate.UserID,
+			Name:          candidate.Name,
+			DepartmentID:  candidate.DepartmentID,
+			MatchedSkills: candidate.MatchedSkills,
+			SkillMatch:    skillMatch,
+			OpenTasks:     load.OpenTasks,
+			DueBefore:     load.DueBefore,
+			Score: skillMatch*suggestionSkillWeight -
+				float64(load.OpenTasks)*suggestionOpenTaskPenalty -
+				float64(load.DueBefore)*suggestionDuePenalty,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		if suggestions[i].OpenTasks != suggestions[j].OpenTasks {
+			return suggestions[i].OpenTasks < suggestions[j].OpenTasks
+		}
+		return suggestions[i].UserID < suggestions[j].UserID
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// normalizeSuggestionSkills lowercases skills and drops blanks and duplicates, like the user service stores them
+func normalizeSuggestionSkills(skills []string) []string {
+	seen := make(map[string]bool, len(skills))
+	normalized := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		skill = strings.ToLower(strings.Join(strings.Fields(skill), " "))
+		if skill != "" && !seen[skill] {
+			seen[skill] = true
+			normalized = append(normalized, skill)
+		}
+	}
+	return normalized
+}
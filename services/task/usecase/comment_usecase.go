@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"tachyon-messenger/services/task/models"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
 
 	"gorm.io/gorm"
 )
@@ -34,6 +36,7 @@ func (u *taskUsecase) AddComment(userID, taskID uint, req *models.CreateTaskComm
 	}
 
 	// Validate parent comment if provided
+	depth := 0
 	if req.ParentID != nil {
 		parentComment, err := u.commentRepo.GetByID(*req.ParentID)
 		if err != nil {
@@ -42,6 +45,10 @@ func (u *taskUsecase) AddComment(userID, taskID uint, req *models.CreateTaskComm
 		if parentComment.TaskID != taskID {
 			return nil, fmt.Errorf("parent comment does not belong to this task")
 		}
+		if err := sharedmodels.ValidateReplyDepth(parentComment.Depth); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		depth = parentComment.Depth + 1
 	}
 
 	// Create comment
@@ -51,6 +58,7 @@ func (u *taskUsecase) AddComment(userID, taskID uint, req *models.CreateTaskComm
 		Content:  strings.TrimSpace(req.Content),
 		ParentID: req.ParentID,
 	}
+	comment.Depth = depth
 
 	if err := u.commentRepo.Create(comment); err != nil {
 		return nil, fmt.Errorf("failed to create comment: %w", err)
@@ -92,7 +100,7 @@ func (u *taskUsecase) GetTaskComments(userID, taskID uint, filter *models.Commen
 	// Convert to response format
 	responses := make([]*models.TaskCommentResponse, len(comments))
 	for i, comment := range comments {
-		responses[i] = comment.ToResponse()
+		responses[i] = comment.ToResponseForUser(userID)
 	}
 
 	return &models.CommentListResponse{
@@ -124,14 +132,21 @@ func (u *taskUsecase) UpdateComment(userID, commentID uint, req *models.UpdateTa
 		return nil, fmt.Errorf("access denied: only comment author can update the comment")
 	}
 
+	// Nothing changed, keep edit markers untouched
+	content := strings.TrimSpace(req.Content)
+	if content == comment.Content {
+		return comment.ToResponseForUser(userID), nil
+	}
+
 	// Update comment content
-	comment.Content = strings.TrimSpace(req.Content)
+	comment.Content = content
+	comment.MarkEdited()
 
 	if err := u.commentRepo.Update(comment); err != nil {
 		return nil, fmt.Errorf("failed to update comment: %w", err)
 	}
 
-	return comment.ToResponse(), nil
+	return comment.ToResponseForUser(userID), nil
 }
 
 // DeleteComment deletes a task comment
@@ -158,45 +173,108 @@ func (u *taskUsecase) DeleteComment(userID, commentID uint) error {
 	return nil
 }
 
-// Comment validation methods
-
-// validateCreateCommentRequest validates comment creation request
-func (u *taskUsecase) validateCreateCommentRequest(req *models.CreateTaskCommentRequest) error {
+// AddCommentReaction adds an emoji reaction to a task comment and returns updated aggregates
+func (u *taskUsecase) AddCommentReaction(userID, commentID uint, req *sharedmodels.CommentReactionRequest) ([]sharedmodels.ReactionSummary, error) {
 	if req == nil {
-		return fmt.Errorf("request is required")
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	emoji := strings.TrimSpace(req.Emoji)
+	if err := sharedmodels.ValidateReactionEmoji(emoji); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Validate content
-	content := strings.TrimSpace(req.Content)
-	if content == "" {
-		return fmt.Errorf("comment content is required")
+	if err := u.checkCommentAccess(userID, commentID); err != nil {
+		return nil, err
 	}
-	if len(content) > 1000 {
-		return fmt.Errorf("comment content must be less than 1000 characters")
+
+	reaction := &models.TaskCommentReaction{}
+	reaction.CommentID = commentID
+	reaction.UserID = userID
+	reaction.Emoji = emoji
+
+	if err := u.commentRepo.AddReaction(reaction); err != nil {
+		return nil, fmt.Errorf("failed to add reaction: %w", err)
 	}
 
-	// Validate parent ID if provided
-	if req.ParentID != nil && *req.ParentID == 0 {
-		return fmt.Errorf("invalid parent comment ID")
+	return u.getReactionSummary(userID, commentID)
+}
+
+// RemoveCommentReaction removes user's emoji reaction from a task comment and returns updated aggregates
+func (u *taskUsecase) RemoveCommentReaction(userID, commentID uint, emoji string) ([]sharedmodels.ReactionSummary, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" {
+		return nil, fmt.Errorf("validation failed: emoji is required")
 	}
 
-	return nil
+	if err := u.checkCommentAccess(userID, commentID); err != nil {
+		return nil, err
+	}
+
+	if err := u.commentRepo.RemoveReaction(commentID, userID, emoji); err != nil {
+		return nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return u.getReactionSummary(userID, commentID)
 }
 
-// validateUpdateCommentRequest validates comment update request
-func (u *taskUsecase) validateUpdateCommentRequest(req *models.UpdateTaskCommentRequest) error {
-	if req == nil {
-		return fmt.Errorf("request is required")
+// checkCommentAccess verifies that the comment exists and the user has access to its task
+func (u *taskUsecase) checkCommentAccess(userID, commentID uint) error {
+	comment, err := u.commentRepo.GetByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("comment not found")
+		}
+		return fmt.Errorf("failed to get comment: %w", err)
 	}
 
-	// Validate content
-	content := strings.TrimSpace(req.Content)
-	if content == "" {
-		return fmt.Errorf("comment content cannot be empty")
+	task, err := u.taskRepo.GetByID(comment.TaskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("task not found")
+		}
+		return fmt.Errorf("failed to get task: %w", err)
 	}
-	if len(content) > 1000 {
-		return fmt.Errorf("comment content must be less than 1000 characters")
+
+	if !u.hasTaskAccess(userID, task) {
+		return fmt.Errorf("access denied: insufficient permissions to react on this task")
 	}
 
 	return nil
 }
+
+// getReactionSummary returns aggregated reactions for a comment
+func (u *taskUsecase) getReactionSummary(userID, commentID uint) ([]sharedmodels.ReactionSummary, error) {
+	reactions, err := u.commentRepo.GetReactions(commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+
+	plain := make([]sharedmodels.CommentReaction, len(reactions))
+	for i, reaction := range reactions {
+		plain[i] = reaction.CommentReaction
+	}
+
+	summary := sharedmodels.AggregateReactions(plain, userID)
+	if summary == nil {
+		summary = []sharedmodels.ReactionSummary{}
+	}
+	return summary, nil
+}
+
+// Comment validation methods
+
+// validateCreateCommentRequest validates comment creation request
+func (u *taskUsecase) validateCreateCommentRequest(req *models.CreateTaskCommentRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+	return validation.Struct(req)
+}
+
+// validateUpdateCommentRequest validates comment update request
+func (u *taskUsecase) validateUpdateCommentRequest(req *models.UpdateTaskCommentRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+	return validation.Struct(req)
+}
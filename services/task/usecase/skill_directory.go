@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SkillCandidate is an active user with some of the requested skills
+type SkillCandidate struct {
+	UserID        uint     `json:"user_id"`
+	Name          string   `json:"name"`
+	DepartmentID  *uint    `json:"department_id,omitempty"`
+	MatchedSkills []string `json:"matched_skills"`
+}
+
+// SkillDirectory finds users by their skills
+type SkillDirectory interface {
+	// Candidates returns active users with any of the skills, users matching more skills first
+	Candidates(skills []string, limit int) ([]*SkillCandidate, error)
+}
+
+// httpSkillDirectory asks the user service for users with the skills
+type httpSkillDirectory struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSkillDirectory creates a skill directory for the user service at baseURL.
+// It returns nil if baseURL is empty, assignee suggestions are then unavailable.
+func NewHTTPSkillDirectory(baseURL string) SkillDirectory {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpSkillDirectory{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Candidates requests users with the skills from the user service
+func (d *httpSkillDirectory) Candidates(skills []string, limit int) ([]*SkillCandidate, error) {
+	body, err := json.Marshal(map[string]interface{}{"skills": skills, "limit": limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode skill candidates request: %w", err)
+	}
+
+	resp, err := d.client.Post(d.baseURL+"/api/v1/internal/users/skills", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request skill candidates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service responded with status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Candidates []*SkillCandidate `json:"candidates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode skill candidates: %w", err)
+	}
+
+	return payload.Candidates, nil
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"tachyon-messenger/services/task/models"
+)
+
+// ganttHoursPerDay converts task estimates to days on the Gantt chart
+const ganttHoursPerDay = 8
+
+// GetGantt lays tasks of a sprint out on a timeline. Tasks without a start date start when the
+// tasks they depend on are finished, or early enough to meet their due date, or at the start of
+// the sprint. Tasks without a due date last their estimate, one day if not estimated. Slack and
+// the critical path are computed against the finish of the last task.
+func (u *sprintUsecase) GetGantt(sprintID uint) (*models.SprintGantt, error) {
+	sprint, err := u.sprintRepo.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := u.sprintRepo.GetTasks(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := u.sprintRepo.GetDependencies(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildGantt(sprint, tasks, dependencies), nil
+}
+
+// buildGantt schedules tasks with a forward pass over dependencies and computes slack with a backward pass
+func buildGantt(sprint *models.Sprint, tasks []*models.Task, dependencies []*models.TaskDependency) *models.SprintGantt {
+	gantt := &models.SprintGantt{
+		SprintID:     sprint.ID,
+		StartDate:    sprint.FirstDay(),
+		EndDate:      dateOf(sprint.EndDate),
+		Tasks:        make([]*models.GanttTask, 0, len(tasks)),
+		CriticalPath: []uint{},
+	}
+	if len(tasks) == 0 {
+		return gantt
+	}
+
+	bars := make(map[uint]*models.GanttTask, len(tasks))
+	byID := make(map[uint]*models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+		bars[task.ID] = &models.GanttTask{
+			TaskID:     task.ID,
+			Title:      task.Title,
+			Status:     task.Status,
+			Priority:   task.Priority,
+			AssignedTo: task.AssignedTo,
+			DependsOn:  []uint{},
+		}
+	}
+
+	successors := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		bar, exists := bars[dependency.TaskID]
+		if !exists || bars[dependency.DependsOnID] == nil {
+			continue
+		}
+		bar.DependsOn = append(bar.DependsOn, dependency.DependsOnID)
+		successors[dependency.DependsOnID] = append(successors[dependency.DependsOnID], dependency.TaskID)
+	}
+
+	order := ganttOrder(tasks, bars, successors)
+
+	// Forward pass: earliest dates
+	for _, id := range order {
+		task, bar := byID[id], bars[id]
+		duration := ganttDuration(task)
+
+		var ready *time.Time // Day after the last dependency is finished
+		for _, dependsOnID := range bar.DependsOn {
+			if end := bars[dependsOnID].EndDate; !end.IsZero() {
+				next := end.AddDate(0, 0, 1)
+				if ready == nil || next.After(*ready) {
+					ready = &next
+				}
+			}
+		}
+
+		switch {
+		case task.StartDate != nil:
+			bar.StartDate = dateOf(*task.StartDate)
+		case task.DueDate != nil:
+			bar.StartDate = dateOf(*task.DueDate).AddDate(0, 0, 1-duration)
+			if ready != nil && ready.After(bar.StartDate) {
+				bar.StartDate = *ready
+			}
+			bar.StartInferred = true
+		case ready != nil:
+			bar.StartDate = *ready
+			bar.StartInferred = true
+		default:
+			bar.StartDate = gantt.StartDate
+			bar.StartInferred = true
+		}
+
+		if task.DueDate != nil {
+			bar.EndDate = dateOf(*task.DueDate)
+			if bar.EndDate.Before(bar.StartDate) {
+				bar.EndDate = bar.StartDate
+			}
+		} else {
+			bar.EndDate = bar.StartDate.AddDate(0, 0, duration-1)
+			bar.EndInferred = true
+		}
+		bar.DurationDays = daysBetween(bar.StartDate, bar.EndDate) + 1
+	}
+
+	finish := bars[order[0]].EndDate
+	for _, bar := range bars {
+		if bar.EndDate.After(finish) {
+			finish = bar.EndDate
+		}
+	}
+	gantt.FinishDate = &finish
+
+	// Backward pass: latest finish keeping the finish date, slack is the gap to the earliest one
+	latestStart := make(map[uint]time.Time, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		bar := bars[id]
+
+		latestFinish := finish
+		for _, successorID := range successors[id] {
+			if start, exists := latestStart[successorID]; exists {
+				if limit := start.AddDate(0, 0, -1); limit.Before(latestFinish) {
+					latestFinish = limit
+				}
+			}
+		}
+		latestStart[id] = latestFinish.AddDate(0, 0, 1-bar.DurationDays)
+
+		bar.SlackDays = daysBetween(bar.EndDate, latestFinish)
+		bar.Critical = bar.SlackDays <= 0
+	}
+
+	for _, id := range order {
+		gantt.Tasks = append(gantt.Tasks, bars[id])
+	}
+	sort.SliceStable(gantt.Tasks, func(i, j int) bool {
+		return gantt.Tasks[i].StartDate.Before(gantt.Tasks[j].StartDate)
+	})
+	for _, bar := range gantt.Tasks {
+		if bar.Critical {
+			gantt.CriticalPath = append(gantt.CriticalPath, bar.TaskID)
+		}
+	}
+
+	return gantt
+}
+
+// ganttOrder sorts tasks so that each one comes after the tasks it depends on. Tasks left in a
+// dependency cycle, which can't be added through the API, are appended in ID order.
+func ganttOrder(tasks []*models.Task, bars map[uint]*models.GanttTask, successors map[uint][]uint) []uint {
+	pending := make(map[uint]int, len(tasks))
+	var ready []uint
+	for _, task := range tasks {
+		pending[task.ID] = len(bars[task.ID].DependsOn)
+		if pending[task.ID] == 0 {
+			ready = append(ready, task.ID)
+		}
+	}
+
+	order := make([]uint, 0, len(tasks))
+	done := make(map[uint]bool, len(tasks))
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		done[id] = true
+
+		for _, successorID := range successors[id] {
+			pending[successorID]--
+			if pending[successorID] == 0 {
+				ready = append(ready, successorID)
+			}
+		}
+	}
+
+	for _, task := range tasks {
+		if !done[task.ID] {
+			order = append(order, task.ID)
+		}
+	}
+	return order
+}
+
+// ganttDuration returns the number of days a task takes: between its start and due dates, or its
+// estimate in working days, one day if not estimated
+func ganttDuration(task *models.Task) int {
+	if task.StartDate != nil && task.DueDate != nil {
+		if days := daysBetween(dateOf(*task.StartDate), dateOf(*task.DueDate)) + 1; days > 0 {
+			return days
+		}
+	}
+	if task.EstimateHours != nil && *task.EstimateHours > 0 {
+		return int(math.Ceil(*task.EstimateHours / ganttHoursPerDay))
+	}
+	return 1
+}
+
+// daysBetween returns the number of days from one date to another
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// dateOf truncates t to the start of its day in UTC
+func dateOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,421 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/services/task/repository"
+	"tachyon-messenger/shared/validation"
+)
+
+// defaultSprintLimit is the page size of sprint lists
+const defaultSprintLimit = 20
+
+// defaultVelocitySprints is the number of completed sprints the velocity is reported over by default
+const defaultVelocitySprints = 6
+
+// SprintUsecase defines the interface for sprint planning business logic
+type SprintUsecase interface {
+	CreateSprint(userID uint, req *models.CreateSprintRequest) (*models.Sprint, error)
+	GetSprint(sprintID uint) (*models.Sprint, error)
+	ListSprints(filter *models.SprintFilterRequest) ([]*models.Sprint, int64, error)
+	UpdateSprint(sprintID uint, req *models.UpdateSprintRequest) (*models.Sprint, error)
+	DeleteSprint(sprintID uint) error
+
+	// Sprint tasks and statistics
+	AddTasks(sprintID uint, req *models.SprintTasksRequest) (*models.SprintPlanning, error)
+	RemoveTask(sprintID, taskID uint) error
+	GetPlanning(sprintID uint) (*models.SprintPlanning, error)
+	GetSummary(sprintID uint) (*models.SprintSummary, error)
+	GetBurndown(sprintID uint) (*models.SprintBurndown, error)
+	GetVelocity(req *models.SprintVelocityRequest) (*models.SprintVelocity, error)
+	GetGantt(sprintID uint) (*models.SprintGantt, error)
+}
+
+// sprintUsecase implements SprintUsecase interface
+type sprintUsecase struct {
+	sprintRepo repository.SprintRepository
+}
+
+// NewSprintUsecase creates a new sprint usecase
+func NewSprintUsecase(sprintRepo repository.SprintRepository) SprintUsecase {
+	return &sprintUsecase{
+		sprintRepo: sprintRepo,
+	}
+}
+
+// CreateSprint creates a new planned sprint
+func (u *sprintUsecase) CreateSprint(userID uint, req *models.CreateSprintRequest) (*models.Sprint, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validateSprintDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	sprint := &models.Sprint{
+		Name:           strings.TrimSpace(req.Name),
+		Goal:           strings.TrimSpace(req.Goal),
+		Status:         models.SprintStatusPlanned,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		CapacityPoints: req.CapacityPoints,
+		CreatedBy:      userID,
+	}
+	if err := u.sprintRepo.Create(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// GetSprint retrieves a sprint by ID
+func (u *sprintUsecase) GetSprint(sprintID uint) (*models.Sprint, error) {
+	return u.sprintRepo.GetByID(sprintID)
+}
+
+// ListSprints lists sprints, latest first
+func (u *sprintUsecase) ListSprints(filter *models.SprintFilterRequest) ([]*models.Sprint, int64, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSprintLimit
+	}
+	return u.sprintRepo.List(filter)
+}
+
+// UpdateSprint updates a sprint
+func (u *sprintUsecase) UpdateSprint(sprintID uint, req *models.UpdateSprintRequest) (*models.Sprint, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	sprint, err := u.sprintRepo.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		sprint.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Goal != nil {
+		sprint.Goal = strings.TrimSpace(*req.Goal)
+	}
+	if req.Status != nil {
+		sprint.Status = *req.Status
+	}
+	if req.StartDate != nil {
+		sprint.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		sprint.EndDate = *req.EndDate
+	}
+	if req.CapacityPoints != nil {
+		sprint.CapacityPoints = *req.CapacityPoints
+	}
+	if err := validateSprintDates(sprint.StartDate, sprint.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := u.sprintRepo.Update(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// DeleteSprint deletes a sprint, its tasks go back to the backlog
+func (u *sprintUsecase) DeleteSprint(sprintID uint) error {
+	return u.sprintRepo.Delete(sprintID)
+}
+
+// AddTasks moves tasks to a sprint and returns the updated planning
+func (u *sprintUsecase) AddTasks(sprintID uint, req *models.SprintTasksRequest) (*models.SprintPlanning, error) {
+	taskIDs := uniqueIDs(req.TaskIDs)
+	if len(taskIDs) == 0 {
+		return nil, fmt.Errorf("validation failed: at least one task is required")
+	}
+	if len(taskIDs) > models.MaxSprintTasks {
+		return nil, fmt.Errorf("validation failed: at most %d tasks can be added at once", models.MaxSprintTasks)
+	}
+
+	sprint, err := u.sprintRepo.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status == models.SprintStatusCompleted {
+		return nil, fmt.Errorf("cannot add tasks to a completed sprint")
+	}
+
+	missing, err := u.sprintRepo.AddTasks(sprintID, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("validation failed: unknown task IDs: %v", missing)
+	}
+
+	return u.planning(sprint)
+}
+
+// RemoveTask takes a task out of a sprint back to the backlog
+func (u *sprintUsecase) RemoveTask(sprintID, taskID uint) error {
+	if _, err := u.sprintRepo.GetByID(sprintID); err != nil {
+		return err
+	}
+	return u.sprintRepo.RemoveTask(sprintID, taskID)
+}
+
+// GetPlanning compares the capacity of a sprint with story points committed to it
+func (u *sprintUsecase) GetPlanning(sprintID uint) (*models.SprintPlanning, error) {
+	sprint, err := u.sprintRepo.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	return u.planning(sprint)
+}
+
+// planning builds the planning of a sprint from its task statistics
+func (u *sprintUsecase) planning(sprint *models.Sprint) (*models.SprintPlanning, error) {
+	stats, err := u.sprintRepo.GetTaskStats(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	planning := &models.SprintPlanning{
+		SprintID:       sprint.ID,
+		CapacityPoints: int64(sprint.CapacityPoints),
+		Assignees:      []*models.SprintAssigneeLoad{},
+	}
+
+	// Cancelled tasks don't take capacity, stats come ordered by assignee
+	var current *models.SprintAssigneeLoad
+	for _, row := range stats {
+		if row.Status == models.TaskStatusCancelled {
+			continue
+		}
+		if current == nil || !sameAssignee(current.AssignedTo, row.AssignedTo) {
+			current = &models.SprintAssigneeLoad{AssignedTo: row.AssignedTo}
+			planning.Assignees = append(planning.Assignees, current)
+		}
+		current.Tasks += row.Tasks
+		current.CommittedPoints += row.Points
+		current.Unestimated += row.Unestimated
+
+		planning.CommittedPoints += row.Points
+		planning.Unestimated += row.Unestimated
+	}
+
+	planning.RemainingCapacity = planning.CapacityPoints - planning.CommittedPoints
+	planning.OverCapacity = planning.RemainingCapacity < 0
+	return planning, nil
+}
+
+// GetSummary reports progress of a sprint: tasks and story points done, by status, and days left
+func (u *sprintUsecase) GetSummary(sprintID uint) (*models.SprintSummary, error) {
+	sprint, err := u.sprintRepo.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+	return u.summary(sprint, time.Now())
+}
+
+// summary builds the summary of a sprint at now
+func (u *sprintUsecase) summary(sprint *models.Sprint, now time.Time) (*models.SprintSummary, error) {
+	stats, err := u.sprintRepo.GetTaskStats(sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.SprintSummary{
+		SprintID:  sprint.ID,
+		Status:    sprint.Status,
+		StartDate: sprint.StartDate,
+		EndDate:   sprint.EndDate,
+		DaysTotal: sprint.Days(),
+		ByStatus:  make(map[models.TaskStatus]int64),
+	}
+
+	summary.DaysElapsed = sprint.DaysElapsed(now)
+	summary.DaysRemaining = summary.DaysTotal - summary.DaysElapsed
+
+	for _, row := range stats {
+		summary.ByStatus[row.Status] += row.Tasks
+		// Cancelled tasks are out of the sprint scope
+		if row.Status == models.TaskStatusCancelled {
+			continue
+		}
+		summary.TotalTasks += row.Tasks
+		summary.TotalPoints += row.Points
+		summary.Unestimated += row.Unestimated
+		if row.Status == models.TaskStatusDone {
+			summary.CompletedTasks += row.Tasks
+			summary.CompletedPoints += row.Points
+		}
+	}
+
+	summary.RemainingPoints = summary.TotalPoints - summary.CompletedPoints
+	if summary.TotalPoints > 0 {
+		summary.Progress = float64(summary.CompletedPoints) / float64(summary.TotalPoints)
+	}
+	return summary, nil
+}
+
+// GetBurndown reports remaining story points of a sprint at the end of each of its days next to
+// the ideal line. The current scope of the sprint is burned down, tasks added later count from
+// the first day.
+func (u *sprintUsecase) GetBurndown(sprintID uint) (*models.SprintBurndown, error) {
+	sprint, err := u.sprintRepo.GetByID(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	summary, err := u.summary(sprint, now)
+	if err != nil {
+		return nil, err
+	}
+	completions, err := u.sprintRepo.GetCompletions(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	burndown := &models.SprintBurndown{
+		SprintID:    sprint.ID,
+		TotalPoints: summary.TotalPoints,
+		Days:        make([]*models.BurndownDay, 0, summary.DaysTotal),
+	}
+
+	start := sprint.FirstDay()
+	next := 0
+	remaining := summary.TotalPoints
+	for day := 0; day < summary.DaysTotal; day++ {
+		date := start.AddDate(0, 0, day)
+		point := &models.BurndownDay{
+			Date:  date,
+			Ideal: float64(summary.TotalPoints) * float64(summary.DaysTotal-day-1) / float64(summary.DaysTotal),
+		}
+
+		if !date.After(now) {
+			endOfDay := date.AddDate(0, 0, 1)
+			for next < len(completions) && completions[next].CompletedAt.Before(endOfDay) {
+				remaining -= completions[next].Points
+				next++
+			}
+			value := remaining
+			point.Remaining = &value
+		}
+		burndown.Days = append(burndown.Days, point)
+	}
+
+	return burndown, nil
+}
+
+// GetVelocity reports tasks and story points each assignee completed in each of the last completed
+// sprints, and their averages per sprint
+func (u *sprintUsecase) GetVelocity(req *models.SprintVelocityRequest) (*models.SprintVelocity, error) {
+	count := req.Sprints
+	if count <= 0 {
+		count = defaultVelocitySprints
+	}
+	if count > models.MaxVelocitySprints {
+		return nil, fmt.Errorf("validation failed: velocity can be reported over at most %d sprints", models.MaxVelocitySprints)
+	}
+
+	status := models.SprintStatusCompleted
+	sprints, _, err := u.sprintRepo.List(&models.SprintFilterRequest{Status: &status, Limit: count})
+	if err != nil {
+		return nil, err
+	}
+
+	// Sprints come latest first, the report goes oldest first
+	velocity := &models.SprintVelocity{
+		Sprints:   make([]*models.VelocitySprint, len(sprints)),
+		Assignees: []*models.AssigneeVelocity{},
+	}
+	sprintIDs := make([]uint, len(sprints))
+	positions := make(map[uint]int, len(sprints))
+	for i, sprint := range sprints {
+		position := len(sprints) - 1 - i
+		sprintIDs[position] = sprint.ID
+		positions[sprint.ID] = position
+		velocity.Sprints[position] = &models.VelocitySprint{
+			SprintID:  sprint.ID,
+			Name:      sprint.Name,
+			StartDate: sprint.StartDate,
+			EndDate:   sprint.EndDate,
+		}
+	}
+
+	stats, err := u.sprintRepo.GetCompletedStats(sprintIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unassigned tasks are kept under 0, user IDs start at 1
+	assignees := make(map[uint]*models.AssigneeVelocity)
+	for _, row := range stats {
+		var key uint
+		if row.AssignedTo != nil {
+			key = *row.AssignedTo
+		}
+		assignee, ok := assignees[key]
+		if !ok {
+			assignee = &models.AssigneeVelocity{
+				AssignedTo: row.AssignedTo,
+				Sprints:    make([]*models.AssigneeSprintVelocity, len(sprintIDs)),
+			}
+			for i, sprintID := range sprintIDs {
+				assignee.Sprints[i] = &models.AssigneeSprintVelocity{SprintID: sprintID}
+			}
+			assignees[key] = assignee
+			velocity.Assignees = append(velocity.Assignees, assignee)
+		}
+
+		position := positions[row.SprintID]
+		assignee.Sprints[position].CompletedTasks += row.Tasks
+		assignee.Sprints[position].CompletedPoints += row.Points
+		velocity.Sprints[position].CompletedTasks += row.Tasks
+		velocity.Sprints[position].CompletedPoints += row.Points
+	}
+
+	// Assignees by ID, unassigned tasks last
+	sort.Slice(velocity.Assignees, func(i, j int) bool {
+		a, b := velocity.Assignees[i].AssignedTo, velocity.Assignees[j].AssignedTo
+		return b == nil && a != nil || a != nil && b != nil && *a < *b
+	})
+
+	if len(sprints) == 0 {
+		return velocity, nil
+	}
+	sprintCount := float64(len(sprints))
+	for _, assignee := range velocity.Assignees {
+		for _, sprint := range assignee.Sprints {
+			assignee.AverageTasks += float64(sprint.CompletedTasks) / sprintCount
+			assignee.AveragePoints += float64(sprint.CompletedPoints) / sprintCount
+		}
+	}
+	for _, sprint := range velocity.Sprints {
+		velocity.AverageTasks += float64(sprint.CompletedTasks) / sprintCount
+		velocity.AveragePoints += float64(sprint.CompletedPoints) / sprintCount
+	}
+	return velocity, nil
+}
+
+// validateSprintDates checks that the sprint ends after it starts and isn't too long
+func validateSprintDates(start, end time.Time) error {
+	if end.Before(start) {
+		return fmt.Errorf("validation failed: end date must not be before start date")
+	}
+	if end.Sub(start) > time.Duration(models.MaxSprintDays)*24*time.Hour {
+		return fmt.Errorf("validation failed: sprint can't be longer than %d days", models.MaxSprintDays)
+	}
+	return nil
+}
+
+// sameAssignee compares optional assignee IDs
+func sameAssignee(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/services/task/repository/repotest"
+)
+
+func TestSprintBurndown(t *testing.T) {
+	repos := repotest.New(t)
+	uc := NewSprintUsecase(repos.Sprints)
+
+	// A sprint of four days that started two days ago
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	sprint := &models.Sprint{Name: "Sprint 1", Status: models.SprintStatusActive, CreatedBy: 1,
+		StartDate: today.AddDate(0, 0, -2), EndDate: today.AddDate(0, 0, 1)}
+	if err := repos.Sprints.Create(sprint); err != nil {
+		t.Fatalf("failed to create sprint: %v", err)
+	}
+
+	points := func(p int) *int { return &p }
+	repos.Task(t, 1, func(task *models.Task) {
+		task.SprintID = &sprint.ID
+		task.StoryPoints = points(3)
+		task.SetStatus(models.TaskStatusDone, today.AddDate(0, 0, -2).Add(time.Hour))
+	})
+	repos.Task(t, 1, func(task *models.Task) {
+		task.SprintID = &sprint.ID
+		task.StoryPoints = points(5)
+		task.SetStatus(models.TaskStatusDone, time.Now())
+	})
+	repos.Task(t, 1, func(task *models.Task) { task.SprintID = &sprint.ID; task.StoryPoints = points(2) })
+	repos.Task(t, 1, func(task *models.Task) {
+		task.SprintID = &sprint.ID
+		task.StoryPoints = points(8)
+		task.Status = models.TaskStatusCancelled
+	})
+
+	burndown, err := uc.GetBurndown(sprint.ID)
+	if err != nil {
+		t.Fatalf("GetBurndown failed: %v", err)
+	}
+	if burndown.TotalPoints != 10 {
+		t.Errorf("total points = %d, want 10 without the cancelled task", burndown.TotalPoints)
+	}
+	if len(burndown.Days) != 4 {
+		t.Fatalf("days = %d, want 4", len(burndown.Days))
+	}
+
+	want := []int64{7, 7, 2}
+	for i, day := range burndown.Days[:3] {
+		if day.Remaining == nil || *day.Remaining != want[i] {
+			t.Errorf("day %d remaining = %v, want %d", i, day.Remaining, want[i])
+		}
+	}
+	if burndown.Days[3].Remaining != nil {
+		t.Errorf("expected no remaining points for tomorrow, got %d", *burndown.Days[3].Remaining)
+	}
+	if burndown.Days[0].Ideal != 7.5 || burndown.Days[3].Ideal != 0 {
+		t.Errorf("ideal line = %v .. %v, want 7.5 .. 0", burndown.Days[0].Ideal, burndown.Days[3].Ideal)
+	}
+}
+
+func TestSprintVelocity(t *testing.T) {
+	repos := repotest.New(t)
+	uc := NewSprintUsecase(repos.Sprints)
+
+	sprint := func(name string, status models.SprintStatus, start time.Time) *models.Sprint {
+		sprint := &models.Sprint{Name: name, Status: status, StartDate: start, EndDate: start.AddDate(0, 0, 13), CreatedBy: 1}
+		if err := repos.Sprints.Create(sprint); err != nil {
+			t.Fatalf("failed to create sprint: %v", err)
+		}
+		return sprint
+	}
+	start := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	first := sprint("Sprint 1", models.SprintStatusCompleted, start)
+	second := sprint("Sprint 2", models.SprintStatusCompleted, start.AddDate(0, 0, 14))
+	current := sprint("Sprint 3", models.SprintStatusActive, start.AddDate(0, 0, 28))
+
+	anna, boris := uint(2), uint(3)
+	task := func(sprint *models.Sprint, assignee *uint, points int, status models.TaskStatus) {
+		repos.Task(t, 1, func(task *models.Task) {
+			task.SprintID = &sprint.ID
+			task.AssignedTo = assignee
+			task.StoryPoints = &points
+			task.SetStatus(status, sprint.StartDate.Add(time.Hour))
+		})
+	}
+	task(first, &anna, 3, models.TaskStatusDone)
+	task(first, &anna, 5, models.TaskStatusDone)
+	task(first, &boris, 2, models.TaskStatusDone)
+	task(first, nil, 1, models.TaskStatusDone)
+	task(second, &anna, 8, models.TaskStatusDone)
+	task(second, &boris, 13, models.TaskStatusInProgress)
+	task(current, &boris, 5, models.TaskStatusDone)
+
+	velocity, err := uc.GetVelocity(&models.SprintVelocityRequest{})
+	if err != nil {
+		t.Fatalf("GetVelocity failed: %v", err)
+	}
+
+	// Completed sprints only, oldest first
+	if len(velocity.Sprints) != 2 || velocity.Sprints[0].SprintID != first.ID || velocity.Sprints[1].SprintID != second.ID {
+		t.Fatalf("unexpected sprints: %+v", velocity.Sprints)
+	}
+	if velocity.Sprints[0].CompletedPoints != 11 || velocity.Sprints[1].CompletedPoints != 8 {
+		t.Errorf("sprint points = %d, %d, want 11, 8", velocity.Sprints[0].CompletedPoints, velocity.Sprints[1].CompletedPoints)
+	}
+	if velocity.AveragePoints != 9.5 || velocity.AverageTasks != 2.5 {
+		t.Errorf("averages = %v points, %v tasks, want 9.5, 2.5", velocity.AveragePoints, velocity.AverageTasks)
+	}
+
+	// Assignees by ID, unassigned last, with every sprint of the report
+	if len(velocity.Assignees) != 3 {
+		t.Fatalf("assignees = %d, want 3", len(velocity.Assignees))
+	}
+	annaVelocity, borisVelocity, unassigned := velocity.Assignees[0], velocity.Assignees[1], velocity.Assignees[2]
+	if annaVelocity.AssignedTo == nil || *annaVelocity.AssignedTo != anna || borisVelocity.AssignedTo == nil ||
+		*borisVelocity.AssignedTo != boris || unassigned.AssignedTo != nil {
+		t.Fatalf("unexpected assignee order: %v, %v, %v", annaVelocity.AssignedTo, borisVelocity.AssignedTo, unassigned.AssignedTo)
+	}
+	if annaVelocity.Sprints[0].CompletedTasks != 2 || annaVelocity.Sprints[0].CompletedPoints != 8 ||
+		annaVelocity.Sprints[1].CompletedPoints != 8 || annaVelocity.AveragePoints != 8 {
+		t.Errorf("unexpected velocity of the first assignee: %+v %+v", annaVelocity.Sprints[0], annaVelocity.Sprints[1])
+	}
+	if len(borisVelocity.Sprints) != 2 || borisVelocity.Sprints[1].SprintID != second.ID ||
+		borisVelocity.Sprints[1].CompletedTasks != 0 || borisVelocity.AveragePoints != 1 {
+		t.Errorf("unexpected velocity of the second assignee: %+v %+v", borisVelocity.Sprints[0], borisVelocity.Sprints[1])
+	}
+
+	// The window is limited to the latest sprints
+	velocity, err = uc.GetVelocity(&models.SprintVelocityRequest{Sprints: 1})
+	if err != nil {
+		t.Fatalf("GetVelocity failed: %v", err)
+	}
+	if len(velocity.Sprints) != 1 || velocity.Sprints[0].SprintID != second.ID {
+		t.Errorf("expected only the latest completed sprint, got %+v", velocity.Sprints)
+	}
+	if _, err := uc.GetVelocity(&models.SprintVelocityRequest{Sprints: models.MaxVelocitySprints + 1}); err == nil {
+		t.Error("expected an error for too many sprints")
+	}
+}
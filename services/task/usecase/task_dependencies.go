@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/services/task/repository"
+	"tachyon-messenger/shared/validation"
+
+	"gorm.io/gorm"
+)
+
+// Errors of task access. Their text keeps the "not found" and "access denied" wording
+// handlers map to response statuses.
+var (
+	ErrTaskNotFound     = errors.New("task not found")
+	ErrTaskAccessDenied = errors.New("access denied: insufficient permissions")
+)
+
+// GetDependencies retrieves tasks a task depends on and tasks blocked by it
+func (u *taskUsecase) GetDependencies(userID, taskID uint) (*models.TaskDependenciesResponse, error) {
+	if _, err := u.getAccessibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+	return u.dependencies(taskID)
+}
+
+// AddDependency makes a task start only after another task is finished. Both tasks must be
+// accessible to the user. Dependencies that would form a cycle are rejected.
+func (u *taskUsecase) AddDependency(userID, taskID uint, req *models.AddTaskDependencyRequest) (*models.TaskDependenciesResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: request is required")
+	}
+	if err := validation.Struct(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if req.DependsOnID == taskID {
+		return nil, fmt.Errorf("validation failed: task cannot depend on itself")
+	}
+
+	if _, err := u.getAccessibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+	// A task the user cannot see is reported as missing, so task IDs cannot be probed
+	if _, err := u.getAccessibleTask(userID, req.DependsOnID); err != nil {
+		if errors.Is(err, ErrTaskNotFound) || errors.Is(err, ErrTaskAccessDenied) {
+			return nil, fmt.Errorf("dependency %w", ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to get dependency task: %w", err)
+	}
+
+	current, err := u.dependencies(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range current.DependsOn {
+		if id == req.DependsOnID {
+			return current, nil
+		}
+	}
+	if len(current.DependsOn) >= models.MaxTaskDependencies {
+		return nil, fmt.Errorf("validation failed: task can depend on at most %d tasks", models.MaxTaskDependencies)
+	}
+
+	cycle, err := u.taskRepo.HasDependencyPath(req.DependsOnID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if cycle {
+		return nil, fmt.Errorf("cannot add dependency: task %d already depends on task %d", req.DependsOnID, taskID)
+	}
+
+	dependency := &models.TaskDependency{
+		TaskID:      taskID,
+		DependsOnID: req.DependsOnID,
+		CreatedBy:   userID,
+	}
+	if err := u.taskRepo.AddDependency(dependency); err != nil {
+		return nil, err
+	}
+
+	return u.dependencies(taskID)
+}
+
+// RemoveDependency removes the dependency of a task on another one
+func (u *taskUsecase) RemoveDependency(userID, taskID, dependsOnID uint) error {
+	if _, err := u.getAccessibleTask(userID, taskID); err != nil {
+		return err
+	}
+	return u.taskRepo.RemoveDependency(taskID, dependsOnID)
+}
+
+// dependencies builds the dependencies response of a task
+func (u *taskUsecase) dependencies(taskID uint) (*models.TaskDependenciesResponse, error) {
+	dependsOn, blocks, err := u.taskRepo.GetDependencies(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TaskDependenciesResponse{
+		TaskID:    taskID,
+		DependsOn: dependsOn,
+		Blocks:    blocks,
+	}, nil
+}
+
+// getAccessibleTask returns a task if the user is its creator or assignee
+func (u *taskUsecase) getAccessibleTask(userID, taskID uint) (*models.Task, error) {
+	task, err := u.taskRepo.GetByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, repository.ErrTaskNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if !u.hasTaskAccess(userID, task) {
+		return nil, ErrTaskAccessDenied
+	}
+	return task, nil
+}
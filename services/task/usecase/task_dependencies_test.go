@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/services/task/repository/repotest"
+)
+
+func TestAddDependencyErrors(t *testing.T) {
+	repos := repotest.New(t)
+	uc := &taskUsecase{taskRepo: repos.Tasks}
+
+	design := repos.Task(t, 1)
+	build := repos.Task(t, 1)
+	private := repos.Task(t, 2)
+
+	if _, err := uc.AddDependency(1, build.ID, &models.AddTaskDependencyRequest{DependsOnID: design.ID}); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	// A missing task and a task of another user are both reported as a missing dependency
+	for _, dependsOnID := range []uint{private.ID, 999} {
+		_, err := uc.AddDependency(1, build.ID, &models.AddTaskDependencyRequest{DependsOnID: dependsOnID})
+		if !errors.Is(err, ErrTaskNotFound) || err.Error() != "dependency task not found" {
+			t.Errorf("dependency on task %d: got %v, want the dependency task not found", dependsOnID, err)
+		}
+	}
+
+	if _, err := uc.GetDependencies(1, private.ID); !errors.Is(err, ErrTaskAccessDenied) {
+		t.Errorf("GetDependencies of another user's task: got %v, want %v", err, ErrTaskAccessDenied)
+	}
+	if err := uc.RemoveDependency(1, 999, design.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("RemoveDependency of a missing task: got %v, want %v", err, ErrTaskNotFound)
+	}
+
+	if _, err := uc.AddDependency(1, design.ID, &models.AddTaskDependencyRequest{DependsOnID: build.ID}); err == nil {
+		t.Error("expected a dependency cycle to be rejected")
+	}
+}
@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/task/models"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/refs"
+	"tachyon-messenger/shared/spreadsheet"
+	"tachyon-messenger/shared/validation"
+)
+
+// exportBatchSize is how many tasks are loaded at a time while exporting
+const exportBatchSize = 500
+
+// importFieldAliases maps common spreadsheet headers to task fields, besides the field names themselves
+var importFieldAliases = map[string]string{
+	"name":        models.ImportFieldTitle,
+	"task":        models.ImportFieldTitle,
+	"summary":     models.ImportFieldTitle,
+	"state":       models.ImportFieldStatus,
+	"assignee":    models.ImportFieldAssignedTo,
+	"assignee_id": models.ImportFieldAssignedTo,
+	"start":       models.ImportFieldStartDate,
+	"due":         models.ImportFieldDueDate,
+	"deadline":    models.ImportFieldDueDate,
+	"estimate":    models.ImportFieldEstimateHours,
+	"hours":       models.ImportFieldEstimateHours,
+	"points":      models.ImportFieldStoryPoints,
+}
+
+// importDateLayouts are accepted date formats of imported cells, Excel serial dates are accepted too
+var importDateLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02", "02.01.2006", "01/02/2006"}
+
+// importedTask is a parsed spreadsheet row
+type importedTask struct {
+	result *models.TaskImportRow
+	task   *models.Task
+}
+
+// ImportTasks creates tasks from spreadsheet rows. Every row is validated on its own: valid rows
+// are created even if others fail, and the report tells what happened to each row. A dry run
+// only validates rows, so the mapping can be previewed before importing.
+func (u *taskUsecase) ImportTasks(userID uint, req *models.ImportTasksRequest) (*models.TaskImportReport, error) {
+	if req == nil || len(req.Rows) == 0 {
+		return nil, fmt.Errorf("validation failed: file is empty")
+	}
+	if len(req.Rows)-1 > models.MaxImportRows {
+		return nil, fmt.Errorf("validation failed: file has more than %d tasks", models.MaxImportRows)
+	}
+
+	columns, report, err := resolveImportColumns(req.Rows[0], req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = req.DryRun
+	report.Rows = []*models.TaskImportRow{}
+
+	now := time.Now()
+	var imported []*importedTask
+	for i, row := range req.Rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		item := parseImportRow(userID, columns, row, now)
+		item.result.Row = i + 2
+		imported = append(imported, item)
+	}
+	if err := u.checkImportAssignees(imported); err != nil {
+		return nil, err
+	}
+
+	for _, item := range imported {
+		report.Rows = append(report.Rows, item.result)
+		report.Total++
+		if len(item.result.Errors) > 0 {
+			report.Invalid++
+			continue
+		}
+		report.Valid++
+		if req.DryRun {
+			continue
+		}
+
+		if err := u.taskRepo.Create(item.task); err != nil {
+			item.result.Errors = append(item.result.Errors, "failed to create task")
+			report.Valid--
+			report.Invalid++
+			continue
+		}
+		item.result.TaskID = &item.task.ID
+		report.Created++
+	}
+
+	return report, nil
+}
+
+// ExportTasks passes tasks of the user matching the filter to write in list order, loading
+// them in batches so exports of any size use bounded memory
+func (u *taskUsecase) ExportTasks(userID uint, filter *models.TaskFilterRequest, write func(*models.TaskResponse) error) error {
+	batch := models.TaskFilterRequest{}
+	if filter != nil {
+		batch = *filter
+	}
+	page := query.Default(models.TaskListOptions)
+	if batch.Page != nil {
+		copied := *batch.Page
+		page = &copied
+	}
+	page.Limit = exportBatchSize
+	page.Offset = 0
+	batch.Page = page
+
+	for {
+		tasks, _, err := u.taskRepo.GetUserTasks(userID, &batch)
+		if err != nil {
+			return fmt.Errorf("failed to get user tasks: %w", err)
+		}
+		for _, task := range tasks {
+			if err := write(task.ToResponse()); err != nil {
+				return err
+			}
+		}
+		if len(tasks) < exportBatchSize {
+			return nil
+		}
+		page.Offset += exportBatchSize
+	}
+}
+
+// resolveImportColumns returns the task field of every header column. Columns named in the
+// mapping get the mapped field, others are matched by their name.
+func resolveImportColumns(header []string, mapping map[string]string) ([]string, *models.TaskImportReport, error) {
+	explicit := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		field = strings.TrimSpace(field)
+		if field != "" && !isImportField(field) {
+			return nil, nil, fmt.Errorf("validation failed: unknown task field %q for column %q, expected one of %s",
+				field, column, strings.Join(models.ImportFields, ", "))
+		}
+		explicit[normalizeImportHeader(column)] = field
+	}
+
+	report := &models.TaskImportReport{Mapping: map[string]string{}}
+	columns := make([]string, len(header))
+	mappedTo := map[string]string{}
+	for i, name := range header {
+		key := normalizeImportHeader(name)
+		field, ok := explicit[key]
+		if ok {
+			delete(explicit, key)
+		} else if isImportField(key) {
+			field = key
+		} else {
+			field = importFieldAliases[key]
+		}
+		if field == "" {
+			if strings.TrimSpace(name) != "" {
+				report.IgnoredColumns = append(report.IgnoredColumns, name)
+			}
+			continue
+		}
+
+		if other, ok := mappedTo[field]; ok {
+			return nil, nil, fmt.Errorf("validation failed: columns %q and %q are both mapped to %s", other, name, field)
+		}
+		mappedTo[field] = name
+		columns[i] = field
+		report.Mapping[name] = field
+	}
+
+	for column := range mapping {
+		if _, ok := explicit[normalizeImportHeader(column)]; ok {
+			return nil, nil, fmt.Errorf("validation failed: mapped column %q not found in file", column)
+		}
+	}
+	if _, ok := mappedTo[models.ImportFieldTitle]; !ok {
+		return nil, nil, fmt.Errorf("validation failed: no column is mapped to title")
+	}
+
+	return columns, report, nil
+}
+
+// parseImportRow builds a task from row cells, collecting all problems of the row
+func parseImportRow(userID uint, columns []string, row []string, now time.Time) *importedTask {
+	task := &models.Task{
+		CreatedBy: userID,
+		Status:    models.TaskStatusNew,
+		Priority:  models.TaskPriorityMedium,
+	}
+	result := &models.TaskImportRow{}
+	status := models.TaskStatusNew
+
+	for i, field := range columns {
+		if field == "" || i >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(row[i])
+		if value == "" {
+			continue
+		}
+
+		var err error
+		switch field {
+		case models.ImportFieldTitle:
+			task.Title = value
+			result.Title = value
+		case models.ImportFieldDescription:
+			task.Description = value
+		case models.ImportFieldStatus:
+			status = models.TaskStatus(normalizeImportHeader(value))
+			if !status.IsValid() {
+				err = fmt.Errorf("unknown status %q", value)
+			}
+		case models.ImportFieldPriority:
+			task.Priority = models.TaskPriority(normalizeImportHeader(value))
+			if !task.Priority.IsValid() {
+				err = fmt.Errorf("unknown priority %q", value)
+			}
+		case models.ImportFieldAssignedTo:
+			var id uint64
+			if id, err = strconv.ParseUint(value, 10, 0); err != nil || id == 0 {
+				err = fmt.Errorf("invalid assignee ID %q", value)
+			} else {
+				assignee := uint(id)
+				task.AssignedTo = &assignee
+			}
+		case models.ImportFieldStartDate:
+			task.StartDate, err = parseImportDate(value)
+		case models.ImportFieldDueDate:
+			task.DueDate, err = parseImportDate(value)
+		case models.ImportFieldEstimateHours:
+			var hours float64
+			if hours, err = strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64); err != nil {
+				err = fmt.Errorf("invalid estimate %q", value)
+			} else {
+				task.EstimateHours = &hours
+			}
+		case models.ImportFieldStoryPoints:
+			var points int
+			if points, err = strconv.Atoi(value); err != nil {
+				err = fmt.Errorf("invalid story points %q", value)
+			} else {
+				task.StoryPoints = &points
+			}
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+
+	task.SetStatus(status, now)
+	if len(result.Errors) == 0 {
+		// Dates in the past are fine here, migrated tasks are often overdue or done
+		var fieldErrs validation.Errors
+		if err := validation.Struct(task); errors.As(err, &fieldErrs) {
+			for _, fieldErr := range fieldErrs {
+				result.Errors = append(result.Errors, fieldErr.Message)
+			}
+		} else if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else if err := validateTaskDates(task.StartDate, task.DueDate); err != nil {
+			result.Errors = append(result.Errors, strings.TrimPrefix(err.Error(), "validation failed: "))
+		}
+	}
+
+	return &importedTask{result: result, task: task}
+}
+
+// checkImportAssignees verifies assignees of all valid rows at once, marking rows with unknown users
+func (u *taskUsecase) checkImportAssignees(imported []*importedTask) error {
+	var ids []uint
+	for _, item := range imported {
+		if len(item.result.Errors) == 0 && item.task.AssignedTo != nil {
+			ids = append(ids, *item.task.AssignedTo)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := u.userRefs.CheckUsers(ids...)
+	var missing *refs.MissingError
+	if err == nil || !errors.As(err, &missing) {
+		return err
+	}
+
+	unknown := make(map[uint]bool, len(missing.IDs))
+	for _, id := range missing.IDs {
+		unknown[id] = true
+	}
+	for _, item := range imported {
+		if len(item.result.Errors) == 0 && item.task.AssignedTo != nil && unknown[*item.task.AssignedTo] {
+			item.result.Errors = append(item.result.Errors, fmt.Sprintf("unknown assignee %d", *item.task.AssignedTo))
+		}
+	}
+	return nil
+}
+
+// parseImportDate parses a date cell as text or an Excel serial date
+func parseImportDate(value string) (*time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date, nil
+		}
+	}
+	if date, ok := spreadsheet.ExcelDate(value); ok {
+		return &date, nil
+	}
+	return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+}
+
+// normalizeImportHeader lowercases a header or enum cell and joins its words with underscores
+func normalizeImportHeader(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "_")
+}
+
+// isImportField checks if the name is an importable task field
+func isImportField(name string) bool {
+	for _, field := range models.ImportFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlankRow checks if all cells of a row are empty
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
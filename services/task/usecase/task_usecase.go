@@ -8,6 +8,11 @@ import (
 
 	"tachyon-messenger/services/task/models"
 	"tachyon-messenger/services/task/repository"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/query"
+	"tachyon-messenger/shared/refs"
+	"tachyon-messenger/shared/undo"
+	"tachyon-messenger/shared/validation"
 
 	"gorm.io/gorm"
 )
@@ -17,32 +22,85 @@ type TaskUsecase interface {
 	CreateTask(userID uint, req *models.CreateTaskRequest) (*models.TaskResponse, error)
 	GetTaskByID(userID, taskID uint) (*models.TaskResponse, error)
 	UpdateTask(userID, taskID uint, req *models.UpdateTaskRequest) (*models.TaskResponse, error)
-	DeleteTask(userID, taskID uint) error
+	DeleteTask(userID, taskID uint) (*undo.Receipt, error)
 	AssignTask(userID, taskID uint, req *models.AssignTaskRequest) (*models.TaskResponse, error)
 	UnassignTask(userID, taskID uint) (*models.TaskResponse, error)
 	UpdateTaskStatus(userID, taskID uint, req *models.UpdateTaskStatusRequest) (*models.TaskResponse, error)
 	GetUserTasks(userID uint, filter *models.TaskFilterRequest) ([]*models.TaskResponse, int64, error)
 	GetTaskStats(userID uint) (*models.TaskStatsResponse, error)
+	SuggestAssignees(req *models.AssigneeSuggestionRequest) ([]*models.AssigneeSuggestion, error)
+	GetWorkloadReport(req *models.WorkloadReportRequest) (*models.WorkloadReport, error)
+
+	// Spreadsheet import and export
+	ImportTasks(userID uint, req *models.ImportTasksRequest) (*models.TaskImportReport, error)
+	ExportTasks(userID uint, filter *models.TaskFilterRequest, write func(*models.TaskResponse) error) error
+
+	// Dependency methods
+	GetDependencies(userID, taskID uint) (*models.TaskDependenciesResponse, error)
+	AddDependency(userID, taskID uint, req *models.AddTaskDependencyRequest) (*models.TaskDependenciesResponse, error)
+	RemoveDependency(userID, taskID, dependsOnID uint) error
+
+	// Trash methods
+	GetDeletedTasks(userID uint, filter *models.TaskFilterRequest) ([]*models.TaskResponse, int64, error)
+	RestoreTask(userID, taskID uint) (*models.TaskResponse, error)
+	PurgeDeletedTasks(retention time.Duration) (int64, error)
 
 	// Comment methods
 	AddComment(userID, taskID uint, req *models.CreateTaskCommentRequest) (*models.TaskCommentResponse, error)
 	GetTaskComments(userID, taskID uint, filter *models.CommentFilterRequest) (*models.CommentListResponse, error)
 	UpdateComment(userID, commentID uint, req *models.UpdateTaskCommentRequest) (*models.TaskCommentResponse, error)
 	DeleteComment(userID, commentID uint) error
+	AddCommentReaction(userID, commentID uint, req *sharedmodels.CommentReactionRequest) ([]sharedmodels.ReactionSummary, error)
+	RemoveCommentReaction(userID, commentID uint, emoji string) ([]sharedmodels.ReactionSummary, error)
+
+	// Account merge
+	MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error)
+
+	// Daily digest
+	GetDigestStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.TasksDigestStats, error)
 }
 
 // taskUsecase implements TaskUsecase interface
 type taskUsecase struct {
 	taskRepo    repository.TaskRepository
 	commentRepo repository.CommentRepository
+	userRefs    *refs.Validator
+	skills      SkillDirectory      // nil disables assignee suggestions
+	departments DepartmentDirectory // nil disables department workload reports
+	undo        *undo.Manager
+
+	workloadThreshold models.WorkloadThreshold
 }
 
-// NewTaskUsecase creates a new task usecase
-func NewTaskUsecase(taskRepo repository.TaskRepository, commentRepo repository.CommentRepository) TaskUsecase {
-	return &taskUsecase{
-		taskRepo:    taskRepo,
-		commentRepo: commentRepo,
-	}
+// ActionDeleteTask is the undoable action kind of task deletion
+const ActionDeleteTask = "delete_task"
+
+// NewTaskUsecase creates a new task usecase and registers its undoable actions with undoManager.
+// userRefs may be nil to store assignee IDs unchecked, skills may be nil to disable assignee suggestions
+// and departments may be nil to report workload of user lists only.
+func NewTaskUsecase(
+	taskRepo repository.TaskRepository,
+	commentRepo repository.CommentRepository,
+	userRefs *refs.Validator,
+	skills SkillDirectory,
+	departments DepartmentDirectory,
+	undoManager *undo.Manager,
+	workloadThreshold models.WorkloadThreshold,
+) TaskUsecase {
+	u := &taskUsecase{
+		taskRepo:          taskRepo,
+		commentRepo:       commentRepo,
+		userRefs:          userRefs,
+		skills:            skills,
+		departments:       departments,
+		undo:              undoManager,
+		workloadThreshold: workloadThreshold,
+	}
+	// A deleted task stays in trash, so there is nothing left to commit when the window elapses
+	undoManager.Register(ActionDeleteTask, undo.Operation{
+		Revert: u.revertTaskDeletion,
+	})
+	return u
 }
 
 // CreateTask creates a new task
@@ -51,13 +109,19 @@ func (u *taskUsecase) CreateTask(userID uint, req *models.CreateTaskRequest) (*m
 	if err := u.validateCreateTaskRequest(req); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := validateTaskDates(req.StartDate, req.DueDate); err != nil {
+		return nil, err
+	}
 
 	// Create task model
 	task := &models.Task{
-		Title:       strings.TrimSpace(req.Title),
-		Description: strings.TrimSpace(req.Description),
-		CreatedBy:   userID,
-		DueDate:     req.DueDate,
+		Title:         strings.TrimSpace(req.Title),
+		Description:   strings.TrimSpace(req.Description),
+		CreatedBy:     userID,
+		StartDate:     req.StartDate,
+		DueDate:       req.DueDate,
+		EstimateHours: req.EstimateHours,
+		StoryPoints:   req.StoryPoints,
 	}
 
 	// Set priority (default to medium if not provided)
@@ -69,6 +133,9 @@ func (u *taskUsecase) CreateTask(userID uint, req *models.CreateTaskRequest) (*m
 
 	// Set assigned user if provided
 	if req.AssignedTo != nil {
+		if err := u.userRefs.CheckUsers(*req.AssignedTo); err != nil {
+			return nil, err
+		}
 		task.AssignedTo = req.AssignedTo
 	}
 
@@ -127,17 +194,37 @@ func (u *taskUsecase) UpdateTask(userID, taskID uint, req *models.UpdateTaskRequ
 		task.Description = strings.TrimSpace(*req.Description)
 	}
 	if req.Status != nil {
-		task.Status = *req.Status
+		task.SetStatus(*req.Status, time.Now())
 	}
 	if req.Priority != nil {
 		task.Priority = *req.Priority
 	}
 	if req.AssignedTo != nil {
+		if err := u.userRefs.CheckUsers(*req.AssignedTo); err != nil {
+			return nil, err
+		}
 		task.AssignedTo = req.AssignedTo
 	}
+	if req.StartDate != nil {
+		task.StartDate = req.StartDate
+	}
 	if req.DueDate != nil {
 		task.DueDate = req.DueDate
 	}
+	if err := validateTaskDates(task.StartDate, task.DueDate); err != nil {
+		return nil, err
+	}
+	if req.EstimateHours != nil {
+		task.EstimateHours = req.EstimateHours
+	}
+	if req.StoryPoints != nil {
+		task.StoryPoints = req.StoryPoints
+	}
+
+	// Changes based on a stale version are rejected on save
+	if req.Version != nil {
+		task.Version = *req.Version
+	}
 
 	// Save updated task
 	if err := u.taskRepo.Update(task); err != nil {
@@ -147,28 +234,126 @@ func (u *taskUsecase) UpdateTask(userID, taskID uint, req *models.UpdateTaskRequ
 	return task.ToResponse(), nil
 }
 
-// DeleteTask deletes a task
-func (u *taskUsecase) DeleteTask(userID, taskID uint) error {
+// DeleteTask moves a task to trash and stages the deletion, which can be undone with the returned receipt
+func (u *taskUsecase) DeleteTask(userID, taskID uint) (*undo.Receipt, error) {
 	// Get existing task
 	task, err := u.taskRepo.GetByID(taskID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
-			return fmt.Errorf("task not found")
+			return nil, fmt.Errorf("task not found")
 		}
-		return fmt.Errorf("failed to get task: %w", err)
+		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
 	// Check permissions: only creator can delete
 	if task.CreatedBy != userID {
-		return fmt.Errorf("access denied: only task creator can delete the task")
+		return nil, fmt.Errorf("access denied: only task creator can delete the task")
 	}
 
 	// Delete task
 	if err := u.taskRepo.Delete(taskID); err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+		return nil, fmt.Errorf("failed to delete task: %w", err)
 	}
 
-	return nil
+	action, err := u.undo.Stage(userID, ActionDeleteTask, taskID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete task: %w", err)
+	}
+	if action == nil {
+		return nil, nil
+	}
+	return action.Receipt(), nil
+}
+
+// revertTaskDeletion restores a task deleted within the undo window
+func (u *taskUsecase) revertTaskDeletion(action *undo.PendingAction) error {
+	return u.taskRepo.Restore(action.ResourceID)
+}
+
+// GetDeletedTasks retrieves tasks deleted by the user that can still be restored
+func (u *taskUsecase) GetDeletedTasks(userID uint, filter *models.TaskFilterRequest) ([]*models.TaskResponse, int64, error) {
+	if filter == nil {
+		filter = &models.TaskFilterRequest{}
+	}
+	if filter.Page == nil {
+		filter.Page = query.Default(models.TaskTrashListOptions)
+	}
+
+	tasks, total, err := u.taskRepo.GetDeletedTasks(userID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted tasks: %w", err)
+	}
+
+	responses := make([]*models.TaskResponse, len(tasks))
+	for i, task := range tasks {
+		responses[i] = task.ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+// RestoreTask restores a deleted task from trash
+func (u *taskUsecase) RestoreTask(userID, taskID uint) (*models.TaskResponse, error) {
+	task, err := u.taskRepo.GetDeletedByID(taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("task not found")
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	// Check permissions: only creator can restore, as only creator can delete
+	if task.CreatedBy != userID {
+		return nil, fmt.Errorf("access denied: only task creator can restore the task")
+	}
+
+	if err := u.taskRepo.Restore(taskID); err != nil {
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	restored, err := u.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored task: %w", err)
+	}
+
+	return restored.ToResponse(), nil
+}
+
+// PurgeDeletedTasks permanently deletes tasks that stayed in trash longer than retention
+func (u *taskUsecase) PurgeDeletedTasks(retention time.Duration) (int64, error) {
+	purged, err := u.taskRepo.PurgeDeleted(time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+	return purged, nil
+}
+
+// MergeUsers moves tasks, comments and reactions of a duplicate account to the primary account
+func (u *taskUsecase) MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error) {
+	if req.PrimaryUserID == req.DuplicateUserID {
+		return nil, fmt.Errorf("validation failed: cannot merge user into itself")
+	}
+
+	result, err := u.taskRepo.MergeUsers(req.PrimaryUserID, req.DuplicateUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge users: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetDigestStats counts tasks of a user for the daily digest of the notification service.
+// Upcoming deadlines are those due by the end of tomorrow in the user's timezone.
+func (u *taskUsecase) GetDigestStats(req *sharedmodels.DigestStatsRequest) (*sharedmodels.TasksDigestStats, error) {
+	if req.UserID == 0 {
+		return nil, fmt.Errorf("validation failed: user_id is required")
+	}
+
+	stats, err := u.taskRepo.GetDigestStats(req.UserID, req.Since, time.Now(), req.DayStart.AddDate(0, 0, 2))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest stats: %w", err)
+	}
+	return stats, nil
 }
 
 // AssignTask assigns a task to a user
@@ -192,6 +377,10 @@ func (u *taskUsecase) AssignTask(userID, taskID uint, req *models.AssignTaskRequ
 		return nil, fmt.Errorf("access denied: insufficient permissions")
 	}
 
+	if err := u.userRefs.CheckUsers(req.AssignedTo); err != nil {
+		return nil, err
+	}
+
 	// Assign task
 	task.AssignedTo = &req.AssignedTo
 
@@ -252,7 +441,7 @@ func (u *taskUsecase) UpdateTaskStatus(userID, taskID uint, req *models.UpdateTa
 	}
 
 	// Update status
-	task.Status = req.Status
+	task.SetStatus(req.Status, time.Now())
 
 	// Save updated task
 	if err := u.taskRepo.Update(task); err != nil {
@@ -266,17 +455,10 @@ func (u *taskUsecase) UpdateTaskStatus(userID, taskID uint, req *models.UpdateTa
 func (u *taskUsecase) GetUserTasks(userID uint, filter *models.TaskFilterRequest) ([]*models.TaskResponse, int64, error) {
 	// Set default pagination if not provided
 	if filter == nil {
-		filter = &models.TaskFilterRequest{
-			Limit:  20,
-			Offset: 0,
-		}
+		filter = &models.TaskFilterRequest{}
 	}
-
-	if filter.Limit <= 0 {
-		filter.Limit = 20
-	}
-	if filter.Limit > 100 {
-		filter.Limit = 100
+	if filter.Page == nil {
+		filter.Page = query.Default(models.TaskListOptions)
 	}
 
 	// Get tasks from repository
@@ -323,47 +505,20 @@ func (u *taskUsecase) hasTaskAccess(userID uint, task *models.Task) bool {
 
 // Validation methods
 
+// validateTaskDates checks that a task isn't due before it starts
+func validateTaskDates(startDate, dueDate *time.Time) error {
+	if startDate != nil && dueDate != nil && dueDate.Before(*startDate) {
+		return fmt.Errorf("validation failed: due date must not be before start date")
+	}
+	return nil
+}
+
 // validateCreateTaskRequest validates task creation request
 func (u *taskUsecase) validateCreateTaskRequest(req *models.CreateTaskRequest) error {
 	if req == nil {
 		return fmt.Errorf("request is required")
 	}
-
-	// Validate title
-	title := strings.TrimSpace(req.Title)
-	if title == "" {
-		return fmt.Errorf("task title is required")
-	}
-	if len(title) > 255 {
-		return fmt.Errorf("task title must be less than 255 characters")
-	}
-
-	// Validate description if provided
-	if req.Description != "" {
-		description := strings.TrimSpace(req.Description)
-		if len(description) > 2000 {
-			return fmt.Errorf("task description must be less than 2000 characters")
-		}
-	}
-
-	// Validate priority if provided
-	if req.Priority != nil {
-		if !u.isValidPriority(*req.Priority) {
-			return fmt.Errorf("invalid priority value")
-		}
-	}
-
-	// Validate assignee if provided
-	if req.AssignedTo != nil && *req.AssignedTo == 0 {
-		return fmt.Errorf("invalid assignee ID")
-	}
-
-	// Validate due date if provided
-	if req.DueDate != nil && req.DueDate.Before(time.Now()) {
-		return fmt.Errorf("due date cannot be in the past")
-	}
-
-	return nil
+	return validation.Struct(req)
 }
 
 // validateUpdateTaskRequest validates task update request
@@ -371,46 +526,7 @@ func (u *taskUsecase) validateUpdateTaskRequest(req *models.UpdateTaskRequest) e
 	if req == nil {
 		return fmt.Errorf("request is required")
 	}
-
-	// Validate title if provided
-	if req.Title != nil {
-		title := strings.TrimSpace(*req.Title)
-		if title == "" {
-			return fmt.Errorf("task title cannot be empty")
-		}
-		if len(title) > 255 {
-			return fmt.Errorf("task title must be less than 255 characters")
-		}
-	}
-
-	// Validate description if provided
-	if req.Description != nil {
-		description := strings.TrimSpace(*req.Description)
-		if len(description) > 2000 {
-			return fmt.Errorf("task description must be less than 2000 characters")
-		}
-	}
-
-	// Validate status if provided
-	if req.Status != nil {
-		if !u.isValidStatus(*req.Status) {
-			return fmt.Errorf("invalid status value")
-		}
-	}
-
-	// Validate priority if provided
-	if req.Priority != nil {
-		if !u.isValidPriority(*req.Priority) {
-			return fmt.Errorf("invalid priority value")
-		}
-	}
-
-	// Validate assignee if provided
-	if req.AssignedTo != nil && *req.AssignedTo == 0 {
-		return fmt.Errorf("invalid assignee ID")
-	}
-
-	return nil
+	return validation.Struct(req)
 }
 
 // validateUpdateTaskStatusRequest validates task status update request
@@ -418,12 +534,7 @@ func (u *taskUsecase) validateUpdateTaskStatusRequest(req *models.UpdateTaskStat
 	if req == nil {
 		return fmt.Errorf("request is required")
 	}
-
-	if !u.isValidStatus(req.Status) {
-		return fmt.Errorf("invalid status value")
-	}
-
-	return nil
+	return validation.Struct(req)
 }
 
 // validateAssignTaskRequest validates task assignment request
@@ -431,32 +542,5 @@ func (u *taskUsecase) validateAssignTaskRequest(req *models.AssignTaskRequest) e
 	if req == nil {
 		return fmt.Errorf("request is required")
 	}
-
-	if req.AssignedTo == 0 {
-		return fmt.Errorf("assignee ID is required")
-	}
-
-	return nil
-}
-
-// isValidStatus checks if the status is valid
-func (u *taskUsecase) isValidStatus(status models.TaskStatus) bool {
-	switch status {
-	case models.TaskStatusNew, models.TaskStatusInProgress, models.TaskStatusReview,
-		models.TaskStatusDone, models.TaskStatusCancelled:
-		return true
-	default:
-		return false
-	}
-}
-
-// isValidPriority checks if the priority is valid
-func (u *taskUsecase) isValidPriority(priority models.TaskPriority) bool {
-	switch priority {
-	case models.TaskPriorityLow, models.TaskPriorityMedium,
-		models.TaskPriorityHigh, models.TaskPriorityCritical:
-		return true
-	default:
-		return false
-	}
+	return validation.Struct(req)
 }
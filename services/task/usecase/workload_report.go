@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/task/models"
+)
+
+const (
+	// defaultUpcomingDays is how far ahead due dates are listed in the workload report
+	defaultUpcomingDays = 7
+
+	// maxUpcomingPerUser limits due dates listed for each assignee
+	maxUpcomingPerUser = 5
+)
+
+// workloadPriorities lists task priorities reported for every assignee, including empty ones
+var workloadPriorities = []models.TaskPriority{
+	models.TaskPriorityLow,
+	models.TaskPriorityMedium,
+	models.TaskPriorityHigh,
+	models.TaskPriorityCritical,
+}
+
+// GetWorkloadReport reports open tasks of each user of a department or of a list of users:
+// their number by priority, total estimate, overdue tasks and upcoming due dates. Users above
+// the workload threshold get warnings.
+func (u *taskUsecase) GetWorkloadReport(req *models.WorkloadReportRequest) (*models.WorkloadReport, error) {
+	var userIDs []uint
+	switch {
+	case req.DepartmentID != nil:
+		if u.departments == nil {
+			return nil, fmt.Errorf("department workload is not available")
+		}
+		members, err := u.departments.Members(*req.DepartmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get department members: %w", err)
+		}
+		userIDs = members
+	case len(req.UserIDs) > 0:
+		userIDs = uniqueIDs(req.UserIDs)
+	default:
+		return nil, fmt.Errorf("validation failed: department_id or user_ids is required")
+	}
+
+	upcomingDays := req.UpcomingDays
+	if upcomingDays <= 0 {
+		upcomingDays = defaultUpcomingDays
+	}
+
+	now := time.Now()
+	report := &models.WorkloadReport{
+		DepartmentID:  req.DepartmentID,
+		Threshold:     u.workloadThreshold,
+		UpcomingUntil: now.AddDate(0, 0, upcomingDays),
+		Users:         make([]*models.UserWorkload, 0, len(userIDs)),
+		GeneratedAt:   now,
+	}
+
+	load, err := u.taskRepo.GetAssigneeLoad(userIDs, now)
+	if err != nil {
+		return nil, err
+	}
+	upcoming, err := u.taskRepo.GetUpcomingDue(userIDs, now, report.UpcomingUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uint]*models.UserWorkload, len(userIDs))
+	for _, userID := range userIDs {
+		workload := &models.UserWorkload{
+			UserID:     userID,
+			ByPriority: make(map[models.TaskPriority]int64, len(workloadPriorities)),
+			Upcoming:   []*models.WorkloadDueTask{},
+			Warnings:   []string{},
+		}
+		for _, priority := range workloadPriorities {
+			workload.ByPriority[priority] = 0
+		}
+		byUser[userID] = workload
+		report.Users = append(report.Users, workload)
+	}
+
+	for _, row := range load {
+		workload, exists := byUser[row.UserID]
+		if !exists {
+			continue
+		}
+		workload.OpenTasks += row.OpenTasks
+		workload.ByPriority[row.Priority] += row.OpenTasks
+		workload.EstimateHours += row.EstimateHours
+		workload.Unestimated += row.Unestimated
+		workload.Overdue += row.Overdue
+	}
+
+	for _, task := range upcoming {
+		if workload, exists := byUser[task.AssignedTo]; exists && len(workload.Upcoming) < maxUpcomingPerUser {
+			workload.Upcoming = append(workload.Upcoming, task)
+		}
+	}
+
+	for _, workload := range report.Users {
+		workload.Warnings = workloadWarnings(workload, u.workloadThreshold)
+		if len(workload.Warnings) > 0 {
+			report.Overloaded++
+		}
+	}
+
+	return report, nil
+}
+
+// workloadWarnings lists the thresholds the workload exceeds
+func workloadWarnings(workload *models.UserWorkload, threshold models.WorkloadThreshold) []string {
+	warnings := []string{}
+	if threshold.OpenTasks > 0 && workload.OpenTasks > threshold.OpenTasks {
+		warnings = append(warnings, models.WorkloadWarningOpenTasks)
+	}
+	if threshold.EstimateHours > 0 && workload.EstimateHours > threshold.EstimateHours {
+		warnings = append(warnings, models.WorkloadWarningEstimate)
+	}
+	return warnings
+}
+
+// uniqueIDs drops duplicate IDs, keeping their order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
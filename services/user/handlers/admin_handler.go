@@ -7,8 +7,10 @@ import (
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -16,15 +18,17 @@ import (
 
 // AdminHandler handles HTTP requests for admin operations
 type AdminHandler struct {
-	adminUsecase usecase.AdminUsecase
-	userUsecase  usecase.UserUsecase
+	adminUsecase    usecase.AdminUsecase
+	userUsecase     usecase.UserUsecase
+	securityUsecase usecase.SecurityUsecase
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(adminUsecase usecase.AdminUsecase, userUsecase usecase.UserUsecase) *AdminHandler {
+func NewAdminHandler(adminUsecase usecase.AdminUsecase, userUsecase usecase.UserUsecase, securityUsecase usecase.SecurityUsecase) *AdminHandler {
 	return &AdminHandler{
-		adminUsecase: adminUsecase,
-		userUsecase:  userUsecase,
+		adminUsecase:    adminUsecase,
+		userUsecase:     userUsecase,
+		securityUsecase: securityUsecase,
 	}
 }
 
@@ -32,40 +36,42 @@ func NewAdminHandler(adminUsecase usecase.AdminUsecase, userUsecase usecase.User
 func (h *AdminHandler) GetUsers(c *gin.Context) {
 	requestID := requestid.Get(c)
 
-	// Parse pagination parameters
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100 // Maximum limit
+	viewer, ok := getUserViewer(c, requestID)
+	if !ok {
+		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	var req models.UserListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for admin get users")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
 	}
 
 	// Get filter parameters
 	status := c.Query("status")
 	role := c.Query("role")
-	departmentID := c.Query("department_id")
 	isActive := c.Query("is_active")
 
 	logger.WithFields(map[string]interface{}{
 		"request_id":    requestID,
-		"limit":         limit,
-		"offset":        offset,
+		"limit":         req.Limit,
+		"offset":        req.Offset,
 		"status":        status,
 		"role":          role,
-		"department_id": departmentID,
+		"department_id": req.DepartmentID,
 		"is_active":     isActive,
 	}).Info("Admin getting users list")
 
-	users, total, err := h.userUsecase.GetUsers(limit, offset)
+	users, total, err := h.userUsecase.GetUsers(viewer, &req)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -88,8 +94,8 @@ func (h *AdminHandler) GetUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"users":      users,
 		"total":      total,
-		"limit":      limit,
-		"offset":     offset,
+		"limit":      req.Limit,
+		"offset":     req.Offset,
 		"request_id": requestID,
 	})
 }
@@ -122,8 +128,8 @@ func (h *AdminHandler) CreateUser(c *gin.Context) {
 		}).Warn("Invalid request body for admin create user")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -261,8 +267,8 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 		}).Warn("Invalid request body for admin update user")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -401,8 +407,8 @@ func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
 		}).Warn("Invalid request body for update user role")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -443,6 +449,10 @@ func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
 		"new_role":   req.Role,
 	}).Info("User role updated successfully by admin")
 
+	if h.securityUsecase != nil {
+		h.securityUsecase.RecordRoleChanged(uint(id), adminID, clientInfo(c))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "User role updated successfully",
 		"user":       user,
@@ -497,8 +507,8 @@ func (h *AdminHandler) UpdateUserStatus(c *gin.Context) {
 		}).Warn("Invalid request body for update user status")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles HTTP requests for API keys of the public API
+type APIKeyHandler struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyUsecase usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+// CreateKey handles issuing an API key (admin only)
+// POST /admin/api-keys
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, ok := getOrgSettingsAdminID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.apiKeyUsecase.CreateKey(adminID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"error":      err.Error(),
+		}).Error("Failed to create API key")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to create API key"
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		case strings.Contains(err.Error(), "validation failed"):
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"admin_id":   adminID,
+		"key_id":     result.APIKey.ID,
+		"user_id":    result.APIKey.UserID,
+		"scopes":     result.APIKey.Scopes,
+	}).Info("API key created")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "API key created, store the key now: it is not shown again",
+		"api_key":    result.APIKey,
+		"key":        result.Key,
+		"request_id": requestID,
+	})
+}
+
+// ListKeys handles listing API keys (admin only)
+// GET /admin/api-keys?include_revoked=true
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	includeRevoked, _ := strconv.ParseBool(c.Query("include_revoked"))
+
+	keys, err := h.apiKeyUsecase.ListKeys(includeRevoked)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to list API keys")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to list API keys",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys":   keys,
+		"total":      len(keys),
+		"request_id": requestID,
+	})
+}
+
+// RevokeKey handles revoking an API key (admin only)
+// DELETE /admin/api-keys/:id
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid API key ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.apiKeyUsecase.RevokeKey(uint(id)); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"key_id":     id,
+			"error":      err.Error(),
+		}).Error("Failed to revoke API key")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to revoke API key"
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+			errorMessage = "API key not found"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "API key revoked successfully",
+		"request_id": requestID,
+	})
+}
+
+// GetUsageReport handles getting the monthly usage of API keys (admin only)
+// GET /admin/api-keys/usage?month=YYYY-MM
+func (h *APIKeyHandler) GetUsageReport(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	report, err := h.apiKeyUsecase.GetUsageReport(c.Query("month"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get API key usage"
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		} else {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"error":      err.Error(),
+			}).Error("Failed to get API key usage")
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report":     report,
+		"request_id": requestID,
+	})
+}
+
+// VerifyKey handles checking an API key for the gateway (internal)
+// POST /api/v1/internal/api-keys/verify
+func (h *APIKeyHandler) VerifyKey(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.VerifyAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	identity, err := h.apiKeyUsecase.VerifyKey(req.Key)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to verify API key"
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+			errorMessage = "API key not found"
+		case strings.Contains(err.Error(), "access denied"):
+			statusCode = http.StatusForbidden
+			errorMessage = err.Error()
+		default:
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"error":      err.Error(),
+			}).Error("Failed to verify API key")
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"identity":   identity,
+		"request_id": requestID,
+	})
+}
+
+// RecordUsage handles usage counters reported by the gateway (internal)
+// POST /api/v1/internal/api-keys/usage
+func (h *APIKeyHandler) RecordUsage(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.RecordAPIKeyUsageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	if err := h.apiKeyUsecase.RecordUsage(req.Entries); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"entries":    len(req.Entries),
+			"error":      err.Error(),
+		}).Error("Failed to record API key usage")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to record API key usage"
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recorded":   len(req.Entries),
+		"request_id": requestID,
+	})
+}
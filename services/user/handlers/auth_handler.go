@@ -6,7 +6,9 @@ import (
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -14,13 +16,18 @@ import (
 
 // AuthHandler handles HTTP requests for authentication
 type AuthHandler struct {
-	authUsecase usecase.AuthUsecase
+	authUsecase       usecase.AuthUsecase
+	securityUsecase   usecase.SecurityUsecase
+	onboardingUsecase usecase.OnboardingUsecase
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(authUsecase usecase.AuthUsecase) *AuthHandler {
+// NewAuthHandler creates a new auth handler. A nil security usecase disables login device tracking,
+// a nil onboarding usecase disables onboarding of registered users.
+func NewAuthHandler(authUsecase usecase.AuthUsecase, securityUsecase usecase.SecurityUsecase, onboardingUsecase usecase.OnboardingUsecase) *AuthHandler {
 	return &AuthHandler{
-		authUsecase: authUsecase,
+		authUsecase:       authUsecase,
+		securityUsecase:   securityUsecase,
+		onboardingUsecase: onboardingUsecase,
 	}
 }
 
@@ -36,8 +43,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		}).Warn("Invalid request body for user registration")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -71,6 +78,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// Detect the language of new users from the browser, the organization default applies otherwise
+	if req.Locale == "" {
+		if locale, ok := i18n.Detect(c.GetHeader("Accept-Language")); ok {
+			req.Locale = locale
+		}
+	}
+
 	// Call usecase to register user
 	user, err := h.authUsecase.Register(&req)
 	if err != nil {
@@ -108,6 +122,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		"email":      user.Email,
 	}).Info("User registered successfully")
 
+	if h.onboardingUsecase != nil {
+		h.onboardingUsecase.Start(user.ID)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message":    "User registered successfully",
 		"user":       user,
@@ -131,8 +149,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		}).Warn("Invalid request body for user login")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -199,6 +217,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		"email":      loginResponse.User.Email,
 	}).Info("User logged in successfully")
 
+	if h.securityUsecase != nil {
+		h.securityUsecase.RecordLogin(loginResponse.User.ID, clientInfo(c))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Login successful",
 		"user":       loginResponse.User,
@@ -7,7 +7,9 @@ import (
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -122,8 +124,8 @@ func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
 		}).Warn("Invalid request body for create department")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -197,8 +199,8 @@ func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
 		}).Warn("Invalid request body for update department")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -300,6 +302,83 @@ func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
 	})
 }
 
+// MergeDepartment handles merging a department into another one
+func (h *DepartmentHandler) MergeDepartment(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":    requestID,
+			"department_id": idStr,
+			"error":         err.Error(),
+		}).Warn("Invalid department ID")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid department ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.MergeDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":    requestID,
+			"department_id": id,
+			"error":         err.Error(),
+		}).Warn("Invalid request body for merge department")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.departmentUsecase.MergeDepartment(uint(id), &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":           requestID,
+			"department_id":        id,
+			"target_department_id": req.TargetDepartmentID,
+			"error":                err.Error(),
+		}).Error("Failed to merge department")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to merge department"
+
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		} else if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+			errorMessage = err.Error()
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":           requestID,
+		"department_id":        id,
+		"target_department_id": req.TargetDepartmentID,
+		"moved_users":          result.MovedUsers,
+	}).Info("Department merged successfully")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Department merged successfully",
+		"result":     result,
+		"request_id": requestID,
+	})
+}
+
 // GetDepartmentWithUsers handles getting a department with its users
 func (h *DepartmentHandler) GetDepartmentWithUsers(c *gin.Context) {
 	requestID := requestid.Get(c)
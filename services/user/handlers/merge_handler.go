@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// MergeUsers handles merging a duplicate account into the primary account (admin only)
+// POST /admin/users/merge
+func (h *AdminHandler) MergeUsers(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	// Get admin user info from context
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get admin ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Admin not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for merge users")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	merge, err := h.adminUsecase.MergeUsers(adminID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id":        requestID,
+			"admin_id":          adminID,
+			"primary_user_id":   req.PrimaryUserID,
+			"duplicate_user_id": req.DuplicateUserID,
+			"error":             err.Error(),
+		}).Error("Failed to merge users")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to merge users"
+
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+			errorMessage = err.Error()
+		} else if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		} else if strings.Contains(err.Error(), "cannot") {
+			statusCode = http.StatusConflict
+			errorMessage = err.Error()
+		} else if merge != nil {
+			// Some services failed to move data, report what was done so the merge can be retried
+			statusCode = http.StatusBadGateway
+		}
+
+		response := gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		}
+		if merge != nil {
+			response["merge"] = merge
+		}
+		c.JSON(statusCode, response)
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":        requestID,
+		"admin_id":          adminID,
+		"merge_id":          merge.ID,
+		"primary_user_id":   merge.PrimaryUserID,
+		"duplicate_user_id": merge.DuplicateUserID,
+	}).Info("Users merged successfully by admin")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Users merged successfully",
+		"merge":      merge,
+		"request_id": requestID,
+	})
+}
+
+// GetUserMerges handles getting the account merge audit log (admin only)
+// GET /admin/users/merges
+func (h *AdminHandler) GetUserMerges(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	merges, total, err := h.adminUsecase.GetUserMerges(limit, offset)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user merges")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get user merges",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"merges":     merges,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+		"request_id": requestID,
+	})
+}
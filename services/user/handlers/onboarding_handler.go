@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// OnboardingHandler handles HTTP requests for the onboarding of new users
+type OnboardingHandler struct {
+	onboardingUsecase usecase.OnboardingUsecase
+}
+
+// NewOnboardingHandler creates a new onboarding handler
+func NewOnboardingHandler(onboardingUsecase usecase.OnboardingUsecase) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboardingUsecase: onboardingUsecase,
+	}
+}
+
+// GetPlan handles getting the onboarding plan (admin only)
+// GET /admin/settings/onboarding
+func (h *OnboardingHandler) GetPlan(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	plan, err := h.onboardingUsecase.GetPlan()
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get onboarding plan")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get onboarding plan",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"plan":       plan,
+		"request_id": requestID,
+	})
+}
+
+// UpdatePlan handles replacing the onboarding plan (admin only)
+// PUT /admin/settings/onboarding
+func (h *OnboardingHandler) UpdatePlan(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, ok := getOrgSettingsAdminID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.OnboardingPlan
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update onboarding plan")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	plan, err := h.onboardingUsecase.UpdatePlan(adminID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"error":      err.Error(),
+		}).Error("Failed to update onboarding plan")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to update onboarding plan"
+
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"admin_id":   adminID,
+	}).Info("Onboarding plan updated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Onboarding plan updated successfully",
+		"plan":       plan,
+		"request_id": requestID,
+	})
+}
+
+// GetUserOnboarding handles getting the onboarding progress of a user (admin only)
+// GET /admin/users/:id/onboarding
+func (h *OnboardingHandler) GetUserOnboarding(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid user ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	steps, err := h.onboardingUsecase.GetUserOnboarding(uint(id))
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    id,
+			"error":      err.Error(),
+		}).Error("Failed to get user onboarding")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get onboarding progress"
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":    id,
+		"steps":      steps,
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// OrgSettingsHandler handles HTTP requests for organization settings
+type OrgSettingsHandler struct {
+	orgSettingsUsecase usecase.OrgSettingsUsecase
+}
+
+// NewOrgSettingsHandler creates a new organization settings handler
+func NewOrgSettingsHandler(orgSettingsUsecase usecase.OrgSettingsUsecase) *OrgSettingsHandler {
+	return &OrgSettingsHandler{
+		orgSettingsUsecase: orgSettingsUsecase,
+	}
+}
+
+// GetSettings handles getting organization settings
+// GET /admin/settings, GET /api/v1/internal/settings
+func (h *OrgSettingsHandler) GetSettings(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	settings, err := h.orgSettingsUsecase.GetSettings()
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get organization settings")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get organization settings",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings":   settings,
+		"request_id": requestID,
+	})
+}
+
+// UpdateSettings handles replacing organization settings (admin only)
+// PUT /admin/settings
+func (h *OrgSettingsHandler) UpdateSettings(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, ok := getOrgSettingsAdminID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req sharedmodels.OrgSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body for update organization settings")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	settings, err := h.orgSettingsUsecase.UpdateSettings(adminID, &req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"error":      err.Error(),
+		}).Error("Failed to update organization settings")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to update organization settings"
+
+		if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"admin_id":   adminID,
+	}).Info("Organization settings updated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Organization settings updated successfully",
+		"settings":   settings,
+		"request_id": requestID,
+	})
+}
+
+// ResetSettings handles restoring default organization settings (admin only)
+// DELETE /admin/settings
+func (h *OrgSettingsHandler) ResetSettings(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, ok := getOrgSettingsAdminID(c, requestID)
+	if !ok {
+		return
+	}
+
+	settings, err := h.orgSettingsUsecase.ResetSettings(adminID)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"error":      err.Error(),
+		}).Error("Failed to reset organization settings")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to reset organization settings",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"admin_id":   adminID,
+	}).Info("Organization settings reset to defaults")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Organization settings reset to defaults",
+		"settings":   settings,
+		"request_id": requestID,
+	})
+}
+
+// GetSettingsChanges handles getting the organization settings audit log (admin only)
+// GET /admin/settings/changes
+func (h *OrgSettingsHandler) GetSettingsChanges(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	changes, total, err := h.orgSettingsUsecase.GetSettingsChanges(limit, offset)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get organization settings changes")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get organization settings changes",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes":    changes,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+		"request_id": requestID,
+	})
+}
+
+// GetRetentionPolicies handles getting retention periods of data types removed by cleanup jobs (admin only)
+// GET /admin/settings/retention
+func (h *OrgSettingsHandler) GetRetentionPolicies(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	policies, err := h.orgSettingsUsecase.GetRetentionPolicies()
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get retention policies")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get retention policies",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policies":   policies,
+		"request_id": requestID,
+	})
+}
+
+// UpdateRetentionPolicy handles setting the retention period of a data type (admin only)
+// PUT /admin/settings/retention/:data_type
+func (h *OrgSettingsHandler) UpdateRetentionPolicy(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, ok := getOrgSettingsAdminID(c, requestID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	dataType := sharedmodels.RetentionDataType(c.Param("data_type"))
+	policy, err := h.orgSettingsUsecase.SetRetentionPolicy(adminID, dataType, req.Days)
+	if err != nil {
+		writeRetentionPolicyError(c, requestID, adminID, dataType, err, "Failed to update retention policy")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"admin_id":   adminID,
+		"data_type":  dataType,
+		"days":       policy.Days,
+	}).Info("Retention policy updated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Retention policy updated successfully",
+		"policy":     policy,
+		"request_id": requestID,
+	})
+}
+
+// ResetRetentionPolicy handles restoring the default retention period of a data type (admin only)
+// DELETE /admin/settings/retention/:data_type
+func (h *OrgSettingsHandler) ResetRetentionPolicy(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, ok := getOrgSettingsAdminID(c, requestID)
+	if !ok {
+		return
+	}
+
+	dataType := sharedmodels.RetentionDataType(c.Param("data_type"))
+	policy, err := h.orgSettingsUsecase.ResetRetentionPolicy(adminID, dataType)
+	if err != nil {
+		writeRetentionPolicyError(c, requestID, adminID, dataType, err, "Failed to reset retention policy")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"admin_id":   adminID,
+		"data_type":  dataType,
+	}).Info("Retention policy reset to default")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Retention policy reset to default",
+		"policy":     policy,
+		"request_id": requestID,
+	})
+}
+
+// writeRetentionPolicyError maps usecase errors of retention policies to HTTP responses
+func writeRetentionPolicyError(c *gin.Context, requestID string, adminID uint, dataType sharedmodels.RetentionDataType, err error, message string) {
+	statusCode := http.StatusInternalServerError
+	errorMessage := message
+
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = "Unknown retention data type"
+	default:
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"admin_id":   adminID,
+			"data_type":  dataType,
+			"error":      err.Error(),
+		}).Error(message)
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
+
+// getOrgSettingsAdminID returns the admin ID from context, writing an error response if it is missing
+func getOrgSettingsAdminID(c *gin.Context, requestID string) (uint, bool) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get admin ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "Admin not authenticated",
+			"request_id": requestID,
+		})
+		return 0, false
+	}
+	return adminID, true
+}
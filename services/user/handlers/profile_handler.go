@@ -7,8 +7,10 @@ import (
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -16,20 +18,27 @@ import (
 
 // ProfileHandler handles HTTP requests for profile operations
 type ProfileHandler struct {
-	profileUsecase usecase.ProfileUsecase
+	profileUsecase  usecase.ProfileUsecase
+	securityUsecase usecase.SecurityUsecase
 }
 
 // NewProfileHandler creates a new profile handler
-func NewProfileHandler(profileUsecase usecase.ProfileUsecase) *ProfileHandler {
+func NewProfileHandler(profileUsecase usecase.ProfileUsecase, securityUsecase usecase.SecurityUsecase) *ProfileHandler {
 	return &ProfileHandler{
-		profileUsecase: profileUsecase,
+		profileUsecase:  profileUsecase,
+		securityUsecase: securityUsecase,
 	}
 }
 
-// GetProfile handles getting user profile by ID
+// GetProfile handles getting user profile by ID, limited to what the requesting user may see
 func (h *ProfileHandler) GetProfile(c *gin.Context) {
 	requestID := requestid.Get(c)
 
+	viewer, ok := getUserViewer(c, requestID)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -46,7 +55,7 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	profile, err := h.profileUsecase.GetProfile(uint(id))
+	profile, err := h.profileUsecase.GetProfile(viewer, uint(id))
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -88,22 +97,13 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 func (h *ProfileHandler) GetMyProfile(c *gin.Context) {
 	requestID := requestid.Get(c)
 
-	// Extract user ID from JWT token
-	userID, err := middleware.GetUserIDFromContext(c)
-	if err != nil {
-		logger.WithFields(map[string]interface{}{
-			"request_id": requestID,
-			"error":      err.Error(),
-		}).Error("Failed to get user ID from context")
-
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":      "User not authenticated",
-			"request_id": requestID,
-		})
+	viewer, ok := getUserViewer(c, requestID)
+	if !ok {
 		return
 	}
+	userID := viewer.ID
 
-	profile, err := h.profileUsecase.GetProfile(userID)
+	profile, err := h.profileUsecase.GetProfile(viewer, userID)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -169,8 +169,8 @@ func (h *ProfileHandler) UpdateMyProfile(c *gin.Context) {
 		}).Warn("Invalid request body for update profile")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -249,8 +249,8 @@ func (h *ProfileHandler) ChangePassword(c *gin.Context) {
 		}).Warn("Invalid request body for change password")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -293,6 +293,10 @@ func (h *ProfileHandler) ChangePassword(c *gin.Context) {
 		"user_id":    userID,
 	}).Info("Password changed successfully")
 
+	if h.securityUsecase != nil {
+		h.securityUsecase.RecordPasswordChanged(userID, userID, clientInfo(c))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Password changed successfully",
 		"request_id": requestID,
@@ -327,8 +331,8 @@ func (h *ProfileHandler) UpdateStatus(c *gin.Context) {
 		}).Warn("Invalid request body for update status")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
-			"details":    err.Error(),
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
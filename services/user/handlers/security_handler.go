@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserSecurityEvents handles getting recent security events of a user (admin only)
+// GET /admin/users/:id/security-events
+func (h *AdminHandler) GetUserSecurityEvents(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid user ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	events, err := h.securityUsecase.GetUserSecurityEvents(uint(id), limit)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    id,
+			"error":      err.Error(),
+		}).Error("Failed to get user security events")
+
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get security events"
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+			errorMessage = "User not found"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":    id,
+		"events":     events,
+		"request_id": requestID,
+	})
+}
+
+// clientInfo returns the address and user agent of the request
+func clientInfo(c *gin.Context) *models.ClientInfo {
+	return &models.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// SkillHandler handles HTTP requests for user skills and the skill directory
+type SkillHandler struct {
+	skillUsecase usecase.SkillUsecase
+}
+
+// NewSkillHandler creates a new skill handler
+func NewSkillHandler(skillUsecase usecase.SkillUsecase) *SkillHandler {
+	return &SkillHandler{
+		skillUsecase: skillUsecase,
+	}
+}
+
+// GetMySkills handles getting skills of the current user
+// GET /api/v1/profile/skills
+func (h *SkillHandler) GetMySkills(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	skills, err := h.skillUsecase.GetUserSkills(userID)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get skills")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"skills":     skills,
+		"request_id": requestID,
+	})
+}
+
+// UpdateMySkills handles replacing self-declared skills of the current user
+// PUT /api/v1/profile/skills
+func (h *SkillHandler) UpdateMySkills(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.UpdateSkillsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	skills, err := h.skillUsecase.UpdateMySkills(userID, &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to update skills")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"user_id":    userID,
+		"skills":     skills.SelfDeclared,
+	}).Info("Skills updated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"skills":     skills,
+		"request_id": requestID,
+	})
+}
+
+// GetUserSkills handles getting skills of a user
+// GET /api/v1/users/:id/skills
+func (h *SkillHandler) GetUserSkills(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid user ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	skills, err := h.skillUsecase.GetUserSkills(uint(id))
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to get skills")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"skills":     skills,
+		"request_id": requestID,
+	})
+}
+
+// UpdateManagedSkills handles replacing admin-managed skills of a user (admin only)
+// PUT /admin/users/:id/skills
+func (h *SkillHandler) UpdateManagedSkills(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	adminID, ok := getOrgSettingsAdminID(c, requestID)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid user ID",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	var req models.UpdateSkillsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	skills, err := h.skillUsecase.UpdateManagedSkills(adminID, uint(id), &req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to update skills")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"admin_id":   adminID,
+		"user_id":    id,
+		"skills":     skills.Managed,
+	}).Info("Managed skills updated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"skills":     skills,
+		"request_id": requestID,
+	})
+}
+
+// ListSkills handles listing skills in use with the number of users
+// GET /api/v1/skills?q=go&limit=20
+func (h *SkillHandler) ListSkills(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	skills, err := h.skillUsecase.ListSkills(c.Query("q"), limit)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to list skills")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"skills":     skills,
+		"request_id": requestID,
+	})
+}
+
+// SearchDirectory handles searching users by skills
+// GET /api/v1/skills/users?skills=go,postgres&match=all&department_id=1
+func (h *SkillHandler) SearchDirectory(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.SkillSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+	req.Skills = parseSkillList(c.QueryArray("skills")...)
+	req.MatchAll = c.Query("match") == "all"
+
+	users, total, err := h.skillUsecase.SearchDirectory(&req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to search skills")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":      users,
+		"total":      total,
+		"request_id": requestID,
+	})
+}
+
+// FindCandidates handles finding active users by skills for other services
+// POST /api/v1/internal/users/skills
+func (h *SkillHandler) FindCandidates(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.SkillCandidatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	candidates, err := h.skillUsecase.FindCandidates(&req)
+	if err != nil {
+		h.respondError(c, requestID, err, "Failed to find candidates")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"candidates": candidates,
+		"request_id": requestID,
+	})
+}
+
+// respondError maps skill usecase errors to HTTP statuses
+func (h *SkillHandler) respondError(c *gin.Context, requestID string, err error, message string) {
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"error":      err.Error(),
+	}).Error(message)
+
+	statusCode := http.StatusInternalServerError
+	errorMessage := message
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = "User not found"
+	case strings.Contains(err.Error(), "deactivated"):
+		statusCode = http.StatusForbidden
+		errorMessage = "User is deactivated"
+	}
+
+	c.JSON(statusCode, gin.H{
+		"error":      errorMessage,
+		"request_id": requestID,
+	})
+}
+
+// parseSkillList splits comma-separated skill lists of query parameters
+func parseSkillList(values ...string) []string {
+	var skills []string
+	for _, value := range values {
+		for _, skill := range strings.Split(value, ",") {
+			if skill = strings.TrimSpace(skill); skill != "" {
+				skills = append(skills, skill)
+			}
+		}
+	}
+	return skills
+}
@@ -3,10 +3,14 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -36,7 +40,8 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		}).Warn("Invalid request body for create user")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -69,10 +74,15 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	})
 }
 
-// GetUser handles getting a single user by ID
+// GetUser handles getting a single user by ID, limited to what the requesting user may see
 func (h *UserHandler) GetUser(c *gin.Context) {
 	requestID := requestid.Get(c)
 
+	viewer, ok := getUserViewer(c, requestID)
+	if !ok {
+		return
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -89,7 +99,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userUsecase.GetUser(uint(id))
+	user, err := h.userUsecase.GetUser(viewer, uint(id))
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
@@ -115,35 +125,50 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	})
 }
 
-// GetUsers handles getting all users with pagination
+// GetUsers handles getting users with pagination, limited to what the requesting user may see
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	requestID := requestid.Get(c)
 
-	// Parse pagination parameters
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 0 {
-		limit = 20
+	viewer, ok := getUserViewer(c, requestID)
+	if !ok {
+		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	var req models.UserListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Warn("Invalid query parameters for get users")
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_query_parameters"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
 	}
 
-	users, total, err := h.userUsecase.GetUsers(limit, offset)
+	users, total, err := h.userUsecase.GetUsers(viewer, &req)
 	if err != nil {
 		logger.WithFields(map[string]interface{}{
 			"request_id": requestID,
-			"limit":      limit,
-			"offset":     offset,
+			"user_id":    viewer.ID,
+			"limit":      req.Limit,
+			"offset":     req.Offset,
 			"error":      err.Error(),
 		}).Error("Failed to get users")
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":      "Failed to get users",
+		statusCode := http.StatusInternalServerError
+		errorMessage := "Failed to get users"
+
+		if strings.Contains(err.Error(), "access denied") {
+			statusCode = http.StatusForbidden
+			errorMessage = err.Error()
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error":      errorMessage,
 			"request_id": requestID,
 		})
 		return
@@ -152,8 +177,8 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"users":      users,
 		"total":      total,
-		"limit":      limit,
-		"offset":     offset,
+		"limit":      req.Limit,
+		"offset":     req.Offset,
 		"request_id": requestID,
 	})
 }
@@ -187,7 +212,8 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		}).Warn("Invalid request body for update user")
 
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":      "Invalid request body",
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
 			"request_id": requestID,
 		})
 		return
@@ -272,3 +298,142 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		"request_id": requestID,
 	})
 }
+
+// getUserViewer returns the requesting user's ID and role from JWT context
+func getUserViewer(c *gin.Context, requestID string) (*models.UserViewer, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user ID from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+
+	role, err := middleware.GetUserRoleFromContext(c)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get user role from context")
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":      "User not authenticated",
+			"request_id": requestID,
+		})
+		return nil, false
+	}
+
+	return &models.UserViewer{ID: userID, Role: role}, true
+}
+
+// CheckUsersExist handles checking which users exist, used by other services to validate stored user IDs
+// POST /api/v1/internal/users/exists
+func (h *UserHandler) CheckUsersExist(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.UserExistsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	result, err := h.userUsecase.CheckUsersExist(req.IDs)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to check users")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to check users",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"existing":   result.Existing,
+		"missing":    result.Missing,
+		"request_id": requestID,
+	})
+}
+
+// GetAudience handles listing active users of departments, used by other services to fan out content
+// POST /api/v1/internal/users/audience
+func (h *UserHandler) GetAudience(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.AudienceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	userIDs, err := h.userUsecase.GetAudience(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to get audience")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get audience",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_ids":   userIDs,
+		"request_id": requestID,
+	})
+}
+
+// MatchUsers handles finding active users by names mentioned in free text
+// POST /api/v1/internal/users/match
+func (h *UserHandler) MatchUsers(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	var req models.UserMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      i18n.Message(c, "error.invalid_request_body"),
+			"details":    validation.Details(c, err),
+			"request_id": requestID,
+		})
+		return
+	}
+
+	matches, err := h.userUsecase.MatchUsers(&req)
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"error":      err.Error(),
+		}).Error("Failed to match users")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to match users",
+			"request_id": requestID,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches":    matches,
+		"request_id": requestID,
+	})
+}
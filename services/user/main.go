@@ -15,8 +15,13 @@ import (
 	"tachyon-messenger/services/user/usecase"
 	"tachyon-messenger/shared/config"
 	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/jobs"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+	"tachyon-messenger/shared/quota"
+	"tachyon-messenger/shared/redis"
+	"tachyon-messenger/shared/validation"
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
@@ -36,6 +41,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Resolve other services through the registry, endpoints are refreshed in the background
+	registry, err := config.NewRegistryFromEnv(nil)
+	if err != nil {
+		log.Fatalf("Failed to set up service registry: %v", err)
+	}
+	http.DefaultTransport = registry.Transport(http.DefaultTransport)
+	registryCtx, stopRegistry := context.WithCancel(context.Background())
+	defer stopRegistry()
+	go registry.Run(registryCtx)
+
 	log.Info("Starting User service...")
 
 	// Connect to database
@@ -47,46 +62,111 @@ func main() {
 	defer db.Close()
 
 	// Run database migrations
-	if err := db.Migrate(&models.Department{}, &models.User{}); err != nil {
+	migrationModels := append([]interface{}{&models.Department{}, &models.User{}, &models.UserMerge{}, &models.OrgSettingsRecord{}, &models.OrgSettingsChange{}, &models.SecurityEvent{}, &models.UserDevice{}, &models.OnboardingPlanRecord{}, &models.OnboardingTask{}, &models.APIKey{}, &models.APIKeyUsage{}, &models.UserSkill{}}, jobs.Models()...)
+	if err := db.Migrate(migrationModels...); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	log.Info("Database connected and migrations completed")
 
+	// Connect to Redis (optional, used to share search quotas with other services)
+	redisClient, err := redis.ConnectRedis(redis.DefaultConfig(cfg.Redis.URL))
+	if err != nil {
+		log.Warnf("Failed to connect to Redis, quotas will be counted by this instance only: %v", err)
+	} else {
+		defer redisClient.Close()
+		log.Info("Redis connected successfully")
+	}
+
+	// Per-user quotas of expensive endpoints
+	quotas := quota.NewFromEnv(redisClient)
+
 	// Set Gin mode based on environment
 	if os.Getenv("ENVIRONMENT") == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Validate request DTOs with shared rules
+	validation.Install()
+
 	// Initialize dependencies
 	userRepo := repository.NewUserRepository(db)
 	departmentRepo := repository.NewDepartmentRepository(db)
+	mergeRepo := repository.NewUserMergeRepository(db)
+	orgSettingsRepo := repository.NewOrgSettingsRepository(db)
+	securityRepo := repository.NewSecurityRepository(db)
+	onboardingRepo := repository.NewOnboardingRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	skillRepo := repository.NewSkillRepository(db)
 
 	// Create JWT config
 	jwtConfig := middleware.DefaultJWTConfig(cfg.JWT.Secret)
 
+	// Network restrictions of admin endpoints
+	adminAccess, err := middleware.LoadAdminAccessConfig()
+	if err != nil {
+		log.Fatalf("Failed to load admin access config: %v", err)
+	}
+
+	// Load user list visibility policy
+	visibilityPolicy := getUserVisibilityPolicy()
+	if err := visibilityPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid user visibility policy: %v", err)
+	}
+
+	// Department changes are published to services keeping data per department
+	departmentEvents := usecase.NewDepartmentEventPublisher(departmentRepo,
+		registry.BaseURL(config.ChatService),
+		registry.BaseURL(config.CalendarService),
+		registry.BaseURL(config.PollService))
+
 	// Initialize usecases
-	userUsecase := usecase.NewUserUsecase(userRepo)
-	authUsecase := usecase.NewAuthUsecase(userRepo, departmentRepo, jwtConfig)
-	profileUsecase := usecase.NewProfileUsecase(userRepo, departmentRepo)
-	adminUsecase := usecase.NewAdminUsecase(userRepo, departmentRepo)
-	departmentUsecase := usecase.NewDepartmentUsecase(departmentRepo, userRepo)
+	orgSettingsUsecase := usecase.NewOrgSettingsUsecase(orgSettingsRepo)
+	userUsecase := usecase.NewUserUsecase(userRepo, visibilityPolicy, orgSettingsUsecase, departmentEvents)
+	authUsecase := usecase.NewAuthUsecase(userRepo, departmentRepo, jwtConfig, orgSettingsUsecase, departmentEvents)
+	profileUsecase := usecase.NewProfileUsecase(userRepo, departmentRepo, visibilityPolicy, orgSettingsUsecase, departmentEvents)
+	adminUsecase := usecase.NewAdminUsecase(userRepo, departmentRepo, mergeRepo, orgSettingsUsecase,
+		// Services owning user data, moved when duplicate accounts are merged
+		usecase.NewHTTPUserDataMerger("chat", registry.BaseURL(config.ChatService)),
+		usecase.NewHTTPUserDataMerger("task", registry.BaseURL(config.TaskService)),
+		usecase.NewHTTPUserDataMerger("calendar", registry.BaseURL(config.CalendarService)),
+		usecase.NewHTTPUserDataMerger("poll", registry.BaseURL(config.PollService)),
+		usecase.NewHTTPUserDataMerger("notification", registry.BaseURL(config.NotificationService)),
+	)
+	departmentUsecase := usecase.NewDepartmentUsecase(departmentRepo, userRepo, departmentEvents)
+	securityUsecase := usecase.NewSecurityUsecase(securityRepo, userRepo,
+		usecase.NewHTTPSecurityNotifier(registry.BaseURL(config.NotificationService)))
+	onboardingUsecase := usecase.NewOnboardingUsecase(onboardingRepo, userRepo,
+		usecase.NewHTTPOnboardingNotifier(registry.BaseURL(config.NotificationService)),
+		usecase.NewHTTPDefaultChatJoiner(registry.BaseURL(config.ChatService)))
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo, userRepo)
+	skillUsecase := usecase.NewSkillUsecase(skillRepo, userRepo)
+
+	// Schedule background jobs
+	scheduler := jobs.NewScheduler("user", db, nil)
+	registerJobs(scheduler, orgSettingsUsecase, securityUsecase, onboardingUsecase, log)
+	scheduler.Start()
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userUsecase)
-	authHandler := handlers.NewAuthHandler(authUsecase)
-	profileHandler := handlers.NewProfileHandler(profileUsecase)
+	authHandler := handlers.NewAuthHandler(authUsecase, securityUsecase, onboardingUsecase)
+	profileHandler := handlers.NewProfileHandler(profileUsecase, securityUsecase)
 	departmentHandler := handlers.NewDepartmentHandler(departmentUsecase)
-	adminHandler := handlers.NewAdminHandler(adminUsecase, userUsecase)
+	adminHandler := handlers.NewAdminHandler(adminUsecase, userUsecase, securityUsecase)
+	orgSettingsHandler := handlers.NewOrgSettingsHandler(orgSettingsUsecase)
+	onboardingHandler := handlers.NewOnboardingHandler(onboardingUsecase)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyUsecase)
+	skillHandler := handlers.NewSkillHandler(skillUsecase)
 
 	// Create Gin router
 	router := gin.New()
 
 	// Setup common middleware
 	middleware.SetupCommonMiddleware(router)
+	router.Use(middleware.BodyLimitMiddleware(middleware.DefaultBodyLimitConfig()))
 
 	// Setup routes
-	setupRoutes(router, userHandler, authHandler, profileHandler, departmentHandler, adminHandler, jwtConfig)
+	setupRoutes(router, userHandler, authHandler, profileHandler, departmentHandler, adminHandler, orgSettingsHandler, onboardingHandler, apiKeyHandler, skillHandler, scheduler, quotas, jwtConfig, adminAccess)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -117,16 +197,25 @@ func main() {
 		log.Errorf("Server forced to shutdown: %v", err)
 	}
 
+	// Wait for running background jobs
+	scheduler.Stop()
+
 	log.Info("User service stopped")
 }
 
 // setupRoutes configures all routes for the user service
-func setupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, authHandler *handlers.AuthHandler, profileHandler *handlers.ProfileHandler, departmentHandler *handlers.DepartmentHandler, adminHandler *handlers.AdminHandler, jwtConfig *middleware.JWTConfig) {
+func setupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, authHandler *handlers.AuthHandler, profileHandler *handlers.ProfileHandler, departmentHandler *handlers.DepartmentHandler, adminHandler *handlers.AdminHandler, orgSettingsHandler *handlers.OrgSettingsHandler, onboardingHandler *handlers.OnboardingHandler, apiKeyHandler *handlers.APIKeyHandler, skillHandler *handlers.SkillHandler, scheduler *jobs.Scheduler, quotas *quota.Quotas, jwtConfig *middleware.JWTConfig, adminAccess *middleware.AdminAccessConfig) {
+	// Concurrency limits of route groups, requests over them are shed with 503.
+	// Admin routes are not limited, so the system can be managed under load.
+	limits := middleware.NewConcurrencyLimits("user-service")
+
 	// Health check endpoint
-	router.GET("/health", healthHandler)
+	router.GET("/health", healthHandler(limits))
+	router.GET("/metrics", limits.MetricsHandler())
 
 	// Public authentication routes (no JWT required)
 	auth := router.Group("/auth")
+	auth.Use(limits.Group("auth"))
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
@@ -138,11 +227,12 @@ func setupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, authHand
 	v1 := router.Group("/api/v1")
 	{
 		// Public authentication routes (alternative paths)
-		v1.POST("/register", authHandler.Register)
-		v1.POST("/login", authHandler.Login)
+		v1.POST("/register", limits.Group("auth"), authHandler.Register)
+		v1.POST("/login", limits.Group("auth"), authHandler.Login)
 
 		// Protected user routes (require JWT authentication)
 		users := v1.Group("/users")
+		users.Use(limits.Group("api"))
 		users.Use(middleware.JWTMiddleware(jwtConfig)) // Apply JWT middleware to all user routes
 		{
 			users.GET("", userHandler.GetUsers)                                                          // GET /api/v1/users
@@ -150,21 +240,37 @@ func setupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, authHand
 			users.GET("/:id", userHandler.GetUser)                                                       // GET /api/v1/users/:id
 			users.PUT("/:id", userHandler.UpdateUser)                                                    // PUT /api/v1/users/:id
 			users.DELETE("/:id", middleware.RequireRole("admin", "super_admin"), userHandler.DeleteUser) // DELETE /api/v1/users/:id (admin only)
+			users.GET("/:id/skills", skillHandler.GetUserSkills)                                         // GET /api/v1/users/:id/skills
 		}
 
 		// Protected profile routes (require JWT authentication)
 		profile := v1.Group("/profile")
+		profile.Use(limits.Group("api"))
 		profile.Use(middleware.JWTMiddleware(jwtConfig))
 		{
 			profile.GET("", profileHandler.GetMyProfile)            // GET /api/v1/profile (current user)
 			profile.PUT("", profileHandler.UpdateMyProfile)         // PUT /api/v1/profile (current user)
 			profile.PUT("/password", profileHandler.ChangePassword) // PUT /api/v1/profile/password
 			profile.PUT("/status", profileHandler.UpdateStatus)     // PUT /api/v1/profile/status
+			profile.GET("/skills", skillHandler.GetMySkills)        // GET /api/v1/profile/skills
+			profile.PUT("/skills", skillHandler.UpdateMySkills)     // PUT /api/v1/profile/skills
 			profile.GET("/:id", profileHandler.GetProfile)          // GET /api/v1/profile/:id (any user profile)
 		}
 
+		// Skill directory (require JWT authentication)
+		skills := v1.Group("/skills")
+		skills.Use(limits.Group("api"))
+		skills.Use(middleware.JWTMiddleware(jwtConfig))
+		{
+			skills.GET("", skillHandler.ListSkills) // GET /api/v1/skills?q=
+
+			// Directory search spends the user's search quota
+			skills.GET("/users", quotas.Middleware(quota.BucketSearch, quota.PerPage(1, 20)), skillHandler.SearchDirectory) // GET /api/v1/skills/users?skills=&match=all
+		}
+
 		// Department management routes (admin only)
 		departments := v1.Group("/departments")
+		departments.Use(limits.Group("api"))
 		departments.Use(middleware.JWTMiddleware(jwtConfig))
 		departments.Use(middleware.RequireAdminRole())
 		{
@@ -173,15 +279,32 @@ func setupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, authHand
 			departments.GET("/:id", departmentHandler.GetDepartment)                // GET /api/v1/departments/:id
 			departments.PUT("/:id", departmentHandler.UpdateDepartment)             // PUT /api/v1/departments/:id
 			departments.DELETE("/:id", departmentHandler.DeleteDepartment)          // DELETE /api/v1/departments/:id
+			departments.POST("/:id/merge", departmentHandler.MergeDepartment)       // POST /api/v1/departments/:id/merge
 			departments.GET("/:id/users", departmentHandler.GetDepartmentWithUsers) // GET /api/v1/departments/:id/users
 		}
+
+		// Internal routes for other services (not exposed through the gateway)
+		internal := v1.Group("/internal")
+		internal.Use(limits.Group("internal"))
+		{
+			internal.GET("/settings", orgSettingsHandler.GetSettings)   // GET /api/v1/internal/settings
+			internal.POST("/users/exists", userHandler.CheckUsersExist) // POST /api/v1/internal/users/exists
+			internal.POST("/users/audience", userHandler.GetAudience)   // POST /api/v1/internal/users/audience
+			internal.POST("/users/skills", skillHandler.FindCandidates) // POST /api/v1/internal/users/skills
+			internal.POST("/users/match", userHandler.MatchUsers)       // POST /api/v1/internal/users/match
+
+			// API keys of the public API, verified and metered by the gateway
+			internal.POST("/api-keys/verify", apiKeyHandler.VerifyKey)  // POST /api/v1/internal/api-keys/verify
+			internal.POST("/api-keys/usage", apiKeyHandler.RecordUsage) // POST /api/v1/internal/api-keys/usage
+		}
 	}
 
 	// Admin routes with specific middleware and logging
 	admin := router.Group("/admin")
-	admin.Use(middleware.JWTMiddleware(jwtConfig)) // Require authentication
-	admin.Use(middleware.AdminOnlyMiddleware())    // Require admin role
-	admin.Use(middleware.ValidateAdminRequest())   // Validate request format
+	admin.Use(middleware.AdminAccessMiddleware(adminAccess)) // Require allowed network
+	admin.Use(middleware.JWTMiddleware(jwtConfig))           // Require authentication
+	admin.Use(middleware.AdminOnlyMiddleware())              // Require admin role
+	admin.Use(middleware.ValidateAdminRequest())             // Validate request format
 	{
 		// User management endpoints
 		users := admin.Group("/users")
@@ -220,6 +343,30 @@ func setupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, authHand
 			users.PUT("/:id/deactivate",
 				middleware.LogAdminAction("deactivate_user"),
 				adminHandler.DeactivateUser) // PUT /admin/users/:id/deactivate
+
+			// Account security audit
+			users.GET("/:id/security-events",
+				middleware.LogAdminAction("list_user_security_events"),
+				adminHandler.GetUserSecurityEvents) // GET /admin/users/:id/security-events
+
+			// Onboarding progress of new users
+			users.GET("/:id/onboarding",
+				middleware.LogAdminAction("get_user_onboarding"),
+				onboardingHandler.GetUserOnboarding) // GET /admin/users/:id/onboarding
+
+			// Duplicate account merge
+			users.POST("/merge",
+				middleware.LogAdminAction("merge_users"),
+				adminHandler.MergeUsers) // POST /admin/users/merge
+
+			users.GET("/merges",
+				middleware.LogAdminAction("list_user_merges"),
+				adminHandler.GetUserMerges) // GET /admin/users/merges
+
+			// Skills confirmed by admins
+			users.PUT("/:id/skills",
+				middleware.LogAdminAction("update_user_skills"),
+				skillHandler.UpdateManagedSkills) // PUT /admin/users/:id/skills
 		}
 
 		// Department management for admins
@@ -245,11 +392,80 @@ func setupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, authHand
 				middleware.LogAdminAction("delete_department"),
 				departmentHandler.DeleteDepartment) // DELETE /admin/departments/:id
 
+			departments.POST("/:id/merge",
+				middleware.LogAdminAction("merge_department"),
+				departmentHandler.MergeDepartment) // POST /admin/departments/:id/merge
+
 			departments.GET("/:id/users",
 				middleware.LogAdminAction("get_department_users"),
 				departmentHandler.GetDepartmentWithUsers) // GET /admin/departments/:id/users
 		}
 
+		// Organization settings
+		settings := admin.Group("/settings")
+		{
+			settings.GET("",
+				middleware.LogAdminAction("get_org_settings"),
+				orgSettingsHandler.GetSettings) // GET /admin/settings
+
+			settings.PUT("",
+				middleware.LogAdminAction("update_org_settings"),
+				orgSettingsHandler.UpdateSettings) // PUT /admin/settings
+
+			settings.DELETE("",
+				middleware.LogAdminAction("reset_org_settings"),
+				orgSettingsHandler.ResetSettings) // DELETE /admin/settings
+
+			settings.GET("/changes",
+				middleware.LogAdminAction("list_org_settings_changes"),
+				orgSettingsHandler.GetSettingsChanges) // GET /admin/settings/changes
+
+			// Retention periods of data removed by cleanup jobs of all services
+			settings.GET("/retention",
+				middleware.LogAdminAction("list_retention_policies"),
+				orgSettingsHandler.GetRetentionPolicies) // GET /admin/settings/retention
+
+			settings.PUT("/retention/:data_type",
+				middleware.LogAdminAction("update_retention_policy"),
+				orgSettingsHandler.UpdateRetentionPolicy) // PUT /admin/settings/retention/:data_type
+
+			settings.DELETE("/retention/:data_type",
+				middleware.LogAdminAction("reset_retention_policy"),
+				orgSettingsHandler.ResetRetentionPolicy) // DELETE /admin/settings/retention/:data_type
+
+			// Onboarding plan of new users
+			settings.GET("/onboarding",
+				middleware.LogAdminAction("get_onboarding_plan"),
+				onboardingHandler.GetPlan) // GET /admin/settings/onboarding
+
+			settings.PUT("/onboarding",
+				middleware.LogAdminAction("update_onboarding_plan"),
+				onboardingHandler.UpdatePlan) // PUT /admin/settings/onboarding
+		}
+
+		// API keys of external partners using the public API
+		apiKeys := admin.Group("/api-keys")
+		{
+			apiKeys.GET("",
+				middleware.LogAdminAction("list_api_keys"),
+				apiKeyHandler.ListKeys) // GET /admin/api-keys
+
+			apiKeys.POST("",
+				middleware.LogAdminAction("create_api_key"),
+				apiKeyHandler.CreateKey) // POST /admin/api-keys
+
+			apiKeys.GET("/usage",
+				middleware.LogAdminAction("get_api_key_usage"),
+				apiKeyHandler.GetUsageReport) // GET /admin/api-keys/usage
+
+			apiKeys.DELETE("/:id",
+				middleware.LogAdminAction("revoke_api_key"),
+				apiKeyHandler.RevokeKey) // DELETE /admin/api-keys/:id
+		}
+
+		// Background jobs
+		jobs.RegisterRoutes(admin, scheduler) // /admin/jobs
+
 		// System administration endpoints (super admin only)
 		system := admin.Group("/system")
 		system.Use(middleware.SuperAdminOnlyMiddleware()) // Require super admin role
@@ -294,13 +510,80 @@ func systemStatsHandler(c *gin.Context) {
 }
 
 // healthHandler handles health check requests
-func healthHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "healthy",
-		"service":   "user-service",
-		"timestamp": time.Now().UTC(),
-		"version":   "1.0.0",
-	})
+func healthHandler(limits *middleware.ConcurrencyLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":      "healthy",
+			"service":     "user-service",
+			"timestamp":   time.Now().UTC(),
+			"version":     "1.0.0",
+			"concurrency": limits.Stats(),
+		})
+	}
+}
+
+// registerJobs schedules background jobs of the user service
+func registerJobs(scheduler *jobs.Scheduler, orgSettingsUsecase usecase.OrgSettingsUsecase, securityUsecase usecase.SecurityUsecase, onboardingUsecase usecase.OnboardingUsecase, log *logger.Logger) {
+	userJobs := []jobs.Job{
+		{
+			// Delete security events and settings changes older than the audit log retention policy
+			Name:     "cleanup_audit_logs",
+			Schedule: "@daily",
+			Run: func(ctx context.Context) error {
+				settings, err := orgSettingsUsecase.GetSettings()
+				if err != nil {
+					return err
+				}
+				policy := settings.Retention.Policy(sharedmodels.RetentionAuditLogs)
+				cutoff := time.Now().Add(-settings.Retention.TTL(sharedmodels.RetentionAuditLogs))
+				jobs.Report(ctx, "data_type", policy.DataType)
+				jobs.Report(ctx, "retention_days", policy.Days)
+				jobs.Report(ctx, "cutoff", cutoff)
+
+				events, err := securityUsecase.PurgeSecurityEvents(cutoff)
+				if err != nil {
+					return err
+				}
+				jobs.Report(ctx, "security_events_deleted", events)
+
+				changes, err := orgSettingsUsecase.PurgeSettingsChanges(cutoff)
+				if err != nil {
+					return err
+				}
+				jobs.Report(ctx, "settings_changes_deleted", changes)
+
+				if events > 0 || changes > 0 {
+					log.WithFields(map[string]interface{}{
+						"security_events":  events,
+						"settings_changes": changes,
+					}).Info("Cleaned up old audit logs")
+				}
+				return nil
+			},
+		},
+		{
+			// Send onboarding reminders and retry failed onboarding steps of new users
+			Name:     "run_onboarding_steps",
+			Schedule: "@every 5m",
+			Run: func(ctx context.Context) error {
+				processed, err := onboardingUsecase.RunDueSteps(time.Now())
+				jobs.Report(ctx, "processed_count", processed)
+				if err != nil {
+					return err
+				}
+				if processed > 0 {
+					log.WithField("processed_count", processed).Info("Ran due onboarding steps")
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, job := range userJobs {
+		if err := scheduler.Register(job); err != nil {
+			log.Fatalf("Failed to register background jobs: %v", err)
+		}
+	}
 }
 
 // getServerPort returns the server port from environment or default
@@ -310,3 +593,15 @@ func getServerPort() string {
 	}
 	return "8081" // Default port for user service
 }
+
+// getUserVisibilityPolicy returns the user list visibility policy from environment or defaults
+func getUserVisibilityPolicy() *models.UserVisibilityPolicy {
+	policy := models.DefaultUserVisibilityPolicy()
+	if scope := os.Getenv("USER_LIST_MANAGER_SCOPE"); scope != "" {
+		policy.ManagerScope = models.UserListScope(scope)
+	}
+	if visibility := os.Getenv("USER_LIST_MANAGER_VISIBILITY"); visibility != "" {
+		policy.ManagerVisibility = models.UserVisibility(visibility)
+	}
+	return policy
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/models"
+)
+
+// API key scopes, each one opens an endpoint of the public API at the gateway
+const (
+	APIKeyScopeTasksWrite        = "tasks:write"        // Создание задач
+	APIKeyScopeNotificationsSend = "notifications:send" // Отправка уведомлений
+)
+
+// APIKeyScopes lists the known API key scopes
+var APIKeyScopes = []string{APIKeyScopeTasksWrite, APIKeyScopeNotificationsSend}
+
+// Default and maximum requests per minute of an API key
+const (
+	DefaultAPIKeyRateLimit = 60
+	MaxAPIKeyRateLimit     = 6000
+)
+
+// APIKey gives an external partner access to the public API. Requests act on behalf of
+// the user the key belongs to. Only a hash of the key is stored, the key is shown once.
+type APIKey struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Name       string     `gorm:"not null;size:100" json:"name"`
+	Prefix     string     `gorm:"not null;size:20;uniqueIndex" json:"prefix"` // Начало ключа, чтобы узнать его в списке
+	KeyHash    string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	Scopes     []string   `gorm:"type:text;serializer:json;not null" json:"scopes"`
+	RateLimit  int        `gorm:"not null" json:"rate_limit"` // Запросов в минуту
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	CreatedBy  uint       `gorm:"not null" json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsRevoked checks if the key was revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope checks if the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyUsage counts the requests made with an API key to an endpoint during a month
+type APIKeyUsage struct {
+	ID        uint   `gorm:"primarykey" json:"-"`
+	KeyID     uint   `gorm:"not null;uniqueIndex:idx_api_key_usage_period" json:"key_id"`
+	Month     string `gorm:"not null;size:7;uniqueIndex:idx_api_key_usage_period;index" json:"month"` // YYYY-MM
+	Endpoint  string `gorm:"not null;size:50;uniqueIndex:idx_api_key_usage_period" json:"endpoint"`
+	Requests  int64  `gorm:"not null;default:0" json:"requests"`
+	Throttled int64  `gorm:"not null;default:0" json:"throttled"` // Отклонено превышением лимита
+	Errors    int64  `gorm:"not null;default:0" json:"errors"`    // Ответы с кодом 4xx и 5xx
+}
+
+// TableName returns the table name for APIKeyUsage model
+func (APIKeyUsage) TableName() string {
+	return "api_key_usage"
+}
+
+// CreateAPIKeyRequest represents a request to issue an API key
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,oneof=tasks:write notifications:send" validate:"required,min=1,dive,oneof=tasks:write notifications:send"`
+	RateLimit int      `json:"rate_limit,omitempty" binding:"omitempty,min=1,max=6000" validate:"omitempty,min=1,max=6000"`
+	UserID    uint     `json:"user_id" binding:"required,min=1" validate:"required,min=1"` // Пользователь, от имени которого выполняются запросы
+}
+
+// CreateAPIKeyResponse holds a new API key, Key is never returned again
+type CreateAPIKeyResponse struct {
+	APIKey *APIKey `json:"api_key"`
+	Key    string  `json:"key"`
+}
+
+// VerifyAPIKeyRequest represents a request of the gateway to check an API key
+type VerifyAPIKeyRequest struct {
+	Key string `json:"key" binding:"required,max=100" validate:"required,max=100"`
+}
+
+// APIKeyIdentity describes a valid API key and the user it acts for
+type APIKeyIdentity struct {
+	KeyID     uint        `json:"key_id"`
+	Name      string      `json:"name"`
+	Scopes    []string    `json:"scopes"`
+	RateLimit int         `json:"rate_limit"`
+	UserID    uint        `json:"user_id"`
+	Email     string      `json:"email"`
+	Role      models.Role `json:"role"`
+	Locale    i18n.Locale `json:"locale"`
+}
+
+// RecordAPIKeyUsageRequest represents usage counters metered by the gateway since its last report
+type RecordAPIKeyUsageRequest struct {
+	Entries []APIKeyUsage `json:"entries" binding:"required,max=1000" validate:"required,max=1000"`
+}
+
+// APIKeyUsageReport holds the usage of all API keys during a month
+type APIKeyUsageReport struct {
+	Month     string             `json:"month"`
+	Keys      []*APIKeyUsageStat `json:"keys"`
+	Requests  int64              `json:"requests"`
+	Throttled int64              `json:"throttled"`
+	Errors    int64              `json:"errors"`
+}
+
+// APIKeyUsageStat holds the usage of an API key during a month
+type APIKeyUsageStat struct {
+	KeyID     uint           `json:"key_id"`
+	Name      string         `json:"name"`
+	Prefix    string         `json:"prefix"`
+	Requests  int64          `json:"requests"`
+	Throttled int64          `json:"throttled"`
+	Errors    int64          `json:"errors"`
+	Endpoints []*APIKeyUsage `json:"endpoints"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// UserMergeStatus represents the outcome of an account merge
+type UserMergeStatus string
+
+const (
+	UserMergeStatusInProgress UserMergeStatus = "in_progress"
+	UserMergeStatusCompleted  UserMergeStatus = "completed"
+	UserMergeStatusFailed     UserMergeStatus = "failed" // Часть сервисов не перенесла данные, слияние можно повторить
+)
+
+// UserMerge records a merge of a duplicate account into the primary account
+type UserMerge struct {
+	models.BaseModel
+	PrimaryUserID   uint            `gorm:"not null;index" json:"primary_user_id"`
+	DuplicateUserID uint            `gorm:"not null;index" json:"duplicate_user_id"`
+	MergedBy        uint            `gorm:"not null" json:"merged_by"`
+	Reason          string          `gorm:"size:500" json:"reason,omitempty"`
+	Status          UserMergeStatus `gorm:"not null;size:20;index" json:"status"`
+	Results         string          `gorm:"type:text" json:"-"` // JSON результатов переноса по сервисам
+	Error           string          `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt     *time.Time      `json:"completed_at,omitempty"`
+}
+
+// TableName returns the table name for UserMerge model
+func (UserMerge) TableName() string {
+	return "user_merges"
+}
+
+// MergeUsersRequest represents admin request to merge a duplicate account into the primary account
+type MergeUsersRequest struct {
+	PrimaryUserID   uint   `json:"primary_user_id" binding:"required,min=1" validate:"required,min=1"`
+	DuplicateUserID uint   `json:"duplicate_user_id" binding:"required,min=1,nefield=PrimaryUserID" validate:"required,min=1,nefield=PrimaryUserID"`
+	Reason          string `json:"reason,omitempty" binding:"omitempty,max=500" validate:"omitempty,max=500"`
+}
+
+// UserMergeResponse represents an account merge audit record
+type UserMergeResponse struct {
+	ID              uint                       `json:"id"`
+	PrimaryUserID   uint                       `json:"primary_user_id"`
+	DuplicateUserID uint                       `json:"duplicate_user_id"`
+	MergedBy        uint                       `json:"merged_by"`
+	Reason          string                     `json:"reason,omitempty"`
+	Status          UserMergeStatus            `json:"status"`
+	Results         []*models.MergeUsersResult `json:"results"`
+	Error           string                     `json:"error,omitempty"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	CompletedAt     *time.Time                 `json:"completed_at,omitempty"`
+}
+
+// ToResponse converts UserMerge to UserMergeResponse
+func (m *UserMerge) ToResponse() *UserMergeResponse {
+	response := &UserMergeResponse{
+		ID:              m.ID,
+		PrimaryUserID:   m.PrimaryUserID,
+		DuplicateUserID: m.DuplicateUserID,
+		MergedBy:        m.MergedBy,
+		Reason:          m.Reason,
+		Status:          m.Status,
+		Results:         []*models.MergeUsersResult{},
+		Error:           m.Error,
+		CreatedAt:       m.CreatedAt,
+		CompletedAt:     m.CompletedAt,
+	}
+
+	if m.Results != "" {
+		_ = json.Unmarshal([]byte(m.Results), &response.Results)
+	}
+
+	return response
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// OnboardingStepKind represents what an onboarding step does for a new user
+type OnboardingStepKind string
+
+const (
+	OnboardingStepWelcomeEmail OnboardingStepKind = "welcome_email" // Приветственное письмо по шаблону welcome
+	OnboardingStepDefaultChats OnboardingStepKind = "default_chats" // Добавление в чаты по умолчанию
+	OnboardingStepChecklist    OnboardingStepKind = "checklist"     // Уведомление из списка дел нового сотрудника
+)
+
+// Built-in checklist items have localized texts and are skipped when the user already did them
+const (
+	OnboardingChecklistCompleteProfile    = "complete_profile"
+	OnboardingChecklistJoinDepartmentChat = "join_department_chat"
+)
+
+// OnboardingTaskStatus represents the state of a scheduled onboarding step
+type OnboardingTaskStatus string
+
+const (
+	OnboardingTaskPending OnboardingTaskStatus = "pending"
+	OnboardingTaskDone    OnboardingTaskStatus = "done"
+	OnboardingTaskSkipped OnboardingTaskStatus = "skipped"
+	OnboardingTaskFailed  OnboardingTaskStatus = "failed"
+)
+
+// OnboardingPlan defines the steps new users go through after registration
+type OnboardingPlan struct {
+	Enabled        bool                      `json:"enabled"`
+	WelcomeEmail   bool                      `json:"welcome_email"`
+	DefaultChatIDs []uint                    `json:"default_chat_ids" binding:"max=20,dive,min=1" validate:"max=20,dive,min=1"`
+	Checklist      []OnboardingChecklistItem `json:"checklist" binding:"max=20,dive" validate:"max=20,dive"`
+}
+
+// OnboardingChecklistItem is an in-app reminder sent a number of hours after registration
+type OnboardingChecklistItem struct {
+	Key        string `json:"key" binding:"required,max=50" validate:"required,max=50"`
+	DelayHours int    `json:"delay_hours" binding:"min=0,max=2160" validate:"min=0,max=2160"`
+	Title      string `json:"title,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"` // Пусто у встроенных пунктов - локализованный текст
+	Message    string `json:"message,omitempty" binding:"omitempty,max=2000" validate:"omitempty,max=2000"`
+	ActionURL  string `json:"action_url,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
+}
+
+// DefaultOnboardingPlan returns the plan used until an administrator changes it
+func DefaultOnboardingPlan() *OnboardingPlan {
+	return &OnboardingPlan{
+		Enabled:        true,
+		WelcomeEmail:   true,
+		DefaultChatIDs: []uint{},
+		Checklist: []OnboardingChecklistItem{
+			{Key: OnboardingChecklistCompleteProfile, DelayHours: 24},
+			{Key: OnboardingChecklistJoinDepartmentChat, DelayHours: 72},
+		},
+	}
+}
+
+// OnboardingPlanRecord stores the onboarding plan, the table holds a single row
+type OnboardingPlanRecord struct {
+	models.BaseModel
+	Plan      OnboardingPlan `gorm:"type:text;serializer:json;not null" json:"plan"`
+	UpdatedBy uint           `json:"updated_by"`
+}
+
+// TableName returns the table name for OnboardingPlanRecord model
+func (OnboardingPlanRecord) TableName() string {
+	return "onboarding_plans"
+}
+
+// OnboardingTask is a step of the onboarding plan scheduled for a new user. Texts and chats are
+// copied from the plan at registration, later plan changes apply to new users only.
+type OnboardingTask struct {
+	ID          uint                 `gorm:"primarykey" json:"id"`
+	UserID      uint                 `gorm:"not null;index" json:"user_id"`
+	Kind        OnboardingStepKind   `gorm:"not null;size:20" json:"kind"`
+	Key         string               `gorm:"size:50" json:"key,omitempty"` // Пункт списка дел
+	Title       string               `gorm:"size:255" json:"title,omitempty"`
+	Message     string               `gorm:"type:text" json:"message,omitempty"`
+	ActionURL   string               `gorm:"size:500" json:"action_url,omitempty"`
+	ChatIDs     []uint               `gorm:"type:text;serializer:json" json:"chat_ids,omitempty"`
+	DueAt       time.Time            `gorm:"not null;index" json:"due_at"`
+	Status      OnboardingTaskStatus `gorm:"not null;size:20;default:'pending';index" json:"status"`
+	Attempts    int                  `gorm:"not null;default:0" json:"attempts"`
+	LastError   string               `gorm:"size:500" json:"last_error,omitempty"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// TableName returns the table name for OnboardingTask model
+func (OnboardingTask) TableName() string {
+	return "onboarding_tasks"
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+// OrgSettingsRecord stores organization settings, the table holds a single row
+type OrgSettingsRecord struct {
+	models.BaseModel
+	Settings  string `gorm:"type:text;not null" json:"-"` // JSON models.OrgSettings
+	UpdatedBy uint   `json:"updated_by"`
+}
+
+// TableName returns the table name for OrgSettingsRecord model
+func (OrgSettingsRecord) TableName() string {
+	return "org_settings"
+}
+
+// OrgSettingsChange records a change of organization settings
+type OrgSettingsChange struct {
+	models.BaseModel
+	ChangedBy uint   `gorm:"not null;index" json:"changed_by"`
+	Action    string `gorm:"not null;size:20" json:"action"` // update или reset
+	Changes   string `gorm:"type:text;not null" json:"-"`    // JSON map[string]OrgSettingFieldChange
+}
+
+// TableName returns the table name for OrgSettingsChange model
+func (OrgSettingsChange) TableName() string {
+	return "org_settings_changes"
+}
+
+// Organization settings change actions
+const (
+	OrgSettingsActionUpdate = "update"
+	OrgSettingsActionReset  = "reset"
+)
+
+// UpdateRetentionPolicyRequest represents a request to set the retention period of a data type
+type UpdateRetentionPolicyRequest struct {
+	Days int `json:"days" binding:"required,min=1,max=3650" validate:"required,min=1,max=3650"`
+}
+
+// OrgSettingFieldChange holds old and new values of a changed setting
+type OrgSettingFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// OrgSettingsChangeResponse represents an organization settings audit record
+type OrgSettingsChangeResponse struct {
+	ID        uint                             `json:"id"`
+	ChangedBy uint                             `json:"changed_by"`
+	Action    string                           `json:"action"`
+	Changes   map[string]OrgSettingFieldChange `json:"changes"`
+	CreatedAt time.Time                        `json:"created_at"`
+}
+
+// ToResponse converts OrgSettingsChange to OrgSettingsChangeResponse
+func (c *OrgSettingsChange) ToResponse() *OrgSettingsChangeResponse {
+	response := &OrgSettingsChangeResponse{
+		ID:        c.ID,
+		ChangedBy: c.ChangedBy,
+		Action:    c.Action,
+		Changes:   map[string]OrgSettingFieldChange{},
+		CreatedAt: c.CreatedAt,
+	}
+
+	if c.Changes != "" {
+		_ = json.Unmarshal([]byte(c.Changes), &response.Changes)
+	}
+
+	return response
+}
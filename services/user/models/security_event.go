@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SecurityEventType represents the kind of account security event
+type SecurityEventType string
+
+const (
+	SecurityEventNewDeviceLogin  SecurityEventType = "new_device_login"
+	SecurityEventPasswordChanged SecurityEventType = "password_changed"
+	SecurityEventRoleChanged     SecurityEventType = "role_changed"
+)
+
+// SecurityEvent records a security relevant change of a user account
+type SecurityEvent struct {
+	ID        uint              `gorm:"primarykey" json:"id"`
+	UserID    uint              `gorm:"not null;index" json:"user_id"`
+	Type      SecurityEventType `gorm:"not null;size:30;index" json:"type"`
+	ActorID   *uint             `json:"actor_id,omitempty"` // Администратор, если событие вызвано не самим пользователем
+	IPAddress string            `gorm:"size:45" json:"ip_address,omitempty"`
+	UserAgent string            `gorm:"size:500" json:"user_agent,omitempty"`
+	Details   string            `gorm:"type:text" json:"-"` // JSON с подробностями события
+	CreatedAt time.Time         `gorm:"index" json:"created_at"`
+}
+
+// TableName returns the table name for SecurityEvent model
+func (SecurityEvent) TableName() string {
+	return "security_events"
+}
+
+// UserDevice is a device a user has logged in from, identified by its user agent
+type UserDevice struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_user_devices_fingerprint" json:"user_id"`
+	Fingerprint string    `gorm:"not null;size:64;uniqueIndex:idx_user_devices_fingerprint" json:"-"`
+	UserAgent   string    `gorm:"size:500" json:"user_agent"`
+	LastIP      string    `gorm:"size:45" json:"last_ip,omitempty"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// TableName returns the table name for UserDevice model
+func (UserDevice) TableName() string {
+	return "user_devices"
+}
+
+// ClientInfo describes where a request came from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// SecurityEventResponse represents a security event in admin responses
+type SecurityEventResponse struct {
+	ID        uint              `json:"id"`
+	UserID    uint              `json:"user_id"`
+	Type      SecurityEventType `json:"type"`
+	ActorID   *uint             `json:"actor_id,omitempty"`
+	IPAddress string            `json:"ip_address,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// ToResponse converts SecurityEvent to SecurityEventResponse
+func (e *SecurityEvent) ToResponse() *SecurityEventResponse {
+	response := &SecurityEventResponse{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		Type:      e.Type,
+		ActorID:   e.ActorID,
+		IPAddress: e.IPAddress,
+		UserAgent: e.UserAgent,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.Details != "" {
+		_ = json.Unmarshal([]byte(e.Details), &response.Details)
+	}
+	return response
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SkillSource represents who declared a skill of a user
+type SkillSource string
+
+const (
+	SkillSourceSelf  SkillSource = "self"  // Указан самим пользователем
+	SkillSourceAdmin SkillSource = "admin" // Подтверждён администратором
+)
+
+// Skill limits
+const (
+	MaxSkillLength    = 50
+	MaxSkillsPerUser  = 30 // Per source
+	MaxSearchSkills   = 10
+	MaxSkillMatches   = 100
+	DefaultSkillLimit = 20
+)
+
+// UserSkill is a skill tag of a user. Users manage their self-declared skills, admins manage
+// a separate set that users can't change. A skill is stored normalized, see NormalizeSkill.
+type UserSkill struct {
+	ID        uint        `gorm:"primarykey" json:"id"`
+	UserID    uint        `gorm:"not null;uniqueIndex:idx_user_skills_user_skill_source" json:"user_id"`
+	Skill     string      `gorm:"not null;size:50;uniqueIndex:idx_user_skills_user_skill_source;index" json:"skill"`
+	Source    SkillSource `gorm:"not null;size:10;uniqueIndex:idx_user_skills_user_skill_source" json:"source"`
+	AddedBy   uint        `gorm:"not null" json:"added_by"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// TableName returns the table name for UserSkill model
+func (UserSkill) TableName() string {
+	return "user_skills"
+}
+
+// NormalizeSkill lowercases a skill tag and collapses its whitespace, so "Go " and "go" are one skill
+func NormalizeSkill(skill string) string {
+	return strings.ToLower(strings.Join(strings.Fields(skill), " "))
+}
+
+// NormalizeSkills normalizes skill tags, drops duplicates and sorts them. max limits the number of skills.
+func NormalizeSkills(skills []string, max int) ([]string, error) {
+	seen := make(map[string]bool, len(skills))
+	normalized := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		skill = NormalizeSkill(skill)
+		if skill == "" || seen[skill] {
+			continue
+		}
+		if len([]rune(skill)) > MaxSkillLength {
+			return nil, fmt.Errorf("skill %q is longer than %d characters", skill, MaxSkillLength)
+		}
+		seen[skill] = true
+		normalized = append(normalized, skill)
+	}
+	if len(normalized) > max {
+		return nil, fmt.Errorf("at most %d skills are allowed", max)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// UpdateSkillsRequest replaces the skills of one source of a user
+type UpdateSkillsRequest struct {
+	Skills []string `json:"skills" binding:"max=30,dive,max=50" validate:"max=30,dive,max=50"`
+}
+
+// UserSkillsResponse lists the skills of a user
+type UserSkillsResponse struct {
+	UserID       uint     `json:"user_id"`
+	Skills       []string `json:"skills"`        // Все навыки без повторов
+	SelfDeclared []string `json:"self_declared"` // Указанные пользователем
+	Managed      []string `json:"managed"`       // Подтверждённые администратором
+}
+
+// NewUserSkillsResponse groups skill records of a user by source
+func NewUserSkillsResponse(userID uint, skills []*UserSkill) *UserSkillsResponse {
+	response := &UserSkillsResponse{
+		UserID:       userID,
+		Skills:       []string{},
+		SelfDeclared: []string{},
+		Managed:      []string{},
+	}
+
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		if skill.Source == SkillSourceAdmin {
+			response.Managed = append(response.Managed, skill.Skill)
+		} else {
+			response.SelfDeclared = append(response.SelfDeclared, skill.Skill)
+		}
+		if !seen[skill.Skill] {
+			seen[skill.Skill] = true
+			response.Skills = append(response.Skills, skill.Skill)
+		}
+	}
+	sort.Strings(response.Skills)
+	sort.Strings(response.SelfDeclared)
+	sort.Strings(response.Managed)
+	return response
+}
+
+// SkillCount is a skill tag with the number of users having it
+type SkillCount struct {
+	Skill string `json:"skill"`
+	Users int64  `json:"users"`
+}
+
+// SkillSearchRequest searches the skill directory. Users with any of the skills are returned,
+// or only users with all of them when MatchAll is set.
+type SkillSearchRequest struct {
+	Skills       []string `form:"-"`
+	MatchAll     bool     `form:"-"`
+	DepartmentID *uint    `form:"department_id" binding:"omitempty,min=1"`
+	Limit        int      `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset       int      `form:"offset" binding:"omitempty,min=0"`
+}
+
+// SkillMatch is a user with the requested skills found in the directory
+type SkillMatch struct {
+	UserID uint
+	Count  int64 // Number of requested skills the user has
+}
+
+// SkillDirectoryEntry is a user of the skill directory, with basic profile fields only
+type SkillDirectoryEntry struct {
+	UserID        uint     `json:"user_id"`
+	Name          string   `json:"name"`
+	Position      string   `json:"position,omitempty"`
+	DepartmentID  *uint    `json:"department_id,omitempty"`
+	Avatar        string   `json:"avatar,omitempty"`
+	Skills        []string `json:"skills"`
+	Managed       []string `json:"managed"`
+	MatchedSkills []string `json:"matched_skills"`
+}
+
+// SkillCandidatesRequest asks for active users with any of the skills, used by other services
+type SkillCandidatesRequest struct {
+	Skills []string `json:"skills" binding:"required,min=1,max=10,dive,max=50"`
+	Limit  int      `json:"limit" binding:"omitempty,min=1,max=100"`
+}
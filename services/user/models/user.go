@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
@@ -11,8 +12,9 @@ import (
 // Department represents a department in the organization
 type Department struct {
 	models.BaseModel
-	Name  string `gorm:"uniqueIndex;not null;size:100" json:"name" validate:"required,min=2,max=100"`
-	Users []User `gorm:"foreignKey:DepartmentID" json:"users,omitempty"`
+	Name         string `gorm:"uniqueIndex;not null;size:100" json:"name" validate:"required,min=2,max=100"`
+	ChatDisabled bool   `gorm:"not null;default:false" json:"chat_disabled"` // Не создавать чат отдела автоматически
+	Users        []User `gorm:"foreignKey:DepartmentID" json:"users,omitempty"`
 }
 
 // TableName returns the table name for Department model
@@ -33,6 +35,7 @@ type User struct {
 	Avatar         string            `gorm:"size:500" json:"avatar,omitempty" validate:"omitempty,url,max=500"`
 	Phone          string            `gorm:"size:20" json:"phone,omitempty" validate:"omitempty,e164,max=20"`
 	Position       string            `gorm:"size:100" json:"position,omitempty" validate:"omitempty,max=100"`
+	Locale         i18n.Locale       `gorm:"not null;default:'ru';size:5" json:"locale" validate:"omitempty,enum"`
 	LastActiveAt   *time.Time        `json:"last_active_at,omitempty"`
 	IsActive       bool              `gorm:"not null;default:true" json:"is_active"`
 }
@@ -51,6 +54,9 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.Status == "" {
 		u.Status = models.StatusOffline
 	}
+	if u.Locale == "" {
+		u.Locale = i18n.DefaultLocale
+	}
 	return nil
 }
 
@@ -66,23 +72,31 @@ func (u *User) BeforeUpdate(tx *gorm.DB) error {
 
 // CreateDepartmentRequest represents request for creating a department
 type CreateDepartmentRequest struct {
-	Name string `json:"name" binding:"required,min=2,max=100" validate:"required,min=2,max=100"`
+	Name         string `json:"name" binding:"required,min=2,max=100" validate:"required,min=2,max=100"`
+	ChatDisabled bool   `json:"chat_disabled,omitempty"`
 }
 
 // UpdateDepartmentRequest represents request for updating a department
 type UpdateDepartmentRequest struct {
-	Name *string `json:"name,omitempty" binding:"omitempty,min=2,max=100" validate:"omitempty,min=2,max=100"`
+	Name         *string `json:"name,omitempty" binding:"omitempty,min=2,max=100" validate:"omitempty,min=2,max=100"`
+	ChatDisabled *bool   `json:"chat_disabled,omitempty"`
+}
+
+// MergeDepartmentRequest represents request for merging a department into another one
+type MergeDepartmentRequest struct {
+	TargetDepartmentID uint `json:"target_department_id" binding:"required,min=1" validate:"required,min=1"`
 }
 
 // CreateUserRequest represents request for creating a user
 type CreateUserRequest struct {
-	Email        string `json:"email" binding:"required,email,max=255" validate:"required,email,max=255"`
-	Name         string `json:"name" binding:"required,min=2,max=100" validate:"required,min=2,max=100"`
-	Password     string `json:"password" binding:"required,min=6,max=100" validate:"required,min=6,max=100"`
-	Role         string `json:"role,omitempty" binding:"omitempty,oneof=super_admin admin manager employee" validate:"omitempty,oneof=super_admin admin manager employee"`
-	DepartmentID *uint  `json:"department_id,omitempty" validate:"omitempty,min=1"`
-	Phone        string `json:"phone,omitempty" binding:"omitempty,e164,max=20" validate:"omitempty,e164,max=20"`
-	Position     string `json:"position,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
+	Email        string      `json:"email" binding:"required,email,max=255" validate:"required,email,max=255"`
+	Name         string      `json:"name" binding:"required,min=2,max=100" validate:"required,min=2,max=100"`
+	Password     string      `json:"password" binding:"required,min=6,max=100" validate:"required,min=6,max=100"`
+	Role         string      `json:"role,omitempty" binding:"omitempty,oneof=super_admin admin manager employee" validate:"omitempty,oneof=super_admin admin manager employee"`
+	DepartmentID *uint       `json:"department_id,omitempty" validate:"omitempty,min=1"`
+	Phone        string      `json:"phone,omitempty" binding:"omitempty,e164,max=20" validate:"omitempty,e164,max=20"`
+	Position     string      `json:"position,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
+	Locale       i18n.Locale `json:"locale,omitempty" binding:"omitempty,oneof=ru en" validate:"omitempty,enum"`
 }
 
 // UpdateUserRequest represents request for updating a user
@@ -98,10 +112,18 @@ type UpdateUserRequest struct {
 
 // DepartmentResponse represents department response
 type DepartmentResponse struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	ChatDisabled bool      `json:"chat_disabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DepartmentMergeResponse represents the result of merging a department into another one
+type DepartmentMergeResponse struct {
+	Department         *DepartmentResponse `json:"department"` // Оставшийся отдел
+	MergedDepartmentID uint                `json:"merged_department_id"`
+	MovedUsers         int64               `json:"moved_users"`
 }
 
 // UserResponse represents user response (without sensitive data)
@@ -116,6 +138,7 @@ type UserResponse struct {
 	Avatar       string              `json:"avatar,omitempty"`
 	Phone        string              `json:"phone,omitempty"`
 	Position     string              `json:"position,omitempty"`
+	Locale       i18n.Locale         `json:"locale"`
 	LastActiveAt *time.Time          `json:"last_active_at,omitempty"`
 	IsActive     bool                `json:"is_active"`
 	CreatedAt    time.Time           `json:"created_at"`
@@ -134,6 +157,7 @@ func (u *User) ToResponse() *UserResponse {
 		Avatar:       u.Avatar,
 		Phone:        u.Phone,
 		Position:     u.Position,
+		Locale:       u.Locale,
 		LastActiveAt: u.LastActiveAt,
 		IsActive:     u.IsActive,
 		CreatedAt:    u.CreatedAt,
@@ -156,10 +180,11 @@ func (u *User) ToResponse() *UserResponse {
 // ToResponse converts Department to DepartmentResponse
 func (d *Department) ToResponse() *DepartmentResponse {
 	return &DepartmentResponse{
-		ID:        d.ID,
-		Name:      d.Name,
-		CreatedAt: d.CreatedAt,
-		UpdatedAt: d.UpdatedAt,
+		ID:           d.ID,
+		Name:         d.Name,
+		ChatDisabled: d.ChatDisabled,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
 	}
 }
 
@@ -167,11 +192,12 @@ func (d *Department) ToResponse() *DepartmentResponse {
 
 // UpdateProfileRequest represents profile update request payload
 type UpdateProfileRequest struct {
-	Name         *string `json:"name,omitempty" binding:"omitempty,min=2,max=100" validate:"omitempty,min=2,max=100"`
-	Avatar       *string `json:"avatar,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
-	Phone        *string `json:"phone,omitempty" binding:"omitempty,max=20" validate:"omitempty,max=20"`
-	Position     *string `json:"position,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
-	DepartmentID *uint   `json:"department_id,omitempty" validate:"omitempty,min=0"`
+	Name         *string      `json:"name,omitempty" binding:"omitempty,min=2,max=100" validate:"omitempty,min=2,max=100"`
+	Avatar       *string      `json:"avatar,omitempty" binding:"omitempty,url,max=500" validate:"omitempty,url,max=500"`
+	Phone        *string      `json:"phone,omitempty" binding:"omitempty,max=20" validate:"omitempty,max=20"`
+	Position     *string      `json:"position,omitempty" binding:"omitempty,max=100" validate:"omitempty,max=100"`
+	DepartmentID *uint        `json:"department_id,omitempty" validate:"omitempty,min=0"`
+	Locale       *i18n.Locale `json:"locale,omitempty" binding:"omitempty,oneof=ru en" validate:"omitempty,enum"` // Новый язык попадает в токен при следующем входе
 }
 
 // ChangePasswordRequest represents password change request payload
@@ -212,3 +238,34 @@ type AdminUpdateUserRoleRequest struct {
 type AdminUpdateUserStatusRequest struct {
 	Status models.UserStatus `json:"status" binding:"required,oneof=online busy away offline" validate:"required,oneof=online busy away offline"`
 }
+
+// UserExistsRequest represents a batch of user IDs other services check before storing them
+type UserExistsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=500"`
+}
+
+// UserExistsResponse lists which of the requested users exist
+type UserExistsResponse struct {
+	Existing []uint `json:"existing"`
+	Missing  []uint `json:"missing"`
+}
+
+// AudienceRequest selects active users by department for fan-out from other services,
+// no department IDs selects all active users
+type AudienceRequest struct {
+	DepartmentIDs []uint `json:"department_ids" binding:"max=100,dive,min=1"`
+}
+
+// UserMatchRequest looks up active users by names mentioned in free text for other services.
+// A name matches users whose first or last name starts with it, so stems of inflected names match.
+type UserMatchRequest struct {
+	Names []string `json:"names" binding:"required,min=1,max=20,dive,min=2,max=100"`
+}
+
+// UserMatch is an active user matching a requested name
+type UserMatch struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Position     string `json:"position,omitempty"`
+	DepartmentID *uint  `json:"department_id,omitempty"`
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/models"
+)
+
+// UserVisibility represents the set of user fields a viewer may see
+type UserVisibility string
+
+const (
+	// UserVisibilityBasic shows name, position, department and avatar
+	UserVisibilityBasic UserVisibility = "basic"
+	// UserVisibilityContact adds email, phone and presence status
+	UserVisibilityContact UserVisibility = "contact"
+	// UserVisibilityFull shows all fields including role, account state and last login
+	UserVisibilityFull UserVisibility = "full"
+)
+
+// IsValid checks if the visibility is valid
+func (v UserVisibility) IsValid() bool {
+	switch v {
+	case UserVisibilityBasic, UserVisibilityContact, UserVisibilityFull:
+		return true
+	default:
+		return false
+	}
+}
+
+// UserListScope represents which users a viewer may list
+type UserListScope string
+
+const (
+	UserListScopeAll        UserListScope = "all"
+	UserListScopeDepartment UserListScope = "department"
+)
+
+// IsValid checks if the scope is valid
+func (s UserListScope) IsValid() bool {
+	return s == UserListScopeAll || s == UserListScopeDepartment
+}
+
+// UserVisibilityPolicy configures what managers see of other users.
+// Regular users always see basic fields and admins see everything.
+type UserVisibilityPolicy struct {
+	// ManagerScope limits managers to users of their own department
+	ManagerScope UserListScope
+	// ManagerVisibility applies to users of the manager's department, others get basic fields
+	ManagerVisibility UserVisibility
+}
+
+// DefaultUserVisibilityPolicy returns the default visibility policy
+func DefaultUserVisibilityPolicy() *UserVisibilityPolicy {
+	return &UserVisibilityPolicy{
+		ManagerScope:      UserListScopeAll,
+		ManagerVisibility: UserVisibilityContact,
+	}
+}
+
+// Validate checks that the policy values are valid
+func (p *UserVisibilityPolicy) Validate() error {
+	if !p.ManagerScope.IsValid() {
+		return fmt.Errorf("invalid manager scope: %s", p.ManagerScope)
+	}
+	if !p.ManagerVisibility.IsValid() {
+		return fmt.Errorf("invalid manager visibility: %s", p.ManagerVisibility)
+	}
+	return nil
+}
+
+// UserViewer identifies the user requesting a user or a user list
+type UserViewer struct {
+	ID   uint
+	Role models.Role
+}
+
+// UserListRequest represents user list filter and pagination parameters
+type UserListRequest struct {
+	DepartmentID *uint `form:"department_id" binding:"omitempty,min=1"`
+	Limit        int   `form:"limit"`
+	Offset       int   `form:"offset"`
+}
+
+// UserListItem represents a user as another user sees it, with fields limited by the viewer's visibility
+type UserListItem struct {
+	ID           uint                `json:"id"`
+	Name         string              `json:"name"`
+	Position     string              `json:"position,omitempty"`
+	DepartmentID *uint               `json:"department_id,omitempty"`
+	Department   *DepartmentResponse `json:"department,omitempty"`
+	Avatar       string              `json:"avatar,omitempty"`
+
+	// Contact fields
+	Email  string            `json:"email,omitempty"`
+	Phone  string            `json:"phone,omitempty"`
+	Status models.UserStatus `json:"status,omitempty"`
+
+	// Account fields
+	Role         models.Role `json:"role,omitempty"`
+	Locale       i18n.Locale `json:"locale,omitempty"`
+	IsActive     *bool       `json:"is_active,omitempty"`
+	LastActiveAt *time.Time  `json:"last_active_at,omitempty"`
+	CreatedAt    *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time  `json:"updated_at,omitempty"`
+}
+
+// ToListItem converts User to UserListItem showing only fields allowed by visibility
+func (u *User) ToListItem(visibility UserVisibility) *UserListItem {
+	item := &UserListItem{
+		ID:           u.ID,
+		Name:         u.Name,
+		Position:     u.Position,
+		DepartmentID: u.DepartmentID,
+		Avatar:       u.Avatar,
+	}
+	if u.Department != nil {
+		item.Department = u.Department.ToResponse()
+	}
+
+	if visibility == UserVisibilityContact || visibility == UserVisibilityFull {
+		item.Email = u.Email
+		item.Phone = u.Phone
+		item.Status = u.Status
+	}
+
+	if visibility == UserVisibilityFull {
+		isActive := u.IsActive
+		createdAt := u.CreatedAt
+		updatedAt := u.UpdatedAt
+
+		item.Role = u.Role
+		item.Locale = u.Locale
+		item.IsActive = &isActive
+		item.LastActiveAt = u.LastActiveAt
+		item.CreatedAt = &createdAt
+		item.UpdatedAt = &updatedAt
+	}
+
+	return item
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// APIKeyRepository defines the interface for API key and usage operations
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByID(id uint) (*models.APIKey, error)
+	GetByHash(hash string) (*models.APIKey, error)
+	List(includeRevoked bool) ([]*models.APIKey, error)
+	Revoke(id uint, at time.Time) error
+	TouchLastUsed(ids []uint, at time.Time) error
+	AddUsage(entries []models.APIKeyUsage) error
+	GetUsage(month string) ([]*models.APIKeyUsage, error)
+}
+
+// apiKeyRepository implements APIKeyRepository interface
+type apiKeyRepository struct {
+	db *database.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *database.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+// Create stores a new API key
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *apiKeyRepository) GetByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return &key, nil
+}
+
+// GetByHash retrieves an API key by the hash of the key
+func (r *apiKeyRepository) GetByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return &key, nil
+}
+
+// List retrieves API keys, newest first
+func (r *apiKeyRepository) List(includeRevoked bool) ([]*models.APIKey, error) {
+	query := r.db.Order("created_at DESC, id DESC")
+	if !includeRevoked {
+		query = query.Where("revoked_at IS NULL")
+	}
+
+	var keys []*models.APIKey
+	if err := query.Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke marks an API key as revoked
+func (r *apiKeyRepository) Revoke(id uint, at time.Time) error {
+	result := r.db.Model(&models.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("API key not found")
+	}
+	return nil
+}
+
+// TouchLastUsed sets the last use time of API keys
+func (r *apiKeyRepository) TouchLastUsed(ids []uint, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.Model(&models.APIKey{}).Where("id IN ?", ids).Update("last_used_at", at).Error; err != nil {
+		return fmt.Errorf("failed to update API key last use: %w", err)
+	}
+	return nil
+}
+
+// AddUsage adds metered counters to the monthly usage of API keys
+func (r *apiKeyRepository) AddUsage(entries []models.APIKeyUsage) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range entries {
+			entry := entries[i]
+			entry.ID = 0
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "key_id"}, {Name: "month"}, {Name: "endpoint"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"requests":  gorm.Expr("api_key_usage.requests + ?", entry.Requests),
+					"throttled": gorm.Expr("api_key_usage.throttled + ?", entry.Throttled),
+					"errors":    gorm.Expr("api_key_usage.errors + ?", entry.Errors),
+				}),
+			}).Create(&entry).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add API key usage: %w", err)
+	}
+	return nil
+}
+
+// GetUsage retrieves the usage of all API keys during a month
+func (r *apiKeyRepository) GetUsage(month string) ([]*models.APIKeyUsage, error) {
+	var usage []*models.APIKeyUsage
+	if err := r.db.Where("month = ?", month).Order("key_id ASC, endpoint ASC").Find(&usage).Error; err != nil {
+		return nil, fmt.Errorf("failed to get API key usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/database"
+)
+
+// UserMergeRepository defines the interface for account merge audit operations
+type UserMergeRepository interface {
+	Create(merge *models.UserMerge) error
+	Update(merge *models.UserMerge) error
+	GetAll(limit, offset int) ([]*models.UserMerge, int64, error)
+}
+
+// userMergeRepository implements UserMergeRepository interface
+type userMergeRepository struct {
+	db *database.DB
+}
+
+// NewUserMergeRepository creates a new account merge repository
+func NewUserMergeRepository(db *database.DB) UserMergeRepository {
+	return &userMergeRepository{
+		db: db,
+	}
+}
+
+// Create records a new account merge
+func (r *userMergeRepository) Create(merge *models.UserMerge) error {
+	if err := r.db.Create(merge).Error; err != nil {
+		return fmt.Errorf("failed to create user merge: %w", err)
+	}
+	return nil
+}
+
+// Update saves the outcome of an account merge
+func (r *userMergeRepository) Update(merge *models.UserMerge) error {
+	if err := r.db.Save(merge).Error; err != nil {
+		return fmt.Errorf("failed to update user merge: %w", err)
+	}
+	return nil
+}
+
+// GetAll retrieves account merges, most recent first
+func (r *userMergeRepository) GetAll(limit, offset int) ([]*models.UserMerge, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.UserMerge{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count user merges: %w", err)
+	}
+
+	var merges []*models.UserMerge
+	err := r.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&merges).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user merges: %w", err)
+	}
+
+	return merges, total, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// OnboardingRepository defines the interface for onboarding plan and task operations
+type OnboardingRepository interface {
+	GetPlan() (*models.OnboardingPlanRecord, error)
+	SavePlan(record *models.OnboardingPlanRecord) error
+	CreateTasks(tasks []*models.OnboardingTask) error
+	UpdateTask(task *models.OnboardingTask) error
+	GetDueTasks(now time.Time, limit int) ([]*models.OnboardingTask, error)
+	GetTasksByUser(userID uint) ([]*models.OnboardingTask, error)
+}
+
+// onboardingRepository implements OnboardingRepository interface
+type onboardingRepository struct {
+	db *database.DB
+}
+
+// NewOnboardingRepository creates a new onboarding repository
+func NewOnboardingRepository(db *database.DB) OnboardingRepository {
+	return &onboardingRepository{
+		db: db,
+	}
+}
+
+// GetPlan retrieves the stored onboarding plan, nil if it was never saved
+func (r *onboardingRepository) GetPlan() (*models.OnboardingPlanRecord, error) {
+	var record models.OnboardingPlanRecord
+	if err := r.db.Order("id").First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get onboarding plan: %w", err)
+	}
+	return &record, nil
+}
+
+// SavePlan stores the onboarding plan
+func (r *onboardingRepository) SavePlan(record *models.OnboardingPlanRecord) error {
+	if err := r.db.Save(record).Error; err != nil {
+		return fmt.Errorf("failed to save onboarding plan: %w", err)
+	}
+	return nil
+}
+
+// CreateTasks schedules onboarding steps
+func (r *onboardingRepository) CreateTasks(tasks []*models.OnboardingTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	if err := r.db.Create(&tasks).Error; err != nil {
+		return fmt.Errorf("failed to create onboarding tasks: %w", err)
+	}
+	return nil
+}
+
+// UpdateTask saves the state of an onboarding step
+func (r *onboardingRepository) UpdateTask(task *models.OnboardingTask) error {
+	if err := r.db.Save(task).Error; err != nil {
+		return fmt.Errorf("failed to update onboarding task: %w", err)
+	}
+	return nil
+}
+
+// GetDueTasks retrieves pending onboarding steps due at now, earliest first
+func (r *onboardingRepository) GetDueTasks(now time.Time, limit int) ([]*models.OnboardingTask, error) {
+	var tasks []*models.OnboardingTask
+	err := r.db.Where("status = ? AND due_at <= ?", models.OnboardingTaskPending, now).
+		Order("due_at ASC, id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due onboarding tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetTasksByUser retrieves all onboarding steps of a user in schedule order
+func (r *onboardingRepository) GetTasksByUser(userID uint) ([]*models.OnboardingTask, error) {
+	var tasks []*models.OnboardingTask
+	if err := r.db.Where("user_id = ?", userID).Order("due_at ASC, id ASC").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get onboarding tasks: %w", err)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// OrgSettingsRepository defines the interface for organization settings operations
+type OrgSettingsRepository interface {
+	Get() (*models.OrgSettingsRecord, error)
+	Save(record *models.OrgSettingsRecord, change *models.OrgSettingsChange) error
+	GetChanges(limit, offset int) ([]*models.OrgSettingsChange, int64, error)
+	DeleteChangesBefore(before time.Time) (int64, error)
+}
+
+// orgSettingsRepository implements OrgSettingsRepository interface
+type orgSettingsRepository struct {
+	db *database.DB
+}
+
+// NewOrgSettingsRepository creates a new organization settings repository
+func NewOrgSettingsRepository(db *database.DB) OrgSettingsRepository {
+	return &orgSettingsRepository{
+		db: db,
+	}
+}
+
+// Get retrieves stored organization settings, nil if they were never saved
+func (r *orgSettingsRepository) Get() (*models.OrgSettingsRecord, error) {
+	var record models.OrgSettingsRecord
+	if err := r.db.Order("id").First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization settings: %w", err)
+	}
+	return &record, nil
+}
+
+// Save stores organization settings together with the audit record of the change
+func (r *orgSettingsRepository) Save(record *models.OrgSettingsRecord, change *models.OrgSettingsChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(record).Error; err != nil {
+			return fmt.Errorf("failed to save organization settings: %w", err)
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return fmt.Errorf("failed to record organization settings change: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetChanges retrieves organization settings changes, most recent first
+func (r *orgSettingsRepository) GetChanges(limit, offset int) ([]*models.OrgSettingsChange, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.OrgSettingsChange{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count organization settings changes: %w", err)
+	}
+
+	var changes []*models.OrgSettingsChange
+	err := r.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&changes).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get organization settings changes: %w", err)
+	}
+
+	return changes, total, nil
+}
+
+// DeleteChangesBefore permanently deletes organization settings changes recorded before the given time
+func (r *orgSettingsRepository) DeleteChangesBefore(before time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("created_at < ?", before).Delete(&models.OrgSettingsChange{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old organization settings changes: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
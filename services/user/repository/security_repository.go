@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// SecurityRepository defines the interface for security event and known device operations
+type SecurityRepository interface {
+	CreateEvent(event *models.SecurityEvent) error
+	GetEventsByUser(userID uint, limit int) ([]*models.SecurityEvent, error)
+	GetDevice(userID uint, fingerprint string) (*models.UserDevice, error)
+	SaveDevice(device *models.UserDevice) error
+	CountDevices(userID uint) (int64, error)
+	DeleteEventsBefore(before time.Time) (int64, error)
+}
+
+// securityRepository implements SecurityRepository interface
+type securityRepository struct {
+	db *database.DB
+}
+
+// NewSecurityRepository creates a new security repository
+func NewSecurityRepository(db *database.DB) SecurityRepository {
+	return &securityRepository{
+		db: db,
+	}
+}
+
+// CreateEvent records a new security event
+func (r *securityRepository) CreateEvent(event *models.SecurityEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create security event: %w", err)
+	}
+	return nil
+}
+
+// GetEventsByUser retrieves the latest security events of a user
+func (r *securityRepository) GetEventsByUser(userID uint, limit int) ([]*models.SecurityEvent, error) {
+	var events []*models.SecurityEvent
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get security events: %w", err)
+	}
+	return events, nil
+}
+
+// GetDevice retrieves a known device of a user, nil if the device is new
+func (r *securityRepository) GetDevice(userID uint, fingerprint string) (*models.UserDevice, error) {
+	var device models.UserDevice
+	err := r.db.Where("user_id = ? AND fingerprint = ?", userID, fingerprint).First(&device).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user device: %w", err)
+	}
+	return &device, nil
+}
+
+// SaveDevice creates or updates a known device
+func (r *securityRepository) SaveDevice(device *models.UserDevice) error {
+	if err := r.db.Save(device).Error; err != nil {
+		return fmt.Errorf("failed to save user device: %w", err)
+	}
+	return nil
+}
+
+// CountDevices counts known devices of a user
+func (r *securityRepository) CountDevices(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.UserDevice{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count user devices: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteEventsBefore permanently deletes security events recorded before the given time
+func (r *securityRepository) DeleteEventsBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.SecurityEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old security events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/gorm"
+)
+
+// SkillRepository defines the interface for user skill operations
+type SkillRepository interface {
+	GetByUser(userID uint) ([]*models.UserSkill, error)
+	GetByUsers(userIDs []uint) ([]*models.UserSkill, error)
+	Replace(userID uint, source models.SkillSource, skills []string, addedBy uint) error
+	Search(skills []string, matchAll bool, departmentID *uint, limit, offset int) ([]models.SkillMatch, int64, error)
+	Counts(prefix string, limit int) ([]*models.SkillCount, error)
+}
+
+// skillRepository implements SkillRepository interface
+type skillRepository struct {
+	db *database.DB
+}
+
+// NewSkillRepository creates a new skill repository
+func NewSkillRepository(db *database.DB) SkillRepository {
+	return &skillRepository{
+		db: db,
+	}
+}
+
+// GetByUser retrieves skills of a user of both sources
+func (r *skillRepository) GetByUser(userID uint) ([]*models.UserSkill, error) {
+	var skills []*models.UserSkill
+	if err := r.db.Where("user_id = ?", userID).Order("skill").Find(&skills).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user skills: %w", err)
+	}
+	return skills, nil
+}
+
+// GetByUsers retrieves skills of several users
+func (r *skillRepository) GetByUsers(userIDs []uint) ([]*models.UserSkill, error) {
+	skills := []*models.UserSkill{}
+	if len(userIDs) == 0 {
+		return skills, nil
+	}
+	if err := r.db.Where("user_id IN ?", userIDs).Order("user_id, skill").Find(&skills).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user skills: %w", err)
+	}
+	return skills, nil
+}
+
+// Replace sets the skills of one source of a user. Skills kept from before keep who added them.
+func (r *skillRepository) Replace(userID uint, source models.SkillSource, skills []string, addedBy uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("user_id = ? AND source = ?", userID, source)
+		if len(skills) > 0 {
+			query = query.Where("skill NOT IN ?", skills)
+		}
+		if err := query.Delete(&models.UserSkill{}).Error; err != nil {
+			return err
+		}
+
+		var existing []string
+		if err := tx.Model(&models.UserSkill{}).
+			Where("user_id = ? AND source = ?", userID, source).
+			Pluck("skill", &existing).Error; err != nil {
+			return err
+		}
+		kept := make(map[string]bool, len(existing))
+		for _, skill := range existing {
+			kept[skill] = true
+		}
+
+		var added []*models.UserSkill
+		for _, skill := range skills {
+			if !kept[skill] {
+				added = append(added, &models.UserSkill{UserID: userID, Skill: skill, Source: source, AddedBy: addedBy})
+			}
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		return tx.Create(&added).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update user skills: %w", err)
+	}
+	return nil
+}
+
+// Search finds active users with the given skills, ordered by the number of matched skills
+func (r *skillRepository) Search(skills []string, matchAll bool, departmentID *uint, limit, offset int) ([]models.SkillMatch, int64, error) {
+	matches := []models.SkillMatch{}
+	if len(skills) == 0 {
+		return matches, 0, nil
+	}
+
+	newQuery := func() *gorm.DB {
+		query := r.db.Model(&models.UserSkill{}).
+			Joins("JOIN users ON users.id = user_skills.user_id AND users.deleted_at IS NULL AND users.is_active = ?", true).
+			Where("user_skills.skill IN ?", skills)
+		if departmentID != nil {
+			query = query.Where("users.department_id = ?", *departmentID)
+		}
+		query = query.Group("user_skills.user_id")
+		if matchAll {
+			query = query.Having("COUNT(DISTINCT user_skills.skill) = ?", len(skills))
+		}
+		return query
+	}
+
+	var total int64
+	if err := r.db.Table("(?) AS matches", newQuery().Select("user_skills.user_id")).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count skill matches: %w", err)
+	}
+
+	err := newQuery().
+		Select("user_skills.user_id AS user_id, COUNT(DISTINCT user_skills.skill) AS count").
+		Order("count DESC, user_skills.user_id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&matches).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search skills: %w", err)
+	}
+	return matches, total, nil
+}
+
+// Counts lists skills of active users starting with prefix, most common first
+func (r *skillRepository) Counts(prefix string, limit int) ([]*models.SkillCount, error) {
+	counts := []*models.SkillCount{}
+	query := r.db.Model(&models.UserSkill{}).
+		Select("user_skills.skill AS skill, COUNT(DISTINCT user_skills.user_id) AS users").
+		Joins("JOIN users ON users.id = user_skills.user_id AND users.deleted_at IS NULL AND users.is_active = ?", true)
+	if prefix != "" {
+		query = query.Where("user_skills.skill LIKE ?", prefix+"%")
+	}
+	err := query.Group("user_skills.skill").
+		Order("users DESC, skill").
+		Limit(limit).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count skills: %w", err)
+	}
+	return counts, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/shared/database"
@@ -10,17 +11,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// likeEscaper escapes LIKE wildcards in matched names
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
 	Create(user *models.User) error
 	GetByID(id uint) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
+	GetByIDs(ids []uint) ([]*models.User, error)
 	GetAll(limit, offset int) ([]*models.User, error)
 	Update(user *models.User) error
 	Delete(id uint) error
 	Count() (int64, error)
 	GetWithDepartment(id uint) (*models.User, error)
 	GetAllWithDepartments(limit, offset int) ([]*models.User, error)
+	List(req *models.UserListRequest) ([]*models.User, int64, error)
+	ExistingIDs(ids []uint) ([]uint, error)
+	ActiveIDs(departmentIDs []uint) ([]uint, error)
+	MatchName(name string, limit int) ([]*models.User, error)
 }
 
 // DepartmentRepository defines the interface for department data operations
@@ -31,6 +40,7 @@ type DepartmentRepository interface {
 	GetAll() ([]*models.Department, error)
 	Update(department *models.Department) error
 	Delete(id uint) error
+	Merge(mergedID, targetID uint) (int64, error)
 }
 
 // userRepository implements UserRepository interface
@@ -96,6 +106,18 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+// GetByIDs retrieves users by IDs, missing users are skipped
+func (r *userRepository) GetByIDs(ids []uint) ([]*models.User, error) {
+	users := []*models.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	return users, nil
+}
+
 // GetAll retrieves all users with pagination
 func (r *userRepository) GetAll(limit, offset int) ([]*models.User, error) {
 	var users []*models.User
@@ -143,6 +165,48 @@ func (r *userRepository) Count() (int64, error) {
 	return count, nil
 }
 
+// ExistingIDs returns which of the given user IDs belong to users that were not deleted
+func (r *userRepository) ExistingIDs(ids []uint) ([]uint, error) {
+	existing := []uint{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	err := r.db.Model(&models.User{}).Where("id IN ?", ids).Order("id").Pluck("id", &existing).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to check users: %w", err)
+	}
+	return existing, nil
+}
+
+// ActiveIDs returns IDs of active users in the given departments, or of all active users if none are given
+func (r *userRepository) ActiveIDs(departmentIDs []uint) ([]uint, error) {
+	ids := []uint{}
+	query := r.db.Model(&models.User{}).Where("is_active = ?", true)
+	if len(departmentIDs) > 0 {
+		query = query.Where("department_id IN ?", departmentIDs)
+	}
+	if err := query.Order("id").Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get active users: %w", err)
+	}
+	return ids, nil
+}
+
+// MatchName retrieves active users with a word of the name starting with name, case-insensitively
+func (r *userRepository) MatchName(name string, limit int) ([]*models.User, error) {
+	prefix := likeEscaper.Replace(strings.ToLower(name))
+
+	var users []*models.User
+	err := r.db.Where("is_active = ?", true).
+		Where("LOWER(name) LIKE ? ESCAPE '\\' OR LOWER(name) LIKE ? ESCAPE '\\'", prefix+"%", "% "+prefix+"%").
+		Order("id").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to match users by name: %w", err)
+	}
+	return users, nil
+}
+
 // GetWithDepartment retrieves a user by ID with department preloaded
 func (r *userRepository) GetWithDepartment(id uint) (*models.User, error) {
 	var user models.User
@@ -166,6 +230,31 @@ func (r *userRepository) GetAllWithDepartments(limit, offset int) ([]*models.Use
 	return users, nil
 }
 
+// List retrieves users with departments preloaded, optionally limited to a department
+func (r *userRepository) List(req *models.UserListRequest) ([]*models.User, int64, error) {
+	query := r.db.Model(&models.User{})
+	if req.DepartmentID != nil {
+		query = query.Where("department_id = ?", *req.DepartmentID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	var users []*models.User
+	err := query.Preload("Department").
+		Limit(req.Limit).
+		Offset(req.Offset).
+		Order("created_at DESC").
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return users, total, nil
+}
+
 // Department Repository Methods
 
 // Create creates a new department
@@ -241,3 +330,29 @@ func (r *departmentRepository) Delete(id uint) error {
 	}
 	return nil
 }
+
+// Merge moves users of the merged department to the target department and soft deletes the merged
+// department. It returns the number of moved users.
+func (r *departmentRepository) Merge(mergedID, targetID uint) (int64, error) {
+	var moved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("department_id = ?", mergedID).Update("department_id", targetID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move department users: %w", result.Error)
+		}
+		moved = result.RowsAffected
+
+		result = tx.Delete(&models.Department{}, mergedID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete merged department: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("department not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
@@ -33,7 +33,7 @@ func setupTestDB() (*database.DB, error) {
 	db := &database.DB{DB: gormDB}
 
 	// Run migrations
-	err = db.AutoMigrate(&models.Department{}, &models.User{})
+	err = db.AutoMigrate(&models.Department{}, &models.User{}, &models.OrgSettingsRecord{}, &models.OrgSettingsChange{})
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +65,7 @@ func setupTestRouter() (*gin.Engine, error) {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	departmentRepo := repository.NewDepartmentRepository(db)
+	orgSettingsRepo := repository.NewOrgSettingsRepository(db)
 
 	// Create JWT config for testing
 	jwtConfig := &middleware.JWTConfig{
@@ -75,12 +76,13 @@ func setupTestRouter() (*gin.Engine, error) {
 	}
 
 	// Initialize usecases
-	userUsecase := usecase.NewUserUsecase(userRepo)
-	authUsecase := usecase.NewAuthUsecase(userRepo, departmentRepo, jwtConfig)
+	orgSettingsUsecase := usecase.NewOrgSettingsUsecase(orgSettingsRepo)
+	userUsecase := usecase.NewUserUsecase(userRepo, models.DefaultUserVisibilityPolicy(), orgSettingsUsecase, nil)
+	authUsecase := usecase.NewAuthUsecase(userRepo, departmentRepo, jwtConfig, orgSettingsUsecase, nil)
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userUsecase)
-	authHandler := handlers.NewAuthHandler(authUsecase)
+	authHandler := handlers.NewAuthHandler(authUsecase, nil, nil)
 
 	// Setup router
 	router := gin.New()
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tachyon-messenger/services/user/handlers"
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+	"tachyon-messenger/services/user/usecase"
+	"tachyon-messenger/shared/middleware"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupVisibilityRouter creates a router serving single users and profiles, with a viewer of
+// each role and an engineer they look at
+func setupVisibilityRouter(t *testing.T) (*gin.Engine, *middleware.JWTConfig, map[sharedmodels.Role]*models.User, *models.User) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := setupTestDB()
+	require.NoError(t, err)
+
+	engineering, marketing := uint(1), uint(2)
+	users := map[sharedmodels.Role]*models.User{
+		sharedmodels.RoleEmployee: {Email: "employee@example.com", Name: "Employee", Role: sharedmodels.RoleEmployee, DepartmentID: &marketing},
+		sharedmodels.RoleManager:  {Email: "manager@example.com", Name: "Manager", Role: sharedmodels.RoleManager, DepartmentID: &engineering},
+		sharedmodels.RoleAdmin:    {Email: "admin@example.com", Name: "Admin", Role: sharedmodels.RoleAdmin},
+	}
+	target := &models.User{Email: "target@example.com", Name: "Target", Phone: "+1234567890",
+		Role: sharedmodels.RoleEmployee, DepartmentID: &engineering}
+	for _, user := range []*models.User{target, users[sharedmodels.RoleEmployee], users[sharedmodels.RoleManager], users[sharedmodels.RoleAdmin]} {
+		user.HashedPassword = "hash"
+		user.IsActive = true
+		require.NoError(t, db.Create(user).Error)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	departmentRepo := repository.NewDepartmentRepository(db)
+	policy := models.DefaultUserVisibilityPolicy()
+
+	userHandler := handlers.NewUserHandler(usecase.NewUserUsecase(userRepo, policy, nil, nil))
+	profileHandler := handlers.NewProfileHandler(usecase.NewProfileUsecase(userRepo, departmentRepo, policy, nil, nil), nil)
+
+	jwtConfig := &middleware.JWTConfig{
+		Secret:               "test-secret-key",
+		AccessTokenDuration:  time.Hour,
+		RefreshTokenDuration: time.Hour,
+		Issuer:               "test-tachyon-messenger",
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/users/:id", middleware.JWTMiddleware(jwtConfig), userHandler.GetUser)
+	router.GET("/api/v1/profile/:id", middleware.JWTMiddleware(jwtConfig), profileHandler.GetProfile)
+
+	return router, jwtConfig, users, target
+}
+
+func TestGetUserAndProfileHideFields(t *testing.T) {
+	router, jwtConfig, users, target := setupVisibilityRouter(t)
+
+	// The target is in the manager's department but not the employee's
+	tests := []struct {
+		name       string
+		viewer     sharedmodels.Role
+		contact    bool
+		fullFields bool
+	}{
+		{name: "employee of another department sees basic fields", viewer: sharedmodels.RoleEmployee},
+		{name: "manager of the department sees contact fields", viewer: sharedmodels.RoleManager, contact: true},
+		{name: "admin sees all fields", viewer: sharedmodels.RoleAdmin, contact: true, fullFields: true},
+	}
+
+	for _, path := range []string{"/api/v1/users/%d", "/api/v1/profile/%d"} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s %s", path, tt.name), func(t *testing.T) {
+				viewer := users[tt.viewer]
+				token, err := middleware.GenerateAccessToken(viewer.ID, viewer.Email, viewer.Role, "", time.Hour, jwtConfig)
+				require.NoError(t, err)
+
+				req, err := http.NewRequest("GET", fmt.Sprintf(path, target.ID), nil)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer "+token)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+				var response struct {
+					User    map[string]interface{} `json:"user"`
+					Profile map[string]interface{} `json:"profile"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				user := response.User
+				if user == nil {
+					user = response.Profile
+				}
+
+				assert.Equal(t, "Target", user["name"])
+				_, hasEmail := user["email"]
+				_, hasPhone := user["phone"]
+				_, hasRole := user["role"]
+				_, hasActive := user["is_active"]
+				assert.Equal(t, tt.contact, hasEmail, "email")
+				assert.Equal(t, tt.contact, hasPhone, "phone")
+				assert.Equal(t, tt.fullFields, hasRole, "role")
+				assert.Equal(t, tt.fullFields, hasActive, "is_active")
+			})
+		}
+	}
+}
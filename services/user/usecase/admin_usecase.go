@@ -21,19 +21,40 @@ type AdminUsecase interface {
 	ActivateUser(id uint) (*models.UserResponse, error)
 	DeactivateUser(id uint) (*models.UserResponse, error)
 	ResetUserPassword(id uint, newPassword string) error
+	MergeUsers(adminID uint, req *models.MergeUsersRequest) (*models.UserMergeResponse, error)
+	GetUserMerges(limit, offset int) ([]*models.UserMergeResponse, int64, error)
 }
 
 // adminUsecase implements AdminUsecase interface
 type adminUsecase struct {
 	userRepo       repository.UserRepository
 	departmentRepo repository.DepartmentRepository
+	mergeRepo      repository.UserMergeRepository
+	orgSettings    OrgSettingsUsecase
+	mergers        []UserDataMerger // Сервисы, переносящие данные при слиянии аккаунтов
 }
 
-// NewAdminUsecase creates a new admin usecase
-func NewAdminUsecase(userRepo repository.UserRepository, departmentRepo repository.DepartmentRepository) AdminUsecase {
+// NewAdminUsecase creates a new admin usecase. Nil mergers are skipped.
+func NewAdminUsecase(
+	userRepo repository.UserRepository,
+	departmentRepo repository.DepartmentRepository,
+	mergeRepo repository.UserMergeRepository,
+	orgSettings OrgSettingsUsecase,
+	mergers ...UserDataMerger,
+) AdminUsecase {
+	var activeMergers []UserDataMerger
+	for _, merger := range mergers {
+		if merger != nil {
+			activeMergers = append(activeMergers, merger)
+		}
+	}
+
 	return &adminUsecase{
 		userRepo:       userRepo,
 		departmentRepo: departmentRepo,
+		mergeRepo:      mergeRepo,
+		orgSettings:    orgSettings,
+		mergers:        activeMergers,
 	}
 }
 
@@ -198,6 +219,11 @@ func (a *adminUsecase) ResetUserPassword(id uint, newPassword string) error {
 	if err := validatePasswordStrength(newPassword); err != nil {
 		return fmt.Errorf("invalid password: %w", err)
 	}
+	if a.orgSettings != nil {
+		if err := a.orgSettings.CheckAccount("", newPassword); err != nil {
+			return err
+		}
+	}
 
 	// Get user
 	user, err := a.userRepo.GetByID(id)
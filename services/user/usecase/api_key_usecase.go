@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+)
+
+const (
+	apiKeyPrefix      = "tk_"
+	apiKeySecretBytes = 20
+	apiKeyPrefixLen   = len(apiKeyPrefix) + 8
+	apiKeyMonthLayout = "2006-01"
+)
+
+// APIKeyUsecase manages API keys of the public API and their monthly usage.
+// The gateway verifies keys and reports metered usage through the internal endpoints.
+type APIKeyUsecase interface {
+	CreateKey(adminID uint, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error)
+	ListKeys(includeRevoked bool) ([]*models.APIKey, error)
+	RevokeKey(id uint) error
+	VerifyKey(key string) (*models.APIKeyIdentity, error)
+	RecordUsage(entries []models.APIKeyUsage) error
+	GetUsageReport(month string) (*models.APIKeyUsageReport, error)
+}
+
+// apiKeyUsecase implements APIKeyUsecase interface
+type apiKeyUsecase struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   repository.UserRepository
+}
+
+// NewAPIKeyUsecase creates a new API key usecase
+func NewAPIKeyUsecase(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository) APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// CreateKey issues an API key acting for req.UserID. The key is returned once, only its hash is stored.
+func (a *apiKeyUsecase) CreateKey(adminID uint, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("validation failed: name is required")
+	}
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	rateLimit := req.RateLimit
+	if rateLimit == 0 {
+		rateLimit = models.DefaultAPIKeyRateLimit
+	}
+	if rateLimit < 1 || rateLimit > models.MaxAPIKeyRateLimit {
+		return nil, fmt.Errorf("validation failed: rate limit must be between 1 and %d", models.MaxAPIKeyRateLimit)
+	}
+
+	user, err := a.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("validation failed: user is not active")
+	}
+
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+
+	apiKey := &models.APIKey{
+		Name:      name,
+		Prefix:    key[:apiKeyPrefixLen],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		UserID:    user.ID,
+		CreatedBy: adminID,
+	}
+	if err := a.apiKeyRepo.Create(apiKey); err != nil {
+		return nil, err
+	}
+
+	return &models.CreateAPIKeyResponse{APIKey: apiKey, Key: key}, nil
+}
+
+// ListKeys returns API keys, revoked keys only if includeRevoked is set
+func (a *apiKeyUsecase) ListKeys(includeRevoked bool) ([]*models.APIKey, error) {
+	return a.apiKeyRepo.List(includeRevoked)
+}
+
+// RevokeKey revokes an API key. The gateway stops accepting it once its cached verification expires.
+func (a *apiKeyUsecase) RevokeKey(id uint) error {
+	return a.apiKeyRepo.Revoke(id, time.Now())
+}
+
+// VerifyKey checks an API key and returns the identity requests made with it act as
+func (a *apiKeyUsecase) VerifyKey(key string) (*models.APIKeyIdentity, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	apiKey, err := a.apiKeyRepo.GetByHash(hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.IsRevoked() {
+		return nil, fmt.Errorf("access denied: API key is revoked")
+	}
+
+	user, err := a.userRepo.GetByID(apiKey.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("access denied: user of API key no longer exists")
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("access denied: user of API key is not active")
+	}
+
+	return &models.APIKeyIdentity{
+		KeyID:     apiKey.ID,
+		Name:      apiKey.Name,
+		Scopes:    apiKey.Scopes,
+		RateLimit: apiKey.RateLimit,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		Locale:    user.Locale,
+	}, nil
+}
+
+// RecordUsage adds usage metered by the gateway to the monthly counters
+func (a *apiKeyUsecase) RecordUsage(entries []models.APIKeyUsage) error {
+	var usedKeys []uint
+	seen := make(map[uint]bool)
+	for _, entry := range entries {
+		if entry.KeyID == 0 || strings.TrimSpace(entry.Endpoint) == "" {
+			return fmt.Errorf("validation failed: usage entry needs key_id and endpoint")
+		}
+		if _, err := time.Parse(apiKeyMonthLayout, entry.Month); err != nil {
+			return fmt.Errorf("validation failed: invalid month %q, expected YYYY-MM", entry.Month)
+		}
+		if entry.Requests < 0 || entry.Throttled < 0 || entry.Errors < 0 {
+			return fmt.Errorf("validation failed: usage counters cannot be negative")
+		}
+		if entry.Requests > 0 && !seen[entry.KeyID] {
+			seen[entry.KeyID] = true
+			usedKeys = append(usedKeys, entry.KeyID)
+		}
+	}
+
+	if err := a.apiKeyRepo.AddUsage(entries); err != nil {
+		return err
+	}
+	return a.apiKeyRepo.TouchLastUsed(usedKeys, time.Now())
+}
+
+// GetUsageReport returns the usage of API keys during month (YYYY-MM), the current month if empty
+func (a *apiKeyUsecase) GetUsageReport(month string) (*models.APIKeyUsageReport, error) {
+	if month == "" {
+		month = time.Now().UTC().Format(apiKeyMonthLayout)
+	}
+	if _, err := time.Parse(apiKeyMonthLayout, month); err != nil {
+		return nil, fmt.Errorf("validation failed: invalid month %q, expected YYYY-MM", month)
+	}
+
+	usage, err := a.apiKeyRepo.GetUsage(month)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := a.apiKeyRepo.List(true)
+	if err != nil {
+		return nil, err
+	}
+	keysByID := make(map[uint]*models.APIKey, len(keys))
+	for _, key := range keys {
+		keysByID[key.ID] = key
+	}
+
+	report := &models.APIKeyUsageReport{Month: month, Keys: []*models.APIKeyUsageStat{}}
+	stats := make(map[uint]*models.APIKeyUsageStat)
+	for _, entry := range usage {
+		stat, ok := stats[entry.KeyID]
+		if !ok {
+			stat = &models.APIKeyUsageStat{KeyID: entry.KeyID}
+			if key, ok := keysByID[entry.KeyID]; ok {
+				stat.Name = key.Name
+				stat.Prefix = key.Prefix
+			}
+			stats[entry.KeyID] = stat
+			report.Keys = append(report.Keys, stat)
+		}
+		stat.Requests += entry.Requests
+		stat.Throttled += entry.Throttled
+		stat.Errors += entry.Errors
+		stat.Endpoints = append(stat.Endpoints, entry)
+
+		report.Requests += entry.Requests
+		report.Throttled += entry.Throttled
+		report.Errors += entry.Errors
+	}
+
+	sort.SliceStable(report.Keys, func(i, j int) bool {
+		return report.Keys[i].Requests > report.Keys[j].Requests
+	})
+	return report, nil
+}
+
+// normalizeAPIKeyScopes removes duplicate scopes and rejects unknown ones
+func normalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	known := make(map[string]bool, len(models.APIKeyScopes))
+	for _, scope := range models.APIKeyScopes {
+		known[scope] = true
+	}
+
+	result := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !known[scope] {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	return result, nil
+}
+
+// hashAPIKey returns the stored hash of an API key
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -28,14 +28,18 @@ type authUsecase struct {
 	userRepo       repository.UserRepository
 	departmentRepo repository.DepartmentRepository
 	jwtConfig      *middleware.JWTConfig
+	orgSettings    OrgSettingsUsecase
+	events         *DepartmentEventPublisher // nil disables department events
 }
 
 // NewAuthUsecase creates a new auth usecase
-func NewAuthUsecase(userRepo repository.UserRepository, departmentRepo repository.DepartmentRepository, jwtConfig *middleware.JWTConfig) AuthUsecase {
+func NewAuthUsecase(userRepo repository.UserRepository, departmentRepo repository.DepartmentRepository, jwtConfig *middleware.JWTConfig, orgSettings OrgSettingsUsecase, events *DepartmentEventPublisher) AuthUsecase {
 	return &authUsecase{
 		userRepo:       userRepo,
 		departmentRepo: departmentRepo,
 		jwtConfig:      jwtConfig,
+		orgSettings:    orgSettings,
+		events:         events,
 	}
 }
 
@@ -51,6 +55,13 @@ func (a *authUsecase) Register(req *models.CreateUserRequest) (*models.UserRespo
 		return nil, fmt.Errorf("invalid password: %w", err)
 	}
 
+	// Check allowed email domains and password policy of the organization
+	if a.orgSettings != nil {
+		if err := a.orgSettings.CheckAccount(req.Email, req.Password); err != nil {
+			return nil, err
+		}
+	}
+
 	// Normalize email
 	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
 
@@ -88,6 +99,10 @@ func (a *authUsecase) Register(req *models.CreateUserRequest) (*models.UserRespo
 		DepartmentID:   req.DepartmentID,
 		Position:       strings.TrimSpace(req.Position),
 		Phone:          strings.TrimSpace(req.Phone),
+		Locale:         req.Locale,
+	}
+	if user.Locale == "" && a.orgSettings != nil {
+		user.Locale = a.orgSettings.DefaultLocale()
 	}
 
 	// Set role if provided, otherwise use default (employee)
@@ -105,6 +120,8 @@ func (a *authUsecase) Register(req *models.CreateUserRequest) (*models.UserRespo
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	a.events.UserMoved(user.ID, nil, user.DepartmentID)
+
 	// Get user with department for response
 	userWithDept, err := a.userRepo.GetWithDepartment(user.ID)
 	if err != nil {
@@ -159,7 +176,7 @@ func (a *authUsecase) Login(email, password string) (*sharedmodels.LoginResponse
 	}
 
 	// Generate JWT tokens
-	tokens, err := middleware.GenerateTokens(user.ID, user.Email, user.Role, a.jwtConfig)
+	tokens, err := middleware.GenerateTokens(user.ID, user.Email, user.Role, string(user.Locale), a.jwtConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
@@ -196,6 +213,7 @@ func convertUserToSharedModel(user *models.User) *sharedmodels.User {
 		Avatar:       user.Avatar,
 		Phone:        user.Phone,
 		Position:     user.Position,
+		Locale:       string(user.Locale),
 		LastActiveAt: user.LastActiveAt,
 		IsActive:     user.IsActive,
 	}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// DepartmentEventPublisher delivers department changes to the internal department event endpoint of
+// services keeping data per department, such as department chats, absences and department polls. Delivery failures are logged and
+// not returned, consuming services reconcile their data periodically. All methods work on a nil
+// publisher and publish nothing.
+type DepartmentEventPublisher struct {
+	departmentRepo repository.DepartmentRepository
+	endpoints      []string
+	client         *http.Client
+}
+
+// NewDepartmentEventPublisher creates a publisher for the services at baseURLs, empty URLs are skipped.
+// It returns nil if no URL is given, which disables department events.
+func NewDepartmentEventPublisher(departmentRepo repository.DepartmentRepository, baseURLs ...string) *DepartmentEventPublisher {
+	var endpoints []string
+	for _, baseURL := range baseURLs {
+		if baseURL != "" {
+			endpoints = append(endpoints, strings.TrimRight(baseURL, "/")+"/api/v1/internal/departments/events")
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+	return &DepartmentEventPublisher{
+		departmentRepo: departmentRepo,
+		endpoints:      endpoints,
+		client:         &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// DepartmentChanged publishes that a department was created, updated or deleted
+func (p *DepartmentEventPublisher) DepartmentChanged(eventType sharedmodels.DepartmentEventType, department *models.Department) {
+	if p == nil {
+		return
+	}
+	p.publish(&sharedmodels.DepartmentEvent{
+		Type:           eventType,
+		DepartmentID:   &department.ID,
+		DepartmentName: department.Name,
+		ChatDisabled:   department.ChatDisabled,
+	})
+}
+
+// DepartmentMerged publishes that the merged department was merged into department and removed
+func (p *DepartmentEventPublisher) DepartmentMerged(department, merged *models.Department) {
+	if p == nil {
+		return
+	}
+	p.publish(&sharedmodels.DepartmentEvent{
+		Type:                   sharedmodels.DepartmentEventMerged,
+		DepartmentID:           &department.ID,
+		DepartmentName:         department.Name,
+		ChatDisabled:           department.ChatDisabled,
+		PreviousDepartmentID:   &merged.ID,
+		PreviousDepartmentName: merged.Name,
+	})
+}
+
+// UserMoved publishes that a user joined, left or changed department, if the department changed.
+// A zero department ID means no department.
+func (p *DepartmentEventPublisher) UserMoved(userID uint, previous, current *uint) {
+	if p == nil {
+		return
+	}
+	previous, current = departmentOrNil(previous), departmentOrNil(current)
+	if previous == nil && current == nil || previous != nil && current != nil && *previous == *current {
+		return
+	}
+
+	event := &sharedmodels.DepartmentEvent{
+		Type:                 sharedmodels.DepartmentEventUserMoved,
+		DepartmentID:         current,
+		UserID:               userID,
+		PreviousDepartmentID: previous,
+	}
+	if current != nil {
+		department, err := p.departmentRepo.GetByID(*current)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"user_id":       userID,
+				"department_id": *current,
+				"error":         err.Error(),
+			}).Warn("Failed to get department for department event")
+			return
+		}
+		event.DepartmentName = department.Name
+		event.ChatDisabled = department.ChatDisabled
+	}
+
+	p.publish(event)
+}
+
+// publish sends the event to every service
+func (p *DepartmentEventPublisher) publish(event *sharedmodels.DepartmentEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Failed to encode department event")
+		return
+	}
+
+	for _, endpoint := range p.endpoints {
+		if err := p.send(endpoint, body); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"type":     event.Type,
+				"endpoint": endpoint,
+				"error":    err.Error(),
+			}).Warn("Failed to publish department event")
+		}
+	}
+}
+
+// send posts an encoded event to a service
+func (p *DepartmentEventPublisher) send(endpoint string, body []byte) error {
+	resp, err := p.client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send department event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("service responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// departmentOrNil returns nil for a missing or zero department ID
+func departmentOrNil(departmentID *uint) *uint {
+	if departmentID == nil || *departmentID == 0 {
+		return nil
+	}
+	return departmentID
+}
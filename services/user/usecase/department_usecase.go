@@ -7,6 +7,7 @@ import (
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/services/user/repository"
+	sharedmodels "tachyon-messenger/shared/models"
 
 	"gorm.io/gorm"
 )
@@ -18,6 +19,7 @@ type DepartmentUsecase interface {
 	CreateDepartment(req *models.CreateDepartmentRequest) (*models.DepartmentResponse, error)
 	UpdateDepartment(id uint, req *models.UpdateDepartmentRequest) (*models.DepartmentResponse, error)
 	DeleteDepartment(id uint) error
+	MergeDepartment(id uint, req *models.MergeDepartmentRequest) (*models.DepartmentMergeResponse, error)
 	GetDepartmentWithUsers(id uint) (*models.DepartmentWithUsersResponse, error)
 }
 
@@ -25,13 +27,15 @@ type DepartmentUsecase interface {
 type departmentUsecase struct {
 	departmentRepo repository.DepartmentRepository
 	userRepo       repository.UserRepository
+	events         *DepartmentEventPublisher // nil disables department events
 }
 
 // NewDepartmentUsecase creates a new department usecase
-func NewDepartmentUsecase(departmentRepo repository.DepartmentRepository, userRepo repository.UserRepository) DepartmentUsecase {
+func NewDepartmentUsecase(departmentRepo repository.DepartmentRepository, userRepo repository.UserRepository, events *DepartmentEventPublisher) DepartmentUsecase {
 	return &departmentUsecase{
 		departmentRepo: departmentRepo,
 		userRepo:       userRepo,
+		events:         events,
 	}
 }
 
@@ -81,13 +85,16 @@ func (d *departmentUsecase) CreateDepartment(req *models.CreateDepartmentRequest
 
 	// Create department
 	department := &models.Department{
-		Name: strings.TrimSpace(req.Name),
+		Name:         strings.TrimSpace(req.Name),
+		ChatDisabled: req.ChatDisabled,
 	}
 
 	if err := d.departmentRepo.Create(department); err != nil {
 		return nil, fmt.Errorf("failed to create department: %w", err)
 	}
 
+	d.events.DepartmentChanged(sharedmodels.DepartmentEventCreated, department)
+
 	return department.ToResponse(), nil
 }
 
@@ -124,19 +131,24 @@ func (d *departmentUsecase) UpdateDepartment(id uint, req *models.UpdateDepartme
 
 		department.Name = newName
 	}
+	if req.ChatDisabled != nil {
+		department.ChatDisabled = *req.ChatDisabled
+	}
 
 	// Save updated department
 	if err := d.departmentRepo.Update(department); err != nil {
 		return nil, fmt.Errorf("failed to update department: %w", err)
 	}
 
+	d.events.DepartmentChanged(sharedmodels.DepartmentEventUpdated, department)
+
 	return department.ToResponse(), nil
 }
 
 // DeleteDepartment deletes a department by ID
 func (d *departmentUsecase) DeleteDepartment(id uint) error {
 	// Check if department exists
-	_, err := d.departmentRepo.GetByID(id)
+	department, err := d.departmentRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
 			return fmt.Errorf("department not found")
@@ -153,9 +165,50 @@ func (d *departmentUsecase) DeleteDepartment(id uint) error {
 		return fmt.Errorf("failed to delete department: %w", err)
 	}
 
+	d.events.DepartmentChanged(sharedmodels.DepartmentEventDeleted, department)
+
 	return nil
 }
 
+// MergeDepartment merges a department into the target department: users move to the target and
+// the merged department is deleted. Other services move their department data on the merge event.
+func (d *departmentUsecase) MergeDepartment(id uint, req *models.MergeDepartmentRequest) (*models.DepartmentMergeResponse, error) {
+	if req.TargetDepartmentID == 0 {
+		return nil, fmt.Errorf("validation failed: target department is required")
+	}
+	if req.TargetDepartmentID == id {
+		return nil, fmt.Errorf("validation failed: cannot merge department into itself")
+	}
+
+	merged, err := d.departmentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("department not found")
+		}
+		return nil, fmt.Errorf("failed to get department: %w", err)
+	}
+	target, err := d.departmentRepo.GetByID(req.TargetDepartmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("target department not found")
+		}
+		return nil, fmt.Errorf("failed to get target department: %w", err)
+	}
+
+	moved, err := d.departmentRepo.Merge(merged.ID, target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge department: %w", err)
+	}
+
+	d.events.DepartmentMerged(target, merged)
+
+	return &models.DepartmentMergeResponse{
+		Department:         target.ToResponse(),
+		MergedDepartmentID: merged.ID,
+		MovedUsers:         moved,
+	}, nil
+}
+
 // GetDepartmentWithUsers retrieves a department with its users
 func (d *departmentUsecase) GetDepartmentWithUsers(id uint) (*models.DepartmentWithUsersResponse, error) {
 	// Get department
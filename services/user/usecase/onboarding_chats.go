@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultChatJoiner adds new users to the default chats of the onboarding plan
+type DefaultChatJoiner interface {
+	JoinChats(userID uint, chatIDs []uint) error
+}
+
+// httpDefaultChatJoiner adds users to chats through the chat service
+type httpDefaultChatJoiner struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPDefaultChatJoiner creates a chat joiner for the chat service at baseURL.
+// It returns nil if baseURL is empty, default chats are then skipped.
+func NewHTTPDefaultChatJoiner(baseURL string) DefaultChatJoiner {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpDefaultChatJoiner{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// JoinChats asks the chat service to add the user to the chats
+func (j *httpDefaultChatJoiner) JoinChats(userID uint, chatIDs []uint) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"user_id":  userID,
+		"chat_ids": chatIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode chat join request: %w", err)
+	}
+
+	resp, err := j.client.Post(j.baseURL+"/api/v1/internal/chats/members", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to request chat membership: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("chat service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+)
+
+// OnboardingNotifier sends onboarding messages to new users
+type OnboardingNotifier interface {
+	SendWelcome(userID uint, locale i18n.Locale, userName string) error
+	NotifyUser(userID uint, locale i18n.Locale, title, message, actionURL string) error
+}
+
+// httpOnboardingNotifier queues onboarding notifications in the notification service
+type httpOnboardingNotifier struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPOnboardingNotifier creates a notifier for the notification service at baseURL.
+// It returns nil if baseURL is empty, onboarding messages are then skipped.
+func NewHTTPOnboardingNotifier(baseURL string) OnboardingNotifier {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpOnboardingNotifier{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendWelcome queues the welcome email template, with an in-app copy, for the user
+func (n *httpOnboardingNotifier) SendWelcome(userID uint, locale i18n.Locale, userName string) error {
+	return n.addTask(map[string]interface{}{
+		"type":     "templated",
+		"priority": "medium",
+		"templated_notification": map[string]interface{}{
+			"user_id":       userID,
+			"type":          "system",
+			"template_name": "welcome",
+			"variables":     map[string]interface{}{"UserName": userName},
+			"channels":      []string{"email", "in_app"},
+			"related_type":  "onboarding",
+			"locale":        locale,
+		},
+	})
+}
+
+// NotifyUser queues an in-app onboarding notification for the user
+func (n *httpOnboardingNotifier) NotifyUser(userID uint, locale i18n.Locale, title, message, actionURL string) error {
+	notification := map[string]interface{}{
+		"user_id":      userID,
+		"type":         "system",
+		"title":        title,
+		"message":      message,
+		"related_type": "onboarding",
+		"channels":     []string{"in_app"},
+		"locale":       locale,
+	}
+	if actionURL != "" {
+		notification["action_url"] = actionURL
+	}
+
+	return n.addTask(map[string]interface{}{
+		"type":         "single",
+		"priority":     "medium",
+		"notification": notification,
+	})
+}
+
+// addTask posts a task to the notification service queue
+func (n *httpOnboardingNotifier) addTask(task map[string]interface{}) error {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification task: %w", err)
+	}
+
+	resp, err := n.client.Post(n.baseURL+"/api/v1/internal/notifications/task", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification task: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
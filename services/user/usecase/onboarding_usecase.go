@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+)
+
+const (
+	onboardingBatchSize   = 200
+	onboardingMaxAttempts = 5
+	onboardingRetryDelay  = 15 * time.Minute
+)
+
+// onboardingChecklistTexts maps built-in checklist items to their localized notification keys
+var onboardingChecklistTexts = map[string]string{
+	models.OnboardingChecklistCompleteProfile:    "notification.onboarding_profile",
+	models.OnboardingChecklistJoinDepartmentChat: "notification.onboarding_dept_chat",
+}
+
+// OnboardingUsecase schedules the onboarding plan for new users and runs its steps when they are due.
+// Starting onboarding never fails the registration that triggered it: errors are logged.
+type OnboardingUsecase interface {
+	GetPlan() (*models.OnboardingPlan, error)
+	UpdatePlan(adminID uint, plan *models.OnboardingPlan) (*models.OnboardingPlan, error)
+	Start(userID uint)
+	RunDueSteps(now time.Time) (int, error)
+	GetUserOnboarding(userID uint) ([]*models.OnboardingTask, error)
+}
+
+// onboardingUsecase implements OnboardingUsecase interface
+type onboardingUsecase struct {
+	onboardingRepo repository.OnboardingRepository
+	userRepo       repository.UserRepository
+	notifier       OnboardingNotifier
+	chats          DefaultChatJoiner
+}
+
+// NewOnboardingUsecase creates a new onboarding usecase. Steps that need a nil notifier
+// or chat joiner are skipped.
+func NewOnboardingUsecase(onboardingRepo repository.OnboardingRepository, userRepo repository.UserRepository, notifier OnboardingNotifier, chats DefaultChatJoiner) OnboardingUsecase {
+	return &onboardingUsecase{
+		onboardingRepo: onboardingRepo,
+		userRepo:       userRepo,
+		notifier:       notifier,
+		chats:          chats,
+	}
+}
+
+// GetPlan returns the onboarding plan, the default plan if an administrator never changed it
+func (o *onboardingUsecase) GetPlan() (*models.OnboardingPlan, error) {
+	record, err := o.onboardingRepo.GetPlan()
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return models.DefaultOnboardingPlan(), nil
+	}
+	return &record.Plan, nil
+}
+
+// UpdatePlan replaces the onboarding plan. Users who already registered keep their schedule.
+func (o *onboardingUsecase) UpdatePlan(adminID uint, plan *models.OnboardingPlan) (*models.OnboardingPlan, error) {
+	if err := validateOnboardingPlan(plan); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if plan.DefaultChatIDs == nil {
+		plan.DefaultChatIDs = []uint{}
+	}
+	if plan.Checklist == nil {
+		plan.Checklist = []models.OnboardingChecklistItem{}
+	}
+
+	record, err := o.onboardingRepo.GetPlan()
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		record = &models.OnboardingPlanRecord{}
+	}
+	record.Plan = *plan
+	record.UpdatedBy = adminID
+
+	if err := o.onboardingRepo.SavePlan(record); err != nil {
+		return nil, err
+	}
+	return &record.Plan, nil
+}
+
+// Start schedules the onboarding plan for a newly registered user and runs the steps due right away
+func (o *onboardingUsecase) Start(userID uint) {
+	plan, err := o.GetPlan()
+	if err != nil {
+		o.logError(userID, "Failed to get onboarding plan", err)
+		return
+	}
+	if !plan.Enabled {
+		return
+	}
+
+	now := time.Now()
+	var tasks []*models.OnboardingTask
+	if plan.WelcomeEmail {
+		tasks = append(tasks, &models.OnboardingTask{UserID: userID, Kind: models.OnboardingStepWelcomeEmail, DueAt: now})
+	}
+	if len(plan.DefaultChatIDs) > 0 {
+		tasks = append(tasks, &models.OnboardingTask{
+			UserID:  userID,
+			Kind:    models.OnboardingStepDefaultChats,
+			ChatIDs: plan.DefaultChatIDs,
+			DueAt:   now,
+		})
+	}
+	for _, item := range plan.Checklist {
+		tasks = append(tasks, &models.OnboardingTask{
+			UserID:    userID,
+			Kind:      models.OnboardingStepChecklist,
+			Key:       item.Key,
+			Title:     item.Title,
+			Message:   item.Message,
+			ActionURL: item.ActionURL,
+			DueAt:     now.Add(time.Duration(item.DelayHours) * time.Hour),
+		})
+	}
+	if len(tasks) == 0 {
+		return
+	}
+	for _, task := range tasks {
+		task.Status = models.OnboardingTaskPending
+	}
+
+	if err := o.onboardingRepo.CreateTasks(tasks); err != nil {
+		o.logError(userID, "Failed to schedule onboarding", err)
+		return
+	}
+
+	user, err := o.userRepo.GetByID(userID)
+	if err != nil {
+		o.logError(userID, "Failed to get user for onboarding", err)
+		return
+	}
+	for _, task := range tasks {
+		if !task.DueAt.After(now) {
+			o.runTask(task, user, now)
+		}
+	}
+}
+
+// RunDueSteps runs onboarding steps that are due at now. It returns the number of processed steps.
+func (o *onboardingUsecase) RunDueSteps(now time.Time) (int, error) {
+	tasks, err := o.onboardingRepo.GetDueTasks(now, onboardingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	users := make(map[uint]*models.User)
+	for i, task := range tasks {
+		user, ok := users[task.UserID]
+		if !ok {
+			user, err = o.userRepo.GetByID(task.UserID)
+			if err != nil && !strings.Contains(err.Error(), "not found") {
+				return i, err
+			}
+			// Steps of deleted users are skipped by runTask
+			users[task.UserID] = user
+		}
+		o.runTask(task, user, now)
+	}
+
+	return len(tasks), nil
+}
+
+// GetUserOnboarding returns the onboarding steps of a user with their progress
+func (o *onboardingUsecase) GetUserOnboarding(userID uint) ([]*models.OnboardingTask, error) {
+	if _, err := o.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+	return o.onboardingRepo.GetTasksByUser(userID)
+}
+
+// runTask performs an onboarding step and stores its outcome. Failed steps are retried
+// with a growing delay until onboardingMaxAttempts is reached.
+func (o *onboardingUsecase) runTask(task *models.OnboardingTask, user *models.User, now time.Time) {
+	skipReason, err := o.performTask(task, user)
+
+	switch {
+	case err != nil:
+		task.Attempts++
+		task.LastError = truncate(err.Error(), 500)
+		if task.Attempts >= onboardingMaxAttempts {
+			task.Status = models.OnboardingTaskFailed
+			o.logError(task.UserID, "Onboarding step failed", err)
+		} else {
+			task.DueAt = now.Add(time.Duration(task.Attempts) * onboardingRetryDelay)
+		}
+	case skipReason != "":
+		task.Status = models.OnboardingTaskSkipped
+		task.LastError = skipReason
+		task.CompletedAt = &now
+	default:
+		task.Status = models.OnboardingTaskDone
+		task.LastError = ""
+		task.CompletedAt = &now
+	}
+
+	if err := o.onboardingRepo.UpdateTask(task); err != nil {
+		o.logError(task.UserID, "Failed to save onboarding step", err)
+	}
+}
+
+// performTask runs a single onboarding step. It returns why the step was skipped, if it was.
+func (o *onboardingUsecase) performTask(task *models.OnboardingTask, user *models.User) (string, error) {
+	if user == nil || !user.IsActive {
+		return "user is not active", nil
+	}
+
+	switch task.Kind {
+	case models.OnboardingStepWelcomeEmail:
+		if o.notifier == nil {
+			return "notifications are not configured", nil
+		}
+		return "", o.notifier.SendWelcome(user.ID, user.Locale, user.Name)
+
+	case models.OnboardingStepDefaultChats:
+		if o.chats == nil {
+			return "chats are not configured", nil
+		}
+		return "", o.chats.JoinChats(user.ID, task.ChatIDs)
+
+	case models.OnboardingStepChecklist:
+		if o.notifier == nil {
+			return "notifications are not configured", nil
+		}
+		if reason := checklistItemDone(task.Key, user); reason != "" {
+			return reason, nil
+		}
+
+		title, message := task.Title, task.Message
+		if key, ok := onboardingChecklistTexts[task.Key]; ok && title == "" {
+			args := map[string]interface{}{"UserName": user.Name}
+			title = i18n.T(user.Locale, key+"_title", args)
+			message = i18n.T(user.Locale, key+"_message", args)
+		}
+		return "", o.notifier.NotifyUser(user.ID, user.Locale, title, message, task.ActionURL)
+
+	default:
+		return fmt.Sprintf("unknown step kind %q", task.Kind), nil
+	}
+}
+
+// checklistItemDone tells why a built-in checklist reminder is not needed, empty if it is
+func checklistItemDone(key string, user *models.User) string {
+	switch key {
+	case models.OnboardingChecklistCompleteProfile:
+		if user.Avatar != "" && user.Phone != "" && user.Position != "" {
+			return "profile is complete"
+		}
+	case models.OnboardingChecklistJoinDepartmentChat:
+		if user.DepartmentID == nil {
+			return "user has no department"
+		}
+	}
+	return ""
+}
+
+// validateOnboardingPlan checks that checklist items are unique and custom items have a title
+func validateOnboardingPlan(plan *models.OnboardingPlan) error {
+	keys := make(map[string]bool, len(plan.Checklist))
+	for i := range plan.Checklist {
+		item := &plan.Checklist[i]
+		item.Key = strings.TrimSpace(item.Key)
+		item.Title = strings.TrimSpace(item.Title)
+		item.Message = strings.TrimSpace(item.Message)
+
+		if item.Key == "" {
+			return fmt.Errorf("checklist item key is required")
+		}
+		if keys[item.Key] {
+			return fmt.Errorf("duplicate checklist item %q", item.Key)
+		}
+		keys[item.Key] = true
+
+		if _, builtIn := onboardingChecklistTexts[item.Key]; !builtIn && item.Title == "" {
+			return fmt.Errorf("checklist item %q needs a title", item.Key)
+		}
+	}
+	return nil
+}
+
+// logError logs a failed onboarding operation
+func (o *onboardingUsecase) logError(userID uint, message string, err error) {
+	logger.WithFields(map[string]interface{}{
+		"user_id": userID,
+		"error":   err.Error(),
+	}).Error(message)
+}
@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+	"tachyon-messenger/shared/i18n"
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// OrgSettingsUsecase defines the interface for organization settings business logic
+type OrgSettingsUsecase interface {
+	GetSettings() (*sharedmodels.OrgSettings, error)
+	UpdateSettings(adminID uint, settings *sharedmodels.OrgSettings) (*sharedmodels.OrgSettings, error)
+	ResetSettings(adminID uint) (*sharedmodels.OrgSettings, error)
+	GetSettingsChanges(limit, offset int) ([]*models.OrgSettingsChangeResponse, int64, error)
+	CheckAccount(email, password string) error
+	DefaultLocale() i18n.Locale
+	GetRetentionPolicies() ([]sharedmodels.RetentionPolicy, error)
+	SetRetentionPolicy(adminID uint, dataType sharedmodels.RetentionDataType, days int) (*sharedmodels.RetentionPolicy, error)
+	ResetRetentionPolicy(adminID uint, dataType sharedmodels.RetentionDataType) (*sharedmodels.RetentionPolicy, error)
+	PurgeSettingsChanges(before time.Time) (int64, error)
+}
+
+// orgSettingsUsecase implements OrgSettingsUsecase interface
+type orgSettingsUsecase struct {
+	settingsRepo repository.OrgSettingsRepository
+}
+
+// NewOrgSettingsUsecase creates a new organization settings usecase
+func NewOrgSettingsUsecase(settingsRepo repository.OrgSettingsRepository) OrgSettingsUsecase {
+	return &orgSettingsUsecase{
+		settingsRepo: settingsRepo,
+	}
+}
+
+// GetSettings returns current organization settings, defaults if they were never changed
+func (u *orgSettingsUsecase) GetSettings() (*sharedmodels.OrgSettings, error) {
+	record, err := u.settingsRepo.Get()
+	if err != nil {
+		return nil, err
+	}
+	return settingsFromRecord(record)
+}
+
+// UpdateSettings replaces organization settings and records changed fields
+func (u *orgSettingsUsecase) UpdateSettings(adminID uint, settings *sharedmodels.OrgSettings) (*sharedmodels.OrgSettings, error) {
+	if settings == nil {
+		return nil, fmt.Errorf("validation failed: settings are required")
+	}
+
+	settings.Normalize()
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	return u.save(adminID, models.OrgSettingsActionUpdate, settings)
+}
+
+// ResetSettings restores default organization settings
+func (u *orgSettingsUsecase) ResetSettings(adminID uint) (*sharedmodels.OrgSettings, error) {
+	return u.save(adminID, models.OrgSettingsActionReset, sharedmodels.DefaultOrgSettings())
+}
+
+// GetSettingsChanges retrieves the organization settings audit log
+func (u *orgSettingsUsecase) GetSettingsChanges(limit, offset int) ([]*models.OrgSettingsChangeResponse, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	changes, total, err := u.settingsRepo.GetChanges(limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]*models.OrgSettingsChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = change.ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+// CheckAccount checks the email domain and password of a new or changed account against organization settings.
+// An empty password is not checked.
+func (u *orgSettingsUsecase) CheckAccount(email, password string) error {
+	settings, err := u.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	if email != "" && !settings.IsEmailAllowed(email) {
+		return fmt.Errorf("invalid email: domain is not allowed in this organization")
+	}
+	if password != "" {
+		if err := settings.PasswordPolicy.Check(password); err != nil {
+			return fmt.Errorf("invalid password: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DefaultLocale returns the locale of new accounts without a detected language
+func (u *orgSettingsUsecase) DefaultLocale() i18n.Locale {
+	settings, err := u.GetSettings()
+	if err != nil || !settings.DefaultLocale.IsValid() {
+		return i18n.DefaultLocale
+	}
+	return settings.DefaultLocale
+}
+
+// GetRetentionPolicies returns effective retention periods of all data types
+func (u *orgSettingsUsecase) GetRetentionPolicies() ([]sharedmodels.RetentionPolicy, error) {
+	settings, err := u.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	return settings.Retention.PolicyList(), nil
+}
+
+// SetRetentionPolicy sets how many days data of the type is kept by cleanup jobs
+func (u *orgSettingsUsecase) SetRetentionPolicy(adminID uint, dataType sharedmodels.RetentionDataType, days int) (*sharedmodels.RetentionPolicy, error) {
+	if !sharedmodels.IsValidRetentionDataType(dataType) {
+		return nil, fmt.Errorf("retention data type not found")
+	}
+	if days < 1 || days > 3650 {
+		return nil, fmt.Errorf("validation failed: retention period must be between 1 and 3650 days")
+	}
+
+	return u.updateRetentionPolicies(adminID, dataType, func(policies map[sharedmodels.RetentionDataType]int) {
+		policies[dataType] = days
+	})
+}
+
+// ResetRetentionPolicy removes the policy of a data type, so its default period applies
+func (u *orgSettingsUsecase) ResetRetentionPolicy(adminID uint, dataType sharedmodels.RetentionDataType) (*sharedmodels.RetentionPolicy, error) {
+	if !sharedmodels.IsValidRetentionDataType(dataType) {
+		return nil, fmt.Errorf("retention data type not found")
+	}
+
+	return u.updateRetentionPolicies(adminID, dataType, func(policies map[sharedmodels.RetentionDataType]int) {
+		delete(policies, dataType)
+	})
+}
+
+// PurgeSettingsChanges deletes organization settings changes older than the audit log retention
+func (u *orgSettingsUsecase) PurgeSettingsChanges(before time.Time) (int64, error) {
+	return u.settingsRepo.DeleteChangesBefore(before)
+}
+
+// updateRetentionPolicies applies a change to retention policies and returns the resulting policy of the data type
+func (u *orgSettingsUsecase) updateRetentionPolicies(adminID uint, dataType sharedmodels.RetentionDataType, change func(map[sharedmodels.RetentionDataType]int)) (*sharedmodels.RetentionPolicy, error) {
+	settings, err := u.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[sharedmodels.RetentionDataType]int, len(settings.Retention.Policies)+1)
+	for key, days := range settings.Retention.Policies {
+		policies[key] = days
+	}
+	change(policies)
+	settings.Retention.Policies = policies
+
+	saved, err := u.save(adminID, models.OrgSettingsActionUpdate, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := saved.Retention.Policy(dataType)
+	return &policy, nil
+}
+
+// save stores settings and the audit record of changed fields, nothing is recorded if settings did not change
+func (u *orgSettingsUsecase) save(adminID uint, action string, settings *sharedmodels.OrgSettings) (*sharedmodels.OrgSettings, error) {
+	record, err := u.settingsRepo.Get()
+	if err != nil {
+		return nil, err
+	}
+	current, err := settingsFromRecord(record)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffSettings(current, settings)
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal organization settings: %w", err)
+	}
+	changesData, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal organization settings changes: %w", err)
+	}
+
+	if record == nil {
+		record = &models.OrgSettingsRecord{}
+	}
+	record.Settings = string(data)
+	record.UpdatedBy = adminID
+
+	change := &models.OrgSettingsChange{
+		ChangedBy: adminID,
+		Action:    action,
+		Changes:   string(changesData),
+	}
+	if err := u.settingsRepo.Save(record, change); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// settingsFromRecord decodes stored settings on top of defaults, so settings added later get default values
+func settingsFromRecord(record *models.OrgSettingsRecord) (*sharedmodels.OrgSettings, error) {
+	settings := sharedmodels.DefaultOrgSettings()
+	if record == nil {
+		return settings, nil
+	}
+	if err := json.Unmarshal([]byte(record.Settings), settings); err != nil {
+		return nil, fmt.Errorf("failed to decode organization settings: %w", err)
+	}
+	return settings, nil
+}
+
+// diffSettings returns changed settings keyed by JSON path, e.g. "password_policy.min_length"
+func diffSettings(old, new *sharedmodels.OrgSettings) map[string]models.OrgSettingFieldChange {
+	oldFields := flattenSettings(old)
+	newFields := flattenSettings(new)
+
+	changes := make(map[string]models.OrgSettingFieldChange)
+	for key, newValue := range newFields {
+		if oldValue := oldFields[key]; !reflect.DeepEqual(oldValue, newValue) {
+			changes[key] = models.OrgSettingFieldChange{Old: oldValue, New: newValue}
+		}
+	}
+	return changes
+}
+
+// flattenSettings converts settings to a map of JSON paths to values
+func flattenSettings(settings *sharedmodels.OrgSettings) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fields
+	}
+	var sections map[string]interface{}
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return fields
+	}
+
+	for name, value := range sections {
+		if section, ok := value.(map[string]interface{}); ok {
+			for key, fieldValue := range section {
+				fields[name+"."+key] = fieldValue
+			}
+			continue
+		}
+		fields[name] = value
+	}
+	return fields
+}
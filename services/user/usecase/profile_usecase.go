@@ -16,7 +16,7 @@ import (
 
 // ProfileUsecase defines the interface for profile business logic
 type ProfileUsecase interface {
-	GetProfile(id uint) (*models.UserResponse, error)
+	GetProfile(viewer *models.UserViewer, id uint) (*models.UserListItem, error)
 	UpdateProfile(id uint, req *models.UpdateProfileRequest) (*models.UserResponse, error)
 	ChangePassword(id uint, req *models.ChangePasswordRequest) error
 	UpdateStatus(id uint, status sharedmodels.UserStatus) (*models.UserResponse, error)
@@ -24,20 +24,30 @@ type ProfileUsecase interface {
 
 // profileUsecase implements ProfileUsecase interface
 type profileUsecase struct {
-	userRepo       repository.UserRepository
-	departmentRepo repository.DepartmentRepository
+	userRepo         repository.UserRepository
+	departmentRepo   repository.DepartmentRepository
+	visibilityPolicy *models.UserVisibilityPolicy
+	orgSettings      OrgSettingsUsecase
+	events           *DepartmentEventPublisher // nil disables department events
 }
 
 // NewProfileUsecase creates a new profile usecase
-func NewProfileUsecase(userRepo repository.UserRepository, departmentRepo repository.DepartmentRepository) ProfileUsecase {
+func NewProfileUsecase(userRepo repository.UserRepository, departmentRepo repository.DepartmentRepository, visibilityPolicy *models.UserVisibilityPolicy, orgSettings OrgSettingsUsecase, events *DepartmentEventPublisher) ProfileUsecase {
+	if visibilityPolicy == nil {
+		visibilityPolicy = models.DefaultUserVisibilityPolicy()
+	}
+
 	return &profileUsecase{
-		userRepo:       userRepo,
-		departmentRepo: departmentRepo,
+		userRepo:         userRepo,
+		departmentRepo:   departmentRepo,
+		visibilityPolicy: visibilityPolicy,
+		orgSettings:      orgSettings,
+		events:           events,
 	}
 }
 
-// GetProfile retrieves a user profile by ID
-func (p *profileUsecase) GetProfile(id uint) (*models.UserResponse, error) {
+// GetProfile retrieves a user profile by ID, showing only the fields the viewer may see
+func (p *profileUsecase) GetProfile(viewer *models.UserViewer, id uint) (*models.UserListItem, error) {
 	user, err := p.userRepo.GetWithDepartment(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
@@ -51,7 +61,12 @@ func (p *profileUsecase) GetProfile(id uint) (*models.UserResponse, error) {
 		return nil, fmt.Errorf("profile is deactivated")
 	}
 
-	return user.ToResponse(), nil
+	viewerDepartmentID, err := viewerDepartment(p.userRepo, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.ToListItem(visibilityFor(p.visibilityPolicy, viewer, viewerDepartmentID, user)), nil
 }
 
 // UpdateProfile updates a user's profile
@@ -88,6 +103,10 @@ func (p *profileUsecase) UpdateProfile(id uint, req *models.UpdateProfileRequest
 	if req.Position != nil {
 		user.Position = strings.TrimSpace(*req.Position)
 	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	previousDepartmentID := user.DepartmentID
 	if req.DepartmentID != nil {
 		// Validate department exists
 		if *req.DepartmentID > 0 {
@@ -104,6 +123,8 @@ func (p *profileUsecase) UpdateProfile(id uint, req *models.UpdateProfileRequest
 		return nil, fmt.Errorf("failed to update profile: %w", err)
 	}
 
+	p.events.UserMoved(user.ID, previousDepartmentID, user.DepartmentID)
+
 	// Get user with department for response
 	userWithDept, err := p.userRepo.GetWithDepartment(user.ID)
 	if err != nil {
@@ -140,6 +161,13 @@ func (p *profileUsecase) ChangePassword(id uint, req *models.ChangePasswordReque
 		return fmt.Errorf("current password is incorrect")
 	}
 
+	// Check new password against organization password policy
+	if p.orgSettings != nil {
+		if err := p.orgSettings.CheckAccount("", req.NewPassword); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
 	// Hash new password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -244,6 +272,11 @@ func (p *profileUsecase) validateUpdateProfileRequest(req *models.UpdateProfileR
 		}
 	}
 
+	// Validate locale if provided
+	if req.Locale != nil && !req.Locale.IsValid() {
+		return fmt.Errorf("unsupported locale: %s", *req.Locale)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+)
+
+// SecurityNotifier sends account security notifications to users
+type SecurityNotifier interface {
+	NotifyUser(userID uint, locale i18n.Locale, title, message string) error
+}
+
+// httpSecurityNotifier queues security notifications in the notification service
+type httpSecurityNotifier struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSecurityNotifier creates a notifier for the notification service at baseURL.
+// It returns nil if baseURL is empty, which disables security notifications.
+func NewHTTPSecurityNotifier(baseURL string) SecurityNotifier {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpSecurityNotifier{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NotifyUser queues a high priority security notification for the user
+func (n *httpSecurityNotifier) NotifyUser(userID uint, locale i18n.Locale, title, message string) error {
+	task := map[string]interface{}{
+		"type":     "single",
+		"priority": "high",
+		"notification": map[string]interface{}{
+			"user_id":      userID,
+			"type":         "security",
+			"title":        title,
+			"message":      message,
+			"priority":     "high",
+			"related_type": "security",
+			"locale":       locale,
+		},
+	}
+
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification task: %w", err)
+	}
+
+	resp, err := n.client.Post(n.baseURL+"/api/v1/internal/notifications/task", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification task: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification service responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
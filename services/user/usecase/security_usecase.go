@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+)
+
+const (
+	defaultSecurityEventsLimit = 50
+	maxSecurityEventsLimit     = 200
+)
+
+// SecurityUsecase records account security events and notifies users about them.
+// Recording never fails the operation that triggered it: errors are logged.
+type SecurityUsecase interface {
+	RecordLogin(userID uint, client *models.ClientInfo)
+	RecordPasswordChanged(userID uint, actorID uint, client *models.ClientInfo)
+	RecordRoleChanged(userID uint, actorID uint, client *models.ClientInfo)
+	GetUserSecurityEvents(userID uint, limit int) ([]*models.SecurityEventResponse, error)
+	PurgeSecurityEvents(before time.Time) (int64, error)
+}
+
+// securityUsecase implements SecurityUsecase interface
+type securityUsecase struct {
+	securityRepo repository.SecurityRepository
+	userRepo     repository.UserRepository
+	notifier     SecurityNotifier
+}
+
+// NewSecurityUsecase creates a new security usecase. A nil notifier only records events.
+func NewSecurityUsecase(securityRepo repository.SecurityRepository, userRepo repository.UserRepository, notifier SecurityNotifier) SecurityUsecase {
+	return &securityUsecase{
+		securityRepo: securityRepo,
+		userRepo:     userRepo,
+		notifier:     notifier,
+	}
+}
+
+// RecordLogin remembers the device of a successful login. The first device of a user
+// is trusted silently, any later unknown device is reported to the user.
+func (s *securityUsecase) RecordLogin(userID uint, client *models.ClientInfo) {
+	if client == nil {
+		client = &models.ClientInfo{}
+	}
+	now := time.Now()
+	fingerprint := deviceFingerprint(client.UserAgent)
+
+	device, err := s.securityRepo.GetDevice(userID, fingerprint)
+	if err != nil {
+		s.logError(userID, models.SecurityEventNewDeviceLogin, err)
+		return
+	}
+	if device != nil {
+		device.LastIP = client.IPAddress
+		device.LastSeenAt = now
+		if err := s.securityRepo.SaveDevice(device); err != nil {
+			s.logError(userID, models.SecurityEventNewDeviceLogin, err)
+		}
+		return
+	}
+
+	known, err := s.securityRepo.CountDevices(userID)
+	if err != nil {
+		s.logError(userID, models.SecurityEventNewDeviceLogin, err)
+		return
+	}
+
+	device = &models.UserDevice{
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		UserAgent:   truncate(client.UserAgent, 500),
+		LastIP:      client.IPAddress,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+	if err := s.securityRepo.SaveDevice(device); err != nil {
+		s.logError(userID, models.SecurityEventNewDeviceLogin, err)
+		return
+	}
+	if known == 0 {
+		return
+	}
+
+	deviceName := client.UserAgent
+	if deviceName == "" {
+		deviceName = "unknown"
+	}
+	s.record(userID, models.SecurityEventNewDeviceLogin, nil, client, map[string]string{
+		"Device": truncate(deviceName, 100),
+		"IP":     client.IPAddress,
+	})
+}
+
+// RecordPasswordChanged records a password change by the user or an administrator
+func (s *securityUsecase) RecordPasswordChanged(userID uint, actorID uint, client *models.ClientInfo) {
+	s.record(userID, models.SecurityEventPasswordChanged, actorOf(userID, actorID), client, nil)
+}
+
+// RecordRoleChanged records a role change made by an administrator
+func (s *securityUsecase) RecordRoleChanged(userID uint, actorID uint, client *models.ClientInfo) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		s.logError(userID, models.SecurityEventRoleChanged, err)
+		return
+	}
+	s.record(userID, models.SecurityEventRoleChanged, actorOf(userID, actorID), client, map[string]string{
+		"Role": string(user.Role),
+	})
+}
+
+// GetUserSecurityEvents retrieves the latest security events of a user
+func (s *securityUsecase) GetUserSecurityEvents(userID uint, limit int) ([]*models.SecurityEventResponse, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if limit <= 0 {
+		limit = defaultSecurityEventsLimit
+	}
+	if limit > maxSecurityEventsLimit {
+		limit = maxSecurityEventsLimit
+	}
+
+	events, err := s.securityRepo.GetEventsByUser(userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*models.SecurityEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = event.ToResponse()
+	}
+	return responses, nil
+}
+
+// record stores the event and notifies the user in background
+func (s *securityUsecase) record(userID uint, eventType models.SecurityEventType, actorID *uint, client *models.ClientInfo, details map[string]string) {
+	event := &models.SecurityEvent{
+		UserID:  userID,
+		Type:    eventType,
+		ActorID: actorID,
+	}
+	if client != nil {
+		event.IPAddress = client.IPAddress
+		event.UserAgent = truncate(client.UserAgent, 500)
+	}
+	if len(details) > 0 {
+		if data, err := json.Marshal(details); err == nil {
+			event.Details = string(data)
+		}
+	}
+
+	if err := s.securityRepo.CreateEvent(event); err != nil {
+		s.logError(userID, eventType, err)
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"user_id":    userID,
+		"event":      eventType,
+		"actor_id":   actorID,
+		"ip_address": event.IPAddress,
+	}).Info("Security event recorded")
+
+	if s.notifier == nil {
+		return
+	}
+
+	locale := i18n.DefaultLocale
+	if user, err := s.userRepo.GetByID(userID); err == nil && user.Locale != "" {
+		locale = user.Locale
+	}
+
+	args := make(map[string]interface{}, len(details))
+	for key, value := range details {
+		args[key] = value
+	}
+	title := i18n.T(locale, "notification.security_"+securityNotificationKey(eventType)+"_title", args)
+	message := i18n.T(locale, "notification.security_"+securityNotificationKey(eventType)+"_message", args)
+
+	go func() {
+		if err := s.notifier.NotifyUser(userID, locale, title, message); err != nil {
+			s.logError(userID, eventType, err)
+		}
+	}()
+}
+
+// logError logs a failure to record or deliver a security event
+func (s *securityUsecase) logError(userID uint, eventType models.SecurityEventType, err error) {
+	logger.WithFields(map[string]interface{}{
+		"user_id": userID,
+		"event":   eventType,
+		"error":   err.Error(),
+	}).Error("Failed to process security event")
+}
+
+// securityNotificationKey returns the i18n key part of an event type
+func securityNotificationKey(eventType models.SecurityEventType) string {
+	if eventType == models.SecurityEventNewDeviceLogin {
+		return "new_device"
+	}
+	return string(eventType)
+}
+
+// actorOf returns the actor of an event, nil when the user acted on their own account
+func actorOf(userID, actorID uint) *uint {
+	if actorID == 0 || actorID == userID {
+		return nil
+	}
+	return &actorID
+}
+
+// deviceFingerprint identifies a device by its user agent
+func deviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncate shortens a string to at most max bytes
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
+
+// PurgeSecurityEvents deletes security events older than the audit log retention
+func (s *securityUsecase) PurgeSecurityEvents(before time.Time) (int64, error) {
+	return s.securityRepo.DeleteEventsBefore(before)
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"fmt"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+)
+
+// SkillUsecase manages skill tags of users and the skill directory.
+// Users declare their own skills, admins manage a separate set users can't change.
+type SkillUsecase interface {
+	GetUserSkills(userID uint) (*models.UserSkillsResponse, error)
+	UpdateMySkills(userID uint, req *models.UpdateSkillsRequest) (*models.UserSkillsResponse, error)
+	UpdateManagedSkills(adminID, userID uint, req *models.UpdateSkillsRequest) (*models.UserSkillsResponse, error)
+	ListSkills(prefix string, limit int) ([]*models.SkillCount, error)
+	SearchDirectory(req *models.SkillSearchRequest) ([]*models.SkillDirectoryEntry, int64, error)
+	FindCandidates(req *models.SkillCandidatesRequest) ([]*models.SkillDirectoryEntry, error)
+}
+
+// skillUsecase implements SkillUsecase interface
+type skillUsecase struct {
+	skillRepo repository.SkillRepository
+	userRepo  repository.UserRepository
+}
+
+// NewSkillUsecase creates a new skill usecase
+func NewSkillUsecase(skillRepo repository.SkillRepository, userRepo repository.UserRepository) SkillUsecase {
+	return &skillUsecase{
+		skillRepo: skillRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// GetUserSkills retrieves the skills of a user
+func (s *skillUsecase) GetUserSkills(userID uint) (*models.UserSkillsResponse, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	skills, err := s.skillRepo.GetByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewUserSkillsResponse(userID, skills), nil
+}
+
+// UpdateMySkills replaces the self-declared skills of a user
+func (s *skillUsecase) UpdateMySkills(userID uint, req *models.UpdateSkillsRequest) (*models.UserSkillsResponse, error) {
+	return s.updateSkills(userID, userID, models.SkillSourceSelf, req)
+}
+
+// UpdateManagedSkills replaces the admin-managed skills of a user
+func (s *skillUsecase) UpdateManagedSkills(adminID, userID uint, req *models.UpdateSkillsRequest) (*models.UserSkillsResponse, error) {
+	return s.updateSkills(userID, adminID, models.SkillSourceAdmin, req)
+}
+
+// updateSkills replaces the skills of one source of a user
+func (s *skillUsecase) updateSkills(userID, actorID uint, source models.SkillSource, req *models.UpdateSkillsRequest) (*models.UserSkillsResponse, error) {
+	skills, err := models.NormalizeSkills(req.Skills, models.MaxSkillsPerUser)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("user is deactivated")
+	}
+
+	if err := s.skillRepo.Replace(userID, source, skills, actorID); err != nil {
+		return nil, err
+	}
+	return s.GetUserSkills(userID)
+}
+
+// ListSkills lists skills in use, most common first, optionally starting with prefix
+func (s *skillUsecase) ListSkills(prefix string, limit int) ([]*models.SkillCount, error) {
+	if limit <= 0 {
+		limit = models.DefaultSkillLimit
+	}
+	if limit > models.MaxSkillMatches {
+		limit = models.MaxSkillMatches
+	}
+	return s.skillRepo.Counts(models.NormalizeSkill(prefix), limit)
+}
+
+// SearchDirectory finds active users by skills, users matching more skills come first
+func (s *skillUsecase) SearchDirectory(req *models.SkillSearchRequest) ([]*models.SkillDirectoryEntry, int64, error) {
+	skills, err := models.NormalizeSkills(req.Skills, models.MaxSearchSkills)
+	if err != nil {
+		return nil, 0, fmt.Errorf("validation failed: %w", err)
+	}
+	if len(skills) == 0 {
+		return nil, 0, fmt.Errorf("validation failed: at least one skill is required")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = models.DefaultSkillLimit
+	}
+	if limit > models.MaxSkillMatches {
+		limit = models.MaxSkillMatches
+	}
+
+	matches, total, err := s.skillRepo.Search(skills, req.MatchAll, req.DepartmentID, limit, req.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries, err := s.directoryEntries(matches, skills)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// FindCandidates finds active users with any of the skills for other services, such as
+// assignee suggestions of the task service
+func (s *skillUsecase) FindCandidates(req *models.SkillCandidatesRequest) ([]*models.SkillDirectoryEntry, error) {
+	entries, _, err := s.SearchDirectory(&models.SkillSearchRequest{Skills: req.Skills, Limit: req.Limit})
+	return entries, err
+}
+
+// directoryEntries loads users and skills of the search matches, keeping their order
+func (s *skillUsecase) directoryEntries(matches []models.SkillMatch, requested []string) ([]*models.SkillDirectoryEntry, error) {
+	entries := make([]*models.SkillDirectoryEntry, 0, len(matches))
+	if len(matches) == 0 {
+		return entries, nil
+	}
+
+	userIDs := make([]uint, len(matches))
+	for i, match := range matches {
+		userIDs[i] = match.UserID
+	}
+
+	users, err := s.userRepo.GetByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uint]*models.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	skills, err := s.skillRepo.GetByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	skillsByUser := make(map[uint][]*models.UserSkill, len(userIDs))
+	for _, skill := range skills {
+		skillsByUser[skill.UserID] = append(skillsByUser[skill.UserID], skill)
+	}
+
+	wanted := make(map[string]bool, len(requested))
+	for _, skill := range requested {
+		wanted[skill] = true
+	}
+
+	for _, match := range matches {
+		user, exists := usersByID[match.UserID]
+		if !exists {
+			continue
+		}
+
+		userSkills := models.NewUserSkillsResponse(user.ID, skillsByUser[user.ID])
+		matched := []string{}
+		for _, skill := range userSkills.Skills {
+			if wanted[skill] {
+				matched = append(matched, skill)
+			}
+		}
+
+		entries = append(entries, &models.SkillDirectoryEntry{
+			UserID:        user.ID,
+			Name:          user.Name,
+			Position:      user.Position,
+			DepartmentID:  user.DepartmentID,
+			Avatar:        user.Avatar,
+			Skills:        userSkills.Skills,
+			Managed:       userSkills.Managed,
+			MatchedSkills: matched,
+		})
+	}
+	return entries, nil
+}
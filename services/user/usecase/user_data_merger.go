@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	sharedmodels "tachyon-messenger/shared/models"
+)
+
+// UserDataMerger moves data of a duplicate account to the primary account in one service
+type UserDataMerger interface {
+	Service() string
+	MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error)
+}
+
+// httpUserDataMerger calls the internal merge endpoint of a service
+type httpUserDataMerger struct {
+	service string
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPUserDataMerger creates a merger for the service at baseURL.
+// It returns nil if baseURL is empty, which leaves the service out of account merges.
+func NewHTTPUserDataMerger(service, baseURL string) UserDataMerger {
+	if baseURL == "" {
+		return nil
+	}
+	return &httpUserDataMerger{
+		service: service,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Service returns the name of the service
+func (m *httpUserDataMerger) Service() string {
+	return m.service
+}
+
+// MergeUsers asks the service to move data of the duplicate account
+func (m *httpUserDataMerger) MergeUsers(req *sharedmodels.MergeUsersRequest) (*sharedmodels.MergeUsersResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merge request: %w", err)
+	}
+
+	resp, err := m.client.Post(m.baseURL+"/api/v1/internal/users/merge", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send merge request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s service responded with status %d", m.service, resp.StatusCode)
+	}
+
+	var payload struct {
+		Result *sharedmodels.MergeUsersResult `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode merge response: %w", err)
+	}
+	if payload.Result == nil {
+		return nil, fmt.Errorf("%s service returned no merge result", m.service)
+	}
+
+	return payload.Result, nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/shared/logger"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// MergeUsers merges a duplicate account into the primary account. Every service moves data of the
+// duplicate to the primary account, then the duplicate is deactivated. If a service fails, the merge
+// is recorded as failed and the duplicate stays active; merging again is safe as moved data is skipped.
+func (a *adminUsecase) MergeUsers(adminID uint, req *models.MergeUsersRequest) (*models.UserMergeResponse, error) {
+	if req.PrimaryUserID == req.DuplicateUserID {
+		return nil, fmt.Errorf("validation failed: cannot merge user into itself")
+	}
+
+	primary, err := a.getUser(req.PrimaryUserID, "primary")
+	if err != nil {
+		return nil, err
+	}
+	if !primary.IsActive {
+		return nil, fmt.Errorf("cannot merge into deactivated user")
+	}
+	duplicate, err := a.getUser(req.DuplicateUserID, "duplicate")
+	if err != nil {
+		return nil, err
+	}
+
+	merge := &models.UserMerge{
+		PrimaryUserID:   primary.ID,
+		DuplicateUserID: duplicate.ID,
+		MergedBy:        adminID,
+		Reason:          strings.TrimSpace(req.Reason),
+		Status:          models.UserMergeStatusInProgress,
+	}
+	if err := a.mergeRepo.Create(merge); err != nil {
+		return nil, fmt.Errorf("failed to record user merge: %w", err)
+	}
+
+	serviceReq := &sharedmodels.MergeUsersRequest{
+		PrimaryUserID:   primary.ID,
+		DuplicateUserID: duplicate.ID,
+	}
+
+	var results []*sharedmodels.MergeUsersResult
+	var failures []string
+	for _, merger := range a.mergers {
+		result, err := merger.MergeUsers(serviceReq)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"merge_id": merge.ID,
+				"service":  merger.Service(),
+				"error":    err.Error(),
+			}).Error("Failed to merge user data in service")
+
+			failures = append(failures, fmt.Sprintf("%s: %v", merger.Service(), err))
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if data, err := json.Marshal(results); err == nil {
+		merge.Results = string(data)
+	}
+
+	now := time.Now()
+	merge.CompletedAt = &now
+	if len(failures) > 0 {
+		merge.Status = models.UserMergeStatusFailed
+		merge.Error = strings.Join(failures, "; ")
+	} else {
+		// All data moved, the duplicate account is no longer used
+		duplicate.IsActive = false
+		duplicate.Status = sharedmodels.StatusOffline
+		if err := a.userRepo.Update(duplicate); err != nil {
+			merge.Status = models.UserMergeStatusFailed
+			merge.Error = fmt.Sprintf("failed to deactivate duplicate user: %v", err)
+		} else {
+			merge.Status = models.UserMergeStatusCompleted
+		}
+	}
+
+	if err := a.mergeRepo.Update(merge); err != nil {
+		return nil, fmt.Errorf("failed to record user merge: %w", err)
+	}
+
+	if merge.Status == models.UserMergeStatusFailed {
+		return merge.ToResponse(), fmt.Errorf("failed to merge users: %s", merge.Error)
+	}
+
+	return merge.ToResponse(), nil
+}
+
+// GetUserMerges retrieves the account merge audit log
+func (a *adminUsecase) GetUserMerges(limit, offset int) ([]*models.UserMergeResponse, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	merges, total, err := a.mergeRepo.GetAll(limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user merges: %w", err)
+	}
+
+	responses := make([]*models.UserMergeResponse, len(merges))
+	for i, merge := range merges {
+		responses[i] = merge.ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+// getUser retrieves a user taking part in a merge
+func (a *adminUsecase) getUser(id uint, role string) (*models.User, error) {
+	user, err := a.userRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("%s user not found", role)
+		}
+		return nil, fmt.Errorf("failed to get %s user: %w", role, err)
+	}
+	return user, nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"tachyon-messenger/services/user/models"
 	"tachyon-messenger/services/user/repository"
@@ -15,26 +16,49 @@ import (
 // UserUsecase defines the interface for user business logic
 type UserUsecase interface {
 	CreateUser(req *models.CreateUserRequest) (*models.UserResponse, error)
-	GetUser(id uint) (*models.UserResponse, error)
-	GetUsers(limit, offset int) ([]*models.UserResponse, int64, error)
+	GetUser(viewer *models.UserViewer, id uint) (*models.UserListItem, error)
+	GetUsers(viewer *models.UserViewer, req *models.UserListRequest) ([]*models.UserListItem, int64, error)
 	UpdateUser(id uint, req *models.UpdateUserRequest) (*models.UserResponse, error)
 	DeleteUser(id uint) error
+	CheckUsersExist(ids []uint) (*models.UserExistsResponse, error)
+	GetAudience(req *models.AudienceRequest) ([]uint, error)
+	MatchUsers(req *models.UserMatchRequest) (map[string][]*models.UserMatch, error)
 }
 
+// maxUserMatches limits users returned for one name, more matches make the name ambiguous anyway
+const maxUserMatches = 5
+
 // userUsecase implements UserUsecase interface
 type userUsecase struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	visibilityPolicy *models.UserVisibilityPolicy
+	orgSettings      OrgSettingsUsecase
+	events           *DepartmentEventPublisher // nil disables department events
 }
 
 // NewUserUsecase creates a new user usecase
-func NewUserUsecase(userRepo repository.UserRepository) UserUsecase {
+func NewUserUsecase(userRepo repository.UserRepository, visibilityPolicy *models.UserVisibilityPolicy, orgSettings OrgSettingsUsecase, events *DepartmentEventPublisher) UserUsecase {
+	if visibilityPolicy == nil {
+		visibilityPolicy = models.DefaultUserVisibilityPolicy()
+	}
+
 	return &userUsecase{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		visibilityPolicy: visibilityPolicy,
+		orgSettings:      orgSettings,
+		events:           events,
 	}
 }
 
 // CreateUser creates a new user
 func (u *userUsecase) CreateUser(req *models.CreateUserRequest) (*models.UserResponse, error) {
+	// Check allowed email domains and password policy of the organization
+	if u.orgSettings != nil {
+		if err := u.orgSettings.CheckAccount(req.Email, req.Password); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if user already exists
 	existingUser, err := u.userRepo.GetByEmail(req.Email)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -58,6 +82,10 @@ func (u *userUsecase) CreateUser(req *models.CreateUserRequest) (*models.UserRes
 		DepartmentID:   req.DepartmentID,
 		Position:       req.Position,
 		Phone:          req.Phone,
+		Locale:         req.Locale,
+	}
+	if user.Locale == "" && u.orgSettings != nil {
+		user.Locale = u.orgSettings.DefaultLocale()
 	}
 
 	// Set role if provided, otherwise use default
@@ -70,11 +98,13 @@ func (u *userUsecase) CreateUser(req *models.CreateUserRequest) (*models.UserRes
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	u.events.UserMoved(user.ID, nil, user.DepartmentID)
+
 	return user.ToResponse(), nil
 }
 
-// GetUser retrieves a user by ID
-func (u *userUsecase) GetUser(id uint) (*models.UserResponse, error) {
+// GetUser retrieves a user by ID, showing only the fields the viewer may see
+func (u *userUsecase) GetUser(viewer *models.UserViewer, id uint) (*models.UserListItem, error) {
 	user, err := u.userRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -83,37 +113,56 @@ func (u *userUsecase) GetUser(id uint) (*models.UserResponse, error) {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return user.ToResponse(), nil
+	viewerDepartmentID, err := viewerDepartment(u.userRepo, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.ToListItem(visibilityFor(u.visibilityPolicy, viewer, viewerDepartmentID, user)), nil
 }
 
-// GetUsers retrieves all users with pagination
-func (u *userUsecase) GetUsers(limit, offset int) ([]*models.UserResponse, int64, error) {
+// GetUsers retrieves users with pagination, showing only the fields and users the viewer may see
+func (u *userUsecase) GetUsers(viewer *models.UserViewer, req *models.UserListRequest) ([]*models.UserListItem, int64, error) {
 	// Set default pagination values
-	if limit <= 0 {
-		limit = 20
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
 	}
-	if limit > 100 {
-		limit = 100
+	if req.Offset < 0 {
+		req.Offset = 0
 	}
 
-	users, err := u.userRepo.GetAll(limit, offset)
+	// Department of the viewer decides what managers may see
+	viewerDepartmentID, err := viewerDepartment(u.userRepo, viewer)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get users: %w", err)
+		return nil, 0, err
+	}
+	if viewer.Role == sharedmodels.RoleManager {
+		if u.visibilityPolicy.ManagerScope == models.UserListScopeDepartment {
+			if viewerDepartmentID == nil {
+				return nil, 0, fmt.Errorf("access denied: no department assigned")
+			}
+			if req.DepartmentID != nil && *req.DepartmentID != *viewerDepartmentID {
+				return nil, 0, fmt.Errorf("access denied: users of other departments are not visible")
+			}
+			req.DepartmentID = viewerDepartmentID
+		}
 	}
 
-	// Get total count
-	total, err := u.userRepo.Count()
+	users, total, err := u.userRepo.List(req)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+		return nil, 0, fmt.Errorf("failed to get users: %w", err)
 	}
 
 	// Convert to response format
-	responses := make([]*models.UserResponse, len(users))
+	items := make([]*models.UserListItem, len(users))
 	for i, user := range users {
-		responses[i] = user.ToResponse()
+		items[i] = user.ToListItem(visibilityFor(u.visibilityPolicy, viewer, viewerDepartmentID, user))
 	}
 
-	return responses, total, nil
+	return items, total, nil
 }
 
 // UpdateUser updates an existing user
@@ -127,6 +176,8 @@ func (u *userUsecase) UpdateUser(id uint, req *models.UpdateUserRequest) (*model
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	previousDepartmentID := user.DepartmentID
+
 	// Update fields if provided
 	if req.Name != nil {
 		user.Name = *req.Name
@@ -155,9 +206,66 @@ func (u *userUsecase) UpdateUser(id uint, req *models.UpdateUserRequest) (*model
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	u.events.UserMoved(user.ID, previousDepartmentID, user.DepartmentID)
+
 	return user.ToResponse(), nil
 }
 
+// CheckUsersExist reports which of the users exist, so other services can reject dangling user IDs
+func (u *userUsecase) CheckUsersExist(ids []uint) (*models.UserExistsResponse, error) {
+	existing, err := u.userRepo.ExistingIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+
+	response := &models.UserExistsResponse{Existing: existing, Missing: []uint{}}
+	for _, id := range ids {
+		if !found[id] {
+			found[id] = true // report duplicates once
+			response.Missing = append(response.Missing, id)
+		}
+	}
+	return response, nil
+}
+
+// GetAudience returns IDs of active users in the requested departments, so other services
+// can fan out company-wide content to all or selected departments
+func (u *userUsecase) GetAudience(req *models.AudienceRequest) ([]uint, error) {
+	return u.userRepo.ActiveIDs(req.DepartmentIDs)
+}
+
+// MatchUsers finds active users by names mentioned in free text, such as participants of an event
+// described in a sentence, so that other services can resolve them to user IDs
+func (u *userUsecase) MatchUsers(req *models.UserMatchRequest) (map[string][]*models.UserMatch, error) {
+	matches := make(map[string][]*models.UserMatch, len(req.Names))
+	for _, name := range req.Names {
+		name = strings.TrimSpace(name)
+		if _, done := matches[name]; done || name == "" {
+			continue
+		}
+
+		users, err := u.userRepo.MatchName(name, maxUserMatches)
+		if err != nil {
+			return nil, err
+		}
+		matches[name] = make([]*models.UserMatch, 0, len(users))
+		for _, user := range users {
+			matches[name] = append(matches[name], &models.UserMatch{
+				ID:           user.ID,
+				Name:         user.Name,
+				Position:     user.Position,
+				DepartmentID: user.DepartmentID,
+			})
+		}
+	}
+	return matches, nil
+}
+
 // DeleteUser deletes a user by ID
 func (u *userUsecase) DeleteUser(id uint) error {
 	// Check if user exists
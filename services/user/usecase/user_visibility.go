@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"tachyon-messenger/services/user/models"
+	"tachyon-messenger/services/user/repository"
+	sharedmodels "tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+// viewerDepartment returns the department of a manager viewer, nil for other roles,
+// since only managers see more of users of their own department
+func viewerDepartment(userRepo repository.UserRepository, viewer *models.UserViewer) (*uint, error) {
+	if viewer.Role != sharedmodels.RoleManager {
+		return nil, nil
+	}
+
+	viewerUser, err := userRepo.GetByID(viewer.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return viewerUser.DepartmentID, nil
+}
+
+// visibilityFor returns the fields of a user the viewer may see
+func visibilityFor(policy *models.UserVisibilityPolicy, viewer *models.UserViewer, viewerDepartmentID *uint, user *models.User) models.UserVisibility {
+	switch {
+	case viewer.Role == sharedmodels.RoleAdmin || viewer.Role == sharedmodels.RoleSuperAdmin:
+		return models.UserVisibilityFull
+	case viewer.ID == user.ID:
+		return models.UserVisibilityFull
+	case viewer.Role == sharedmodels.RoleManager && viewerDepartmentID != nil &&
+		user.DepartmentID != nil && *user.DepartmentID == *viewerDepartmentID:
+		return policy.ManagerVisibility
+	default:
+		return models.UserVisibilityBasic
+	}
+}
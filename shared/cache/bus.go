@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/redis"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultChannel is the pub/sub channel shared by all services
+const DefaultChannel = "cache:invalidate"
+
+// invalidator is a cache that drops entries by tags
+type invalidator interface {
+	Name() string
+	InvalidateTags(tags ...string) int
+	Stats() Stats
+}
+
+// invalidation is a message broadcast on the bus
+type invalidation struct {
+	Origin string   `json:"origin"` // Bus that published the message, it has applied it already
+	Tags   []string `json:"tags"`
+}
+
+// Bus broadcasts changed objects to caches of all instances through Redis pub/sub.
+// Delivery is best effort, entries missed by a broadcast live until their TTL. Without
+// Redis the bus only invalidates caches of the instance. All methods are safe on a nil bus,
+// which does nothing.
+type Bus struct {
+	client  *redis.Client
+	channel string
+	origin  string
+
+	mu     sync.Mutex
+	caches []invalidator
+
+	published atomic.Int64
+	received  atomic.Int64
+}
+
+// NewBus creates an invalidation bus on channel, empty channel uses DefaultChannel.
+// A nil client keeps invalidation local to the instance.
+func NewBus(client *redis.Client, channel string) *Bus {
+	if channel == "" {
+		channel = DefaultChannel
+	}
+
+	origin := make([]byte, 8)
+	_, _ = rand.Read(origin)
+
+	return &Bus{
+		client:  client,
+		channel: channel,
+		origin:  hex.EncodeToString(origin),
+	}
+}
+
+// register adds a cache invalidated by the bus
+func (b *Bus) register(c invalidator) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.caches = append(b.caches, c)
+}
+
+// Invalidate drops entries with any of the tags from the caches of this instance and
+// broadcasts the tags to other instances, e.g. Invalidate(Tag(KindUser, 42)) after user 42
+// changed
+func (b *Bus) Invalidate(ctx context.Context, tags ...string) error {
+	if b == nil || len(tags) == 0 {
+		return nil
+	}
+
+	b.apply(tags)
+
+	if b.client == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(invalidation{Origin: b.origin, Tags: tags})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache invalidation: %w", err)
+	}
+	if err := b.client.Client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish cache invalidation: %w", err)
+	}
+
+	b.published.Add(1)
+	return nil
+}
+
+// Run applies invalidations broadcast by other instances until ctx is done
+func (b *Bus) Run(ctx context.Context) error {
+	if b == nil || b.client == nil {
+		return nil
+	}
+
+	pubsub := b.client.Client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to cache invalidations: %w", err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var event invalidation
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				logger.WithField("error", err.Error()).Warn("Failed to decode cache invalidation")
+				continue
+			}
+			if event.Origin == b.origin {
+				continue
+			}
+
+			b.received.Add(1)
+			b.apply(event.Tags)
+		}
+	}
+}
+
+// apply drops entries with the tags from all registered caches
+func (b *Bus) apply(tags []string) {
+	b.mu.Lock()
+	caches := append([]invalidator(nil), b.caches...)
+	b.mu.Unlock()
+
+	for _, c := range caches {
+		c.InvalidateTags(tags...)
+	}
+}
+
+// Stats returns counters of all registered caches, sorted by name
+func (b *Bus) Stats() []Stats {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	stats := make([]Stats, 0, len(b.caches))
+	for _, c := range b.caches {
+		stats = append(stats, c.Stats())
+	}
+	b.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// WriteMetrics writes counters of the bus and its caches in Prometheus text format
+func (b *Bus) WriteMetrics(out io.Writer, service string) error {
+	if b == nil {
+		return nil
+	}
+
+	stats := b.Stats()
+
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value func(Stats) float64
+	}{
+		{"cache_entries", "gauge", "Entries in the cache",
+			func(s Stats) float64 { return float64(s.Entries) }},
+		{"cache_hits_total", "counter", "Lookups served from the cache",
+			func(s Stats) float64 { return float64(s.Hits) }},
+		{"cache_misses_total", "counter", "Lookups not found in the cache",
+			func(s Stats) float64 { return float64(s.Misses) }},
+		{"cache_invalidations_total", "counter", "Entries dropped by invalidated tags",
+			func(s Stats) float64 { return float64(s.Invalidations) }},
+		{"cache_evictions_total", "counter", "Entries dropped to stay within the size limit",
+			func(s Stats) float64 { return float64(s.Evictions) }},
+	}
+
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind); err != nil {
+			return err
+		}
+		for _, cache := range stats {
+			if _, err := fmt.Fprintf(out, "%s{service=%q,cache=%q} %g\n",
+				metric.name, service, cache.Name, metric.value(cache)); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(out,
+		"# HELP cache_invalidation_messages_published_total Invalidations broadcast to other instances\n"+
+			"# TYPE cache_invalidation_messages_published_total counter\n"+
+			"cache_invalidation_messages_published_total{service=%q} %d\n"+
+			"# HELP cache_invalidation_messages_received_total Invalidations received from other instances\n"+
+			"# TYPE cache_invalidation_messages_received_total counter\n"+
+			"cache_invalidation_messages_received_total{service=%q} %d\n",
+		service, b.published.Load(), service, b.received.Load())
+	return err
+}
+
+// MetricsHandler serves counters of the bus and its caches in Prometheus text format
+func (b *Bus) MetricsHandler(service string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4")
+		if err := b.WriteMetrics(c.Writer, service); err != nil {
+			logger.WithField("error", err.Error()).Error("Failed to write cache metrics")
+		}
+	}
+}
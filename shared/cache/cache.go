@@ -0,0 +1,264 @@
+// Package cache keeps short-lived copies of data owned by other services, such as user
+// lookups, notification preferences or department info.
+//
+//   - entries are tagged with the objects they were built from, e.g. user:42 or department:7
+//   - a change of an object is broadcast on the invalidation bus, and caches of all instances
+//     and services drop the entries tagged with it
+//   - entries also expire after the cache TTL, which bounds staleness when a broadcast is lost
+//   - hits, misses and invalidations are exported in Prometheus text format
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long entries are kept when the cache has no TTL of its own
+	DefaultTTL = 5 * time.Minute
+
+	// DefaultMaxEntries bounds caches created without a limit
+	DefaultMaxEntries = 10000
+)
+
+// Tag kinds of objects shared between services
+const (
+	KindUser        = "user"
+	KindDepartment  = "department"
+	KindPreferences = "preferences" // Notification preferences of a user
+)
+
+// Tag returns the tag of an object, e.g. Tag(KindUser, 42) is "user:42"
+func Tag(kind string, id uint) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
+
+// Stats represents counters of a cache
+type Stats struct {
+	Name          string `json:"name"`
+	Entries       int    `json:"entries"`
+	Hits          int64  `json:"hits"`
+	Misses        int64  `json:"misses"`
+	Invalidations int64  `json:"invalidations"` // Entries dropped by invalidated tags
+	Evictions     int64  `json:"evictions"`     // Entries dropped to stay within the size limit
+}
+
+// entry is a cached value with its tags
+type entry[V any] struct {
+	value     V
+	tags      []string
+	expiresAt time.Time
+}
+
+// Cache is an in-memory cache of tagged entries. It is safe for concurrent use.
+type Cache[V any] struct {
+	name       string
+	ttl        time.Duration
+	maxEntries int
+
+	mu         sync.Mutex
+	entries    map[string]*entry[V]
+	byTag      map[string]map[string]struct{} // Tag -> keys of entries with the tag
+	generation uint64                         // Incremented by every invalidation
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+	evictions     atomic.Int64
+}
+
+// New creates a cache and registers it with the bus, so it drops entries of tags invalidated
+// by any instance. A nil bus keeps invalidation local. Zero ttl uses DefaultTTL, zero
+// maxEntries uses DefaultMaxEntries.
+func New[V any](bus *Bus, name string, ttl time.Duration, maxEntries int) *Cache[V] {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	c := &Cache[V]{
+		name:       name,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*entry[V]),
+		byTag:      make(map[string]map[string]struct{}),
+	}
+	bus.register(c)
+	return c
+}
+
+// Name returns the name the cache is reported under
+func (c *Cache[V]) Name() string {
+	return c.name
+}
+
+// Get returns a cached value
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		if time.Now().Before(e.expiresAt) {
+			c.hits.Add(1)
+			return e.value, true
+		}
+		c.remove(key)
+	}
+
+	c.misses.Add(1)
+	var zero V
+	return zero, false
+}
+
+// Set caches a value with the tags of the objects it was built from
+func (c *Cache[V]) Set(key string, value V, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, tags)
+}
+
+// GetOrLoad returns a cached value or loads and caches it. A value loaded while its tags
+// were invalidated is returned but not cached, it may already be stale.
+func (c *Cache[V]) GetOrLoad(key string, load func() (V, []string, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, tags, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.set(key, value, tags)
+	}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// Delete drops an entry
+func (c *Cache[V]) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+}
+
+// InvalidateTags drops entries with any of the tags and returns their number. Use
+// Bus.Invalidate to drop the entries on all instances.
+func (c *Cache[V]) InvalidateTags(tags ...string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	dropped := 0
+	for _, tag := range tags {
+		for key := range c.byTag[tag] {
+			c.remove(key)
+			dropped++
+		}
+	}
+
+	c.invalidations.Add(int64(dropped))
+	return dropped
+}
+
+// Clear drops all entries
+func (c *Cache[V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*entry[V])
+	c.byTag = make(map[string]map[string]struct{})
+}
+
+// Stats returns counters of the cache
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return Stats{
+		Name:          c.name,
+		Entries:       entries,
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+		Evictions:     c.evictions.Load(),
+	}
+}
+
+// set caches a value, c.mu must be held
+func (c *Cache[V]) set(key string, value V, tags []string) {
+	c.remove(key)
+	if len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+
+	c.entries[key] = &entry[V]{
+		value:     value,
+		tags:      tags,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	for _, tag := range tags {
+		keys, ok := c.byTag[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.byTag[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// remove drops an entry and its tag references, c.mu must be held
+func (c *Cache[V]) remove(key string) {
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(c.entries, key)
+	for _, tag := range e.tags {
+		if keys, ok := c.byTag[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(c.byTag, tag)
+			}
+		}
+	}
+}
+
+// evict makes room for a new entry: expired entries are dropped, if there are none an
+// arbitrary entry is, c.mu must be held
+func (c *Cache[V]) evict() {
+	now := time.Now()
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			c.remove(key)
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+
+	for key := range c.entries {
+		c.remove(key)
+		c.evictions.Add(1)
+		if len(c.entries) < c.maxEntries {
+			return
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetSetCountsHitsAndMisses(t *testing.T) {
+	c := New[string](nil, "users", time.Minute, 0)
+
+	if _, ok := c.Get("42"); ok {
+		t.Fatal("Get() found a value in an empty cache")
+	}
+	c.Set("42", "Alice", Tag(KindUser, 42))
+	if value, ok := c.Get("42"); !ok || value != "Alice" {
+		t.Fatalf("Get() = %q, %v, want Alice, true", value, ok)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Stats() = %+v, want 1 hit, 1 miss, 1 entry", stats)
+	}
+}
+
+func TestEntriesExpire(t *testing.T) {
+	c := New[int](nil, "short", time.Millisecond, 0)
+	c.Set("key", 1)
+
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("key"); ok {
+		t.Error("Get() returned an expired entry")
+	}
+	if entries := c.Stats().Entries; entries != 0 {
+		t.Errorf("expired entry is kept, entries = %d", entries)
+	}
+}
+
+func TestInvalidateTagsDropsTaggedEntries(t *testing.T) {
+	c := New[string](nil, "members", time.Minute, 0)
+	c.Set("department:7:members", "Alice, Bob", Tag(KindDepartment, 7), Tag(KindUser, 1), Tag(KindUser, 2))
+	c.Set("user:1", "Alice", Tag(KindUser, 1))
+	c.Set("user:3", "Carol", Tag(KindUser, 3))
+
+	if dropped := c.InvalidateTags(Tag(KindUser, 1)); dropped != 2 {
+		t.Errorf("InvalidateTags() = %d, want 2", dropped)
+	}
+	for _, key := range []string{"department:7:members", "user:1"} {
+		if _, ok := c.Get(key); ok {
+			t.Errorf("entry %s survived invalidation", key)
+		}
+	}
+	if _, ok := c.Get("user:3"); !ok {
+		t.Error("entry of another user was invalidated")
+	}
+	if invalidations := c.Stats().Invalidations; invalidations != 2 {
+		t.Errorf("invalidations = %d, want 2", invalidations)
+	}
+}
+
+func TestGetOrLoadSkipsValuesInvalidatedWhileLoading(t *testing.T) {
+	c := New[string](nil, "users", time.Minute, 0)
+
+	loads := 0
+	load := func() (string, []string, error) {
+		loads++
+		if loads == 1 {
+			c.InvalidateTags(Tag(KindUser, 42)) // The user changes while it is loaded
+		}
+		return "Alice", []string{Tag(KindUser, 42)}, nil
+	}
+
+	if value, err := c.GetOrLoad("42", load); err != nil || value != "Alice" {
+		t.Fatalf("GetOrLoad() = %q, %v", value, err)
+	}
+	if _, ok := c.Get("42"); ok {
+		t.Error("value loaded during invalidation was cached")
+	}
+
+	if _, err := c.GetOrLoad("42", load); err != nil {
+		t.Fatalf("GetOrLoad() error = %v", err)
+	}
+	if _, err := c.GetOrLoad("42", load); err != nil {
+		t.Fatalf("GetOrLoad() error = %v", err)
+	}
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2", loads)
+	}
+}
+
+func TestGetOrLoadDoesNotCacheErrors(t *testing.T) {
+	c := New[string](nil, "users", time.Minute, 0)
+	failure := errors.New("user service is unavailable")
+
+	if _, err := c.GetOrLoad("42", func() (string, []string, error) { return "", nil, failure }); !errors.Is(err, failure) {
+		t.Fatalf("GetOrLoad() error = %v, want %v", err, failure)
+	}
+	if entries := c.Stats().Entries; entries != 0 {
+		t.Errorf("failed load was cached, entries = %d", entries)
+	}
+}
+
+func TestMaxEntriesEvicts(t *testing.T) {
+	c := New[int](nil, "small", time.Minute, 2)
+	c.Set("a", 1, "tag")
+	c.Set("b", 2, "tag")
+	c.Set("c", 3, "tag")
+
+	stats := c.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("Stats() = %+v, want 2 entries, 1 eviction", stats)
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("newest entry was evicted")
+	}
+	if dropped := c.InvalidateTags("tag"); dropped != 2 {
+		t.Errorf("InvalidateTags() = %d, evicted entry kept its tag", dropped)
+	}
+}
+
+func TestBusInvalidatesRegisteredCachesLocally(t *testing.T) {
+	bus := NewBus(nil, "")
+	users := New[string](bus, "users", time.Minute, 0)
+	preferences := New[bool](bus, "preferences", time.Minute, 0)
+	users.Set("42", "Alice", Tag(KindUser, 42))
+	preferences.Set("42:email", true, Tag(KindUser, 42), Tag(KindPreferences, 42))
+
+	if err := bus.Invalidate(context.Background(), Tag(KindUser, 42)); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if _, ok := users.Get("42"); ok {
+		t.Error("users cache kept invalidated entry")
+	}
+	if _, ok := preferences.Get("42:email"); ok {
+		t.Error("preferences cache kept invalidated entry")
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var bus *Bus
+	c := New[int](bus, "users", time.Minute, 0)
+	c.Set("1", 1)
+
+	if err := bus.Invalidate(context.Background(), Tag(KindUser, 1)); err != nil {
+		t.Errorf("Invalidate() error = %v", err)
+	}
+	if err := bus.Run(context.Background()); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if stats := bus.Stats(); stats != nil {
+		t.Errorf("Stats() = %v, want nil", stats)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	bus := NewBus(nil, "")
+	c := New[string](bus, "users", time.Minute, 0)
+	c.Set("42", "Alice", Tag(KindUser, 42))
+	c.Get("42")
+	c.Get("43")
+
+	var out strings.Builder
+	if err := bus.WriteMetrics(&out, "chat-service"); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+
+	for _, line := range []string{
+		`cache_hits_total{service="chat-service",cache="users"} 1`,
+		`cache_misses_total{service="chat-service",cache="users"} 1`,
+		`cache_entries{service="chat-service",cache="users"} 1`,
+		`cache_invalidation_messages_published_total{service="chat-service"} 0`,
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("metrics are missing %q:\n%s", line, out.String())
+		}
+	}
+}
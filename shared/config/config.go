@@ -1,9 +1,11 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +16,9 @@ type Config struct {
 	Redis    RedisConfig
 	JWT      JWTConfig
 	Server   ServerConfig
+
+	// Secrets fetches credentials from the provider selected by SECRETS_PROVIDER
+	Secrets *Secrets
 }
 
 // DatabaseConfig holds database configuration
@@ -85,9 +90,26 @@ func LoadConfig() (*Config, error) {
 		fmt.Println("⚠️  No .env file loaded, using only environment variables")
 	}
 
+	// Учётные данные берём из провайдера секретов, по умолчанию из окружения
+	secrets, err := NewSecretsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure secrets provider: %w", err)
+	}
+	fmt.Printf("Secrets provider: %s\n", secrets.Backend())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	jwtSecret, err := secrets.Get(ctx, SecretJWT)
+	if err != nil {
+		return nil, err
+	}
+	databaseURL, err := secrets.Get(ctx, SecretDatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+
 	// Получаем переменные окружения
-	jwtSecret := os.Getenv("JWT_SECRET")
-	databaseURL := os.Getenv("DATABASE_URL")
 	redisURL := os.Getenv("REDIS_URL")
 	serverPort := os.Getenv("SERVER_PORT")
 
@@ -120,6 +142,7 @@ func LoadConfig() (*Config, error) {
 		Server: ServerConfig{
 			Port: serverPort,
 		},
+		Secrets: secrets,
 	}
 
 	// Validate required fields
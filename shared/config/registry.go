@@ -0,0 +1,597 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+)
+
+// Service names known to the registry. Inter-service clients address a service with
+// the logical URL returned by Registry.BaseURL, e.g. http://user-service.
+const (
+	UserService         = "user-service"
+	ChatService         = "chat-service"
+	TaskService         = "task-service"
+	CalendarService     = "calendar-service"
+	PollService         = "poll-service"
+	NotificationService = "notification-service"
+	FileService         = "file-service"
+	AnalyticsService    = "analytics-service"
+)
+
+// ServiceNames lists all services resolved by the registry
+var ServiceNames = []string{
+	UserService, ChatService, TaskService, CalendarService,
+	PollService, NotificationService, FileService, AnalyticsService,
+}
+
+const (
+	// DefaultRegistryRefresh is how often endpoints are looked up and health checked
+	DefaultRegistryRefresh = 30 * time.Second
+
+	// DefaultEndpointCooldown is how long an endpoint is skipped after a failed request
+	DefaultEndpointCooldown = 30 * time.Second
+
+	// DefaultHealthPath is requested on every endpoint to check its health
+	DefaultHealthPath = "/health"
+)
+
+// RegistryBackend looks up endpoints of a service
+type RegistryBackend interface {
+	// Name identifies the backend in logs and health output
+	Name() string
+
+	// Lookup returns base URLs of all instances of the service. Dynamic backends return
+	// an empty list for unknown services, the static backend for unconfigured ones.
+	Lookup(ctx context.Context, service string) ([]string, error)
+
+	// Static reports whether endpoints never change, so refreshing them is not needed
+	Static() bool
+}
+
+// EndpointStatus describes one instance of a service
+type EndpointStatus struct {
+	URL       string     `json:"url"`
+	Healthy   bool       `json:"healthy"`
+	DownUntil *time.Time `json:"down_until,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// endpoint is the state of one service instance
+type endpoint struct {
+	url       *url.URL
+	healthy   bool
+	downUntil time.Time
+	lastError string
+	checkedAt time.Time
+}
+
+// available reports whether requests may be sent to the endpoint
+func (e *endpoint) available(now time.Time) bool {
+	return e.healthy && !now.Before(e.downUntil)
+}
+
+// Registry resolves services to healthy endpoints. Endpoints come from a backend (static
+// environment URLs, DNS SRV records or Consul) and are refreshed in the background, so
+// services can move without restarting their clients.
+//
+// Clients use logical URLs from BaseURL; the transport returned by Transport sends their
+// requests to an available endpoint of the service, round robin. An endpoint is skipped
+// while its health check fails and for a cooldown after a request to it failed.
+type Registry struct {
+	backend    RegistryBackend
+	refresh    time.Duration
+	cooldown   time.Duration
+	healthPath string
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	endpoints map[string][]*endpoint
+	next      map[string]int
+}
+
+// RegistryOptions configures a registry, zero values use defaults
+type RegistryOptions struct {
+	Refresh    time.Duration
+	Cooldown   time.Duration
+	HealthPath string
+}
+
+// NewRegistry creates a registry on top of backend and looks up all services once
+func NewRegistry(backend RegistryBackend, options RegistryOptions) *Registry {
+	if options.Refresh <= 0 {
+		options.Refresh = DefaultRegistryRefresh
+	}
+	if options.Cooldown <= 0 {
+		options.Cooldown = DefaultEndpointCooldown
+	}
+	if options.HealthPath == "" {
+		options.HealthPath = DefaultHealthPath
+	}
+
+	registry := &Registry{
+		backend:    backend,
+		refresh:    options.Refresh,
+		cooldown:   options.Cooldown,
+		healthPath: options.HealthPath,
+		httpClient: &http.Client{Timeout: 2 * time.Second},
+		endpoints:  make(map[string][]*endpoint),
+		next:       make(map[string]int),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	registry.Refresh(ctx)
+
+	return registry
+}
+
+// NewRegistryFromEnv creates a registry configured with SERVICE_REGISTRY (static, dns or
+// consul), SERVICE_REGISTRY_REFRESH, SERVICE_REGISTRY_COOLDOWN and
+// SERVICE_REGISTRY_HEALTH_PATH. Static URLs missing from the environment fall back to
+// defaults, which may be nil.
+func NewRegistryFromEnv(defaults map[string]string) (*Registry, error) {
+	var backend RegistryBackend
+	switch kind := strings.ToLower(strings.TrimSpace(os.Getenv("SERVICE_REGISTRY"))); kind {
+	case "", "static":
+		backend = NewStaticBackend(defaults)
+	case "dns":
+		domain := strings.TrimSpace(os.Getenv("SERVICE_REGISTRY_DNS_DOMAIN"))
+		if domain == "" {
+			return nil, fmt.Errorf("SERVICE_REGISTRY_DNS_DOMAIN is required for the dns registry")
+		}
+		backend = NewDNSBackend(domain)
+	case "consul":
+		backend = NewConsulBackend(os.Getenv("CONSUL_HTTP_ADDR"), os.Getenv("CONSUL_HTTP_TOKEN"))
+	default:
+		return nil, fmt.Errorf("unknown SERVICE_REGISTRY %q", kind)
+	}
+
+	var options RegistryOptions
+	for key, target := range map[string]*time.Duration{
+		"SERVICE_REGISTRY_REFRESH":  &options.Refresh,
+		"SERVICE_REGISTRY_COOLDOWN": &options.Cooldown,
+	} {
+		value := strings.TrimSpace(os.Getenv(key))
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*target = duration
+	}
+	options.HealthPath = strings.TrimSpace(os.Getenv("SERVICE_REGISTRY_HEALTH_PATH"))
+
+	return NewRegistry(backend, options), nil
+}
+
+// Backend returns the name of the registry backend
+func (r *Registry) Backend() string {
+	return r.backend.Name()
+}
+
+// BaseURL returns the logical URL of a service for inter-service clients, or an empty
+// string if the static backend has no URL for it, so optional clients stay disabled
+func (r *Registry) BaseURL(service string) string {
+	if r.backend.Static() && len(r.Endpoints(service)) == 0 {
+		return ""
+	}
+	return "http://" + service
+}
+
+// Endpoints returns the state of all known endpoints of a service
+func (r *Registry) Endpoints(service string) []EndpointStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	statuses := make([]EndpointStatus, 0, len(r.endpoints[service]))
+	for _, e := range r.endpoints[service] {
+		status := EndpointStatus{
+			URL:       e.url.String(),
+			Healthy:   e.healthy,
+			LastError: e.lastError,
+		}
+		if now.Before(e.downUntil) {
+			downUntil := e.downUntil
+			status.DownUntil = &downUntil
+		}
+		if !e.checkedAt.IsZero() {
+			checkedAt := e.checkedAt
+			status.CheckedAt = &checkedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Resolve picks an endpoint of a service, round robin over available endpoints. If none
+// is available all endpoints are tried in turn, a possibly recovered instance is better
+// than failing the request outright.
+func (r *Registry) Resolve(service string) (*url.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoints := r.endpoints[service]
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoints registered for %s", service)
+	}
+
+	now := time.Now()
+	candidates := make([]*endpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e.available(now) {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = endpoints
+	}
+
+	index := r.next[service] % len(candidates)
+	r.next[service] = index + 1
+
+	resolved := *candidates[index].url
+	return &resolved, nil
+}
+
+// ReportFailure skips the endpoint of a service for the cooldown after a failed request
+func (r *Registry) ReportFailure(service string, endpointURL *url.URL, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.endpoints[service] {
+		if e.url.Host == endpointURL.Host && e.url.Scheme == endpointURL.Scheme {
+			e.downUntil = time.Now().Add(r.cooldown)
+			e.lastError = err.Error()
+		}
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"service":  service,
+		"endpoint": endpointURL.String(),
+		"error":    err.Error(),
+		"cooldown": r.cooldown.String(),
+	}).Warn("Service endpoint failed, skipping it for a while")
+}
+
+// Refresh looks up endpoints of all services. State of endpoints that are still
+// registered is kept; if a lookup fails the previous endpoints are used.
+func (r *Registry) Refresh(ctx context.Context) {
+	for _, service := range ServiceNames {
+		urls, err := r.backend.Lookup(ctx, service)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"service": service,
+				"backend": r.backend.Name(),
+				"error":   err.Error(),
+			}).Warn("Failed to look up service endpoints")
+			continue
+		}
+
+		r.setEndpoints(service, urls)
+	}
+}
+
+// setEndpoints replaces endpoints of a service, keeping state of known ones
+func (r *Registry) setEndpoints(service string, urls []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	known := make(map[string]*endpoint, len(r.endpoints[service]))
+	for _, e := range r.endpoints[service] {
+		known[e.url.String()] = e
+	}
+
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, raw := range urls {
+		parsed, err := url.Parse(strings.TrimRight(strings.TrimSpace(raw), "/"))
+		if err != nil || parsed.Host == "" {
+			logger.WithFields(map[string]interface{}{
+				"service": service,
+				"url":     raw,
+			}).Warn("Ignoring invalid service endpoint")
+			continue
+		}
+		if e, ok := known[parsed.String()]; ok {
+			endpoints = append(endpoints, e)
+			continue
+		}
+		// New endpoints are trusted until their first health check
+		endpoints = append(endpoints, &endpoint{url: parsed, healthy: true})
+	}
+
+	if len(endpoints) != len(r.endpoints[service]) {
+		logger.WithFields(map[string]interface{}{
+			"service":   service,
+			"endpoints": len(endpoints),
+		}).Info("Service endpoints changed")
+	}
+	r.endpoints[service] = endpoints
+}
+
+// CheckHealth requests the health path of every endpoint in parallel
+func (r *Registry) CheckHealth(ctx context.Context) {
+	r.mu.RLock()
+	var endpoints []*endpoint
+	for _, service := range ServiceNames {
+		endpoints = append(endpoints, r.endpoints[service]...)
+	}
+	r.mu.RUnlock()
+
+	results := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, target url.URL) {
+			defer wg.Done()
+			results[i] = r.checkEndpoint(ctx, target)
+		}(i, *e.url)
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i, e := range endpoints {
+		e.checkedAt = now
+		e.healthy = results[i] == nil
+		if results[i] != nil {
+			e.lastError = results[i].Error()
+		} else if !now.Before(e.downUntil) {
+			e.lastError = ""
+		}
+	}
+}
+
+// checkEndpoint requests the health path of an endpoint
+func (r *Registry) checkEndpoint(ctx context.Context, target url.URL) error {
+	target.Path = strings.TrimRight(target.Path, "/") + r.healthPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health check request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Run refreshes endpoints and checks their health until ctx is done
+func (r *Registry) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.refresh)
+	defer ticker.Stop()
+
+	for {
+		if !r.backend.Static() {
+			r.Refresh(ctx)
+		}
+		r.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Transport returns a round tripper that sends requests to logical service URLs
+// (http://<service>) to an endpoint of the service and passes other requests to base
+func (r *Registry) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &registryTransport{registry: r, base: base}
+}
+
+// registryTransport resolves logical service URLs
+type registryTransport struct {
+	registry *Registry
+	base     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *registryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := req.URL.Host
+	if !isServiceName(service) {
+		return t.base.RoundTrip(req)
+	}
+
+	target, err := t.registry.Resolve(service)
+	if err != nil {
+		return nil, err
+	}
+
+	out := req.Clone(req.Context())
+	out.URL.Scheme = target.Scheme
+	out.URL.Host = target.Host
+	out.URL.Path = target.Path + req.URL.Path
+	out.URL.RawPath = ""
+	out.Host = ""
+
+	resp, err := t.base.RoundTrip(out)
+	if err != nil {
+		t.registry.ReportFailure(service, target, err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// isServiceName reports whether host is a logical service name
+func isServiceName(host string) bool {
+	for _, service := range ServiceNames {
+		if host == service {
+			return true
+		}
+	}
+	return false
+}
+
+// staticBackend reads service URLs from the environment
+type staticBackend struct {
+	defaults map[string]string
+}
+
+// NewStaticBackend creates a backend reading <SERVICE>_URL variables, e.g. USER_SERVICE_URL
+// for user-service. A variable may list several comma separated URLs.
+func NewStaticBackend(defaults map[string]string) RegistryBackend {
+	return &staticBackend{defaults: defaults}
+}
+
+func (b *staticBackend) Name() string { return "static" }
+
+func (b *staticBackend) Static() bool { return true }
+
+func (b *staticBackend) Lookup(ctx context.Context, service string) ([]string, error) {
+	value := os.Getenv(ServiceURLEnv(service))
+	if value == "" {
+		value = b.defaults[service]
+	}
+
+	var urls []string
+	for _, raw := range strings.Split(value, ",") {
+		if raw = strings.TrimSpace(raw); raw != "" {
+			urls = append(urls, raw)
+		}
+	}
+	return urls, nil
+}
+
+// ServiceURLEnv returns the environment variable holding static URLs of a service
+func ServiceURLEnv(service string) string {
+	return strings.ToUpper(strings.ReplaceAll(service, "-", "_")) + "_URL"
+}
+
+// dnsBackend resolves _http._tcp SRV records
+type dnsBackend struct {
+	domain   string
+	resolver *net.Resolver
+}
+
+// NewDNSBackend creates a backend resolving SRV records _http._tcp.<service>.<domain>
+func NewDNSBackend(domain string) RegistryBackend {
+	return &dnsBackend{domain: strings.Trim(domain, "."), resolver: net.DefaultResolver}
+}
+
+func (b *dnsBackend) Name() string { return "dns" }
+
+func (b *dnsBackend) Static() bool { return false }
+
+func (b *dnsBackend) Lookup(ctx context.Context, service string) ([]string, error) {
+	_, records, err := b.resolver.LookupSRV(ctx, "http", "tcp", service+"."+b.domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve SRV records: %w", err)
+	}
+
+	// Only the most preferred priority is used, the others are fallbacks
+	sort.Slice(records, func(i, j int) bool { return records[i].Priority < records[j].Priority })
+	var urls []string
+	for _, record := range records {
+		if record.Priority != records[0].Priority {
+			break
+		}
+		host := strings.TrimSuffix(record.Target, ".")
+		urls = append(urls, "http://"+net.JoinHostPort(host, fmt.Sprint(record.Port)))
+	}
+	return urls, nil
+}
+
+// consulBackend reads passing service instances from the Consul health API
+type consulBackend struct {
+	address    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewConsulBackend creates a backend for the Consul agent at address, http://localhost:8500
+// if empty. Token is sent as the ACL token if set.
+func NewConsulBackend(address, token string) RegistryBackend {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		address = "http://localhost:8500"
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return &consulBackend{
+		address:    strings.TrimRight(address, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (b *consulBackend) Name() string { return "consul" }
+
+func (b *consulBackend) Static() bool { return false }
+
+func (b *consulBackend) Lookup(ctx context.Context, service string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		b.address+"/v1/health/service/"+url.PathEscape(service)+"?passing=true", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul request: %w", err)
+	}
+	if b.token != "" {
+		req.Header.Set("X-Consul-Token", b.token)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consul: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned status %d", resp.StatusCode)
+	}
+
+	var entries []struct {
+		Node struct {
+			Address string `json:"Address"`
+		} `json:"Node"`
+		Service struct {
+			Address string `json:"Address"`
+			Port    int    `json:"Port"`
+		} `json:"Service"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode consul response: %w", err)
+	}
+
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+		urls = append(urls, "http://"+net.JoinHostPort(host, fmt.Sprint(entry.Service.Port)))
+	}
+	return urls, nil
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeBackend returns fixed endpoints, which can be replaced between refreshes
+type fakeBackend struct {
+	urls map[string][]string
+}
+
+func (b *fakeBackend) Name() string { return "fake" }
+
+func (b *fakeBackend) Static() bool { return false }
+
+func (b *fakeBackend) Lookup(ctx context.Context, service string) ([]string, error) {
+	return b.urls[service], nil
+}
+
+func newInstance(t *testing.T, name string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", name, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, client *http.Client, rawURL string) string {
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestRegistryRoundRobin(t *testing.T) {
+	first := newInstance(t, "first")
+	second := newInstance(t, "second")
+	backend := &fakeBackend{urls: map[string][]string{UserService: {first.URL, second.URL}}}
+	registry := NewRegistry(backend, RegistryOptions{})
+	client := &http.Client{Transport: registry.Transport(nil)}
+
+	baseURL := registry.BaseURL(UserService)
+	if baseURL != "http://user-service" {
+		t.Fatalf("unexpected base URL %q", baseURL)
+	}
+	seen := map[string]int{}
+	for i := 0; i < 4; i++ {
+		seen[get(t, client, baseURL+"/api/v1/users")]++
+	}
+	if seen["first /api/v1/users"] != 2 || seen["second /api/v1/users"] != 2 {
+		t.Fatalf("expected requests spread over both instances, got %v", seen)
+	}
+
+	// Services can move without a restart
+	third := newInstance(t, "third")
+	backend.urls[UserService] = []string{third.URL}
+	registry.Refresh(context.Background())
+	if body := get(t, client, baseURL+"/health"); body != "third /health" {
+		t.Fatalf("expected the moved instance, got %q", body)
+	}
+}
+
+func TestRegistrySkipsUnhealthyEndpoints(t *testing.T) {
+	healthy := newInstance(t, "healthy")
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	registry := NewRegistry(&fakeBackend{urls: map[string][]string{
+		ChatService: {failing.URL, healthy.URL},
+	}}, RegistryOptions{Cooldown: time.Minute})
+	registry.CheckHealth(context.Background())
+
+	for i := 0; i < 3; i++ {
+		target, err := registry.Resolve(ChatService)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if target.String() != healthy.URL {
+			t.Fatalf("expected the healthy instance, got %s", target)
+		}
+	}
+
+	// A failed request takes the last endpoint out too, then all endpoints are tried again
+	healthyURL, _ := url.Parse(healthy.URL)
+	registry.ReportFailure(ChatService, healthyURL, errors.New("connection refused"))
+	if _, err := registry.Resolve(ChatService); err != nil {
+		t.Fatalf("expected a fallback endpoint, got %v", err)
+	}
+	statuses := registry.Endpoints(ChatService)
+	if len(statuses) != 2 || statuses[0].Healthy || statuses[1].DownUntil == nil {
+		t.Fatalf("unexpected endpoint statuses: %+v", statuses)
+	}
+
+	if _, err := registry.Resolve(PollService); err == nil {
+		t.Fatal("expected an error for a service without endpoints")
+	}
+}
+
+func TestStaticBackend(t *testing.T) {
+	t.Setenv("CALENDAR_SERVICE_URL", "http://calendar-1:8084, http://calendar-2:8084/")
+	t.Setenv("POLL_SERVICE_URL", "")
+
+	registry := NewRegistry(NewStaticBackend(map[string]string{
+		TaskService: "http://localhost:8083",
+	}), RegistryOptions{})
+
+	if endpoints := registry.Endpoints(CalendarService); len(endpoints) != 2 || endpoints[1].URL != "http://calendar-2:8084" {
+		t.Fatalf("unexpected calendar endpoints: %+v", endpoints)
+	}
+	if registry.BaseURL(TaskService) != "http://task-service" {
+		t.Fatalf("expected the default task service URL to be used")
+	}
+	// Unconfigured services stay disabled
+	if baseURL := registry.BaseURL(PollService); baseURL != "" {
+		t.Fatalf("expected no poll service URL, got %q", baseURL)
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+)
+
+// Secret names. The env provider reads them as environment variables, the Vault provider as
+// keys of the secret at VAULT_SECRETS_PATH.
+const (
+	SecretJWT          = "JWT_SECRET"
+	SecretDatabaseURL  = "DATABASE_URL"
+	SecretSMTPPassword = "SMTP_PASSWORD"
+)
+
+// DefaultSecretsCacheTTL is how long a fetched secret is used before it is fetched again,
+// so rotated secrets are picked up without a restart
+const DefaultSecretsCacheTTL = 5 * time.Minute
+
+// SecretsBackend fetches secrets by name
+type SecretsBackend interface {
+	// Name identifies the backend in logs, e.g. env or vault
+	Name() string
+
+	// Lookup returns the value of a secret, empty if the backend has none
+	Lookup(ctx context.Context, name string) (string, error)
+}
+
+// cachedSecret is a fetched secret and when it has to be fetched again
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Secrets fetches secrets from a backend on first use and caches them. Secrets the backend
+// does not have are read from the environment, so credentials can be moved to the backend one
+// by one. A nil Secrets reads only the environment.
+type Secrets struct {
+	backend SecretsBackend
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// NewSecrets creates secrets fetched from backend, zero ttl uses the default
+func NewSecrets(backend SecretsBackend, ttl time.Duration) *Secrets {
+	if ttl <= 0 {
+		ttl = DefaultSecretsCacheTTL
+	}
+	return &Secrets{
+		backend: backend,
+		ttl:     ttl,
+		cache:   make(map[string]cachedSecret),
+	}
+}
+
+// NewSecretsFromEnv creates secrets configured with SECRETS_PROVIDER (env or vault) and
+// SECRETS_CACHE_TTL. The vault provider is configured with VAULT_ADDR, VAULT_NAMESPACE,
+// VAULT_TOKEN or VAULT_ROLE_ID and VAULT_SECRET_ID for AppRole auth, VAULT_APPROLE_MOUNT,
+// VAULT_KV_MOUNT and VAULT_SECRETS_PATH.
+func NewSecretsFromEnv() (*Secrets, error) {
+	var backend SecretsBackend
+	switch kind := strings.ToLower(strings.TrimSpace(os.Getenv("SECRETS_PROVIDER"))); kind {
+	case "", "env":
+		backend = NewEnvSecretsBackend()
+	case "vault":
+		vault, err := NewVaultBackend(VaultOptions{
+			Address:      os.Getenv("VAULT_ADDR"),
+			Namespace:    os.Getenv("VAULT_NAMESPACE"),
+			Token:        os.Getenv("VAULT_TOKEN"),
+			RoleID:       os.Getenv("VAULT_ROLE_ID"),
+			SecretID:     os.Getenv("VAULT_SECRET_ID"),
+			AppRoleMount: os.Getenv("VAULT_APPROLE_MOUNT"),
+			Mount:        os.Getenv("VAULT_KV_MOUNT"),
+			Path:         os.Getenv("VAULT_SECRETS_PATH"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		backend = vault
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q", kind)
+	}
+
+	var ttl time.Duration
+	if value := strings.TrimSpace(os.Getenv("SECRETS_CACHE_TTL")); value != "" {
+		var err error
+		if ttl, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid SECRETS_CACHE_TTL: %w", err)
+		}
+	}
+
+	return NewSecrets(backend, ttl), nil
+}
+
+// Backend returns the name of the secrets backend
+func (s *Secrets) Backend() string {
+	if s == nil {
+		return "env"
+	}
+	return s.backend.Name()
+}
+
+// Get returns the value of a secret. When the backend fails, the last fetched value is used
+// until the backend is back, a secret never fetched fails.
+func (s *Secrets) Get(ctx context.Context, name string) (string, error) {
+	if s == nil {
+		return os.Getenv(name), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	cached, exists := s.cache[name]
+	if exists && now.Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	value, err := s.backend.Lookup(ctx, name)
+	if err != nil {
+		if exists {
+			logger.WithFields(map[string]interface{}{
+				"backend": s.backend.Name(),
+				"secret":  name,
+				"error":   err.Error(),
+			}).Warn("Failed to refresh secret, using the cached value")
+			return cached.value, nil
+		}
+		return "", fmt.Errorf("failed to get secret %s from %s: %w", name, s.backend.Name(), err)
+	}
+	if value == "" {
+		value = os.Getenv(name)
+	}
+
+	s.cache[name] = cachedSecret{value: value, expiresAt: now.Add(s.ttl)}
+	return value, nil
+}
+
+// envSecretsBackend reads secrets from environment variables
+type envSecretsBackend struct{}
+
+// NewEnvSecretsBackend creates a backend reading secrets from environment variables
+func NewEnvSecretsBackend() SecretsBackend {
+	return envSecretsBackend{}
+}
+
+func (envSecretsBackend) Name() string { return "env" }
+
+func (envSecretsBackend) Lookup(ctx context.Context, name string) (string, error) {
+	return os.Getenv(name), nil
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVault serves AppRole login, token renewal and one KV v2 secret
+type fakeVault struct {
+	mu       sync.Mutex
+	logins   int
+	renewals int
+	reads    int
+	token    string
+	revoked  bool
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	auth := func() map[string]interface{} {
+		return map[string]interface{}{"client_token": v.token, "lease_duration": 3600, "renewable": true}
+	}
+	if r.URL.Path == "/v1/auth/approle/login" {
+		v.logins++
+		v.token = fmt.Sprintf("token-%d", v.logins)
+		v.revoked = false
+		json.NewEncoder(w).Encode(map[string]interface{}{"auth": auth()})
+		return
+	}
+
+	if r.Header.Get("X-Vault-Token") != v.token || v.revoked {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/token/renew-self":
+		v.renewals++
+		json.NewEncoder(w).Encode(map[string]interface{}{"auth": auth()})
+	case "/v1/secret/data/tachyon":
+		v.reads++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"data": map[string]interface{}{SecretJWT: "jwt-from-vault"}},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestVaultSecrets(t *testing.T) {
+	vault := &fakeVault{}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	backend, err := NewVaultBackend(VaultOptions{Address: server.URL, RoleID: "role", SecretID: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secrets := NewSecrets(backend, time.Minute)
+	ctx := context.Background()
+	expire := func() {
+		for name, cached := range secrets.cache {
+			cached.expiresAt = time.Now().Add(-time.Second)
+			secrets.cache[name] = cached
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		value, err := secrets.Get(ctx, SecretJWT)
+		if err != nil || value != "jwt-from-vault" {
+			t.Fatalf("expected the secret from vault, got %q (%v)", value, err)
+		}
+	}
+	if vault.logins != 1 || vault.reads != 1 {
+		t.Fatalf("expected one login and one cached read, got %d logins and %d reads", vault.logins, vault.reads)
+	}
+
+	// Secrets not moved to vault yet come from the environment
+	t.Setenv(SecretSMTPPassword, "smtp-from-env")
+	if value, _ := secrets.Get(ctx, SecretSMTPPassword); value != "smtp-from-env" {
+		t.Fatalf("expected the secret from env, got %q", value)
+	}
+
+	// The token is renewed before its lease runs out
+	backend.(*vaultBackend).renewAt = time.Now().Add(-time.Second)
+	expire()
+	if _, err := secrets.Get(ctx, SecretJWT); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vault.renewals != 1 || vault.logins != 1 {
+		t.Fatalf("expected the token to be renewed, got %d renewals and %d logins", vault.renewals, vault.logins)
+	}
+
+	// A revoked token is replaced by logging in again
+	vault.revoked = true
+	expire()
+	if value, err := secrets.Get(ctx, SecretJWT); err != nil || value != "jwt-from-vault" {
+		t.Fatalf("expected the secret after logging in again, got %q (%v)", value, err)
+	}
+	if vault.logins != 2 {
+		t.Fatalf("expected a second login, got %d", vault.logins)
+	}
+
+	// Fetched secrets outlive a vault outage
+	server.Close()
+	expire()
+	if value, err := secrets.Get(ctx, SecretJWT); err != nil || value != "jwt-from-vault" {
+		t.Fatalf("expected the cached secret during the outage, got %q (%v)", value, err)
+	}
+	if _, err := secrets.Get(ctx, SecretDatabaseURL); err == nil {
+		t.Error("expected a secret never fetched to fail during the outage")
+	}
+}
+
+func TestSecretsFromEnv(t *testing.T) {
+	t.Setenv(SecretJWT, "jwt-from-env")
+
+	var secrets *Secrets
+	if value, _ := secrets.Get(context.Background(), SecretJWT); value != "jwt-from-env" {
+		t.Fatalf("expected nil secrets to read env, got %q", value)
+	}
+
+	t.Setenv("SECRETS_PROVIDER", "vault")
+	if _, err := NewSecretsFromEnv(); err == nil {
+		t.Error("expected vault without credentials to be rejected")
+	}
+
+	t.Setenv("SECRETS_PROVIDER", "keychain")
+	if _, err := NewSecretsFromEnv(); err == nil {
+		t.Error("expected an unknown provider to be rejected")
+	}
+
+	t.Setenv("SECRETS_PROVIDER", "")
+	secrets, err := NewSecretsFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secrets.Backend() != "env" {
+		t.Fatalf("expected the env provider by default, got %s", secrets.Backend())
+	}
+	if value, _ := secrets.Get(context.Background(), SecretJWT); value != "jwt-from-env" {
+		t.Fatalf("expected the secret from env, got %q", value)
+	}
+}
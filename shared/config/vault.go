@@ -0,0 +1,283 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+)
+
+// VaultOptions configures the Vault secrets backend. Either Token or RoleID and SecretID
+// are required.
+type VaultOptions struct {
+	Address      string // http://localhost:8200 if empty
+	Namespace    string // Vault Enterprise namespace, optional
+	Token        string
+	RoleID       string
+	SecretID     string
+	AppRoleMount string // approle if empty
+	Mount        string // KV v2 mount, secret if empty
+	Path         string // Path of the secret holding all keys, tachyon if empty
+}
+
+// vaultBackend reads keys of a KV v2 secret. The token is renewed before its lease runs out,
+// an AppRole token that can no longer be renewed is replaced by logging in again.
+type vaultBackend struct {
+	address      string
+	namespace    string
+	roleID       string
+	secretID     string
+	appRoleMount string
+	mount        string
+	path         string
+	httpClient   *http.Client
+
+	mu         sync.Mutex
+	token      string
+	leaseKnown bool      // Lease of the token was read, false for a token from VAULT_TOKEN until first use
+	renewable  bool      // Token can be renewed
+	renewAt    time.Time // Zero if the token never expires
+	expiresAt  time.Time
+}
+
+// vaultResponse is the envelope of Vault API responses
+type vaultResponse struct {
+	Data json.RawMessage `json:"data"`
+	Auth *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+	Errors []string `json:"errors"`
+}
+
+// NewVaultBackend creates a backend reading secrets from Vault
+func NewVaultBackend(options VaultOptions) (SecretsBackend, error) {
+	if options.Token == "" && (options.RoleID == "" || options.SecretID == "") {
+		return nil, fmt.Errorf("VAULT_TOKEN or VAULT_ROLE_ID and VAULT_SECRET_ID are required for the vault secrets provider")
+	}
+
+	address := strings.TrimSpace(options.Address)
+	if address == "" {
+		address = "http://localhost:8200"
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	backend := &vaultBackend{
+		address:      strings.TrimRight(address, "/"),
+		namespace:    strings.TrimSpace(options.Namespace),
+		token:        options.Token,
+		roleID:       options.RoleID,
+		secretID:     options.SecretID,
+		appRoleMount: vaultPathOrDefault(options.AppRoleMount, "approle"),
+		mount:        vaultPathOrDefault(options.Mount, "secret"),
+		path:         vaultPathOrDefault(options.Path, "tachyon"),
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+	}
+	return backend, nil
+}
+
+func (b *vaultBackend) Name() string { return "vault" }
+
+func (b *vaultBackend) Lookup(ctx context.Context, name string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.ensureToken(ctx); err != nil {
+		return "", err
+	}
+
+	var data struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	status, err := b.do(ctx, http.MethodGet, "/v1/"+b.mount+"/data/"+b.path, nil, &data)
+	if status == http.StatusForbidden && b.roleID != "" {
+		// The token was revoked before its lease ran out, log in again once
+		if err = b.login(ctx); err == nil {
+			status, err = b.do(ctx, http.MethodGet, "/v1/"+b.mount+"/data/"+b.path, nil, &data)
+		}
+	}
+	if status == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault secret %s/%s: %w", b.mount, b.path, err)
+	}
+
+	value, exists := data.Data[name]
+	if !exists || value == nil {
+		return "", nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// ensureToken makes sure the token is valid, renewing it or logging in again as needed
+func (b *vaultBackend) ensureToken(ctx context.Context) error {
+	if b.token != "" && !b.leaseKnown {
+		if err := b.lookupSelf(ctx); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if b.token != "" && (b.renewAt.IsZero() || now.Before(b.renewAt)) {
+		return nil
+	}
+
+	if b.token != "" && b.renewable && now.Before(b.expiresAt) {
+		err := b.renewSelf(ctx)
+		if err == nil {
+			return nil
+		}
+		logger.WithFields(map[string]interface{}{
+			"error": err.Error(),
+		}).Warn("Failed to renew vault token")
+	}
+
+	if b.roleID != "" {
+		return b.login(ctx)
+	}
+	if now.Before(b.expiresAt) {
+		return nil
+	}
+	return fmt.Errorf("vault token expired and can't be renewed, set a new VAULT_TOKEN")
+}
+
+// login logs in with AppRole credentials
+func (b *vaultBackend) login(ctx context.Context) error {
+	body := map[string]string{"role_id": b.roleID, "secret_id": b.secretID}
+
+	b.token = ""
+	var resp vaultResponse
+	if _, err := b.call(ctx, http.MethodPost, "/v1/auth/"+b.appRoleMount+"/login", body, &resp); err != nil {
+		return fmt.Errorf("failed to log in to vault with approle: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return fmt.Errorf("failed to log in to vault with approle: no token in response")
+	}
+
+	b.token = resp.Auth.ClientToken
+	b.setLease(resp.Auth.LeaseDuration, resp.Auth.Renewable)
+	logger.WithFields(map[string]interface{}{
+		"ttl_seconds": resp.Auth.LeaseDuration,
+	}).Info("Logged in to vault with approle")
+	return nil
+}
+
+// renewSelf extends the lease of the token
+func (b *vaultBackend) renewSelf(ctx context.Context) error {
+	var resp vaultResponse
+	if _, err := b.call(ctx, http.MethodPost, "/v1/auth/token/renew-self", map[string]string{}, &resp); err != nil {
+		return err
+	}
+	if resp.Auth == nil {
+		return fmt.Errorf("no lease in vault response")
+	}
+	b.setLease(resp.Auth.LeaseDuration, resp.Auth.Renewable)
+	return nil
+}
+
+// lookupSelf reads the lease of a token given in VAULT_TOKEN
+func (b *vaultBackend) lookupSelf(ctx context.Context) error {
+	var data struct {
+		TTL       int64 `json:"ttl"`
+		Renewable bool  `json:"renewable"`
+	}
+	if _, err := b.do(ctx, http.MethodGet, "/v1/auth/token/lookup-self", nil, &data); err != nil {
+		return fmt.Errorf("failed to look up vault token: %w", err)
+	}
+	b.setLease(data.TTL, data.Renewable)
+	return nil
+}
+
+// setLease records the lease of the token, renewing it after two thirds of the TTL.
+// Zero TTL means the token never expires, e.g. a root token.
+func (b *vaultBackend) setLease(ttlSeconds int64, renewable bool) {
+	b.leaseKnown = true
+	b.renewable = renewable
+	b.renewAt, b.expiresAt = time.Time{}, time.Time{}
+	if ttlSeconds <= 0 {
+		return
+	}
+
+	now := time.Now()
+	ttl := time.Duration(ttlSeconds) * time.Second
+	b.renewAt = now.Add(ttl * 2 / 3)
+	b.expiresAt = now.Add(ttl)
+}
+
+// do calls the Vault API and decodes the data field of the response into out
+func (b *vaultBackend) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var resp vaultResponse
+	status, err := b.call(ctx, method, path, body, &resp)
+	if err != nil {
+		return status, err
+	}
+	if out != nil && len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return status, fmt.Errorf("failed to decode vault data: %w", err)
+		}
+	}
+	return status, nil
+}
+
+// call sends a request to the Vault API with the current token and decodes the response envelope
+func (b *vaultBackend) call(ctx context.Context, method, path string, body interface{}, resp *vaultResponse) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode vault request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.address+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	if b.token != "" {
+		req.Header.Set("X-Vault-Token", b.token)
+	}
+	if b.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", b.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := b.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query vault: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if err := json.NewDecoder(httpResp.Body).Decode(resp); err != nil && err != io.EOF {
+		return httpResp.StatusCode, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		if len(resp.Errors) > 0 {
+			return httpResp.StatusCode, fmt.Errorf("vault returned status %d: %s", httpResp.StatusCode, strings.Join(resp.Errors, "; "))
+		}
+		return httpResp.StatusCode, fmt.Errorf("vault returned status %d", httpResp.StatusCode)
+	}
+	return httpResp.StatusCode, nil
+}
+
+// vaultPathOrDefault trims slashes around a Vault path, fallback if it is empty
+func vaultPathOrDefault(path, fallback string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return fallback
+	}
+	return path
+}
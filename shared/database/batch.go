@@ -0,0 +1,70 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Batch insert tuning.
+//
+// BenchmarkCreateInBatches (batch_test.go) on in-memory SQLite, 5000 rows:
+// 1 row per INSERT ~277ms, 50-2000 rows per INSERT ~100-110ms. Gains flatten after
+// a few dozen rows without network latency; against PostgreSQL every statement is
+// also a round trip, so larger batches matter more. 500 keeps a statement well under
+// the bind parameter limit for all our models while amortizing round trips.
+const (
+	DefaultBatchSize = 500   // Строк в одном INSERT
+	DefaultChunkSize = 5000  // Строк в одной транзакции при потоковой вставке
+	MaxBindParams    = 65535 // Лимит параметров одного запроса в PostgreSQL
+)
+
+// ChunkProgress is called after every inserted chunk with the number of processed rows
+type ChunkProgress func(done, total int)
+
+// BatchSizeFor returns rows per INSERT for model, capped so that a single statement
+// never exceeds MaxBindParams bind parameters
+func (db *DB) BatchSizeFor(model interface{}) int {
+	stmt := &gorm.Statement{DB: db.DB}
+	if err := stmt.Parse(model); err != nil || len(stmt.Schema.DBNames) == 0 {
+		return DefaultBatchSize
+	}
+
+	maxRows := MaxBindParams / len(stmt.Schema.DBNames)
+	if maxRows < DefaultBatchSize {
+		return maxRows
+	}
+	return DefaultBatchSize
+}
+
+// CreateInChunks inserts rows in chunks of chunkSize, every chunk in its own transaction
+// as multi-row INSERTs of batchSize rows. Chunks that were already committed stay in the
+// database if a later chunk fails; the error reports how many rows were inserted.
+// Zero chunkSize or batchSize use the defaults.
+func CreateInChunks[T any](db *DB, rows []T, chunkSize, batchSize int, progress ChunkProgress) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if batchSize <= 0 {
+		batchSize = db.BatchSizeFor(rows[0])
+	}
+
+	for start := 0; start < len(rows); start += chunkSize {
+		end := min(start+chunkSize, len(rows))
+
+		chunk := rows[start:end]
+		if err := db.CreateInBatches(chunk, batchSize).Error; err != nil {
+			return fmt.Errorf("failed to insert rows %d-%d of %d (%d inserted): %w", start+1, end, len(rows), start, err)
+		}
+
+		if progress != nil {
+			progress(end, len(rows))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// benchRow mirrors a typical wide row (notification-like)
+type benchRow struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uint   `gorm:"index"`
+	Type        string `gorm:"size:20"`
+	Title       string `gorm:"size:255"`
+	Message     string `gorm:"type:text"`
+	Priority    string `gorm:"size:20"`
+	Status      string `gorm:"size:20"`
+	RelatedType string `gorm:"size:50"`
+	ActionURL   string `gorm:"size:500"`
+	IsRead      bool
+}
+
+func openBenchDB(tb testing.TB) *DB {
+	tb.Helper()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", tb.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		tb.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&benchRow{}); err != nil {
+		tb.Fatalf("failed to migrate: %v", err)
+	}
+	return &DB{db}
+}
+
+func makeBenchRows(n int) []*benchRow {
+	rows := make([]*benchRow, n)
+	for i := range rows {
+		rows[i] = &benchRow{
+			UserID:      uint(i + 1),
+			Type:        "system",
+			Title:       "Benchmark notification",
+			Message:     "Lorem ipsum dolor sit amet, consectetur adipiscing elit",
+			Priority:    "medium",
+			Status:      "pending",
+			RelatedType: "task",
+			ActionURL:   "https://example.com/tasks/1",
+		}
+	}
+	return rows
+}
+
+// BenchmarkCreateInBatches compares rows per INSERT for 5000 rows
+func BenchmarkCreateInBatches(b *testing.B) {
+	const total = 5000
+
+	for _, batchSize := range []int{1, 50, 100, 250, 500, 1000, 2000} {
+		b.Run(fmt.Sprintf("batch_%d", batchSize), func(b *testing.B) {
+			db := openBenchDB(b)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				rows := makeBenchRows(total)
+				b.StartTimer()
+
+				if err := db.CreateInBatches(rows, batchSize).Error; err != nil {
+					b.Fatalf("insert failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateInChunks(t *testing.T) {
+	db := openBenchDB(t)
+	rows := makeBenchRows(1234)
+
+	var calls []int
+	err := CreateInChunks(db, rows, 500, 100, func(done, total int) {
+		if total != len(rows) {
+			t.Errorf("expected total %d, got %d", len(rows), total)
+		}
+		calls = append(calls, done)
+	})
+	if err != nil {
+		t.Fatalf("CreateInChunks failed: %v", err)
+	}
+
+	expected := []int{500, 1000, 1234}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("expected progress %v, got %v", expected, calls)
+	}
+
+	var count int64
+	db.Model(&benchRow{}).Count(&count)
+	if count != int64(len(rows)) {
+		t.Errorf("expected %d rows, got %d", len(rows), count)
+	}
+
+	for _, row := range rows {
+		if row.ID == 0 {
+			t.Fatalf("expected IDs to be set after insert")
+		}
+	}
+}
+
+func TestBatchSizeFor(t *testing.T) {
+	db := openBenchDB(t)
+
+	if size := db.BatchSizeFor(&benchRow{}); size != DefaultBatchSize {
+		t.Errorf("expected %d for a narrow model, got %d", DefaultBatchSize, size)
+	}
+}
@@ -49,6 +49,11 @@ func Connect(config *Config) (*DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Count statements of sessions with an attached QueryCounter
+	if err := RegisterQueryCounter(db); err != nil {
+		return nil, err
+	}
+
 	// Get underlying sql.DB for connection pool configuration
 	sqlDB, err := db.DB()
 	if err != nil {
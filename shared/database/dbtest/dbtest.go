@@ -0,0 +1,125 @@
+// Package dbtest opens throwaway in-memory SQLite databases for repository and usecase tests,
+// so they run in milliseconds without Postgres. Repositories work unchanged: Postgres ILIKE is
+// rewritten to LIKE, which SQLite compares case-insensitively for ASCII. Raw SQL using other
+// Postgres functions (EXTRACT, date_trunc, ...) still needs Postgres.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// databaseCounter gives every database its own name, so parallel tests do not share data
+var databaseCounter int64
+
+// ilikePattern matches the Postgres case-insensitive LIKE operator
+var ilikePattern = regexp.MustCompile(`(?i)\bILIKE\b`)
+
+// Open creates an empty in-memory database with tables of the models.
+// The database is closed when the test finishes.
+func Open(tb testing.TB, models ...interface{}) *database.DB {
+	tb.Helper()
+
+	dsn := fmt.Sprintf("file:dbtest%d?mode=memory&cache=shared&_foreign_keys=1", atomic.AddInt64(&databaseCounter, 1))
+	sqlDB, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		tb.Fatalf("failed to open test database: %v", err)
+	}
+	// SQLite locks tables across connections of a shared in-memory database
+	sqlDB.SetMaxOpenConns(1)
+	tb.Cleanup(func() { sqlDB.Close() })
+
+	gormDB, err := gorm.Open(sqlite.New(sqlite.Config{Conn: &connPool{rewritingPool: rewritingPool{pool: sqlDB}, db: sqlDB}}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		tb.Fatalf("failed to open test database: %v", err)
+	}
+
+	db := &database.DB{DB: gormDB}
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			tb.Fatalf("failed to migrate test database: %v", err)
+		}
+	}
+	return db
+}
+
+// sqlPool is implemented by both *sql.DB and *sql.Tx
+type sqlPool interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// rewritingPool rewrites Postgres-only SQL before it reaches SQLite
+type rewritingPool struct {
+	pool sqlPool
+}
+
+func (p *rewritingPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.pool.PrepareContext(ctx, rewrite(query))
+}
+
+func (p *rewritingPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.pool.ExecContext(ctx, rewrite(query), args...)
+}
+
+func (p *rewritingPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.pool.QueryContext(ctx, rewrite(query), args...)
+}
+
+func (p *rewritingPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.pool.QueryRowContext(ctx, rewrite(query), args...)
+}
+
+// connPool is the connection pool of a test database
+type connPool struct {
+	rewritingPool
+	db *sql.DB
+}
+
+// BeginTx starts a transaction whose statements are rewritten as well
+func (p *connPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &txPool{rewritingPool: rewritingPool{pool: tx}, tx: tx}, nil
+}
+
+// GetDBConn returns the underlying database, used by gorm.DB.DB()
+func (p *connPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// txPool runs statements of a transaction. It cannot begin transactions itself,
+// so GORM reuses it for nested creates in hooks like it does with *sql.Tx.
+type txPool struct {
+	rewritingPool
+	tx *sql.Tx
+}
+
+func (p *txPool) Commit() error {
+	return p.tx.Commit()
+}
+
+func (p *txPool) Rollback() error {
+	return p.tx.Rollback()
+}
+
+// rewrite translates Postgres-only syntax to SQLite
+func rewrite(query string) string {
+	return ilikePattern.ReplaceAllString(query, "LIKE")
+}
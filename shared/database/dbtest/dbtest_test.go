@@ -0,0 +1,44 @@
+package dbtest
+
+import (
+	"testing"
+
+	"tachyon-messenger/shared/models"
+
+	"gorm.io/gorm"
+)
+
+type item struct {
+	models.BaseModel
+	Name string
+}
+
+func TestOpen(t *testing.T) {
+	db := Open(t, &item{})
+	other := Open(t, &item{})
+
+	if err := db.Create(&item{Name: "Quarterly Report"}).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var found []item
+	if err := db.Where("name ILIKE ?", "%report%").Find(&found).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("expected ILIKE to match 1 row, got %d", len(found))
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.Where("name ilike ?", "%REPORT%").Find(&found).Error
+	})
+	if err != nil || len(found) != 1 {
+		t.Errorf("expected ILIKE to work in transactions, got %d rows, error %v", len(found), err)
+	}
+
+	var count int64
+	other.Model(&item{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected databases to be isolated, got %d rows", count)
+	}
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// queryCounterKey is the statement context key of an attached QueryCounter
+type queryCounterKey struct{}
+
+// QueryCounter counts SQL statements executed through sessions created by WithQueryCounter.
+// It is used in debug logs of hot read paths to catch N+1 regressions.
+type QueryCounter struct {
+	count atomic.Int64
+}
+
+// NewQueryCounter creates a new query counter
+func NewQueryCounter() *QueryCounter {
+	return &QueryCounter{}
+}
+
+// Count returns the number of executed statements
+func (c *QueryCounter) Count() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.count.Load()
+}
+
+// WithQueryCounter returns a session whose statements are counted by counter.
+// Counting requires RegisterQueryCounter on the connection, Connect does it.
+func (db *DB) WithQueryCounter(counter *QueryCounter) *DB {
+	ctx := context.Background()
+	if db.Statement != nil && db.Statement.Context != nil {
+		ctx = db.Statement.Context
+	}
+	return &DB{db.WithContext(context.WithValue(ctx, queryCounterKey{}, counter))}
+}
+
+// RegisterQueryCounter installs callbacks that feed counters attached by WithQueryCounter
+func RegisterQueryCounter(db *gorm.DB) error {
+	count := func(tx *gorm.DB) {
+		if tx.Statement.Context == nil {
+			return
+		}
+		if counter, ok := tx.Statement.Context.Value(queryCounterKey{}).(*QueryCounter); ok && counter != nil {
+			counter.count.Add(1)
+		}
+	}
+
+	callbacks := []struct {
+		name     string
+		register func(name string, fn func(*gorm.DB)) error
+	}{
+		{"query", db.Callback().Query().After("gorm:query").Register},
+		{"create", db.Callback().Create().After("gorm:create").Register},
+		{"update", db.Callback().Update().After("gorm:update").Register},
+		{"delete", db.Callback().Delete().After("gorm:delete").Register},
+		{"row", db.Callback().Row().After("gorm:row").Register},
+		{"raw", db.Callback().Raw().After("gorm:raw").Register},
+	}
+
+	for _, callback := range callbacks {
+		if err := callback.register("tachyon:query_counter_"+callback.name, count); err != nil {
+			return fmt.Errorf("failed to register query counter for %s: %w", callback.name, err)
+		}
+	}
+	return nil
+}
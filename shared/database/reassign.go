@@ -0,0 +1,36 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ReassignUser moves rows of model from one user to another by userColumn, including soft-deleted rows.
+// When scopeColumns are given, rows the target user already has for the same scope (e.g. the same chat)
+// are permanently deleted from the source user instead of being moved. It returns the number of moved and dropped rows.
+func ReassignUser(tx *gorm.DB, model interface{}, userColumn string, scopeColumns []string, fromUserID, toUserID uint) (int64, int64, error) {
+	var dropped int64
+	if len(scopeColumns) > 0 {
+		scope := strings.Join(scopeColumns, ", ")
+		existing := tx.Model(model).Select(scope).Where(userColumn+" = ?", toUserID)
+
+		result := tx.Unscoped().Where(userColumn+" = ?", fromUserID).
+			Where("("+scope+") IN (?)", existing).
+			Delete(model)
+		if result.Error != nil {
+			return 0, 0, fmt.Errorf("failed to drop conflicting rows: %w", result.Error)
+		}
+		dropped = result.RowsAffected
+	}
+
+	result := tx.Unscoped().Model(model).
+		Where(userColumn+" = ?", fromUserID).
+		Update(userColumn, toUserID)
+	if result.Error != nil {
+		return 0, 0, fmt.Errorf("failed to reassign rows: %w", result.Error)
+	}
+
+	return result.RowsAffected, dropped, nil
+}
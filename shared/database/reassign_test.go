@@ -0,0 +1,73 @@
+package database
+
+import (
+	"testing"
+
+	"tachyon-messenger/shared/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type membershipRow struct {
+	models.BaseModel
+	ChatID uint
+	UserID uint
+}
+
+func TestReassignUser(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := gdb.AutoMigrate(&membershipRow{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	// Duplicate user 2 shares chat 1 with primary user 1 and alone is in chats 2 and 3
+	rows := []*membershipRow{
+		{ChatID: 1, UserID: 1},
+		{ChatID: 1, UserID: 2},
+		{ChatID: 2, UserID: 2},
+		{ChatID: 3, UserID: 2},
+	}
+	if err := gdb.Create(rows).Error; err != nil {
+		t.Fatalf("failed to create rows: %v", err)
+	}
+	if err := gdb.Delete(rows[3]).Error; err != nil {
+		t.Fatalf("failed to delete row: %v", err)
+	}
+
+	moved, dropped, err := ReassignUser(gdb, &membershipRow{}, "user_id", []string{"chat_id"}, 2, 1)
+	if err != nil {
+		t.Fatalf("ReassignUser failed: %v", err)
+	}
+	if moved != 2 || dropped != 1 {
+		t.Fatalf("expected 2 moved and 1 dropped, got %d moved and %d dropped", moved, dropped)
+	}
+
+	var live []membershipRow
+	if err := gdb.Order("chat_id").Find(&live).Error; err != nil {
+		t.Fatalf("failed to load rows: %v", err)
+	}
+	if len(live) != 2 || live[0].ChatID != 1 || live[1].ChatID != 2 {
+		t.Fatalf("expected memberships in chats 1 and 2, got %+v", live)
+	}
+	for _, row := range live {
+		if row.UserID != 1 {
+			t.Fatalf("expected all memberships moved to user 1, got %+v", row)
+		}
+	}
+
+	// Soft-deleted rows are moved too, so they can still be restored by the primary user
+	var trashed membershipRow
+	if err := gdb.Unscoped().Where("chat_id = ?", 3).First(&trashed).Error; err != nil {
+		t.Fatalf("failed to load trashed row: %v", err)
+	}
+	if trashed.UserID != 1 {
+		t.Fatalf("expected trashed membership moved to user 1, got user %d", trashed.UserID)
+	}
+}
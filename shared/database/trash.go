@@ -0,0 +1,35 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTrashRetention is how long soft-deleted records stay restorable before they are purged
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// Trashed returns a session over soft-deleted records only
+func (db *DB) Trashed() *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NOT NULL")
+}
+
+// Restore clears deleted_at of a soft-deleted record.
+// It returns false if the record doesn't exist or is not deleted.
+func (db *DB) Restore(model interface{}, id uint) (bool, error) {
+	result := db.Unscoped().Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// PurgeDeleted permanently deletes records of model soft-deleted before deletedBefore
+func (db *DB) PurgeDeleted(model interface{}, deletedBefore time.Time) (int64, error) {
+	result := db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Delete(model)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,33 @@
+package database
+
+import "errors"
+
+// ErrVersionConflict is returned when a record was modified or deleted since it was read
+var ErrVersionConflict = errors.New("version conflict")
+
+// Versioned is implemented by models with an optimistic locking version column
+type Versioned interface {
+	GetVersion() uint
+	SetVersion(version uint)
+}
+
+// SaveVersioned updates all fields of model if the stored version still equals the
+// version of model, and increments it. It returns ErrVersionConflict otherwise.
+func (db *DB) SaveVersioned(model Versioned) error {
+	version := model.GetVersion()
+	model.SetVersion(version + 1)
+
+	// Unlike Save, Updates never falls back to an insert when no row matched
+	result := db.Model(model).
+		Where("version = ?", version).
+		Select("*").
+		Updates(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		model.SetVersion(version)
+		return result.Error
+	}
+	return nil
+}
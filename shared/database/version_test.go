@@ -0,0 +1,72 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"tachyon-messenger/shared/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type versionedRow struct {
+	models.BaseModel
+	models.VersionMeta
+	Title string
+}
+
+func TestSaveVersioned(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := gdb.AutoMigrate(&versionedRow{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	db := &DB{gdb}
+
+	row := &versionedRow{Title: "initial"}
+	if err := db.Create(row).Error; err != nil {
+		t.Fatalf("failed to create row: %v", err)
+	}
+	if row.Version != 1 {
+		t.Fatalf("expected version 1 after create, got %d", row.Version)
+	}
+
+	// Two copies read at the same version
+	var first, second versionedRow
+	db.First(&first, row.ID)
+	db.First(&second, row.ID)
+
+	first.Title = "first"
+	if err := db.SaveVersioned(&first); err != nil {
+		t.Fatalf("failed to save first copy: %v", err)
+	}
+	if first.Version != 2 {
+		t.Fatalf("expected version 2 after update, got %d", first.Version)
+	}
+
+	second.Title = "second"
+	if err := db.SaveVersioned(&second); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected version conflict for stale copy, got %v", err)
+	}
+	if second.Version != 1 {
+		t.Fatalf("expected stale copy to keep version 1, got %d", second.Version)
+	}
+
+	var stored versionedRow
+	db.First(&stored, row.ID)
+	if stored.Title != "first" || stored.Version != 2 {
+		t.Fatalf("stale update must not be applied, got %q at version %d", stored.Title, stored.Version)
+	}
+
+	// Deleted rows can't be updated either
+	db.Delete(&stored)
+	if err := db.SaveVersioned(&stored); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected version conflict for deleted row, got %v", err)
+	}
+}
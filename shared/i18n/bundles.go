@@ -0,0 +1,240 @@
+package i18n
+
+// bundles holds messages of all supported locales by key.
+// Placeholders use the {{.Name}} form and are filled from T args.
+var bundles = map[Locale]map[string]string{
+	LocaleRU: {
+		// API errors
+		"error.authorization_required":       "Требуется заголовок Authorization",
+		"error.invalid_authorization_format": "Неверный формат заголовка Authorization",
+		"error.invalid_token":                "Недействительный или просроченный токен",
+		"error.user_role_not_found":          "Роль пользователя не найдена",
+		"error.invalid_role_type":            "Некорректный тип роли",
+		"error.insufficient_permissions":     "Недостаточно прав",
+		"error.authentication_required":      "Требуется аутентификация",
+		"error.invalid_authentication_data":  "Некорректные данные аутентификации",
+		"error.admin_access_required":        "Требуются права администратора",
+		"error.super_admin_access_required":  "Требуются права суперадминистратора",
+		"error.invalid_content_type":         "Неверный Content-Type",
+		"error.invalid_request_body":         "Некорректное тело запроса",
+		"error.invalid_filter_parameters":    "Некорректные параметры фильтра",
+		"error.invalid_query_parameters":     "Некорректные параметры запроса",
+		"error.invalid_calendar_parameters":  "Некорректные параметры календаря",
+		"error.maintenance":                  "Ведутся технические работы, повторите попытку позже",
+		"error.feature_disabled":             "Функция временно отключена",
+		"error.request_too_large":            "Размер запроса превышает допустимый",
+		"error.service_overloaded":           "Сервис перегружен, повторите попытку позже",
+		"error.admin_network_denied":         "Доступ к администрированию из этой сети запрещён",
+		"error.quota_exceeded":               "Превышен лимит запросов, повторите попытку позже",
+
+		// Validation errors
+		"validation.value":      "значение",
+		"validation.required":   "Поле {{.Field}} обязательно для заполнения",
+		"validation.min.string": "Поле {{.Field}} должно содержать не менее {{.Param}} символов",
+		"validation.max.string": "Поле {{.Field}} должно содержать не более {{.Param}} символов",
+		"validation.len.string": "Поле {{.Field}} должно содержать ровно {{.Param}} символов",
+		"validation.min.items":  "Поле {{.Field}} должно содержать не менее {{.Param}} элементов",
+		"validation.max.items":  "Поле {{.Field}} должно содержать не более {{.Param}} элементов",
+		"validation.len.items":  "Поле {{.Field}} должно содержать ровно {{.Param}} элементов",
+		"validation.min.number": "Значение поля {{.Field}} должно быть не меньше {{.Param}}",
+		"validation.max.number": "Значение поля {{.Field}} должно быть не больше {{.Param}}",
+		"validation.len.number": "Значение поля {{.Field}} должно быть равно {{.Param}}",
+		"validation.gt":         "Значение поля {{.Field}} должно быть больше {{.Param}}",
+		"validation.gte":        "Значение поля {{.Field}} должно быть не меньше {{.Param}}",
+		"validation.lt":         "Значение поля {{.Field}} должно быть меньше {{.Param}}",
+		"validation.lte":        "Значение поля {{.Field}} должно быть не больше {{.Param}}",
+		"validation.oneof":      "Поле {{.Field}} должно иметь одно из значений: {{.Param}}",
+		"validation.email":      "Поле {{.Field}} должно содержать корректный email",
+		"validation.url":        "Поле {{.Field}} должно содержать корректный URL",
+		"validation.e164":       "Поле {{.Field}} должно содержать номер телефона в формате E.164",
+		"validation.hexcolor":   "Поле {{.Field}} должно содержать цвет в формате HEX (например, #3788d8)",
+		"validation.notblank":   "Поле {{.Field}} не может быть пустым",
+		"validation.future":     "Поле {{.Field}} должно содержать дату в будущем",
+		"validation.enum":       "Поле {{.Field}} содержит неподдерживаемое значение",
+		"validation.type":       "Поле {{.Field}} должно иметь тип {{.Param}}",
+		"validation.invalid":    "Поле {{.Field}} не прошло проверку {{.Rule}}",
+
+		// Notification content
+		"notification.welcome_title":                      "Добро пожаловать, {{.UserName}}!",
+		"notification.welcome_message":                    "Ваш аккаунт успешно создан в Tachyon Messenger",
+		"notification.onboarding_profile_title":           "Заполните профиль",
+		"notification.onboarding_profile_message":         "{{.UserName}}, добавьте фото, должность и телефон, чтобы коллеги могли вас узнать и связаться с вами.",
+		"notification.onboarding_dept_chat_title":         "Чат вашего отдела",
+		"notification.onboarding_dept_chat_message":       "Загляните в чат своего отдела: там обсуждают рабочие вопросы и встречают новых коллег.",
+		"notification.task_assigned_title":                "Новая задача: {{.TaskTitle}}",
+		"notification.task_assigned_message":              "Вам назначена задача с приоритетом {{.TaskPriority}}",
+		"notification.message_notification_title":         "Новое сообщение от {{.SenderName}}",
+		"notification.message_notification_message":       "{{.MessageContent}}",
+		"notification.calendar_reminder_title":            "Напоминание: {{.EventTitle}}",
+		"notification.calendar_reminder_message":          "Событие начинается {{.StartTime}}",
+		"notification.calendar_rsvp_reminder_title":       "Ответьте на приглашение: {{.EventTitle}}",
+		"notification.calendar_rsvp_reminder_message":     "Событие начинается {{.StartTime}}. Подтвердите или отклоните участие.",
+		"notification.calendar_event_cancelled_title":     "Событие отменено: {{.EventTitle}}",
+		"notification.calendar_event_cancelled_message":   "Причина: {{.Reason}}",
+		"notification.calendar_event_rescheduled_title":   "Событие перенесено: {{.EventTitle}}",
+		"notification.calendar_event_rescheduled_message": "Новое время: {{.StartTime}}. {{.Reason}}",
+		"notification.calendar_delegate_invite_title":     "Приглашение на событие: {{.EventTitle}}",
+		"notification.calendar_delegate_invite_message":   "Приглашение отправлено ассистентом от имени организатора. Начало {{.StartTime}}.",
+		"notification.calendar_delegate_created_title":    "Событие создано от вашего имени: {{.EventTitle}}",
+		"notification.calendar_delegate_created_message":  "Ассистент добавил событие в ваш календарь. Начало {{.StartTime}}.",
+		"notification.calendar_delegate_replied_title":    "Ответ на приглашение от вашего имени: {{.EventTitle}}",
+		"notification.calendar_delegate_replied_message":  "Ассистент ответил на приглашение: {{.Status}}.",
+		"notification.company_event_published_title":      "Событие компании: {{.EventTitle}}",
+		"notification.company_event_published_message":    "Начало {{.StartTime}}. {{.Location}}",
+		"notification.company_event_updated_title":        "Событие компании изменено: {{.EventTitle}}",
+		"notification.company_event_updated_message":      "Начало {{.StartTime}}. {{.Location}}",
+		"notification.poll_deadline_extended_title":       "Голосование продлено: {{.PollTitle}}",
+		"notification.poll_deadline_extended_message":     "Голосование продлится до {{.EndTime}}. Вы ещё не проголосовали.",
+		"notification.poll_comment_hidden_title":          "Комментарий скрыт: {{.PollTitle}}",
+		"notification.poll_comment_hidden_message":        "Модератор скрыл ваш комментарий к опросу. {{.Note}}",
+		"notification.poll_comment_auto_hidden_message":   "Ваш комментарий к опросу скрыт после жалоб участников и ждёт решения модератора.",
+		"notification.poll_comment_removed_title":         "Комментарий удалён: {{.PollTitle}}",
+		"notification.poll_comment_removed_message":       "Модератор удалил ваш комментарий к опросу. {{.Note}}",
+		"notification.poll_comment_restored_title":        "Комментарий восстановлен: {{.PollTitle}}",
+		"notification.poll_comment_restored_message":      "Модератор отклонил жалобы, ваш комментарий к опросу снова виден участникам. {{.Note}}",
+		"notification.department_merged_title":            "Отдел {{.PreviousDepartment}} объединён с отделом {{.Department}}",
+		"notification.company_event_retargeted_message":   "Событие компании «{{.EventTitle}}» теперь адресовано отделу {{.Department}}.",
+		"notification.poll_retargeted_message":            "Голосование «{{.PollTitle}}» теперь проходит в отделе {{.Department}}.",
+		"notification.security_new_device_title":          "Вход с нового устройства",
+		"notification.security_new_device_message":        "В ваш аккаунт выполнен вход с устройства {{.Device}} (IP {{.IP}}). Если это были не вы, смените пароль.",
+		"notification.security_password_changed_title":    "Пароль изменён",
+		"notification.security_password_changed_message":  "Пароль вашего аккаунта был изменён. Если это были не вы, обратитесь к администратору.",
+		"notification.security_role_changed_title":        "Роль изменена",
+		"notification.security_role_changed_message":      "Ваша роль изменена на {{.Role}}.",
+		"notification.daily_digest_title":                 "Сводка за {{.Date}}",
+		"notification.daily_digest_message":               "Новых сообщений: {{.NewMessages}}, непрочитанных: {{.UnreadMessages}}. Новых задач: {{.NewTasks}}, просроченных: {{.OverdueTasks}}. Событий сегодня: {{.TodayEvents}}.",
+
+		// Email wrappers
+		"email.automated_footer": "Это автоматическое сообщение от Tachyon Messenger",
+		"email.more_info":        "Для получения дополнительной информации перейдите по ссылке: {{.URL}}",
+		"email.open":             "Открыть",
+
+		// Calendar
+		"calendar.quick_add_default_title": "Новое событие",
+
+		// Chat slash commands
+		"chat.command_task_created":     "Создана задача «{{.Title}}»",
+		"chat.command_poll_created":     "Создан опрос «{{.Question}}»",
+		"chat.command_poll_yes":         "Да",
+		"chat.command_poll_no":          "Нет",
+		"chat.command_remind_scheduled": "Напоминание запланировано на {{.Time}}",
+		"chat.command_remind_default":   "Напоминание",
+	},
+	LocaleEN: {
+		// API errors
+		"error.authorization_required":       "Authorization header is required",
+		"error.invalid_authorization_format": "Invalid authorization header format",
+		"error.invalid_token":                "Invalid or expired token",
+		"error.user_role_not_found":          "User role not found in context",
+		"error.invalid_role_type":            "Invalid role type in context",
+		"error.insufficient_permissions":     "Insufficient permissions",
+		"error.authentication_required":      "Authentication required",
+		"error.invalid_authentication_data":  "Invalid authentication data",
+		"error.admin_access_required":        "Admin access required",
+		"error.super_admin_access_required":  "Super admin access required",
+		"error.invalid_content_type":         "Invalid Content-Type",
+		"error.invalid_request_body":         "Invalid request body",
+		"error.invalid_filter_parameters":    "Invalid filter parameters",
+		"error.invalid_query_parameters":     "Invalid query parameters",
+		"error.invalid_calendar_parameters":  "Invalid calendar parameters",
+		"error.maintenance":                  "Maintenance in progress, please try again later",
+		"error.feature_disabled":             "This feature is temporarily disabled",
+		"error.request_too_large":            "Request body is too large",
+		"error.service_overloaded":           "Service is overloaded, please retry later",
+		"error.admin_network_denied":         "Admin access is not allowed from this network",
+		"error.quota_exceeded":               "Request quota exceeded, please retry later",
+
+		// Validation errors
+		"validation.value":      "value",
+		"validation.required":   "{{.Field}} is required",
+		"validation.min.string": "{{.Field}} must be at least {{.Param}} characters long",
+		"validation.max.string": "{{.Field}} must be at most {{.Param}} characters long",
+		"validation.len.string": "{{.Field}} must be exactly {{.Param}} characters long",
+		"validation.min.items":  "{{.Field}} must contain at least {{.Param}} items",
+		"validation.max.items":  "{{.Field}} must contain at most {{.Param}} items",
+		"validation.len.items":  "{{.Field}} must contain exactly {{.Param}} items",
+		"validation.min.number": "{{.Field}} must be at least {{.Param}}",
+		"validation.max.number": "{{.Field}} must be at most {{.Param}}",
+		"validation.len.number": "{{.Field}} must be equal to {{.Param}}",
+		"validation.gt":         "{{.Field}} must be greater than {{.Param}}",
+		"validation.gte":        "{{.Field}} must be at least {{.Param}}",
+		"validation.lt":         "{{.Field}} must be less than {{.Param}}",
+		"validation.lte":        "{{.Field}} must be at most {{.Param}}",
+		"validation.oneof":      "{{.Field}} must be one of: {{.Param}}",
+		"validation.email":      "{{.Field}} must be a valid email address",
+		"validation.url":        "{{.Field}} must be a valid URL",
+		"validation.e164":       "{{.Field}} must be a phone number in E.164 format",
+		"validation.hexcolor":   "{{.Field}} must be a valid hex color code (e.g., #3788d8)",
+		"validation.notblank":   "{{.Field}} cannot be blank",
+		"validation.future":     "{{.Field}} must be in the future",
+		"validation.enum":       "{{.Field}} has an unsupported value",
+		"validation.type":       "{{.Field}} must be of type {{.Param}}",
+		"validation.invalid":    "{{.Field}} failed on the {{.Rule}} rule",
+
+		// Notification content
+		"notification.welcome_title":                      "Welcome, {{.UserName}}!",
+		"notification.welcome_message":                    "Your Tachyon Messenger account has been created",
+		"notification.onboarding_profile_title":           "Complete your profile",
+		"notification.onboarding_profile_message":         "{{.UserName}}, add a photo, your position and phone so colleagues can recognize and reach you.",
+		"notification.onboarding_dept_chat_title":         "Join your department chat",
+		"notification.onboarding_dept_chat_message":       "Drop by your department chat: that's where work is discussed and new colleagues are welcomed.",
+		"notification.task_assigned_title":                "New task: {{.TaskTitle}}",
+		"notification.task_assigned_message":              "You have been assigned a task with {{.TaskPriority}} priority",
+		"notification.message_notification_title":         "New message from {{.SenderName}}",
+		"notification.message_notification_message":       "{{.MessageContent}}",
+		"notification.calendar_reminder_title":            "Reminder: {{.EventTitle}}",
+		"notification.calendar_reminder_message":          "Event starts at {{.StartTime}}",
+		"notification.calendar_rsvp_reminder_title":       "Please respond: {{.EventTitle}}",
+		"notification.calendar_rsvp_reminder_message":     "Event starts at {{.StartTime}}. Accept or decline the invitation.",
+		"notification.calendar_event_cancelled_title":     "Event cancelled: {{.EventTitle}}",
+		"notification.calendar_event_cancelled_message":   "Reason: {{.Reason}}",
+		"notification.calendar_event_rescheduled_title":   "Event rescheduled: {{.EventTitle}}",
+		"notification.calendar_event_rescheduled_message": "New time: {{.StartTime}}. {{.Reason}}",
+		"notification.calendar_delegate_invite_title":     "Invitation: {{.EventTitle}}",
+		"notification.calendar_delegate_invite_message":   "Sent by an assistant on behalf of the organizer. Starts at {{.StartTime}}.",
+		"notification.calendar_delegate_created_title":    "Event created on your behalf: {{.EventTitle}}",
+		"notification.calendar_delegate_created_message":  "Your assistant added an event to your calendar. Starts at {{.StartTime}}.",
+		"notification.calendar_delegate_replied_title":    "Invitation answered on your behalf: {{.EventTitle}}",
+		"notification.calendar_delegate_replied_message":  "Your assistant responded to the invitation: {{.Status}}.",
+		"notification.company_event_published_title":      "Company event: {{.EventTitle}}",
+		"notification.company_event_published_message":    "Starts at {{.StartTime}}. {{.Location}}",
+		"notification.company_event_updated_title":        "Company event updated: {{.EventTitle}}",
+		"notification.company_event_updated_message":      "Starts at {{.StartTime}}. {{.Location}}",
+		"notification.poll_deadline_extended_title":       "Poll extended: {{.PollTitle}}",
+		"notification.poll_deadline_extended_message":     "Voting is open until {{.EndTime}}. You have not voted yet.",
+		"notification.poll_comment_hidden_title":          "Comment hidden: {{.PollTitle}}",
+		"notification.poll_comment_hidden_message":        "A moderator hid your comment on the poll. {{.Note}}",
+		"notification.poll_comment_auto_hidden_message":   "Your comment on the poll was hidden after reports from participants and awaits a moderator's decision.",
+		"notification.poll_comment_removed_title":         "Comment removed: {{.PollTitle}}",
+		"notification.poll_comment_removed_message":       "A moderator removed your comment on the poll. {{.Note}}",
+		"notification.poll_comment_restored_title":        "Comment restored: {{.PollTitle}}",
+		"notification.poll_comment_restored_message":      "A moderator dismissed the reports, your comment on the poll is visible again. {{.Note}}",
+		"notification.department_merged_title":            "Department {{.PreviousDepartment}} merged into {{.Department}}",
+		"notification.company_event_retargeted_message":   "Company event \"{{.EventTitle}}\" now targets department {{.Department}}.",
+		"notification.poll_retargeted_message":            "Poll \"{{.PollTitle}}\" now belongs to department {{.Department}}.",
+		"notification.security_new_device_title":          "New device sign-in",
+		"notification.security_new_device_message":        "Your account was signed in from {{.Device}} (IP {{.IP}}). If this wasn't you, change your password.",
+		"notification.security_password_changed_title":    "Password changed",
+		"notification.security_password_changed_message":  "Your account password was changed. If this wasn't you, contact an administrator.",
+		"notification.security_role_changed_title":        "Role changed",
+		"notification.security_role_changed_message":      "Your role has been changed to {{.Role}}.",
+		"notification.daily_digest_title":                 "Daily summary for {{.Date}}",
+		"notification.daily_digest_message":               "New messages: {{.NewMessages}}, unread: {{.UnreadMessages}}. New tasks: {{.NewTasks}}, overdue: {{.OverdueTasks}}. Events today: {{.TodayEvents}}.",
+
+		// Email wrappers
+		"email.automated_footer": "This is an automated message from Tachyon Messenger",
+		"email.more_info":        "For more information follow the link: {{.URL}}",
+		"email.open":             "Open",
+
+		// Calendar
+		"calendar.quick_add_default_title": "New event",
+
+		// Chat slash commands
+		"chat.command_task_created":     "Created task “{{.Title}}”",
+		"chat.command_poll_created":     "Created poll “{{.Question}}”",
+		"chat.command_poll_yes":         "Yes",
+		"chat.command_poll_no":          "No",
+		"chat.command_remind_scheduled": "Reminder set for {{.Time}}",
+		"chat.command_remind_default":   "Reminder",
+	},
+}
@@ -0,0 +1,47 @@
+package i18n
+
+import (
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Date and time layouts of locales, x/text has no date formatting
+var dateLayouts = map[Locale]struct{ date, dateTime string }{
+	LocaleRU: {"02.01.2006", "02.01.2006 15:04"},
+	LocaleEN: {"Jan 2, 2006", "Jan 2, 2006 3:04 PM"},
+}
+
+// Tag returns the language tag of locale, DefaultLocale for unsupported ones
+func Tag(locale Locale) language.Tag {
+	if !locale.IsValid() {
+		locale = DefaultLocale
+	}
+	return language.Make(string(locale))
+}
+
+// FormatNumber formats a number with digit grouping and decimal separator of locale,
+// e.g. 1 234,5 in Russian and 1,234.5 in English. Fractions are rounded to two digits.
+func FormatNumber(locale Locale, value interface{}) string {
+	return message.NewPrinter(Tag(locale)).Sprint(number.Decimal(value, number.MaxFractionDigits(2)))
+}
+
+// FormatDate formats the date of t in locale
+func FormatDate(locale Locale, t time.Time) string {
+	return t.Format(layouts(locale).date)
+}
+
+// FormatDateTime formats the date and time of t in locale
+func FormatDateTime(locale Locale, t time.Time) string {
+	return t.Format(layouts(locale).dateTime)
+}
+
+// layouts returns date layouts of locale, DefaultLocale for unsupported ones
+func layouts(locale Locale) struct{ date, dateTime string } {
+	if layout, exists := dateLayouts[locale]; exists {
+		return layout
+	}
+	return dateLayouts[DefaultLocale]
+}
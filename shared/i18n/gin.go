@@ -0,0 +1,24 @@
+package i18n
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ContextKey is the gin context key of the user locale, set by the JWT middleware from claims
+const ContextKey = "locale"
+
+// FromContext returns the locale of the request: the user locale from JWT claims,
+// then the Accept-Language header, then DefaultLocale
+func FromContext(c *gin.Context) Locale {
+	if value, exists := c.Get(ContextKey); exists {
+		if locale, ok := value.(Locale); ok && locale.IsValid() {
+			return locale
+		}
+	}
+	return Parse(c.GetHeader("Accept-Language"))
+}
+
+// Message returns the message for key in the locale of the request
+func Message(c *gin.Context, key string) string {
+	return T(FromContext(c), key, nil)
+}
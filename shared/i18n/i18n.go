@@ -0,0 +1,86 @@
+// Package i18n renders user-facing messages (API errors, validation errors, notification
+// content) in the locale of the user. Messages are looked up by key in built-in bundles.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Locale is a supported language code
+type Locale string
+
+const (
+	LocaleRU Locale = "ru"
+	LocaleEN Locale = "en"
+
+	// DefaultLocale is used when the user has no locale or it is not supported
+	DefaultLocale = LocaleRU
+)
+
+// IsValid checks if the locale has a bundle
+func (l Locale) IsValid() bool {
+	_, exists := bundles[l]
+	return exists
+}
+
+// Supported returns all locales with bundles
+func Supported() []Locale {
+	locales := make([]Locale, 0, len(bundles))
+	for locale := range bundles {
+		locales = append(locales, locale)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// Parse returns the first supported locale of a language tag ("en", "en-US") or an
+// Accept-Language header value ("en-US,en;q=0.9,ru;q=0.8"). It falls back to DefaultLocale.
+func Parse(value string) Locale {
+	if locale, ok := Detect(value); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Detect is like Parse but reports whether a supported locale was found instead of
+// falling back, so callers can use their own default, e.g. the organization one
+func Detect(value string) (Locale, bool) {
+	for _, part := range strings.Split(value, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if i := strings.IndexAny(tag, "-_"); i >= 0 {
+			tag = tag[:i]
+		}
+		if locale := Locale(strings.ToLower(tag)); locale.IsValid() {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// T returns the message for key in locale with {{.Name}} placeholders replaced by args.
+// Missing keys fall back to DefaultLocale and then to the key itself.
+func T(locale Locale, key string, args map[string]interface{}) string {
+	message, exists := bundles[locale][key]
+	if !exists {
+		message, exists = bundles[DefaultLocale][key]
+	}
+	if !exists {
+		return key
+	}
+
+	for name, value := range args {
+		message = strings.ReplaceAll(message, fmt.Sprintf("{{.%s}}", name), fmt.Sprintf("%v", value))
+	}
+	return message
+}
+
+// Has checks if locale (or DefaultLocale) has a message for key
+func Has(locale Locale, key string) bool {
+	if _, exists := bundles[locale][key]; exists {
+		return true
+	}
+	_, exists := bundles[DefaultLocale][key]
+	return exists
+}
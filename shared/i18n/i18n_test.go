@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]Locale{
+		"":                        DefaultLocale,
+		"en":                      LocaleEN,
+		"en-US":                   LocaleEN,
+		"RU_ru":                   LocaleRU,
+		"de-DE,en;q=0.9,ru;q=0.8": LocaleEN,
+		"fr":                      DefaultLocale,
+	}
+	for value, expected := range cases {
+		if locale := Parse(value); locale != expected {
+			t.Errorf("Parse(%q) = %q, expected %q", value, locale, expected)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	args := map[string]interface{}{"Field": "title"}
+	if message := T(LocaleEN, "validation.required", args); message != "title is required" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if message := T(Locale("fr"), "validation.required", args); message != T(DefaultLocale, "validation.required", args) {
+		t.Errorf("expected fallback to default locale, got %q", message)
+	}
+	if message := T(LocaleEN, "unknown.key", nil); message != "unknown.key" {
+		t.Errorf("expected key for missing message, got %q", message)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	moment := time.Date(2026, time.March, 5, 14, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		locale   Locale
+		number   string
+		date     string
+		dateTime string
+	}{
+		{LocaleRU, "1 234 567,5", "05.03.2026", "05.03.2026 14:30"},
+		{LocaleEN, "1,234,567.5", "Mar 5, 2026", "Mar 5, 2026 2:30 PM"},
+		{Locale("fr"), "1 234 567,5", "05.03.2026", "05.03.2026 14:30"},
+	}
+	for _, c := range cases {
+		if number := FormatNumber(c.locale, 1234567.5); number != c.number {
+			t.Errorf("FormatNumber(%q) = %q, expected %q", c.locale, number, c.number)
+		}
+		if date := FormatDate(c.locale, moment); date != c.date {
+			t.Errorf("FormatDate(%q) = %q, expected %q", c.locale, date, c.date)
+		}
+		if dateTime := FormatDateTime(c.locale, moment); dateTime != c.dateTime {
+			t.Errorf("FormatDateTime(%q) = %q, expected %q", c.locale, dateTime, c.dateTime)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	if locale, ok := Detect("de-DE,en-GB;q=0.8"); !ok || locale != LocaleEN {
+		t.Errorf("expected en to be detected, got %q", locale)
+	}
+	if _, ok := Detect("fr-FR"); ok {
+		t.Error("expected no supported locale")
+	}
+}
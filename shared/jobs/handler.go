@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes adds admin endpoints of the scheduler to an admin-only route group:
+//
+//	GET  /jobs             - list jobs with state and last run
+//	GET  /jobs/:name/runs  - recent runs of a job
+//	POST /jobs/:name/run   - trigger a run
+//	POST /jobs/:name/pause - pause scheduled runs
+//	POST /jobs/:name/resume
+func RegisterRoutes(group *gin.RouterGroup, scheduler *Scheduler) {
+	jobs := group.Group("/jobs")
+	{
+		jobs.GET("", listJobsHandler(scheduler))
+		jobs.GET("/:name/runs", jobRunsHandler(scheduler))
+		jobs.POST("/:name/run", jobActionHandler(scheduler, "triggered", scheduler.Trigger))
+		jobs.POST("/:name/pause", jobActionHandler(scheduler, "paused", scheduler.Pause))
+		jobs.POST("/:name/resume", jobActionHandler(scheduler, "resumed", scheduler.Resume))
+	}
+}
+
+// listJobsHandler returns registered jobs
+func listJobsHandler(scheduler *Scheduler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		jobs, err := scheduler.Jobs()
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"request_id": requestID,
+				"error":      err.Error(),
+			}).Error("Failed to list jobs")
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":      "Failed to list jobs",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"service":    scheduler.service,
+			"jobs":       jobs,
+			"request_id": requestID,
+		})
+	}
+}
+
+// jobRunsHandler returns recent runs of a job
+func jobRunsHandler(scheduler *Scheduler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+		name := c.Param("name")
+
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+		runs, err := scheduler.Runs(name, limit)
+		if err != nil {
+			writeJobError(c, requestID, name, "Failed to get job runs", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"job":        name,
+			"runs":       runs,
+			"request_id": requestID,
+		})
+	}
+}
+
+// jobActionHandler applies an action to a job
+func jobActionHandler(scheduler *Scheduler, result string, action func(name string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+		name := c.Param("name")
+
+		if err := action(name); err != nil {
+			writeJobError(c, requestID, name, "Failed to update job", err)
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"service":    scheduler.service,
+			"job":        name,
+		}).Info("Job " + result)
+
+		status := http.StatusOK
+		if result == "triggered" {
+			status = http.StatusAccepted
+		}
+		c.JSON(status, gin.H{
+			"message":    "Job " + result,
+			"job":        name,
+			"request_id": requestID,
+		})
+	}
+}
+
+// writeJobError maps scheduler errors to HTTP responses
+func writeJobError(c *gin.Context, requestID, name, message string, err error) {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":      "Job not found",
+			"request_id": requestID,
+		})
+	case errors.Is(err, ErrJobRunning):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Job is already running",
+			"request_id": requestID,
+		})
+	default:
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"job":        name,
+			"error":      err.Error(),
+		}).Error(message)
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      message,
+			"request_id": requestID,
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import "time"
+
+// RunStatus represents the outcome of a job run
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// RunTrigger tells what started a job run
+type RunTrigger string
+
+const (
+	TriggerSchedule RunTrigger = "schedule"
+	TriggerManual   RunTrigger = "manual"
+)
+
+// JobRun records a single run of a background job
+type JobRun struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Service    string     `gorm:"not null;size:50;index:idx_job_runs_job" json:"service"`
+	Job        string     `gorm:"not null;size:100;index:idx_job_runs_job" json:"job"`
+	Trigger    RunTrigger `gorm:"not null;size:20" json:"trigger"`
+	Status     RunStatus  `gorm:"not null;size:20" json:"status"`
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  time.Time  `gorm:"not null;index" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+
+	// Values reported by the job with Report, e.g. numbers of processed records
+	Report map[string]interface{} `gorm:"type:text;serializer:json" json:"report,omitempty"`
+}
+
+// TableName returns the table name for JobRun model
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// JobState stores the paused state of a job, shared by all instances of a service
+type JobState struct {
+	Service   string    `gorm:"primaryKey;size:50" json:"service"`
+	Job       string    `gorm:"primaryKey;size:100" json:"job"`
+	Paused    bool      `gorm:"not null;default:false" json:"paused"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for JobState model
+func (JobState) TableName() string {
+	return "job_states"
+}
+
+// JobInfo describes a registered job for admin endpoints
+type JobInfo struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Paused   bool       `json:"paused"`
+	Running  bool       `json:"running"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	LastRun  *JobRun    `json:"last_run,omitempty"`
+}
+
+// Models returns models to migrate in services using the scheduler
+func Models() []interface{} {
+	return []interface{}{&JobRun{}, &JobState{}}
+}
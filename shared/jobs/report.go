@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// reportKey is the context key of the report of the current run
+type reportKey struct{}
+
+// runReport collects values a job reports about its run
+type runReport struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+// Report adds a value to the report of the current run, e.g. the number of deleted records.
+// Reports are stored with the run and shown by the admin endpoints. It does nothing if ctx
+// does not belong to a job run.
+func Report(ctx context.Context, key string, value interface{}) {
+	report, ok := ctx.Value(reportKey{}).(*runReport)
+	if !ok {
+		return
+	}
+
+	report.mu.Lock()
+	defer report.mu.Unlock()
+	if report.values == nil {
+		report.values = make(map[string]interface{})
+	}
+	report.values[key] = value
+}
+
+// withReport returns a context collecting the report of a run
+func withReport(ctx context.Context) (context.Context, *runReport) {
+	report := &runReport{}
+	return context.WithValue(ctx, reportKey{}, report), report
+}
+
+// snapshot returns reported values, nil if nothing was reported
+func (r *runReport) snapshot() map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.values) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{}, len(r.values))
+	for key, value := range r.values {
+		values[key] = value
+	}
+	return values
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 7, 30, 0, time.UTC) // Пятница
+
+	cases := map[string]time.Time{
+		"@every 10m":   base.Add(10 * time.Minute),
+		"*/15 * * * *": time.Date(2024, time.March, 15, 10, 15, 0, 0, time.UTC),
+		"0 3 * * *":    time.Date(2024, time.March, 16, 3, 0, 0, 0, time.UTC),
+		"30 9 * * 1-5": time.Date(2024, time.March, 18, 9, 30, 0, 0, time.UTC),
+		"0 0 1 * *":    time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"@hourly":      time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC),
+		"0 12 1 * 5":   time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC),
+	}
+	for spec, expected := range cases {
+		schedule, err := ParseSchedule(spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) failed: %v", spec, err)
+			continue
+		}
+		if next := schedule.Next(base); !next.Equal(expected) {
+			t.Errorf("ParseSchedule(%q).Next = %v, expected %v", spec, next, expected)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "@every -1m", "@every soon"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) expected error", spec)
+		}
+	}
+
+	never, err := ParseSchedule("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("ParseSchedule failed: %v", err)
+	}
+	if next := never.Next(base); !next.IsZero() {
+		t.Errorf("expected no run for February 31, got %v", next)
+	}
+}
@@ -0,0 +1,413 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/redis"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultTimeout bounds a job run and how long its lock is held
+	DefaultTimeout = 30 * time.Minute
+
+	// historyLimit is the number of runs kept per job
+	historyLimit = 100
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+)
+
+// Func is the work of a job. The context is cancelled when the job times out or the scheduler stops.
+type Func func(ctx context.Context) error
+
+// Job is a background task run on a schedule
+type Job struct {
+	Name     string
+	Schedule string        // See ParseSchedule
+	Timeout  time.Duration // Zero uses DefaultTimeout
+	Run      Func
+}
+
+// scheduledJob is a registered job with its parsed schedule
+type scheduledJob struct {
+	Job
+	schedule Schedule
+	running  atomic.Bool
+	nextRun  time.Time // Guarded by Scheduler.mu
+}
+
+// Scheduler runs background jobs of a service. A run takes a Redis lock, so with several
+// instances of a service each run happens on one of them; without Redis every instance runs jobs.
+// Runs are recorded in the database, panics are recovered and recorded as failures.
+type Scheduler struct {
+	service string
+	db      *database.DB
+	locker  *redis.Client
+
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a job scheduler for the service, locker may be nil
+func NewScheduler(service string, db *database.DB, locker *redis.Client) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		service: service,
+		db:      db,
+		locker:  locker,
+		jobs:    make(map[string]*scheduledJob),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Register adds a job, jobs registered after Start are scheduled immediately
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job name and function are required")
+	}
+
+	schedule, err := ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = DefaultTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+
+	scheduled := &scheduledJob{Job: job, schedule: schedule}
+	s.jobs[job.Name] = scheduled
+	if s.started {
+		s.startJob(scheduled)
+	}
+	return nil
+}
+
+// Start begins running jobs on their schedules
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, job := range s.jobs {
+		s.startJob(job)
+	}
+}
+
+// Stop stops scheduling and waits for running jobs, which see their context cancelled
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Trigger starts a job run outside its schedule, paused jobs can be triggered too
+func (s *Scheduler) Trigger(name string) error {
+	job, err := s.getJob(name)
+	if err != nil {
+		return err
+	}
+	if job.running.Load() {
+		return ErrJobRunning
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(job, TriggerManual)
+	}()
+	return nil
+}
+
+// Pause stops scheduled runs of a job on all instances until it is resumed
+func (s *Scheduler) Pause(name string) error {
+	return s.setPaused(name, true)
+}
+
+// Resume restarts scheduled runs of a paused job
+func (s *Scheduler) Resume(name string) error {
+	return s.setPaused(name, false)
+}
+
+// Jobs returns registered jobs with their state and last run
+func (s *Scheduler) Jobs() ([]*JobInfo, error) {
+	paused, err := s.pausedJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	infos := make([]*JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		info := &JobInfo{
+			Name:     job.Name,
+			Schedule: job.Schedule,
+			Paused:   paused[job.Name],
+			Running:  job.running.Load(),
+		}
+		if !job.nextRun.IsZero() {
+			nextRun := job.nextRun
+			info.NextRun = &nextRun
+		}
+		infos = append(infos, info)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	for _, info := range infos {
+		runs, err := s.Runs(info.Name, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			info.LastRun = runs[0]
+		}
+	}
+
+	return infos, nil
+}
+
+// Runs returns recent runs of a job, most recent first
+func (s *Scheduler) Runs(name string, limit int) ([]*JobRun, error) {
+	if _, err := s.getJob(name); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > historyLimit {
+		limit = historyLimit
+	}
+
+	var runs []*JobRun
+	err := s.db.Where("service = ? AND job = ?", s.service, name).
+		Order("id DESC").Limit(limit).Find(&runs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job runs: %w", err)
+	}
+	return runs, nil
+}
+
+// startJob runs the schedule loop of a job, s.mu must be held
+func (s *Scheduler) startJob(job *scheduledJob) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(job)
+	}()
+}
+
+// loop waits for scheduled times of a job and runs it unless paused
+func (s *Scheduler) loop(job *scheduledJob) {
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+
+		s.mu.Lock()
+		job.nextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		paused, err := s.isPaused(job.Name)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"service": s.service,
+				"job":     job.Name,
+				"error":   err.Error(),
+			}).Error("Failed to check job state")
+			continue
+		}
+		if paused {
+			continue
+		}
+
+		s.run(job, TriggerSchedule)
+	}
+}
+
+// run executes a job once if no other run of it is in progress on any instance
+func (s *Scheduler) run(job *scheduledJob, trigger RunTrigger) {
+	if !job.running.CompareAndSwap(false, true) {
+		return
+	}
+	defer job.running.Store(false)
+
+	fields := map[string]interface{}{
+		"service": s.service,
+		"job":     job.Name,
+		"trigger": trigger,
+	}
+
+	release, acquired, err := s.locker.TryLock(fmt.Sprintf("jobs:lock:%s:%s", s.service, job.Name), job.Timeout)
+	if err != nil {
+		fields["error"] = err.Error()
+		logger.WithFields(fields).Error("Failed to lock job")
+		return
+	}
+	if !acquired {
+		logger.WithFields(fields).Debug("Job is running on another instance")
+		return
+	}
+	defer release()
+
+	record := &JobRun{
+		Service:   s.service,
+		Job:       job.Name,
+		Trigger:   trigger,
+		Status:    RunStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.db.Create(record).Error; err != nil {
+		fields["error"] = err.Error()
+		logger.WithFields(fields).Warn("Failed to record job run")
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, job.Timeout)
+	ctx, report := withReport(ctx)
+	err = safeRun(ctx, job.Run)
+	cancel()
+
+	finishedAt := time.Now()
+	record.FinishedAt = &finishedAt
+	record.Report = report.snapshot()
+	record.DurationMs = finishedAt.Sub(record.StartedAt).Milliseconds()
+	record.Status = RunStatusSucceeded
+	if err != nil {
+		record.Status = RunStatusFailed
+		record.Error = err.Error()
+
+		fields["error"] = err.Error()
+		logger.WithFields(fields).Error("Job failed")
+	}
+
+	if record.ID != 0 {
+		if err := s.db.Save(record).Error; err != nil {
+			logger.WithFields(map[string]interface{}{
+				"service": s.service,
+				"job":     job.Name,
+				"error":   err.Error(),
+			}).Warn("Failed to record job run")
+		}
+		s.pruneHistory(job.Name)
+	}
+}
+
+// safeRun calls the job function turning a panic into an error
+func safeRun(ctx context.Context, fn Func) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			logger.WithField("stack", string(debug.Stack())).Error("Job panicked")
+		}
+	}()
+	return fn(ctx)
+}
+
+// pruneHistory deletes old runs of a job beyond historyLimit
+func (s *Scheduler) pruneHistory(name string) {
+	keep := s.db.Model(&JobRun{}).Select("id").
+		Where("service = ? AND job = ?", s.service, name).
+		Order("id DESC").Limit(historyLimit)
+
+	err := s.db.Where("service = ? AND job = ? AND id NOT IN (?)", s.service, name, keep).
+		Delete(&JobRun{}).Error
+	if err != nil {
+		logger.WithFields(map[string]interface{}{
+			"service": s.service,
+			"job":     name,
+			"error":   err.Error(),
+		}).Warn("Failed to prune job run history")
+	}
+}
+
+// getJob returns a registered job by name
+func (s *Scheduler) getJob(name string) (*scheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// setPaused stores the paused state of a job
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	if _, err := s.getJob(name); err != nil {
+		return err
+	}
+
+	state := &JobState{Service: s.service, Job: name, Paused: paused}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "service"}, {Name: "job"}},
+		DoUpdates: clause.AssignmentColumns([]string{"paused", "updated_at"}),
+	}).Create(state).Error
+	if err != nil {
+		return fmt.Errorf("failed to update job state: %w", err)
+	}
+	return nil
+}
+
+// isPaused checks the stored paused state of a job
+func (s *Scheduler) isPaused(name string) (bool, error) {
+	var state JobState
+	err := s.db.Where("service = ? AND job = ?", s.service, name).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get job state: %w", err)
+	}
+	return state.Paused, nil
+}
+
+// pausedJobs returns paused state of all jobs of the service
+func (s *Scheduler) pausedJobs() (map[string]bool, error) {
+	var states []JobState
+	if err := s.db.Where("service = ?", s.service).Find(&states).Error; err != nil {
+		return nil, fmt.Errorf("failed to get job states: %w", err)
+	}
+
+	paused := make(map[string]bool, len(states))
+	for _, state := range states {
+		paused[state.Job] = state.Paused
+	}
+	return paused, nil
+}
@@ -0,0 +1,184 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tachyon-messenger/shared/database"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestScheduler(t *testing.T) *Scheduler {
+	gdb, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to an in-memory database opens a new empty database
+	sqlDB, err := gdb.DB()
+	if err != nil {
+		t.Fatalf("failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := gdb.AutoMigrate(Models()...); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	scheduler := NewScheduler("test", &database.DB{DB: gdb}, nil)
+	t.Cleanup(scheduler.Stop)
+	return scheduler
+}
+
+func TestSchedulerTriggerRecordsRuns(t *testing.T) {
+	scheduler := newTestScheduler(t)
+
+	done := make(chan struct{}, 2)
+	calls := 0
+	err := scheduler.Register(Job{
+		Name:     "flaky",
+		Schedule: "@daily",
+		Run: func(ctx context.Context) error {
+			defer func() { done <- struct{}{} }()
+			calls++
+			if calls == 1 {
+				panic("boom")
+			}
+			return errors.New("still failing")
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := scheduler.Trigger("flaky"); err != nil {
+			t.Fatalf("Trigger failed: %v", err)
+		}
+		<-done
+		waitIdle(t, scheduler, "flaky")
+	}
+
+	runs, err := scheduler.Runs("flaky", 10)
+	if err != nil {
+		t.Fatalf("Runs failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	if runs[1].Status != RunStatusFailed || runs[1].Error != "panic: boom" {
+		t.Errorf("expected recovered panic recorded, got %+v", runs[1])
+	}
+	if runs[0].Status != RunStatusFailed || runs[0].Trigger != TriggerManual || runs[0].FinishedAt == nil {
+		t.Errorf("expected finished manual failed run, got %+v", runs[0])
+	}
+
+	if err := scheduler.Trigger("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestSchedulerRunReport(t *testing.T) {
+	scheduler := newTestScheduler(t)
+
+	done := make(chan struct{}, 1)
+	err := scheduler.Register(Job{
+		Name:     "cleanup",
+		Schedule: "@daily",
+		Run: func(ctx context.Context) error {
+			defer func() { done <- struct{}{} }()
+			Report(ctx, "deleted", 42)
+			Report(ctx, "data_type", "notifications")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if err := scheduler.Trigger("cleanup"); err != nil {
+		t.Fatalf("Trigger failed: %v", err)
+	}
+	<-done
+	waitIdle(t, scheduler, "cleanup")
+
+	runs, err := scheduler.Runs("cleanup", 1)
+	if err != nil {
+		t.Fatalf("Runs failed: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	// Numbers come back as float64 from the JSON column
+	if runs[0].Report["deleted"] != float64(42) || runs[0].Report["data_type"] != "notifications" {
+		t.Errorf("expected run report stored, got %+v", runs[0].Report)
+	}
+
+	// Reporting outside of a run is ignored
+	Report(context.Background(), "deleted", 1)
+}
+
+func TestSchedulerPause(t *testing.T) {
+	scheduler := newTestScheduler(t)
+
+	ran := make(chan struct{}, 10)
+	err := scheduler.Register(Job{
+		Name:     "tick",
+		Schedule: "@every 20ms",
+		Run: func(ctx context.Context) error {
+			ran <- struct{}{}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if err := scheduler.Pause("tick"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	scheduler.Start()
+
+	select {
+	case <-ran:
+		t.Fatal("paused job must not run on schedule")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	jobs, err := scheduler.Jobs()
+	if err != nil {
+		t.Fatalf("Jobs failed: %v", err)
+	}
+	if len(jobs) != 1 || !jobs[0].Paused || jobs[0].NextRun == nil {
+		t.Fatalf("expected paused job with next run, got %+v", jobs)
+	}
+
+	if err := scheduler.Resume("tick"); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("resumed job did not run")
+	}
+}
+
+func waitIdle(t *testing.T, scheduler *Scheduler, name string) {
+	job, err := scheduler.getJob(name)
+	if err != nil {
+		t.Fatalf("getJob failed: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for job.running.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("job did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
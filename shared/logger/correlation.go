@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Headers carrying correlation between services
+const (
+	CorrelationIDHeader = "X-Correlation-ID" // Shared by all work caused by one user action
+	ParentIDHeader      = "X-Parent-ID"      // Request or task that caused the request
+)
+
+// Correlation ties a request or a background task to the user action that caused it
+type Correlation struct {
+	ID        string // Global correlation ID, the request ID of the first request of the action
+	ParentID  string // ID of the request or task that caused this one
+	RequestID string // ID of this request or task
+}
+
+// Child returns the correlation of work caused by this request or task
+func (c Correlation) Child() Correlation {
+	return Correlation{ID: c.ID, ParentID: c.RequestID}
+}
+
+// Fields returns the non-empty correlation IDs as log fields
+func (c Correlation) Fields() map[string]interface{} {
+	fields := make(map[string]interface{}, 2)
+	if c.ID != "" {
+		fields["correlation_id"] = c.ID
+	}
+	if c.ParentID != "" {
+		fields["parent_id"] = c.ParentID
+	}
+	return fields
+}
+
+// SetHeaders sets headers making a request sent on behalf of this one its child
+func (c Correlation) SetHeaders(header http.Header) {
+	if c.ID == "" {
+		return
+	}
+	header.Set(CorrelationIDHeader, c.ID)
+	if c.RequestID != "" {
+		header.Set(ParentIDHeader, c.RequestID)
+	}
+}
+
+type correlationKey struct{}
+
+// ContextWithCorrelation returns a copy of ctx carrying the correlation
+func ContextWithCorrelation(ctx context.Context, correlation Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlation)
+}
+
+// CorrelationFromContext returns the correlation carried by ctx, empty if there is none
+func CorrelationFromContext(ctx context.Context) Correlation {
+	if ctx == nil {
+		return Correlation{}
+	}
+	correlation, _ := ctx.Value(correlationKey{}).(Correlation)
+	return correlation
+}
+
+// WithCorrelation adds correlation IDs to log entry using default logger
+func WithCorrelation(correlation Correlation) *logrus.Entry {
+	return defaultLogger.WithFields(correlation.Fields())
+}
+
+// FromContext adds correlation IDs carried by ctx to log entry using default logger
+func FromContext(ctx context.Context) *logrus.Entry {
+	return WithCorrelation(CorrelationFromContext(ctx))
+}
+
+// correlationTransport sets correlation headers from the request context on outgoing requests
+type correlationTransport struct {
+	base http.RoundTripper
+}
+
+// NewTransport returns an HTTP transport propagating correlation of requests created with
+// a context carrying one. A nil base uses http.DefaultTransport.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &correlationTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *correlationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	correlation := CorrelationFromContext(req.Context())
+	if correlation.ID == "" || req.Header.Get(CorrelationIDHeader) != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the request
+	req = req.Clone(req.Context())
+	correlation.SetHeaders(req.Header)
+	return t.base.RoundTrip(req)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/logger"
 	"tachyon-messenger/shared/models"
 
@@ -34,7 +35,7 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 		userRole, exists := c.Get("user_role")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":      "Authentication required",
+				"error":      i18n.Message(c, "error.authentication_required"),
 				"message":    "Please log in to access admin features",
 				"request_id": requestID,
 			})
@@ -45,7 +46,7 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 		role, ok := userRole.(models.Role)
 		if !ok {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":      "Invalid authentication data",
+				"error":      i18n.Message(c, "error.invalid_authentication_data"),
 				"request_id": requestID,
 			})
 			c.Abort()
@@ -68,7 +69,7 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 			}).Warn("Unauthorized admin access attempt")
 
 			c.JSON(http.StatusForbidden, gin.H{
-				"error":      "Admin access required",
+				"error":      i18n.Message(c, "error.admin_access_required"),
 				"message":    "This action requires administrator privileges",
 				"request_id": requestID,
 			})
@@ -90,7 +91,7 @@ func SuperAdminOnlyMiddleware() gin.HandlerFunc {
 		userRole, exists := c.Get("user_role")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":      "Authentication required",
+				"error":      i18n.Message(c, "error.authentication_required"),
 				"message":    "Please log in to access super admin features",
 				"request_id": requestID,
 			})
@@ -101,7 +102,7 @@ func SuperAdminOnlyMiddleware() gin.HandlerFunc {
 		role, ok := userRole.(models.Role)
 		if !ok {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":      "Invalid authentication data",
+				"error":      i18n.Message(c, "error.invalid_authentication_data"),
 				"request_id": requestID,
 			})
 			c.Abort()
@@ -124,7 +125,7 @@ func SuperAdminOnlyMiddleware() gin.HandlerFunc {
 			}).Warn("Unauthorized super admin access attempt")
 
 			c.JSON(http.StatusForbidden, gin.H{
-				"error":      "Super admin access required",
+				"error":      i18n.Message(c, "error.super_admin_access_required"),
 				"message":    "This action requires super administrator privileges",
 				"request_id": requestID,
 			})
@@ -195,7 +196,7 @@ func ValidateAdminRequest() gin.HandlerFunc {
 		contentType := c.GetHeader("Content-Type")
 		if contentType != "application/json" && c.Request.Method != "DELETE" {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error":      "Invalid Content-Type",
+				"error":      i18n.Message(c, "error.invalid_content_type"),
 				"message":    "Content-Type must be application/json for this request",
 				"request_id": requestID,
 			})
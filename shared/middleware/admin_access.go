@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// BreakGlassHeader carries the break-glass token that lets admin requests through
+// network restrictions in an emergency, e.g. when the office VPN is down
+const BreakGlassHeader = "X-Break-Glass-Token"
+
+// minBreakGlassTokenLength rejects guessable break-glass tokens
+const minBreakGlassTokenLength = 32
+
+// AdminAccessConfig restricts where admin endpoints can be reached from
+type AdminAccessConfig struct {
+	AllowedNetworks  []*net.IPNet // Empty allows any address
+	TrustedProxies   []*net.IPNet // Proxies whose X-Forwarded-For is trusted
+	BlockedCountries []string     // ISO 3166 country codes
+	CountryHeader    string       // Header with the client country set by the edge proxy or CDN
+	BreakGlassToken  string       // Empty disables the override
+}
+
+// LoadAdminAccessConfig reads admin access restrictions from environment:
+// ADMIN_ALLOWED_CIDRS, ADMIN_TRUSTED_PROXIES, ADMIN_BLOCKED_COUNTRIES, ADMIN_COUNTRY_HEADER
+// and ADMIN_BREAK_GLASS_TOKEN. Invalid values are errors, so a typo never opens admin endpoints.
+func LoadAdminAccessConfig() (*AdminAccessConfig, error) {
+	allowed, err := parseNetworks(os.Getenv("ADMIN_ALLOWED_CIDRS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ADMIN_ALLOWED_CIDRS: %w", err)
+	}
+
+	trusted, err := parseNetworks(os.Getenv("ADMIN_TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ADMIN_TRUSTED_PROXIES: %w", err)
+	}
+
+	var countries []string
+	for _, country := range strings.Split(os.Getenv("ADMIN_BLOCKED_COUNTRIES"), ",") {
+		if country = strings.ToUpper(strings.TrimSpace(country)); country != "" {
+			countries = append(countries, country)
+		}
+	}
+
+	countryHeader := os.Getenv("ADMIN_COUNTRY_HEADER")
+	if countryHeader == "" {
+		countryHeader = "CF-IPCountry"
+	}
+
+	token := os.Getenv("ADMIN_BREAK_GLASS_TOKEN")
+	if token != "" && len(token) < minBreakGlassTokenLength {
+		return nil, fmt.Errorf("ADMIN_BREAK_GLASS_TOKEN must be at least %d characters", minBreakGlassTokenLength)
+	}
+
+	return &AdminAccessConfig{
+		AllowedNetworks:  allowed,
+		TrustedProxies:   trusted,
+		BlockedCountries: countries,
+		CountryHeader:    countryHeader,
+		BreakGlassToken:  token,
+	}, nil
+}
+
+// AdminAccessMiddleware rejects admin requests from addresses outside the allowed networks
+// or from blocked countries. A valid break-glass token overrides both checks.
+// Blocked and overridden requests are logged for audit.
+func AdminAccessMiddleware(config *AdminAccessConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientIP := config.clientIP(c)
+		country := strings.ToUpper(strings.TrimSpace(c.GetHeader(config.CountryHeader)))
+
+		reason := config.denyReason(clientIP, country)
+		if reason == "" {
+			c.Next()
+			return
+		}
+
+		fields := map[string]interface{}{
+			"request_id": requestid.Get(c),
+			"client_ip":  clientIP.String(),
+			"country":    country,
+			"reason":     reason,
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"user_agent": c.Request.UserAgent(),
+		}
+		if userID, exists := c.Get("user_id"); exists {
+			fields["user_id"] = userID
+		}
+
+		if config.isBreakGlass(c.GetHeader(BreakGlassHeader)) {
+			logger.WithFields(fields).Warn("Admin access restriction overridden with break-glass token")
+			c.Next()
+			return
+		}
+
+		logger.WithFields(fields).Warn("Blocked admin access attempt")
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":      i18n.Message(c, "error.admin_network_denied"),
+			"request_id": requestid.Get(c),
+		})
+	}
+}
+
+// denyReason returns why a request must be blocked, empty if it is allowed
+func (config *AdminAccessConfig) denyReason(ip net.IP, country string) string {
+	if len(config.AllowedNetworks) > 0 && !containsIP(config.AllowedNetworks, ip) {
+		return "address not allowed"
+	}
+	for _, blocked := range config.BlockedCountries {
+		if country == blocked {
+			return "country blocked"
+		}
+	}
+	return ""
+}
+
+// isBreakGlass checks the break-glass token in constant time
+func (config *AdminAccessConfig) isBreakGlass(token string) bool {
+	if config.BreakGlassToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(config.BreakGlassToken)) == 1
+}
+
+// clientIP returns the address of the client. X-Forwarded-For is only used when the
+// connection comes from a trusted proxy, walking it from the right past trusted proxies.
+func (config *AdminAccessConfig) clientIP(c *gin.Context) net.IP {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil || !containsIP(config.TrustedProxies, ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(c.GetHeader("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if hop == nil {
+			break
+		}
+		ip = hop
+		if !containsIP(config.TrustedProxies, hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// containsIP checks if any of the networks contains the address
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetworks parses a comma-separated list of CIDRs, single addresses are treated as /32 or /128
+func parseNetworks(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
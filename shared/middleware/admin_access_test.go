@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminAccessMiddleware(t *testing.T) {
+	allowed, _ := parseNetworks("10.8.0.0/16, 192.168.1.10")
+	trusted, _ := parseNetworks("172.20.0.0/16")
+	config := &AdminAccessConfig{
+		AllowedNetworks:  allowed,
+		TrustedProxies:   trusted,
+		BlockedCountries: []string{"XX"},
+		CountryHeader:    "CF-IPCountry",
+		BreakGlassToken:  strings.Repeat("k", minBreakGlassTokenLength),
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AdminAccessMiddleware(config))
+	router.GET("/admin/stats", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   int
+	}{
+		{"allowed network", "10.8.3.4:5000", nil, http.StatusOK},
+		{"allowed address", "192.168.1.10:5000", nil, http.StatusOK},
+		{"outside allowed networks", "203.0.113.7:5000", nil, http.StatusForbidden},
+		{"spoofed forwarded header from untrusted peer", "203.0.113.7:5000", map[string]string{"X-Forwarded-For": "10.8.3.4"}, http.StatusForbidden},
+		{"forwarded by trusted proxy", "172.20.0.5:5000", map[string]string{"X-Forwarded-For": "10.8.3.4"}, http.StatusOK},
+		{"spoofed hop before trusted proxy", "172.20.0.5:5000", map[string]string{"X-Forwarded-For": "10.8.3.4, 203.0.113.7"}, http.StatusForbidden},
+		{"blocked country", "10.8.3.4:5000", map[string]string{"CF-IPCountry": "xx"}, http.StatusForbidden},
+		{"break-glass token", "203.0.113.7:5000", map[string]string{BreakGlassHeader: strings.Repeat("k", minBreakGlassTokenLength)}, http.StatusOK},
+		{"wrong break-glass token", "203.0.113.7:5000", map[string]string{BreakGlassHeader: "guess"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
+
+func TestLoadAdminAccessConfig(t *testing.T) {
+	t.Setenv("ADMIN_ALLOWED_CIDRS", "")
+	t.Setenv("ADMIN_BREAK_GLASS_TOKEN", "")
+	config, err := LoadAdminAccessConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.AllowedNetworks) != 0 || config.CountryHeader != "CF-IPCountry" {
+		t.Errorf("unexpected default config: %+v", config)
+	}
+
+	t.Setenv("ADMIN_ALLOWED_CIDRS", "10.0.0.0/8,10.0.0.0/33")
+	if _, err := LoadAdminAccessConfig(); err == nil {
+		t.Error("expected error for invalid network")
+	}
+
+	t.Setenv("ADMIN_ALLOWED_CIDRS", "10.0.0.0/8")
+	t.Setenv("ADMIN_BREAK_GLASS_TOKEN", "short")
+	if _, err := LoadAdminAccessConfig(); err == nil {
+		t.Error("expected error for short break-glass token")
+	}
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// Request body size limits:
+//   - JSON API requests are limited to BodyLimitConfig.JSONLimit. Bodies without Content-Length
+//     are read up to the limit before the handler runs, so handlers never see a truncated body
+//   - requests to upload paths are limited to BodyLimitConfig.UploadLimit and are never buffered:
+//     the body is wrapped in http.MaxBytesReader and must be consumed as a stream, see StreamMultipart
+//   - oversized requests are rejected with 413 and the limit in "max_bytes"
+
+const (
+	// DefaultJSONBodyLimit is the default body limit of API requests
+	DefaultJSONBodyLimit int64 = 1 << 20 // 1 MB
+
+	// DefaultUploadBodyLimit is the default body limit of upload requests
+	DefaultUploadBodyLimit int64 = 50 << 20 // 50 MB
+
+	// bodyLimitKey is the gin context key of the body limit of the request
+	bodyLimitKey = "body_limit"
+)
+
+// ErrBodyTooLarge is returned when a request body exceeds its limit
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// BodyLimitConfig holds request body size limits
+type BodyLimitConfig struct {
+	JSONLimit   int64
+	UploadLimit int64
+	UploadPaths []string // Path prefixes of upload routes
+}
+
+// DefaultBodyLimitConfig returns body limits from MAX_BODY_SIZE and MAX_UPLOAD_SIZE environment
+// variables (bytes or a KB/MB/GB suffixed size) or defaults
+func DefaultBodyLimitConfig(uploadPaths ...string) *BodyLimitConfig {
+	return &BodyLimitConfig{
+		JSONLimit:   sizeFromEnv("MAX_BODY_SIZE", DefaultJSONBodyLimit),
+		UploadLimit: sizeFromEnv("MAX_UPLOAD_SIZE", DefaultUploadBodyLimit),
+		UploadPaths: uploadPaths,
+	}
+}
+
+// BodyLimitMiddleware enforces request body size limits
+func BodyLimitMiddleware(config *BodyLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		upload := config.isUploadPath(c.Request.URL.Path)
+		limit := config.JSONLimit
+		if upload {
+			limit = config.UploadLimit
+		}
+		c.Set(bodyLimitKey, limit)
+
+		if c.Request.ContentLength > limit {
+			AbortBodyTooLarge(c)
+			return
+		}
+
+		if upload {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength < 0 {
+			// Unknown length: read up to the limit so an oversized body gets a proper 413
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+			c.Request.Body.Close()
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error":      i18n.Message(c, "error.invalid_request_body"),
+					"request_id": requestid.Get(c),
+				})
+				return
+			}
+			if int64(len(body)) > limit {
+				AbortBodyTooLarge(c)
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			c.Request.ContentLength = int64(len(body))
+			c.Next()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// AbortBodyTooLarge rejects the request with 413 and the body limit of the request
+func AbortBodyTooLarge(c *gin.Context) {
+	requestID := requestid.Get(c)
+	limit := c.GetInt64(bodyLimitKey)
+
+	logger.WithFields(map[string]interface{}{
+		"request_id":     requestID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+		"content_length": c.Request.ContentLength,
+		"max_bytes":      limit,
+	}).Warn("Request body too large")
+
+	c.Header("Connection", "close")
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":      i18n.Message(c, "error.request_too_large"),
+		"max_bytes":  limit,
+		"request_id": requestID,
+	})
+}
+
+// IsBodyTooLarge checks if an error was caused by a request body over its limit
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.Is(err, ErrBodyTooLarge) || errors.As(err, &maxBytesErr)
+}
+
+// StreamMultipart reads a multipart request part by part without buffering files in memory
+// or on disk. handle must consume the part before returning. Exceeding the body limit
+// returns an error matching IsBodyTooLarge.
+func StreamMultipart(c *gin.Context, handle func(part *multipart.Part) error) error {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return fmt.Errorf("invalid multipart request: %w", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if IsBodyTooLarge(err) {
+				return ErrBodyTooLarge
+			}
+			return fmt.Errorf("failed to read multipart request: %w", err)
+		}
+
+		err = handle(part)
+		part.Close()
+		if err != nil {
+			if IsBodyTooLarge(err) {
+				return ErrBodyTooLarge
+			}
+			return err
+		}
+	}
+}
+
+// isUploadPath checks if a path belongs to an upload route
+func (config *BodyLimitConfig) isUploadPath(path string) bool {
+	for _, prefix := range config.UploadPaths {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sizeFromEnv parses a size such as "1048576", "512KB" or "10MB" from environment or returns the default
+func sizeFromEnv(key string, defaultValue int64) int64 {
+	value := strings.ToUpper(strings.TrimSpace(os.Getenv(key)))
+	if value == "" {
+		return defaultValue
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		logger.WithFields(map[string]interface{}{
+			"variable": key,
+			"value":    os.Getenv(key),
+		}).Warn("Invalid body size limit, using default")
+		return defaultValue
+	}
+	return size * multiplier
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBodyLimitRouter(config *BodyLimitConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimitMiddleware(config))
+
+	router.POST("/api/v1/items", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	})
+
+	router.POST("/api/v1/files/upload", func(c *gin.Context) {
+		var size int64
+		err := StreamMultipart(c, func(part *multipart.Part) error {
+			n, err := io.Copy(io.Discard, part)
+			size += n
+			return err
+		})
+		if IsBodyTooLarge(err) {
+			AbortBodyTooLarge(c)
+			return
+		}
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, "%d", size)
+	})
+
+	return router
+}
+
+func TestBodyLimitMiddlewareJSON(t *testing.T) {
+	router := newBodyLimitRouter(&BodyLimitConfig{JSONLimit: 10, UploadLimit: 1000, UploadPaths: []string{"/api/v1/files"}})
+
+	tests := []struct {
+		name     string
+		body     string
+		chunked  bool
+		expected int
+	}{
+		{"within limit", "0123456789", false, http.StatusOK},
+		{"over limit", "0123456789a", false, http.StatusRequestEntityTooLarge},
+		{"chunked within limit", "0123", true, http.StatusOK},
+		{"chunked over limit", strings.Repeat("a", 100), true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/items", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, w.Code)
+			}
+			if w.Code == http.StatusRequestEntityTooLarge && !strings.Contains(w.Body.String(), `"max_bytes":10`) {
+				t.Errorf("expected max_bytes in response, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBodyLimitMiddlewareUpload(t *testing.T) {
+	router := newBodyLimitRouter(&BodyLimitConfig{JSONLimit: 10, UploadLimit: 1000, UploadPaths: []string{"/api/v1/files"}})
+
+	upload := func(size int, chunked bool) int {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "data.bin")
+		part.Write(bytes.Repeat([]byte("x"), size))
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/files/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		if chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := upload(500, false); code != http.StatusOK {
+		t.Errorf("expected upload within limit to succeed, got %d", code)
+	}
+	if code := upload(2000, false); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for declared oversized upload, got %d", code)
+	}
+	if code := upload(2000, true); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for streamed oversized upload, got %d", code)
+	}
+}
+
+func TestSizeFromEnv(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+	}{
+		{"", 42},
+		{"2048", 2048},
+		{"512KB", 512 << 10},
+		{"10 mb", 10 << 20},
+		{"1GB", 1 << 30},
+		{"lots", 42},
+		{"-1", 42},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("TEST_BODY_SIZE", tt.value)
+		if size := sizeFromEnv("TEST_BODY_SIZE", 42); size != tt.expected {
+			t.Errorf("sizeFromEnv(%q) = %d, want %d", tt.value, size, tt.expected)
+		}
+	}
+}
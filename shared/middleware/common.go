@@ -23,9 +23,11 @@ func CORSMiddleware() gin.HandlerFunc {
 		"X-CSRF-Token",
 		"Authorization",
 		"X-Request-ID",
+		"X-Correlation-ID",
 		"X-Requested-With",
+		"If-Match",
 	}
-	config.ExposeHeaders = []string{"X-Request-ID"}
+	config.ExposeHeaders = []string{"X-Request-ID", "X-Correlation-ID", "ETag"}
 	config.AllowCredentials = true
 	config.MaxAge = 12 * time.Hour
 
@@ -117,6 +119,11 @@ func LoggerMiddlewareWithRequestID() gin.HandlerFunc {
 			"body_size":   c.Writer.Size(),
 		}
 
+		// Add correlation of the user action
+		for key, value := range logger.CorrelationFromContext(c.Request.Context()).Fields() {
+			logFields[key] = value
+		}
+
 		// Add user ID if available (for authenticated requests)
 		if userID, exists := c.Get("user_id"); exists {
 			logFields["user_id"] = userID
@@ -143,6 +150,9 @@ func SetupCommonMiddleware(r *gin.Engine) {
 	// Request ID for tracking
 	r.Use(RequestIDMiddleware())
 
+	// Correlation of requests caused by one user action across services
+	r.Use(CorrelationMiddleware())
+
 	// CORS for cross-origin requests
 	r.Use(CORSMiddleware())
 
@@ -0,0 +1,321 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// Request concurrency limits:
+//   - every limited route group has its own number of requests in flight; a request over the
+//     limit waits up to the queue timeout for a free slot
+//   - requests that don't get a slot in time are shed with 503 and a Retry-After hint,
+//     so a burst is rejected quickly instead of piling up until the service falls over
+//   - LOAD_SHEDDING_ENABLED=false turns all limits off; CONCURRENCY_LIMIT sets the limit of every
+//     group and CONCURRENCY_LIMIT_<GROUP> of one group, 0 disables the limit
+//   - CONCURRENCY_QUEUE_TIMEOUT (a duration such as "250ms") and CONCURRENCY_RETRY_AFTER (seconds)
+//     tune waiting and the retry hint
+
+const (
+	// DefaultConcurrencyLimit is the default number of requests in flight of a route group
+	DefaultConcurrencyLimit = 256
+
+	// DefaultConcurrencyQueueTimeout is how long a request waits for a slot by default
+	DefaultConcurrencyQueueTimeout = 100 * time.Millisecond
+
+	// DefaultConcurrencyRetryAfter is the default retry hint of shed requests in seconds
+	DefaultConcurrencyRetryAfter = 1
+)
+
+// ConcurrencyConfig holds the concurrency limit of a route group
+type ConcurrencyConfig struct {
+	Limit        int // Requests in flight, 0 disables the limit
+	QueueTimeout time.Duration
+	RetryAfter   int // Seconds
+}
+
+// ConcurrencyConfigFromEnv returns the concurrency limit of a route group from environment
+// variables, defaultLimit is used when none is set
+func ConcurrencyConfigFromEnv(group string, defaultLimit int) *ConcurrencyConfig {
+	config := &ConcurrencyConfig{
+		Limit:        defaultLimit,
+		QueueTimeout: DefaultConcurrencyQueueTimeout,
+		RetryAfter:   DefaultConcurrencyRetryAfter,
+	}
+
+	if enabled, err := strconv.ParseBool(os.Getenv("LOAD_SHEDDING_ENABLED")); err == nil && !enabled {
+		config.Limit = 0
+		return config
+	}
+
+	config.Limit = intFromEnv("CONCURRENCY_LIMIT", config.Limit)
+	config.Limit = intFromEnv("CONCURRENCY_LIMIT_"+envName(group), config.Limit)
+	config.RetryAfter = intFromEnv("CONCURRENCY_RETRY_AFTER", config.RetryAfter)
+	if value := strings.TrimSpace(os.Getenv("CONCURRENCY_QUEUE_TIMEOUT")); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			logger.WithFields(map[string]interface{}{
+				"variable": "CONCURRENCY_QUEUE_TIMEOUT",
+				"value":    value,
+			}).Warn("Invalid concurrency queue timeout, using default")
+		} else {
+			config.QueueTimeout = timeout
+		}
+	}
+	return config
+}
+
+// ConcurrencyStats represents the current load of a route group
+type ConcurrencyStats struct {
+	Group      string  `json:"group"`
+	Limit      int     `json:"limit"` // 0 when the group is not limited
+	InFlight   int64   `json:"in_flight"`
+	Waiting    int64   `json:"waiting"`
+	Saturation float64 `json:"saturation"` // In-flight requests against the limit, 0 to 1
+	Admitted   uint64  `json:"admitted"`
+	Shed       uint64  `json:"shed"`
+}
+
+// ConcurrencyLimiter bounds the number of requests a route group handles at once
+type ConcurrencyLimiter struct {
+	group  string
+	config ConcurrencyConfig
+	slots  chan struct{} // nil when the group is not limited
+
+	inFlight atomic.Int64
+	waiting  atomic.Int64
+	admitted atomic.Uint64
+	shed     atomic.Uint64
+}
+
+// NewConcurrencyLimiter creates a limiter of a route group
+func NewConcurrencyLimiter(group string, config *ConcurrencyConfig) *ConcurrencyLimiter {
+	limiter := &ConcurrencyLimiter{group: group, config: *config}
+	if config.Limit > 0 {
+		limiter.slots = make(chan struct{}, config.Limit)
+	}
+	if limiter.config.RetryAfter <= 0 {
+		limiter.config.RetryAfter = DefaultConcurrencyRetryAfter
+	}
+	return limiter
+}
+
+// Middleware admits requests while the group has free slots and sheds the rest
+func (l *ConcurrencyLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.slots == nil {
+			l.track(c)
+			return
+		}
+
+		if !l.acquire(c) {
+			l.shed.Add(1)
+			l.abortOverloaded(c)
+			return
+		}
+		defer func() { <-l.slots }()
+
+		l.track(c)
+	}
+}
+
+// Stats returns the current load of the group
+func (l *ConcurrencyLimiter) Stats() ConcurrencyStats {
+	stats := ConcurrencyStats{
+		Group:    l.group,
+		Limit:    l.config.Limit,
+		InFlight: l.inFlight.Load(),
+		Waiting:  l.waiting.Load(),
+		Admitted: l.admitted.Load(),
+		Shed:     l.shed.Load(),
+	}
+	if stats.Limit > 0 {
+		stats.Saturation = float64(stats.InFlight) / float64(stats.Limit)
+	}
+	return stats
+}
+
+// acquire takes a slot, waiting up to the queue timeout or until the client goes away
+func (l *ConcurrencyLimiter) acquire(c *gin.Context) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if l.config.QueueTimeout <= 0 {
+		return false
+	}
+
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	timer := time.NewTimer(l.config.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
+
+// track runs the rest of the chain counting the request as in flight
+func (l *ConcurrencyLimiter) track(c *gin.Context) {
+	l.admitted.Add(1)
+	l.inFlight.Add(1)
+	defer l.inFlight.Add(-1)
+
+	c.Next()
+}
+
+// abortOverloaded rejects the request with 503 and a retry hint
+func (l *ConcurrencyLimiter) abortOverloaded(c *gin.Context) {
+	requestID := requestid.Get(c)
+
+	logger.WithFields(map[string]interface{}{
+		"request_id": requestID,
+		"group":      l.group,
+		"method":     c.Request.Method,
+		"path":       c.Request.URL.Path,
+		"limit":      l.config.Limit,
+	}).Warn("Request shed, concurrency limit reached")
+
+	c.Header("Retry-After", strconv.Itoa(l.config.RetryAfter))
+	c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+		"error":       i18n.Message(c, "error.service_overloaded"),
+		"retry_after": l.config.RetryAfter,
+		"request_id":  requestID,
+	})
+}
+
+// ConcurrencyLimits holds the limiters of the route groups of a service
+type ConcurrencyLimits struct {
+	service string
+
+	mu       sync.Mutex
+	limiters map[string]*ConcurrencyLimiter
+}
+
+// NewConcurrencyLimits creates the concurrency limits of a service
+func NewConcurrencyLimits(service string) *ConcurrencyLimits {
+	return &ConcurrencyLimits{
+		service:  service,
+		limiters: make(map[string]*ConcurrencyLimiter),
+	}
+}
+
+// Group returns the middleware limiting a route group, configured from environment variables.
+// Route groups of the same name share one limit.
+func (l *ConcurrencyLimits) Group(group string) gin.HandlerFunc {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, exists := l.limiters[group]
+	if !exists {
+		limiter = NewConcurrencyLimiter(group, ConcurrencyConfigFromEnv(group, DefaultConcurrencyLimit))
+		l.limiters[group] = limiter
+	}
+	return limiter.Middleware()
+}
+
+// Stats returns the current load of all route groups, ordered by group
+func (l *ConcurrencyLimits) Stats() []ConcurrencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make([]ConcurrencyStats, 0, len(l.limiters))
+	for _, limiter := range l.limiters {
+		stats = append(stats, limiter.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Group < stats[j].Group
+	})
+	return stats
+}
+
+// WriteMetrics writes the load of all route groups in Prometheus text format
+func (l *ConcurrencyLimits) WriteMetrics(out io.Writer) error {
+	stats := l.Stats()
+
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value func(ConcurrencyStats) float64
+	}{
+		{"http_concurrency_limit", "gauge", "Requests in flight allowed, 0 when not limited",
+			func(s ConcurrencyStats) float64 { return float64(s.Limit) }},
+		{"http_concurrency_in_flight", "gauge", "Requests in flight",
+			func(s ConcurrencyStats) float64 { return float64(s.InFlight) }},
+		{"http_concurrency_waiting", "gauge", "Requests waiting for a slot",
+			func(s ConcurrencyStats) float64 { return float64(s.Waiting) }},
+		{"http_concurrency_saturation", "gauge", "Requests in flight against the limit",
+			func(s ConcurrencyStats) float64 { return s.Saturation }},
+		{"http_requests_admitted_total", "counter", "Requests admitted",
+			func(s ConcurrencyStats) float64 { return float64(s.Admitted) }},
+		{"http_requests_shed_total", "counter", "Requests rejected over the concurrency limit",
+			func(s ConcurrencyStats) float64 { return float64(s.Shed) }},
+	}
+
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind); err != nil {
+			return err
+		}
+		for _, group := range stats {
+			if _, err := fmt.Fprintf(out, "%s{service=%q,group=%q} %g\n",
+				metric.name, l.service, group.Group, metric.value(group)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MetricsHandler serves the load of all route groups in Prometheus text format
+func (l *ConcurrencyLimits) MetricsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4")
+		if err := l.WriteMetrics(c.Writer); err != nil {
+			logger.WithField("error", err.Error()).Error("Failed to write concurrency metrics")
+		}
+	}
+}
+
+// envName converts a route group name to the suffix of its environment variable
+func envName(group string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", "/", "_", " ", "_").Replace(group))
+}
+
+// intFromEnv parses a non-negative integer from environment or returns the default
+func intFromEnv(key string, defaultValue int) int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		logger.WithFields(map[string]interface{}{
+			"variable": key,
+			"value":    value,
+		}).Warn("Invalid concurrency setting, using default")
+		return defaultValue
+	}
+	return parsed
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newConcurrencyRouter(limiter *ConcurrencyLimiter, release <-chan struct{}, started chan<- struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(limiter.Middleware())
+
+	router.GET("/api/v1/items", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestConcurrencyLimiterShedsOverLimit(t *testing.T) {
+	limiter := NewConcurrencyLimiter("api", &ConcurrencyConfig{Limit: 1, QueueTimeout: 10 * time.Millisecond, RetryAfter: 3})
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	router := newConcurrencyRouter(limiter, release, started)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	first := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+	}()
+	<-started
+
+	if stats := limiter.Stats(); stats.InFlight != 1 || stats.Saturation != 1 {
+		t.Errorf("expected the group to be saturated, got %+v", stats)
+	}
+
+	shed := httptest.NewRecorder()
+	router.ServeHTTP(shed, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+	if shed.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 over the limit, got %d", shed.Code)
+	}
+	if shed.Header().Get("Retry-After") != "3" {
+		t.Errorf("expected Retry-After 3, got %q", shed.Header().Get("Retry-After"))
+	}
+
+	close(release)
+	wg.Wait()
+	if first.Code != http.StatusOK {
+		t.Errorf("expected admitted request to succeed, got %d", first.Code)
+	}
+
+	stats := limiter.Stats()
+	if stats.InFlight != 0 || stats.Admitted != 1 || stats.Shed != 1 {
+		t.Errorf("unexpected stats after requests finished: %+v", stats)
+	}
+}
+
+func TestConcurrencyLimiterQueuesUntilSlotFrees(t *testing.T) {
+	limiter := NewConcurrencyLimiter("api", &ConcurrencyConfig{Limit: 1, QueueTimeout: time.Second})
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	router := newConcurrencyRouter(limiter, release, started)
+
+	var wg sync.WaitGroup
+	codes := make([]int, 2)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+			codes[i] = recorder.Code
+		}(i)
+	}
+
+	<-started
+	deadline := time.Now().Add(time.Second)
+	for limiter.Stats().Waiting != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	release <- struct{}{}
+	<-started
+	release <- struct{}{}
+	wg.Wait()
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK {
+		t.Errorf("expected queued request to be admitted, got %v", codes)
+	}
+}
+
+func TestConcurrencyConfigFromEnv(t *testing.T) {
+	t.Setenv("CONCURRENCY_LIMIT", "50")
+	t.Setenv("CONCURRENCY_LIMIT_INTERNAL_API", "10")
+	t.Setenv("CONCURRENCY_QUEUE_TIMEOUT", "250ms")
+
+	if config := ConcurrencyConfigFromEnv("api", DefaultConcurrencyLimit); config.Limit != 50 || config.QueueTimeout != 250*time.Millisecond {
+		t.Errorf("expected the service-wide limit, got %+v", config)
+	}
+	if config := ConcurrencyConfigFromEnv("internal-api", DefaultConcurrencyLimit); config.Limit != 10 {
+		t.Errorf("expected the group limit, got %+v", config)
+	}
+
+	t.Setenv("LOAD_SHEDDING_ENABLED", "false")
+	if config := ConcurrencyConfigFromEnv("internal-api", DefaultConcurrencyLimit); config.Limit != 0 {
+		t.Errorf("expected limits to be off, got %+v", config)
+	}
+}
+
+func TestConcurrencyLimitsMetrics(t *testing.T) {
+	limits := NewConcurrencyLimits("poll-service")
+	limits.Group("api")
+	limits.Group("internal")
+
+	var out bytes.Buffer
+	if err := limits.WriteMetrics(&out); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+	if !strings.Contains(out.String(), `http_concurrency_limit{service="poll-service",group="internal"} 256`) {
+		t.Errorf("expected limit of the internal group, got:\n%s", out.String())
+	}
+	if stats := limits.Stats(); len(stats) != 2 || stats[0].Group != "api" {
+		t.Errorf("expected stats of both groups, got %+v", stats)
+	}
+}
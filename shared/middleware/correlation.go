@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// CorrelationMiddleware puts the correlation of the request into its context. A request without
+// a correlation ID header starts a new user action correlated by its own request ID. Must run
+// after the request ID middleware.
+func CorrelationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correlation := logger.Correlation{
+			ID:        c.GetHeader(logger.CorrelationIDHeader),
+			ParentID:  c.GetHeader(logger.ParentIDHeader),
+			RequestID: requestid.Get(c),
+		}
+		if correlation.ID == "" {
+			correlation.ID = correlation.RequestID
+		}
+
+		c.Request = c.Request.WithContext(logger.ContextWithCorrelation(c.Request.Context(), correlation))
+		c.Set("correlation_id", correlation.ID)
+		if correlation.ParentID != "" {
+			c.Set("parent_id", correlation.ParentID)
+		}
+		c.Header(logger.CorrelationIDHeader, correlation.ID)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tachyon-messenger/shared/logger"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+func newCorrelationRouter(downstream string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestid.New())
+	router.Use(CorrelationMiddleware())
+
+	router.GET("/api/v1/items", func(c *gin.Context) {
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, downstream, nil)
+		resp, err := (&http.Client{Transport: logger.NewTransport(nil)}).Do(req)
+		if err != nil {
+			c.Status(http.StatusBadGateway)
+			return
+		}
+		resp.Body.Close()
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestCorrelationMiddleware(t *testing.T) {
+	var forwarded http.Header
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Clone()
+	}))
+	defer downstream.Close()
+
+	router := newCorrelationRouter(downstream.URL)
+
+	t.Run("starts a correlation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(logger.CorrelationIDHeader); got != "req-1" {
+			t.Fatalf("correlation ID = %q, want req-1", got)
+		}
+		if got := forwarded.Get(logger.CorrelationIDHeader); got != "req-1" {
+			t.Errorf("forwarded correlation ID = %q, want req-1", got)
+		}
+		if got := forwarded.Get(logger.ParentIDHeader); got != "req-1" {
+			t.Errorf("forwarded parent ID = %q, want req-1", got)
+		}
+	})
+
+	t.Run("continues a correlation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/items", nil)
+		req.Header.Set("X-Request-ID", "req-2")
+		req.Header.Set(logger.CorrelationIDHeader, "action-1")
+		req.Header.Set(logger.ParentIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get(logger.CorrelationIDHeader); got != "action-1" {
+			t.Fatalf("correlation ID = %q, want action-1", got)
+		}
+		if got := forwarded.Get(logger.CorrelationIDHeader); got != "action-1" {
+			t.Errorf("forwarded correlation ID = %q, want action-1", got)
+		}
+		if got := forwarded.Get(logger.ParentIDHeader); got != "req-2" {
+			t.Errorf("forwarded parent ID = %q, want req-2", got)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"tachyon-messenger/shared/i18n"
 	"tachyon-messenger/shared/models"
 
 	"github.com/gin-gonic/gin"
@@ -30,16 +31,17 @@ func DefaultJWTConfig(secret string) *JWTConfig {
 	}
 }
 
-// GenerateTokens generates access and refresh token pair
-func GenerateTokens(userID uint, email string, role models.Role, config *JWTConfig) (*models.TokenPair, error) {
+// GenerateTokens generates access and refresh token pair.
+// locale is the user's profile locale, services read it from claims to localize responses.
+func GenerateTokens(userID uint, email string, role models.Role, locale string, config *JWTConfig) (*models.TokenPair, error) {
 	// Generate access token
-	accessToken, err := generateToken(userID, email, role, config.AccessTokenDuration, config)
+	accessToken, err := generateToken(userID, email, role, locale, config.AccessTokenDuration, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generate refresh token
-	refreshToken, err := generateToken(userID, email, role, config.RefreshTokenDuration, config)
+	refreshToken, err := generateToken(userID, email, role, locale, config.RefreshTokenDuration, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
@@ -50,13 +52,24 @@ func GenerateTokens(userID uint, email string, role models.Role, config *JWTConf
 	}, nil
 }
 
+// GenerateAccessToken generates a single access token valid for duration. The gateway uses it
+// to act on behalf of the user an API key belongs to.
+func GenerateAccessToken(userID uint, email string, role models.Role, locale string, duration time.Duration, config *JWTConfig) (string, error) {
+	token, err := generateToken(userID, email, role, locale, duration, config)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+	return token, nil
+}
+
 // generateToken generates a JWT token with specified duration
-func generateToken(userID uint, email string, role models.Role, duration time.Duration, config *JWTConfig) (string, error) {
+func generateToken(userID uint, email string, role models.Role, locale string, duration time.Duration, config *JWTConfig) (string, error) {
 	now := time.Now()
 	claims := &models.Claims{
 		UserID: userID,
 		Email:  email,
 		Role:   role,
+		Locale: locale,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -106,7 +119,7 @@ func JWTMiddleware(config *JWTConfig) gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authorization header is required",
+				"error": i18n.Message(c, "error.authorization_required"),
 			})
 			c.Abort()
 			return
@@ -116,7 +129,7 @@ func JWTMiddleware(config *JWTConfig) gin.HandlerFunc {
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization header format",
+				"error": i18n.Message(c, "error.invalid_authorization_format"),
 			})
 			c.Abort()
 			return
@@ -128,7 +141,7 @@ func JWTMiddleware(config *JWTConfig) gin.HandlerFunc {
 		claims, err := ValidateToken(tokenString, config)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid or expired token",
+				"error": i18n.Message(c, "error.invalid_token"),
 			})
 			c.Abort()
 			return
@@ -139,6 +152,9 @@ func JWTMiddleware(config *JWTConfig) gin.HandlerFunc {
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", claims.Role)
 		c.Set("claims", claims)
+		if claims.Locale != "" {
+			c.Set(i18n.ContextKey, i18n.Parse(claims.Locale))
+		}
 
 		c.Next()
 	}
@@ -150,7 +166,7 @@ func RequireRole(allowedRoles ...models.Role) gin.HandlerFunc {
 		userRole, exists := c.Get("user_role")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User role not found in context",
+				"error": i18n.Message(c, "error.user_role_not_found"),
 			})
 			c.Abort()
 			return
@@ -159,7 +175,7 @@ func RequireRole(allowedRoles ...models.Role) gin.HandlerFunc {
 		role, ok := userRole.(models.Role)
 		if !ok {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid role type in context",
+				"error": i18n.Message(c, "error.invalid_role_type"),
 			})
 			c.Abort()
 			return
@@ -174,7 +190,7 @@ func RequireRole(allowedRoles ...models.Role) gin.HandlerFunc {
 		}
 
 		c.JSON(http.StatusForbidden, gin.H{
-			"error": "Insufficient permissions",
+			"error": i18n.Message(c, "error.insufficient_permissions"),
 		})
 		c.Abort()
 	}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+func TestGenerateAccessToken(t *testing.T) {
+	config := DefaultJWTConfig("test-secret")
+
+	token, err := GenerateAccessToken(42, "partner@example.com", models.RoleEmployee, "en", time.Minute, config)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	claims, err := ValidateToken(token, config)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if claims.UserID != 42 || claims.Role != models.RoleEmployee || claims.Locale != "en" {
+		t.Errorf("claims = %+v, want user 42 with role employee and locale en", claims)
+	}
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl > time.Minute || ttl < 50*time.Second {
+		t.Errorf("token expires in %v, want about a minute", ttl)
+	}
+
+	if _, err := ValidateToken(token, DefaultJWTConfig("other-secret")); err == nil {
+		t.Error("ValidateToken() accepted a token signed with another secret")
+	}
+}
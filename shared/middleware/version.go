@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Optimistic locking of versioned resources (tasks, events, polls):
+//   - responses carry the resource version in the ETag header, e.g. ETag: "3"
+//   - updates pass the version they are based on in the If-Match header or in the
+//     "version" field of the request body, the body field wins if both are set
+//   - a stale version is rejected with 409 Conflict and the current resource state
+//   - updates without a version are applied unconditionally
+
+// SetVersionETag sets the ETag response header to the resource version
+func SetVersionETag(c *gin.Context, version uint) {
+	c.Header("ETag", strconv.Quote(strconv.FormatUint(uint64(version), 10)))
+}
+
+// GetIfMatchVersion parses the resource version from the If-Match header.
+// It returns nil if the header is absent or matches any version.
+func GetIfMatchVersion(c *gin.Context) (*uint, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || version == 0 {
+		return nil, fmt.Errorf("invalid If-Match header: %q", c.GetHeader("If-Match"))
+	}
+
+	v := uint(version)
+	return &v, nil
+}
+
+// GetExpectedVersion returns the version an update is based on, taken from the request
+// body or, if it is not set there, from the If-Match header
+func GetExpectedVersion(c *gin.Context, bodyVersion *uint) (*uint, error) {
+	if bodyVersion != nil {
+		return bodyVersion, nil
+	}
+	return GetIfMatchVersion(c)
+}
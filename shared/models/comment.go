@@ -0,0 +1,112 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Comment limits shared by poll and task comments
+const (
+	MaxCommentDepth   = 3  // Максимальная глубина ветки ответов (0 - корневой комментарий)
+	MaxReactionLength = 10 // Максимальная длина эмодзи реакции в байтах
+)
+
+// CommentMeta contains threading and edit tracking fields common for all comment models
+type CommentMeta struct {
+	Depth    int        `gorm:"not null;default:0" json:"depth"`         // Глубина в ветке ответов
+	IsEdited bool       `gorm:"not null;default:false" json:"is_edited"` // Был ли комментарий отредактирован
+	EditedAt *time.Time `json:"edited_at,omitempty"`                     // Время последнего редактирования
+}
+
+// MarkEdited sets edit markers on the comment
+func (m *CommentMeta) MarkEdited() {
+	now := time.Now()
+	m.IsEdited = true
+	m.EditedAt = &now
+}
+
+// CommentReaction contains fields common for emoji reactions on comments
+type CommentReaction struct {
+	BaseModel
+	CommentID uint   `gorm:"not null;index" json:"comment_id" validate:"required"`
+	UserID    uint   `gorm:"not null;index" json:"user_id" validate:"required"`
+	Emoji     string `gorm:"not null;size:10" json:"emoji" validate:"required,max=10"`
+}
+
+// ReactionSummary represents aggregated reactions of one emoji on a comment
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+// CommentReactionRequest represents request for adding a reaction to a comment
+type CommentReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required,max=10" validate:"required,max=10"`
+}
+
+// UpdateCommentRequest represents request for editing a comment
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=1000" validate:"required,notblank,max=1000"`
+}
+
+// ValidateReactionEmoji validates emoji used as a comment reaction
+func ValidateReactionEmoji(emoji string) error {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" {
+		return fmt.Errorf("emoji is required")
+	}
+	if len(emoji) > MaxReactionLength {
+		return fmt.Errorf("emoji is too long (max %d bytes)", MaxReactionLength)
+	}
+	if !utf8.ValidString(emoji) {
+		return fmt.Errorf("emoji must be a valid UTF-8 string")
+	}
+	// Reactions are emoji, not plain text
+	for _, r := range emoji {
+		if r >= utf8.RuneSelf {
+			return nil
+		}
+	}
+	return fmt.Errorf("reaction must be an emoji")
+}
+
+// ValidateReplyDepth checks that a reply to a comment with parentDepth stays within MaxCommentDepth
+func ValidateReplyDepth(parentDepth int) error {
+	if parentDepth+1 > MaxCommentDepth {
+		return fmt.Errorf("maximum reply depth of %d exceeded", MaxCommentDepth)
+	}
+	return nil
+}
+
+// AggregateReactions groups reactions by emoji, marking the ones left by currentUserID
+func AggregateReactions(reactions []CommentReaction, currentUserID uint) []ReactionSummary {
+	if len(reactions) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int)
+	summaries := make([]ReactionSummary, 0)
+	for _, reaction := range reactions {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(summaries)
+			index[reaction.Emoji] = i
+			summaries = append(summaries, ReactionSummary{Emoji: reaction.Emoji})
+		}
+		summaries[i].Count++
+		if reaction.UserID == currentUserID {
+			summaries[i].ReactedByMe = true
+		}
+	}
+
+	// Most popular reactions first, stable by first appearance
+	sort.SliceStable(summaries, func(a, b int) bool {
+		return summaries[a].Count > summaries[b].Count
+	})
+
+	return summaries
+}
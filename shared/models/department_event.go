@@ -0,0 +1,32 @@
+package models
+
+// DepartmentEventType is the kind of a department change published by the user service
+type DepartmentEventType string
+
+const (
+	// DepartmentEventCreated is published when a department is created
+	DepartmentEventCreated DepartmentEventType = "department.created"
+	// DepartmentEventUpdated is published when a department is renamed or its settings change
+	DepartmentEventUpdated DepartmentEventType = "department.updated"
+	// DepartmentEventDeleted is published when a department is deleted
+	DepartmentEventDeleted DepartmentEventType = "department.deleted"
+	// DepartmentEventUserMoved is published when a user joins, leaves or changes department
+	DepartmentEventUserMoved DepartmentEventType = "department.user_moved"
+	// DepartmentEventMerged is published when a department is merged into another one and removed
+	DepartmentEventMerged DepartmentEventType = "department.merged"
+)
+
+// DepartmentEvent tells services keeping data per department, such as department chats, about
+// a department change. For user moves DepartmentID is the new department of the user, nil if
+// the user left it, and the department fields describe the new department. For merges DepartmentID
+// is the department that remains, PreviousDepartmentID and PreviousDepartmentName describe the
+// merged department, which is removed after its users moved to the remaining one.
+type DepartmentEvent struct {
+	Type                   DepartmentEventType `json:"type" binding:"required,oneof=department.created department.updated department.deleted department.user_moved department.merged"`
+	DepartmentID           *uint               `json:"department_id,omitempty" binding:"omitempty,min=1"`
+	DepartmentName         string              `json:"department_name,omitempty" binding:"omitempty,max=100"`
+	ChatDisabled           bool                `json:"chat_disabled"` // Отказ от автоматического чата отдела
+	UserID                 uint                `json:"user_id,omitempty"`
+	PreviousDepartmentID   *uint               `json:"previous_department_id,omitempty" binding:"omitempty,min=1"`
+	PreviousDepartmentName string              `json:"previous_department_name,omitempty" binding:"omitempty,max=100"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// DigestStatsRequest asks a service for activity of a user to include in the daily digest.
+// Times carry the offset of the user's timezone, so days are counted in local time.
+type DigestStatsRequest struct {
+	UserID   uint      `json:"user_id" binding:"required,min=1" validate:"required,min=1"`
+	Since    time.Time `json:"since" binding:"required" validate:"required"`     // Начало периода сводки, обычно время прошлой сводки
+	DayStart time.Time `json:"day_start" binding:"required" validate:"required"` // Полночь текущего дня пользователя
+}
+
+// MessagesDigestStats is the chat activity of a user since the last digest
+type MessagesDigestStats struct {
+	NewMessages    int64 `json:"new_messages"`    // Сообщения других участников в чатах пользователя
+	UnreadMessages int64 `json:"unread_messages"` // Непрочитанные сообщения на момент сводки
+	ActiveChats    int64 `json:"active_chats"`    // Чаты с новыми сообщениями
+}
+
+// TasksDigestStats is the task activity of a user since the last digest
+type TasksDigestStats struct {
+	NewTasks          int64 `json:"new_tasks"`          // Задачи, назначенные пользователю
+	CompletedTasks    int64 `json:"completed_tasks"`    // Задачи пользователя, переведённые в done
+	OverdueTasks      int64 `json:"overdue_tasks"`      // Открытые задачи пользователя с прошедшим сроком
+	UpcomingDeadlines int64 `json:"upcoming_deadlines"` // Открытые задачи пользователя со сроком до конца завтрашнего дня
+}
+
+// CalendarDigestStats is the calendar of a user for today and tomorrow
+type CalendarDigestStats struct {
+	TodayEvents    int64        `json:"today_events"`
+	TomorrowEvents int64        `json:"tomorrow_events"`
+	NextEvent      *DigestEvent `json:"next_event,omitempty"` // Ближайшее предстоящее событие сегодня или завтра
+}
+
+// DigestEvent is an event mentioned in the daily digest
+type DigestEvent struct {
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"` // В часовом поясе пользователя
+}
+
+// String formats the event for digest templates
+func (e *DigestEvent) String() string {
+	return e.Title + ", " + e.StartTime.Format("02.01 15:04")
+}
@@ -0,0 +1,24 @@
+package models
+
+// MergeUsersRequest asks a service to move data of a duplicate account to the primary account
+type MergeUsersRequest struct {
+	PrimaryUserID   uint `json:"primary_user_id" binding:"required,min=1" validate:"required,min=1"`
+	DuplicateUserID uint `json:"duplicate_user_id" binding:"required,min=1,nefield=PrimaryUserID" validate:"required,min=1,nefield=PrimaryUserID"`
+}
+
+// MergeUsersResult reports how many records of each kind a service moved to the primary account.
+// Records the primary account already had for the same chat, event or poll are dropped from the duplicate.
+type MergeUsersResult struct {
+	Service string           `json:"service"`
+	Moved   map[string]int64 `json:"moved"`
+	Dropped map[string]int64 `json:"dropped,omitempty"`
+}
+
+// NewMergeUsersResult creates an empty merge result of a service
+func NewMergeUsersResult(service string) *MergeUsersResult {
+	return &MergeUsersResult{
+		Service: service,
+		Moved:   make(map[string]int64),
+		Dropped: make(map[string]int64),
+	}
+}
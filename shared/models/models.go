@@ -47,6 +47,7 @@ type User struct {
 	Phone          string     `json:"phone,omitempty"`
 	Department     string     `json:"department,omitempty"`
 	Position       string     `json:"position,omitempty"`
+	Locale         string     `json:"locale,omitempty"`
 	LastActiveAt   *time.Time `json:"last_active_at,omitempty"`
 	IsActive       bool       `gorm:"not null;default:true" json:"is_active"`
 }
@@ -64,6 +65,7 @@ type Claims struct {
 	UserID uint   `json:"user_id"`
 	Email  string `json:"email"`
 	Role   Role   `json:"role"`
+	Locale string `json:"locale,omitempty"` // Язык пользователя из профиля
 	jwt.RegisteredClaims
 }
 
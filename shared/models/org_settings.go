@@ -0,0 +1,277 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"tachyon-messenger/shared/i18n"
+)
+
+// OrgSettings contains organization-wide settings shared by all services
+type OrgSettings struct {
+	WorkingWeek         WorkingWeek       `json:"working_week"`
+	DefaultTimezone     string            `json:"default_timezone" binding:"required,max=64" validate:"required,max=64"`
+	DefaultLocale       i18n.Locale       `json:"default_locale" binding:"omitempty,oneof=ru en" validate:"omitempty,enum"` // Язык писем и уведомлений пользователей без языка в профиле
+	PasswordPolicy      PasswordPolicy    `json:"password_policy"`
+	Retention           RetentionSettings `json:"retention"`
+	AllowedEmailDomains []string          `json:"allowed_email_domains" binding:"omitempty,max=50,dive,required,max=255" validate:"omitempty,max=50,dive,required,max=255"`
+	Branding            BrandingSettings  `json:"branding"`
+}
+
+// WorkingWeek describes working days and hours in the default timezone
+type WorkingWeek struct {
+	Days      []time.Weekday `json:"days" binding:"required,min=1,max=7,dive,min=0,max=6" validate:"required,min=1,max=7,dive,min=0,max=6"` // 0 - воскресенье
+	StartHour int            `json:"start_hour" binding:"min=0,max=23" validate:"min=0,max=23"`
+	EndHour   int            `json:"end_hour" binding:"min=1,max=24,gtfield=StartHour" validate:"min=1,max=24,gtfield=StartHour"`
+}
+
+// PasswordPolicy describes requirements for user passwords
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length" binding:"min=6,max=100" validate:"min=6,max=100"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+}
+
+// RetentionSettings describes default retention periods in days
+type RetentionSettings struct {
+	TrashDays        int `json:"trash_days" binding:"min=1,max=3650" validate:"min=1,max=3650"`
+	NotificationDays int `json:"notification_days" binding:"min=1,max=3650" validate:"min=1,max=3650"`
+
+	// Policies overrides retention of data types in days, see RetentionDataTypes
+	Policies map[RetentionDataType]int `json:"policies" binding:"omitempty,dive,min=1,max=3650" validate:"omitempty,dive,min=1,max=3650"`
+}
+
+// RetentionDataType is a kind of data removed by cleanup jobs after its retention period
+type RetentionDataType string
+
+const (
+	RetentionTrash           RetentionDataType = "trash"             // Soft-deleted chats, tasks and events
+	RetentionNotifications   RetentionDataType = "notifications"     // Notifications of users
+	RetentionAuditLogs       RetentionDataType = "audit_logs"        // Security events and organization settings changes
+	RetentionDeadLetterTasks RetentionDataType = "dead_letter_tasks" // Notification tasks that exhausted their retries
+)
+
+// RetentionDataTypes lists data types with retention policies and their default periods in days.
+// Trash and notifications default to TrashDays and NotificationDays.
+var RetentionDataTypes = []struct {
+	Type        RetentionDataType
+	DefaultDays int
+}{
+	{RetentionTrash, 30},
+	{RetentionNotifications, 30},
+	{RetentionAuditLogs, 365},
+	{RetentionDeadLetterTasks, 7},
+}
+
+// RetentionPolicy represents the effective retention period of a data type
+type RetentionPolicy struct {
+	DataType  RetentionDataType `json:"data_type"`
+	Days      int               `json:"days"`
+	IsDefault bool              `json:"is_default"` // No policy was set, the default period applies
+}
+
+// IsValidRetentionDataType checks if the data type has a retention policy
+func IsValidRetentionDataType(dataType RetentionDataType) bool {
+	for _, known := range RetentionDataTypes {
+		if known.Type == dataType {
+			return true
+		}
+	}
+	return false
+}
+
+// BrandingSettings contains strings substituted into email templates
+type BrandingSettings struct {
+	CompanyName  string `json:"company_name" binding:"max=100" validate:"max=100"`
+	ProductName  string `json:"product_name" binding:"max=100" validate:"max=100"`
+	SupportEmail string `json:"support_email" binding:"omitempty,email,max=255" validate:"omitempty,email,max=255"`
+	EmailFooter  string `json:"email_footer" binding:"max=500" validate:"max=500"`
+}
+
+// DefaultOrgSettings returns settings used until an administrator changes them
+func DefaultOrgSettings() *OrgSettings {
+	return &OrgSettings{
+		WorkingWeek: WorkingWeek{
+			Days:      []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			StartHour: 9,
+			EndHour:   18,
+		},
+		DefaultTimezone: "UTC",
+		DefaultLocale:   i18n.DefaultLocale,
+		PasswordPolicy: PasswordPolicy{
+			MinLength: 6,
+		},
+		Retention: RetentionSettings{
+			TrashDays:        30,
+			NotificationDays: 30,
+		},
+		AllowedEmailDomains: []string{},
+		Branding: BrandingSettings{
+			ProductName: "Tachyon Messenger",
+		},
+	}
+}
+
+// Normalize trims and lowercases email domains and removes duplicates
+func (s *OrgSettings) Normalize() {
+	seen := make(map[string]bool)
+	domains := make([]string, 0, len(s.AllowedEmailDomains))
+	for _, domain := range s.AllowedEmailDomains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+		if domain != "" && !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	s.AllowedEmailDomains = domains
+	s.DefaultTimezone = strings.TrimSpace(s.DefaultTimezone)
+	if s.DefaultLocale == "" {
+		s.DefaultLocale = i18n.DefaultLocale
+	}
+}
+
+// Validate checks settings that cannot be expressed with validation tags
+func (s *OrgSettings) Validate() error {
+	if _, err := time.LoadLocation(s.DefaultTimezone); err != nil {
+		return fmt.Errorf("invalid default timezone: %s", s.DefaultTimezone)
+	}
+	if !s.DefaultLocale.IsValid() {
+		return fmt.Errorf("unsupported default locale: %s", s.DefaultLocale)
+	}
+	for _, domain := range s.AllowedEmailDomains {
+		if !strings.Contains(domain, ".") || strings.ContainsAny(domain, "@ ") {
+			return fmt.Errorf("invalid email domain: %s", domain)
+		}
+	}
+	for dataType := range s.Retention.Policies {
+		if !IsValidRetentionDataType(dataType) {
+			return fmt.Errorf("invalid retention data type: %s", dataType)
+		}
+	}
+	return nil
+}
+
+// Location returns the default timezone, UTC if it cannot be loaded
+func (s *OrgSettings) Location() *time.Location {
+	location, err := time.LoadLocation(s.DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// IsEmailAllowed checks the email domain against allowed domains, any domain is allowed if the list is empty
+func (s *OrgSettings) IsEmailAllowed(email string) bool {
+	if len(s.AllowedEmailDomains) == 0 {
+		return true
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+
+	for _, allowed := range s.AllowedEmailDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkingDay checks if the weekday is a working day
+func (w WorkingWeek) IsWorkingDay(day time.Weekday) bool {
+	for _, workingDay := range w.Days {
+		if workingDay == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Check validates a password against the policy
+func (p PasswordPolicy) Check(password string) error {
+	if len(password) < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", p.MinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUppercase && !hasUpper {
+		return fmt.Errorf("password must contain at least one uppercase letter")
+	}
+	if p.RequireLowercase && !hasLower {
+		return fmt.Errorf("password must contain at least one lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		return fmt.Errorf("password must contain at least one digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		return fmt.Errorf("password must contain at least one symbol")
+	}
+	return nil
+}
+
+// TrashRetention returns how long soft-deleted records stay restorable
+func (r RetentionSettings) TrashRetention() time.Duration {
+	return r.TTL(RetentionTrash)
+}
+
+// NotificationRetention returns how long notifications are kept
+func (r RetentionSettings) NotificationRetention() time.Duration {
+	return r.TTL(RetentionNotifications)
+}
+
+// TTL returns how long data of the type is kept
+func (r RetentionSettings) TTL(dataType RetentionDataType) time.Duration {
+	return time.Duration(r.Policy(dataType).Days) * 24 * time.Hour
+}
+
+// Policy returns the effective retention policy of a data type
+func (r RetentionSettings) Policy(dataType RetentionDataType) RetentionPolicy {
+	if days := r.Policies[dataType]; days > 0 {
+		return RetentionPolicy{DataType: dataType, Days: days}
+	}
+
+	policy := RetentionPolicy{DataType: dataType, IsDefault: true}
+	switch dataType {
+	case RetentionTrash:
+		policy.Days = r.TrashDays
+	case RetentionNotifications:
+		policy.Days = r.NotificationDays
+	}
+	if policy.Days <= 0 {
+		for _, known := range RetentionDataTypes {
+			if known.Type == dataType {
+				policy.Days = known.DefaultDays
+			}
+		}
+	}
+	return policy
+}
+
+// PolicyList returns effective retention policies of all data types
+func (r RetentionSettings) PolicyList() []RetentionPolicy {
+	policies := make([]RetentionPolicy, len(RetentionDataTypes))
+	for i, known := range RetentionDataTypes {
+		policies[i] = r.Policy(known.Type)
+	}
+	return policies
+}
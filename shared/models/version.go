@@ -0,0 +1,17 @@
+package models
+
+// VersionMeta contains the optimistic locking version of a model.
+// The version starts at 1 and is incremented by every update, see database.SaveVersioned.
+type VersionMeta struct {
+	Version uint `gorm:"not null;default:1" json:"version"` // Версия записи для обнаружения конфликтов
+}
+
+// GetVersion returns the current version of the model
+func (m *VersionMeta) GetVersion() uint {
+	return m.Version
+}
+
+// SetVersion sets the version of the model
+func (m *VersionMeta) SetVersion(version uint) {
+	m.Version = version
+}
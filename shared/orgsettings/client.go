@@ -0,0 +1,112 @@
+package orgsettings
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/models"
+)
+
+// DefaultCacheTTL is how long fetched settings are used before they are requested again
+const DefaultCacheTTL = 5 * time.Minute
+
+// Client reads organization settings from the user service and caches them.
+// If the user service is unavailable, the last fetched settings are used, or defaults
+// if settings were never fetched. All methods work on a nil client and return defaults,
+// so services can run without the user service.
+type Client struct {
+	baseURL    string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	settings  *models.OrgSettings
+	fetchedAt time.Time
+}
+
+// NewClient creates a settings client for the user service at baseURL, zero ttl uses DefaultCacheTTL.
+// It returns nil if baseURL is empty.
+func NewClient(baseURL string, ttl time.Duration) *Client {
+	if baseURL == "" {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		ttl:        ttl,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Get returns current organization settings. The returned settings must not be modified.
+func (c *Client) Get() *models.OrgSettings {
+	if c == nil {
+		return models.DefaultOrgSettings()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.settings != nil && time.Since(c.fetchedAt) < c.ttl {
+		return c.settings
+	}
+
+	settings, err := c.fetch()
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to fetch organization settings")
+
+		// Retry after ttl instead of on every call while the user service is down
+		c.fetchedAt = time.Now()
+		if c.settings == nil {
+			c.settings = models.DefaultOrgSettings()
+		}
+		return c.settings
+	}
+
+	c.settings = settings
+	c.fetchedAt = time.Now()
+	return c.settings
+}
+
+// Invalidate drops cached settings, so the next Get fetches them again
+func (c *Client) Invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetchedAt = time.Time{}
+}
+
+// fetch requests settings from the internal endpoint of the user service
+func (c *Client) fetch() (*models.OrgSettings, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/v1/internal/settings")
+	if err != nil {
+		return nil, fmt.Errorf("failed to request settings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user service responded with status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Settings *models.OrgSettings `json:"settings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	if payload.Settings == nil {
+		return nil, fmt.Errorf("user service returned no settings")
+	}
+
+	return payload.Settings, nil
+}
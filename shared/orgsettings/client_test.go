@@ -0,0 +1,60 @@
+package orgsettings
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tachyon-messenger/shared/models"
+)
+
+func TestClientCachesSettings(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/api/v1/internal/settings" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		settings := models.DefaultOrgSettings()
+		settings.DefaultTimezone = "Europe/Moscow"
+		json.NewEncoder(w).Encode(map[string]interface{}{"settings": settings})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Minute)
+	for i := 0; i < 3; i++ {
+		if tz := client.Get().DefaultTimezone; tz != "Europe/Moscow" {
+			t.Fatalf("expected fetched timezone, got %q", tz)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected settings fetched once, got %d requests", requests)
+	}
+
+	client.Invalidate()
+	client.Get()
+	if requests != 2 {
+		t.Fatalf("expected settings fetched again after invalidation, got %d requests", requests)
+	}
+}
+
+func TestClientFallsBackToDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	defaults := models.DefaultOrgSettings()
+	if settings := NewClient(server.URL, time.Minute).Get(); settings.DefaultTimezone != defaults.DefaultTimezone {
+		t.Fatalf("expected default settings, got %+v", settings)
+	}
+
+	var client *Client
+	if settings := client.Get(); settings.Retention.TrashDays != defaults.Retention.TrashDays {
+		t.Fatalf("expected default settings from nil client, got %+v", settings)
+	}
+}
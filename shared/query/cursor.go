@@ -0,0 +1,97 @@
+package query
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NextCursor returns the cursor continuing after the last item of a full page, or an empty
+// string when the page is not full and so is the last one. Items are matched to sort fields by their JSON names,
+// so it works with both models and response DTOs.
+func (p *Params) NextCursor(items interface{}) string {
+	list := reflect.ValueOf(items)
+	if list.Kind() != reflect.Slice || list.Len() == 0 || list.Len() < p.Limit {
+		return ""
+	}
+
+	data, err := json.Marshal(list.Index(list.Len() - 1).Interface())
+	if err != nil {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return ""
+	}
+
+	names := make([]string, 0, len(p.Sort)+1)
+	for _, field := range p.Sort {
+		names = append(names, field.Field)
+	}
+	names = append(names, columnName(p.tieBreaker))
+
+	values := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		value, ok := fields[name]
+		if !ok || value == nil {
+			// Keyset paging is not possible on missing or NULL values
+			return ""
+		}
+		values = append(values, value)
+	}
+
+	data, err = json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor decodes keyset values of a cursor, which must match the sort fields
+func decodeCursor(raw string, expected int) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	var values []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+
+	if len(values) != expected {
+		return nil, fmt.Errorf("%w: cursor does not match the requested sort", ErrInvalidQuery)
+	}
+
+	for i, value := range values {
+		switch v := value.(type) {
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				values[i] = n
+			} else if f, err := v.Float64(); err == nil {
+				values[i] = f
+			}
+		case string, bool:
+		default:
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+		}
+	}
+
+	return values, nil
+}
+
+// columnName strips the table qualifier from a column
+func columnName(column string) string {
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		return column[i+1:]
+	}
+	return column
+}
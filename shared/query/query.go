@@ -0,0 +1,316 @@
+// Package query parses list request parameters (pagination, sorting and filtering)
+// with the same semantics in every service and applies them to GORM queries.
+//
+// Supported query string parameters:
+//
+//	limit=20                   page size, capped by Options.MaxLimit
+//	offset=40                  rows to skip (ignored when cursor is set)
+//	cursor=...                 opaque keyset cursor returned as next_cursor
+//	sort=-priority,created_at  sort fields, "-" prefix for descending order
+//	sort_by=title&sort_order=asc  legacy single-field sorting, descending without sort_order
+//	filter[status]=done        equality filter
+//	filter[due_date][lt]=2024-06-01  filter with operator (eq, ne, gt, gte, lt, lte, in, like)
+//
+// Only fields whitelisted in Options can be sorted and filtered on.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultLimit is the page size used when Options.DefaultLimit is not set
+	DefaultLimit = 20
+	// MaxLimit is the page size cap used when Options.MaxLimit is not set
+	MaxLimit = 100
+
+	// maxSortFields limits the number of fields in a sort expression
+	maxSortFields = 3
+	// maxInValues limits the number of values of an "in" filter
+	maxInValues = 100
+)
+
+// ErrInvalidQuery is returned for malformed or not allowed list parameters
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Operator represents a filter comparison operator
+type Operator string
+
+const (
+	OpEq   Operator = "eq"
+	OpNe   Operator = "ne"
+	OpGt   Operator = "gt"
+	OpGte  Operator = "gte"
+	OpLt   Operator = "lt"
+	OpLte  Operator = "lte"
+	OpIn   Operator = "in"
+	OpLike Operator = "like"
+)
+
+// IsValid checks if the operator is supported
+func (o Operator) IsValid() bool {
+	switch o {
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpLike:
+		return true
+	default:
+		return false
+	}
+}
+
+// Options describes what a list endpoint allows
+type Options struct {
+	DefaultLimit int
+	MaxLimit     int
+
+	// DefaultSort is used when the request has no sort, in the sort parameter format
+	DefaultSort string
+
+	// SortFields and FilterFields map API field names to SQL columns
+	SortFields   map[string]string
+	FilterFields map[string]string
+
+	// TieBreaker is the unique column appended to the sort to keep pages stable
+	// and used in cursors, "id" by default
+	TieBreaker string
+
+	// LegacyAscending makes sort_by without sort_order sort ascending, for endpoints
+	// that sorted that way before the sort parameter
+	LegacyAscending bool
+}
+
+// SortField represents a single sort criterion
+type SortField struct {
+	Field  string `json:"field"`
+	Column string `json:"-"`
+	Desc   bool   `json:"desc"`
+}
+
+// Filter represents a single filter condition
+type Filter struct {
+	Field    string   `json:"field"`
+	Column   string   `json:"-"`
+	Operator Operator `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+// Params represents parsed list parameters
+type Params struct {
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+	Sort    []SortField `json:"sort"`
+	Filters []Filter    `json:"filters,omitempty"`
+
+	// cursor holds decoded keyset values of the last row of the previous page
+	cursor     []interface{}
+	tieBreaker string
+}
+
+// HasCursor reports whether the page continues from a cursor
+func (p *Params) HasCursor() bool {
+	return p.cursor != nil
+}
+
+// Parse parses list parameters from a query string
+func Parse(values url.Values, opts *Options) (*Params, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	params := &Params{
+		Limit:      opts.defaultLimit(),
+		tieBreaker: opts.tieBreaker(),
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("%w: limit must be a positive integer", ErrInvalidQuery)
+		}
+		params.Limit = min(limit, opts.maxLimit())
+	}
+
+	if raw := values.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("%w: offset must be a non-negative integer", ErrInvalidQuery)
+		}
+		params.Offset = offset
+	}
+
+	sortExpr := values.Get("sort")
+	if sortExpr == "" && values.Get("sort_by") != "" {
+		sortExpr = values.Get("sort_by")
+		desc := !opts.LegacyAscending
+		if order := values.Get("sort_order"); order != "" {
+			desc = strings.EqualFold(order, "desc")
+		}
+		if desc {
+			sortExpr = "-" + sortExpr
+		}
+	}
+	if sortExpr == "" {
+		sortExpr = opts.DefaultSort
+	}
+
+	sortFields, err := parseSort(sortExpr, opts.SortFields)
+	if err != nil {
+		return nil, err
+	}
+	params.Sort = sortFields
+
+	filters, err := parseFilters(values, opts.FilterFields)
+	if err != nil {
+		return nil, err
+	}
+	params.Filters = filters
+
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw, len(params.Sort)+1)
+		if err != nil {
+			return nil, err
+		}
+		params.cursor = cursor
+		params.Offset = 0
+	}
+
+	return params, nil
+}
+
+// Default returns parameters of a request without list parameters
+func Default(opts *Options) *Params {
+	params, err := Parse(url.Values{}, opts)
+	if err != nil {
+		// Only a misconfigured DefaultSort can fail here
+		panic(err)
+	}
+	return params
+}
+
+// parseSort parses a comma-separated sort expression against the whitelist
+func parseSort(expr string, allowed map[string]string) ([]SortField, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(expr, ",")
+	if len(parts) > maxSortFields {
+		return nil, fmt.Errorf("%w: at most %d sort fields are allowed", ErrInvalidQuery, maxSortFields)
+	}
+
+	seen := make(map[string]bool, len(parts))
+	fields := make([]SortField, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+
+		column, ok := allowed[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: sorting by %q is not allowed (allowed: %s)", ErrInvalidQuery, name, allowedNames(allowed))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate sort field %q", ErrInvalidQuery, name)
+		}
+		seen[name] = true
+
+		fields = append(fields, SortField{Field: name, Column: column, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+// parseFilters parses filter[field] and filter[field][op] parameters against the whitelist
+func parseFilters(values url.Values, allowed map[string]string) ([]Filter, error) {
+	keys := make([]string, 0)
+	for key := range values {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys) // Deterministic SQL for the same request
+
+	filters := make([]Filter, 0, len(keys))
+	for _, key := range keys {
+		name, op, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		column, ok := allowed[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: filtering by %q is not allowed (allowed: %s)", ErrInvalidQuery, name, allowedNames(allowed))
+		}
+
+		raw := values.Get(key)
+		filterValues := []string{raw}
+		if op == OpIn {
+			filterValues = strings.Split(raw, ",")
+			if len(filterValues) > maxInValues {
+				return nil, fmt.Errorf("%w: at most %d values are allowed in filter %q", ErrInvalidQuery, maxInValues, name)
+			}
+		}
+
+		filters = append(filters, Filter{Field: name, Column: column, Operator: op, Values: filterValues})
+	}
+
+	return filters, nil
+}
+
+// parseFilterKey splits "filter[field]" or "filter[field][op]" into field and operator
+func parseFilterKey(key string) (string, Operator, error) {
+	rest := strings.TrimPrefix(key, "filter[")
+	name, rest, ok := strings.Cut(rest, "]")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("%w: malformed filter parameter %q", ErrInvalidQuery, key)
+	}
+
+	if rest == "" {
+		return name, OpEq, nil
+	}
+
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+		return "", "", fmt.Errorf("%w: malformed filter parameter %q", ErrInvalidQuery, key)
+	}
+	op := Operator(rest[1 : len(rest)-1])
+	if !op.IsValid() {
+		return "", "", fmt.Errorf("%w: unsupported filter operator %q", ErrInvalidQuery, op)
+	}
+
+	return name, op, nil
+}
+
+// allowedNames lists whitelisted field names for error messages
+func allowedNames(allowed map[string]string) string {
+	names := make([]string, 0, len(allowed))
+	for name := range allowed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (o *Options) defaultLimit() int {
+	if o.DefaultLimit > 0 {
+		return min(o.DefaultLimit, o.maxLimit())
+	}
+	return min(DefaultLimit, o.maxLimit())
+}
+
+func (o *Options) maxLimit() int {
+	if o.MaxLimit > 0 {
+		return o.MaxLimit
+	}
+	return MaxLimit
+}
+
+func (o *Options) tieBreaker() string {
+	if o.TieBreaker != "" {
+		return o.TieBreaker
+	}
+	return "id"
+}
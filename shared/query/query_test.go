@@ -0,0 +1,166 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testRow struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	Title    string `json:"title"`
+	Priority int    `json:"priority"`
+	Status   string `json:"status"`
+}
+
+var testOptions = &Options{
+	DefaultLimit: 2,
+	MaxLimit:     5,
+	DefaultSort:  "-priority",
+	SortFields:   map[string]string{"priority": "priority", "title": "title"},
+	FilterFields: map[string]string{"status": "status", "title": "title"},
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&testRow{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	rows := []*testRow{
+		{Title: "Alpha", Priority: 3, Status: "open"},
+		{Title: "Beta", Priority: 1, Status: "done"},
+		{Title: "Gamma", Priority: 3, Status: "open"},
+		{Title: "Delta", Priority: 2, Status: "open"},
+		{Title: "Epsilon 100%", Priority: 2, Status: "open"},
+	}
+	if err := db.Create(rows).Error; err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+	return db
+}
+
+func TestParse(t *testing.T) {
+	params, err := Parse(url.Values{}, testOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Limit != 2 || params.Offset != 0 || len(params.Sort) != 1 || !params.Sort[0].Desc {
+		t.Fatalf("unexpected defaults: %+v", params)
+	}
+
+	params, err = Parse(url.Values{"limit": {"500"}, "sort_by": {"title"}, "sort_order": {"desc"}}, testOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Limit != 5 {
+		t.Errorf("expected limit capped at 5, got %d", params.Limit)
+	}
+	if params.Sort[0].Field != "title" || !params.Sort[0].Desc {
+		t.Errorf("expected legacy sort to be title desc, got %+v", params.Sort)
+	}
+
+	// Legacy sort_by without sort_order keeps sorting descending, unless the endpoint sorted ascending
+	params, err = Parse(url.Values{"sort_by": {"title"}}, testOptions)
+	if err != nil || !params.Sort[0].Desc {
+		t.Errorf("expected legacy sort without order to be descending, got %+v, %v", params.Sort, err)
+	}
+	ascending := *testOptions
+	ascending.LegacyAscending = true
+	params, err = Parse(url.Values{"sort_by": {"title"}}, &ascending)
+	if err != nil || params.Sort[0].Desc {
+		t.Errorf("expected legacy sort without order to be ascending, got %+v, %v", params.Sort, err)
+	}
+	params, err = Parse(url.Values{"sort_by": {"title"}, "sort_order": {"asc"}}, testOptions)
+	if err != nil || params.Sort[0].Desc {
+		t.Errorf("expected legacy sort to be title asc, got %+v, %v", params.Sort, err)
+	}
+
+	invalid := []url.Values{
+		{"limit": {"0"}},
+		{"offset": {"-1"}},
+		{"sort": {"password"}},
+		{"sort": {"title,-title"}},
+		{"filter[email]": {"x"}},
+		{"filter[status][regex]": {"x"}},
+		{"filter[status": {"x"}},
+		{"cursor": {"not a cursor"}},
+	}
+	for _, values := range invalid {
+		if _, err := Parse(values, testOptions); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("expected ErrInvalidQuery for %v, got %v", values, err)
+		}
+	}
+}
+
+func TestScopes(t *testing.T) {
+	db := openTestDB(t)
+
+	params, err := Parse(url.Values{
+		"filter[status]":      {"open"},
+		"filter[title][like]": {"100%"},
+		"filter[status][in]":  {"open,done"},
+		"sort":                {"title"},
+	}, testOptions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rows []*testRow
+	if err := db.Model(&testRow{}).Scopes(params.FilterScope, params.PageScope).Find(&rows).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Title != "Epsilon 100%" {
+		t.Fatalf("expected only Epsilon 100%%, got %+v", rows)
+	}
+}
+
+func TestCursorPaging(t *testing.T) {
+	db := openTestDB(t)
+
+	// Sorted by priority desc, then id desc: Gamma, Alpha, Epsilon, Delta, Beta
+	want := []string{"Gamma", "Alpha", "Epsilon 100%", "Delta", "Beta"}
+
+	var got []string
+	values := url.Values{}
+	for page := 0; page < 5; page++ {
+		params, err := Parse(values, testOptions)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var rows []*testRow
+		if err := db.Model(&testRow{}).Scopes(params.PageScope).Find(&rows).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		for _, row := range rows {
+			got = append(got, row.Title)
+		}
+
+		cursor := params.NextCursor(rows)
+		if cursor == "" {
+			break
+		}
+		values = url.Values{"cursor": {cursor}}
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if _, err := Parse(url.Values{"cursor": {"WzEsMl0"}, "sort": {"title,priority"}}, testOptions); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("expected cursor/sort mismatch to fail, got %v", err)
+	}
+}
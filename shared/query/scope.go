@@ -0,0 +1,99 @@
+package query
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// likeEscaper escapes LIKE wildcards in filter values
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// FilterScope applies whitelisted filters to the query. Use it before counting totals.
+func (p *Params) FilterScope(db *gorm.DB) *gorm.DB {
+	for _, filter := range p.Filters {
+		switch filter.Operator {
+		case OpNe:
+			db = db.Where(filter.Column+" <> ?", filter.Values[0])
+		case OpGt:
+			db = db.Where(filter.Column+" > ?", filter.Values[0])
+		case OpGte:
+			db = db.Where(filter.Column+" >= ?", filter.Values[0])
+		case OpLt:
+			db = db.Where(filter.Column+" < ?", filter.Values[0])
+		case OpLte:
+			db = db.Where(filter.Column+" <= ?", filter.Values[0])
+		case OpIn:
+			db = db.Where(filter.Column+" IN ?", filter.Values)
+		case OpLike:
+			db = db.Where("LOWER("+filter.Column+") LIKE LOWER(?) ESCAPE '\\'", "%"+likeEscaper.Replace(filter.Values[0])+"%")
+		default:
+			db = db.Where(filter.Column+" = ?", filter.Values[0])
+		}
+	}
+	return db
+}
+
+// PageScope applies sorting, the cursor condition and limit/offset to the query
+func (p *Params) PageScope(db *gorm.DB) *gorm.DB {
+	return p.OrderScope(p.cursorScope(db)).Limit(p.Limit).Offset(p.Offset)
+}
+
+// OrderScope applies sorting with the tie breaker to the query
+func (p *Params) OrderScope(db *gorm.DB) *gorm.DB {
+	for _, field := range p.Sort {
+		db = db.Order(orderClause(field.Column, field.Desc))
+	}
+	return db.Order(orderClause(p.tieBreaker, p.tieBreakerDesc()))
+}
+
+// cursorScope restricts the query to rows after the cursor position in sort order
+func (p *Params) cursorScope(db *gorm.DB) *gorm.DB {
+	if p.cursor == nil {
+		return db
+	}
+
+	columns := make([]string, 0, len(p.Sort)+1)
+	desc := make([]bool, 0, len(p.Sort)+1)
+	for _, field := range p.Sort {
+		columns = append(columns, field.Column)
+		desc = append(desc, field.Desc)
+	}
+	columns = append(columns, p.tieBreaker)
+	desc = append(desc, p.tieBreakerDesc())
+
+	// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ... with the comparison following each sort direction
+	var conditions []string
+	var args []interface{}
+	for i := range columns {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j]+" = ?")
+			args = append(args, p.cursor[j])
+		}
+		op := " > ?"
+		if desc[i] {
+			op = " < ?"
+		}
+		parts = append(parts, columns[i]+op)
+		args = append(args, p.cursor[i])
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// tieBreakerDesc returns the tie breaker direction, which follows the last sort field
+func (p *Params) tieBreakerDesc() bool {
+	if len(p.Sort) == 0 {
+		return false
+	}
+	return p.Sort[len(p.Sort)-1].Desc
+}
+
+func orderClause(column string, desc bool) string {
+	if desc {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
@@ -0,0 +1,490 @@
+// Package quota limits how much of expensive endpoints, such as search and exports, every user may use.
+//
+//   - an endpoint spends its cost from a bucket of the user; costs are weighted, so a request
+//     for a large page spends more than a small one
+//   - buckets refill in fixed windows; requests over the limit get 429 with quota headers
+//   - counters live in Redis, so a user's budget is shared by all instances and services;
+//     if Redis fails every instance counts on its own
+//   - QUOTAS_ENABLED=false turns quotas off, QUOTA_<BUCKET>_LIMIT sets the cost units of a
+//     bucket per window (0 disables the bucket) and QUOTA_<BUCKET>_WINDOW its window
+//   - administrators exempt service accounts or raise their limits with overrides
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+	"tachyon-messenger/shared/redis"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Buckets of expensive endpoints
+const (
+	BucketSearch = "search" // Search of messages, users, notifications and polls
+	BucketExport = "export" // Downloads of a user's data, streamed from the database
+)
+
+const (
+	// DefaultSearchLimit is the default search budget of a user per window
+	DefaultSearchLimit = 60
+
+	// DefaultExportLimit is the default export budget of a user per DefaultExportWindow
+	DefaultExportLimit = 10
+
+	// DefaultExportWindow is the default period after which the export bucket refills
+	DefaultExportWindow = time.Hour
+
+	// DefaultWindow is the default period after which buckets refill
+	DefaultWindow = time.Minute
+
+	// DefaultCacheTTL is how long overrides are used without reading Redis
+	DefaultCacheTTL = 10 * time.Second
+
+	// maxLocalCounters bounds the counters kept in memory while Redis is unavailable
+	maxLocalCounters = 10000
+)
+
+// Redis keys shared by the gateway and all services
+const (
+	counterKeyPrefix = "quota:counter:"  // quota:counter:<bucket>:<user ID>:<window start>
+	overridesKey     = "quota:overrides" // Hash of user ID -> Override JSON
+)
+
+// defaultLimits are the budgets of known buckets per DefaultWindow
+var defaultLimits = map[string]int{
+	BucketSearch: DefaultSearchLimit,
+	BucketExport: DefaultExportLimit,
+}
+
+// defaultWindows are the windows of buckets that do not refill every DefaultWindow
+var defaultWindows = map[string]time.Duration{
+	BucketExport: DefaultExportWindow,
+}
+
+var (
+	ErrUnavailable = errors.New("quota storage is unavailable")
+	ErrInvalid     = errors.New("invalid quota override")
+)
+
+// Limit is the budget of a bucket
+type Limit struct {
+	Bucket string        `json:"bucket"`
+	Limit  int           `json:"limit"` // Cost units per window, 0 when the bucket is not limited
+	Window time.Duration `json:"-"`
+
+	WindowSeconds int `json:"window_seconds"`
+}
+
+// LimitsFromEnv returns budgets of all known buckets from environment variables
+func LimitsFromEnv() []Limit {
+	buckets := make([]string, 0, len(defaultLimits))
+	for bucket := range defaultLimits {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+
+	limits := make([]Limit, 0, len(buckets))
+	for _, bucket := range buckets {
+		name := strings.ToUpper(bucket)
+		window, ok := defaultWindows[bucket]
+		if !ok {
+			window = DefaultWindow
+		}
+		limits = append(limits, Limit{
+			Bucket: bucket,
+			Limit:  intFromEnv("QUOTA_"+name+"_LIMIT", defaultLimits[bucket]),
+			Window: durationFromEnv("QUOTA_"+name+"_WINDOW", window),
+		})
+	}
+	return limits
+}
+
+// Override exempts a user, usually a service account, from quotas or multiplies their limits
+type Override struct {
+	UserID     uint       `json:"user_id"`
+	Exempt     bool       `json:"exempt"`
+	Multiplier float64    `json:"multiplier,omitempty"` // Applied to all limits when not exempt
+	Reason     string     `json:"reason,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	CreatedBy  uint       `json:"created_by"`
+}
+
+// IsActive checks if the override applies at the given time
+func (o *Override) IsActive(now time.Time) bool {
+	return o != nil && (o.ExpiresAt == nil || now.Before(*o.ExpiresAt))
+}
+
+// Usage is the state of a user's bucket after a request
+type Usage struct {
+	Bucket     string    `json:"bucket"`
+	Limit      int       `json:"limit"`
+	Used       int       `json:"used"`
+	Remaining  int       `json:"remaining"`
+	Cost       int       `json:"cost"`
+	ResetAt    time.Time `json:"reset_at"`
+	RetryAfter int       `json:"retry_after,omitempty"` // Seconds, set when the request was rejected
+}
+
+// Cost returns how many units a request spends
+type Cost func(c *gin.Context) int
+
+// Fixed spends the same cost on every request
+func Fixed(cost int) Cost {
+	return func(c *gin.Context) int { return cost }
+}
+
+// PerPage spends base for every started pageSize items requested with the limit query
+// parameter, so a request for a large page costs as much as several small ones
+func PerPage(base, pageSize int) Cost {
+	return func(c *gin.Context) int {
+		limit, err := strconv.Atoi(c.Query("limit"))
+		if err != nil || limit <= pageSize {
+			return base
+		}
+		return base * int(math.Ceil(float64(limit)/float64(pageSize)))
+	}
+}
+
+// Quotas counts spending of users per bucket. All methods are safe on nil, which
+// never limits anything, so quotas can be turned off.
+type Quotas struct {
+	client *redis.Client // nil counts on this instance only and disables overrides
+	limits map[string]Limit
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	local     map[string]int
+	overrides map[uint]*Override
+	fetchedAt time.Time
+}
+
+// New creates quotas with the given budgets, zero ttl uses DefaultCacheTTL
+func New(client *redis.Client, limits []Limit, ttl time.Duration) *Quotas {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	q := &Quotas{
+		client: client,
+		limits: make(map[string]Limit, len(limits)),
+		ttl:    ttl,
+		local:  make(map[string]int),
+	}
+	for _, limit := range limits {
+		if limit.Window <= 0 {
+			limit.Window = DefaultWindow
+		}
+		limit.WindowSeconds = int(limit.Window.Seconds())
+		q.limits[limit.Bucket] = limit
+	}
+	return q
+}
+
+// NewFromEnv creates quotas with budgets from environment variables.
+// It returns nil if QUOTAS_ENABLED is false.
+func NewFromEnv(client *redis.Client) *Quotas {
+	if enabled, err := strconv.ParseBool(os.Getenv("QUOTAS_ENABLED")); err == nil && !enabled {
+		return nil
+	}
+	return New(client, LimitsFromEnv(), 0)
+}
+
+// Limits returns budgets of all buckets
+func (q *Quotas) Limits() []Limit {
+	if q == nil {
+		return nil
+	}
+
+	limits := make([]Limit, 0, len(q.limits))
+	for _, limit := range q.limits {
+		limits = append(limits, limit)
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Bucket < limits[j].Bucket })
+	return limits
+}
+
+// Middleware spends the cost of a request from the user's bucket and rejects the request
+// with 429 when the bucket is exhausted. It must run after JWT authentication.
+func (q *Quotas) Middleware(bucket string, cost Cost) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if q == nil {
+			return
+		}
+		userID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			return
+		}
+
+		usage, allowed := q.Spend(bucket, userID, cost(c), time.Now())
+		if usage == nil {
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(usage.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(usage.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(usage.ResetAt.Unix(), 10))
+		c.Header("X-Quota-Bucket", usage.Bucket)
+		c.Header("X-Quota-Cost", strconv.Itoa(usage.Cost))
+		if allowed {
+			return
+		}
+
+		requestID := requestid.Get(c)
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"bucket":     bucket,
+			"cost":       usage.Cost,
+			"limit":      usage.Limit,
+			"path":       c.Request.URL.Path,
+		}).Warn("Request rejected, quota exceeded")
+
+		c.Header("Retry-After", strconv.Itoa(usage.RetryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":       i18n.Message(c, "error.quota_exceeded"),
+			"code":        "quota_exceeded",
+			"quota":       usage,
+			"retry_after": usage.RetryAfter,
+			"request_id":  requestID,
+		})
+	}
+}
+
+// Spend counts cost against the user's bucket in the window of now. It returns nil usage
+// when the bucket is not limited for the user. Rejected requests are not counted, so
+// retrying clients don't push their own reset back.
+func (q *Quotas) Spend(bucket string, userID uint, cost int, now time.Time) (*Usage, bool) {
+	if q == nil {
+		return nil, true
+	}
+	limit, ok := q.limits[bucket]
+	if !ok || limit.Limit <= 0 {
+		return nil, true
+	}
+
+	budget := limit.Limit
+	if override := q.override(userID, now); override != nil {
+		if override.Exempt {
+			return nil, true
+		}
+		if override.Multiplier > 0 {
+			budget = int(math.Round(float64(budget) * override.Multiplier))
+		}
+	}
+	if cost < 1 {
+		cost = 1
+	}
+
+	windowStart := now.Truncate(limit.Window)
+	key := fmt.Sprintf("%s%s:%d:%d", counterKeyPrefix, bucket, userID, windowStart.Unix())
+	used := q.count(key, cost, limit.Window)
+
+	usage := &Usage{
+		Bucket:  bucket,
+		Limit:   budget,
+		Used:    used,
+		Cost:    cost,
+		ResetAt: windowStart.Add(limit.Window),
+	}
+	allowed := used <= budget
+	if !allowed {
+		q.count(key, -cost, limit.Window)
+		usage.Used -= cost
+		usage.RetryAfter = max(int(math.Ceil(usage.ResetAt.Sub(now).Seconds())), 1)
+	}
+	usage.Remaining = max(budget-usage.Used, 0)
+	return usage, allowed
+}
+
+// count adds delta to a counter and returns its value, in Redis when available
+func (q *Quotas) count(key string, delta int, window time.Duration) int {
+	if q.client != nil {
+		ctx := context.Background()
+		value, err := q.client.Client.IncrBy(ctx, key, int64(delta)).Result()
+		if err == nil {
+			if value == int64(delta) {
+				err = q.client.Client.Expire(ctx, key, 2*window).Err()
+			}
+			if err == nil {
+				return int(value)
+			}
+		}
+		logger.WithFields(map[string]interface{}{
+			"key":   key,
+			"error": err.Error(),
+		}).Warn("Failed to count quota in Redis, counting locally")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.local[key]; !ok && len(q.local) >= maxLocalCounters {
+		q.local = make(map[string]int)
+	}
+	q.local[key] += delta
+	return q.local[key]
+}
+
+// Overrides returns all overrides, including expired ones, ordered by user
+func (q *Quotas) Overrides() ([]*Override, error) {
+	if q == nil || q.client == nil {
+		return nil, ErrUnavailable
+	}
+
+	overrides, err := q.fetchOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Override, 0, len(overrides))
+	for _, override := range overrides {
+		list = append(list, override)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UserID < list[j].UserID })
+	return list, nil
+}
+
+// SetOverride saves the override of a user, replacing a previous one
+func (q *Quotas) SetOverride(override *Override) error {
+	if q == nil || q.client == nil {
+		return ErrUnavailable
+	}
+	if override.UserID == 0 || (!override.Exempt && override.Multiplier <= 0) {
+		return ErrInvalid
+	}
+
+	data, err := json.Marshal(override)
+	if err != nil {
+		return fmt.Errorf("failed to encode quota override: %w", err)
+	}
+	field := strconv.FormatUint(uint64(override.UserID), 10)
+	if err := q.client.Client.HSet(context.Background(), overridesKey, field, data).Err(); err != nil {
+		return fmt.Errorf("failed to save quota override: %w", err)
+	}
+
+	q.Invalidate()
+	return nil
+}
+
+// DeleteOverride removes the override of a user
+func (q *Quotas) DeleteOverride(userID uint) error {
+	if q == nil || q.client == nil {
+		return ErrUnavailable
+	}
+
+	field := strconv.FormatUint(uint64(userID), 10)
+	if err := q.client.Client.HDel(context.Background(), overridesKey, field).Err(); err != nil {
+		return fmt.Errorf("failed to delete quota override: %w", err)
+	}
+
+	q.Invalidate()
+	return nil
+}
+
+// Invalidate drops cached overrides so the next request reads them from Redis
+func (q *Quotas) Invalidate() {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.fetchedAt = time.Time{}
+	q.mu.Unlock()
+}
+
+// override returns the active override of a user from the cache, refreshed after the TTL.
+// If Redis fails the last known overrides are kept.
+func (q *Quotas) override(userID uint, now time.Time) *Override {
+	if q.client == nil {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.overrides == nil || time.Since(q.fetchedAt) >= q.ttl {
+		overrides, err := q.fetchOverrides()
+		if err != nil {
+			logger.WithField("error", err.Error()).Warn("Failed to load quota overrides, using last known state")
+		} else {
+			q.overrides = overrides
+		}
+		// Retry after the TTL instead of hitting Redis on every request
+		q.fetchedAt = time.Now()
+	}
+
+	if override := q.overrides[userID]; override.IsActive(now) {
+		return override
+	}
+	return nil
+}
+
+// fetchOverrides reads overrides from Redis
+func (q *Quotas) fetchOverrides() (map[uint]*Override, error) {
+	fields, err := q.client.Client.HGetAll(context.Background(), overridesKey).Result()
+	if err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("failed to get quota overrides: %w", err)
+	}
+
+	overrides := make(map[uint]*Override, len(fields))
+	for field, value := range fields {
+		var override Override
+		if err := json.Unmarshal([]byte(value), &override); err != nil {
+			return nil, fmt.Errorf("failed to decode quota override %s: %w", field, err)
+		}
+		overrides[override.UserID] = &override
+	}
+	return overrides, nil
+}
+
+// intFromEnv parses a non-negative integer from environment or returns the default
+func intFromEnv(key string, defaultValue int) int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		logger.WithFields(map[string]interface{}{
+			"variable": key,
+			"value":    value,
+		}).Warn("Invalid quota setting, using default")
+		return defaultValue
+	}
+	return parsed
+}
+
+// durationFromEnv parses a positive duration from environment or returns the default
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		logger.WithFields(map[string]interface{}{
+			"variable": key,
+			"value":    value,
+		}).Warn("Invalid quota setting, using default")
+		return defaultValue
+	}
+	return parsed
+}
@@ -0,0 +1,127 @@
+package quota
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSpendCountsCostPerWindow(t *testing.T) {
+	q := New(nil, []Limit{{Bucket: BucketSearch, Limit: 5, Window: time.Minute}}, 0)
+	now := time.Date(2026, 1, 1, 12, 0, 10, 0, time.UTC)
+
+	usage, allowed := q.Spend(BucketSearch, 1, 3, now)
+	if !allowed || usage.Used != 3 || usage.Remaining != 2 {
+		t.Fatalf("unexpected usage after first request: %+v", usage)
+	}
+
+	// Rejected requests are not counted
+	usage, allowed = q.Spend(BucketSearch, 1, 3, now)
+	if allowed || usage.Used != 3 || usage.RetryAfter != 50 {
+		t.Fatalf("expected rejection, got %+v", usage)
+	}
+	if usage, allowed = q.Spend(BucketSearch, 1, 2, now); !allowed || usage.Remaining != 0 {
+		t.Fatalf("expected the rest of the budget to be spent, got %+v", usage)
+	}
+
+	// Other users and the next window have their own budgets
+	if _, allowed = q.Spend(BucketSearch, 2, 5, now); !allowed {
+		t.Fatal("expected another user to have a full budget")
+	}
+	if _, allowed = q.Spend(BucketSearch, 1, 5, now.Add(time.Minute)); !allowed {
+		t.Fatal("expected the budget to refill in the next window")
+	}
+}
+
+func TestSpendWithoutLimit(t *testing.T) {
+	var disabled *Quotas
+	if usage, allowed := disabled.Spend(BucketSearch, 1, 100, time.Now()); usage != nil || !allowed {
+		t.Fatal("expected nil quotas to allow everything")
+	}
+
+	q := New(nil, []Limit{{Bucket: BucketSearch, Limit: 0}}, 0)
+	if usage, allowed := q.Spend(BucketSearch, 1, 100, time.Now()); usage != nil || !allowed {
+		t.Fatal("expected a zero limit to allow everything")
+	}
+	if usage, allowed := q.Spend("export", 1, 100, time.Now()); usage != nil || !allowed {
+		t.Fatal("expected an unknown bucket to allow everything")
+	}
+}
+
+func TestOverrideIsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		override *Override
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"without expiry", &Override{Exempt: true}, true},
+		{"before expiry", &Override{Exempt: true, ExpiresAt: &future}, true},
+		{"after expiry", &Override{Exempt: true, ExpiresAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if active := tt.override.IsActive(now); active != tt.expected {
+				t.Errorf("IsActive() = %v, want %v", active, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPerPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cost := PerPage(2, 20)
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"", 2},
+		{"limit=abc", 2},
+		{"limit=20", 2},
+		{"limit=21", 4},
+		{"limit=100", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/search?"+tt.query, nil)
+			if got := cost(c); got != tt.expected {
+				t.Errorf("cost = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMiddlewareRejectsWithQuotaHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	q := New(nil, []Limit{{Bucket: BucketSearch, Limit: 2, Window: time.Minute}}, 0)
+
+	router := gin.New()
+	router.GET("/search", func(c *gin.Context) {
+		c.Set("user_id", uint(7))
+	}, q.Middleware(BucketSearch, Fixed(2)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search", nil))
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("unexpected first response: %d %v", w.Code, w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search", nil))
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 429 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+}
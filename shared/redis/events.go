@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// UserEvents delivers real-time events to all devices of a user. Events go through Redis
+// pub/sub, so a device receives them whichever service instance serves its stream.
+// Delivery is best effort: devices that were not subscribed catch up through the service's
+// own sync API. All methods are no-ops on nil, so services can run without Redis.
+type UserEvents struct {
+	client *Client
+	prefix string
+}
+
+// NewUserEvents creates user events on channels named prefix + user ID.
+// It returns nil if client is nil.
+func NewUserEvents(client *Client, prefix string) *UserEvents {
+	if client == nil {
+		return nil
+	}
+	return &UserEvents{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Publish sends an event, encoded as JSON, to the user's subscribers
+func (e *UserEvents) Publish(userID uint, event interface{}) error {
+	if e == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode user event: %w", err)
+	}
+	if err := e.client.Client.Publish(e.client.ctx, e.channel(userID), payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish user event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe returns JSON payloads of the user's events until ctx is done, then the
+// channel is closed
+func (e *UserEvents) Subscribe(ctx context.Context, userID uint) (<-chan []byte, error) {
+	if e == nil {
+		return nil, fmt.Errorf("real-time events are not available")
+	}
+
+	pubsub := e.client.Client.Subscribe(ctx, e.channel(userID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to user events: %w", err)
+	}
+
+	events := make(chan []byte, 16)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case events <- []byte(message.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// channel returns the pub/sub channel of a user
+func (e *UserEvents) channel(userID uint) string {
+	return e.prefix + strconv.FormatUint(uint64(userID), 10)
+}
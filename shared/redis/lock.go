@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseLockScript deletes a lock only if it is still held by the same owner,
+// so an instance never releases a lock that expired and was taken by another one
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// TryLock acquires a lock held by one instance at a time until released or ttl expires.
+// ok is false if another instance holds the lock. The returned release function frees the lock.
+// On a nil client the lock is always acquired, so services can run without Redis.
+func (c *Client) TryLock(key string, ttl time.Duration) (release func(), ok bool, err error) {
+	if c == nil {
+		return func() {}, true, nil
+	}
+
+	token, err := lockToken()
+	if err != nil {
+		return nil, false, err
+	}
+
+	ok, err = c.Client.SetNX(c.ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	release = func() {
+		releaseLockScript.Run(c.ctx, c.Client, []string{key}, token)
+	}
+	return release, true, nil
+}
+
+// lockToken generates a random lock owner token
+func lockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
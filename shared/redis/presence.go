@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Presence keys. The status of a connected user expires unless the instance holding the
+// connection refreshes it, so users of a crashed instance turn offline. Last seen outlives it.
+const (
+	presenceStatusPrefix   = "presence:status:"
+	presenceLastSeenPrefix = "presence:seen:"
+
+	// DefaultPresenceTTL is how long a connected user stays online without a refresh
+	DefaultPresenceTTL = 90 * time.Second
+
+	// presenceLastSeenTTL keeps last seen of users who have not connected for a long time
+	presenceLastSeenTTL = 30 * 24 * time.Hour
+)
+
+// Presence statuses
+const (
+	PresenceOnline  = "online"
+	PresenceAway    = "away"
+	PresenceOffline = "offline"
+)
+
+// Presence is the status of a user and when the user was last seen, zero if never
+type Presence struct {
+	Status   string
+	LastSeen time.Time
+}
+
+// PresenceStore keeps presence of users connected to any instance. All methods are no-ops
+// on a nil store, so services can run without Redis.
+type PresenceStore struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewPresenceStore creates a presence store, zero ttl uses the default
+func NewPresenceStore(client *Client, ttl time.Duration) *PresenceStore {
+	if ttl <= 0 {
+		ttl = DefaultPresenceTTL
+	}
+	return &PresenceStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// SetOnline records connected users with their status, online or away. Instances call it
+// for all their users more often than the TTL.
+func (s *PresenceStore) SetOnline(presence map[uint]Presence) error {
+	if s == nil || len(presence) == 0 {
+		return nil
+	}
+
+	pipe := s.client.Client.Pipeline()
+	for userID, p := range presence {
+		pipe.Set(s.client.ctx, presenceStatusKey(userID), p.Status, s.ttl)
+		pipe.Set(s.client.ctx, presenceLastSeenKey(userID), p.LastSeen.UnixMilli(), presenceLastSeenTTL)
+	}
+	if _, err := pipe.Exec(s.client.ctx); err != nil {
+		return fmt.Errorf("failed to set presence: %w", err)
+	}
+	return nil
+}
+
+// SetOffline records a user who disconnected at lastSeen
+func (s *PresenceStore) SetOffline(userID uint, lastSeen time.Time) error {
+	if s == nil {
+		return nil
+	}
+
+	pipe := s.client.Client.Pipeline()
+	pipe.Del(s.client.ctx, presenceStatusKey(userID))
+	pipe.Set(s.client.ctx, presenceLastSeenKey(userID), lastSeen.UnixMilli(), presenceLastSeenTTL)
+	if _, err := pipe.Exec(s.client.ctx); err != nil {
+		return fmt.Errorf("failed to set presence: %w", err)
+	}
+	return nil
+}
+
+// GetPresence returns presence of every user, users without a status are offline
+func (s *PresenceStore) GetPresence(userIDs []uint) (map[uint]Presence, error) {
+	if s == nil || len(userIDs) == 0 {
+		return map[uint]Presence{}, nil
+	}
+
+	statusKeys := make([]string, len(userIDs))
+	lastSeenKeys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		statusKeys[i] = presenceStatusKey(userID)
+		lastSeenKeys[i] = presenceLastSeenKey(userID)
+	}
+
+	pipe := s.client.Client.Pipeline()
+	statuses := pipe.MGet(s.client.ctx, statusKeys...)
+	lastSeen := pipe.MGet(s.client.ctx, lastSeenKeys...)
+	if _, err := pipe.Exec(s.client.ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	presence := make(map[uint]Presence, len(userIDs))
+	for i, userID := range userIDs {
+		p := Presence{Status: PresenceOffline}
+		if status, ok := statuses.Val()[i].(string); ok && status != "" {
+			p.Status = status
+		}
+		if value, ok := lastSeen.Val()[i].(string); ok {
+			if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+				p.LastSeen = time.UnixMilli(millis)
+			}
+		}
+		presence[userID] = p
+	}
+	return presence, nil
+}
+
+func presenceStatusKey(userID uint) string {
+	return presenceStatusPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func presenceLastSeenKey(userID uint) string {
+	return presenceLastSeenPrefix + strconv.FormatUint(uint64(userID), 10)
+}
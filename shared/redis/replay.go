@@ -0,0 +1,171 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Replay buffer keys. The sequence is a plain counter per user, buffered events are
+// a sorted set of frames scored by their sequence number.
+const (
+	replaySeqPrefix    = "replay:seq:"
+	replayEventsPrefix = "replay:events:"
+
+	// DefaultReplayTTL is how long events of an idle user stay buffered
+	DefaultReplayTTL = 2 * time.Minute
+
+	// DefaultReplayMaxEvents bounds the number of buffered events per user
+	DefaultReplayMaxEvents = 500
+
+	// replaySeqTTL keeps sequences of idle users long after their events expired, so a late
+	// resume is seen as a gap instead of matching a restarted sequence
+	replaySeqTTL = 7 * 24 * time.Hour
+)
+
+// appendReplayScript assigns the next sequence number of a user and buffers the frame under it,
+// dropping the oldest events over the limit
+var appendReplayScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+redis.call('ZADD', KEYS[2], seq, ARGV[1])
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -tonumber(ARGV[4]) - 1)
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return seq
+`)
+
+// ReplayEvent is a buffered event with its sequence number
+type ReplayEvent struct {
+	Seq   uint64
+	Frame []byte
+}
+
+// ReplayBuffer keeps recent events sent to every user under a per-user sequence number,
+// until they are acknowledged or expire. All methods are no-ops on a nil buffer, so services
+// can run without Redis.
+type ReplayBuffer struct {
+	client    *Client
+	ttl       time.Duration
+	maxEvents int64
+}
+
+// NewReplayBuffer creates a replay buffer, zero ttl and maxEvents use the defaults
+func NewReplayBuffer(client *Client, ttl time.Duration, maxEvents int64) *ReplayBuffer {
+	if ttl <= 0 {
+		ttl = DefaultReplayTTL
+	}
+	if maxEvents <= 0 {
+		maxEvents = DefaultReplayMaxEvents
+	}
+	return &ReplayBuffer{
+		client:    client,
+		ttl:       ttl,
+		maxEvents: maxEvents,
+	}
+}
+
+// TTL returns how long events of an idle user stay buffered, 0 on a nil buffer
+func (b *ReplayBuffer) TTL() time.Duration {
+	if b == nil {
+		return 0
+	}
+	return b.ttl
+}
+
+// Append buffers a frame for every user and returns the sequence number assigned to each
+func (b *ReplayBuffer) Append(frame []byte, userIDs []uint) (map[uint]uint64, error) {
+	if b == nil || len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	pipe := b.client.Client.Pipeline()
+	cmds := make([]*redis.Cmd, len(userIDs))
+	for i, userID := range userIDs {
+		// Scripts can't fall back from EVALSHA inside a pipeline, so send the source
+		cmds[i] = appendReplayScript.Eval(b.client.ctx, pipe,
+			[]string{replaySeqKey(userID), replayEventsKey(userID)},
+			frame, replaySeqTTL.Milliseconds(), b.ttl.Milliseconds(), b.maxEvents)
+	}
+	if _, err := pipe.Exec(b.client.ctx); err != nil {
+		return nil, fmt.Errorf("failed to buffer replay events: %w", err)
+	}
+
+	seqs := make(map[uint]uint64, len(userIDs))
+	for i, userID := range userIDs {
+		seq, err := cmds[i].Int64()
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer replay event: %w", err)
+		}
+		seqs[userID] = uint64(seq)
+	}
+	return seqs, nil
+}
+
+// LastSeq returns the last sequence number assigned to a user, 0 if none
+func (b *ReplayBuffer) LastSeq(userID uint) (uint64, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	seq, err := b.client.Client.Get(b.client.ctx, replaySeqKey(userID)).Uint64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get replay sequence: %w", err)
+	}
+	return seq, nil
+}
+
+// Since returns the buffered events of a user after seq in order, and the last sequence number
+// assigned to the user. Events missing between seq and the first returned one already expired.
+func (b *ReplayBuffer) Since(userID uint, seq uint64) (events []ReplayEvent, lastSeq uint64, err error) {
+	if b == nil {
+		return nil, 0, nil
+	}
+
+	pipe := b.client.Client.Pipeline()
+	lastCmd := pipe.Get(b.client.ctx, replaySeqKey(userID))
+	eventsCmd := pipe.ZRangeByScoreWithScores(b.client.ctx, replayEventsKey(userID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatUint(seq, 10),
+		Max: "+inf",
+	})
+	if _, err := pipe.Exec(b.client.ctx); err != nil && err != redis.Nil {
+		return nil, 0, fmt.Errorf("failed to get replay events: %w", err)
+	}
+
+	if lastSeq, err = lastCmd.Uint64(); err != nil && err != redis.Nil {
+		return nil, 0, fmt.Errorf("failed to get replay sequence: %w", err)
+	}
+
+	members := eventsCmd.Val()
+	events = make([]ReplayEvent, 0, len(members))
+	for _, member := range members {
+		frame, _ := member.Member.(string)
+		events = append(events, ReplayEvent{Seq: uint64(member.Score), Frame: []byte(frame)})
+	}
+	return events, lastSeq, nil
+}
+
+// Ack drops the buffered events of a user up to and including seq
+func (b *ReplayBuffer) Ack(userID uint, seq uint64) error {
+	if b == nil || seq == 0 {
+		return nil
+	}
+
+	err := b.client.Client.ZRemRangeByScore(b.client.ctx, replayEventsKey(userID), "-inf", strconv.FormatUint(seq, 10)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to ack replay events: %w", err)
+	}
+	return nil
+}
+
+func replaySeqKey(userID uint) string {
+	return replaySeqPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func replayEventsKey(userID uint) string {
+	return replayEventsPrefix + strconv.FormatUint(uint64(userID), 10)
+}
@@ -0,0 +1,288 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Unread counter keys. Notification counter is a plain integer, chat counters are
+// a hash of chat ID -> unread messages for every user.
+const (
+	unreadNotificationsPrefix = "unread:notifications:"
+	unreadChatsPrefix         = "unread:chats:"
+
+	// DefaultUnreadTTL bounds how long counters of inactive users stay in Redis
+	DefaultUnreadTTL = 24 * time.Hour
+)
+
+// incrIfExistsScript changes a counter only if it is already cached, so a cold key
+// is never initialized with a partial value. Counters never go below zero.
+var incrIfExistsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if value < 0 then
+	redis.call('SET', KEYS[1], 0, 'KEEPTTL')
+	value = 0
+end
+return value
+`)
+
+// hincrIfExistsScript is incrIfExistsScript for a field of a cached hash
+var hincrIfExistsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local value = redis.call('HINCRBY', KEYS[1], ARGV[1], ARGV[2])
+if value < 0 then
+	redis.call('HSET', KEYS[1], ARGV[1], 0)
+	value = 0
+end
+return value
+`)
+
+// UnreadCounter caches per-user unread counters.
+// Counters are only adjusted while cached; a missing key means the caller must load
+// the value from the database and store it with Set*. All methods are no-ops on a nil
+// counter, so services can run without Redis.
+type UnreadCounter struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewUnreadCounter creates an unread counter cache, zero ttl uses DefaultUnreadTTL
+func NewUnreadCounter(client *Client, ttl time.Duration) *UnreadCounter {
+	if ttl <= 0 {
+		ttl = DefaultUnreadTTL
+	}
+	return &UnreadCounter{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Notification counters
+
+// GetNotifications returns cached unread notification count, ok is false on cache miss
+func (u *UnreadCounter) GetNotifications(userID uint) (count int64, ok bool, err error) {
+	if u == nil {
+		return 0, false, nil
+	}
+
+	count, err = u.client.Client.Get(u.client.ctx, notificationsKey(userID)).Int64()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get unread notifications: %w", err)
+	}
+	return count, true, nil
+}
+
+// SetNotifications caches unread notification count loaded from the database
+func (u *UnreadCounter) SetNotifications(userID uint, count int64) error {
+	if u == nil {
+		return nil
+	}
+	return u.client.Client.Set(u.client.ctx, notificationsKey(userID), count, u.ttl).Err()
+}
+
+// ReconcileNotifications overwrites a cached counter without extending its TTL
+func (u *UnreadCounter) ReconcileNotifications(userID uint, count int64) error {
+	if u == nil {
+		return nil
+	}
+	return u.client.Client.SetArgs(u.client.ctx, notificationsKey(userID), count, redis.SetArgs{KeepTTL: true}).Err()
+}
+
+// IncrNotifications adjusts cached unread notification count by delta
+func (u *UnreadCounter) IncrNotifications(userID uint, delta int64) error {
+	if u == nil || delta == 0 {
+		return nil
+	}
+	return incrIfExistsScript.Run(u.client.ctx, u.client.Client, []string{notificationsKey(userID)}, delta).Err()
+}
+
+// InvalidateNotifications drops cached notification counters
+func (u *UnreadCounter) InvalidateNotifications(userIDs ...uint) error {
+	if u == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = notificationsKey(userID)
+	}
+	return u.client.Client.Del(u.client.ctx, keys...).Err()
+}
+
+// Chat counters
+
+// GetChats returns cached unread message counts by chat ID, ok is false on cache miss
+func (u *UnreadCounter) GetChats(userID uint) (counts map[uint]int64, ok bool, err error) {
+	if u == nil {
+		return nil, false, nil
+	}
+
+	values, err := u.client.Client.HGetAll(u.client.ctx, chatsKey(userID)).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get unread chats: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+
+	counts = make(map[uint]int64, len(values))
+	for field, value := range values {
+		chatID, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[uint(chatID)] = count
+	}
+	return counts, true, nil
+}
+
+// SetChats caches unread counts of all user's chats loaded from the database.
+// Users without chats are not cached.
+func (u *UnreadCounter) SetChats(userID uint, counts map[uint]int64) error {
+	if u == nil || len(counts) == 0 {
+		return nil
+	}
+
+	key := chatsKey(userID)
+	_, err := u.client.Client.TxPipelined(u.client.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(u.client.ctx, key)
+		pipe.HSet(u.client.ctx, key, chatCountsArgs(counts)...)
+		pipe.Expire(u.client.ctx, key, u.ttl)
+		return nil
+	})
+	return err
+}
+
+// ReconcileChats overwrites cached chat counters without extending their TTL
+func (u *UnreadCounter) ReconcileChats(userID uint, counts map[uint]int64) error {
+	if u == nil {
+		return nil
+	}
+
+	key := chatsKey(userID)
+	ttl, err := u.client.Client.PTTL(u.client.ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get unread chats ttl: %w", err)
+	}
+	if ttl <= 0 || len(counts) == 0 {
+		// Expired in the meantime or user has no chats anymore
+		return u.client.Client.Del(u.client.ctx, key).Err()
+	}
+
+	_, err = u.client.Client.TxPipelined(u.client.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(u.client.ctx, key)
+		pipe.HSet(u.client.ctx, key, chatCountsArgs(counts)...)
+		pipe.PExpire(u.client.ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+// IncrChat adjusts cached unread count of a chat by delta for every user.
+// A chat missing from a cached hash starts from zero (user joined after caching).
+func (u *UnreadCounter) IncrChat(chatID uint, delta int64, userIDs ...uint) error {
+	if u == nil || delta == 0 || len(userIDs) == 0 {
+		return nil
+	}
+
+	field := strconv.FormatUint(uint64(chatID), 10)
+	pipe := u.client.Client.Pipeline()
+	for _, userID := range userIDs {
+		hincrIfExistsScript.Eval(u.client.ctx, pipe, []string{chatsKey(userID)}, field, delta)
+	}
+	_, err := pipe.Exec(u.client.ctx)
+	return err
+}
+
+// RemoveChat drops a chat from cached counters of users who left it
+func (u *UnreadCounter) RemoveChat(chatID uint, userIDs ...uint) error {
+	if u == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	field := strconv.FormatUint(uint64(chatID), 10)
+	pipe := u.client.Client.Pipeline()
+	for _, userID := range userIDs {
+		pipe.HDel(u.client.ctx, chatsKey(userID), field)
+	}
+	_, err := pipe.Exec(u.client.ctx)
+	return err
+}
+
+// InvalidateChats drops cached chat counters
+func (u *UnreadCounter) InvalidateChats(userIDs ...uint) error {
+	if u == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = chatsKey(userID)
+	}
+	return u.client.Client.Del(u.client.ctx, keys...).Err()
+}
+
+// Reconciliation helpers
+
+// CachedNotificationUsers returns users with a cached notification counter
+func (u *UnreadCounter) CachedNotificationUsers() ([]uint, error) {
+	return u.cachedUsers(unreadNotificationsPrefix)
+}
+
+// CachedChatUsers returns users with cached chat counters
+func (u *UnreadCounter) CachedChatUsers() ([]uint, error) {
+	return u.cachedUsers(unreadChatsPrefix)
+}
+
+// cachedUsers scans keys with prefix and extracts user IDs
+func (u *UnreadCounter) cachedUsers(prefix string) ([]uint, error) {
+	if u == nil {
+		return nil, nil
+	}
+
+	var userIDs []uint
+	iter := u.client.Client.Scan(u.client.ctx, 0, prefix+"*", 500).Iterator()
+	for iter.Next(u.client.ctx) {
+		userID, err := strconv.ParseUint(strings.TrimPrefix(iter.Val(), prefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, uint(userID))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan unread counters: %w", err)
+	}
+	return userIDs, nil
+}
+
+func notificationsKey(userID uint) string {
+	return unreadNotificationsPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func chatsKey(userID uint) string {
+	return unreadChatsPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func chatCountsArgs(counts map[uint]int64) []interface{} {
+	args := make([]interface{}, 0, len(counts)*2)
+	for chatID, count := range counts {
+		args = append(args, strconv.FormatUint(uint64(chatID), 10), count)
+	}
+	return args
+}
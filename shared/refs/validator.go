@@ -0,0 +1,287 @@
+package refs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+)
+
+// DefaultCacheTTL is how long an ID confirmed by its owning service is trusted without asking again
+const DefaultCacheTTL = 10 * time.Minute
+
+// MaxBatchSize is the largest number of IDs sent to an owning service in one request
+const MaxBatchSize = 500
+
+// maxCachedIDs bounds the cache of one kind, expired entries are dropped when it is exceeded
+const maxCachedIDs = 10000
+
+// Kind is a kind of entity referenced by ID across services
+type Kind string
+
+const (
+	KindUser Kind = "user"
+)
+
+// Mode is the strictness of reference validation
+type Mode string
+
+const (
+	// ModeOff disables validation
+	ModeOff Mode = "off"
+	// ModeWarn logs unknown IDs but accepts them, for rolling out validation on existing data
+	ModeWarn Mode = "warn"
+	// ModeStrict rejects unknown IDs
+	ModeStrict Mode = "strict"
+)
+
+// ParseMode parses a validation mode, empty or unknown values give ModeStrict
+func ParseMode(value string) Mode {
+	switch Mode(strings.ToLower(strings.TrimSpace(value))) {
+	case ModeOff:
+		return ModeOff
+	case ModeWarn:
+		return ModeWarn
+	default:
+		return ModeStrict
+	}
+}
+
+// MissingError is returned when referenced IDs do not exist in their owning service
+type MissingError struct {
+	Kind Kind
+	IDs  []uint
+}
+
+// Error implements error
+func (e *MissingError) Error() string {
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return fmt.Sprintf("validation failed: unknown %s IDs: %s", e.Kind, strings.Join(ids, ", "))
+}
+
+// Validator verifies that IDs stored by a service exist in the services owning them, so services
+// do not keep dangling references. IDs are checked in batches through the internal endpoint
+// of the owning service, and confirmed IDs are cached. If an owning service is unavailable
+// the IDs are accepted and a warning is logged, validation must not take services down.
+// All methods work on a nil validator and accept every ID.
+type Validator struct {
+	mode       Mode
+	ttl        time.Duration
+	endpoints  map[Kind]string
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	known map[Kind]map[uint]time.Time // Confirmed IDs and when they expire
+}
+
+// NewValidator creates a validator checking IDs of each kind against the endpoint of its owning
+// service, zero ttl uses DefaultCacheTTL. It returns nil if mode is ModeOff or no endpoints are given.
+func NewValidator(mode Mode, endpoints map[Kind]string, ttl time.Duration) *Validator {
+	configured := make(map[Kind]string)
+	for kind, endpoint := range endpoints {
+		if endpoint != "" {
+			configured[kind] = endpoint
+		}
+	}
+	if mode == ModeOff || len(configured) == 0 {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Validator{
+		mode:       mode,
+		ttl:        ttl,
+		endpoints:  configured,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		known:      make(map[Kind]map[uint]time.Time),
+	}
+}
+
+// NewUserValidator creates a validator for user IDs owned by the user service at baseURL.
+// It returns nil if baseURL is empty or mode is ModeOff.
+func NewUserValidator(baseURL string, mode Mode, ttl time.Duration) *Validator {
+	if baseURL == "" {
+		return nil
+	}
+	return NewValidator(mode, map[Kind]string{
+		KindUser: strings.TrimRight(baseURL, "/") + "/api/v1/internal/users/exists",
+	}, ttl)
+}
+
+// NewUserValidatorFromEnv creates a user ID validator for the user service at baseURL
+// in the mode from REFERENCE_VALIDATION (off, warn or strict, strict by default)
+func NewUserValidatorFromEnv(baseURL string) *Validator {
+	return NewUserValidator(baseURL, ParseMode(os.Getenv("REFERENCE_VALIDATION")), 0)
+}
+
+// Mode returns the strictness of the validator
+func (v *Validator) Mode() Mode {
+	if v == nil {
+		return ModeOff
+	}
+	return v.mode
+}
+
+// CheckUsers verifies that users exist, see Check
+func (v *Validator) CheckUsers(ids ...uint) error {
+	return v.Check(KindUser, ids...)
+}
+
+// Check verifies that IDs of a kind exist in the owning service. In strict mode it returns
+// a *MissingError listing unknown IDs, in warn mode unknown IDs are only logged.
+// Zero IDs and kinds without an endpoint are not checked.
+func (v *Validator) Check(kind Kind, ids ...uint) error {
+	if v == nil {
+		return nil
+	}
+	endpoint, ok := v.endpoints[kind]
+	if !ok {
+		return nil
+	}
+
+	unknown := v.uncached(kind, ids)
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	var missing []uint
+	for start := 0; start < len(unknown); start += MaxBatchSize {
+		end := start + MaxBatchSize
+		if end > len(unknown) {
+			end = len(unknown)
+		}
+		batch := unknown[start:end]
+
+		existing, err := v.fetch(endpoint, batch)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"kind":  kind,
+				"ids":   batch,
+				"error": err.Error(),
+			}).Warn("Failed to validate references, accepting them unverified")
+			continue
+		}
+
+		v.remember(kind, existing)
+		missing = append(missing, difference(batch, existing)...)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if v.mode == ModeWarn {
+		logger.WithFields(map[string]interface{}{
+			"kind": kind,
+			"ids":  missing,
+		}).Warn("Unknown references accepted")
+		return nil
+	}
+	return &MissingError{Kind: kind, IDs: missing}
+}
+
+// uncached returns distinct non-zero IDs that are not confirmed in the cache, in ascending order
+func (v *Validator) uncached(kind Kind, ids []uint) []uint {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	seen := make(map[uint]bool, len(ids))
+	var result []uint
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if expiresAt, ok := v.known[kind][id]; ok && now.Before(expiresAt) {
+			continue
+		}
+		result = append(result, id)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// remember caches IDs confirmed by the owning service
+func (v *Validator) remember(kind Kind, ids []uint) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	known := v.known[kind]
+	if known == nil {
+		known = make(map[uint]time.Time)
+		v.known[kind] = known
+	}
+
+	now := time.Now()
+	if len(known)+len(ids) > maxCachedIDs {
+		for id, expiresAt := range known {
+			if !now.Before(expiresAt) {
+				delete(known, id)
+			}
+		}
+	}
+
+	expiresAt := now.Add(v.ttl)
+	for _, id := range ids {
+		if len(known) >= maxCachedIDs {
+			break
+		}
+		known[id] = expiresAt
+	}
+}
+
+// fetch asks the owning service which of the IDs exist
+func (v *Validator) fetch(endpoint string, ids []uint) ([]uint, error) {
+	body, err := json.Marshal(map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode IDs: %w", err)
+	}
+
+	resp, err := v.httpClient.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to request references: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("owning service responded with status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Existing []uint `json:"existing"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode references: %w", err)
+	}
+
+	return payload.Existing, nil
+}
+
+// difference returns IDs of requested that are not in existing
+func difference(requested, existing []uint) []uint {
+	found := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+
+	var missing []uint
+	for _, id := range requested {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
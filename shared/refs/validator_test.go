@@ -0,0 +1,124 @@
+package refs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newUserService serves the exists endpoint for users 1 to 10
+func newUserService(t *testing.T, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/internal/users/exists" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req struct {
+			IDs []uint `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		existing := []uint{}
+		for _, id := range req.IDs {
+			if id <= 10 {
+				existing = append(existing, id)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"existing": existing})
+	}))
+}
+
+func TestCheckRejectsUnknownIDs(t *testing.T) {
+	var requests int32
+	server := newUserService(t, &requests)
+	defer server.Close()
+
+	validator := NewUserValidator(server.URL, ModeStrict, time.Minute)
+
+	if err := validator.CheckUsers(1, 2, 2, 0); err != nil {
+		t.Fatalf("expected existing users accepted, got %v", err)
+	}
+
+	err := validator.CheckUsers(3, 12, 11)
+	var missing *MissingError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected MissingError, got %v", err)
+	}
+	if !reflect.DeepEqual(missing.IDs, []uint{11, 12}) {
+		t.Fatalf("expected 11 and 12 missing, got %v", missing.IDs)
+	}
+	if err.Error() != "validation failed: unknown user IDs: 11, 12" {
+		t.Fatalf("unexpected error message %q", err.Error())
+	}
+}
+
+func TestCheckCachesExistingIDs(t *testing.T) {
+	var requests int32
+	server := newUserService(t, &requests)
+	defer server.Close()
+
+	validator := NewUserValidator(server.URL, ModeStrict, time.Minute)
+	validator.CheckUsers(1, 2)
+	validator.CheckUsers(2, 1)
+	if requests != 1 {
+		t.Fatalf("expected confirmed users cached, got %d requests", requests)
+	}
+
+	// Unknown IDs are asked again, the user may have been created meanwhile
+	validator.CheckUsers(20)
+	validator.CheckUsers(20)
+	if requests != 3 {
+		t.Fatalf("expected unknown users requested each time, got %d requests", requests)
+	}
+}
+
+func TestCheckModes(t *testing.T) {
+	var requests int32
+	server := newUserService(t, &requests)
+	defer server.Close()
+
+	if err := NewUserValidator(server.URL, ModeWarn, 0).CheckUsers(50); err != nil {
+		t.Fatalf("expected warn mode to accept unknown users, got %v", err)
+	}
+	if validator := NewUserValidator(server.URL, ModeOff, 0); validator != nil {
+		t.Fatal("expected no validator in off mode")
+	}
+	if mode := ParseMode(""); mode != ModeStrict {
+		t.Fatalf("expected strict mode by default, got %s", mode)
+	}
+	if mode := ParseMode(" Warn "); mode != ModeWarn {
+		t.Fatalf("expected warn mode, got %s", mode)
+	}
+}
+
+func TestCheckAcceptsWhenServiceUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := NewUserValidator(server.URL, ModeStrict, 0).CheckUsers(50); err != nil {
+		t.Fatalf("expected unverified users accepted, got %v", err)
+	}
+}
+
+func TestNilValidator(t *testing.T) {
+	var validator *Validator
+	if err := validator.CheckUsers(1, 2); err != nil {
+		t.Fatalf("expected nil validator to accept users, got %v", err)
+	}
+	if validator.Mode() != ModeOff {
+		t.Fatalf("expected nil validator to be off, got %s", validator.Mode())
+	}
+	if NewUserValidator("", ModeStrict, 0) != nil {
+		t.Fatal("expected no validator without user service URL")
+	}
+}
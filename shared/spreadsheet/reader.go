@@ -0,0 +1,409 @@
+// Package spreadsheet reads and writes tables as CSV or XLSX files. XLSX support covers what
+// imports and exports need: the first worksheet, text and number cells, no styles or formulas.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a spreadsheet file format
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ContentType returns the MIME type of files of the format
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ParseFormat parses a format name, CSV by default
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported spreadsheet format %q, expected csv or xlsx", name)
+	}
+}
+
+// ErrTooManyRows is returned when a file has more data rows than allowed
+var ErrTooManyRows = errors.New("too many rows")
+
+// ErrTooLarge is returned when a file would take more memory than a spreadsheet may: cells past
+// the last XLSX column or row, too many cells in total, or oversized parts of an XLSX archive
+var ErrTooLarge = errors.New("spreadsheet is too large")
+
+// Limits of XLSX files, checked while reading, so a small crafted file can't exhaust memory
+const (
+	maxColumns           = 16384    // Last XLSX column, XFD
+	maxRows              = 1048576  // Last XLSX row
+	maxCells             = 4 << 20  // Cells of all rows, empty cells padding skipped ones included
+	maxPartSize          = 64 << 20 // Decompressed size of a worksheet or the shared string table
+	maxSharedStringBytes = 32 << 20 // Text of all shared strings
+)
+
+// utf8BOM is written by Excel at the start of UTF-8 CSV files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// DetectFormat tells the format of a file from its content, which wins over the file name
+func DetectFormat(filename string, data []byte) Format {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return FormatXLSX
+	}
+	if strings.EqualFold(path.Ext(filename), ".xlsx") && len(data) == 0 {
+		return FormatXLSX
+	}
+	return FormatCSV
+}
+
+// Read returns rows of a CSV file or of the first worksheet of an XLSX file. Trailing empty rows
+// are dropped and short rows are not padded. Files with more than maxRows rows, header included,
+// fail with ErrTooManyRows; maxRows 0 reads everything.
+func Read(data []byte, format Format, maxRows int) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch format {
+	case FormatXLSX:
+		rows, err = readXLSX(data, maxRows)
+	default:
+		rows, err = readCSV(data, maxRows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for len(rows) > 0 && isEmptyRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, nil
+}
+
+// readCSV reads a CSV file separated by commas, semicolons or tabs, whichever the header uses most
+func readCSV(data []byte, maxRows int) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	header := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		header = data[:i]
+	}
+	delimiter := ','
+	for _, candidate := range []rune{';', '\t'} {
+		if bytes.Count(header, []byte(string(candidate))) > bytes.Count(header, []byte(string(delimiter))) {
+			delimiter = candidate
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %w", err)
+		}
+		if maxRows > 0 && len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		rows = append(rows, record)
+	}
+}
+
+// readXLSX reads cells of the first worksheet of an XLSX file
+func readXLSX(data []byte, maxRows int) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX file: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	var sheets []string
+	for _, file := range archive.File {
+		files[file.Name] = file
+		if strings.HasPrefix(file.Name, "xl/worksheets/") && strings.HasSuffix(file.Name, ".xml") {
+			sheets = append(sheets, file.Name)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("invalid XLSX file: no worksheets")
+	}
+	sheet := "xl/worksheets/sheet1.xml"
+	if files[sheet] == nil {
+		sort.Strings(sheets)
+		sheet = sheets[0]
+	}
+
+	var sharedStrings []string
+	if file := files["xl/sharedStrings.xml"]; file != nil {
+		if sharedStrings, err = readSharedStrings(file); err != nil {
+			return nil, err
+		}
+	}
+
+	return readWorksheet(files[sheet], sharedStrings, maxRows)
+}
+
+// xlsxText is a text element of a shared or inline string, plain or split in rich text runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String joins the text of all runs
+func (t *xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var text strings.Builder
+	for _, run := range t.Runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
+
+// readSharedStrings reads the shared string table cells of type "s" refer to
+func readSharedStrings(file *zip.File) ([]string, error) {
+	reader, err := openPart(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var strs []string
+	size := 0
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return strs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX shared strings: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "si" {
+			continue
+		}
+		var item xlsxText
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return nil, fmt.Errorf("invalid XLSX shared strings: %w", err)
+		}
+
+		text := item.String()
+		if size += len(text); size > maxSharedStringBytes || len(strs) >= maxCells {
+			return nil, ErrTooLarge
+		}
+		strs = append(strs, text)
+	}
+}
+
+// xlsxCell is a cell of a worksheet row
+type xlsxCell struct {
+	Ref    string    `xml:"r,attr"`
+	Type   string    `xml:"t,attr"`
+	Value  string    `xml:"v"`
+	Inline *xlsxText `xml:"is"`
+}
+
+// readWorksheet reads rows of a worksheet, placing cells by their reference so skipped empty
+// cells and rows keep the columns aligned
+func readWorksheet(file *zip.File, sharedStrings []string, rowLimit int) ([][]string, error) {
+	reader, err := openPart(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var rows [][]string
+	cells := 0
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX worksheet: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		var row struct {
+			Index int        `xml:"r,attr"`
+			Cells []xlsxCell `xml:"c"`
+		}
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return nil, fmt.Errorf("invalid XLSX worksheet: %w", err)
+		}
+
+		index := len(rows)
+		if row.Index > 0 {
+			index = row.Index - 1
+		}
+		if rowLimit > 0 && index >= rowLimit {
+			if !isEmptyCells(row.Cells) {
+				return nil, ErrTooManyRows
+			}
+			continue
+		}
+		if index >= maxRows {
+			return nil, ErrTooLarge
+		}
+		for len(rows) <= index {
+			rows = append(rows, nil)
+		}
+
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			if column < 0 {
+				return nil, fmt.Errorf("invalid XLSX worksheet: invalid cell reference %q", cell.Ref)
+			}
+			if column >= maxColumns {
+				return nil, ErrTooLarge
+			}
+			if missing := column + 1 - len(rows[index]); missing > 0 {
+				if cells += missing; cells > maxCells {
+					return nil, ErrTooLarge
+				}
+				rows[index] = append(rows[index], make([]string, missing)...)
+			}
+			rows[index][column] = cellValue(&cell, sharedStrings)
+		}
+	}
+}
+
+// cellValue returns the text of a cell
+func cellValue(cell *xlsxCell, sharedStrings []string) string {
+	switch cell.Type {
+	case "s":
+		i, err := strconv.Atoi(cell.Value)
+		if err != nil || i < 0 || i >= len(sharedStrings) {
+			return ""
+		}
+		return sharedStrings[i]
+	case "inlineStr":
+		if cell.Inline == nil {
+			return ""
+		}
+		return cell.Inline.String()
+	case "b":
+		if cell.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return cell.Value
+	}
+}
+
+// columnIndex returns the zero-based column of a cell reference such as "AB12", -1 if it has no
+// column. Columns past the last XLSX column are returned as maxColumns.
+func columnIndex(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		if column = column*26 + int(r-'A'+1); column > maxColumns {
+			return maxColumns
+		}
+	}
+	return column - 1
+}
+
+// limitedPart reads a part of an XLSX archive, failing with ErrTooLarge once more than
+// maxPartSize bytes are decompressed
+type limitedPart struct {
+	io.ReadCloser
+	left int64
+}
+
+// openPart opens a part of an XLSX archive for reading through a size limit
+func openPart(file *zip.File) (io.ReadCloser, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	return &limitedPart{ReadCloser: reader, left: maxPartSize}, nil
+}
+
+// Read reads decompressed bytes of the part until the limit is reached
+func (p *limitedPart) Read(b []byte) (int, error) {
+	if p.left <= 0 {
+		return 0, ErrTooLarge
+	}
+	if int64(len(b)) > p.left {
+		b = b[:p.left]
+	}
+	n, err := p.ReadCloser.Read(b)
+	p.left -= int64(n)
+	return n, err
+}
+
+// isEmptyCells checks if no cell of an XLSX row has a value
+func isEmptyCells(cells []xlsxCell) bool {
+	for _, cell := range cells {
+		if cell.Value != "" || (cell.Inline != nil && cell.Inline.String() != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyRow checks if all cells of a row are blank
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// excelEpoch is day zero of Excel serial dates, shifted for the 1900 leap year bug
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// ExcelDate converts an Excel serial date, how XLSX stores date cells, to a UTC time.
+// It returns false if the value is not a serial date between 1900 and 2200.
+func ExcelDate(value string) (time.Time, bool) {
+	serial, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || serial < 1 || serial > 110000 {
+		return time.Time{}, false
+	}
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	return excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second), true
+}
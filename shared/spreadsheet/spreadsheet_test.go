@@ -0,0 +1,170 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSVDetectsDelimiter(t *testing.T) {
+	data := []byte("\xEF\xBB\xBFTitle;Priority\n\"Fix; login\";high\nDocs;low\n\n")
+
+	rows, err := Read(data, DetectFormat("tasks.csv", data), 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	want := [][]string{{"Title", "Priority"}, {"Fix; login", "high"}, {"Docs", "low"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestReadLimitsRows(t *testing.T) {
+	data := []byte("title\na\nb\nc\n")
+
+	if _, err := Read(data, FormatCSV, 3); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("err = %v, want ErrTooManyRows", err)
+	}
+	if _, err := Read(data, FormatCSV, 4); err != nil {
+		t.Errorf("Read failed: %v", err)
+	}
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"Title", "Estimate", "Code", "Note"},
+		{"Ship <v2> & celebrate", "2.5", "007", ""},
+		{"Задача", "", "", "last"},
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, FormatXLSX, "Tasks")
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow failed: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data := buf.Bytes()
+	if format := DetectFormat("export.bin", data); format != FormatXLSX {
+		t.Fatalf("format = %s, want xlsx", format)
+	}
+	got, err := Read(data, FormatXLSX, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	want := [][]string{
+		{"Title", "Estimate", "Code", "Note"},
+		{"Ship <v2> & celebrate", "2.5", "007"},
+		{"Задача", "", "", "last"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestXLSXRejectsHostileFiles(t *testing.T) {
+	xlsx := func(sheet string, sharedStrings string) []byte {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		parts := map[string]string{"xl/worksheets/sheet1.xml": sheet}
+		if sharedStrings != "" {
+			parts["xl/sharedStrings.xml"] = sharedStrings
+		}
+		for name, content := range parts {
+			w, err := archive.Create(name)
+			if err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			w.Write([]byte(content))
+		}
+		if err := archive.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		return buf.Bytes()
+	}
+	sheet := func(rows string) string {
+		return `<worksheet><sheetData>` + rows + `</sheetData></worksheet>`
+	}
+	longText := `<si><t>` + strings.Repeat("x", 1<<20) + `</t></si>`
+
+	cases := map[string][]byte{
+		"column past XFD":         xlsx(sheet(`<row r="1"><c r="ZZZZZZ1" t="inlineStr"><is><t>x</t></is></c></row>`), ""),
+		"overflowing column":      xlsx(sheet(`<row r="1"><c r="`+strings.Repeat("Z", 40)+`1"><v>1</v></c></row>`), ""),
+		"row past the last row":   xlsx(sheet(`<row r="2000000"><c r="A2000000"><v>1</v></c></row>`), ""),
+		"too many cells":          xlsx(sheet(strings.Repeat(`<row><c r="XFD1"><v>1</v></c></row>`, maxCells/maxColumns+1)), ""),
+		"too many shared strings": xlsx(sheet(""), `<sst>`+strings.Repeat(longText, maxSharedStringBytes>>20+1)+`</sst>`),
+	}
+	for name, data := range cases {
+		if _, err := Read(data, FormatXLSX, 0); !errors.Is(err, ErrTooLarge) {
+			t.Errorf("%s: err = %v, want ErrTooLarge", name, err)
+		}
+	}
+
+	data := xlsx(sheet(`<row r="1"><c r="12"><v>1</v></c></row>`), "")
+	if _, err := Read(data, FormatXLSX, 0); err == nil {
+		t.Error("expected an error for a cell reference without a column")
+	}
+}
+
+func TestCSVWriterWritesBOM(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, FormatCSV, "")
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	writer.WriteRow([]string{"a", "b,c"})
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got, want := buf.String(), "\xEF\xBB\xBFa,\"b,c\"\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != name {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, name)
+		}
+		if got := columnIndex(name + "12"); got != index {
+			t.Errorf("columnIndex(%s12) = %d, want %d", name, got, index)
+		}
+	}
+}
+
+func TestExcelDate(t *testing.T) {
+	date, ok := ExcelDate("45292.5")
+	if !ok {
+		t.Fatal("ExcelDate rejected a serial date")
+	}
+	if want := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC); !date.Equal(want) {
+		t.Errorf("date = %v, want %v", date, want)
+	}
+	if _, ok := ExcelDate("2024-01-01"); ok {
+		t.Error("ExcelDate accepted a text date")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(""); err != nil || format != FormatCSV {
+		t.Errorf("ParseFormat(\"\") = %s, %v", format, err)
+	}
+	if format, err := ParseFormat("XLSX"); err != nil || format != FormatXLSX {
+		t.Errorf("ParseFormat(XLSX) = %s, %v", format, err)
+	}
+	if _, err := ParseFormat("ods"); err == nil {
+		t.Error("ParseFormat accepted ods")
+	}
+}
@@ -0,0 +1,176 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// Writer writes rows of a table to a file as they come, so large exports can be streamed
+type Writer interface {
+	// WriteRow writes one row of cells
+	WriteRow(cells []string) error
+	// Close finishes the file, it doesn't close the underlying writer
+	Close() error
+}
+
+// NewWriter creates a writer of the format. CSV files start with a UTF-8 BOM so that Excel
+// opens non-Latin text correctly.
+func NewWriter(w io.Writer, format Format, sheetName string) (Writer, error) {
+	if format == FormatXLSX {
+		return newXLSXWriter(w, sheetName)
+	}
+	if _, err := w.Write(utf8BOM); err != nil {
+		return nil, fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}, nil
+}
+
+// csvWriter writes CSV files
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// WriteRow writes a CSV record
+func (w *csvWriter) WriteRow(cells []string) error {
+	if err := w.writer.Write(cells); err != nil {
+		return fmt.Errorf("failed to write CSV row: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered records
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	return nil
+}
+
+// Static parts of XLSX files written by xlsxWriter
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// numberCell matches cells written as numbers, values like "007" stay text
+var numberCell = regexp.MustCompile(`^-?(0|[1-9][0-9]{0,14})(\.[0-9]+)?$`)
+
+// xlsxWriter writes a single worksheet XLSX file with inline string cells, streaming rows into
+// the worksheet entry of the archive
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+// newXLSXWriter writes the workbook parts and opens the worksheet
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapeXML(sheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write XLSX file: %w", err)
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write XLSX file: %w", err)
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write XLSX file: %w", err)
+	}
+	writer := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(sheet)}
+	if _, err := writer.sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, fmt.Errorf("failed to write XLSX file: %w", err)
+	}
+	return writer, nil
+}
+
+// WriteRow writes a worksheet row, numbers as number cells and the rest as inline strings
+func (w *xlsxWriter) WriteRow(cells []string) error {
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for i, cell := range cells {
+		ref := columnName(i) + fmt.Sprint(w.rows)
+		switch {
+		case cell == "":
+			continue
+		case numberCell.MatchString(cell):
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, cell)
+		default:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escapeXML(cell))
+		}
+	}
+	if _, err := w.sheet.WriteString(`</row>`); err != nil {
+		return fmt.Errorf("failed to write XLSX row: %w", err)
+	}
+	return nil
+}
+
+// Close finishes the worksheet and the archive
+func (w *xlsxWriter) Close() error {
+	if _, err := w.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return fmt.Errorf("failed to write XLSX file: %w", err)
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return fmt.Errorf("failed to write XLSX file: %w", err)
+	}
+	if err := w.archive.Close(); err != nil {
+		return fmt.Errorf("failed to write XLSX file: %w", err)
+	}
+	return nil
+}
+
+// columnName returns the letters of a zero-based column, e.g. 27 is "AB"
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// escapeXML escapes text for XML content and attributes, dropping characters XML can't hold
+func escapeXML(text string) string {
+	var escaped stringWriter
+	xml.EscapeText(&escaped, []byte(text))
+	return string(escaped)
+}
+
+// stringWriter collects written bytes
+type stringWriter []byte
+
+// Write appends p
+func (w *stringWriter) Write(p []byte) (int, error) {
+	*w = append(*w, p...)
+	return len(p), nil
+}
@@ -0,0 +1,321 @@
+package switches
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Redis keys shared by the gateway and all services
+const (
+	maintenanceKey = "switches:maintenance"
+	killSwitchKey  = "switches:kill" // Hash of switch name -> KillSwitch JSON
+)
+
+const (
+	// DefaultCacheTTL is how long a store serves switches without reading Redis
+	DefaultCacheTTL = 10 * time.Second
+
+	// DefaultRetryAfter is the retry hint in seconds of maintenance without an end time
+	DefaultRetryAfter = 300
+)
+
+// Kill switches. An active switch turns the feature off on all instances.
+const (
+	DisableEmailSending       = "disable_email_sending"
+	DisableFileUploads        = "disable_file_uploads"
+	HoldOutboundNotifications = "hold_outbound_notifications"
+)
+
+// Definition describes a known kill switch
+type Definition struct {
+	Name        string `json:"name"`
+	Service     string `json:"service"`
+	Description string `json:"description"`
+}
+
+// definitions lists kill switches that can be activated
+var definitions = []Definition{
+	{Name: DisableEmailSending, Service: "notification-service", Description: "Stop sending email notifications"},
+	{Name: DisableFileUploads, Service: "file-service", Description: "Reject new file uploads"},
+	{Name: HoldOutboundNotifications, Service: "notification-service", Description: "Store notifications but hold email, push and SMS deliveries until released"},
+}
+
+var (
+	ErrUnavailable   = errors.New("switch storage is unavailable")
+	ErrUnknownSwitch = errors.New("unknown kill switch")
+)
+
+// Definitions returns all known kill switches
+func Definitions() []Definition {
+	return append([]Definition(nil), definitions...)
+}
+
+// Lookup returns the definition of a kill switch
+func Lookup(name string) (Definition, bool) {
+	for _, definition := range definitions {
+		if definition.Name == name {
+			return definition, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Maintenance describes maintenance mode. While active the gateway rejects non-admin traffic.
+type Maintenance struct {
+	Enabled    bool       `json:"enabled"`
+	Message    string     `json:"message,omitempty"`
+	RetryAfter int        `json:"retry_after,omitempty"` // Seconds, used when EndsAt is not set
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	StartedBy  uint       `json:"started_by"`
+}
+
+// IsActive checks if maintenance is on at the given time. Maintenance with an end time turns off by itself.
+func (m *Maintenance) IsActive(now time.Time) bool {
+	if m == nil || !m.Enabled {
+		return false
+	}
+	return m.EndsAt == nil || now.Before(*m.EndsAt)
+}
+
+// RetryAfterSeconds returns when clients should retry: the time left until EndsAt,
+// otherwise RetryAfter or DefaultRetryAfter
+func (m *Maintenance) RetryAfterSeconds(now time.Time) int {
+	if m.EndsAt != nil {
+		if seconds := int(m.EndsAt.Sub(now).Seconds()); seconds > 0 {
+			return seconds
+		}
+		return 1
+	}
+	if m.RetryAfter > 0 {
+		return m.RetryAfter
+	}
+	return DefaultRetryAfter
+}
+
+// KillSwitch is an active kill switch
+type KillSwitch struct {
+	Definition
+	Reason      string    `json:"reason,omitempty"`
+	ActivatedAt time.Time `json:"activated_at"`
+	ActivatedBy uint      `json:"activated_by"`
+}
+
+// state holds maintenance mode and active kill switches
+type state struct {
+	maintenance  *Maintenance
+	killSwitches map[string]*KillSwitch
+}
+
+// Store reads and changes switches in Redis. Reads are cached for the store TTL, so
+// a change reaches other instances within that time. All methods are safe on a nil
+// store, which never reports maintenance or active switches, so services can run without Redis.
+type Store struct {
+	client *redis.Client
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	state     *state
+	fetchedAt time.Time
+}
+
+// NewStore creates a switch store, nil client returns nil, zero ttl uses DefaultCacheTTL
+func NewStore(client *redis.Client, ttl time.Duration) *Store {
+	if client == nil {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Store{client: client, ttl: ttl}
+}
+
+// Maintenance returns maintenance mode if it is active, otherwise nil
+func (s *Store) Maintenance() *Maintenance {
+	current := s.get()
+	if current == nil || !current.maintenance.IsActive(time.Now()) {
+		return nil
+	}
+	return current.maintenance
+}
+
+// IsActive checks if a kill switch is active
+func (s *Store) IsActive(name string) bool {
+	current := s.get()
+	if current == nil {
+		return false
+	}
+	_, ok := current.killSwitches[name]
+	return ok
+}
+
+// Active returns active kill switches, optionally only those of the given service
+func (s *Store) Active(service string) []*KillSwitch {
+	current := s.get()
+	if current == nil {
+		return nil
+	}
+
+	active := make([]*KillSwitch, 0, len(current.killSwitches))
+	for _, killSwitch := range current.killSwitches {
+		if service == "" || killSwitch.Service == service {
+			active = append(active, killSwitch)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Name < active[j].Name })
+	return active
+}
+
+// SetMaintenance turns maintenance mode on
+func (s *Store) SetMaintenance(maintenance *Maintenance) error {
+	if s == nil {
+		return ErrUnavailable
+	}
+
+	data, err := json.Marshal(maintenance)
+	if err != nil {
+		return fmt.Errorf("failed to encode maintenance: %w", err)
+	}
+	if err := s.client.Client.Set(context.Background(), maintenanceKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save maintenance: %w", err)
+	}
+
+	s.Invalidate()
+	return nil
+}
+
+// ClearMaintenance turns maintenance mode off
+func (s *Store) ClearMaintenance() error {
+	if s == nil {
+		return ErrUnavailable
+	}
+	if err := s.client.Client.Del(context.Background(), maintenanceKey).Err(); err != nil {
+		return fmt.Errorf("failed to clear maintenance: %w", err)
+	}
+
+	s.Invalidate()
+	return nil
+}
+
+// Activate turns a kill switch on
+func (s *Store) Activate(name, reason string, activatedBy uint) (*KillSwitch, error) {
+	if s == nil {
+		return nil, ErrUnavailable
+	}
+	definition, ok := Lookup(name)
+	if !ok {
+		return nil, ErrUnknownSwitch
+	}
+
+	killSwitch := &KillSwitch{
+		Definition:  definition,
+		Reason:      reason,
+		ActivatedAt: time.Now().UTC(),
+		ActivatedBy: activatedBy,
+	}
+	data, err := json.Marshal(killSwitch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode kill switch: %w", err)
+	}
+	if err := s.client.Client.HSet(context.Background(), killSwitchKey, name, data).Err(); err != nil {
+		return nil, fmt.Errorf("failed to activate kill switch: %w", err)
+	}
+
+	s.Invalidate()
+	return killSwitch, nil
+}
+
+// Deactivate turns a kill switch off
+func (s *Store) Deactivate(name string) error {
+	if s == nil {
+		return ErrUnavailable
+	}
+	if _, ok := Lookup(name); !ok {
+		return ErrUnknownSwitch
+	}
+	if err := s.client.Client.HDel(context.Background(), killSwitchKey, name).Err(); err != nil {
+		return fmt.Errorf("failed to deactivate kill switch: %w", err)
+	}
+
+	s.Invalidate()
+	return nil
+}
+
+// Invalidate drops the cached switches so the next read goes to Redis
+func (s *Store) Invalidate() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.fetchedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// get returns cached switches, refreshing them when the cache expired.
+// If Redis fails the last known switches are kept.
+func (s *Store) get() *state {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state != nil && time.Since(s.fetchedAt) < s.ttl {
+		return s.state
+	}
+
+	loaded, err := s.fetch()
+	if err != nil {
+		logger.WithField("error", err.Error()).Warn("Failed to load switches, using last known state")
+		// Retry after the TTL instead of hitting Redis on every call
+		s.fetchedAt = time.Now()
+		return s.state
+	}
+
+	s.state = loaded
+	s.fetchedAt = time.Now()
+	return s.state
+}
+
+// fetch reads switches from Redis
+func (s *Store) fetch() (*state, error) {
+	ctx := context.Background()
+	loaded := &state{killSwitches: make(map[string]*KillSwitch)}
+
+	data, err := s.client.Client.Get(ctx, maintenanceKey).Bytes()
+	if err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("failed to get maintenance: %w", err)
+	}
+	if err == nil {
+		var maintenance Maintenance
+		if err := json.Unmarshal(data, &maintenance); err != nil {
+			return nil, fmt.Errorf("failed to decode maintenance: %w", err)
+		}
+		loaded.maintenance = &maintenance
+	}
+
+	fields, err := s.client.Client.HGetAll(ctx, killSwitchKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kill switches: %w", err)
+	}
+	for name, value := range fields {
+		var killSwitch KillSwitch
+		if err := json.Unmarshal([]byte(value), &killSwitch); err != nil {
+			return nil, fmt.Errorf("failed to decode kill switch %s: %w", name, err)
+		}
+		loaded.killSwitches[name] = &killSwitch
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,94 @@
+package switches
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceIsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		maintenance *Maintenance
+		expected    bool
+	}{
+		{"nil", nil, false},
+		{"disabled", &Maintenance{Enabled: false}, false},
+		{"enabled without end", &Maintenance{Enabled: true}, true},
+		{"enabled before end", &Maintenance{Enabled: true, EndsAt: &future}, true},
+		{"enabled after end", &Maintenance{Enabled: true, EndsAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if active := tt.maintenance.IsActive(now); active != tt.expected {
+				t.Errorf("IsActive() = %v, want %v", active, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMaintenanceRetryAfterSeconds(t *testing.T) {
+	now := time.Now()
+	endsAt := now.Add(90 * time.Second)
+	ended := now.Add(-time.Second)
+
+	tests := []struct {
+		name        string
+		maintenance *Maintenance
+		expected    int
+	}{
+		{"default", &Maintenance{Enabled: true}, DefaultRetryAfter},
+		{"explicit", &Maintenance{Enabled: true, RetryAfter: 60}, 60},
+		{"until end", &Maintenance{Enabled: true, RetryAfter: 60, EndsAt: &endsAt}, 90},
+		{"already ended", &Maintenance{Enabled: true, EndsAt: &ended}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if seconds := tt.maintenance.RetryAfterSeconds(now); seconds != tt.expected {
+				t.Errorf("RetryAfterSeconds() = %d, want %d", seconds, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	definition, ok := Lookup(DisableEmailSending)
+	if !ok || definition.Service != "notification-service" {
+		t.Fatalf("expected email switch of notification service, got %+v", definition)
+	}
+
+	if _, ok := Lookup("disable_everything"); ok {
+		t.Fatal("expected unknown switch not to be found")
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	store := NewStore(nil, 0)
+	if store != nil {
+		t.Fatal("expected nil store without Redis client")
+	}
+
+	if store.Maintenance() != nil {
+		t.Error("nil store must not report maintenance")
+	}
+	if store.IsActive(DisableEmailSending) {
+		t.Error("nil store must not report active switches")
+	}
+	if active := store.Active(""); len(active) != 0 {
+		t.Errorf("expected no active switches, got %d", len(active))
+	}
+
+	if err := store.SetMaintenance(&Maintenance{Enabled: true}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable, got %v", err)
+	}
+	if _, err := store.Activate(DisableEmailSending, "", 1); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable, got %v", err)
+	}
+	store.Invalidate()
+}
@@ -0,0 +1,88 @@
+package undo
+
+import (
+	"errors"
+	"net/http"
+
+	"tachyon-messenger/shared/logger"
+	"tachyon-messenger/shared/middleware"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes adds the undo endpoint to an authenticated route group:
+//
+//	POST /undo/:token - revert a staged action of the current user
+func RegisterRoutes(group *gin.RouterGroup, manager *Manager) {
+	group.POST("/undo/:token", undoHandler(manager))
+}
+
+// undoHandler reverts a staged action by its token
+func undoHandler(manager *Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.Get(c)
+
+		userID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":      "User not authenticated",
+				"request_id": requestID,
+			})
+			return
+		}
+
+		action, err := manager.Undo(userID, c.Param("token"))
+		if err != nil {
+			writeUndoError(c, requestID, userID, err)
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"request_id":  requestID,
+			"user_id":     userID,
+			"service":     manager.service,
+			"kind":        action.Kind,
+			"resource_id": action.ResourceID,
+		}).Info("Action undone")
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Action undone",
+			"kind":        action.Kind,
+			"resource_id": action.ResourceID,
+			"request_id":  requestID,
+		})
+	}
+}
+
+// writeUndoError maps undo errors to HTTP responses
+func writeUndoError(c *gin.Context, requestID string, userID uint, err error) {
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":      "Undo token not found",
+			"request_id": requestID,
+		})
+	case errors.Is(err, ErrWindowExpired):
+		c.JSON(http.StatusGone, gin.H{
+			"error":      "Undo window has expired",
+			"request_id": requestID,
+		})
+	case errors.Is(err, ErrNotRevertible):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Action can't be undone",
+			"request_id": requestID,
+		})
+	default:
+		logger.WithFields(map[string]interface{}{
+			"request_id": requestID,
+			"user_id":    userID,
+			"error":      err.Error(),
+		}).Error("Failed to undo action")
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to undo action",
+			"request_id": requestID,
+		})
+	}
+}
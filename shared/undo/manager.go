@@ -0,0 +1,258 @@
+// Package undo stages destructive actions for a short window in which their author can revert them.
+//
+// A service applies the reversible part of an action right away (hides a message, soft-deletes a
+// task, marks an event cancelled) and stages it with Manager.Stage, which returns an undo token.
+// POST /undo/:token reverts the action while the window is open. When it elapses, CommitDue runs
+// the final effect of the action, e.g. notifications, so nothing leaves the service before then.
+package undo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/database"
+	"tachyon-messenger/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultWindow is how long a staged action can be undone
+	DefaultWindow = 10 * time.Second
+
+	// commitBatchSize bounds the number of actions committed by one CommitDue call
+	commitBatchSize = 100
+
+	// commitRetryDelay is how long a failed commit waits before it is retried
+	commitRetryDelay = time.Minute
+)
+
+var (
+	ErrTokenNotFound  = errors.New("undo token not found")
+	ErrWindowExpired  = errors.New("undo window has expired")
+	ErrUnknownKind    = errors.New("unknown action kind")
+	ErrNotRevertible  = errors.New("action can't be reverted")
+	errMalformedToken = errors.New("malformed undo token")
+)
+
+// WindowFromEnv returns the undo window from UNDO_WINDOW_SECONDS or DefaultWindow, 0 disables undo
+func WindowFromEnv() time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv("UNDO_WINDOW_SECONDS")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return DefaultWindow
+}
+
+// Operation finalizes or reverts staged actions of one kind
+type Operation struct {
+	// Commit runs the final effect of the action when its window elapses, may be nil
+	Commit func(action *PendingAction) error
+	// Revert restores the state changed when the action was staged
+	Revert func(action *PendingAction) error
+}
+
+// Manager stages actions of a service and commits them when their undo window elapses.
+// Tokens are prefixed with the service name, so the gateway can route them.
+type Manager struct {
+	service    string
+	db         *database.DB
+	window     time.Duration
+	operations map[string]Operation
+}
+
+// NewManager creates an undo manager for the service. A window of zero or less
+// disables undo: staged actions are committed immediately.
+func NewManager(service string, db *database.DB, window time.Duration) *Manager {
+	return &Manager{
+		service:    service,
+		db:         db,
+		window:     window,
+		operations: make(map[string]Operation),
+	}
+}
+
+// Register adds the operation of an action kind. Operations are registered at startup,
+// before actions are staged.
+func (m *Manager) Register(kind string, operation Operation) {
+	m.operations[kind] = operation
+}
+
+// Stage records an action whose reversible part has been applied and returns it with its undo token.
+// If the action can't be staged it is reverted. With undo disabled the action is committed
+// right away and nil is returned.
+func (m *Manager) Stage(userID uint, kind string, resourceID uint, payload map[string]interface{}) (*PendingAction, error) {
+	operation, ok := m.operations[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	now := time.Now()
+	action := &PendingAction{
+		Service:    m.service,
+		UserID:     userID,
+		Kind:       kind,
+		ResourceID: resourceID,
+		ExecuteAt:  now.Add(m.window),
+		Payload:    payload,
+	}
+
+	if m.window <= 0 {
+		if operation.Commit != nil {
+			if err := operation.Commit(action); err != nil {
+				return nil, fmt.Errorf("failed to commit %s: %w", kind, err)
+			}
+		}
+		return nil, nil
+	}
+
+	token, err := newToken(m.service)
+	if err == nil {
+		action.Token = token
+		err = m.db.Create(action).Error
+	}
+	if err != nil {
+		if revertErr := operation.Revert(action); revertErr != nil {
+			logger.WithFields(map[string]interface{}{
+				"service":     m.service,
+				"kind":        kind,
+				"resource_id": resourceID,
+				"error":       revertErr.Error(),
+			}).Error("Failed to revert action that could not be staged")
+		}
+		return nil, fmt.Errorf("failed to stage %s: %w", kind, err)
+	}
+
+	return action, nil
+}
+
+// Undo reverts a staged action of the user while its window is open
+func (m *Manager) Undo(userID uint, token string) (*PendingAction, error) {
+	var action PendingAction
+	err := m.db.Where("service = ? AND token = ? AND user_id = ?", m.service, token, userID).
+		First(&action).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending action: %w", err)
+	}
+	if !action.ExecuteAt.After(time.Now()) {
+		return nil, ErrWindowExpired
+	}
+
+	operation, ok := m.operations[action.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, action.Kind)
+	}
+
+	// Whoever removes the record owns the action, the commit job may have taken it meanwhile
+	claimed, err := m.claim(&action)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrWindowExpired
+	}
+
+	if err := operation.Revert(&action); err != nil {
+		// Keep the action staged, so it is committed when the window elapses
+		if restoreErr := m.db.Create(&action).Error; restoreErr != nil {
+			logger.WithFields(map[string]interface{}{
+				"service":     m.service,
+				"kind":        action.Kind,
+				"resource_id": action.ResourceID,
+				"error":       restoreErr.Error(),
+			}).Error("Failed to restore pending action after failed undo")
+		}
+		return nil, fmt.Errorf("%w: %v", ErrNotRevertible, err)
+	}
+
+	return &action, nil
+}
+
+// CommitDue commits staged actions whose window elapsed before now and returns their number.
+// A failed commit is retried later.
+func (m *Manager) CommitDue(now time.Time) (int, error) {
+	var due []*PendingAction
+	err := m.db.Where("service = ? AND execute_at <= ?", m.service, now).
+		Order("execute_at ASC").
+		Limit(commitBatchSize).
+		Find(&due).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due actions: %w", err)
+	}
+
+	committed := 0
+	for _, action := range due {
+		claimed, err := m.claim(action)
+		if err != nil {
+			return committed, err
+		}
+		if !claimed {
+			continue
+		}
+
+		operation, ok := m.operations[action.Kind]
+		if !ok {
+			logger.WithFields(map[string]interface{}{
+				"service":     m.service,
+				"kind":        action.Kind,
+				"resource_id": action.ResourceID,
+			}).Warn("Dropping pending action of unknown kind")
+			continue
+		}
+
+		if operation.Commit != nil {
+			if err := operation.Commit(action); err != nil {
+				logger.WithFields(map[string]interface{}{
+					"service":     m.service,
+					"kind":        action.Kind,
+					"resource_id": action.ResourceID,
+					"error":       err.Error(),
+				}).Error("Failed to commit pending action, will retry")
+
+				action.ExecuteAt = now.Add(commitRetryDelay)
+				if err := m.db.Create(action).Error; err != nil {
+					return committed, fmt.Errorf("failed to reschedule pending action: %w", err)
+				}
+				continue
+			}
+		}
+		committed++
+	}
+
+	return committed, nil
+}
+
+// claim removes a pending action, it returns false if another caller removed it first
+func (m *Manager) claim(action *PendingAction) (bool, error) {
+	result := m.db.Where("id = ?", action.ID).Delete(&PendingAction{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim pending action: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// newToken generates an undo token prefixed with the service name
+func newToken(service string) (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate undo token: %w", err)
+	}
+	return service + "_" + hex.EncodeToString(bytes), nil
+}
+
+// ServiceOf returns the service that issued an undo token
+func ServiceOf(token string) (string, error) {
+	service, secret, ok := strings.Cut(token, "_")
+	if !ok || service == "" || secret == "" {
+		return "", errMalformedToken
+	}
+	return service, nil
+}
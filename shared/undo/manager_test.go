@@ -0,0 +1,146 @@
+package undo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tachyon-messenger/shared/database/dbtest"
+)
+
+// recorder counts operation calls of staged actions
+type recorder struct {
+	committed []uint
+	reverted  []uint
+}
+
+func newTestManager(t *testing.T, window time.Duration) (*Manager, *recorder) {
+	manager := NewManager("test", dbtest.Open(t, Models()...), window)
+	calls := &recorder{}
+	manager.Register("delete_note", Operation{
+		Commit: func(action *PendingAction) error {
+			calls.committed = append(calls.committed, action.ResourceID)
+			return nil
+		},
+		Revert: func(action *PendingAction) error {
+			calls.reverted = append(calls.reverted, action.ResourceID)
+			return nil
+		},
+	})
+	return manager, calls
+}
+
+func TestUndoRevertsStagedAction(t *testing.T) {
+	manager, calls := newTestManager(t, time.Minute)
+
+	action, err := manager.Stage(1, "delete_note", 42, map[string]interface{}{"status": "active"})
+	if err != nil {
+		t.Fatalf("failed to stage action: %v", err)
+	}
+	if service, err := ServiceOf(action.Token); err != nil || service != "test" {
+		t.Errorf("expected token of service test, got %q (%v)", service, err)
+	}
+
+	if _, err := manager.Undo(2, action.Token); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("expected ErrTokenNotFound for another user, got %v", err)
+	}
+
+	undone, err := manager.Undo(1, action.Token)
+	if err != nil {
+		t.Fatalf("failed to undo action: %v", err)
+	}
+	if undone.Payload["status"] != "active" {
+		t.Errorf("expected payload to be kept, got %v", undone.Payload)
+	}
+	if len(calls.reverted) != 1 || calls.reverted[0] != 42 {
+		t.Errorf("expected action to be reverted once, got %v", calls.reverted)
+	}
+
+	if _, err := manager.Undo(1, action.Token); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("expected ErrTokenNotFound after undo, got %v", err)
+	}
+
+	committed, err := manager.CommitDue(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to commit due actions: %v", err)
+	}
+	if committed != 0 || len(calls.committed) != 0 {
+		t.Errorf("expected undone action not to be committed, got %d", committed)
+	}
+}
+
+func TestCommitDueAfterWindow(t *testing.T) {
+	manager, calls := newTestManager(t, time.Minute)
+
+	action, err := manager.Stage(1, "delete_note", 7, nil)
+	if err != nil {
+		t.Fatalf("failed to stage action: %v", err)
+	}
+
+	committed, err := manager.CommitDue(time.Now())
+	if err != nil {
+		t.Fatalf("failed to commit due actions: %v", err)
+	}
+	if committed != 0 {
+		t.Errorf("expected no action due within the window, got %d", committed)
+	}
+
+	committed, err = manager.CommitDue(time.Now().Add(2 * time.Minute))
+	if err != nil {
+		t.Fatalf("failed to commit due actions: %v", err)
+	}
+	if committed != 1 || len(calls.committed) != 1 || calls.committed[0] != 7 {
+		t.Errorf("expected action to be committed once, got %d %v", committed, calls.committed)
+	}
+
+	if _, err := manager.Undo(1, action.Token); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("expected ErrTokenNotFound after commit, got %v", err)
+	}
+}
+
+func TestStageWithoutWindowCommitsImmediately(t *testing.T) {
+	manager, calls := newTestManager(t, 0)
+
+	action, err := manager.Stage(1, "delete_note", 3, nil)
+	if err != nil {
+		t.Fatalf("failed to stage action: %v", err)
+	}
+	if action != nil {
+		t.Errorf("expected no pending action with undo disabled, got %+v", action)
+	}
+	if len(calls.committed) != 1 {
+		t.Errorf("expected action to be committed immediately, got %v", calls.committed)
+	}
+
+	if _, err := manager.Stage(1, "unknown", 3, nil); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("expected ErrUnknownKind, got %v", err)
+	}
+}
+
+func TestServiceOfMalformedToken(t *testing.T) {
+	for _, token := range []string{"", "chat", "_abc", "chat_"} {
+		if _, err := ServiceOf(token); err == nil {
+			t.Errorf("expected error for token %q", token)
+		}
+	}
+}
+
+func TestWindowFromEnv(t *testing.T) {
+	cases := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", DefaultWindow},
+		{"30", 30 * time.Second},
+		{"0", 0},
+		{"-5", DefaultWindow},
+		{"soon", DefaultWindow},
+	}
+
+	for _, tc := range cases {
+		t.Setenv("UNDO_WINDOW_SECONDS", tc.value)
+		if window := WindowFromEnv(); window != tc.want {
+			t.Errorf("UNDO_WINDOW_SECONDS=%q: window = %v, want %v", tc.value, window, tc.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package undo
+
+import "time"
+
+// PendingAction is a destructive action staged until its undo window elapses
+type PendingAction struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	Service    string    `gorm:"not null;size:50;index:idx_pending_actions_due" json:"service"`
+	Token      string    `gorm:"not null;size:100;uniqueIndex" json:"token"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	Kind       string    `gorm:"not null;size:50" json:"kind"`
+	ResourceID uint      `gorm:"not null" json:"resource_id"`
+	ExecuteAt  time.Time `gorm:"not null;index:idx_pending_actions_due" json:"execute_at"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// State the operation needs to commit or revert the action, e.g. the previous status
+	Payload map[string]interface{} `gorm:"type:text;serializer:json" json:"payload,omitempty"`
+}
+
+// TableName returns the table name for PendingAction model
+func (PendingAction) TableName() string {
+	return "pending_actions"
+}
+
+// Receipt tells the client how to undo a staged action
+type Receipt struct {
+	Token     string    `json:"undo_token"`
+	ExpiresAt time.Time `json:"undo_expires_at"`
+}
+
+// Receipt returns the undo receipt of the action
+func (a *PendingAction) Receipt() *Receipt {
+	return &Receipt{
+		Token:     a.Token,
+		ExpiresAt: a.ExecuteAt,
+	}
+}
+
+// Models returns models to migrate in services using the undo manager
+func Models() []interface{} {
+	return []interface{}{&PendingAction{}}
+}
@@ -0,0 +1,118 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"tachyon-messenger/shared/i18n"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Install makes gin binding (ShouldBindJSON, ShouldBindQuery, ...) check both `binding`
+// and `validate` tags with the shared rules and return Errors on failure
+func Install() {
+	binding.Validator = &ginValidator{}
+}
+
+// ginValidator implements binding.StructValidator
+type ginValidator struct{}
+
+// ValidateStruct validates a struct, a pointer to it or a slice of them
+func (v *ginValidator) ValidateStruct(obj interface{}) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return v.ValidateStruct(value.Elem().Interface())
+	case reflect.Struct:
+		return validateStruct(obj)
+	case reflect.Slice, reflect.Array:
+		var all Errors
+		for i := 0; i < value.Len(); i++ {
+			err := v.ValidateStruct(value.Index(i).Interface())
+			var fieldErrs Errors
+			if errors.As(err, &fieldErrs) {
+				for _, fieldErr := range fieldErrs {
+					fieldErr.Field = fmt.Sprintf("[%d].%s", i, fieldErr.Field)
+					all = append(all, fieldErr)
+				}
+			} else if err != nil {
+				return err
+			}
+		}
+		if len(all) > 0 {
+			return all
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Engine returns the validator used for `binding` tags
+func (v *ginValidator) Engine() interface{} {
+	return bindingValidate
+}
+
+// validateStruct checks `binding` tags and then `validate` tags, skipping rules
+// already reported for a field
+func validateStruct(obj interface{}) error {
+	var all Errors
+	seen := make(map[string]bool)
+
+	for _, err := range []error{convert(bindingValidate.Struct(obj)), Struct(obj)} {
+		var fieldErrs Errors
+		if !errors.As(err, &fieldErrs) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, fieldErr := range fieldErrs {
+			if seen[fieldErr.Field] {
+				continue
+			}
+			seen[fieldErr.Field] = true
+			all = append(all, fieldErr)
+		}
+	}
+
+	if len(all) > 0 {
+		return all
+	}
+	return nil
+}
+
+// Details returns field-level details of a binding or validation error for API responses
+// in the locale of the request. Errors that are not about particular fields are reported
+// as a single entry without field.
+func Details(c *gin.Context, err error) Errors {
+	var fieldErrs Errors
+	if errors.As(err, &fieldErrs) {
+		return fieldErrs.Localize(i18n.FromContext(c))
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return Errors{{
+			Field: typeErr.Field,
+			Rule:  "type",
+			Param: typeErr.Type.String(),
+		}}.Localize(i18n.FromContext(c))
+	}
+
+	if err == nil {
+		return nil
+	}
+	return Errors{{Rule: "format", Message: err.Error()}}
+}
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Enum is implemented by enumeration types to be checked with the `enum` rule
+type Enum interface {
+	IsValid() bool
+}
+
+// rule is a custom validation rule with its error message format.
+// Messages of built-in rules are in i18n bundles under "validation.<tag>".
+type rule struct {
+	fn      validator.Func
+	message string
+}
+
+// rules are custom rules available in both `validate` and `binding` tags
+var rules = map[string]rule{
+	// notblank: string is not empty after trimming whitespace
+	"notblank": {fn: isNotBlank},
+	// future: time is after now
+	"future": {fn: isFuture},
+	// enum: value implementing Enum is a known member
+	"enum": {fn: isEnumMember},
+}
+
+func isNotBlank(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return false
+	}
+	return strings.TrimSpace(field.String()) != ""
+}
+
+func isFuture(fl validator.FieldLevel) bool {
+	t, ok := fl.Field().Interface().(time.Time)
+	return ok && t.After(time.Now())
+}
+
+func isEnumMember(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if value, ok := field.Interface().(Enum); ok {
+		return value.IsValid()
+	}
+	// IsValid may be declared on the pointer receiver
+	if field.CanAddr() {
+		if value, ok := field.Addr().Interface().(Enum); ok {
+			return value.IsValid()
+		}
+	}
+	return false
+}
+
+// message returns a human readable message for a failed rule in locale
+func message(locale i18n.Locale, field, tag, param string, kind reflect.Kind) string {
+	if field == "" {
+		field = i18n.T(locale, "validation.value", nil)
+	}
+	args := map[string]interface{}{"Field": field, "Param": param, "Rule": tag}
+
+	switch tag {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return i18n.T(locale, "validation.required", args)
+	case "min", "max", "len":
+		return i18n.T(locale, "validation."+tag+"."+sizeClass(kind), args)
+	case "oneof":
+		args["Param"] = strings.Join(strings.Fields(param), ", ")
+		return i18n.T(locale, "validation.oneof", args)
+	}
+
+	if i18n.Has(locale, "validation."+tag) {
+		return i18n.T(locale, "validation."+tag, args)
+	}
+	if r, ok := rules[tag]; ok && r.message != "" {
+		return fmt.Sprintf(r.message, field)
+	}
+	return i18n.T(locale, "validation.invalid", args)
+}
+
+// sizeClass returns the message variant of min, max and len rules for the field kind
+func sizeClass(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	default:
+		return "number"
+	}
+}
+
+// kindOf returns the kind of fieldType with pointers dereferenced
+func kindOf(fieldType reflect.Type) reflect.Kind {
+	if fieldType == nil {
+		return reflect.Invalid
+	}
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind()
+}
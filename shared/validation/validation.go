@@ -0,0 +1,147 @@
+// Package validation runs struct tag validation of request DTOs with rules shared by all
+// services and reports failures as field-level errors in a single format.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"tachyon-messenger/shared/i18n"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a single failed rule of a request field
+type FieldError struct {
+	Field   string `json:"field,omitempty"` // Путь к полю в JSON, например options[0].text
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+
+	kind reflect.Kind // Вид значения поля для выбора формы сообщения
+}
+
+// Errors is a list of field errors returned by Struct
+type Errors []FieldError
+
+// Error joins messages of all field errors
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Localize returns a copy of the errors with messages in locale.
+// Errors without a rule message (e.g. malformed JSON) keep their original message.
+func (e Errors) Localize(locale i18n.Locale) Errors {
+	localized := make(Errors, len(e))
+	for i, fieldErr := range e {
+		if fieldErr.Rule != "format" {
+			fieldErr.Message = message(locale, fieldErr.Field, fieldErr.Rule, fieldErr.Param, fieldErr.kind)
+		}
+		localized[i] = fieldErr
+	}
+	return localized
+}
+
+var (
+	// validate checks `validate` tags of DTOs and models
+	validate = newValidator("validate")
+	// bindingValidate checks gin `binding` tags, see Install
+	bindingValidate = newValidator("binding")
+)
+
+// newValidator creates a validator for tagName with shared rules registered
+func newValidator(tagName string) *validator.Validate {
+	v := validator.New()
+	v.SetTagName(tagName)
+
+	// Report JSON (or query) field names instead of Go field names
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	for tag, rule := range rules {
+		if err := v.RegisterValidation(tag, rule.fn); err != nil {
+			panic(fmt.Sprintf("validation: failed to register rule %q: %v", tag, err))
+		}
+	}
+	return v
+}
+
+// Struct validates `validate` tags of s. It returns Errors if any rule fails.
+// Messages are in English for logs; use Errors.Localize or Details for API responses.
+func Struct(s interface{}) error {
+	return convert(validate.Struct(s))
+}
+
+// Var validates a single value against tag rules, field is used in error messages
+func Var(field string, value interface{}, tag string) error {
+	err := convert(validate.Var(value, tag))
+
+	var fieldErrs Errors
+	if errors.As(err, &fieldErrs) {
+		for i := range fieldErrs {
+			fieldErrs[i].Field = field
+			fieldErrs[i].kind = kindOf(reflect.TypeOf(value))
+			fieldErrs[i].Message = message(i18n.LocaleEN, field, fieldErrs[i].Rule, fieldErrs[i].Param, fieldErrs[i].kind)
+		}
+		return fieldErrs
+	}
+	return err
+}
+
+// RegisterValidation adds a custom rule for both `validate` and `binding` tags.
+// message is a format with the field name as the only argument, e.g. "%s must be even".
+// It must be called on startup, before any validation runs.
+func RegisterValidation(tag string, fn validator.Func, message string) error {
+	rules[tag] = rule{fn: fn, message: message}
+	if err := validate.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+	return bindingValidate.RegisterValidation(tag, fn)
+}
+
+// convert translates validator errors to Errors
+func convert(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fieldErrs := make(Errors, len(validationErrs))
+	for i, fe := range validationErrs {
+		field := fieldPath(fe)
+		kind := kindOf(fe.Type())
+		fieldErrs[i] = FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: message(i18n.LocaleEN, field, fe.Tag(), fe.Param(), kind),
+			kind:    kind,
+		}
+	}
+	return fieldErrs
+}
+
+// fieldPath returns the field namespace without the root struct name
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
@@ -0,0 +1,93 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tachyon-messenger/shared/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testStatus string
+
+func (s testStatus) IsValid() bool {
+	return s == "open" || s == "closed"
+}
+
+type testItem struct {
+	Name string `json:"name" validate:"required,notblank"`
+}
+
+type testRequest struct {
+	Title    string      `json:"title" validate:"required,notblank,max=10"`
+	Status   *testStatus `json:"status,omitempty" validate:"omitempty,enum"`
+	Deadline *time.Time  `json:"deadline,omitempty" validate:"omitempty,future"`
+	Color    string      `json:"color,omitempty" validate:"omitempty,len=7,hexcolor"`
+	Items    []testItem  `json:"items" validate:"omitempty,dive"`
+}
+
+func TestStruct(t *testing.T) {
+	open := testStatus("open")
+	future := time.Now().Add(time.Hour)
+
+	valid := &testRequest{Title: "Report", Status: &open, Deadline: &future, Color: "#3788d8", Items: []testItem{{Name: "a"}}}
+	if err := Struct(valid); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+
+	unknown := testStatus("unknown")
+	past := time.Now().Add(-time.Hour)
+	invalid := &testRequest{Title: "   ", Status: &unknown, Deadline: &past, Color: "3788d8x", Items: []testItem{{Name: ""}}}
+
+	err := Struct(invalid)
+	var fieldErrs Errors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+
+	expected := map[string]string{
+		"title":         "notblank",
+		"status":        "enum",
+		"deadline":      "future",
+		"color":         "hexcolor",
+		"items[0].name": "required",
+	}
+	if len(fieldErrs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), fieldErrs)
+	}
+	for _, fieldErr := range fieldErrs {
+		if expected[fieldErr.Field] != fieldErr.Rule {
+			t.Errorf("unexpected error %+v", fieldErr)
+		}
+		if fieldErr.Message == "" {
+			t.Errorf("expected message for %s", fieldErr.Field)
+		}
+	}
+}
+
+func TestDetails(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Request.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	err := Struct(&testRequest{})
+	details := Details(c, err)
+	if len(details) != 1 || details[0].Field != "title" || details[0].Message != "title is required" {
+		t.Errorf("unexpected details %+v", details)
+	}
+
+	c.Set(i18n.ContextKey, i18n.LocaleRU)
+	details = Details(c, err)
+	if len(details) != 1 || details[0].Message != "Поле title обязательно для заполнения" {
+		t.Errorf("unexpected localized details %+v", details)
+	}
+
+	details = Details(c, errors.New("unexpected EOF"))
+	if len(details) != 1 || details[0].Rule != "format" {
+		t.Errorf("unexpected details %+v", details)
+	}
+}